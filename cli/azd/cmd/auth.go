@@ -50,5 +50,7 @@ func authActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 		ActionResolver: newLogoutAction,
 	})
 
+	authFederationActions(group)
+
 	return group
 }
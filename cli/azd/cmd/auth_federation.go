@@ -0,0 +1,495 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/pipeline"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func authFederationActions(group *actions.ActionDescriptor) {
+	federationGroup := group.Add("federation", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "federation",
+			Short: "Manage the federated credentials of service principals and managed identities.",
+		},
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdAuthFederationHelpDescription,
+			Footer:      getCmdAuthFederationHelpFooter,
+		},
+	})
+
+	federationGroup.Add("create", &actions.ActionDescriptorOptions{
+		Command:        newAuthFederationCreateCmd(),
+		FlagsResolver:  newAuthFederationCreateFlags,
+		ActionResolver: newAuthFederationCreateAction,
+	})
+
+	federationGroup.Add("list", &actions.ActionDescriptorOptions{
+		Command:        newAuthFederationListCmd(),
+		FlagsResolver:  newAuthFederationListFlags,
+		ActionResolver: newAuthFederationListAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
+		DefaultFormat:  output.TableFormat,
+	})
+
+	federationGroup.Add("rotate", &actions.ActionDescriptorOptions{
+		Command:        newAuthFederationRotateCmd(),
+		FlagsResolver:  newAuthFederationRotateFlags,
+		ActionResolver: newAuthFederationRotateAction,
+	})
+}
+
+// authFederationTargetFlags select the identity whose federated credentials are managed.
+type authFederationTargetFlags struct {
+	clientId     string
+	identity     string
+	subscription string
+}
+
+func (f *authFederationTargetFlags) Bind(local *pflag.FlagSet) {
+	local.StringVar(
+		&f.clientId,
+		"client-id",
+		"",
+		"The application (client) ID or the display name of the service principal.",
+	)
+	local.StringVar(
+		&f.identity,
+		"identity",
+		"",
+		"The resource ID of the user-assigned managed identity.",
+	)
+	local.StringVar(
+		&f.subscription,
+		"subscription",
+		"",
+		"The ID of a subscription in the tenant of the service principal. Defaults to AZURE_SUBSCRIPTION_ID, "+
+			"or the default subscription of azd.",
+	)
+}
+
+// resolve returns the federation target and the subscription used to manage it. The subscription of a managed
+// identity is the subscription of its resource ID.
+func (f *authFederationTargetFlags) resolve(
+	ctx context.Context,
+	accountManager account.Manager,
+) (pipeline.FederationTarget, string, error) {
+	if (f.clientId == "") == (f.identity == "") {
+		return pipeline.FederationTarget{}, "", &internal.ErrorWithSuggestion{
+			Err:        internal.ErrInvalidFlagCombination,
+			Suggestion: "Set either --client-id for a service principal, or --identity for a managed identity.",
+		}
+	}
+
+	if f.identity != "" {
+		identityId, err := arm.ParseResourceID(f.identity)
+		if err != nil || !strings.EqualFold(identityId.ResourceType.String(), managedIdentityResourceType) {
+			return pipeline.FederationTarget{}, "", fmt.Errorf(
+				"--identity must be the resource ID of a user-assigned managed identity, like "+
+					"/subscriptions/<id>/resourceGroups/<group>/providers/%s/<name>", managedIdentityResourceType)
+		}
+
+		return pipeline.FederationTarget{IdentityId: f.identity}, identityId.SubscriptionID, nil
+	}
+
+	subscriptionId := f.subscription
+	if subscriptionId == "" {
+		subscriptionId = os.Getenv(environment.SubscriptionIdEnvVarName)
+	}
+	if subscriptionId == "" {
+		subscriptionId = accountManager.GetDefaultSubscriptionID(ctx)
+	}
+	if subscriptionId == "" {
+		return pipeline.FederationTarget{}, "", &internal.ErrorWithSuggestion{
+			Err: errors.New("no subscription was found to select the tenant of the service principal"),
+			Suggestion: "Set --subscription, or run 'azd config set defaults.subscription <subscription-id>' to set " +
+				"a default subscription.",
+		}
+	}
+
+	return pipeline.FederationTarget{ClientId: f.clientId}, subscriptionId, nil
+}
+
+const managedIdentityResourceType = "Microsoft.ManagedIdentity/userAssignedIdentities"
+
+// authFederationCredentialFlags describe the workloads trusted by a federated credential.
+type authFederationCredentialFlags struct {
+	provider          string
+	issuer            string
+	subject           string
+	repo              string
+	branch            string
+	environment       string
+	pullRequest       bool
+	serviceConnection string
+	audience          string
+}
+
+func (f *authFederationCredentialFlags) Bind(local *pflag.FlagSet) {
+	providers := make([]string, len(pipeline.FederationIssuers))
+	for i, issuer := range pipeline.FederationIssuers {
+		providers[i] = string(issuer)
+	}
+
+	local.StringVar(
+		&f.provider,
+		"provider",
+		"",
+		fmt.Sprintf("The provider of the tokens trusted by the credential (%s).", strings.Join(providers, ", ")),
+	)
+	local.StringVar(
+		&f.issuer,
+		"issuer",
+		"",
+		"The URL of the issuer. Required for azdo and custom. Defaults to https://gitlab.com for gitlab.",
+	)
+	local.StringVar(
+		&f.subject,
+		"subject",
+		"",
+		"The subject trusted by the credential. Required for custom. By default, built from the other flags.",
+	)
+	local.StringVar(
+		&f.repo,
+		"repo",
+		"",
+		"The repository: <owner>/<repo> for github, <group>/<project> for gitlab or <organization>/<project> for azdo.",
+	)
+	local.StringVar(&f.branch, "branch", "main", "The branch trusted for github and gitlab.")
+	local.StringVar(&f.environment, "github-environment", "", "The GitHub environment trusted, instead of a branch.")
+	local.BoolVar(
+		&f.pullRequest, "pull-request", false, "Trusts the pull request workflows of the GitHub repository.")
+	local.StringVar(&f.serviceConnection, "service-connection", "", "The Azure DevOps service connection trusted.")
+	local.StringVar(&f.audience, "audience", "", "The audience of the tokens. Defaults to api://AzureADTokenExchange.")
+}
+
+func (f *authFederationCredentialFlags) credential(name string) (*pipeline.FederatedCredential, error) {
+	if f.provider == "" {
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        fmt.Errorf("--provider is required: %w", internal.ErrInvalidFlagCombination),
+			Suggestion: "Set --provider to github, azdo, gitlab or custom.",
+		}
+	}
+
+	options := pipeline.FederatedCredentialOptions{
+		IssuerKind:        pipeline.FederationIssuer(strings.ToLower(f.provider)),
+		Issuer:            f.issuer,
+		Subject:           f.subject,
+		Repository:        f.repo,
+		Branch:            f.branch,
+		Environment:       f.environment,
+		PullRequest:       f.pullRequest,
+		ServiceConnection: f.serviceConnection,
+		Name:              name,
+		Audience:          f.audience,
+	}
+
+	return options.Credential()
+}
+
+// azd auth federation create
+
+func newAuthFederationCreateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "create",
+		Short: "Create a federated credential that trusts the tokens of a CI/CD system or an OIDC issuer.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type authFederationCreateFlags struct {
+	target     authFederationTargetFlags
+	credential authFederationCredentialFlags
+	name       string
+	global     *internal.GlobalCommandOptions
+}
+
+func newAuthFederationCreateFlags(
+	cmd *cobra.Command,
+	global *internal.GlobalCommandOptions,
+) *authFederationCreateFlags {
+	flags := &authFederationCreateFlags{}
+	flags.Bind(cmd.Flags(), global)
+	return flags
+}
+
+func (f *authFederationCreateFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.target.Bind(local)
+	f.credential.Bind(local)
+	local.StringVar(&f.name, "name", "", "The name of the credential. Defaults to the subject, sanitized.")
+	f.global = global
+}
+
+type authFederationCreateAction struct {
+	flags             *authFederationCreateFlags
+	accountManager    account.Manager
+	federationManager *pipeline.FederationManager
+}
+
+func newAuthFederationCreateAction(
+	flags *authFederationCreateFlags,
+	accountManager account.Manager,
+	federationManager *pipeline.FederationManager,
+) actions.Action {
+	return &authFederationCreateAction{
+		flags:             flags,
+		accountManager:    accountManager,
+		federationManager: federationManager,
+	}
+}
+
+func (a *authFederationCreateAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	target, subscriptionId, err := a.flags.target.resolve(ctx, a.accountManager)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := a.flags.credential.credential(a.flags.name)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := a.federationManager.Create(ctx, subscriptionId, target, []pipeline.FederatedCredential{*credential})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(created) == 0 {
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: fmt.Sprintf("A federated credential already trusts the subject %s.", credential.Subject),
+			},
+		}, nil
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf(
+				"Created federated credential '%s' for the subject %s.", created[0].Name, created[0].Subject),
+		},
+	}, nil
+}
+
+// azd auth federation list
+
+func newAuthFederationListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Short:   "List the federated credentials of a service principal or a managed identity.",
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+	}
+}
+
+type authFederationListFlags struct {
+	target authFederationTargetFlags
+	global *internal.GlobalCommandOptions
+}
+
+func newAuthFederationListFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *authFederationListFlags {
+	flags := &authFederationListFlags{}
+	flags.Bind(cmd.Flags(), global)
+	return flags
+}
+
+func (f *authFederationListFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.target.Bind(local)
+	f.global = global
+}
+
+type authFederationListAction struct {
+	flags             *authFederationListFlags
+	accountManager    account.Manager
+	federationManager *pipeline.FederationManager
+	formatter         output.Formatter
+	writer            io.Writer
+}
+
+func newAuthFederationListAction(
+	flags *authFederationListFlags,
+	accountManager account.Manager,
+	federationManager *pipeline.FederationManager,
+	formatter output.Formatter,
+	writer io.Writer,
+) actions.Action {
+	return &authFederationListAction{
+		flags:             flags,
+		accountManager:    accountManager,
+		federationManager: federationManager,
+		formatter:         formatter,
+		writer:            writer,
+	}
+}
+
+func (a *authFederationListAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	target, subscriptionId, err := a.flags.target.resolve(ctx, a.accountManager)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := a.federationManager.List(ctx, subscriptionId, target)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.formatter.Kind() == output.TableFormat {
+		columns := []output.Column{
+			{
+				Heading:       "NAME",
+				ValueTemplate: "{{.Name}}",
+			},
+			{
+				Heading:       "ISSUER",
+				ValueTemplate: "{{.Issuer}}",
+			},
+			{
+				Heading:       "SUBJECT",
+				ValueTemplate: "{{.Subject}}",
+			},
+		}
+
+		err = a.formatter.Format(credentials, a.writer, output.TableFormatterOptions{
+			Columns: columns,
+		})
+	} else {
+		err = a.formatter.Format(credentials, a.writer, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("formatting federated credentials: %w", err)
+	}
+
+	return nil, nil
+}
+
+// azd auth federation rotate
+
+func newAuthFederationRotateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rotate",
+		Short: "Replace the issuer and subject trusted by an existing federated credential.",
+		Long: "Replaces the issuer and subject trusted by an existing federated credential, for example after a " +
+			"repository is renamed or moved. The previous subject is no longer trusted.",
+		Args: cobra.NoArgs,
+	}
+}
+
+type authFederationRotateFlags struct {
+	target     authFederationTargetFlags
+	credential authFederationCredentialFlags
+	name       string
+	global     *internal.GlobalCommandOptions
+}
+
+func newAuthFederationRotateFlags(
+	cmd *cobra.Command,
+	global *internal.GlobalCommandOptions,
+) *authFederationRotateFlags {
+	flags := &authFederationRotateFlags{}
+	flags.Bind(cmd.Flags(), global)
+	return flags
+}
+
+func (f *authFederationRotateFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.target.Bind(local)
+	f.credential.Bind(local)
+	local.StringVar(&f.name, "name", "", "The name of the credential to rotate.")
+	f.global = global
+}
+
+type authFederationRotateAction struct {
+	flags             *authFederationRotateFlags
+	accountManager    account.Manager
+	federationManager *pipeline.FederationManager
+}
+
+func newAuthFederationRotateAction(
+	flags *authFederationRotateFlags,
+	accountManager account.Manager,
+	federationManager *pipeline.FederationManager,
+) actions.Action {
+	return &authFederationRotateAction{
+		flags:             flags,
+		accountManager:    accountManager,
+		federationManager: federationManager,
+	}
+}
+
+func (a *authFederationRotateAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if a.flags.name == "" {
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        fmt.Errorf("--name is required: %w", internal.ErrInvalidFlagCombination),
+			Suggestion: "Run 'azd auth federation list' to see the names of the federated credentials.",
+		}
+	}
+
+	target, subscriptionId, err := a.flags.target.resolve(ctx, a.accountManager)
+	if err != nil {
+		return nil, err
+	}
+
+	replacement, err := a.flags.credential.credential(a.flags.name)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, rotated, err := a.federationManager.Rotate(ctx, subscriptionId, target, a.flags.name, *replacement)
+	if errors.Is(err, pipeline.ErrFederatedCredentialNotFound) {
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        err,
+			Suggestion: "Run 'azd auth federation list' to see the names of the federated credentials.",
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Rotated federated credential '%s' to the subject %s.", rotated.Name, rotated.Subject),
+			FollowUp: fmt.Sprintf(
+				"The subject %s of the issuer %s is no longer trusted.", previous.Subject, previous.Issuer),
+		},
+	}, nil
+}
+
+func getCmdAuthFederationHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Manage the federated credentials that let CI/CD systems and other OIDC issuers sign in to Azure as a service "+
+			"principal or a user-assigned managed identity, without secrets.",
+		[]string{
+			formatHelpNote(
+				"The issuer and the subject are built from --repo and --branch for github and gitlab, and from --repo " +
+					"and --service-connection for azdo. Set --issuer and --subject for any other issuer."),
+			formatHelpNote(
+				"Unlike 'azd pipeline config', these commands don't configure the repository or assign roles."),
+		})
+}
+
+func getCmdAuthFederationHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Trust the main branch of a GitHub repository.": output.WithHighLightFormat(
+			"azd auth federation create --client-id <client-id> --provider github --repo <owner>/<repo>"),
+		"Trust a branch of a GitLab project with a managed identity.": output.WithHighLightFormat(
+			"azd auth federation create --identity <resource-id> --provider gitlab --repo <group>/<project> " +
+				"--branch release"),
+		"List the federated credentials of a service principal.": output.WithHighLightFormat(
+			"azd auth federation list --client-id <client-id>"),
+		"Move a federated credential to a renamed GitHub repository.": output.WithHighLightFormat(
+			"azd auth federation rotate --client-id <client-id> --name <name> --provider github --repo <owner>/<new-repo>"),
+	})
+}
@@ -0,0 +1,77 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/pipeline"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockaccount"
+)
+
+func Test_authFederationTargetFlags_resolve(t *testing.T) {
+	const identityId = "/subscriptions/identity-sub/resourceGroups/rg/providers/" +
+		"Microsoft.ManagedIdentity/userAssignedIdentities/id"
+
+	accountManager := &mockaccount.MockAccountManager{DefaultSubscription: "default-sub"}
+
+	t.Run("Identity", func(t *testing.T) {
+		flags := &authFederationTargetFlags{identity: identityId, subscription: "ignored"}
+		target, subscriptionId, err := flags.resolve(t.Context(), accountManager)
+		require.NoError(t, err)
+		require.Equal(t, pipeline.FederationTarget{IdentityId: identityId}, target)
+		require.Equal(t, "identity-sub", subscriptionId)
+	})
+
+	t.Run("InvalidIdentity", func(t *testing.T) {
+		flags := &authFederationTargetFlags{
+			identity: "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/sa",
+		}
+		_, _, err := flags.resolve(t.Context(), accountManager)
+		require.ErrorContains(t, err, "user-assigned managed identity")
+	})
+
+	t.Run("ClientIdSubscriptionFlag", func(t *testing.T) {
+		t.Setenv(environment.SubscriptionIdEnvVarName, "env-sub")
+		flags := &authFederationTargetFlags{clientId: "client-id", subscription: "flag-sub"}
+		target, subscriptionId, err := flags.resolve(t.Context(), accountManager)
+		require.NoError(t, err)
+		require.Equal(t, pipeline.FederationTarget{ClientId: "client-id"}, target)
+		require.Equal(t, "flag-sub", subscriptionId)
+	})
+
+	t.Run("ClientIdSubscriptionEnvVar", func(t *testing.T) {
+		t.Setenv(environment.SubscriptionIdEnvVarName, "env-sub")
+		flags := &authFederationTargetFlags{clientId: "client-id"}
+		_, subscriptionId, err := flags.resolve(t.Context(), accountManager)
+		require.NoError(t, err)
+		require.Equal(t, "env-sub", subscriptionId)
+	})
+
+	t.Run("ClientIdDefaultSubscription", func(t *testing.T) {
+		t.Setenv(environment.SubscriptionIdEnvVarName, "")
+		flags := &authFederationTargetFlags{clientId: "client-id"}
+		_, subscriptionId, err := flags.resolve(t.Context(), accountManager)
+		require.NoError(t, err)
+		require.Equal(t, "default-sub", subscriptionId)
+
+		_, _, err = flags.resolve(t.Context(), &mockaccount.MockAccountManager{})
+		require.ErrorContains(t, err, "no subscription was found")
+	})
+
+	t.Run("NoTarget", func(t *testing.T) {
+		_, _, err := (&authFederationTargetFlags{}).resolve(t.Context(), accountManager)
+		require.ErrorIs(t, err, internal.ErrInvalidFlagCombination)
+	})
+
+	t.Run("BothTargets", func(t *testing.T) {
+		flags := &authFederationTargetFlags{clientId: "client-id", identity: identityId}
+		_, _, err := flags.resolve(t.Context(), accountManager)
+		require.ErrorIs(t, err, internal.ErrInvalidFlagCombination)
+	})
+}
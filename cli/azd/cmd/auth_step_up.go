@@ -0,0 +1,70 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/internal/tracing/resource"
+	"github.com/azure/azure-dev/cli/azd/pkg/auth"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/workflow"
+)
+
+// stepUpLoginHandler satisfies claims challenges, such as Conditional Access step-up or multi-factor authentication
+// required for an operation, by running `azd auth login` with the challenged claims once the user confirms.
+type stepUpLoginHandler struct {
+	console        input.Console
+	workflowRunner *workflow.Runner
+}
+
+func newStepUpLoginHandler(console input.Console, workflowRunner *workflow.Runner) auth.StepUpHandler {
+	return &stepUpLoginHandler{
+		console:        console,
+		workflowRunner: workflowRunner,
+	}
+}
+
+// StepUp implements auth.StepUpHandler.
+func (h *stepUpLoginHandler) StepUp(ctx context.Context, challenge auth.ClaimsChallenge) error {
+	// Without a user to interact with, the challenge fails with instructions to sign in again.
+	if h.console.IsNoPromptMode() || resource.IsRunningOnCI() {
+		return auth.ErrStepUpUnavailable
+	}
+
+	h.console.StopSpinner(ctx, "", input.Step)
+	h.console.Message(ctx, output.WithWarningFormat("WARNING: %s", challenge.Reason))
+
+	signIn, err := h.console.Confirm(ctx, input.ConsoleOptions{
+		Message:      "Would you like to sign in again now?",
+		DefaultValue: true,
+	})
+	h.console.Message(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	if !signIn {
+		return auth.ErrStepUpUnavailable
+	}
+
+	err = h.workflowRunner.Run(ctx, &workflow.Workflow{
+		Name: "Login",
+		Steps: []*workflow.Step{
+			{
+				AzdCommand: workflow.Command{
+					Args: challenge.LoginArgs(),
+				},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("signing in again: %w", err)
+	}
+
+	h.console.Message(ctx, "")
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/internal/tracing/resource"
+	"github.com/azure/azure-dev/cli/azd/pkg/auth"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/workflow"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingCommandRunner records the arguments of the azd commands it runs.
+type recordingCommandRunner struct {
+	commands []string
+	err      error
+}
+
+func (r *recordingCommandRunner) ExecuteContext(ctx context.Context, args []string) error {
+	r.commands = append(r.commands, strings.Join(args, " "))
+	return r.err
+}
+
+func Test_StepUpLoginHandler(t *testing.T) {
+	challenge := auth.ClaimsChallenge{
+		Claims:   `{"access_token":{}}`,
+		TenantID: "tenant-1",
+		Reason:   "Multi-factor authentication is required for this operation.",
+	}
+
+	t.Run("NoPrompt", func(t *testing.T) {
+		console := mockinput.NewMockConsole()
+		console.SetNoPromptMode(true)
+		runner := &recordingCommandRunner{}
+
+		err := newStepUpLoginHandler(console, workflow.NewRunner(runner, console)).StepUp(t.Context(), challenge)
+		require.ErrorIs(t, err, auth.ErrStepUpUnavailable)
+		require.Empty(t, runner.commands)
+	})
+
+	if resource.IsRunningOnCI() {
+		t.Skip("skipping: CI short-circuits before console Confirm")
+	}
+
+	t.Run("SignsInAgain", func(t *testing.T) {
+		console := mockinput.NewMockConsole()
+		console.WhenConfirm(func(options input.ConsoleOptions) bool {
+			return options.Message == "Would you like to sign in again now?"
+		}).Respond(true)
+		runner := &recordingCommandRunner{}
+
+		err := newStepUpLoginHandler(console, workflow.NewRunner(runner, console)).StepUp(t.Context(), challenge)
+		require.NoError(t, err)
+		require.Equal(t, []string{"auth login --claims eyJhY2Nlc3NfdG9rZW4iOnt9fQ== --tenant-id tenant-1"}, runner.commands)
+		require.Contains(t, console.Output(), "WARNING: Multi-factor authentication is required for this operation.")
+	})
+
+	t.Run("Declined", func(t *testing.T) {
+		console := mockinput.NewMockConsole()
+		console.WhenConfirm(func(options input.ConsoleOptions) bool {
+			return true
+		}).Respond(false)
+		runner := &recordingCommandRunner{}
+
+		err := newStepUpLoginHandler(console, workflow.NewRunner(runner, console)).StepUp(t.Context(), challenge)
+		require.ErrorIs(t, err, auth.ErrStepUpUnavailable)
+		require.Empty(t, runner.commands)
+	})
+
+	t.Run("LoginFailed", func(t *testing.T) {
+		console := mockinput.NewMockConsole()
+		console.WhenConfirm(func(options input.ConsoleOptions) bool {
+			return true
+		}).Respond(true)
+		runner := &recordingCommandRunner{err: errors.New("browser closed")}
+
+		err := newStepUpLoginHandler(console, workflow.NewRunner(runner, console)).StepUp(t.Context(), challenge)
+		require.ErrorContains(t, err, "browser closed")
+		require.NotErrorIs(t, err, auth.ErrStepUpUnavailable)
+	})
+}
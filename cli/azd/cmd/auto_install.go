@@ -722,6 +722,11 @@ func CreateGlobalFlagSet() *pflag.FlagSet {
 
 	globalFlags.StringP("cwd", "C", "", "Sets the current working directory.")
 	globalFlags.Bool("debug", false, "Enables debugging and diagnostics logging.")
+	globalFlags.Bool("quiet", false, "Only writes the result of the command and errors.")
+	globalFlags.Bool(
+		"verbose",
+		false,
+		"Writes more details, like the full output of tools and every provisioned resource.")
 	globalFlags.Bool(
 		"no-prompt",
 		false,
@@ -734,6 +739,16 @@ func CreateGlobalFlagSet() *pflag.FlagSet {
 		false,
 		"Alias for --no-prompt.")
 	_ = globalFlags.MarkHidden("non-interactive")
+	globalFlags.String(
+		"answers",
+		"",
+		"Answers prompts from a YAML file mapping prompt keys, like subscription, location or parameters.<name>, "+
+			"to values. Defaults to the AZD_ANSWERS_FILE environment variable.")
+	globalFlags.Int(
+		"prompt-timeout",
+		0,
+		"Waits at most the given number of seconds for the answer to a prompt, then uses its default value, "+
+			"or fails when it has none. Defaults to the AZD_PROMPT_TIMEOUT environment variable.")
 	globalFlags.StringP(internal.EnvironmentNameFlagName, "e", "", "The name of the environment to use.")
 
 	// The telemetry system is responsible for reading these flags value and using it to configure the telemetry
@@ -794,6 +809,14 @@ func ParseGlobalFlags(args []string, opts *internal.GlobalCommandOptions) error
 		opts.EnableDebugLogging = boolVal
 	}
 
+	if boolVal, err := globalFlagSet.GetBool("quiet"); err == nil {
+		opts.Quiet = boolVal
+	}
+
+	if boolVal, err := globalFlagSet.GetBool("verbose"); err == nil {
+		opts.Verbose = boolVal
+	}
+
 	// --non-interactive is an alias for --no-prompt; either flag sets NoPrompt.
 	// When both are present, true wins (either flag opting in is sufficient).
 	noPromptVal, _ := globalFlagSet.GetBool("no-prompt")
@@ -832,6 +855,30 @@ func ParseGlobalFlags(args []string, opts *internal.GlobalCommandOptions) error
 		}
 	}
 
+	// --answers takes precedence over the AZD_ANSWERS_FILE environment variable.
+	if strVal, err := globalFlagSet.GetString("answers"); err == nil && strVal != "" {
+		opts.AnswersFile = strVal
+	} else {
+		opts.AnswersFile = os.Getenv(input.AnswersFileEnvVarName)
+	}
+
+	// --prompt-timeout takes precedence over the AZD_PROMPT_TIMEOUT environment variable. Timeouts that aren't a
+	// positive number of seconds leave prompts waiting for their answer.
+	seconds, _ := globalFlagSet.GetInt("prompt-timeout")
+	if flag := globalFlagSet.Lookup("prompt-timeout"); flag == nil || !flag.Changed {
+		if envVal := os.Getenv(input.PromptTimeoutEnvVarName); envVal != "" {
+			if parsed, err := strconv.Atoi(envVal); err == nil {
+				seconds = parsed
+			} else {
+				log.Printf("warning: %s=%q is not a number of seconds, ignoring", input.PromptTimeoutEnvVarName, envVal)
+			}
+		}
+	}
+
+	if seconds > 0 {
+		opts.PromptTimeout = time.Duration(seconds) * time.Second
+	}
+
 	// Parse -e/--environment with lenient validation.
 	// Only accept values that look like valid environment names (alphanumeric, hyphens, dots,
 	// underscores). Values that don't match (e.g., URLs from extensions reusing -e for
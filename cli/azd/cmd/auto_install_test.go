@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
@@ -727,6 +728,75 @@ func TestParseGlobalFlags_EnvironmentName(t *testing.T) {
 	}
 }
 
+func TestParseGlobalFlags_AnswersFile(t *testing.T) {
+	clearAgentEnvVarsForTest(t)
+	agentdetect.ResetDetection()
+	t.Cleanup(agentdetect.ResetDetection)
+
+	opts := &internal.GlobalCommandOptions{}
+	require.NoError(t, ParseGlobalFlags([]string{"up"}, opts))
+	assert.Empty(t, opts.AnswersFile)
+
+	t.Setenv(input.AnswersFileEnvVarName, "env-answers.yaml")
+	opts = &internal.GlobalCommandOptions{}
+	require.NoError(t, ParseGlobalFlags([]string{"up"}, opts))
+	assert.Equal(t, "env-answers.yaml", opts.AnswersFile)
+
+	// The flag takes precedence over the environment variable
+	opts = &internal.GlobalCommandOptions{}
+	require.NoError(t, ParseGlobalFlags([]string{"--answers", "answers.yaml", "up"}, opts))
+	assert.Equal(t, "answers.yaml", opts.AnswersFile)
+}
+
+func TestParseGlobalFlags_PromptTimeout(t *testing.T) {
+	clearAgentEnvVarsForTest(t)
+	agentdetect.ResetDetection()
+	t.Cleanup(agentdetect.ResetDetection)
+
+	opts := &internal.GlobalCommandOptions{}
+	require.NoError(t, ParseGlobalFlags([]string{"up"}, opts))
+	assert.Zero(t, opts.PromptTimeout)
+
+	t.Setenv(input.PromptTimeoutEnvVarName, "30")
+	opts = &internal.GlobalCommandOptions{}
+	require.NoError(t, ParseGlobalFlags([]string{"up"}, opts))
+	assert.Equal(t, 30*time.Second, opts.PromptTimeout)
+
+	// The flag takes precedence over the environment variable, and 0 disables the timeout
+	opts = &internal.GlobalCommandOptions{}
+	require.NoError(t, ParseGlobalFlags([]string{"--prompt-timeout", "0", "up"}, opts))
+	assert.Zero(t, opts.PromptTimeout)
+
+	opts = &internal.GlobalCommandOptions{}
+	require.NoError(t, ParseGlobalFlags([]string{"--prompt-timeout=5", "up"}, opts))
+	assert.Equal(t, 5*time.Second, opts.PromptTimeout)
+
+	// Invalid environment variable values are ignored
+	t.Setenv(input.PromptTimeoutEnvVarName, "5m")
+	opts = &internal.GlobalCommandOptions{}
+	require.NoError(t, ParseGlobalFlags([]string{"up"}, opts))
+	assert.Zero(t, opts.PromptTimeout)
+}
+
+func TestParseGlobalFlags_Verbosity(t *testing.T) {
+	clearAgentEnvVarsForTest(t)
+	agentdetect.ResetDetection()
+	t.Cleanup(agentdetect.ResetDetection)
+
+	opts := &internal.GlobalCommandOptions{}
+	require.NoError(t, ParseGlobalFlags([]string{"up"}, opts))
+	assert.False(t, opts.Quiet)
+	assert.False(t, opts.Verbose)
+
+	opts = &internal.GlobalCommandOptions{}
+	require.NoError(t, ParseGlobalFlags([]string{"up", "--quiet"}, opts))
+	assert.True(t, opts.Quiet)
+
+	opts = &internal.GlobalCommandOptions{}
+	require.NoError(t, ParseGlobalFlags([]string{"--verbose", "up"}, opts))
+	assert.True(t, opts.Verbose)
+}
+
 func TestParseGlobalFlags_InvalidEnvironmentName(t *testing.T) {
 	// Invalid environment names are silently ignored (not errors) so that
 	// third-party extensions reusing -e for their own flags (e.g., URLs)
@@ -17,6 +17,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/progress"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/azure/azure-dev/cli/azd/pkg/workflow"
 	"github.com/spf13/cobra"
@@ -70,6 +71,7 @@ type buildAction struct {
 	importManager  *project.ImportManager
 	serviceManager project.ServiceManager
 	console        input.Console
+	reporter       progress.Reporter
 	formatter      output.Formatter
 	writer         io.Writer
 	workflowRunner *workflow.Runner
@@ -83,6 +85,7 @@ func newBuildAction(
 	importManager *project.ImportManager,
 	serviceManager project.ServiceManager,
 	console input.Console,
+	reporter progress.Reporter,
 	formatter output.Formatter,
 	writer io.Writer,
 	workflowRunner *workflow.Runner,
@@ -95,6 +98,7 @@ func newBuildAction(
 		projectManager: projectManager,
 		serviceManager: serviceManager,
 		console:        console,
+		reporter:       reporter,
 		formatter:      formatter,
 		writer:         writer,
 		importManager:  importManager,
@@ -176,25 +180,23 @@ func (ba *buildAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 
 	err = ba.projectConfig.Invoke(ctx, project.ProjectEventBuild, projectEventArgs, func() error {
 		for _, svc := range stableServices {
-			stepMessage := fmt.Sprintf("Building service %s", svc.Name)
-			ba.console.ShowSpinner(ctx, stepMessage, input.Step)
+			step := progress.Start(
+				ctx, ba.reporter, progress.PhaseBuild, svc.Name, fmt.Sprintf("Building service %s", svc.Name))
 
 			buildResult, err := async.RunWithProgress(
 				func(buildProgress project.ServiceProgress) {
-					progressMessage := fmt.Sprintf("Building service %s (%s)", svc.Name, buildProgress.Message)
-					ba.console.ShowSpinner(ctx, progressMessage, input.Step)
+					step.Update(buildProgress.Message)
 				},
 				func(progress *async.Progress[project.ServiceProgress]) (*project.ServiceBuildResult, error) {
 					return ba.serviceManager.Build(ctx, svc, nil, progress)
 				},
 			)
+			step.Done(err)
 
 			if err != nil {
-				ba.console.StopSpinner(ctx, stepMessage, input.StepFailed)
 				return err
 			}
 
-			ba.console.StopSpinner(ctx, stepMessage, input.StepDone)
 			buildResults[svc.Name] = buildResult
 
 			// report build outputs
@@ -13,6 +13,7 @@ import (
 
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/progress"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
 )
 
@@ -23,7 +24,7 @@ func Test_NewBuildAction_Constructor(t *testing.T) {
 	console := mockinput.NewMockConsole()
 	formatter := &output.JsonFormatter{}
 	a := newBuildAction(
-		flags, args, nil, nil, nil, nil, console, formatter, io.Discard, nil,
+		flags, args, nil, nil, nil, nil, console, progress.Discard, formatter, io.Discard, nil,
 	)
 	ba := a.(*buildAction)
 	require.Same(t, flags, ba.flags)
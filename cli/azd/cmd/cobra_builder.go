@@ -56,8 +56,9 @@ func (cb *CobraBuilder) BuildCommand(descriptor *actions.ActionDescriptor) (*cob
 		}
 	}
 
-	// Configure action resolver for leaf commands
-	if !cmd.HasSubCommands() {
+	// Configure action resolver for leaf commands, and for commands like 'provision [<layer>]' that accept
+	// positional arguments of their own in addition to hosting subcommands
+	if !cmd.HasSubCommands() || (cmd.Args != nil && descriptor.Options.ActionResolver != nil) {
 		if err := cb.configureActionResolver(cmd, descriptor); err != nil {
 			return nil, err
 		}
@@ -235,11 +236,6 @@ func (cb *CobraBuilder) bindCommand(cmd *cobra.Command, descriptor *actions.Acti
 	// Consistently registers output formats for the descriptor
 	if len(descriptor.Options.OutputFormats) > 0 {
 		output.AddOutputParam(cmd, descriptor.Options.OutputFormats, descriptor.Options.DefaultFormat)
-
-		// Add query flag only for commands that support JSON format
-		if slices.Contains(descriptor.Options.OutputFormats, output.JsonFormat) {
-			output.AddQueryParam(cmd)
-		}
 	}
 
 	// Create, register and bind flags when required
@@ -258,6 +254,12 @@ func (cb *CobraBuilder) bindCommand(cmd *cobra.Command, descriptor *actions.Acti
 		}
 	}
 
+	// Add the JMESPath query flag only for commands that support JSON format, once the flags of the command are bound
+	// so a --query flag of its own takes precedence
+	if slices.Contains(descriptor.Options.OutputFormats, output.JsonFormat) {
+		output.AddQueryParam(cmd)
+	}
+
 	// Registers and bind action resolves when required
 	// Action resolvers are essential go functions that create the instance of the required actions.Action
 	// These functions are typically the constructor function for the action. ex) newDeployAction(...)
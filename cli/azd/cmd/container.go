@@ -13,6 +13,7 @@ import (
 	"os"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
@@ -60,9 +61,11 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/kubelogin"
 	"github.com/azure/azure-dev/cli/azd/pkg/kustomize"
 	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/lockfile"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/pipeline"
 	"github.com/azure/azure-dev/cli/azd/pkg/platform"
+	"github.com/azure/azure-dev/cli/azd/pkg/progress"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
 	"github.com/azure/azure-dev/cli/azd/pkg/state"
@@ -70,12 +73,14 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/tool"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/az"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/bash"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/devtunnel"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/github"
 	golangtools "github.com/azure/azure-dev/cli/azd/pkg/tools/golang"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/javac"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kiota"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/language"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/maven"
@@ -83,6 +88,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/powershell"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/python"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/swa"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/syft"
 	"github.com/azure/azure-dev/cli/azd/pkg/workflow"
 	"github.com/mattn/go-colorable"
 	"github.com/spf13/cobra"
@@ -117,6 +123,10 @@ func resolveAction[T actions.Action](serviceLocator ioc.ServiceLocator, actionNa
 	return instance, nil
 }
 
+// accessibleConfigKey is the user config key that enables the accessible output of the console, with plain status
+// lines instead of spinners and progress redraws.
+const accessibleConfigKey = "ux.accessible"
+
 // Registers common Azd dependencies
 func registerCommonDependencies(container *ioc.NestedContainer) {
 	// Core bootstrapping registrations
@@ -129,7 +139,8 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.MustRegisterScoped(func(
 		rootOptions *internal.GlobalCommandOptions,
 		formatter output.Formatter,
-		cmd *cobra.Command) input.Console {
+		userConfigManager config.UserConfigManager,
+		cmd *cobra.Command) (input.Console, error) {
 		writer := cmd.OutOrStdout()
 		// When using JSON formatting, we want to ensure we always write messages from the console to stderr.
 		if formatter != nil && formatter.Kind() == output.JsonFormat {
@@ -156,11 +167,46 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 			}
 		}
 
-		return input.NewConsole(rootOptions.NoPrompt, isTerminal, input.Writers{Output: writer}, input.ConsoleHandles{
+		console := input.NewConsole(rootOptions.NoPrompt, isTerminal, input.Writers{Output: writer}, input.ConsoleHandles{
 			Stdin:  cmd.InOrStdin(),
 			Stdout: cmd.OutOrStdout(),
 			Stderr: cmd.ErrOrStderr(),
 		}, formatter, externalPromptCfg)
+
+		if askerConsole, ok := console.(*input.AskerConsole); ok {
+			// The most verbose of --quiet, --verbose and --debug applies.
+			switch {
+			case rootOptions.EnableDebugLogging:
+				askerConsole.SetVerbosity(input.VerbosityDebug)
+			case rootOptions.Verbose:
+				askerConsole.SetVerbosity(input.VerbosityVerbose)
+			case rootOptions.Quiet:
+				askerConsole.SetVerbosity(input.VerbosityQuiet)
+			}
+		}
+
+		if userConfig, err := userConfigManager.Load(); err != nil {
+			log.Printf("failed to load user config for the console, using the default output: %v", err)
+		} else if value, has := userConfig.GetString(accessibleConfigKey); has && value == "on" {
+			if askerConsole, ok := console.(*input.AskerConsole); ok {
+				askerConsole.EnableAccessibleMode()
+			}
+		}
+
+		if rootOptions.PromptTimeout > 0 {
+			console = input.NewTimeoutConsole(console, rootOptions.PromptTimeout)
+		}
+
+		if rootOptions.AnswersFile == "" {
+			return console, nil
+		}
+
+		answers, err := input.LoadAnswers(rootOptions.AnswersFile)
+		if err != nil {
+			return nil, err
+		}
+
+		return input.NewAnswersConsole(console, answers), nil
 	})
 
 	container.MustRegisterSingleton(
@@ -195,6 +241,8 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		return writer
 	})
 
+	container.MustRegisterScoped(progress.NewReporter)
+
 	container.MustRegisterScoped(func(
 		ctx context.Context,
 		cmd *cobra.Command,
@@ -277,6 +325,11 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 			// This allows any previous lazy instances (such as hooks) to now point to the same instance
 			lazyEnv.SetValue(env)
 
+			// Shown by `azd env list`, to find the environments that are no longer used.
+			if err := azdContext.SetEnvironmentLastUsed(env.Name(), time.Now()); err != nil {
+				log.Printf("recording the use of environment '%s': %v", env.Name(), err)
+			}
+
 			return env, nil
 		},
 	)
@@ -301,8 +354,29 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		}
 	})
 
-	container.MustRegisterSingleton(environment.NewLocalFileDataStore)
+	container.MustRegisterSingleton(func(
+		azdContext *azdcontext.AzdContext,
+		configManager config.FileConfigManager,
+		featureManager *alpha.FeatureManager,
+	) environment.LocalDataStore {
+		if featureManager.IsEnabled(environment.EncryptSecretsFeature) {
+			return environment.NewLocalFileDataStoreWithSecretEncryption(
+				azdContext, configManager, environment.NewLocalSecretCipher())
+		}
+
+		return environment.NewLocalFileDataStore(azdContext, configManager)
+	})
 	container.MustRegisterSingleton(environment.NewManager)
+	container.MustRegisterSingleton(func(
+		azdContext *azdcontext.AzdContext,
+		featureManager *alpha.FeatureManager,
+	) *environment.SnapshotStore {
+		if featureManager.IsEnabled(environment.EncryptSecretsFeature) {
+			return environment.NewSnapshotStoreWithSecretEncryption(azdContext, environment.NewLocalSecretCipher())
+		}
+
+		return environment.NewSnapshotStore(azdContext)
+	})
 
 	container.MustRegisterSingleton(func(serviceLocator ioc.ServiceLocator) *lazy.Lazy[environment.LocalDataStore] {
 		return lazy.NewLazy(func() (environment.LocalDataStore, error) {
@@ -539,7 +613,7 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 
 	container.MustRegisterSingleton(func(transport policy.Transporter, cloud *cloud.Cloud) *azcore.ClientOptions {
 		return &azcore.ClientOptions{
-			Cloud: cloud.Configuration,
+			Cloud: cloud.ClientConfiguration(),
 			PerCallPolicies: []policy.Policy{
 				azsdk.NewMsCorrelationPolicy(),
 				azsdk.NewUserAgentPolicy(internal.UserAgent()),
@@ -660,6 +734,7 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.MustRegisterSingleton(func() auth.UserAgent {
 		return auth.UserAgent(internal.UserAgent())
 	})
+	container.MustRegisterScoped(newStepUpLoginHandler)
 	container.MustRegisterScoped(auth.NewManager)
 	container.MustRegisterSingleton(azapi.NewUserProfileService)
 	container.MustRegisterScoped(func(authManager *auth.Manager) middleware.CurrentUserAuthManager {
@@ -670,6 +745,7 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.MustRegisterSingleton(account.NewSubscriptionsManager)
 	container.MustRegisterSingleton(account.NewSubscriptionCredentialProvider)
 	container.MustRegisterSingleton(azapi.NewManagedClustersService)
+	container.MustRegisterSingleton(azapi.NewFleetsService)
 	container.MustRegisterSingleton(entraid.NewEntraIdService)
 	container.MustRegisterSingleton(armmsi.NewArmMsiService)
 	container.MustRegisterSingleton(azapi.NewContainerRegistryService)
@@ -720,7 +796,14 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 
 	// Tools
 	container.MustRegisterSingleton(azapi.NewResourceService)
+	container.MustRegisterSingleton(azapi.NewPurgeService)
 	container.MustRegisterSingleton(azapi.NewPermissionsService)
+	container.MustRegisterSingleton(azapi.NewPolicyRestrictionsService)
+	container.MustRegisterSingleton(azapi.NewResourceCleanupService)
+	container.MustRegisterSingleton(azapi.NewRegionalCapacityService)
+	container.MustRegisterSingleton(azapi.NewApiCenterService)
+	container.MustRegisterSingleton(azapi.NewFeatureFlagsService)
+	container.MustRegisterSingleton(devtunnel.NewCli)
 	container.MustRegisterSingleton(docker.NewCli)
 	container.MustRegisterSingleton(dotnet.NewCli)
 	container.MustRegisterSingleton(git.NewCli)
@@ -735,6 +818,8 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.MustRegisterSingleton(node.NewCli)
 	container.MustRegisterSingleton(python.NewCli)
 	container.MustRegisterSingleton(swa.NewCli)
+	container.MustRegisterSingleton(kiota.NewCli)
+	container.MustRegisterSingleton(syft.NewCli)
 	container.MustRegisterScoped(ai.NewPythonBridge)
 	container.MustRegisterScoped(project.NewAiHelper)
 	container.MustRegisterSingleton(az.NewCli)
@@ -743,13 +828,16 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.MustRegisterSingleton(func(
 		serviceLocator ioc.ServiceLocator,
 		featureManager *alpha.FeatureManager,
+		lazyProjectConfig *lazy.Lazy[*project.ProjectConfig],
 	) (azapi.DeploymentService, error) {
-		deploymentsType := azapi.DeploymentTypeStandard
-
-		if featureManager.IsEnabled(azapi.FeatureDeploymentStacks) {
-			deploymentsType = azapi.DeploymentTypeStacks
+		// Commands that run outside of a project fall back to the alpha feature setting.
+		var infraOptions provisioning.Options
+		if projectConfig, err := lazyProjectConfig.GetValue(); err == nil {
+			infraOptions = projectConfig.Infra
 		}
 
+		deploymentsType := provisioning.DeploymentType(infraOptions, featureManager)
+
 		var deployments azapi.DeploymentService
 		if err := serviceLocator.ResolveNamed(string(deploymentsType), &deployments); err != nil {
 			return nil, err
@@ -833,6 +921,7 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		language.HookKindJavaScript: language.NewJavaScriptExecutor,
 		language.HookKindTypeScript: language.NewTypeScriptExecutor,
 		language.HookKindDotNet:     language.NewDotNetExecutor,
+		language.HookKindDocker:     language.NewDockerExecutor,
 	}
 
 	for kind, constructor := range hookExecutorMap {
@@ -841,6 +930,8 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 
 	// Pipelines
 	container.MustRegisterScoped(pipeline.NewPipelineManager)
+	container.MustRegisterSingleton(pipeline.NewCleanupManager)
+	container.MustRegisterSingleton(pipeline.NewFederationManager)
 	container.MustRegisterSingleton(func(flags *pipelineConfigFlags) *pipeline.PipelineManagerArgs {
 		return &flags.PipelineManagerArgs
 	})
@@ -850,6 +941,8 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		"github-scm": pipeline.NewGitHubScmProvider,
 		"azdo-ci":    pipeline.NewAzdoCiProvider,
 		"azdo-scm":   pipeline.NewAzdoScmProvider,
+		"gitlab-ci":  pipeline.NewGitLabCiProvider,
+		"gitlab-scm": pipeline.NewGitLabScmProvider,
 	}
 
 	for provider, constructor := range pipelineProviderMap {
@@ -1003,6 +1096,7 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	) *tool.Manager {
 		return tool.NewManager(detector, installer, updateChecker)
 	})
+	container.MustRegisterSingleton(lockfile.NewManager)
 
 	// gRPC Server
 	container.MustRegisterScoped(grpcserver.NewServer)
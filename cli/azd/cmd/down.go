@@ -16,6 +16,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	inf "github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
@@ -29,6 +30,7 @@ import (
 type downFlags struct {
 	forceDelete bool
 	purgeDelete bool
+	keep        []string
 	global      *internal.GlobalCommandOptions
 	internal.EnvFlag
 }
@@ -43,6 +45,13 @@ func (i *downFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOpt
 		"Does not require confirmation before it permanently deletes resources that are soft-deleted by default (for example, key vaults).",
 	)
 
+	local.StringArrayVar(
+		&i.keep,
+		"keep",
+		nil,
+		"Keeps the resources with the given name, type or resource ID instead of deleting them (can be repeated).",
+	)
+
 	i.EnvFlag.Bind(local, global)
 	i.global = global
 }
@@ -66,6 +75,7 @@ func newDownCmd() *cobra.Command {
 type downAction struct {
 	flags               *downFlags
 	args                []string
+	azdCtx              *azdcontext.AzdContext
 	provisionManager    *provisioning.Manager
 	importManager       *project.ImportManager
 	env                 *environment.Environment
@@ -73,11 +83,13 @@ type downAction struct {
 	console             input.Console
 	projectConfig       *project.ProjectConfig
 	alphaFeatureManager *alpha.FeatureManager
+	azCli               *azapi.AzureClient
 }
 
 func newDownAction(
 	args []string,
 	flags *downFlags,
+	azdCtx *azdcontext.AzdContext,
 	provisionManager *provisioning.Manager,
 	env *environment.Environment,
 	envManager environment.Manager,
@@ -85,9 +97,11 @@ func newDownAction(
 	console input.Console,
 	alphaFeatureManager *alpha.FeatureManager,
 	importManager *project.ImportManager,
+	azCli *azapi.AzureClient,
 ) actions.Action {
 	return &downAction{
 		flags:               flags,
+		azdCtx:              azdCtx,
 		provisionManager:    provisionManager,
 		env:                 env,
 		envManager:          envManager,
@@ -95,6 +109,7 @@ func newDownAction(
 		projectConfig:       projectConfig,
 		importManager:       importManager,
 		alphaFeatureManager: alphaFeatureManager,
+		azCli:               azCli,
 		args:                args,
 	}
 }
@@ -114,8 +129,25 @@ func (a *downAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	}
 	defer func() { _ = infra.Cleanup() }()
 
-	if a.alphaFeatureManager.IsEnabled(azapi.FeatureDeploymentStacks) {
+	// Resources of deployment stacks are deleted with the stack, so they can't be kept.
+	stacks := provisioning.DeploymentType(a.projectConfig.Infra, a.alphaFeatureManager) == azapi.DeploymentTypeStacks
+	if stacks {
 		a.console.WarnForFeature(ctx, azapi.FeatureDeploymentStacks)
+
+		if len(a.flags.keep) > 0 {
+			return nil, &internal.ErrorWithSuggestion{
+				Err:        fmt.Errorf("%w: --keep isn't supported with deployment stacks", internal.ErrInvalidFlagCombination),
+				Suggestion: "Remove --keep, or delete the resources manually in the Azure Portal.",
+			}
+		}
+
+		if infra.Options.HasProtectedResources() {
+			return nil, &internal.ErrorWithSuggestion{
+				Err: fmt.Errorf("%w: protected resources aren't supported with deployment stacks",
+					internal.ErrInvalidFlagCombination),
+				Suggestion: "Remove 'protect' from the infra section of azure.yaml, or use standard deployments.",
+			}
+		}
 	}
 
 	downLayer := ""
@@ -138,6 +170,7 @@ func (a *downAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	a.provisionManager.RecordInfraProviderUsage(ctx, layers)
 
 	skippedDeletion := false
+	retainedResources := 0
 	for _, layer := range layers {
 		if downLayer != "" || len(layers) > 1 {
 			a.console.EnsureBlankLine(ctx)
@@ -150,14 +183,17 @@ func (a *downAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 			return nil, fmt.Errorf("initializing provisioning manager: %w", err)
 		}
 
-		destroyOptions := provisioning.NewDestroyOptions(a.flags.forceDelete, a.flags.purgeDelete)
+		destroyOptions := provisioning.NewDestroyOptions(a.flags.forceDelete, a.flags.purgeDelete).
+			WithKeep(a.flags.keep, !stacks).
+			WithProtect(infra.Options.ProtectedResources(layer))
 		destroyResult, err := a.provisionManager.Destroy(ctx, destroyOptions)
 		if errors.Is(err, inf.ErrDeploymentsNotFound) || errors.Is(err, inf.ErrDeploymentResourcesNotFound) {
 			a.console.MessageUxItem(ctx, &ux.DoneMessage{Message: "No Azure resources were found."})
 		} else if err != nil {
 			return nil, fmt.Errorf("deleting infrastructure: %w", err)
-		} else if destroyResult != nil && destroyResult.SkippedDeletion {
-			skippedDeletion = true
+		} else if destroyResult != nil {
+			skippedDeletion = skippedDeletion || destroyResult.SkippedDeletion
+			retainedResources += len(destroyResult.RetainedResources)
 		}
 	}
 
@@ -172,9 +208,39 @@ func (a *downAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		return &actions.ActionResult{}, nil
 	}
 
+	// Preview environments can be deployed to a static web app that isn't deleted with the resources of the
+	// environment, so they are deleted explicitly.
+	if len(project.PreviewEnvironments(a.env)) > 0 {
+		spinnerMessage := "Deleting Static Web App preview environments"
+		a.console.ShowSpinner(ctx, spinnerMessage, input.Step)
+		err := project.DeletePreviewEnvironments(ctx, a.azCli, a.env)
+		a.console.StopSpinner(ctx, spinnerMessage, input.GetStepResultFormat(err))
+		if err != nil {
+			return nil, fmt.Errorf("deleting preview environments: %w", err)
+		}
+	}
+
+	// The deployed code was deleted with the resources, so the next deploy must not skip unchanged services, and
+	// the services can't be rolled back to the packages deployed to the deleted resources.
+	history := project.NewDeploymentHistory(a.env, a.azdCtx.EnvironmentRoot(a.env.Name()))
+	for name := range a.projectConfig.Services {
+		a.env.DotenvDelete(fmt.Sprintf("SERVICE_%s_%s", environment.Key(name), project.PackageHashProperty))
+		if err := history.Clear(name); err != nil {
+			log.Printf("clearing deployment history: %v", err)
+		}
+	}
+	if err := a.envManager.Save(ctx, a.env); err != nil {
+		return nil, fmt.Errorf("saving environment: %w", err)
+	}
+
+	header := fmt.Sprintf("Your application was removed from Azure in %s.", ux.DurationAsText(since(startTime)))
+	if retainedResources > 0 {
+		header += fmt.Sprintf(" %d protected resource(s) were retained.", retainedResources)
+	}
+
 	return &actions.ActionResult{
 		Message: &actions.ResultMessage{
-			Header: fmt.Sprintf("Your application was removed from Azure in %s.", ux.DurationAsText(since(startTime))),
+			Header: header,
 		},
 	}, nil
 }
@@ -185,6 +251,9 @@ func getCmdDownHelpDescription(*cobra.Command) string {
 			" files on your local machine.", output.WithHighLightFormat("azd down")), []string{
 		"When <layer> is specified, only deletes resources for the given layer." +
 			" When omitted, deletes resources for all layers defined in the project.",
+		"For Bicep projects, running interactively lists the resources to delete and lets you deselect" +
+			" the resources to keep. Use --keep to keep resources without prompting.",
+		"Resources protected in the infra section of azure.yaml, by name pattern or tag, are always retained.",
 		"For Terraform projects, running non-interactively (in a CI/CD pipeline or with --no-prompt)" +
 			" without --force previews the resources that would be deleted and exits without deleting" +
 			" anything. Re-run with --force to delete resources without confirmation.",
@@ -198,5 +267,8 @@ func getCmdDownHelpFooter(*cobra.Command) string {
 		"Forcibly delete all applications resources without confirmation.": output.WithHighLightFormat("azd down --force"),
 		"Permanently delete resources that are soft-deleted by default," +
 			" without confirmation.": output.WithHighLightFormat("azd down --purge"),
+		"Delete all resources except the key vaults and the resource named 'mydb'," +
+			" without confirmation.": output.WithHighLightFormat(
+			"azd down --force --keep Microsoft.KeyVault/vaults --keep mydb"),
 	})
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
@@ -31,7 +32,7 @@ func Test_NewDownAction_Constructor(t *testing.T) {
 	t.Parallel()
 	flags := &downFlags{}
 	console := mockinput.NewMockConsole()
-	a := newDownAction(nil, flags, nil, nil, nil, nil, console, nil, nil)
+	a := newDownAction(nil, flags, nil, nil, nil, nil, nil, console, nil, nil, nil)
 	da := a.(*downAction)
 	require.Same(t, flags, da.flags)
 }
@@ -146,16 +147,27 @@ func Test_DownAction_RecordsInfraProvider(t *testing.T) {
 // mockRefreshProvider for the remaining interface methods.
 type mockDownProvider struct {
 	*mockRefreshProvider
-	destroyResult *provisioning.DestroyResult
-	destroyErr    error
+	destroyResult  *provisioning.DestroyResult
+	destroyErr     error
+	destroyOptions *provisioning.DestroyOptions
 }
 
 func (p *mockDownProvider) Destroy(
-	_ context.Context, _ provisioning.DestroyOptions,
+	_ context.Context, options provisioning.DestroyOptions,
 ) (*provisioning.DestroyResult, error) {
+	p.destroyOptions = &options
 	return p.destroyResult, p.destroyErr
 }
 
+// mockKeepingDownProvider is a mockDownProvider that supports keeping resources.
+type mockKeepingDownProvider struct {
+	*mockDownProvider
+}
+
+func (p *mockKeepingDownProvider) CanKeepResources() bool {
+	return true
+}
+
 // newTestDownAction wires a downAction against a real provisioning.Manager backed by the given
 // mock provider, mirroring newTestEnvRefreshAction.
 func newTestDownAction(
@@ -204,6 +216,7 @@ func newTestDownAction(
 
 	action := &downAction{
 		flags:               &downFlags{global: &internal.GlobalCommandOptions{}},
+		azdCtx:              azdcontext.NewAzdContextWithDirectory(t.TempDir()),
 		provisionManager:    provisionManager,
 		env:                 env,
 		envManager:          envManager,
@@ -249,3 +262,101 @@ func Test_DownAction_Run_Deleted(t *testing.T) {
 	require.NotNil(t, result.Message)
 	require.Contains(t, result.Message.Header, "Your application was removed")
 }
+
+// Test_DownAction_Run_Keep verifies that the resources to keep are passed to providers that can keep resources, and
+// rejected otherwise.
+func Test_DownAction_Run_Keep(t *testing.T) {
+	t.Run("Kept", func(t *testing.T) {
+		provider := &mockKeepingDownProvider{
+			mockDownProvider: &mockDownProvider{
+				mockRefreshProvider: &mockRefreshProvider{},
+				destroyResult:       &provisioning.DestroyResult{},
+			},
+		}
+		action, _, _ := newTestDownAction(t, provider)
+		action.flags.keep = []string{"Microsoft.KeyVault/vaults", "mydb"}
+
+		_, err := action.Run(t.Context())
+
+		require.NoError(t, err)
+		require.NotNil(t, provider.destroyOptions)
+		require.Equal(t, []string{"Microsoft.KeyVault/vaults", "mydb"}, provider.destroyOptions.Keep())
+		require.True(t, provider.destroyOptions.Selectable())
+	})
+
+	t.Run("NotSupportedByProvider", func(t *testing.T) {
+		provider := &mockDownProvider{
+			mockRefreshProvider: &mockRefreshProvider{},
+			destroyResult:       &provisioning.DestroyResult{},
+		}
+		action, _, _ := newTestDownAction(t, provider)
+		action.flags.keep = []string{"mydb"}
+
+		_, err := action.Run(t.Context())
+
+		require.ErrorIs(t, err, provisioning.ErrKeepNotSupportedByProvider)
+		require.Nil(t, provider.destroyOptions)
+	})
+
+	t.Run("DeploymentStacks", func(t *testing.T) {
+		provider := &mockKeepingDownProvider{
+			mockDownProvider: &mockDownProvider{
+				mockRefreshProvider: &mockRefreshProvider{},
+				destroyResult:       &provisioning.DestroyResult{},
+			},
+		}
+		action, _, _ := newTestDownAction(t, provider)
+		action.projectConfig.Infra.DeploymentMode = provisioning.DeploymentModeStacks
+		action.flags.keep = []string{"mydb"}
+
+		_, err := action.Run(t.Context())
+
+		require.ErrorIs(t, err, internal.ErrInvalidFlagCombination)
+		require.Nil(t, provider.destroyOptions)
+	})
+}
+
+// Test_DownAction_Run_Protect verifies that the resources protected in azure.yaml are passed to the provider, and that
+// the resources it retained are reported.
+func Test_DownAction_Run_Protect(t *testing.T) {
+	t.Run("Retained", func(t *testing.T) {
+		provider := &mockKeepingDownProvider{
+			mockDownProvider: &mockDownProvider{
+				mockRefreshProvider: &mockRefreshProvider{},
+				destroyResult: &provisioning.DestroyResult{
+					RetainedResources: []string{
+						"/subscriptions/SUB/resourceGroups/rg-shared/providers/Microsoft.ContainerRegistry/registries/acr1",
+					},
+				},
+			},
+		}
+		action, _, _ := newTestDownAction(t, provider)
+		action.projectConfig.Infra.Protect = []provisioning.ProtectedResource{{Name: "acr*"}}
+		action.projectConfig.Infra.Layers[0].Protect = []provisioning.ProtectedResource{{Tag: "shared=true"}}
+
+		result, err := action.Run(t.Context())
+
+		require.NoError(t, err)
+		require.NotNil(t, provider.destroyOptions)
+		require.Equal(t, []provisioning.ProtectedResource{{Name: "acr*"}, {Tag: "shared=true"}},
+			provider.destroyOptions.Protect())
+		require.Contains(t, result.Message.Header, "1 protected resource(s) were retained.")
+	})
+
+	t.Run("DeploymentStacks", func(t *testing.T) {
+		provider := &mockKeepingDownProvider{
+			mockDownProvider: &mockDownProvider{
+				mockRefreshProvider: &mockRefreshProvider{},
+				destroyResult:       &provisioning.DestroyResult{},
+			},
+		}
+		action, _, _ := newTestDownAction(t, provider)
+		action.projectConfig.Infra.DeploymentMode = provisioning.DeploymentModeStacks
+		action.projectConfig.Infra.Protect = []provisioning.ProtectedResource{{Name: "acr*"}}
+
+		_, err := action.Run(t.Context())
+
+		require.ErrorIs(t, err, internal.ErrInvalidFlagCombination)
+		require.Nil(t, provider.destroyOptions)
+	})
+}
@@ -9,7 +9,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
+	"maps"
 	"os"
+	"path/filepath"
 	"slices"
 	"strings"
 	"time"
@@ -63,6 +66,12 @@ func envActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 		ActionResolver: newEnvSetAction,
 	})
 
+	group.Add("set-json", &actions.ActionDescriptorOptions{
+		Command:        newEnvSetJsonCmd(),
+		FlagsResolver:  newEnvSetJsonFlags,
+		ActionResolver: newEnvSetJsonAction,
+	})
+
 	group.Add("set-secret", &actions.ActionDescriptorOptions{
 		Command: &cobra.Command{
 			Use:   "set-secret <name>",
@@ -85,6 +94,15 @@ func envActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 		ActionResolver: newEnvNewAction,
 	})
 
+	group.Add("copy", &actions.ActionDescriptorOptions{
+		Command:        newEnvCopyCmd(),
+		FlagsResolver:  newEnvCopyFlags,
+		ActionResolver: newEnvCopyAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdEnvCopyHelpDescription,
+		},
+	})
+
 	group.Add("remove", &actions.ActionDescriptorOptions{
 		Command:        newEnvRemoveCmd(),
 		FlagsResolver:  newEnvRemoveFlags,
@@ -94,8 +112,19 @@ func envActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 		},
 	})
 
+	group.Add("prune", &actions.ActionDescriptorOptions{
+		Command:        newEnvPruneCmd(),
+		FlagsResolver:  newEnvPruneFlags,
+		ActionResolver: newEnvPruneAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdEnvPruneHelpDescription,
+			Footer:      getCmdEnvPruneHelpFooter,
+		},
+	})
+
 	group.Add("list", &actions.ActionDescriptorOptions{
 		Command:        newEnvListCmd(),
+		FlagsResolver:  newEnvListFlags,
 		ActionResolver: newEnvListAction,
 		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
 		DefaultFormat:  output.TableFormat,
@@ -123,6 +152,9 @@ func envActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 		ActionResolver: newEnvGetValueAction,
 	})
 
+	envSnapshotActions(group)
+	envTagActions(group)
+
 	// Add env config sub-command group
 	configGroup := group.Add("config", &actions.ActionDescriptorOptions{
 		Command: &cobra.Command{
@@ -180,13 +212,32 @@ func newEnvSetCmd() *cobra.Command {
 
 type envSetFlags struct {
 	internal.EnvFlag
-	global *internal.GlobalCommandOptions
-	file   string
+	global    *internal.GlobalCommandOptions
+	file      string
+	secret    bool
+	valueType string
 }
 
 func (f *envSetFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
 	f.EnvFlag.Bind(local, global)
 	local.StringVar(&f.file, "file", "", "Path to .env formatted file to load environment values from.")
+	local.BoolVar(
+		&f.secret,
+		"secret",
+		false,
+		"Marks the values as secrets, which are redacted from JSON output and encrypted when the env.encryptSecrets "+
+			"alpha feature is on.",
+	)
+	local.StringVar(
+		&f.valueType,
+		"type",
+		"",
+		fmt.Sprintf(
+			"Validates and normalizes the values as the given type, which azd env get-values --output json uses. "+
+				"Allowed values: %s.",
+			strings.Join(environment.ValueTypes, ", "),
+		),
+	)
 	f.global = global
 }
 
@@ -274,12 +325,152 @@ func (e *envSetAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		}
 	}
 
+	if e.flags.valueType != "" && !slices.Contains(environment.ValueTypes, e.flags.valueType) {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("invalid value type '%s' for --type: %w", e.flags.valueType, internal.ErrInvalidArgValue),
+			Suggestion: fmt.Sprintf(
+				"Use one of the supported value types: %s.", strings.Join(environment.ValueTypes, ", ")),
+		}
+	}
+
+	// Validate all the values before setting any of them
+	valueTypes := map[string]string{}
+	if e.flags.valueType != "" {
+		for key, value := range keyValues {
+			normalized, outputType, err := environment.NormalizeValue(e.flags.valueType, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s value for %s: %w", e.flags.valueType, key, err)
+			}
+
+			keyValues[key] = normalized
+			valueTypes[key] = outputType
+		}
+	}
+
 	// Apply the values
 	for key, value := range keyValues {
 		warnKeyCaseConflicts(ctx, e.console, dotEnv, key)
 		e.env.DotenvSet(key, value)
 		// Update to check case conflicts in subsequent keys
 		dotEnv[key] = value
+
+		if outputType, has := valueTypes[key]; has {
+			if err := e.env.SetValueType(key, outputType); err != nil {
+				return nil, fmt.Errorf("recording the type of %s: %w", key, err)
+			}
+		}
+
+		if e.flags.secret {
+			if err := e.env.MarkSecret(key); err != nil {
+				return nil, fmt.Errorf("marking %s as secret: %w", key, err)
+			}
+		}
+	}
+
+	if err := e.envManager.Save(ctx, e.env); err != nil {
+		return nil, fmt.Errorf("saving environment: %w", err)
+	}
+
+	return nil, nil
+}
+
+func newEnvSetJsonFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envSetJsonFlags {
+	flags := &envSetJsonFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvSetJsonCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-json <key> <value>",
+		Short: "Set an environment value to a JSON document.",
+		Long: "Set an environment value to a JSON document, given inline as the value or read from a file with a " +
+			"value of @<filepath>.\n" +
+			"The document is validated and stored as compact JSON, and azd env get-values --output json returns it " +
+			"as structured JSON.",
+		Args: cobra.ExactArgs(2),
+		// Sample arguments used in tests
+		Annotations: map[string]string{
+			"azdtest.use": "set-json key {}",
+		},
+	}
+}
+
+type envSetJsonFlags struct {
+	internal.EnvFlag
+	global *internal.GlobalCommandOptions
+	secret bool
+}
+
+func (f *envSetJsonFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag.Bind(local, global)
+	local.BoolVar(
+		&f.secret,
+		"secret",
+		false,
+		"Marks the value as a secret, which is redacted from JSON output and encrypted when the env.encryptSecrets "+
+			"alpha feature is on.",
+	)
+	f.global = global
+}
+
+type envSetJsonAction struct {
+	console    input.Console
+	env        *environment.Environment
+	envManager environment.Manager
+	flags      *envSetJsonFlags
+	args       []string
+}
+
+func newEnvSetJsonAction(
+	env *environment.Environment,
+	envManager environment.Manager,
+	console input.Console,
+	flags *envSetJsonFlags,
+	args []string,
+) actions.Action {
+	return &envSetJsonAction{
+		console:    console,
+		env:        env,
+		envManager: envManager,
+		flags:      flags,
+		args:       args,
+	}
+}
+
+func (e *envSetJsonAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	key, document := e.args[0], []byte(e.args[1])
+
+	if filename, isFile := strings.CutPrefix(e.args[1], "@"); isFile {
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
+		}
+
+		document = content
+	}
+
+	value, outputType, err := environment.NormalizeValue(environment.ValueTypeJson, string(document))
+	if err != nil {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("invalid JSON value for %s: %w", key, err),
+			Suggestion: "Quote the JSON document for your shell, or read it from a file with " +
+				output.WithHighLightFormat("azd env set-json %s @<filepath>", key) + ".",
+		}
+	}
+
+	warnKeyCaseConflicts(ctx, e.console, e.env.Dotenv(), key)
+	e.env.DotenvSet(key, value)
+
+	if err := e.env.SetValueType(key, outputType); err != nil {
+		return nil, fmt.Errorf("recording the type of %s: %w", key, err)
+	}
+
+	if e.flags.secret {
+		if err := e.env.MarkSecret(key); err != nil {
+			return nil, fmt.Errorf("marking %s as secret: %w", key, err)
+		}
 	}
 
 	if err := e.envManager.Save(ctx, e.env); err != nil {
@@ -289,6 +480,35 @@ func (e *envSetAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	return nil, nil
 }
 
+// getFlagEnvironment returns the environment named by the --environment flag, or the default environment.
+func getFlagEnvironment(
+	ctx context.Context,
+	azdCtx *azdcontext.AzdContext,
+	envManager environment.Manager,
+	envFlag internal.EnvFlag,
+) (*environment.Environment, error) {
+	name, err := azdCtx.GetDefaultEnvironmentName()
+	if err != nil {
+		return nil, err
+	}
+	if envFlag.EnvironmentName != "" {
+		name = envFlag.EnvironmentName
+	}
+
+	env, err := envManager.Get(ctx, name)
+	if errors.Is(err, environment.ErrNotFound) {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("environment '%s' does not exist: %w", name, environment.ErrNotFound),
+			Suggestion: fmt.Sprintf(
+				"Run 'azd env list' to see environments, or 'azd env new %s' to create it.", name),
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("getting environment: %w", err)
+	}
+
+	return env, nil
+}
+
 // parseKeyValue parses a key=value string and returns the key and value parts
 func parseKeyValue(arg string) (string, string, error) {
 	parts := strings.SplitN(arg, "=", 2)
@@ -852,11 +1072,34 @@ func newEnvListCmd() *cobra.Command {
 	}
 }
 
+type envListFlags struct {
+	global  *internal.GlobalCommandOptions
+	filters []string
+}
+
+func newEnvListFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envListFlags {
+	flags := &envListFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func (f *envListFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringArrayVar(
+		&f.filters,
+		"filter",
+		nil,
+		"Lists only the environments with the tag, in the <tag>=<value> format. Can be repeated to match all the tags.",
+	)
+	f.global = global
+}
+
 type envListAction struct {
 	envManager environment.Manager
 	azdCtx     *azdcontext.AzdContext
 	formatter  output.Formatter
 	writer     io.Writer
+	flags      *envListFlags
 }
 
 func newEnvListAction(
@@ -864,16 +1107,26 @@ func newEnvListAction(
 	azdCtx *azdcontext.AzdContext,
 	formatter output.Formatter,
 	writer io.Writer,
+	flags *envListFlags,
 ) actions.Action {
 	return &envListAction{
 		envManager: envManager,
 		azdCtx:     azdCtx,
 		formatter:  formatter,
 		writer:     writer,
+		flags:      flags,
 	}
 }
 
 func (e *envListAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	filter, err := environment.ParseTagFilter(e.flags.filters)
+	if err != nil {
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        fmt.Errorf("%w: %w", internal.ErrInvalidArgValue, err),
+			Suggestion: "Filter environments by tag with 'azd env list --filter <tag>=<value>'.",
+		}
+	}
+
 	envs, err := e.envManager.List(ctx)
 
 	if err != nil {
@@ -882,6 +1135,33 @@ func (e *envListAction) Run(ctx context.Context) (*actions.ActionResult, error)
 
 	tracing.SetUsageAttributes(fields.EnvCountKey.Int(len(envs)))
 
+	// Tags and expiry times are stored in the config of each environment, which is only read for local environments.
+	now := time.Now()
+	expired := 0
+	filtered := []*environment.Description{}
+	for _, env := range envs {
+		if env.HasLocal {
+			loaded, err := e.envManager.Get(ctx, env.Name)
+			if err != nil {
+				log.Printf("reading the tags of environment '%s': %v", env.Name, err)
+			} else {
+				env.Tags = loaded.Tags()
+				if expiresOn, has := loaded.ExpiresOn(); has {
+					env.ExpiresOn = &expiresOn
+					env.Expired = loaded.IsExpired(now)
+				}
+			}
+		}
+
+		if env.Expired {
+			expired++
+		}
+
+		if filter == nil || filter.Matches(env.Tags) {
+			filtered = append(filtered, env)
+		}
+	}
+
 	if e.formatter.Kind() == output.TableFormat {
 		columns := []output.Column{
 			{
@@ -900,13 +1180,30 @@ func (e *envListAction) Run(ctx context.Context) (*actions.ActionResult, error)
 				Heading:       "REMOTE",
 				ValueTemplate: "{{.HasRemote}}",
 			},
+			{
+				Heading:       "TAGS",
+				ValueTemplate: `{{if .Tags}}{{range $name, $value := .Tags}}{{$name}}={{$value}} {{end}}{{else}}-{{end}}`,
+			},
+			{
+				Heading:       "LAST USED",
+				ValueTemplate: `{{if .LastUsed}}{{.LastUsed.Local.Format "2006-01-02 15:04"}}{{else}}-{{end}}`,
+			},
+			{
+				Heading: "EXPIRES ON",
+				ValueTemplate: `{{if .ExpiresOn}}{{.ExpiresOn.Local.Format "2006-01-02 15:04"}}` +
+					`{{if .Expired}} (expired){{end}}{{else}}-{{end}}`,
+			},
 		}
 
-		err = e.formatter.Format(envs, e.writer, output.TableFormatterOptions{
+		err = e.formatter.Format(filtered, e.writer, output.TableFormatterOptions{
 			Columns: columns,
 		})
+		if err == nil && expired > 0 {
+			_, err = fmt.Fprintf(e.writer, "\n%s\n", output.WithWarningFormat(
+				"%d environment(s) expired. Run 'azd env prune' to delete them and their Azure resources.", expired))
+		}
 	} else {
-		err = e.formatter.Format(envs, e.writer, nil)
+		err = e.formatter.Format(filtered, e.writer, nil)
 	}
 	if err != nil {
 		return nil, err
@@ -918,6 +1215,7 @@ func (e *envListAction) Run(ctx context.Context) (*actions.ActionResult, error)
 type envNewFlags struct {
 	subscription string
 	location     string
+	expiresOn    string
 	global       *internal.GlobalCommandOptions
 }
 
@@ -929,6 +1227,12 @@ func (f *envNewFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandO
 		"ID of an Azure subscription to use for the new environment",
 	)
 	local.StringVarP(&f.location, "location", "l", "", "Azure location for the new environment")
+	local.StringVar(
+		&f.expiresOn,
+		"expires-on",
+		"",
+		"Date (2026-12-31) or time (2026-12-31T18:00:00Z) after which the new environment can be deleted by azd env prune",
+	)
 
 	f.global = global
 }
@@ -980,6 +1284,14 @@ func (en *envNewAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		environmentName = en.args[0]
 	}
 
+	var expiresOn time.Time
+	if en.flags.expiresOn != "" {
+		var err error
+		if expiresOn, err = environment.ParseExpiresOn(en.flags.expiresOn); err != nil {
+			return nil, fmt.Errorf("%w: %w", internal.ErrInvalidArgValue, err)
+		}
+	}
+
 	envSpec := environment.Spec{
 		Name:         environmentName,
 		Subscription: en.flags.subscription,
@@ -991,6 +1303,16 @@ func (en *envNewAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		return nil, fmt.Errorf("creating new environment: %w", err)
 	}
 
+	if !expiresOn.IsZero() {
+		if err := env.SetExpiresOn(expiresOn); err != nil {
+			return nil, fmt.Errorf("setting the expiry of the new environment: %w", err)
+		}
+
+		if err := en.envManager.Save(ctx, env); err != nil {
+			return nil, fmt.Errorf("saving new environment: %w", err)
+		}
+	}
+
 	envs, err := en.envManager.List(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("listing environments: %w", err)
@@ -1048,6 +1370,7 @@ func (en *envNewAction) Run(ctx context.Context) (*actions.ActionResult, error)
 type envRefreshFlags struct {
 	hint   string
 	layer  string
+	check  bool
 	global *internal.GlobalCommandOptions
 	internal.EnvFlag
 }
@@ -1055,6 +1378,13 @@ type envRefreshFlags struct {
 func (er *envRefreshFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
 	local.StringVarP(&er.hint, "hint", "", "", "Hint to help identify the environment to refresh")
 	local.StringVarP(&er.layer, "layer", "", "", "Provisioning layer to refresh the environment from.")
+	local.BoolVar(
+		&er.check,
+		"check",
+		false,
+		"Checks whether the environment values from provisioning outputs are out of date, without updating them. "+
+			"Fails when they are.",
+	)
 
 	er.EnvFlag.Bind(local, global)
 	er.global = global
@@ -1223,6 +1553,7 @@ func (ef *envRefreshAction) Run(ctx context.Context) (*actions.ActionResult, err
 
 	var state provisioning.State
 	stateRefreshed := false
+	stale := []provisioning.StaleOutput{}
 	for _, layer := range layers {
 		if ef.flags.layer != "" || len(layers) > 1 {
 			ef.console.EnsureBlankLine(ctx)
@@ -1261,6 +1592,10 @@ func (ef *envRefreshAction) Run(ctx context.Context) (*actions.ActionResult, err
 		}
 
 		stateOptions := provisioning.NewStateOptions(ef.flags.hint)
+		if ef.flags.check {
+			stateOptions = provisioning.NewCheckStateOptions(ef.flags.hint)
+		}
+
 		result, err := ef.provisionManager.State(ctx, stateOptions)
 		if err != nil {
 			// No deployment exists yet (for example, refresh before `azd provision`): this is
@@ -1290,7 +1625,17 @@ func (ef *envRefreshAction) Run(ctx context.Context) (*actions.ActionResult, err
 			continue
 		}
 
-		if err := provisioning.UpdateEnvironment(ctx, result.State.Outputs, ef.env, ef.envManager); err != nil {
+		if ef.flags.check {
+			layerStale, err := provisioning.FindStaleOutputs(result.State, ef.env)
+			if err != nil {
+				return nil, err
+			}
+
+			stale = append(stale, layerStale...)
+			continue
+		}
+
+		if err := provisioning.UpdateEnvironmentFromState(ctx, result.State, ef.env, ef.envManager); err != nil {
 			return nil, err
 		}
 
@@ -1298,6 +1643,10 @@ func (ef *envRefreshAction) Run(ctx context.Context) (*actions.ActionResult, err
 		stateRefreshed = true
 	}
 
+	if ef.flags.check {
+		return ef.checkResult(ctx, stale)
+	}
+
 	if ef.formatter.Kind() == output.JsonFormat {
 		err = ef.formatter.Format(provisioning.NewEnvRefreshResultFromState(&state), ef.writer, nil)
 		if err != nil {
@@ -1348,6 +1697,39 @@ func (ef *envRefreshAction) Run(ctx context.Context) (*actions.ActionResult, err
 	}, nil
 }
 
+// checkResult reports the environment values that are out of date with the provisioning outputs, failing when there
+// are any.
+func (ef *envRefreshAction) checkResult(
+	ctx context.Context, stale []provisioning.StaleOutput) (*actions.ActionResult, error) {
+	if ef.formatter.Kind() == output.JsonFormat {
+		err := ef.formatter.Format(provisioning.NewEnvRefreshCheckResult(stale), ef.writer, nil)
+		if err != nil {
+			return nil, fmt.Errorf("writing check result in JSON format: %w", err)
+		}
+	} else if len(stale) > 0 {
+		ef.console.EnsureBlankLine(ctx)
+		for _, output := range stale {
+			ef.console.Message(ctx, fmt.Sprintf("  %s (%s)", output.Name, output.Reason))
+		}
+	}
+
+	if len(stale) > 0 {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf(
+				"%d value(s) of environment '%s' are out of date with the provisioning outputs",
+				len(stale), ef.env.Name()),
+			Suggestion: fmt.Sprintf(
+				"Run %s to update them.", output.WithHighLightFormat("azd env refresh -e %s", ef.env.Name())),
+		}
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Environment '%s' is up to date with the provisioning outputs", ef.env.Name()),
+		},
+	}, nil
+}
+
 func newEnvGetValuesFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envGetValuesFlags {
 	flags := &envGetValuesFlags{}
 	flags.Bind(cmd.Flags(), global)
@@ -1365,11 +1747,26 @@ func newEnvGetValuesCmd() *cobra.Command {
 
 type envGetValuesFlags struct {
 	internal.EnvFlag
-	global *internal.GlobalCommandOptions
+	showSecrets bool
+	showSource  bool
+	global      *internal.GlobalCommandOptions
 }
 
 func (eg *envGetValuesFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
 	eg.EnvFlag.Bind(local, global)
+	local.BoolVar(
+		&eg.showSecrets,
+		"show-secrets",
+		false,
+		"Shows the values of secure provisioning outputs instead of redacting them when using --output json.",
+	)
+	local.BoolVar(
+		&eg.showSource,
+		"show-source",
+		false,
+		"Shows whether each value comes from the .env file of the environment or from the .azure/common.env file "+
+			"shared by all environments.",
+	)
 	eg.global = global
 }
 
@@ -1434,9 +1831,96 @@ func (eg *envGetValuesAction) Run(ctx context.Context) (*actions.ActionResult, e
 		return nil, fmt.Errorf("ensuring environment exists: %w", err)
 	}
 
+	if eg.formatter.Kind() == output.JsonFormat {
+		// JSON can represent the original type of values written from provisioning outputs.
+		values := env.TypedDotenv(!eg.flags.showSecrets)
+		if eg.flags.showSource {
+			return nil, eg.formatter.Format(envValuesWithSource(env, values), eg.writer, nil)
+		}
+
+		return nil, eg.formatter.Format(values, eg.writer, nil)
+	}
+
+	if eg.flags.showSource {
+		return nil, writeEnvValuesWithSource(eg.writer, env)
+	}
+
 	return nil, eg.formatter.Format(env.Dotenv(), eg.writer, nil)
 }
 
+// envValueWithSource is a value of an environment with the layer it comes from, as shown by
+// `azd env get-values --show-source --output json`.
+type envValueWithSource struct {
+	Value  any                     `json:"value"`
+	Source environment.ValueSource `json:"source"`
+	// OverridesCommon is true when the value of the environment overrides a value of the common.env file.
+	OverridesCommon bool `json:"overridesCommon,omitempty"`
+}
+
+func envValuesWithSource(env *environment.Environment, values map[string]any) map[string]envValueWithSource {
+	common := env.CommonDotenv()
+	withSource := make(map[string]envValueWithSource, len(values))
+	for key, value := range values {
+		source, _ := env.DotenvSource(key)
+		_, inCommon := common[key]
+		withSource[key] = envValueWithSource{
+			Value:           value,
+			Source:          source,
+			OverridesCommon: source == environment.ValueSourceEnvironment && inCommon,
+		}
+	}
+
+	return withSource
+}
+
+// writeEnvValuesWithSource writes the values of the environment as dotenv, grouped under a comment naming the file they
+// come from. The common values come first, since the values of the environment override them.
+func writeEnvValuesWithSource(writer io.Writer, env *environment.Environment) error {
+	commonPath := filepath.Join(azdcontext.EnvironmentDirectoryName, environment.CommonDotEnvFileName)
+	envPath := filepath.Join(azdcontext.EnvironmentDirectoryName, env.Name(), environment.DotEnvFileName)
+
+	values := env.Dotenv()
+	common := env.CommonDotenv()
+	sources := map[environment.ValueSource][]string{}
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		source, _ := env.DotenvSource(key)
+		sources[source] = append(sources[source], key)
+	}
+
+	var sb strings.Builder
+	for _, source := range []environment.ValueSource{environment.ValueSourceCommon, environment.ValueSourceEnvironment} {
+		keys := sources[source]
+		if len(keys) == 0 {
+			continue
+		}
+
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+
+		path := envPath
+		if source == environment.ValueSourceCommon {
+			path = commonPath
+		}
+		sb.WriteString(fmt.Sprintf("# %s\n", path))
+
+		for _, key := range keys {
+			if _, inCommon := common[key]; inCommon && source == environment.ValueSourceEnvironment {
+				sb.WriteString(fmt.Sprintf("# overrides the value in %s\n", commonPath))
+			}
+
+			line, err := godotenv.Marshal(map[string]string{key: values[key]})
+			if err != nil {
+				return fmt.Errorf("could not format values: %w", err)
+			}
+			sb.WriteString(line + "\n")
+		}
+	}
+
+	_, err := io.WriteString(writer, sb.String())
+	return err
+}
+
 func newEnvGetValueFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envGetValueFlags {
 	flags := &envGetValueFlags{}
 	flags.Bind(cmd.Flags(), global)
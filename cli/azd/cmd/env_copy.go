@@ -0,0 +1,242 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func getCmdEnvCopyHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Creates a new environment with the .env values and the configuration of an existing environment.",
+		[]string{
+			formatHelpNote("Values written by 'azd provision' still refer to the resources of the source environment" +
+				" until the new environment is provisioned."),
+			formatHelpNote("Use --exclude-secrets to leave out Key Vault secret references, secure outputs and" +
+				" secure parameters."),
+		})
+}
+
+func newEnvCopyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "copy <source-environment> <new-environment>",
+		Short: "Copy an environment to a new environment.",
+		Args:  cobra.ExactArgs(2),
+	}
+}
+
+type envCopyFlags struct {
+	excludeSecrets bool
+	subscription   string
+	location       string
+	global         *internal.GlobalCommandOptions
+}
+
+func (f *envCopyFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.BoolVar(
+		&f.excludeSecrets,
+		"exclude-secrets",
+		false,
+		"Leaves Key Vault secret references, secure outputs and secure parameters out of the new environment.",
+	)
+	local.StringVar(
+		&f.subscription,
+		"subscription",
+		"",
+		"ID of an Azure subscription to use for the new environment instead of the one of the source environment",
+	)
+	local.StringVarP(
+		&f.location,
+		"location",
+		"l",
+		"",
+		"Azure location for the new environment instead of the one of the source environment",
+	)
+
+	f.global = global
+}
+
+func newEnvCopyFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envCopyFlags {
+	flags := &envCopyFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+type envCopyAction struct {
+	envManager environment.Manager
+	console    input.Console
+	flags      *envCopyFlags
+	args       []string
+}
+
+func newEnvCopyAction(
+	envManager environment.Manager,
+	console input.Console,
+	flags *envCopyFlags,
+	args []string,
+) actions.Action {
+	return &envCopyAction{
+		envManager: envManager,
+		console:    console,
+		flags:      flags,
+		args:       args,
+	}
+}
+
+func (ec *envCopyAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	sourceName, targetName := ec.args[0], ec.args[1]
+
+	source, err := ec.envManager.Get(ctx, sourceName)
+	if errors.Is(err, environment.ErrNotFound) {
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        fmt.Errorf("environment '%s' does not exist: %w", sourceName, environment.ErrNotFound),
+			Suggestion: "Run 'azd env list' to see environments.",
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("loading environment '%s': %w", sourceName, err)
+	}
+
+	target, err := ec.envManager.Create(ctx, environment.Spec{Name: targetName})
+	if err != nil {
+		return nil, fmt.Errorf("creating new environment: %w", err)
+	}
+
+	excluded, err := copyEnvironment(source, target, ec.flags.excludeSecrets)
+	if err != nil {
+		return nil, fmt.Errorf("copying environment '%s': %w", sourceName, err)
+	}
+
+	if ec.flags.subscription != "" && ec.flags.subscription != target.GetSubscriptionId() {
+		target.SetSubscriptionId(ec.flags.subscription)
+		// The tenant of the source subscription may not own the new one, and is looked up again when needed.
+		target.DotenvDelete(environment.TenantIdEnvVarName)
+	}
+
+	if ec.flags.location != "" {
+		target.SetLocation(ec.flags.location)
+	}
+
+	if err := ec.envManager.Save(ctx, target); err != nil {
+		return nil, fmt.Errorf("saving environment '%s': %w", targetName, err)
+	}
+
+	if len(excluded) > 0 {
+		ec.console.Message(ctx, fmt.Sprintf(
+			"Secrets not copied: %s", output.WithHighLightFormat(strings.Join(excluded, ", "))))
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Environment '%s' was copied to '%s'.", sourceName, targetName),
+			FollowUp: fmt.Sprintf(
+				"Run 'azd env select %s' to use the new environment, then 'azd provision' to create its resources.",
+				targetName),
+		},
+	}, nil
+}
+
+// copyEnvironment copies the .env values and the configuration of source to target, and returns the sorted names of
+// the secrets that were excluded. The values of source that come from the common values of the project aren't copied,
+// since target shares them already.
+func copyEnvironment(source *environment.Environment, target *environment.Environment, excludeSecrets bool) (
+	[]string,
+	error,
+) {
+	if err := copyConfig(source.Config, target.Config, "", source.Config.Raw(), excludeSecrets); err != nil {
+		return nil, err
+	}
+
+	excluded := []string{}
+	values := source.Dotenv()
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		if key == environment.EnvNameEnvVarName {
+			continue
+		}
+
+		if valueSource, _ := source.DotenvSource(key); valueSource != environment.ValueSourceEnvironment {
+			continue
+		}
+
+		if excludeSecrets && isEnvSecret(source, key, values[key]) {
+			if err := target.ClearOutputMetadata(key); err != nil {
+				return nil, err
+			}
+
+			excluded = append(excluded, key)
+			continue
+		}
+
+		target.DotenvSet(key, values[key])
+	}
+
+	return excluded, nil
+}
+
+// isEnvSecret reports whether the .env value for key is a Key Vault secret reference or a secret value.
+func isEnvSecret(env *environment.Environment, key string, value string) bool {
+	return keyvault.IsSecretReference(value) || env.IsSecret(key)
+}
+
+// copyConfig copies the values of node, found at path in source, to target. Secrets are stored again in the vault of
+// target, rather than sharing the vault of source, or left out when excludeSecrets is true.
+func copyConfig(source config.Config, target config.Config, path string, node map[string]any, excludeSecrets bool) error {
+	for key, value := range node {
+		if path == "" && key == "vault" {
+			// The reference to the vault of source.
+			continue
+		}
+
+		valuePath := key
+		if path != "" {
+			valuePath = path + "." + key
+		}
+
+		if child, isMap := value.(map[string]any); isMap {
+			if err := copyConfig(source, target, valuePath, child, excludeSecrets); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if !config.IsSecretReference(value) {
+			if err := target.Set(valuePath, value); err != nil {
+				return fmt.Errorf("copying config '%s': %w", valuePath, err)
+			}
+
+			continue
+		}
+
+		if excludeSecrets {
+			continue
+		}
+
+		secret, has := source.GetString(valuePath)
+		if !has {
+			return fmt.Errorf("reading secret config '%s'", valuePath)
+		}
+
+		if err := target.SetSecret(valuePath, secret); err != nil {
+			return fmt.Errorf("copying secret config '%s': %w", valuePath, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupEnvCopySource creates a dev environment with plain values, secrets, a secure parameter and a common value.
+func setupEnvCopySource(t *testing.T) environment.Manager {
+	t.Setenv("AZD_CONFIG_DIR", t.TempDir())
+
+	azdCtx, envManager, _ := setupTestEnvironment(t, "dev", map[string]any{
+		"infra": map[string]any{
+			"parameters": map[string]any{"sku": "B1"},
+		},
+	})
+
+	env, err := envManager.Get(t.Context(), "dev")
+	require.NoError(t, err)
+
+	env.DotenvSet(environment.SubscriptionIdEnvVarName, "SUB-DEV")
+	env.DotenvSet(environment.LocationEnvVarName, "westus")
+	env.DotenvSet(environment.TenantIdEnvVarName, "TENANT-DEV")
+	env.DotenvSet("API_URL", "https://dev.contoso.com")
+	env.DotenvSet("DB_PASSWORD", "akvs://SUB-DEV/kv-dev/db-password")
+	env.DotenvSet("STORAGE_KEY", "key")
+	require.NoError(t, env.SetOutputMetadata("STORAGE_KEY", environment.OutputMetadata{
+		Type: environment.OutputTypeString, Secure: true,
+	}))
+	require.NoError(t, env.Config.SetSecret("infra.parameters.adminPassword", "P@ssw0rd"))
+	require.NoError(t, envManager.Save(t.Context(), env))
+
+	require.NoError(t, os.WriteFile(
+		filepath.Join(azdCtx.EnvironmentDirectory(), environment.CommonDotEnvFileName),
+		[]byte("TEAM=platform\n"),
+		0600,
+	))
+
+	return envManager
+}
+
+func Test_EnvCopyAction(t *testing.T) {
+	t.Run("CopiesValuesAndConfig", func(t *testing.T) {
+		envManager := setupEnvCopySource(t)
+
+		action := newEnvCopyAction(envManager, mockinput.NewMockConsole(), &envCopyFlags{
+			subscription: "SUB-STAGING",
+			location:     "eastus",
+		}, []string{"dev", "staging"})
+		result, err := action.Run(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, "Environment 'dev' was copied to 'staging'.", result.Message.Header)
+
+		staging, err := envManager.Get(t.Context(), "staging")
+		require.NoError(t, err)
+
+		// The common value is shared, not copied to the .env file of the new environment.
+		source, _ := staging.DotenvSource("TEAM")
+		assert.Equal(t, environment.ValueSourceCommon, source)
+
+		values := staging.Dotenv()
+		delete(values, "TEAM")
+		assert.Equal(t, map[string]string{
+			environment.EnvNameEnvVarName:        "staging",
+			environment.SubscriptionIdEnvVarName: "SUB-STAGING",
+			environment.LocationEnvVarName:       "eastus",
+			"API_URL":                            "https://dev.contoso.com",
+			"DB_PASSWORD":                        "akvs://SUB-DEV/kv-dev/db-password",
+			"STORAGE_KEY":                        "key",
+		}, values)
+
+		metadata, has := staging.OutputMetadata("STORAGE_KEY")
+		require.True(t, has)
+		assert.True(t, metadata.Secure)
+
+		sku, _ := staging.Config.GetString("infra.parameters.sku")
+		assert.Equal(t, "B1", sku)
+
+		// The secure parameter is stored in the vault of the new environment.
+		password, has := staging.Config.GetString("infra.parameters.adminPassword")
+		require.True(t, has)
+		assert.Equal(t, "P@ssw0rd", password)
+
+		dev, err := envManager.Get(t.Context(), "dev")
+		require.NoError(t, err)
+		stagingVault, _ := staging.Config.GetString("vault")
+		devVault, _ := dev.Config.GetString("vault")
+		assert.NotEqual(t, devVault, stagingVault)
+	})
+
+	t.Run("ExcludeSecrets", func(t *testing.T) {
+		envManager := setupEnvCopySource(t)
+
+		action := newEnvCopyAction(envManager, mockinput.NewMockConsole(), &envCopyFlags{
+			excludeSecrets: true,
+		}, []string{"dev", "staging"})
+		_, err := action.Run(t.Context())
+		require.NoError(t, err)
+
+		staging, err := envManager.Get(t.Context(), "staging")
+		require.NoError(t, err)
+
+		values := staging.Dotenv()
+		assert.NotContains(t, values, "DB_PASSWORD")
+		assert.NotContains(t, values, "STORAGE_KEY")
+		assert.Equal(t, "SUB-DEV", values[environment.SubscriptionIdEnvVarName])
+		assert.Equal(t, "TENANT-DEV", values[environment.TenantIdEnvVarName])
+
+		_, has := staging.OutputMetadata("STORAGE_KEY")
+		assert.False(t, has)
+
+		_, has = staging.Config.Get("infra.parameters.adminPassword")
+		assert.False(t, has)
+		_, has = staging.Config.Get("vault")
+		assert.False(t, has)
+	})
+
+	t.Run("SourceNotFound", func(t *testing.T) {
+		envManager := setupEnvCopySource(t)
+
+		action := newEnvCopyAction(
+			envManager, mockinput.NewMockConsole(), &envCopyFlags{}, []string{"test", "staging"})
+		_, err := action.Run(t.Context())
+		require.ErrorIs(t, err, environment.ErrNotFound)
+
+		_, ok := errors.AsType[*internal.ErrorWithSuggestion](err)
+		require.True(t, ok)
+	})
+
+	t.Run("TargetExists", func(t *testing.T) {
+		envManager := setupEnvCopySource(t)
+		_, err := envManager.Create(t.Context(), environment.Spec{Name: "staging"})
+		require.NoError(t, err)
+
+		action := newEnvCopyAction(
+			envManager, mockinput.NewMockConsole(), &envCopyFlags{}, []string{"dev", "staging"})
+		_, err = action.Run(t.Context())
+		require.ErrorIs(t, err, environment.ErrExists)
+	})
+}
+
+func Test_CopyConfig_SkipsVaultReference(t *testing.T) {
+	source := config.NewConfig(map[string]any{
+		"vault": "00000000-0000-0000-0000-000000000000",
+		"infra": map[string]any{"parameters": map[string]any{"sku": "B1"}},
+	})
+	target := config.NewEmptyConfig()
+
+	require.NoError(t, copyConfig(source, target, "", source.Raw(), false))
+	assert.Equal(t, map[string]any{
+		"infra": map[string]any{"parameters": map[string]any{"sku": "B1"}},
+	}, target.Raw())
+}
@@ -0,0 +1,229 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/workflow"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func getCmdEnvPruneHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Deletes the environments that are past their expiry time, and their Azure resources.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("Set the expiry time of an environment with %s, or with %s.",
+				output.WithHighLightFormat("azd env new --expires-on <date>"),
+				output.WithHighLightFormat("azd env config set expiresOn <date>"))),
+			formatHelpNote("Runs 'azd down' for each selected environment that was provisioned, then removes it."),
+			formatHelpNote("Only environments that exist locally are pruned."),
+		})
+}
+
+func getCmdEnvPruneHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Select the expired environments to delete": output.WithHighLightFormat("azd env prune"),
+		"Delete the expired environments without confirmation, purging soft-deleted resources": output.WithHighLightFormat(
+			"azd env prune --force --purge"),
+	})
+}
+
+func newEnvPruneCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "prune",
+		Short: "Delete expired environments and their Azure resources.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type envPruneFlags struct {
+	global *internal.GlobalCommandOptions
+	force  bool
+	purge  bool
+}
+
+func newEnvPruneFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envPruneFlags {
+	flags := &envPruneFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func (f *envPruneFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.BoolVar(
+		&f.force,
+		"force",
+		false,
+		"Deletes the expired environments and their Azure resources without confirmation.",
+	)
+	local.BoolVar(
+		&f.purge,
+		"purge",
+		false,
+		//nolint:lll
+		"Permanently deletes the soft-deleted resources of the expired environments, like Key Vaults and Cognitive Services accounts.",
+	)
+	f.global = global
+}
+
+type envPruneAction struct {
+	envManager     environment.Manager
+	console        input.Console
+	workflowRunner *workflow.Runner
+	flags          *envPruneFlags
+}
+
+func newEnvPruneAction(
+	envManager environment.Manager,
+	console input.Console,
+	workflowRunner *workflow.Runner,
+	flags *envPruneFlags,
+) actions.Action {
+	return &envPruneAction{
+		envManager:     envManager,
+		console:        console,
+		workflowRunner: workflowRunner,
+		flags:          flags,
+	}
+}
+
+func (a *envPruneAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	a.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title:     "Delete expired environments (azd env prune)",
+		TitleNote: "Deletes the Azure resources of expired environments, then removes them.",
+	})
+
+	envs, err := a.envManager.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing environments: %w", err)
+	}
+
+	now := time.Now()
+	expired := []*environment.Environment{}
+	for _, description := range envs {
+		if !description.HasLocal {
+			continue
+		}
+
+		env, err := a.envManager.Get(ctx, description.Name)
+		if err != nil {
+			return nil, fmt.Errorf("loading environment '%s': %w", description.Name, err)
+		}
+
+		if env.IsExpired(now) {
+			expired = append(expired, env)
+		}
+	}
+
+	if len(expired) == 0 {
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: "No environment is past its expiry time.",
+			},
+		}, nil
+	}
+
+	if !a.flags.force && a.console.IsNoPromptMode() {
+		return nil, &input.PromptRequiredError{
+			Inputs: []input.RequiredInput{
+				{
+					Name:        "force",
+					Description: fmt.Sprintf("Confirms the deletion of %d expired environment(s)", len(expired)),
+					Sources:     []input.InputSource{{Kind: input.InputSourceFlag, Name: "--force"}},
+				},
+			},
+		}
+	}
+
+	selected := expired
+	if !a.flags.force {
+		if selected, err = a.selectEnvironments(ctx, expired); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, env := range selected {
+		if err := a.prune(ctx, env); err != nil {
+			return nil, err
+		}
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Deleted %d of %d expired environment(s).", len(selected), len(expired)),
+		},
+	}, nil
+}
+
+// selectEnvironments prompts for the expired environments to delete. None is selected by default.
+func (a *envPruneAction) selectEnvironments(
+	ctx context.Context,
+	expired []*environment.Environment,
+) ([]*environment.Environment, error) {
+	names := make([]string, len(expired))
+	details := make([]string, len(expired))
+	for i, env := range expired {
+		expiresOn, _ := env.ExpiresOn()
+		names[i] = env.Name()
+		details[i] = fmt.Sprintf("Expired on %s", expiresOn.Local().Format(time.DateOnly))
+	}
+
+	selectedNames, err := a.console.MultiSelect(ctx, input.ConsoleOptions{
+		Message:       "Select the expired environments to delete, along with their Azure resources",
+		Options:       names,
+		OptionDetails: details,
+		DefaultValue:  []string{},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	selected := []*environment.Environment{}
+	for _, env := range expired {
+		if slices.Contains(selectedNames, env.Name()) {
+			selected = append(selected, env)
+		}
+	}
+
+	return selected, nil
+}
+
+// prune deletes the Azure resources of the environment, when it was provisioned, and removes the environment.
+func (a *envPruneAction) prune(ctx context.Context, env *environment.Environment) error {
+	if env.GetSubscriptionId() != "" {
+		args := []string{"down", "-e", env.Name(), "--force"}
+		if a.flags.purge {
+			args = append(args, "--purge")
+		}
+
+		err := a.workflowRunner.Run(ctx, &workflow.Workflow{
+			Name:  "prune",
+			Steps: []*workflow.Step{{AzdCommand: workflow.Command{Args: args}}},
+		})
+		if err != nil {
+			return fmt.Errorf("deleting the Azure resources of environment '%s': %w", env.Name(), err)
+		}
+	}
+
+	if err := a.envManager.Delete(ctx, env.Name()); err != nil {
+		return fmt.Errorf("removing environment '%s': %w", env.Name(), err)
+	}
+
+	a.console.MessageUxItem(ctx, &ux.DoneMessage{
+		Message: fmt.Sprintf("Deleted environment '%s'", env.Name()),
+	})
+
+	return nil
+}
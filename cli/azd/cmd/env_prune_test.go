@@ -0,0 +1,94 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/workflow"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EnvPruneAction(t *testing.T) {
+	newEnvs := func(t *testing.T) *mockenv.MockEnvManager {
+		expired := environment.NewWithValues("dev", map[string]string{
+			environment.SubscriptionIdEnvVarName: "sub-1",
+		})
+		require.NoError(t, expired.SetExpiresOn(time.Now().Add(-time.Hour)))
+		unprovisioned := environment.New("demo")
+		require.NoError(t, unprovisioned.SetExpiresOn(time.Now().Add(-time.Hour)))
+		current := environment.New("prod")
+		require.NoError(t, current.SetExpiresOn(time.Now().Add(time.Hour)))
+
+		mgr := newTestEnvManager()
+		mgr.On("List", mock.Anything).Return([]*environment.Description{
+			{Name: "dev", HasLocal: true},
+			{Name: "demo", HasLocal: true},
+			{Name: "prod", HasLocal: true},
+			{Name: "remote", HasRemote: true},
+		}, nil)
+		mgr.On("Get", mock.Anything, "dev").Return(expired, nil)
+		mgr.On("Get", mock.Anything, "demo").Return(unprovisioned, nil)
+		mgr.On("Get", mock.Anything, "prod").Return(current, nil)
+		mgr.On("Delete", mock.Anything).Return(nil)
+
+		return mgr
+	}
+
+	t.Run("Force", func(t *testing.T) {
+		mgr := newEnvs(t)
+		console := mockinput.NewMockConsole()
+		runner := &recordingCommandRunner{}
+
+		action := newEnvPruneAction(
+			mgr, console, workflow.NewRunner(runner, console), &envPruneFlags{force: true, purge: true})
+		result, err := action.Run(t.Context())
+		require.NoError(t, err)
+		require.Equal(t, "Deleted 2 of 2 expired environment(s).", result.Message.Header)
+
+		// Only the provisioned environment has Azure resources to delete.
+		require.Equal(t, []string{"down -e dev --force --purge"}, runner.commands)
+		mgr.AssertCalled(t, "Delete", "dev")
+		mgr.AssertCalled(t, "Delete", "demo")
+		mgr.AssertNotCalled(t, "Delete", "prod")
+	})
+
+	t.Run("NoPrompt", func(t *testing.T) {
+		mgr := newEnvs(t)
+		console := mockinput.NewMockConsole()
+		console.SetNoPromptMode(true)
+		runner := &recordingCommandRunner{}
+
+		action := newEnvPruneAction(mgr, console, workflow.NewRunner(runner, console), &envPruneFlags{})
+		_, err := action.Run(t.Context())
+		var promptErr *input.PromptRequiredError
+		require.ErrorAs(t, err, &promptErr)
+		require.Empty(t, runner.commands)
+		mgr.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+
+	t.Run("Select", func(t *testing.T) {
+		mgr := newEnvs(t)
+		console := mockinput.NewMockConsole()
+		console.WhenMultiSelect(func(options input.ConsoleOptions) bool {
+			return slices.Equal([]string{"dev", "demo"}, options.Options)
+		}).Respond([]string{"demo"})
+		runner := &recordingCommandRunner{}
+
+		action := newEnvPruneAction(mgr, console, workflow.NewRunner(runner, console), &envPruneFlags{})
+		result, err := action.Run(t.Context())
+		require.NoError(t, err)
+		require.Equal(t, "Deleted 1 of 2 expired environment(s).", result.Message.Header)
+		require.Empty(t, runner.commands)
+		mgr.AssertCalled(t, "Delete", "demo")
+		mgr.AssertNotCalled(t, "Delete", "dev")
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"slices"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
@@ -17,6 +18,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -153,6 +155,20 @@ func (er *envRemoveAction) Run(ctx context.Context) (*actions.ActionResult, erro
 	}
 
 	env := envs[idx]
+
+	// Preview environments are deleted by 'azd down', which can't find them once the environment is removed
+	if loaded, err := er.envManager.Get(ctx, env.Name); err != nil {
+		log.Printf("reading environment '%s': %v", env.Name, err)
+	} else if previews := project.PreviewEnvironments(loaded); len(previews) > 0 {
+		er.console.MessageUxItem(ctx, &ux.WarningMessage{
+			Description: fmt.Sprintf(
+				"The environment has %d Static Web App preview environment(s) that won't be deleted. "+
+					"Run 'azd down' first to delete them.",
+				len(previews),
+			),
+		})
+	}
+
 	if !er.flags.force {
 		confirm, err := er.console.Confirm(
 			ctx,
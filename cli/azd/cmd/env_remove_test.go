@@ -6,6 +6,7 @@ package cmd
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
@@ -73,6 +74,7 @@ func Test_EnvRemoveAction_ForceDelete(t *testing.T) {
 	envMgr.On("List", mock.Anything).Return([]*environment.Description{
 		{Name: "my-env"},
 	}, nil)
+	envMgr.On("Get", mock.Anything, "my-env").Return(environment.NewWithValues("my-env", nil), nil)
 	envMgr.On("Delete", "my-env").Return(nil)
 
 	console := mockinput.NewMockConsole()
@@ -93,6 +95,7 @@ func Test_EnvRemoveAction_DeleteError(t *testing.T) {
 	envMgr.On("List", mock.Anything).Return([]*environment.Description{
 		{Name: "my-env"},
 	}, nil)
+	envMgr.On("Get", mock.Anything, "my-env").Return(environment.NewWithValues("my-env", nil), nil)
 	envMgr.On("Delete", "my-env").Return(fmt.Errorf("io error"))
 
 	console := mockinput.NewMockConsole()
@@ -111,6 +114,7 @@ func Test_EnvRemoveAction_WithFlagOverride(t *testing.T) {
 	envMgr.On("List", mock.Anything).Return([]*environment.Description{
 		{Name: "flag-env"},
 	}, nil)
+	envMgr.On("Get", mock.Anything, "flag-env").Return(environment.NewWithValues("flag-env", nil), nil)
 	envMgr.On("Delete", "flag-env").Return(nil)
 
 	console := mockinput.NewMockConsole()
@@ -131,6 +135,7 @@ func Test_EnvRemoveAction_ConfirmDenied(t *testing.T) {
 	envMgr.On("List", mock.Anything).Return([]*environment.Description{
 		{Name: "my-env"},
 	}, nil)
+	envMgr.On("Get", mock.Anything, "my-env").Return(environment.NewWithValues("my-env", nil), nil)
 
 	console := mockinput.NewMockConsole()
 	console.WhenConfirm(func(options input.ConsoleOptions) bool { return true }).Respond(false)
@@ -143,6 +148,30 @@ func Test_EnvRemoveAction_ConfirmDenied(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func Test_EnvRemoveAction_WarnsAboutPreviewEnvironments(t *testing.T) {
+	azdCtx := newEnvRemoveTestContext(t)
+	setDefaultEnv(t, azdCtx, "my-env")
+
+	env := environment.NewWithValues("my-env", map[string]string{
+		"SERVICE_WEB_PREVIEW_ENVIRONMENT_ID": "/subscriptions/SUB_ID/resourceGroups/RG_ID/providers/" +
+			"Microsoft.Web/staticSites/SWA_NAME/builds/my-env",
+	})
+	envMgr := &mockenv.MockEnvManager{}
+	envMgr.On("List", mock.Anything).Return([]*environment.Description{
+		{Name: "my-env"},
+	}, nil)
+	envMgr.On("Get", mock.Anything, "my-env").Return(env, nil)
+	envMgr.On("Delete", "my-env").Return(nil)
+
+	console := mockinput.NewMockConsole()
+	flags := &envRemoveFlags{global: &internal.GlobalCommandOptions{}, force: true}
+	action := newEnvRemoveAction(azdCtx, envMgr, console, &output.NoneFormatter{}, &bytes.Buffer{}, flags, nil)
+
+	_, err := action.Run(t.Context())
+	require.NoError(t, err)
+	assert.Contains(t, strings.Join(console.Output(), "\n"), "1 Static Web App preview environment(s)")
+}
+
 func Test_EnvRemoveAction_ListError(t *testing.T) {
 	azdCtx := newEnvRemoveTestContext(t)
 	setDefaultEnv(t, azdCtx, "my-env")
@@ -0,0 +1,369 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func envSnapshotActions(group *actions.ActionDescriptor) {
+	snapshotGroup := group.Add("snapshot", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "snapshot",
+			Short: "Manage point in time snapshots of environment values and configuration.",
+		},
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdEnvSnapshotHelpDescription,
+			Footer:      getCmdEnvSnapshotHelpFooter,
+		},
+	})
+
+	snapshotGroup.Add("create", &actions.ActionDescriptorOptions{
+		Command:        newEnvSnapshotCreateCmd(),
+		FlagsResolver:  newEnvSnapshotCreateFlags,
+		ActionResolver: newEnvSnapshotCreateAction,
+	})
+
+	snapshotGroup.Add("list", &actions.ActionDescriptorOptions{
+		Command:        newEnvSnapshotListCmd(),
+		FlagsResolver:  newEnvSnapshotListFlags,
+		ActionResolver: newEnvSnapshotListAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
+		DefaultFormat:  output.TableFormat,
+	})
+
+	snapshotGroup.Add("restore", &actions.ActionDescriptorOptions{
+		Command:        newEnvSnapshotRestoreCmd(),
+		FlagsResolver:  newEnvSnapshotRestoreFlags,
+		ActionResolver: newEnvSnapshotRestoreAction,
+	})
+}
+
+// azd env snapshot create
+
+func newEnvSnapshotCreateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "create",
+		Short: "Create a snapshot of the environment values and configuration.",
+		Long: "Creates a snapshot of the environment's .env values and config.json configuration, which includes the " +
+			"answers to infrastructure parameter prompts and the identifiers of deployed artifacts.",
+		Args: cobra.NoArgs,
+	}
+}
+
+type envSnapshotCreateFlags struct {
+	internal.EnvFlag
+	global      *internal.GlobalCommandOptions
+	description string
+}
+
+func newEnvSnapshotCreateFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envSnapshotCreateFlags {
+	flags := &envSnapshotCreateFlags{}
+	flags.Bind(cmd.Flags(), global)
+	return flags
+}
+
+func (f *envSnapshotCreateFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+	local.StringVarP(&f.description, "description", "d", "", "A description of the snapshot.")
+}
+
+type envSnapshotCreateAction struct {
+	azdCtx        *azdcontext.AzdContext
+	envManager    environment.Manager
+	snapshotStore *environment.SnapshotStore
+	flags         *envSnapshotCreateFlags
+}
+
+func newEnvSnapshotCreateAction(
+	azdCtx *azdcontext.AzdContext,
+	envManager environment.Manager,
+	snapshotStore *environment.SnapshotStore,
+	flags *envSnapshotCreateFlags,
+) actions.Action {
+	return &envSnapshotCreateAction{
+		azdCtx:        azdCtx,
+		envManager:    envManager,
+		snapshotStore: snapshotStore,
+		flags:         flags,
+	}
+}
+
+func (a *envSnapshotCreateAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	env, err := getFlagEnvironment(ctx, a.azdCtx, a.envManager, a.flags.EnvFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := a.snapshotStore.Create(env, a.flags.description)
+	if err != nil {
+		return nil, fmt.Errorf("creating snapshot: %w", err)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Created snapshot '%s' of environment '%s'.", snapshot.Id, env.Name()),
+			FollowUp: fmt.Sprintf("To roll back to it, run %s.",
+				output.WithHighLightFormat("azd env snapshot restore %s", snapshot.Id)),
+		},
+	}, nil
+}
+
+// azd env snapshot list
+
+func newEnvSnapshotListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Short:   "List the snapshots of the environment.",
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+	}
+}
+
+type envSnapshotListFlags struct {
+	internal.EnvFlag
+	global *internal.GlobalCommandOptions
+}
+
+func newEnvSnapshotListFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envSnapshotListFlags {
+	flags := &envSnapshotListFlags{}
+	flags.Bind(cmd.Flags(), global)
+	return flags
+}
+
+func (f *envSnapshotListFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+}
+
+type envSnapshotListAction struct {
+	azdCtx        *azdcontext.AzdContext
+	envManager    environment.Manager
+	snapshotStore *environment.SnapshotStore
+	formatter     output.Formatter
+	writer        io.Writer
+	flags         *envSnapshotListFlags
+}
+
+func newEnvSnapshotListAction(
+	azdCtx *azdcontext.AzdContext,
+	envManager environment.Manager,
+	snapshotStore *environment.SnapshotStore,
+	formatter output.Formatter,
+	writer io.Writer,
+	flags *envSnapshotListFlags,
+) actions.Action {
+	return &envSnapshotListAction{
+		azdCtx:        azdCtx,
+		envManager:    envManager,
+		snapshotStore: snapshotStore,
+		formatter:     formatter,
+		writer:        writer,
+		flags:         flags,
+	}
+}
+
+func (a *envSnapshotListAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	env, err := getFlagEnvironment(ctx, a.azdCtx, a.envManager, a.flags.EnvFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := a.snapshotStore.List(env.Name())
+	if err != nil {
+		return nil, fmt.Errorf("listing snapshots: %w", err)
+	}
+
+	// prefer empty array over `nil` since this is a contracted return value.
+	results := []contracts.EnvSnapshot{}
+	for _, snapshot := range snapshots {
+		results = append(results, contracts.EnvSnapshot{
+			Id:          snapshot.Id,
+			CreatedAt:   snapshot.CreatedAt,
+			Description: snapshot.Description,
+			ValueCount:  len(snapshot.Values),
+			Artifacts:   snapshot.Artifacts,
+		})
+	}
+
+	if a.formatter.Kind() == output.TableFormat {
+		columns := []output.Column{
+			{
+				Heading:       "ID",
+				ValueTemplate: "{{.Id}}",
+			},
+			{
+				Heading:       "CREATED",
+				ValueTemplate: `{{.CreatedAt.Local.Format "2006-01-02 15:04:05"}}`,
+			},
+			{
+				Heading:       "VALUES",
+				ValueTemplate: "{{.ValueCount}}",
+			},
+			{
+				Heading:       "ARTIFACTS",
+				ValueTemplate: "{{len .Artifacts}}",
+			},
+			{
+				Heading:       "DESCRIPTION",
+				ValueTemplate: "{{.Description}}",
+			},
+		}
+
+		err = a.formatter.Format(results, a.writer, output.TableFormatterOptions{
+			Columns: columns,
+		})
+	} else {
+		err = a.formatter.Format(results, a.writer, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("formatting snapshots: %w", err)
+	}
+
+	return nil, nil
+}
+
+// azd env snapshot restore <snapshot-id>
+
+func newEnvSnapshotRestoreCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "restore <snapshot-id>",
+		Short: "Restore the environment values and configuration from a snapshot.",
+		Long: "Replaces the environment's .env values and config.json configuration with the ones in a snapshot. " +
+			"A snapshot of the current values is created first, so a restore can be undone.",
+		Args: cobra.ExactArgs(1),
+	}
+}
+
+type envSnapshotRestoreFlags struct {
+	internal.EnvFlag
+	global *internal.GlobalCommandOptions
+	force  bool
+}
+
+func newEnvSnapshotRestoreFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envSnapshotRestoreFlags {
+	flags := &envSnapshotRestoreFlags{}
+	flags.Bind(cmd.Flags(), global)
+	return flags
+}
+
+func (f *envSnapshotRestoreFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+	local.BoolVar(&f.force, "force", false, "Skips confirmation before restoring the snapshot.")
+}
+
+type envSnapshotRestoreAction struct {
+	azdCtx        *azdcontext.AzdContext
+	envManager    environment.Manager
+	snapshotStore *environment.SnapshotStore
+	console       input.Console
+	flags         *envSnapshotRestoreFlags
+	args          []string
+}
+
+func newEnvSnapshotRestoreAction(
+	azdCtx *azdcontext.AzdContext,
+	envManager environment.Manager,
+	snapshotStore *environment.SnapshotStore,
+	console input.Console,
+	flags *envSnapshotRestoreFlags,
+	args []string,
+) actions.Action {
+	return &envSnapshotRestoreAction{
+		azdCtx:        azdCtx,
+		envManager:    envManager,
+		snapshotStore: snapshotStore,
+		console:       console,
+		flags:         flags,
+		args:          args,
+	}
+}
+
+func (a *envSnapshotRestoreAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	env, err := getFlagEnvironment(ctx, a.azdCtx, a.envManager, a.flags.EnvFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := a.snapshotStore.Get(env.Name(), a.args[0])
+	if errors.Is(err, environment.ErrSnapshotNotFound) {
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        err,
+			Suggestion: "Run 'azd env snapshot list' to see the snapshots of the environment.",
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if !a.flags.force {
+		confirm, err := a.console.Confirm(ctx, input.ConsoleOptions{
+			Message: fmt.Sprintf(
+				"Restore the environment '%s' to the snapshot '%s'?", env.Name(), snapshot.Id),
+		})
+		if !confirm || err != nil {
+			return nil, err
+		}
+	}
+
+	// Keep the current state, so restoring the wrong snapshot can be undone.
+	previous, err := a.snapshotStore.Create(env, fmt.Sprintf("Before restoring %s", snapshot.Id))
+	if err != nil {
+		return nil, fmt.Errorf("creating snapshot of the current values: %w", err)
+	}
+
+	snapshot.Restore(env)
+	if err := a.envManager.Save(ctx, env); err != nil {
+		return nil, fmt.Errorf("saving environment: %w", err)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Restored environment '%s' to snapshot '%s'.", env.Name(), snapshot.Id),
+			FollowUp: fmt.Sprintf(
+				"Azure resources weren't changed. Run %s or %s to apply the restored configuration.\n"+
+					"To undo the restore, run %s.",
+				output.WithHighLightFormat("azd provision"),
+				output.WithHighLightFormat("azd deploy"),
+				output.WithHighLightFormat("azd env snapshot restore %s", previous.Id)),
+		},
+	}, nil
+}
+
+func getCmdEnvSnapshotHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Manage point in time snapshots of an environment, to roll back its configuration after a bad change.",
+		[]string{
+			formatHelpNote("A snapshot contains the .env values, the config.json configuration (including infrastructure" +
+				" parameter answers), and the identifiers of deployed artifacts."),
+			formatHelpNote("Snapshots are stored in .azure/<environment-name>/snapshots and can contain secrets."),
+			formatHelpNote("Restoring a snapshot doesn't change Azure resources."),
+		})
+}
+
+func getCmdEnvSnapshotHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Create a snapshot before changing the environment": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd env snapshot create --description"),
+			output.WithWarningFormat("\"before upgrade\"")),
+		"List the snapshots of the environment": output.WithHighLightFormat("azd env snapshot list"),
+		"Restore a snapshot": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd env snapshot restore"),
+			output.WithWarningFormat("20240101-120000")),
+	})
+}
@@ -0,0 +1,126 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvSnapshotCreateListRestore(t *testing.T) {
+	t.Parallel()
+	envName := "test-env"
+	azdCtx, envManager, _ := setupTestEnvironment(t, envName, map[string]any{
+		"infra": map[string]any{"parameters": map[string]any{"sku": "S1"}},
+	})
+	snapshotStore := environment.NewSnapshotStore(azdCtx)
+
+	env, err := envManager.Get(t.Context(), envName)
+	require.NoError(t, err)
+	env.DotenvSet("SERVICE_API_IMAGE_NAME", "api:1")
+	require.NoError(t, envManager.Save(t.Context(), env))
+
+	createFlags := &envSnapshotCreateFlags{description: "known good"}
+	createFlags.EnvironmentName = envName
+	result, err := newEnvSnapshotCreateAction(azdCtx, envManager, snapshotStore, createFlags).Run(t.Context())
+	require.NoError(t, err)
+	require.Contains(t, result.Message.Header, "Created snapshot")
+
+	snapshots, err := snapshotStore.List(envName)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	knownGood := snapshots[0]
+
+	// A bad change.
+	env.DotenvSet("SERVICE_API_IMAGE_NAME", "api:2")
+	env.DotenvSet("EXTRA", "value")
+	require.NoError(t, env.Config.Set("infra.parameters.sku", "P1"))
+	require.NoError(t, envManager.Save(t.Context(), env))
+
+	console := mockinput.NewMockConsole()
+	console.WhenConfirm(func(options input.ConsoleOptions) bool { return true }).Respond(true)
+
+	restoreFlags := &envSnapshotRestoreFlags{}
+	restoreFlags.EnvironmentName = envName
+	result, err = newEnvSnapshotRestoreAction(
+		azdCtx, envManager, snapshotStore, console, restoreFlags, []string{knownGood.Id}).Run(t.Context())
+	require.NoError(t, err)
+	require.Contains(t, result.Message.Header, "Restored environment")
+
+	env, err = envManager.Get(t.Context(), envName)
+	require.NoError(t, err)
+	require.NoError(t, envManager.Reload(t.Context(), env))
+	require.Equal(t, "api:1", env.Getenv("SERVICE_API_IMAGE_NAME"))
+	_, has := env.Dotenv()["EXTRA"]
+	require.False(t, has)
+	sku, _ := env.Config.GetString("infra.parameters.sku")
+	require.Equal(t, "S1", sku)
+
+	// The state before the restore was kept.
+	var buf bytes.Buffer
+	listFlags := &envSnapshotListFlags{}
+	listFlags.EnvironmentName = envName
+	_, err = newEnvSnapshotListAction(
+		azdCtx, envManager, snapshotStore, &output.JsonFormatter{}, &buf, listFlags).Run(t.Context())
+	require.NoError(t, err)
+
+	var listed []contracts.EnvSnapshot
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &listed))
+	require.Len(t, listed, 2)
+	require.Equal(t, "Before restoring "+knownGood.Id, listed[0].Description)
+	require.Equal(t, map[string]string{"SERVICE_API_IMAGE_NAME": "api:2"}, listed[0].Artifacts)
+	require.Equal(t, knownGood.Id, listed[1].Id)
+	require.Equal(t, "known good", listed[1].Description)
+}
+
+func TestEnvSnapshotRestore(t *testing.T) {
+	t.Parallel()
+	envName := "test-env"
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+		azdCtx, envManager, _ := setupTestEnvironment(t, envName, map[string]any{})
+
+		flags := &envSnapshotRestoreFlags{force: true}
+		flags.EnvironmentName = envName
+		_, err := newEnvSnapshotRestoreAction(
+			azdCtx, envManager, environment.NewSnapshotStore(azdCtx), mockinput.NewMockConsole(), flags,
+			[]string{"20240101-000000"}).Run(t.Context())
+		require.ErrorIs(t, err, environment.ErrSnapshotNotFound)
+	})
+
+	t.Run("Declined", func(t *testing.T) {
+		t.Parallel()
+		azdCtx, envManager, _ := setupTestEnvironment(t, envName, map[string]any{})
+		snapshotStore := environment.NewSnapshotStore(azdCtx)
+
+		env, err := envManager.Get(t.Context(), envName)
+		require.NoError(t, err)
+		snapshot, err := snapshotStore.Create(env, "")
+		require.NoError(t, err)
+
+		console := mockinput.NewMockConsole()
+		console.WhenConfirm(func(options input.ConsoleOptions) bool { return true }).Respond(false)
+
+		flags := &envSnapshotRestoreFlags{}
+		flags.EnvironmentName = envName
+		result, err := newEnvSnapshotRestoreAction(
+			azdCtx, envManager, snapshotStore, console, flags, []string{snapshot.Id}).Run(t.Context())
+		require.NoError(t, err)
+		require.Nil(t, result)
+
+		// Nothing was restored, so no snapshot of the current state was taken.
+		snapshots, err := snapshotStore.List(envName)
+		require.NoError(t, err)
+		require.Len(t, snapshots, 1)
+	})
+}
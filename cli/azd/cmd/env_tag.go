@@ -0,0 +1,203 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func envTagActions(group *actions.ActionDescriptor) {
+	tagGroup := group.Add("tag", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "tag",
+			Short: "Manage the tags of an environment, like its owner, purpose or expiry.",
+		},
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdEnvTagHelpDescription,
+			Footer:      getCmdEnvTagHelpFooter,
+		},
+	})
+
+	tagGroup.Add("set", &actions.ActionDescriptorOptions{
+		Command:        newEnvTagSetCmd(),
+		FlagsResolver:  newEnvTagFlags,
+		ActionResolver: newEnvTagSetAction,
+	})
+
+	tagGroup.Add("unset", &actions.ActionDescriptorOptions{
+		Command:        newEnvTagUnsetCmd(),
+		FlagsResolver:  newEnvTagFlags,
+		ActionResolver: newEnvTagUnsetAction,
+	})
+}
+
+type envTagFlags struct {
+	internal.EnvFlag
+	global *internal.GlobalCommandOptions
+}
+
+func newEnvTagFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envTagFlags {
+	flags := &envTagFlags{}
+	flags.Bind(cmd.Flags(), global)
+	return flags
+}
+
+func (f *envTagFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+}
+
+// azd env tag set
+
+func newEnvTagSetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <tag=value...>",
+		Short: "Set one or more tags of the environment.",
+		Long:  "Sets tags of the environment, stored in its config.json, and shown by azd env list.",
+		Args:  cobra.MinimumNArgs(1),
+		// Sample arguments used in tests
+		Annotations: map[string]string{
+			"azdtest.use": "set owner=alice",
+		},
+	}
+}
+
+type envTagSetAction struct {
+	azdCtx     *azdcontext.AzdContext
+	envManager environment.Manager
+	flags      *envTagFlags
+	args       []string
+}
+
+func newEnvTagSetAction(
+	azdCtx *azdcontext.AzdContext,
+	envManager environment.Manager,
+	flags *envTagFlags,
+	args []string,
+) actions.Action {
+	return &envTagSetAction{
+		azdCtx:     azdCtx,
+		envManager: envManager,
+		flags:      flags,
+		args:       args,
+	}
+}
+
+func (a *envTagSetAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	env, err := getFlagEnvironment(ctx, a.azdCtx, a.envManager, a.flags.EnvFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, arg := range a.args {
+		name, value, has := strings.Cut(arg, "=")
+		if !has {
+			return nil, &internal.ErrorWithSuggestion{
+				Err:        fmt.Errorf("invalid tag '%s': %w", arg, internal.ErrInvalidArgValue),
+				Suggestion: "Set tags in the <tag>=<value> format, like 'azd env tag set owner=alice'.",
+			}
+		}
+
+		if err := env.SetTag(name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := a.envManager.Save(ctx, env); err != nil {
+		return nil, fmt.Errorf("saving environment: %w", err)
+	}
+
+	return nil, nil
+}
+
+// azd env tag unset
+
+func newEnvTagUnsetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unset <tag...>",
+		Short: "Remove one or more tags of the environment.",
+		Args:  cobra.MinimumNArgs(1),
+		// Sample arguments used in tests
+		Annotations: map[string]string{
+			"azdtest.use": "unset owner",
+		},
+	}
+}
+
+type envTagUnsetAction struct {
+	azdCtx     *azdcontext.AzdContext
+	envManager environment.Manager
+	flags      *envTagFlags
+	args       []string
+}
+
+func newEnvTagUnsetAction(
+	azdCtx *azdcontext.AzdContext,
+	envManager environment.Manager,
+	flags *envTagFlags,
+	args []string,
+) actions.Action {
+	return &envTagUnsetAction{
+		azdCtx:     azdCtx,
+		envManager: envManager,
+		flags:      flags,
+		args:       args,
+	}
+}
+
+func (a *envTagUnsetAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	env, err := getFlagEnvironment(ctx, a.azdCtx, a.envManager, a.flags.EnvFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range a.args {
+		if err := env.UnsetTag(name); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := a.envManager.Save(ctx, env); err != nil {
+		return nil, fmt.Errorf("saving environment: %w", err)
+	}
+
+	return nil, nil
+}
+
+func getCmdEnvTagHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Manage the tags of an environment, to record who owns it, what it is for and when it can be deleted.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("Tags are stored in the environment's config.json. Common tags are %s, %s and %s.",
+				output.WithHighLightFormat(environment.TagOwner),
+				output.WithHighLightFormat(environment.TagPurpose),
+				output.WithHighLightFormat(environment.TagExpiry))),
+			formatHelpNote(fmt.Sprintf("Use %s to list the environments with a tag.",
+				output.WithHighLightFormat("azd env list --filter <tag>=<value>"))),
+		})
+}
+
+func getCmdEnvTagHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Tag the environment with its owner and expiry": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd env tag set"),
+			output.WithWarningFormat("owner=alice expiry=2026-12-31")),
+		"Remove the expiry of the environment": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd env tag unset"),
+			output.WithWarningFormat("expiry")),
+		"List the environments owned by alice": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd env list --filter"),
+			output.WithWarningFormat("owner=alice")),
+	})
+}
@@ -0,0 +1,37 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EnvTagActions(t *testing.T) {
+	t.Parallel()
+	azdCtx := newTestAzdContext(t)
+	env := environment.New("dev")
+	mgr := newTestEnvManager()
+	mgr.On("Get", mock.Anything, "dev").Return(env, nil)
+	mgr.On("Save", mock.Anything, env).Return(nil)
+	flags := &envTagFlags{EnvFlag: internal.EnvFlag{EnvironmentName: "dev"}}
+
+	action := newEnvTagSetAction(azdCtx, mgr, flags, []string{"owner=alice", "expiry=2026-12-31"})
+	_, err := action.Run(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"owner": "alice", "expiry": "2026-12-31"}, env.Tags())
+
+	action = newEnvTagUnsetAction(azdCtx, mgr, flags, []string{"expiry", "missing"})
+	_, err = action.Run(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"owner": "alice"}, env.Tags())
+
+	action = newEnvTagSetAction(azdCtx, mgr, flags, []string{"owner"})
+	_, err = action.Run(t.Context())
+	require.ErrorContains(t, err, "invalid tag 'owner'")
+}
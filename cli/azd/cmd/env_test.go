@@ -6,6 +6,7 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -259,6 +260,22 @@ func Test_EnvSetAction_KeyEqualsValue(t *testing.T) {
 	assert.Equal(t, "my_value", env.Getenv("MY_KEY"))
 }
 
+func Test_EnvSetAction_Secret(t *testing.T) {
+	t.Parallel()
+	azdCtx := newTestAzdContext(t)
+	env := environment.NewWithValues("test", map[string]string{})
+	mgr := newTestEnvManager()
+	mgr.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	action := newEnvSetAction(
+		azdCtx, env, mgr, mockinput.NewMockConsole(), &envSetFlags{secret: true}, []string{"API_KEY=secret"})
+	_, err := action.Run(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "secret", env.Getenv("API_KEY"))
+	assert.True(t, env.IsSecret("API_KEY"))
+	assert.Equal(t, environment.RedactedValue, env.TypedDotenv(true)["API_KEY"])
+}
+
 func Test_EnvSetAction_FromFile(t *testing.T) {
 	t.Parallel()
 
@@ -308,6 +325,64 @@ func Test_EnvSetAction_CaseConflictWarning(t *testing.T) {
 	assert.Equal(t, "new_value", env.Getenv("my_key"))
 }
 
+func Test_EnvSetAction_Type(t *testing.T) {
+	t.Parallel()
+	azdCtx := newTestAzdContext(t)
+	env := environment.NewWithValues("test", map[string]string{})
+	mgr := newTestEnvManager()
+	mgr.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	action := newEnvSetAction(
+		azdCtx, env, mgr, mockinput.NewMockConsole(), &envSetFlags{valueType: "int"}, []string{"COUNT= 007"})
+	_, err := action.Run(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "7", env.Getenv("COUNT"))
+	assert.Equal(t, json.Number("7"), env.TypedDotenv(true)["COUNT"])
+
+	// Invalid values are rejected, without setting any of the values.
+	action = newEnvSetAction(
+		azdCtx, env, mgr, mockinput.NewMockConsole(), &envSetFlags{valueType: "bool"},
+		[]string{"ENABLED=true", "OTHER=maybe"})
+	_, err = action.Run(t.Context())
+	require.ErrorContains(t, err, "invalid bool value for OTHER")
+	_, has := env.LookupEnv("ENABLED")
+	assert.False(t, has)
+
+	action = newEnvSetAction(
+		azdCtx, env, mgr, mockinput.NewMockConsole(), &envSetFlags{valueType: "float"}, []string{"COUNT=1.5"})
+	_, err = action.Run(t.Context())
+	require.ErrorContains(t, err, "invalid value type 'float'")
+}
+
+func Test_EnvSetJsonAction(t *testing.T) {
+	t.Parallel()
+	env := environment.NewWithValues("test", map[string]string{})
+	mgr := newTestEnvManager()
+	mgr.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	action := newEnvSetJsonAction(
+		env, mgr, mockinput.NewMockConsole(), &envSetJsonFlags{}, []string{"HOSTS", `[ "a", "b" ]`})
+	_, err := action.Run(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, `["a","b"]`, env.Getenv("HOSTS"))
+	assert.Equal(t, []any{"a", "b"}, env.TypedDotenv(true)["HOSTS"])
+
+	file := filepath.Join(t.TempDir(), "settings.json")
+	require.NoError(t, os.WriteFile(file, []byte("{\n  \"tier\": \"basic\"\n}\n"), 0600))
+	action = newEnvSetJsonAction(
+		env, mgr, mockinput.NewMockConsole(), &envSetJsonFlags{secret: true}, []string{"SETTINGS", "@" + file})
+	_, err = action.Run(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, `{"tier":"basic"}`, env.Getenv("SETTINGS"))
+	assert.True(t, env.IsSecret("SETTINGS"))
+	assert.Equal(t, map[string]any{"tier": "basic"}, env.TypedDotenv(false)["SETTINGS"])
+
+	action = newEnvSetJsonAction(
+		env, mgr, mockinput.NewMockConsole(), &envSetJsonFlags{}, []string{"BROKEN", `{"tier":`})
+	_, err = action.Run(t.Context())
+	require.ErrorContains(t, err, "invalid JSON value for BROKEN")
+}
+
 // --- envListAction Tests ---
 
 func Test_EnvListAction_JsonFormat(t *testing.T) {
@@ -320,9 +395,10 @@ func Test_EnvListAction_JsonFormat(t *testing.T) {
 			{Name: "env2", HasLocal: true},
 		}, nil,
 	)
+	mgr.On("Get", mock.Anything, mock.Anything).Return(environment.New("env"), nil)
 
 	buf := &bytes.Buffer{}
-	action := newEnvListAction(mgr, azdCtx, &output.JsonFormatter{}, buf)
+	action := newEnvListAction(mgr, azdCtx, &output.JsonFormatter{}, buf, &envListFlags{})
 	result, err := action.Run(t.Context())
 	require.NoError(t, err)
 	assert.Nil(t, result)
@@ -337,7 +413,7 @@ func Test_EnvListAction_NoneFormat(t *testing.T) {
 	mgr.On("List", mock.Anything).Return([]*environment.Description{}, nil)
 
 	buf := &bytes.Buffer{}
-	action := newEnvListAction(mgr, azdCtx, &output.NoneFormatter{}, buf)
+	action := newEnvListAction(mgr, azdCtx, &output.NoneFormatter{}, buf, &envListFlags{})
 	_, err := action.Run(t.Context())
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "attempted to output formatted data")
@@ -350,7 +426,7 @@ func Test_EnvListAction_ListError(t *testing.T) {
 	mgr.On("List", mock.Anything).Return(([]*environment.Description)(nil), assert.AnError)
 
 	buf := &bytes.Buffer{}
-	action := newEnvListAction(mgr, azdCtx, &output.JsonFormatter{}, buf)
+	action := newEnvListAction(mgr, azdCtx, &output.JsonFormatter{}, buf, &envListFlags{})
 	_, err := action.Run(t.Context())
 	require.Error(t, err)
 }
@@ -438,6 +514,7 @@ func Test_EnvRemoveAction_Confirmed(t *testing.T) {
 	mgr := newTestEnvManager()
 	envDesc := &environment.Description{Name: "testenv", HasLocal: true}
 	mgr.On("List", mock.Anything).Return([]*environment.Description{envDesc}, nil)
+	mgr.On("Get", mock.Anything, "testenv").Return(environment.NewWithValues("testenv", nil), nil)
 	mgr.On("Delete", "testenv").Return(nil)
 
 	buf := &bytes.Buffer{}
@@ -462,6 +539,7 @@ func Test_EnvRemoveAction_Force(t *testing.T) {
 	mgr := newTestEnvManager()
 	envDesc := &environment.Description{Name: "testenv", HasLocal: true}
 	mgr.On("List", mock.Anything).Return([]*environment.Description{envDesc}, nil)
+	mgr.On("Get", mock.Anything, "testenv").Return(environment.NewWithValues("testenv", nil), nil)
 	mgr.On("Delete", "testenv").Return(nil)
 
 	buf := &bytes.Buffer{}
@@ -521,6 +599,33 @@ func Test_EnvNewAction_OnlyEnv(t *testing.T) {
 	require.Equal(t, "newenv", defaultName)
 }
 
+func Test_EnvNewAction_ExpiresOn(t *testing.T) {
+	t.Parallel()
+	azdCtx := newTestAzdContext(t)
+	env := environment.NewWithValues("newenv", nil)
+	mgr := newTestEnvManager()
+	mgr.On("Create", mock.Anything, mock.Anything).Return(env, nil)
+	mgr.On("Save", mock.Anything, env).Return(nil)
+	mgr.On("List", mock.Anything).Return([]*environment.Description{
+		{Name: "newenv"},
+	}, nil)
+
+	flags := &envNewFlags{expiresOn: "2026-12-31"}
+	action := newEnvNewAction(azdCtx, mgr, flags, []string{"newenv"}, mockinput.NewMockConsole())
+	_, err := action.Run(t.Context())
+	require.NoError(t, err)
+
+	expiresOn, has := env.ExpiresOn()
+	require.True(t, has)
+	require.Equal(t, time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), expiresOn)
+
+	// Invalid expiry times are rejected before the environment is created.
+	flags = &envNewFlags{expiresOn: "tomorrow"}
+	action = newEnvNewAction(azdCtx, newTestEnvManager(), flags, []string{"other"}, mockinput.NewMockConsole())
+	_, err = action.Run(t.Context())
+	require.ErrorIs(t, err, internal.ErrInvalidArgValue)
+}
+
 func Test_EnvNewAction_CreateError(t *testing.T) {
 	t.Parallel()
 	azdCtx := newTestAzdContext(t)
@@ -688,14 +793,96 @@ func Test_EnvListAction_TableFormat(t *testing.T) {
 		{Name: "env1", HasLocal: true, IsDefault: true},
 		{Name: "env2", HasLocal: true},
 	}, nil)
+	mgr.On("Get", mock.Anything, mock.Anything).Return(environment.New("env"), nil)
 
 	buf := &bytes.Buffer{}
-	action := newEnvListAction(mgr, azdCtx, &output.TableFormatter{}, buf)
+	action := newEnvListAction(mgr, azdCtx, &output.TableFormatter{}, buf, &envListFlags{})
 	_, err := action.Run(t.Context())
 	require.NoError(t, err)
 	require.Contains(t, buf.String(), "env1")
 }
 
+func Test_EnvListAction_FilterByTag(t *testing.T) {
+	t.Parallel()
+	azdCtx := newTestAzdContext(t)
+	mgr := newTestEnvManager()
+	lastUsed := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	mgr.On("List", mock.Anything).Return([]*environment.Description{
+		{Name: "dev", HasLocal: true, LastUsed: &lastUsed},
+		{Name: "prod", HasLocal: true},
+		{Name: "remote", HasRemote: true},
+	}, nil)
+
+	dev := environment.New("dev")
+	require.NoError(t, dev.SetTag("owner", "alice"))
+	require.NoError(t, dev.SetTag("purpose", "testing"))
+	prod := environment.New("prod")
+	require.NoError(t, prod.SetTag("owner", "bob"))
+	mgr.On("Get", mock.Anything, "dev").Return(dev, nil)
+	mgr.On("Get", mock.Anything, "prod").Return(prod, nil)
+
+	buf := &bytes.Buffer{}
+	action := newEnvListAction(
+		mgr, azdCtx, &output.JsonFormatter{}, buf, &envListFlags{filters: []string{"owner=alice"}})
+	_, err := action.Run(t.Context())
+	require.NoError(t, err)
+
+	var envs []environment.Description
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &envs))
+	require.Len(t, envs, 1)
+	require.Equal(t, "dev", envs[0].Name)
+	require.Equal(t, map[string]string{"owner": "alice", "purpose": "testing"}, envs[0].Tags)
+	require.True(t, lastUsed.Equal(*envs[0].LastUsed))
+
+	buf.Reset()
+	action = newEnvListAction(mgr, azdCtx, &output.TableFormatter{}, buf, &envListFlags{})
+	_, err = action.Run(t.Context())
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "owner=alice purpose=testing")
+	require.Contains(t, buf.String(), "owner=bob")
+	require.Contains(t, buf.String(), "remote")
+
+	action = newEnvListAction(
+		mgr, azdCtx, &output.JsonFormatter{}, buf, &envListFlags{filters: []string{"owner"}})
+	_, err = action.Run(t.Context())
+	require.ErrorContains(t, err, "invalid filter 'owner'")
+}
+
+func Test_EnvListAction_Expired(t *testing.T) {
+	t.Parallel()
+	azdCtx := newTestAzdContext(t)
+	mgr := newTestEnvManager()
+	mgr.On("List", mock.Anything).Return([]*environment.Description{
+		{Name: "dev", HasLocal: true},
+		{Name: "prod", HasLocal: true},
+	}, nil)
+
+	dev := environment.New("dev")
+	require.NoError(t, dev.SetExpiresOn(time.Date(2020, 1, 31, 12, 0, 0, 0, time.UTC)))
+	mgr.On("Get", mock.Anything, "dev").Return(dev, nil)
+	mgr.On("Get", mock.Anything, "prod").Return(environment.New("prod"), nil)
+
+	buf := &bytes.Buffer{}
+	action := newEnvListAction(mgr, azdCtx, &output.JsonFormatter{}, buf, &envListFlags{})
+	_, err := action.Run(t.Context())
+	require.NoError(t, err)
+
+	var envs []environment.Description
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &envs))
+	require.Len(t, envs, 2)
+	require.True(t, envs[0].Expired)
+	require.NotNil(t, envs[0].ExpiresOn)
+	require.False(t, envs[1].Expired)
+	require.Nil(t, envs[1].ExpiresOn)
+
+	buf.Reset()
+	action = newEnvListAction(mgr, azdCtx, &output.TableFormatter{}, buf, &envListFlags{})
+	_, err = action.Run(t.Context())
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "(expired)")
+	require.Contains(t, buf.String(), "1 environment(s) expired. Run 'azd env prune'")
+}
+
 func Test_EnvListAction_Empty(t *testing.T) {
 	t.Parallel()
 	azdCtx := newTestAzdContext(t)
@@ -703,7 +890,7 @@ func Test_EnvListAction_Empty(t *testing.T) {
 	mgr.On("List", mock.Anything).Return([]*environment.Description{}, nil)
 
 	buf := &bytes.Buffer{}
-	action := newEnvListAction(mgr, azdCtx, &output.JsonFormatter{}, buf)
+	action := newEnvListAction(mgr, azdCtx, &output.JsonFormatter{}, buf, &envListFlags{})
 	_, err := action.Run(t.Context())
 	require.NoError(t, err)
 }
@@ -1015,6 +1202,106 @@ func Test_EnvGetValuesAction_SuccessJson(t *testing.T) {
 	require.Contains(t, buf.String(), "KEY1")
 }
 
+func Test_EnvGetValuesAction_TypedJson(t *testing.T) {
+	t.Parallel()
+	azdCtx := newTestAzdContext(t)
+	require.NoError(t, azdCtx.SetProjectState(azdcontext.ProjectState{DefaultEnvironment: "myenv"}))
+
+	env := environment.NewWithValues("myenv", map[string]string{
+		"PLAIN":    "value",
+		"REPLICAS": "3",
+		"HOSTS":    `["a","b"]`,
+		"PASSWORD": "hunter2",
+	})
+	require.NoError(t, env.SetOutputMetadata("REPLICAS", environment.OutputMetadata{Type: environment.OutputTypeNumber}))
+	require.NoError(t, env.SetOutputMetadata("HOSTS", environment.OutputMetadata{Type: environment.OutputTypeArray}))
+	require.NoError(t, env.SetOutputMetadata("PASSWORD", environment.OutputMetadata{
+		Type: environment.OutputTypeString, Secure: true,
+	}))
+
+	mgr := newTestEnvManager()
+	mgr.On("Get", mock.Anything, "myenv").Return(env, nil)
+
+	run := func(flags *envGetValuesFlags) map[string]any {
+		buf := &bytes.Buffer{}
+		action := newEnvGetValuesAction(azdCtx, mgr, mockinput.NewMockConsole(), &output.JsonFormatter{}, buf, flags)
+		_, err := action.Run(t.Context())
+		require.NoError(t, err)
+
+		var values map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &values))
+		return values
+	}
+
+	values := run(&envGetValuesFlags{})
+	require.Equal(t, map[string]any{
+		"PLAIN":    "value",
+		"REPLICAS": float64(3),
+		"HOSTS":    []any{"a", "b"},
+		"PASSWORD": environment.RedactedValue,
+	}, values)
+
+	values = run(&envGetValuesFlags{showSecrets: true})
+	require.Equal(t, "hunter2", values["PASSWORD"])
+}
+
+func Test_EnvGetValuesAction_ShowSource(t *testing.T) {
+	t.Parallel()
+	azdCtx := newTestAzdContext(t)
+	require.NoError(t, azdCtx.SetProjectState(azdcontext.ProjectState{DefaultEnvironment: "myenv"}))
+
+	dataStore := environment.NewLocalFileDataStore(azdCtx, config.NewFileConfigManager(config.NewManager()))
+	env := environment.New("myenv")
+	env.DotenvSet("REGION", "westus")
+	require.NoError(t, dataStore.Save(t.Context(), env, nil))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(azdCtx.EnvironmentDirectory(), environment.CommonDotEnvFileName),
+		[]byte("REGION=eastus\nTEAM=platform\n"),
+		0600,
+	))
+
+	env, err := dataStore.Get(t.Context(), "myenv")
+	require.NoError(t, err)
+
+	mgr := newTestEnvManager()
+	mgr.On("Get", mock.Anything, "myenv").Return(env, nil)
+
+	t.Run("EnvVars", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		action := newEnvGetValuesAction(
+			azdCtx, mgr, mockinput.NewMockConsole(), &output.EnvVarsFormatter{}, buf,
+			&envGetValuesFlags{showSource: true})
+		_, err := action.Run(t.Context())
+		require.NoError(t, err)
+
+		commonPath := filepath.Join(".azure", "common.env")
+		require.Equal(t,
+			"# "+commonPath+"\n"+
+				"TEAM=\"platform\"\n"+
+				"\n"+
+				"# "+filepath.Join(".azure", "myenv", ".env")+"\n"+
+				"AZURE_ENV_NAME=\"myenv\"\n"+
+				"# overrides the value in "+commonPath+"\n"+
+				"REGION=\"westus\"\n",
+			buf.String())
+	})
+
+	t.Run("Json", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		action := newEnvGetValuesAction(
+			azdCtx, mgr, mockinput.NewMockConsole(), &output.JsonFormatter{}, buf,
+			&envGetValuesFlags{showSource: true})
+		_, err := action.Run(t.Context())
+		require.NoError(t, err)
+
+		require.JSONEq(t, `{
+			"AZURE_ENV_NAME": {"value": "myenv", "source": "environment"},
+			"REGION": {"value": "westus", "source": "environment", "overridesCommon": true},
+			"TEAM": {"value": "platform", "source": "common"}
+		}`, buf.String())
+	})
+}
+
 func Test_EnvGetValuesAction_WithFlagOverride(t *testing.T) {
 	t.Parallel()
 	azdCtx := newTestAzdContext(t)
@@ -1123,7 +1410,7 @@ func Test_EnvListAction_ListError_DetailedMessage(t *testing.T) {
 	mgr.On("List", mock.Anything).Return(([]*environment.Description)(nil), fmt.Errorf("list failed"))
 
 	buf := &bytes.Buffer{}
-	action := newEnvListAction(mgr, azdCtx, &output.JsonFormatter{}, buf)
+	action := newEnvListAction(mgr, azdCtx, &output.JsonFormatter{}, buf, &envListFlags{})
 	_, err := action.Run(t.Context())
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "listing environments")
@@ -2257,7 +2544,7 @@ func Test_EnvListAction_FormatError(t *testing.T) {
 		[]*environment.Description{{Name: "env1"}}, nil)
 
 	// NoneFormatter always returns error on Format()
-	action := newEnvListAction(mgr, azdCtx, &output.NoneFormatter{}, &bytes.Buffer{})
+	action := newEnvListAction(mgr, azdCtx, &output.NoneFormatter{}, &bytes.Buffer{}, &envListFlags{})
 	_, err := action.Run(t.Context())
 	require.Error(t, err)
 }
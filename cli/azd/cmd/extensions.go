@@ -47,22 +47,40 @@ func isJsonOutputFromArgs(args []string) bool {
 // as an absolute path). Passing the raw flag through to extensions would cause them
 // to re-resolve a relative path against the already-changed CWD, doubling the path.
 func stripCwdFlag(args []string) []string {
+	return stripFlag(args, "cwd", "C")
+}
+
+// stripAnswersFlag removes --answers and its value from an argument list.
+// Answers files only answer the prompts of core azd, and extensions don't define the flag.
+func stripAnswersFlag(args []string) []string {
+	return stripFlag(args, "answers", "")
+}
+
+// stripPromptTimeoutFlag removes --prompt-timeout and its value from an argument list.
+// Prompt timeouts only apply to the prompts of core azd, and extensions don't define the flag.
+func stripPromptTimeoutFlag(args []string) []string {
+	return stripFlag(args, "prompt-timeout", "")
+}
+
+// stripFlag removes the string flag with the given long name, and optional short name, and its value from an
+// argument list.
+func stripFlag(args []string, long string, short string) []string {
 	result := make([]string, 0, len(args))
 	for i := 0; i < len(args); i++ {
 		arg := args[i]
 
-		// --cwd=value or -C=value (combined form with equals)
-		if strings.HasPrefix(arg, "--cwd=") || strings.HasPrefix(arg, "-C=") {
+		// --flag=value or -f=value (combined form with equals)
+		if strings.HasPrefix(arg, "--"+long+"=") || short != "" && strings.HasPrefix(arg, "-"+short+"=") {
 			continue
 		}
 
-		// -Cvalue (combined shorthand without equals, e.g. -CmyFolder)
-		if len(arg) > 2 && strings.HasPrefix(arg, "-C") && arg[2] != '-' {
+		// -fvalue (combined shorthand without equals, e.g. -CmyFolder)
+		if short != "" && len(arg) > 2 && strings.HasPrefix(arg, "-"+short) && arg[2] != '-' {
 			continue
 		}
 
-		// --cwd value or -C value (separate value)
-		if (arg == "--cwd" || arg == "-C") && i+1 < len(args) {
+		// --flag value or -f value (separate value)
+		if (arg == "--"+long || short != "" && arg == "-"+short) && i+1 < len(args) {
 			i++ // skip the value
 			continue
 		}
@@ -299,7 +317,9 @@ func (a *extensionAction) Run(ctx context.Context) (*actions.ActionResult, error
 	// directory, changed CWD, set AZD_CWD to absolute path). Passing the
 	// raw relative -C value through would cause the extension to resolve
 	// it again against the already-changed CWD, doubling the path.
-	extensionArgs := stripCwdFlag(a.args)
+	//
+	// --answers and --prompt-timeout are stripped too: they only apply to the prompts of core azd.
+	extensionArgs := stripPromptTimeoutFlag(stripAnswersFlag(stripCwdFlag(a.args)))
 
 	options := &extensions.InvokeOptions{
 		Args: extensionArgs,
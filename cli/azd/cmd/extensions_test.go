@@ -242,6 +242,28 @@ func TestStripCwdFlag(t *testing.T) {
 	}
 }
 
+func Test_StripAnswersFlag(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t,
+		[]string{"run", "--debug"},
+		stripAnswersFlag([]string{"run", "--answers", "answers.yaml", "--debug"}))
+	require.Equal(t,
+		[]string{"run", "-a", "value"},
+		stripAnswersFlag([]string{"--answers=answers.yaml", "run", "-a", "value"}))
+}
+
+func Test_StripPromptTimeoutFlag(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t,
+		[]string{"run", "--debug"},
+		stripPromptTimeoutFlag([]string{"run", "--prompt-timeout", "30", "--debug"}))
+	require.Equal(t,
+		[]string{"run"},
+		stripPromptTimeoutFlag([]string{"--prompt-timeout=30", "run"}))
+}
+
 func Test_ExtensionAction_MissingAnnotation(t *testing.T) {
 	t.Parallel()
 	cmd := &cobra.Command{Use: "test"}
@@ -0,0 +1,298 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func flagsActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
+	group := root.Add("flags", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "flags",
+			Short: "Manage the feature flags of your environments.",
+		},
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdFlagsHelpDescription,
+			Footer:      getCmdFlagsHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupBeta,
+		},
+		RequireLogin: true,
+	})
+
+	group.Add("set", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "set <name>",
+			Short: "Create or update a feature flag.",
+			Args:  cobra.ExactArgs(1),
+		},
+		FlagsResolver:  newFlagsSetFlags,
+		ActionResolver: newFlagsSetAction,
+	})
+
+	group.Add("enable", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "enable <name>",
+			Short: "Enable a feature flag.",
+			Args:  cobra.ExactArgs(1),
+		},
+		FlagsResolver:  newFlagsToggleFlags,
+		ActionResolver: newFlagsEnableAction,
+	})
+
+	group.Add("disable", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "disable <name>",
+			Short: "Disable a feature flag.",
+			Args:  cobra.ExactArgs(1),
+		},
+		FlagsResolver:  newFlagsToggleFlags,
+		ActionResolver: newFlagsDisableAction,
+	})
+
+	return group
+}
+
+type flagsSetFlags struct {
+	internal.EnvFlag
+	global      *internal.GlobalCommandOptions
+	description string
+	enabled     bool
+}
+
+func (f *flagsSetFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+	local.StringVar(&f.description, "description", "", "The description of the feature flag.")
+	local.BoolVar(
+		&f.enabled,
+		"enabled",
+		false,
+		"Enables the feature flag. When not set, new flags are created disabled and existing flags keep their state.",
+	)
+}
+
+func newFlagsSetFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *flagsSetFlags {
+	flags := &flagsSetFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+type flagsToggleFlags struct {
+	internal.EnvFlag
+	global *internal.GlobalCommandOptions
+}
+
+func (f *flagsToggleFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+}
+
+func newFlagsToggleFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *flagsToggleFlags {
+	flags := &flagsToggleFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+// featureFlagsStore locates the App Configuration store of the environment and updates its feature flags.
+type featureFlagsStore struct {
+	env                 *environment.Environment
+	console             input.Console
+	featureFlagsService *azapi.FeatureFlagsService
+}
+
+func newFeatureFlagsStore(
+	env *environment.Environment,
+	console input.Console,
+	featureFlagsService *azapi.FeatureFlagsService,
+) *featureFlagsStore {
+	return &featureFlagsStore{
+		env:                 env,
+		console:             console,
+		featureFlagsService: featureFlagsService,
+	}
+}
+
+// update gets the feature flag with the given name, or a new disabled flag when it doesn't exist, applies the change
+// and saves the flag in the store of the environment.
+func (s *featureFlagsStore) update(
+	ctx context.Context,
+	name string,
+	change func(flag *azapi.FeatureFlag),
+) (*azapi.FeatureFlag, error) {
+	endpoint := infra.AppConfigurationEndpoint(s.env)
+	if endpoint == "" {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf(
+				"environment '%s' has no App Configuration store for feature flags: %w",
+				s.env.Name(), internal.ErrResourceNotConfigured),
+			Suggestion: "Run 'azd add' and select 'Feature flags' to add a store to your project, " +
+				"then run 'azd provision' to create it.",
+		}
+	}
+
+	subscriptionId := s.env.GetSubscriptionId()
+
+	stepMessage := fmt.Sprintf("Saving feature flag %s", output.WithHighLightFormat(name))
+	s.console.ShowSpinner(ctx, stepMessage, input.Step)
+
+	flag, err := s.featureFlagsService.GetFeatureFlag(ctx, subscriptionId, endpoint, name)
+	if errors.Is(err, azapi.ErrFeatureFlagNotFound) {
+		flag, err = azapi.NewFeatureFlag(name), nil
+	}
+
+	if err == nil {
+		change(flag)
+		err = s.featureFlagsService.SetFeatureFlag(ctx, subscriptionId, endpoint, flag)
+	}
+
+	s.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+	if err != nil {
+		return nil, fmt.Errorf("saving feature flag '%s': %w", name, err)
+	}
+
+	return flag, nil
+}
+
+func featureFlagResult(env *environment.Environment, flag *azapi.FeatureFlag) *actions.ActionResult {
+	state := "disabled"
+	if flag.Enabled {
+		state = "enabled"
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Feature flag '%s' is %s in environment '%s'.", flag.ID, state, env.Name()),
+		},
+	}
+}
+
+type flagsSetAction struct {
+	args  []string
+	flags *flagsSetFlags
+	env   *environment.Environment
+	store *featureFlagsStore
+}
+
+func newFlagsSetAction(
+	args []string,
+	flags *flagsSetFlags,
+	env *environment.Environment,
+	console input.Console,
+	featureFlagsService *azapi.FeatureFlagsService,
+) actions.Action {
+	return &flagsSetAction{
+		args:  args,
+		flags: flags,
+		env:   env,
+		store: newFeatureFlagsStore(env, console, featureFlagsService),
+	}
+}
+
+func (a *flagsSetAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	flag, err := a.store.update(ctx, a.args[0], func(flag *azapi.FeatureFlag) {
+		if a.flags.description != "" {
+			flag.Description = a.flags.description
+		}
+
+		if a.flags.enabled {
+			flag.Enabled = true
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return featureFlagResult(a.env, flag), nil
+}
+
+type flagsToggleAction struct {
+	args    []string
+	env     *environment.Environment
+	store   *featureFlagsStore
+	enabled bool
+}
+
+func newFlagsEnableAction(
+	args []string,
+	_ *flagsToggleFlags,
+	env *environment.Environment,
+	console input.Console,
+	featureFlagsService *azapi.FeatureFlagsService,
+) actions.Action {
+	return &flagsToggleAction{
+		args:    args,
+		env:     env,
+		store:   newFeatureFlagsStore(env, console, featureFlagsService),
+		enabled: true,
+	}
+}
+
+func newFlagsDisableAction(
+	args []string,
+	_ *flagsToggleFlags,
+	env *environment.Environment,
+	console input.Console,
+	featureFlagsService *azapi.FeatureFlagsService,
+) actions.Action {
+	return &flagsToggleAction{
+		args:    args,
+		env:     env,
+		store:   newFeatureFlagsStore(env, console, featureFlagsService),
+		enabled: false,
+	}
+}
+
+func (a *flagsToggleAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	flag, err := a.store.update(ctx, a.args[0], func(flag *azapi.FeatureFlag) {
+		flag.Enabled = a.enabled
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return featureFlagResult(a.env, flag), nil
+}
+
+func getCmdFlagsHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Manage the feature flags stored in the App Configuration store of an environment.",
+		[]string{
+			formatHelpNote(fmt.Sprintf(
+				"Run %s and select 'Feature flags' to add the store to your project. Services that use it receive"+
+					" its endpoint in %s and are granted read access with their managed identity.",
+				output.WithHighLightFormat("azd add"),
+				output.WithHighLightFormat("AZURE_APPCONFIGURATION_ENDPOINT"))),
+			formatHelpNote(
+				"Each environment has its own store, so flags can be turned on in one environment without" +
+					" affecting the others."),
+		})
+}
+
+func getCmdFlagsHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Create a disabled feature flag with a description.": output.WithHighLightFormat(
+			"azd flags set beta-ui --description \"New user interface\""),
+		"Enable a feature flag in the staging environment.": output.WithHighLightFormat(
+			"azd flags enable beta-ui -e staging"),
+		"Disable a feature flag.": output.WithHighLightFormat(
+			"azd flags disable beta-ui"),
+	})
+}
@@ -0,0 +1,142 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+)
+
+func newFlagsTestEnv() *environment.Environment {
+	return environment.NewWithValues("dev", map[string]string{
+		environment.SubscriptionIdEnvVarName: "SUB",
+		"AZURE_APPCONFIGURATION_ENDPOINT":    "https://appcs-test.azconfig.io",
+	})
+}
+
+// mockFeatureFlagStore serves the feature flags in flags, keyed by name, and records the flags that are saved.
+func mockFeatureFlagStore(mockContext *mocks.MockContext, flags map[string]*azapi.FeatureFlag) {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.URL.Host == "appcs-test.azconfig.io"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		name := request.URL.Path[len("/kv/"+azapi.FeatureFlagKeyPrefix):]
+
+		if request.Method == http.MethodPut {
+			raw, err := io.ReadAll(request.Body)
+			if err != nil {
+				return nil, err
+			}
+
+			var keyValue map[string]string
+			if err := json.Unmarshal(raw, &keyValue); err != nil {
+				return nil, err
+			}
+
+			var flag azapi.FeatureFlag
+			if err := json.Unmarshal([]byte(keyValue["value"]), &flag); err != nil {
+				return nil, err
+			}
+
+			flags[name] = &flag
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, keyValue)
+		}
+
+		flag, has := flags[name]
+		if !has {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusNotFound)
+		}
+
+		value, err := json.Marshal(flag)
+		if err != nil {
+			return nil, err
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]string{
+			"key":          azapi.FeatureFlagKeyPrefix + name,
+			"value":        string(value),
+			"content_type": azapi.FeatureFlagContentType,
+		})
+	})
+}
+
+func Test_FlagsSetAction(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	flags := map[string]*azapi.FeatureFlag{}
+	mockFeatureFlagStore(mockContext, flags)
+
+	service := azapi.NewFeatureFlagsService(mockContext.SubscriptionCredentialProvider, mockContext.CoreClientOptions)
+	env := newFlagsTestEnv()
+
+	action := newFlagsSetAction(
+		[]string{"beta-ui"},
+		&flagsSetFlags{description: "New user interface"},
+		env,
+		mockContext.Console,
+		service,
+	)
+
+	result, err := action.Run(*mockContext.Context)
+	require.NoError(t, err)
+	assert.Equal(t, "Feature flag 'beta-ui' is disabled in environment 'dev'.", result.Message.Header)
+
+	require.Contains(t, flags, "beta-ui")
+	assert.Equal(t, "New user interface", flags["beta-ui"].Description)
+	assert.False(t, flags["beta-ui"].Enabled)
+	assert.JSONEq(t, `{"client_filters":[]}`, string(flags["beta-ui"].Conditions))
+}
+
+func Test_FlagsToggleAction(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	flags := map[string]*azapi.FeatureFlag{
+		"beta-ui": {
+			ID:          "beta-ui",
+			Description: "New user interface",
+			Conditions:  json.RawMessage(`{"client_filters":[{"name":"Microsoft.Percentage"}]}`),
+		},
+	}
+	mockFeatureFlagStore(mockContext, flags)
+
+	service := azapi.NewFeatureFlagsService(mockContext.SubscriptionCredentialProvider, mockContext.CoreClientOptions)
+	env := newFlagsTestEnv()
+
+	enable := newFlagsEnableAction([]string{"beta-ui"}, &flagsToggleFlags{}, env, mockContext.Console, service)
+	result, err := enable.Run(*mockContext.Context)
+	require.NoError(t, err)
+	assert.Equal(t, "Feature flag 'beta-ui' is enabled in environment 'dev'.", result.Message.Header)
+
+	assert.True(t, flags["beta-ui"].Enabled)
+	assert.Equal(t, "New user interface", flags["beta-ui"].Description)
+	assert.JSONEq(t,
+		`{"client_filters":[{"name":"Microsoft.Percentage"}]}`, string(flags["beta-ui"].Conditions))
+
+	disable := newFlagsDisableAction([]string{"beta-ui"}, &flagsToggleFlags{}, env, mockContext.Console, service)
+	_, err = disable.Run(*mockContext.Context)
+	require.NoError(t, err)
+	assert.False(t, flags["beta-ui"].Enabled)
+}
+
+func Test_FlagsAction_NoStore(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	service := azapi.NewFeatureFlagsService(mockContext.SubscriptionCredentialProvider, mockContext.CoreClientOptions)
+	env := environment.NewWithValues("dev", map[string]string{
+		environment.SubscriptionIdEnvVarName: "SUB",
+	})
+
+	action := newFlagsEnableAction([]string{"beta-ui"}, &flagsToggleFlags{}, env, mockContext.Console, service)
+	_, err := action.Run(*mockContext.Context)
+
+	var errWithSuggestion *internal.ErrorWithSuggestion
+	require.ErrorAs(t, err, &errWithSuggestion)
+	assert.Contains(t, errWithSuggestion.Err.Error(), "has no App Configuration store")
+}
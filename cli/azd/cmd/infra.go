@@ -42,6 +42,20 @@ func infraActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 		UseMiddleware("hooks", middleware.NewHooksMiddleware).
 		UseMiddleware("extensions", middleware.NewExtensionsMiddleware)
 
+	group.
+		Add("diff", &actions.ActionDescriptorOptions{
+			Command:        newInfraDiffCmd(),
+			FlagsResolver:  newInfraDiffFlags,
+			ActionResolver: newInfraDiffAction,
+			OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+			DefaultFormat:  output.NoneFormat,
+			HelpOptions: actions.ActionHelpOptions{
+				Description: getCmdInfraDiffHelpDescription,
+				Footer:      getCmdInfraDiffHelpFooter,
+			},
+			RequireLogin: true,
+		})
+
 	group.
 		Add("generate", &actions.ActionDescriptorOptions{
 			Command:        newInfraGenerateCmd(),
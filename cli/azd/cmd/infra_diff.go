@@ -0,0 +1,236 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/internal/cmd"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// infraDiffDriftExitCode is the exit code of `azd infra diff` when drift is detected, so scheduled checks can tell
+// drift apart from a failure to compute the diff, which exits with 1.
+const infraDiffDriftExitCode = 2
+
+type infraDiffFlags struct {
+	internal.EnvFlag
+}
+
+func (f *infraDiffFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag.Bind(local, global)
+}
+
+func newInfraDiffFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *infraDiffFlags {
+	flags := &infraDiffFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newInfraDiffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff [<layer>]",
+		Short: "Compare the infrastructure templates with the deployed Azure resources.",
+		Args:  cobra.MaximumNArgs(1),
+	}
+}
+
+func getCmdInfraDiffHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Compare the Bicep or Terraform templates with the deployed Azure resources, without changing them. "+
+			"Exits with code 2 when provisioning would create, modify, replace or delete a resource.",
+		[]string{
+			formatHelpNote("Bicep templates are compared with a what-if operation, Terraform templates with a plan."),
+			formatHelpNote("When <layer> is specified, only compares the given layer."),
+		})
+}
+
+func getCmdInfraDiffHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Check every layer for drift.": output.WithHighLightFormat("azd infra diff --no-prompt"),
+		"Check a layer for drift and get the changes as JSON.": output.WithHighLightFormat(
+			"azd infra diff <layer> --output json",
+		),
+	})
+}
+
+type infraDiffAction struct {
+	args             []string
+	flags            *infraDiffFlags
+	projectConfig    *project.ProjectConfig
+	projectManager   project.ProjectManager
+	importManager    *project.ImportManager
+	provisionManager *provisioning.Manager
+	console          input.Console
+	formatter        output.Formatter
+	writer           io.Writer
+}
+
+func newInfraDiffAction(
+	args []string,
+	flags *infraDiffFlags,
+	projectConfig *project.ProjectConfig,
+	projectManager project.ProjectManager,
+	importManager *project.ImportManager,
+	provisionManager *provisioning.Manager,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+) actions.Action {
+	return &infraDiffAction{
+		args:             args,
+		flags:            flags,
+		projectConfig:    projectConfig,
+		projectManager:   projectManager,
+		importManager:    importManager,
+		provisionManager: provisionManager,
+		console:          console,
+		formatter:        formatter,
+		writer:           writer,
+	}
+}
+
+func (a *infraDiffAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	showUx := a.formatter.Kind() == output.NoneFormat
+	if showUx {
+		a.console.MessageUxItem(ctx, &ux.MessageTitle{
+			Title:     "Comparing infrastructure with Azure resources (azd infra diff)",
+			TitleNote: "No changes will be applied to your Azure resources.",
+		})
+	}
+
+	if err := a.projectManager.Initialize(ctx, a.projectConfig); err != nil {
+		return nil, err
+	}
+
+	if err := a.projectManager.EnsureAllTools(ctx, a.projectConfig, nil); err != nil {
+		return nil, err
+	}
+
+	infra, err := a.importManager.ProjectInfrastructure(ctx, a.projectConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = infra.Cleanup() }()
+
+	layers := infra.Options.GetLayers()
+	if len(a.args) > 0 {
+		layer, err := infra.Options.GetLayer(a.args[0])
+		if err != nil {
+			return nil, err
+		}
+
+		layers = []provisioning.Options{layer}
+	}
+
+	result := contracts.InfraDiff{Layers: []contracts.InfraDiffLayer{}}
+	for _, layer := range layers {
+		if err := a.provisionManager.Initialize(ctx, a.projectConfig.Path, layer); err != nil {
+			return nil, fmt.Errorf("initializing provisioning manager: %w", err)
+		}
+
+		preview, err := a.provisionManager.Diff(ctx)
+		if err != nil {
+			if layer.Name != "" {
+				return nil, fmt.Errorf("layer '%s': %w", layer.Name, err)
+			}
+
+			return nil, err
+		}
+
+		drift := driftChanges(preview)
+		layerDiff := contracts.InfraDiffLayer{Name: layer.Name, Changes: []contracts.InfraDiffChange{}}
+		for _, change := range drift {
+			layerDiff.Changes = append(layerDiff.Changes, contracts.InfraDiffChange{
+				ChangeType:   string(change.ChangeType),
+				ResourceType: change.ResourceType,
+				Name:         change.Name,
+			})
+		}
+		result.Layers = append(result.Layers, layerDiff)
+		result.Drift = result.Drift || len(drift) > 0
+
+		if showUx {
+			if layer.Name != "" {
+				a.console.Message(ctx, fmt.Sprintf("Layer: %s", output.WithHighLightFormat(layer.Name)))
+			}
+
+			if len(drift) == 0 {
+				a.console.Message(ctx, "No drift detected.\n")
+			} else {
+				a.console.MessageUxItem(ctx, cmd.DeployResultToUx(&provisioning.DeployPreviewResult{
+					Preview: &provisioning.DeploymentPreview{
+						Status:     preview.Preview.Status,
+						Properties: &provisioning.DeploymentPreviewProperties{Changes: drift},
+					},
+				}))
+			}
+		}
+	}
+
+	if !showUx {
+		if err := a.formatter.Format(result, a.writer, nil); err != nil {
+			return nil, fmt.Errorf("formatting diff: %w", err)
+		}
+	}
+
+	if result.Drift {
+		changed := 0
+		for _, layer := range result.Layers {
+			changed += len(layer.Changes)
+		}
+
+		return nil, &internal.ExitCodeError{
+			ExitCode: infraDiffDriftExitCode,
+			Err: &internal.ErrorWithSuggestion{
+				Err: fmt.Errorf("provisioning would change %d resource(s): %w", changed, internal.ErrDriftDetected),
+				Suggestion: "Run 'azd provision' to bring the Azure resources back in line with the templates, " +
+					"or update the templates to match the changes made to the resources.",
+			},
+		}
+	}
+
+	if !showUx {
+		return nil, nil
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: "The Azure resources match the infrastructure templates.",
+		},
+	}, nil
+}
+
+// driftChanges returns the changes in preview that mean the deployed resources differ from the templates. Resources
+// the provider can't compare in detail, reported as Deploy or Unsupported, aren't counted as drift.
+func driftChanges(preview *provisioning.DeployPreviewResult) []*provisioning.DeploymentPreviewChange {
+	var drift []*provisioning.DeploymentPreviewChange
+	if preview == nil || preview.Preview == nil || preview.Preview.Properties == nil {
+		return drift
+	}
+
+	for _, change := range preview.Preview.Properties.Changes {
+		switch change.ChangeType {
+		case provisioning.ChangeTypeCreate,
+			provisioning.ChangeTypeDelete,
+			provisioning.ChangeTypeModify,
+			provisioning.ChangeTypeReplace:
+			drift = append(drift, change)
+		}
+	}
+
+	return drift
+}
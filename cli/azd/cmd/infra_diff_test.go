@@ -0,0 +1,54 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriftChanges(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		changeType provisioning.ChangeType
+		drift      bool
+	}{
+		{changeType: provisioning.ChangeTypeCreate, drift: true},
+		{changeType: provisioning.ChangeTypeDelete, drift: true},
+		{changeType: provisioning.ChangeTypeModify, drift: true},
+		{changeType: provisioning.ChangeTypeReplace, drift: true},
+		{changeType: provisioning.ChangeTypeNoChange},
+		{changeType: provisioning.ChangeTypeIgnore},
+		{changeType: provisioning.ChangeTypeDeploy},
+		{changeType: provisioning.ChangeTypeUnsupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.changeType), func(t *testing.T) {
+			t.Parallel()
+			change := &provisioning.DeploymentPreviewChange{ChangeType: tt.changeType, Name: "app-web"}
+			drift := driftChanges(&provisioning.DeployPreviewResult{
+				Preview: &provisioning.DeploymentPreview{
+					Properties: &provisioning.DeploymentPreviewProperties{
+						Changes: []*provisioning.DeploymentPreviewChange{change},
+					},
+				},
+			})
+
+			if tt.drift {
+				require.Equal(t, []*provisioning.DeploymentPreviewChange{change}, drift)
+			} else {
+				require.Empty(t, drift)
+			}
+		})
+	}
+
+	t.Run("NoPreview", func(t *testing.T) {
+		t.Parallel()
+		require.Empty(t, driftChanges(&provisioning.DeployPreviewResult{}))
+	})
+}
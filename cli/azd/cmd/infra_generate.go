@@ -5,11 +5,14 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
@@ -32,6 +35,7 @@ type infraGenerateFlags struct {
 	global *internal.GlobalCommandOptions
 	*internal.EnvFlag
 	force bool
+	only  []string
 }
 
 func newInfraGenerateFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *infraGenerateFlags {
@@ -47,6 +51,13 @@ func (f *infraGenerateFlags) Bind(local *pflag.FlagSet, global *internal.GlobalC
 	f.global = global
 	f.EnvFlag.Bind(local, global)
 	local.BoolVar(&f.force, "force", false, "Overwrite any existing files without prompting")
+	local.StringArrayVar(
+		&f.only,
+		"only",
+		nil,
+		"Writes only the given generated file of the infra directory, such as resources.bicep, for customization, "+
+			"while the rest of the infrastructure keeps being generated from azure.yaml. Can be repeated.",
+	)
 }
 
 func newInfraGenerateCmd() *cobra.Command {
@@ -118,6 +129,13 @@ func (a *infraGenerateAction) Run(ctx context.Context) (*actions.ActionResult, e
 			output.WithHighLightFormat("azd infra generate"))
 	}
 
+	if len(a.flags.only) > 0 && a.importManager.HasAppHost(ctx, a.projectConfig) {
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        fmt.Errorf("--only isn't supported for .NET Aspire projects: %w", internal.ErrInvalidArgValue),
+			Suggestion: "Run 'azd infra gen' without --only to write all the infrastructure of the project.",
+		}
+	}
+
 	spinnerMessage := "Generating infrastructure"
 
 	a.console.ShowSpinner(ctx, spinnerMessage, input.Step)
@@ -128,6 +146,22 @@ func (a *infraGenerateAction) Run(ctx context.Context) (*actions.ActionResult, e
 	}
 	a.console.StopSpinner(ctx, spinnerMessage, input.StepDone)
 
+	infraOptions, err := a.projectConfig.Infra.GetWithDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	infraPath := infraOptions.Path
+	infraRoot := infraPath
+	if !filepath.IsAbs(infraRoot) {
+		infraRoot = filepath.Join(a.azdCtx.ProjectDirectory(), infraRoot)
+	}
+
+	manifest, err := project.LoadInfraManifest(infraRoot)
+	if err != nil {
+		return nil, err
+	}
+
 	staging, err := os.MkdirTemp("", "infra-generate")
 	if err != nil {
 		return nil, err
@@ -159,36 +193,129 @@ func (a *infraGenerateAction) Run(ctx context.Context) (*actions.ActionResult, e
 		return nil, err
 	}
 
-	options := copy.Options{}
+	stagingInfra := filepath.Join(staging, infraPath)
+	if len(a.flags.only) > 0 {
+		if err := keepOnly(stagingInfra, a.flags.only, infraOptions.Module); err != nil {
+			return nil, err
+		}
+	}
 
-	if a.flags.force {
-		options.Skip = func(fileInfo os.FileInfo, src, dest string) (bool, error) {
-			return false, nil
+	var skipStagingFiles map[string]struct{}
+	if !a.flags.force {
+		// generated files that weren't edited since they were generated are overwritten without prompting
+		unmodified := func(file string) bool {
+			path, err := filepath.Rel(infraPath, file)
+			return err == nil && manifest.Unmodified(infraRoot, path)
 		}
 
-	} else {
-		skipStagingFiles, err := a.promptForDuplicates(ctx, staging, a.azdCtx.ProjectDirectory())
+		skipStagingFiles, err = a.promptForDuplicates(ctx, staging, a.azdCtx.ProjectDirectory(), unmodified)
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		if skipStagingFiles != nil {
-			options.Skip = func(fileInfo os.FileInfo, src, dest string) (bool, error) {
-				_, skip := skipStagingFiles[src]
-				return skip, nil
-			}
-		}
+	options := copy.Options{
+		Skip: func(fileInfo os.FileInfo, src, dest string) (bool, error) {
+			_, skip := skipStagingFiles[src]
+			return skip, nil
+		},
 	}
 
 	if err := copy.Copy(staging, a.azdCtx.ProjectDirectory(), options); err != nil {
 		return nil, fmt.Errorf("copying contents from temp staging directory: %w", err)
 	}
 
+	if filepath.IsAbs(infraPath) {
+		// the infra directory is outside of the project, and isn't generated
+		return nil, nil
+	}
+
+	err = filepath.WalkDir(stagingInfra, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(stagingInfra, path)
+		if err != nil {
+			return err
+		}
+
+		if _, skip := skipStagingFiles[path]; skip {
+			manifest.SetUserOwned(rel)
+		} else if len(a.flags.only) > 0 {
+			manifest.Eject(rel)
+		} else {
+			contents, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			manifest.SetGenerated(rel, contents)
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("tracking generated files: %w", err)
+	}
+
+	if err := manifest.Save(infraRoot); err != nil {
+		return nil, fmt.Errorf("saving infra manifest: %w", err)
+	}
+
+	if len(a.flags.only) > 0 {
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: fmt.Sprintf("Wrote %s to %s for customization.", strings.Join(a.flags.only, ", "), infraPath),
+				FollowUp: "The rest of the infrastructure keeps being generated from azure.yaml, " +
+					"with your version of these files.",
+			},
+		}, nil
+	}
+
 	return nil, nil
 }
 
+// keepOnly removes the generated files of the infra directory dir, except for the files of only. The main module of
+// the infrastructure can't be kept alone, since azd only generates the infrastructure when there's no main module.
+func keepOnly(dir string, only []string, module string) error {
+	keep := map[string]struct{}{}
+	for _, file := range only {
+		path := filepath.Clean(filepath.FromSlash(file))
+		if strings.TrimSuffix(path, filepath.Ext(path)) == module {
+			return &internal.ErrorWithSuggestion{
+				Err: fmt.Errorf(
+					"%s can't be written on its own, since it replaces the generated infrastructure: %w",
+					file, internal.ErrInvalidArgValue),
+				Suggestion: "Run 'azd infra gen' without --only to write all the infrastructure of the project.",
+			}
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, path)); err != nil {
+			return fmt.Errorf("%s isn't a generated infrastructure file: %w", file, internal.ErrInvalidArgValue)
+		}
+
+		keep[filepath.Join(dir, path)] = struct{}{}
+	}
+
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		if _, has := keep[path]; has {
+			return nil
+		}
+
+		return os.Remove(path)
+	})
+}
+
 func (a *infraGenerateAction) promptForDuplicates(
-	ctx context.Context, staging string, target string) (skipSourceFiles map[string]struct{}, err error) {
+	ctx context.Context,
+	staging string,
+	target string,
+	unmodified func(file string) bool) (skipSourceFiles map[string]struct{}, err error) {
 	log.Printf(
 		"infrastructure generate, checking for duplicates. source: %s target: %s",
 		staging,
@@ -200,6 +327,8 @@ func (a *infraGenerateAction) promptForDuplicates(
 		return nil, fmt.Errorf("checking for overwrites: %w", err)
 	}
 
+	duplicateFiles = slices.DeleteFunc(duplicateFiles, unmodified)
+
 	if len(duplicateFiles) > 0 {
 		a.console.StopSpinner(ctx, "", input.StepDone)
 		a.console.MessageUxItem(ctx, &ux.WarningMessage{
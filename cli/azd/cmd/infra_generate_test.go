@@ -227,3 +227,38 @@ func Test_InfraGenerateAction_RecordsInfraProvider(t *testing.T) {
 		})
 	}
 }
+
+func Test_KeepOnly(t *testing.T) {
+	t.Parallel()
+
+	newDir := func(t *testing.T) string {
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "modules"), 0700))
+		for _, file := range []string{"main.bicep", "resources.bicep", "modules/role-assignment.bicep"} {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(file), 0600))
+		}
+		return dir
+	}
+
+	t.Run("Keeps", func(t *testing.T) {
+		t.Parallel()
+		dir := newDir(t)
+		require.NoError(t, keepOnly(dir, []string{"resources.bicep"}, "main"))
+
+		require.FileExists(t, filepath.Join(dir, "resources.bicep"))
+		require.NoFileExists(t, filepath.Join(dir, "main.bicep"))
+		require.NoFileExists(t, filepath.Join(dir, "modules", "role-assignment.bicep"))
+	})
+
+	t.Run("Module", func(t *testing.T) {
+		t.Parallel()
+		err := keepOnly(newDir(t), []string{"main.bicep"}, "main")
+		require.ErrorIs(t, err, internal.ErrInvalidArgValue)
+	})
+
+	t.Run("NotGenerated", func(t *testing.T) {
+		t.Parallel()
+		err := keepOnly(newDir(t), []string{"network.bicep"}, "main")
+		require.ErrorIs(t, err, internal.ErrInvalidArgValue)
+	})
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/internal/tracing"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
@@ -72,15 +73,17 @@ is reused.`,
 }
 
 type initFlags struct {
-	templatePath   string
-	templateBranch string
-	templateTags   []string
-	subscription   string
-	location       string
-	global         *internal.GlobalCommandOptions
-	fromCode       bool
-	minimal        bool
-	up             bool
+	templatePath      string
+	templateBranch    string
+	templateTags      []string
+	subscription      string
+	location          string
+	global            *internal.GlobalCommandOptions
+	fromCode          bool
+	fromResourceGroup string
+	minimal           bool
+	refreshServices   bool
+	up                bool
 	internal.EnvFlag
 }
 
@@ -120,6 +123,18 @@ func (i *initFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOpt
 		false,
 		"Initializes a new application from your existing code.",
 	)
+	local.StringVar(
+		&i.fromResourceGroup,
+		"from-resource-group",
+		"",
+		"Initializes a new application from the resources of an existing resource group.",
+	)
+	local.BoolVar(
+		&i.refreshServices,
+		"refresh-services",
+		false,
+		"Detects the projects of your existing code that aren't services yet, and adds them to azure.yaml.",
+	)
 	local.BoolVarP(
 		&i.minimal,
 		"minimal",
@@ -156,6 +171,7 @@ type initAction struct {
 	agentFactory      agent.AgentFactory
 	consentManager    consent.ConsentManager
 	configManager     config.UserConfigManager
+	resourceService   *azapi.ResourceService
 	// isRunningInAgent reports whether azd was invoked by an AI agent.
 	// Defaults to agentdetect.IsRunningInAgent; overridable in tests.
 	isRunningInAgent func() bool
@@ -177,6 +193,7 @@ func newInitAction(
 	agentFactory agent.AgentFactory,
 	consentManager consent.ConsentManager,
 	configManager config.UserConfigManager,
+	resourceService *azapi.ResourceService,
 ) actions.Action {
 	return &initAction{
 		lazyAzdCtx:        lazyAzdCtx,
@@ -194,6 +211,7 @@ func newInitAction(
 		agentFactory:      agentFactory,
 		consentManager:    consentManager,
 		configManager:     configManager,
+		resourceService:   resourceService,
 		isRunningInAgent:  agentdetect.IsRunningInAgent,
 	}
 }
@@ -223,10 +241,17 @@ func (i *initAction) Run(ctx context.Context) (_ *actions.ActionResult, retErr e
 	if i.flags.minimal {
 		initModeCount++
 	}
+	if i.flags.fromResourceGroup != "" {
+		initModeCount++
+	}
+	if i.flags.refreshServices {
+		initModeCount++
+	}
 	if initModeCount > 1 {
 		return nil, &internal.ErrorWithSuggestion{
-			Err:        internal.ErrMultipleInitModes,
-			Suggestion: "Choose one: 'azd init --template <url>', 'azd init --from-code', or 'azd init --minimal'.",
+			Err: internal.ErrMultipleInitModes,
+			Suggestion: "Choose one: 'azd init --template <url>', 'azd init --from-code', 'azd init --minimal', " +
+				"'azd init --from-resource-group <name>', or 'azd init --refresh-services'.",
 		}
 	}
 
@@ -374,6 +399,10 @@ func (i *initAction) Run(ctx context.Context) (_ *actions.ActionResult, retErr e
 		initTypeSelect = initAppTemplate
 	} else if i.flags.fromCode || i.flags.minimal {
 		initTypeSelect = initFromApp
+	} else if i.flags.fromResourceGroup != "" {
+		initTypeSelect = initFromResourceGroup
+	} else if i.flags.refreshServices {
+		initTypeSelect = initRefreshServices
 	}
 
 	if initTypeSelect == initUnknown {
@@ -508,6 +537,49 @@ func (i *initAction) Run(ctx context.Context) (_ *actions.ActionResult, retErr e
 			}
 			return nil, err
 		}
+	case initFromResourceGroup:
+		tracing.SetUsageAttributes(fields.InitMethod.String("resource-group"))
+		if existingProject {
+			return nil, &internal.ErrorWithSuggestion{
+				Err: fmt.Errorf("%s already exists: %w", azdcontext.ProjectFileName, internal.ErrInvalidFlagCombination),
+				Suggestion: "Run 'azd init --from-resource-group' in a directory without a project, " +
+					"or 'azd add --existing' to add existing resources to this project.",
+			}
+		}
+
+		if err := i.initFromResourceGroup(ctx, azdCtx); err != nil {
+			if errors.Is(err, errInitEnvCancelled) {
+				return initCancelledResult(), nil
+			}
+			return nil, err
+		}
+
+		header = fmt.Sprintf("Initialized the project from resource group %s.",
+			output.WithHighLightFormat(i.flags.fromResourceGroup))
+		followUp = "Run " + output.WithHighLightFormat("azd deploy") +
+			" to deploy your services to their existing resources.\n" +
+			"Run " + output.WithHighLightFormat("azd add") + " to add new Azure components to your project."
+	case initRefreshServices:
+		tracing.SetUsageAttributes(fields.InitMethod.String("refresh-services"))
+		if !existingProject {
+			return nil, &internal.ErrorWithSuggestion{
+				Err: fmt.Errorf("--refresh-services requires an existing %s: %w",
+					azdcontext.ProjectFileName, internal.ErrInvalidFlagCombination),
+				Suggestion: "Run 'azd init --from-code' to initialize the project from your existing code.",
+			}
+		}
+
+		added, err := i.repoInitializer.RefreshServices(ctx, azdCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		header = "No services were added to " + azdcontext.ProjectFileName + "."
+		followUp = ""
+		if len(added) > 0 {
+			header = fmt.Sprintf("Added %s to %s.", ux.ListAsText(added), azdcontext.ProjectFileName)
+			followUp = "Run " + output.WithHighLightFormat("azd up") + " to provision and deploy the new services to Azure."
+		}
 	case initEnvironment:
 		tracing.SetUsageAttributes(fields.InitMethod.String("environment"))
 		env, err := i.initializeEnv(ctx, azdCtx, templates.Metadata{})
@@ -700,6 +772,8 @@ const (
 	initAppTemplate
 	initEnvironment
 	initWithAgent
+	initFromResourceGroup
+	initRefreshServices
 )
 
 func promptInitType(
@@ -769,6 +843,39 @@ func promptInitType(
 	}
 }
 
+// initFromResourceGroup initializes the project from the resources of the resource group of --from-resource-group.
+func (i *initAction) initFromResourceGroup(ctx context.Context, azdCtx *azdcontext.AzdContext) error {
+	subscriptionId := i.flags.subscription
+	if subscriptionId == "" {
+		subscriptionId = os.Getenv(environment.SubscriptionIdEnvVarName)
+	}
+
+	if subscriptionId == "" {
+		return &internal.ErrorWithSuggestion{
+			Err:        fmt.Errorf("--from-resource-group requires a subscription: %w", internal.ErrInvalidFlagCombination),
+			Suggestion: fmt.Sprintf("Add '--subscription <id>', or set %s.", environment.SubscriptionIdEnvVarName),
+		}
+	}
+
+	// the environment is linked to the subscription of the resource group
+	i.flags.subscription = subscriptionId
+
+	resourceGroup, err := i.resourceService.GetResourceGroup(ctx, subscriptionId, i.flags.fromResourceGroup)
+	if err != nil {
+		return err
+	}
+
+	resources, err := i.resourceService.ListResourceGroupResources(ctx, subscriptionId, resourceGroup.Name, nil)
+	if err != nil {
+		return fmt.Errorf("listing resources of resource group %s: %w", resourceGroup.Name, err)
+	}
+
+	return i.repoInitializer.InitFromResourceGroup(ctx, azdCtx, resourceGroup, resources,
+		func() (*environment.Environment, error) {
+			return i.initializeEnv(ctx, azdCtx, templates.Metadata{})
+		})
+}
+
 func (i *initAction) initializeTemplate(
 	ctx context.Context,
 	azdCtx *azdcontext.AzdContext) (templates.Template, error) {
@@ -1229,6 +1336,12 @@ func getCmdInitHelpFooter(*cobra.Command) string {
 			output.WithWarningFormat("[GitHub repo URL]"),
 			output.WithHighLightFormat("."),
 		),
+		"Initialize a project from the resources of an existing resource group.": fmt.Sprintf("%s %s %s %s",
+			output.WithHighLightFormat("azd init --from-resource-group"),
+			output.WithWarningFormat("[Resource group]"),
+			output.WithHighLightFormat("--subscription"),
+			output.WithWarningFormat("[Subscription ID]"),
+		),
 		"Initialize a template from a branch other than main.": fmt.Sprintf("%s %s %s %s",
 			output.WithHighLightFormat("azd init --template"),
 			output.WithWarningFormat("[GitHub repo URL]"),
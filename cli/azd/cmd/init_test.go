@@ -738,6 +738,7 @@ func Test_NewInitAction(t *testing.T) {
 		nil, // agentFactory
 		nil, // consentManager
 		nil, // configManager
+		nil, // resourceService
 	)
 	require.NotNil(t, action)
 }
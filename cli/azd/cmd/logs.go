@@ -0,0 +1,271 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/servicelogs"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// logsHosts are the hosts of the services whose logs can be streamed.
+var logsHosts = []project.ServiceTargetKind{
+	project.ContainerAppTarget,
+	project.AppServiceTarget,
+	project.AzureFunctionTarget,
+}
+
+// logsPrefixColors are the colors of the prefixes of the log lines of each service, when several services are
+// streamed.
+var logsPrefixColors = []func(string, ...any) string{
+	color.CyanString,
+	color.GreenString,
+	color.MagentaString,
+	color.YellowString,
+	color.BlueString,
+	color.HiCyanString,
+	color.HiGreenString,
+	color.HiMagentaString,
+}
+
+type logsFlags struct {
+	since  time.Duration
+	follow bool
+	internal.EnvFlag
+}
+
+func (f *logsFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.DurationVar(
+		&f.since,
+		"since",
+		0,
+		"Only show logs written within this duration, such as 10m or 2h. Function apps default to 1h.",
+	)
+	local.BoolVarP(&f.follow, "follow", "f", false, "Keep streaming new logs until Ctrl+C is pressed.")
+	f.EnvFlag.Bind(local, global)
+}
+
+func newLogsFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *logsFlags {
+	flags := &logsFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newLogsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "logs [<service>...]",
+		Short: "Stream the logs of deployed services.",
+	}
+}
+
+func getCmdLogsHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Stream the logs of the resources that host the services. Without a service name, the logs of every service "+
+			"that supports it are streamed, each line prefixed with the name of its service.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("%s services stream the console logs of the latest ready revision.",
+				output.WithHighLightFormat("containerapp"))),
+			formatHelpNote(fmt.Sprintf("%s services stream the log stream of the app.",
+				output.WithHighLightFormat("appservice"))),
+			formatHelpNote(fmt.Sprintf("%s services read the traces of the app from Application Insights.",
+				output.WithHighLightFormat("function"))),
+		})
+}
+
+func getCmdLogsHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Show the recent logs of every service.": output.WithHighLightFormat("azd logs"),
+		"Stream the logs of the api service from the last 10 minutes until Ctrl+C is pressed.": output.WithHighLightFormat(
+			"azd logs api --since 10m --follow",
+		),
+	})
+}
+
+type logsAction struct {
+	args             []string
+	flags            *logsFlags
+	projectConfig    *project.ProjectConfig
+	importManager    *project.ImportManager
+	env              *environment.Environment
+	resourceManager  project.ResourceManager
+	creds            account.SubscriptionCredentialProvider
+	armClientOptions *arm.ClientOptions
+	cloud            *cloud.Cloud
+	writer           io.Writer
+}
+
+func newLogsAction(
+	args []string,
+	flags *logsFlags,
+	projectConfig *project.ProjectConfig,
+	importManager *project.ImportManager,
+	env *environment.Environment,
+	resourceManager project.ResourceManager,
+	creds account.SubscriptionCredentialProvider,
+	armClientOptions *arm.ClientOptions,
+	cloud *cloud.Cloud,
+	writer io.Writer,
+) actions.Action {
+	return &logsAction{
+		args:             args,
+		flags:            flags,
+		projectConfig:    projectConfig,
+		importManager:    importManager,
+		env:              env,
+		resourceManager:  resourceManager,
+		creds:            creds,
+		armClientOptions: armClientOptions,
+		cloud:            cloud,
+		writer:           writer,
+	}
+}
+
+func (l *logsAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	services, err := l.services(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptionId := l.env.GetSubscriptionId()
+	credential, err := l.creds.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+	streamer := servicelogs.NewStreamer(credential, l.cloud, l.armClientOptions)
+
+	targets := make([]*environment.TargetResource, len(services))
+	for i, svc := range services {
+		targets[i], err = l.resourceManager.GetTargetResource(ctx, subscriptionId, svc)
+		if err != nil {
+			return nil, fmt.Errorf("getting the resource of service '%s': %w", svc.Name, err)
+		}
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Ctrl+C stops streaming, which isn't a failure.
+	pop := input.PushInterruptHandler(func() bool {
+		cancel()
+		return true
+	})
+	defer pop()
+
+	var writeMu sync.Mutex
+	var wg sync.WaitGroup
+	errs := make([]error, len(services))
+	for i, svc := range services {
+		prefix := ""
+		if len(services) > 1 {
+			prefix = logsPrefixColors[i%len(logsPrefixColors)]("[%s]", svc.Name) + " "
+		}
+
+		wg.Go(func() {
+			err := streamer.Stream(streamCtx, targets[i], servicelogs.Options{
+				Since:  l.flags.since,
+				Follow: l.flags.follow,
+			}, func(line string) {
+				writeMu.Lock()
+				defer writeMu.Unlock()
+				fmt.Fprintln(l.writer, prefix+line)
+			})
+
+			if err != nil && !errors.Is(err, context.Canceled) {
+				errs[i] = fmt.Errorf("streaming the logs of service '%s': %w", svc.Name, err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	// Streams stopped with Ctrl+C end the command successfully, but a canceled command doesn't.
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return nil, nil
+}
+
+// services returns the services named in the arguments, or every service whose host supports logs.
+func (l *logsAction) services(ctx context.Context) ([]*project.ServiceConfig, error) {
+	stableServices, err := l.importManager.ServiceStable(ctx, l.projectConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	supported := func(svc *project.ServiceConfig) bool {
+		return slices.Contains(logsHosts, svc.Host)
+	}
+	supportedHosts := make([]string, len(logsHosts))
+	for i, host := range logsHosts {
+		supportedHosts[i] = string(host)
+	}
+
+	if len(l.args) == 0 {
+		var services []*project.ServiceConfig
+		for _, svc := range stableServices {
+			if supported(svc) {
+				services = append(services, svc)
+			}
+		}
+
+		if len(services) == 0 {
+			return nil, &internal.ErrorWithSuggestion{
+				Err: errors.New("the project has no services to stream the logs of"),
+				Suggestion: fmt.Sprintf(
+					"Logs can be streamed for services with host %s.", strings.Join(supportedHosts, ", ")),
+			}
+		}
+
+		return services, nil
+	}
+
+	var services []*project.ServiceConfig
+	for _, name := range l.args {
+		index := slices.IndexFunc(stableServices, func(svc *project.ServiceConfig) bool { return svc.Name == name })
+		if index < 0 {
+			return nil, fmt.Errorf("service '%s' isn't defined in azure.yaml: %w", name, internal.ErrInvalidArgValue)
+		}
+
+		svc := stableServices[index]
+		if !supported(svc) {
+			return nil, &internal.ErrorWithSuggestion{
+				Err: fmt.Errorf(
+					"streaming the logs of service '%s' with host '%s' isn't supported: %w",
+					name, svc.Host, internal.ErrInvalidArgValue),
+				Suggestion: fmt.Sprintf(
+					"Logs can be streamed for services with host %s.", strings.Join(supportedHosts, ", ")),
+			}
+		}
+
+		if !slices.Contains(services, svc) {
+			services = append(services, svc)
+		}
+	}
+
+	return services, nil
+}
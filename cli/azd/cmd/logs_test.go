@@ -0,0 +1,61 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogsServices(t *testing.T) {
+	projectConfig := &project.ProjectConfig{
+		Name: "test",
+		Services: map[string]*project.ServiceConfig{
+			"api":    {Name: "api", Host: project.ContainerAppTarget},
+			"web":    {Name: "web", Host: project.StaticWebAppTarget},
+			"worker": {Name: "worker", Host: project.AzureFunctionTarget},
+		},
+	}
+
+	services := func(args ...string) ([]*project.ServiceConfig, error) {
+		action := &logsAction{
+			args:          args,
+			projectConfig: projectConfig,
+			importManager: project.NewImportManager(nil),
+		}
+		return action.services(t.Context())
+	}
+
+	t.Run("AllSupported", func(t *testing.T) {
+		result, err := services()
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		require.Equal(t, "api", result[0].Name)
+		require.Equal(t, "worker", result[1].Name)
+	})
+
+	t.Run("Named", func(t *testing.T) {
+		result, err := services("worker", "worker")
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.Equal(t, "worker", result[0].Name)
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		_, err := services("web")
+		require.ErrorIs(t, err, internal.ErrInvalidArgValue)
+		suggestion, ok := errors.AsType[*internal.ErrorWithSuggestion](err)
+		require.True(t, ok)
+		require.Contains(t, suggestion.Suggestion, "containerapp, appservice, function")
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		_, err := services("missing")
+		require.ErrorContains(t, err, "service 'missing' isn't defined in azure.yaml")
+	})
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 )
 
 type HooksMiddleware struct {
@@ -198,7 +199,14 @@ func (m *HooksMiddleware) createServiceEventHandler(
 	hooksRunner *ext.HooksRunner,
 ) ext.EventHandlerFn[project.ServiceLifecycleEventArgs] {
 	return func(ctx context.Context, eventArgs project.ServiceLifecycleEventArgs) error {
-		return hooksRunner.RunHooks(ctx, hookType, "service", nil, hookName)
+		var options *tools.ExecutionContext
+		if eventArgs.Service != nil {
+			options = &tools.ExecutionContext{
+				EnvVars: []string{fmt.Sprintf("%s=%s", ext.HookEnvServiceName, eventArgs.Service.Name)},
+			}
+		}
+
+		return hooksRunner.RunHooks(ctx, hookType, "service", options, hookName)
 	}
 }
 
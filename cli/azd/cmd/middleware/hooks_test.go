@@ -203,6 +203,51 @@ func Test_CommandHooks_Middleware_PreHookWithErrorAndContinue(t *testing.T) {
 	require.True(t, *actionRan)
 }
 
+func Test_CommandHooks_Middleware_OnErrorHook(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	registerHookExecutors(mockContext)
+	azdContext := createAzdContext(t)
+
+	envName := "test"
+	runOptions := Options{CommandPath: "deploy"}
+
+	projectConfig := project.ProjectConfig{
+		Name: envName,
+		Hooks: map[string][]*ext.HookConfig{
+			"onerror": {
+				{
+					Run:   "echo $AZD_ERROR_MESSAGE",
+					Shell: string(language.HookKindBash),
+				},
+			},
+		},
+	}
+
+	err := ensureAzdValid(mockContext, azdContext, envName, &projectConfig)
+	require.NoError(t, err)
+
+	var errorHookEnv []string
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return true
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		errorHookEnv = args.Env
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	deployErr := errors.New("deploying service 'api': timeout")
+	nextFn := func(ctx context.Context) (*actions.ActionResult, error) {
+		return nil, deployErr
+	}
+
+	result, err := runMiddleware(mockContext, envName, &projectConfig, &runOptions, nextFn)
+
+	require.Nil(t, result)
+	require.ErrorIs(t, err, deployErr)
+	require.Contains(t, errorHookEnv, "AZD_ERROR_COMMAND=deploy")
+	require.Contains(t, errorHookEnv, "AZD_ERROR_STAGE=deploy")
+	require.Contains(t, errorHookEnv, "AZD_ERROR_MESSAGE=deploying service 'api': timeout")
+}
+
 func Test_CommandHooks_Middleware_WithCmdAlias(t *testing.T) {
 	mockContext := mocks.NewMockContext(t.Context())
 	registerHookExecutors(mockContext)
@@ -253,6 +298,7 @@ func Test_ServiceHooks_Registered(t *testing.T) {
 
 	serviceConfig := &project.ServiceConfig{
 		EventDispatcher: ext.NewEventDispatcher[project.ServiceLifecycleEventArgs](project.ServiceEvents...),
+		Name:            "api",
 		Language:        "ts",
 		RelativePath:    "./src/api",
 		Host:            "appservice",
@@ -274,6 +320,7 @@ func Test_ServiceHooks_Registered(t *testing.T) {
 		return strings.Contains(command, "predeploy")
 	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
 		preDeployCount++
+		require.Contains(t, args.Env, "AZD_SERVICE_NAME=api")
 		return exec.NewRunResult(0, "", ""), nil
 	})
 
@@ -13,6 +13,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/resource"
 	"github.com/azure/azure-dev/cli/azd/pkg/auth"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/errorhandler"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/workflow"
@@ -60,6 +61,8 @@ func (l *LoginGuardMiddleware) Run(ctx context.Context, next NextFn) (*actions.A
 		if errors.Is(err, auth.ErrNoCurrentUser) {
 			return nil, &internal.ErrorWithSuggestion{
 				Err:        err,
+				Code:       "not_logged_in",
+				Category:   errorhandler.ErrorCategoryAuth,
 				Suggestion: "Run 'azd auth login' to sign in before running this command.",
 			}
 		}
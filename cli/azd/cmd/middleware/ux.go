@@ -44,6 +44,9 @@ func (m *UxMiddleware) Run(ctx context.Context, next NextFn) (*actions.ActionRes
 	// Stop the spinner always to un-hide cursor
 	m.console.StopSpinner(ctx, "", input.Step)
 
+	// Errors and the result of the action are written even with --quiet.
+	ctx = input.WithOutputVerbosity(ctx, input.VerbosityQuiet)
+
 	// User intentionally aborted — not a failure.
 	// The action already printed a message; swallow the error so the CLI exits with code 0.
 	if errors.Is(err, internal.ErrAbortedByUser) {
@@ -57,6 +60,8 @@ func (m *UxMiddleware) Run(ctx context.Context, next NextFn) (*actions.ActionRes
 		if suggestionErr, ok := errors.AsType[*internal.ErrorWithSuggestion](err); ok {
 			displayErr := &ux.ErrorWithSuggestion{
 				Err:        suggestionErr.Err,
+				Code:       suggestionErr.Code,
+				Category:   suggestionErr.Category,
 				Message:    suggestionErr.Message,
 				Suggestion: suggestionErr.Suggestion,
 				Links:      suggestionErr.Links,
@@ -4,13 +4,18 @@
 package middleware
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"os"
 	"testing"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/errorhandler"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/stretchr/testify/require"
 )
@@ -80,3 +85,59 @@ func TestUxMiddleware_Success_ShowsActionResult(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, actionResult, result)
 }
+
+func TestUxMiddleware_Quiet_ShowsResultAndErrors(t *testing.T) {
+	t.Parallel()
+	buf := &bytes.Buffer{}
+	console := input.NewConsole(false, false, input.Writers{Output: buf}, input.ConsoleHandles{
+		Stdin:  os.Stdin,
+		Stdout: buf,
+		Stderr: buf,
+	}, &output.NoneFormatter{}, nil)
+	console.(*input.AskerConsole).SetVerbosity(input.VerbosityQuiet)
+	ux := NewUxMiddleware(&Options{}, console, &alpha.FeatureManager{})
+
+	_, err := ux.Run(t.Context(), func(ctx context.Context) (*actions.ActionResult, error) {
+		console.Message(ctx, "Packaging services")
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: "All done!",
+			},
+		}, nil
+	})
+	require.NoError(t, err)
+	require.NotContains(t, buf.String(), "Packaging services")
+	require.Contains(t, buf.String(), "All done!")
+
+	_, err = ux.Run(t.Context(), func(ctx context.Context) (*actions.ActionResult, error) {
+		return nil, errors.New("deployment failed")
+	})
+	require.Error(t, err)
+	require.Contains(t, buf.String(), "ERROR: deployment failed")
+}
+
+func TestUxMiddleware_ErrorCode_Json(t *testing.T) {
+	t.Parallel()
+	buf := &bytes.Buffer{}
+	console := input.NewConsole(false, false, input.Writers{Output: buf}, input.ConsoleHandles{
+		Stdin:  os.Stdin,
+		Stdout: buf,
+		Stderr: buf,
+	}, &output.JsonFormatter{}, nil)
+	ux := NewUxMiddleware(&Options{}, console, &alpha.FeatureManager{})
+
+	_, err := ux.Run(t.Context(), func(ctx context.Context) (*actions.ActionResult, error) {
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        errors.New("InsufficientQuota: not enough cores"),
+			Code:       "insufficient_quota",
+			Category:   errorhandler.ErrorCategoryProvisioning,
+			Message:    "Your subscription has insufficient quota for this resource.",
+			Suggestion: "Request a quota increase.",
+		}
+	})
+	require.Error(t, err)
+
+	// Automation keys off the code and category of the error, rather than its message.
+	require.Contains(t, buf.String(), `"code":"insufficient_quota"`)
+	require.Contains(t, buf.String(), `"category":"provisioning"`)
+}
@@ -6,7 +6,11 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
@@ -19,7 +23,9 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/monitorquery"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -28,6 +34,7 @@ type monitorFlags struct {
 	monitorLive     bool
 	monitorLogs     bool
 	monitorOverview bool
+	query           string
 	global          *internal.GlobalCommandOptions
 	internal.EnvFlag
 }
@@ -41,6 +48,12 @@ func (m *monitorFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommand
 	)
 	local.BoolVar(&m.monitorLogs, "logs", false, "Open a browser to Application Insights Logs.")
 	local.BoolVar(&m.monitorOverview, "overview", false, "Open a browser to Application Insights Overview Dashboard.")
+	local.StringVar(
+		&m.query,
+		"query",
+		"",
+		"Run a predefined or azure.yaml KQL query, such as requests, failures, exceptions, traces or dependencies.",
+	)
 	m.EnvFlag.Bind(local, global)
 	m.global = global
 }
@@ -69,6 +82,12 @@ type monitorAction struct {
 	flags                *monitorFlags
 	portalUrlBase        string
 	alphaFeaturesManager *alpha.FeatureManager
+	projectConfig        *project.ProjectConfig
+	credentialProvider   account.SubscriptionCredentialProvider
+	armClientOptions     *arm.ClientOptions
+	cloud                *cloud.Cloud
+	formatter            output.Formatter
+	writer               io.Writer
 }
 
 func newMonitorAction(
@@ -81,6 +100,11 @@ func newMonitorAction(
 	flags *monitorFlags,
 	cloud *cloud.Cloud,
 	alphaFeatureManager *alpha.FeatureManager,
+	projectConfig *project.ProjectConfig,
+	credentialProvider account.SubscriptionCredentialProvider,
+	armClientOptions *arm.ClientOptions,
+	formatter output.Formatter,
+	writer io.Writer,
 ) actions.Action {
 	return &monitorAction{
 		azdCtx:               azdCtx,
@@ -92,10 +116,21 @@ func newMonitorAction(
 		subResolver:          subResolver,
 		portalUrlBase:        cloud.PortalUrlBase,
 		alphaFeaturesManager: alphaFeatureManager,
+		projectConfig:        projectConfig,
+		credentialProvider:   credentialProvider,
+		armClientOptions:     armClientOptions,
+		cloud:                cloud,
+		formatter:            formatter,
+		writer:               writer,
 	}
 }
 
 func (m *monitorAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if m.flags.query != "" && (m.flags.monitorLive || m.flags.monitorLogs || m.flags.monitorOverview) {
+		return nil, fmt.Errorf(
+			"--query can't be combined with --live, --logs or --overview: %w", internal.ErrInvalidFlagCombination)
+	}
+
 	if !m.flags.monitorLive && !m.flags.monitorLogs && !m.flags.monitorOverview {
 		m.flags.monitorOverview = true
 	}
@@ -107,34 +142,30 @@ func (m *monitorAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		}
 	}
 
+	if m.flags.query != "" {
+		return nil, m.runQuery(ctx)
+	}
+
 	aspireDashboard := apphost.AspireDashboardUrl(ctx, m.env, m.alphaFeaturesManager)
 	if aspireDashboard != nil {
 		openWithDefaultBrowser(ctx, m.console, aspireDashboard.Link)
 		return nil, nil
 	}
 
-	resourceGroups, err := m.resourceManager.GetResourceGroupsForEnvironment(ctx, m.env.GetSubscriptionId(), m.env.Name())
+	resources, err := m.environmentResources(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("discovering resource groups from deployment: %w", err)
+		return nil, err
 	}
 
 	var insightsResources []*azapi.ResourceExtended
 	var portalResources []*azapi.ResourceExtended
 
-	for _, resourceGroup := range resourceGroups {
-		resources, err := m.resourceService.ListResourceGroupResources(
-			ctx, azure.SubscriptionFromRID(resourceGroup.Id), resourceGroup.Name, nil)
-		if err != nil {
-			return nil, fmt.Errorf("listing resources: %w", err)
-		}
-
-		for _, resource := range resources {
-			switch resource.Type {
-			case string(azapi.AzureResourceTypePortalDashboard):
-				portalResources = append(portalResources, resource)
-			case string(azapi.AzureResourceTypeAppInsightComponent):
-				insightsResources = append(insightsResources, resource)
-			}
+	for _, resource := range resources {
+		switch resource.Type {
+		case string(azapi.AzureResourceTypePortalDashboard):
+			portalResources = append(portalResources, resource)
+		case string(azapi.AzureResourceTypeAppInsightComponent):
+			insightsResources = append(insightsResources, resource)
 		}
 	}
 
@@ -180,6 +211,125 @@ func (m *monitorAction) Run(ctx context.Context) (*actions.ActionResult, error)
 	return nil, nil
 }
 
+// environmentResources returns the resources of the resource groups of the environment.
+func (m *monitorAction) environmentResources(ctx context.Context) ([]*azapi.ResourceExtended, error) {
+	resourceGroups, err := m.resourceManager.GetResourceGroupsForEnvironment(ctx, m.env.GetSubscriptionId(), m.env.Name())
+	if err != nil {
+		return nil, fmt.Errorf("discovering resource groups from deployment: %w", err)
+	}
+
+	var all []*azapi.ResourceExtended
+	for _, resourceGroup := range resourceGroups {
+		resources, err := m.resourceService.ListResourceGroupResources(
+			ctx, azure.SubscriptionFromRID(resourceGroup.Id), resourceGroup.Name, nil)
+		if err != nil {
+			return nil, fmt.Errorf("listing resources: %w", err)
+		}
+
+		all = append(all, resources...)
+	}
+
+	return all, nil
+}
+
+// runQuery runs the query named by --query against the first resource of the environment that its source supports,
+// and writes the rows it returns.
+func (m *monitorAction) runQuery(ctx context.Context) error {
+	projectQueries := m.projectConfig.MonitorQueries()
+
+	query, found := monitorquery.Find(m.flags.query, projectQueries)
+	if !found {
+		var available []string
+		for _, queries := range [][]monitorquery.Query{projectQueries, monitorquery.Predefined} {
+			for _, q := range queries {
+				available = append(available, fmt.Sprintf("  %s: %s", q.Name, q.Description))
+			}
+		}
+
+		return &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("query '%s' isn't a predefined query or declared in azure.yaml: %w",
+				m.flags.query, internal.ErrInvalidArgValue),
+			Suggestion: "Run one of these queries, or declare it under monitor.queries in azure.yaml:\n" +
+				strings.Join(available, "\n"),
+		}
+	}
+
+	resources, err := m.environmentResources(ctx)
+	if err != nil {
+		return err
+	}
+
+	resourceType := query.Source.ResourceType()
+	var target *azapi.ResourceExtended
+	for _, resource := range resources {
+		if strings.EqualFold(resource.Type, string(resourceType)) {
+			target = resource
+			break
+		}
+	}
+
+	if target == nil {
+		return &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("no %s found to run query '%s' against: %w",
+				azapi.GetResourceTypeDisplayName(resourceType), query.Name, internal.ErrResourceNotConfigured),
+			Suggestion: fmt.Sprintf("Ensure your infrastructure includes a %s resource.", resourceType),
+		}
+	}
+
+	credential, err := m.credentialProvider.CredentialForSubscription(ctx, m.env.GetSubscriptionId())
+	if err != nil {
+		return err
+	}
+
+	result, err := monitorquery.NewClient(credential, m.cloud, m.armClientOptions).Run(ctx, target.Id, query.Kql)
+	if err != nil {
+		return fmt.Errorf("running query '%s' against %s: %w", query.Name, target.Name, err)
+	}
+
+	if m.formatter.Kind() != output.TableFormat {
+		return m.formatter.Format(result.Records(), m.writer, nil)
+	}
+
+	if len(result.Rows) == 0 {
+		m.console.Message(ctx, "No results.")
+		return nil
+	}
+
+	columns := make([]output.Column, len(result.Columns))
+	for i, name := range result.Columns {
+		columns[i] = output.Column{
+			Heading:       name,
+			ValueTemplate: fmt.Sprintf("{{index . %d}}", i),
+		}
+	}
+
+	rows := make([][]string, len(result.Rows))
+	for i, row := range result.Rows {
+		rows[i] = make([]string, len(result.Columns))
+		for j := range result.Columns {
+			if j < len(row) {
+				rows[i][j] = formatQueryValue(row[j])
+			}
+		}
+	}
+
+	return m.formatter.Format(rows, m.writer, output.TableFormatterOptions{Columns: columns})
+}
+
+// formatQueryValue formats a value of a query result for a table cell.
+func formatQueryValue(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.ReplaceAll(strings.TrimRight(value, "\r\n"), "\n", " ")
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
 func getCmdMonitorHelpDescription(*cobra.Command) string {
 	return generateCmdHelpDescription(
 		fmt.Sprintf("Monitor a deployed application %s. For more information, go to: %s.",
@@ -192,5 +342,6 @@ func getCmdMonitorHelpFooter(c *cobra.Command) string {
 		"Open Application Insights Overview Dashboard.": output.WithHighLightFormat("azd monitor --overview"),
 		"Open Application Insights Live Metrics.":       output.WithHighLightFormat("azd monitor --live"),
 		"Open Application Insights Logs.":               output.WithHighLightFormat("azd monitor --logs"),
+		"Show the failed requests of the last hour.":    output.WithHighLightFormat("azd monitor --query failures"),
 	})
 }
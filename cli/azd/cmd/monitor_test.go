@@ -4,6 +4,7 @@
 package cmd
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/spf13/cobra"
@@ -12,6 +13,8 @@ import (
 
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
 )
 
@@ -20,7 +23,7 @@ func Test_NewMonitorAction_Constructor(t *testing.T) {
 	flags := &monitorFlags{}
 	console := mockinput.NewMockConsole()
 	c := &cloud.Cloud{PortalUrlBase: "https://portal.azure.com"}
-	a := newMonitorAction(nil, nil, nil, nil, nil, console, flags, c, nil)
+	a := newMonitorAction(nil, nil, nil, nil, nil, console, flags, c, nil, nil, nil, nil, nil, nil)
 	ma := a.(*monitorAction)
 	require.Same(t, flags, ma.flags)
 	require.Equal(t, "https://portal.azure.com", ma.portalUrlBase)
@@ -40,3 +43,48 @@ func Test_NewMonitorFlags(t *testing.T) {
 	flags := newMonitorFlags(cmd, global)
 	require.NotNil(t, flags)
 }
+
+func Test_MonitorAction_Query(t *testing.T) {
+	t.Parallel()
+	env := environment.NewWithValues("test", map[string]string{environment.SubscriptionIdEnvVarName: "SUB_ID"})
+	projectConfig := &project.ProjectConfig{
+		Monitor: &project.MonitorConfig{
+			Queries: map[string]*project.MonitorQueryConfig{
+				"slow": {Description: "Slow requests", Query: "requests | where duration > 1000"},
+			},
+		},
+	}
+
+	t.Run("Unknown", func(t *testing.T) {
+		t.Parallel()
+		flags := &monitorFlags{query: "missing"}
+		a := newMonitorAction(nil, env, nil, nil, nil, mockinput.NewMockConsole(), flags, cloud.AzurePublic(), nil,
+			projectConfig, nil, nil, nil, nil)
+
+		_, err := a.Run(t.Context())
+		require.ErrorIs(t, err, internal.ErrInvalidArgValue)
+		withSuggestion, ok := errors.AsType[*internal.ErrorWithSuggestion](err)
+		require.True(t, ok)
+		require.Contains(t, withSuggestion.Suggestion, "slow: Slow requests")
+		require.Contains(t, withSuggestion.Suggestion, "failures: ")
+	})
+
+	t.Run("CombinedWithPortal", func(t *testing.T) {
+		t.Parallel()
+		flags := &monitorFlags{query: "slow", monitorLogs: true}
+		a := newMonitorAction(nil, env, nil, nil, nil, mockinput.NewMockConsole(), flags, cloud.AzurePublic(), nil,
+			projectConfig, nil, nil, nil, nil)
+
+		_, err := a.Run(t.Context())
+		require.ErrorIs(t, err, internal.ErrInvalidFlagCombination)
+	})
+}
+
+func Test_FormatQueryValue(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "", formatQueryValue(nil))
+	require.Equal(t, "1500000", formatQueryValue(float64(1500000)))
+	require.Equal(t, "12.5", formatQueryValue(12.5))
+	require.Equal(t, "first second", formatQueryValue("first\nsecond\n"))
+	require.Equal(t, "true", formatQueryValue(true))
+}
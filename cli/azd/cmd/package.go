@@ -7,26 +7,40 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/artifacts"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/progress"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/syft"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
 type packageFlags struct {
 	all    bool
+	tags   []string
 	global *internal.GlobalCommandOptions
 	*internal.EnvFlag
 	outputPath string
+	push       bool
+	sbom       bool
+	manifest   string
 }
 
 func newPackageFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *packageFlags {
@@ -49,12 +63,37 @@ func (pf *packageFlags) Bind(local *pflag.FlagSet, global *internal.GlobalComman
 		false,
 		"Packages all services that are listed in "+azdcontext.ProjectFileName,
 	)
+	local.StringSliceVar(
+		&pf.tags,
+		"tag",
+		nil,
+		"Packages the services with the tag. Prefix a tag with ! to skip the services with the tag. Can be repeated.",
+	)
 	local.StringVar(
 		&pf.outputPath,
 		"output-path",
 		"",
 		"File or folder path where the generated packages will be saved.",
 	)
+	local.BoolVar(
+		&pf.push,
+		"push",
+		false,
+		"Pushes the generated packages to the artifact store configured in "+azdcontext.ProjectFileName+".",
+	)
+	local.BoolVar(
+		&pf.sbom,
+		"sbom",
+		false,
+		"Generates a software bill of materials (SBOM) of each package with Syft.",
+	)
+	local.StringVar(
+		&pf.manifest,
+		"manifest",
+		"",
+		"File path where the package manifest is written. Defaults to "+project.PackageManifestFileName+
+			" in the --output-path folder.",
+	)
 }
 
 func newPackageCmd() *cobra.Command {
@@ -74,8 +113,13 @@ type packageAction struct {
 	importManager  *project.ImportManager
 	serviceManager project.ServiceManager
 	console        input.Console
+	reporter       progress.Reporter
 	formatter      output.Formatter
 	writer         io.Writer
+	artifacts      *artifacts.Manager
+	syftCli        *syft.Cli
+	gitCli         *git.Cli
+	dockerCli      *docker.Cli
 }
 
 func newPackageAction(
@@ -85,9 +129,14 @@ func newPackageAction(
 	projectManager project.ProjectManager,
 	serviceManager project.ServiceManager,
 	console input.Console,
+	reporter progress.Reporter,
 	formatter output.Formatter,
 	writer io.Writer,
 	importManager *project.ImportManager,
+	artifactManager *artifacts.Manager,
+	syftCli *syft.Cli,
+	gitCli *git.Cli,
+	dockerCli *docker.Cli,
 ) actions.Action {
 	return &packageAction{
 		flags:          flags,
@@ -96,9 +145,14 @@ func newPackageAction(
 		projectManager: projectManager,
 		serviceManager: serviceManager,
 		console:        console,
+		reporter:       reporter,
 		formatter:      formatter,
 		writer:         writer,
 		importManager:  importManager,
+		artifacts:      artifactManager,
+		syftCli:        syftCli,
+		gitCli:         gitCli,
+		dockerCli:      dockerCli,
 	}
 }
 
@@ -120,14 +174,34 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		targetServiceName = pa.args[0]
 	}
 
-	targetServiceName, err := getTargetServiceName(
+	tagFilter, err := project.ParseServiceTagFilter(pa.flags.tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if tagFilter != nil && targetServiceName != "" {
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        fmt.Errorf("cannot specify both --tag and <service>: %w", internal.ErrInvalidFlagCombination),
+			Suggestion: "Use either 'azd package <service>' or 'azd package --tag <tag>'.",
+		}
+	}
+
+	if pa.flags.push && !pa.artifacts.Configured() {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("--push requires an artifact store: %w", artifacts.ErrStoreNotConfigured),
+			Suggestion: fmt.Sprintf(
+				"Configure the artifact store in the 'artifacts' section of %s.", azdcontext.ProjectFileName),
+		}
+	}
+
+	targetServiceName, err = getTargetServiceName(
 		ctx,
 		pa.projectManager,
 		pa.importManager,
 		pa.projectConfig,
 		string(project.ServiceEventPackage),
 		targetServiceName,
-		pa.flags.all,
+		pa.flags.all || tagFilter != nil,
 	)
 	if err != nil {
 		return nil, err
@@ -138,16 +212,27 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 	}
 
 	if err := pa.projectManager.EnsureAllTools(ctx, pa.projectConfig, func(svc *project.ServiceConfig) bool {
-		return targetServiceName == "" || svc.Name == targetServiceName
+		return (targetServiceName == "" || svc.Name == targetServiceName) && tagFilter.Matches(svc)
 	}); err != nil {
 		return nil, err
 	}
 
+	if pa.flags.sbom {
+		if err := tools.EnsureInstalled(ctx, pa.syftCli); err != nil {
+			return nil, err
+		}
+	}
+
 	serviceTable, err := pa.importManager.ServiceStableFiltered(ctx, pa.projectConfig, targetServiceName, os.Getenv)
 	if err != nil {
 		return nil, err
 	}
 
+	serviceTable, err = tagFilter.Filter(serviceTable)
+	if err != nil {
+		return nil, err
+	}
+
 	serviceCount := len(serviceTable)
 
 	projectEventArgs := project.ProjectLifecycleEventArgs{
@@ -192,20 +277,23 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 				continue
 			}
 
-			stepMessage := fmt.Sprintf("Packaging service %s", svc.Name)
-			pa.console.ShowSpinner(ctx, stepMessage, input.Step)
+			step := progress.Start(
+				ctx, pa.reporter, progress.PhasePackage, svc.Name, fmt.Sprintf("Packaging service %s", svc.Name))
 
-			options := &project.PackageOptions{OutputPath: pa.flags.outputPath}
+			options := &project.PackageOptions{OutputPath: pa.flags.outputPath, Sbom: pa.flags.sbom}
 			packageResult, err := async.RunWithProgress(
 				func(packageProgress project.ServiceProgress) {
-					progressMessage := fmt.Sprintf("Packaging service %s (%s)", svc.Name, packageProgress.Message)
-					pa.console.ShowSpinner(ctx, progressMessage, input.Step)
+					step.Update(packageProgress.Message)
 				},
 				func(progress *async.Progress[project.ServiceProgress]) (*project.ServicePackageResult, error) {
 					return pa.serviceManager.Package(ctx, svc, nil, progress, options)
 				},
 			)
-			pa.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+			if err == nil && pa.flags.push {
+				step.Update("Pushing package")
+				err = pa.pushArtifacts(ctx, packageResult.Artifacts)
+			}
+			step.Done(err)
 
 			if err != nil {
 				return err
@@ -226,6 +314,17 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		return nil, err
 	}
 
+	var followUp string
+	if manifestPath := pa.manifestPath(); manifestPath != "" {
+		if err := pa.writeManifest(ctx, manifestPath, serviceTable, packageResults); err != nil {
+			return nil, err
+		}
+
+		followUp = fmt.Sprintf("The package manifest was written to %s. Deploy the packages with %s.",
+			output.WithHyperlink(manifestPath, manifestPath),
+			output.WithHighLightFormat("azd deploy --from-package %s", manifestPath))
+	}
+
 	if pa.formatter.Kind() == output.JsonFormat {
 		packageResult := PackageResult{
 			Timestamp: time.Now(),
@@ -239,11 +338,147 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 
 	return &actions.ActionResult{
 		Message: &actions.ResultMessage{
-			Header: fmt.Sprintf("Your application was packaged for Azure in %s.", ux.DurationAsText(since(startTime))),
+			Header:   fmt.Sprintf("Your application was packaged for Azure in %s.", ux.DurationAsText(since(startTime))),
+			FollowUp: followUp,
 		},
 	}, nil
 }
 
+// pushArtifacts pushes the local package files and their SBOMs to the artifact store, and records their references in
+// the artifact metadata. Container images are pushed to their registry on deploy, so only their image manifests and
+// SBOMs are pushed to the artifact store.
+func (pa *packageAction) pushArtifacts(ctx context.Context, packageArtifacts project.ArtifactCollection) error {
+	for _, artifact := range packageArtifacts {
+		if artifact.LocationKind != project.LocationKindLocal {
+			continue
+		}
+
+		if artifact.Metadata == nil {
+			artifact.Metadata = map[string]string{}
+		}
+
+		switch artifact.Kind {
+		case project.ArtifactKindArchive:
+			ref, err := pa.artifacts.Push(ctx, artifact.Location)
+			if err != nil {
+				return fmt.Errorf("pushing package %s: %w", artifact.Location, err)
+			}
+
+			artifact.Metadata[project.MetadataKeyReference] = ref.String()
+		case project.ArtifactKindContainer:
+			ref, err := pa.pushImageManifest(ctx, artifact.Location)
+			if err != nil {
+				return fmt.Errorf("pushing image manifest of %s: %w", artifact.Location, err)
+			}
+
+			artifact.Metadata[project.MetadataKeyImageManifestReference] = ref.String()
+		}
+
+		if sbom := artifact.Metadata[project.MetadataKeySbom]; sbom != "" {
+			ref, err := pa.artifacts.Push(ctx, sbom)
+			if err != nil {
+				return fmt.Errorf("pushing SBOM %s: %w", sbom, err)
+			}
+
+			artifact.Metadata[project.MetadataKeySbomReference] = ref.String()
+		}
+	}
+
+	return nil
+}
+
+// pushImageManifest pushes the manifest of a local container image to the artifact store: the output of
+// `docker image inspect`, with the image ID, layers, configuration and platform of the image.
+func (pa *packageAction) pushImageManifest(ctx context.Context, imageName string) (artifacts.Reference, error) {
+	manifest, err := pa.dockerCli.Inspect(ctx, imageName, "{{json .}}")
+	if err != nil {
+		return artifacts.Reference{}, err
+	}
+
+	dir, err := os.MkdirTemp("", "azd-image-manifest")
+	if err != nil {
+		return artifacts.Reference{}, err
+	}
+	defer os.RemoveAll(dir)
+
+	fileName := strings.NewReplacer("/", "-", ":", "-", "@", "-").Replace(imageName) + ".image.json"
+	manifestPath := filepath.Join(dir, fileName)
+	if err := os.WriteFile(manifestPath, []byte(strings.TrimSpace(manifest)), osutil.PermissionFile); err != nil {
+		return artifacts.Reference{}, err
+	}
+
+	return pa.artifacts.Push(ctx, manifestPath)
+}
+
+// manifestPath returns the path the package manifest is written to: the --manifest path, or the manifest file in the
+// --output-path folder. An empty path means no manifest is written.
+func (pa *packageAction) manifestPath() string {
+	if pa.flags.manifest != "" {
+		return pa.flags.manifest
+	}
+
+	if pa.flags.outputPath != "" && filepath.Ext(pa.flags.outputPath) == "" {
+		return filepath.Join(pa.flags.outputPath, project.PackageManifestFileName)
+	}
+
+	return ""
+}
+
+// writeManifest writes the package manifest describing the packages of the services, and the git commit they were
+// packaged from.
+func (pa *packageAction) writeManifest(
+	ctx context.Context,
+	manifestPath string,
+	serviceTable []*project.ServiceConfig,
+	packageResults map[string]*project.ServicePackageResult,
+) error {
+	manifest := &project.PackageManifest{
+		CreatedAt: time.Now().UTC(),
+		Project:   pa.projectConfig.Name,
+		Git:       pa.packageGitCommit(ctx),
+		Services:  map[string]*project.PackageManifestService{},
+	}
+
+	for _, svc := range serviceTable {
+		packageResult, has := packageResults[svc.Name]
+		if !has {
+			continue
+		}
+
+		service, err := project.NewPackageManifestService(svc, packageResult.Artifacts, filepath.Dir(manifestPath))
+		if err != nil {
+			return fmt.Errorf("describing packages of service '%s': %w", svc.Name, err)
+		}
+		manifest.Services[svc.Name] = service
+	}
+
+	return manifest.Save(manifestPath)
+}
+
+// packageGitCommit returns the git commit the project is packaged from, or nil when the project isn't in a git
+// repository.
+func (pa *packageAction) packageGitCommit(ctx context.Context) *project.PackageManifestGit {
+	if pa.gitCli == nil {
+		return nil
+	}
+
+	commit, err := pa.gitCli.GetHeadCommit(ctx, pa.projectConfig.Path)
+	if err != nil {
+		log.Printf("not recording the git commit in the package manifest: %v", err)
+		return nil
+	}
+
+	manifestGit := &project.PackageManifestGit{Commit: commit}
+	if branch, err := pa.gitCli.GetCurrentBranch(ctx, pa.projectConfig.Path); err == nil {
+		manifestGit.Branch = branch
+	}
+	if dirty, err := pa.gitCli.IsDirty(ctx, pa.projectConfig.Path); err == nil {
+		manifestGit.Dirty = dirty
+	}
+
+	return manifestGit
+}
+
 func getCmdPackageHelpDescription(*cobra.Command) string {
 	return generateCmdHelpDescription(fmt.Sprintf(
 		"Packages application's code to be deployed to Azure. %s",
@@ -254,7 +489,25 @@ func getCmdPackageHelpDescription(*cobra.Command) string {
 				" or the service described in the project that matches the current directory."),
 		formatHelpNote(
 			fmt.Sprintf("When %s is set, only the specific service is packaged.", output.WithHighLightFormat("<service>"))),
+		formatHelpNote(
+			fmt.Sprintf("When %s is set, only the services with matching tags are packaged.",
+				output.WithHighLightFormat("--tag"))),
 		formatHelpNote("After the packaging is complete, the package locations are printed."),
+		formatHelpNote(
+			fmt.Sprintf("When %s is set, an SBOM of each package is generated with Syft, next to the package or in %s.",
+				output.WithHighLightFormat("--sbom"),
+				output.WithHighLightFormat("--output-path"))),
+		formatHelpNote(
+			fmt.Sprintf("When %s or %s is set, a package manifest describing the packages, their digests and the git"+
+				" commit is written. Deploy the packages of a manifest with %s.",
+				output.WithHighLightFormat("--output-path <folder>"),
+				output.WithHighLightFormat("--manifest"),
+				output.WithHighLightFormat("azd deploy --from-package <manifest>"))),
+		formatHelpNote(
+			fmt.Sprintf("When %s is set, the packages are pushed to the artifact store configured in 'azure.yaml',"+
+				" and their references are printed. Deploy a pushed package with %s.",
+				output.WithHighLightFormat("--push"),
+				output.WithHighLightFormat("azd deploy <service> --from-package <reference>"))),
 	})
 }
 
@@ -269,5 +522,14 @@ func getCmdPackageHelpFooter(*cobra.Command) string {
 		"Packages the service named 'api' to the specified output path.": output.WithHighLightFormat(
 			"azd package api --output-path ./dist/api.zip",
 		),
+		"Packages the services that aren't tagged 'critical'.": output.WithHighLightFormat(
+			"azd package --tag '!critical'",
+		),
+		"Packages all services and pushes the packages to the artifact store.": output.WithHighLightFormat(
+			"azd package --all --push",
+		),
+		"Packages all services with an SBOM of each package.": output.WithHighLightFormat(
+			"azd package --all --sbom --output-path ./dist",
+		),
 	})
 }
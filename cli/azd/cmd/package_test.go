@@ -5,6 +5,9 @@ package cmd
 
 import (
 	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
@@ -12,7 +15,14 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/artifacts"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/progress"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockexec"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
 )
 
@@ -22,7 +32,7 @@ func Test_NewPackageAction_Constructor(t *testing.T) {
 	console := mockinput.NewMockConsole()
 	formatter := &output.JsonFormatter{}
 	a := newPackageAction(
-		flags, nil, nil, nil, nil, console, formatter, io.Discard, nil,
+		flags, nil, nil, nil, nil, console, progress.Discard, formatter, io.Discard, nil, nil, nil, nil, nil,
 	)
 	pa := a.(*packageAction)
 	require.Same(t, flags, pa.flags)
@@ -42,3 +52,127 @@ func Test_NewPackageFlags(t *testing.T) {
 	flags := newPackageFlags(cmd, global)
 	require.NotNil(t, flags)
 }
+
+func Test_PackageAction_PushArtifacts(t *testing.T) {
+	t.Parallel()
+	storeConfig := &artifacts.Config{
+		Store: &artifacts.StoreConfig{
+			Kind:   artifacts.StoreKindLocal,
+			Config: map[string]any{"path": t.TempDir()},
+		},
+	}
+	container := ioc.NewNestedContainer(nil)
+	container.MustRegisterNamedSingleton(string(artifacts.StoreKindLocal), func() (artifacts.Store, error) {
+		return artifacts.NewLocalStore(storeConfig, nil)
+	})
+
+	packagePath := filepath.Join(t.TempDir(), "api.zip")
+	require.NoError(t, os.WriteFile(packagePath, []byte("package content"), 0600))
+
+	packageArtifacts := project.ArtifactCollection{
+		{Kind: project.ArtifactKindArchive, Location: packagePath, LocationKind: project.LocationKindLocal},
+		{Kind: project.ArtifactKindContainer, Location: "api:latest", LocationKind: project.LocationKindLocal},
+	}
+
+	commandRunner := mockexec.NewMockCommandRunner()
+	commandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "image inspect") && strings.HasSuffix(command, "api:latest")
+	}).Respond(exec.RunResult{Stdout: `{"Id":"sha256:abc","RepoTags":["api:latest"]}` + "\n"})
+
+	pa := &packageAction{artifacts: artifacts.NewManager(storeConfig, container), dockerCli: docker.NewCli(commandRunner)}
+	require.NoError(t, pa.pushArtifacts(t.Context(), packageArtifacts))
+
+	ref, err := artifacts.ParseReference(packageArtifacts[0].Metadata[project.MetadataKeyReference])
+	require.NoError(t, err)
+	require.Equal(t, "api.zip", ref.Name)
+
+	// The container image isn't pushed, only its image manifest.
+	require.Empty(t, packageArtifacts[1].Metadata[project.MetadataKeyReference])
+	ref, err = artifacts.ParseReference(packageArtifacts[1].Metadata[project.MetadataKeyImageManifestReference])
+	require.NoError(t, err)
+	require.Equal(t, "api-latest.image.json", ref.Name)
+
+	manifestPath, err := pa.artifacts.Pull(t.Context(), ref, t.TempDir())
+	require.NoError(t, err)
+	manifest, err := os.ReadFile(manifestPath)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"Id":"sha256:abc","RepoTags":["api:latest"]}`, string(manifest))
+}
+
+func Test_PackageAction_PushArtifacts_Sbom(t *testing.T) {
+	t.Parallel()
+	storeConfig := &artifacts.Config{
+		Store: &artifacts.StoreConfig{
+			Kind:   artifacts.StoreKindLocal,
+			Config: map[string]any{"path": t.TempDir()},
+		},
+	}
+	container := ioc.NewNestedContainer(nil)
+	container.MustRegisterNamedSingleton(string(artifacts.StoreKindLocal), func() (artifacts.Store, error) {
+		return artifacts.NewLocalStore(storeConfig, nil)
+	})
+
+	sbomPath := filepath.Join(t.TempDir(), "api.spdx.json")
+	require.NoError(t, os.WriteFile(sbomPath, []byte(`{"spdxVersion": "SPDX-2.3"}`), 0600))
+
+	packageArtifacts := project.ArtifactCollection{
+		{
+			Kind:         project.ArtifactKindContainer,
+			Location:     "api:latest",
+			LocationKind: project.LocationKindLocal,
+			Metadata:     map[string]string{project.MetadataKeySbom: sbomPath},
+		},
+	}
+
+	commandRunner := mockexec.NewMockCommandRunner()
+	commandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "image inspect")
+	}).Respond(exec.RunResult{Stdout: `{"Id":"sha256:abc"}`})
+
+	pa := &packageAction{artifacts: artifacts.NewManager(storeConfig, container), dockerCli: docker.NewCli(commandRunner)}
+	require.NoError(t, pa.pushArtifacts(t.Context(), packageArtifacts))
+
+	ref, err := artifacts.ParseReference(packageArtifacts[0].Metadata[project.MetadataKeySbomReference])
+	require.NoError(t, err)
+	require.Equal(t, "api.spdx.json", ref.Name)
+	require.Empty(t, packageArtifacts[0].Metadata[project.MetadataKeyReference])
+}
+
+func Test_PackageAction_WriteManifest(t *testing.T) {
+	t.Parallel()
+	outputPath := t.TempDir()
+	packagePath := filepath.Join(outputPath, "api.zip")
+	require.NoError(t, os.WriteFile(packagePath, []byte("package content"), 0600))
+
+	pa := &packageAction{
+		flags:         &packageFlags{outputPath: outputPath},
+		projectConfig: &project.ProjectConfig{Name: "todo"},
+	}
+	manifestPath := pa.manifestPath()
+	require.Equal(t, filepath.Join(outputPath, project.PackageManifestFileName), manifestPath)
+
+	serviceConfig := &project.ServiceConfig{Name: "api", Host: project.AppServiceTarget}
+	err := pa.writeManifest(t.Context(), manifestPath, []*project.ServiceConfig{serviceConfig},
+		map[string]*project.ServicePackageResult{
+			"api": {Artifacts: project.ArtifactCollection{{
+				Kind:         project.ArtifactKindArchive,
+				Location:     packagePath,
+				LocationKind: project.LocationKindLocal,
+			}}},
+		})
+	require.NoError(t, err)
+
+	manifest, err := project.LoadPackageManifest(manifestPath)
+	require.NoError(t, err)
+	require.Equal(t, "todo", manifest.Project)
+	require.Nil(t, manifest.Git)
+	apiPackage, err := manifest.Services["api"].Package()
+	require.NoError(t, err)
+	require.Equal(t, "api.zip", apiPackage.Path)
+
+	// A single package output path has no manifest, unless --manifest is set.
+	pa.flags = &packageFlags{outputPath: packagePath}
+	require.Empty(t, pa.manifestPath())
+	pa.flags.manifest = "azd-package.json"
+	require.Equal(t, "azd-package.json", pa.manifestPath())
+}
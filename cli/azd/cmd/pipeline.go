@@ -55,7 +55,7 @@ func (pc *pipelineConfigFlags) Bind(local *pflag.FlagSet, global *internal.Globa
 		&pc.PipelineAuthTypeName,
 		"auth-type",
 		"",
-		"The authentication type used between the pipeline provider and Azure for deployment (Only valid for GitHub provider). Valid values: federated, client-credentials.",
+		"The authentication type used between the pipeline provider and Azure for deployment (Only valid for GitHub and GitLab providers). Valid values: federated, client-credentials.",
 	)
 	//nolint:lll
 	local.StringArrayVar(
@@ -67,13 +67,20 @@ func (pc *pipelineConfigFlags) Bind(local *pflag.FlagSet, global *internal.Globa
 	// default provider is empty because it can be set from azure.yaml. By letting default here be empty, we know that
 	// there no customer input using --provider
 	local.StringVar(&pc.PipelineProvider, "provider", "",
-		"The pipeline provider to use (github for Github Actions and azdo for Azure Pipelines).")
+		"The pipeline provider to use (github for Github Actions, azdo for Azure Pipelines and gitlab for GitLab CI/CD).")
 	local.StringVarP(&pc.ServiceManagementReference, "applicationServiceManagementReference", "m", "",
 		"Service Management Reference. "+
 			"References application or service contact information from a Service or Asset Management database. "+
 			"This value must be a Universally Unique Identifier (UUID). "+
 			"You can set this value globally by running "+
 			"azd config set pipeline.config.applicationServiceManagementReference <UUID>.")
+	//nolint:lll
+	local.StringSliceVar(
+		&pc.PipelineStages,
+		"stages",
+		nil,
+		"The stages of a multi-stage pipeline, in promotion order (e.g. dev,staging,prod). Each stage deploys the azd environment with the same name (Only valid for GitHub provider).",
+	)
 	pc.EnvFlag.Bind(local, global)
 	pc.global = global
 }
@@ -104,6 +111,40 @@ func pipelineActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 		},
 	})
 
+	group.Add("cleanup", &actions.ActionDescriptorOptions{
+		Command:        newPipelineCleanupCmd(),
+		FlagsResolver:  newPipelineCleanupFlags,
+		ActionResolver: newPipelineCleanupAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdPipelineCleanupHelpDescription,
+			Footer:      getCmdPipelineCleanupHelpFooter,
+		},
+	})
+
+	group.Add("run", &actions.ActionDescriptorOptions{
+		Command:        newPipelineRunCmd(),
+		FlagsResolver:  newPipelineRunFlags,
+		ActionResolver: newPipelineRunAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdPipelineRunHelpDescription,
+			Footer:      getCmdPipelineRunHelpFooter,
+		},
+	})
+
+	group.Add("status", &actions.ActionDescriptorOptions{
+		Command:        newPipelineStatusCmd(),
+		FlagsResolver:  newPipelineStatusFlags,
+		ActionResolver: newPipelineStatusAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdPipelineStatusHelpDescription,
+			Footer:      getCmdPipelineStatusHelpFooter,
+		},
+	})
+
 	return group
 }
 
@@ -276,8 +317,8 @@ func getCmdPipelineHelpDescription(*cobra.Command) string {
 				"azd commands (e.g. " +
 					output.WithHighLightFormat("provision") + ", " +
 					output.WithHighLightFormat("deploy") + ") " +
-					"can be used within GitHub Actions and Azure Pipelines to test your code against real Azure resources " +
-					"and facilitate deployments."),
+					"can be used within GitHub Actions, Azure Pipelines and GitLab CI/CD to test your code against real " +
+					"Azure resources and facilitate deployments."),
 			formatHelpNote(
 				"After creating a pipeline definition file, running " +
 					output.WithHighLightFormat("pipeline config") +
@@ -291,6 +332,11 @@ func getCmdPipelineHelpFooter(c *cobra.Command) string {
 	return generateCmdHelpSamplesBlock(map[string]string{
 		"Walk through the steps required " +
 			"to set up your deployment pipeline.": output.WithHighLightFormat("azd pipeline config"),
+		"Remove the pipeline identities of deleted repositories and environments.": output.WithHighLightFormat(
+			"azd pipeline cleanup"),
+		"Run the deployment pipeline on the current branch.": output.WithHighLightFormat("azd pipeline run"),
+		"Show the status of the latest run of the deployment pipeline.": output.WithHighLightFormat(
+			"azd pipeline status"),
 	})
 }
 
@@ -299,8 +345,8 @@ func getCmdPipelineConfigHelpDescription(*cobra.Command) string {
 		"Configure your deployment pipeline to connect securely to Azure",
 		[]string{
 			formatHelpNote(
-				"Supports GitHub Actions and Azure Pipelines. To configure using a specific pipeline provider, " +
-					"provide a value for the '--provider' flag."),
+				"Supports GitHub Actions, Azure Pipelines and GitLab CI/CD. To configure using a specific pipeline " +
+					"provider, provide a value for the '--provider' flag."),
 			formatHelpNote(
 				output.WithHighLightFormat("pipeline config") +
 					" creates or uses a service principal on the Azure subscription to create a secure connection between" +
@@ -309,6 +355,10 @@ func getCmdPipelineConfigHelpDescription(*cobra.Command) string {
 				output.WithHighLightFormat("pipeline config") +
 				" will set deployment pipeline variables and secrets using the current environment. " +
 				"To configure for a new or an existing environment, provide a value for the '-e' flag."),
+			formatHelpNote(
+				"To promote changes through several environments, provide the stages of the pipeline with the " +
+					"'--stages' flag or in the pipeline section of azure.yaml. Each stage deploys its own environment, " +
+					"after the previous stage succeeds and, optionally, after a reviewer approves it."),
 		})
 }
 
@@ -327,5 +377,14 @@ func getCmdPipelineConfigHelpFooter(c *cobra.Command) string {
 			output.WithWarningFormat("app-test"),
 			output.WithHighLightFormat("--provider azdo"),
 		),
+		"Configure a deployment pipeline for 'app-test' environment on GitLab CI/CD.": fmt.Sprintf("%s %s %s",
+			output.WithHighLightFormat("azd pipeline config -e"),
+			output.WithWarningFormat("app-test"),
+			output.WithHighLightFormat("--provider gitlab"),
+		),
+		"Configure a deployment pipeline promoting changes through 'dev', 'staging' and 'prod'.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd pipeline config --stages"),
+			output.WithWarningFormat("dev,staging,prod"),
+		),
 	})
 }
@@ -0,0 +1,181 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/pipeline"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/github"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type pipelineCleanupFlags struct {
+	internal.EnvFlag
+	global *internal.GlobalCommandOptions
+	force  bool
+	dryRun bool
+}
+
+func (f *pipelineCleanupFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+	local.BoolVar(&f.force, "force", false, "Skips confirmation before removing the identities.")
+	local.BoolVar(&f.dryRun, "dry-run", false, "Lists the identities that would be removed without removing them.")
+}
+
+func newPipelineCleanupFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *pipelineCleanupFlags {
+	flags := &pipelineCleanupFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newPipelineCleanupCmd() *cobra.Command {
+	return &cobra.Command{
+		Use: "cleanup",
+		Short: fmt.Sprintf(
+			"Remove the pipeline identities of deleted repositories and environments. %s",
+			output.WithWarningFormat("(Beta)")),
+		Args: cobra.NoArgs,
+	}
+}
+
+type pipelineCleanupAction struct {
+	flags          *pipelineCleanupFlags
+	env            *environment.Environment
+	console        input.Console
+	ghCli          *github.Cli
+	cleanupManager *pipeline.CleanupManager
+}
+
+func newPipelineCleanupAction(
+	flags *pipelineCleanupFlags,
+	env *environment.Environment,
+	console input.Console,
+	ghCli *github.Cli,
+	cleanupManager *pipeline.CleanupManager,
+) actions.Action {
+	return &pipelineCleanupAction{
+		flags:          flags,
+		env:            env,
+		console:        console,
+		ghCli:          ghCli,
+		cleanupManager: cleanupManager,
+	}
+}
+
+func (p *pipelineCleanupAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	p.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title: "Clean up pipeline identities (azd pipeline cleanup)",
+		TitleNote: "Removes the federated credentials and app registrations created by 'azd pipeline config'" +
+			" for GitHub repositories and environments that no longer exist.",
+	})
+
+	subscriptionId := p.env.GetSubscriptionId()
+	if subscriptionId == "" {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf(
+				"environment '%s' has no subscription: %w", p.env.Name(), internal.ErrKeyNotFound),
+			Suggestion: "Run 'azd env set AZURE_SUBSCRIPTION_ID <subscription-id>' to select the tenant to clean up.",
+		}
+	}
+
+	if err := p.ghCli.EnsureInstalled(ctx); err != nil {
+		return nil, err
+	}
+
+	p.console.ShowSpinner(ctx, "Finding stale pipeline identities", input.Step)
+	plan, err := p.cleanupManager.Plan(ctx, subscriptionId)
+	p.console.StopSpinner(ctx, "", input.Step)
+	if err != nil {
+		return nil, err
+	}
+
+	if plan.Empty() {
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: "No stale pipeline identities were found.",
+			},
+		}, nil
+	}
+
+	for _, application := range plan.Applications {
+		p.console.Message(ctx, fmt.Sprintf(
+			"  App registration %s %s",
+			output.WithHighLightFormat(application.DisplayName),
+			output.WithGrayFormat("(all its federated credentials are stale)")))
+	}
+
+	for _, stale := range plan.Credentials {
+		p.console.Message(ctx, fmt.Sprintf(
+			"  Federated credential %s of %s %s",
+			output.WithHighLightFormat(stale.Credential.Name),
+			stale.Application.DisplayName,
+			output.WithGrayFormat("(%s)", stale.Reason)))
+	}
+	p.console.Message(ctx, "")
+
+	if p.flags.dryRun {
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: "Dry run complete, no identities were removed.",
+			},
+		}, nil
+	}
+
+	if !p.flags.force {
+		confirm, err := p.console.Confirm(ctx, input.ConsoleOptions{
+			Message: "Remove these identities?",
+		})
+		if !confirm || err != nil {
+			return nil, err
+		}
+	}
+
+	p.console.ShowSpinner(ctx, "Removing stale pipeline identities", input.Step)
+	err = p.cleanupManager.Apply(ctx, subscriptionId, plan)
+	p.console.StopSpinner(ctx, "Removing stale pipeline identities", input.GetStepResultFormat(err))
+	if err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf(
+				"Removed %d app registration(s) and %d federated credential(s).",
+				len(plan.Applications), len(plan.Credentials)),
+		},
+	}, nil
+}
+
+func getCmdPipelineCleanupHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Remove the identities created by 'azd pipeline config' for deleted repositories and environments.",
+		[]string{
+			formatHelpNote(
+				"Federated credentials of app registrations named 'az-dev-*' are removed when the GitHub repository or" +
+					" environment they trust no longer exists. App registrations whose federated credentials are all" +
+					" stale are deleted, together with their service principal."),
+			formatHelpNote(
+				"App registrations that use client secrets, and credentials for Azure Pipelines, are never removed."),
+		})
+}
+
+func getCmdPipelineCleanupHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"List the stale pipeline identities without removing them.": output.WithHighLightFormat(
+			"azd pipeline cleanup --dry-run"),
+		"Remove the stale pipeline identities without confirmation.": output.WithHighLightFormat(
+			"azd pipeline cleanup --force"),
+	})
+}
@@ -0,0 +1,246 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/pipeline"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type pipelineRunFlags struct {
+	internal.EnvFlag
+	global *internal.GlobalCommandOptions
+	branch string
+}
+
+func (f *pipelineRunFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+	local.StringVar(&f.branch, "branch", "", "The branch to run the pipeline on. Defaults to the current branch.")
+}
+
+func newPipelineRunFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *pipelineRunFlags {
+	flags := &pipelineRunFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newPipelineRunCmd() *cobra.Command {
+	return &cobra.Command{
+		Use: "run",
+		Short: fmt.Sprintf(
+			"Run your deployment pipeline on the current branch. %s",
+			output.WithWarningFormat("(Beta)")),
+		Args: cobra.NoArgs,
+	}
+}
+
+// pipelineRunAction triggers the pipeline configured by `azd pipeline config`. The pipeline manager resolves the
+// provider from azure.yaml or from the last `azd pipeline config` of the environment.
+type pipelineRunAction struct {
+	flags     *pipelineRunFlags
+	console   input.Console
+	manager   *pipeline.PipelineManager
+	formatter output.Formatter
+	writer    io.Writer
+}
+
+func newPipelineRunAction(
+	flags *pipelineRunFlags,
+	console input.Console,
+	manager *pipeline.PipelineManager,
+	formatter output.Formatter,
+	writer io.Writer,
+) actions.Action {
+	return &pipelineRunAction{
+		flags:     flags,
+		console:   console,
+		manager:   manager,
+		formatter: formatter,
+		writer:    writer,
+	}
+}
+
+func (p *pipelineRunAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if p.formatter.Kind() == output.JsonFormat {
+		run, err := p.manager.RunPipeline(ctx, p.flags.branch)
+		if err != nil {
+			return nil, err
+		}
+		return nil, p.formatter.Format(run, p.writer, nil)
+	}
+
+	p.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title: fmt.Sprintf("Run your %s pipeline (azd pipeline run)", p.manager.CiProviderName()),
+	})
+
+	stepMessage := "Triggering the pipeline"
+	p.console.ShowSpinner(ctx, stepMessage, input.Step)
+	run, err := p.manager.RunPipeline(ctx, p.flags.branch)
+	p.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+	if err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Your pipeline was triggered on branch %s.", output.WithHighLightFormat(run.Branch)),
+			FollowUp: fmt.Sprintf(
+				"Link to view the run: %s\nRun %s to check its status.",
+				output.WithLinkFormat("%s", run.Url),
+				output.WithHighLightFormat("azd pipeline status")),
+		},
+	}, nil
+}
+
+type pipelineStatusFlags struct {
+	internal.EnvFlag
+	global *internal.GlobalCommandOptions
+	branch string
+}
+
+func (f *pipelineStatusFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+	local.StringVar(&f.branch, "branch", "", "The branch of the pipeline run. Defaults to the current branch.")
+}
+
+func newPipelineStatusFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *pipelineStatusFlags {
+	flags := &pipelineStatusFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newPipelineStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use: "status",
+		Short: fmt.Sprintf(
+			"Show the status of the latest run of your deployment pipeline. %s",
+			output.WithWarningFormat("(Beta)")),
+		Args: cobra.NoArgs,
+	}
+}
+
+type pipelineStatusAction struct {
+	flags     *pipelineStatusFlags
+	console   input.Console
+	manager   *pipeline.PipelineManager
+	formatter output.Formatter
+	writer    io.Writer
+}
+
+func newPipelineStatusAction(
+	flags *pipelineStatusFlags,
+	console input.Console,
+	manager *pipeline.PipelineManager,
+	formatter output.Formatter,
+	writer io.Writer,
+) actions.Action {
+	return &pipelineStatusAction{
+		flags:     flags,
+		console:   console,
+		manager:   manager,
+		formatter: formatter,
+		writer:    writer,
+	}
+}
+
+func (p *pipelineStatusAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	run, err := p.manager.LatestPipelineRun(ctx, p.flags.branch)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.formatter.Kind() == output.JsonFormat {
+		// no runs are reported as null
+		return nil, p.formatter.Format(run, p.writer, nil)
+	}
+
+	if run == nil {
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: "Your pipeline has no runs on this branch.",
+				FollowUp: fmt.Sprintf(
+					"Run %s to start one.", output.WithHighLightFormat("azd pipeline run")),
+			},
+		}, nil
+	}
+
+	name := run.Id
+	if run.Name != "" {
+		name = fmt.Sprintf("%s (%s)", run.Name, run.Id)
+	}
+	status := string(run.Status)
+	switch {
+	case run.Status == pipeline.PipelineRunInProgress:
+		status = "in progress"
+	case run.Result == "succeeded":
+		status = output.WithSuccessFormat("%s (%s)", run.Status, run.Result)
+	case run.Result != "":
+		status = output.WithErrorFormat("%s (%s)", run.Status, run.Result)
+	}
+
+	p.console.Message(ctx, fmt.Sprintf("  Run:    %s", output.WithHighLightFormat(name)))
+	p.console.Message(ctx, fmt.Sprintf("  Branch: %s", run.Branch))
+	p.console.Message(ctx, fmt.Sprintf("  Status: %s", status))
+	if run.QueuedAt != nil {
+		p.console.Message(ctx, fmt.Sprintf("  Queued: %s", run.QueuedAt.Local().Format(time.DateTime)))
+	}
+	p.console.Message(ctx, fmt.Sprintf("  Logs:   %s", output.WithLinkFormat("%s", run.Url)))
+	p.console.Message(ctx, "")
+
+	return nil, nil
+}
+
+func getCmdPipelineRunHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Run the deployment pipeline configured by 'azd pipeline config', without pushing a commit.",
+		[]string{
+			formatHelpNote(
+				"Supports GitHub Actions and Azure Pipelines. GitHub workflows must have the workflow_dispatch" +
+					" trigger, like the workflows generated by azd."),
+			formatHelpNote(
+				"The pipeline runs the code of the branch in the remote repository. Push your changes before running it."),
+		})
+}
+
+func getCmdPipelineRunHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Run the deployment pipeline on the current branch.": output.WithHighLightFormat("azd pipeline run"),
+		"Run the deployment pipeline on the 'release' branch.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd pipeline run --branch"),
+			output.WithWarningFormat("release"),
+		),
+	})
+}
+
+func getCmdPipelineStatusHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Show the status of the latest run of the deployment pipeline, with a link to its logs.",
+		[]string{
+			formatHelpNote("Supports GitHub Actions and Azure Pipelines."),
+		})
+}
+
+func getCmdPipelineStatusHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Show the latest run of the deployment pipeline on the current branch.": output.WithHighLightFormat(
+			"azd pipeline status"),
+		"Show the latest run of the deployment pipeline as JSON.": output.WithHighLightFormat(
+			"azd pipeline status --output json"),
+	})
+}
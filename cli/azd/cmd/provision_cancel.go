@@ -0,0 +1,199 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type provisionCancelFlags struct {
+	global *internal.GlobalCommandOptions
+	internal.EnvFlag
+}
+
+func (f *provisionCancelFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+}
+
+func newProvisionCancelFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *provisionCancelFlags {
+	flags := &provisionCancelFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newProvisionCancelCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cancel [<layer>]",
+		Short: "Cancel the in-progress Azure deployment for the current environment.",
+	}
+	cmd.Args = cobra.MaximumNArgs(1)
+	return cmd
+}
+
+type provisionCancelAction struct {
+	flags            *provisionCancelFlags
+	args             []string
+	provisionManager *provisioning.Manager
+	importManager    *project.ImportManager
+	projectConfig    *project.ProjectConfig
+	console          input.Console
+	formatter        output.Formatter
+	writer           io.Writer
+}
+
+func newProvisionCancelAction(
+	args []string,
+	flags *provisionCancelFlags,
+	provisionManager *provisioning.Manager,
+	importManager *project.ImportManager,
+	projectConfig *project.ProjectConfig,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+) actions.Action {
+	return &provisionCancelAction{
+		flags:            flags,
+		args:             args,
+		provisionManager: provisionManager,
+		importManager:    importManager,
+		projectConfig:    projectConfig,
+		console:          console,
+		formatter:        formatter,
+		writer:           writer,
+	}
+}
+
+func (a *provisionCancelAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	// Progress messages are only written for the default output format, so --output json only writes the results.
+	showProgress := a.formatter.Kind() == output.NoneFormat
+	if showProgress {
+		a.console.MessageUxItem(ctx, &ux.MessageTitle{
+			Title: "Canceling the in-progress Azure deployment (azd provision cancel)",
+		})
+	}
+
+	infra, err := a.importManager.ProjectInfrastructure(ctx, a.projectConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = infra.Cleanup() }()
+
+	cancelLayer := ""
+	if len(a.args) > 0 {
+		cancelLayer = a.args[0]
+	}
+
+	layers := infra.Options.GetLayers()
+	if cancelLayer != "" {
+		layerOpt, err := infra.Options.GetLayer(cancelLayer)
+		if err != nil {
+			return nil, err
+		}
+		layers = []provisioning.Options{layerOpt}
+	}
+
+	a.provisionManager.RecordInfraProviderUsage(ctx, layers)
+
+	results := []*provisioning.CancelResult{}
+	for _, layer := range layers {
+		if showProgress && (cancelLayer != "" || len(layers) > 1) {
+			a.console.EnsureBlankLine(ctx)
+			a.console.Message(ctx, fmt.Sprintf("Layer: %s", output.WithHighLightFormat(layer.Name)))
+			a.console.Message(ctx, "")
+		}
+
+		layer.Mode = provisioning.ModeCancel
+		if err := a.provisionManager.Initialize(ctx, a.projectConfig.Path, layer); err != nil {
+			return nil, fmt.Errorf("initializing provisioning manager: %w", err)
+		}
+
+		result, err := a.provisionManager.Cancel(ctx)
+		if errors.Is(err, provisioning.ErrNoActiveDeployment) {
+			if showProgress {
+				a.console.MessageUxItem(ctx, &ux.DoneMessage{Message: "No in-progress Azure deployment was found."})
+			}
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("canceling deployment: %w", err)
+		}
+
+		results = append(results, result)
+		if showProgress {
+			a.printCancelResult(ctx, result)
+		}
+	}
+
+	if a.formatter.Kind() == output.JsonFormat {
+		if err := a.formatter.Format(results, a.writer, nil); err != nil {
+			return nil, fmt.Errorf("formatting cancel results: %w", err)
+		}
+		return nil, nil
+	}
+
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: "Cancellation of the Azure deployment was requested.",
+		},
+	}, nil
+}
+
+// printCancelResult writes the final deployment state and the state of each resource it touched, so resources that
+// were left partially created are easy to spot.
+func (a *provisionCancelAction) printCancelResult(ctx context.Context, result *provisioning.CancelResult) {
+	a.console.Message(ctx, fmt.Sprintf("Deployment %s: %s",
+		output.WithHighLightFormat(result.DeploymentName), result.ProvisioningState))
+	if result.PortalUrl != "" {
+		a.console.Message(ctx, fmt.Sprintf("Details: %s", output.WithLinkFormat(result.PortalUrl)))
+	}
+
+	if len(result.Resources) == 0 {
+		return
+	}
+
+	a.console.Message(ctx, "\nResources:")
+	for _, resource := range result.Resources {
+		a.console.Message(ctx, fmt.Sprintf("  - %s (%s): %s",
+			resource.Name, output.WithGrayFormat(resource.Type), resource.ProvisioningState))
+	}
+}
+
+func getCmdProvisionCancelHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Cancel the in-progress Azure deployment started by a previous or concurrent run of "+
+			output.WithHighLightFormat("azd provision")+" for the current environment.",
+		[]string{
+			"When <layer> is specified, only cancels the deployment for the given layer." +
+				" When omitted, cancels in-progress deployments for all layers defined in the project.",
+			"Resources that were already created are not deleted. The final state of each resource" +
+				" is reported so partially created resources can be cleaned up or re-provisioned.",
+		})
+}
+
+func getCmdProvisionCancelHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Cancel the in-progress deployment for the current environment.": output.WithHighLightFormat(
+			"azd provision cancel"),
+		"Cancel the in-progress deployment for a specific layer.": output.WithHighLightFormat(
+			"azd provision cancel <layer>"),
+	})
+}
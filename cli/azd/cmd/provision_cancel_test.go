@@ -0,0 +1,61 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvisionCancelTitle(t *testing.T) {
+	tests := []struct {
+		name      string
+		formatter output.Formatter
+		wantTitle bool
+	}{
+		{name: "DefaultFormat", formatter: &output.NoneFormatter{}, wantTitle: true},
+		{name: "JsonFormat", formatter: &output.JsonFormatter{}, wantTitle: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(t.Context())
+			projectConfig := &project.ProjectConfig{
+				Path: t.TempDir(),
+				Infra: provisioning.Options{
+					Layers: []provisioning.Options{{Name: "core"}},
+				},
+			}
+
+			// An unknown layer fails before any deployment is looked up.
+			action := newProvisionCancelAction(
+				[]string{"missing"},
+				&provisionCancelFlags{},
+				nil,
+				project.NewImportManager(nil),
+				projectConfig,
+				mockContext.Console,
+				tt.formatter,
+				&bytes.Buffer{},
+			)
+
+			_, err := action.Run(*mockContext.Context)
+			require.Error(t, err)
+
+			out := strings.Join(mockContext.Console.Output(), "\n")
+			if tt.wantTitle {
+				require.Contains(t, out, "azd provision cancel")
+			} else {
+				require.Empty(t, mockContext.Console.Output())
+			}
+		})
+	}
+}
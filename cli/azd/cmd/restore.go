@@ -5,8 +5,13 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
@@ -15,18 +20,27 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/helm"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/lockfile"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/progress"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/swa"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
 type restoreFlags struct {
-	all         bool
-	global      *internal.GlobalCommandOptions
-	serviceName string
+	all                      bool
+	updateLock               bool
+	allowToolVersionMismatch bool
+	global                   *internal.GlobalCommandOptions
+	serviceName              string
 	internal.EnvFlag
 }
 
@@ -44,6 +58,20 @@ func (r *restoreFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommand
 		//nolint:lll
 		"Restores a specific service (when the string is unspecified, all services that are listed in the "+azdcontext.ProjectFileName+" file are restored).",
 	)
+	local.BoolVar(
+		&r.updateLock,
+		"update-lock",
+		false,
+		"Writes "+lockfile.FileName+" with the extensions, tools and template used by the project,"+
+			" instead of restoring the ones it pins.",
+	)
+	local.BoolVar(
+		&r.allowToolVersionMismatch,
+		"allow-tool-version-mismatch",
+		false,
+		"Continues when a tool pinned by "+lockfile.FileName+" can't be installed at its locked version,"+
+			" instead of failing.",
+	)
 	//deprecate:flag hide --service
 	_ = local.MarkHidden("service")
 }
@@ -70,6 +98,7 @@ type restoreAction struct {
 	flags          *restoreFlags
 	args           []string
 	console        input.Console
+	reporter       progress.Reporter
 	formatter      output.Formatter
 	writer         io.Writer
 	azdCtx         *azdcontext.AzdContext
@@ -79,12 +108,14 @@ type restoreAction struct {
 	importManager  *project.ImportManager
 	serviceManager project.ServiceManager
 	commandRunner  exec.CommandRunner
+	lockManager    *lockfile.Manager
 }
 
 func newRestoreAction(
 	flags *restoreFlags,
 	args []string,
 	console input.Console,
+	reporter progress.Reporter,
 	formatter output.Formatter,
 	writer io.Writer,
 	azdCtx *azdcontext.AzdContext,
@@ -94,11 +125,13 @@ func newRestoreAction(
 	serviceManager project.ServiceManager,
 	commandRunner exec.CommandRunner,
 	importManager *project.ImportManager,
+	lockManager *lockfile.Manager,
 ) actions.Action {
 	return &restoreAction{
 		flags:          flags,
 		args:           args,
 		console:        console,
+		reporter:       reporter,
 		formatter:      formatter,
 		writer:         writer,
 		azdCtx:         azdCtx,
@@ -108,12 +141,14 @@ func newRestoreAction(
 		env:            env,
 		commandRunner:  commandRunner,
 		importManager:  importManager,
+		lockManager:    lockManager,
 	}
 }
 
 type RestoreResult struct {
 	Timestamp time.Time                                `json:"timestamp"`
 	Services  map[string]*project.ServiceRestoreResult `json:"services"`
+	Lock      *lockfile.RestoreResult                  `json:"lock,omitempty"`
 }
 
 func (ra *restoreAction) Run(ctx context.Context) (*actions.ActionResult, error) {
@@ -144,6 +179,15 @@ func (ra *restoreAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		return nil, err
 	}
 
+	// The toolset pinned by the lock file is restored first, since services may be restored by locked extensions.
+	var lockResult *lockfile.RestoreResult
+	if !ra.flags.updateLock {
+		lockResult, err = ra.restoreLock(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if err := ra.projectManager.Initialize(ctx, ra.projectConfig); err != nil {
 		return nil, err
 	}
@@ -167,28 +211,26 @@ func (ra *restoreAction) Run(ctx context.Context) (*actions.ActionResult, error)
 
 	err = ra.projectConfig.Invoke(ctx, project.ProjectEventRestore, projectEventArgs, func() error {
 		for _, svc := range stableServices {
-			stepMessage := fmt.Sprintf("Restoring service %s", svc.Name)
-			ra.console.ShowSpinner(ctx, stepMessage, input.Step)
+			step := progress.Start(
+				ctx, ra.reporter, progress.PhaseRestore, svc.Name, fmt.Sprintf("Restoring service %s", svc.Name))
 
 			// Initialize service context for restore operation
 			serviceContext := &project.ServiceContext{}
 
 			restoreResult, err := async.RunWithProgress(
-				func(buildProgress project.ServiceProgress) {
-					progressMessage := fmt.Sprintf("Building service %s (%s)", svc.Name, buildProgress.Message)
-					ra.console.ShowSpinner(ctx, progressMessage, input.Step)
+				func(restoreProgress project.ServiceProgress) {
+					step.Update(restoreProgress.Message)
 				},
 				func(progress *async.Progress[project.ServiceProgress]) (*project.ServiceRestoreResult, error) {
 					return ra.serviceManager.Restore(ctx, svc, serviceContext, progress)
 				},
 			)
+			step.Done(err)
 
 			if err != nil {
-				ra.console.StopSpinner(ctx, stepMessage, input.StepFailed)
 				return err
 			}
 
-			ra.console.StopSpinner(ctx, stepMessage, input.StepDone)
 			restoreResults[svc.Name] = restoreResult
 
 			// report restore output
@@ -202,10 +244,17 @@ func (ra *restoreAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		return nil, err
 	}
 
+	if ra.flags.updateLock {
+		if err := ra.updateLock(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	if ra.formatter.Kind() == output.JsonFormat {
 		restoreResult := RestoreResult{
 			Timestamp: time.Now(),
 			Services:  restoreResults,
+			Lock:      lockResult,
 		}
 
 		if fmtErr := ra.formatter.Format(restoreResult, ra.writer, nil); fmtErr != nil {
@@ -221,12 +270,162 @@ func (ra *restoreAction) Run(ctx context.Context) (*actions.ActionResult, error)
 	}, nil
 }
 
+// restoreLock installs the extensions and tools pinned by the lock file of the project, when the project has one.
+func (ra *restoreAction) restoreLock(ctx context.Context) (*lockfile.RestoreResult, error) {
+	lock, err := lockfile.Load(lockfile.Path(ra.azdCtx.ProjectDirectory()))
+	if errors.Is(err, lockfile.ErrLockFileNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if lock.Template != nil && ra.projectConfig.Metadata != nil {
+		if ra.projectConfig.Metadata.Template != lock.Template.String() {
+			ra.console.MessageUxItem(ctx, &ux.WarningMessage{
+				Description: fmt.Sprintf(
+					"The project uses template %s, but %s was written for template %s. Run 'azd restore --update-lock'"+
+						" to update it.",
+					ra.projectConfig.Metadata.Template, lockfile.FileName, lock.Template),
+			})
+		} else if source := ra.projectConfig.Metadata.TemplateSource; source != nil && lock.Template.Commit != "" &&
+			source.Commit != lock.Template.Commit {
+			ra.console.MessageUxItem(ctx, &ux.WarningMessage{
+				Description: fmt.Sprintf(
+					"The project is at commit %s of template %s, but %s was written for commit %s. Run 'azd restore"+
+						" --update-lock' to update it.",
+					source.Commit, lock.Template, lockfile.FileName, lock.Template.Commit),
+			})
+		}
+	}
+
+	spinnerMessage := fmt.Sprintf("Restoring toolset from %s", lockfile.FileName)
+	ra.console.ShowSpinner(ctx, spinnerMessage, input.Step)
+	result, err := ra.lockManager.Restore(ctx, lock, lockfile.RestoreOptions{
+		AllowToolVersionMismatch: ra.flags.allowToolVersionMismatch,
+	})
+	ra.console.StopSpinner(ctx, spinnerMessage, input.GetStepResultFormat(err))
+	if errors.Is(err, lockfile.ErrToolVersionMismatch) {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("restoring toolset from %s: %w", lockfile.FileName, err),
+			Suggestion: "Install the locked version of the tool, run 'azd restore --update-lock' to lock the" +
+				" installed version, or run with --allow-tool-version-mismatch to continue with it.",
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("restoring toolset from %s: %w", lockfile.FileName, err)
+	}
+
+	for _, extension := range result.Extensions {
+		ra.console.MessageUxItem(ctx, &ux.DoneMessage{
+			Message: fmt.Sprintf("Installed extension %s version %s", extension.Id, extension.Version),
+		})
+	}
+
+	for _, tool := range result.Tools {
+		ra.console.MessageUxItem(ctx, &ux.DoneMessage{Message: fmt.Sprintf("Installed tool %s", tool.Id)})
+	}
+
+	for _, mismatch := range slices.Concat(result.NewerTools, result.MismatchedTools) {
+		ra.console.MessageUxItem(ctx, &ux.WarningMessage{
+			Description: fmt.Sprintf("%s is locked to version %s, but version %s is installed.",
+				mismatch.Id, mismatch.Version, mismatch.InstalledVersion),
+		})
+	}
+
+	return result, nil
+}
+
+// updateLock writes the lock file of the project from the installed versions of the extensions and tools it requires.
+func (ra *restoreAction) updateLock(ctx context.Context) error {
+	options := lockfile.CaptureOptions{}
+	if metadata := ra.projectConfig.Metadata; metadata != nil {
+		options.Template = metadata.Template
+		if metadata.TemplateSource != nil {
+			options.TemplateRepository = metadata.TemplateSource.Repository
+			options.TemplateCommit = metadata.TemplateSource.Commit
+		}
+	}
+
+	if ra.projectConfig.RequiredVersions != nil {
+		options.RequiredExtensions = slices.Sorted(maps.Keys(ra.projectConfig.RequiredVersions.Extensions))
+	}
+
+	requiredTools, err := ra.requiredTools(ctx)
+	if err != nil {
+		return err
+	}
+	options.RequiredTools = requiredTools
+
+	lock, err := ra.lockManager.Capture(ctx, options)
+	if err != nil {
+		return fmt.Errorf("capturing toolset: %w", err)
+	}
+
+	if err := lockfile.Save(lockfile.Path(ra.azdCtx.ProjectDirectory()), lock); err != nil {
+		return err
+	}
+
+	ra.console.MessageUxItem(ctx, &ux.DoneMessage{
+		Message: fmt.Sprintf("Pinned %d extension(s) and %d tool(s) in %s",
+			len(lock.Extensions), len(lock.Tools), lockfile.FileName),
+	})
+
+	return nil
+}
+
+// requiredTools returns the ids of the tools of `azd tool list` that the infrastructure provider and the services of
+// the project require.
+func (ra *restoreAction) requiredTools(ctx context.Context) ([]string, error) {
+	var ids []string
+	switch ra.projectConfig.Infra.Provider {
+	case provisioning.NotSpecified, provisioning.Bicep:
+		ids = append(ids, "bicep-cli")
+	case provisioning.Terraform:
+		ids = append(ids, "terraform")
+	}
+
+	services, err := ra.importManager.ServiceStable(ctx, ra.projectConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, svc := range services {
+		svcTools, err := ra.serviceManager.GetRequiredTools(ctx, svc)
+		if err != nil {
+			return nil, fmt.Errorf("getting required tools of service '%s': %w", svc.Name, err)
+		}
+
+		for _, svcTool := range svcTools {
+			switch svcTool.(type) {
+			case *kubectl.Cli:
+				ids = append(ids, "kubectl")
+			case *helm.Cli:
+				ids = append(ids, "helm")
+			case *swa.Cli:
+				ids = append(ids, "swa-cli")
+			case *docker.Cli:
+				// Images of services without a Dockerfile are built from source with pack.
+				if svc.Docker.Path != "" {
+					continue
+				}
+				if _, err := os.Stat(filepath.Join(svc.Path(), "Dockerfile")); errors.Is(err, os.ErrNotExist) {
+					ids = append(ids, "pack")
+				}
+			}
+		}
+	}
+
+	slices.Sort(ids)
+	return slices.Compact(ids), nil
+}
+
 func getCmdRestoreHelpDescription(*cobra.Command) string {
 	return generateCmdHelpDescription(
 		fmt.Sprintf("Restore application dependencies. %s", output.WithWarningFormat("(Beta)")),
 		[]string{
 			formatHelpNote("Run this command to download and install all required dependencies so that you can build," +
 				" run, and debug the application locally."),
+			formatHelpNote(fmt.Sprintf("When the project has an %s file, the extensions and tools it pins are"+
+				" installed at their locked version first. Run with --update-lock to write it.", lockfile.FileName)),
 			formatHelpNote(fmt.Sprintf("For the best local run and debug experience, go to %s to learn how "+
 				"to use the Visual Studio Code extension.",
 				output.WithLinkFormat("https://aka.ms/azure-dev/vscode"),
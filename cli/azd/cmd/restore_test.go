@@ -13,6 +13,7 @@ import (
 
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/progress"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
 )
 
@@ -22,8 +23,8 @@ func Test_NewRestoreAction_Constructor(t *testing.T) {
 	console := mockinput.NewMockConsole()
 	formatter := &output.JsonFormatter{}
 	a := newRestoreAction(
-		flags, nil, console, formatter, io.Discard,
-		nil, nil, nil, nil, nil, nil, nil,
+		flags, nil, console, progress.Discard, formatter, io.Discard,
+		nil, nil, nil, nil, nil, nil, nil, nil,
 	)
 	ra := a.(*restoreAction)
 	require.Same(t, flags, ra.flags)
@@ -213,6 +213,7 @@ func newRootCmd(
 	mcpActions(root)
 	copilotActions(root)
 	execActions(root)
+	flagsActions(root)
 
 	toolActions(root)
 
@@ -307,7 +308,7 @@ func newRootCmd(
 		UseMiddleware("hooks", middleware.NewHooksMiddleware).
 		UseMiddleware("extensions", middleware.NewExtensionsMiddleware)
 
-	root.
+	provision := root.
 		Add("provision", &actions.ActionDescriptorOptions{
 			Command:        cmd.NewProvisionCmd(),
 			FlagsResolver:  cmd.NewProvisionFlags,
@@ -324,6 +325,10 @@ func newRootCmd(
 			RequireLogin: true,
 		}).
 		UseMiddlewareWhen("hooks", middleware.NewHooksMiddleware, func(descriptor *actions.ActionDescriptor) bool {
+			// Provision hooks only apply to provisioning itself, not to subcommands such as 'provision cancel'.
+			if descriptor.Name != "provision" {
+				return false
+			}
 			if onPreview, _ := descriptor.Options.Command.Flags().GetBool("preview"); onPreview {
 				log.Println("Skipping provision hooks due to preview flag.")
 				return false
@@ -332,6 +337,19 @@ func newRootCmd(
 		}).
 		UseMiddleware("extensions", middleware.NewExtensionsMiddleware)
 
+	provision.Add("cancel", &actions.ActionDescriptorOptions{
+		Command:        newProvisionCancelCmd(),
+		FlagsResolver:  newProvisionCancelFlags,
+		ActionResolver: newProvisionCancelAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdProvisionCancelHelpDescription,
+			Footer:      getCmdProvisionCancelHelpFooter,
+		},
+		RequireLogin: true,
+	})
+
 	root.
 		Add("package", &actions.ActionDescriptorOptions{
 			Command:        newPackageCmd(),
@@ -406,10 +424,25 @@ func newRootCmd(
 		UseMiddleware("hooks", middleware.NewHooksMiddleware).
 		UseMiddleware("extensions", middleware.NewExtensionsMiddleware)
 
+	root.Add("logs", &actions.ActionDescriptorOptions{
+		Command:        newLogsCmd(),
+		FlagsResolver:  newLogsFlags,
+		ActionResolver: newLogsAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdLogsHelpDescription,
+			Footer:      getCmdLogsHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupBeta,
+		},
+	})
+
 	root.Add("monitor", &actions.ActionDescriptorOptions{
 		Command:        newMonitorCmd(),
 		FlagsResolver:  newMonitorFlags,
 		ActionResolver: newMonitorAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
+		DefaultFormat:  output.TableFormat,
 		HelpOptions: actions.ActionHelpOptions{
 			Description: getCmdMonitorHelpDescription,
 			Footer:      getCmdMonitorHelpFooter,
@@ -419,6 +452,45 @@ func newRootCmd(
 		},
 	})
 
+	root.Add("run", &actions.ActionDescriptorOptions{
+		Command:        newRunCmd(),
+		FlagsResolver:  newRunFlags,
+		ActionResolver: newRunAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdRunHelpDescription,
+			Footer:      getCmdRunHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupBeta,
+		},
+	})
+
+	root.Add("shell", &actions.ActionDescriptorOptions{
+		Command:        newShellCmd(),
+		FlagsResolver:  newShellFlags,
+		ActionResolver: newShellAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdShellHelpDescription,
+			Footer:      getCmdShellHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupBeta,
+		},
+	})
+
+	root.Add("wait", &actions.ActionDescriptorOptions{
+		Command:        newWaitCmd(),
+		FlagsResolver:  newWaitFlags,
+		ActionResolver: newWaitAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdWaitHelpDescription,
+			Footer:      getCmdWaitHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupBeta,
+		},
+	})
+
 	root.
 		Add("down", &actions.ActionDescriptorOptions{
 			Command:        newDownCmd(),
@@ -440,6 +512,7 @@ func newRootCmd(
 	root.
 		Add("add", &actions.ActionDescriptorOptions{
 			Command:        add.NewAddCmd(),
+			FlagsResolver:  add.NewAddFlags,
 			ActionResolver: add.NewAddAction,
 			GroupingOptions: actions.CommandGroupOptions{
 				RootLevelHelp: actions.CmdGroupBeta,
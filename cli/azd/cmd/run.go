@@ -0,0 +1,581 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/devtunnel"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type runFlags struct {
+	readyTimeout time.Duration
+	expose       []string
+	internal.EnvFlag
+}
+
+func (f *runFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.DurationVar(
+		&f.readyTimeout,
+		"ready-timeout",
+		2*time.Minute,
+		"How long to wait for a service with a run.port to accept connections before starting the services that use it, "+
+			"and for the dev tunnels of exposed services to start.",
+	)
+	local.StringArrayVar(
+		&f.expose,
+		"expose",
+		nil,
+		"Expose a service with a public URL through a dev tunnel. Can be repeated.",
+	)
+	f.EnvFlag.Bind(local, global)
+}
+
+func newRunFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *runFlags {
+	flags := &runFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newRunCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "run [<service>...]",
+		Short: "Run the services of the project locally.",
+	}
+}
+
+func getCmdRunHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Start the services of the project on this machine, with the values of the environment as environment "+
+			"variables, until Ctrl+C is pressed. Services start after the services they use, and each line of their "+
+			"output is prefixed with the name of its service.",
+		[]string{
+			formatHelpNote(fmt.Sprintf(
+				"The command of a service is inferred from its language and host, such as %s, %s or %s, "+
+					"or set with %s in azure.yaml.",
+				output.WithHighLightFormat("dotnet run"),
+				output.WithHighLightFormat("npm run start"),
+				output.WithHighLightFormat("uvicorn"),
+				output.WithHighLightFormat("run.command"))),
+			formatHelpNote(fmt.Sprintf(
+				"Services with a %s start the services that use them once they accept connections on it.",
+				output.WithHighLightFormat("run.port"))),
+			formatHelpNote(fmt.Sprintf(
+				"Services exposed with %s get a public URL through a dev tunnel, set in the %s environment variable "+
+					"of the service and of the services that use it. The tunnel is deleted when the services stop.",
+				output.WithHighLightFormat("--expose"),
+				output.WithHighLightFormat("SERVICE_<NAME>_TUNNEL_URL"))),
+			formatHelpNote("When a service exits, the other services are stopped."),
+		})
+}
+
+func getCmdRunHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Run every service of the project.":           output.WithHighLightFormat("azd run"),
+		"Run the api and web services.":               output.WithHighLightFormat("azd run api web"),
+		"Run every service with the dev environment.": output.WithHighLightFormat("azd run -e dev"),
+		"Run every service, and expose the api service with a public URL.": output.WithHighLightFormat(
+			"azd run --expose api"),
+	})
+}
+
+type runAction struct {
+	args          []string
+	flags         *runFlags
+	projectConfig *project.ProjectConfig
+	importManager *project.ImportManager
+	env           *environment.Environment
+	commandRunner exec.CommandRunner
+	devTunnelCli  *devtunnel.Cli
+	console       input.Console
+	writer        io.Writer
+}
+
+func newRunAction(
+	args []string,
+	flags *runFlags,
+	projectConfig *project.ProjectConfig,
+	importManager *project.ImportManager,
+	env *environment.Environment,
+	commandRunner exec.CommandRunner,
+	devTunnelCli *devtunnel.Cli,
+	console input.Console,
+	writer io.Writer,
+) actions.Action {
+	return &runAction{
+		args:          args,
+		flags:         flags,
+		projectConfig: projectConfig,
+		importManager: importManager,
+		env:           env,
+		commandRunner: commandRunner,
+		devTunnelCli:  devTunnelCli,
+		console:       console,
+		writer:        writer,
+	}
+}
+
+// localService is a service started by azd run.
+type localService struct {
+	config  *project.ServiceConfig
+	command string
+	dir     string
+	// ready is closed once the service is started, and accepts connections when it has a run.port.
+	ready chan struct{}
+	// tunnel is the dev tunnel of the service, when it's exposed with --expose.
+	tunnel *localTunnel
+}
+
+// localTunnel is the dev tunnel that exposes a service run by azd run.
+type localTunnel struct {
+	// id is set once the tunnel is created.
+	id  string
+	url string
+}
+
+func (r *runAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	services, err := r.services(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Ctrl+C stops the services, which isn't a failure.
+	pop := input.PushInterruptHandler(func() bool {
+		cancel()
+		return true
+	})
+	defer pop()
+
+	var writeMu sync.Mutex
+	var wg sync.WaitGroup
+	outs := make([]*linePrefixWriter, len(services))
+	for i, service := range services {
+		prefix := logsPrefixColors[i%len(logsPrefixColors)]("[%s]", service.config.Name) + " "
+		outs[i] = &linePrefixWriter{mu: &writeMu, writer: r.writer, prefix: prefix}
+	}
+
+	// The tunnels are deleted once their hosts are stopped.
+	defer r.deleteTunnels(ctx, services)
+	defer wg.Wait()
+	defer cancel()
+
+	tunnelErrs := make([]error, len(services))
+	if err := r.startTunnels(runCtx, cancel, services, outs, &wg, tunnelErrs); err != nil {
+		return nil, err
+	}
+
+	r.console.Message(ctx, fmt.Sprintf("Starting %d service(s). Press Ctrl+C to stop.", len(services)))
+
+	errs := make([]error, len(services))
+	for i, service := range services {
+		out := outs[i]
+
+		wg.Go(func() {
+			defer out.Flush()
+
+			if !r.waitForDependencies(runCtx, service, services, out) {
+				return
+			}
+
+			env, err := r.serviceEnv(service.config, services)
+			if err != nil {
+				errs[i] = err
+				cancel()
+				return
+			}
+
+			fmt.Fprintf(out, "> %s\n", service.command)
+
+			exited := make(chan struct{})
+			go r.waitForReady(runCtx, service, exited, out)
+
+			_, err = r.commandRunner.Run(runCtx, exec.RunArgs{
+				Cmd:      service.command,
+				Cwd:      service.dir,
+				Env:      env,
+				UseShell: true,
+				StdOut:   out,
+				Stderr:   out,
+			})
+			close(exited)
+
+			// Stopped by Ctrl+C, or because another service exited.
+			if runCtx.Err() != nil {
+				return
+			}
+
+			if err != nil {
+				errs[i] = fmt.Errorf("service '%s' exited: %w", service.config.Name, err)
+			} else {
+				errs[i] = fmt.Errorf("service '%s' exited", service.config.Name)
+			}
+
+			// A service that exits stops the others, like a failed deployment stops azd up.
+			cancel()
+		})
+	}
+	wg.Wait()
+
+	if err := errors.Join(append(tunnelErrs, errs...)...); err != nil {
+		return nil, err
+	}
+
+	// Services stopped with Ctrl+C end the command successfully, but a canceled command doesn't.
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return nil, nil
+}
+
+// services returns the services named in the arguments, or every service of the project, in the order they start.
+// Services whose command can't be inferred are skipped, unless they're named.
+func (r *runAction) services(ctx context.Context) ([]*localService, error) {
+	stableServices, err := r.importManager.ServiceStable(ctx, r.projectConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range r.args {
+		if !slices.ContainsFunc(stableServices, func(svc *project.ServiceConfig) bool { return svc.Name == name }) {
+			return nil, fmt.Errorf("service '%s' isn't defined in azure.yaml: %w", name, internal.ErrInvalidArgValue)
+		}
+	}
+
+	suggestion := "Set the command that starts the service with run.command in azure.yaml."
+
+	var services []*localService
+	for _, svc := range stableServices {
+		named := slices.Contains(r.args, svc.Name)
+		if len(r.args) > 0 && !named {
+			continue
+		}
+
+		command, dir, err := svc.LocalRunCommand()
+		if errors.Is(err, project.ErrNoLocalRunCommand) && !named {
+			r.console.MessageUxItem(ctx, &ux.WarningMessage{
+				Description: fmt.Sprintf("Skipping service '%s', its command can't be inferred. %s", svc.Name, suggestion),
+			})
+			continue
+		}
+		if err != nil {
+			return nil, &internal.ErrorWithSuggestion{Err: err, Suggestion: suggestion}
+		}
+
+		services = append(services, &localService{
+			config:  svc,
+			command: command,
+			dir:     dir,
+			ready:   make(chan struct{}),
+		})
+	}
+
+	if len(services) == 0 {
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        errors.New("the project has no services to run"),
+			Suggestion: suggestion,
+		}
+	}
+
+	for _, name := range r.flags.expose {
+		index := slices.IndexFunc(services, func(s *localService) bool { return s.config.Name == name })
+		if index < 0 {
+			return nil, fmt.Errorf("exposed service '%s' isn't run: %w", name, internal.ErrInvalidArgValue)
+		}
+
+		if services[index].config.LocalRunPort() == 0 {
+			return nil, &internal.ErrorWithSuggestion{
+				Err: fmt.Errorf(
+					"exposed service '%s' doesn't have a run.port: %w", name, internal.ErrInvalidArgValue),
+				Suggestion: "Set the port the service listens on with run.port in azure.yaml.",
+			}
+		}
+
+		services[index].tunnel = &localTunnel{}
+	}
+
+	return services, nil
+}
+
+// startTunnels creates and hosts the dev tunnels of the exposed services, and waits for their public URLs. A tunnel
+// whose host exits records its error in errs and stops the services.
+func (r *runAction) startTunnels(
+	ctx context.Context,
+	cancel context.CancelFunc,
+	services []*localService,
+	outs []*linePrefixWriter,
+	wg *sync.WaitGroup,
+	errs []error,
+) error {
+	if !slices.ContainsFunc(services, func(s *localService) bool { return s.tunnel != nil }) {
+		return nil
+	}
+
+	if err := tools.EnsureInstalled(ctx, r.devTunnelCli); err != nil {
+		return err
+	}
+
+	for i, service := range services {
+		if service.tunnel == nil {
+			continue
+		}
+
+		id := tunnelId(service.config.Name)
+		port := service.config.LocalRunPort()
+		if err := r.devTunnelCli.Create(ctx, id, port); err != nil {
+			return &internal.ErrorWithSuggestion{
+				Err: err,
+				Suggestion: fmt.Sprintf(
+					"Sign in to Dev Tunnels with %s.", output.WithHighLightFormat("devtunnel user login")),
+			}
+		}
+
+		service.tunnel.id = id
+
+		urls := make(chan string, 1)
+		wg.Go(func() {
+			err := r.devTunnelCli.Host(ctx, service.tunnel.id, port, func(url string) { urls <- url })
+			if ctx.Err() != nil {
+				return
+			}
+
+			if err != nil {
+				errs[i] = fmt.Errorf("tunnel of service '%s' closed: %w", service.config.Name, err)
+			} else {
+				errs[i] = fmt.Errorf("tunnel of service '%s' closed", service.config.Name)
+			}
+			cancel()
+		})
+
+		select {
+		case service.tunnel.url = <-urls:
+		case <-ctx.Done():
+			return errors.Join(errs[i], ctx.Err())
+		case <-time.After(r.flags.readyTimeout):
+			return fmt.Errorf("tunnel of service '%s' didn't start within %s", service.config.Name, r.flags.readyTimeout)
+		}
+
+		fmt.Fprintf(outs[i], "Exposed at %s\n", output.WithHyperlink(service.tunnel.url, service.tunnel.url))
+	}
+
+	return nil
+}
+
+// deleteTunnels deletes the dev tunnels created for the exposed services, even when ctx is canceled.
+func (r *runAction) deleteTunnels(ctx context.Context, services []*localService) {
+	for _, service := range services {
+		if service.tunnel == nil || service.tunnel.id == "" {
+			continue
+		}
+
+		if err := r.devTunnelCli.Delete(context.WithoutCancel(ctx), service.tunnel.id); err != nil {
+			r.console.MessageUxItem(ctx, &ux.WarningMessage{
+				Description: fmt.Sprintf("Failed to delete the tunnel '%s' of service '%s': %v",
+					service.tunnel.id, service.config.Name, err),
+			})
+		}
+	}
+}
+
+// tunnelId returns a unique ID for the dev tunnel of a service. IDs may only contain lowercase letters, digits and
+// hyphens.
+func tunnelId(serviceName string) string {
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(serviceName))
+	if len(name) > 32 {
+		name = name[:32]
+	}
+
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return fmt.Sprintf("azd-%s-%s", strings.Trim(name, "-"), hex.EncodeToString(suffix))
+}
+
+// waitForDependencies waits until the services used by service are ready, and returns false when ctx is done first.
+// Only the services that are run are waited for.
+func (r *runAction) waitForDependencies(
+	ctx context.Context,
+	service *localService,
+	services []*localService,
+	out io.Writer,
+) bool {
+	for _, name := range service.config.DeployDependencies() {
+		index := slices.IndexFunc(services, func(s *localService) bool { return s.config.Name == name })
+		if index < 0 {
+			continue
+		}
+
+		select {
+		case <-services[index].ready:
+		default:
+			fmt.Fprintf(out, "Waiting for %s\n", name)
+			select {
+			case <-services[index].ready:
+			case <-ctx.Done():
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// waitForReady marks service ready once it accepts connections on its run.port, or right away when it doesn't have
+// one. A service that isn't ready within --ready-timeout is marked ready with a warning, so the services that use it
+// still start.
+func (r *runAction) waitForReady(ctx context.Context, service *localService, exited <-chan struct{}, out io.Writer) {
+	defer close(service.ready)
+
+	port := service.config.LocalRunPort()
+	if port == 0 {
+		return
+	}
+
+	address := net.JoinHostPort("localhost", strconv.Itoa(port))
+	timeout := time.After(r.flags.readyTimeout)
+	for {
+		conn, err := net.DialTimeout("tcp", address, time.Second)
+		if err == nil {
+			conn.Close()
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-exited:
+			return
+		case <-timeout:
+			fmt.Fprintf(out, "Not accepting connections on port %d after %s, starting the services that use it\n",
+				port, r.flags.readyTimeout)
+			return
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+// serviceEnv returns the environment variables of a service: the values of the azd environment, the public URLs of
+// the tunnels of the service and of the services it uses, the env of the service, and PORT when the service has a
+// run.port. Key Vault references in the env of the service are only resolved when the service is deployed, so they
+// aren't set.
+func (r *runAction) serviceEnv(svc *project.ServiceConfig, services []*localService) ([]string, error) {
+	env := r.env.Environ()
+
+	tunnelUrls := map[string]string{}
+	for _, service := range services {
+		if service.tunnel != nil {
+			tunnelUrls[tunnelUrlEnvName(service.config.Name)] = service.tunnel.url
+		}
+	}
+
+	for _, name := range append([]string{svc.Name}, svc.DeployDependencies()...) {
+		if url, has := tunnelUrls[tunnelUrlEnvName(name)]; has {
+			env = append(env, tunnelUrlEnvName(name)+"="+url)
+		}
+	}
+
+	getenv := func(name string) string {
+		if url, has := tunnelUrls[name]; has {
+			return url
+		}
+		return r.env.Getenv(name)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(svc.Environment)) {
+		if keyvault.HasSecretExpression(svc.Environment[name].Template()) {
+			continue
+		}
+
+		value, err := svc.Environment[name].Envsubst(getenv)
+		if err != nil {
+			return nil, fmt.Errorf("evaluating env '%s' of service '%s': %w", name, svc.Name, err)
+		}
+		env = append(env, name+"="+value)
+	}
+
+	if port := svc.LocalRunPort(); port != 0 {
+		env = append(env, "PORT="+strconv.Itoa(port))
+	}
+
+	return env, nil
+}
+
+// tunnelUrlEnvName is the environment variable with the public URL of the tunnel of a service.
+func tunnelUrlEnvName(serviceName string) string {
+	return fmt.Sprintf("SERVICE_%s_TUNNEL_URL", environment.Key(serviceName))
+}
+
+// linePrefixWriter writes complete lines to writer, each prefixed with prefix, so the lines of services run at the
+// same time don't interleave.
+type linePrefixWriter struct {
+	mu      *sync.Mutex
+	writer  io.Writer
+	prefix  string
+	pending []byte
+}
+
+func (w *linePrefixWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.pending = append(w.pending, p...)
+	for {
+		index := bytes.IndexByte(w.pending, '\n')
+		if index < 0 {
+			break
+		}
+
+		line := bytes.TrimSuffix(w.pending[:index], []byte{'\r'})
+		if _, err := fmt.Fprintf(w.writer, "%s%s\n", w.prefix, line); err != nil {
+			return 0, err
+		}
+		w.pending = w.pending[index+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush writes the last line, when it doesn't end with a new line.
+func (w *linePrefixWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.pending) > 0 {
+		fmt.Fprintf(w.writer, "%s%s\n", w.prefix, w.pending)
+		w.pending = nil
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/devtunnel"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunServices(t *testing.T) {
+	color.NoColor = true
+	dir := t.TempDir()
+
+	newAction := func(mockContext *mocks.MockContext, writer *bytes.Buffer, args ...string) *runAction {
+		projectConfig := &project.ProjectConfig{
+			Name: "test",
+			Path: dir,
+			Services: map[string]*project.ServiceConfig{
+				"api": {
+					Name:        "api",
+					Host:        project.ContainerAppTarget,
+					Language:    project.ServiceLanguageGo,
+					Environment: osutil.ExpandableMap{"DB_HOST": osutil.NewExpandableString("${DB_HOST}")},
+				},
+				"web": {
+					Name: "web",
+					Host: project.StaticWebAppTarget,
+					Uses: []string{"api"},
+					Run:  &project.LocalRunOptions{Command: "npm run dev"},
+				},
+				"infra-only": {Name: "infra-only", Host: project.ContainerAppTarget, Language: project.ServiceLanguageDocker},
+			},
+		}
+		for _, svc := range projectConfig.Services {
+			svc.Project = projectConfig
+		}
+
+		return &runAction{
+			args:          args,
+			flags:         &runFlags{readyTimeout: time.Second},
+			projectConfig: projectConfig,
+			importManager: project.NewImportManager(nil),
+			env:           environment.NewWithValues("dev", map[string]string{"DB_HOST": "localhost"}),
+			commandRunner: mockContext.CommandRunner,
+			devTunnelCli:  devtunnel.NewCli(mockContext.CommandRunner),
+			console:       mockContext.Console,
+			writer:        writer,
+		}
+	}
+
+	t.Run("StartsInOrder", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+
+		var mu sync.Mutex
+		var started []string
+		webExited := make(chan struct{})
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return args.Cmd == "go run ."
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			mu.Lock()
+			started = append(started, "api")
+			mu.Unlock()
+
+			require.True(t, args.UseShell)
+			require.Contains(t, args.Env, "DB_HOST=localhost")
+			fmt.Fprint(args.StdOut, "listening\r\non :8080")
+
+			// The api runs until the web service exits and stops it.
+			<-webExited
+			return exec.RunResult{}, nil
+		})
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return args.Cmd == "npm run dev"
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			mu.Lock()
+			started = append(started, "web")
+			mu.Unlock()
+
+			fmt.Fprintln(args.Stderr, "build failed")
+			close(webExited)
+			return exec.RunResult{ExitCode: 1}, fmt.Errorf("exit code: 1")
+		})
+
+		var out bytes.Buffer
+		_, err := newAction(mockContext, &out).Run(*mockContext.Context)
+		require.ErrorContains(t, err, "service 'web' exited: exit code: 1")
+		require.Equal(t, []string{"api", "web"}, started)
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Contains(t, lines, "[api] > go run .")
+		require.Contains(t, lines, "[api] listening")
+		require.Contains(t, lines, "[api] on :8080")
+		require.Contains(t, lines, "[web] > npm run dev")
+		require.Contains(t, lines, "[web] build failed")
+	})
+
+	t.Run("Expose", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		mockContext.CommandRunner.MockToolInPath("devtunnel", nil)
+
+		// The api accepts connections right away.
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer listener.Close()
+
+		ctx, cancel := context.WithCancel(*mockContext.Context)
+		defer cancel()
+
+		var tunnelCommands []string
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return args.Cmd == "devtunnel" && args.Args[0] != "host"
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			tunnelCommands = append(tunnelCommands, args.Args[0])
+			return exec.RunResult{}, nil
+		})
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return args.Cmd == "devtunnel" && args.Args[0] == "host"
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			fmt.Fprintln(args.StdOut, "Connect via browser: https://abcd1234-8080.usw2.devtunnels.ms")
+			<-ctx.Done()
+			return exec.RunResult{}, nil
+		})
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return args.Cmd == "go run ."
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			require.Contains(t, args.Env, "SERVICE_API_TUNNEL_URL=https://abcd1234-8080.usw2.devtunnels.ms")
+			<-ctx.Done()
+			return exec.RunResult{}, nil
+		})
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return args.Cmd == "npm run dev"
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			require.Contains(t, args.Env, "SERVICE_API_TUNNEL_URL=https://abcd1234-8080.usw2.devtunnels.ms")
+			// Stopped with Ctrl+C.
+			cancel()
+			return exec.RunResult{}, nil
+		})
+
+		var out bytes.Buffer
+		action := newAction(mockContext, &out)
+		action.flags.expose = []string{"api"}
+		action.projectConfig.Services["api"].Run = &project.LocalRunOptions{
+			Port: listener.Addr().(*net.TCPAddr).Port,
+		}
+
+		_, err = action.Run(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, []string{"create", "port", "delete"}, tunnelCommands)
+		require.Contains(t, out.String(), "[api] Exposed at https://abcd1234-8080.usw2.devtunnels.ms\n")
+	})
+
+	t.Run("ExposeWithoutPort", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+
+		action := newAction(mockContext, &bytes.Buffer{})
+		action.flags.expose = []string{"api"}
+
+		_, err := action.Run(*mockContext.Context)
+		require.ErrorIs(t, err, internal.ErrInvalidArgValue)
+	})
+
+	t.Run("NamedWithoutCommand", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+
+		_, err := newAction(mockContext, &bytes.Buffer{}, "infra-only").Run(*mockContext.Context)
+		require.ErrorIs(t, err, project.ErrNoLocalRunCommand)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+
+		_, err := newAction(mockContext, &bytes.Buffer{}, "missing").Run(*mockContext.Context)
+		require.ErrorIs(t, err, internal.ErrInvalidArgValue)
+	})
+}
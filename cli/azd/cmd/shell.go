@@ -0,0 +1,218 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+)
+
+// shellHosts are the hosts of the services that azd shell can run a command in.
+var shellHosts = []project.ServiceTargetKind{
+	project.ContainerAppTarget,
+	project.AksTarget,
+}
+
+type shellFlags struct {
+	command   string
+	instance  string
+	container string
+	internal.EnvFlag
+}
+
+func (f *shellFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(&f.command, "command", "/bin/sh", "The command to run, such as /bin/bash or 'python manage.py shell'.")
+	local.StringVar(
+		&f.instance,
+		"instance",
+		"",
+		"The replica of the container app or the pod to run the command in. Defaults to the first one.",
+	)
+	local.StringVar(
+		&f.container,
+		"container",
+		"",
+		"The container to run the command in. Defaults to the container named after the app, or the first one.",
+	)
+	f.EnvFlag.Bind(local, global)
+}
+
+func newShellFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *shellFlags {
+	flags := &shellFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newShellCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "shell [<service>]",
+		Short: "Open a shell in a running instance of a deployed service.",
+		Args:  cobra.MaximumNArgs(1),
+	}
+}
+
+func getCmdShellHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Run a shell, or another command, in a running instance of a service, resolved from the environment. "+
+			"The service can be omitted when the project has a single service that supports it.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("%s services run the command in a replica of the latest ready revision.",
+				output.WithHighLightFormat("containerapp"))),
+			formatHelpNote(fmt.Sprintf("%s services run the command in a pod of the deployment with kubectl exec.",
+				output.WithHighLightFormat("aks"))),
+		})
+}
+
+func getCmdShellHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Open a shell in the api service.": output.WithHighLightFormat("azd shell api"),
+		"Run a command in a specific replica of the api service.": output.WithHighLightFormat(
+			"azd shell api --instance <replica> --command 'python manage.py shell'",
+		),
+	})
+}
+
+type shellAction struct {
+	args           []string
+	flags          *shellFlags
+	projectConfig  *project.ProjectConfig
+	importManager  *project.ImportManager
+	serviceManager project.ServiceManager
+}
+
+func newShellAction(
+	args []string,
+	flags *shellFlags,
+	projectConfig *project.ProjectConfig,
+	importManager *project.ImportManager,
+	serviceManager project.ServiceManager,
+) actions.Action {
+	return &shellAction{
+		args:           args,
+		flags:          flags,
+		projectConfig:  projectConfig,
+		importManager:  importManager,
+		serviceManager: serviceManager,
+	}
+}
+
+func (s *shellAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	svc, err := s.service(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceTarget, err := s.serviceManager.GetServiceTarget(ctx, svc)
+	if err != nil {
+		return nil, err
+	}
+
+	shell, ok := serviceTarget.(project.ServiceShell)
+	if !ok {
+		return nil, fmt.Errorf(
+			"service '%s' with host '%s' doesn't support running commands: %w",
+			svc.Name, svc.Host, internal.ErrUnsupportedOperation)
+	}
+
+	targetResource, err := s.serviceManager.GetTargetResource(ctx, svc, serviceTarget)
+	if err != nil {
+		return nil, fmt.Errorf("getting the resource of service '%s': %w", svc.Name, err)
+	}
+
+	if targetResource.ResourceName() == "" {
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        fmt.Errorf("service '%s' hasn't been deployed: %w", svc.Name, internal.ErrResourceNotConfigured),
+			Suggestion: fmt.Sprintf("Deploy the service with 'azd deploy %s' first.", svc.Name),
+		}
+	}
+
+	command := strings.Fields(s.flags.command)
+	if len(command) == 0 {
+		return nil, fmt.Errorf("--command can't be empty: %w", internal.ErrInvalidArgValue)
+	}
+
+	err = shell.Shell(ctx, svc, targetResource, &project.ShellOptions{
+		Command:   command,
+		Instance:  s.flags.instance,
+		Container: s.flags.container,
+		//nolint:gosec // G115: file descriptors fit in int on all supported platforms
+		Tty: term.IsTerminal(int(os.Stdin.Fd())),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// service returns the service named in the arguments, or the only service whose host supports running commands.
+func (s *shellAction) service(ctx context.Context) (*project.ServiceConfig, error) {
+	stableServices, err := s.importManager.ServiceStable(ctx, s.projectConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	supportedHosts := make([]string, len(shellHosts))
+	for i, host := range shellHosts {
+		supportedHosts[i] = string(host)
+	}
+	suggestion := fmt.Sprintf("Commands can be run in services with host %s.", strings.Join(supportedHosts, ", "))
+
+	if len(s.args) == 1 {
+		index := slices.IndexFunc(stableServices, func(svc *project.ServiceConfig) bool { return svc.Name == s.args[0] })
+		if index < 0 {
+			return nil, fmt.Errorf(
+				"service '%s' isn't defined in azure.yaml: %w", s.args[0], internal.ErrInvalidArgValue)
+		}
+
+		svc := stableServices[index]
+		if !slices.Contains(shellHosts, svc.Host) {
+			return nil, &internal.ErrorWithSuggestion{
+				Err: fmt.Errorf("running commands in service '%s' with host '%s' isn't supported: %w",
+					svc.Name, svc.Host, internal.ErrInvalidArgValue),
+				Suggestion: suggestion,
+			}
+		}
+
+		return svc, nil
+	}
+
+	var names []string
+	var supported *project.ServiceConfig
+	for _, svc := range stableServices {
+		if slices.Contains(shellHosts, svc.Host) {
+			supported = svc
+			names = append(names, svc.Name)
+		}
+	}
+
+	switch len(names) {
+	case 0:
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        errors.New("the project has no services to run commands in"),
+			Suggestion: suggestion,
+		}
+	case 1:
+		return supported, nil
+	default:
+		return nil, &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("the project has several services to run commands in: %w", internal.ErrNoArgsProvided),
+			Suggestion: fmt.Sprintf("Name the service to run the command in, one of %s, such as 'azd shell %s'.",
+				strings.Join(names, ", "), names[0]),
+		}
+	}
+}
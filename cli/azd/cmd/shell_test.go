@@ -0,0 +1,59 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShellService(t *testing.T) {
+	service := func(services map[string]*project.ServiceConfig, args ...string) (*project.ServiceConfig, error) {
+		action := &shellAction{
+			args:          args,
+			projectConfig: &project.ProjectConfig{Name: "test", Services: services},
+			importManager: project.NewImportManager(nil),
+		}
+		return action.service(t.Context())
+	}
+
+	services := map[string]*project.ServiceConfig{
+		"api":    {Name: "api", Host: project.ContainerAppTarget},
+		"web":    {Name: "web", Host: project.StaticWebAppTarget},
+		"worker": {Name: "worker", Host: project.AksTarget},
+	}
+
+	t.Run("Named", func(t *testing.T) {
+		svc, err := service(services, "worker")
+		require.NoError(t, err)
+		require.Equal(t, "worker", svc.Name)
+	})
+
+	t.Run("OnlySupported", func(t *testing.T) {
+		svc, err := service(map[string]*project.ServiceConfig{
+			"api": services["api"],
+			"web": services["web"],
+		})
+		require.NoError(t, err)
+		require.Equal(t, "api", svc.Name)
+	})
+
+	t.Run("SeveralSupported", func(t *testing.T) {
+		_, err := service(services)
+		require.ErrorIs(t, err, internal.ErrNoArgsProvided)
+		suggestion, ok := errors.AsType[*internal.ErrorWithSuggestion](err)
+		require.True(t, ok)
+		require.Contains(t, suggestion.Suggestion, "one of api, worker")
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		_, err := service(services, "web")
+		require.ErrorIs(t, err, internal.ErrInvalidArgValue)
+		require.ErrorContains(t, err, "service 'web' with host 'staticwebapp' isn't supported")
+	})
+}
@@ -245,6 +245,7 @@ func TestCommandTelemetryCoverage(t *testing.T) {
 		"package",           // (via hooks middleware)
 		"pipeline config",   // pipeline.provider, pipeline.auth
 		"provision",         // infra.provider (resolved provider, via provisioning manager)
+		"provision cancel",  // infra.provider (resolved provider, via provisioning manager)
 		"restore",           // (via hooks middleware)
 		"tool check",        // tool.check.updates_available
 		"tool install",      // tool.id(s), tool.dry_run, tool.install.* aggregate + per-tool fields
@@ -0,0 +1,428 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/ext"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+// smokeTestInterval is how long a URL smoke test waits between requests.
+const smokeTestInterval = 5 * time.Second
+
+// smokeTestHookName is the name smoke test scripts run under, as seen by the hooks runner.
+const smokeTestHookName = "smoketest"
+
+type templateTestFlags struct {
+	envName      string
+	subscription string
+	location     string
+	keep         bool
+	report       string
+}
+
+func newTemplateTestFlags(cmd *cobra.Command) *templateTestFlags {
+	flags := &templateTestFlags{}
+	cmd.Flags().StringVar(
+		&flags.envName,
+		"env-name",
+		"",
+		"Name of the environment to create for the test. Defaults to a name generated from the current time.",
+	)
+	cmd.Flags().StringVar(&flags.subscription, "subscription", "", "ID of the Azure subscription to test the template in.")
+	cmd.Flags().StringVarP(&flags.location, "location", "l", "", "Azure location to test the template in.")
+	cmd.Flags().BoolVar(
+		&flags.keep,
+		"keep",
+		false,
+		"Keeps the Azure resources and the environment after the test, instead of deleting them.",
+	)
+	cmd.Flags().StringVar(&flags.report, "report", "", "Path of a file to write the test report to, as JSON.")
+
+	return flags
+}
+
+func newTemplateTestCmd() *cobra.Command {
+	return &cobra.Command{
+		Use: "test",
+		Short: fmt.Sprintf(
+			"Provision the template in a new environment, run its smoke tests and tear it down. %s",
+			output.WithWarningFormat("(Beta)"),
+		),
+		Args: cobra.NoArgs,
+	}
+}
+
+func getCmdTemplateTestHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Test the template in the current directory end to end: create a new environment, provision and deploy it "+
+			"with 'azd up', run the smoke tests declared in azure.yaml, then delete the Azure resources and the "+
+			"environment. Exits with an error when any step or smoke test fails.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("Smoke tests are declared in the %s section of %s. A smoke test either "+
+				"requests a %s until it succeeds, or runs a script with %s like a hook.",
+				output.WithHighLightFormat("test.smoke"),
+				output.WithHighLightFormat("azure.yaml"),
+				output.WithHighLightFormat("url"),
+				output.WithHighLightFormat("run"))),
+			formatHelpNote("The report includes how long each step took and the resources that were deployed."),
+			formatHelpNote(fmt.Sprintf("Resources are deleted even when a step fails, unless %s is set.",
+				output.WithHighLightFormat("--keep"))),
+		})
+}
+
+func getCmdTemplateTestHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Test the template in a sandbox subscription.": output.WithHighLightFormat(
+			"azd template test --subscription <subscription id> --location eastus2 --no-prompt",
+		),
+		"Test the template and save the report.": output.WithHighLightFormat(
+			"azd template test --report report.json",
+		),
+	})
+}
+
+type templateTestAction struct {
+	flags           *templateTestFlags
+	projectConfig   *project.ProjectConfig
+	azdCtx          *azdcontext.AzdContext
+	envManager      environment.Manager
+	workflowRunner  *workflow.Runner
+	resourceManager infra.ResourceManager
+	resourceService *azapi.ResourceService
+	commandRunner   exec.CommandRunner
+	transporter     policy.Transporter
+	serviceLocator  ioc.ServiceLocator
+	console         input.Console
+	formatter       output.Formatter
+	writer          io.Writer
+}
+
+func newTemplateTestAction(
+	flags *templateTestFlags,
+	projectConfig *project.ProjectConfig,
+	azdCtx *azdcontext.AzdContext,
+	envManager environment.Manager,
+	workflowRunner *workflow.Runner,
+	resourceManager infra.ResourceManager,
+	resourceService *azapi.ResourceService,
+	commandRunner exec.CommandRunner,
+	transporter policy.Transporter,
+	serviceLocator ioc.ServiceLocator,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+) actions.Action {
+	return &templateTestAction{
+		flags:           flags,
+		projectConfig:   projectConfig,
+		azdCtx:          azdCtx,
+		envManager:      envManager,
+		workflowRunner:  workflowRunner,
+		resourceManager: resourceManager,
+		resourceService: resourceService,
+		commandRunner:   commandRunner,
+		transporter:     transporter,
+		serviceLocator:  serviceLocator,
+		console:         console,
+		formatter:       formatter,
+		writer:          writer,
+	}
+}
+
+func (a *templateTestAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	envName := a.flags.envName
+	if envName == "" {
+		envName = "azdtest-" + time.Now().UTC().Format("20060102150405")
+	}
+
+	if !environment.IsValidEnvironmentName(envName) {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("'%s' isn't a valid environment name: %w", envName, internal.ErrInvalidArgValue),
+			Suggestion: "Use a name of at most 64 letters, digits, '-', '_' and '.', " +
+				"or leave --env-name out to generate one.",
+		}
+	}
+
+	// The environment is deleted at the end of the test, so it can't be one the user already has.
+	if _, err := a.envManager.Get(ctx, envName); err == nil {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("environment '%s' already exists: %w", envName, internal.ErrInvalidArgValue),
+			Suggestion: "Pass the name of a new environment with --env-name, " +
+				"or leave it out to generate one.",
+		}
+	} else if !errors.Is(err, environment.ErrNotFound) {
+		return nil, fmt.Errorf("checking environment '%s': %w", envName, err)
+	}
+
+	showUx := a.formatter.Kind() == output.NoneFormat
+	if showUx {
+		a.console.MessageUxItem(ctx, &ux.MessageTitle{
+			Title: "Testing template (azd template test)",
+			TitleNote: fmt.Sprintf(
+				"Creates the environment %s, runs the smoke tests and deletes everything it created.",
+				output.WithHighLightFormat(envName)),
+		})
+	}
+
+	// `env new` selects the new environment as the default one, which is restored once the test is done.
+	previousDefault, err := a.azdCtx.GetDefaultEnvironmentName()
+	if err != nil {
+		return nil, fmt.Errorf("getting default environment: %w", err)
+	}
+
+	start := time.Now()
+	report := &contracts.TemplateTestReport{
+		Environment:  envName,
+		Subscription: a.flags.subscription,
+		Location:     a.flags.location,
+		Steps:        []contracts.TemplateTestResult{},
+		SmokeTests:   []contracts.TemplateTestResult{},
+		Resources:    []contracts.TemplateTestResource{},
+	}
+
+	envNewArgs := []string{"env", "new", envName}
+	if a.flags.subscription != "" {
+		envNewArgs = append(envNewArgs, "--subscription", a.flags.subscription)
+	}
+	if a.flags.location != "" {
+		envNewArgs = append(envNewArgs, "--location", a.flags.location)
+	}
+
+	created := a.runStep(ctx, report, "Create environment", envNewArgs...)
+	if created && a.runStep(ctx, report, "Provision and deploy", "up", "-e", envName) {
+		if env, err := a.envManager.Get(ctx, envName); err != nil {
+			report.Steps = append(report.Steps, contracts.TemplateTestResult{
+				Name:  "Load environment",
+				Error: err.Error(),
+			})
+		} else {
+			report.Subscription = env.GetSubscriptionId()
+			report.Location = env.GetLocation()
+			a.runSmokeTests(ctx, report, env)
+			a.snapshotResources(ctx, report, env)
+		}
+	}
+
+	if created && !a.flags.keep {
+		// Tear down even when the test is canceled, so it doesn't leave resources behind.
+		teardownCtx := context.WithoutCancel(ctx)
+		if a.runStep(teardownCtx, report, "Tear down", "down", "-e", envName, "--force", "--purge") {
+			a.runStep(teardownCtx, report, "Remove environment", "env", "remove", envName, "--force")
+		}
+	}
+
+	if previousDefault != "" {
+		if err := a.azdCtx.SetProjectState(azdcontext.ProjectState{DefaultEnvironment: previousDefault}); err != nil {
+			log.Printf("template test: restoring default environment '%s': %v", previousDefault, err)
+		}
+	}
+
+	report.DurationSeconds = time.Since(start).Seconds()
+	report.Passed = !slices.ContainsFunc(report.Steps, templateTestFailed) &&
+		!slices.ContainsFunc(report.SmokeTests, templateTestFailed)
+
+	if a.flags.report != "" {
+		reportJson, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("marshalling report: %w", err)
+		}
+
+		if err := os.WriteFile(a.flags.report, reportJson, osutil.PermissionFile); err != nil {
+			return nil, fmt.Errorf("writing report: %w", err)
+		}
+	}
+
+	if showUx {
+		a.console.Message(ctx, "")
+		a.console.MessageUxItem(ctx, &ux.TemplateTestReport{Report: report})
+	} else if err := a.formatter.Format(report, a.writer, nil); err != nil {
+		return nil, fmt.Errorf("formatting report: %w", err)
+	}
+
+	if !report.Passed {
+		suggestion := "Check the output of the failed step or smoke test above."
+		if created && !a.flags.keep && slices.ContainsFunc(report.Steps, func(result contracts.TemplateTestResult) bool {
+			return result.Name == "Tear down" && !result.Passed
+		}) {
+			suggestion = fmt.Sprintf("The resources weren't deleted. Run 'azd down -e %s --force --purge' "+
+				"to delete them.", envName)
+		}
+
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        fmt.Errorf("template test in environment '%s': %w", envName, internal.ErrTemplateTestFailed),
+			Suggestion: suggestion,
+		}
+	}
+
+	if !showUx {
+		return nil, nil
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("The template passed the test in %s.",
+				ux.DurationAsText(time.Duration(report.DurationSeconds*float64(time.Second)))),
+		},
+	}, nil
+}
+
+func templateTestFailed(result contracts.TemplateTestResult) bool {
+	return !result.Passed
+}
+
+// runStep runs an azd command as a step of the test and records its result. It returns whether the command succeeded.
+func (a *templateTestAction) runStep(
+	ctx context.Context, report *contracts.TemplateTestReport, name string, args ...string,
+) bool {
+	start := time.Now()
+	err := a.workflowRunner.Run(ctx, &workflow.Workflow{
+		Name:  name,
+		Steps: []*workflow.Step{workflow.NewAzdCommandStep(args...)},
+	})
+
+	result := contracts.TemplateTestResult{
+		Name:            name,
+		Passed:          err == nil,
+		DurationSeconds: time.Since(start).Seconds(),
+	}
+	if err != nil {
+		log.Printf("template test: step '%s' failed: %v", name, err)
+		result.Error = err.Error()
+	}
+
+	report.Steps = append(report.Steps, result)
+	return err == nil
+}
+
+// runSmokeTests runs the smoke tests declared in azure.yaml, in order. A failed smoke test doesn't stop the next ones.
+func (a *templateTestAction) runSmokeTests(
+	ctx context.Context, report *contracts.TemplateTestReport, env *environment.Environment,
+) {
+	if a.projectConfig.Test == nil {
+		return
+	}
+
+	for _, smoke := range a.projectConfig.Test.Smoke {
+		start := time.Now()
+		err := a.runSmokeTest(ctx, smoke, env)
+
+		result := contracts.TemplateTestResult{
+			Name:            smoke.Name,
+			Passed:          err == nil,
+			DurationSeconds: time.Since(start).Seconds(),
+		}
+		if err != nil {
+			log.Printf("template test: smoke test '%s' failed: %v", smoke.Name, err)
+			result.Error = err.Error()
+		}
+
+		report.SmokeTests = append(report.SmokeTests, result)
+	}
+}
+
+func (a *templateTestAction) runSmokeTest(
+	ctx context.Context, smoke *project.SmokeTestConfig, env *environment.Environment,
+) error {
+	if smoke.IsUrl() {
+		target, err := smoke.Url.Envsubst(env.Getenv)
+		if err != nil {
+			return fmt.Errorf("expanding url: %w", err)
+		}
+
+		if !isHttpUrl(target) {
+			return fmt.Errorf("'%s' isn't an http or https URL", target)
+		}
+
+		timeout, err := smoke.RequestTimeout()
+		if err != nil {
+			return err
+		}
+
+		waiter := &waitAction{
+			transporter: a.transporter,
+			console:     a.console,
+			flags:       &waitFlags{timeout: timeout, interval: smokeTestInterval},
+		}
+
+		waitCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		return waiter.waitFor(ctx, waitCtx, waitCondition{kind: waitForUrl, target: target})
+	}
+
+	// The hooks runner names the hooks it runs, so it gets a copy to keep the name of the smoke test.
+	hook := smoke.HookConfig
+	hooksMap := map[string][]*ext.HookConfig{
+		smokeTestHookName: {&hook},
+	}
+
+	hooksManager := ext.NewHooksManager(ext.HooksManagerOptions{
+		Cwd: a.projectConfig.Path, ProjectDir: a.projectConfig.Path,
+	}, a.commandRunner)
+	hooksRunner := ext.NewHooksRunner(
+		hooksManager, a.commandRunner, a.envManager, a.console, a.projectConfig.Path, hooksMap, env, a.serviceLocator)
+
+	return hooksRunner.RunHooks(ctx, ext.HookTypeNone, "project", nil, smokeTestHookName)
+}
+
+// snapshotResources records how many resources of each type the environment deployed, before they're deleted.
+// Listing the resources is best effort: a failure leaves the snapshot empty rather than failing the test.
+func (a *templateTestAction) snapshotResources(
+	ctx context.Context, report *contracts.TemplateTestReport, env *environment.Environment,
+) {
+	subscriptionId := env.GetSubscriptionId()
+	groups, err := a.resourceManager.GetResourceGroupsForEnvironment(ctx, subscriptionId, env.Name())
+	if err != nil {
+		log.Printf("template test: listing resource groups: %v", err)
+		return
+	}
+
+	counts := map[string]int{}
+	for _, group := range groups {
+		resources, err := a.resourceService.ListResourceGroupResources(ctx, subscriptionId, group.Name, nil)
+		if err != nil {
+			log.Printf("template test: listing resources of resource group '%s': %v", group.Name, err)
+			continue
+		}
+
+		for _, resource := range resources {
+			counts[resource.Type]++
+		}
+	}
+
+	for _, resourceType := range slices.Sorted(maps.Keys(counts)) {
+		report.Resources = append(report.Resources, contracts.TemplateTestResource{
+			Type:  resourceType,
+			Count: counts[resourceType],
+		})
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/ext"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/workflow"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/stretchr/testify/require"
+)
+
+// templateTestCommandRunner fakes the azd commands run by `azd template test`, recording their arguments.
+type templateTestCommandRunner struct {
+	envManager environment.Manager
+	azdCtx     *azdcontext.AzdContext
+	// endpoint is set as the SERVICE_WEB_URI output of the environment by `azd up`.
+	endpoint string
+	// failing is the name of the command that fails.
+	failing  string
+	commands []string
+}
+
+func (r *templateTestCommandRunner) ExecuteContext(ctx context.Context, args []string) error {
+	r.commands = append(r.commands, strings.Join(args, " "))
+	if args[0] == r.failing {
+		return errors.New("command failed")
+	}
+
+	switch args[0] {
+	case "env":
+		if args[1] == "remove" {
+			return r.envManager.Delete(ctx, args[2])
+		}
+
+		if _, err := r.envManager.Create(ctx, environment.Spec{Name: args[2]}); err != nil {
+			return err
+		}
+
+		return r.azdCtx.SetProjectState(azdcontext.ProjectState{DefaultEnvironment: args[2]})
+	case "up":
+		env, err := r.envManager.Get(ctx, args[2])
+		if err != nil {
+			return err
+		}
+
+		env.DotenvSet("SERVICE_WEB_URI", r.endpoint)
+		env.DotenvSet(environment.SubscriptionIdEnvVarName, "SUBSCRIPTION_ID")
+		return r.envManager.Save(ctx, env)
+	}
+
+	return nil
+}
+
+// noResourceGroupsManager is a resource manager that finds no resource groups for the environment.
+type noResourceGroupsManager struct {
+	infra.ResourceManager
+}
+
+func (m *noResourceGroupsManager) GetResourceGroupsForEnvironment(
+	ctx context.Context, subscriptionId string, envName string,
+) ([]*azapi.Resource, error) {
+	return nil, errors.New("no resource groups")
+}
+
+func TestTemplateTestAction(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name        string
+		flags       templateTestFlags
+		failing     string
+		smoke       []*project.SmokeTestConfig
+		wantErr     error
+		wantCommand []string
+		wantSmoke   []bool
+	}{
+		{
+			name:  "Passed",
+			flags: templateTestFlags{envName: "azdtest", subscription: "SUBSCRIPTION_ID", location: "eastus2"},
+			smoke: []*project.SmokeTestConfig{
+				{Url: osutil.NewExpandableString("${SERVICE_WEB_URI}/health"), HookConfig: ext.HookConfig{Name: "web"}},
+			},
+			wantCommand: []string{
+				"env new azdtest --subscription SUBSCRIPTION_ID --location eastus2",
+				"up -e azdtest",
+				"down -e azdtest --force --purge",
+				"env remove azdtest --force",
+			},
+			wantSmoke: []bool{true},
+		},
+		{
+			name:  "SmokeTestFailed",
+			flags: templateTestFlags{envName: "azdtest"},
+			smoke: []*project.SmokeTestConfig{
+				{Url: osutil.NewExpandableString("${SERVICE_WEB_URI}"), HookConfig: ext.HookConfig{Name: "web"}},
+				// The output isn't set, so the URL is empty.
+				{Url: osutil.NewExpandableString("${SERVICE_API_URI}"), HookConfig: ext.HookConfig{Name: "api"}},
+			},
+			wantErr: internal.ErrTemplateTestFailed,
+			wantCommand: []string{
+				"env new azdtest",
+				"up -e azdtest",
+				"down -e azdtest --force --purge",
+				"env remove azdtest --force",
+			},
+			wantSmoke: []bool{true, false},
+		},
+		{
+			name:    "UpFailed",
+			flags:   templateTestFlags{envName: "azdtest"},
+			failing: "up",
+			smoke: []*project.SmokeTestConfig{
+				{Url: osutil.NewExpandableString("${SERVICE_WEB_URI}"), HookConfig: ext.HookConfig{Name: "web"}},
+			},
+			wantErr: internal.ErrTemplateTestFailed,
+			wantCommand: []string{
+				"env new azdtest",
+				"up -e azdtest",
+				"down -e azdtest --force --purge",
+				"env remove azdtest --force",
+			},
+			wantSmoke: []bool{},
+		},
+		{
+			name:        "Keep",
+			flags:       templateTestFlags{envName: "azdtest", keep: true},
+			wantCommand: []string{"env new azdtest", "up -e azdtest"},
+			wantSmoke:   []bool{},
+		},
+		{
+			name:        "ExistingEnvironment",
+			flags:       templateTestFlags{envName: "dev"},
+			wantErr:     internal.ErrInvalidArgValue,
+			wantCommand: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			azdCtx, envManager, projectDir := setupTestEnvironment(t, "dev", map[string]any{})
+			require.NoError(t, azdCtx.SetProjectState(azdcontext.ProjectState{DefaultEnvironment: "dev"}))
+
+			runner := &templateTestCommandRunner{
+				envManager: envManager,
+				azdCtx:     azdCtx,
+				endpoint:   server.URL,
+				failing:    tt.failing,
+			}
+			console := mockinput.NewMockConsole()
+			flags := tt.flags
+			flags.report = filepath.Join(t.TempDir(), "report.json")
+			projectConfig := &project.ProjectConfig{Path: projectDir, Test: &project.TestConfig{Smoke: tt.smoke}}
+
+			action := newTemplateTestAction(
+				&flags,
+				projectConfig,
+				azdCtx,
+				envManager,
+				workflow.NewRunner(runner, console),
+				&noResourceGroupsManager{},
+				nil,
+				nil,
+				server.Client(),
+				nil,
+				console,
+				&output.NoneFormatter{},
+				nil,
+			)
+
+			_, err := action.Run(t.Context())
+			require.Equal(t, tt.wantCommand, runner.commands)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			if tt.wantCommand == nil {
+				return
+			}
+
+			// The environment selected before the test is selected again.
+			defaultEnv, err := azdCtx.GetDefaultEnvironmentName()
+			require.NoError(t, err)
+			require.Equal(t, "dev", defaultEnv)
+
+			reportJson, err := os.ReadFile(flags.report)
+			require.NoError(t, err)
+
+			var report contracts.TemplateTestReport
+			require.NoError(t, json.Unmarshal(reportJson, &report))
+			require.Equal(t, tt.wantErr == nil, report.Passed)
+			require.Len(t, report.Steps, len(tt.wantCommand))
+
+			smokePassed := []bool{}
+			for _, smoke := range report.SmokeTests {
+				smokePassed = append(smokePassed, smoke.Passed)
+			}
+			require.Equal(t, tt.wantSmoke, smokePassed)
+		})
+	}
+}
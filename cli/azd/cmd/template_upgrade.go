@@ -0,0 +1,93 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal/repository"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/spf13/cobra"
+)
+
+func newTemplateUpgradeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use: "upgrade",
+		Short: fmt.Sprintf(
+			"Apply the changes of the template of the project to its infrastructure and azure.yaml. %s",
+			output.WithWarningFormat("(Beta)"),
+		),
+		Args: cobra.NoArgs,
+	}
+}
+
+func getCmdTemplateUpgradeHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Apply the changes made to the template of the project, since the project was initialized or last upgraded, "+
+			"to the infrastructure and azure.yaml of the project.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("The template version is recorded in the %s section of %s by %s.",
+				output.WithHighLightFormat("metadata.templateSource"),
+				output.WithHighLightFormat("azure.yaml"),
+				output.WithHighLightFormat("azd init --template"))),
+			formatHelpNote("Files that were also changed locally can be merged with the changes of the template. " +
+				"Merge conflicts are marked in the files."),
+		})
+}
+
+type templateUpgradeAction struct {
+	console         input.Console
+	azdCtx          *azdcontext.AzdContext
+	repoInitializer *repository.Initializer
+}
+
+func newTemplateUpgradeAction(
+	console input.Console,
+	azdCtx *azdcontext.AzdContext,
+	repoInitializer *repository.Initializer,
+) actions.Action {
+	return &templateUpgradeAction{
+		console:         console,
+		azdCtx:          azdCtx,
+		repoInitializer: repoInitializer,
+	}
+}
+
+func (a *templateUpgradeAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	a.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title: "Upgrade the template of the project (azd template upgrade)",
+	})
+
+	result, err := a.repoInitializer.UpgradeTemplate(ctx, a.azdCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Updated) == 0 {
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: fmt.Sprintf("The project is up to date with its template (%s).", result.Commit),
+			},
+		}, nil
+	}
+
+	followUp := "Review the changes, then run `azd provision` to apply them."
+	if len(result.Conflicts) > 0 {
+		followUp = fmt.Sprintf("Resolve the merge conflicts marked in %s, then run `azd provision` to apply the "+
+			"changes.", strings.Join(result.Conflicts, ", "))
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header:   fmt.Sprintf("Upgraded the template of the project to %s.", result.Commit),
+			FollowUp: followUp,
+		},
+	}, nil
+}
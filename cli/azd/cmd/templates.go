@@ -48,14 +48,40 @@ func templatesActions(root *actions.ActionDescriptor) *actions.ActionDescriptor
 		DefaultFormat:  output.NoneFormat,
 	})
 
+	group.Add("test", &actions.ActionDescriptorOptions{
+		Command:        newTemplateTestCmd(),
+		ActionResolver: newTemplateTestAction,
+		FlagsResolver:  newTemplateTestFlags,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdTemplateTestHelpDescription,
+			Footer:      getCmdTemplateTestHelpFooter,
+		},
+		RequireLogin: true,
+	})
+
+	group.Add("upgrade", &actions.ActionDescriptorOptions{
+		Command:        newTemplateUpgradeCmd(),
+		ActionResolver: newTemplateUpgradeAction,
+		OutputFormats:  []output.Format{output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdTemplateUpgradeHelpDescription,
+		},
+	})
+
 	_ = templateSourceActions(group)
 
 	return group
 }
 
 type templateListFlags struct {
-	source string
-	tags   []string
+	source        string
+	tags          []string
+	languages     []string
+	azureServices []string
+	search        string
 }
 
 func newTemplateListFlags(cmd *cobra.Command) *templateListFlags {
@@ -69,6 +95,24 @@ func newTemplateListFlags(cmd *cobra.Command) *templateListFlags {
 		[]string{},
 		"The tag(s) used to filter template results. Supports comma-separated values.",
 	)
+	cmd.Flags().StringSliceVar(
+		&flags.languages,
+		"language",
+		[]string{},
+		"The programming language(s) used by the templates. Supports comma-separated values.",
+	)
+	cmd.Flags().StringSliceVar(
+		&flags.azureServices,
+		"azure-service",
+		[]string{},
+		"The Azure service(s) used by the templates. Supports comma-separated values.",
+	)
+	cmd.Flags().StringVar(
+		&flags.search,
+		"search",
+		"",
+		"Keywords to search for in the name, description and tags of the templates, like an architecture.",
+	)
 
 	return flags
 }
@@ -117,8 +161,11 @@ func newTemplateListAction(
 
 func (tl *templateListAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	options := &templates.ListOptions{
-		Source: tl.flags.source,
-		Tags:   tl.flags.tags,
+		Source:        tl.flags.source,
+		Tags:          tl.flags.tags,
+		Languages:     tl.flags.languages,
+		AzureServices: tl.flags.azureServices,
+		Search:        tl.flags.search,
 	}
 	listedTemplates, err := tl.templateManager.ListTemplates(ctx, options)
 	if err != nil {
@@ -290,6 +337,14 @@ func getCmdTemplateSourceAddHelpFooter(*cobra.Command) string {
 		"Add templates from a file path": output.WithHighLightFormat(
 			"azd template source add <key> --type file --location /path/to/templates.json",
 		),
+		"Add templates from a private Azure DevOps repository": output.WithHighLightFormat(
+			"azd template source add <key> --type azdo " +
+				"--location https://dev.azure.com/<org>/<project>/_git/<repo>?path=/templates.json",
+		),
+		"Add templates from a private Azure Storage blob": output.WithHighLightFormat(
+			"azd template source add <key> --type url " +
+				"--location https://<account>.blob.core.windows.net/<container>/templates.json",
+		),
 	})
 }
 
@@ -429,7 +484,7 @@ func newTemplateSourceAddFlags(cmd *cobra.Command) *templateSourceAddFlags {
 	flags := &templateSourceAddFlags{}
 
 	cmd.Flags().StringVarP(&flags.kind, "type", "t", "", "Kind of the template source. Supported types are "+
-		"'file', 'url' and 'gh'.")
+		"'file', 'url', 'gh' and 'azdo'.")
 	cmd.Flags().StringVarP(&flags.location, "location", "l", "", "Location of the template source. "+
 		"Required when using type flag.")
 	cmd.Flags().StringVarP(&flags.name, "name", "n", "", "Display name of the template source.")
@@ -480,7 +535,7 @@ func (a *templateSourceAddAction) Run(ctx context.Context) (*actions.ActionResul
 				Suggestion: fmt.Sprintf(
 					"For custom keys, supported types are %s."+
 						" To add the known source '%s', run 'azd template source add %s' without --type.",
-					ux.ListAsText([]string{"'file'", "'url'", "'gh'"}), a.flags.kind, a.flags.kind),
+					ux.ListAsText([]string{"'file'", "'url'", "'gh'", "'azdo'"}), a.flags.kind, a.flags.kind),
 			}
 		}
 	}
@@ -504,7 +559,7 @@ func (a *templateSourceAddAction) Run(ctx context.Context) (*actions.ActionResul
 						a.flags.kind, internal.ErrValidationFailed),
 					Suggestion: fmt.Sprintf(
 						"Supported source types are %s.",
-						ux.ListAsText([]string{"'file'", "'url'", "'gh'"})),
+						ux.ListAsText([]string{"'file'", "'url'", "'gh'", "'azdo'"})),
 				}
 			}
 
@@ -591,6 +646,9 @@ func getCmdTemplateHelpFooter(*cobra.Command) string {
 		"View the details of an azd template.": output.WithHighLightFormat(
 			"azd template show <template-name>",
 		),
+		"Search the azd templates written in Python that use Azure OpenAI for RAG.": output.WithHighLightFormat(
+			"azd template list --language python --azure-service openai --search rag",
+		),
 	})
 }
 
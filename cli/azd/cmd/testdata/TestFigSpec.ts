@@ -215,6 +215,12 @@ const completionSpec: Fig.Spec = {
 		{
 			name: ['add'],
 			description: 'Add a component to your project.',
+			options: [
+				{
+					name: ['--existing'],
+					description: 'Adds an existing resource of your subscription, which is connected to the services of the project without being provisioned.',
+				},
+			],
 		},
 		{
 			name: ['ai'],
@@ -5033,6 +5039,281 @@ const completionSpec: Fig.Spec = {
 			name: ['auth'],
 			description: 'Authenticate with Azure.',
 			subcommands: [
+				{
+					name: ['federation'],
+					description: 'Manage the federated credentials of service principals and managed identities.',
+					subcommands: [
+						{
+							name: ['create'],
+							description: 'Create a federated credential that trusts the tokens of a CI/CD system or an OIDC issuer.',
+							options: [
+								{
+									name: ['--audience'],
+									description: 'The audience of the tokens. Defaults to api://AzureADTokenExchange.',
+									args: [
+										{
+											name: 'audience',
+										},
+									],
+								},
+								{
+									name: ['--branch'],
+									description: 'The branch trusted for github and gitlab.',
+									args: [
+										{
+											name: 'branch',
+										},
+									],
+								},
+								{
+									name: ['--client-id'],
+									description: 'The application (client) ID or the display name of the service principal.',
+									args: [
+										{
+											name: 'client-id',
+										},
+									],
+								},
+								{
+									name: ['--github-environment'],
+									description: 'The GitHub environment trusted, instead of a branch.',
+									args: [
+										{
+											name: 'github-environment',
+										},
+									],
+								},
+								{
+									name: ['--identity'],
+									description: 'The resource ID of the user-assigned managed identity.',
+									args: [
+										{
+											name: 'identity',
+										},
+									],
+								},
+								{
+									name: ['--issuer'],
+									description: 'The URL of the issuer. Required for azdo and custom. Defaults to https://gitlab.com for gitlab.',
+									args: [
+										{
+											name: 'issuer',
+										},
+									],
+								},
+								{
+									name: ['--name'],
+									description: 'The name of the credential. Defaults to the subject, sanitized.',
+									args: [
+										{
+											name: 'name',
+										},
+									],
+								},
+								{
+									name: ['--provider'],
+									description: 'The provider of the tokens trusted by the credential (github, azdo, gitlab, custom).',
+									args: [
+										{
+											name: 'provider',
+										},
+									],
+								},
+								{
+									name: ['--pull-request'],
+									description: 'Trusts the pull request workflows of the GitHub repository.',
+								},
+								{
+									name: ['--repo'],
+									description: 'The repository: <owner>/<repo> for github, <group>/<project> for gitlab or <organization>/<project> for azdo.',
+									args: [
+										{
+											name: 'repo',
+										},
+									],
+								},
+								{
+									name: ['--service-connection'],
+									description: 'The Azure DevOps service connection trusted.',
+									args: [
+										{
+											name: 'service-connection',
+										},
+									],
+								},
+								{
+									name: ['--subject'],
+									description: 'The subject trusted by the credential. Required for custom. By default, built from the other flags.',
+									args: [
+										{
+											name: 'subject',
+										},
+									],
+								},
+								{
+									name: ['--subscription'],
+									description: 'The ID of a subscription in the tenant of the service principal. Defaults to AZURE_SUBSCRIPTION_ID, or the default subscription of azd.',
+									args: [
+										{
+											name: 'subscription',
+										},
+									],
+								},
+							],
+						},
+						{
+							name: ['list', 'ls'],
+							description: 'List the federated credentials of a service principal or a managed identity.',
+							options: [
+								{
+									name: ['--client-id'],
+									description: 'The application (client) ID or the display name of the service principal.',
+									args: [
+										{
+											name: 'client-id',
+										},
+									],
+								},
+								{
+									name: ['--identity'],
+									description: 'The resource ID of the user-assigned managed identity.',
+									args: [
+										{
+											name: 'identity',
+										},
+									],
+								},
+								{
+									name: ['--subscription'],
+									description: 'The ID of a subscription in the tenant of the service principal. Defaults to AZURE_SUBSCRIPTION_ID, or the default subscription of azd.',
+									args: [
+										{
+											name: 'subscription',
+										},
+									],
+								},
+							],
+						},
+						{
+							name: ['rotate'],
+							description: 'Replace the issuer and subject trusted by an existing federated credential.',
+							options: [
+								{
+									name: ['--audience'],
+									description: 'The audience of the tokens. Defaults to api://AzureADTokenExchange.',
+									args: [
+										{
+											name: 'audience',
+										},
+									],
+								},
+								{
+									name: ['--branch'],
+									description: 'The branch trusted for github and gitlab.',
+									args: [
+										{
+											name: 'branch',
+										},
+									],
+								},
+								{
+									name: ['--client-id'],
+									description: 'The application (client) ID or the display name of the service principal.',
+									args: [
+										{
+											name: 'client-id',
+										},
+									],
+								},
+								{
+									name: ['--github-environment'],
+									description: 'The GitHub environment trusted, instead of a branch.',
+									args: [
+										{
+											name: 'github-environment',
+										},
+									],
+								},
+								{
+									name: ['--identity'],
+									description: 'The resource ID of the user-assigned managed identity.',
+									args: [
+										{
+											name: 'identity',
+										},
+									],
+								},
+								{
+									name: ['--issuer'],
+									description: 'The URL of the issuer. Required for azdo and custom. Defaults to https://gitlab.com for gitlab.',
+									args: [
+										{
+											name: 'issuer',
+										},
+									],
+								},
+								{
+									name: ['--name'],
+									description: 'The name of the credential to rotate.',
+									args: [
+										{
+											name: 'name',
+										},
+									],
+								},
+								{
+									name: ['--provider'],
+									description: 'The provider of the tokens trusted by the credential (github, azdo, gitlab, custom).',
+									args: [
+										{
+											name: 'provider',
+										},
+									],
+								},
+								{
+									name: ['--pull-request'],
+									description: 'Trusts the pull request workflows of the GitHub repository.',
+								},
+								{
+									name: ['--repo'],
+									description: 'The repository: <owner>/<repo> for github, <group>/<project> for gitlab or <organization>/<project> for azdo.',
+									args: [
+										{
+											name: 'repo',
+										},
+									],
+								},
+								{
+									name: ['--service-connection'],
+									description: 'The Azure DevOps service connection trusted.',
+									args: [
+										{
+											name: 'service-connection',
+										},
+									],
+								},
+								{
+									name: ['--subject'],
+									description: 'The subject trusted by the credential. Required for custom. By default, built from the other flags.',
+									args: [
+										{
+											name: 'subject',
+										},
+									],
+								},
+								{
+									name: ['--subscription'],
+									description: 'The ID of a subscription in the tenant of the service principal. Defaults to AZURE_SUBSCRIPTION_ID, or the default subscription of azd.',
+									args: [
+										{
+											name: 'subscription',
+										},
+									],
+								},
+							],
+						},
+					],
+				},
 				{
 					name: ['login'],
 					description: 'Log in to Azure.',
@@ -5602,15 +5883,47 @@ const completionSpec: Fig.Spec = {
 					name: ['--all'],
 					description: 'Deploys all services that are listed in azure.yaml',
 				},
+				{
+					name: ['--force'],
+					description: 'Deploys all services, including the services whose package is unchanged since their last deploy.',
+					isDangerous: true,
+				},
 				{
 					name: ['--from-package'],
-					description: 'Deploys the packaged service located at the provided path. Supports zipped file packages (file path) or container images (image tag).',
+					description: 'Deploys the packaged service located at the provided path. Supports zipped file packages (file path), container images (image tag), packages pushed to the artifact store (sha256:<digest>/<file name>) or the package manifest written by azd package (JSON file path).',
 					args: [
 						{
 							name: 'file-path|image-tag',
 						},
 					],
 				},
+				{
+					name: ['--image'],
+					description: 'Deploys the service from an image in a container registry, referenced by digest (<registry>/<repository>@sha256:<digest>), without building or packaging it.',
+					args: [
+						{
+							name: 'image',
+						},
+					],
+				},
+				{
+					name: ['--refresh-outputs'],
+					description: 'Refreshes the environment from the provisioning outputs before deploying, so that services are deployed with up-to-date values. Can be enabled by default with \'azd config set deploy.refreshOutputs on\'.',
+				},
+				{
+					name: ['--rollback'],
+					description: 'Rolls the service back to the package that was deployed before its last deployment.',
+				},
+				{
+					name: ['--tag'],
+					description: 'Deploys the services with the tag. Prefix a tag with ! to skip the services with the tag. Can be repeated.',
+					isRepeatable: true,
+					args: [
+						{
+							name: 'tag',
+						},
+					],
+				},
 				{
 					name: ['--timeout'],
 					description: 'Maximum time in seconds for azd to wait for each service deployment. This stops azd from waiting but does not cancel the Azure-side deployment. (default: 1200)',
@@ -5635,6 +5948,16 @@ const completionSpec: Fig.Spec = {
 					description: 'Does not require confirmation before it deletes resources.',
 					isDangerous: true,
 				},
+				{
+					name: ['--keep'],
+					description: 'Keeps the resources with the given name, type or resource ID instead of deleting them (can be repeated).',
+					isRepeatable: true,
+					args: [
+						{
+							name: 'keep',
+						},
+					],
+				},
 				{
 					name: ['--purge'],
 					description: 'Does not require confirmation before it permanently deletes resources that are soft-deleted by default (for example, key vaults).',
@@ -5683,28 +6006,146 @@ const completionSpec: Fig.Spec = {
 					],
 				},
 				{
-					name: ['get-value'],
-					description: 'Get specific environment value.',
-					args: {
-						name: 'keyName',
-						generators: azdGenerators.listEnvironmentVariables,
-					},
-				},
-				{
-					name: ['get-values'],
-					description: 'Get all environment values.',
-				},
-				{
-					name: ['list', 'ls'],
-					description: 'List environments.',
-				},
-				{
-					name: ['new'],
-					description: 'Create a new environment and set it as the default.',
+					name: ['copy'],
+					description: 'Copy an environment to a new environment.',
 					options: [
+						{
+							name: ['--exclude-secrets'],
+							description: 'Leaves Key Vault secret references, secure outputs and secure parameters out of the new environment.',
+						},
 						{
 							name: ['--location', '-l'],
-							description: 'Azure location for the new environment',
+							description: 'Azure location for the new environment instead of the one of the source environment',
+							args: [
+								{
+									name: 'location',
+								},
+							],
+						},
+						{
+							name: ['--subscription'],
+							description: 'ID of an Azure subscription to use for the new environment instead of the one of the source environment',
+							args: [
+								{
+									name: 'subscription',
+								},
+							],
+						},
+						{
+							name: ['--secret'],
+							description: 'Marks the values as secrets, which are redacted from JSON output and encrypted when the env.encryptSecrets alpha feature is on.',
+						},
+						{
+							name: ['--type'],
+							description: 'Validates and normalizes the values as the given type, which azd env get-values --output json uses. Allowed values: string, int, bool, json.',
+							args: [
+								{
+									name: 'type',
+								},
+							],
+						},
+					],
+					args: [
+						{
+							name: 'source-environment',
+						},
+						{
+							name: 'new-environment',
+						},
+					],
+				},
+				{
+					name: ['copy'],
+					description: 'Copy an environment to a new environment.',
+					options: [
+						{
+							name: ['--exclude-secrets'],
+							description: 'Leaves Key Vault secret references, secure outputs and secure parameters out of the new environment.',
+						},
+						{
+							name: ['--location', '-l'],
+							description: 'Azure location for the new environment instead of the one of the source environment',
+							args: [
+								{
+									name: 'location',
+								},
+							],
+						},
+						{
+							name: ['--subscription'],
+							description: 'ID of an Azure subscription to use for the new environment instead of the one of the source environment',
+							args: [
+								{
+									name: 'subscription',
+								},
+							],
+						},
+					],
+					args: [
+						{
+							name: 'source-environment',
+							generators: azdGenerators.listEnvironments,
+						},
+						{
+							name: 'new-environment',
+						},
+					],
+				},
+				{
+					name: ['get-value'],
+					description: 'Get specific environment value.',
+					args: {
+						name: 'keyName',
+						generators: azdGenerators.listEnvironmentVariables,
+					},
+				},
+				{
+					name: ['get-values'],
+					description: 'Get all environment values.',
+					options: [
+						{
+							name: ['--show-secrets'],
+							description: 'Shows the values of secure provisioning outputs instead of redacting them when using --output json.',
+							isDangerous: true,
+						},
+						{
+							name: ['--show-source'],
+							description: 'Shows whether each value comes from the .env file of the environment or from the .azure/common.env file shared by all environments.',
+						},
+					],
+				},
+				{
+					name: ['list', 'ls'],
+					description: 'List environments.',
+					options: [
+						{
+							name: ['--filter'],
+							description: 'Lists only the environments with the tag, in the <tag>=<value> format. Can be repeated to match all the tags.',
+							isRepeatable: true,
+							args: [
+								{
+									name: 'filter',
+								},
+							],
+						},
+					],
+				},
+				{
+					name: ['new'],
+					description: 'Create a new environment and set it as the default.',
+					options: [
+						{
+							name: ['--expires-on'],
+							description: 'Date (2026-12-31) or time (2026-12-31T18:00:00Z) after which the new environment can be deleted by azd env prune',
+							args: [
+								{
+									name: 'expires-on',
+								},
+							],
+						},
+						{
+							name: ['--location', '-l'],
+							description: 'Azure location for the new environment',
 							args: [
 								{
 									name: 'location',
@@ -5725,10 +6166,30 @@ const completionSpec: Fig.Spec = {
 						name: 'environment',
 					},
 				},
+				{
+					name: ['prune'],
+					description: 'Delete expired environments and their Azure resources.',
+					options: [
+						{
+							name: ['--force'],
+							description: 'Deletes the expired environments and their Azure resources without confirmation.',
+							isDangerous: true,
+						},
+						{
+							name: ['--purge'],
+							description: 'Permanently deletes the soft-deleted resources of the expired environments, like Key Vaults and Cognitive Services accounts.',
+							isDangerous: true,
+						},
+					],
+				},
 				{
 					name: ['refresh'],
 					description: 'Refresh environment values by using information from a previous infrastructure provision.',
 					options: [
+						{
+							name: ['--check'],
+							description: 'Checks whether the environment values from provisioning outputs are out of date, without updating them. Fails when they are.',
+						},
 						{
 							name: ['--hint'],
 							description: 'Hint to help identify the environment to refresh',
@@ -5800,6 +6261,24 @@ const completionSpec: Fig.Spec = {
 						},
 					],
 				},
+				{
+					name: ['set-json'],
+					description: 'Set an environment value to a JSON document.',
+					options: [
+						{
+							name: ['--secret'],
+							description: 'Marks the value as a secret, which is redacted from JSON output and encrypted when the env.encryptSecrets alpha feature is on.',
+						},
+					],
+					args: [
+						{
+							name: 'key',
+						},
+						{
+							name: 'value',
+						},
+					],
+				},
 				{
 					name: ['set-secret'],
 					description: 'Set a name as a reference to a Key Vault secret in the environment.',
@@ -5807,6 +6286,65 @@ const completionSpec: Fig.Spec = {
 						name: 'name',
 					},
 				},
+				{
+					name: ['snapshot'],
+					description: 'Manage point in time snapshots of environment values and configuration.',
+					subcommands: [
+						{
+							name: ['create'],
+							description: 'Create a snapshot of the environment values and configuration.',
+							options: [
+								{
+									name: ['--description', '-d'],
+									description: 'A description of the snapshot.',
+									args: [
+										{
+											name: 'description',
+										},
+									],
+								},
+							],
+						},
+						{
+							name: ['list', 'ls'],
+							description: 'List the snapshots of the environment.',
+						},
+						{
+							name: ['restore'],
+							description: 'Restore the environment values and configuration from a snapshot.',
+							options: [
+								{
+									name: ['--force'],
+									description: 'Skips confirmation before restoring the snapshot.',
+									isDangerous: true,
+								},
+							],
+							args: {
+								name: 'snapshot-id',
+							},
+						},
+					],
+				},
+				{
+					name: ['tag'],
+					description: 'Manage the tags of an environment, like its owner, purpose or expiry.',
+					subcommands: [
+						{
+							name: ['set'],
+							description: 'Set one or more tags of the environment.',
+							args: {
+								name: 'tag=value...',
+							},
+						},
+						{
+							name: ['unset'],
+							description: 'Remove one or more tags of the environment.',
+							args: {
+								name: 'tag...',
+							},
+						},
+					],
+				},
 			],
 		},
 		{
@@ -6047,6 +6585,48 @@ const completionSpec: Fig.Spec = {
 				},
 			],
 		},
+		{
+			name: ['flags'],
+			description: 'Manage the feature flags of your environments.',
+			subcommands: [
+				{
+					name: ['disable'],
+					description: 'Disable a feature flag.',
+					args: {
+						name: 'name',
+					},
+				},
+				{
+					name: ['enable'],
+					description: 'Enable a feature flag.',
+					args: {
+						name: 'name',
+					},
+				},
+				{
+					name: ['set'],
+					description: 'Create or update a feature flag.',
+					options: [
+						{
+							name: ['--description'],
+							description: 'The description of the feature flag.',
+							args: [
+								{
+									name: 'description',
+								},
+							],
+						},
+						{
+							name: ['--enabled'],
+							description: 'Enables the feature flag. When not set, new flags are created disabled and existing flags keep their state.',
+						},
+					],
+					args: {
+						name: 'name',
+					},
+				},
+			],
+		},
 		{
 			name: ['hooks'],
 			description: 'Develop, test and run hooks for a project.',
@@ -6111,6 +6691,14 @@ const completionSpec: Fig.Spec = {
 			name: ['infra'],
 			description: 'Manage your Infrastructure as Code (IaC).',
 			subcommands: [
+				{
+					name: ['diff'],
+					description: 'Compare the infrastructure templates with the deployed Azure resources.',
+					args: {
+						name: 'layer',
+						isOptional: true,
+					},
+				},
 				{
 					name: ['generate', 'gen', 'synth'],
 					description: 'Write IaC for your project to disk, allowing you to manually manage it.',
@@ -6120,6 +6708,16 @@ const completionSpec: Fig.Spec = {
 							description: 'Overwrite any existing files without prompting',
 							isDangerous: true,
 						},
+						{
+							name: ['--only'],
+							description: 'Writes only the given generated file of the infra directory, such as resources.bicep, for customization, while the rest of the infrastructure keeps being generated from azure.yaml. Can be repeated.',
+							isRepeatable: true,
+							args: [
+								{
+									name: 'only',
+								},
+							],
+						},
 					],
 				},
 			],
@@ -6152,6 +6750,15 @@ const completionSpec: Fig.Spec = {
 					name: ['--from-code'],
 					description: 'Initializes a new application from your existing code.',
 				},
+				{
+					name: ['--from-resource-group'],
+					description: 'Initializes a new application from the resources of an existing resource group.',
+					args: [
+						{
+							name: 'from-resource-group',
+						},
+					],
+				},
 				{
 					name: ['--location', '-l'],
 					description: 'Azure location for the new environment',
@@ -6165,6 +6772,10 @@ const completionSpec: Fig.Spec = {
 					name: ['--minimal', '-m'],
 					description: 'Initializes a minimal project.',
 				},
+				{
+					name: ['--refresh-services'],
+					description: 'Detects the projects of your existing code that aren\'t services yet, and adds them to azure.yaml.',
+				},
 				{
 					name: ['--subscription', '-s'],
 					description: 'ID of an Azure subscription to use for the new environment',
@@ -6190,6 +6801,29 @@ const completionSpec: Fig.Spec = {
 				},
 			],
 		},
+		{
+			name: ['logs'],
+			description: 'Stream the logs of deployed services.',
+			options: [
+				{
+					name: ['--follow', '-f'],
+					description: 'Keep streaming new logs until Ctrl+C is pressed.',
+				},
+				{
+					name: ['--since'],
+					description: 'Only show logs written within this duration, such as 10m or 2h. Function apps default to 1h.',
+					args: [
+						{
+							name: 'since',
+						},
+					],
+				},
+			],
+			args: {
+				name: 'service',
+				isOptional: true,
+			},
+		},
 		{
 			name: ['mcp'],
 			description: 'Manage Model Context Protocol (MCP) server. (Alpha)',
@@ -6216,6 +6850,15 @@ const completionSpec: Fig.Spec = {
 					name: ['--overview'],
 					description: 'Open a browser to Application Insights Overview Dashboard.',
 				},
+				{
+					name: ['--query'],
+					description: 'Run a predefined or azure.yaml KQL query, such as requests, failures, exceptions, traces or dependencies.',
+					args: [
+						{
+							name: 'query',
+						},
+					],
+				},
 			],
 		},
 		{
@@ -6226,6 +6869,15 @@ const completionSpec: Fig.Spec = {
 					name: ['--all'],
 					description: 'Packages all services that are listed in azure.yaml',
 				},
+				{
+					name: ['--manifest'],
+					description: 'File path where the package manifest is written. Defaults to azd-package.json in the --output-path folder.',
+					args: [
+						{
+							name: 'manifest',
+						},
+					],
+				},
 				{
 					name: ['--output-path'],
 					description: 'File or folder path where the generated packages will be saved.',
@@ -6235,6 +6887,24 @@ const completionSpec: Fig.Spec = {
 						},
 					],
 				},
+				{
+					name: ['--push'],
+					description: 'Pushes the generated packages to the artifact store configured in azure.yaml.',
+				},
+				{
+					name: ['--sbom'],
+					description: 'Generates a software bill of materials (SBOM) of each package with Syft.',
+				},
+				{
+					name: ['--tag'],
+					description: 'Packages the services with the tag. Prefix a tag with ! to skip the services with the tag. Can be repeated.',
+					isRepeatable: true,
+					args: [
+						{
+							name: 'tag',
+						},
+					],
+				},
 			],
 			args: {
 				name: 'service',
@@ -6245,6 +6915,21 @@ const completionSpec: Fig.Spec = {
 			name: ['pipeline'],
 			description: 'Manage and configure your deployment pipelines.',
 			subcommands: [
+				{
+					name: ['cleanup'],
+					description: 'Remove the pipeline identities of deleted repositories and environments. (Beta)',
+					options: [
+						{
+							name: ['--dry-run'],
+							description: 'Lists the identities that would be removed without removing them.',
+						},
+						{
+							name: ['--force'],
+							description: 'Skips confirmation before removing the identities.',
+							isDangerous: true,
+						},
+					],
+				},
 				{
 					name: ['config'],
 					description: 'Configure your deployment pipeline to connect securely to Azure. (Beta)',
@@ -6260,7 +6945,7 @@ const completionSpec: Fig.Spec = {
 						},
 						{
 							name: ['--auth-type'],
-							description: 'The authentication type used between the pipeline provider and Azure for deployment (Only valid for GitHub provider). Valid values: federated, client-credentials.',
+							description: 'The authentication type used between the pipeline provider and Azure for deployment (Only valid for GitHub and GitLab providers). Valid values: federated, client-credentials.',
 							args: [
 								{
 									name: 'auth-type',
@@ -6298,7 +6983,7 @@ const completionSpec: Fig.Spec = {
 						},
 						{
 							name: ['--provider'],
-							description: 'The pipeline provider to use (github for Github Actions and azdo for Azure Pipelines).',
+							description: 'The pipeline provider to use (github for Github Actions, azdo for Azure Pipelines and gitlab for GitLab CI/CD).',
 							args: [
 								{
 									name: 'provider',
@@ -6315,6 +7000,46 @@ const completionSpec: Fig.Spec = {
 								},
 							],
 						},
+						{
+							name: ['--stages'],
+							description: 'The stages of a multi-stage pipeline, in promotion order (e.g. dev,staging,prod). Each stage deploys the azd environment with the same name (Only valid for GitHub provider).',
+							isRepeatable: true,
+							args: [
+								{
+									name: 'stages',
+								},
+							],
+						},
+					],
+				},
+				{
+					name: ['run'],
+					description: 'Run your deployment pipeline on the current branch. (Beta)',
+					options: [
+						{
+							name: ['--branch'],
+							description: 'The branch to run the pipeline on. Defaults to the current branch.',
+							args: [
+								{
+									name: 'branch',
+								},
+							],
+						},
+					],
+				},
+				{
+					name: ['status'],
+					description: 'Show the status of the latest run of your deployment pipeline. (Beta)',
+					options: [
+						{
+							name: ['--branch'],
+							description: 'The branch of the pipeline run. Defaults to the current branch.',
+							args: [
+								{
+									name: 'branch',
+								},
+							],
+						},
 					],
 				},
 			],
@@ -6322,6 +7047,16 @@ const completionSpec: Fig.Spec = {
 		{
 			name: ['provision'],
 			description: 'Provision Azure resources for your project.',
+			subcommands: [
+				{
+					name: ['cancel'],
+					description: 'Cancel the in-progress Azure deployment for the current environment.',
+					args: {
+						name: 'layer',
+						isOptional: true,
+					},
+				},
+			],
 			options: [
 				{
 					name: ['--location', '-l'],
@@ -6349,6 +7084,10 @@ const completionSpec: Fig.Spec = {
 						},
 					],
 				},
+				{
+					name: ['--timings'],
+					description: '(Bicep only) Displays how long each resource and module took to deploy, slowest first.',
+				},
 			],
 			args: {
 				name: 'layer',
@@ -6395,6 +7134,80 @@ const completionSpec: Fig.Spec = {
 					name: ['--all'],
 					description: 'Restores all services that are listed in azure.yaml',
 				},
+				{
+					name: ['--allow-tool-version-mismatch'],
+					description: 'Continues when a tool pinned by azd.lock can\'t be installed at its locked version, instead of failing.',
+				},
+				{
+					name: ['--update-lock'],
+					description: 'Writes azd.lock with the extensions, tools and template used by the project, instead of restoring the ones it pins.',
+				},
+			],
+			args: {
+				name: 'service',
+				isOptional: true,
+			},
+		},
+		{
+			name: ['run'],
+			description: 'Run the services of the project locally.',
+			options: [
+				{
+					name: ['--expose'],
+					description: 'Expose a service with a public URL through a dev tunnel. Can be repeated.',
+					isRepeatable: true,
+					args: [
+						{
+							name: 'expose',
+						},
+					],
+				},
+				{
+					name: ['--ready-timeout'],
+					description: 'How long to wait for a service with a run.port to accept connections before starting the services that use it, and for the dev tunnels of exposed services to start.',
+					args: [
+						{
+							name: 'ready-timeout',
+						},
+					],
+				},
+			],
+			args: {
+				name: 'service',
+				isOptional: true,
+			},
+		},
+		{
+			name: ['shell'],
+			description: 'Open a shell in a running instance of a deployed service.',
+			options: [
+				{
+					name: ['--command'],
+					description: 'The command to run, such as /bin/bash or \'python manage.py shell\'.',
+					args: [
+						{
+							name: 'command',
+						},
+					],
+				},
+				{
+					name: ['--container'],
+					description: 'The container to run the command in. Defaults to the container named after the app, or the first one.',
+					args: [
+						{
+							name: 'container',
+						},
+					],
+				},
+				{
+					name: ['--instance'],
+					description: 'The replica of the container app or the pod to run the command in. Defaults to the first one.',
+					args: [
+						{
+							name: 'instance',
+						},
+					],
+				},
 			],
 			args: {
 				name: 'service',
@@ -6405,6 +7218,10 @@ const completionSpec: Fig.Spec = {
 			name: ['show'],
 			description: 'Display information about your project and its resources.',
 			options: [
+				{
+					name: ['--live'],
+					description: 'Queries the resources of the services for their current state, replicas, endpoints and last deployment time.',
+				},
 				{
 					name: ['--show-secrets'],
 					description: 'Unmask secrets in output.',
@@ -6424,6 +7241,16 @@ const completionSpec: Fig.Spec = {
 					name: ['list', 'ls'],
 					description: 'Show list of sample azd templates. (Beta)',
 					options: [
+						{
+							name: ['--azure-service'],
+							description: 'The Azure service(s) used by the templates. Supports comma-separated values.',
+							isRepeatable: true,
+							args: [
+								{
+									name: 'azure-service',
+								},
+							],
+						},
 						{
 							name: ['--filter', '-f'],
 							description: 'The tag(s) used to filter template results. Supports comma-separated values.',
@@ -6435,6 +7262,25 @@ const completionSpec: Fig.Spec = {
 								},
 							],
 						},
+						{
+							name: ['--language'],
+							description: 'The programming language(s) used by the templates. Supports comma-separated values.',
+							isRepeatable: true,
+							args: [
+								{
+									name: 'language',
+								},
+							],
+						},
+						{
+							name: ['--search'],
+							description: 'Keywords to search for in the name, description and tags of the templates, like an architecture.',
+							args: [
+								{
+									name: 'search',
+								},
+							],
+						},
 						{
 							name: ['--source', '-s'],
 							description: 'Filters templates by source.',
@@ -6482,7 +7328,7 @@ const completionSpec: Fig.Spec = {
 								},
 								{
 									name: ['--type', '-t'],
-									description: 'Kind of the template source. Supported types are \'file\', \'url\' and \'gh\'.',
+									description: 'Kind of the template source. Supported types are \'file\', \'url\', \'gh\' and \'azdo\'.',
 									args: [
 										{
 											name: 'type',
@@ -6507,10 +7353,60 @@ const completionSpec: Fig.Spec = {
 						},
 					],
 				},
+				{
+					name: ['test'],
+					description: 'Provision the template in a new environment, run its smoke tests and tear it down. (Beta)',
+					options: [
+						{
+							name: ['--env-name'],
+							description: 'Name of the environment to create for the test. Defaults to a name generated from the current time.',
+							args: [
+								{
+									name: 'env-name',
+								},
+							],
+						},
+						{
+							name: ['--keep'],
+							description: 'Keeps the Azure resources and the environment after the test, instead of deleting them.',
+						},
+						{
+							name: ['--location', '-l'],
+							description: 'Azure location to test the template in.',
+							args: [
+								{
+									name: 'location',
+								},
+							],
+						},
+						{
+							name: ['--report'],
+							description: 'Path of a file to write the test report to, as JSON.',
+							args: [
+								{
+									name: 'report',
+								},
+							],
+						},
+						{
+							name: ['--subscription'],
+							description: 'ID of the Azure subscription to test the template in.',
+							args: [
+								{
+									name: 'subscription',
+								},
+							],
+						},
+					],
+				},
+				{
+					name: ['upgrade'],
+					description: 'Apply the changes of the template of the project to its infrastructure and azure.yaml. (Beta)',
+				},
 			],
 		},
 		{
-			name: ['tool'],
+			name: ['tool', 'tools'],
 			description: 'Manage Azure development tools.',
 			subcommands: [
 				{
@@ -6636,6 +7532,10 @@ const completionSpec: Fig.Spec = {
 						},
 					],
 				},
+				{
+					name: ['--watch'],
+					description: 'After provisioning and deploying, watches the projects of the services and redeploys the services that change, until Ctrl+C is pressed.',
+				},
 			],
 		},
 		{
@@ -6666,6 +7566,44 @@ const completionSpec: Fig.Spec = {
 			name: ['version'],
 			description: 'Print the version number of Azure Developer CLI.',
 		},
+		{
+			name: ['wait'],
+			description: 'Wait for provisioning outputs, endpoints or resources to be ready.',
+			options: [
+				{
+					name: ['--for'],
+					description: 'A condition to wait for: output:<name>, url:<url> or resource:<resource id>[=<state>]. Can be repeated.',
+					isRepeatable: true,
+					args: [
+						{
+							name: 'for',
+						},
+					],
+				},
+				{
+					name: ['--healthy'],
+					description: 'Also wait for outputs that are URLs to respond with a success status code.',
+				},
+				{
+					name: ['--interval'],
+					description: 'How long to wait between checks.',
+					args: [
+						{
+							name: 'interval',
+						},
+					],
+				},
+				{
+					name: ['--timeout'],
+					description: 'How long to wait for all conditions before failing.',
+					args: [
+						{
+							name: 'timeout',
+						},
+					],
+				},
+			],
+		},
 		{
 			name: ['x'],
 			description: 'This extension provides a set of tools for azd extension developers to test and debug their extensions.',
@@ -6937,6 +7875,16 @@ const completionSpec: Fig.Spec = {
 		},
 	],
 	options: [
+		{
+			name: ['--answers'],
+			description: 'Answers prompts from a YAML file mapping prompt keys, like subscription, location or parameters.<name>, to values. Defaults to the AZD_ANSWERS_FILE environment variable.',
+			isPersistent: true,
+			args: [
+				{
+					name: 'answers',
+				},
+			],
+		},
 		{
 			name: ['--cwd', '-C'],
 			description: 'Sets the current working directory.',
@@ -6967,6 +7915,26 @@ const completionSpec: Fig.Spec = {
 			description: 'Runs without prompts. Uses existing values; fails if any required value or decision cannot be resolved automatically. Automatically enabled when azd detects a CI/CD or AI-agent environment; set AZD_NON_INTERACTIVE=false to opt out of that automatic enablement.',
 			isPersistent: true,
 		},
+		{
+			name: ['--prompt-timeout'],
+			description: 'Waits at most the given number of seconds for the answer to a prompt, then uses its default value, or fails when it has none. Defaults to the AZD_PROMPT_TIMEOUT environment variable.',
+			isPersistent: true,
+			args: [
+				{
+					name: 'prompt-timeout',
+				},
+			],
+		},
+		{
+			name: ['--quiet'],
+			description: 'Only writes the result of the command and errors.',
+			isPersistent: true,
+		},
+		{
+			name: ['--verbose'],
+			description: 'Writes more details, like the full output of tools and every provisioned resource.',
+			isPersistent: true,
+		},
 		{
 			name: ['--docs'],
 			description: 'Opens the documentation for azd in your web browser.',
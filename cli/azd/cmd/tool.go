@@ -91,9 +91,10 @@ func emitToolInstallTelemetry(
 // toolActions registers the "azd tool" command group and all of its subcommands.
 func toolActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 	toolCmd := &cobra.Command{
-		Use:   "tool",
-		Short: "Manage Azure development tools.",
-		Long:  "Discover, install, upgrade, and check status of Azure development tools.",
+		Use:     "tool",
+		Short:   "Manage Azure development tools.",
+		Aliases: []string{"tools"},
+		Long:    "Discover, install, upgrade, and check status of Azure development tools.",
 	}
 
 	group := root.Add("tool", &actions.ActionDescriptorOptions{
@@ -422,6 +423,9 @@ func (a *toolListAction) Run(ctx context.Context) (*actions.ActionResult, error)
 			status = "Installed"
 			version = s.InstalledVersion
 		}
+		if s.BelowMinVersion() {
+			status = fmt.Sprintf("Upgrade required (>= %s)", s.Tool.MinVersion)
+		}
 
 		rows = append(rows, toolListItem{
 			Id:          s.Tool.Id,
@@ -2571,6 +2575,9 @@ func toolStatusColor(s string) string {
 	case "Installed":
 		return output.WithSuccessFormat(s)
 	default:
+		if strings.HasPrefix(s, "Upgrade required") {
+			return output.WithWarningFormat(s)
+		}
 		// "Not installed" and any other state render in gray.
 		return output.WithGrayFormat(s)
 	}
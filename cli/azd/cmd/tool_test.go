@@ -49,6 +49,19 @@ func TestToolCommandGating(t *testing.T) {
 	require.True(t, found, "expected 'tool' subcommand to always be present")
 }
 
+func TestToolsAlias(t *testing.T) {
+	// "azd tools list" and "azd tools install" run the commands of the "tool" group.
+	t.Setenv("AZD_CONFIG_DIR", t.TempDir())
+
+	root := NewRootCmd(true, nil, nil)
+	for _, name := range []string{"list", "install"} {
+		cmd, _, err := root.Find([]string{"tools", name})
+		require.NoError(t, err)
+		require.Equal(t, name, cmd.Name())
+		require.Equal(t, "tool", cmd.Parent().Name())
+	}
+}
+
 func TestRunToolOperationUnsuccessfulResultReturnsError(t *testing.T) {
 	toolDef := &tool.ToolDefinition{
 		Id:   "az-cli",
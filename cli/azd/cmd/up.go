@@ -37,6 +37,7 @@ type upFlags struct {
 	// workflow runner that spawned `azd package` / `azd provision` as
 	// child processes — see UpGraphAction.Run).
 	flagSet *pflag.FlagSet
+	watch   bool
 }
 
 func (u *upFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
@@ -48,6 +49,14 @@ func (u *upFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptio
 	u.ProvisionFlags.SetCommon(&u.EnvFlag)
 	u.DeployFlags.BindNonCommon(local, global)
 	u.DeployFlags.SetCommon(&u.EnvFlag)
+
+	local.BoolVar(
+		&u.watch,
+		"watch",
+		false,
+		"After provisioning and deploying, watches the projects of the services and redeploys the services that change, "+
+			"until Ctrl+C is pressed.",
+	)
 }
 
 func newUpFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *upFlags {
@@ -187,15 +196,20 @@ func (u *upAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		if err := u.workflowRunner.Run(ctx, upWorkflow); err != nil {
 			return nil, err
 		}
-		return &actions.ActionResult{
+		return u.watchIfEnabled(ctx, &actions.ActionResult{
 			Message: &actions.ResultMessage{
 				Header: fmt.Sprintf("Your up workflow to provision and deploy to Azure completed in %s.",
 					ux.DurationAsText(since(startTime))),
 			},
-		}, nil
+		})
+	}
+
+	result, err := u.upGraph.Run(ctx, layers, &u.flags.DeployFlags, u.flags.flagSet, startTime)
+	if err != nil {
+		return result, err
 	}
 
-	return u.upGraph.Run(ctx, layers, &u.flags.DeployFlags, u.flags.flagSet, startTime)
+	return u.watchIfEnabled(ctx, result)
 }
 
 func getCmdUpHelpDescription(c *cobra.Command) string {
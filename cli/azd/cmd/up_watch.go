@@ -0,0 +1,187 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/watch"
+	"github.com/azure/azure-dev/cli/azd/pkg/workflow"
+)
+
+// watchDebounce is how long `azd up --watch` waits after the last file change before redeploying, so that saving
+// several files at once redeploys once.
+const watchDebounce = 500 * time.Millisecond
+
+// watchedService is a service whose project directory is watched by `azd up --watch`.
+type watchedService struct {
+	name string
+	path string
+}
+
+// watchIfEnabled returns the result of `azd up`, or with --watch, displays it and watches the services for changes
+// until Ctrl+C is pressed.
+func (u *upAction) watchIfEnabled(ctx context.Context, result *actions.ActionResult) (*actions.ActionResult, error) {
+	if !u.flags.watch {
+		return result, nil
+	}
+
+	if result != nil && result.Message != nil {
+		u.console.MessageUxItem(ctx, &ux.ActionResult{
+			SuccessMessage: result.Message.Header,
+			FollowUp:       result.Message.FollowUp,
+		})
+	}
+
+	if err := u.watch(ctx); err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: "Stopped watching for changes.",
+		},
+	}, nil
+}
+
+// watch watches the project directories of the services, and redeploys the services whose files change. A failed
+// redeploy is reported, and the services are still watched. It returns when Ctrl+C is pressed.
+func (u *upAction) watch(ctx context.Context) error {
+	services, err := u.importManager.ServiceStable(ctx, u.projectConfig)
+	if err != nil {
+		return err
+	}
+
+	var watched []watchedService
+	var dirs []string
+	for _, svc := range services {
+		// Services that only reference a container image have no project to watch.
+		if svc.RelativePath == "" {
+			continue
+		}
+
+		path := svc.Path()
+		watched = append(watched, watchedService{name: svc.Name, path: path})
+		if !slices.Contains(dirs, path) {
+			dirs = append(dirs, path)
+		}
+	}
+
+	if len(watched) == 0 {
+		u.console.Message(ctx, output.WithWarningFormat("There are no service projects to watch."))
+		return nil
+	}
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Ctrl+C stops watching, and cancels the redeploy in progress.
+	pop := input.PushInterruptHandler(func() bool {
+		cancel()
+		return true
+	})
+	defer pop()
+
+	watcher, err := watch.NewDirWatcher(watchCtx, dirs, watchDebounce)
+	if err != nil {
+		return fmt.Errorf("watching the service projects: %w", err)
+	}
+
+	names := make([]string, len(watched))
+	for i, svc := range watched {
+		names[i] = svc.name
+	}
+	watching := output.WithGrayFormat(
+		"Watching %s for changes. Press Ctrl+C to stop.", ux.ListAsText(names))
+
+	u.console.Message(ctx, "")
+	u.console.Message(ctx, watching)
+
+	for files := range watcher.Changes {
+		changed := servicesForChanges(watched, files)
+		if len(changed) == 0 {
+			continue
+		}
+
+		// Packaging writes build outputs to the service projects, which must not trigger another redeploy.
+		watcher.Pause()
+		u.redeploy(watchCtx, changed, len(files))
+		watcher.Resume()
+
+		if watchCtx.Err() != nil {
+			break
+		}
+
+		u.console.Message(ctx, "")
+		u.console.Message(ctx, watching)
+	}
+
+	return nil
+}
+
+// redeploy deploys the changed services, and displays a summary of the redeploy.
+func (u *upAction) redeploy(ctx context.Context, services []string, changedFiles int) {
+	u.console.Message(ctx, "")
+	u.console.Message(ctx, fmt.Sprintf(
+		"%d changed file(s) in %s, redeploying.", changedFiles, output.WithHighLightFormat(ux.ListAsText(services))))
+
+	startTime := time.Now()
+	var failed []string
+	for _, name := range services {
+		err := u.workflowRunner.Run(ctx, &workflow.Workflow{
+			Name: "watch",
+			Steps: []*workflow.Step{
+				{AzdCommand: workflow.Command{Args: []string{"deploy", name, "-e", u.env.Name()}}},
+			},
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			failed = append(failed, name)
+			u.console.Message(ctx, output.WithErrorFormat("ERROR: %v", err))
+		}
+	}
+
+	elapsed := ux.DurationAsText(since(startTime))
+	if len(failed) > 0 {
+		u.console.MessageUxItem(ctx, &ux.WarningMessage{
+			Description: fmt.Sprintf("Redeploy of %s failed after %s.", ux.ListAsText(failed), elapsed),
+			Hints:       []string{"Fix the error and save the files again to retry."},
+		})
+		return
+	}
+
+	u.console.MessageUxItem(ctx, &ux.DoneMessage{
+		Message: fmt.Sprintf("Redeployed %s in %s", ux.ListAsText(services), elapsed),
+	})
+}
+
+// servicesForChanges returns the names of the services whose project directory contains any of the changed files, in
+// the order of services.
+func servicesForChanges(services []watchedService, files []string) []string {
+	var changed []string
+	for _, svc := range services {
+		for _, file := range files {
+			rel, err := filepath.Rel(svc.path, file)
+			if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				continue
+			}
+
+			changed = append(changed, svc.name)
+			break
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,56 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServicesForChanges(t *testing.T) {
+	root := t.TempDir()
+	services := []watchedService{
+		{name: "api", path: filepath.Join(root, "src", "api")},
+		{name: "apiworker", path: filepath.Join(root, "src", "apiworker")},
+		{name: "web", path: filepath.Join(root, "src", "web")},
+	}
+
+	tests := []struct {
+		name  string
+		files []string
+		want  []string
+	}{
+		{
+			name:  "SingleService",
+			files: []string{filepath.Join(root, "src", "api", "main.py")},
+			want:  []string{"api"},
+		},
+		{
+			name: "MultipleServices",
+			files: []string{
+				filepath.Join(root, "src", "web", "index.js"),
+				filepath.Join(root, "src", "api", "routes", "users.py"),
+				filepath.Join(root, "src", "api", "main.py"),
+			},
+			want: []string{"api", "web"},
+		},
+		{
+			name:  "SharedPrefix",
+			files: []string{filepath.Join(root, "src", "apiworker", "worker.py")},
+			want:  []string{"apiworker"},
+		},
+		{
+			name:  "OutsideServices",
+			files: []string{filepath.Join(root, "README.md")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, servicesForChanges(services, tt.files))
+		})
+	}
+}
@@ -0,0 +1,342 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// waitRequestTimeout bounds each request made to check a URL, so an endpoint that doesn't respond doesn't use up the
+// whole --timeout in a single check.
+const waitRequestTimeout = 30 * time.Second
+
+type waitFlags struct {
+	conditions []string
+	healthy    bool
+	timeout    time.Duration
+	interval   time.Duration
+	internal.EnvFlag
+}
+
+func (f *waitFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringArrayVar(
+		&f.conditions,
+		"for",
+		nil,
+		"A condition to wait for: output:<name>, url:<url> or resource:<resource id>[=<state>]. Can be repeated.",
+	)
+	local.BoolVar(
+		&f.healthy,
+		"healthy",
+		false,
+		"Also wait for outputs that are URLs to respond with a success status code.",
+	)
+	local.DurationVar(&f.timeout, "timeout", 10*time.Minute, "How long to wait for all conditions before failing.")
+	local.DurationVar(&f.interval, "interval", 10*time.Second, "How long to wait between checks.")
+	f.EnvFlag.Bind(local, global)
+}
+
+func newWaitFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *waitFlags {
+	flags := &waitFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newWaitCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "wait",
+		Short: "Wait for provisioning outputs, endpoints or resources to be ready.",
+	}
+}
+
+func getCmdWaitHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Block until every condition passed with --for is met, or fail when --timeout is reached.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("%s waits for the environment to have a value for an output.",
+				output.WithHighLightFormat("output:<name>"))),
+			formatHelpNote(fmt.Sprintf("%s waits for a URL to respond with a success status code.",
+				output.WithHighLightFormat("url:<url>"))),
+			formatHelpNote(fmt.Sprintf("%s waits for a resource to reach a provisioning state, %s by default.",
+				output.WithHighLightFormat("resource:<resource id>[=<state>]"),
+				output.WithHighLightFormat("Succeeded"))),
+		})
+}
+
+func getCmdWaitHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Wait for the endpoint output to be set and respond.": output.WithHighLightFormat(
+			"azd wait --for output:SERVICE_WEB_URI --healthy --timeout 10m",
+		),
+		"Wait for a resource to finish provisioning.": output.WithHighLightFormat(
+			"azd wait --for resource:<resource id>",
+		),
+	})
+}
+
+// waitConditionKind is the kind of thing a wait condition checks.
+type waitConditionKind string
+
+const (
+	waitForOutput   waitConditionKind = "output"
+	waitForUrl      waitConditionKind = "url"
+	waitForResource waitConditionKind = "resource"
+)
+
+// waitCondition is a condition passed to azd wait with --for.
+type waitCondition struct {
+	kind waitConditionKind
+	// target is the name of the output, the URL or the resource id.
+	target string
+	// state is the provisioning state a resource has to reach.
+	state string
+	// resourceId is the parsed target of a resource condition.
+	resourceId *arm.ResourceID
+}
+
+func (c waitCondition) String() string {
+	if c.kind == waitForResource {
+		return fmt.Sprintf("%s:%s=%s", c.kind, c.target, c.state)
+	}
+
+	return fmt.Sprintf("%s:%s", c.kind, c.target)
+}
+
+// parseWaitCondition parses a condition in the <kind>:<target> form.
+func parseWaitCondition(value string) (waitCondition, error) {
+	kind, target, _ := strings.Cut(value, ":")
+	condition := waitCondition{kind: waitConditionKind(kind), target: target}
+	if target == "" {
+		return condition, fmt.Errorf("condition '%s' has no target: %w", value, internal.ErrInvalidArgValue)
+	}
+
+	switch condition.kind {
+	case waitForOutput:
+	case waitForUrl:
+		if !isHttpUrl(target) {
+			return condition, fmt.Errorf("'%s' isn't an http or https URL: %w", target, internal.ErrInvalidArgValue)
+		}
+	case waitForResource:
+		condition.target, condition.state, _ = strings.Cut(target, "=")
+		if condition.state == "" {
+			condition.state = "Succeeded"
+		}
+
+		resourceId, err := arm.ParseResourceID(condition.target)
+		// Only resources listed in their resource group, and not child resources, have a provisioning state to wait
+		// for.
+		if err != nil || resourceId.Parent == nil ||
+			resourceId.Parent.ResourceType.String() != arm.ResourceGroupResourceType.String() {
+			return condition, fmt.Errorf(
+				"'%s' isn't the id of a resource in a resource group: %w", condition.target, internal.ErrInvalidArgValue)
+		}
+		condition.resourceId = resourceId
+	default:
+		return condition, fmt.Errorf(
+			"condition '%s' must start with output:, url: or resource:: %w", value, internal.ErrInvalidArgValue)
+	}
+
+	return condition, nil
+}
+
+func isHttpUrl(value string) bool {
+	parsed, err := url.Parse(value)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+type waitAction struct {
+	env             *environment.Environment
+	envManager      environment.Manager
+	resourceService *azapi.ResourceService
+	transporter     policy.Transporter
+	console         input.Console
+	flags           *waitFlags
+}
+
+func newWaitAction(
+	env *environment.Environment,
+	envManager environment.Manager,
+	resourceService *azapi.ResourceService,
+	transporter policy.Transporter,
+	console input.Console,
+	flags *waitFlags,
+) actions.Action {
+	return &waitAction{
+		env:             env,
+		envManager:      envManager,
+		resourceService: resourceService,
+		transporter:     transporter,
+		console:         console,
+		flags:           flags,
+	}
+}
+
+func (w *waitAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if len(w.flags.conditions) == 0 {
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        fmt.Errorf("no conditions to wait for: %w", internal.ErrNoArgsProvided),
+			Suggestion: "Pass at least one condition with --for, such as 'azd wait --for output:SERVICE_WEB_URI'.",
+		}
+	}
+
+	conditions := make([]waitCondition, 0, len(w.flags.conditions))
+	for _, value := range w.flags.conditions {
+		condition, err := parseWaitCondition(value)
+		if err != nil {
+			return nil, &internal.ErrorWithSuggestion{
+				Err: err,
+				Suggestion: "Use output:<name>, url:<url> or resource:<resource id>[=<state>], " +
+					"such as 'azd wait --for output:SERVICE_WEB_URI'.",
+			}
+		}
+		conditions = append(conditions, condition)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, w.flags.timeout)
+	defer cancel()
+
+	// Conditions are waited for in order, within the same overall timeout.
+	for _, condition := range conditions {
+		if err := w.waitFor(ctx, waitCtx, condition); err != nil {
+			return nil, err
+		}
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: "All conditions were met.",
+		},
+	}, nil
+}
+
+// waitFor checks condition every interval until it's met or waitCtx is done.
+func (w *waitAction) waitFor(ctx context.Context, waitCtx context.Context, condition waitCondition) error {
+	title := fmt.Sprintf("Waiting for %s", condition)
+	w.console.ShowSpinner(ctx, title, input.Step)
+
+	// The check that's interrupted by the timeout fails without a status, so the timeout reports the last known one.
+	lastStatus := "it wasn't checked"
+	for {
+		met, status, err := w.check(waitCtx, condition)
+		if err != nil && waitCtx.Err() == nil {
+			w.console.StopSpinner(ctx, title, input.StepFailed)
+			return fmt.Errorf("checking %s: %w", condition, err)
+		}
+
+		if met {
+			w.console.StopSpinner(ctx, title, input.StepDone)
+			return nil
+		}
+
+		if status != "" {
+			lastStatus = status
+		}
+		log.Printf("wait: %s isn't met yet: %s", condition, lastStatus)
+
+		select {
+		case <-waitCtx.Done():
+			w.console.StopSpinner(ctx, title, input.StepFailed)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			return &internal.ErrorWithSuggestion{
+				Err: fmt.Errorf(
+					"%s after %s, %s: %w", condition, w.flags.timeout, lastStatus, internal.ErrWaitTimedOut),
+				Suggestion: "Check that the provisioning or deployment that the condition depends on succeeded, or " +
+					"wait longer with --timeout.",
+			}
+		case <-time.After(w.flags.interval):
+		}
+	}
+}
+
+// check returns whether condition is met and, when it isn't, a short description of why. Failures that can go away,
+// such as an endpoint that isn't responding yet, mean the condition isn't met. Other failures are returned as errors.
+func (w *waitAction) check(ctx context.Context, condition waitCondition) (bool, string, error) {
+	switch condition.kind {
+	case waitForOutput:
+		// The output can be set by a provisioning that's running in another process.
+		if err := w.envManager.Reload(ctx, w.env); err != nil {
+			return false, "", fmt.Errorf("reloading environment: %w", err)
+		}
+
+		value := w.env.Getenv(condition.target)
+		if value == "" {
+			return false, "the output isn't set", nil
+		}
+
+		if w.flags.healthy && isHttpUrl(value) {
+			return w.checkUrl(ctx, value)
+		}
+
+		return true, "", nil
+	case waitForUrl:
+		return w.checkUrl(ctx, condition.target)
+	case waitForResource:
+		state, err := w.resourceService.GetProvisioningState(ctx, condition.resourceId)
+		if respErr, ok := errors.AsType[*azcore.ResponseError](err); ok && respErr.StatusCode == http.StatusNotFound {
+			return false, "the resource group doesn't exist", nil
+		} else if err != nil {
+			return false, "", err
+		}
+
+		if state == "" {
+			return false, "the resource doesn't exist", nil
+		}
+
+		if !strings.EqualFold(state, condition.state) {
+			return false, fmt.Sprintf("the provisioning state is %s", state), nil
+		}
+
+		return true, "", nil
+	default:
+		return false, "", fmt.Errorf("unknown condition kind '%s'", condition.kind)
+	}
+}
+
+// checkUrl returns whether a GET request to target responds with a success status code.
+func (w *waitAction) checkUrl(ctx context.Context, target string) (bool, string, error) {
+	requestCtx, cancel := context.WithTimeout(ctx, waitRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(requestCtx, http.MethodGet, target, nil)
+	if err != nil {
+		return false, "", err
+	}
+
+	res, err := w.transporter.Do(req)
+	if err != nil {
+		return false, fmt.Sprintf("%s didn't respond", target), nil
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode >= 400 {
+		return false, fmt.Sprintf("%s responded with %s", target, res.Status), nil
+	}
+
+	return true, "", nil
+}
@@ -0,0 +1,160 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWaitCondition(t *testing.T) {
+	t.Parallel()
+	resourceId := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-dev" +
+		"/providers/Microsoft.Web/sites/app-web"
+
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "Output", value: "output:SERVICE_WEB_URI", expected: "output:SERVICE_WEB_URI"},
+		{name: "Url", value: "url:https://example.com/health", expected: "url:https://example.com/health"},
+		{name: "ResourceDefaultState", value: "resource:" + resourceId, expected: "resource:" + resourceId + "=Succeeded"},
+		{name: "ResourceState", value: "resource:" + resourceId + "=Running", expected: "resource:" + resourceId + "=Running"},
+		{name: "UnknownKind", value: "endpoint:web", wantErr: true},
+		{name: "NoTarget", value: "output:", wantErr: true},
+		{name: "NoKind", value: "SERVICE_WEB_URI", wantErr: true},
+		{name: "NotHttpUrl", value: "url:ftp://example.com", wantErr: true},
+		{name: "InvalidResourceId", value: "resource:app-web", wantErr: true},
+		{
+			name:    "ResourceGroup",
+			value:   "resource:/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-dev",
+			wantErr: true,
+		},
+		{name: "ChildResource", value: "resource:" + resourceId + "/slots/staging", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			condition, err := parseWaitCondition(tt.value)
+			if tt.wantErr {
+				require.ErrorIs(t, err, internal.ErrInvalidArgValue)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, condition.String())
+		})
+	}
+}
+
+func TestWaitAction(t *testing.T) {
+	t.Parallel()
+	envName := "test-env"
+
+	t.Run("OutputHealthy", func(t *testing.T) {
+		t.Parallel()
+		// The endpoint fails twice before it's healthy.
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) <= 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		_, envManager, _ := setupTestEnvironment(t, envName, map[string]any{})
+		env, err := envManager.Get(t.Context(), envName)
+		require.NoError(t, err)
+		env.DotenvSet("SERVICE_WEB_URI", server.URL)
+		require.NoError(t, envManager.Save(t.Context(), env))
+
+		flags := &waitFlags{
+			conditions: []string{"output:SERVICE_WEB_URI"},
+			healthy:    true,
+			timeout:    time.Minute,
+			interval:   time.Millisecond,
+		}
+		result, err := newWaitAction(
+			env, envManager, nil, server.Client(), mockinput.NewMockConsole(), flags).Run(t.Context())
+		require.NoError(t, err)
+		require.Equal(t, "All conditions were met.", result.Message.Header)
+		require.Equal(t, int32(3), requests.Load())
+	})
+
+	t.Run("OutputNotHealthyWithoutFlag", func(t *testing.T) {
+		t.Parallel()
+		_, envManager, _ := setupTestEnvironment(t, envName, map[string]any{})
+		env, err := envManager.Get(t.Context(), envName)
+		require.NoError(t, err)
+		// Without --healthy the URL isn't requested.
+		env.DotenvSet("SERVICE_WEB_URI", "http://localhost:0")
+		require.NoError(t, envManager.Save(t.Context(), env))
+
+		flags := &waitFlags{
+			conditions: []string{"output:SERVICE_WEB_URI"},
+			timeout:    time.Minute,
+			interval:   time.Millisecond,
+		}
+		_, err = newWaitAction(env, envManager, nil, nil, mockinput.NewMockConsole(), flags).Run(t.Context())
+		require.NoError(t, err)
+	})
+
+	t.Run("TimedOut", func(t *testing.T) {
+		t.Parallel()
+		_, envManager, _ := setupTestEnvironment(t, envName, map[string]any{})
+		env, err := envManager.Get(t.Context(), envName)
+		require.NoError(t, err)
+
+		flags := &waitFlags{
+			conditions: []string{"output:SERVICE_WEB_URI"},
+			timeout:    20 * time.Millisecond,
+			interval:   time.Millisecond,
+		}
+		_, err = newWaitAction(env, envManager, nil, nil, mockinput.NewMockConsole(), flags).Run(t.Context())
+		require.ErrorIs(t, err, internal.ErrWaitTimedOut)
+		require.ErrorContains(t, err, "the output isn't set")
+	})
+
+	t.Run("TimedOutWhileChecking", func(t *testing.T) {
+		t.Parallel()
+		env := environment.NewWithValues(envName, nil)
+		// The second reload is interrupted by the timeout.
+		envManager := &mockenv.MockEnvManager{}
+		envManager.On("Reload", mock.Anything, env).Return(nil).Once()
+		envManager.On("Reload", mock.Anything, env).Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+		}).Return(context.DeadlineExceeded)
+
+		flags := &waitFlags{
+			conditions: []string{"output:SERVICE_WEB_URI"},
+			timeout:    20 * time.Millisecond,
+			interval:   time.Millisecond,
+		}
+		_, err := newWaitAction(env, envManager, nil, nil, mockinput.NewMockConsole(), flags).Run(t.Context())
+		require.ErrorIs(t, err, internal.ErrWaitTimedOut)
+		require.ErrorContains(t, err, "the output isn't set")
+	})
+
+	t.Run("NoConditions", func(t *testing.T) {
+		t.Parallel()
+		_, err := newWaitAction(nil, nil, nil, nil, mockinput.NewMockConsole(), &waitFlags{}).Run(t.Context())
+		require.ErrorIs(t, err, internal.ErrNoArgsProvided)
+	})
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/yamlnode"
 	"github.com/braydonk/yaml"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 func NewAddCmd() *cobra.Command {
@@ -43,7 +44,31 @@ func NewAddCmd() *cobra.Command {
 	}
 }
 
+type addFlags struct {
+	global   *internal.GlobalCommandOptions
+	existing bool
+}
+
+func (f *addFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.BoolVar(
+		&f.existing,
+		"existing",
+		false,
+		"Adds an existing resource of your subscription, which is connected to the services of the project "+
+			"without being provisioned.",
+	)
+	f.global = global
+}
+
+func NewAddFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *addFlags {
+	flags := &addFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
 type AddAction struct {
+	flags            *addFlags
 	azd              workflow.AzdCommandRunner
 	azdCtx           *azdcontext.AzdContext
 	env              *environment.Environment
@@ -83,19 +108,24 @@ func (a *AddAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		return strings.Compare(a.Label, b.Label)
 	})
 
-	selections := make([]string, 0, len(selectMenu))
-	for _, menu := range selectMenu {
-		selections = append(selections, menu.Label)
-	}
-	idx, err := a.console.Select(ctx, input.ConsoleOptions{
-		Message: "What would you like to add?",
-		Options: selections,
-	})
-	if err != nil {
-		return nil, err
-	}
+	var selected Menu
+	if a.flags != nil && a.flags.existing {
+		selected = Menu{Namespace: "existing", SelectResource: a.selectExistingResource}
+	} else {
+		selections := make([]string, 0, len(selectMenu))
+		for _, menu := range selectMenu {
+			selections = append(selections, menu.Label)
+		}
+		idx, err := a.console.Select(ctx, input.ConsoleOptions{
+			Message: "What would you like to add?",
+			Options: selections,
+		})
+		if err != nil {
+			return nil, err
+		}
 
-	selected := selectMenu[idx]
+		selected = selectMenu[idx]
+	}
 
 	resourceToAdd := &project.ResourceConfig{}
 	var serviceToAdd *project.ServiceConfig
@@ -126,6 +156,11 @@ func (a *AddAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		return nil, err
 	}
 
+	resourceToAdd, err = fillPrivateNetworking(ctx, resourceToAdd, a.console)
+	if err != nil {
+		return nil, err
+	}
+
 	usedBy, err := promptUsedBy(ctx, resourceToAdd, a.console, promptOpts)
 	if err != nil {
 		return nil, err
@@ -301,6 +336,12 @@ func (a *AddAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	keyVaultFollowUpMessage := fmt.Sprintf(
 		"\nRun '%s' to add a secret to the key vault.",
 		output.WithHighLightFormat("azd env set-secret <name>"))
+	addedFeatureFlags := slices.ContainsFunc(resourcesToAdd, func(resource *project.ResourceConfig) bool {
+		return resource.Type == project.ResourceTypeFeatureFlags
+	})
+	featureFlagsFollowUpMessage := fmt.Sprintf(
+		"\nRun '%s' to add a feature flag once the store is provisioned.",
+		output.WithHighLightFormat("azd flags set <name>"))
 
 	if _, err := pathHasInfraModule(infraRoot, infraOptions.Module); err == nil {
 		followUpMessage = fmt.Sprintf(
@@ -311,6 +352,9 @@ func (a *AddAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		if addedKeyVault {
 			followUpMessage += keyVaultFollowUpMessage
 		}
+		if addedFeatureFlags {
+			followUpMessage += featureFlagsFollowUpMessage
+		}
 		return &actions.ActionResult{
 			Message: &actions.ResultMessage{
 				FollowUp: followUpMessage,
@@ -378,6 +422,10 @@ func (a *AddAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		followUpMessage += keyVaultFollowUpMessage
 	}
 
+	if addedFeatureFlags {
+		followUpMessage += featureFlagsFollowUpMessage
+	}
+
 	return &actions.ActionResult{
 		Message: &actions.ResultMessage{
 			FollowUp: followUpMessage,
@@ -471,6 +519,7 @@ func selectProvisionOptions(
 }
 
 func NewAddAction(
+	flags *addFlags,
 	azdCtx *azdcontext.AzdContext,
 	envManager environment.Manager,
 	subManager *account.SubscriptionsManager,
@@ -487,6 +536,7 @@ func NewAddAction(
 	azureClient *azapi.AzureClient,
 	importManager *project.ImportManager) actions.Action {
 	return &AddAction{
+		flags:            flags,
 		azdCtx:           azdCtx,
 		console:          console,
 		envManager:       envManager,
@@ -121,6 +121,16 @@ func Configure(
 
 		r.Name = "vault"
 		return r, nil
+	case project.ResourceTypeFeatureFlags:
+		if _, exists := p.PrjConfig.Resources["flags"]; exists {
+			return nil, fmt.Errorf(
+				"you already have a feature flags store named 'flags'. " +
+					"To add a feature flag to it, run 'azd flags set <name>'",
+			)
+		}
+
+		r.Name = "flags"
+		return r, nil
 	default:
 		return r, nil
 	}
@@ -158,6 +168,31 @@ func fillDatabaseName(
 	return r, nil
 }
 
+// fillPrivateNetworking prompts whether a database that supports private networking is only reachable through a
+// private endpoint, in a virtual network that the host services are joined to.
+func fillPrivateNetworking(
+	ctx context.Context,
+	r *project.ResourceConfig,
+	console input.Console) (*project.ResourceConfig, error) {
+	if r.Existing || (r.Type != project.ResourceTypeDbPostgres && r.Type != project.ResourceTypeDbRedis) {
+		return r, nil
+	}
+
+	private, err := console.Confirm(ctx, input.ConsoleOptions{
+		Message: "Only allow access from the app's virtual network?",
+		Help: "Hint: Private networking\n\n" +
+			"The database is reachable only through a private endpoint, in a virtual network " +
+			"that the app's services are joined to. Otherwise, it's reachable from the internet.",
+		DefaultValue: false,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.Props = project.DatabaseProps{Private: private}
+	return r, nil
+}
+
 func fillOpenAiModelName(
 	ctx context.Context,
 	r *project.ResourceConfig,
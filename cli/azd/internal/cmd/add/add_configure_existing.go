@@ -5,9 +5,11 @@ package add
 
 import (
 	"context"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/azure/azure-dev/cli/azd/internal/names"
+	"github.com/azure/azure-dev/cli/azd/internal/scaffold"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 )
@@ -48,6 +50,31 @@ func ConfigureExisting(
 	return r, nil
 }
 
+// supportsExisting reports whether resources of type t can be added as existing resources. The connection variables
+// of existing resources are read from the resource, so they can't reference Key Vault secrets or the spec of the
+// resource in azure.yaml.
+func supportsExisting(t project.ResourceType) bool {
+	resourceMeta, ok := scaffold.ResourceMetaFromType(t.AzureResourceType())
+	if !ok {
+		return false
+	}
+
+	for _, value := range resourceMeta.Variables {
+		expressions, err := scaffold.Parse(&value)
+		if err != nil {
+			return false
+		}
+
+		for _, expr := range expressions {
+			if expr.Kind == scaffold.VaultExpr || expr.Kind == scaffold.SpecExpr {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
 // resourceType returns the resource type for the given Azure resource type.
 func resourceType(azureResourceType string) project.ResourceType {
 	resourceTypes := project.AllResourceTypes()
@@ -59,3 +86,29 @@ func resourceType(azureResourceType string) project.ResourceType {
 
 	return project.ResourceType("")
 }
+
+// ExistingResourceType returns the resource type that an existing Azure resource, of the given Azure resource type and
+// kind, can be added to a project as. It returns an empty resource type when the resource can't be added as an existing
+// resource.
+func ExistingResourceType(azureResourceType string, kind string) project.ResourceType {
+	for _, resourceType := range project.AllResourceTypes() {
+		resourceMeta, ok := scaffold.ResourceMetaFromType(resourceType.AzureResourceType())
+		if !ok {
+			continue
+		}
+
+		if resourceMeta.ResourceType != azureResourceType && resourceMeta.ParentForEval != azureResourceType {
+			continue
+		}
+
+		if resourceMeta.ResourceKind != "" && !strings.EqualFold(resourceMeta.ResourceKind, kind) {
+			continue
+		}
+
+		if supportsExisting(resourceType) {
+			return resourceType
+		}
+	}
+
+	return project.ResourceType("")
+}
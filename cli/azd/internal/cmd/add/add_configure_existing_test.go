@@ -7,7 +7,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 )
 
@@ -24,3 +26,44 @@ func TestResourceType_KnownAndUnknown(t *testing.T) {
 	got = resourceType("Microsoft.DoesNot/exist")
 	assert.Equal(t, project.ResourceType(""), got)
 }
+
+func TestSupportsExisting(t *testing.T) {
+	t.Parallel()
+	// Variables read from the resource
+	assert.True(t, supportsExisting(project.ResourceTypeDbCosmos))
+	assert.True(t, supportsExisting(project.ResourceTypeStorage))
+	assert.True(t, supportsExisting(project.ResourceTypeMessagingServiceBus))
+
+	// Variables referencing Key Vault secrets
+	assert.False(t, supportsExisting(project.ResourceTypeDbRedis))
+	assert.False(t, supportsExisting(project.ResourceTypeDbPostgres))
+	assert.False(t, supportsExisting(project.ResourceTypeDbMongo))
+}
+
+func TestExistingResourceType(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, project.ResourceTypeStorage, ExistingResourceType("Microsoft.Storage/storageAccounts", "StorageV2"))
+	assert.Equal(t,
+		project.ResourceTypeDbCosmos, ExistingResourceType("Microsoft.DocumentDB/databaseAccounts", "GlobalDocumentDB"))
+
+	// Accounts of other kinds
+	assert.Equal(t, project.ResourceType(""), ExistingResourceType("Microsoft.DocumentDB/databaseAccounts", "MongoDB"))
+	// Variables referencing Key Vault secrets
+	assert.Equal(t, project.ResourceType(""), ExistingResourceType("Microsoft.Cache/redis", ""))
+	// Unknown
+	assert.Equal(t, project.ResourceType(""), ExistingResourceType("Microsoft.DoesNot/exist", ""))
+}
+
+func TestSelectExistingDatabase(t *testing.T) {
+	t.Parallel()
+	c := newTestConsole()
+	c.WhenSelect(func(input.ConsoleOptions) bool { return true }).
+		RespondFn(func(opts input.ConsoleOptions) (any, error) {
+			assert.Equal(t, []string{project.ResourceTypeDbCosmos.String()}, opts.Options)
+			return 0, nil
+		})
+
+	r, err := selectExistingDatabase(c, t.Context(), PromptOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, project.ResourceTypeDbCosmos, r.Type)
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -651,6 +652,42 @@ func TestConfigure_RedisDuplicateReturnsError(t *testing.T) {
 	assert.Contains(t, err.Error(), "Redis")
 }
 
+func TestFillPrivateNetworking(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		resType project.ResourceType
+		private bool
+	}{
+		{name: "RedisPrivate", resType: project.ResourceTypeDbRedis, private: true},
+		{name: "RedisPublic", resType: project.ResourceTypeDbRedis},
+		{name: "PostgresPrivate", resType: project.ResourceTypeDbPostgres, private: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			c := newTestConsole()
+			c.WhenConfirm(func(opts input.ConsoleOptions) bool {
+				return strings.Contains(opts.Message, "virtual network")
+			}).Respond(tt.private)
+
+			r := &project.ResourceConfig{Name: "db", Type: tt.resType}
+			got, err := fillPrivateNetworking(t.Context(), r, c)
+			require.NoError(t, err)
+			assert.Equal(t, project.DatabaseProps{Private: tt.private}, got.Props)
+		})
+	}
+}
+
+func TestFillPrivateNetworking_NotSupported(t *testing.T) {
+	t.Parallel()
+	r := &project.ResourceConfig{Name: "db", Type: project.ResourceTypeDbMySql}
+	got, err := fillPrivateNetworking(t.Context(), r, nil)
+	require.NoError(t, err)
+	assert.Nil(t, got.Props)
+}
+
 func TestFillUses_CrossHostSkipped(t *testing.T) {
 	t.Parallel()
 	// r is AppService; a ContainerApp in the project should be skipped.
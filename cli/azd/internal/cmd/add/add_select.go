@@ -41,6 +41,7 @@ func (a *AddAction) selectMenu() []Menu {
 		{Namespace: "messaging", Label: "Messaging", SelectResource: selectMessaging},
 		{Namespace: "storage", Label: "Storage account", SelectResource: selectStorage},
 		{Namespace: "keyvault", Label: "Key Vault", SelectResource: selectKeyVault},
+		{Namespace: "featureflags", Label: "Feature flags", SelectResource: selectFeatureFlags},
 		{Namespace: "existing", Label: "~Existing resource", SelectResource: a.selectExistingResource},
 	}
 }
@@ -80,9 +81,25 @@ func selectDatabase(
 	console input.Console,
 	ctx context.Context,
 	p PromptOptions) (*project.ResourceConfig, error) {
+	return selectDatabaseType(console, ctx, func(project.ResourceType) bool { return true })
+}
+
+// selectExistingDatabase prompts for the type of an existing database, out of the types that can be added as existing
+// resources.
+func selectExistingDatabase(
+	console input.Console,
+	ctx context.Context,
+	p PromptOptions) (*project.ResourceConfig, error) {
+	return selectDatabaseType(console, ctx, supportsExisting)
+}
+
+func selectDatabaseType(
+	console input.Console,
+	ctx context.Context,
+	include func(project.ResourceType) bool) (*project.ResourceConfig, error) {
 	resourceTypesDisplayMap := make(map[string]project.ResourceType)
 	for _, resourceType := range project.AllResourceTypes() {
-		if strings.HasPrefix(string(resourceType), "db.") {
+		if strings.HasPrefix(string(resourceType), "db.") && include(resourceType) {
 			resourceTypesDisplayMap[resourceType.String()] = resourceType
 		}
 	}
@@ -178,6 +195,12 @@ func selectKeyVault(console input.Console, ctx context.Context, p PromptOptions)
 	return r, nil
 }
 
+func selectFeatureFlags(console input.Console, ctx context.Context, p PromptOptions) (*project.ResourceConfig, error) {
+	r := &project.ResourceConfig{}
+	r.Type = project.ResourceTypeFeatureFlags
+	return r, nil
+}
+
 func (a *AddAction) selectExistingResource(
 	console input.Console,
 	ctx context.Context,
@@ -193,11 +216,14 @@ func (a *AddAction) selectExistingResource(
 				continue
 			}
 
-			if menu.Namespace == "host" || // host resources are not yet supported
-				menu.Namespace == "db" { // db resources are not yet supported
+			if menu.Namespace == "host" { // host resources are not yet supported
 				continue
 			}
 
+			if menu.Namespace == "db" {
+				menu.SelectResource = selectExistingDatabase
+			}
+
 			selectMenu = append(selectMenu, menu)
 
 		}
@@ -267,7 +293,7 @@ func (a *AddAction) selectExistingResource(
 
 		azureResourceType := resourceId.ResourceType.String()
 		resourceType := resourceType(azureResourceType)
-		if resourceType == "" {
+		if resourceType == "" || !supportsExisting(resourceType) {
 			return nil, fmt.Errorf("resource type '%s' is not currently supported", azureResourceType)
 		}
 
@@ -9,10 +9,14 @@ import (
 	"fmt"
 	"log"
 	"maps"
+	"math"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/cognitiveservices/armcognitiveservices/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/ai"
 	"github.com/azure/azure-dev/cli/azd/pkg/azureutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/convert"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
@@ -57,6 +61,7 @@ func (a *AddAction) promptOpenAi(
 	}
 
 	var allModels []ModelList
+	var usages []*armcognitiveservices.Usage
 	for {
 		err = provisioning.EnsureSubscriptionAndLocation(
 			ctx, a.envManager, a.env, a.prompter, provisioning.EnsureSubscriptionAndLocationOptions{})
@@ -73,11 +78,17 @@ func (a *AddAction) promptOpenAi(
 		if err != nil {
 			return nil, err
 		}
+
+		usages, err = a.azureClient.GetAiUsages(ctx, a.env.GetSubscriptionId(), a.env.GetLocation())
+		if err != nil {
+			return nil, fmt.Errorf("getting usages: %w", err)
+		}
 		console.StopSpinner(ctx, "", input.Step)
 
 		for _, model := range supportedModels {
 			if model.Kind == "OpenAI" && slices.ContainsFunc(model.Model.Skus, func(sku ModelSku) bool {
-				return sku.Name == "Standard"
+				// models without quota left in the location can't be deployed
+				return sku.Name == "Standard" && quotaAvailable(sku, usages)
 			}) {
 				switch aiOption {
 				case 0:
@@ -145,16 +156,100 @@ func (a *AddAction) promptOpenAi(
 		return nil, err
 	}
 
+	skuIndex := slices.IndexFunc(models[sel].Skus, func(sku ModelSku) bool { return sku.Name == "Standard" })
+	sku := models[sel].Skus[skuIndex]
+	capacity, err := promptCapacity(ctx, console, sku, remainingQuota(sku, usages))
+	if err != nil {
+		return nil, err
+	}
+
 	r.Props = project.AIModelProps{
 		Model: project.AIModelPropsModel{
 			Name:    models[sel].Name,
 			Version: models[sel].Version,
 		},
+		Sku: project.AIModelPropsSku{
+			Name:     sku.Name,
+			Capacity: capacity,
+		},
 	}
 
 	return r, nil
 }
 
+// promptCapacity prompts for the capacity of a deployment of sku, which must be within the capacity limits of sku and
+// the remaining quota.
+func promptCapacity(
+	ctx context.Context,
+	console input.Console,
+	sku ModelSku,
+	remaining float64) (int32, error) {
+	aiSku := aiModelSku(sku)
+	defaultCapacity, ok := ai.ResolveCapacityWithQuota(aiSku, nil, remaining)
+	if !ok {
+		return 0, fmt.Errorf("not enough quota left for %s", sku.UsageName)
+	}
+
+	help := fmt.Sprintf("%.0f remaining in quota.", remaining)
+	if aiSku.MinCapacity > 0 && aiSku.MaxCapacity > 0 {
+		help = fmt.Sprintf("Between %d and %d, with %.0f remaining in quota.",
+			aiSku.MinCapacity, aiSku.MaxCapacity, remaining)
+	}
+
+	for {
+		value, err := console.Prompt(ctx, input.ConsoleOptions{
+			Message:      "Capacity, in thousands of tokens per minute",
+			Help:         help,
+			DefaultValue: strconv.Itoa(int(defaultCapacity)),
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		capacity, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			console.Message(ctx, "The capacity must be a whole number.")
+			continue
+		}
+
+		preferred := int32(capacity)
+		if _, ok := ai.ResolveCapacityWithQuota(aiSku, &preferred, remaining); !ok {
+			console.Message(ctx, fmt.Sprintf("The capacity isn't allowed for the model, or exceeds the quota. %s", help))
+			continue
+		}
+
+		return preferred, nil
+	}
+}
+
+// quotaAvailable reports whether the quota left for sku allows a deployment of sku.
+func quotaAvailable(sku ModelSku, usages []*armcognitiveservices.Usage) bool {
+	_, ok := ai.ResolveCapacityWithQuota(aiModelSku(sku), nil, remainingQuota(sku, usages))
+	return ok
+}
+
+// remainingQuota returns the quota left for sku in usages. The quota is unlimited when usages has no usage for sku.
+func remainingQuota(sku ModelSku, usages []*armcognitiveservices.Usage) float64 {
+	for _, usage := range usages {
+		if usage.Name != nil && usage.Name.Value != nil && *usage.Name.Value == sku.UsageName {
+			return convert.ToValueWithDefault(usage.Limit, 0) - convert.ToValueWithDefault(usage.CurrentValue, 0)
+		}
+	}
+
+	return math.MaxInt32
+}
+
+func aiModelSku(sku ModelSku) ai.AiModelSku {
+	return ai.AiModelSku{
+		Name:            sku.Name,
+		UsageName:       sku.UsageName,
+		DefaultCapacity: sku.Capacity.Default,
+		MinCapacity:     sku.Capacity.Minimum,
+		MaxCapacity:     sku.Capacity.Maximum,
+		CapacityStep:    sku.Capacity.Step,
+	}
+}
+
 func (a *AddAction) supportedModelsInLocation(ctx context.Context, subId, location string) ([]ModelList, error) {
 	models, err := a.azureClient.GetAiModels(ctx, subId, location)
 	if err != nil {
@@ -4,8 +4,11 @@
 package add
 
 import (
+	"math"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/cognitiveservices/armcognitiveservices/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -74,3 +77,74 @@ func TestSelectFromSkus_MultipleError(t *testing.T) {
 	_, err := selectFromSkus(t.Context(), c, "q", skus)
 	require.Error(t, err)
 }
+
+func TestPromptCapacity(t *testing.T) {
+	t.Parallel()
+	sku := ModelSku{
+		Name:      "Standard",
+		UsageName: "OpenAI.Standard.gpt-4o",
+		Capacity:  ModelSkuCapacity{Minimum: 1, Maximum: 1000, Step: 1, Default: 50},
+	}
+
+	tests := []struct {
+		name      string
+		remaining float64
+		responses []string
+		want      int32
+	}{
+		{name: "Default", remaining: 100, responses: []string{"50"}, want: 50},
+		{name: "RetriesInvalid", remaining: 100, responses: []string{"abc", "200", "80"}, want: 80},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			c := newTestConsole()
+			calls := 0
+			c.WhenPrompt(func(input.ConsoleOptions) bool { return true }).
+				RespondFn(func(opts input.ConsoleOptions) (any, error) {
+					assert.Equal(t, "50", opts.DefaultValue)
+					calls++
+					return tt.responses[calls-1], nil
+				})
+
+			got, err := promptCapacity(t.Context(), c, sku, tt.remaining)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, len(tt.responses), calls)
+		})
+	}
+}
+
+func TestPromptCapacity_NoQuota(t *testing.T) {
+	t.Parallel()
+	sku := ModelSku{
+		Name:      "Standard",
+		UsageName: "OpenAI.Standard.gpt-4o",
+		Capacity:  ModelSkuCapacity{Minimum: 10, Maximum: 1000, Step: 10, Default: 50},
+	}
+
+	_, err := promptCapacity(t.Context(), newTestConsole(), sku, 5)
+	require.ErrorContains(t, err, "not enough quota")
+}
+
+func TestRemainingQuota(t *testing.T) {
+	t.Parallel()
+	sku := ModelSku{Name: "Standard", UsageName: "OpenAI.Standard.gpt-4o"}
+	usages := []*armcognitiveservices.Usage{
+		{
+			Name:         &armcognitiveservices.MetricName{Value: to.Ptr("OpenAI.Standard.gpt-4")},
+			CurrentValue: to.Ptr(10.0),
+			Limit:        to.Ptr(100.0),
+		},
+		{
+			Name:         &armcognitiveservices.MetricName{Value: to.Ptr("OpenAI.Standard.gpt-4o")},
+			CurrentValue: to.Ptr(70.0),
+			Limit:        to.Ptr(100.0),
+		},
+	}
+
+	assert.Equal(t, 30.0, remainingQuota(sku, usages))
+	assert.True(t, quotaAvailable(sku, usages))
+	assert.Equal(t, float64(math.MaxInt32), remainingQuota(sku, nil))
+}
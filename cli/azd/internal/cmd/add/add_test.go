@@ -368,7 +368,7 @@ func TestSelectMenu_AllNamespacesPresent(t *testing.T) {
 		assert.NotNil(t, m.SelectResource, "menu item %q should have a SelectResource func", m.Namespace)
 	}
 
-	for _, ns := range []string{"db", "host", "ai", "messaging", "storage", "keyvault", "existing"} {
+	for _, ns := range []string{"db", "host", "ai", "messaging", "storage", "keyvault", "featureflags", "existing"} {
 		assert.True(t, namespaces[ns], "expected namespace %q in menu", ns)
 	}
 }
@@ -1217,7 +1217,7 @@ func TestNewAddAction_Constructs(t *testing.T) {
 	t.Parallel()
 	// Pass nils for all deps — this is a no-op constructor that only
 	// assigns fields; no methods are invoked.
-	a := NewAddAction(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	a := NewAddAction(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	require.NotNil(t, a)
 }
 
@@ -409,6 +409,20 @@ func TestConfigure_SingletonResources(t *testing.T) {
 			},
 			wantError: "already have a project key vault",
 		},
+		{
+			name:      "feature flags sets name",
+			resType:   project.ResourceTypeFeatureFlags,
+			resources: map[string]*project.ResourceConfig{},
+			wantName:  "flags",
+		},
+		{
+			name:    "feature flags duplicate error",
+			resType: project.ResourceTypeFeatureFlags,
+			resources: map[string]*project.ResourceConfig{
+				"flags": {},
+			},
+			wantError: "already have a feature flags store",
+		},
 	}
 
 	for _, tt := range tests {
@@ -10,17 +10,21 @@ import (
 	"io"
 	"log"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/apphost"
+	"github.com/azure/azure-dev/cli/azd/pkg/artifacts"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
@@ -28,7 +32,10 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/progress"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/workflow"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -36,8 +43,16 @@ import (
 type DeployFlags struct {
 	ServiceName string
 	All         bool
+	Tags        []string
 	Timeout     int
+	// RefreshOutputs refreshes the environment from the provisioning outputs before deploying.
+	RefreshOutputs bool
+	// Force deploys the services whose package is unchanged since their last deploy.
+	Force bool
+	// Rollback deploys the package that was deployed to the service before its last deployment.
+	Rollback    bool
 	fromPackage string
+	image       string
 	flagSet     *pflag.FlagSet
 	global      *internal.GlobalCommandOptions
 	*internal.EnvFlag
@@ -45,6 +60,9 @@ type DeployFlags struct {
 
 const defaultDeployTimeoutSeconds = 1200
 
+// refreshOutputsConfigKey is the user configuration that enables refreshing the environment before every deploy.
+const refreshOutputsConfigKey = "deploy.refreshOutputs"
+
 func (d *DeployFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
 	d.BindNonCommon(local, global)
 	d.bindCommon(local, global)
@@ -76,12 +94,18 @@ func (d *DeployFlags) bindCommon(local *pflag.FlagSet, global *internal.GlobalCo
 		false,
 		"Deploys all services that are listed in "+azdcontext.ProjectFileName,
 	)
+	local.StringSliceVar(
+		&d.Tags,
+		"tag",
+		nil,
+		"Deploys the services with the tag. Prefix a tag with ! to skip the services with the tag. Can be repeated.",
+	)
 	local.StringVar(
 		&d.fromPackage,
 		"from-package",
 		"",
 		//nolint:lll
-		"Deploys the packaged service located at the provided path. Supports zipped file packages (file path) or container images (image tag).",
+		"Deploys the packaged service located at the provided path. Supports zipped file packages (file path), container images (image tag), packages pushed to the artifact store (sha256:<digest>/<file name>) or the package manifest written by azd package (JSON file path).",
 	)
 	local.IntVar(
 		&d.Timeout,
@@ -93,6 +117,32 @@ func (d *DeployFlags) bindCommon(local *pflag.FlagSet, global *internal.GlobalCo
 			defaultDeployTimeoutSeconds,
 		),
 	)
+	local.BoolVar(
+		&d.RefreshOutputs,
+		"refresh-outputs",
+		false,
+		"Refreshes the environment from the provisioning outputs before deploying, so that services are deployed with "+
+			"up-to-date values. Can be enabled by default with 'azd config set deploy.refreshOutputs on'.",
+	)
+	local.BoolVar(
+		&d.Force,
+		"force",
+		false,
+		"Deploys all services, including the services whose package is unchanged since their last deploy.",
+	)
+	local.BoolVar(
+		&d.Rollback,
+		"rollback",
+		false,
+		"Rolls the service back to the package that was deployed before its last deployment.",
+	)
+	local.StringVar(
+		&d.image,
+		"image",
+		"",
+		"Deploys the service from an image in a container registry, referenced by digest "+
+			"(<registry>/<repository>@sha256:<digest>), without building or packaging it.",
+	)
 }
 
 func (d *DeployFlags) SetCommon(envFlag *internal.EnvFlag) {
@@ -145,6 +195,7 @@ type DeployAction struct {
 	resourceManager     project.ResourceManager
 	accountManager      account.Manager
 	azCli               *azapi.AzureClient
+	resourceService     *azapi.ResourceService
 	portalUrlBase       string
 	formatter           output.Formatter
 	writer              io.Writer
@@ -152,7 +203,12 @@ type DeployAction struct {
 	commandRunner       exec.CommandRunner
 	alphaFeatureManager *alpha.FeatureManager
 	importManager       *project.ImportManager
+	artifactManager     *artifacts.Manager
+	userConfigManager   config.UserConfigManager
+	workflowRunner      *workflow.Runner
+	reporter            progress.Reporter
 	progressTracker     *deployProgressTracker // set at runtime when using parallel deployment graph
+	stepReporter        *deployStepReporter    // set at runtime when using parallel deployment graph
 }
 
 func NewDeployAction(
@@ -170,10 +226,15 @@ func NewDeployAction(
 	azCli *azapi.AzureClient,
 	commandRunner exec.CommandRunner,
 	console input.Console,
+	reporter progress.Reporter,
 	formatter output.Formatter,
 	writer io.Writer,
 	alphaFeatureManager *alpha.FeatureManager,
 	importManager *project.ImportManager,
+	artifactManager *artifacts.Manager,
+	userConfigManager config.UserConfigManager,
+	workflowRunner *workflow.Runner,
+	resourceService *azapi.ResourceService,
 ) actions.Action {
 	return &DeployAction{
 		flags:               flags,
@@ -191,9 +252,14 @@ func NewDeployAction(
 		formatter:           formatter,
 		writer:              writer,
 		console:             console,
+		reporter:            reporter,
 		commandRunner:       commandRunner,
 		alphaFeatureManager: alphaFeatureManager,
 		importManager:       importManager,
+		artifactManager:     artifactManager,
+		userConfigManager:   userConfigManager,
+		workflowRunner:      workflowRunner,
+		resourceService:     resourceService,
 	}
 }
 
@@ -215,39 +281,91 @@ func (da *DeployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		}
 	}
 
-	targetServiceName, err := getTargetServiceName(
+	tagFilter, err := project.ParseServiceTagFilter(da.flags.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if tagFilter != nil && targetServiceName != "" {
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        fmt.Errorf("cannot specify both --tag and <service>: %w", internal.ErrInvalidFlagCombination),
+			Suggestion: "Use either 'azd deploy <service>' or 'azd deploy --tag <tag>'.",
+		}
+	}
+
+	targetServiceName, err = getTargetServiceName(
 		ctx,
 		da.projectManager,
 		da.importManager,
 		da.projectConfig,
 		string(project.ServiceEventDeploy),
 		targetServiceName,
-		da.flags.All,
+		da.flags.All || tagFilter != nil,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	if da.flags.All && da.flags.fromPackage != "" {
+	// A package manifest holds the packages of several services, so it can be deployed to all of them.
+	manifestPath := ""
+	if project.IsPackageManifest(da.flags.fromPackage) {
+		manifestPath = da.flags.fromPackage
+	}
+
+	if da.flags.All && da.flags.fromPackage != "" && manifestPath == "" {
 		return nil, &internal.ErrorWithSuggestion{
 			Err:        internal.ErrFromPackageWithAll,
 			Suggestion: "Use 'azd deploy <service> --from-package <path>' to target a specific service.",
 		}
 	}
 
-	if targetServiceName == "" && da.flags.fromPackage != "" {
+	if targetServiceName == "" && da.flags.fromPackage != "" && manifestPath == "" {
 		return nil, &internal.ErrorWithSuggestion{
 			Err:        internal.ErrFromPackageNoService,
 			Suggestion: "Use 'azd deploy <service> --from-package <path>' to target a specific service.",
 		}
 	}
 
+	if da.flags.image != "" {
+		if err := da.validateImage(targetServiceName); err != nil {
+			return nil, err
+		}
+	}
+
+	fromPackage := da.flags.fromPackage
+	if da.flags.Rollback {
+		previous, err := da.rollbackPackage(targetServiceName)
+		if err != nil {
+			return nil, err
+		}
+
+		fromPackage = previous
+	} else if manifestPath != "" {
+		fromPackage = ""
+	} else if artifacts.IsReference(fromPackage) {
+		packagePath, err := da.pullPackage(ctx, fromPackage)
+		if packagePath != "" {
+			defer os.RemoveAll(filepath.Dir(packagePath))
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		fromPackage = packagePath
+	}
+
+	if da.refreshOutputsEnabled() {
+		if err := da.refreshOutputs(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := da.projectManager.Initialize(ctx, da.projectConfig); err != nil {
 		return nil, err
 	}
 
 	if err := da.projectManager.EnsureServiceTargetTools(ctx, da.projectConfig, func(svc *project.ServiceConfig) bool {
-		return targetServiceName == "" || svc.Name == targetServiceName
+		return (targetServiceName == "" || svc.Name == targetServiceName) && tagFilter.Matches(svc)
 	}); err != nil {
 		return nil, err
 	}
@@ -264,10 +382,113 @@ func (da *DeployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		return nil, err
 	}
 
+	stableServices, err = tagFilter.Filter(stableServices)
+	if err != nil {
+		return nil, err
+	}
+
+	fromPackages := map[string]*project.Artifact{}
+	if manifestPath != "" {
+		var cleanup func()
+		fromPackages, cleanup, err = da.manifestPackages(ctx, manifestPath, stableServices)
+		defer cleanup()
+		if err != nil {
+			return nil, err
+		}
+	} else if da.flags.image != "" {
+		// The image is already in its registry, so the service isn't packaged, and the image isn't pushed again.
+		fromPackages[targetServiceName] = &project.Artifact{
+			Kind:         project.ArtifactKindContainer,
+			Location:     da.flags.image,
+			LocationKind: project.LocationKindRemote,
+		}
+	} else if fromPackage != "" {
+		fromPackages[targetServiceName] = &project.Artifact{
+			Kind:         determineArtifactKind(fromPackage),
+			Location:     fromPackage,
+			LocationKind: project.LocationKindLocal,
+		}
+	}
+
 	// Always deploy through the service execution graph. The graph handles
 	// any service count (including N=1) with a uniform progress tracker
 	// and the same package → publish → deploy step topology.
-	return da.deployServicesGraph(ctx, stableServices, startTime)
+	return da.deployServicesGraph(ctx, stableServices, fromPackages, startTime)
+}
+
+// refreshOutputsEnabled returns whether the environment is refreshed from the provisioning outputs before deploying,
+// with the --refresh-outputs flag or the deploy.refreshOutputs user configuration.
+func (da *DeployAction) refreshOutputsEnabled() bool {
+	if da.flags.RefreshOutputs {
+		return true
+	}
+
+	if da.userConfigManager == nil {
+		return false
+	}
+
+	userConfig, err := da.userConfigManager.Load()
+	if err != nil {
+		log.Printf("failed to load user config, not refreshing outputs: %v", err)
+		return false
+	}
+
+	value, _ := userConfig.GetString(refreshOutputsConfigKey)
+	return value == "on"
+}
+
+// refreshOutputs runs `azd env refresh` for the environment, and reloads the environment with the refreshed values.
+func (da *DeployAction) refreshOutputs(ctx context.Context) error {
+	err := da.workflowRunner.Run(ctx, &workflow.Workflow{
+		Name: "deploy",
+		Steps: []*workflow.Step{
+			{AzdCommand: workflow.Command{Args: []string{"env", "refresh", "-e", da.env.Name()}}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("refreshing environment '%s' from the provisioning outputs: %w", da.env.Name(), err)
+	}
+
+	if err := da.envManager.Reload(ctx, da.env); err != nil {
+		return fmt.Errorf("reloading environment '%s': %w", da.env.Name(), err)
+	}
+
+	return nil
+}
+
+// pullPackage downloads the package identified by an artifact store reference to a temporary directory, and
+// returns the path of the downloaded package.
+func (da *DeployAction) pullPackage(ctx context.Context, reference string) (string, error) {
+	ref, err := artifacts.ParseReference(reference)
+	if err != nil {
+		return "", &internal.ErrorWithSuggestion{
+			Err:        fmt.Errorf("%w: %w", internal.ErrInvalidArgValue, err),
+			Suggestion: "Use the package reference printed by 'azd package --push'.",
+		}
+	}
+
+	if da.artifactManager == nil || !da.artifactManager.Configured() {
+		return "", &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("deploying package %s: %w", ref, artifacts.ErrStoreNotConfigured),
+			Suggestion: fmt.Sprintf(
+				"Configure the artifact store in the 'artifacts' section of %s.", azdcontext.ProjectFileName),
+		}
+	}
+
+	tempDir, err := os.MkdirTemp("", "azd-package")
+	if err != nil {
+		return "", err
+	}
+
+	da.console.ShowSpinner(ctx, fmt.Sprintf("Pulling package %s", ref.Name), input.Step)
+	packagePath, err := da.artifactManager.Pull(ctx, ref, tempDir)
+	da.console.StopSpinner(ctx, "", input.GetStepResultFormat(err))
+	if err != nil {
+		_ = os.RemoveAll(tempDir)
+		return "", err
+	}
+
+	return packagePath, nil
 }
 
 // deployServicesGraph builds an execution graph of service deployments and runs them in
@@ -281,6 +502,7 @@ func (da *DeployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 func (da *DeployAction) deployServicesGraph(
 	ctx context.Context,
 	stableServices []*project.ServiceConfig,
+	fromPackages map[string]*project.Artifact,
 	startTime time.Time,
 ) (*actions.ActionResult, error) {
 	deployTimeout, err := da.resolveDeployTimeout()
@@ -321,19 +543,24 @@ func (da *DeployAction) deployServicesGraph(
 		// Still wrap the console for thread-safety without suppressing spinners.
 		da.console = sc
 	}
+	// The progress table owns the console view of the deployment; the other progress frontends receive the
+	// per-service steps.
+	da.stepReporter = newDeployStepReporter(ctx, progress.Headless(da.reporter))
 	defer func() {
 		da.console = origConsole
 		da.progressTracker = nil
+		da.stepReporter = nil
 	}()
 
 	g := exegraph.NewGraph()
 	state := newDeployGraphState(stableServices)
+	history := project.NewDeploymentHistory(da.env, da.azdCtx.EnvironmentRoot(da.env.Name()))
 
 	if _, err := addServiceStepsToGraph(g, serviceGraphOptions{
 		services:       stableServices,
 		serviceManager: da.serviceManager,
 		deployTimeout:  deployTimeout,
-		fromPackage:    da.flags.fromPackage,
+		fromPackages:   fromPackages,
 		state:          state,
 		onDeployTimeout: func(ctx context.Context, svc *project.ServiceConfig) {
 			da.console.MessageUxItem(ctx, deployTimeoutWarning(svc.Name, deployTimeout))
@@ -346,6 +573,13 @@ func (da *DeployAction) deployServicesGraph(
 			// when the tracker is nil (JSON output / no writer paths).
 			da.updateProgress(svcName, phase, detail)
 		},
+		// A package given with --from-package has no known hash, and is
+		// always deployed.
+		trackPackageHashes:  len(fromPackages) == 0,
+		env:                 da.env,
+		skipUnchanged:       da.skipUnchanged(),
+		resourceCreatedTime: resourceCreatedTime(da.resourceService),
+		history:             history,
 	}); err != nil {
 		return nil, err
 	}
@@ -389,7 +623,11 @@ func (da *DeployAction) deployServicesGraph(
 				}
 			}
 			if svc, ok := strings.CutPrefix(stepName, "deploy-"); ok {
-				da.updateProgress(svc, phaseDone, "")
+				if state.IsUnchanged(svc) {
+					da.updateProgress(svc, phaseSkipped, "no changes")
+				} else {
+					da.updateProgress(svc, phaseDone, "")
+				}
 			}
 		},
 	}
@@ -427,11 +665,22 @@ func (da *DeployAction) deployServicesGraph(
 		da.progressTracker.RenderFinal()
 	}
 
+	// Record the packages of the deployed services, so they can be rolled
+	// back, before the temporary packages are removed.
+	state.RecordDeployments(history, stableServices)
+
 	// Clean up temporary package artifacts created during graph execution.
-	if da.flags.fromPackage == "" {
+	if len(fromPackages) == 0 {
 		state.CleanupTempArtifacts()
 	}
 
+	// Record the package hashes of the deployed services, including when
+	// another service failed, so the next deploy skips the unchanged ones.
+	state.RecordPackageHashes(da.env, stableServices)
+	if saveErr := da.envManager.Save(ctx, da.env); saveErr != nil {
+		log.Printf("warning: failed to save deployment history: %v", saveErr)
+	}
+
 	if err != nil {
 		return nil, err
 	}
@@ -439,9 +688,16 @@ func (da *DeployAction) deployServicesGraph(
 	// Display service endpoint artifacts collected during deploy steps.
 	if da.formatter.Kind() != output.JsonFormat {
 		for _, svc := range stableServices {
-			if dr := state.GetResult(svc.Name); dr != nil && len(dr.Artifacts) > 0 {
+			dr := state.GetResult(svc.Name)
+			if dr == nil {
+				continue
+			}
+			if len(dr.Artifacts) > 0 {
 				da.console.MessageUxItem(ctx, dr.Artifacts)
 			}
+			if dr.HealthCheck != nil {
+				da.console.MessageUxItem(ctx, dr.HealthCheck)
+			}
 		}
 	}
 
@@ -484,6 +740,187 @@ func (da *DeployAction) deployServicesGraph(
 	}, nil
 }
 
+// manifestPackages returns the packages of the services from the package manifest at manifestPath. Packages that are no
+// longer on the local disk are pulled from the artifact store when they were pushed, to temporary directories that
+// cleanup removes.
+func (da *DeployAction) manifestPackages(
+	ctx context.Context,
+	manifestPath string,
+	services []*project.ServiceConfig,
+) (map[string]*project.Artifact, func(), error) {
+	var tempDirs []string
+	cleanup := func() {
+		for _, tempDir := range tempDirs {
+			_ = os.RemoveAll(tempDir)
+		}
+	}
+
+	manifest, err := project.LoadPackageManifest(manifestPath)
+	if err != nil {
+		return nil, cleanup, &internal.ErrorWithSuggestion{
+			Err:        fmt.Errorf("%w: %w", internal.ErrInvalidArgValue, err),
+			Suggestion: "Use the package manifest written by 'azd package --output-path <folder>'.",
+		}
+	}
+
+	if manifest.Git != nil {
+		log.Printf("deploying the packages of commit %s of project %s", manifest.Git.Commit, manifest.Project)
+	}
+
+	manifestDir := filepath.Dir(manifestPath)
+	packages := map[string]*project.Artifact{}
+	for _, svc := range services {
+		manifestService, has := manifest.Services[svc.Name]
+		if !has {
+			return nil, cleanup, &internal.ErrorWithSuggestion{
+				Err: fmt.Errorf("service '%s' is not in the package manifest %s: %w",
+					svc.Name, manifestPath, internal.ErrInvalidArgValue),
+				Suggestion: "Package the service with 'azd package', or select the services of the manifest with " +
+					"'azd deploy <service> --from-package <manifest>'.",
+			}
+		}
+
+		if manifestService.Host != svc.Host {
+			return nil, cleanup, fmt.Errorf(
+				"service '%s' was packaged for host '%s', but is now configured for host '%s'",
+				svc.Name, manifestService.Host, svc.Host)
+		}
+
+		manifestArtifact, err := manifestService.Package()
+		if err != nil {
+			return nil, cleanup, fmt.Errorf("service '%s': %w", svc.Name, err)
+		}
+
+		artifact, err := manifestArtifact.LocalArtifact(manifestArtifact.Location(manifestDir))
+		reference := manifestArtifact.Metadata[project.MetadataKeyReference]
+		if errors.Is(err, os.ErrNotExist) && reference != "" {
+			log.Printf("package of service %s not found, pulling %s", svc.Name, reference)
+			packagePath, pullErr := da.pullPackage(ctx, reference)
+			if packagePath != "" {
+				tempDirs = append(tempDirs, filepath.Dir(packagePath))
+			}
+			if pullErr != nil {
+				return nil, cleanup, pullErr
+			}
+
+			artifact, err = manifestArtifact.LocalArtifact(packagePath)
+		}
+		if err != nil {
+			return nil, cleanup, fmt.Errorf("package of service '%s': %w", svc.Name, err)
+		}
+
+		packages[svc.Name] = artifact
+	}
+
+	return packages, cleanup, nil
+}
+
+// validateImage validates the --image flag: a single service hosted on Container Apps or App Service is deployed from
+// an image referenced by digest.
+func (da *DeployAction) validateImage(serviceName string) error {
+	if da.flags.fromPackage != "" || da.flags.Rollback {
+		return &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf(
+				"cannot specify --image with --from-package or --rollback: %w", internal.ErrInvalidFlagCombination),
+			Suggestion: "Use either 'azd deploy <service> --image <image>', 'azd deploy <service> --from-package <path>' " +
+				"or 'azd deploy <service> --rollback'.",
+		}
+	}
+
+	if serviceName == "" {
+		return &internal.ErrorWithSuggestion{
+			Err:        internal.ErrImageNoService,
+			Suggestion: "Use 'azd deploy <service> --image <image>' to deploy a specific service.",
+		}
+	}
+
+	name, _, ok := docker.SplitImageDigest(da.flags.image)
+	if ok {
+		parsedImage, err := docker.ParseContainerImage(name)
+		ok = err == nil && parsedImage.Registry != ""
+	}
+	if !ok {
+		return &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("image '%s' is not referenced by digest: %w", da.flags.image, internal.ErrInvalidArgValue),
+			Suggestion: "Reference the image by its registry and digest, such as " +
+				"'myregistry.azurecr.io/api@sha256:<digest>'.",
+		}
+	}
+
+	if svc, has := da.projectConfig.Services[serviceName]; has &&
+		svc.Host != project.ContainerAppTarget && svc.Host != project.AppServiceTarget {
+		return fmt.Errorf(
+			"service '%s' is hosted on '%s', --image only deploys services hosted on '%s' or '%s': %w",
+			serviceName, svc.Host, project.ContainerAppTarget, project.AppServiceTarget, internal.ErrInvalidArgValue)
+	}
+
+	return nil
+}
+
+// rollbackPackage returns the package deployed to the service before its last deployment, which --rollback deploys.
+func (da *DeployAction) rollbackPackage(serviceName string) (string, error) {
+	if da.flags.fromPackage != "" {
+		return "", &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf(
+				"cannot specify both --rollback and --from-package: %w", internal.ErrInvalidFlagCombination),
+			Suggestion: "Use either 'azd deploy <service> --rollback' or 'azd deploy <service> --from-package <path>'.",
+		}
+	}
+
+	if serviceName == "" {
+		return "", &internal.ErrorWithSuggestion{
+			Err:        internal.ErrRollbackNoService,
+			Suggestion: "Use 'azd deploy <service> --rollback' to roll back a specific service.",
+		}
+	}
+
+	previous := project.NewDeploymentHistory(da.env, da.azdCtx.EnvironmentRoot(da.env.Name())).Previous(serviceName)
+	if previous == "" {
+		return "", &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("service '%s': %w", serviceName, internal.ErrNoPreviousDeployment),
+			Suggestion: "A service can be rolled back once it was deployed twice with azd. Container images " +
+				"are only recorded for container apps and app services, and zip packages for the other hosts.",
+		}
+	}
+
+	// Package archives are saved as absolute paths, container images are references to a registry.
+	if filepath.IsAbs(previous) {
+		if _, err := os.Stat(previous); err != nil {
+			return "", &internal.ErrorWithSuggestion{
+				Err:        fmt.Errorf("previous package of service '%s' not found: %w", serviceName, err),
+				Suggestion: fmt.Sprintf("Redeploy the package with 'azd deploy %s --from-package <path>'.", serviceName),
+			}
+		}
+	}
+
+	log.Printf("rolling back service %s to package %s", serviceName, previous)
+	return previous, nil
+}
+
+// skipUnchanged returns the policy that skips the services whose deploy hash matches the hash recorded by their last
+// deploy, or nil when --force deploys every service.
+func (da *DeployAction) skipUnchanged() func(svc *project.ServiceConfig, packageHash string) bool {
+	if da.flags.Force {
+		return nil
+	}
+
+	return func(svc *project.ServiceConfig, packageHash string) bool {
+		return da.env.GetServiceProperty(svc.Name, project.PackageHashProperty) == packageHash
+	}
+}
+
+// resourceCreatedTime returns the function that gets the creation time of the resource a service is deployed to, or nil
+// when resourceService is nil.
+func resourceCreatedTime(
+	resourceService *azapi.ResourceService,
+) func(ctx context.Context, resourceId *arm.ResourceID) (time.Time, error) {
+	if resourceService == nil {
+		return nil
+	}
+
+	return resourceService.GetCreatedTime
+}
+
 // resolveDAGConcurrency reads AZD_DEPLOY_CONCURRENCY from the environment.
 // Returns 0 (unlimited) if the variable is unset or invalid.
 func (da *DeployAction) resolveDAGConcurrency() int {
@@ -544,6 +981,18 @@ func GetCmdDeployHelpDescription(*cobra.Command) string {
 				" or the service described in the project that matches the current directory."),
 		formatHelpNote(
 			fmt.Sprintf("When %s is set, only the specific service is deployed.", output.WithHighLightFormat("<service>"))),
+		formatHelpNote(
+			fmt.Sprintf("When %s is set, only the services with matching tags are deployed.",
+				output.WithHighLightFormat("--tag"))),
+		formatHelpNote("Services whose package is unchanged since their last deploy are skipped." +
+			fmt.Sprintf(" Use %s to deploy them anyway.", output.WithHighLightFormat("--force"))),
+		formatHelpNote(
+			fmt.Sprintf("When %s is set, the service is redeployed with the package deployed before its last deployment.",
+				output.WithHighLightFormat("--rollback"))),
+		formatHelpNote(
+			fmt.Sprintf("When %s is set, the service is deployed from an image already in a container registry,"+
+				" without building or packaging it. The image must exist in the registry.",
+				output.WithHighLightFormat("--image"))),
 		formatHelpNote("After the deployment is complete, the endpoint is printed. To start the service, select" +
 			" the endpoint or paste it in a browser."),
 	})
@@ -563,15 +1012,36 @@ func GetCmdDeployHelpFooter(*cobra.Command) string {
 		"Deploy the service named 'api' to Azure from a previously generated package.": output.WithHighLightFormat(
 			"azd deploy api --from-package <package-path>",
 		),
+		"Deploy the service named 'api' to Azure from a package pushed to the artifact store.": output.WithHighLightFormat(
+			"azd deploy api --from-package sha256:<digest>/<file name>",
+		),
+		"Deploy all services to Azure from the packages of a package manifest.": output.WithHighLightFormat(
+			"azd deploy --all --from-package ./dist/" + project.PackageManifestFileName,
+		),
+		"Deploy the service named 'api' to Azure from an image pushed to a registry.": output.WithHighLightFormat(
+			"azd deploy api --image myregistry.azurecr.io/api@sha256:<digest>",
+		),
+		"Deploy the services tagged 'frontend' to Azure.": output.WithHighLightFormat(
+			"azd deploy --tag frontend",
+		),
+		"Deploy all services to Azure, including the services that are unchanged.": output.WithHighLightFormat(
+			"azd deploy --all --force",
+		),
+		"Roll the service named 'api' back to the package deployed before its last deployment.": output.WithHighLightFormat(
+			"azd deploy api --rollback",
+		),
 	})
 }
 
-// updateProgress notifies the progress tracker if it is active.
-// When the tracker is nil (single-service path), this is a no-op.
+// updateProgress notifies the progress tracker and the progress reporter if they are active.
+// When they are nil (single-service path), this is a no-op.
 func (da *DeployAction) updateProgress(serviceName string, phase deployPhase, detail string) {
 	if da.progressTracker != nil {
 		da.progressTracker.Update(serviceName, phase, detail)
 	}
+	if da.stepReporter != nil {
+		da.stepReporter.Update(serviceName, phase, detail)
+	}
 }
 
 // unwrapStepErrors extracts the inner (action-level) errors from a graph
@@ -5,6 +5,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"regexp"
@@ -12,6 +13,8 @@ import (
 	"sync"
 	"time"
 	"unicode"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/progress"
 )
 
 // Column widths for the progress table.
@@ -337,3 +340,52 @@ func phaseIcon(phase deployPhase) string {
 		return " "
 	}
 }
+
+// deployStepReporter reports the phases of each service deployment as structured progress steps, so frontends other
+// than the progress table (the JSON event stream and the extension / IDE APIs) see the same lifecycle.
+type deployStepReporter struct {
+	ctx      context.Context
+	reporter progress.Reporter
+
+	mu    sync.Mutex
+	steps map[string]*progress.Step
+}
+
+func newDeployStepReporter(ctx context.Context, reporter progress.Reporter) *deployStepReporter {
+	return &deployStepReporter{
+		ctx:      ctx,
+		reporter: reporter,
+		steps:    map[string]*progress.Step{},
+	}
+}
+
+// Update reports a service's phase and optional detail message, starting the service's step on its first update.
+func (r *deployStepReporter) Update(serviceName string, phase deployPhase, detail string) {
+	if phase == phaseWaiting {
+		return
+	}
+
+	r.mu.Lock()
+	step, has := r.steps[serviceName]
+	if !has {
+		step = progress.Start(
+			r.ctx, r.reporter, progress.PhaseDeploy, serviceName, fmt.Sprintf("Deploying service %s", serviceName))
+		r.steps[serviceName] = step
+	}
+	r.mu.Unlock()
+
+	switch phase {
+	case phaseDone:
+		step.Done(nil)
+	case phaseFailed:
+		step.Done(errors.New(detail))
+	case phaseSkipped:
+		step.Skip(detail)
+	default:
+		message := string(phase)
+		if detail != "" {
+			message = fmt.Sprintf("%s: %s", phase, detail)
+		}
+		step.Update(message)
+	}
+}
@@ -5,10 +5,13 @@ package cmd
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/progress"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -233,3 +236,35 @@ func TestDeployProgressTracker_HasActivity_NoServices(t *testing.T) {
 	// Empty tracker has no activity.
 	assert.False(t, tracker.HasActivity())
 }
+
+func TestDeployStepReporter(t *testing.T) {
+	var events []progress.Event
+	reporter := newDeployStepReporter(t.Context(), progress.ReporterFunc(
+		func(_ context.Context, event progress.Event) {
+			events = append(events, event)
+		}))
+
+	reporter.Update("web", phaseWaiting, "")
+	reporter.Update("web", phasePackaging, "")
+	reporter.Update("web", phaseDeploying, "Pushing image")
+	reporter.Update("web", phaseDone, "")
+	reporter.Update("api", phaseFailed, "boom")
+	reporter.Update("worker", phaseSkipped, "canceled")
+
+	summary := make([]string, len(events))
+	for i, e := range events {
+		require.Equal(t, progress.PhaseDeploy, e.Phase)
+		summary[i] = fmt.Sprintf("%s %s %s %s", e.Step, e.Kind, e.Status, e.Message)
+	}
+
+	require.Equal(t, []string{
+		"deploy/web stepStarted running ",
+		"deploy/web stepUpdated running Packaging",
+		"deploy/web stepUpdated running Deploying: Pushing image",
+		"deploy/web stepCompleted succeeded ",
+		"deploy/api stepStarted running ",
+		"deploy/api stepCompleted failed boom",
+		"deploy/worker stepStarted running ",
+		"deploy/worker stepCompleted skipped canceled",
+	}, summary)
+}
@@ -7,6 +7,8 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
@@ -14,12 +16,17 @@ import (
 
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/artifacts"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -308,6 +315,13 @@ type mockDeployServiceManager struct {
 	deployHasDeadline bool
 	deployErr         error
 	waitForTimeout    bool
+	// deployedPackage is the content of the package file that was deployed.
+	deployedPackage string
+	// deployedArtifact is the package artifact that was deployed.
+	deployedArtifact *project.Artifact
+	// packageContent, when set, is the content of the archive package produced by Package.
+	packageContent string
+	packageDir     string
 }
 
 func (m *mockDeployServiceManager) GetRequiredTools(
@@ -352,7 +366,25 @@ func (m *mockDeployServiceManager) Package(
 	progress *async.Progress[project.ServiceProgress],
 	options *project.PackageOptions,
 ) (*project.ServicePackageResult, error) {
-	return &project.ServicePackageResult{}, nil
+	if m.packageContent == "" {
+		return &project.ServicePackageResult{}, nil
+	}
+
+	packagePath := filepath.Join(m.packageDir, serviceConfig.Name+".zip")
+	if err := os.WriteFile(packagePath, []byte(m.packageContent), 0600); err != nil {
+		return nil, err
+	}
+
+	artifact := &project.Artifact{
+		Kind:         project.ArtifactKindArchive,
+		Location:     packagePath,
+		LocationKind: project.LocationKindLocal,
+	}
+	if err := serviceContext.Package.Add(artifact); err != nil {
+		return nil, err
+	}
+
+	return &project.ServicePackageResult{Artifacts: project.ArtifactCollection{artifact}}, nil
 }
 
 func (m *mockDeployServiceManager) Publish(
@@ -374,6 +406,13 @@ func (m *mockDeployServiceManager) Deploy(
 	m.deployDeadline, m.deployHasDeadline = ctx.Deadline()
 	m.Called(serviceConfig.Name)
 
+	if serviceContext != nil && len(serviceContext.Package) > 0 {
+		m.deployedArtifact = serviceContext.Package[0]
+		if content, err := os.ReadFile(serviceContext.Package[0].Location); err == nil {
+			m.deployedPackage = string(content)
+		}
+	}
+
 	if m.waitForTimeout {
 		<-ctx.Done()
 		return nil, ctx.Err()
@@ -408,6 +447,316 @@ func (m *mockDeployServiceManager) GetServiceTarget(
 	return nil, nil
 }
 
+func TestDeployActionRunTagFilter(t *testing.T) {
+	t.Parallel()
+
+	projectYaml := `name: test-proj
+services:
+  api:
+    project: src/api
+    language: js
+    host: containerapp
+    tags: [backend]
+  web:
+    project: src/web
+    language: js
+    host: containerapp
+    tags: [frontend]
+`
+
+	tests := []struct {
+		name         string
+		args         []string
+		flags        []string
+		wantDeployed string
+		wantErr      string
+	}{
+		{
+			name:         "Include",
+			flags:        []string{"--tag", "frontend"},
+			wantDeployed: "web",
+		},
+		{
+			name:         "Exclude",
+			flags:        []string{"--tag", "!frontend"},
+			wantDeployed: "api",
+		},
+		{
+			name:    "NoMatch",
+			flags:   []string{"--tag", "database"},
+			wantErr: "no services match the tag filter 'database'",
+		},
+		{
+			name:    "WithServiceName",
+			args:    []string{"web"},
+			flags:   []string{"--tag", "frontend"},
+			wantErr: "cannot specify both --tag and <service>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			projectConfig, err := project.Parse(t.Context(), projectYaml)
+			require.NoError(t, err)
+
+			cmd := NewDeployCmd()
+			flags := NewDeployFlags(cmd, &internal.GlobalCommandOptions{})
+			require.NoError(t, cmd.ParseFlags(tt.flags))
+
+			env := environment.New("test-env")
+			env.SetSubscriptionId("subscription-id")
+
+			projectManager := &mockDeployProjectManager{}
+			projectManager.On("Initialize", projectConfig).Return(nil).Maybe()
+			projectManager.On("EnsureServiceTargetTools", projectConfig).Return(nil).Maybe()
+
+			deployErr := mockDeployErr(t.Name())
+			serviceManager := &mockDeployServiceManager{deployErr: deployErr}
+			if tt.wantDeployed != "" {
+				serviceManager.On("Deploy", tt.wantDeployed).Return().Once()
+			}
+
+			action := &DeployAction{
+				flags:               flags,
+				args:                tt.args,
+				projectConfig:       projectConfig,
+				azdCtx:              azdcontext.NewAzdContextWithDirectory(t.TempDir()),
+				env:                 env,
+				importManager:       project.NewImportManager(nil),
+				envManager:          newDeployTestEnvManager(),
+				projectManager:      projectManager,
+				serviceManager:      serviceManager,
+				console:             mockinput.NewMockConsole(),
+				formatter:           &output.NoneFormatter{},
+				writer:              io.Discard,
+				alphaFeatureManager: alpha.NewFeaturesManagerWithConfig(config.NewEmptyConfig()),
+			}
+
+			_, err = action.Run(t.Context())
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+			} else {
+				require.ErrorIs(t, err, deployErr)
+			}
+
+			serviceManager.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeployActionFromPackageReference(t *testing.T) {
+	t.Parallel()
+
+	storeConfig := &artifacts.Config{
+		Store: &artifacts.StoreConfig{
+			Kind:   artifacts.StoreKindLocal,
+			Config: map[string]any{"path": t.TempDir()},
+		},
+	}
+	container := ioc.NewNestedContainer(nil)
+	container.MustRegisterNamedSingleton(string(artifacts.StoreKindLocal), func() (artifacts.Store, error) {
+		return artifacts.NewLocalStore(storeConfig, nil)
+	})
+	artifactManager := artifacts.NewManager(storeConfig, container)
+
+	packagePath := filepath.Join(t.TempDir(), "api.zip")
+	require.NoError(t, os.WriteFile(packagePath, []byte("package content"), 0600))
+	ref, err := artifactManager.Push(t.Context(), packagePath)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		fromPackage     string
+		artifactManager *artifacts.Manager
+		wantErr         error
+	}{
+		{name: "Pulled", fromPackage: ref.String(), artifactManager: artifactManager},
+		{
+			name:            "NotFound",
+			fromPackage:     artifacts.Reference{Digest: strings.Repeat("0", 64), Name: "api.zip"}.String(),
+			artifactManager: artifactManager,
+			wantErr:         artifacts.ErrArtifactNotFound,
+		},
+		{
+			name:            "StoreNotConfigured",
+			fromPackage:     ref.String(),
+			artifactManager: artifacts.NewManager(nil, container),
+			wantErr:         artifacts.ErrStoreNotConfigured,
+		},
+		{
+			name:            "InvalidReference",
+			fromPackage:     "sha256:abcd/api.zip",
+			artifactManager: artifactManager,
+			wantErr:         internal.ErrInvalidArgValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			deployErr := mockDeployErr(t.Name())
+			action, serviceManager := newDeployActionForFromPackageTest(t, tt.fromPackage, deployErr, tt.wantErr == nil)
+			action.artifactManager = tt.artifactManager
+
+			_, err := action.Run(t.Context())
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			// The deployment itself fails, after the pulled package was handed to the service target.
+			require.ErrorIs(t, err, deployErr)
+			require.Equal(t, "package content", serviceManager.deployedPackage)
+		})
+	}
+}
+
+func TestDeployActionSkipsUnchangedServices(t *testing.T) {
+	t.Parallel()
+
+	env := environment.New("test-env")
+	env.SetSubscriptionId("subscription-id")
+	packageDir := t.TempDir()
+
+	deploy := func(t *testing.T, content string, wantDeploy bool, flags ...string) {
+		action := newDeployTimeoutAction(t, nil)
+		action.env = env
+		action.formatter = &output.JsonFormatter{}
+		action.projectConfig.Services["api"].Environment = osutil.ExpandableMap{
+			"API_URL": osutil.NewExpandableString("${API_URL}"),
+		}
+		require.NoError(t, action.flags.flagSet.Parse(flags))
+
+		projectManager := &mockDeployProjectManager{}
+		projectManager.On("Initialize", action.projectConfig).Return(nil).Once()
+		projectManager.On("EnsureServiceTargetTools", action.projectConfig).Return(nil).Once()
+
+		serviceManager := &mockDeployServiceManager{packageContent: content, packageDir: packageDir}
+		if wantDeploy {
+			serviceManager.On("Deploy", "api").Return().Once()
+		}
+
+		action.projectManager = projectManager
+		action.serviceManager = serviceManager
+
+		_, err := action.Run(t.Context())
+		require.NoError(t, err)
+		serviceManager.AssertExpectations(t)
+		require.NotEmpty(t, env.GetServiceProperty("api", project.PackageHashProperty))
+	}
+
+	deploy(t, "v1", true)
+	hash := env.GetServiceProperty("api", project.PackageHashProperty)
+
+	// Unchanged package: skipped.
+	deploy(t, "v1", false)
+	require.Equal(t, hash, env.GetServiceProperty("api", project.PackageHashProperty))
+
+	// --force deploys the unchanged package.
+	deploy(t, "v1", true, "--force")
+	require.Equal(t, hash, env.GetServiceProperty("api", project.PackageHashProperty))
+
+	// Changed package: deployed, and the new hash recorded.
+	deploy(t, "v2", true)
+	require.NotEqual(t, hash, env.GetServiceProperty("api", project.PackageHashProperty))
+	hash = env.GetServiceProperty("api", project.PackageHashProperty)
+
+	// Unchanged package, but a changed value of the environment the service reads: deployed.
+	env.DotenvSet("API_URL", "https://api.contoso.com")
+	deploy(t, "v2", true)
+	require.NotEqual(t, hash, env.GetServiceProperty("api", project.PackageHashProperty))
+}
+
+func TestDeployActionRollback(t *testing.T) {
+	t.Parallel()
+
+	env := environment.New("test-env")
+	env.SetSubscriptionId("subscription-id")
+	azdCtx := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+	packageDir := t.TempDir()
+
+	deploy := func(t *testing.T, content string, flags ...string) (*mockDeployServiceManager, error) {
+		action := newDeployTimeoutAction(t, nil)
+		action.args = []string{"api"}
+		action.azdCtx = azdCtx
+		action.env = env
+		action.formatter = &output.JsonFormatter{}
+		require.NoError(t, action.flags.flagSet.Parse(flags))
+		action.flags.All = false
+
+		projectManager := &mockDeployProjectManager{}
+		projectManager.On("Initialize", action.projectConfig).Return(nil).Maybe()
+		projectManager.On("EnsureServiceTargetTools", action.projectConfig).Return(nil).Maybe()
+
+		serviceManager := &mockDeployServiceManager{packageContent: content, packageDir: packageDir}
+		serviceManager.On("Deploy", "api").Return().Maybe()
+
+		action.projectManager = projectManager
+		action.serviceManager = serviceManager
+
+		_, err := action.Run(t.Context())
+		return serviceManager, err
+	}
+
+	_, err := deploy(t, "v1", "--rollback")
+	require.ErrorIs(t, err, internal.ErrNoPreviousDeployment)
+
+	_, err = deploy(t, "v1")
+	require.NoError(t, err)
+	_, err = deploy(t, "v2")
+	require.NoError(t, err)
+
+	_, err = deploy(t, "", "--rollback", "--from-package", "api.zip")
+	require.ErrorIs(t, err, internal.ErrInvalidFlagCombination)
+
+	// The package deployed before the last deployment is deployed again.
+	serviceManager, err := deploy(t, "", "--rollback")
+	require.NoError(t, err)
+	require.Equal(t, "v1", serviceManager.deployedPackage)
+
+	// Rolling back again undoes the rollback.
+	serviceManager, err = deploy(t, "", "--rollback")
+	require.NoError(t, err)
+	require.Equal(t, "v2", serviceManager.deployedPackage)
+
+	entries, err := os.ReadDir(filepath.Join(azdCtx.EnvironmentRoot(env.Name()), "deployments", "api"))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+}
+
+func newDeployActionForFromPackageTest(
+	t *testing.T,
+	fromPackage string,
+	deployErr error,
+	wantDeploy bool,
+) (*DeployAction, *mockDeployServiceManager) {
+	t.Helper()
+
+	action := newDeployTimeoutAction(t, nil)
+	action.args = []string{"api"}
+	action.flags.All = false
+	action.flags.fromPackage = fromPackage
+
+	projectManager := &mockDeployProjectManager{}
+	projectManager.On("Initialize", action.projectConfig).Return(nil).Maybe()
+	projectManager.On("EnsureServiceTargetTools", action.projectConfig).Return(nil).Maybe()
+
+	serviceManager := &mockDeployServiceManager{deployErr: deployErr}
+	if wantDeploy {
+		serviceManager.On("Deploy", "api").Return().Once()
+	}
+	t.Cleanup(func() {
+		serviceManager.AssertExpectations(t)
+	})
+
+	action.projectManager = projectManager
+	action.serviceManager = serviceManager
+	return action, serviceManager
+}
+
 func newDeployActionForTimeoutTest(
 	t *testing.T,
 	flagTimeout *int,
@@ -452,7 +801,9 @@ func newDeployTimeoutAction(t *testing.T, flagTimeout *int) *DeployAction {
 	return &DeployAction{
 		flags:               flags,
 		projectConfig:       projectConfig,
+		azdCtx:              azdcontext.NewAzdContextWithDirectory(t.TempDir()),
 		env:                 env,
+		envManager:          newDeployTestEnvManager(),
 		importManager:       project.NewImportManager(nil),
 		console:             mockinput.NewMockConsole(),
 		formatter:           &output.NoneFormatter{},
@@ -461,6 +812,13 @@ func newDeployTimeoutAction(t *testing.T, flagTimeout *int) *DeployAction {
 	}
 }
 
+func newDeployTestEnvManager() *mockenv.MockEnvManager {
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", mock.Anything, mock.Anything).Return(nil).Maybe()
+	envManager.On("InvalidateEnvCache", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return envManager
+}
+
 func deployTimeoutTestProjectConfig(t *testing.T) *project.ProjectConfig {
 	t.Helper()
 
@@ -538,3 +896,151 @@ func TestResolveDAGConcurrency(t *testing.T) {
 		})
 	}
 }
+
+func TestDeployActionFromPackageManifest(t *testing.T) {
+	t.Parallel()
+
+	writeManifest := func(t *testing.T, host project.ServiceTargetKind) string {
+		distDir := t.TempDir()
+		packagePath := filepath.Join(distDir, "api.zip")
+		require.NoError(t, os.WriteFile(packagePath, []byte("package content"), 0600))
+
+		service, err := project.NewPackageManifestService(
+			&project.ServiceConfig{Name: "api", Host: host},
+			project.ArtifactCollection{{
+				Kind:         project.ArtifactKindArchive,
+				Location:     packagePath,
+				LocationKind: project.LocationKindLocal,
+			}},
+			distDir,
+		)
+		require.NoError(t, err)
+
+		manifestPath := filepath.Join(distDir, project.PackageManifestFileName)
+		manifest := &project.PackageManifest{
+			Project:  "test-proj",
+			Services: map[string]*project.PackageManifestService{"api": service},
+		}
+		require.NoError(t, manifest.Save(manifestPath))
+		return manifestPath
+	}
+
+	t.Run("All", func(t *testing.T) {
+		t.Parallel()
+
+		deployErr := mockDeployErr(t.Name())
+		manifestPath := writeManifest(t, project.ContainerAppTarget)
+		action, serviceManager := newDeployActionForFromPackageTest(t, manifestPath, deployErr, true)
+		action.args = nil
+		action.flags.All = true
+
+		// The deployment itself fails, after the package of the manifest was handed to the service target.
+		_, err := action.Run(t.Context())
+		require.ErrorIs(t, err, deployErr)
+		require.Equal(t, "package content", serviceManager.deployedPackage)
+	})
+
+	t.Run("DigestMismatch", func(t *testing.T) {
+		t.Parallel()
+
+		manifestPath := writeManifest(t, project.ContainerAppTarget)
+		packagePath := filepath.Join(filepath.Dir(manifestPath), "api.zip")
+		require.NoError(t, os.WriteFile(packagePath, []byte("changed content"), 0600))
+
+		action, _ := newDeployActionForFromPackageTest(t, manifestPath, nil, false)
+		_, err := action.Run(t.Context())
+		require.ErrorContains(t, err, "the package manifest expects sha256:")
+	})
+
+	t.Run("HostChanged", func(t *testing.T) {
+		t.Parallel()
+
+		action, _ := newDeployActionForFromPackageTest(t, writeManifest(t, project.AppServiceTarget), nil, false)
+		_, err := action.Run(t.Context())
+		require.ErrorContains(t, err, "was packaged for host 'appservice'")
+	})
+
+	t.Run("ServiceMissing", func(t *testing.T) {
+		t.Parallel()
+
+		manifestPath := filepath.Join(t.TempDir(), project.PackageManifestFileName)
+		require.NoError(t, (&project.PackageManifest{Project: "test-proj"}).Save(manifestPath))
+
+		action, _ := newDeployActionForFromPackageTest(t, manifestPath, nil, false)
+		_, err := action.Run(t.Context())
+		require.ErrorIs(t, err, internal.ErrInvalidArgValue)
+	})
+}
+
+func TestDeployActionImage(t *testing.T) {
+	t.Parallel()
+
+	image := "myregistry.azurecr.io/api@sha256:" + strings.Repeat("ab", 32)
+
+	t.Run("Deployed", func(t *testing.T) {
+		t.Parallel()
+
+		deployErr := mockDeployErr(t.Name())
+		action, serviceManager := newDeployActionForFromPackageTest(t, "", deployErr, true)
+		action.flags.image = image
+
+		// The deployment itself fails, after the image was handed to the service target.
+		_, err := action.Run(t.Context())
+		require.ErrorIs(t, err, deployErr)
+		require.Equal(t, &project.Artifact{
+			Kind:         project.ArtifactKindContainer,
+			Location:     image,
+			LocationKind: project.LocationKindRemote,
+		}, serviceManager.deployedArtifact)
+	})
+
+	tests := []struct {
+		name    string
+		setup   func(action *DeployAction)
+		wantErr error
+	}{
+		{
+			name:    "NotByDigest",
+			setup:   func(action *DeployAction) { action.flags.image = "myregistry.azurecr.io/api:latest" },
+			wantErr: internal.ErrInvalidArgValue,
+		},
+		{
+			name:    "NoRegistry",
+			setup:   func(action *DeployAction) { action.flags.image = "api@sha256:" + strings.Repeat("ab", 32) },
+			wantErr: internal.ErrInvalidArgValue,
+		},
+		{
+			name: "AllServices",
+			setup: func(action *DeployAction) {
+				action.args = nil
+				action.flags.All = true
+			},
+			wantErr: internal.ErrImageNoService,
+		},
+		{
+			name:    "WithFromPackage",
+			setup:   func(action *DeployAction) { action.flags.fromPackage = "api.zip" },
+			wantErr: internal.ErrInvalidFlagCombination,
+		},
+		{
+			name: "NotContainerHost",
+			setup: func(action *DeployAction) {
+				action.projectConfig.Services["api"].Host = project.AzureFunctionTarget
+			},
+			wantErr: internal.ErrInvalidArgValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			action, _ := newDeployActionForFromPackageTest(t, "", nil, false)
+			action.flags.image = image
+			tt.setup(action)
+
+			_, err := action.Run(t.Context())
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
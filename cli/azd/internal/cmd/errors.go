@@ -24,17 +24,26 @@ import (
 	"github.com/azure/azure-dev/cli/azd/internal/tracing"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/errchain"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
+	"github.com/azure/azure-dev/cli/azd/pkg/artifacts"
 	"github.com/azure/azure-dev/cli/azd/pkg/auth"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/azdext"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/extensions"
+	"github.com/azure/azure-dev/cli/azd/pkg/gitlab"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/lockfile"
+	"github.com/azure/azure-dev/cli/azd/pkg/monitorquery"
 	"github.com/azure/azure-dev/cli/azd/pkg/pipeline"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/servicelogs"
+	"github.com/azure/azure-dev/cli/azd/pkg/tool"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/github"
 	"github.com/azure/azure-dev/cli/azd/pkg/update"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -160,8 +169,9 @@ func classify(err error) (string, []attribute.KeyValue) {
 
 // classifyErrorWithSuggestion handles *internal.ErrorWithSuggestion.
 // It preserves the historical narrow attribute set (only error.type
-// from the inner classification, plus the auth special case) and
-// emits the legacy `error.suggestion` ResultCode.
+// from the inner classification, the error catalog code and category,
+// plus the auth special case) and emits the legacy `error.suggestion`
+// ResultCode.
 func classifyErrorWithSuggestion(
 	ews *internal.ErrorWithSuggestion,
 ) (string, []attribute.KeyValue) {
@@ -169,6 +179,12 @@ func classifyErrorWithSuggestion(
 	innerCode, _ := classify(innerErr)
 
 	attrs := []attribute.KeyValue{fields.ErrType.String(innerCode)}
+	if ews.Code != "" {
+		attrs = append(attrs, fields.ErrCode.String(ews.Code))
+	}
+	if ews.Category != "" {
+		attrs = append(attrs, fields.ErrCategory.String(string(ews.Category)))
+	}
 
 	// Preserve the AAD-detail enrichment when an AuthFailedError is
 	// wrapped by a suggestion so it still surfaces on the outer span.
@@ -306,8 +322,14 @@ func classifySentinel(err error) string {
 	case errors.Is(err, internal.ErrInfraNotProvisioned):
 		return "internal.infra_not_provisioned"
 	case errors.Is(err, internal.ErrFromPackageWithAll),
-		errors.Is(err, internal.ErrFromPackageNoService):
+		errors.Is(err, internal.ErrFromPackageNoService),
+		errors.Is(err, internal.ErrRollbackNoService),
+		errors.Is(err, internal.ErrImageNoService):
 		return "internal.invalid_flag_combination"
+	case errors.Is(err, internal.ErrServiceUnhealthy):
+		return "internal.service_unhealthy"
+	case errors.Is(err, internal.ErrNoPreviousDeployment):
+		return "internal.no_previous_deployment"
 	case errors.Is(err, internal.ErrCannotChangeSubscription):
 		return "internal.cannot_change_subscription"
 	case errors.Is(err, internal.ErrCannotChangeLocation):
@@ -329,6 +351,26 @@ func classifySentinel(err error) string {
 		return "internal.invalid_args"
 	case errors.Is(err, environment.ErrNotFound):
 		return "internal.env_not_found"
+	case errors.Is(err, environment.ErrSnapshotNotFound):
+		return "internal.env_snapshot_not_found"
+	case errors.Is(err, environment.ErrSecretNotDecrypted):
+		return "internal.env_secret_not_decrypted"
+	case errors.Is(err, artifacts.ErrStoreNotConfigured):
+		return "artifacts.store_not_configured"
+	case errors.Is(err, artifacts.ErrArtifactNotFound):
+		return "artifacts.not_found"
+	case errors.Is(err, artifacts.ErrDigestMismatch):
+		return "artifacts.digest_mismatch"
+	case errors.Is(err, lockfile.ErrUnsupportedSchema):
+		return "lockfile.unsupported_version"
+	case errors.Is(err, lockfile.ErrDigestMismatch):
+		return "lockfile.digest_mismatch"
+	case errors.Is(err, lockfile.ErrSourceMismatch):
+		return "lockfile.source_mismatch"
+	case errors.Is(err, lockfile.ErrLockedVersionAbsent):
+		return "lockfile.version_unavailable"
+	case errors.Is(err, lockfile.ErrToolVersionMismatch):
+		return "lockfile.tool_version_mismatch"
 	case errors.Is(err, azdcontext.ErrNoProject):
 		return "internal.no_project"
 	case errors.Is(err, internal.ErrNoArgsProvided),
@@ -371,6 +413,8 @@ func classifySentinel(err error) string {
 		return "internal.cancel_not_supported"
 	case errors.Is(err, provisioning.ErrBindMountOperationDisabled):
 		return "internal.bind_mount_disabled"
+	case errors.Is(err, provisioning.ErrDeploymentTimedOut):
+		return "internal.deployment_timed_out"
 	case errors.Is(err, provisioning.ErrDeploymentInterruptedLeaveRunning):
 		return "user.canceled.leave_running"
 	case errors.Is(err, provisioning.ErrDeploymentCanceledByUser):
@@ -381,12 +425,45 @@ func classifySentinel(err error) string {
 		return "user.canceled.cancel_too_late"
 	case errors.Is(err, provisioning.ErrDeploymentCancelFailed):
 		return "user.canceled.cancel_failed"
+	case errors.Is(err, provisioning.ErrNoActiveDeployment):
+		return "internal.no_active_deployment"
+	case errors.Is(err, provisioning.ErrCancelNotSupportedByProvider):
+		return "internal.provider_cancel_not_supported"
+	case errors.Is(err, provisioning.ErrKeepNotSupportedByProvider):
+		return "internal.provider_keep_not_supported"
+	case errors.Is(err, provisioning.ErrResourcesLocked):
+		return "user.resources_locked"
 	case errors.Is(err, update.ErrNeedsElevation):
 		return "update.elevationRequired"
 	case errors.Is(err, pipeline.ErrRemoteHostIsNotAzDo):
 		return "internal.remote_not_azdo"
+	case errors.Is(err, monitorquery.ErrUnsupportedResource),
+		errors.Is(err, servicelogs.ErrUnsupportedResource):
+		return "internal.unsupported_resource"
+	case errors.Is(err, project.ErrNoLocalRunCommand):
+		return "internal.no_local_run_command"
+	case errors.Is(err, gitlab.ErrRemoteHostIsNotGitLab):
+		return "internal.remote_not_gitlab"
+	case errors.Is(err, pipeline.ErrFederatedCredentialNotFound):
+		return "internal.federated_credential_not_found"
+	case errors.Is(err, github.ErrWorkflowNotFound):
+		return "internal.workflow_not_found"
+	case errors.Is(err, github.ErrWorkflowNotDispatchable):
+		return "internal.workflow_not_dispatchable"
+	case errors.Is(err, ext.ErrHookInputMissing):
+		return "internal.hook_input_missing"
+	case errors.Is(err, ext.ErrInvalidHookOutput):
+		return "internal.invalid_hook_output"
+	case errors.Is(err, tool.ErrMissingChecksum):
+		return "internal.tool_checksum_missing"
 	case errors.Is(err, internal.ErrToolUpgradeFailed):
 		return "internal.tool_upgrade_failed"
+	case errors.Is(err, internal.ErrWaitTimedOut):
+		return "internal.wait_timed_out"
+	case errors.Is(err, internal.ErrDriftDetected):
+		return "internal.drift_detected"
+	case errors.Is(err, internal.ErrTemplateTestFailed):
+		return "internal.template_test_failed"
 	default:
 		return ""
 	}
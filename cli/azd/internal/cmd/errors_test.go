@@ -31,10 +31,19 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/azdext"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/errorhandler"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/ext"
+	"github.com/azure/azure-dev/cli/azd/pkg/gitlab"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/lockfile"
+	"github.com/azure/azure-dev/cli/azd/pkg/monitorquery"
 	"github.com/azure/azure-dev/cli/azd/pkg/pipeline"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/servicelogs"
+	"github.com/azure/azure-dev/cli/azd/pkg/tool"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/github"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mocktracing"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/attribute"
@@ -352,12 +361,78 @@ func Test_MapError(t *testing.T) {
 			wantErrReason:  "internal.bind_mount_disabled",
 			wantErrDetails: nil,
 		},
+		{
+			name:           "WithErrResourcesLocked",
+			err:            fmt.Errorf("2 resource lock(s) prevent deletion: %w", provisioning.ErrResourcesLocked),
+			wantErrReason:  "user.resources_locked",
+			wantErrDetails: nil,
+		},
 		{
 			name:           "WithErrRemoteHostIsNotAzDo",
 			err:            fmt.Errorf("%w: https://dev.azure.com/org", pipeline.ErrRemoteHostIsNotAzDo),
 			wantErrReason:  "internal.remote_not_azdo",
 			wantErrDetails: nil,
 		},
+		{
+			name:           "WithServiceLogsErrUnsupportedResource",
+			err:            fmt.Errorf("service 'api': %w", servicelogs.ErrUnsupportedResource),
+			wantErrReason:  "internal.unsupported_resource",
+			wantErrDetails: nil,
+		},
+		{
+			name:           "WithMonitorQueryErrUnsupportedResource",
+			err:            fmt.Errorf("resource 'kv': %w", monitorquery.ErrUnsupportedResource),
+			wantErrReason:  "internal.unsupported_resource",
+			wantErrDetails: nil,
+		},
+		{
+			name:           "WithErrNoLocalRunCommand",
+			err:            fmt.Errorf("service 'api': %w", project.ErrNoLocalRunCommand),
+			wantErrReason:  "internal.no_local_run_command",
+			wantErrDetails: nil,
+		},
+		{
+			name:           "WithErrRemoteHostIsNotGitLab",
+			err:            fmt.Errorf("remote 'origin': %w", gitlab.ErrRemoteHostIsNotGitLab),
+			wantErrReason:  "internal.remote_not_gitlab",
+			wantErrDetails: nil,
+		},
+		{
+			name:           "WithErrFederatedCredentialNotFound",
+			err:            fmt.Errorf("federated credential 'main': %w", pipeline.ErrFederatedCredentialNotFound),
+			wantErrReason:  "internal.federated_credential_not_found",
+			wantErrDetails: nil,
+		},
+		{
+			name:           "WithErrWorkflowNotFound",
+			err:            fmt.Errorf("%w: azure-dev.yml", github.ErrWorkflowNotFound),
+			wantErrReason:  "internal.workflow_not_found",
+			wantErrDetails: nil,
+		},
+		{
+			name:           "WithErrWorkflowNotDispatchable",
+			err:            fmt.Errorf("%w: azure-dev.yml", github.ErrWorkflowNotDispatchable),
+			wantErrReason:  "internal.workflow_not_dispatchable",
+			wantErrDetails: nil,
+		},
+		{
+			name:           "WithErrHookInputMissing",
+			err:            fmt.Errorf("hook 'preprovision' requires DB_HOST: %w", ext.ErrHookInputMissing),
+			wantErrReason:  "internal.hook_input_missing",
+			wantErrDetails: nil,
+		},
+		{
+			name:           "WithErrMissingChecksum",
+			err:            fmt.Errorf("downloading bicep: %w", tool.ErrMissingChecksum),
+			wantErrReason:  "internal.tool_checksum_missing",
+			wantErrDetails: nil,
+		},
+		{
+			name:           "WithErrToolVersionMismatch",
+			err:            fmt.Errorf("restoring toolset from azd.lock: %w", lockfile.ErrToolVersionMismatch),
+			wantErrReason:  "lockfile.tool_version_mismatch",
+			wantErrDetails: nil,
+		},
 		{
 			name: "WithDNSError",
 			err: &net.DNSError{
@@ -483,6 +558,21 @@ func Test_MapError(t *testing.T) {
 				fields.ErrType.String("internal.unclassified"),
 			},
 		},
+		{
+			name: "WithSuggestionWithCode",
+			err: &internal.ErrorWithSuggestion{
+				Err:        errors.New("InsufficientQuota: not enough cores"),
+				Code:       "insufficient_quota",
+				Category:   errorhandler.ErrorCategoryProvisioning,
+				Suggestion: "Request a quota increase.",
+			},
+			wantErrReason: "error.suggestion",
+			wantErrDetails: []attribute.KeyValue{
+				fields.ErrType.String("internal.unclassified"),
+				fields.ErrCode.String("insufficient_quota"),
+				fields.ErrCategory.String("provisioning"),
+			},
+		},
 		// Sentinel error test cases — verify typed errors wrapped in
 		// ErrorWithSuggestion produce error.suggestion ResultCode with
 		// the sentinel code in error.type via classifySentinel.
@@ -552,6 +642,28 @@ func Test_MapError(t *testing.T) {
 					"internal.invalid_flag_combination"),
 			},
 		},
+		{
+			name: "WithErrNoPreviousDeployment",
+			err: &internal.ErrorWithSuggestion{
+				Err: fmt.Errorf(
+					"service 'api': %w",
+					internal.ErrNoPreviousDeployment),
+				Suggestion: "Deploy the service first.",
+			},
+			wantErrReason: "error.suggestion",
+			wantErrDetails: []attribute.KeyValue{
+				fields.ErrType.String(
+					"internal.no_previous_deployment"),
+			},
+		},
+		{
+			name: "WithErrServiceUnhealthy",
+			err: fmt.Errorf(
+				"%w: api: checking https://api.contoso.com/health: unexpected status 500; "+
+					"rolling back service api: %w",
+				internal.ErrServiceUnhealthy, internal.ErrNoPreviousDeployment),
+			wantErrReason: "internal.service_unhealthy",
+		},
 		{
 			name: "WithErrCannotChangeSubscription",
 			err: &internal.ErrorWithSuggestion{
@@ -1214,8 +1326,12 @@ func Test_PackageLevelErrorsMapped(t *testing.T) {
 		"ErrSourceExists":                "caught in template source manager before reaching telemetry",
 		"ErrSourceTypeInvalid":           "caught in template source manager before reaching telemetry",
 		"ErrRepositoryNameInUse":         "caught in pipeline config flow before reaching telemetry",
+		"ErrReleaseNotFound":             "caught in the GitHub artifact store before reaching telemetry",
 		"ErrResourceNotFound":            "caught in kubectl callers before reaching telemetry",
 		"ErrResourceNotReady":            "caught in kubectl callers before reaching telemetry",
+		"ErrStepUpUnavailable":           "caught in the azd credential, which returns a re-login error instead",
+		"ErrLockFileNotFound":            "caught in cmd/restore.go, projects without a lock file are skipped",
+		"ErrFeatureFlagNotFound":         "caught in cmd/flags.go, which creates the missing flag",
 
 		// Duplicate definitions (same error variable defined in multiple packages)
 		"ErrDebuggerAborted": "defined in both cmd/middleware and pkg/azdext, handled at debug middleware level",
@@ -23,6 +23,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/progress"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -32,6 +33,7 @@ type ProvisionFlags struct {
 	noProgress            bool
 	preview               bool
 	ignoreDeploymentState bool
+	timings               bool
 	subscription          string
 	location              string
 	global                *internal.GlobalCommandOptions
@@ -83,6 +85,11 @@ func (i *ProvisionFlags) bindCommon(local *pflag.FlagSet, global *internal.Globa
 		false,
 		"(Bicep only) Forces a fresh deployment based on current Bicep template files, "+
 			"ignoring any stored deployment state.")
+	local.BoolVar(
+		&i.timings,
+		"timings",
+		false,
+		"(Bicep only) Displays how long each resource and module took to deploy, slowest first.")
 
 	i.EnvFlag = &internal.EnvFlag{}
 	i.EnvFlag.Bind(local, global)
@@ -130,6 +137,7 @@ type ProvisionAction struct {
 	projectConfig       *project.ProjectConfig
 	writer              io.Writer
 	console             input.Console
+	reporter            progress.Reporter
 	commandRunner       exec.CommandRunner
 	serviceLocator      ioc.ServiceLocator
 	subManager          *account.SubscriptionsManager
@@ -147,6 +155,10 @@ type ProvisionAction struct {
 	graphSyncConsole *syncConsole
 	graphEnvMu       *sync.Mutex
 	graphHookMu      *sync.Mutex
+
+	// Resource timings collected from every provisioned layer when --timings is set.
+	timingsMu sync.Mutex
+	timings   []provisioning.ResourceTiming
 }
 
 func NewProvisionAction(
@@ -160,6 +172,7 @@ func NewProvisionAction(
 	env *environment.Environment,
 	envManager environment.Manager,
 	console input.Console,
+	reporter progress.Reporter,
 	commandRunner exec.CommandRunner,
 	serviceLocator ioc.ServiceLocator,
 	formatter output.Formatter,
@@ -182,6 +195,7 @@ func NewProvisionAction(
 		projectConfig:       projectConfig,
 		writer:              writer,
 		console:             console,
+		reporter:            reporter,
 		commandRunner:       commandRunner,
 		serviceLocator:      serviceLocator,
 		subManager:          subManager,
@@ -311,8 +325,8 @@ func (p *ProvisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 	return p.provisionLayersGraph(ctx, layers, startTime, previewMode)
 }
 
-// deployResultToUx creates the ux element to display from a provision preview
-func deployResultToUx(previewResult *provisioning.DeployPreviewResult) ux.UxItem {
+// DeployResultToUx creates the ux element to display from a provision preview
+func DeployResultToUx(previewResult *provisioning.DeployPreviewResult) ux.UxItem {
 	var operations []*ux.Resource
 	for _, change := range previewResult.Preview.Properties.Changes {
 		// Convert property deltas to UX format
@@ -11,6 +11,7 @@ import (
 	"io"
 	"log"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -34,6 +35,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/progress"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"go.uber.org/multierr"
 )
@@ -121,6 +123,7 @@ func (p *ProvisionAction) provisionLayersGraph(
 		quiet = true
 		layer := layers[0]
 		layer.IgnoreDeploymentState = p.flags.ignoreDeploymentState
+		layer.CollectTimings = p.flags.timings
 		layerPath := layer.AbsolutePath(p.projectConfig.Path)
 
 		if err := g.AddStep(&exegraph.Step{
@@ -137,7 +140,7 @@ func (p *ProvisionAction) provisionLayersGraph(
 				}
 				p.console.Message(ctx, "")
 
-				if p.alphaFeatureManager.IsEnabled(azapi.FeatureDeploymentStacks) {
+				if provisioning.DeploymentType(p.projectConfig.Infra, p.alphaFeatureManager) == azapi.DeploymentTypeStacks {
 					p.console.WarnForFeature(ctx, azapi.FeatureDeploymentStacks)
 				}
 
@@ -151,6 +154,7 @@ func (p *ProvisionAction) provisionLayersGraph(
 				}
 
 				var deployResult *provisioning.DeployResult
+				step := startLayerProgress(ctx, p.reporter, layer, provisionLayerStepName(layer))
 				hookErr := p.runLayerProvisionWithHooks(ctx, layer, layerPath, func() error {
 					return p.projectConfig.Invoke(ctx, project.ProjectEventProvision, projectEventArgs, func() error {
 						var innerErr error
@@ -158,6 +162,8 @@ func (p *ProvisionAction) provisionLayersGraph(
 						return innerErr
 					})
 				})
+				finishLayerProgress(step, deployResult, hookErr)
+				p.addTimings(deployResult)
 				// Raw errors only — the outer graph error path runs every step
 				// failure through wrapProvisionError exactly once, avoiding
 				// double-wrapping ("deployment failed: deployment failed: …")
@@ -221,7 +227,7 @@ func (p *ProvisionAction) provisionLayersGraph(
 		}
 		p.displayEnvironmentDetails(ctx)
 		p.console.Message(ctx, "")
-		if p.alphaFeatureManager.IsEnabled(azapi.FeatureDeploymentStacks) {
+		if provisioning.DeploymentType(p.projectConfig.Infra, p.alphaFeatureManager) == azapi.DeploymentTypeStacks {
 			p.console.WarnForFeature(ctx, azapi.FeatureDeploymentStacks)
 		}
 
@@ -257,6 +263,7 @@ func (p *ProvisionAction) provisionLayersGraph(
 
 		for i, layer := range layers {
 			layer.IgnoreDeploymentState = p.flags.ignoreDeploymentState
+			layer.CollectTimings = p.flags.timings
 
 			// Translate bicep-inferred indices into exegraph dependency names.
 			var deps []string
@@ -315,6 +322,8 @@ func (p *ProvisionAction) provisionLayersGraph(
 		}, nil
 	}
 
+	timings := p.sortedTimings()
+
 	// JSON state dump (for --output json).
 	if p.formatter.Kind() == output.JsonFormat {
 		stateResult, err := p.provisionManager.State(ctx, nil)
@@ -325,8 +334,12 @@ func (p *ProvisionAction) provisionLayersGraph(
 			)
 		}
 
-		if err := p.formatter.Format(
-			provisioning.NewEnvRefreshResultFromState(stateResult.State), p.writer, nil); err != nil {
+		envRefreshResult := provisioning.NewEnvRefreshResultFromState(stateResult.State)
+		if len(timings) > 0 {
+			envRefreshResult.Timings = provisioning.NewEnvRefreshTimings(timings)
+		}
+
+		if err := p.formatter.Format(envRefreshResult, p.writer, nil); err != nil {
 			return nil, fmt.Errorf(
 				"deployment succeeded but the deployment result could not be displayed: %w",
 				err,
@@ -334,6 +347,10 @@ func (p *ProvisionAction) provisionLayersGraph(
 		}
 	}
 
+	if p.flags.timings && p.formatter.Kind() != output.JsonFormat {
+		p.displayTimings(ctx, timings)
+	}
+
 	// Invalidate cache after successful provisioning so next azd show will refresh.
 	if err := p.envManager.InvalidateEnvCache(ctx, p.env.Name()); err != nil {
 		log.Printf("warning: failed to invalidate state cache: %v", err)
@@ -377,7 +394,7 @@ func (p *ProvisionAction) provisionPreview(
 	}
 	p.console.Message(ctx, "")
 
-	if p.alphaFeatureManager.IsEnabled(azapi.FeatureDeploymentStacks) {
+	if provisioning.DeploymentType(p.projectConfig.Infra, p.alphaFeatureManager) == azapi.DeploymentTypeStacks {
 		p.console.WarnForFeature(ctx, azapi.FeatureDeploymentStacks)
 	}
 
@@ -386,7 +403,7 @@ func (p *ProvisionAction) provisionPreview(
 		return nil, p.wrapProvisionError(ctx, err)
 	}
 
-	p.console.MessageUxItem(ctx, deployResultToUx(deployPreviewResult))
+	p.console.MessageUxItem(ctx, DeployResultToUx(deployPreviewResult))
 
 	return &actions.ActionResult{
 		Message: &actions.ResultMessage{
@@ -621,11 +638,15 @@ func (p *ProvisionAction) provisionSingleLayerWithOutcome(
 		commandRunner:       p.commandRunner,
 		importManager:       p.importManager,
 		hookMu:              p.graphHookMu,
+		reporter:            p.reporter,
 	}
 
+	step := startLayerProgress(ctx, p.reporter, layer, stepName)
 	result, err := runProvisionSingleLayer(
 		ctx, deps, layer, stepName, p.graphSyncConsole, p.graphEnvMu,
 	)
+	finishLayerProgress(step, result, err)
+	p.addTimings(result)
 	if err != nil {
 		return provisionDeployed, err
 	}
@@ -635,6 +656,50 @@ func (p *ProvisionAction) provisionSingleLayerWithOutcome(
 	return provisionDeployed, nil
 }
 
+// addTimings records the resource timings of a provisioned layer. Layers can be provisioned concurrently.
+func (p *ProvisionAction) addTimings(result *provisioning.DeployResult) {
+	if result == nil || len(result.Timings) == 0 {
+		return
+	}
+
+	p.timingsMu.Lock()
+	defer p.timingsMu.Unlock()
+	p.timings = append(p.timings, result.Timings...)
+}
+
+// sortedTimings returns the resource timings of every provisioned layer, slowest first.
+func (p *ProvisionAction) sortedTimings() []provisioning.ResourceTiming {
+	p.timingsMu.Lock()
+	defer p.timingsMu.Unlock()
+
+	timings := slices.Clone(p.timings)
+	provisioning.SortTimings(timings)
+	return timings
+}
+
+// displayTimings prints the resource timings table requested with --timings.
+func (p *ProvisionAction) displayTimings(ctx context.Context, timings []provisioning.ResourceTiming) {
+	if len(timings) == 0 {
+		p.console.Message(ctx, output.WithGrayFormat("No deployment timings are available for this provider."))
+		return
+	}
+
+	resources := make([]*ux.TimedResource, len(timings))
+	for idx, timing := range timings {
+		resources[idx] = &ux.TimedResource{
+			Name:     timing.Name,
+			Type:     timing.Type,
+			Module:   timing.Module,
+			Failed:   timing.State == string(ux.FailedState),
+			Duration: timing.Duration,
+		}
+	}
+
+	p.console.EnsureBlankLine(ctx)
+	p.console.MessageUxItem(ctx, &ux.ProvisionTimings{Resources: resources})
+	p.console.Message(ctx, "")
+}
+
 // logProvisionGraphTimings emits per-step and total timings from a provision
 // graph run to the debug log.
 func (p *ProvisionAction) logProvisionGraphTimings(result *exegraph.RunResult) {
@@ -803,6 +868,8 @@ type provisionLayerDeps struct {
 	// hookMu serializes project event handler execution across concurrent
 	// layers. Handlers like AKS's setK8sContext are not goroutine-safe.
 	hookMu *sync.Mutex
+	// reporter receives the progress step of each layer.
+	reporter progress.Reporter
 }
 
 // provisionSingleLayer is the [UpGraphAction]-facing entry point. It delegates
@@ -816,10 +883,41 @@ func provisionSingleLayer(
 	console input.Console,
 	envMu *sync.Mutex,
 ) error {
-	_, err := runProvisionSingleLayer(ctx, deps, layer, stepName, console, envMu)
+	step := startLayerProgress(ctx, deps.reporter, layer, stepName)
+	result, err := runProvisionSingleLayer(ctx, deps, layer, stepName, console, envMu)
+	finishLayerProgress(step, result, err)
 	return err
 }
 
+// startLayerProgress reports the start of a layer's provisioning as a progress step. The console view of provisioning
+// is owned by the provisioning provider, so the step is only reported to the headless progress frontends.
+func startLayerProgress(
+	ctx context.Context,
+	reporter progress.Reporter,
+	layer provisioning.Options,
+	stepName string,
+) *progress.Step {
+	title := "Provisioning Azure resources"
+	if layer.Name != "" {
+		title = fmt.Sprintf("Provisioning layer %s", layer.Name)
+	}
+
+	return progress.Start(ctx, progress.Headless(reporter), progress.PhaseProvision, stepName, title)
+}
+
+// finishLayerProgress finishes the progress step of a layer with the outcome of its provisioning.
+func finishLayerProgress(step *progress.Step, result *provisioning.DeployResult, err error) {
+	switch {
+	case errors.Is(err, errValidationCanceledByUser),
+		err == nil && result != nil && result.SkippedReason == provisioning.ProvisionValidationCanceledSkipped:
+		step.Skip("canceled")
+	case err == nil && result != nil && result.SkippedReason == provisioning.DeploymentStateSkipped:
+		step.Skip("no changes to provision")
+	default:
+		step.Done(err)
+	}
+}
+
 // runProvisionSingleLayer provisions a single infrastructure layer. It creates
 // an isolated environment clone so that parallel layers don't interfere with
 // each other's parameter resolution, then merges outputs back into the shared
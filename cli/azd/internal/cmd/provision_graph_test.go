@@ -8,9 +8,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
@@ -477,3 +479,33 @@ func newPropagationTestDeps(
 	}
 	return deps, &sync.Mutex{}, envPath
 }
+
+func TestProvisionAction_DisplayTimings(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	action := &ProvisionAction{console: mockContext.Console}
+
+	// Layers report their timings concurrently and out of order.
+	var wg sync.WaitGroup
+	for _, result := range []*provisioning.DeployResult{
+		{Timings: []provisioning.ResourceTiming{{Name: "app", Type: "Microsoft.Web/sites", Duration: time.Minute}}},
+		{Timings: []provisioning.ResourceTiming{
+			{Name: "db", Type: "Microsoft.Sql/servers", State: "Failed", Duration: 5 * time.Minute},
+		}},
+		{SkippedReason: provisioning.DeploymentStateSkipped},
+		nil,
+	} {
+		wg.Go(func() { action.addTimings(result) })
+	}
+	wg.Wait()
+
+	timings := action.sortedTimings()
+	require.Len(t, timings, 2)
+	require.Equal(t, "db", timings[0].Name)
+	require.Equal(t, "app", timings[1].Name)
+
+	action.displayTimings(*mockContext.Context, timings)
+	consoleOutput := strings.Join(mockContext.Console.Output(), "\n")
+	require.Contains(t, consoleOutput, "Deployment timings (slowest first):")
+	require.Contains(t, consoleOutput, "5m0s  Microsoft.Sql/servers  db (failed)")
+	require.Contains(t, consoleOutput, "1m0s  Microsoft.Web/sites    app")
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/progress"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
 	"github.com/spf13/cobra"
@@ -100,6 +101,7 @@ func NewPublishAction(
 	azCli *azapi.AzureClient,
 	commandRunner exec.CommandRunner,
 	console input.Console,
+	reporter progress.Reporter,
 	formatter output.Formatter,
 	writer io.Writer,
 	alphaFeatureManager *alpha.FeatureManager,
@@ -120,6 +122,7 @@ func NewPublishAction(
 		formatter:           formatter,
 		writer:              writer,
 		console:             console,
+		reporter:            reporter,
 		commandRunner:       commandRunner,
 		alphaFeatureManager: alphaFeatureManager,
 		importManager:       importManager,
@@ -141,6 +144,7 @@ type PublishAction struct {
 	formatter           output.Formatter
 	writer              io.Writer
 	console             input.Console
+	reporter            progress.Reporter
 	commandRunner       exec.CommandRunner
 	alphaFeatureManager *alpha.FeatureManager
 	importManager       *project.ImportManager
@@ -255,8 +259,8 @@ func (pa *PublishAction) Run(ctx context.Context) (*actions.ActionResult, error)
 
 	err = pa.projectConfig.Invoke(ctx, project.ProjectEventPublish, projectEventArgs, func() error {
 		for _, svc := range stableServices {
-			stepMessage := fmt.Sprintf("Publishing service %s", svc.Name)
-			pa.console.ShowSpinner(ctx, stepMessage, input.Step)
+			step := progress.Start(
+				ctx, pa.reporter, progress.PhasePublish, svc.Name, fmt.Sprintf("Publishing service %s", svc.Name))
 
 			if alphaFeatureId, isAlphaFeature := alpha.IsFeatureKey(string(svc.Host)); isAlphaFeature {
 				// alpha feature on/off detection for host is done during initialization.
@@ -265,7 +269,7 @@ func (pa *PublishAction) Run(ctx context.Context) (*actions.ActionResult, error)
 			}
 
 			if !pa.supportsPublish(ctx, svc) {
-				pa.console.StopSpinner(ctx, stepMessage, input.StepSkipped)
+				step.Skip("")
 
 				var message string
 				if svc.Host == project.DotNetContainerAppTarget {
@@ -294,37 +298,37 @@ func (pa *PublishAction) Run(ctx context.Context) (*actions.ActionResult, error)
 				})
 
 				if err != nil {
-					pa.console.StopSpinner(ctx, stepMessage, input.StepFailed)
+					step.Done(err)
 					return err
 				}
 			} else {
 				//  --from-package not set, automatically package the application
+				packageStep := step.SubStep("package", fmt.Sprintf("Packaging service %s", svc.Name))
 				packageResult, err := async.RunWithProgress(
 					func(packageProgress project.ServiceProgress) {
-						progressMessage := fmt.Sprintf("Packaging service %s (%s)", svc.Name, packageProgress.Message)
-						pa.console.ShowSpinner(ctx, progressMessage, input.Step)
+						packageStep.Update(packageProgress.Message)
 					},
 					func(progress *async.Progress[project.ServiceProgress]) (*project.ServicePackageResult, error) {
 						return pa.serviceManager.Package(ctx, svc, serviceContext, progress, nil)
 					},
 				)
+				packageStep.Done(err)
 
 				if err != nil {
-					pa.console.StopSpinner(ctx, stepMessage, input.StepFailed)
+					step.Done(err)
 					return err
 				}
 
 				// Append package artifacts
 				if err := serviceContext.Package.Add(packageResult.Artifacts...); err != nil {
-					pa.console.StopSpinner(ctx, stepMessage, input.StepFailed)
+					step.Done(err)
 					return err
 				}
 			}
 
 			publishResult, err := async.RunWithProgress(
 				func(publishProgress project.ServiceProgress) {
-					progressMessage := fmt.Sprintf("Publishing service %s (%s)", svc.Name, publishProgress.Message)
-					pa.console.ShowSpinner(ctx, progressMessage, input.Step)
+					step.Update(publishProgress.Message)
 				},
 				func(progress *async.Progress[project.ServiceProgress]) (*project.ServicePublishResult, error) {
 					return pa.serviceManager.Publish(ctx, svc, serviceContext, progress, publishOptions)
@@ -332,12 +336,12 @@ func (pa *PublishAction) Run(ctx context.Context) (*actions.ActionResult, error)
 			)
 
 			if err != nil {
-				pa.console.StopSpinner(ctx, stepMessage, input.StepFailed)
+				step.Done(err)
 				return err
 			}
 
 			if err := serviceContext.Publish.Add(publishResult.Artifacts...); err != nil {
-				pa.console.StopSpinner(ctx, stepMessage, input.StepFailed)
+				step.Done(err)
 				return err
 			}
 
@@ -352,7 +356,7 @@ func (pa *PublishAction) Run(ctx context.Context) (*actions.ActionResult, error)
 				}
 			}
 
-			pa.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+			step.Done(nil)
 
 			publishResults[svc.Name] = publishResult
 			pa.console.MessageUxItem(ctx, publishResult.Artifacts)
@@ -15,7 +15,10 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/exegraph"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
@@ -35,13 +38,19 @@ type deployGraphState struct {
 
 	resMu   sync.Mutex
 	results map[string]*project.ServiceDeployResult
+
+	hashMu    sync.Mutex
+	hashes    map[string]string
+	unchanged map[string]bool
 }
 
 // newDeployGraphState creates a state container pre-sized for the given services.
 func newDeployGraphState(services []*project.ServiceConfig) *deployGraphState {
 	return &deployGraphState{
-		contexts: make(map[string]*project.ServiceContext, len(services)),
-		results:  make(map[string]*project.ServiceDeployResult, len(services)),
+		contexts:  make(map[string]*project.ServiceContext, len(services)),
+		results:   make(map[string]*project.ServiceDeployResult, len(services)),
+		hashes:    make(map[string]string, len(services)),
+		unchanged: make(map[string]bool, len(services)),
 	}
 }
 
@@ -73,6 +82,67 @@ func (s *deployGraphState) GetResult(name string) *project.ServiceDeployResult {
 	return s.results[name]
 }
 
+// StorePackageHash records the package hash computed by a package step, and whether the service is unchanged since its
+// last deploy, in which case its publish and deploy steps are skipped.
+func (s *deployGraphState) StorePackageHash(name string, hash string, unchanged bool) {
+	s.hashMu.Lock()
+	defer s.hashMu.Unlock()
+	s.hashes[name] = hash
+	s.unchanged[name] = unchanged
+}
+
+// LoadPackageHash returns the hash stored for a service by [deployGraphState.StorePackageHash].
+func (s *deployGraphState) LoadPackageHash(name string) string {
+	s.hashMu.Lock()
+	defer s.hashMu.Unlock()
+	return s.hashes[name]
+}
+
+// IsUnchanged reports whether the package of a service is unchanged since its last deploy.
+func (s *deployGraphState) IsUnchanged(name string) bool {
+	s.hashMu.Lock()
+	defer s.hashMu.Unlock()
+	return s.unchanged[name]
+}
+
+// RecordPackageHashes records in env the package hash of each service deployed by the graph, so that the next
+// deploy can skip the services that didn't change. The hash of a service that wasn't deployed, failed to deploy, or
+// was deployed from a package without a known hash is removed, so the next deploy doesn't skip it.
+// Services skipped because they were unchanged keep their hash.
+func (s *deployGraphState) RecordPackageHashes(env *environment.Environment, services []*project.ServiceConfig) {
+	s.hashMu.Lock()
+	defer s.hashMu.Unlock()
+	for _, svc := range services {
+		if s.unchanged[svc.Name] {
+			continue
+		}
+
+		key := fmt.Sprintf("SERVICE_%s_%s", environment.Key(svc.Name), project.PackageHashProperty)
+		if hash := s.hashes[svc.Name]; hash != "" && s.GetResult(svc.Name) != nil {
+			env.DotenvSet(key, hash)
+		} else {
+			env.DotenvDelete(key)
+		}
+	}
+}
+
+// RecordDeployments records in history the package of each service deployed by the graph, so that the service can be
+// rolled back to it. It must be called before [deployGraphState.CleanupTempArtifacts] removes the packages. Services
+// that weren't deployed, failed to deploy, or were skipped because they were unchanged keep their history.
+func (s *deployGraphState) RecordDeployments(history *project.DeploymentHistory, services []*project.ServiceConfig) {
+	for _, svc := range services {
+		sc := s.LoadContext(svc.Name)
+		if sc == nil || s.GetResult(svc.Name) == nil || s.IsUnchanged(svc.Name) {
+			continue
+		}
+
+		if err := history.Record(svc, sc); err != nil {
+			// A missing history only means the service can't be rolled back.
+			log.Printf("recording deployment of service %s: %v", svc.Name, err)
+		}
+	}
+}
+
 // ResultsSnapshot returns a shallow copy of the results map, safe to iterate
 // without holding the lock.
 func (s *deployGraphState) ResultsSnapshot() map[string]*project.ServiceDeployResult {
@@ -116,11 +186,12 @@ type serviceGraphOptions struct {
 	// deployTimeout bounds each individual deploy step. Must be > 0.
 	deployTimeout time.Duration
 
-	// fromPackage — when non-empty — creates a synthetic package artifact at
-	// the supplied path instead of invoking the service packager. Only the
-	// stand-alone `azd deploy` path sets this (the `--from-package` flag);
-	// the `azd up` path always leaves this empty.
-	fromPackage string
+	// fromPackages — keyed by service name — are the package artifacts
+	// deployed instead of invoking the service packager. Only the
+	// stand-alone `azd deploy` path sets this (the `--from-package` flag,
+	// with a package or a package manifest); the `azd up` path always
+	// leaves this empty.
+	fromPackages map[string]*project.Artifact
 
 	// publishExtraDeps augments every publish step's DependsOn with these
 	// step names. Used by `azd up` to wire publish steps behind the synthetic
@@ -182,6 +253,106 @@ type serviceGraphOptions struct {
 	// IMPORTANT: This callback is invoked from worker goroutines and must
 	// not block. Blocking implementations stall the graph scheduler.
 	onPhaseProgress func(serviceName string, phase deployPhase, detail string)
+
+	// trackPackageHashes computes the [project.PackageHash] of each service
+	// after it is packaged, and its [project.DeployHash] before it is
+	// published, and stores it in state, so the caller can record it with
+	// [deployGraphState.RecordPackageHashes] once the graph finishes.
+	trackPackageHashes bool
+
+	// env is the environment whose values the deploy hash of each service
+	// is computed from. Required when trackPackageHashes is set.
+	env *environment.Environment
+
+	// skipUnchanged, if non-nil, is invoked with the deploy hash of each
+	// service when trackPackageHashes is set, and reports whether the
+	// service is unchanged since its last deploy. The publish and deploy
+	// steps of unchanged services return without doing anything. When nil,
+	// every service is deployed.
+	skipUnchanged func(svc *project.ServiceConfig, packageHash string) bool
+
+	// resourceCreatedTime returns the creation time of the resource a
+	// service is deployed to, which is part of its deploy hash. When nil,
+	// or when the creation time isn't known, services deployed to a
+	// resource have no deploy hash and are always deployed.
+	resourceCreatedTime func(ctx context.Context, resourceId *arm.ResourceID) (time.Time, error)
+
+	// history, if non-nil, is the deployment history a service that fails
+	// its health check is rolled back from, when its healthCheck sets
+	// rollback. When nil, unhealthy services are never rolled back.
+	history *project.DeploymentHistory
+}
+
+// storeDeployHash computes the [project.DeployHash] of svc from the package hash stored by its package step, the values of
+// opts.env and the resource svc is deployed to, and stores it in opts.state. It reports whether svc is unchanged since
+// its last deploy, in which case its publish and deploy steps are skipped.
+func storeDeployHash(ctx context.Context, opts serviceGraphOptions, svc *project.ServiceConfig) bool {
+	packageHash := opts.state.LoadPackageHash(svc.Name)
+
+	var hash string
+	if packageHash != "" {
+		target, err := serviceTargetResource(ctx, opts.serviceManager, svc)
+		var createdTime time.Time
+		if err == nil && target != nil && opts.resourceCreatedTime != nil {
+			createdTime, err = targetCreatedTime(ctx, opts.resourceCreatedTime, target)
+		}
+		if err == nil {
+			hash, err = project.DeployHash(packageHash, svc, opts.env, target, createdTime)
+		}
+		if err != nil {
+			// A missing hash only means the service is deployed and its
+			// hash isn't recorded.
+			log.Printf("computing deploy hash of service %s: %v", svc.Name, err)
+		}
+	}
+
+	unchanged := hash != "" && opts.skipUnchanged != nil && opts.skipUnchanged(svc, hash)
+	if unchanged {
+		log.Printf("service %s is unchanged since its last deploy, skipping", svc.Name)
+	}
+	opts.state.StorePackageHash(svc.Name, hash, unchanged)
+
+	return unchanged
+}
+
+// targetCreatedTime returns the creation time of target with resourceCreatedTime.
+func targetCreatedTime(
+	ctx context.Context,
+	resourceCreatedTime func(ctx context.Context, resourceId *arm.ResourceID) (time.Time, error),
+	target *environment.TargetResource,
+) (time.Time, error) {
+	resourceId, err := arm.ParseResourceID(fmt.Sprintf(
+		"%s/providers/%s/%s",
+		azure.ResourceGroupRID(target.SubscriptionId(), target.ResourceGroupName()),
+		target.ResourceType(),
+		target.ResourceName()))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing target resource ID: %w", err)
+	}
+
+	createdTime, err := resourceCreatedTime(ctx, resourceId)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("getting creation time of %s: %w", resourceId, err)
+	}
+
+	return createdTime, nil
+}
+
+// serviceTargetResource resolves the resource svc is deployed to.
+func serviceTargetResource(
+	ctx context.Context, serviceManager project.ServiceManager, svc *project.ServiceConfig,
+) (*environment.TargetResource, error) {
+	serviceTarget, err := serviceManager.GetServiceTarget(ctx, svc)
+	if err != nil {
+		return nil, fmt.Errorf("getting service target: %w", err)
+	}
+
+	target, err := serviceManager.GetTargetResource(ctx, svc, serviceTarget)
+	if err != nil {
+		return nil, fmt.Errorf("getting target resource: %w", err)
+	}
+
+	return target, nil
 }
 
 // serviceGraphHandles exposes the names of the steps that addServiceStepsToGraph
@@ -208,12 +379,13 @@ type serviceGraphHandles struct {
 //	                                                            get a runtime mutex in their context
 //	                                                              (no graph edges between deploys).
 //
-// Deploy ordering: when no service declares a `uses:` edge targeting
-// another service in this graph, deploy steps chain sequentially in the
-// order provided by opts.services (alphabetical via ServiceStable()) for
-// backward compatibility with templates that relied on implicit ordering.
-// When at least one service declares `uses:`, the graph uses explicit
-// edges and services without mutual `uses:` edges deploy in parallel.
+// Deploy ordering: when no service declares a `uses:` or `dependsOn:` edge
+// targeting another service in this graph, deploy steps chain sequentially
+// in the order provided by opts.services (alphabetical via ServiceStable())
+// for backward compatibility with templates that relied on implicit
+// ordering. When at least one service declares such an edge, the graph uses
+// explicit edges and services without edges between them deploy in
+// parallel.
 // Package and publish steps always run in parallel regardless.
 //
 // When opts.packageExtraDeps is empty (stand-alone `azd deploy`), package
@@ -268,7 +440,8 @@ func addServiceStepsToGraph(g *exegraph.Graph, opts serviceGraphOptions) (*servi
 
 	// hasExplicitOrdering is true when the graph has an explicit execution
 	// policy — either:
-	//   (a) at least one service declares `uses: [otherService]`, OR
+	//   (a) at least one service declares `uses: [otherService]` or
+	//       `dependsOn: [otherService]`, OR
 	//   (b) a buildGateKey policy applies (e.g. Aspire's shared AppHost
 	//       build serialization).
 	//
@@ -278,9 +451,9 @@ func addServiceStepsToGraph(g *exegraph.Graph, opts serviceGraphOptions) (*servi
 	// compatibility with templates that relied on implicit ordering.
 	hasExplicitOrdering := false
 
-	// Check (a): service-to-service `uses:` edges.
+	// Check (a): service-to-service `uses:` and `dependsOn:` edges.
 	for _, svc := range opts.services {
-		for _, dep := range svc.Uses {
+		for _, dep := range svc.DeployDependencies() {
 			if dep != svc.Name {
 				if _, ok := serviceNames[dep]; ok {
 					hasExplicitOrdering = true
@@ -308,8 +481,8 @@ func addServiceStepsToGraph(g *exegraph.Graph, opts serviceGraphOptions) (*servi
 
 	if !hasExplicitOrdering && len(opts.services) > 1 {
 		log.Printf(
-			"deploying %d services sequentially (no uses: edges declared; "+
-				"add uses: to azure.yaml to enable parallel deployment)",
+			"deploying %d services sequentially (no uses: or dependsOn: edges declared; "+
+				"add uses: or dependsOn: to azure.yaml to enable parallel deployment)",
 			len(opts.services),
 		)
 		// Advisory: scan service env configs for SERVICE_<OTHER>_* references
@@ -371,14 +544,10 @@ func addServiceStepsToGraph(g *exegraph.Graph, opts serviceGraphOptions) (*servi
 			Action: func(ctx context.Context) error {
 				sc := project.NewServiceContext()
 
-				if opts.fromPackage != "" {
-					// --from-package bypasses the packager and wraps the
+				if fromPackage, has := opts.fromPackages[pkgSvc.Name]; has {
+					// --from-package bypasses the packager and uses the
 					// user-supplied artifact directly.
-					if pkgErr := sc.Package.Add(&project.Artifact{
-						Kind:         determineArtifactKind(opts.fromPackage),
-						Location:     opts.fromPackage,
-						LocationKind: project.LocationKindLocal,
-					}); pkgErr != nil {
+					if pkgErr := sc.Package.Add(fromPackage); pkgErr != nil {
 						return fmt.Errorf("packaging service %s: %w", pkgSvc.Name, pkgErr)
 					}
 				} else {
@@ -393,6 +562,19 @@ func addServiceStepsToGraph(g *exegraph.Graph, opts serviceGraphOptions) (*servi
 				}
 
 				opts.state.StoreContext(pkgSvc.Name, sc)
+
+				if opts.trackPackageHashes {
+					hash, hashErr := project.PackageHash(pkgSvc, sc.Package)
+					if hashErr != nil {
+						// A missing hash only means the service is deployed
+						// and its hash isn't recorded.
+						log.Printf("computing package hash of service %s: %v", pkgSvc.Name, hashErr)
+					}
+					// Whether the service is unchanged is decided by its
+					// publish step, once provisioning has updated the
+					// values its deployment reads.
+					opts.state.StorePackageHash(pkgSvc.Name, hash, false)
+				}
 				return nil
 			},
 		}); err != nil {
@@ -411,6 +593,10 @@ func addServiceStepsToGraph(g *exegraph.Graph, opts serviceGraphOptions) (*servi
 			DependsOn: publishDeps,
 			Tags:      []string{"publish"},
 			Action: func(stepCtx context.Context) error {
+				if opts.trackPackageHashes && storeDeployHash(stepCtx, opts, pubSvc) {
+					return nil
+				}
+
 				sc := opts.state.LoadContext(pubSvc.Name)
 
 				if sc == nil {
@@ -456,7 +642,7 @@ func addServiceStepsToGraph(g *exegraph.Graph, opts serviceGraphOptions) (*servi
 		}
 
 		// ── deploy-<svc> ── publish + declared service-to-service `uses:`
-		// edges + any caller-supplied fan-in.
+		// and `dependsOn:` edges + any caller-supplied fan-in.
 		//
 		// Build-gate synchronization is handled at RUNTIME, not via graph
 		// topology: the deploy step's context carries a per-gate-key mutex
@@ -464,7 +650,8 @@ func addServiceStepsToGraph(g *exegraph.Graph, opts serviceGraphOptions) (*servi
 		// (dotnet publish) and releases before the Azure deployment begins.
 		// This serializes the race-prone build phase while keeping the slow
 		// Azure deployment portion fully parallel.
-		deployDeps := make([]string, 0, 1+len(svc.Uses)+len(opts.deployExtraDeps))
+		dependencies := svc.DeployDependencies()
+		deployDeps := make([]string, 0, 1+len(dependencies)+len(opts.deployExtraDeps))
 		deployDeps = append(deployDeps, publishStepName)
 		if opts.buildGateKey != nil {
 			if key := opts.buildGateKey(svc); key != "" {
@@ -473,21 +660,23 @@ func addServiceStepsToGraph(g *exegraph.Graph, opts serviceGraphOptions) (*servi
 				}
 			}
 		}
-		// Translate `services.<name>.uses: [depSvc]` into a deploy-step
-		// edge so hooks that pass values between services (e.g. api's
-		// postdeploy writes an env var that web's predeploy reads) retain
-		// the deploy ordering they had under the old sequential loop.
-		// Entries that don't match another service's name target a
-		// resource and are left to the provision layer. Duplicates are
-		// filtered so the build-gate and `uses:` edges collapse when they
-		// name the same predecessor.
-		for _, dep := range svc.Uses {
+		// Translate `services.<name>.uses: [depSvc]` and
+		// `services.<name>.dependsOn: [depSvc]` into a deploy-step edge so
+		// hooks that pass values between services (e.g. api's postdeploy
+		// writes an env var that web's predeploy reads) retain the deploy
+		// ordering they had under the old sequential loop. `uses:` entries
+		// that don't match another service's name target a resource and are
+		// left to the provision layer; `dependsOn:` entries that don't match
+		// name a service that isn't deployed with this graph, like with
+		// `azd deploy <service>`. Duplicates are filtered so the build-gate
+		// and dependency edges collapse when they name the same predecessor.
+		for _, dep := range dependencies {
 			if dep == svc.Name {
 				continue
 			}
 			if _, ok := serviceNames[dep]; !ok {
 				log.Printf(
-					"debug: service %q declares uses: %q — not a known service name",
+					"debug: service %q depends on %q — not a service in this deployment",
 					svc.Name, dep,
 				)
 				continue
@@ -498,13 +687,13 @@ func addServiceStepsToGraph(g *exegraph.Graph, opts serviceGraphOptions) (*servi
 			}
 		}
 		// Sequential fallback: when no service in this graph declares a
-		// `uses:` edge to another service, chain deploy steps in the
-		// order provided (alphabetical via ServiceStable()) so that
-		// templates relying on implicit sequential ordering continue to
-		// work. This preserves backward compatibility with existing
-		// templates while still
-		// allowing parallel deployment for templates that opt in via
-		// `uses:`. Package and publish steps remain parallel regardless.
+		// `uses:` or `dependsOn:` edge to another service, chain deploy
+		// steps in the order provided (alphabetical via ServiceStable()) so
+		// that templates relying on implicit sequential ordering continue
+		// to work. This preserves backward compatibility with existing
+		// templates while still allowing parallel deployment for templates
+		// that opt in via `uses:` or `dependsOn:`. Package and publish
+		// steps remain parallel regardless.
 		if !hasExplicitOrdering && len(handles.DeploySteps) >= 2 {
 			prevDeploy := handles.DeploySteps[len(handles.DeploySteps)-2]
 			if !slices.Contains(deployDeps, prevDeploy) {
@@ -519,6 +708,10 @@ func addServiceStepsToGraph(g *exegraph.Graph, opts serviceGraphOptions) (*servi
 			DependsOn: deployDeps,
 			Tags:      []string{"deploy"},
 			Action: func(stepCtx context.Context) error {
+				if opts.state.IsUnchanged(depSvc.Name) {
+					return nil
+				}
+
 				sc := opts.state.LoadContext(depSvc.Name)
 
 				if sc == nil {
@@ -563,6 +756,10 @@ func addServiceStepsToGraph(g *exegraph.Graph, opts serviceGraphOptions) (*servi
 					return fmt.Errorf("deploying service %s: %w", depSvc.Name, depErr)
 				}
 
+				if depSvc.HealthCheck != nil {
+					return checkServiceHealth(stepCtx, opts, depSvc, result, progress.Progress)
+				}
+
 				opts.state.StoreResult(depSvc.Name, result)
 
 				return nil
@@ -635,3 +832,75 @@ func suggestServiceDeps(services []*project.ServiceConfig) {
 		}
 	}
 }
+
+// checkServiceHealth runs the health check of a deployed service, and attaches its result to the deployment result.
+// A service that doesn't become healthy is rolled back to the package deployed before when its health check sets
+// rollback; either way, the deploy step fails. The result of a service that isn't rolled back is stored, so the
+// package that is still deployed is recorded in the deployment history.
+func checkServiceHealth(
+	ctx context.Context,
+	opts serviceGraphOptions,
+	svc *project.ServiceConfig,
+	result *project.ServiceDeployResult,
+	progress *async.Progress[project.ServiceProgress],
+) error {
+	progress.SetProgress(project.NewServiceProgress("Checking service health"))
+	health := project.CheckHealth(ctx, svc.HealthCheck, result, nil)
+	result.HealthCheck = health
+
+	if health.Healthy {
+		opts.state.StoreResult(svc.Name, result)
+		return nil
+	}
+
+	unhealthyErr := fmt.Errorf(
+		"%w: %s: checking %s: %s", internal.ErrServiceUnhealthy, svc.Name, health.Url, health.Error)
+	if !svc.HealthCheck.Rollback {
+		opts.state.StoreResult(svc.Name, result)
+		return unhealthyErr
+	}
+
+	progress.SetProgress(project.NewServiceProgress("Rolling back to the previous deployment"))
+	if err := rollbackService(ctx, opts, svc, progress); err != nil {
+		opts.state.StoreResult(svc.Name, result)
+		return fmt.Errorf("%w; rolling back service %s: %w", unhealthyErr, svc.Name, err)
+	}
+
+	health.RolledBack = true
+	return fmt.Errorf("%w; service %s was rolled back to the previous deployment", unhealthyErr, svc.Name)
+}
+
+// rollbackService deploys the package recorded in the deployment history before the current deployment of a service.
+func rollbackService(
+	ctx context.Context,
+	opts serviceGraphOptions,
+	svc *project.ServiceConfig,
+	progress *async.Progress[project.ServiceProgress],
+) error {
+	var previous string
+	if opts.history != nil {
+		previous = opts.history.Previous(svc.Name)
+	}
+	if previous == "" {
+		return internal.ErrNoPreviousDeployment
+	}
+
+	sc := project.NewServiceContext()
+	if err := sc.Package.Add(&project.Artifact{
+		Kind:         determineArtifactKind(previous),
+		Location:     previous,
+		LocationKind: project.LocationKindLocal,
+	}); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.deployTimeout)
+	defer cancel()
+
+	if _, err := opts.serviceManager.Publish(ctx, svc, sc, progress, nil); err != nil {
+		return err
+	}
+
+	_, err := opts.serviceManager.Deploy(ctx, svc, sc, progress)
+	return err
+}
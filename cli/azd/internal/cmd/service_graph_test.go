@@ -5,11 +5,15 @@ package cmd
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/apphost"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
@@ -191,6 +195,37 @@ func TestSequentialFallbackNotAppliedWithUses(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// TestDependsOn verifies that `dependsOn:` entries produce deploy-step
+// edges like `uses:` entries, and that services without edges between
+// them deploy in parallel rather than sequentially.
+func TestDependsOn(t *testing.T) {
+	t.Parallel()
+	services := []*project.ServiceConfig{
+		{Name: "api"},
+		{Name: "web", DependsOn: []string{"api"}, Uses: []string{"api"}},
+		{Name: "worker", DependsOn: []string{"api"}},
+		{Name: "independent"},
+	}
+
+	opts, g := newGraphOpts(services)
+	_, err := addServiceStepsToGraph(g, opts)
+	require.NoError(t, err)
+	require.NoError(t, g.Validate())
+
+	stepMap := make(map[string]*exegraph.Step, g.Len())
+	for _, s := range g.Steps() {
+		stepMap[s.Name] = s
+	}
+
+	require.Equal(t, []string{"publish-api"}, stepMap["deploy-api"].DependsOn)
+	require.Equal(t, []string{"publish-web", "deploy-api"}, stepMap["deploy-web"].DependsOn)
+	require.Equal(t, []string{"publish-worker", "deploy-api"}, stepMap["deploy-worker"].DependsOn)
+	require.Equal(t, []string{"publish-independent"}, stepMap["deploy-independent"].DependsOn)
+
+	err = exegraph.Run(t.Context(), g, exegraph.RunOptions{})
+	require.NoError(t, err)
+}
+
 // TestSuggestServiceDeps verifies that the advisory scanner detects
 // SERVICE_<OTHER>_* references in service env configs.
 func TestSuggestServiceDeps(t *testing.T) {
@@ -211,6 +246,18 @@ func TestSuggestServiceDeps(t *testing.T) {
 	suggestServiceDeps(services)
 }
 
+func TestTargetCreatedTime(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	target := environment.NewTargetResource("sub", "rg", "app-api", "Microsoft.Web/sites")
+
+	got, err := targetCreatedTime(t.Context(), func(ctx context.Context, resourceId *arm.ResourceID) (time.Time, error) {
+		require.Equal(t, "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/sites/app-api", resourceId.String())
+		return created, nil
+	}, target)
+	require.NoError(t, err)
+	require.Equal(t, created, got)
+}
+
 func TestDeployGraphState_ResultsSnapshot(t *testing.T) {
 	t.Parallel()
 	services := []*project.ServiceConfig{{Name: "api"}, {Name: "web"}}
@@ -365,3 +412,123 @@ func TestBuildGateMultipleKeys(t *testing.T) {
 		}
 	}
 }
+
+// healthCheckServiceManager deploys every service to a fixed endpoint, and
+// records the package of each deployment.
+type healthCheckServiceManager struct {
+	stubServiceManager
+	endpoint string
+
+	mu       sync.Mutex
+	packages []string
+}
+
+func (s *healthCheckServiceManager) Deploy(
+	_ context.Context, _ *project.ServiceConfig, sc *project.ServiceContext,
+	_ *async.Progress[project.ServiceProgress],
+) (*project.ServiceDeployResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	location := ""
+	if len(sc.Package) > 0 {
+		location = sc.Package[0].Location
+	}
+	s.packages = append(s.packages, location)
+
+	return &project.ServiceDeployResult{
+		Artifacts: project.ArtifactCollection{{
+			Kind:         project.ArtifactKindEndpoint,
+			Location:     s.endpoint,
+			LocationKind: project.LocationKindRemote,
+		}},
+	}, nil
+}
+
+// TestHealthCheck verifies that the deploy step checks the health of a
+// service with a healthCheck, and rolls an unhealthy service back to its
+// previous package when the health check asks for it.
+func TestHealthCheck(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(healthy.Close)
+
+	unhealthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(unhealthy.Close)
+
+	run := func(
+		t *testing.T, endpoint string, healthCheck *project.HealthCheckOptions, history *project.DeploymentHistory,
+	) (*healthCheckServiceManager, *deployGraphState, error) {
+		services := []*project.ServiceConfig{{Name: "api", HealthCheck: healthCheck}}
+		serviceManager := &healthCheckServiceManager{endpoint: endpoint}
+
+		opts, g := newGraphOpts(services)
+		opts.serviceManager = serviceManager
+		opts.history = history
+		_, err := addServiceStepsToGraph(g, opts)
+		require.NoError(t, err)
+
+		return serviceManager, opts.state, exegraph.Run(t.Context(), g, exegraph.RunOptions{})
+	}
+
+	t.Run("Healthy", func(t *testing.T) {
+		_, state, err := run(t, healthy.URL, &project.HealthCheckOptions{Path: "/health"}, nil)
+		require.NoError(t, err)
+
+		result := state.GetResult("api")
+		require.NotNil(t, result)
+		require.NotNil(t, result.HealthCheck)
+		require.True(t, result.HealthCheck.Healthy)
+		require.Equal(t, healthy.URL+"/health", result.HealthCheck.Url)
+	})
+
+	t.Run("Unhealthy", func(t *testing.T) {
+		serviceManager, state, err := run(
+			t, unhealthy.URL, &project.HealthCheckOptions{Path: "/health", Timeout: 1}, nil)
+		require.ErrorIs(t, err, internal.ErrServiceUnhealthy)
+		require.ErrorContains(t, err, "unexpected status 500")
+		require.Len(t, serviceManager.packages, 1)
+
+		// The unhealthy deployment stays, and is recorded.
+		result := state.GetResult("api")
+		require.NotNil(t, result)
+		require.False(t, result.HealthCheck.Healthy)
+	})
+
+	t.Run("RolledBack", func(t *testing.T) {
+		env := environment.New("test")
+		history := project.NewDeploymentHistory(env, t.TempDir())
+		serviceConfig := &project.ServiceConfig{Name: "api", Host: project.ContainerAppTarget}
+		for _, image := range []string{"contoso.azurecr.io/api:v1", "contoso.azurecr.io/api:v2"} {
+			sc := project.NewServiceContext()
+			require.NoError(t, sc.Publish.Add(&project.Artifact{
+				Kind:         project.ArtifactKindContainer,
+				Location:     image,
+				LocationKind: project.LocationKindRemote,
+			}))
+			require.NoError(t, history.Record(serviceConfig, sc))
+		}
+
+		serviceManager, state, err := run(
+			t, unhealthy.URL, &project.HealthCheckOptions{Path: "/health", Timeout: 1, Rollback: true}, history)
+		require.ErrorIs(t, err, internal.ErrServiceUnhealthy)
+		require.ErrorContains(t, err, "was rolled back to the previous deployment")
+		require.Equal(t, []string{"", "contoso.azurecr.io/api:v1"}, serviceManager.packages)
+		require.Nil(t, state.GetResult("api"))
+	})
+
+	t.Run("NoPreviousDeployment", func(t *testing.T) {
+		env := environment.New("test")
+		history := project.NewDeploymentHistory(env, t.TempDir())
+
+		serviceManager, state, err := run(
+			t, unhealthy.URL, &project.HealthCheckOptions{Path: "/health", Timeout: 1, Rollback: true}, history)
+		require.ErrorIs(t, err, internal.ErrServiceUnhealthy)
+		require.ErrorIs(t, err, internal.ErrNoPreviousDeployment)
+		require.Len(t, serviceManager.packages, 1)
+		require.NotNil(t, state.GetResult("api"))
+	})
+}
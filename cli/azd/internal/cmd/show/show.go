@@ -43,6 +43,7 @@ import (
 type showFlags struct {
 	global      *internal.GlobalCommandOptions
 	showSecrets bool
+	live        bool
 	internal.EnvFlag
 }
 
@@ -54,6 +55,12 @@ func (s *showFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOpt
 		false,
 		"Unmask secrets in output.",
 	)
+	local.BoolVar(
+		&s.live,
+		"live",
+		false,
+		"Queries the resources of the services for their current state, replicas, endpoints and last deployment time.",
+	)
 	s.global = global
 }
 
@@ -257,7 +264,10 @@ func (s *showAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 								resourceIds[idx] = res.Id
 							}
 
-							ingressUrl := s.serviceEndpoint(ctx, subId, serviceConfig, env)
+							var ingressUrl string
+							if endpoints := s.serviceEndpoints(ctx, subId, serviceConfig, env); len(endpoints) > 0 {
+								ingressUrl = endpoints[0]
+							}
 
 							resSvc := res.Services[svcName]
 							resSvc.Target = &contracts.ShowTargetArm{
@@ -288,6 +298,10 @@ func (s *showAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 						err)
 				}
 			}
+
+			if s.flags.live {
+				s.addLiveStatus(ctx, env, subId, stableServices, &res)
+			}
 		}
 	}
 
@@ -316,6 +330,11 @@ func (s *showAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 			Name:      serviceName,
 			IngresUrl: service.IngresUrl,
 		}
+		if service.Status != nil {
+			uxServices[index].State = string(service.Status.State)
+			uxServices[index].Replicas = service.Status.Replicas
+			uxServices[index].LastDeployed = service.Status.LastDeployed
+		}
 		index++
 	}
 
@@ -639,36 +658,37 @@ func showContainerAppJob(
 	return service, nil
 }
 
-func (s *showAction) serviceEndpoint(
-	ctx context.Context, subId string, serviceConfig *project.ServiceConfig, env *environment.Environment) string {
+// serviceEndpoints returns the endpoints of a service, or nil when they can't be determined.
+func (s *showAction) serviceEndpoints(
+	ctx context.Context, subId string, serviceConfig *project.ServiceConfig, env *environment.Environment) []string {
 	resourceManager, err := s.lazyResourceManager.GetValue()
 	if err != nil {
 		log.Printf("error: getting lazy resource manager. Endpoints will be empty: %v", err)
-		return ""
+		return nil
 	}
 
 	serviceManager, err := s.lazyServiceManager.GetValue()
 	if err != nil {
 		log.Printf("error: getting lazy service manager. Endpoints will be empty: %v", err)
-		return ""
+		return nil
 	}
 
 	// Initialize the service to ensure external service targets can create provider instances
 	if err := serviceManager.Initialize(ctx, serviceConfig); err != nil {
 		log.Printf("error: initializing service. Endpoints will be empty: %v", err)
-		return ""
+		return nil
 	}
 
 	st, err := serviceManager.GetServiceTarget(ctx, serviceConfig)
 	if err != nil {
 		log.Printf("error: getting service target. Endpoints will be empty: %v", err)
-		return ""
+		return nil
 	}
 
 	targetResource, err := s.resolveTargetResource(ctx, st, resourceManager, subId, serviceConfig)
 	if err != nil {
 		log.Printf("error: getting target-resource. Endpoints will be empty: %v", err)
-		return ""
+		return nil
 	}
 
 	endpoints, err := st.Endpoints(ctx, serviceConfig, targetResource)
@@ -681,11 +701,7 @@ func (s *showAction) serviceEndpoint(
 		endpoints = overriddenEndpoints
 	}
 
-	if len(endpoints) == 0 {
-		return ""
-	}
-
-	return endpoints[0]
+	return endpoints
 }
 
 func (s *showAction) resolveTargetResource(
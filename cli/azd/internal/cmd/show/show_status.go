@@ -0,0 +1,233 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package show
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+)
+
+// addLiveStatus queries the current state and the endpoints of the resources of the services. A service whose
+// resource can't be queried reports the unknown state with the reason, so the other services are still shown.
+func (s *showAction) addLiveStatus(
+	ctx context.Context,
+	env *environment.Environment,
+	subId string,
+	services []*project.ServiceConfig,
+	res *contracts.ShowResult,
+) {
+	credential, err := s.creds.CredentialForSubscription(ctx, subId)
+	if err != nil {
+		log.Printf("error getting credential, the live status of services will not be available: %v", err)
+		return
+	}
+
+	for _, serviceConfig := range services {
+		resSvc, has := res.Services[serviceConfig.Name]
+		if !has || resSvc.Target == nil || len(resSvc.Target.ResourceIds) == 0 {
+			continue
+		}
+
+		status := &contracts.ShowServiceStatus{State: contracts.ShowServiceStateUnknown}
+		if id, err := arm.ParseResourceID(resSvc.Target.ResourceIds[0]); err != nil {
+			status.Error = err.Error()
+		} else if status, err = liveStatus(ctx, credential, id, s.armClientOptions); err != nil {
+			log.Printf("error getting the live status of service %s: %v", serviceConfig.Name, err)
+			status = &contracts.ShowServiceStatus{State: contracts.ShowServiceStateUnknown, Error: err.Error()}
+		}
+
+		status.Endpoints = s.serviceEndpoints(ctx, subId, serviceConfig, env)
+		resSvc.Status = status
+		res.Services[serviceConfig.Name] = resSvc
+	}
+}
+
+// liveStatus queries the current state of a resource. Resources of types that azd can't query report the unknown
+// state.
+func liveStatus(
+	ctx context.Context,
+	cred azcore.TokenCredential,
+	id *arm.ResourceID,
+	clientOpts *arm.ClientOptions,
+) (*contracts.ShowServiceStatus, error) {
+	resType := id.ResourceType.Namespace + "/" + id.ResourceType.Type
+	switch {
+	case strings.EqualFold(resType, string(azapi.AzureResourceTypeContainerApp)):
+		return containerAppStatus(ctx, cred, id, clientOpts)
+	case strings.EqualFold(resType, string(azapi.AzureResourceTypeContainerAppJob)):
+		return containerAppJobStatus(ctx, cred, id, clientOpts)
+	case strings.EqualFold(resType, string(azapi.AzureResourceTypeWebSite)):
+		return appServiceStatus(ctx, cred, id, clientOpts)
+	default:
+		return &contracts.ShowServiceStatus{State: contracts.ShowServiceStateUnknown}, nil
+	}
+}
+
+// showServiceState returns the state reported by Azure in lower case, or the unknown state when it's empty.
+func showServiceState(state string) contracts.ShowServiceState {
+	if state == "" {
+		return contracts.ShowServiceStateUnknown
+	}
+
+	return contracts.ShowServiceState(strings.ToLower(state))
+}
+
+// containerAppStatus returns the running status of a container app, and the replicas and the creation time of its
+// latest ready revision.
+func containerAppStatus(
+	ctx context.Context,
+	cred azcore.TokenCredential,
+	id *arm.ResourceID,
+	clientOpts *arm.ClientOptions,
+) (*contracts.ShowServiceStatus, error) {
+	client, err := armappcontainers.NewContainerAppsClient(id.SubscriptionID, cred, clientOpts)
+	if err != nil {
+		return nil, fmt.Errorf("creating container-apps client: %w", err)
+	}
+
+	app, err := client.Get(ctx, id.ResourceGroupName, id.Name, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting container app: %w", err)
+	}
+
+	status := &contracts.ShowServiceStatus{State: contracts.ShowServiceStateUnknown}
+	if app.Properties == nil {
+		return status, nil
+	}
+
+	if app.Properties.RunningStatus != nil {
+		status.State = showServiceState(string(*app.Properties.RunningStatus))
+	}
+
+	if app.Properties.LatestReadyRevisionName == nil || *app.Properties.LatestReadyRevisionName == "" {
+		return status, nil
+	}
+
+	revisionsClient, err := armappcontainers.NewContainerAppsRevisionsClient(id.SubscriptionID, cred, clientOpts)
+	if err != nil {
+		return nil, fmt.Errorf("creating container-apps revisions client: %w", err)
+	}
+
+	revision, err := revisionsClient.GetRevision(
+		ctx, id.ResourceGroupName, id.Name, *app.Properties.LatestReadyRevisionName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting revision %s: %w", *app.Properties.LatestReadyRevisionName, err)
+	}
+
+	if revision.Properties != nil {
+		if revision.Properties.Replicas != nil {
+			status.Replicas = new(int(*revision.Properties.Replicas))
+		}
+		status.LastDeployed = revision.Properties.CreatedTime
+	}
+
+	return status, nil
+}
+
+// containerAppJobStatus returns the state of a container app job, which is ready once it's provisioned, and the time
+// it was last updated.
+func containerAppJobStatus(
+	ctx context.Context,
+	cred azcore.TokenCredential,
+	id *arm.ResourceID,
+	clientOpts *arm.ClientOptions,
+) (*contracts.ShowServiceStatus, error) {
+	client, err := armappcontainers.NewJobsClient(id.SubscriptionID, cred, clientOpts)
+	if err != nil {
+		return nil, fmt.Errorf("creating container-app-jobs client: %w", err)
+	}
+
+	job, err := client.Get(ctx, id.ResourceGroupName, id.Name, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting container app job: %w", err)
+	}
+
+	status := &contracts.ShowServiceStatus{State: contracts.ShowServiceStateUnknown}
+	if job.Properties != nil && job.Properties.ProvisioningState != nil {
+		if *job.Properties.ProvisioningState == armappcontainers.JobProvisioningStateSucceeded {
+			status.State = showServiceState(string(armappcontainers.ContainerAppRunningStatusReady))
+		} else {
+			status.State = showServiceState(string(*job.Properties.ProvisioningState))
+		}
+	}
+
+	if job.SystemData != nil {
+		status.LastDeployed = job.SystemData.LastModifiedAt
+	}
+
+	return status, nil
+}
+
+// appServiceStatus returns the state of an App Service or a function app, the number of its instances, and the end
+// time of its active deployment, or the time it was last modified when it has no deployment history.
+func appServiceStatus(
+	ctx context.Context,
+	cred azcore.TokenCredential,
+	id *arm.ResourceID,
+	clientOpts *arm.ClientOptions,
+) (*contracts.ShowServiceStatus, error) {
+	client, err := armappservice.NewWebAppsClient(id.SubscriptionID, cred, clientOpts)
+	if err != nil {
+		return nil, fmt.Errorf("creating web-apps client: %w", err)
+	}
+
+	site, err := client.Get(ctx, id.ResourceGroupName, id.Name, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting web app: %w", err)
+	}
+
+	status := &contracts.ShowServiceStatus{State: contracts.ShowServiceStateUnknown}
+	if site.Properties != nil {
+		if site.Properties.State != nil {
+			status.State = showServiceState(*site.Properties.State)
+		}
+		status.LastDeployed = site.Properties.LastModifiedTimeUTC
+	}
+
+	instances := 0
+	instancesPager := client.NewListInstanceIdentifiersPager(id.ResourceGroupName, id.Name, nil)
+	for instancesPager.More() {
+		page, err := instancesPager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing instances of web app: %w", err)
+		}
+		instances += len(page.Value)
+	}
+	status.Replicas = &instances
+
+	// The deployment history isn't available for every plan, so it's only used when it can be read.
+	deploymentsPager := client.NewListDeploymentsPager(id.ResourceGroupName, id.Name, nil)
+	var lastDeployed *time.Time
+	for deploymentsPager.More() && lastDeployed == nil {
+		page, err := deploymentsPager.NextPage(ctx)
+		if err != nil {
+			log.Printf("ignoring error listing the deployments of web app %s: %v", id.Name, err)
+			break
+		}
+
+		for _, deployment := range page.Value {
+			if deployment.Properties != nil && deployment.Properties.Active != nil && *deployment.Properties.Active {
+				lastDeployed = deployment.Properties.EndTime
+				break
+			}
+		}
+	}
+	if lastDeployed != nil {
+		status.LastDeployed = lastDeployed
+	}
+
+	return status, nil
+}
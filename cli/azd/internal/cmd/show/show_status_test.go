@@ -0,0 +1,140 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package show
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLiveStatus_ContainerApp(t *testing.T) {
+	appName := "my-app"
+	createdTime := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	mockContext := mocks.NewMockContext(t.Context())
+	mockContainerAppGetResponse(mockContext, appName, &armappcontainers.ContainerApp{
+		Name: &appName,
+		Properties: &armappcontainers.ContainerAppProperties{
+			RunningStatus:           new(armappcontainers.ContainerAppRunningStatusRunning),
+			LatestReadyRevisionName: new("my-app--rev2"),
+		},
+	})
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/revisions/my-app--rev2")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappcontainers.Revision{
+			Properties: &armappcontainers.RevisionProperties{
+				Replicas:    new(int32(2)),
+				CreatedTime: &createdTime,
+			},
+		})
+	})
+
+	status, err := liveStatus(
+		*mockContext.Context, mockContext.Credentials, newContainerAppResourceID(appName), mockContext.ArmClientOptions)
+	require.NoError(t, err)
+	assert.Equal(t, contracts.ShowServiceStateRunning, status.State)
+	require.NotNil(t, status.Replicas)
+	assert.Equal(t, 2, *status.Replicas)
+	require.NotNil(t, status.LastDeployed)
+	assert.True(t, createdTime.Equal(*status.LastDeployed))
+}
+
+func TestLiveStatus_ContainerAppJob(t *testing.T) {
+	jobName := "my-job"
+	lastModified := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	mockContext := mocks.NewMockContext(t.Context())
+	mockJobGetResponse(mockContext, jobName, &armappcontainers.Job{
+		Name: &jobName,
+		Properties: &armappcontainers.JobProperties{
+			ProvisioningState: new(armappcontainers.JobProvisioningStateSucceeded),
+		},
+		SystemData: &armappcontainers.SystemData{LastModifiedAt: &lastModified},
+	})
+
+	status, err := liveStatus(
+		*mockContext.Context, mockContext.Credentials, newJobResourceID(jobName), mockContext.ArmClientOptions)
+	require.NoError(t, err)
+	assert.Equal(t, contracts.ShowServiceState("ready"), status.State)
+	assert.Nil(t, status.Replicas)
+	require.NotNil(t, status.LastDeployed)
+}
+
+func TestLiveStatus_AppService(t *testing.T) {
+	appName := "my-webapp"
+	lastModified := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	deployed := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	mockAppService := func(t *testing.T, deploymentsStatus int) *mocks.MockContext {
+		mockContext := mocks.NewMockContext(t.Context())
+		mockAppServiceGetResponse(mockContext, appName, &armappservice.Site{
+			Name: &appName,
+			Properties: &armappservice.SiteProperties{
+				State:               new("Stopped"),
+				LastModifiedTimeUTC: &lastModified,
+			},
+		})
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/instances")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappservice.WebAppInstanceStatusCollection{
+				Value: []*armappservice.WebSiteInstanceStatus{{Name: new("a")}, {Name: new("b")}},
+			})
+		})
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/deployments")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, deploymentsStatus, armappservice.DeploymentCollection{
+				Value: []*armappservice.Deployment{
+					{Properties: &armappservice.DeploymentProperties{Active: new(false), EndTime: &lastModified}},
+					{Properties: &armappservice.DeploymentProperties{Active: new(true), EndTime: &deployed}},
+				},
+			})
+		})
+		return mockContext
+	}
+
+	t.Run("ActiveDeployment", func(t *testing.T) {
+		mockContext := mockAppService(t, http.StatusOK)
+		status, err := liveStatus(
+			*mockContext.Context, mockContext.Credentials, newAppServiceResourceID(appName), mockContext.ArmClientOptions)
+		require.NoError(t, err)
+		assert.Equal(t, contracts.ShowServiceStateStopped, status.State)
+		require.NotNil(t, status.Replicas)
+		assert.Equal(t, 2, *status.Replicas)
+		require.NotNil(t, status.LastDeployed)
+		assert.True(t, deployed.Equal(*status.LastDeployed))
+	})
+
+	t.Run("NoDeploymentHistory", func(t *testing.T) {
+		mockContext := mockAppService(t, http.StatusNotFound)
+		status, err := liveStatus(
+			*mockContext.Context, mockContext.Credentials, newAppServiceResourceID(appName), mockContext.ArmClientOptions)
+		require.NoError(t, err)
+		require.NotNil(t, status.LastDeployed)
+		assert.True(t, lastModified.Equal(*status.LastDeployed))
+	})
+}
+
+func TestLiveStatus_UnsupportedResource(t *testing.T) {
+	id, err := arm.ParseResourceID(
+		"/subscriptions/" + testSubscriptionID + "/resourceGroups/" + testResourceGroup +
+			"/providers/Microsoft.Web/staticSites/my-swa")
+	require.NoError(t, err)
+
+	status, err := liveStatus(t.Context(), nil, id, nil)
+	require.NoError(t, err)
+	assert.Equal(t, contracts.ShowServiceStateUnknown, status.State)
+}
@@ -941,7 +941,7 @@ func TestShowAppService_AllKnownSecretKeys(t *testing.T) {
 }
 
 // ---------------------------------------------------------------------------
-// serviceEndpoint — error paths (lazy manager failures)
+// serviceEndpoints — error paths (lazy manager failures)
 // ---------------------------------------------------------------------------
 
 func TestServiceEndpoint_LazyResourceManagerError(t *testing.T) {
@@ -954,7 +954,7 @@ func TestServiceEndpoint_LazyResourceManagerError(t *testing.T) {
 		lazyResourceManager: lazyRM,
 	}
 
-	result := s.serviceEndpoint(t.Context(), "sub123", &project.ServiceConfig{}, nil)
+	result := s.serviceEndpoints(t.Context(), "sub123", &project.ServiceConfig{}, nil)
 	assert.Empty(t, result)
 }
 
@@ -971,7 +971,7 @@ func TestServiceEndpoint_LazyServiceManagerError(t *testing.T) {
 		lazyServiceManager:  lazySM,
 	}
 
-	result := s.serviceEndpoint(t.Context(), "sub123", &project.ServiceConfig{}, nil)
+	result := s.serviceEndpoints(t.Context(), "sub123", &project.ServiceConfig{}, nil)
 	assert.Empty(t, result)
 }
 
@@ -20,9 +20,11 @@ import (
 	"github.com/azure/azure-dev/cli/azd/internal/tracing"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/azsdk/storage"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/exegraph"
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
@@ -32,6 +34,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/progress"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/spf13/pflag"
 )
@@ -71,6 +74,7 @@ import (
 //     `ProjectEventProvision` events internally.
 type UpGraphAction struct {
 	projectConfig       *project.ProjectConfig
+	azdCtx              *azdcontext.AzdContext
 	env                 *environment.Environment
 	envManager          environment.Manager
 	console             input.Console
@@ -83,16 +87,19 @@ type UpGraphAction struct {
 	fileShareService    storage.FileShareService
 	cloud               *cloud.Cloud
 	commandRunner       exec.CommandRunner
+	reporter            progress.Reporter
 	formatter           output.Formatter
 	writer              io.Writer
 	portalUrlBase       string
 	provisionManager    *provisioning.Manager
+	resourceService     *azapi.ResourceService
 }
 
 // NewUpGraphAction creates a new UpGraphAction. Dependencies are resolved via
 // the IoC container.
 func NewUpGraphAction(
 	projectConfig *project.ProjectConfig,
+	azdCtx *azdcontext.AzdContext,
 	env *environment.Environment,
 	envManager environment.Manager,
 	console input.Console,
@@ -105,12 +112,15 @@ func NewUpGraphAction(
 	fileShareService storage.FileShareService,
 	cloud *cloud.Cloud,
 	commandRunner exec.CommandRunner,
+	reporter progress.Reporter,
 	formatter output.Formatter,
 	writer io.Writer,
 	provisionManager *provisioning.Manager,
+	resourceService *azapi.ResourceService,
 ) *UpGraphAction {
 	return &UpGraphAction{
 		projectConfig:       projectConfig,
+		azdCtx:              azdCtx,
 		env:                 env,
 		envManager:          envManager,
 		console:             console,
@@ -123,10 +133,12 @@ func NewUpGraphAction(
 		fileShareService:    fileShareService,
 		cloud:               cloud,
 		commandRunner:       commandRunner,
+		reporter:            reporter,
 		formatter:           formatter,
 		writer:              writer,
 		portalUrlBase:       cloud.PortalUrlBase,
 		provisionManager:    provisionManager,
+		resourceService:     resourceService,
 	}
 }
 
@@ -362,18 +374,23 @@ func (u *UpGraphAction) Run(
 		deployTracker = newDeployProgressTracker(w, u.console.IsSpinnerInteractive(), serviceNames)
 	}
 
+	// The progress table owns the console view of the deployment; the other progress frontends receive the
+	// per-service steps.
+	deploySteps := newDeployStepReporter(ctx, progress.Headless(u.reporter))
+
 	updateDeployProgress := func(svcName string, phase deployPhase, detail string) {
 		if deployTracker != nil {
 			deployTracker.Update(svcName, phase, detail)
 		}
+		deploySteps.Update(svcName, phase, detail)
 	}
 
+	history := project.NewDeploymentHistory(u.env, u.azdCtx.EnvironmentRoot(u.env.Name()))
 	handles, err := addServiceStepsToGraph(g, serviceGraphOptions{
 		services:       stableServices,
 		serviceManager: u.serviceManager,
 		deployTimeout:  deployTimeout,
-		// `azd up` never takes a --from-package flag; leave empty.
-		fromPackage:      "",
+		// `azd up` never takes a --from-package flag; fromPackages is left empty.
 		packageExtraDeps: []string{prePackageEventStep},
 		publishExtraDeps: []string{preDeployEventStep},
 		state:            state,
@@ -384,6 +401,12 @@ func (u *UpGraphAction) Run(
 		onPhaseProgress: func(svcName string, phase deployPhase, detail string) {
 			updateDeployProgress(svcName, phase, detail)
 		},
+		// `azd up` deploys every service, but records their package hashes
+		// so a later `azd deploy` can skip the unchanged ones.
+		trackPackageHashes:  true,
+		env:                 u.env,
+		resourceCreatedTime: resourceCreatedTime(u.resourceService),
+		history:             history,
 	})
 	if err != nil {
 		return nil, err
@@ -591,9 +614,18 @@ func (u *UpGraphAction) Run(
 		deployTracker.RenderFinal()
 	}
 
+	// Record the packages of the deployed services, so they can be rolled
+	// back, before the temporary packages are removed.
+	state.RecordDeployments(history, stableServices)
+
 	// Clean up temporary package artifacts regardless of success/failure.
 	state.CleanupTempArtifacts()
 
+	state.RecordPackageHashes(u.env, stableServices)
+	if saveErr := u.envManager.Save(ctx, u.env); saveErr != nil {
+		log.Printf("warning: failed to save deployment history: %v", saveErr)
+	}
+
 	// Log per-step timing for diagnostics and benchmarking.
 	for _, st := range result.Steps {
 		log.Printf("up-graph step %-30s  %s  %s", st.Name, st.Status, st.Duration.Round(time.Millisecond))
@@ -618,11 +650,18 @@ func (u *UpGraphAction) Run(
 		return nil, result.Error
 	}
 
-	// Display service endpoint artifacts collected during deploy steps.
+	// Display service endpoint artifacts and health checks collected during deploy steps.
 	for _, svc := range stableServices {
-		if dr := state.GetResult(svc.Name); dr != nil && len(dr.Artifacts) > 0 {
+		dr := state.GetResult(svc.Name)
+		if dr == nil {
+			continue
+		}
+		if len(dr.Artifacts) > 0 {
 			u.console.MessageUxItem(ctx, dr.Artifacts)
 		}
+		if dr.HealthCheck != nil {
+			u.console.MessageUxItem(ctx, dr.HealthCheck)
+		}
 	}
 
 	// 6. Finalize: invalidate env cache.
@@ -783,6 +822,7 @@ func (u *UpGraphAction) addProvisionSteps(
 		commandRunner:       u.commandRunner,
 		importManager:       u.importManager,
 		hookMu:              &sync.Mutex{},
+		reporter:            u.reporter,
 	}
 
 	// Compute all step names first so that dependency wiring can reference any
@@ -49,6 +49,12 @@ var (
 	ErrFromPackageWithAll   = errors.New("'--from-package' cannot be specified when '--all' is set")
 	ErrFromPackageNoService = errors.New(
 		"'--from-package' cannot be specified when deploying all services")
+	ErrRollbackNoService = errors.New(
+		"'--rollback' cannot be specified when deploying all services")
+	ErrImageNoService = errors.New(
+		"'--image' cannot be specified when deploying all services")
+	ErrNoPreviousDeployment = errors.New("no previous deployment to roll back to")
+	ErrServiceUnhealthy     = errors.New("service is not healthy")
 )
 
 // Provision command errors
@@ -63,7 +69,8 @@ var (
 	ErrBranchRequiresTemplate = errors.New(
 		"using branch argument requires a template argument to be specified")
 	ErrMultipleInitModes = errors.New(
-		"only one of init modes: --template, --from-code, or --minimal should be set")
+		"only one of init modes: --template, --from-code, --minimal, --from-resource-group, or --refresh-services " +
+			"should be set")
 )
 
 // Auth command errors
@@ -124,6 +131,21 @@ var (
 	ErrToolUpgradeFailed = errors.New("tool upgrade did not succeed")
 )
 
+// Infrastructure diff errors
+var (
+	ErrDriftDetected = errors.New("infrastructure drift detected")
+)
+
+// Wait errors
+var (
+	ErrWaitTimedOut = errors.New("timed out waiting for condition")
+)
+
+// Template test errors
+var (
+	ErrTemplateTestFailed = errors.New("template test failed")
+)
+
 // Subscription filter errors
 var (
 	ErrInteractiveRequired  = errors.New("interactive mode required")
@@ -30,9 +30,12 @@ var hookNameValues = []string{
 var serviceCommandPaths = []string{
 	"azd build",
 	"azd deploy",
+	"azd logs",
 	"azd package",
 	"azd publish",
 	"azd restore",
+	"azd run",
+	"azd shell",
 }
 
 // GetSuggestions returns static suggestion values for flags that accept a fixed set of options
@@ -84,6 +87,10 @@ func (c *Customizations) GetCommandArgGenerator(ctx *CommandContext, argName str
 		if argName == "environment" {
 			return FigGenListEnvironments
 		}
+	case "azd env copy":
+		if argName == "source-environment" {
+			return FigGenListEnvironments
+		}
 	case "azd template show":
 		if argName == "template" {
 			return FigGenListTemplates
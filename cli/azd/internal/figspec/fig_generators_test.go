@@ -24,6 +24,8 @@ func TestCustomizations_GetCommandArgGenerator(t *testing.T) {
 		{"env_get_value", "azd env get-value", "keyName", FigGenListEnvironmentVariables},
 		{"env_get_value_other", "azd env get-value", "other", ""},
 		{"env_select", "azd env select", "environment", FigGenListEnvironments},
+		{"env_copy", "azd env copy", "source-environment", FigGenListEnvironments},
+		{"env_copy_new", "azd env copy", "new-environment", ""},
 		{"template_show", "azd template show", "template", FigGenListTemplates},
 		// install is handled via GetCommandArgs (combined id|zip arg), not here.
 		{"ext_install", "azd extension install", "extension-id", ""},
@@ -3,6 +3,8 @@
 
 package internal
 
+import "time"
+
 type GlobalCommandOptions struct {
 	// Cwd allows the user to override the current working directory, temporarily.
 	// The root command will take care of cd'ing into that folder before your command
@@ -14,6 +16,12 @@ type GlobalCommandOptions struct {
 	// launched tools. It's enabled with `--debug`, for any command.
 	EnableDebugLogging bool
 
+	// Quiet and Verbose set how much output commands write, with `--quiet` and `--verbose`. Quiet commands only write
+	// their result and errors, and verbose commands write more details. The most verbose of `--quiet`, `--verbose` and
+	// `--debug` applies.
+	Quiet   bool
+	Verbose bool
+
 	// NoPrompt mode disables interactive input.
 	//
 	// Instead of prompting for missing or unclear information, the operation fails.
@@ -35,6 +43,16 @@ type GlobalCommandOptions struct {
 	//     AZD_NON_INTERACTIVE=false)
 	NoPrompt bool
 
+	// AnswersFile is the path of a YAML file with predefined answers to prompts, keyed by prompt keys like `subscription`,
+	// `location` or `parameters.<name>`, or by prompt messages. Answered prompts aren't shown, so that commands can run
+	// without a terminal. Set with `--answers` or the AZD_ANSWERS_FILE environment variable.
+	AnswersFile string
+
+	// PromptTimeout is how long prompts wait for an answer before using their default value, or failing with the
+	// missing input when they have none, so that an unexpected prompt can't hang a CI job. Zero waits indefinitely. Set
+	// with `--prompt-timeout` or the AZD_PROMPT_TIMEOUT environment variable, in seconds.
+	PromptTimeout time.Duration
+
 	// EnvironmentName holds the value of `-e/--environment` parsed from the command line
 	// before Cobra command tree construction. For extension commands (which use
 	// DisableFlagParsing), this is the only reliable way to know what `-e` value
@@ -42,31 +42,13 @@ func (i *Initializer) InitFromApp(
 	i.console.ShowSpinner(ctx, title, input.Step)
 	wd := azdCtx.ProjectDirectory()
 
-	projects := []appdetect.Project{}
 	start := time.Now()
-	sourceDir := filepath.Join(wd, "src")
 	tracing.SetUsageAttributes(fields.AppInitLastStep.String("detect"))
 
-	// Prioritize src directory if it exists
-	if ent, err := os.Stat(sourceDir); err == nil && ent.IsDir() {
-		prj, err := appdetect.Detect(ctx, sourceDir)
-		if err == nil && len(prj) > 0 {
-			projects = prj
-		}
-	}
-
-	if len(projects) == 0 {
-		prj, err := appdetect.Detect(ctx, wd, appdetect.WithExcludePatterns([]string{
-			"**/eng",
-			"**/tool",
-			"**/tools"},
-			false))
-		if err != nil {
-			i.console.StopSpinner(ctx, title, input.GetStepResultFormat(err))
-			return err
-		}
-
-		projects = prj
+	projects, err := detectProjects(ctx, wd)
+	if err != nil {
+		i.console.StopSpinner(ctx, title, input.GetStepResultFormat(err))
+		return err
 	}
 
 	appHostManifests := make(map[string]*apphost.Manifest)
@@ -228,7 +210,7 @@ func (i *Initializer) InitFromApp(
 	tracing.SetUsageAttributes(fields.AppInitLastStep.String("modify"))
 
 	// Confirm selection of services and databases
-	err := detect.Confirm(ctx)
+	err = detect.Confirm(ctx)
 	if err != nil {
 		return err
 	}
@@ -275,6 +257,24 @@ func (i *Initializer) InitFromApp(
 	return nil
 }
 
+// detectProjects detects the projects of the app in the directory wd. Projects of the src directory are preferred when
+// it exists.
+func detectProjects(ctx context.Context, wd string) ([]appdetect.Project, error) {
+	sourceDir := filepath.Join(wd, "src")
+	if ent, err := os.Stat(sourceDir); err == nil && ent.IsDir() {
+		prj, err := appdetect.Detect(ctx, sourceDir)
+		if err == nil && len(prj) > 0 {
+			return prj, nil
+		}
+	}
+
+	return appdetect.Detect(ctx, wd, appdetect.WithExcludePatterns([]string{
+		"**/eng",
+		"**/tool",
+		"**/tools"},
+		false))
+}
+
 func (i *Initializer) genProjectFile(
 	ctx context.Context,
 	azdCtx *azdcontext.AzdContext,
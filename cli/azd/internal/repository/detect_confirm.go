@@ -45,6 +45,10 @@ const (
 	EntryKindModified EntryKind = "modified"
 )
 
+// selectServicesThreshold is the number of detected services above which the services to include are selected upfront,
+// as the repository is likely a monorepo with projects that shouldn't all be hosted.
+const selectServicesThreshold = 3
+
 // detectConfirm handles prompting for confirming the detected services and databases
 type detectConfirm struct {
 	// detected services and databases
@@ -111,6 +115,12 @@ func (d *detectConfirm) Confirm(ctx context.Context) error {
 		optionAddService         = "Add an undetected service"
 	)
 
+	if len(d.Services) > selectServicesThreshold && !d.console.IsNoPromptMode() {
+		if err := d.selectServices(ctx); err != nil {
+			return err
+		}
+	}
+
 	for {
 		if err := d.render(ctx); err != nil {
 			return err
@@ -158,6 +168,53 @@ func (d *detectConfirm) Confirm(ctx context.Context) error {
 	}
 }
 
+// selectServices prompts the user to select which of the detected services to include. Detected databases that are no
+// longer used by a selected service are removed.
+func (d *detectConfirm) selectServices(ctx context.Context) error {
+	options := make([]string, 0, len(d.Services))
+	for _, svc := range d.Services {
+		options = append(options, fmt.Sprintf("%s in %s", projectDisplayName(svc), relSafe(d.root, svc.Path)))
+	}
+
+	selected, err := d.console.MultiSelect(ctx, input.ConsoleOptions{
+		Message:      fmt.Sprintf("%d services were detected. Select the services to include", len(d.Services)),
+		Options:      options,
+		DefaultValue: options,
+	})
+	if err != nil {
+		return err
+	}
+
+	services := make([]appdetect.Project, 0, len(selected))
+	for i, svc := range d.Services {
+		if slices.Contains(selected, options[i]) {
+			services = append(services, svc)
+		}
+	}
+
+	if len(services) == len(d.Services) {
+		return nil
+	}
+
+	d.Services = services
+	d.modified = true
+
+	for db, entry := range d.Databases {
+		if entry != EntryKindDetected {
+			continue
+		}
+
+		used := slices.ContainsFunc(d.Services, func(svc appdetect.Project) bool {
+			return slices.Contains(svc.DatabaseDeps, db)
+		})
+		if !used {
+			delete(d.Databases, db)
+		}
+	}
+
+	return nil
+}
+
 func (d *detectConfirm) render(ctx context.Context) error {
 	if d.modified {
 		d.console.ShowSpinner(ctx, "Revising detected services", input.Step)
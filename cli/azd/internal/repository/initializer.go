@@ -121,13 +121,14 @@ func (i *Initializer) Initialize(
 	}()
 
 	var filesWithExecPerms []string
+	var commit string
 	if templates.IsLocalPath(templateUrl) {
 		err = i.copyLocalTemplate(templateUrl, staging)
 		if err == nil {
 			filesWithExecPerms, err = findExecutableFiles(staging)
 		}
 	} else {
-		filesWithExecPerms, err = i.fetchCode(ctx, templateUrl, templateBranch, staging)
+		filesWithExecPerms, commit, err = i.fetchCode(ctx, templateUrl, templateBranch, staging)
 	}
 	if err != nil {
 		return err
@@ -142,6 +143,12 @@ func (i *Initializer) Initialize(
 	// keep only those still present in staging.
 	filesWithExecPerms = filterExistingFiles(staging, filesWithExecPerms)
 
+	// Prompt for the values declared by the template manifest, and customize the files with them.
+	if err := i.applyTemplateManifest(ctx, staging); err != nil {
+		return err
+	}
+	filesWithExecPerms = filterExistingFiles(staging, filesWithExecPerms)
+
 	skipStagingFiles, err := i.promptForDuplicates(ctx, staging, target)
 	if err != nil {
 		return err
@@ -176,6 +183,18 @@ func (i *Initializer) Initialize(
 		return fmt.Errorf("initializing project: %w", err)
 	}
 
+	// Record the version of the template so the project can be upgraded with azd template upgrade
+	if commit != "" {
+		err = recordTemplateSource(azdCtx.ProjectPath(), &project.TemplateSource{
+			Repository: templateUrl,
+			Branch:     templateBranch,
+			Commit:     commit,
+		})
+		if err != nil {
+			return fmt.Errorf("recording template version: %w", err)
+		}
+	}
+
 	err = i.gitInitialize(ctx, target, filesWithExecPerms, isEmpty)
 	if err != nil {
 		return err
@@ -188,27 +207,34 @@ func (i *Initializer) fetchCode(
 	ctx context.Context,
 	templateUrl string,
 	templateBranch string,
-	destination string) (executableFilePaths []string, err error) {
+	destination string) (executableFilePaths []string, commit string, err error) {
 	err = i.gitCli.ShallowClone(ctx, templateUrl, templateBranch, destination)
 	if err != nil {
-		return nil, fmt.Errorf("fetching template: %w", err)
+		return nil, "", fmt.Errorf("fetching template: %w", err)
 	}
 
 	stagedFilesOutput, err := i.gitCli.ListStagedFiles(ctx, destination)
 	if err != nil {
-		return nil, fmt.Errorf("listing files with permissions: %w", err)
+		return nil, "", fmt.Errorf("listing files with permissions: %w", err)
 	}
 
 	executableFilePaths, err = parseExecutableFiles(stagedFilesOutput)
 	if err != nil {
-		return nil, fmt.Errorf("parsing file permissions output: %w", err)
+		return nil, "", fmt.Errorf("parsing file permissions output: %w", err)
+	}
+
+	// The commit is only used to upgrade the project later on, so failing to get it doesn't fail the init.
+	commit, err = i.gitCli.GetHeadCommit(ctx, destination)
+	if err != nil {
+		log.Printf("getting the commit of template %s: %v", templateUrl, err)
+		commit = ""
 	}
 
 	if err := os.RemoveAll(filepath.Join(destination, ".git")); err != nil {
-		return nil, fmt.Errorf("removing .git folder after clone: %w", err)
+		return nil, "", fmt.Errorf("removing .git folder after clone: %w", err)
 	}
 
-	return executableFilePaths, nil
+	return executableFilePaths, commit, nil
 }
 
 // copyLocalTemplate copies a local template directory to the destination, respecting .gitignore
@@ -277,10 +303,11 @@ func (i *Initializer) copyLocalTemplate(source, destination string) error {
 				return true, nil
 			}
 
-			// Never skip the root .azdignore — it must reach staging so
-			// removeAzdIgnoredFiles can apply its rules and then remove it.
+			// Never skip the root .azdignore and template manifest — they must reach staging so
+			// removeAzdIgnoredFiles and applyTemplateManifest can apply and then remove them.
 			if relToSource, relErr := filepath.Rel(source, src); relErr == nil &&
-				filepath.ToSlash(relToSource) == azdIgnoreFileName {
+				(filepath.ToSlash(relToSource) == azdIgnoreFileName ||
+					filepath.ToSlash(relToSource) == templateManifestFileName) {
 				return false, nil
 			}
 
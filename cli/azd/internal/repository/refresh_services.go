@@ -0,0 +1,213 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/azure/azure-dev/cli/azd/internal/appdetect"
+	"github.com/azure/azure-dev/cli/azd/internal/cmd/add"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/yamlnode"
+	"github.com/braydonk/yaml"
+)
+
+// RefreshServices detects the projects of the app that aren't services of the project yet, and adds the ones selected
+// by the user to azure.yaml. It returns the names of the added services.
+func (i *Initializer) RefreshServices(ctx context.Context, azdCtx *azdcontext.AzdContext) ([]string, error) {
+	prjConfig, err := project.Load(ctx, azdCtx.ProjectPath())
+	if err != nil {
+		return nil, err
+	}
+
+	i.console.Message(ctx, "")
+	title := "Scanning app code in current directory"
+	i.console.ShowSpinner(ctx, title, input.Step)
+	projects, err := detectProjects(ctx, azdCtx.ProjectDirectory())
+	i.console.StopSpinner(ctx, title, input.GetStepResultFormat(err))
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := newProjects(prjConfig, projects)
+	if len(candidates) == 0 {
+		i.console.Message(ctx, "\nNo new services were detected.")
+		return nil, nil
+	}
+
+	options := make([]string, 0, len(candidates))
+	for _, prj := range candidates {
+		options = append(options,
+			fmt.Sprintf("%s in %s", projectDisplayName(prj), relSafe(azdCtx.ProjectDirectory(), prj.Path)))
+	}
+
+	selected, err := i.console.MultiSelect(ctx, input.ConsoleOptions{
+		Message:      "Select the new services to add to " + azdcontext.ProjectFileName,
+		Options:      options,
+		DefaultValue: options,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	taken := map[string]struct{}{}
+	for name := range prjConfig.Services {
+		taken[name] = struct{}{}
+	}
+	for name := range prjConfig.Resources {
+		taken[name] = struct{}{}
+	}
+
+	var services []*project.ServiceConfig
+	var resources []*project.ResourceConfig
+	for idx, prj := range candidates {
+		if !slices.Contains(selected, options[idx]) {
+			continue
+		}
+
+		svc, err := add.ServiceFromDetect(azdCtx.ProjectDirectory(), "", prj, project.ContainerAppTarget)
+		if err != nil {
+			return nil, err
+		}
+
+		svc.Name = uniqueName(svc.Name, taken)
+		services = append(services, svc)
+
+		// projects that declare their hosts as resources get a host for the new service
+		if len(prjConfig.Resources) > 0 {
+			port, err := add.PromptPort(i.console, ctx, svc.Name, prj)
+			if err != nil {
+				return nil, err
+			}
+
+			resources = append(resources, &project.ResourceConfig{
+				Type:  project.ResourceTypeHostContainerApp,
+				Name:  svc.Name,
+				Props: project.ContainerAppProps{Port: port},
+			})
+		}
+	}
+
+	if len(services) == 0 {
+		return nil, nil
+	}
+
+	if err := addToProjectFile(azdCtx.ProjectPath(), services, resources); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(services))
+	for _, svc := range services {
+		names = append(names, svc.Name)
+		i.console.MessageUxItem(ctx, &ux.DoneMessage{
+			Message: fmt.Sprintf("Adding service %s", output.WithHighLightFormat(svc.Name)),
+		})
+	}
+
+	return names, nil
+}
+
+// newProjects returns the detected projects that can be hosted and aren't services of the project yet.
+func newProjects(prjConfig *project.ProjectConfig, projects []appdetect.Project) []appdetect.Project {
+	var result []appdetect.Project
+	for _, prj := range projects {
+		if _, supported := add.LanguageMap[prj.Language]; !supported || prj.Language == appdetect.DotNetAppHost {
+			continue
+		}
+
+		existing := false
+		for _, svc := range prjConfig.Services {
+			if filepath.Clean(svc.Path()) == filepath.Clean(prj.Path) {
+				existing = true
+				break
+			}
+		}
+
+		if !existing {
+			result = append(result, prj)
+		}
+	}
+
+	return result
+}
+
+// addToProjectFile adds the services and resources to the project file at path, preserving its formatting and comments.
+func addToProjectFile(
+	path string,
+	services []*project.ServiceConfig,
+	resources []*project.ResourceConfig) error {
+	return editProjectFile(path, func(doc *yaml.Node) error {
+		for _, svc := range services {
+			node, err := yamlnode.Encode(svc)
+			if err != nil {
+				return fmt.Errorf("encoding service %s: %w", svc.Name, err)
+			}
+
+			if err := yamlnode.Set(doc, fmt.Sprintf("services?.%s", svc.Name), node); err != nil {
+				return fmt.Errorf("adding service: %w", err)
+			}
+		}
+
+		for _, res := range resources {
+			node, err := yamlnode.Encode(res)
+			if err != nil {
+				return fmt.Errorf("encoding resource %s: %w", res.Name, err)
+			}
+
+			if err := yamlnode.Set(doc, fmt.Sprintf("resources?.%s", res.Name), node); err != nil {
+				return fmt.Errorf("adding resource: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// editProjectFile edits the document of the project file at path with edit, preserving its formatting and comments.
+func editProjectFile(path string, edit func(doc *yaml.Node) error) error {
+	file, err := os.OpenFile(path, os.O_RDWR, osutil.PermissionFile)
+	if err != nil {
+		return fmt.Errorf("reading project file: %w", err)
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.SetScanBlockScalarAsLiteral(true)
+
+	var doc yaml.Node
+	if err := decoder.Decode(&doc); err != nil {
+		return fmt.Errorf("failed to decode: %w", err)
+	}
+
+	if err := edit(&doc); err != nil {
+		return err
+	}
+
+	if err := file.Truncate(0); err != nil {
+		return fmt.Errorf("truncating file: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("seeking to start of file: %w", err)
+	}
+
+	encoder := yaml.NewEncoder(file)
+	encoder.SetIndent(2)
+	// preserve multi-line blocks style
+	encoder.SetAssumeBlockAsLiteral(true)
+	if err := encoder.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to encode: %w", err)
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,107 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/internal/appdetect"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitializer_RefreshServices(t *testing.T) {
+	dir := t.TempDir()
+	dir, err := filepath.EvalSymlinks(dir)
+	require.NoError(t, err)
+
+	for _, svc := range []string{"api", "web", "worker"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "src", svc), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(
+			filepath.Join(dir, "src", svc, "requirements.txt"), []byte("flask\n"), osutil.PermissionFile))
+	}
+
+	azureYaml := `# project comment
+name: app
+services:
+  api:
+    project: src/api
+    host: containerapp
+    language: python
+`
+	azdCtx := azdcontext.NewAzdContextWithDirectory(dir)
+	require.NoError(t, os.WriteFile(azdCtx.ProjectPath(), []byte(azureYaml), osutil.PermissionFile))
+
+	console := mockinput.NewMockConsole()
+	console.WhenMultiSelect(func(options input.ConsoleOptions) bool { return true }).
+		RespondFn(func(options input.ConsoleOptions) (any, error) {
+			require.Equal(t, []string{
+				"Python in " + filepath.Join("src", "web"),
+				"Python in " + filepath.Join("src", "worker"),
+			}, options.Options)
+
+			return []string{options.Options[1]}, nil
+		})
+
+	i := &Initializer{console: console}
+	added, err := i.RefreshServices(t.Context(), azdCtx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"worker"}, added)
+
+	contents, err := os.ReadFile(azdCtx.ProjectPath())
+	require.NoError(t, err)
+	require.Contains(t, string(contents), "# project comment")
+
+	prjConfig, err := project.Load(t.Context(), azdCtx.ProjectPath())
+	require.NoError(t, err)
+	require.Len(t, prjConfig.Services, 2)
+	require.Equal(t, filepath.Join("src", "worker"), prjConfig.Services["worker"].RelativePath)
+	require.Equal(t, project.ServiceLanguagePython, prjConfig.Services["worker"].Language)
+
+	// only web isn't a service yet
+	projects, err := detectProjects(t.Context(), dir)
+	require.NoError(t, err)
+	require.Len(t, newProjects(prjConfig, projects), 1)
+}
+
+func Test_detectConfirm_selectServices(t *testing.T) {
+	dir := t.TempDir()
+
+	services := []appdetect.Project{}
+	for _, name := range []string{"a", "b", "c", "d"} {
+		services = append(services, appdetect.Project{
+			Language:     appdetect.Python,
+			Path:         filepath.Join(dir, name),
+			DatabaseDeps: []appdetect.DatabaseDep{appdetect.DatabaseDep("db-" + name)},
+		})
+	}
+
+	console := mockinput.NewMockConsole()
+	console.WhenMultiSelect(func(options input.ConsoleOptions) bool { return true }).
+		RespondFn(func(options input.ConsoleOptions) (any, error) {
+			require.Equal(t, options.Options, options.DefaultValue)
+			return []string{options.Options[0], options.Options[2]}, nil
+		})
+
+	d := detectConfirm{console: console, root: dir, Services: services}
+	d.Databases = map[appdetect.DatabaseDep]EntryKind{
+		"db-a": EntryKindDetected,
+		"db-b": EntryKindDetected,
+		"db-d": EntryKindManual,
+	}
+
+	require.NoError(t, d.selectServices(t.Context()))
+	require.Equal(t, []appdetect.Project{services[0], services[2]}, d.Services)
+	require.Equal(t, map[appdetect.DatabaseDep]EntryKind{
+		"db-a": EntryKindDetected,
+		"db-d": EntryKindManual,
+	}, d.Databases)
+	require.True(t, d.modified)
+}
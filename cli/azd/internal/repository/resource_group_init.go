@@ -0,0 +1,247 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/internal/appdetect"
+	"github.com/azure/azure-dev/cli/azd/internal/cmd/add"
+	"github.com/azure/azure-dev/cli/azd/internal/names"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+)
+
+// InitFromResourceGroup initializes a project from the resources of an existing resource group.
+//
+// Web apps, function apps, static web apps and container apps become services that are deployed to the existing
+// resources. The other resources that can be referenced by a project become existing resources. The environment is
+// linked to the resource group.
+func (i *Initializer) InitFromResourceGroup(
+	ctx context.Context,
+	azdCtx *azdcontext.AzdContext,
+	resourceGroup *azapi.ResourceGroup,
+	resources []*azapi.ResourceExtended,
+	initializeEnv func() (*environment.Environment, error)) error {
+	i.console.ShowSpinner(ctx, "Discovering resources", input.Step)
+	config, skipped, err := i.prjConfigFromResources(ctx, azdCtx.ProjectDirectory(), resources)
+	i.console.StopSpinner(ctx, "Discovering resources", input.GetStepResultFormat(err))
+	if err != nil {
+		return err
+	}
+
+	if len(config.Services) == 0 && len(config.Resources) == 0 {
+		return fmt.Errorf("no resources that can be added to a project were found in resource group '%s'",
+			resourceGroup.Name)
+	}
+
+	for _, res := range skipped {
+		i.console.Message(ctx, output.WithGrayFormat("  Skipping %s (%s): not supported", res.Name, res.Type))
+	}
+
+	tracing.SetUsageAttributes(fields.AppInitLastStep.String("config"))
+
+	env, err := initializeEnv()
+	if err != nil {
+		return err
+	}
+
+	env.DotenvSet(environment.ResourceGroupEnvVarName, resourceGroup.Name)
+	if env.GetLocation() == "" {
+		env.DotenvSet(environment.LocationEnvVarName, resourceGroup.Location)
+	}
+
+	for name, res := range config.Resources {
+		env.DotenvSet(infra.ResourceIdName(name), res.ResourceId)
+	}
+
+	envManager, err := i.lazyEnvManager.GetValue()
+	if err != nil {
+		return err
+	}
+
+	if err := envManager.Save(ctx, env); err != nil {
+		return fmt.Errorf("saving environment: %w", err)
+	}
+
+	tracing.SetUsageAttributes(fields.AppInitLastStep.String("generate"))
+
+	title := "Generating " + output.WithHighLightFormat("./"+azdcontext.ProjectFileName)
+	i.console.ShowSpinner(ctx, title, input.Step)
+	err = project.Save(ctx, &config, azdCtx.ProjectPath())
+	if err == nil {
+		err = i.writeCoreAssets(ctx, azdCtx)
+	}
+	i.console.StopSpinner(ctx, title, input.GetStepResultFormat(err))
+	if err != nil {
+		return err
+	}
+
+	i.console.MessageUxItem(ctx, &ux.DoneMessage{
+		Message: fmt.Sprintf("Linking environment %s to resource group %s",
+			output.WithHighLightFormat(env.Name()), output.WithHighLightFormat(resourceGroup.Name)),
+	})
+
+	return nil
+}
+
+// prjConfigFromResources returns the project configuration for the resources of a resource group, along with the
+// resources that were skipped because they can't be added to a project.
+func (i *Initializer) prjConfigFromResources(
+	ctx context.Context,
+	root string,
+	resources []*azapi.ResourceExtended) (project.ProjectConfig, []*azapi.ResourceExtended, error) {
+	config := project.ProjectConfig{
+		Name: azdcontext.ProjectName(root),
+		Metadata: &project.ProjectMetadata{
+			Template: fmt.Sprintf("%s@%s", InitGenTemplateId, internal.VersionInfo().Version),
+		},
+		Services:  map[string]*project.ServiceConfig{},
+		Resources: map[string]*project.ResourceConfig{},
+	}
+
+	resources = slices.Clone(resources)
+	slices.SortFunc(resources, func(a, b *azapi.ResourceExtended) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	var hosted []*azapi.ResourceExtended
+	var skipped []*azapi.ResourceExtended
+	taken := map[string]struct{}{}
+
+	for _, res := range resources {
+		if serviceHost(res) != project.NonSpecifiedTarget {
+			hosted = append(hosted, res)
+			continue
+		}
+
+		resourceType := add.ExistingResourceType(res.Type, res.Kind)
+		if resourceType == "" {
+			skipped = append(skipped, res)
+			continue
+		}
+
+		name := uniqueName(names.LabelName(res.Name), taken)
+		config.Resources[name] = &project.ResourceConfig{
+			Type:       resourceType,
+			Name:       name,
+			Existing:   true,
+			ResourceId: res.Id,
+		}
+	}
+
+	for _, res := range hosted {
+		name := uniqueName(names.LabelName(res.Name), taken)
+
+		defaultPath := filepath.Join("src", name)
+		if len(hosted) == 1 {
+			defaultPath = "."
+		}
+
+		path, err := i.console.Prompt(ctx, input.ConsoleOptions{
+			Message:      fmt.Sprintf("Where is the code of %s, relative to the project?", output.WithBold("%s", res.Name)),
+			Help:         "The code is deployed to the existing resource when running azd deploy.",
+			DefaultValue: defaultPath,
+		})
+		if err != nil {
+			return config, nil, err
+		}
+
+		svc, err := serviceFromResource(ctx, root, name, filepath.Clean(path), res)
+		if err != nil {
+			return config, nil, err
+		}
+
+		config.Services[name] = svc
+	}
+
+	return config, skipped, nil
+}
+
+// serviceFromResource returns the configuration of the service name, whose code is at path, that is deployed to the
+// existing resource res. The language of the service is detected when the code exists.
+func serviceFromResource(
+	ctx context.Context,
+	root string,
+	name string,
+	path string,
+	res *azapi.ResourceExtended) (*project.ServiceConfig, error) {
+	host := serviceHost(res)
+	svc := &project.ServiceConfig{
+		Name:         name,
+		Host:         host,
+		RelativePath: path,
+	}
+
+	if _, err := os.Stat(filepath.Join(root, path)); err == nil {
+		prj, err := appdetect.DetectDirectory(ctx, filepath.Join(root, path))
+		if err != nil {
+			return nil, fmt.Errorf("detecting project of %s: %w", name, err)
+		}
+
+		if prj != nil {
+			if detected, err := add.ServiceFromDetect(root, name, *prj, host); err == nil {
+				svc = detected
+			}
+		}
+	}
+
+	svc.ResourceName = osutil.NewExpandableString(res.Name)
+	return svc, nil
+}
+
+// serviceHost returns the host of the services deployed to the resource res, or NonSpecifiedTarget when services
+// can't be deployed to it.
+func serviceHost(res *azapi.ResourceExtended) project.ServiceTargetKind {
+	kind := strings.ToLower(res.Kind)
+
+	switch strings.ToLower(res.Type) {
+	case strings.ToLower(string(azapi.AzureResourceTypeWebSite)):
+		if strings.Contains(kind, "workflowapp") {
+			return project.NonSpecifiedTarget
+		}
+
+		if strings.Contains(kind, "functionapp") {
+			return project.AzureFunctionTarget
+		}
+
+		return project.AppServiceTarget
+	case strings.ToLower(string(azapi.AzureResourceTypeStaticWebSite)):
+		return project.StaticWebAppTarget
+	case strings.ToLower(string(azapi.AzureResourceTypeContainerApp)):
+		return project.ContainerAppTarget
+	}
+
+	return project.NonSpecifiedTarget
+}
+
+// uniqueName returns name, suffixed with a number when it's already taken, and records it as taken.
+func uniqueName(name string, taken map[string]struct{}) string {
+	unique := name
+	for n := 2; ; n++ {
+		if _, has := taken[unique]; !has {
+			break
+		}
+
+		unique = fmt.Sprintf("%s-%d", name, n)
+	}
+
+	taken[unique] = struct{}{}
+	return unique
+}
@@ -0,0 +1,85 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitializer_prjConfigFromResources(t *testing.T) {
+	resource := func(name string, resourceType string, kind string) *azapi.ResourceExtended {
+		return &azapi.ResourceExtended{
+			Resource: azapi.Resource{
+				Id:   "/subscriptions/sub/resourceGroups/rg/providers/" + resourceType + "/" + name,
+				Name: name,
+				Type: resourceType,
+			},
+			Kind: kind,
+		}
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "src", "api"), osutil.PermissionDirectory))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(dir, "src", "api", "requirements.txt"), []byte("flask\n"), osutil.PermissionFile))
+
+	i := &Initializer{
+		console: newCapturedTestConsole(t, []string{
+			"",           // code of api
+			"src/worker", // code of worker
+		}),
+	}
+
+	storage := resource("appstorage", "Microsoft.Storage/storageAccounts", "StorageV2")
+	config, skipped, err := i.prjConfigFromResources(t.Context(), dir, []*azapi.ResourceExtended{
+		resource("worker", "Microsoft.Web/sites", "functionapp,linux"),
+		resource("api", "Microsoft.App/containerApps", ""),
+		storage,
+		resource("cache", "Microsoft.Cache/redis", ""),
+		resource("logs", "Microsoft.OperationalInsights/workspaces", ""),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, config.Services, 2)
+
+	api := config.Services["api"]
+	require.Equal(t, project.ContainerAppTarget, api.Host)
+	require.Equal(t, filepath.Join("src", "api"), api.RelativePath)
+	require.Equal(t, project.ServiceLanguagePython, api.Language)
+	require.Equal(t, osutil.NewExpandableString("api"), api.ResourceName)
+
+	worker := config.Services["worker"]
+	require.Equal(t, project.AzureFunctionTarget, worker.Host)
+	require.Equal(t, filepath.Join("src", "worker"), worker.RelativePath)
+	require.Equal(t, project.ServiceLanguageKind(""), worker.Language)
+
+	require.Equal(t, map[string]*project.ResourceConfig{
+		"appstorage": {
+			Type:       project.ResourceTypeStorage,
+			Name:       "appstorage",
+			Existing:   true,
+			ResourceId: storage.Id,
+		},
+	}, config.Resources)
+
+	skippedNames := []string{}
+	for _, res := range skipped {
+		skippedNames = append(skippedNames, res.Name)
+	}
+	require.Equal(t, []string{"cache", "logs"}, skippedNames)
+}
+
+func TestUniqueName(t *testing.T) {
+	taken := map[string]struct{}{}
+	require.Equal(t, "api", uniqueName("api", taken))
+	require.Equal(t, "api-2", uniqueName("api", taken))
+	require.Equal(t, "api-3", uniqueName("api", taken))
+}
@@ -0,0 +1,438 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/braydonk/yaml"
+	gitignore "github.com/denormal/go-gitignore"
+)
+
+// templateManifestFileName is the name of the file template authors can place at the root of a template
+// repository to prompt for values when consumers run azd init, and to customize the files of the project with them.
+const templateManifestFileName = ".azdtemplate.yaml"
+
+// templateManifestMaxSize is the maximum allowed size for a template manifest (1 MB).
+const templateManifestMaxSize = 1 << 20
+
+// templateManifest is the contents of a template manifest.
+type templateManifest struct {
+	// Prompts are the values prompted for, in order.
+	Prompts []templatePrompt `yaml:"prompts"`
+	// Files configures how the files of the template are customized with the values.
+	Files templateFiles `yaml:"files"`
+}
+
+// templatePrompt is a value prompted for by azd init.
+type templatePrompt struct {
+	// Name is the name of the value, used in tokens (`{{ azd.<name> }}`) and conditions.
+	Name string `yaml:"name"`
+	// Message is the message of the prompt.
+	Message string `yaml:"message"`
+	// Help is the help text of the prompt.
+	Help string `yaml:"help"`
+	// Type is either `string` (the default) or `boolean`.
+	Type string `yaml:"type"`
+	// Default is the default value.
+	Default any `yaml:"default"`
+	// Options restricts a string value to a list of options.
+	Options []string `yaml:"options"`
+}
+
+// templateFiles configures how the files of the template are customized.
+type templateFiles struct {
+	// Replace lists, in .gitignore syntax, the files in which tokens are replaced. Tokens are replaced in all the
+	// text files when empty.
+	Replace []string `yaml:"replace"`
+	// Include lists the files that are only included when a condition is met.
+	Include []templateInclude `yaml:"include"`
+}
+
+// templateInclude includes files only when a condition is met.
+type templateInclude struct {
+	// Paths lists the files, in .gitignore syntax.
+	Paths []string `yaml:"paths"`
+	// When is the condition: `<name>` or `!<name>` for a boolean value, `<name> == <value>` or `<name> != <value>` for
+	// a string value.
+	When string `yaml:"when"`
+}
+
+const (
+	templatePromptString  = "string"
+	templatePromptBoolean = "boolean"
+)
+
+var (
+	templatePromptNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	templateTokenRegex      = regexp.MustCompile(`\{\{\s*azd\.([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+	templateConditionRegex  = regexp.MustCompile(`^\s*(!?)\s*([A-Za-z_][A-Za-z0-9_]*)\s*(?:(==|!=)\s*(.*?))?\s*$`)
+)
+
+// loadTemplateManifest reads the template manifest from the root of dir. Returns nil if there is no manifest.
+func loadTemplateManifest(dir string) (*templateManifest, error) {
+	path := filepath.Join(dir, templateManifestFileName)
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", templateManifestFileName, err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s must be a regular file", templateManifestFileName)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", templateManifestFileName, err)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, templateManifestMaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", templateManifestFileName, err)
+	}
+	if len(data) > templateManifestMaxSize {
+		return nil, fmt.Errorf(
+			"%s exceeds maximum size (%d bytes)", templateManifestFileName, templateManifestMaxSize)
+	}
+
+	var manifest templateManifest
+	if err := yaml.Unmarshal(bytes.TrimPrefix(data, utf8BOM), &manifest); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", templateManifestFileName, err)
+	}
+
+	if err := manifest.validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", templateManifestFileName, err)
+	}
+
+	return &manifest, nil
+}
+
+// validate checks the prompts, and that the conditions only reference prompts of the right type.
+func (m *templateManifest) validate() error {
+	types := map[string]string{}
+	for idx := range m.Prompts {
+		prompt := &m.Prompts[idx]
+		if !templatePromptNameRegex.MatchString(prompt.Name) {
+			return fmt.Errorf("prompt name '%s' must only contain letters, digits and underscores", prompt.Name)
+		}
+		if _, has := types[prompt.Name]; has {
+			return fmt.Errorf("prompt '%s' is declared more than once", prompt.Name)
+		}
+		if prompt.Message == "" {
+			return fmt.Errorf("prompt '%s' has no message", prompt.Name)
+		}
+
+		switch prompt.Type {
+		case "":
+			prompt.Type = templatePromptString
+		case templatePromptString:
+		case templatePromptBoolean:
+			if len(prompt.Options) > 0 {
+				return fmt.Errorf("boolean prompt '%s' can't have options", prompt.Name)
+			}
+			if _, isBool := prompt.Default.(bool); prompt.Default != nil && !isBool {
+				return fmt.Errorf("the default value of boolean prompt '%s' must be true or false", prompt.Name)
+			}
+		default:
+			return fmt.Errorf("prompt '%s' has unsupported type '%s'", prompt.Name, prompt.Type)
+		}
+
+		if prompt.Type == templatePromptString && prompt.Default != nil && len(prompt.Options) > 0 &&
+			!slices.Contains(prompt.Options, fmt.Sprint(prompt.Default)) {
+			return fmt.Errorf("the default value of prompt '%s' isn't one of its options", prompt.Name)
+		}
+
+		types[prompt.Name] = prompt.Type
+	}
+
+	for _, include := range m.Files.Include {
+		if len(include.Paths) == 0 {
+			return errors.New("include entries must list paths")
+		}
+
+		match := templateConditionRegex.FindStringSubmatch(include.When)
+		if match == nil {
+			return fmt.Errorf("invalid condition '%s'", include.When)
+		}
+
+		negate, name, operator := match[1] == "!", match[2], match[3]
+		promptType, has := types[name]
+		switch {
+		case !has:
+			return fmt.Errorf("condition '%s' references unknown prompt '%s'", include.When, name)
+		case operator == "" && promptType != templatePromptBoolean:
+			return fmt.Errorf("condition '%s' must compare string prompt '%s' with == or !=", include.When, name)
+		case operator != "" && (negate || promptType != templatePromptString):
+			return fmt.Errorf("invalid condition '%s'", include.When)
+		}
+	}
+
+	return nil
+}
+
+// promptTemplateValues prompts for the values declared by the manifest. The values are formatted as strings, booleans
+// being "true" or "false".
+func (i *Initializer) promptTemplateValues(ctx context.Context, manifest *templateManifest) (map[string]string, error) {
+	values := make(map[string]string, len(manifest.Prompts))
+
+	for _, prompt := range manifest.Prompts {
+		options := input.ConsoleOptions{
+			Key:     "template." + prompt.Name,
+			Message: prompt.Message,
+			Help:    prompt.Help,
+		}
+
+		switch {
+		case prompt.Type == templatePromptBoolean:
+			defaultValue, _ := prompt.Default.(bool)
+			options.DefaultValue = defaultValue
+
+			value, err := i.console.Confirm(ctx, options)
+			if err != nil {
+				return nil, fmt.Errorf("prompting for %s: %w", prompt.Name, err)
+			}
+			values[prompt.Name] = strconv.FormatBool(value)
+		case len(prompt.Options) > 0:
+			options.Options = prompt.Options
+			if prompt.Default != nil {
+				options.DefaultValue = fmt.Sprint(prompt.Default)
+			}
+
+			selected, err := i.console.Select(ctx, options)
+			if err != nil {
+				return nil, fmt.Errorf("prompting for %s: %w", prompt.Name, err)
+			}
+			values[prompt.Name] = prompt.Options[selected]
+		default:
+			if prompt.Default != nil {
+				options.DefaultValue = fmt.Sprint(prompt.Default)
+			}
+
+			value, err := i.console.Prompt(ctx, options)
+			if err != nil {
+				return nil, fmt.Errorf("prompting for %s: %w", prompt.Name, err)
+			}
+			values[prompt.Name] = value
+		}
+	}
+
+	return values, nil
+}
+
+// applyTemplateManifest prompts for the values declared by the template manifest at the root of dir, removes the
+// files whose conditions aren't met and replaces the tokens in the remaining files and their paths. The manifest
+// itself is removed. This is a no-op when there is no manifest.
+func (i *Initializer) applyTemplateManifest(ctx context.Context, dir string) error {
+	manifest, err := loadTemplateManifest(dir)
+	if err != nil || manifest == nil {
+		return err
+	}
+
+	if len(manifest.Prompts) > 0 {
+		i.console.StopSpinner(ctx, "", input.StepDone)
+	}
+
+	values, err := i.promptTemplateValues(ctx, manifest)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(filepath.Join(dir, templateManifestFileName)); err != nil {
+		return fmt.Errorf("removing %s: %w", templateManifestFileName, err)
+	}
+
+	return customizeTemplateFiles(dir, manifest, values)
+}
+
+// customizeTemplateFiles removes the files of dir whose conditions aren't met, and replaces the tokens in the
+// remaining files and their paths with values.
+func customizeTemplateFiles(dir string, manifest *templateManifest, values map[string]string) error {
+	for _, include := range manifest.Files.Include {
+		if evaluateTemplateCondition(include.When, values) {
+			continue
+		}
+
+		excluded := gitignore.New(strings.NewReader(strings.Join(include.Paths, "\n")), dir, nil)
+		if err := removeMatchingFiles(dir, excluded); err != nil {
+			return fmt.Errorf("excluding files: %w", err)
+		}
+	}
+
+	var replaced gitignore.GitIgnore
+	if len(manifest.Files.Replace) > 0 {
+		replaced = gitignore.New(strings.NewReader(strings.Join(manifest.Files.Replace, "\n")), dir, nil)
+	}
+
+	replace := func(text []byte) []byte {
+		return templateTokenRegex.ReplaceAllFunc(text, func(token []byte) []byte {
+			name := templateTokenRegex.FindSubmatch(token)[1]
+			if value, has := values[string(name)]; has {
+				return []byte(value)
+			}
+
+			// Unknown tokens are kept as they are.
+			return token
+		})
+	}
+
+	// Paths are collected first, and renamed deepest first, so that renaming a directory doesn't affect the paths
+	// of its contents.
+	var renames []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+
+		if d.Name() == ".git" && d.IsDir() {
+			return filepath.SkipDir
+		}
+
+		if templateTokenRegex.MatchString(d.Name()) {
+			renames = append(renames, path)
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		if replaced != nil && !matchesPathOrParent(replaced, filepath.ToSlash(rel)) {
+			return nil
+		}
+
+		return replaceFileTokens(path, replace)
+	})
+	if err != nil {
+		return fmt.Errorf("replacing tokens: %w", err)
+	}
+
+	for idx := len(renames) - 1; idx >= 0; idx-- {
+		path := renames[idx]
+		name := string(replace([]byte(filepath.Base(path))))
+		if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+			return fmt.Errorf("the value replacing the tokens of '%s' isn't a valid file name", filepath.Base(path))
+		}
+
+		if err := os.Rename(path, filepath.Join(filepath.Dir(path), name)); err != nil {
+			return fmt.Errorf("renaming %s: %w", filepath.Base(path), err)
+		}
+	}
+
+	return nil
+}
+
+// matchesPathOrParent reports whether the file at the slash-separated relative path, or one of its parent
+// directories, is matched by patterns.
+func matchesPathOrParent(patterns gitignore.GitIgnore, rel string) bool {
+	isDir := false
+	for {
+		if match := patterns.Relative(rel, isDir); match != nil {
+			return match.Ignore()
+		}
+
+		idx := strings.LastIndex(rel, "/")
+		if idx < 0 {
+			return false
+		}
+		rel, isDir = rel[:idx], true
+	}
+}
+
+// replaceFileTokens replaces the tokens of the text file at path. Binary files are left unchanged.
+func replaceFileTokens(path string, replace func([]byte) []byte) error {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	// Like git, files with a NUL byte in their first 8000 bytes are considered binary.
+	if bytes.IndexByte(contents[:min(len(contents), 8000)], 0) >= 0 || !templateTokenRegex.Match(contents) {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, replace(contents), info.Mode().Perm())
+}
+
+// evaluateTemplateCondition evaluates a validated include condition with the prompted values.
+func evaluateTemplateCondition(condition string, values map[string]string) bool {
+	match := templateConditionRegex.FindStringSubmatch(condition)
+	negate, name, operator, operand := match[1] == "!", match[2], match[3], match[4]
+
+	switch operator {
+	case "==":
+		return values[name] == unquote(operand)
+	case "!=":
+		return values[name] != unquote(operand)
+	default:
+		return (values[name] == "true") != negate
+	}
+}
+
+// unquote removes the quotes around a value of a condition, if any.
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+
+	return value
+}
+
+// removeMatchingFiles removes the entries of dir matched by ignore.
+func removeMatchingFiles(dir string, ignore gitignore.GitIgnore) error {
+	var toRemove []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+
+		match := ignore.Relative(filepath.ToSlash(rel), d.IsDir())
+		if match != nil && match.Ignore() {
+			toRemove = append(toRemove, path)
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, path := range toRemove {
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
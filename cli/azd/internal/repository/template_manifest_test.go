@@ -0,0 +1,215 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/templates"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Initializer_Initialize_TemplateManifest(t *testing.T) {
+	t.Parallel()
+
+	localTemplateDir := createLocalTemplateDir(t, testDataPath("template"))
+	writeFiles(t, localTemplateDir, map[string]string{
+		templateManifestFileName: heredoc.Doc(`
+			prompts:
+			  - name: appName
+			    message: Enter the name of the application
+			    default: my-app
+			  - name: includeWorker
+			    message: Include the worker service?
+			    type: boolean
+			  - name: database
+			    message: Select the database
+			    options: [postgres, cosmos]
+			files:
+			  replace: ["*.md", "src/"]
+			  include:
+			    - paths: [worker/]
+			      when: includeWorker
+			    - paths: [infra/postgres.bicep]
+			      when: database == postgres
+			    - paths: [infra/cosmos.bicep]
+			      when: database == cosmos
+		`),
+		"APP.md":                           "# {{ azd.appName }} on {{azd.database}} {{ azd.unknown }}\n",
+		"NOTES.txt":                        "{{ azd.appName }}\n",
+		"src/{{ azd.appName }}/config.txt": "name={{ azd.appName }}\n",
+		"worker/main.py":                   "print('worker')\n",
+		"infra/postgres.bicep":             "// postgres\n",
+		"infra/cosmos.bicep":               "// cosmos\n",
+	})
+
+	projectDir := t.TempDir()
+	azdCtx := azdcontext.NewAzdContextWithDirectory(projectDir)
+
+	console := mockinput.NewMockConsole()
+	console.WhenPrompt(func(options input.ConsoleOptions) bool {
+		return options.Key == "template.appName"
+	}).Respond("contoso")
+	console.WhenConfirm(func(options input.ConsoleOptions) bool {
+		return options.Key == "template.includeWorker"
+	}).Respond(false)
+	console.WhenSelect(func(options input.ConsoleOptions) bool {
+		return options.Key == "template.database"
+	}).Respond(1)
+
+	realRunner := exec.NewCommandRunner(nil)
+	mockEnv := &mockenv.MockEnvManager{}
+	mockEnv.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	i := NewInitializer(
+		console,
+		git.NewCli(realRunner),
+		dotnet.NewCli(realRunner),
+		alpha.NewFeaturesManagerWithConfig(config.NewEmptyConfig()),
+		lazy.From[environment.Manager](mockEnv),
+	)
+
+	err := i.Initialize(t.Context(), azdCtx, &templates.Template{
+		RepositoryPath: localTemplateDir,
+	}, "")
+	require.NoError(t, err)
+
+	require.NoFileExists(t, filepath.Join(projectDir, templateManifestFileName))
+
+	requireFileContents(t, filepath.Join(projectDir, "APP.md"), "# contoso on cosmos {{ azd.unknown }}\n")
+	requireFileContents(t, filepath.Join(projectDir, "src", "contoso", "config.txt"), "name=contoso\n")
+	// Not listed in files.replace
+	requireFileContents(t, filepath.Join(projectDir, "NOTES.txt"), "{{ azd.appName }}\n")
+
+	require.NoDirExists(t, filepath.Join(projectDir, "worker"))
+	require.NoFileExists(t, filepath.Join(projectDir, "infra", "postgres.bicep"))
+	require.FileExists(t, filepath.Join(projectDir, "infra", "cosmos.bicep"))
+}
+
+func Test_customizeTemplateFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"README.md":                     "{{ azd.name }}\n",
+		"bin/data.bin":                  "{{ azd.name }}\x00",
+		"{{ azd.name }}/{{ azd.name }}": "nested",
+		"tests/test.txt":                "test",
+	})
+
+	manifest := &templateManifest{
+		Files: templateFiles{
+			Include: []templateInclude{
+				{Paths: []string{"tests/"}, When: "!withTests"},
+			},
+		},
+	}
+
+	err := customizeTemplateFiles(dir, manifest, map[string]string{"name": "app", "withTests": "true"})
+	require.NoError(t, err)
+
+	requireFileContents(t, filepath.Join(dir, "README.md"), "app\n")
+	requireFileContents(t, filepath.Join(dir, "bin", "data.bin"), "{{ azd.name }}\x00")
+	requireFileContents(t, filepath.Join(dir, "app", "app"), "nested")
+	require.NoFileExists(t, filepath.Join(dir, "tests", "test.txt"))
+
+	invalidDir := t.TempDir()
+	writeFiles(t, invalidDir, map[string]string{"{{ azd.name }}.txt": ""})
+	err = customizeTemplateFiles(invalidDir, &templateManifest{}, map[string]string{"name": "a/b"})
+	require.ErrorContains(t, err, "isn't a valid file name")
+}
+
+func Test_loadTemplateManifest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NoManifest", func(t *testing.T) {
+		manifest, err := loadTemplateManifest(t.TempDir())
+		require.NoError(t, err)
+		require.Nil(t, manifest)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{
+			templateManifestFileName: heredoc.Doc(`
+				prompts:
+				  - name: name
+				    message: Enter a name
+				  - name: enabled
+				    message: Enable?
+				    type: boolean
+				    default: true
+				files:
+				  include:
+				    - paths: [a]
+				      when: "!enabled"
+				    - paths: [b]
+				      when: name != 'x'
+			`),
+		})
+
+		manifest, err := loadTemplateManifest(dir)
+		require.NoError(t, err)
+		require.Equal(t, templatePromptString, manifest.Prompts[0].Type)
+		require.Equal(t, true, manifest.Prompts[1].Default)
+		require.True(t, evaluateTemplateCondition(manifest.Files.Include[0].When, map[string]string{"enabled": "false"}))
+		require.False(t, evaluateTemplateCondition(manifest.Files.Include[1].When, map[string]string{"name": "x"}))
+	})
+
+	invalid := map[string]string{
+		"InvalidName": `prompts: [{name: "a-b", message: m}]`,
+		"Duplicate":   `prompts: [{name: a, message: m}, {name: a, message: m}]`,
+		"NoMessage":   `prompts: [{name: a}]`,
+		"UnknownType": `prompts: [{name: a, message: m, type: number}]`,
+		"BoolDefault": `prompts: [{name: a, message: m, type: boolean, default: yes please}]`,
+		"BadOption":   `prompts: [{name: a, message: m, options: [x, y], default: z}]`,
+		"UnknownRef":  `files: {include: [{paths: [a], when: b}]}`,
+		"StringBare": "prompts: [{name: a, message: m}]\n" +
+			"files: {include: [{paths: [a], when: a}]}",
+		"BoolCompare": "prompts: [{name: a, message: m, type: boolean}]\n" +
+			"files: {include: [{paths: [a], when: a == true}]}",
+		"NoPaths": "prompts: [{name: a, message: m, type: boolean}]\n" +
+			"files: {include: [{when: a}]}",
+	}
+
+	for name, contents := range invalid {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{templateManifestFileName: contents})
+
+			_, err := loadTemplateManifest(dir)
+			require.ErrorContains(t, err, "invalid "+templateManifestFileName)
+		})
+	}
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for path, contents := range files {
+		path = filepath.Join(dir, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+	}
+}
+
+func requireFileContents(t *testing.T, path string, expected string) {
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, expected, strings.ReplaceAll(string(contents), "\r\n", "\n"))
+}
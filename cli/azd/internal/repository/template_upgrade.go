@@ -0,0 +1,466 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/yamlnode"
+	"github.com/braydonk/yaml"
+	"github.com/fatih/color"
+	dmp "github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// TemplateUpgradeResult is the result of upgrading the template of a project.
+type TemplateUpgradeResult struct {
+	// Commit is the commit of the template the project is up to date with.
+	Commit string
+	// Updated are the files updated with the changes of the template, relative to the project.
+	Updated []string
+	// Conflicts are the updated files with merge conflicts to resolve, relative to the project.
+	Conflicts []string
+}
+
+// UpgradeTemplate applies the changes made to the template of the project, since the project was initialized or last
+// upgraded, to the infrastructure and the project file. Files that were also changed locally are merged with the
+// changes of the template, after confirmation.
+func (i *Initializer) UpgradeTemplate(
+	ctx context.Context,
+	azdCtx *azdcontext.AzdContext) (*TemplateUpgradeResult, error) {
+	prjConfig, err := project.Load(ctx, azdCtx.ProjectPath())
+	if err != nil {
+		return nil, err
+	}
+
+	if prjConfig.Metadata == nil || prjConfig.Metadata.TemplateSource == nil ||
+		prjConfig.Metadata.TemplateSource.Commit == "" {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: errors.New("the template version of the project is unknown"),
+			Suggestion: fmt.Sprintf("'azd init --template' records the template version of new projects in %s, "+
+				"under metadata.templateSource.", azdcontext.ProjectFileName),
+		}
+	}
+	source := prjConfig.Metadata.TemplateSource
+
+	infraOptions, err := prjConfig.Infra.GetWithDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	staging, err := os.MkdirTemp("", "az-dev-template")
+	if err != nil {
+		return nil, fmt.Errorf("creating temp folder: %w", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(staging)
+	}()
+
+	baseDir := filepath.Join(staging, "base")
+	upstreamDir := filepath.Join(staging, "upstream")
+
+	title := "Fetching template updates"
+	i.console.ShowSpinner(ctx, title, input.Step)
+	upstreamCommit, err := i.fetchTemplateVersions(ctx, source, baseDir, upstreamDir)
+	i.console.StopSpinner(ctx, title, input.GetStepResultFormat(err))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TemplateUpgradeResult{Commit: upstreamCommit}
+	if upstreamCommit == source.Commit {
+		return result, nil
+	}
+
+	upstreamSource := *source
+	upstreamSource.Commit = upstreamCommit
+
+	changes, err := templateChanges(
+		azdCtx.ProjectDirectory(), baseDir, upstreamDir, infraOptions.Path, source, &upstreamSource)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(changes) > 0 {
+		i.console.Message(ctx, fmt.Sprintf("\nThe template was updated from %s to %s:",
+			output.WithHighLightFormat(shortCommit(source.Commit)), output.WithHighLightFormat(shortCommit(upstreamCommit))))
+		for _, change := range changes {
+			i.console.Message(ctx, fmt.Sprintf("  %s %s", change.action(), change.Path))
+		}
+		i.console.Message(ctx, "")
+
+		apply, err := i.console.Confirm(ctx, input.ConsoleOptions{
+			Message:      "Apply the changes of the template?",
+			DefaultValue: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !apply {
+			return nil, errors.New("template upgrade cancelled")
+		}
+
+		for _, change := range changes {
+			updated, conflicts, err := i.applyTemplateChange(ctx, azdCtx.ProjectDirectory(), change)
+			if err != nil {
+				return nil, fmt.Errorf("updating %s: %w", change.Path, err)
+			}
+
+			if updated {
+				result.Updated = append(result.Updated, change.Path)
+			}
+			if conflicts {
+				result.Conflicts = append(result.Conflicts, change.Path)
+			}
+		}
+	}
+
+	// the project file can't be parsed until its conflicts are resolved, but its merged template version is then
+	// already the upstream one
+	if !slices.Contains(result.Conflicts, azdcontext.ProjectFileName) {
+		if err := recordTemplateSource(azdCtx.ProjectPath(), &upstreamSource); err != nil {
+			return nil, fmt.Errorf("recording template version: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// fetchTemplateVersions fetches the version of the template recorded in source to baseDir, and the latest version of
+// the template to upstreamDir. It returns the commit of the latest version.
+func (i *Initializer) fetchTemplateVersions(
+	ctx context.Context,
+	source *project.TemplateSource,
+	baseDir string,
+	upstreamDir string) (string, error) {
+	if err := i.gitCli.ShallowClone(ctx, source.Repository, source.Branch, upstreamDir); err != nil {
+		return "", fmt.Errorf("fetching template: %w", err)
+	}
+
+	upstreamCommit, err := i.gitCli.GetHeadCommit(ctx, upstreamDir)
+	if err != nil {
+		return "", err
+	}
+
+	if upstreamCommit == source.Commit {
+		return upstreamCommit, nil
+	}
+
+	if err := os.Mkdir(baseDir, osutil.PermissionDirectory); err != nil {
+		return "", err
+	}
+
+	if err := i.gitCli.ShallowCloneCommit(ctx, source.Repository, source.Commit, baseDir); err != nil {
+		return "", fmt.Errorf("fetching template version: %w", err)
+	}
+
+	for _, dir := range []string{baseDir, upstreamDir} {
+		if err := removeAzdIgnoredFiles(dir); err != nil {
+			return "", fmt.Errorf("applying %s rules: %w", azdIgnoreFileName, err)
+		}
+	}
+
+	return upstreamCommit, nil
+}
+
+// templateChange is a file changed between two versions of a template, that differs from the file of the project.
+type templateChange struct {
+	// Path is the path of the file, relative to the project.
+	Path string
+
+	// Base, Upstream and Local are the contents of the file in the version of the template of the project, in the
+	// latest version of the template, and in the project. They are nil when the file doesn't exist.
+	Base     []byte
+	Upstream []byte
+	Local    []byte
+}
+
+// conflicting reports whether the file was also changed locally.
+func (c *templateChange) conflicting() bool {
+	return !contentsEqual(c.Local, c.Base)
+}
+
+// action describes how the change is applied.
+func (c *templateChange) action() string {
+	switch {
+	case c.conflicting():
+		return output.WithWarningFormat("Merge ")
+	case c.Upstream == nil:
+		return color.RedString("Delete")
+	case c.Local == nil:
+		return color.GreenString("Add   ")
+	default:
+		return "Update"
+	}
+}
+
+// templateChanges returns the changes of the files of the infrastructure at infraPath and of the project file, between
+// the versions of the template in baseDir and upstreamDir. The project files of the template versions are compared as
+// if their template versions were the ones of the project, and the project file of upstreamDir records upstreamSource.
+func templateChanges(
+	projectDir string,
+	baseDir string,
+	upstreamDir string,
+	infraPath string,
+	source *project.TemplateSource,
+	upstreamSource *project.TemplateSource) ([]*templateChange, error) {
+	// compare the project files like the project file written by azd init, which records the version of the template
+	for _, dir := range []string{baseDir, upstreamDir} {
+		if err := recordTemplateSource(filepath.Join(dir, azdcontext.ProjectFileName), source); err != nil {
+			return nil, fmt.Errorf("reading project file of the template: %w", err)
+		}
+	}
+
+	paths := []string{azdcontext.ProjectFileName}
+	for _, dir := range []string{baseDir, upstreamDir} {
+		err := filepath.WalkDir(filepath.Join(dir, infraPath), func(path string, d fs.DirEntry, err error) error {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			if err != nil || d.IsDir() {
+				return err
+			}
+
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+
+			if !slices.Contains(paths, rel) {
+				paths = append(paths, rel)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("listing infrastructure files of the template: %w", err)
+		}
+	}
+	slices.Sort(paths)
+
+	var changes []*templateChange
+	for _, path := range paths {
+		change := &templateChange{Path: path}
+		for dir, contents := range map[string]*[]byte{
+			baseDir:     &change.Base,
+			upstreamDir: &change.Upstream,
+			projectDir:  &change.Local,
+		} {
+			data, err := readOptionalFile(filepath.Join(dir, path))
+			if err != nil {
+				return nil, err
+			}
+			*contents = data
+		}
+
+		if contentsEqual(change.Base, change.Upstream) || contentsEqual(change.Local, change.Upstream) {
+			continue
+		}
+
+		changes = append(changes, change)
+	}
+
+	// the changed project file of upstreamDir records its own version, which is merged along with the other changes
+	if idx := slices.IndexFunc(changes, func(c *templateChange) bool {
+		return c.Path == azdcontext.ProjectFileName && c.Upstream != nil
+	}); idx >= 0 {
+		upstreamProjectPath := filepath.Join(upstreamDir, azdcontext.ProjectFileName)
+		if err := recordTemplateSource(upstreamProjectPath, upstreamSource); err != nil {
+			return nil, fmt.Errorf("reading project file of the template: %w", err)
+		}
+
+		data, err := os.ReadFile(upstreamProjectPath)
+		if err != nil {
+			return nil, err
+		}
+		changes[idx].Upstream = data
+	}
+
+	return changes, nil
+}
+
+// templateChangeChoice is how the user chose to apply a change of the template to a file that was changed locally.
+type templateChangeChoice int
+
+const (
+	templateChangeMerge templateChangeChoice = iota
+	templateChangeUseTemplate
+	templateChangeKeepLocal
+	templateChangeShow
+)
+
+// applyTemplateChange applies the change of the template to the file of the project in projectDir. The user chooses
+// how changes to files that were also changed locally are applied. It returns whether the file was updated, and
+// whether it has merge conflicts to resolve.
+func (i *Initializer) applyTemplateChange(
+	ctx context.Context,
+	projectDir string,
+	change *templateChange) (updated bool, conflicts bool, err error) {
+	path := filepath.Join(projectDir, change.Path)
+
+	choice := templateChangeUseTemplate
+	if change.conflicting() {
+		choices := []templateChangeChoice{}
+		options := []string{}
+		if change.Base != nil && change.Upstream != nil && change.Local != nil {
+			choices = append(choices, templateChangeMerge)
+			options = append(options, "Merge the changes of the template with my changes")
+		}
+		choices = append(choices, templateChangeUseTemplate, templateChangeKeepLocal, templateChangeShow)
+		options = append(options, "Use the template version", "Keep my version", "Show the changes of the template")
+
+		for {
+			selected, err := i.console.Select(ctx, input.ConsoleOptions{
+				Message:      fmt.Sprintf("%s was also changed locally. How should it be updated?", change.Path),
+				Options:      options,
+				DefaultValue: options[0],
+			})
+			if err != nil {
+				return false, false, err
+			}
+
+			choice = choices[selected]
+			if choice != templateChangeShow {
+				break
+			}
+
+			i.console.Message(ctx, textDiff(string(change.Base), string(change.Upstream)))
+		}
+	}
+
+	switch choice {
+	case templateChangeKeepLocal:
+		return false, false, nil
+	case templateChangeMerge:
+		merged, conflicts, err := i.mergeTemplateChange(ctx, change)
+		if err != nil {
+			return false, false, err
+		}
+
+		return true, conflicts, os.WriteFile(path, merged, osutil.PermissionFile)
+	}
+
+	if change.Upstream == nil {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return false, false, err
+		}
+
+		return true, false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory); err != nil {
+		return false, false, err
+	}
+
+	return true, false, os.WriteFile(path, change.Upstream, osutil.PermissionFile)
+}
+
+// mergeTemplateChange merges the change of the template with the local changes of the file. It returns the merged
+// contents, and whether they have conflicts.
+func (i *Initializer) mergeTemplateChange(ctx context.Context, change *templateChange) ([]byte, bool, error) {
+	dir, err := os.MkdirTemp("", "az-dev-merge")
+	if err != nil {
+		return nil, false, fmt.Errorf("creating temp folder: %w", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	files := [3]string{filepath.Join(dir, "local"), filepath.Join(dir, "base"), filepath.Join(dir, "upstream")}
+	for idx, contents := range [][]byte{change.Local, change.Base, change.Upstream} {
+		if err := os.WriteFile(files[idx], contents, osutil.PermissionFile); err != nil {
+			return nil, false, err
+		}
+	}
+
+	merged, conflicts, err := i.gitCli.MergeFile(ctx, files[0], files[1], files[2],
+		[3]string{change.Path, "template (current)", "template (latest)"})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return []byte(merged), conflicts, nil
+}
+
+// recordTemplateSource records the version of the template in the project file at path, preserving its formatting and
+// comments. Nothing is recorded when the project file doesn't exist.
+func recordTemplateSource(path string, source *project.TemplateSource) error {
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		log.Printf("not recording template version: %s doesn't exist", path)
+		return nil
+	}
+
+	return editProjectFile(path, func(doc *yaml.Node) error {
+		node, err := yamlnode.Encode(source)
+		if err != nil {
+			return fmt.Errorf("encoding template source: %w", err)
+		}
+
+		return yamlnode.Set(doc, "metadata?.templateSource", node)
+	})
+}
+
+// readOptionalFile returns the contents of the file at path, or nil when it doesn't exist.
+func readOptionalFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if data == nil {
+		data = []byte{}
+	}
+	return data, nil
+}
+
+// contentsEqual reports whether a and b are the contents of the same file, nil meaning the file doesn't exist.
+func contentsEqual(a []byte, b []byte) bool {
+	return (a == nil) == (b == nil) && bytes.Equal(a, b)
+}
+
+// textDiff returns the changed lines between old and new, with the removed lines in red and the added lines in green.
+func textDiff(old string, new string) string {
+	diffObj := dmp.New()
+	oldChars, newChars, lines := diffObj.DiffLinesToChars(old, new)
+	diffs := diffObj.DiffCharsToLines(diffObj.DiffMain(oldChars, newChars, false), lines)
+
+	var sb strings.Builder
+	for _, diff := range diffs {
+		for line := range strings.SplitSeq(strings.TrimSuffix(diff.Text, "\n"), "\n") {
+			switch diff.Type {
+			case dmp.DiffInsert:
+				sb.WriteString(color.GreenString("+ %s\n", line))
+			case dmp.DiffDelete:
+				sb.WriteString(color.RedString("- %s\n", line))
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// shortCommit returns the abbreviated form of commit.
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+
+	return commit
+}
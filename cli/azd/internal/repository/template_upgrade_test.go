@@ -0,0 +1,122 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitializer_UpgradeTemplate(t *testing.T) {
+	runner := exec.NewCommandRunner(nil)
+	gitCli := git.NewCli(runner)
+
+	templateDir := t.TempDir()
+	writeFiles := func(dir string, files map[string]string) {
+		for path, contents := range files {
+			require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(path)), osutil.PermissionDirectory))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, path), []byte(contents), osutil.PermissionFile))
+		}
+	}
+	commit := func() string {
+		for _, args := range [][]string{
+			{"add", "-A"},
+			{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-m", "update"},
+		} {
+			_, err := runner.Run(t.Context(), exec.NewRunArgs("git", append([]string{"-C", templateDir}, args...)...))
+			require.NoError(t, err)
+		}
+
+		commit, err := gitCli.GetHeadCommit(t.Context(), templateDir)
+		require.NoError(t, err)
+		return commit
+	}
+
+	require.NoError(t, gitCli.InitRepo(t.Context(), templateDir))
+	writeFiles(templateDir, map[string]string{
+		"azure.yaml":       "# template comment\nname: app\n",
+		"infra/main.bicep": "param a string\n",
+		"infra/old.bicep":  "old\n",
+		"src/app.py":       "print('v1')\n",
+	})
+	baseCommit := commit()
+
+	projectDir := t.TempDir()
+	writeFiles(projectDir, map[string]string{
+		"azure.yaml":       "# template comment\nname: app\n",
+		"infra/main.bicep": "param a string\n// local\n",
+		"infra/old.bicep":  "old\n",
+	})
+	azdCtx := azdcontext.NewAzdContextWithDirectory(projectDir)
+	require.NoError(t, recordTemplateSource(azdCtx.ProjectPath(), &project.TemplateSource{
+		Repository: templateDir,
+		Commit:     baseCommit,
+	}))
+
+	console := mockinput.NewMockConsole()
+	console.WhenConfirm(func(options input.ConsoleOptions) bool { return true }).Respond(true)
+	console.WhenSelect(func(options input.ConsoleOptions) bool { return true }).Respond(0)
+	i := &Initializer{console: console, gitCli: gitCli}
+
+	// up to date
+	result, err := i.UpgradeTemplate(t.Context(), azdCtx)
+	require.NoError(t, err)
+	require.Equal(t, baseCommit, result.Commit)
+	require.Empty(t, result.Updated)
+
+	require.NoError(t, os.Remove(filepath.Join(templateDir, "infra", "old.bicep")))
+	writeFiles(templateDir, map[string]string{
+		"infra/main.bicep": "// template\nparam a string\n",
+		"infra/new.bicep":  "new\n",
+		"src/app.py":       "print('v2')\n",
+	})
+	upstreamCommit := commit()
+
+	result, err = i.UpgradeTemplate(t.Context(), azdCtx)
+	require.NoError(t, err)
+	require.Equal(t, &TemplateUpgradeResult{
+		Commit: upstreamCommit,
+		Updated: []string{
+			filepath.Join("infra", "main.bicep"),
+			filepath.Join("infra", "new.bicep"),
+			filepath.Join("infra", "old.bicep"),
+		},
+	}, result)
+
+	contents, err := os.ReadFile(filepath.Join(projectDir, "infra", "main.bicep"))
+	require.NoError(t, err)
+	require.Equal(t, "// template\nparam a string\n// local\n", string(contents))
+	require.NoFileExists(t, filepath.Join(projectDir, "infra", "old.bicep"))
+	require.FileExists(t, filepath.Join(projectDir, "infra", "new.bicep"))
+	// only the infrastructure and the project file are upgraded
+	require.NoFileExists(t, filepath.Join(projectDir, "src", "app.py"))
+
+	contents, err = os.ReadFile(azdCtx.ProjectPath())
+	require.NoError(t, err)
+	require.Contains(t, string(contents), "# template comment")
+
+	prjConfig, err := project.Load(t.Context(), azdCtx.ProjectPath())
+	require.NoError(t, err)
+	require.Equal(t, upstreamCommit, prjConfig.Metadata.TemplateSource.Commit)
+}
+
+func TestInitializer_UpgradeTemplate_UnknownVersion(t *testing.T) {
+	projectDir := t.TempDir()
+	azdCtx := azdcontext.NewAzdContextWithDirectory(projectDir)
+	require.NoError(t, os.WriteFile(azdCtx.ProjectPath(), []byte("name: app\n"), osutil.PermissionFile))
+
+	i := &Initializer{console: mockinput.NewMockConsole()}
+	_, err := i.UpgradeTemplate(t.Context(), azdCtx)
+	require.ErrorContains(t, err, "the template version of the project is unknown")
+}
@@ -29,6 +29,7 @@ var reservedFlags = []ReservedFlag{
 	{Long: "environment", Short: "e", Description: "The name of the environment to use."},
 	{Long: "cwd", Short: "C", Description: "Sets the current working directory."},
 	{Long: "debug", Short: "", Description: "Enables debugging and diagnostics logging."},
+	{Long: "quiet", Short: "", Description: "Only writes the result of the command and errors."},
 	{
 		Long:  "no-prompt",
 		Short: "",
@@ -37,6 +38,18 @@ var reservedFlags = []ReservedFlag{
 			"Automatically enabled when azd detects a CI/CD or AI-agent environment; " +
 			"set AZD_NON_INTERACTIVE=false to opt out of that automatic enablement.",
 	},
+	{
+		Long:  "answers",
+		Short: "",
+		Description: "Answers prompts from a YAML file mapping prompt keys, like subscription, location or " +
+			"parameters.<name>, to values. Defaults to the AZD_ANSWERS_FILE environment variable.",
+	},
+	{
+		Long:  "prompt-timeout",
+		Short: "",
+		Description: "Waits at most the given number of seconds for the answer to a prompt, then uses its default " +
+			"value, or fails when it has none. Defaults to the AZD_PROMPT_TIMEOUT environment variable.",
+	},
 	{Long: "output", Short: "o", Description: "The output format (json, table, none)."},
 	{Long: "help", Short: "h", Description: "Help for the current command."},
 	{Long: "docs", Short: "", Description: "Opens the documentation for the current command."},
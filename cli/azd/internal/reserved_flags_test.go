@@ -47,6 +47,9 @@ func TestIsReservedLongFlag(t *testing.T) {
 		{"cwd", true},
 		{"debug", true},
 		{"no-prompt", true},
+		{"answers", true},
+		{"prompt-timeout", true},
+		{"quiet", true},
 		{"output", true},
 		{"help", true},
 		{"docs", true},
@@ -77,6 +77,31 @@ var Resources = []ResourceMeta{
 		ResourceType: "Microsoft.App/managedEnvironments",
 		ApiVersion:   "2023-05-01",
 	},
+	{
+		ResourceType:      "Microsoft.AppConfiguration/configurationStores",
+		ApiVersion:        "2024-05-01",
+		StandardVarPrefix: "AZURE_APPCONFIGURATION",
+		Variables: map[string]string{
+			"name":     "${.name}",
+			"endpoint": "${.properties.endpoint}",
+		},
+		RoleAssignments: RoleAssignments{
+			Read: []RoleAssignment{
+				{
+					Name:               "DataReader",
+					RoleDefinitionName: "App Configuration Data Reader",
+					RoleDefinitionId:   "516239f1-63e1-4d78-a4de-a74fb236a071",
+				},
+			},
+			Write: []RoleAssignment{
+				{
+					Name:               "DataOwner",
+					RoleDefinitionName: "App Configuration Data Owner",
+					RoleDefinitionId:   "5ae67dd6-50cb-40e7-96ff-dc2bfa4b606b",
+				},
+			},
+		},
+	},
 	{
 		ResourceType:      "Microsoft.Cache/redis",
 		ApiVersion:        "2024-03-01",
@@ -164,6 +189,7 @@ var Resources = []ResourceMeta{
 	},
 	{
 		ResourceType:      "Microsoft.DocumentDB/databaseAccounts/sqlDatabases",
+		ResourceKind:      "GlobalDocumentDB",
 		ApiVersion:        "2023-04-15",
 		ParentForEval:     "Microsoft.DocumentDB/databaseAccounts",
 		StandardVarPrefix: "AZURE_COSMOS",
@@ -153,6 +153,7 @@ func TestExecInfra(t *testing.T) {
 				EventHubs:      &EventHubs{},
 				StorageAccount: &StorageAccount{},
 				KeyVault:       &KeyVault{},
+				FeatureFlags:   &FeatureFlags{},
 				AISearch:       &AISearch{},
 				Services: []ServiceSpec{
 					{
@@ -184,6 +185,7 @@ func TestExecInfra(t *testing.T) {
 						EventHubs:        &EventHubs{},
 						StorageAccount:   &StorageReference{},
 						KeyVault:         &KeyVaultReference{},
+						FeatureFlags:     &FeatureFlagsReference{},
 						AISearch:         &AISearchReference{},
 						AiFoundryProject: &AiFoundrySpec{},
 						Host:             "containerapp",
@@ -231,6 +233,73 @@ func TestExecInfra(t *testing.T) {
 				},
 			},
 		},
+		{
+			"API and app with private Redis and Postgres",
+			InfraSpec{
+				DbPostgres: &DatabasePostgres{
+					DatabaseName: "appdb",
+					Private:      true,
+				},
+				DbRedis: &DatabaseRedis{
+					Private: true,
+				},
+				VirtualNetwork: &VirtualNetwork{},
+				KeyVault:       &KeyVault{},
+				Services: []ServiceSpec{
+					{
+						Name: "api",
+						Port: 3100,
+						DbPostgres: &DatabaseReference{
+							DatabaseName: "appdb",
+						},
+						DbRedis: &DatabaseReference{
+							DatabaseName: "redis",
+						},
+						Host: "containerapp",
+					},
+					{
+						Name: "app",
+						Port: 3000,
+						Host: "appservice",
+						Runtime: &RuntimeInfo{
+							Type:    "python",
+							Version: "3.11",
+						},
+						DbRedis: &DatabaseReference{
+							DatabaseName: "redis",
+						},
+					},
+				},
+			},
+		},
+		{
+			"API with OpenAI model",
+			InfraSpec{
+				AIModels: []AIModel{
+					{
+						Name: "chat",
+						Model: AIModelModel{
+							Name:    "gpt-4o",
+							Version: "2024-08-06",
+						},
+						Sku: AIModelSku{
+							Name:     "Standard",
+							Capacity: 50,
+						},
+					},
+				},
+				Services: []ServiceSpec{
+					{
+						Name: "api",
+						Port: 3100,
+						AIModels: []AIModelReference{
+							{Name: "chat"},
+						},
+						Host: "containerapp",
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -25,9 +25,15 @@ type InfraSpec struct {
 	DbCosmos      *DatabaseCosmos
 	DbRedis       *DatabaseRedis
 
+	// Virtual network of the databases with private networking
+	VirtualNetwork *VirtualNetwork
+
 	// Key vault
 	KeyVault *KeyVault
 
+	// Feature flags stored in App Configuration
+	FeatureFlags *FeatureFlags
+
 	// Messaging services
 	ServiceBus *ServiceBus
 	EventHubs  *EventHubs
@@ -53,6 +59,8 @@ type Parameter struct {
 
 type DatabasePostgres struct {
 	DatabaseName string
+	// Private makes the server only reachable through a private endpoint in the virtual network.
+	Private bool
 }
 
 type DatabaseMysql struct {
@@ -74,12 +82,20 @@ type CosmosSqlDatabaseContainer struct {
 }
 
 type DatabaseRedis struct {
+	// Private makes the cache only reachable through a private endpoint in the virtual network.
+	Private bool
+}
+
+// VirtualNetwork is the virtual network that the host services are joined to, with the private endpoints of the
+// databases with private networking.
+type VirtualNetwork struct {
 }
 
 // AIModel represents a deployed, ready to use AI model.
 type AIModel struct {
 	Name  string
 	Model AIModelModel
+	Sku   AIModelSku
 }
 
 // AIModel represents a deployed, ready to use AI model.
@@ -100,6 +116,14 @@ type AiFoundryModelSku struct {
 	Capacity  int32  `yaml:"capacity,omitempty"`
 }
 
+// AIModelSku represents the SKU of the deployment of an AIModel.
+type AIModelSku struct {
+	// The name of the SKU, such as Standard.
+	Name string
+	// The capacity of the deployment, in thousands of tokens per minute.
+	Capacity int32
+}
+
 // AIModelModel represents a model that backs the AIModel.
 type AIModelModel struct {
 	// The name of the underlying model.
@@ -123,6 +147,9 @@ type EventHubs struct {
 type KeyVault struct {
 }
 
+type FeatureFlags struct {
+}
+
 type StorageAccount struct {
 	Containers []string
 }
@@ -147,6 +174,9 @@ type ServiceSpec struct {
 	// Key vault
 	KeyVault *KeyVaultReference
 
+	// Feature flags stored in App Configuration
+	FeatureFlags *FeatureFlagsReference
+
 	// Connection to a database
 	DbPostgres    *DatabaseReference
 	DbMySql       *DatabaseReference
@@ -212,6 +242,9 @@ type AISearchReference struct {
 type KeyVaultReference struct {
 }
 
+type FeatureFlagsReference struct {
+}
+
 type ExistingResource struct {
 	// The unique logical name of the existing resource in the infra scope.
 	Name string
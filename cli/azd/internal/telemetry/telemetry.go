@@ -19,6 +19,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/resource"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/redact"
 	"github.com/benbjohnson/clock"
 	"github.com/gofrs/flock"
 	"github.com/spf13/pflag"
@@ -151,7 +152,7 @@ func initialize() (*TelemetrySystem, error) {
 			return nil, fmt.Errorf("failed to create log file %s: %w", logFile, err)
 		}
 
-		stdoutExporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		stdoutExporter, err := stdouttrace.New(stdouttrace.WithWriter(redact.NewWriter(file)))
 		if err != nil {
 			return nil, fmt.Errorf("failed to create log file exporter: %w", err)
 		}
@@ -81,6 +81,7 @@ func (s *debugService) FetchTokenAsync(ctx context.Context, sessionId Session) (
 
 	var c struct {
 		credProvider auth.MultiTenantCredentialProvider `container:"type"`
+		cloud        *cloud.Cloud                       `container:"type"`
 	}
 
 	container, err := session.newContainer(RequestContext{
@@ -100,7 +101,7 @@ func (s *debugService) FetchTokenAsync(ctx context.Context, sessionId Session) (
 	}
 
 	return cred.GetToken(ctx, policy.TokenRequestOptions{
-		Scopes: auth.LoginScopes(cloud.AzurePublic()),
+		Scopes: auth.LoginScopes(c.cloud),
 	})
 }
 
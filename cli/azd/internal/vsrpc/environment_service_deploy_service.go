@@ -9,7 +9,9 @@ import (
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/internal/cmd"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/pkg/progress"
 )
 
 // DeployServiceAsync is the server implementation of:
@@ -56,6 +58,11 @@ func (s *environmentService) DeployServiceAsync(
 	container.outWriter.AddWriter(outputWriter)
 	container.spinnerWriter.AddWriter(spinnerWriter)
 
+	// Send structured progress events to the observer, next to the console output above.
+	container.MustRegisterScoped(func(console input.Console) progress.Reporter {
+		return progress.Multi(progress.NewConsoleRenderer(console), &observerReporter{observer: observer})
+	})
+
 	provisionFlags := cmd.NewProvisionFlagsFromEnvAndOptions(
 		&internal.EnvFlag{
 			EnvironmentName: name,
@@ -104,7 +104,9 @@ func (s *environmentService) loadEnvironmentAsync(
 	}
 
 	if v := e.Getenv("AZURE_CONTAINER_REGISTRY_ENDPOINT"); v != "" {
-		ret.Properties["ContainerRegistry"] = strings.TrimSuffix(v, ".azurecr.io")
+		// The registry name is the first label of the login server, whatever the cloud's registry suffix is.
+		registryName, _, _ := strings.Cut(v, ".")
+		ret.Properties["ContainerRegistry"] = registryName
 	}
 
 	if v := e.Getenv("AZURE_LOG_ANALYTICS_WORKSPACE_NAME"); v != "" {
@@ -57,6 +57,26 @@ type ProgressMessage struct {
 	Kind               MessageKind
 	Code               string
 	AdditionalInfoLink string
+	// When non nil, Progress is the structured progress event the message was rendered from.
+	Progress *ProgressEvent `json:",omitempty"`
+}
+
+// ProgressEvent is the structured form of a progress update reported by a command phase (e.g. provision or deploy).
+type ProgressEvent struct {
+	// The kind of event: stepStarted, stepUpdated, warning or stepCompleted.
+	Kind string
+	// The command phase, e.g. provision or deploy.
+	Phase string
+	// Identifies the step, stable for the lifetime of the step.
+	Step string
+	// The identifier of the parent step, for sub-steps.
+	Parent string `json:",omitempty"`
+	// The human readable name of the step.
+	Title string
+	// The status of the step: running, succeeded, failed or skipped.
+	Status string
+	// When non nil, the completion percentage of the step.
+	Percent *int `json:",omitempty"`
 }
 
 type InitializeServerOptions struct {
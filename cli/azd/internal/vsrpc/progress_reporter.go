@@ -0,0 +1,53 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package vsrpc
+
+import (
+	"context"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/progress"
+)
+
+// observerReporter is a progress.Reporter that sends each progress event to an IObserver[ProgressMessage].
+type observerReporter struct {
+	observer *Observer[ProgressMessage]
+}
+
+// Report implements progress.Reporter.
+func (r *observerReporter) Report(ctx context.Context, event progress.Event) {
+	_ = r.observer.OnNext(ctx, newProgressMessageForEvent(event))
+}
+
+// newProgressMessageForEvent converts a progress event to a ProgressMessage. The message holds the plain text
+// rendering of the event, for clients that don't use the structured form.
+func newProgressMessageForEvent(event progress.Event) ProgressMessage {
+	msg := ProgressMessage{
+		Message:  event.ToString(""),
+		Severity: Info,
+		Time:     event.Timestamp,
+		Kind:     Logging,
+		Progress: &ProgressEvent{
+			Kind:    string(event.Kind),
+			Phase:   string(event.Phase),
+			Step:    event.Step,
+			Parent:  event.Parent,
+			Title:   event.Title,
+			Status:  string(event.Status),
+			Percent: event.Percent,
+		},
+	}
+
+	switch event.Kind {
+	case progress.KindStepStarted, progress.KindStepCompleted:
+		msg.Kind = Important
+	case progress.KindWarning:
+		msg.Severity = Warning
+	}
+
+	if event.Status == progress.StatusFailed {
+		msg.Severity = Error
+	}
+
+	return msg
+}
@@ -0,0 +1,57 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package vsrpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/progress"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProgressMessageForEvent(t *testing.T) {
+	now := time.Now()
+	percent := 30
+
+	tests := []struct {
+		name         string
+		event        progress.Event
+		wantKind     MessageKind
+		wantSeverity MessageSeverity
+	}{
+		{"Started", progress.Event{Kind: progress.KindStepStarted, Status: progress.StatusRunning}, Important, Info},
+		{
+			"Updated",
+			progress.Event{Kind: progress.KindStepUpdated, Status: progress.StatusRunning, Percent: &percent},
+			Logging,
+			Info,
+		},
+		{"Warning", progress.Event{Kind: progress.KindWarning, Status: progress.StatusRunning}, Logging, Warning},
+		{"Failed", progress.Event{Kind: progress.KindStepCompleted, Status: progress.StatusFailed}, Important, Error},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.event.Phase = progress.PhaseDeploy
+			tt.event.Step = "deploy/api"
+			tt.event.Title = "Deploying service api"
+			tt.event.Timestamp = now
+
+			msg := newProgressMessageForEvent(tt.event)
+			require.Equal(t, tt.wantKind, msg.Kind)
+			require.Equal(t, tt.wantSeverity, msg.Severity)
+			require.Equal(t, now, msg.Time)
+			require.Equal(t, tt.event.ToString(""), msg.Message)
+			require.Equal(t, &ProgressEvent{
+				Kind:    string(tt.event.Kind),
+				Phase:   "deploy",
+				Step:    "deploy/api",
+				Title:   "Deploying service api",
+				Status:  string(tt.event.Status),
+				Percent: tt.event.Percent,
+			}, msg.Progress)
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/progress"
 	"github.com/mattn/go-colorable"
 	"go.lsp.dev/jsonrpc2"
 )
@@ -149,6 +150,10 @@ func (s *serverSession) newContainer(rc RequestContext) (*container, error) {
 			})
 	})
 
+	// Progress is rendered with the console spinner, which is forwarded to the spinner writer. Requests that stream
+	// structured progress to their observer register their own reporter.
+	c.MustRegisterScoped(progress.NewConsoleRenderer)
+
 	c.MustRegisterScoped(func(console input.Console) io.Writer {
 		return colorable.NewNonColorable(console.Handles().Stdout)
 	})
@@ -70,10 +70,22 @@ public class ProgressMessage
     public MessageKind Kind;
     public string Code;
     public string AdditionalInfoLink;
+    public ProgressEvent? Progress;
 
     public override string ToString() => $"{Time}: {Severity} {Message}";
 }
 
+public class ProgressEvent
+{
+    public string? Kind { get; set; }
+    public string? Phase { get; set; }
+    public string? Step { get; set; }
+    public string? Parent { get; set; }
+    public string? Title { get; set; }
+    public string? Status { get; set; }
+    public int? Percent { get; set; }
+}
+
 public enum MessageSeverity
 {
     Info = 0,
@@ -28,6 +28,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/oneauth"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/redact"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/update"
 	"github.com/mattn/go-colorable"
@@ -237,7 +238,9 @@ func setupLogging(debugEnabled bool) func() {
 		logOutput = os.Stderr
 	}
 
-	log.SetOutput(logOutput)
+	// Secure values (for example secure infrastructure parameters) are registered with the redact package as they are
+	// collected, so they never reach the debug log or --debug output.
+	log.SetOutput(redact.NewWriter(logOutput))
 	return cleanupFunc
 }
 
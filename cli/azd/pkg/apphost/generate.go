@@ -1166,6 +1166,34 @@ func (b *infraGenerator) addDapr(name string, metadata *DaprResourceMetadata) er
 	return nil
 }
 
+// daprSidecar returns the Dapr sidecar configuration of the container app resourceName, or nil when no dapr.v0 resource
+// targets it. The app port defaults to the target port of the ingress.
+func (b *infraGenerator) daprSidecar(
+	resourceName string, ingress *genContainerAppIngress) *genContainerAppManifestTemplateContextDapr {
+	for _, dapr := range b.dapr {
+		if dapr.Application != resourceName {
+			continue
+		}
+
+		appPort := dapr.AppPort
+		if appPort == nil && ingress != nil {
+			appPort = &ingress.TargetPort
+		}
+
+		return &genContainerAppManifestTemplateContextDapr{
+			AppId:              dapr.AppId,
+			AppPort:            appPort,
+			AppProtocol:        dapr.AppProtocol,
+			EnableApiLogging:   dapr.EnableApiLogging,
+			HttpMaxRequestSize: dapr.DaprHttpMaxRequestSize,
+			HttpReadBufferSize: dapr.DaprHttpReadBufferSize,
+			LogLevel:           dapr.LogLevel,
+		}
+	}
+
+	return nil
+}
+
 func (b *infraGenerator) addDaprComponent(name string, metadata *DaprComponentResourceMetadata) error {
 	if metadata == nil || metadata.Type == nil {
 		return fmt.Errorf("dapr component resource '%s' did not include required metadata", name)
@@ -1315,6 +1343,15 @@ func (b *infraGenerator) Compile() error {
 		return err
 	}
 
+	for name, dapr := range b.dapr {
+		_, isProject := b.projects[dapr.Application]
+		_, isContainer := b.buildContainers[dapr.Application]
+		if !isProject && !isContainer {
+			return fmt.Errorf(
+				"dapr resource '%s' targets '%s', which is not a project or container resource", name, dapr.Application)
+		}
+	}
+
 	for resourceName, bc := range b.buildContainers {
 		var bMounts []*BindMount
 		if len(bc.BindMounts) > 0 {
@@ -1350,7 +1387,15 @@ func (b *infraGenerator) Compile() error {
 			Volumes:         bc.Volumes,
 			DeploySource:    bc.DeploymentSource,
 			BindMounts:      bMounts,
-			Entrypoint:      bc.Entrypoint,
+			Dapr:            b.daprSidecar(resourceName, b.allServicesIngress[resourceName].ingress),
+		}
+
+		if bc.Entrypoint != "" {
+			entrypoint, err := asYamlString(bc.Entrypoint)
+			if err != nil {
+				return fmt.Errorf("marshalling entrypoint for resource %s: %w", resourceName, err)
+			}
+			projectTemplateCtx.Entrypoint = entrypoint
 		}
 
 		if err := b.buildEnvBlock(bc.Env, &projectTemplateCtx); err != nil {
@@ -1379,27 +1424,7 @@ func (b *infraGenerator) Compile() error {
 			DeploySource:    project.DeploymentSource,
 		}
 
-		for _, dapr := range b.dapr {
-			if dapr.Application == resourceName {
-				appPort := dapr.AppPort
-
-				if appPort == nil && projectTemplateCtx.Ingress != nil {
-					appPort = &projectTemplateCtx.Ingress.TargetPort
-				}
-
-				projectTemplateCtx.Dapr = &genContainerAppManifestTemplateContextDapr{
-					AppId:              dapr.AppId,
-					AppPort:            appPort,
-					AppProtocol:        dapr.AppProtocol,
-					EnableApiLogging:   dapr.EnableApiLogging,
-					HttpMaxRequestSize: dapr.DaprHttpMaxRequestSize,
-					HttpReadBufferSize: dapr.DaprHttpReadBufferSize,
-					LogLevel:           dapr.LogLevel,
-				}
-
-				break
-			}
-		}
+		projectTemplateCtx.Dapr = b.daprSidecar(resourceName, projectTemplateCtx.Ingress)
 
 		if err := b.buildEnvBlock(project.Env, &projectTemplateCtx); err != nil {
 			return err
@@ -341,7 +341,7 @@ func TestAspireContainerArgs(t *testing.T) {
 	m, err := ManifestFromAppHost(ctx, filepath.Join("testdata", "AspireDocker.AppHost.csproj"), mockCli, "")
 	require.NoError(t, err)
 
-	for _, name := range []string{"container0", "container1"} {
+	for _, name := range []string{"container0", "container1", "container2"} {
 		t.Run(name, func(t *testing.T) {
 			tmpl, mType, err := ContainerAppManifestTemplateForProject(m, name, AppHostOptions{})
 			require.Equal(t, ContainerAppManifestTypeYAML, mType)
@@ -914,6 +914,54 @@ func TestInfraGenerator_DaprFullFlow(t *testing.T) {
 	require.NotNil(t, g.containerAppTemplateContexts["frontend"].Dapr)
 }
 
+func TestInfraGenerator_DaprContainer(t *testing.T) {
+	app := "worker"
+	appID := "workerapp"
+	storeType := DaprStateStoreComponentType
+
+	g := newInfraGenerator()
+	m := &Manifest{Resources: map[string]*Resource{
+		"worker": {Type: "container.v1", Image: new("worker:latest")},
+		"wsidecar": {
+			Type: "dapr.v0",
+			Dapr: &DaprResourceMetadata{
+				Application: &app,
+				AppId:       &appID,
+			},
+		},
+		"statestore": {
+			Type:          "dapr.component.v0",
+			DaprComponent: &DaprComponentResourceMetadata{Type: &storeType},
+		},
+	}}
+	require.NoError(t, g.LoadManifest(m))
+	require.NoError(t, g.Compile())
+	require.Contains(t, g.bicepContext.DaprComponents, "statestore")
+	require.Equal(t, "state.redis", g.bicepContext.DaprComponents["statestore"].Type)
+
+	dapr := g.containerAppTemplateContexts["worker"].Dapr
+	require.NotNil(t, dapr)
+	require.Equal(t, appID, dapr.AppId)
+}
+
+func TestInfraGenerator_DaprUnknownApplication(t *testing.T) {
+	app := "missing"
+	appID := "missingapp"
+
+	g := newInfraGenerator()
+	m := &Manifest{Resources: map[string]*Resource{
+		"dsidecar": {
+			Type: "dapr.v0",
+			Dapr: &DaprResourceMetadata{
+				Application: &app,
+				AppId:       &appID,
+			},
+		},
+	}}
+	require.NoError(t, g.LoadManifest(m))
+	require.ErrorContains(t, g.Compile(), "not a project or container resource")
+}
+
 func TestInfraGenerator_BicepV0_WithPath(t *testing.T) {
 	g := newInfraGenerator()
 	m := &Manifest{Resources: map[string]*Resource{
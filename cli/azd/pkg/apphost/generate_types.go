@@ -29,7 +29,6 @@ type genContainerAppIngressPort struct {
 
 type genContainerAppIngressAdditionalPortMappings struct {
 	genContainerAppIngressPort
-	ExposedPort int
 }
 
 type genContainerAppIngress struct {
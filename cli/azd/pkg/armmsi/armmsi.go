@@ -256,3 +256,41 @@ func (s *ArmMsiService) ApplyFederatedCredentials(ctx context.Context,
 
 	return result, nil
 }
+
+// ListFederatedCredentials lists the federated identity credentials of the user-assigned managed identity with the
+// specified resource ID.
+func (s *ArmMsiService) ListFederatedCredentials(
+	ctx context.Context,
+	msiResourceId string) ([]armmsi.FederatedIdentityCredential, error) {
+	msiData, err := arm.ParseResourceID(msiResourceId)
+	if err != nil {
+		return nil, fmt.Errorf("parsing MSI resource id: %w", err)
+	}
+
+	credential, err := s.credentialProvider.CredentialForSubscription(ctx, msiData.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := armmsi.NewFederatedIdentityCredentialsClient(msiData.SubscriptionID, credential, s.armClientOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []armmsi.FederatedIdentityCredential{}
+	pager := client.NewListPager(msiData.ResourceGroupName, msiData.Name, nil)
+	for pager.More() {
+		resp, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing federated identity credentials: %w", err)
+		}
+
+		for _, cred := range resp.Value {
+			if cred != nil {
+				result = append(result, *cred)
+			}
+		}
+	}
+
+	return result, nil
+}
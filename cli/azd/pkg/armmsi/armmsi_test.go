@@ -335,3 +335,52 @@ func TestApplyFederatedCredentials_SkipsExistingSubjects(t *testing.T) {
 	require.Len(t, result, 1)
 	require.Equal(t, "new-subject", *result[0].Properties.Subject)
 }
+
+func TestListFederatedCredentials_InvalidResourceId(t *testing.T) {
+	mockCtx := mocks.NewMockContext(t.Context())
+	svc := NewArmMsiService(
+		mockCtx.SubscriptionCredentialProvider,
+		mockCtx.ArmClientOptions,
+	)
+
+	_, err := svc.ListFederatedCredentials(t.Context(), "not-a-resource-id")
+	require.ErrorContains(t, err, "parsing MSI resource id")
+}
+
+func TestListFederatedCredentials_Success(t *testing.T) {
+	mockCtx := mocks.NewMockContext(t.Context())
+
+	mockCtx.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			strings.Contains(
+				request.URL.Path,
+				"MSI_NAME/federatedIdentityCredentials",
+			)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		body := armmsi.FederatedIdentityCredentialsListResult{
+			Value: []*armmsi.FederatedIdentityCredential{
+				{
+					Name: new("cred-1"),
+					Properties: &armmsi.FederatedIdentityCredentialProperties{
+						Subject: new("subject-1"),
+						Issuer:  new("https://issuer"),
+					},
+				},
+				nil,
+			},
+		}
+		return mocks.CreateHttpResponseWithBody(
+			request, http.StatusOK, body,
+		)
+	})
+
+	svc := NewArmMsiService(
+		mockCtx.SubscriptionCredentialProvider,
+		mockCtx.ArmClientOptions,
+	)
+
+	creds, err := svc.ListFederatedCredentials(t.Context(), testMsiResId)
+	require.NoError(t, err)
+	require.Len(t, creds, 1)
+	require.Equal(t, "subject-1", *creds[0].Properties.Subject)
+}
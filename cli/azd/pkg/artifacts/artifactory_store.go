@@ -0,0 +1,162 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package artifacts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// DefaultArtifactoryTokenEnvVar is the environment variable the Artifactory access token is read from when the
+// store doesn't set one.
+const DefaultArtifactoryTokenEnvVar = "ARTIFACTORY_TOKEN"
+
+// ArtifactoryStoreConfig is the configuration of an Artifactory artifact store
+type ArtifactoryStoreConfig struct {
+	// Url is the url of the generic repository the artifacts are stored in,
+	// for example https://example.jfrog.io/artifactory/azd-artifacts.
+	Url string `json:"url"`
+	// TokenEnvVar is the environment variable the access token is read from.
+	TokenEnvVar string `json:"tokenEnvVar"`
+}
+
+type artifactoryStore struct {
+	url         string
+	tokenEnvVar string
+	transporter policy.Transporter
+}
+
+// NewArtifactoryStore creates a store that keeps the artifacts in an Artifactory generic repository
+func NewArtifactoryStore(config *Config, transporter policy.Transporter) (Store, error) {
+	var storeConfig ArtifactoryStoreConfig
+	if err := decodeConfig(config, &storeConfig); err != nil {
+		return nil, err
+	}
+
+	if storeConfig.Url == "" {
+		return nil, errors.New("artifactory artifact store requires a 'url'")
+	}
+
+	if storeConfig.TokenEnvVar == "" {
+		storeConfig.TokenEnvVar = DefaultArtifactoryTokenEnvVar
+	}
+
+	return &artifactoryStore{
+		url:         strings.TrimSuffix(storeConfig.Url, "/"),
+		tokenEnvVar: storeConfig.TokenEnvVar,
+		transporter: transporter,
+	}, nil
+}
+
+func (s *artifactoryStore) Exists(ctx context.Context, key string) (bool, error) {
+	res, err := s.send(ctx, http.MethodHead, key, nil, nil)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected status code %d from %s", res.StatusCode, s.keyUrl(key))
+	}
+}
+
+func (s *artifactoryStore) Upload(ctx context.Context, key string, path string) error {
+	digest, err := fileDigest(path)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Artifactory verifies the uploaded content against the checksum header.
+	res, err := s.send(ctx, http.MethodPut, key, file, map[string]string{"X-Checksum-Sha256": digest})
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("unexpected status code %d from %s: %s", res.StatusCode, s.keyUrl(key), body)
+	}
+
+	return nil
+}
+
+func (s *artifactoryStore) Download(ctx context.Context, key string, path string) error {
+	res, err := s.send(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", ErrArtifactNotFound, key)
+	default:
+		return fmt.Errorf("unexpected status code %d from %s", res.StatusCode, s.keyUrl(key))
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, res.Body); err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+func (s *artifactoryStore) send(
+	ctx context.Context,
+	method string,
+	key string,
+	body *os.File,
+	headers map[string]string,
+) (*http.Response, error) {
+	var req *http.Request
+	var err error
+	if body != nil {
+		req, err = http.NewRequestWithContext(ctx, method, s.keyUrl(key), body)
+	} else {
+		req, err = http.NewRequestWithContext(ctx, method, s.keyUrl(key), nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if token := os.Getenv(s.tokenEnvVar); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	return s.transporter.Do(req)
+}
+
+func (s *artifactoryStore) keyUrl(key string) string {
+	return s.url + "/" + key
+}
@@ -0,0 +1,86 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package artifacts
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactoryStore(t *testing.T) {
+	t.Setenv("TEST_ARTIFACTORY_TOKEN", "token")
+
+	var mu sync.Mutex
+	stored := map[string][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		content, has := stored[r.URL.Path]
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			if r.Header.Get("X-Checksum-Sha256") == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			stored[r.URL.Path] = body
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodHead, http.MethodGet:
+			if !has {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(content)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	store, err := NewArtifactoryStore(&Config{
+		Store: &StoreConfig{
+			Kind:   StoreKindArtifactory,
+			Config: map[string]any{"url": server.URL + "/artifactory/azd/", "tokenEnvVar": "TEST_ARTIFACTORY_TOKEN"},
+		},
+	}, server.Client())
+	require.NoError(t, err)
+
+	key := "sha256/abcd/api.zip"
+	exists, err := store.Exists(t.Context(), key)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	err = store.Download(t.Context(), key, filepath.Join(t.TempDir(), "api.zip"))
+	require.ErrorIs(t, err, ErrArtifactNotFound)
+
+	require.NoError(t, store.Upload(t.Context(), key, writeTestFile(t, "api.zip", "package content")))
+	require.Contains(t, stored, "/artifactory/azd/"+key)
+
+	exists, err = store.Exists(t.Context(), key)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	downloaded := filepath.Join(t.TempDir(), "api.zip")
+	require.NoError(t, store.Download(t.Context(), key, downloaded))
+	content, err := os.ReadFile(downloaded)
+	require.NoError(t, err)
+	require.Equal(t, "package content", string(content))
+}
+
+func TestNewArtifactoryStoreRequiresUrl(t *testing.T) {
+	t.Parallel()
+	_, err := NewArtifactoryStore(&Config{Store: &StoreConfig{Kind: StoreKindArtifactory}}, http.DefaultClient)
+	require.ErrorContains(t, err, "requires a 'url'")
+}
@@ -0,0 +1,111 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package artifacts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/auth"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk/storage"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+)
+
+// DefaultBlobContainerName is the container artifacts are stored in when the blob store doesn't set one.
+const DefaultBlobContainerName = "azd-artifacts"
+
+type blobStore struct {
+	client storage.BlobClient
+}
+
+// NewBlobStore creates a store that keeps the artifacts in an Azure Storage blob container.
+// The store config has the same shape as the blob remote state config: accountName, containerName, endpoint and
+// subscriptionId.
+func NewBlobStore(
+	config *Config,
+	credentialProvider auth.MultiTenantCredentialProvider,
+	userConfigManager config.UserConfigManager,
+	coreClientOptions *azcore.ClientOptions,
+	cloud *cloud.Cloud,
+	subscriptionResolver account.SubscriptionResolver,
+) (Store, error) {
+	var accountConfig storage.AccountConfig
+	if err := decodeConfig(config, &accountConfig); err != nil {
+		return nil, err
+	}
+
+	if accountConfig.AccountName == "" {
+		return nil, errors.New("blob artifact store requires an 'accountName'")
+	}
+
+	if accountConfig.ContainerName == "" {
+		accountConfig.ContainerName = DefaultBlobContainerName
+	}
+
+	client, err := storage.NewBlobSdkClient(
+		credentialProvider, &accountConfig, userConfigManager, coreClientOptions, cloud, subscriptionResolver)
+	if err != nil {
+		return nil, fmt.Errorf("creating blob client: %w", err)
+	}
+
+	return newBlobStore(storage.NewBlobClient(&accountConfig, client)), nil
+}
+
+func newBlobStore(client storage.BlobClient) Store {
+	return &blobStore{client: client}
+}
+
+func (s *blobStore) Exists(ctx context.Context, key string) (bool, error) {
+	reader, err := s.client.Download(ctx, key)
+	if err != nil {
+		if bloberror.HasCode(err, bloberror.BlobNotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, reader.Close()
+}
+
+func (s *blobStore) Upload(ctx context.Context, key string, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return s.client.Upload(ctx, key, file)
+}
+
+func (s *blobStore) Download(ctx context.Context, key string, path string) error {
+	reader, err := s.client.Download(ctx, key)
+	if err != nil {
+		if bloberror.HasCode(err, bloberror.BlobNotFound) {
+			return fmt.Errorf("%w: %s", ErrArtifactNotFound, key)
+		}
+
+		return err
+	}
+	defer reader.Close()
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, reader); err != nil {
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,70 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package artifacts
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk/storage"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryBlobClient is a blob client that keeps the blobs in memory.
+type memoryBlobClient struct {
+	storage.BlobClient
+	blobs map[string][]byte
+}
+
+func (c *memoryBlobClient) Download(ctx context.Context, blobPath string) (io.ReadCloser, error) {
+	content, has := c.blobs[blobPath]
+	if !has {
+		return nil, &azcore.ResponseError{ErrorCode: string(bloberror.BlobNotFound)}
+	}
+
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
+
+func (c *memoryBlobClient) Upload(ctx context.Context, blobPath string, reader io.Reader) error {
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	c.blobs[blobPath] = content
+	return nil
+}
+
+func TestBlobStore(t *testing.T) {
+	t.Parallel()
+	client := &memoryBlobClient{blobs: map[string][]byte{}}
+	store := newBlobStore(client)
+
+	key := "sha256/abcd/api.zip"
+	exists, err := store.Exists(t.Context(), key)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	err = store.Download(t.Context(), key, filepath.Join(t.TempDir(), "api.zip"))
+	require.ErrorIs(t, err, ErrArtifactNotFound)
+
+	require.NoError(t, store.Upload(t.Context(), key, writeTestFile(t, "api.zip", "package content")))
+	require.Equal(t, []byte("package content"), client.blobs[key])
+
+	exists, err = store.Exists(t.Context(), key)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	downloaded := filepath.Join(t.TempDir(), "api.zip")
+	require.NoError(t, store.Download(t.Context(), key, downloaded))
+	content, err := os.ReadFile(downloaded)
+	require.NoError(t, err)
+	require.Equal(t, "package content", string(content))
+}
@@ -0,0 +1,38 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package artifacts
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Config is the artifacts configuration for an azd project
+type Config struct {
+	Store *StoreConfig `json:"store" yaml:"store"`
+}
+
+// StoreConfig is the configuration of the store that package artifacts are pushed to and pulled from
+type StoreConfig struct {
+	Kind   StoreKind      `json:"kind"   yaml:"kind"`
+	Config map[string]any `json:"config" yaml:"config"`
+}
+
+// decodeConfig decodes the store specific configuration into target
+func decodeConfig(config *Config, target any) error {
+	if config == nil || config.Store == nil {
+		return ErrStoreNotConfigured
+	}
+
+	jsonBytes, err := json.Marshal(config.Store.Config)
+	if err != nil {
+		return fmt.Errorf("marshalling artifact store config: %w", err)
+	}
+
+	if err := json.Unmarshal(jsonBytes, target); err != nil {
+		return fmt.Errorf("unmarshalling artifact store config: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package artifacts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/github"
+)
+
+// DefaultGitHubReleaseTag is the tag of the release artifacts are attached to when the GitHub store doesn't set one.
+const DefaultGitHubReleaseTag = "azd-artifacts"
+
+// GitHubStoreConfig is the configuration of a GitHub releases artifact store
+type GitHubStoreConfig struct {
+	// Repository is the slug of the repository, formatted as <owner>/<name>.
+	Repository string `json:"repository"`
+	// Tag is the tag of the release the artifacts are attached to. The release is created when it doesn't exist.
+	Tag string `json:"tag"`
+}
+
+type gitHubStore struct {
+	config GitHubStoreConfig
+	ghCli  *github.Cli
+}
+
+// NewGitHubStore creates a store that keeps the artifacts as assets of a GitHub release, using the GitHub CLI
+func NewGitHubStore(config *Config, ghCli *github.Cli) (Store, error) {
+	var storeConfig GitHubStoreConfig
+	if err := decodeConfig(config, &storeConfig); err != nil {
+		return nil, err
+	}
+
+	if storeConfig.Repository == "" {
+		return nil, errors.New("github artifact store requires a 'repository'")
+	}
+
+	if storeConfig.Tag == "" {
+		storeConfig.Tag = DefaultGitHubReleaseTag
+	}
+
+	return &gitHubStore{config: storeConfig, ghCli: ghCli}, nil
+}
+
+func (s *gitHubStore) Exists(ctx context.Context, key string) (bool, error) {
+	if err := s.ghCli.EnsureInstalled(ctx); err != nil {
+		return false, err
+	}
+
+	assets, err := s.ghCli.ListReleaseAssets(ctx, s.config.Repository, s.config.Tag)
+	if errors.Is(err, github.ErrReleaseNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return slices.Contains(assets, assetName(key)), nil
+}
+
+func (s *gitHubStore) Upload(ctx context.Context, key string, path string) error {
+	if err := s.ghCli.EnsureInstalled(ctx); err != nil {
+		return err
+	}
+
+	_, err := s.ghCli.ListReleaseAssets(ctx, s.config.Repository, s.config.Tag)
+	if errors.Is(err, github.ErrReleaseNotFound) {
+		err = s.ghCli.CreateRelease(
+			ctx, s.config.Repository, s.config.Tag, "Package artifacts pushed by the Azure Developer CLI.")
+	}
+	if err != nil {
+		return err
+	}
+
+	// Release assets are named after the uploaded file, so upload a copy named after the key.
+	tempDir, err := os.MkdirTemp("", "azd-artifact")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	assetPath := filepath.Join(tempDir, assetName(key))
+	if err := copyFile(path, assetPath); err != nil {
+		return err
+	}
+
+	return s.ghCli.UploadReleaseAsset(ctx, s.config.Repository, s.config.Tag, assetPath)
+}
+
+func (s *gitHubStore) Download(ctx context.Context, key string, path string) error {
+	exists, err := s.Exists(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrArtifactNotFound, key)
+	}
+
+	return s.ghCli.DownloadReleaseAsset(ctx, s.config.Repository, s.config.Tag, assetName(key), path)
+}
+
+// assetName returns the release asset name for a key. Release assets are flat, so the key sha256/<digest>/<name>
+// becomes <digest>-<name>.
+func assetName(key string) string {
+	return strings.ReplaceAll(strings.TrimPrefix(key, "sha256/"), "/", "-")
+}
@@ -0,0 +1,108 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package artifacts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// LocalStoreConfig is the configuration of a local artifact store
+type LocalStoreConfig struct {
+	// Path is the directory the artifacts are stored in, relative to the project directory when not absolute.
+	// Typically a network share, or a directory that is cached by the CI system.
+	Path string `json:"path"`
+}
+
+type localStore struct {
+	root string
+}
+
+// NewLocalStore creates a store that keeps the artifacts in a directory
+func NewLocalStore(config *Config, azdCtx *azdcontext.AzdContext) (Store, error) {
+	var storeConfig LocalStoreConfig
+	if err := decodeConfig(config, &storeConfig); err != nil {
+		return nil, err
+	}
+
+	if storeConfig.Path == "" {
+		return nil, errors.New("local artifact store requires a 'path'")
+	}
+
+	root := storeConfig.Path
+	if !filepath.IsAbs(root) && azdCtx != nil {
+		root = filepath.Join(azdCtx.ProjectDirectory(), root)
+	}
+
+	return &localStore{root: root}, nil
+}
+
+func (s *localStore) Exists(ctx context.Context, key string) (bool, error) {
+	if _, err := os.Stat(s.path(key)); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *localStore) Upload(ctx context.Context, key string, path string) error {
+	target := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(target), osutil.PermissionDirectory); err != nil {
+		return err
+	}
+
+	// Copy to a temporary file first, so a failed upload never leaves a partial artifact under the key.
+	temp := target + ".tmp"
+	if err := copyFile(path, temp); err != nil {
+		_ = os.Remove(temp)
+		return err
+	}
+
+	return os.Rename(temp, target)
+}
+
+func (s *localStore) Download(ctx context.Context, key string, path string) error {
+	err := copyFile(s.path(key), path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrArtifactNotFound, key)
+	}
+
+	return err
+}
+
+func (s *localStore) path(key string) string {
+	return filepath.Join(s.root, filepath.FromSlash(key))
+}
+
+func copyFile(source string, target string) error {
+	sourceFile, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer sourceFile.Close()
+
+	targetFile, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	defer targetFile.Close()
+
+	if _, err := io.Copy(targetFile, sourceFile); err != nil {
+		return err
+	}
+
+	return targetFile.Close()
+}
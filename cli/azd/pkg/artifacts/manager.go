@@ -0,0 +1,126 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package artifacts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+)
+
+// Manager pushes package artifacts to, and pulls them from, the artifact store configured for the project.
+type Manager struct {
+	config         *Config
+	serviceLocator ioc.ServiceLocator
+
+	storeOnce sync.Once
+	store     Store
+	storeErr  error
+}
+
+// NewManager creates a new Manager instance
+func NewManager(config *Config, serviceLocator ioc.ServiceLocator) *Manager {
+	return &Manager{
+		config:         config,
+		serviceLocator: serviceLocator,
+	}
+}
+
+// Configured returns whether an artifact store is configured for the project.
+func (m *Manager) Configured() bool {
+	return m.config != nil && m.config.Store != nil
+}
+
+// Push stores the file at filePath, and returns the reference it can be pulled with.
+// The file isn't uploaded again when the store already holds an artifact with the same content and name.
+func (m *Manager) Push(ctx context.Context, filePath string) (Reference, error) {
+	store, err := m.resolveStore()
+	if err != nil {
+		return Reference{}, err
+	}
+
+	digest, err := fileDigest(filePath)
+	if err != nil {
+		return Reference{}, err
+	}
+
+	ref := Reference{Digest: digest, Name: filepath.Base(filePath)}
+
+	exists, err := store.Exists(ctx, ref.Key())
+	if err != nil {
+		return Reference{}, fmt.Errorf("checking artifact '%s': %w", ref, err)
+	}
+
+	if exists {
+		log.Printf("artifact '%s' is already stored, skipping upload", ref)
+		return ref, nil
+	}
+
+	if err := store.Upload(ctx, ref.Key(), filePath); err != nil {
+		return Reference{}, fmt.Errorf("uploading artifact '%s': %w", ref, err)
+	}
+
+	return ref, nil
+}
+
+// Pull downloads the artifact identified by ref into dir, and returns the path of the downloaded file.
+// The content of the downloaded file is verified against the digest of the reference.
+func (m *Manager) Pull(ctx context.Context, ref Reference, dir string) (string, error) {
+	store, err := m.resolveStore()
+	if err != nil {
+		return "", err
+	}
+
+	filePath := filepath.Join(dir, ref.Name)
+	if err := store.Download(ctx, ref.Key(), filePath); err != nil {
+		return "", fmt.Errorf("downloading artifact '%s': %w", ref, err)
+	}
+
+	digest, err := fileDigest(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	if digest != ref.Digest {
+		_ = os.Remove(filePath)
+		return "", fmt.Errorf("%w: artifact '%s' has digest '%s'", ErrDigestMismatch, ref, digest)
+	}
+
+	return filePath, nil
+}
+
+// resolveStore resolves the store for the configured kind on first use, so commands that don't push or pull
+// artifacts aren't affected by the store configuration.
+func (m *Manager) resolveStore() (Store, error) {
+	m.storeOnce.Do(func() {
+		if !m.Configured() {
+			m.storeErr = ErrStoreNotConfigured
+			return
+		}
+
+		kind := m.config.Store.Kind
+		if err := m.serviceLocator.ResolveNamed(string(kind), &m.store); err != nil {
+			if errors.Is(err, ioc.ErrResolveInstance) {
+				m.storeErr = fmt.Errorf(
+					"artifact store configuration is invalid. The specified kind '%s' is not valid. "+
+						"Valid values are '%s'.",
+					kind,
+					ux.ListAsText(ValidStoreKinds),
+				)
+				return
+			}
+
+			m.storeErr = fmt.Errorf("resolving artifact store: %w", err)
+		}
+	})
+
+	return m.store, m.storeErr
+}
@@ -0,0 +1,131 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package artifacts
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/stretchr/testify/require"
+)
+
+// countingStore records the uploads to the wrapped store.
+type countingStore struct {
+	Store
+	uploads int
+}
+
+func (s *countingStore) Upload(ctx context.Context, key string, path string) error {
+	s.uploads++
+	return s.Store.Upload(ctx, key, path)
+}
+
+func newTestManager(t *testing.T, kind StoreKind) (*Manager, *countingStore) {
+	t.Helper()
+
+	config := &Config{
+		Store: &StoreConfig{
+			Kind:   kind,
+			Config: map[string]any{"path": t.TempDir()},
+		},
+	}
+
+	store := &countingStore{}
+	container := ioc.NewNestedContainer(nil)
+	container.MustRegisterNamedSingleton(string(StoreKindLocal), func() (Store, error) {
+		localStore, err := NewLocalStore(config, nil)
+		store.Store = localStore
+		return store, err
+	})
+
+	return NewManager(config, container), store
+}
+
+func writeTestFile(t *testing.T, name string, content string) string {
+	t.Helper()
+
+	filePath := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(filePath, []byte(content), 0600))
+	return filePath
+}
+
+func TestManagerPushPull(t *testing.T) {
+	t.Parallel()
+	manager, store := newTestManager(t, StoreKindLocal)
+	require.True(t, manager.Configured())
+
+	packagePath := writeTestFile(t, "api.zip", "package content")
+	ref, err := manager.Push(t.Context(), packagePath)
+	require.NoError(t, err)
+	require.Equal(t, "api.zip", ref.Name)
+	require.Equal(t, 1, store.uploads)
+
+	// Pushing the same content again doesn't upload it again.
+	again, err := manager.Push(t.Context(), writeTestFile(t, "api.zip", "package content"))
+	require.NoError(t, err)
+	require.Equal(t, ref, again)
+	require.Equal(t, 1, store.uploads)
+
+	pulledPath, err := manager.Pull(t.Context(), ref, t.TempDir())
+	require.NoError(t, err)
+	require.Equal(t, "api.zip", filepath.Base(pulledPath))
+
+	content, err := os.ReadFile(pulledPath)
+	require.NoError(t, err)
+	require.Equal(t, "package content", string(content))
+}
+
+func TestManagerPullErrors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+		manager, _ := newTestManager(t, StoreKindLocal)
+
+		ref := Reference{Digest: "0000000000000000000000000000000000000000000000000000000000000000", Name: "api.zip"}
+		_, err := manager.Pull(t.Context(), ref, t.TempDir())
+		require.ErrorIs(t, err, ErrArtifactNotFound)
+	})
+
+	t.Run("DigestMismatch", func(t *testing.T) {
+		t.Parallel()
+		manager, store := newTestManager(t, StoreKindLocal)
+
+		ref, err := manager.Push(t.Context(), writeTestFile(t, "api.zip", "package content"))
+		require.NoError(t, err)
+
+		// Tamper with the stored artifact.
+		tampered := writeTestFile(t, "api.zip", "tampered content")
+		require.NoError(t, store.Store.Upload(t.Context(), ref.Key(), tampered))
+
+		dir := t.TempDir()
+		_, err = manager.Pull(t.Context(), ref, dir)
+		require.ErrorIs(t, err, ErrDigestMismatch)
+		require.NoFileExists(t, filepath.Join(dir, "api.zip"))
+	})
+}
+
+func TestManagerStoreResolution(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NotConfigured", func(t *testing.T) {
+		t.Parallel()
+		manager := NewManager(nil, ioc.NewNestedContainer(nil))
+		require.False(t, manager.Configured())
+
+		_, err := manager.Push(t.Context(), writeTestFile(t, "api.zip", "package content"))
+		require.ErrorIs(t, err, ErrStoreNotConfigured)
+	})
+
+	t.Run("InvalidKind", func(t *testing.T) {
+		t.Parallel()
+		manager, _ := newTestManager(t, StoreKind("ftp"))
+
+		_, err := manager.Push(t.Context(), writeTestFile(t, "api.zip", "package content"))
+		require.ErrorContains(t, err, "The specified kind 'ftp' is not valid")
+	})
+}
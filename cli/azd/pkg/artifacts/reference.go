@@ -0,0 +1,68 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package artifacts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+)
+
+const referencePrefix = "sha256:"
+
+// referenceRegex matches references like sha256:<digest>/<name>
+var referenceRegex = regexp.MustCompile(`^sha256:([0-9a-f]{64})/([^/\\]+)$`)
+
+// Reference identifies an artifact by the SHA-256 digest of its content, and the file name it was pushed with.
+type Reference struct {
+	Digest string
+	Name   string
+}
+
+// String returns the reference formatted as sha256:<digest>/<name>
+func (r Reference) String() string {
+	return fmt.Sprintf("%s%s/%s", referencePrefix, r.Digest, r.Name)
+}
+
+// Key returns the key the artifact is stored under.
+func (r Reference) Key() string {
+	return path.Join("sha256", r.Digest, r.Name)
+}
+
+// IsReference returns whether value looks like an artifact reference rather than a path or an image.
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, referencePrefix)
+}
+
+// ParseReference parses a reference formatted as sha256:<digest>/<name>
+func ParseReference(value string) (Reference, error) {
+	matches := referenceRegex.FindStringSubmatch(value)
+	if matches == nil || matches[2] == "." || matches[2] == ".." {
+		return Reference{}, fmt.Errorf(
+			"invalid artifact reference '%s', expected sha256:<digest>/<file name>", value)
+	}
+
+	return Reference{Digest: matches[1], Name: matches[2]}, nil
+}
+
+// fileDigest returns the hex encoded SHA-256 digest of the file at filePath.
+func fileDigest(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("computing digest of '%s': %w", filePath, err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
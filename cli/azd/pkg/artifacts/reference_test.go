@@ -0,0 +1,59 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package artifacts
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReference(t *testing.T) {
+	t.Parallel()
+	digest := strings.Repeat("ab", 32)
+
+	tests := []struct {
+		name    string
+		value   string
+		want    Reference
+		wantErr bool
+	}{
+		{name: "Valid", value: "sha256:" + digest + "/api.zip", want: Reference{Digest: digest, Name: "api.zip"}},
+		{name: "MissingName", value: "sha256:" + digest, wantErr: true},
+		{name: "ShortDigest", value: "sha256:abcd/api.zip", wantErr: true},
+		{name: "UppercaseDigest", value: "sha256:" + strings.ToUpper(digest) + "/api.zip", wantErr: true},
+		{name: "NestedName", value: "sha256:" + digest + "/dir/api.zip", wantErr: true},
+		{name: "ParentName", value: "sha256:" + digest + "/..", wantErr: true},
+		{name: "Path", value: "./dist/api.zip", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ref, err := ParseReference(tt.value)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, ref)
+			require.Equal(t, tt.value, ref.String())
+			require.Equal(t, "sha256/"+digest+"/api.zip", ref.Key())
+		})
+	}
+}
+
+func TestIsReference(t *testing.T) {
+	t.Parallel()
+	require.True(t, IsReference("sha256:abcd/api.zip"))
+	require.False(t, IsReference("./dist/api.zip"))
+	require.False(t, IsReference("myregistry.azurecr.io/api@sha256:abcd"))
+}
+
+func TestAssetName(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "abcd-api.zip", assetName("sha256/abcd/api.zip"))
+}
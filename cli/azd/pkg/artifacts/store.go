@@ -0,0 +1,47 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package artifacts
+
+import (
+	"context"
+	"errors"
+)
+
+// StoreKind is the kind of an artifact store
+type StoreKind string
+
+const (
+	StoreKindLocal       StoreKind = "local"
+	StoreKindBlob        StoreKind = "blob"
+	StoreKindGitHub      StoreKind = "github"
+	StoreKindArtifactory StoreKind = "artifactory"
+)
+
+// ValidStoreKinds are the kinds of artifact stores supported by azd
+var ValidStoreKinds = []string{
+	string(StoreKindLocal),
+	string(StoreKindBlob),
+	string(StoreKindGitHub),
+	string(StoreKindArtifactory),
+}
+
+var (
+	ErrStoreNotConfigured = errors.New("artifact store is not configured")
+	ErrArtifactNotFound   = errors.New("artifact not found in the artifact store")
+	ErrDigestMismatch     = errors.New("artifact digest doesn't match its reference")
+)
+
+// Store stores artifacts under content addressed keys.
+// Keys are written once, so a key that exists always holds the same content.
+type Store interface {
+	// Exists returns whether an artifact is stored under key.
+	Exists(ctx context.Context, key string) (bool, error)
+
+	// Upload stores the file at path under key.
+	Upload(ctx context.Context, key string, path string) error
+
+	// Download writes the artifact stored under key to the file at path.
+	// Returns ErrArtifactNotFound when no artifact is stored under key.
+	Download(ctx context.Context, key string, path string) error
+}
@@ -21,17 +21,20 @@ type azdCredential struct {
 	cloud       *cloud.Cloud
 	tenantID    string
 	cacheTracer *msalCacheTracer
+	stepUp      *stepUp
 }
 
 // newAzdCredential creates a credential that acquires tokens via MSAL's public client.
 // tenantID, when non-empty, is forwarded to AcquireTokenSilent so MSAL issues tokens
 // for that specific tenant instead of defaulting to the account's home tenant.
+// stepUp, when non-nil, signs the user in again when a claims challenge can't be satisfied silently.
 func newAzdCredential(
 	client publicClient,
 	account *public.Account,
 	cloud *cloud.Cloud,
 	tenantID string,
 	cacheTracer *msalCacheTracer,
+	stepUp *stepUp,
 ) *azdCredential {
 	return &azdCredential{
 		client:      client,
@@ -39,6 +42,7 @@ func newAzdCredential(
 		cloud:       cloud,
 		tenantID:    tenantID,
 		cacheTracer: cacheTracer,
+		stepUp:      stepUp,
 	}
 }
 
@@ -78,6 +82,21 @@ func (c *azdCredential) GetToken(ctx context.Context, options policy.TokenReques
 			if loginErr, ok := newActionableAuthError(authFailed.Parsed, options.Scopes, c.cloud, tenantID, authFailed); ok {
 				log.Println(authFailed.httpErrorDetails())
 
+				if options.Claims != "" && c.stepUp != nil {
+					challenge := newClaimsChallenge(options.Claims, authFailed.Parsed, options.Scopes, c.cloud, tenantID)
+					res, err := c.stepUp.acquire(ctx, challenge, func() (public.AuthResult, error) {
+						return c.client.AcquireTokenSilent(ctx, options.Scopes, silentOpts...)
+					})
+					if err == nil {
+						return azcore.AccessToken{
+							Token:     res.AccessToken,
+							ExpiresOn: res.ExpiresOn.UTC(),
+						}, nil
+					} else if !errors.Is(err, ErrStepUpUnavailable) {
+						return azcore.AccessToken{}, err
+					}
+				}
+
 				if options.Claims != "" {
 					if err := saveClaims(options.Claims); err != nil {
 						return azcore.AccessToken{}, fmt.Errorf("saving claims: %w", err)
@@ -98,7 +98,7 @@ func TestAzdCredential_GetToken_ForwardsTenantID(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			spy := &spyPublicClient{}
 			account := &public.Account{HomeAccountID: "test.id"}
-			cred := newAzdCredential(spy, account, cloud.AzurePublic(), tt.credTID, nil)
+			cred := newAzdCredential(spy, account, cloud.AzurePublic(), tt.credTID, nil, nil)
 
 			_, err := cred.GetToken(t.Context(), policy.TokenRequestOptions{
 				Scopes:   []string{"https://graph.microsoft.com/.default"},
@@ -191,6 +191,7 @@ func TestAzdCredential_GetToken_LogsSuccessSnapshotAfterFailure(t *testing.T) {
 		cloud.AzurePublic(),
 		"",
 		tracer,
+		nil,
 	)
 
 	_, err := cred.GetToken(t.Context(), policy.TokenRequestOptions{
@@ -227,7 +228,7 @@ func TestAzdCredential_GetToken_Success(t *testing.T) {
 
 	account := public.Account{HomeAccountID: "h1"}
 	cred := newAzdCredential(
-		pc, &account, cloud.AzurePublic(), "", nil,
+		pc, &account, cloud.AzurePublic(), "", nil, nil,
 	)
 
 	tok, err := cred.GetToken(t.Context(), policy.TokenRequestOptions{
@@ -248,7 +249,7 @@ func TestAzdCredential_GetToken_WithTenantID(t *testing.T) {
 
 	account := public.Account{HomeAccountID: "h1"}
 	cred := newAzdCredential(
-		pc, &account, cloud.AzurePublic(), "default-t", nil,
+		pc, &account, cloud.AzurePublic(), "default-t", nil, nil,
 	)
 
 	// Override with request-level tenant
@@ -299,7 +299,7 @@ func TestAzdCredential_GetToken_GenericError(t *testing.T) {
 
 	account := public.Account{HomeAccountID: "h1"}
 	cred := newAzdCredential(
-		pc, &account, cloud.AzurePublic(), "", nil,
+		pc, &account, cloud.AzurePublic(), "", nil, nil,
 	)
 
 	_, err := cred.GetToken(t.Context(), policy.TokenRequestOptions{
@@ -319,7 +319,7 @@ func TestAzdCredential_GetToken_AuthFailedNotReLogin(t *testing.T) {
 
 	account := public.Account{HomeAccountID: "h1"}
 	cred := newAzdCredential(
-		pc, &account, cloud.AzurePublic(), "", nil,
+		pc, &account, cloud.AzurePublic(), "", nil, nil,
 	)
 
 	_, err := cred.GetToken(t.Context(), policy.TokenRequestOptions{
@@ -436,7 +436,7 @@ func TestAzdCredentialGetToken_ClaimsReLoginPath(t *testing.T) {
 	}
 
 	acct := public.Account{HomeAccountID: "home-a"}
-	cred := newAzdCredential(client, &acct, c, "", nil)
+	cred := newAzdCredential(client, &acct, c, "", nil, nil)
 
 	opts := tokenRequestOpts(c, "my-claims")
 	_, err := cred.GetToken(t.Context(), opts)
@@ -101,6 +101,7 @@ type Manager struct {
 	externalAuthCfg     ExternalAuthConfiguration
 	azCli               az.AzCli
 	userAgent           string
+	stepUp              *stepUp
 
 	// azCliCredentials caches az CLI credentials keyed by tenant ID when auth.useAzCliAuth is set.
 	// Each entry is a cachingCredential wrapping an AzureCLICredential. Sharing a single instance per
@@ -129,6 +130,7 @@ func NewManager(
 	externalAuthCfg ExternalAuthConfiguration,
 	azCli az.AzCli,
 	userAgent UserAgent,
+	stepUpHandler StepUpHandler,
 ) (*Manager, error) {
 	cfgRoot, err := config.GetUserConfigDir()
 	if err != nil {
@@ -177,6 +179,7 @@ func NewManager(
 		externalAuthCfg:     externalAuthCfg,
 		azCli:               azCli,
 		userAgent:           string(userAgent),
+		stepUp:              newStepUp(stepUpHandler),
 		azCliCredentials:    map[string]azcore.TokenCredential{},
 	}, nil
 }
@@ -337,6 +340,7 @@ func (m *Manager) CredentialForCurrentUser(
 						m.cloud,
 						"", /* tenantID */
 						m.msalCacheTracer,
+						m.stepUp,
 					), nil
 				} else {
 					newAuthority := m.cloud.Configuration.ActiveDirectoryAuthorityHost + options.TenantID
@@ -359,6 +363,7 @@ func (m *Manager) CredentialForCurrentUser(
 						m.cloud,
 						options.TenantID,
 						m.msalCacheTracer,
+						m.stepUp,
 					), nil
 				}
 			}
@@ -800,6 +805,7 @@ func (m *Manager) LoginInteractive(
 		m.cloud,
 		"", /* tenantID */
 		m.msalCacheTracer,
+		m.stepUp,
 	), nil
 }
 
@@ -874,7 +880,7 @@ func (m *Manager) LoginWithDeviceCode(
 		return nil, err
 	}
 
-	url := "https://microsoft.com/devicelogin"
+	url := m.cloud.DeviceLoginUrl
 
 	if runcontext.IsRunningInCloudShell() {
 		m.console.MessageUxItem(ctx, &ux.MultilineMessage{
@@ -920,6 +926,7 @@ func (m *Manager) LoginWithDeviceCode(
 		m.cloud,
 		"", /* tenantID */
 		m.msalCacheTracer,
+		m.stepUp,
 	), nil
 
 }
@@ -1985,6 +1985,7 @@ func TestNewManager_Success(t *testing.T) {
 		ExternalAuthConfiguration{},
 		az.AzCli{},
 		"test-agent",
+		nil,
 	)
 	require.NoError(t, err)
 	require.NotNil(t, mgr)
@@ -2002,6 +2003,7 @@ func TestNewManager_EmptyUserAgent(t *testing.T) {
 		ExternalAuthConfiguration{},
 		az.AzCli{},
 		"", // empty user-agent — exercises the bypass in newUserAgentClient
+		nil,
 	)
 	require.NoError(t, err)
 	require.NotNil(t, mgr)
@@ -2261,6 +2263,39 @@ func TestLoginWithDeviceCode_OpenURLError(t *testing.T) {
 		"Error launching browser. Manually go to: https://microsoft.com/devicelogin")
 }
 
+func TestLoginWithDeviceCode_SovereignCloudUrl(t *testing.T) {
+	t.Setenv("AZD_CONFIG_DIR", t.TempDir())
+
+	console := mockinput.NewMockConsole()
+	m := &Manager{
+		configManager:     newMemoryConfigManager(),
+		userConfigManager: newMemoryUserConfigManager(),
+		cloud:             cloud.AzureChina(),
+		console:           console,
+		publicClient: &mockPublicClientFull{
+			deviceCodeResult: &stubDeviceCode{
+				msg:  "msg",
+				code: "CODE",
+				res: public.AuthResult{
+					Account: public.Account{
+						HomeAccountID: "dc-china",
+					},
+				},
+			},
+		},
+	}
+
+	var openedUrl string
+	_, err := m.LoginWithDeviceCode(
+		t.Context(), "", nil, "",
+		func(url string) error {
+			openedUrl = url
+			return nil
+		})
+	require.NoError(t, err)
+	require.Equal(t, "https://microsoft.com/deviceloginchina", openedUrl)
+}
+
 func TestLoginWithDeviceCode_AcquireError(t *testing.T) {
 	t.Setenv("AZD_CONFIG_DIR", t.TempDir())
 
@@ -2438,7 +2473,7 @@ func TestCredentialForCurrentUser_PublicClientTenantOverride(t *testing.T) {
 	mgr, err := NewManager(
 		cfgMgr, userCfgMgr, c,
 		http.DefaultClient, nil,
-		ExternalAuthConfiguration{}, az.AzCli{}, "test-ua",
+		ExternalAuthConfiguration{}, az.AzCli{}, "test-ua", nil,
 	)
 	require.NoError(t, err)
 
@@ -2708,6 +2743,7 @@ func TestCredentialCachePaths(t *testing.T) {
 		ExternalAuthConfiguration{},
 		az.AzCli{},
 		"ua",
+		nil,
 	)
 	require.NoError(t, err)
 
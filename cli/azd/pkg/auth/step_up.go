@@ -0,0 +1,139 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+)
+
+// ErrStepUpUnavailable is returned by a [StepUpHandler] when it can't sign the user in again, for example when azd
+// runs without prompting or the user declines. The claims challenge then fails with instructions to sign in again.
+var ErrStepUpUnavailable = errors.New("interactive step-up authentication is unavailable")
+
+// ClaimsChallenge describes a token request that was rejected until the user signs in again with additional claims,
+// for example to satisfy multi-factor authentication or a Conditional Access authentication context.
+type ClaimsChallenge struct {
+	// Claims is the claims JSON requested by the challenge.
+	Claims string
+	// TenantID is the tenant the token was requested for, empty for the home tenant of the account.
+	TenantID string
+	// Scopes are the requested scopes that aren't requested by default on login.
+	Scopes []string
+	// Reason is a user facing description of why additional authentication is required.
+	Reason string
+}
+
+// LoginArgs returns the arguments of the `azd auth login` command that satisfies the challenge.
+func (c ClaimsChallenge) LoginArgs() []string {
+	args := []string{"auth", "login", "--claims", base64.StdEncoding.EncodeToString([]byte(c.Claims))}
+	if c.TenantID != "" {
+		args = append(args, "--tenant-id", c.TenantID)
+	}
+
+	for _, scope := range c.Scopes {
+		args = append(args, "--scope", scope)
+	}
+
+	return args
+}
+
+// StepUpHandler signs the user in again to satisfy a claims challenge.
+type StepUpHandler interface {
+	// StepUp signs the user in with the claims of the challenge. It returns [ErrStepUpUnavailable] when it can't.
+	StepUp(ctx context.Context, challenge ClaimsChallenge) error
+}
+
+// stepUp satisfies claims challenges of credentials with a [StepUpHandler]. Step-ups are serialized, so concurrent
+// token requests rejected by the same challenge only sign the user in once.
+type stepUp struct {
+	handler StepUpHandler
+	mu      sync.Mutex
+}
+
+func newStepUp(handler StepUpHandler) *stepUp {
+	if handler == nil {
+		return nil
+	}
+
+	return &stepUp{handler: handler}
+}
+
+// acquire returns the token of acquireSilent, signing the user in with the handler when the token still can't be
+// acquired silently.
+func (s *stepUp) acquire(
+	ctx context.Context,
+	challenge ClaimsChallenge,
+	acquireSilent func() (public.AuthResult, error),
+) (public.AuthResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Another token request may have signed the user in while this one was waiting.
+	if res, err := acquireSilent(); err == nil {
+		return res, nil
+	}
+
+	if err := s.handler.StepUp(ctx, challenge); err != nil {
+		return public.AuthResult{}, err
+	}
+
+	return acquireSilent()
+}
+
+// newClaimsChallenge returns the challenge of a token request for the scopes that was rejected with the error
+// response.
+func newClaimsChallenge(
+	claims string,
+	response *AadErrorResponse,
+	scopes []string,
+	cloud *cloud.Cloud,
+	tenantID string,
+) ClaimsChallenge {
+	loginScopes := LoginScopesFull(cloud)
+	challengeScopes := []string{}
+	for _, scope := range scopes {
+		// filter out default login scopes
+		if !slices.Contains(loginScopes, scope) {
+			challengeScopes = append(challengeScopes, scope)
+		}
+	}
+
+	return ClaimsChallenge{
+		Claims:   claims,
+		TenantID: tenantID,
+		Scopes:   challengeScopes,
+		Reason:   stepUpReason(claims, response),
+	}
+}
+
+// stepUpReason describes why additional authentication is required, based on the AADSTS error codes of the response
+// and the requested claims.
+func stepUpReason(claims string, response *AadErrorResponse) string {
+	// AADSTS50076, AADSTS50079 and AADSTS50074 are returned when multi-factor authentication is required.
+	if response != nil && slices.ContainsFunc(response.ErrorCodes, func(code int) bool {
+		return code == 50076 || code == 50079 || code == 50074
+	}) {
+		return "Multi-factor authentication is required for this operation."
+	}
+
+	var requested struct {
+		AccessToken map[string]json.RawMessage `json:"access_token"`
+	}
+	if err := json.Unmarshal([]byte(claims), &requested); err == nil {
+		// Conditional Access policies that require an authentication context request the "acrs" claim.
+		if _, has := requested.AccessToken["acrs"]; has {
+			return "A Conditional Access policy requires additional authentication for this operation."
+		}
+	}
+
+	return "Additional authentication is required for this operation."
+}
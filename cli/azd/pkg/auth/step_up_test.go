@@ -0,0 +1,168 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingStepUpHandler struct {
+	challenges []ClaimsChallenge
+	err        error
+}
+
+func (h *recordingStepUpHandler) StepUp(_ context.Context, challenge ClaimsChallenge) error {
+	h.challenges = append(h.challenges, challenge)
+	return h.err
+}
+
+func TestStepUpReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		claims   string
+		response *AadErrorResponse
+		want     string
+	}{
+		{
+			name:     "MfaRequired",
+			claims:   `{"access_token":{"nbf":{"essential":true,"value":"1700000000"}}}`,
+			response: &AadErrorResponse{Error: "interaction_required", ErrorCodes: []int{50076}},
+			want:     "Multi-factor authentication is required for this operation.",
+		},
+		{
+			name:     "AuthenticationContext",
+			claims:   `{"access_token":{"acrs":{"essential":true,"value":"c1"}}}`,
+			response: &AadErrorResponse{Error: "interaction_required", ErrorCodes: []int{50005}},
+			want:     "A Conditional Access policy requires additional authentication for this operation.",
+		},
+		{
+			name:   "Unknown",
+			claims: `{"access_token":{"nbf":{"essential":true,"value":"1700000000"}}}`,
+			want:   "Additional authentication is required for this operation.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, stepUpReason(tt.claims, tt.response))
+		})
+	}
+}
+
+func TestClaimsChallengeLoginArgs(t *testing.T) {
+	challenge := newClaimsChallenge(
+		`{"access_token":{}}`,
+		nil,
+		append(LoginScopesFull(cloud.AzurePublic()), "api://custom/.default"),
+		cloud.AzurePublic(),
+		"tenant-1",
+	)
+
+	assert.Equal(t, []string{
+		"auth", "login",
+		"--claims", "eyJhY2Nlc3NfdG9rZW4iOnt9fQ==",
+		"--tenant-id", "tenant-1",
+		"--scope", "api://custom/.default",
+	}, challenge.LoginArgs())
+}
+
+func TestAzdCredential_GetToken_StepUp(t *testing.T) {
+	claims := `{"access_token":{"acrs":{"essential":true,"value":"c1"}}}`
+	tokenReq, err := http.NewRequest(http.MethodPost, "https://login.microsoftonline.com/common/oauth2/v2.0/token", nil)
+	require.NoError(t, err)
+
+	challengeErr := &AuthFailedError{
+		RawResp: &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Status:     "400 Bad Request",
+			Request:    tokenReq,
+			Body:       http.NoBody,
+		},
+		Parsed: &AadErrorResponse{
+			Error:      "interaction_required",
+			ErrorCodes: []int{50076},
+		},
+		innerErr: errors.New("AADSTS50076"),
+	}
+
+	newClient := func() *sequentialSilentClient {
+		return &sequentialSilentClient{
+			results: []struct {
+				result public.AuthResult
+				err    error
+			}{
+				{err: challengeErr},
+				{err: challengeErr},
+				{result: public.AuthResult{AccessToken: "stepped-up", ExpiresOn: time.Now().Add(time.Hour)}},
+			},
+		}
+	}
+
+	t.Run("SignsInAgain", func(t *testing.T) {
+		t.Setenv("AZD_CONFIG_DIR", t.TempDir())
+		handler := &recordingStepUpHandler{}
+		cred := newAzdCredential(
+			newClient(), &public.Account{HomeAccountID: "h1"}, cloud.AzurePublic(), "", nil, newStepUp(handler))
+
+		tok, err := cred.GetToken(t.Context(), policy.TokenRequestOptions{
+			Scopes: LoginScopesFull(cloud.AzurePublic()),
+			Claims: claims,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "stepped-up", tok.Token)
+
+		require.Len(t, handler.challenges, 1)
+		assert.Equal(t, claims, handler.challenges[0].Claims)
+		assert.Empty(t, handler.challenges[0].Scopes)
+		assert.Equal(t, "Multi-factor authentication is required for this operation.", handler.challenges[0].Reason)
+
+		// The claims are passed to the login directly, so they aren't saved for the next login.
+		savedClaims, _, err := loadClaims()
+		require.NoError(t, err)
+		assert.Empty(t, savedClaims)
+	})
+
+	t.Run("Unavailable", func(t *testing.T) {
+		t.Setenv("AZD_CONFIG_DIR", t.TempDir())
+		handler := &recordingStepUpHandler{err: ErrStepUpUnavailable}
+		cred := newAzdCredential(
+			newClient(), &public.Account{HomeAccountID: "h1"}, cloud.AzurePublic(), "", nil, newStepUp(handler))
+
+		_, err := cred.GetToken(t.Context(), policy.TokenRequestOptions{
+			Scopes: LoginScopesFull(cloud.AzurePublic()),
+			Claims: claims,
+		})
+
+		_, ok := errors.AsType[*ReLoginRequiredError](err)
+		require.True(t, ok)
+		require.Len(t, handler.challenges, 1)
+
+		savedClaims, _, err := loadClaims()
+		require.NoError(t, err)
+		assert.Equal(t, claims, savedClaims)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		t.Setenv("AZD_CONFIG_DIR", t.TempDir())
+		handler := &recordingStepUpHandler{err: errors.New("login failed")}
+		cred := newAzdCredential(
+			newClient(), &public.Account{HomeAccountID: "h1"}, cloud.AzurePublic(), "", nil, newStepUp(handler))
+
+		_, err := cred.GetToken(t.Context(), policy.TokenRequestOptions{
+			Scopes: LoginScopesFull(cloud.AzurePublic()),
+			Claims: claims,
+		})
+		require.ErrorContains(t, err, "login failed")
+	})
+}
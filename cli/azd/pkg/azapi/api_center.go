@@ -0,0 +1,182 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+)
+
+// apiCenterApiVersion is the Microsoft.ApiCenter API version used by azd.
+const apiCenterApiVersion = "2024-03-01"
+
+// apiCenterWorkspace is the workspace of an API Center service. API Center services only have the default workspace.
+const apiCenterWorkspace = "default"
+
+// ApiCenterService registers APIs in the inventory of an Azure API Center service, which is the developer portal
+// where the APIs of an organization are discovered and governed.
+type ApiCenterService struct {
+	credentialProvider account.SubscriptionCredentialProvider
+	armClientOptions   *arm.ClientOptions
+}
+
+// NewApiCenterService creates a new ApiCenterService.
+func NewApiCenterService(
+	credentialProvider account.SubscriptionCredentialProvider,
+	armClientOptions *arm.ClientOptions,
+) *ApiCenterService {
+	return &ApiCenterService{
+		credentialProvider: credentialProvider,
+		armClientOptions:   armClientOptions,
+	}
+}
+
+// ApiCenterApi is an API deployed to an environment, registered in an API Center service.
+type ApiCenterApi struct {
+	// Name is the name of the API, unique in the API Center service.
+	Name string
+	// Title is the display name of the API.
+	Title string
+	// Version is the name of the version of the API.
+	Version string
+	// Environment is the name of the environment the API is deployed to.
+	Environment string
+	// Specification is the OpenAPI description of the version.
+	Specification string
+	// SpecificationVersion is the version of the OpenAPI specification the description conforms to, for example 3.0.3.
+	SpecificationVersion string
+	// RuntimeUris are the endpoints of the deployed API.
+	RuntimeUris []string
+}
+
+// PublishApi registers the API, its version and its OpenAPI description in the API Center service with the resource ID
+// serviceId, and records the deployment of the API to its environment. Existing entries are updated.
+func (s *ApiCenterService) PublishApi(ctx context.Context, serviceId string, api ApiCenterApi) error {
+	resourceId, err := arm.ParseResourceID(serviceId)
+	if err != nil {
+		return fmt.Errorf("parsing API Center resource ID: %w", err)
+	}
+
+	credential, err := s.credentialProvider.CredentialForSubscription(ctx, resourceId.SubscriptionID)
+	if err != nil {
+		return fmt.Errorf("getting credential for subscription %s: %w", resourceId.SubscriptionID, err)
+	}
+
+	pipeline, err := newArmPipeline("azd-api-center", credential, s.armClientOptions)
+	if err != nil {
+		return fmt.Errorf("creating API Center pipeline: %w", err)
+	}
+
+	workspacePath := fmt.Sprintf("/workspaces/%s", apiCenterWorkspace)
+	apiPath := fmt.Sprintf("%s/apis/%s", workspacePath, api.Name)
+	definitionPath := fmt.Sprintf("%s/versions/%s/definitions/openapi", apiPath, api.Version)
+	environmentPath := fmt.Sprintf("%s/environments/%s", workspacePath, api.Environment)
+
+	requests := []struct {
+		method string
+		path   string
+		body   any
+	}{
+		{
+			method: http.MethodPut,
+			path:   apiPath,
+			body:   map[string]any{"properties": map[string]any{"title": api.Title, "kind": "rest"}},
+		},
+		{
+			method: http.MethodPut,
+			path:   fmt.Sprintf("%s/versions/%s", apiPath, api.Version),
+			body: map[string]any{
+				"properties": map[string]any{"title": api.Version, "lifecycleStage": "production"},
+			},
+		},
+		{
+			method: http.MethodPut,
+			path:   definitionPath,
+			body:   map[string]any{"properties": map[string]any{"title": "OpenAPI"}},
+		},
+		{
+			method: http.MethodPost,
+			path:   definitionPath + "/importSpecification",
+			body: map[string]any{
+				"format": "inline",
+				"value":  api.Specification,
+				"specification": map[string]any{
+					"name":    "openapi",
+					"version": api.SpecificationVersion,
+				},
+			},
+		},
+		{
+			method: http.MethodPut,
+			path:   environmentPath,
+			body: map[string]any{
+				"properties": map[string]any{"title": api.Environment, "kind": "development"},
+			},
+		},
+		{
+			method: http.MethodPut,
+			path:   fmt.Sprintf("%s/deployments/%s", apiPath, api.Environment),
+			body: map[string]any{
+				"properties": map[string]any{
+					"title":         api.Environment,
+					"environmentId": environmentPath,
+					"definitionId":  definitionPath,
+					"server":        map[string]any{"runtimeUri": api.RuntimeUris},
+				},
+			},
+		},
+	}
+
+	for _, request := range requests {
+		if err := s.send(ctx, pipeline, request.method, serviceId+request.path, request.body); err != nil {
+			return fmt.Errorf("publishing API '%s' to API Center: %w", api.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *ApiCenterService) send(
+	ctx context.Context,
+	pipeline runtime.Pipeline,
+	method string,
+	resourcePath string,
+	body any,
+) error {
+	endpoint := armEndpoint(s.armClientOptions)
+
+	requestUrl, err := url.JoinPath(endpoint, strings.TrimPrefix(resourcePath, "/"))
+	if err != nil {
+		return fmt.Errorf("building API Center request url: %w", err)
+	}
+
+	req, err := runtime.NewRequest(ctx, method, requestUrl+"?api-version="+apiCenterApiVersion)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	if err := runtime.MarshalAsJSON(req, body); err != nil {
+		return fmt.Errorf("writing request: %w", err)
+	}
+
+	response, err := pipeline.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	// Importing a specification is a long-running operation, which API Center completes in the background.
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusCreated, http.StatusAccepted) {
+		return runtime.NewResponseError(response)
+	}
+
+	return nil
+}
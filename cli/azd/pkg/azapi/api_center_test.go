@@ -0,0 +1,89 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+)
+
+const testApiCenterId = "/subscriptions/SUB/resourceGroups/RG/providers/Microsoft.ApiCenter/services/apic"
+
+func Test_ApiCenterService_PublishApi(t *testing.T) {
+	mockCtx := mocks.NewMockContext(t.Context())
+	svc := NewApiCenterService(mockCtx.SubscriptionCredentialProvider, mockCtx.ArmClientOptions)
+
+	var requests []string
+	bodies := map[string]map[string]any{}
+	mockCtx.HttpClient.When(func(req *http.Request) bool {
+		return strings.HasPrefix(req.URL.Path, testApiCenterId+"/") &&
+			req.URL.Query().Get("api-version") == apiCenterApiVersion
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		path := strings.TrimPrefix(req.URL.Path, testApiCenterId)
+		requests = append(requests, req.Method+" "+path)
+
+		contents, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(contents, &body))
+		bodies[path] = body
+
+		if req.Method == http.MethodPost {
+			return mocks.CreateEmptyHttpResponse(req, http.StatusAccepted)
+		}
+		return mocks.CreateHttpResponseWithBody(req, http.StatusOK, body)
+	})
+
+	err := svc.PublishApi(*mockCtx.Context, testApiCenterId, ApiCenterApi{
+		Name:                 "todo-api",
+		Title:                "Todo API",
+		Version:              "1-0-0",
+		Environment:          "dev",
+		Specification:        "openapi: 3.0.3",
+		SpecificationVersion: "3.0.3",
+		RuntimeUris:          []string{"https://todo.example.com"},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		"PUT /workspaces/default/apis/todo-api",
+		"PUT /workspaces/default/apis/todo-api/versions/1-0-0",
+		"PUT /workspaces/default/apis/todo-api/versions/1-0-0/definitions/openapi",
+		"POST /workspaces/default/apis/todo-api/versions/1-0-0/definitions/openapi/importSpecification",
+		"PUT /workspaces/default/environments/dev",
+		"PUT /workspaces/default/apis/todo-api/deployments/dev",
+	}, requests)
+
+	importBody := bodies["/workspaces/default/apis/todo-api/versions/1-0-0/definitions/openapi/importSpecification"]
+	require.Equal(t, "openapi: 3.0.3", importBody["value"])
+
+	deployment := bodies["/workspaces/default/apis/todo-api/deployments/dev"]["properties"].(map[string]any)
+	require.Equal(t, "/workspaces/default/environments/dev", deployment["environmentId"])
+	require.Equal(t, "/workspaces/default/apis/todo-api/versions/1-0-0/definitions/openapi", deployment["definitionId"])
+	require.Equal(t, []any{"https://todo.example.com"}, deployment["server"].(map[string]any)["runtimeUri"])
+}
+
+func Test_ApiCenterService_PublishApi_Error(t *testing.T) {
+	mockCtx := mocks.NewMockContext(t.Context())
+	svc := NewApiCenterService(mockCtx.SubscriptionCredentialProvider, mockCtx.ArmClientOptions)
+
+	mockCtx.HttpClient.When(func(req *http.Request) bool {
+		return strings.HasPrefix(req.URL.Path, testApiCenterId+"/")
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		return mocks.CreateEmptyHttpResponse(req, http.StatusForbidden)
+	})
+
+	err := svc.PublishApi(*mockCtx.Context, testApiCenterId, ApiCenterApi{Name: "todo-api"})
+	require.ErrorContains(t, err, "publishing API 'todo-api' to API Center")
+
+	err = svc.PublishApi(*mockCtx.Context, "not-a-resource-id", ApiCenterApi{Name: "todo-api"})
+	require.ErrorContains(t, err, "parsing API Center resource ID")
+}
@@ -0,0 +1,36 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/internal"
+)
+
+// newArmPipeline creates a pipeline for the ARM APIs that don't have an SDK client, authenticated with credential and
+// configured with armClientOptions like the SDK clients. moduleName identifies the caller in the user agent. Requests
+// are sent to [armEndpoint].
+func newArmPipeline(
+	moduleName string,
+	credential azcore.TokenCredential,
+	armClientOptions *arm.ClientOptions,
+) (runtime.Pipeline, error) {
+	return armruntime.NewPipeline(moduleName, internal.Version, credential, runtime.PipelineOptions{}, armClientOptions)
+}
+
+// armEndpoint returns the Resource Manager endpoint of the cloud of armClientOptions.
+func armEndpoint(armClientOptions *arm.ClientOptions) string {
+	if armClientOptions != nil {
+		if endpoint := armClientOptions.Cloud.Services[cloud.ResourceManager].Endpoint; endpoint != "" {
+			return endpoint
+		}
+	}
+
+	// Matches the default used by the ARM SDK clients when no cloud is configured
+	return cloud.AzurePublic.Services[cloud.ResourceManager].Endpoint
+}
@@ -0,0 +1,23 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_armEndpoint(t *testing.T) {
+	publicEndpoint := cloud.AzurePublic.Services[cloud.ResourceManager].Endpoint
+
+	require.Equal(t, publicEndpoint, armEndpoint(nil))
+	require.Equal(t, publicEndpoint, armEndpoint(&arm.ClientOptions{}))
+	require.Equal(t, cloud.AzureChina.Services[cloud.ResourceManager].Endpoint, armEndpoint(&arm.ClientOptions{
+		ClientOptions: policy.ClientOptions{Cloud: cloud.AzureChina},
+	}))
+}
@@ -0,0 +1,216 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+)
+
+const (
+	// appConfigDataApiVersion is the App Configuration data plane API version used to manage key-values.
+	appConfigDataApiVersion = "2023-11-01"
+
+	// FeatureFlagKeyPrefix is the prefix of the keys of feature flags in an App Configuration store.
+	FeatureFlagKeyPrefix = ".appconfig.featureflag/"
+	// FeatureFlagContentType is the content type of the key-values that hold feature flags.
+	FeatureFlagContentType = "application/vnd.microsoft.appconfig.ff+json;charset=utf-8"
+)
+
+// ErrFeatureFlagNotFound is returned when a feature flag doesn't exist in the App Configuration store.
+var ErrFeatureFlagNotFound = errors.New("feature flag not found")
+
+// FeatureFlag is a feature flag stored in an App Configuration store, following the Microsoft feature management schema.
+type FeatureFlag struct {
+	ID          string `json:"id"`
+	Description string `json:"description,omitempty"`
+	Enabled     bool   `json:"enabled"`
+	// Conditions, variants, allocation and telemetry are kept as-is, so that flags configured in the portal
+	// are not modified when they are enabled or disabled from azd.
+	Conditions json.RawMessage `json:"conditions,omitempty"`
+	Variants   json.RawMessage `json:"variants,omitempty"`
+	Allocation json.RawMessage `json:"allocation,omitempty"`
+	Telemetry  json.RawMessage `json:"telemetry,omitempty"`
+}
+
+// NewFeatureFlag creates a disabled feature flag with no conditions.
+func NewFeatureFlag(name string) *FeatureFlag {
+	return &FeatureFlag{
+		ID:         name,
+		Conditions: json.RawMessage(`{"client_filters":[]}`),
+	}
+}
+
+type appConfigKeyValue struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	ContentType string `json:"content_type"`
+}
+
+// FeatureFlagsService manages the feature flags of an App Configuration store through its data plane.
+type FeatureFlagsService struct {
+	credentialProvider account.SubscriptionCredentialProvider
+	clientOptions      *azcore.ClientOptions
+}
+
+// NewFeatureFlagsService creates a new FeatureFlagsService.
+func NewFeatureFlagsService(
+	credentialProvider account.SubscriptionCredentialProvider,
+	clientOptions *azcore.ClientOptions,
+) *FeatureFlagsService {
+	return &FeatureFlagsService{
+		credentialProvider: credentialProvider,
+		clientOptions:      clientOptions,
+	}
+}
+
+// GetFeatureFlag gets the feature flag with the given name from the store at endpoint. ErrFeatureFlagNotFound is
+// returned when the flag doesn't exist.
+func (s *FeatureFlagsService) GetFeatureFlag(
+	ctx context.Context,
+	subscriptionId string,
+	endpoint string,
+	name string,
+) (*FeatureFlag, error) {
+	pipeline, err := s.createPipeline(ctx, subscriptionId, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := runtime.NewRequest(ctx, http.MethodGet, featureFlagUrl(endpoint, name))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	response, err := pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if runtime.HasStatusCode(response, http.StatusNotFound) {
+		return nil, fmt.Errorf("'%s': %w", name, ErrFeatureFlagNotFound)
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	var keyValue appConfigKeyValue
+	if err := runtime.UnmarshalAsJSON(response, &keyValue); err != nil {
+		return nil, err
+	}
+
+	var flag FeatureFlag
+	if err := json.Unmarshal([]byte(keyValue.Value), &flag); err != nil {
+		return nil, fmt.Errorf("parsing feature flag '%s': %w", name, err)
+	}
+
+	return &flag, nil
+}
+
+// SetFeatureFlag creates or replaces the feature flag in the store at endpoint.
+func (s *FeatureFlagsService) SetFeatureFlag(
+	ctx context.Context,
+	subscriptionId string,
+	endpoint string,
+	flag *FeatureFlag,
+) error {
+	pipeline, err := s.createPipeline(ctx, subscriptionId, endpoint)
+	if err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(flag)
+	if err != nil {
+		return fmt.Errorf("serializing feature flag '%s': %w", flag.ID, err)
+	}
+
+	body, err := json.Marshal(appConfigKeyValue{
+		Key:         FeatureFlagKeyPrefix + flag.ID,
+		Value:       string(value),
+		ContentType: FeatureFlagContentType,
+	})
+	if err != nil {
+		return fmt.Errorf("serializing feature flag '%s': %w", flag.ID, err)
+	}
+
+	req, err := runtime.NewRequest(ctx, http.MethodPut, featureFlagUrl(endpoint, flag.ID))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	raw := req.Raw()
+	raw.Header.Set("Content-Type", "application/vnd.microsoft.appconfig.kv+json")
+	raw.Body = io.NopCloser(bytes.NewReader(body))
+	raw.ContentLength = int64(len(body))
+
+	response, err := pipeline.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return runtime.NewResponseError(response)
+	}
+
+	return nil
+}
+
+// createPipeline creates a pipeline authenticated for the data plane of the App Configuration store at endpoint.
+func (s *FeatureFlagsService) createPipeline(
+	ctx context.Context,
+	subscriptionId string,
+	endpoint string,
+) (runtime.Pipeline, error) {
+	credential, err := s.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return runtime.Pipeline{}, fmt.Errorf("getting credential for subscription %s: %w", subscriptionId, err)
+	}
+
+	scope, err := appConfigScope(endpoint)
+	if err != nil {
+		return runtime.Pipeline{}, err
+	}
+
+	authPolicy := runtime.NewBearerTokenPolicy(credential, []string{scope}, nil)
+	return runtime.NewPipeline("azd-feature-flags", internal.Version, runtime.PipelineOptions{
+		PerRetry: []policy.Policy{authPolicy},
+	}, s.clientOptions), nil
+}
+
+// appConfigScope returns the token scope of the App Configuration store at endpoint. The audience depends on the cloud
+// of the store, for example https://azconfig.io for https://<name>.azconfig.io.
+func appConfigScope(endpoint string) (string, error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Host == "" {
+		return "", fmt.Errorf("invalid App Configuration endpoint '%s'", endpoint)
+	}
+
+	_, domain, found := strings.Cut(parsed.Hostname(), ".")
+	if !found {
+		return "", fmt.Errorf("invalid App Configuration endpoint '%s'", endpoint)
+	}
+
+	return fmt.Sprintf("https://%s/.default", domain), nil
+}
+
+func featureFlagUrl(endpoint string, name string) string {
+	return fmt.Sprintf("%s/kv/%s?api-version=%s",
+		strings.TrimSuffix(endpoint, "/"), url.PathEscape(FeatureFlagKeyPrefix+name), appConfigDataApiVersion)
+}
@@ -0,0 +1,97 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+)
+
+const testAppConfigEndpoint = "https://appcs-test.azconfig.io"
+
+func Test_FeatureFlagsService_GetFeatureFlag(t *testing.T) {
+	mockCtx := mocks.NewMockContext(t.Context())
+	svc := NewFeatureFlagsService(mockCtx.SubscriptionCredentialProvider, mockCtx.CoreClientOptions)
+
+	mockCtx.HttpClient.When(func(req *http.Request) bool {
+		return req.Method == http.MethodGet &&
+			req.URL.Host == "appcs-test.azconfig.io" &&
+			req.URL.EscapedPath() == "/kv/.appconfig.featureflag%2Fbeta" &&
+			req.URL.Query().Get("api-version") == appConfigDataApiVersion
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(req, http.StatusOK, map[string]any{
+			"key":          ".appconfig.featureflag/beta",
+			"content_type": FeatureFlagContentType,
+			"value": `{"id":"beta","description":"Beta UI","enabled":true,` +
+				`"conditions":{"client_filters":[{"name":"Microsoft.Percentage"}]}}`,
+		})
+	})
+
+	mockCtx.HttpClient.When(func(req *http.Request) bool {
+		return req.Method == http.MethodGet && req.URL.EscapedPath() == "/kv/.appconfig.featureflag%2Fmissing"
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		return mocks.CreateEmptyHttpResponse(req, http.StatusNotFound)
+	})
+
+	flag, err := svc.GetFeatureFlag(*mockCtx.Context, "SUB", testAppConfigEndpoint, "beta")
+	require.NoError(t, err)
+	assert.Equal(t, "beta", flag.ID)
+	assert.Equal(t, "Beta UI", flag.Description)
+	assert.True(t, flag.Enabled)
+	assert.JSONEq(t, `{"client_filters":[{"name":"Microsoft.Percentage"}]}`, string(flag.Conditions))
+
+	_, err = svc.GetFeatureFlag(*mockCtx.Context, "SUB", testAppConfigEndpoint, "missing")
+	require.True(t, errors.Is(err, ErrFeatureFlagNotFound))
+}
+
+func Test_FeatureFlagsService_SetFeatureFlag(t *testing.T) {
+	mockCtx := mocks.NewMockContext(t.Context())
+	svc := NewFeatureFlagsService(mockCtx.SubscriptionCredentialProvider, mockCtx.CoreClientOptions)
+
+	var body map[string]string
+	mockCtx.HttpClient.When(func(req *http.Request) bool {
+		return req.Method == http.MethodPut && req.URL.EscapedPath() == "/kv/.appconfig.featureflag%2Fbeta"
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		raw, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(raw, &body); err != nil {
+			return nil, err
+		}
+
+		return mocks.CreateHttpResponseWithBody(req, http.StatusOK, body)
+	})
+
+	flag := NewFeatureFlag("beta")
+	flag.Enabled = true
+	err := svc.SetFeatureFlag(*mockCtx.Context, "SUB", testAppConfigEndpoint, flag)
+	require.NoError(t, err)
+
+	assert.Equal(t, ".appconfig.featureflag/beta", body["key"])
+	assert.Equal(t, FeatureFlagContentType, body["content_type"])
+	assert.JSONEq(t, `{"id":"beta","enabled":true,"conditions":{"client_filters":[]}}`, body["value"])
+}
+
+func Test_appConfigScope(t *testing.T) {
+	scope, err := appConfigScope("https://appcs-test.azconfig.io/")
+	require.NoError(t, err)
+	assert.Equal(t, "https://azconfig.io/.default", scope)
+
+	scope, err = appConfigScope("https://appcs-test.azconfig.azure.cn")
+	require.NoError(t, err)
+	assert.Equal(t, "https://azconfig.azure.cn/.default", scope)
+
+	_, err = appConfigScope("appcs-test")
+	require.Error(t, err)
+}
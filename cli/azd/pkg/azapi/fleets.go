@@ -0,0 +1,196 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+)
+
+// fleetsApiVersion is the Microsoft.ContainerService/fleets API version used by azd.
+const fleetsApiVersion = "2024-04-01"
+
+// FleetsService provides actions on top of Azure Kubernetes Fleet Manager resources
+type FleetsService interface {
+	// Gets the credentials for the fleet hub cluster
+	GetCredentials(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		fleetName string,
+	) (*armcontainerservice.CredentialResults, error)
+	// Lists the member clusters joined to the fleet
+	ListMembers(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		fleetName string,
+	) ([]*FleetMember, error)
+}
+
+// FleetMember is a member cluster of an Azure Kubernetes Fleet Manager resource.
+type FleetMember struct {
+	Id         string                `json:"id"`
+	Name       string                `json:"name"`
+	Properties FleetMemberProperties `json:"properties"`
+}
+
+type FleetMemberProperties struct {
+	// The ARM resource id of the member AKS cluster
+	ClusterResourceId string `json:"clusterResourceId"`
+	// The update group the member belongs to. Empty when the member is not assigned to a group.
+	Group string `json:"group"`
+}
+
+type fleetMemberList struct {
+	Value    []*FleetMember `json:"value"`
+	NextLink string         `json:"nextLink"`
+}
+
+type fleetsService struct {
+	credentialProvider account.SubscriptionCredentialProvider
+	armClientOptions   *arm.ClientOptions
+}
+
+// Creates a new instance of the FleetsService
+func NewFleetsService(
+	credentialProvider account.SubscriptionCredentialProvider,
+	armClientOptions *arm.ClientOptions,
+) FleetsService {
+	return &fleetsService{
+		credentialProvider: credentialProvider,
+		armClientOptions:   armClientOptions,
+	}
+}
+
+// Gets the credentials for the fleet hub cluster
+func (fs *fleetsService) GetCredentials(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	fleetName string,
+) (*armcontainerservice.CredentialResults, error) {
+	pipeline, err := fs.createPipeline(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	requestUrl, err := fs.fleetUrl(subscriptionId, resourceGroupName, fleetName, "listCredentials")
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := runtime.NewRequest(ctx, http.MethodPost, requestUrl)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	response, err := pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	var credentials armcontainerservice.CredentialResults
+	if err := runtime.UnmarshalAsJSON(response, &credentials); err != nil {
+		return nil, fmt.Errorf("reading fleet credentials: %w", err)
+	}
+
+	return &credentials, nil
+}
+
+// Lists the member clusters joined to the fleet
+func (fs *fleetsService) ListMembers(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	fleetName string,
+) ([]*FleetMember, error) {
+	pipeline, err := fs.createPipeline(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	requestUrl, err := fs.fleetUrl(subscriptionId, resourceGroupName, fleetName, "members")
+	if err != nil {
+		return nil, err
+	}
+
+	members := []*FleetMember{}
+	for requestUrl != "" {
+		req, err := runtime.NewRequest(ctx, http.MethodGet, requestUrl)
+		if err != nil {
+			return nil, fmt.Errorf("creating request: %w", err)
+		}
+
+		response, err := pipeline.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if !runtime.HasStatusCode(response, http.StatusOK) {
+			defer response.Body.Close()
+			return nil, runtime.NewResponseError(response)
+		}
+
+		body, err := runtime.Payload(response)
+		response.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		var page fleetMemberList
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("reading fleet members: %w", err)
+		}
+
+		members = append(members, page.Value...)
+		requestUrl = page.NextLink
+	}
+
+	return members, nil
+}
+
+func (fs *fleetsService) fleetUrl(subscriptionId, resourceGroupName, fleetName, action string) (string, error) {
+	endpoint := armEndpoint(fs.armClientOptions)
+
+	requestUrl, err := url.JoinPath(
+		endpoint,
+		"subscriptions", subscriptionId,
+		"resourceGroups", resourceGroupName,
+		"providers/Microsoft.ContainerService/fleets", fleetName,
+		action,
+	)
+	if err != nil {
+		return "", fmt.Errorf("building fleet request url: %w", err)
+	}
+
+	return requestUrl + "?api-version=" + fleetsApiVersion, nil
+}
+
+func (fs *fleetsService) createPipeline(ctx context.Context, subscriptionId string) (runtime.Pipeline, error) {
+	credential, err := fs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return runtime.Pipeline{}, err
+	}
+
+	pipeline, err := newArmPipeline("azd-fleets", credential, fs.armClientOptions)
+	if err != nil {
+		return runtime.Pipeline{}, fmt.Errorf("creating fleets pipeline: %w", err)
+	}
+
+	return pipeline, nil
+}
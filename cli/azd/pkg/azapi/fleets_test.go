@@ -0,0 +1,91 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+)
+
+func Test_FleetsService_GetCredentials(t *testing.T) {
+	mockCtx := mocks.NewMockContext(t.Context())
+	svc := NewFleetsService(mockCtx.SubscriptionCredentialProvider, mockCtx.ArmClientOptions)
+
+	mockCtx.HttpClient.When(func(req *http.Request) bool {
+		return req.Method == http.MethodPost &&
+			strings.HasSuffix(req.URL.Path, "/providers/Microsoft.ContainerService/fleets/my-fleet/listCredentials") &&
+			req.URL.Query().Get("api-version") == fleetsApiVersion
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(req, http.StatusOK,
+			armcontainerservice.CredentialResults{
+				Kubeconfigs: []*armcontainerservice.CredentialResult{
+					{
+						Name:  new("hub"),
+						Value: []byte("kubeconfig-data"),
+					},
+				},
+			})
+	})
+
+	creds, err := svc.GetCredentials(*mockCtx.Context, "SUB", "RG", "my-fleet")
+	require.NoError(t, err)
+	require.Len(t, creds.Kubeconfigs, 1)
+	assert.Equal(t, []byte("kubeconfig-data"), creds.Kubeconfigs[0].Value)
+}
+
+func Test_FleetsService_GetCredentials_Error(t *testing.T) {
+	mockCtx := mocks.NewMockContext(t.Context())
+	svc := NewFleetsService(mockCtx.SubscriptionCredentialProvider, mockCtx.ArmClientOptions)
+
+	mockCtx.HttpClient.When(func(req *http.Request) bool {
+		return strings.Contains(req.URL.Path, "/fleets/my-fleet/listCredentials")
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		return mocks.CreateEmptyHttpResponse(req, http.StatusForbidden)
+	})
+
+	_, err := svc.GetCredentials(*mockCtx.Context, "SUB", "RG", "my-fleet")
+	require.Error(t, err)
+}
+
+func Test_FleetsService_ListMembers(t *testing.T) {
+	mockCtx := mocks.NewMockContext(t.Context())
+	svc := NewFleetsService(mockCtx.SubscriptionCredentialProvider, mockCtx.ArmClientOptions)
+
+	mockCtx.HttpClient.When(func(req *http.Request) bool {
+		return req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "/fleets/my-fleet/members")
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(req, http.StatusOK, fleetMemberList{
+			Value: []*FleetMember{
+				{Name: "east", Properties: FleetMemberProperties{ClusterResourceId: "/clusters/east", Group: "canary"}},
+			},
+			NextLink: "https://management.azure.com/subscriptions/SUB/resourceGroups/RG" +
+				"/providers/Microsoft.ContainerService/fleets/my-fleet/members/page2",
+		})
+	})
+
+	mockCtx.HttpClient.When(func(req *http.Request) bool {
+		return req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "/fleets/my-fleet/members/page2")
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(req, http.StatusOK, fleetMemberList{
+			Value: []*FleetMember{
+				{Name: "west", Properties: FleetMemberProperties{ClusterResourceId: "/clusters/west", Group: "prod"}},
+			},
+		})
+	})
+
+	members, err := svc.ListMembers(*mockCtx.Context, "SUB", "RG", "my-fleet")
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+	assert.Equal(t, "east", members[0].Name)
+	assert.Equal(t, "canary", members[0].Properties.Group)
+	assert.Equal(t, "west", members[1].Name)
+	assert.Equal(t, "prod", members[1].Properties.Group)
+}
@@ -0,0 +1,277 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+)
+
+// flexConsumptionApiVersion is the Microsoft.Web/sites API version that exposes the function app configuration of
+// Flex Consumption apps, which isn't modeled by the App Service SDK used by azd.
+const flexConsumptionApiVersion = "2023-12-01"
+
+// hostRuntimeApiVersion is the API version of the function host runtime endpoints proxied by ARM.
+const hostRuntimeApiVersion = "2022-03-01"
+
+// The values of the deployment storage types and authentication types of Flex Consumption apps
+const (
+	FunctionAppDeploymentStorageTypeBlobContainer = "blobContainer"
+
+	FunctionAppStorageAuthenticationSystemAssignedIdentity         = "SystemAssignedIdentity"
+	FunctionAppStorageAuthenticationUserAssignedIdentity           = "UserAssignedIdentity"
+	FunctionAppStorageAuthenticationStorageAccountConnectionString = "StorageAccountConnectionString"
+)
+
+// FunctionAppDeploymentStorage is the storage a Flex Consumption app reads its deployment package from.
+type FunctionAppDeploymentStorage struct {
+	// The type of the storage, always 'blobContainer'
+	Type string `json:"type"`
+	// The URL of the blob container
+	Value string `json:"value"`
+	// How the app authenticates to the storage
+	Authentication *FunctionAppStorageAuthentication `json:"authentication,omitempty"`
+}
+
+// FunctionAppStorageAuthentication is how a Flex Consumption app authenticates to its deployment storage.
+type FunctionAppStorageAuthentication struct {
+	// One of 'SystemAssignedIdentity', 'UserAssignedIdentity' or 'StorageAccountConnectionString'
+	Type string `json:"type"`
+	// The resource ID of the user-assigned identity, when Type is 'UserAssignedIdentity'
+	UserAssignedIdentityResourceId string `json:"userAssignedIdentityResourceId,omitempty"`
+	// The name of the app setting holding the connection string, when Type is 'StorageAccountConnectionString'
+	StorageAccountConnectionStringName string `json:"storageAccountConnectionStringName,omitempty"`
+}
+
+// FunctionAppHostStatus is the status of the function host of a function app.
+type FunctionAppHostStatus struct {
+	// The state of the host, for example 'Running' or 'Error'
+	State string `json:"state"`
+	// The version of the host runtime
+	Version string `json:"version"`
+	// The errors that prevent the host from starting
+	Errors []string `json:"errors"`
+}
+
+// The states of the function host
+const (
+	FunctionAppHostStateRunning = "Running"
+	FunctionAppHostStateError   = "Error"
+)
+
+// flexConsumptionSite is the subset of a Microsoft.Web/sites resource read and written for Flex Consumption apps.
+// The function app configuration is kept as raw JSON so that the properties azd doesn't know about are preserved
+// when it's written back.
+type flexConsumptionSite struct {
+	Properties struct {
+		FunctionAppConfig map[string]any `json:"functionAppConfig,omitempty"`
+	} `json:"properties"`
+}
+
+// GetFunctionAppDeploymentStorage returns the deployment storage of a Flex Consumption app, or nil when the app has no
+// deployment storage configured.
+func (cli *AzureClient) GetFunctionAppDeploymentStorage(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+) (*FunctionAppDeploymentStorage, error) {
+	site, err := cli.getFlexConsumptionSite(ctx, subscriptionId, resourceGroup, appName)
+	if err != nil {
+		return nil, err
+	}
+
+	deployment, _ := site.Properties.FunctionAppConfig["deployment"].(map[string]any)
+	if deployment == nil || deployment["storage"] == nil {
+		return nil, nil
+	}
+
+	// Round trip through JSON to read the raw storage configuration into its model
+	storageJson, err := json.Marshal(deployment["storage"])
+	if err != nil {
+		return nil, err
+	}
+
+	var storage FunctionAppDeploymentStorage
+	if err := json.Unmarshal(storageJson, &storage); err != nil {
+		return nil, fmt.Errorf("reading deployment storage of function app %s: %w", appName, err)
+	}
+
+	if storage.Value == "" {
+		return nil, nil
+	}
+
+	return &storage, nil
+}
+
+// UpdateFunctionAppDeploymentStorage sets the deployment storage of a Flex Consumption app. The rest of the function
+// app configuration, such as the runtime and the scale settings, is preserved.
+func (cli *AzureClient) UpdateFunctionAppDeploymentStorage(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	storage *FunctionAppDeploymentStorage,
+) error {
+	site, err := cli.getFlexConsumptionSite(ctx, subscriptionId, resourceGroup, appName)
+	if err != nil {
+		return err
+	}
+
+	functionAppConfig := site.Properties.FunctionAppConfig
+	if functionAppConfig == nil {
+		functionAppConfig = map[string]any{}
+	}
+
+	deployment, _ := functionAppConfig["deployment"].(map[string]any)
+	if deployment == nil {
+		deployment = map[string]any{}
+	}
+	deployment["storage"] = storage
+	functionAppConfig["deployment"] = deployment
+
+	patch := flexConsumptionSite{}
+	patch.Properties.FunctionAppConfig = functionAppConfig
+	body, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+
+	requestUrl, err := cli.functionAppUrl(subscriptionId, resourceGroup, appName, "", flexConsumptionApiVersion)
+	if err != nil {
+		return err
+	}
+
+	response, err := cli.doFunctionAppRequest(ctx, subscriptionId, http.MethodPatch, requestUrl, body)
+	if err != nil {
+		return fmt.Errorf("updating deployment storage of function app %s: %w", appName, err)
+	}
+	defer response.Body.Close()
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusAccepted) {
+		return fmt.Errorf(
+			"updating deployment storage of function app %s: %w", appName, runtime.NewResponseError(response))
+	}
+
+	return nil
+}
+
+// GetFunctionAppHostStatus returns the status of the function host of a function app.
+func (cli *AzureClient) GetFunctionAppHostStatus(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+) (*FunctionAppHostStatus, error) {
+	requestUrl, err := cli.functionAppUrl(
+		subscriptionId, resourceGroup, appName, "hostruntime/admin/host/status", hostRuntimeApiVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := cli.doFunctionAppRequest(ctx, subscriptionId, http.MethodGet, requestUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	var status FunctionAppHostStatus
+	if err := runtime.UnmarshalAsJSON(response, &status); err != nil {
+		return nil, fmt.Errorf("reading host status of function app %s: %w", appName, err)
+	}
+
+	return &status, nil
+}
+
+func (cli *AzureClient) getFlexConsumptionSite(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+) (*flexConsumptionSite, error) {
+	requestUrl, err := cli.functionAppUrl(subscriptionId, resourceGroup, appName, "", flexConsumptionApiVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := cli.doFunctionAppRequest(ctx, subscriptionId, http.MethodGet, requestUrl, nil)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving function app %s: %w", appName, err)
+	}
+	defer response.Body.Close()
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, fmt.Errorf("retrieving function app %s: %w", appName, runtime.NewResponseError(response))
+	}
+
+	var site flexConsumptionSite
+	if err := runtime.UnmarshalAsJSON(response, &site); err != nil {
+		return nil, fmt.Errorf("reading function app %s: %w", appName, err)
+	}
+
+	return &site, nil
+}
+
+func (cli *AzureClient) functionAppUrl(
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	path string,
+	apiVersion string,
+) (string, error) {
+	requestUrl, err := url.JoinPath(
+		armEndpoint(cli.armClientOptions),
+		"subscriptions", subscriptionId,
+		"resourceGroups", resourceGroup,
+		"providers/Microsoft.Web/sites", appName,
+		path,
+	)
+	if err != nil {
+		return "", fmt.Errorf("building function app request url: %w", err)
+	}
+
+	return requestUrl + "?api-version=" + apiVersion, nil
+}
+
+func (cli *AzureClient) doFunctionAppRequest(
+	ctx context.Context,
+	subscriptionId string,
+	method string,
+	requestUrl string,
+	body []byte,
+) (*http.Response, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline, err := newArmPipeline("azd-functions", credential, cli.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating functions pipeline: %w", err)
+	}
+
+	req, err := runtime.NewRequest(ctx, method, requestUrl)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	if body != nil {
+		if err := req.SetBody(streaming.NopCloser(bytes.NewReader(body)), "application/json"); err != nil {
+			return nil, fmt.Errorf("setting request body: %w", err)
+		}
+	}
+
+	return pipeline.Do(req)
+}
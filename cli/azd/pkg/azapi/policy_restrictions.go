@@ -0,0 +1,151 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+)
+
+// policyInsightsApiVersion is the Microsoft.PolicyInsights API version used by azd.
+const policyInsightsApiVersion = "2022-03-01"
+
+// PolicyRestrictionsService evaluates resources against the Azure Policy assignments of a scope before they are
+// deployed, with the checkPolicyRestrictions API of Microsoft.PolicyInsights.
+type PolicyRestrictionsService struct {
+	credentialProvider account.SubscriptionCredentialProvider
+	armClientOptions   *arm.ClientOptions
+}
+
+// NewPolicyRestrictionsService creates a new PolicyRestrictionsService.
+func NewPolicyRestrictionsService(
+	credentialProvider account.SubscriptionCredentialProvider,
+	armClientOptions *arm.ClientOptions,
+) *PolicyRestrictionsService {
+	return &PolicyRestrictionsService{
+		credentialProvider: credentialProvider,
+		armClientOptions:   armClientOptions,
+	}
+}
+
+// PolicyResourceDetails describes a resource to evaluate against the policy assignments of a scope.
+type PolicyResourceDetails struct {
+	// ResourceContent is the resource as it would be sent to Azure Resource Manager, with at least its type.
+	ResourceContent map[string]any `json:"resourceContent"`
+	// ApiVersion is the API version of the resource content.
+	ApiVersion string `json:"apiVersion,omitempty"`
+	// Scope is the scope the resource is created in, for example the ID of a resource group.
+	Scope string `json:"scope,omitempty"`
+}
+
+// PolicyEvaluation is the evaluation of a resource against a policy.
+type PolicyEvaluation struct {
+	PolicyInfo       PolicyReference     `json:"policyInfo"`
+	EvaluationResult string              `json:"evaluationResult"`
+	EffectDetails    PolicyEffectDetails `json:"effectDetails"`
+}
+
+// PolicyReference identifies the policy and the policy assignment of an evaluation.
+type PolicyReference struct {
+	PolicyDefinitionId          string `json:"policyDefinitionId"`
+	PolicySetDefinitionId       string `json:"policySetDefinitionId"`
+	PolicyDefinitionReferenceId string `json:"policyDefinitionReferenceId"`
+	PolicyAssignmentId          string `json:"policyAssignmentId"`
+}
+
+// PolicyEffectDetails is the effect of a policy on a resource.
+type PolicyEffectDetails struct {
+	PolicyEffect string `json:"policyEffect"`
+}
+
+// IsDenied reports whether the policy denies the creation of the resource.
+func (e *PolicyEvaluation) IsDenied() bool {
+	return strings.EqualFold(e.EvaluationResult, "NonCompliant") &&
+		strings.EqualFold(e.EffectDetails.PolicyEffect, "Deny")
+}
+
+type checkPolicyRestrictionsRequest struct {
+	ResourceDetails    PolicyResourceDetails `json:"resourceDetails"`
+	IncludeAuditEffect bool                  `json:"includeAuditEffect"`
+}
+
+type checkPolicyRestrictionsResponse struct {
+	ContentEvaluationResult struct {
+		PolicyEvaluations []*PolicyEvaluation `json:"policyEvaluations"`
+	} `json:"contentEvaluationResult"`
+}
+
+// CheckRestrictions evaluates the resource against the policy assignments of the subscription, or of the resource
+// group when resourceGroupName isn't empty, and returns the evaluations of the policies that apply to the resource.
+func (s *PolicyRestrictionsService) CheckRestrictions(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	resource PolicyResourceDetails,
+) ([]*PolicyEvaluation, error) {
+	credential, err := s.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, fmt.Errorf("getting credential for subscription %s: %w", subscriptionId, err)
+	}
+
+	pipeline, err := newArmPipeline("azd-policy-restrictions", credential, s.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating policy restrictions pipeline: %w", err)
+	}
+
+	requestUrl, err := s.checkRestrictionsUrl(subscriptionId, resourceGroupName)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := runtime.NewRequest(ctx, http.MethodPost, requestUrl)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	if err := runtime.MarshalAsJSON(req, checkPolicyRestrictionsRequest{ResourceDetails: resource}); err != nil {
+		return nil, fmt.Errorf("writing policy restrictions request: %w", err)
+	}
+
+	response, err := pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	var result checkPolicyRestrictionsResponse
+	if err := runtime.UnmarshalAsJSON(response, &result); err != nil {
+		return nil, fmt.Errorf("reading policy restrictions: %w", err)
+	}
+
+	return result.ContentEvaluationResult.PolicyEvaluations, nil
+}
+
+func (s *PolicyRestrictionsService) checkRestrictionsUrl(subscriptionId, resourceGroupName string) (string, error) {
+	endpoint := armEndpoint(s.armClientOptions)
+
+	segments := []string{"subscriptions", subscriptionId}
+	if resourceGroupName != "" {
+		segments = append(segments, "resourceGroups", resourceGroupName)
+	}
+	segments = append(segments, "providers/Microsoft.PolicyInsights/checkPolicyRestrictions")
+
+	requestUrl, err := url.JoinPath(endpoint, segments...)
+	if err != nil {
+		return "", fmt.Errorf("building policy restrictions request url: %w", err)
+	}
+
+	return requestUrl + "?api-version=" + policyInsightsApiVersion, nil
+}
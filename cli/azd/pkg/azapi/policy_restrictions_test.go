@@ -0,0 +1,108 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+)
+
+func Test_PolicyRestrictionsService_CheckRestrictions(t *testing.T) {
+	mockCtx := mocks.NewMockContext(t.Context())
+	svc := NewPolicyRestrictionsService(mockCtx.SubscriptionCredentialProvider, mockCtx.ArmClientOptions)
+
+	var body checkPolicyRestrictionsRequest
+	mockCtx.HttpClient.When(func(req *http.Request) bool {
+		return req.Method == http.MethodPost &&
+			strings.HasSuffix(req.URL.Path,
+				"/subscriptions/SUB/resourceGroups/RG/providers/Microsoft.PolicyInsights/checkPolicyRestrictions") &&
+			req.URL.Query().Get("api-version") == policyInsightsApiVersion
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		contents, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(contents, &body))
+
+		return mocks.CreateHttpResponseWithBody(req, http.StatusOK, map[string]any{
+			"contentEvaluationResult": map[string]any{
+				"policyEvaluations": []map[string]any{
+					{
+						"policyInfo": map[string]any{
+							"policyAssignmentId": "/subscriptions/SUB/providers/" +
+								"Microsoft.Authorization/policyAssignments/allowed-locations",
+						},
+						"evaluationResult": "NonCompliant",
+						"effectDetails":    map[string]any{"policyEffect": "Deny"},
+					},
+					{
+						"evaluationResult": "NonCompliant",
+						"effectDetails":    map[string]any{"policyEffect": "Audit"},
+					},
+					{
+						"evaluationResult": "Compliant",
+						"effectDetails":    map[string]any{"policyEffect": "Deny"},
+					},
+				},
+			},
+		})
+	})
+
+	evaluations, err := svc.CheckRestrictions(*mockCtx.Context, "SUB", "RG", PolicyResourceDetails{
+		ResourceContent: map[string]any{"type": "Microsoft.Storage/storageAccounts", "location": "westus"},
+		ApiVersion:      "2023-01-01",
+		Scope:           "/subscriptions/SUB/resourceGroups/RG",
+	})
+	require.NoError(t, err)
+	require.Len(t, evaluations, 3)
+	assert.True(t, evaluations[0].IsDenied())
+	assert.False(t, evaluations[1].IsDenied())
+	assert.False(t, evaluations[2].IsDenied())
+	assert.Equal(t,
+		"/subscriptions/SUB/providers/Microsoft.Authorization/policyAssignments/allowed-locations",
+		evaluations[0].PolicyInfo.PolicyAssignmentId)
+
+	assert.Equal(t, "Microsoft.Storage/storageAccounts", body.ResourceDetails.ResourceContent["type"])
+	assert.Equal(t, "2023-01-01", body.ResourceDetails.ApiVersion)
+	assert.False(t, body.IncludeAuditEffect)
+}
+
+func Test_PolicyRestrictionsService_CheckRestrictions_Subscription(t *testing.T) {
+	mockCtx := mocks.NewMockContext(t.Context())
+	svc := NewPolicyRestrictionsService(mockCtx.SubscriptionCredentialProvider, mockCtx.ArmClientOptions)
+
+	mockCtx.HttpClient.When(func(req *http.Request) bool {
+		return strings.HasSuffix(req.URL.Path, "/subscriptions/SUB/providers/Microsoft.PolicyInsights/checkPolicyRestrictions")
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(req, http.StatusOK, map[string]any{})
+	})
+
+	evaluations, err := svc.CheckRestrictions(*mockCtx.Context, "SUB", "", PolicyResourceDetails{
+		ResourceContent: map[string]any{"type": "Microsoft.Resources/resourceGroups"},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, evaluations)
+}
+
+func Test_PolicyRestrictionsService_CheckRestrictions_Error(t *testing.T) {
+	mockCtx := mocks.NewMockContext(t.Context())
+	svc := NewPolicyRestrictionsService(mockCtx.SubscriptionCredentialProvider, mockCtx.ArmClientOptions)
+
+	mockCtx.HttpClient.When(func(req *http.Request) bool {
+		return strings.Contains(req.URL.Path, "/checkPolicyRestrictions")
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		return mocks.CreateEmptyHttpResponse(req, http.StatusForbidden)
+	})
+
+	_, err := svc.CheckRestrictions(*mockCtx.Context, "SUB", "RG", PolicyResourceDetails{
+		ResourceContent: map[string]any{"type": "Microsoft.Storage/storageAccounts"},
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,622 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/keyvault/armkeyvault"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+)
+
+// PurgeTarget is a resource that's kept in a soft-deleted state after it's deleted, until it's purged. While it's
+// soft-deleted, its name can't be reused.
+type PurgeTarget struct {
+	Id             string
+	SubscriptionId string
+	ResourceGroup  string
+	Name           string
+	Location       string
+	ResourceType   AzureResourceType
+	// DisplayType is the user facing name of the type of the resource, for example "Key Vault".
+	DisplayType string
+	// Deleted is set when the resource was already soft-deleted when it was discovered.
+	Deleted bool
+	// BeforeDelete is set when the resource is purged by force deleting it, before its resource group is deleted.
+	BeforeDelete bool
+}
+
+// Purger discovers and purges the soft-deleted resources of a resource type.
+type Purger interface {
+	// ResourceType returns the type of the resources the purger purges.
+	ResourceType() AzureResourceType
+	// Target returns the target to purge once the resource is deleted, or nil when the resource isn't soft-deleted
+	// or is protected from purging.
+	Target(ctx context.Context, resourceGroup string, resource *Resource) (*PurgeTarget, error)
+	// Deleted returns the soft-deleted resources of the subscription that can be purged.
+	Deleted(ctx context.Context, subscriptionId string) ([]*PurgeTarget, error)
+	// Purge permanently deletes the resource of the target.
+	Purge(ctx context.Context, target *PurgeTarget) error
+}
+
+// PurgeService discovers the resources that are soft-deleted when they're deleted, and purges them with the purger
+// registered for their resource type.
+type PurgeService struct {
+	purgers []Purger
+}
+
+// NewPurgeService creates a purge service with the purgers of Key Vaults, Managed HSMs, App Configuration stores, API
+// Management services, Cognitive Services accounts and Log Analytics workspaces.
+func NewPurgeService(azureClient *AzureClient) *PurgeService {
+	return &PurgeService{
+		purgers: []Purger{
+			&keyVaultPurger{cli: azureClient},
+			&managedHSMPurger{cli: azureClient},
+			&appConfigPurger{cli: azureClient},
+			&apimPurger{cli: azureClient},
+			&cognitiveAccountPurger{cli: azureClient},
+			&logAnalyticsWorkspacePurger{cli: azureClient},
+		},
+	}
+}
+
+// Register registers the purger, replacing the purger registered for the same resource type.
+func (s *PurgeService) Register(purger Purger) {
+	index := slices.IndexFunc(s.purgers, func(registered Purger) bool {
+		return registered.ResourceType() == purger.ResourceType()
+	})
+	if index < 0 {
+		s.purgers = append(s.purgers, purger)
+		return
+	}
+
+	s.purgers[index] = purger
+}
+
+// Targets returns the targets to purge once the resources grouped by resource group are deleted, in the order of the
+// registered purgers.
+func (s *PurgeService) Targets(
+	ctx context.Context,
+	groupedResources map[string][]*Resource,
+) ([]*PurgeTarget, error) {
+	targets := []*PurgeTarget{}
+	for _, purger := range s.purgers {
+		for _, resourceGroup := range slices.Sorted(maps.Keys(groupedResources)) {
+			for _, resource := range groupedResources[resourceGroup] {
+				if !strings.EqualFold(resource.Type, string(purger.ResourceType())) {
+					continue
+				}
+
+				target, err := purger.Target(ctx, resourceGroup, resource)
+				if err != nil {
+					return nil, fmt.Errorf("getting %s to purge: %w", resource.Name, err)
+				}
+
+				if target != nil {
+					targets = append(targets, target)
+				}
+			}
+		}
+	}
+
+	return targets, nil
+}
+
+// DeletedTargets returns the soft-deleted resources of the resource groups in the subscription, for example the
+// resources left by a previous deletion that didn't purge them. Discovery is best effort: the resource types whose
+// soft-deleted resources can't be listed are skipped.
+func (s *PurgeService) DeletedTargets(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroups []string,
+) []*PurgeTarget {
+	targets := []*PurgeTarget{}
+	for _, purger := range s.purgers {
+		deleted, err := purger.Deleted(ctx, subscriptionId)
+		if err != nil {
+			log.Printf("listing soft-deleted %s resources: %v", purger.ResourceType(), err)
+			continue
+		}
+
+		for _, target := range deleted {
+			if slices.ContainsFunc(resourceGroups, func(resourceGroup string) bool {
+				return strings.EqualFold(resourceGroup, target.ResourceGroup)
+			}) {
+				target.Deleted = true
+				targets = append(targets, target)
+			}
+		}
+	}
+
+	return targets
+}
+
+// Purge purges the target with the purger registered for its resource type.
+func (s *PurgeService) Purge(ctx context.Context, target *PurgeTarget) error {
+	index := slices.IndexFunc(s.purgers, func(purger Purger) bool {
+		return purger.ResourceType() == target.ResourceType
+	})
+	if index < 0 {
+		return fmt.Errorf("no purger registered for resource type %s", target.ResourceType)
+	}
+
+	return s.purgers[index].Purge(ctx, target)
+}
+
+// deletedTarget returns the target of a soft-deleted resource from the ID of the resource before it was deleted.
+func deletedTarget(
+	resourceType AzureResourceType,
+	displayType string,
+	resourceId *string,
+	location *string,
+) (*PurgeTarget, bool) {
+	if resourceId == nil || location == nil {
+		return nil, false
+	}
+
+	id, err := arm.ParseResourceID(*resourceId)
+	if err != nil {
+		return nil, false
+	}
+
+	return &PurgeTarget{
+		Id:             *resourceId,
+		SubscriptionId: id.SubscriptionID,
+		ResourceGroup:  id.ResourceGroupName,
+		Name:           id.Name,
+		Location:       *location,
+		ResourceType:   resourceType,
+		DisplayType:    displayType,
+	}, true
+}
+
+// Azure Key Vaults have a "soft delete" functionality (now enabled by default) where a vault may be marked such that
+// when it is deleted it can be recovered for a period of time. During that time, the name may not be reused.
+//
+// This means that running `azd provision`, then `azd down` and finally `azd provision` again would lead to a
+// deployment error since the vault name is in use.
+//
+// See https://learn.microsoft.com/azure/key-vault/general/soft-delete-overview for more information on this feature.
+type keyVaultPurger struct {
+	cli *AzureClient
+}
+
+func (p *keyVaultPurger) ResourceType() AzureResourceType {
+	return AzureResourceTypeKeyVault
+}
+
+func (p *keyVaultPurger) Target(ctx context.Context, resourceGroup string, resource *Resource) (*PurgeTarget, error) {
+	client, err := p.cli.createKeyVaultClient(ctx, azure.SubscriptionFromRID(resource.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	vault, err := client.Get(ctx, resourceGroup, resource.Name, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting key vault: %w", err)
+	}
+
+	if vault.Properties == nil ||
+		!convert.ToValueWithDefault(vault.Properties.EnableSoftDelete, false) ||
+		convert.ToValueWithDefault(vault.Properties.EnablePurgeProtection, false) {
+		return nil, nil
+	}
+
+	return &PurgeTarget{
+		Id:             *vault.ID,
+		SubscriptionId: azure.SubscriptionFromRID(*vault.ID),
+		ResourceGroup:  resourceGroup,
+		Name:           *vault.Name,
+		Location:       *vault.Location,
+		ResourceType:   AzureResourceTypeKeyVault,
+		DisplayType:    "Key Vault",
+	}, nil
+}
+
+func (p *keyVaultPurger) Deleted(ctx context.Context, subscriptionId string) ([]*PurgeTarget, error) {
+	client, err := p.cli.createKeyVaultClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := []*PurgeTarget{}
+	pager := client.NewListDeletedPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing deleted key vaults: %w", err)
+		}
+
+		for _, vault := range page.Value {
+			if vault.Properties == nil || convert.ToValueWithDefault(vault.Properties.PurgeProtectionEnabled, false) {
+				continue
+			}
+
+			if target, ok := deletedTarget(
+				AzureResourceTypeKeyVault, "Key Vault", vault.Properties.VaultID, vault.Properties.Location); ok {
+				targets = append(targets, target)
+			}
+		}
+	}
+
+	return targets, nil
+}
+
+func (p *keyVaultPurger) Purge(ctx context.Context, target *PurgeTarget) error {
+	client, err := p.cli.createKeyVaultClient(ctx, target.SubscriptionId)
+	if err != nil {
+		return err
+	}
+
+	poller, err := client.BeginPurgeDeleted(ctx, target.Name, target.Location, nil)
+	if err != nil {
+		if respErr, ok := errors.AsType[*azcore.ResponseError](err); ok && respErr.StatusCode == http.StatusNotFound {
+			// no need to purge if the vault is already deleted (not found)
+			log.Printf("key vault '%s' was not found. No need to purge.", target.Name)
+			return nil
+		}
+		return fmt.Errorf("starting purging key vault: %w", err)
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return fmt.Errorf("purging key vault: %w", err)
+	}
+
+	return nil
+}
+
+// Creates a Key Vault client for ARM control plane operations
+func (cli *AzureClient) createKeyVaultClient(
+	ctx context.Context,
+	subscriptionId string,
+) (*armkeyvault.VaultsClient, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := armkeyvault.NewVaultsClient(subscriptionId, credential, cli.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating Resource client: %w", err)
+	}
+
+	return client, nil
+}
+
+// managedHSMPurger purges Managed HSMs, which are soft-deleted like Key Vaults.
+type managedHSMPurger struct {
+	cli *AzureClient
+}
+
+func (p *managedHSMPurger) ResourceType() AzureResourceType {
+	return AzureResourceTypeManagedHSM
+}
+
+func (p *managedHSMPurger) Target(ctx context.Context, resourceGroup string, resource *Resource) (*PurgeTarget, error) {
+	managedHSM, err := p.cli.GetManagedHSM(ctx, azure.SubscriptionFromRID(resource.Id), resourceGroup, resource.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !managedHSM.Properties.EnableSoftDelete || managedHSM.Properties.EnablePurgeProtection {
+		return nil, nil
+	}
+
+	return &PurgeTarget{
+		Id:             managedHSM.Id,
+		SubscriptionId: azure.SubscriptionFromRID(managedHSM.Id),
+		ResourceGroup:  resourceGroup,
+		Name:           managedHSM.Name,
+		Location:       managedHSM.Location,
+		ResourceType:   AzureResourceTypeManagedHSM,
+		DisplayType:    "Managed HSM",
+	}, nil
+}
+
+func (p *managedHSMPurger) Deleted(ctx context.Context, subscriptionId string) ([]*PurgeTarget, error) {
+	client, err := p.cli.createManagedHSMClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := []*PurgeTarget{}
+	pager := client.NewListDeletedPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing deleted managed hsms: %w", err)
+		}
+
+		for _, managedHSM := range page.Value {
+			if managedHSM.Properties == nil ||
+				convert.ToValueWithDefault(managedHSM.Properties.PurgeProtectionEnabled, false) {
+				continue
+			}
+
+			if target, ok := deletedTarget(AzureResourceTypeManagedHSM, "Managed HSM",
+				managedHSM.Properties.MhsmID, managedHSM.Properties.Location); ok {
+				targets = append(targets, target)
+			}
+		}
+	}
+
+	return targets, nil
+}
+
+func (p *managedHSMPurger) Purge(ctx context.Context, target *PurgeTarget) error {
+	return p.cli.PurgeManagedHSM(ctx, target.SubscriptionId, target.Name, target.Location)
+}
+
+// Azure App Configuration stores are soft-deleted, unless purge protection is enabled.
+//
+// See https://learn.microsoft.com/azure/azure-app-configuration/concept-soft-delete for more information on this
+// feature.
+type appConfigPurger struct {
+	cli *AzureClient
+}
+
+func (p *appConfigPurger) ResourceType() AzureResourceType {
+	return AzureResourceTypeAppConfig
+}
+
+func (p *appConfigPurger) Target(ctx context.Context, resourceGroup string, resource *Resource) (*PurgeTarget, error) {
+	config, err := p.cli.GetAppConfig(ctx, azure.SubscriptionFromRID(resource.Id), resourceGroup, resource.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Properties.EnablePurgeProtection {
+		return nil, nil
+	}
+
+	return &PurgeTarget{
+		Id:             config.Id,
+		SubscriptionId: azure.SubscriptionFromRID(config.Id),
+		ResourceGroup:  resourceGroup,
+		Name:           config.Name,
+		Location:       config.Location,
+		ResourceType:   AzureResourceTypeAppConfig,
+		DisplayType:    "App Configuration",
+	}, nil
+}
+
+func (p *appConfigPurger) Deleted(ctx context.Context, subscriptionId string) ([]*PurgeTarget, error) {
+	client, err := p.cli.createAppConfigClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := []*PurgeTarget{}
+	pager := client.NewListDeletedPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing deleted app configurations: %w", err)
+		}
+
+		for _, config := range page.Value {
+			if config.Properties == nil || convert.ToValueWithDefault(config.Properties.PurgeProtectionEnabled, false) {
+				continue
+			}
+
+			if target, ok := deletedTarget(AzureResourceTypeAppConfig, "App Configuration",
+				config.Properties.ConfigurationStoreID, config.Properties.Location); ok {
+				targets = append(targets, target)
+			}
+		}
+	}
+
+	return targets, nil
+}
+
+func (p *appConfigPurger) Purge(ctx context.Context, target *PurgeTarget) error {
+	return p.cli.PurgeAppConfig(ctx, target.SubscriptionId, target.Name, target.Location)
+}
+
+// Azure API Management services are always soft-deleted.
+type apimPurger struct {
+	cli *AzureClient
+}
+
+func (p *apimPurger) ResourceType() AzureResourceType {
+	return AzureResourceTypeApim
+}
+
+func (p *apimPurger) Target(ctx context.Context, resourceGroup string, resource *Resource) (*PurgeTarget, error) {
+	apim, err := p.cli.GetApim(ctx, azure.SubscriptionFromRID(resource.Id), resourceGroup, resource.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PurgeTarget{
+		Id:             apim.Id,
+		SubscriptionId: azure.SubscriptionFromRID(apim.Id),
+		ResourceGroup:  resourceGroup,
+		Name:           apim.Name,
+		Location:       apim.Location,
+		ResourceType:   AzureResourceTypeApim,
+		DisplayType:    "API Management",
+	}, nil
+}
+
+func (p *apimPurger) Deleted(ctx context.Context, subscriptionId string) ([]*PurgeTarget, error) {
+	client, err := p.cli.createApimDeletedClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := []*PurgeTarget{}
+	pager := client.NewListBySubscriptionPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing deleted api management services: %w", err)
+		}
+
+		for _, apim := range page.Value {
+			if apim.Properties == nil {
+				continue
+			}
+
+			if target, ok := deletedTarget(
+				AzureResourceTypeApim, "API Management", apim.Properties.ServiceID, apim.Location); ok {
+				targets = append(targets, target)
+			}
+		}
+	}
+
+	return targets, nil
+}
+
+func (p *apimPurger) Purge(ctx context.Context, target *PurgeTarget) error {
+	return p.cli.PurgeApim(ctx, target.SubscriptionId, target.Name, target.Location)
+}
+
+// Azure Cognitive Services accounts, including Azure OpenAI accounts, are always soft-deleted. They're displayed by
+// their kind.
+type cognitiveAccountPurger struct {
+	cli *AzureClient
+}
+
+func (p *cognitiveAccountPurger) ResourceType() AzureResourceType {
+	return AzureResourceTypeCognitiveServiceAccount
+}
+
+func (p *cognitiveAccountPurger) Target(
+	ctx context.Context,
+	resourceGroup string,
+	resource *Resource,
+) (*PurgeTarget, error) {
+	account, err := p.cli.GetCognitiveAccount(ctx, azure.SubscriptionFromRID(resource.Id), resourceGroup, resource.Name)
+	if err != nil {
+		return nil, fmt.Errorf("getting cognitive account: %w", err)
+	}
+
+	if account.Name == nil {
+		return nil, errors.New("cognitive account without a name")
+	}
+	if account.ID == nil {
+		return nil, errors.New("cognitive account without an id")
+	}
+	if account.Location == nil {
+		return nil, errors.New("cognitive account without a location")
+	}
+
+	return &PurgeTarget{
+		Id:             *account.ID,
+		SubscriptionId: azure.SubscriptionFromRID(*account.ID),
+		ResourceGroup:  resourceGroup,
+		Name:           *account.Name,
+		Location:       *account.Location,
+		ResourceType:   AzureResourceTypeCognitiveServiceAccount,
+		DisplayType:    cognitiveAccountDisplayType(account.Kind),
+	}, nil
+}
+
+func (p *cognitiveAccountPurger) Deleted(ctx context.Context, subscriptionId string) ([]*PurgeTarget, error) {
+	client, err := p.cli.createDeletedCognitiveAccountClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := []*PurgeTarget{}
+	pager := client.NewListPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing deleted cognitive accounts: %w", err)
+		}
+
+		for _, account := range page.Value {
+			if account.ID == nil || account.Name == nil || account.Location == nil {
+				continue
+			}
+
+			// The ID of a deleted account is scoped to its location, but it keeps the resource group segment.
+			resourceGroup := azure.GetResourceGroupName(*account.ID)
+			if resourceGroup == nil {
+				continue
+			}
+
+			targets = append(targets, &PurgeTarget{
+				Id:             *account.ID,
+				SubscriptionId: subscriptionId,
+				ResourceGroup:  *resourceGroup,
+				Name:           *account.Name,
+				Location:       *account.Location,
+				ResourceType:   AzureResourceTypeCognitiveServiceAccount,
+				DisplayType:    cognitiveAccountDisplayType(account.Kind),
+			})
+		}
+	}
+
+	return targets, nil
+}
+
+func (p *cognitiveAccountPurger) Purge(ctx context.Context, target *PurgeTarget) error {
+	return p.cli.PurgeCognitiveAccount(ctx, target.SubscriptionId, target.Location, target.ResourceGroup, target.Name)
+}
+
+// cognitiveAccountDisplayType returns the display type of a cognitive account of the kind.
+func cognitiveAccountDisplayType(kind *string) string {
+	switch {
+	case kind == nil:
+		return "Cognitive Account"
+	case *kind == "FormRecognizer":
+		return "Document Intelligence"
+	default:
+		return *kind
+	}
+}
+
+// Log Analytics workspaces are soft-deleted, but a soft-deleted workspace can't be purged: only the tables of a
+// workspace can be. Instead, the workspace is purged by force deleting it, which must happen before its resource
+// group is deleted. A workspace that was already soft-deleted can only be recovered, so none are listed as deleted.
+type logAnalyticsWorkspacePurger struct {
+	cli *AzureClient
+}
+
+func (p *logAnalyticsWorkspacePurger) ResourceType() AzureResourceType {
+	return AzureResourceTypeLogAnalyticsWorkspace
+}
+
+func (p *logAnalyticsWorkspacePurger) Target(
+	ctx context.Context,
+	resourceGroup string,
+	resource *Resource,
+) (*PurgeTarget, error) {
+	workspace, err := p.cli.GetLogAnalyticsWorkspace(
+		ctx, azure.SubscriptionFromRID(resource.Id), resourceGroup, resource.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PurgeTarget{
+		Id:             workspace.Id,
+		SubscriptionId: azure.SubscriptionFromRID(workspace.Id),
+		ResourceGroup:  resourceGroup,
+		Name:           workspace.Name,
+		ResourceType:   AzureResourceTypeLogAnalyticsWorkspace,
+		DisplayType:    "Log Analytics Workspace",
+		BeforeDelete:   true,
+	}, nil
+}
+
+func (p *logAnalyticsWorkspacePurger) Deleted(ctx context.Context, subscriptionId string) ([]*PurgeTarget, error) {
+	return nil, nil
+}
+
+func (p *logAnalyticsWorkspacePurger) Purge(ctx context.Context, target *PurgeTarget) error {
+	return p.cli.PurgeLogAnalyticsWorkspace(ctx, target.SubscriptionId, target.ResourceGroup, target.Name)
+}
@@ -0,0 +1,266 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+)
+
+// fakePurger purges the resources of a resource type without calling Azure.
+type fakePurger struct {
+	resourceType AzureResourceType
+	deleted      []*PurgeTarget
+	purged       []string
+}
+
+func (p *fakePurger) ResourceType() AzureResourceType {
+	return p.resourceType
+}
+
+func (p *fakePurger) Target(_ context.Context, resourceGroup string, resource *Resource) (*PurgeTarget, error) {
+	return &PurgeTarget{
+		Id:            resource.Id,
+		ResourceGroup: resourceGroup,
+		Name:          resource.Name,
+		ResourceType:  p.resourceType,
+		DisplayType:   "Fake",
+	}, nil
+}
+
+func (p *fakePurger) Deleted(_ context.Context, _ string) ([]*PurgeTarget, error) {
+	return p.deleted, nil
+}
+
+func (p *fakePurger) Purge(_ context.Context, target *PurgeTarget) error {
+	p.purged = append(p.purged, target.Name)
+	return nil
+}
+
+func Test_PurgeService_Register(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	service := NewPurgeService(newAzureClientFromMockContext(mockContext))
+
+	// A registered purger replaces the built-in purger of its resource type.
+	purger := &fakePurger{resourceType: AzureResourceTypeKeyVault}
+	service.Register(purger)
+
+	targets, err := service.Targets(*mockContext.Context, map[string][]*Resource{
+		"rg": {{Id: "/subscriptions/SUB/resourceGroups/rg/providers/Microsoft.KeyVault/vaults/kv", Name: "kv",
+			Type: string(AzureResourceTypeKeyVault)}},
+	})
+	require.NoError(t, err)
+	require.Len(t, targets, 1)
+
+	require.NoError(t, service.Purge(*mockContext.Context, targets[0]))
+	assert.Equal(t, []string{"kv"}, purger.purged)
+
+	err = service.Purge(*mockContext.Context, &PurgeTarget{ResourceType: "Microsoft.Unknown/things"})
+	require.ErrorContains(t, err, "no purger registered")
+}
+
+func Test_PurgeService_Targets(t *testing.T) {
+	websites := &fakePurger{resourceType: AzureResourceTypeWebSite}
+	vaults := &fakePurger{resourceType: AzureResourceTypeKeyVault}
+	service := &PurgeService{purgers: []Purger{vaults, websites}}
+
+	targets, err := service.Targets(t.Context(), map[string][]*Resource{
+		"rg-b": {
+			{Id: "site-1", Name: "site-1", Type: string(AzureResourceTypeWebSite)},
+			{Id: "storage-1", Name: "storage-1", Type: string(AzureResourceTypeStorageAccount)},
+		},
+		"rg-a": {
+			{Id: "kv-1", Name: "kv-1", Type: "microsoft.keyvault/vaults"},
+			{Id: "site-2", Name: "site-2", Type: string(AzureResourceTypeWebSite)},
+		},
+	})
+	require.NoError(t, err)
+
+	names := []string{}
+	for _, target := range targets {
+		names = append(names, target.Name)
+	}
+
+	// Targets are in the order of the purgers, then of the resource groups.
+	assert.Equal(t, []string{"kv-1", "site-2", "site-1"}, names)
+}
+
+func Test_PurgeService_DeletedTargets(t *testing.T) {
+	vaults := &fakePurger{
+		resourceType: AzureResourceTypeKeyVault,
+		deleted: []*PurgeTarget{
+			{Name: "kv-1", ResourceGroup: "RG-A"},
+			{Name: "kv-2", ResourceGroup: "rg-other"},
+		},
+	}
+	service := &PurgeService{purgers: []Purger{vaults}}
+
+	targets := service.DeletedTargets(t.Context(), "SUB", []string{"rg-a"})
+	require.Len(t, targets, 1)
+	assert.Equal(t, "kv-1", targets[0].Name)
+	assert.True(t, targets[0].Deleted)
+}
+
+func Test_KeyVaultPurger(t *testing.T) {
+	t.Run("Target", func(t *testing.T) {
+		tests := []struct {
+			name            string
+			softDelete      bool
+			purgeProtection bool
+			want            bool
+		}{
+			{name: "SoftDeleted", softDelete: true, want: true},
+			{name: "PurgeProtected", softDelete: true, purgeProtection: true},
+			{name: "NotSoftDeleted"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mockContext := mocks.NewMockContext(t.Context())
+				mockContext.HttpClient.When(func(request *http.Request) bool {
+					return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/vaults/kv")
+				}).RespondFn(func(request *http.Request) (*http.Response, error) {
+					return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+						"id":       "/subscriptions/SUB/resourceGroups/rg/providers/Microsoft.KeyVault/vaults/kv",
+						"name":     "kv",
+						"location": "eastus2",
+						"properties": map[string]any{
+							"enableSoftDelete":      tt.softDelete,
+							"enablePurgeProtection": tt.purgeProtection,
+						},
+					})
+				})
+
+				purger := &keyVaultPurger{cli: newAzureClientFromMockContext(mockContext)}
+				target, err := purger.Target(*mockContext.Context, "rg", &Resource{
+					Id:   "/subscriptions/SUB/resourceGroups/rg/providers/Microsoft.KeyVault/vaults/kv",
+					Name: "kv",
+				})
+				require.NoError(t, err)
+
+				if !tt.want {
+					require.Nil(t, target)
+					return
+				}
+
+				require.NotNil(t, target)
+				assert.Equal(t, "SUB", target.SubscriptionId)
+				assert.Equal(t, "eastus2", target.Location)
+				assert.Equal(t, "Key Vault", target.DisplayType)
+			})
+		}
+	})
+
+	t.Run("Deleted", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/deletedVaults")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+				"value": []map[string]any{
+					{
+						"name": "kv-1",
+						"properties": map[string]any{
+							"vaultId":  "/subscriptions/SUB/resourceGroups/rg/providers/Microsoft.KeyVault/vaults/kv-1",
+							"location": "eastus2",
+						},
+					},
+					{
+						"name": "kv-2",
+						"properties": map[string]any{
+							"vaultId":                "/subscriptions/SUB/resourceGroups/rg/providers/Microsoft.KeyVault/vaults/kv-2",
+							"location":               "eastus2",
+							"purgeProtectionEnabled": true,
+						},
+					},
+				},
+			})
+		})
+
+		purger := &keyVaultPurger{cli: newAzureClientFromMockContext(mockContext)}
+		targets, err := purger.Deleted(*mockContext.Context, "SUB")
+		require.NoError(t, err)
+		require.Len(t, targets, 1)
+		assert.Equal(t, "kv-1", targets[0].Name)
+		assert.Equal(t, "rg", targets[0].ResourceGroup)
+	})
+
+	t.Run("PurgeNotFound", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, "/deletedVaults/kv/purge")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusNotFound)
+		})
+
+		purger := &keyVaultPurger{cli: newAzureClientFromMockContext(mockContext)}
+		err := purger.Purge(*mockContext.Context, &PurgeTarget{SubscriptionId: "SUB", Name: "kv", Location: "eastus2"})
+		require.NoError(t, err)
+	})
+}
+
+func Test_CognitiveAccountPurger(t *testing.T) {
+	t.Run("DisplaysKind", func(t *testing.T) {
+		assert.Equal(t, "OpenAI", cognitiveAccountDisplayType(new("OpenAI")))
+		assert.Equal(t, "Document Intelligence", cognitiveAccountDisplayType(new("FormRecognizer")))
+		assert.Equal(t, "Cognitive Account", cognitiveAccountDisplayType(nil))
+	})
+
+	t.Run("TargetValidation", func(t *testing.T) {
+		for name, account := range map[string]map[string]any{
+			"MissingName":     {},
+			"MissingId":       {"name": "n"},
+			"MissingLocation": {"name": "n", "id": "/subscriptions/SUB/resourceGroups/rg/providers/p/accounts/n"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				mockContext := mocks.NewMockContext(t.Context())
+				mockContext.HttpClient.When(func(request *http.Request) bool {
+					return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/accounts/n")
+				}).RespondFn(func(request *http.Request) (*http.Response, error) {
+					return mocks.CreateHttpResponseWithBody(request, http.StatusOK, account)
+				})
+
+				purger := &cognitiveAccountPurger{cli: newAzureClientFromMockContext(mockContext)}
+				_, err := purger.Target(*mockContext.Context, "rg", &Resource{
+					Id:   "/subscriptions/SUB/resourceGroups/rg/providers/Microsoft.CognitiveServices/accounts/n",
+					Name: "n",
+				})
+				require.Error(t, err)
+			})
+		}
+	})
+
+	t.Run("Deleted", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/deletedAccounts")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+				"value": []map[string]any{
+					{
+						"id": "/subscriptions/SUB/providers/Microsoft.CognitiveServices/locations/eastus2/" +
+							"resourceGroups/rg/deletedAccounts/oai",
+						"name":     "oai",
+						"kind":     "OpenAI",
+						"location": "eastus2",
+					},
+				},
+			})
+		})
+
+		purger := &cognitiveAccountPurger{cli: newAzureClientFromMockContext(mockContext)}
+		targets, err := purger.Deleted(*mockContext.Context, "SUB")
+		require.NoError(t, err)
+		require.Len(t, targets, 1)
+		assert.Equal(t, "rg", targets[0].ResourceGroup)
+		assert.Equal(t, "OpenAI", targets[0].DisplayType)
+	})
+}
@@ -0,0 +1,260 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+)
+
+const (
+	// computeSkusApiVersion is the Microsoft.Compute API version used to list resource SKUs.
+	computeSkusApiVersion = "2021-07-01"
+	// computeUsagesApiVersion is the Microsoft.Compute API version used to list usages.
+	computeUsagesApiVersion = "2023-09-01"
+)
+
+// RegionalCapacityService queries the availability and the quota of SKUs in an Azure region, so that templates can be
+// checked before they are deployed.
+type RegionalCapacityService struct {
+	credentialProvider account.SubscriptionCredentialProvider
+	armClientOptions   *arm.ClientOptions
+}
+
+// NewRegionalCapacityService creates a new RegionalCapacityService.
+func NewRegionalCapacityService(
+	credentialProvider account.SubscriptionCredentialProvider,
+	armClientOptions *arm.ClientOptions,
+) *RegionalCapacityService {
+	return &RegionalCapacityService{
+		credentialProvider: credentialProvider,
+		armClientOptions:   armClientOptions,
+	}
+}
+
+// VmSku is a virtual machine size offered in a region.
+type VmSku struct {
+	// Name is the name of the size, for example Standard_D2s_v3.
+	Name string
+	// Family is the quota family of the size, for example standardDSv3Family.
+	Family string
+	// VCpus is the number of vCPUs of the size.
+	VCpus int
+	// Restricted is true when the size can't be deployed in the region by the subscription.
+	Restricted bool
+}
+
+// ComputeUsage is the usage of a Microsoft.Compute quota in a region.
+type ComputeUsage struct {
+	// Name is the name of the quota, either cores for the total regional vCPUs or the family of a VM size.
+	Name         string
+	CurrentValue float64
+	Limit        float64
+}
+
+// TotalRegionalVCpusUsageName is the name of the usage of the total vCPUs of a region.
+const TotalRegionalVCpusUsageName = "cores"
+
+type computeResourceSku struct {
+	ResourceType string `json:"resourceType"`
+	Name         string `json:"name"`
+	Family       string `json:"family"`
+	Capabilities []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"capabilities"`
+	Restrictions []struct {
+		Type       string   `json:"type"`
+		Values     []string `json:"values"`
+		ReasonCode string   `json:"reasonCode"`
+	} `json:"restrictions"`
+}
+
+type computeUsage struct {
+	CurrentValue float64 `json:"currentValue"`
+	Limit        float64 `json:"limit"`
+	Name         struct {
+		Value string `json:"value"`
+	} `json:"name"`
+}
+
+type armListResponse[T any] struct {
+	Value    []T    `json:"value"`
+	NextLink string `json:"nextLink"`
+}
+
+// ListVmSkus lists the virtual machine sizes offered in the location.
+func (s *RegionalCapacityService) ListVmSkus(
+	ctx context.Context,
+	subscriptionId string,
+	location string,
+) ([]VmSku, error) {
+	filter := url.QueryEscape(fmt.Sprintf("location eq '%s'", location))
+	skus, err := listArmResources[computeResourceSku](
+		ctx, s, subscriptionId,
+		fmt.Sprintf("subscriptions/%s/providers/Microsoft.Compute/skus?api-version=%s&$filter=%s",
+			subscriptionId, computeSkusApiVersion, filter),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing VM sizes in %s: %w", location, err)
+	}
+
+	var vmSkus []VmSku
+	for _, sku := range skus {
+		if sku.ResourceType != "virtualMachines" {
+			continue
+		}
+
+		vmSku := VmSku{
+			Name:   sku.Name,
+			Family: sku.Family,
+		}
+
+		for _, capability := range sku.Capabilities {
+			if capability.Name == "vCPUs" {
+				vmSku.VCpus, _ = strconv.Atoi(capability.Value)
+			}
+		}
+
+		// Zone restrictions only limit the availability zones the size can be deployed to.
+		for _, restriction := range sku.Restrictions {
+			if restriction.Type == "Location" &&
+				slices.ContainsFunc(restriction.Values, func(value string) bool {
+					return strings.EqualFold(value, location)
+				}) {
+				vmSku.Restricted = true
+			}
+		}
+
+		vmSkus = append(vmSkus, vmSku)
+	}
+
+	return vmSkus, nil
+}
+
+// ListComputeUsages lists the usages of the Microsoft.Compute quotas of the subscription in the location.
+func (s *RegionalCapacityService) ListComputeUsages(
+	ctx context.Context,
+	subscriptionId string,
+	location string,
+) ([]ComputeUsage, error) {
+	usages, err := listArmResources[computeUsage](
+		ctx, s, subscriptionId,
+		fmt.Sprintf("subscriptions/%s/providers/Microsoft.Compute/locations/%s/usages?api-version=%s",
+			subscriptionId, location, computeUsagesApiVersion),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing compute usages in %s: %w", location, err)
+	}
+
+	result := make([]ComputeUsage, 0, len(usages))
+	for _, usage := range usages {
+		result = append(result, ComputeUsage{
+			Name:         usage.Name.Value,
+			CurrentValue: usage.CurrentValue,
+			Limit:        usage.Limit,
+		})
+	}
+
+	return result, nil
+}
+
+// ListAppServiceLocations lists the locations where App Service plans of the pricing tier, for example PremiumV3, can
+// be created. Locations are returned in their normalized form, for example eastus.
+func (s *RegionalCapacityService) ListAppServiceLocations(
+	ctx context.Context,
+	subscriptionId string,
+	tier string,
+) ([]string, error) {
+	credential, err := s.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := armappservice.NewWebSiteManagementClient(subscriptionId, credential, s.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating App Service client: %w", err)
+	}
+
+	var locations []string
+	pager := client.NewListGeoRegionsPager(&armappservice.WebSiteManagementClientListGeoRegionsOptions{
+		SKU: to.Ptr(armappservice.SKUName(tier)),
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing App Service locations of %s: %w", tier, err)
+		}
+
+		for _, region := range page.Value {
+			if region.Name != nil {
+				// Regions are returned by display name, for example "East US".
+				locations = append(locations, strings.ToLower(strings.ReplaceAll(*region.Name, " ", "")))
+			}
+		}
+	}
+
+	slices.Sort(locations)
+	return slices.Compact(locations), nil
+}
+
+// listArmResources sends a GET request for the ARM list operation at resourcePath, relative to the Resource Manager
+// endpoint, and follows the next links of the response.
+func listArmResources[T any](
+	ctx context.Context,
+	s *RegionalCapacityService,
+	subscriptionId string,
+	resourcePath string,
+) ([]T, error) {
+	credential, err := s.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, fmt.Errorf("getting credential for subscription %s: %w", subscriptionId, err)
+	}
+
+	pipeline, err := newArmPipeline("azd-regional-capacity", credential, s.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating regional capacity pipeline: %w", err)
+	}
+
+	endpoint := armEndpoint(s.armClientOptions)
+
+	var items []T
+	requestUrl := strings.TrimSuffix(endpoint, "/") + "/" + resourcePath
+	for requestUrl != "" {
+		req, err := runtime.NewRequest(ctx, http.MethodGet, requestUrl)
+		if err != nil {
+			return nil, fmt.Errorf("creating request: %w", err)
+		}
+
+		response, err := pipeline.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if !runtime.HasStatusCode(response, http.StatusOK) {
+			return nil, runtime.NewResponseError(response)
+		}
+
+		var page armListResponse[T]
+		if err := runtime.UnmarshalAsJSON(response, &page); err != nil {
+			return nil, err
+		}
+
+		items = append(items, page.Value...)
+		requestUrl = page.NextLink
+	}
+
+	return items, nil
+}
@@ -0,0 +1,116 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+)
+
+func Test_RegionalCapacityService_ListVmSkus(t *testing.T) {
+	mockCtx := mocks.NewMockContext(t.Context())
+	svc := NewRegionalCapacityService(mockCtx.SubscriptionCredentialProvider, mockCtx.ArmClientOptions)
+
+	mockCtx.HttpClient.When(func(req *http.Request) bool {
+		return strings.HasSuffix(req.URL.Path, "/subscriptions/SUB/providers/Microsoft.Compute/skus") &&
+			req.URL.Query().Get("$filter") == "location eq 'eastus'" &&
+			req.URL.Query().Get("page") == ""
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(req, http.StatusOK, map[string]any{
+			"value": []map[string]any{
+				{
+					"resourceType": "virtualMachines",
+					"name":         "Standard_D2s_v3",
+					"family":       "standardDSv3Family",
+					"capabilities": []map[string]any{{"name": "vCPUs", "value": "2"}},
+					"restrictions": []map[string]any{
+						{"type": "Zone", "values": []string{"eastus"}, "reasonCode": "NotAvailableForSubscription"},
+					},
+				},
+				{
+					"resourceType": "disks",
+					"name":         "Premium_LRS",
+				},
+			},
+			"nextLink": "https://management.azure.com/subscriptions/SUB/providers/Microsoft.Compute/skus?page=2",
+		})
+	})
+
+	mockCtx.HttpClient.When(func(req *http.Request) bool {
+		return strings.HasSuffix(req.URL.Path, "/subscriptions/SUB/providers/Microsoft.Compute/skus") &&
+			req.URL.Query().Get("page") == "2"
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(req, http.StatusOK, map[string]any{
+			"value": []map[string]any{
+				{
+					"resourceType": "virtualMachines",
+					"name":         "Standard_M416ms_v2",
+					"family":       "standardMSv2Family",
+					"capabilities": []map[string]any{{"name": "vCPUs", "value": "416"}},
+					"restrictions": []map[string]any{
+						{"type": "Location", "values": []string{"EastUS"}, "reasonCode": "NotAvailableForSubscription"},
+					},
+				},
+			},
+		})
+	})
+
+	skus, err := svc.ListVmSkus(*mockCtx.Context, "SUB", "eastus")
+	require.NoError(t, err)
+	assert.Equal(t, []VmSku{
+		{Name: "Standard_D2s_v3", Family: "standardDSv3Family", VCpus: 2},
+		{Name: "Standard_M416ms_v2", Family: "standardMSv2Family", VCpus: 416, Restricted: true},
+	}, skus)
+}
+
+func Test_RegionalCapacityService_ListComputeUsages(t *testing.T) {
+	mockCtx := mocks.NewMockContext(t.Context())
+	svc := NewRegionalCapacityService(mockCtx.SubscriptionCredentialProvider, mockCtx.ArmClientOptions)
+
+	mockCtx.HttpClient.When(func(req *http.Request) bool {
+		return strings.HasSuffix(req.URL.Path, "/subscriptions/SUB/providers/Microsoft.Compute/locations/eastus/usages") &&
+			req.URL.Query().Get("api-version") == computeUsagesApiVersion
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(req, http.StatusOK, map[string]any{
+			"value": []map[string]any{
+				{"currentValue": 4, "limit": 10, "name": map[string]any{"value": "cores"}},
+				{"currentValue": 2, "limit": 4, "name": map[string]any{"value": "standardDSv3Family"}},
+			},
+		})
+	})
+
+	usages, err := svc.ListComputeUsages(*mockCtx.Context, "SUB", "eastus")
+	require.NoError(t, err)
+	assert.Equal(t, []ComputeUsage{
+		{Name: TotalRegionalVCpusUsageName, CurrentValue: 4, Limit: 10},
+		{Name: "standardDSv3Family", CurrentValue: 2, Limit: 4},
+	}, usages)
+}
+
+func Test_RegionalCapacityService_ListAppServiceLocations(t *testing.T) {
+	mockCtx := mocks.NewMockContext(t.Context())
+	svc := NewRegionalCapacityService(mockCtx.SubscriptionCredentialProvider, mockCtx.ArmClientOptions)
+
+	mockCtx.HttpClient.When(func(req *http.Request) bool {
+		return strings.HasSuffix(req.URL.Path, "/subscriptions/SUB/providers/Microsoft.Web/geoRegions") &&
+			req.URL.Query().Get("sku") == "PremiumV3"
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(req, http.StatusOK, map[string]any{
+			"value": []map[string]any{
+				{"name": "West US 2"},
+				{"name": "East US"},
+			},
+		})
+	})
+
+	locations, err := svc.ListAppServiceLocations(*mockCtx.Context, "SUB", "PremiumV3")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"eastus", "westus2"}, locations)
+}
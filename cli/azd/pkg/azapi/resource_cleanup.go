@@ -0,0 +1,254 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+)
+
+const (
+	// managementLocksApiVersion is the Microsoft.Authorization/locks API version used by azd.
+	managementLocksApiVersion = "2020-05-01"
+	// diagnosticSettingsApiVersion is the Microsoft.Insights/diagnosticSettings API version used by azd.
+	diagnosticSettingsApiVersion = "2021-05-01-preview"
+
+	managementLockProvider = "/providers/Microsoft.Authorization/locks/"
+)
+
+// ResourceCleanupService manages the resources that prevent other resources from being deleted, or that outlive
+// them: the management locks of a resource group and the diagnostic settings of a resource.
+type ResourceCleanupService struct {
+	credentialProvider account.SubscriptionCredentialProvider
+	armClientOptions   *arm.ClientOptions
+}
+
+// NewResourceCleanupService creates a new ResourceCleanupService.
+func NewResourceCleanupService(
+	credentialProvider account.SubscriptionCredentialProvider,
+	armClientOptions *arm.ClientOptions,
+) *ResourceCleanupService {
+	return &ResourceCleanupService{
+		credentialProvider: credentialProvider,
+		armClientOptions:   armClientOptions,
+	}
+}
+
+// ManagementLock is a lock that prevents the deletion or the modification of the resources of its scope.
+type ManagementLock struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	// Level is CanNotDelete or ReadOnly.
+	Level string `json:"level"`
+	Notes string `json:"notes"`
+}
+
+// Scope returns the ID of the resource group or the resource the lock is applied to.
+func (l *ManagementLock) Scope() string {
+	index := strings.Index(strings.ToLower(l.Id), strings.ToLower(managementLockProvider))
+	if index < 0 {
+		return ""
+	}
+
+	return l.Id[:index]
+}
+
+// DiagnosticSetting routes the logs and metrics of a resource to a destination. Diagnostic settings aren't deleted
+// with their resource, and a remaining setting conflicts with the setting of a new resource with the same name.
+type DiagnosticSetting struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type managementLockProperties struct {
+	Level string `json:"level"`
+	Notes string `json:"notes"`
+}
+
+type managementLockListResult struct {
+	Value []struct {
+		Id         string                   `json:"id"`
+		Name       string                   `json:"name"`
+		Properties managementLockProperties `json:"properties"`
+	} `json:"value"`
+	NextLink string `json:"nextLink"`
+}
+
+type diagnosticSettingListResult struct {
+	Value []*DiagnosticSetting `json:"value"`
+}
+
+// ListResourceGroupLocks returns the management locks of the resource group, and of the resources in the resource
+// group.
+func (s *ResourceCleanupService) ListResourceGroupLocks(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+) ([]*ManagementLock, error) {
+	pipeline, err := s.pipeline(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	requestUrl, err := s.requestUrl(
+		fmt.Sprintf(
+			"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Authorization/locks",
+			subscriptionId,
+			resourceGroupName,
+		),
+		managementLocksApiVersion,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	locks := []*ManagementLock{}
+	for requestUrl != "" {
+		var page managementLockListResult
+		if err := s.get(ctx, pipeline, requestUrl, &page); err != nil {
+			return nil, fmt.Errorf("listing locks of resource group %s: %w", resourceGroupName, err)
+		}
+
+		for _, lock := range page.Value {
+			locks = append(locks, &ManagementLock{
+				Id:    lock.Id,
+				Name:  lock.Name,
+				Level: lock.Properties.Level,
+				Notes: lock.Properties.Notes,
+			})
+		}
+
+		requestUrl = page.NextLink
+	}
+
+	return locks, nil
+}
+
+// DeleteLock deletes the management lock. A lock that is already deleted isn't an error.
+func (s *ResourceCleanupService) DeleteLock(ctx context.Context, subscriptionId string, lock *ManagementLock) error {
+	if err := s.delete(ctx, subscriptionId, lock.Id, managementLocksApiVersion); err != nil {
+		return fmt.Errorf("deleting lock %s: %w", lock.Name, err)
+	}
+
+	return nil
+}
+
+// ListDiagnosticSettings returns the diagnostic settings of the resource. Resources of types that don't support
+// diagnostic settings have none.
+func (s *ResourceCleanupService) ListDiagnosticSettings(
+	ctx context.Context,
+	subscriptionId string,
+	resourceId string,
+) ([]*DiagnosticSetting, error) {
+	pipeline, err := s.pipeline(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	requestUrl, err := s.requestUrl(
+		resourceId+"/providers/Microsoft.Insights/diagnosticSettings", diagnosticSettingsApiVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	var result diagnosticSettingListResult
+	if err := s.get(ctx, pipeline, requestUrl, &result); err != nil {
+		return nil, fmt.Errorf("listing diagnostic settings of %s: %w", resourceId, err)
+	}
+
+	return result.Value, nil
+}
+
+// DeleteDiagnosticSetting deletes the diagnostic setting. A setting that is already deleted isn't an error.
+func (s *ResourceCleanupService) DeleteDiagnosticSetting(
+	ctx context.Context,
+	subscriptionId string,
+	setting *DiagnosticSetting,
+) error {
+	if err := s.delete(ctx, subscriptionId, setting.Id, diagnosticSettingsApiVersion); err != nil {
+		return fmt.Errorf("deleting diagnostic setting %s: %w", setting.Name, err)
+	}
+
+	return nil
+}
+
+func (s *ResourceCleanupService) get(ctx context.Context, pipeline runtime.Pipeline, requestUrl string, v any) error {
+	req, err := runtime.NewRequest(ctx, http.MethodGet, requestUrl)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	response, err := pipeline.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return runtime.NewResponseError(response)
+	}
+
+	return runtime.UnmarshalAsJSON(response, v)
+}
+
+func (s *ResourceCleanupService) delete(ctx context.Context, subscriptionId, id, apiVersion string) error {
+	pipeline, err := s.pipeline(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	requestUrl, err := s.requestUrl(id, apiVersion)
+	if err != nil {
+		return err
+	}
+
+	req, err := runtime.NewRequest(ctx, http.MethodDelete, requestUrl)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	response, err := pipeline.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusNoContent, http.StatusNotFound) {
+		return runtime.NewResponseError(response)
+	}
+
+	return nil
+}
+
+func (s *ResourceCleanupService) pipeline(ctx context.Context, subscriptionId string) (runtime.Pipeline, error) {
+	credential, err := s.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return runtime.Pipeline{}, fmt.Errorf("getting credential for subscription %s: %w", subscriptionId, err)
+	}
+
+	pipeline, err := newArmPipeline("azd-resource-cleanup", credential, s.armClientOptions)
+	if err != nil {
+		return runtime.Pipeline{}, fmt.Errorf("creating resource cleanup pipeline: %w", err)
+	}
+
+	return pipeline, nil
+}
+
+func (s *ResourceCleanupService) requestUrl(path string, apiVersion string) (string, error) {
+	endpoint := armEndpoint(s.armClientOptions)
+
+	requestUrl, err := url.JoinPath(endpoint, path)
+	if err != nil {
+		return "", fmt.Errorf("building request url: %w", err)
+	}
+
+	return requestUrl + "?api-version=" + apiVersion, nil
+}
@@ -8,11 +8,14 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/convert"
@@ -112,6 +115,65 @@ func (rs *ResourceService) CheckExistenceByID(
 	return response.Success, nil
 }
 
+// GetProvisioningState returns the provisioning state of a resource. Only resources that are listed in their resource
+// group, and not child resources, are supported. When the resource doesn't exist, an empty state is returned.
+func (rs *ResourceService) GetProvisioningState(ctx context.Context, resourceId *arm.ResourceID) (string, error) {
+	client, err := rs.createResourcesClient(ctx, resourceId.SubscriptionID)
+	if err != nil {
+		return "", err
+	}
+
+	filter := fmt.Sprintf("resourceType eq '%s' and name eq '%s'", resourceId.ResourceType.String(), resourceId.Name)
+	pager := client.NewListByResourceGroupPager(resourceId.ResourceGroupName, &armresources.ClientListByResourceGroupOptions{
+		Filter: &filter,
+		Expand: to.Ptr("provisioningState"),
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return "", fmt.Errorf("listing resources: %w", err)
+		}
+
+		for _, resource := range page.Value {
+			if resource.ID != nil && strings.EqualFold(*resource.ID, resourceId.String()) {
+				return convert.ToValueWithDefault(resource.ProvisioningState, ""), nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// GetCreatedTime returns the time a resource was created, which tells apart a resource that was deleted and created again
+// with the same ID. Only resources that are listed in their resource group, and not child resources, are supported.
+// When the resource doesn't exist, or its creation time isn't known, the zero time is returned.
+func (rs *ResourceService) GetCreatedTime(ctx context.Context, resourceId *arm.ResourceID) (time.Time, error) {
+	client, err := rs.createResourcesClient(ctx, resourceId.SubscriptionID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	filter := fmt.Sprintf("resourceType eq '%s' and name eq '%s'", resourceId.ResourceType.String(), resourceId.Name)
+	pager := client.NewListByResourceGroupPager(resourceId.ResourceGroupName, &armresources.ClientListByResourceGroupOptions{
+		Filter: &filter,
+		Expand: to.Ptr("createdTime"),
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("listing resources: %w", err)
+		}
+
+		for _, resource := range page.Value {
+			if resource.ID != nil && strings.EqualFold(*resource.ID, resourceId.String()) {
+				return convert.ToValueWithDefault(resource.CreatedTime, time.Time{}), nil
+			}
+		}
+	}
+
+	return time.Time{}, nil
+}
+
 func (rs *ResourceService) GetRawResource(
 	ctx context.Context, resourceId arm.ResourceID, apiVersion string) (string, error) {
 	client, err := rs.createResourcesClient(ctx, resourceId.SubscriptionID)
@@ -320,6 +382,93 @@ func (rs *ResourceService) DeleteResourceGroup(ctx context.Context, subscription
 	return nil
 }
 
+// DeleteResource deletes a resource with the latest API version of its resource type. A resource that is already
+// deleted isn't an error.
+func (rs *ResourceService) DeleteResource(ctx context.Context, resourceId *arm.ResourceID) error {
+	apiVersion, err := rs.resourceTypeApiVersion(ctx, resourceId)
+	if err != nil {
+		return err
+	}
+
+	client, err := rs.createResourcesClient(ctx, resourceId.SubscriptionID)
+	if err != nil {
+		return err
+	}
+
+	poller, err := client.BeginDeleteByID(ctx, resourceId.String(), apiVersion, nil)
+	// Resource is already deleted
+	if respErr, ok := errors.AsType[*azcore.ResponseError](err); ok &&
+		respErr.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("beginning resource deletion: %w", err)
+	}
+
+	_, err = poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("deleting resource: %w", err)
+	}
+
+	return nil
+}
+
+// resourceTypeApiVersion returns the API version used to manage resources of the type of the resource, from the
+// registration of its resource provider.
+func (rs *ResourceService) resourceTypeApiVersion(ctx context.Context, resourceId *arm.ResourceID) (string, error) {
+	credential, err := rs.credentialProvider.CredentialForSubscription(ctx, resourceId.SubscriptionID)
+	if err != nil {
+		return "", err
+	}
+
+	client, err := armresources.NewProvidersClient(resourceId.SubscriptionID, credential, rs.armClientOptions)
+	if err != nil {
+		return "", fmt.Errorf("creating Providers client: %w", err)
+	}
+
+	provider, err := client.Get(ctx, resourceId.ResourceType.Namespace, nil)
+	if err != nil {
+		return "", fmt.Errorf("getting resource provider %s: %w", resourceId.ResourceType.Namespace, err)
+	}
+
+	typeName := strings.Join(resourceId.ResourceType.Types, "/")
+	for _, resourceType := range provider.ResourceTypes {
+		if resourceType.ResourceType == nil || !strings.EqualFold(*resourceType.ResourceType, typeName) {
+			continue
+		}
+
+		if apiVersion := latestApiVersion(resourceType.APIVersions); apiVersion != "" {
+			return apiVersion, nil
+		}
+	}
+
+	return "", fmt.Errorf("no API version found for resource type %s", resourceId.ResourceType.String())
+}
+
+// latestApiVersion returns the latest stable API version, or the latest preview API version when there's no stable
+// API version.
+func latestApiVersion(apiVersions []*string) string {
+	var latestStable, latestPreview string
+	for _, apiVersion := range apiVersions {
+		if apiVersion == nil {
+			continue
+		}
+
+		if strings.HasSuffix(*apiVersion, "-preview") {
+			latestPreview = max(latestPreview, *apiVersion)
+		} else {
+			latestStable = max(latestStable, *apiVersion)
+		}
+	}
+
+	if latestStable != "" {
+		return latestStable
+	}
+
+	return latestPreview
+}
+
 // GetResourceGroup retrieves a single resource group by name, returning its metadata
 // including location.
 func (rs *ResourceService) GetResourceGroup(
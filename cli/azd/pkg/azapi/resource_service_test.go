@@ -363,6 +363,69 @@ func Test_ResourceService_DeleteResourceGroup(t *testing.T) {
 	})
 }
 
+func Test_ResourceService_DeleteResource(t *testing.T) {
+	resourceId := mustParseArmResourceID(t,
+		"/subscriptions/SUB/resourceGroups/RG/providers/Microsoft.KeyVault/vaults/kv1")
+
+	mockProvider := func(mockCtx *mocks.MockContext) {
+		mockCtx.HttpClient.When(func(req *http.Request) bool {
+			return req.Method == http.MethodGet &&
+				strings.HasSuffix(req.URL.Path, "/providers/Microsoft.KeyVault")
+		}).RespondFn(func(req *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(req, http.StatusOK, armresources.Provider{
+				Namespace: new("Microsoft.KeyVault"),
+				ResourceTypes: []*armresources.ProviderResourceType{
+					{
+						ResourceType: new("vaults"),
+						APIVersions: []*string{
+							new("2024-12-01-preview"), new("2023-07-01"), new("2024-11-01"), new("2022-07-01"),
+						},
+					},
+				},
+			})
+		})
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockCtx := mocks.NewMockContext(t.Context())
+		rs := NewResourceService(mockCtx.SubscriptionCredentialProvider, mockCtx.ArmClientOptions)
+		mockProvider(mockCtx)
+
+		var apiVersion string
+		mockCtx.HttpClient.When(func(req *http.Request) bool {
+			return req.Method == http.MethodDelete
+		}).RespondFn(func(req *http.Request) (*http.Response, error) {
+			apiVersion = req.URL.Query().Get("api-version")
+			return mocks.CreateEmptyHttpResponse(req, http.StatusOK)
+		})
+
+		err := rs.DeleteResource(*mockCtx.Context, &resourceId)
+		require.NoError(t, err)
+		assert.Equal(t, "2024-11-01", apiVersion)
+	})
+
+	t.Run("AlreadyDeleted", func(t *testing.T) {
+		mockCtx := mocks.NewMockContext(t.Context())
+		rs := NewResourceService(mockCtx.SubscriptionCredentialProvider, mockCtx.ArmClientOptions)
+		mockProvider(mockCtx)
+		mockCtx.HttpClient.When(func(req *http.Request) bool {
+			return req.Method == http.MethodDelete
+		}).RespondFn(func(req *http.Request) (*http.Response, error) {
+			return mocks.CreateEmptyHttpResponse(req, http.StatusNotFound)
+		})
+
+		err := rs.DeleteResource(*mockCtx.Context, &resourceId)
+		require.NoError(t, err) // 404 = already deleted
+	})
+}
+
+func Test_latestApiVersion(t *testing.T) {
+	assert.Equal(t, "2024-11-01", latestApiVersion([]*string{new("2023-07-01"), new("2024-11-01"), nil}))
+	assert.Equal(t, "2024-11-01", latestApiVersion([]*string{new("2025-01-01-preview"), new("2024-11-01")}))
+	assert.Equal(t, "2025-01-01-preview", latestApiVersion([]*string{new("2024-01-01-preview"), new("2025-01-01-preview")}))
+	assert.Empty(t, latestApiVersion(nil))
+}
+
 func Test_GroupByResourceGroup(t *testing.T) {
 	t.Run("GroupsCorrectly", func(t *testing.T) {
 		resources := []*armresources.ResourceReference{
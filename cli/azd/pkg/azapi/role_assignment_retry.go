@@ -0,0 +1,295 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/sethvargo/go-retry"
+)
+
+// roleAssignmentPropagationTimeoutEnvVar is the environment variable that overrides how long a deployment that creates
+// role assignments is retried while it fails with authorization errors. Setting it to 0 disables the retries.
+const roleAssignmentPropagationTimeoutEnvVar = "AZD_ROLE_ASSIGNMENT_PROPAGATION_TIMEOUT"
+
+// Role assignments can take several minutes to propagate, and until they do, the resources that rely on them fail to
+// deploy with authorization errors even though the template is correct. These are declared as vars (not consts) so
+// tests can shorten the delays.
+var (
+	// defaultRoleAssignmentPropagationTimeout is the default window in which a deployment is retried.
+	defaultRoleAssignmentPropagationTimeout = 5 * time.Minute
+
+	// roleAssignmentRetryDelay is the initial backoff between the attempts of a deployment.
+	roleAssignmentRetryDelay = 15 * time.Second
+
+	// roleAssignmentRetryMaxDelay caps the exponential backoff between the attempts of a deployment.
+	roleAssignmentRetryMaxDelay = time.Minute
+)
+
+// authorizationFailureCodes are the error codes returned by Azure while a role assignment has not propagated yet.
+var authorizationFailureCodes = []string{
+	"AuthorizationFailed",
+	"LinkedAuthorizationFailed",
+	"AuthorizationPermissionMismatch",
+	"PrincipalNotFound",
+}
+
+// roleAssignmentPropagationTimeout returns the window in which deployments are retried, from
+// AZD_ROLE_ASSIGNMENT_PROPAGATION_TIMEOUT when set to a valid duration.
+func roleAssignmentPropagationTimeout() time.Duration {
+	if value := os.Getenv(roleAssignmentPropagationTimeoutEnvVar); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err == nil && timeout >= 0 {
+			return timeout
+		}
+
+		log.Printf("ignoring invalid %s value '%s'", roleAssignmentPropagationTimeoutEnvVar, value)
+	}
+
+	return defaultRoleAssignmentPropagationTimeout
+}
+
+// roleAssignmentTarget is a role assignment created by a template.
+type roleAssignmentTarget struct {
+	// principalId is the lowercased object id of the principal the role is assigned to, or empty when the template
+	// computes it, for example from an identity that the template creates.
+	principalId string
+	// scopeType is the lowercased resource type of the scope of the role assignment, for example
+	// microsoft.storage/storageaccounts, or empty when the role is assigned at the scope of the deployment.
+	scopeType string
+}
+
+var (
+	guidRegex               = regexp.MustCompile(`^[0-9a-fA-F]{8}(-[0-9a-fA-F]{4}){3}-[0-9a-fA-F]{12}$`)
+	parameterReferenceRegex = regexp.MustCompile(`^\[parameters\('([^']+)'\)\]$`)
+	resourceTypeRegex       = regexp.MustCompile(`(?i)microsoft\.[a-z0-9]+/[a-z0-9]+`)
+	failureObjectIdRegex    = regexp.MustCompile(`(?i)object id '([0-9a-f-]{36})'`)
+	failureScopeRegex       = regexp.MustCompile(`(?i)scope '([^']+)'`)
+)
+
+// roleAssignmentTargets returns the role assignments created by the template and its nested deployments. Principals set
+// from a parameter are resolved with parameters.
+func roleAssignmentTargets(armTemplate azure.RawArmTemplate, parameters azure.ArmParameters) []roleAssignmentTarget {
+	var template any
+	if err := json.Unmarshal(armTemplate, &template); err != nil {
+		log.Printf("failed to parse the template for role assignments: %v", err)
+		return nil
+	}
+
+	var targets []roleAssignmentTarget
+	var visit func(value any)
+	visit = func(value any) {
+		switch value := value.(type) {
+		case map[string]any:
+			if resourceType, ok := value["type"].(string); ok {
+				if target, ok := newRoleAssignmentTarget(resourceType, value, parameters); ok {
+					targets = append(targets, target)
+				}
+			}
+
+			for _, child := range value {
+				visit(child)
+			}
+		case []any:
+			for _, child := range value {
+				visit(child)
+			}
+		}
+	}
+	visit(template)
+
+	return targets
+}
+
+func newRoleAssignmentTarget(
+	resourceType string,
+	resource map[string]any,
+	parameters azure.ArmParameters,
+) (roleAssignmentTarget, bool) {
+	var target roleAssignmentTarget
+
+	resourceType = strings.ToLower(resourceType)
+	switch {
+	case resourceType == "microsoft.authorization/roleassignments":
+		if scope, ok := resource["scope"].(string); ok {
+			target.scopeType = strings.ToLower(resourceTypeRegex.FindString(scope))
+		}
+	case strings.HasSuffix(resourceType, "/providers/microsoft.authorization/roleassignments"):
+		// The older syntax for role assignments as extension resources, e.g.
+		// Microsoft.Storage/storageAccounts/providers/Microsoft.Authorization/roleAssignments.
+		target.scopeType = strings.TrimSuffix(resourceType, "/providers/microsoft.authorization/roleassignments")
+	default:
+		return target, false
+	}
+
+	if properties, ok := resource["properties"].(map[string]any); ok {
+		if principalId, ok := properties["principalId"].(string); ok {
+			if match := parameterReferenceRegex.FindStringSubmatch(principalId); match != nil {
+				principalId, _ = parameters[match[1]].Value.(string)
+			}
+
+			if guidRegex.MatchString(principalId) {
+				target.principalId = strings.ToLower(principalId)
+			}
+		}
+	}
+
+	return target, true
+}
+
+// matches returns true when a failure of principalId over scopes can be caused by this role assignment not having
+// propagated yet. principalId and scopes are lowercased, and principalId is empty when the failure doesn't name it.
+func (t roleAssignmentTarget) matches(principalId string, scopes []string) bool {
+	if t.principalId != "" && t.principalId != principalId {
+		return false
+	}
+
+	if t.scopeType == "" {
+		// A role assigned at the scope of the deployment covers all of its resources, so only the principal tells the
+		// failures it can cause apart.
+		return t.principalId != ""
+	}
+
+	return slices.ContainsFunc(scopes, func(scope string) bool {
+		return strings.Contains(scope, "/providers/"+t.scopeType)
+	})
+}
+
+// authorizationFailure is an authorization failure of a deployment.
+type authorizationFailure struct {
+	code    string
+	message string
+}
+
+// authorizationFailures returns the authorization failures in err, or any of the errors of a failed deployment, that
+// can be caused by a role assignment that has not propagated yet.
+func authorizationFailures(err error) []authorizationFailure {
+	if err == nil {
+		return nil
+	}
+
+	// A principal that isn't allowed to create role assignments fails the same way, but waiting won't help.
+	if strings.Contains(strings.ToLower(err.Error()), "microsoft.authorization/roleassignments/write") {
+		return nil
+	}
+
+	if responseErr, ok := errors.AsType[*azcore.ResponseError](err); ok &&
+		slices.Contains(authorizationFailureCodes, responseErr.ErrorCode) {
+		return []authorizationFailure{{code: responseErr.ErrorCode, message: responseErr.Error()}}
+	}
+
+	var failures []authorizationFailure
+	var visit func(line *DeploymentErrorLine)
+	visit = func(line *DeploymentErrorLine) {
+		if line == nil {
+			return
+		}
+
+		if slices.Contains(authorizationFailureCodes, line.Code) {
+			failures = append(failures, authorizationFailure{code: line.Code, message: line.Message})
+		}
+
+		for _, inner := range line.Inner {
+			visit(inner)
+		}
+	}
+
+	if errorLine, ok := errors.AsType[*DeploymentErrorLine](err); ok {
+		visit(errorLine)
+	}
+
+	return failures
+}
+
+// IsAuthorizationFailure returns true when err, or any of the errors of a failed deployment, is an authorization failure
+// that can be caused by a role assignment that has not propagated yet.
+func IsAuthorizationFailure(err error) bool {
+	return len(authorizationFailures(err)) > 0
+}
+
+// isRoleAssignmentPropagationFailure returns true when err is an authorization failure on a principal or a scope that one
+// of targets assigns a role to.
+func isRoleAssignmentPropagationFailure(err error, targets []roleAssignmentTarget) bool {
+	return slices.ContainsFunc(authorizationFailures(err), func(failure authorizationFailure) bool {
+		// Azure only returns PrincipalNotFound when a role assignment of the template refers to a principal that
+		// hasn't replicated yet, usually an identity the template just created.
+		if failure.code == "PrincipalNotFound" {
+			return true
+		}
+
+		var principalId string
+		if match := failureObjectIdRegex.FindStringSubmatch(failure.message); match != nil {
+			principalId = strings.ToLower(match[1])
+		}
+
+		var scopes []string
+		for _, match := range failureScopeRegex.FindAllStringSubmatch(failure.message, -1) {
+			scopes = append(scopes, strings.ToLower(match[1]))
+		}
+
+		return slices.ContainsFunc(targets, func(target roleAssignmentTarget) bool {
+			return target.matches(principalId, scopes)
+		})
+	})
+}
+
+// deployWithRoleAssignmentRetry runs deploy and, when the template creates role assignments, deploys again with backoff
+// while the deployment fails with authorization errors on the principals or scopes of those role assignments, until the
+// propagation window elapses. Other authorization errors are returned right away. Deployments are incremental, so the
+// resources that were already created are left unchanged by the next attempts.
+func deployWithRoleAssignmentRetry(
+	ctx context.Context,
+	armTemplate azure.RawArmTemplate,
+	parameters azure.ArmParameters,
+	deploy func(ctx context.Context) (*ResourceDeployment, error),
+) (*ResourceDeployment, error) {
+	timeout := roleAssignmentPropagationTimeout()
+	if timeout == 0 {
+		return deploy(ctx)
+	}
+
+	targets := roleAssignmentTargets(armTemplate, parameters)
+	if len(targets) == 0 {
+		return deploy(ctx)
+	}
+
+	var deployment *ResourceDeployment
+	attempt := 0
+	err := retry.Do(
+		ctx,
+		retry.WithMaxDuration(
+			timeout,
+			retry.WithCappedDuration(roleAssignmentRetryMaxDelay, retry.NewExponential(roleAssignmentRetryDelay)),
+		),
+		func(ctx context.Context) error {
+			attempt++
+
+			var err error
+			deployment, err = deploy(ctx)
+			if err != nil && isRoleAssignmentPropagationFailure(err, targets) {
+				log.Printf(
+					"deployment attempt %d failed with an authorization error, "+
+						"retrying while role assignments propagate: %v", attempt, err)
+				return retry.RetryableError(err)
+			}
+
+			return err
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return deployment, nil
+}
@@ -0,0 +1,330 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/stretchr/testify/require"
+)
+
+const roleAssignmentTemplate = `{
+	"resources": [
+		{"type": "Microsoft.Storage/storageAccounts", "name": "st"},
+		{
+			"type": "Microsoft.Authorization/roleAssignments",
+			"name": "ra",
+			"scope": "[format('Microsoft.Storage/storageAccounts/{0}', 'st')]",
+			"properties": {"principalId": "[parameters('principalId')]"}
+		}
+	]
+}`
+
+const (
+	testPrincipalId = "6f1a9a3e-5b52-4c3c-9d2e-0f4b7a8c1d2e"
+	storageScope    = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/st"
+	keyVaultScope   = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.KeyVault/vaults/kv"
+)
+
+var roleAssignmentParameters = azure.ArmParameters{"principalId": {Value: testPrincipalId}}
+
+func shortenRoleAssignmentRetry(t *testing.T, timeout time.Duration) {
+	defaultTimeout, delay, maxDelay :=
+		defaultRoleAssignmentPropagationTimeout, roleAssignmentRetryDelay, roleAssignmentRetryMaxDelay
+	t.Cleanup(func() {
+		defaultRoleAssignmentPropagationTimeout = defaultTimeout
+		roleAssignmentRetryDelay = delay
+		roleAssignmentRetryMaxDelay = maxDelay
+	})
+
+	defaultRoleAssignmentPropagationTimeout = timeout
+	roleAssignmentRetryDelay = time.Millisecond
+	roleAssignmentRetryMaxDelay = 5 * time.Millisecond
+}
+
+func authorizationFailedDeploymentError() error {
+	return authorizationFailedOnScopeDeploymentError(testPrincipalId, storageScope)
+}
+
+func authorizationFailedOnScopeDeploymentError(principalId string, scope string) error {
+	return NewAzureDeploymentError("Deployment Error Details", fmt.Sprintf(`{
+		"error": {
+			"code": "DeploymentFailed",
+			"message": "At least one resource deployment operation failed.",
+			"details": [
+				{
+					"code": "AuthorizationFailed",
+					"message": "The client with object id '%s' can't perform action 'read' over scope '%s'."
+				}
+			]
+		}
+	}`, principalId, scope), DeploymentOperationDeploy)
+}
+
+func TestIsAuthorizationFailure(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"nil": {
+			err:      nil,
+			expected: false,
+		},
+		"ResponseError": {
+			err:      &azcore.ResponseError{ErrorCode: "AuthorizationPermissionMismatch"},
+			expected: true,
+		},
+		"Forbidden": {
+			err:      &azcore.ResponseError{ErrorCode: "Forbidden"},
+			expected: false,
+		},
+		"NestedDeploymentError": {
+			err:      authorizationFailedDeploymentError(),
+			expected: true,
+		},
+		"OtherDeploymentError": {
+			err: NewAzureDeploymentError("Deployment Error Details",
+				`{"error": {"code": "DeploymentFailed", "details": [{"code": "InvalidTemplate"}]}}`,
+				DeploymentOperationDeploy),
+			expected: false,
+		},
+		"RoleAssignmentWriteDenied": {
+			err: NewAzureDeploymentError("Deployment Error Details", `{"error": {
+				"code": "AuthorizationFailed",
+				"message": "does not have authorization to perform action 'Microsoft.Authorization/roleAssignments/write'"
+			}}`, DeploymentOperationDeploy),
+			expected: false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.expected, IsAuthorizationFailure(tt.err))
+		})
+	}
+}
+
+func TestRoleAssignmentPropagationTimeout(t *testing.T) {
+	t.Setenv(roleAssignmentPropagationTimeoutEnvVar, "")
+	require.Equal(t, defaultRoleAssignmentPropagationTimeout, roleAssignmentPropagationTimeout())
+
+	t.Setenv(roleAssignmentPropagationTimeoutEnvVar, "90s")
+	require.Equal(t, 90*time.Second, roleAssignmentPropagationTimeout())
+
+	t.Setenv(roleAssignmentPropagationTimeoutEnvVar, "0")
+	require.Equal(t, time.Duration(0), roleAssignmentPropagationTimeout())
+
+	t.Setenv(roleAssignmentPropagationTimeoutEnvVar, "soon")
+	require.Equal(t, defaultRoleAssignmentPropagationTimeout, roleAssignmentPropagationTimeout())
+}
+
+func TestDeployWithRoleAssignmentRetry(t *testing.T) {
+	t.Run("RetriesUntilRoleAssignmentsPropagate", func(t *testing.T) {
+		shortenRoleAssignmentRetry(t, time.Minute)
+
+		attempts := 0
+		deployment, err := deployWithRoleAssignmentRetry(
+			t.Context(),
+			azure.RawArmTemplate(roleAssignmentTemplate),
+			roleAssignmentParameters,
+			func(ctx context.Context) (*ResourceDeployment, error) {
+				attempts++
+				if attempts < 3 {
+					return nil, authorizationFailedDeploymentError()
+				}
+
+				return &ResourceDeployment{Name: "deployment"}, nil
+			},
+		)
+		require.NoError(t, err)
+		require.Equal(t, "deployment", deployment.Name)
+		require.Equal(t, 3, attempts)
+	})
+
+	t.Run("SurfacesErrorAfterWindow", func(t *testing.T) {
+		shortenRoleAssignmentRetry(t, 20*time.Millisecond)
+
+		attempts := 0
+		_, err := deployWithRoleAssignmentRetry(
+			t.Context(),
+			azure.RawArmTemplate(roleAssignmentTemplate),
+			roleAssignmentParameters,
+			func(ctx context.Context) (*ResourceDeployment, error) {
+				attempts++
+				return nil, authorizationFailedDeploymentError()
+			},
+		)
+		require.True(t, IsAuthorizationFailure(err))
+		require.Greater(t, attempts, 1)
+	})
+
+	t.Run("DoesNotRetryOtherErrors", func(t *testing.T) {
+		shortenRoleAssignmentRetry(t, time.Minute)
+
+		attempts := 0
+		_, err := deployWithRoleAssignmentRetry(
+			t.Context(),
+			azure.RawArmTemplate(roleAssignmentTemplate),
+			roleAssignmentParameters,
+			func(ctx context.Context) (*ResourceDeployment, error) {
+				attempts++
+				return nil, errors.New("invalid template")
+			},
+		)
+		require.EqualError(t, err, "invalid template")
+		require.Equal(t, 1, attempts)
+	})
+
+	t.Run("DoesNotRetryUnrelatedAuthorizationFailures", func(t *testing.T) {
+		shortenRoleAssignmentRetry(t, time.Minute)
+
+		attempts := 0
+		_, err := deployWithRoleAssignmentRetry(
+			t.Context(),
+			azure.RawArmTemplate(roleAssignmentTemplate),
+			roleAssignmentParameters,
+			func(ctx context.Context) (*ResourceDeployment, error) {
+				attempts++
+				return nil, authorizationFailedOnScopeDeploymentError(testPrincipalId, keyVaultScope)
+			},
+		)
+		require.True(t, IsAuthorizationFailure(err))
+		require.Equal(t, 1, attempts)
+	})
+
+	t.Run("DoesNotRetryWithoutRoleAssignments", func(t *testing.T) {
+		shortenRoleAssignmentRetry(t, time.Minute)
+
+		attempts := 0
+		_, err := deployWithRoleAssignmentRetry(
+			t.Context(),
+			azure.RawArmTemplate(`{"resources": [{"type": "Microsoft.Storage/storageAccounts"}]}`),
+			nil,
+			func(ctx context.Context) (*ResourceDeployment, error) {
+				attempts++
+				return nil, authorizationFailedDeploymentError()
+			},
+		)
+		require.Error(t, err)
+		require.Equal(t, 1, attempts)
+	})
+
+	t.Run("DisabledByEnvironmentVariable", func(t *testing.T) {
+		shortenRoleAssignmentRetry(t, time.Minute)
+		t.Setenv(roleAssignmentPropagationTimeoutEnvVar, "0")
+
+		attempts := 0
+		_, err := deployWithRoleAssignmentRetry(
+			t.Context(),
+			azure.RawArmTemplate(roleAssignmentTemplate),
+			roleAssignmentParameters,
+			func(ctx context.Context) (*ResourceDeployment, error) {
+				attempts++
+				return nil, authorizationFailedDeploymentError()
+			},
+		)
+		require.Error(t, err)
+		require.Equal(t, 1, attempts)
+	})
+}
+
+func TestRoleAssignmentTargets(t *testing.T) {
+	template := `{
+		"parameters": {"principalId": {"type": "string"}},
+		"resources": {
+			"ra": {
+				"type": "Microsoft.Authorization/roleAssignments",
+				"properties": {"principalId": "[parameters('principalId')]"}
+			},
+			"nested": {
+				"type": "Microsoft.Resources/deployments",
+				"properties": {"template": {"resources": [
+					{
+						"type": "Microsoft.Authorization/roleAssignments",
+						"scope": "[resourceId('Microsoft.KeyVault/vaults', 'kv')]",
+						"properties": {"principalId": "[reference('identity').principalId]"}
+					},
+					{
+						"type": "Microsoft.Storage/storageAccounts/providers/Microsoft.Authorization/roleAssignments",
+						"properties": {"principalId": "A1B2C3D4-0000-0000-0000-000000000000"}
+					}
+				]}}
+			}
+		}
+	}`
+
+	targets := roleAssignmentTargets(azure.RawArmTemplate(template), roleAssignmentParameters)
+	require.ElementsMatch(t, []roleAssignmentTarget{
+		{principalId: testPrincipalId},
+		{scopeType: "microsoft.keyvault/vaults"},
+		{principalId: "a1b2c3d4-0000-0000-0000-000000000000", scopeType: "microsoft.storage/storageaccounts"},
+	}, targets)
+
+	require.Empty(t, roleAssignmentTargets(azure.RawArmTemplate(`not json`), nil))
+}
+
+func TestIsRoleAssignmentPropagationFailure(t *testing.T) {
+	const otherPrincipalId = "00000000-0000-0000-0000-000000000001"
+
+	tests := map[string]struct {
+		targets  []roleAssignmentTarget
+		err      error
+		expected bool
+	}{
+		"PrincipalAndScope": {
+			targets:  []roleAssignmentTarget{{principalId: testPrincipalId, scopeType: "microsoft.storage/storageaccounts"}},
+			err:      authorizationFailedOnScopeDeploymentError(testPrincipalId, storageScope),
+			expected: true,
+		},
+		"OtherPrincipal": {
+			targets:  []roleAssignmentTarget{{principalId: testPrincipalId, scopeType: "microsoft.storage/storageaccounts"}},
+			err:      authorizationFailedOnScopeDeploymentError(otherPrincipalId, storageScope),
+			expected: false,
+		},
+		"OtherScope": {
+			targets:  []roleAssignmentTarget{{principalId: testPrincipalId, scopeType: "microsoft.storage/storageaccounts"}},
+			err:      authorizationFailedOnScopeDeploymentError(testPrincipalId, keyVaultScope),
+			expected: false,
+		},
+		"DeploymentScopeWithPrincipal": {
+			targets:  []roleAssignmentTarget{{principalId: testPrincipalId}},
+			err:      authorizationFailedOnScopeDeploymentError(testPrincipalId, keyVaultScope),
+			expected: true,
+		},
+		"ComputedPrincipalOnScope": {
+			targets:  []roleAssignmentTarget{{scopeType: "microsoft.keyvault/vaults"}},
+			err:      authorizationFailedOnScopeDeploymentError(otherPrincipalId, keyVaultScope),
+			expected: true,
+		},
+		"ComputedPrincipalAtDeploymentScope": {
+			targets:  []roleAssignmentTarget{{}},
+			err:      authorizationFailedOnScopeDeploymentError(otherPrincipalId, keyVaultScope),
+			expected: false,
+		},
+		"PrincipalNotFound": {
+			targets: []roleAssignmentTarget{{}},
+			err: NewAzureDeploymentError("Deployment Error Details",
+				`{"error": {"code": "PrincipalNotFound", "message": "Principal does not exist in the directory."}}`,
+				DeploymentOperationDeploy),
+			expected: true,
+		},
+		"NotAuthorizationFailure": {
+			targets:  []roleAssignmentTarget{{principalId: testPrincipalId}},
+			err:      errors.New("invalid template"),
+			expected: false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.expected, isRoleAssignmentPropagationFailure(tt.err, tt.targets))
+		})
+	}
+}
@@ -267,19 +267,23 @@ func (d *StackDeployments) DeployToSubscription(
 		return nil, err
 	}
 
-	poller, err := client.BeginCreateOrUpdateAtSubscription(ctx, deploymentName, stack, nil)
-	if err != nil {
-		return nil, err
-	}
+	return deployWithRoleAssignmentRetry(ctx, armTemplate, parameters, func(
+		ctx context.Context,
+	) (*ResourceDeployment, error) {
+		poller, err := client.BeginCreateOrUpdateAtSubscription(ctx, deploymentName, stack, nil)
+		if err != nil {
+			return nil, err
+		}
 
-	_, err = poller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{
-		Frequency: deployPollFrequency,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("deploying to subscription: %w", createDeploymentError(err, DeploymentOperationDeploy))
-	}
+		_, err = poller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{
+			Frequency: deployPollFrequency,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("deploying to subscription: %w", createDeploymentError(err, DeploymentOperationDeploy))
+		}
 
-	return d.GetSubscriptionDeployment(ctx, subscriptionId, deploymentName)
+		return d.GetSubscriptionDeployment(ctx, subscriptionId, deploymentName)
+	})
 }
 
 func (d *StackDeployments) stackFromArmForResourceGroup(
@@ -346,19 +350,23 @@ func (d *StackDeployments) DeployToResourceGroup(
 		return nil, err
 	}
 
-	poller, err := client.BeginCreateOrUpdateAtResourceGroup(ctx, resourceGroup, deploymentName, stack, nil)
-	if err != nil {
-		return nil, err
-	}
+	return deployWithRoleAssignmentRetry(ctx, armTemplate, parameters, func(
+		ctx context.Context,
+	) (*ResourceDeployment, error) {
+		poller, err := client.BeginCreateOrUpdateAtResourceGroup(ctx, resourceGroup, deploymentName, stack, nil)
+		if err != nil {
+			return nil, err
+		}
 
-	_, err = poller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{
-		Frequency: deployPollFrequency,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("deploying to resource group: %w", createDeploymentError(err, DeploymentOperationDeploy))
-	}
+		_, err = poller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{
+			Frequency: deployPollFrequency,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("deploying to resource group: %w", createDeploymentError(err, DeploymentOperationDeploy))
+		}
 
-	return d.GetResourceGroupDeployment(ctx, subscriptionId, resourceGroup, deploymentName)
+		return d.GetResourceGroupDeployment(ctx, subscriptionId, resourceGroup, deploymentName)
+	})
 }
 
 func (d *StackDeployments) ListSubscriptionDeploymentOperations(
@@ -793,14 +801,18 @@ func convertToStackParams(parameters azure.ArmParameters) map[string]*armdeploym
 	stackParams := map[string]*armdeploymentstacks.DeploymentParameter{}
 	for k, v := range parameters {
 		if v.KeyVaultReference != nil {
-			stackParams[k] = &armdeploymentstacks.DeploymentParameter{
-				Reference: &armdeploymentstacks.KeyVaultParameterReference{
-					KeyVault: &armdeploymentstacks.KeyVaultReference{
-						ID: &v.KeyVaultReference.KeyVault.ID,
-					},
-					SecretName:    &v.KeyVaultReference.SecretName,
-					SecretVersion: &v.KeyVaultReference.SecretVersion,
+			reference := &armdeploymentstacks.KeyVaultParameterReference{
+				KeyVault: &armdeploymentstacks.KeyVaultReference{
+					ID: &v.KeyVaultReference.KeyVault.ID,
 				},
+				SecretName: &v.KeyVaultReference.SecretName,
+			}
+			// An empty version references the latest version of the secret.
+			if v.KeyVaultReference.SecretVersion != "" {
+				reference.SecretVersion = &v.KeyVaultReference.SecretVersion
+			}
+			stackParams[k] = &armdeploymentstacks.DeploymentParameter{
+				Reference: reference,
 			}
 		} else {
 			stackParams[k] = &armdeploymentstacks.DeploymentParameter{
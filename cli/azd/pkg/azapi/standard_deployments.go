@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"maps"
 	"net/http"
 	"net/url"
@@ -48,6 +49,9 @@ const (
 	// These are consistently slow (30-90s), so aggressive polling provides no benefit and
 	// risks hitting the ARM read rate limit (1200 reads/5min) during large parallel deployments.
 	slowPollFrequency = 15 * time.Second
+
+	// maxResourceGroupDeletionAttempts is the number of passes made to delete the resource groups of a deployment.
+	maxResourceGroupDeletionAttempts = 3
 )
 
 // Retry tuning for transient HTTP 404 (DeploymentNotFound) responses returned while
@@ -279,35 +283,39 @@ func (ds *StandardDeployments) DeployToSubscription(
 		return nil, fmt.Errorf("creating deployments client: %w", err)
 	}
 
-	createFromTemplateOperation, err := deploymentClient.BeginCreateOrUpdateAtSubscriptionScope(
-		ctx, deploymentName,
-		armresources.Deployment{
-			Properties: &armresources.DeploymentProperties{
-				Template:   armTemplate,
-				Parameters: parameters,
-				Mode:       to.Ptr(armresources.DeploymentModeIncremental),
-			},
-			Location: new(location),
-			Tags:     tags,
-		}, nil)
-	if err != nil {
-		return nil, fmt.Errorf("starting deployment to subscription: %w", err)
-	}
+	return deployWithRoleAssignmentRetry(ctx, armTemplate, parameters, func(
+		ctx context.Context,
+	) (*ResourceDeployment, error) {
+		createFromTemplateOperation, err := deploymentClient.BeginCreateOrUpdateAtSubscriptionScope(
+			ctx, deploymentName,
+			armresources.Deployment{
+				Properties: &armresources.DeploymentProperties{
+					Template:   armTemplate,
+					Parameters: parameters,
+					Mode:       to.Ptr(armresources.DeploymentModeIncremental),
+				},
+				Location: new(location),
+				Tags:     tags,
+			}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("starting deployment to subscription: %w", err)
+		}
 
-	// Retry transient DeploymentNotFound (404) responses that ARM can return while polling a
-	// subscription-scoped deployment that was just submitted (read-after-write inconsistency).
-	// Scoped to the poller only so a genuine submit-time 404 still fails fast.
-	pollCtx := withDeploymentRetry(ctx)
+		// Retry transient DeploymentNotFound (404) responses that ARM can return while polling a
+		// subscription-scoped deployment that was just submitted (read-after-write inconsistency).
+		// Scoped to the poller only so a genuine submit-time 404 still fails fast.
+		pollCtx := withDeploymentRetry(ctx)
 
-	// wait for deployment creation
-	deployResult, err := createFromTemplateOperation.PollUntilDone(pollCtx, &runtime.PollUntilDoneOptions{
-		Frequency: deployPollFrequency,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("deploying to subscription: %w", createDeploymentError(err, DeploymentOperationDeploy))
-	}
+		// wait for deployment creation
+		deployResult, err := createFromTemplateOperation.PollUntilDone(pollCtx, &runtime.PollUntilDoneOptions{
+			Frequency: deployPollFrequency,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("deploying to subscription: %w", createDeploymentError(err, DeploymentOperationDeploy))
+		}
 
-	return ds.convertFromArmDeployment(&deployResult.DeploymentExtended), nil
+		return ds.convertFromArmDeployment(&deployResult.DeploymentExtended), nil
+	})
 }
 
 func (ds *StandardDeployments) DeployToResourceGroup(
@@ -326,34 +334,38 @@ func (ds *StandardDeployments) DeployToResourceGroup(
 		return nil, fmt.Errorf("creating deployments client: %w", err)
 	}
 
-	createFromTemplateOperation, err := deploymentClient.BeginCreateOrUpdate(
-		ctx, resourceGroup, deploymentName,
-		armresources.Deployment{
-			Properties: &armresources.DeploymentProperties{
-				Template:   armTemplate,
-				Parameters: parameters,
-				Mode:       to.Ptr(armresources.DeploymentModeIncremental),
-			},
-			Tags: tags,
-		}, nil)
-	if err != nil {
-		return nil, fmt.Errorf("starting deployment to resource group: %w", err)
-	}
+	return deployWithRoleAssignmentRetry(ctx, armTemplate, parameters, func(
+		ctx context.Context,
+	) (*ResourceDeployment, error) {
+		createFromTemplateOperation, err := deploymentClient.BeginCreateOrUpdate(
+			ctx, resourceGroup, deploymentName,
+			armresources.Deployment{
+				Properties: &armresources.DeploymentProperties{
+					Template:   armTemplate,
+					Parameters: parameters,
+					Mode:       to.Ptr(armresources.DeploymentModeIncremental),
+				},
+				Tags: tags,
+			}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("starting deployment to resource group: %w", err)
+		}
 
-	// Retry transient DeploymentNotFound (404) responses that ARM can return while polling a
-	// deployment that was just submitted (read-after-write inconsistency). Scoped to the poller
-	// only so a genuine submit-time 404 (e.g. missing resource group) still fails fast.
-	pollCtx := withDeploymentRetry(ctx)
+		// Retry transient DeploymentNotFound (404) responses that ARM can return while polling a
+		// deployment that was just submitted (read-after-write inconsistency). Scoped to the poller
+		// only so a genuine submit-time 404 (e.g. missing resource group) still fails fast.
+		pollCtx := withDeploymentRetry(ctx)
 
-	// wait for deployment creation
-	deployResult, err := createFromTemplateOperation.PollUntilDone(pollCtx, &runtime.PollUntilDoneOptions{
-		Frequency: deployPollFrequency,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("deploying to resource group: %w", createDeploymentError(err, DeploymentOperationDeploy))
-	}
+		// wait for deployment creation
+		deployResult, err := createFromTemplateOperation.PollUntilDone(pollCtx, &runtime.PollUntilDoneOptions{
+			Frequency: deployPollFrequency,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("deploying to resource group: %w", createDeploymentError(err, DeploymentOperationDeploy))
+		}
 
-	return ds.convertFromArmDeployment(&deployResult.DeploymentExtended), nil
+		return ds.convertFromArmDeployment(&deployResult.DeploymentExtended), nil
+	})
 }
 
 func (ds *StandardDeployments) ListSubscriptionDeploymentOperations(
@@ -533,28 +545,49 @@ func (ds *StandardDeployments) DeleteSubscriptionDeployment(
 		resourceGroups[resourceId.ResourceGroupName] = struct{}{}
 	}
 
-	for resourceGroup := range resourceGroups {
-		progress.SetProgress(DeleteDeploymentProgress{
-			Name:    resourceGroup,
-			Message: fmt.Sprintf("Deleting resource group %s", output.WithHighLightFormat(resourceGroup)),
-			State:   DeleteResourceStateInProgress,
-		})
+	// A resource group can fail to delete while a resource of another resource group still depends on its resources,
+	// for example a private endpoint in a subnet of its virtual network. The failed resource groups are deleted again
+	// once the other resource groups are deleted.
+	pending := slices.Sorted(maps.Keys(resourceGroups))
+	for attempt := 1; len(pending) > 0; attempt++ {
+		failed := []string{}
+		errs := []error{}
+		for _, resourceGroup := range pending {
+			progress.SetProgress(DeleteDeploymentProgress{
+				Name:    resourceGroup,
+				Message: fmt.Sprintf("Deleting resource group %s", output.WithHighLightFormat(resourceGroup)),
+				State:   DeleteResourceStateInProgress,
+			})
+
+			if err := ds.resourceService.DeleteResourceGroup(ctx, subscriptionId, resourceGroup); err != nil {
+				log.Printf("deleting resource group '%s' failed (attempt %d): %v", resourceGroup, attempt, err)
+				failed = append(failed, resourceGroup)
+				errs = append(errs, err)
+				continue
+			}
 
-		if err := ds.resourceService.DeleteResourceGroup(ctx, subscriptionId, resourceGroup); err != nil {
 			progress.SetProgress(DeleteDeploymentProgress{
 				Name:    resourceGroup,
-				Message: fmt.Sprintf("Failed deleting resource group %s", output.WithHighLightFormat(resourceGroup)),
-				State:   DeleteResourceStateFailed,
+				Message: fmt.Sprintf("Deleted resource group %s", output.WithHighLightFormat(resourceGroup)),
+				State:   DeleteResourceStateSucceeded,
 			})
+		}
 
-			return err
+		// Stop when no resource group could be deleted in this pass, since the next pass wouldn't delete more.
+		if len(failed) > 0 && (len(failed) == len(pending) || attempt == maxResourceGroupDeletionAttempts) {
+			for _, resourceGroup := range failed {
+				progress.SetProgress(DeleteDeploymentProgress{
+					Name: resourceGroup,
+					Message: fmt.Sprintf(
+						"Failed deleting resource group %s", output.WithHighLightFormat(resourceGroup)),
+					State: DeleteResourceStateFailed,
+				})
+			}
+
+			return errors.Join(errs...)
 		}
 
-		progress.SetProgress(DeleteDeploymentProgress{
-			Name:    resourceGroup,
-			Message: fmt.Sprintf("Deleted resource group %s", output.WithHighLightFormat(resourceGroup)),
-			State:   DeleteResourceStateSucceeded,
-		})
+		pending = failed
 	}
 
 	// Void the deployment state
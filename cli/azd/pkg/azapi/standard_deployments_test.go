@@ -6,6 +6,7 @@ package azapi
 import (
 	"encoding/json"
 	"net/http"
+	"slices"
 	"sort"
 	"strings"
 	"sync/atomic"
@@ -17,6 +18,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
@@ -288,6 +290,51 @@ func Test_StdDeployments_ListSubscriptionDeploymentResources_OnlyCreatedResource
 	assert.Equal(t, "/subscriptions/SUB/resourceGroups/RG1/providers/Microsoft.Web/sites/app1", *resources[1].ID)
 }
 
+func Test_StdDeployments_DeleteSubscriptionDeployment_RetriesDependentResourceGroups(t *testing.T) {
+	mockCtx := mocks.NewMockContext(t.Context())
+	sd := newStdDeployments(mockCtx)
+
+	mockCtx.HttpClient.When(func(req *http.Request) bool {
+		return req.Method == http.MethodGet &&
+			strings.Contains(strings.ToLower(req.URL.Path), "/providers/microsoft.resources/deployments/deploy1")
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		dep := makeDeploymentExtended("deploy1", armresources.ProvisioningStateSucceeded)
+		dep.Properties.OutputResources = []*armresources.ResourceReference{
+			{ID: new("/subscriptions/SUB/resourceGroups/RG-NETWORK")},
+			{ID: new("/subscriptions/SUB/resourceGroups/RG-WEB")},
+		}
+
+		return mocks.CreateHttpResponseWithBody(req, http.StatusOK, dep)
+	})
+
+	mockCtx.HttpClient.When(func(req *http.Request) bool {
+		return req.Method == http.MethodGet && strings.HasSuffix(strings.ToLower(req.URL.Path), "/resources")
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(req, http.StatusOK, armresources.ResourceListResult{})
+	})
+
+	// The virtual network of RG-NETWORK can't be deleted while the private endpoint of RG-WEB uses its subnet.
+	deleted := []string{}
+	mockCtx.HttpClient.When(func(req *http.Request) bool {
+		return req.Method == http.MethodDelete
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		resourceGroup := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+		if resourceGroup == "RG-NETWORK" && !slices.Contains(deleted, "RG-WEB") {
+			return mocks.CreateHttpResponseWithBody(req, http.StatusConflict, map[string]any{
+				"error": map[string]any{"code": "InUseSubnetCannotBeDeleted"},
+			})
+		}
+
+		deleted = append(deleted, resourceGroup)
+		return mocks.CreateEmptyHttpResponse(req, http.StatusOK)
+	})
+
+	err := sd.DeleteSubscriptionDeployment(
+		*mockCtx.Context, "SUB", "deploy1", map[string]any{}, async.NewNoopProgress[DeleteDeploymentProgress]())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"RG-WEB", "RG-NETWORK"}, deleted)
+}
+
 func Test_StdDeployments_ListResourceGroupDeployments(t *testing.T) {
 	mockCtx := mocks.NewMockContext(t.Context())
 	sd := newStdDeployments(mockCtx)
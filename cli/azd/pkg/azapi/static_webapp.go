@@ -7,7 +7,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
 )
 
@@ -88,6 +90,36 @@ func (cli *AzureClient) GetStaticWebAppApiKey(
 	return apiKey, nil
 }
 
+// DeleteStaticWebAppEnvironment deletes a preview environment of a static web app. An environment that doesn't exist,
+// for example because the static web app was deleted, is not an error.
+func (cli *AzureClient) DeleteStaticWebAppEnvironment(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	environmentName string,
+) error {
+	client, err := cli.createStaticSitesClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	poller, err := client.BeginDeleteStaticSiteBuild(ctx, resourceGroup, appName, environmentName, nil)
+	if err == nil {
+		_, err = poller.PollUntilDone(ctx, nil)
+	}
+
+	if respErr, ok := errors.AsType[*azcore.ResponseError](err); ok && respErr.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("deleting static site environment '%s': %w", environmentName, err)
+	}
+
+	return nil
+}
+
 func (cli *AzureClient) createStaticSitesClient(
 	ctx context.Context,
 	subscriptionId string,
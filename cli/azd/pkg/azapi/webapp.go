@@ -11,10 +11,12 @@ import (
 	"log"
 	"maps"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/events"
@@ -539,16 +541,8 @@ func (cli *AzureClient) UpdateAppServiceAppSettings(
 	}
 
 	// Merge: existing settings + new env vars (new values overwrite)
-	merged := make(map[string]*string)
-	if existing.Properties != nil {
-		maps.Copy(merged, existing.Properties)
-	}
-	for k, v := range envVars {
-		merged[k] = &v
-	}
-
 	_, err = client.UpdateApplicationSettings(ctx, resourceGroup, appName,
-		armappservice.StringDictionary{Properties: merged}, nil)
+		armappservice.StringDictionary{Properties: mergeAppSettings(existing.Properties, envVars)}, nil)
 	if err != nil {
 		return fmt.Errorf("updating app settings for %s: %w", appName, err)
 	}
@@ -604,3 +598,261 @@ func (cli *AzureClient) DeployAppServiceSlotZip(
 
 	return &response.StatusText, nil
 }
+
+// CreateAppServiceSlot creates a deployment slot for the specified web app. The slot runs on the App Service plan of the
+// web app, and starts with a copy of its site configuration and application settings, so that the deployed package or
+// container image runs in the slot like it runs in production.
+func (cli *AzureClient) CreateAppServiceSlot(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	slotName string,
+) error {
+	client, err := cli.createWebAppsClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	app, err := client.Get(ctx, resourceGroup, appName, nil)
+	if err != nil {
+		return fmt.Errorf("retrieving webapp %s: %w", appName, err)
+	}
+
+	config, err := client.GetConfiguration(ctx, resourceGroup, appName, nil)
+	if err != nil {
+		return fmt.Errorf("retrieving configuration of webapp %s: %w", appName, err)
+	}
+
+	appSettings, err := client.ListApplicationSettings(ctx, resourceGroup, appName, nil)
+	if err != nil {
+		return fmt.Errorf("listing app settings for %s: %w", appName, err)
+	}
+
+	siteConfig := config.Properties
+	if siteConfig == nil {
+		siteConfig = &armappservice.SiteConfig{}
+	}
+	siteConfig.AppSettings = nil
+	for _, name := range slices.Sorted(maps.Keys(appSettings.Properties)) {
+		siteConfig.AppSettings = append(siteConfig.AppSettings, &armappservice.NameValuePair{
+			Name:  &name,
+			Value: appSettings.Properties[name],
+		})
+	}
+
+	slot := armappservice.Site{
+		Location: app.Location,
+		Kind:     app.Kind,
+		Tags:     app.Tags,
+		Properties: &armappservice.SiteProperties{
+			SiteConfig: siteConfig,
+		},
+	}
+
+	if app.Properties != nil {
+		slot.Properties.ServerFarmID = app.Properties.ServerFarmID
+		slot.Properties.HTTPSOnly = app.Properties.HTTPSOnly
+		slot.Properties.VirtualNetworkSubnetID = app.Properties.VirtualNetworkSubnetID
+	}
+
+	// The slot gets its own system-assigned identity, and shares the user-assigned identities of the web app.
+	if app.Identity != nil && app.Identity.Type != nil {
+		slot.Identity = &armappservice.ManagedServiceIdentity{Type: app.Identity.Type}
+		if len(app.Identity.UserAssignedIdentities) > 0 {
+			slot.Identity.UserAssignedIdentities = map[string]*armappservice.UserAssignedIdentity{}
+			for id := range app.Identity.UserAssignedIdentities {
+				slot.Identity.UserAssignedIdentities[id] = &armappservice.UserAssignedIdentity{}
+			}
+		}
+	}
+
+	poller, err := client.BeginCreateOrUpdateSlot(ctx, resourceGroup, appName, slotName, slot, nil)
+	if err != nil {
+		return fmt.Errorf("starting creation of webapp slot %s: %w", slotName, err)
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return fmt.Errorf("creating webapp slot %s: %w", slotName, err)
+	}
+
+	return nil
+}
+
+// SwapAppServiceSlot swaps a deployment slot with the production slot of the specified web app, so that the app
+// deployed to the slot serves the production traffic, and the slot runs the app that was in production.
+func (cli *AzureClient) SwapAppServiceSlot(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	slotName string,
+) error {
+	client, err := cli.createWebAppsClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	poller, err := client.BeginSwapSlotWithProduction(ctx, resourceGroup, appName, armappservice.CsmSlotEntity{
+		TargetSlot:   &slotName,
+		PreserveVnet: to.Ptr(true),
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("starting swap of webapp slot %s: %w", slotName, err)
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return fmt.Errorf("swapping webapp slot %s with production: %w", slotName, err)
+	}
+
+	return nil
+}
+
+// UpdateAppServiceSlotAppSettings merges the provided environment variables into the application settings of a
+// deployment slot. Existing settings not in the provided map are preserved.
+func (cli *AzureClient) UpdateAppServiceSlotAppSettings(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	slotName string,
+	envVars map[string]string,
+) error {
+	client, err := cli.createWebAppsClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	existing, err := client.ListApplicationSettingsSlot(ctx, resourceGroup, appName, slotName, nil)
+	if err != nil {
+		return fmt.Errorf("listing app settings for %s slot %s: %w", appName, slotName, err)
+	}
+
+	_, err = client.UpdateApplicationSettingsSlot(ctx, resourceGroup, appName, slotName,
+		armappservice.StringDictionary{Properties: mergeAppSettings(existing.Properties, envVars)}, nil)
+	if err != nil {
+		return fmt.Errorf("updating app settings for %s slot %s: %w", appName, slotName, err)
+	}
+
+	return nil
+}
+
+// GetAppServiceAppSettings gets the application settings of an App Service or a function app, or of its deployment slot
+// when slotName isn't empty.
+func (cli *AzureClient) GetAppServiceAppSettings(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	slotName string,
+) (map[string]*string, error) {
+	client, err := cli.createWebAppsClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	var settings armappservice.StringDictionary
+	if slotName == "" {
+		response, err := client.ListApplicationSettings(ctx, resourceGroup, appName, nil)
+		if err != nil {
+			return nil, fmt.Errorf("listing app settings for %s: %w", appName, err)
+		}
+		settings = response.StringDictionary
+	} else {
+		response, err := client.ListApplicationSettingsSlot(ctx, resourceGroup, appName, slotName, nil)
+		if err != nil {
+			return nil, fmt.Errorf("listing app settings for %s slot %s: %w", appName, slotName, err)
+		}
+		settings = response.StringDictionary
+	}
+
+	if settings.Properties == nil {
+		return map[string]*string{}, nil
+	}
+
+	return settings.Properties, nil
+}
+
+// ReplaceAppServiceAppSettings replaces all the application settings of an App Service or a function app, or of its
+// deployment slot when slotName isn't empty. Settings that aren't in settings are removed.
+func (cli *AzureClient) ReplaceAppServiceAppSettings(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	slotName string,
+	settings map[string]*string,
+) error {
+	client, err := cli.createWebAppsClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	if slotName == "" {
+		_, err = client.UpdateApplicationSettings(
+			ctx, resourceGroup, appName, armappservice.StringDictionary{Properties: settings}, nil)
+		if err != nil {
+			return fmt.Errorf("updating app settings for %s: %w", appName, err)
+		}
+
+		return nil
+	}
+
+	_, err = client.UpdateApplicationSettingsSlot(
+		ctx, resourceGroup, appName, slotName, armappservice.StringDictionary{Properties: settings}, nil)
+	if err != nil {
+		return fmt.Errorf("updating app settings for %s slot %s: %w", appName, slotName, err)
+	}
+
+	return nil
+}
+
+// GetAppServiceKeyVaultReference returns the resolution status of the Key Vault reference in an application setting
+// of an App Service or a function app, or of its deployment slot when slotName isn't empty.
+func (cli *AzureClient) GetAppServiceKeyVaultReference(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	slotName string,
+	settingName string,
+) (*armappservice.APIKVReferenceProperties, error) {
+	client, err := cli.createWebAppsClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	var reference armappservice.APIKVReference
+	if slotName == "" {
+		response, err := client.GetAppSettingKeyVaultReference(ctx, resourceGroup, appName, settingName, nil)
+		if err != nil {
+			return nil, fmt.Errorf("getting Key Vault reference of app setting %s for %s: %w", settingName, appName, err)
+		}
+		reference = response.APIKVReference
+	} else {
+		response, err := client.GetAppSettingKeyVaultReferenceSlot(
+			ctx, resourceGroup, appName, settingName, slotName, nil)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"getting Key Vault reference of app setting %s for %s slot %s: %w", settingName, appName, slotName, err)
+		}
+		reference = response.APIKVReference
+	}
+
+	if reference.Properties == nil {
+		return &armappservice.APIKVReferenceProperties{}, nil
+	}
+
+	return reference.Properties, nil
+}
+
+// mergeAppSettings returns the existing application settings, with the values of envVars added or overwritten.
+func mergeAppSettings(existing map[string]*string, envVars map[string]string) map[string]*string {
+	merged := make(map[string]*string)
+	maps.Copy(merged, existing)
+	for k, v := range envVars {
+		merged[k] = &v
+	}
+
+	return merged
+}
@@ -188,3 +188,107 @@ func Test_DeployAppServiceSlotZip(t *testing.T) {
 		require.Nil(t, res)
 	})
 }
+
+func Test_CreateAppServiceSlot(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	azCli := newAzureClientFromMockContext(mockContext)
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/sites/WEB_APP_NAME")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		response := armappservice.WebAppsClientGetResponse{
+			Site: armappservice.Site{
+				Location: new("eastus2"),
+				Kind:     new("app,linux"),
+				Identity: &armappservice.ManagedServiceIdentity{
+					Type: to.Ptr(armappservice.ManagedServiceIdentityTypeUserAssigned),
+					UserAssignedIdentities: map[string]*armappservice.UserAssignedIdentity{
+						"IDENTITY_ID": {PrincipalID: new("PRINCIPAL_ID")},
+					},
+				},
+				Properties: &armappservice.SiteProperties{
+					ServerFarmID: new("PLAN_ID"),
+				},
+			},
+		}
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/config/web")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		response := armappservice.WebAppsClientGetConfigurationResponse{
+			SiteConfigResource: armappservice.SiteConfigResource{
+				Properties: &armappservice.SiteConfig{
+					LinuxFxVersion: new("PYTHON|3.12"),
+				},
+			},
+		}
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, "/config/appsettings/list")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		response := armappservice.WebAppsClientListApplicationSettingsResponse{
+			StringDictionary: armappservice.StringDictionary{
+				Properties: map[string]*string{"SETTING": new("value")},
+			},
+		}
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+	})
+
+	var slot armappservice.Site
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && strings.HasSuffix(request.URL.Path, "/slots/staging")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		if err := mocks.ReadHttpBody(request.Body, &slot); err != nil {
+			return nil, err
+		}
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, slot)
+	})
+
+	err := azCli.CreateAppServiceSlot(
+		*mockContext.Context,
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP_ID",
+		"WEB_APP_NAME",
+		"staging",
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, "eastus2", *slot.Location)
+	require.Equal(t, "PLAN_ID", *slot.Properties.ServerFarmID)
+	require.Equal(t, "PYTHON|3.12", *slot.Properties.SiteConfig.LinuxFxVersion)
+	require.Len(t, slot.Properties.SiteConfig.AppSettings, 1)
+	require.Equal(t, "SETTING", *slot.Properties.SiteConfig.AppSettings[0].Name)
+	require.Equal(t, "value", *slot.Properties.SiteConfig.AppSettings[0].Value)
+	require.Contains(t, slot.Identity.UserAssignedIdentities, "IDENTITY_ID")
+	require.Nil(t, slot.Identity.UserAssignedIdentities["IDENTITY_ID"].PrincipalID)
+}
+
+func Test_SwapAppServiceSlot(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	azCli := newAzureClientFromMockContext(mockContext)
+
+	var swap armappservice.CsmSlotEntity
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, "/sites/WEB_APP_NAME/slotsswap")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		if err := mocks.ReadHttpBody(request.Body, &swap); err != nil {
+			return nil, err
+		}
+		return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+	})
+
+	err := azCli.SwapAppServiceSlot(
+		*mockContext.Context,
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP_ID",
+		"WEB_APP_NAME",
+		"staging",
+	)
+	require.NoError(t, err)
+	require.Equal(t, "staging", *swap.TargetSlot)
+	require.True(t, *swap.PreserveVnet)
+}
@@ -393,3 +393,41 @@ func Test_AzureClient_UpdateAppServiceAppSettings(t *testing.T) {
 		assert.Contains(t, capturedBody, "NEW_KEY", "should include new settings")
 	})
 }
+
+func Test_AzureClient_ReplaceAppServiceAppSettings(t *testing.T) {
+	mockCtx := mocks.NewMockContext(t.Context())
+	client := newAzureClientFromMockContext(mockCtx)
+
+	mockCtx.HttpClient.When(func(req *http.Request) bool {
+		return req.Method == http.MethodPost &&
+			strings.Contains(req.URL.Path, "/slots/staging/config/appsettings/list")
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(req, http.StatusOK,
+			armappservice.StringDictionary{
+				Properties: map[string]*string{
+					"EXISTING_KEY": new("existing_value"),
+				},
+			})
+	})
+
+	var capturedBody string
+	mockCtx.HttpClient.When(func(req *http.Request) bool {
+		return req.Method == http.MethodPut &&
+			strings.Contains(req.URL.Path, "/slots/staging/config/appsettings")
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		bodyBytes, _ := io.ReadAll(req.Body)
+		capturedBody = string(bodyBytes)
+		return mocks.CreateHttpResponseWithBody(req, http.StatusOK, armappservice.StringDictionary{})
+	})
+
+	settings, err := client.GetAppServiceAppSettings(*mockCtx.Context, "SUB", "RG", "my-app", "staging")
+	require.NoError(t, err)
+	require.Equal(t, "existing_value", *settings["EXISTING_KEY"])
+
+	err = client.ReplaceAppServiceAppSettings(
+		*mockCtx.Context, "SUB", "RG", "my-app", "staging",
+		map[string]*string{"NEW_KEY": new("new_value")})
+	require.NoError(t, err)
+	assert.Contains(t, capturedBody, "NEW_KEY")
+	assert.NotContains(t, capturedBody, "EXISTING_KEY", "should replace the existing settings")
+}
@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/artifacts"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk/appconfig"
 	"github.com/azure/azure-dev/cli/azd/pkg/azsdk/storage"
 	"github.com/azure/azure-dev/cli/azd/pkg/cosmosdb"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
@@ -96,6 +98,7 @@ func (p *DefaultPlatform) ConfigureContainer(container *ioc.NestedContainer) err
 	// Remote Environment State Providers
 	remoteStateProviderMap := map[environment.RemoteKind]any{
 		environment.RemoteKindAzureBlobStorage: environment.NewStorageBlobDataStore,
+		environment.RemoteKindAzureAppConfig:   environment.NewAppConfigDataStore,
 	}
 
 	for remoteKind, constructor := range remoteStateProviderMap {
@@ -131,10 +134,68 @@ func (p *DefaultPlatform) ConfigureContainer(container *ioc.NestedContainer) err
 		return storageAccountConfig, nil
 	})
 
+	container.MustRegisterSingleton(func(
+		remoteStateConfig *state.RemoteConfig,
+		projectConfig *project.ProjectConfig,
+	) (*appconfig.StoreConfig, error) {
+		if remoteStateConfig == nil {
+			return nil, nil
+		}
+
+		var storeConfig *appconfig.StoreConfig
+		jsonBytes, err := json.Marshal(remoteStateConfig.Config)
+		if err != nil {
+			return nil, fmt.Errorf("marshalling remote state config: %w", err)
+		}
+
+		if err := json.Unmarshal(jsonBytes, &storeConfig); err != nil {
+			return nil, fmt.Errorf("unmarshalling remote state config: %w", err)
+		}
+
+		if storeConfig == nil {
+			storeConfig = &appconfig.StoreConfig{}
+		}
+
+		// If a key prefix has not been explicitly configured
+		// Default to use the project name, so multiple projects can share the same store
+		if storeConfig.KeyPrefix == "" {
+			storeConfig.KeyPrefix = projectConfig.Name + "/"
+		}
+
+		return storeConfig, nil
+	})
+
+	// App Configuration components
+	container.MustRegisterSingleton(appconfig.NewClient)
+
 	// Storage components
 	container.MustRegisterSingleton(storage.NewBlobClient)
 	container.MustRegisterSingleton(storage.NewBlobSdkClient)
 
+	// Artifact stores
+	container.MustRegisterSingleton(func(lazyProjectConfig *lazy.Lazy[*project.ProjectConfig]) *artifacts.Config {
+		// The project config may not be available, in which case no artifact store is configured
+		projectConfig, _ := lazyProjectConfig.GetValue()
+		if projectConfig == nil {
+			return nil
+		}
+
+		return projectConfig.Artifacts
+	})
+
+	artifactStoreMap := map[artifacts.StoreKind]any{
+		artifacts.StoreKindLocal:       artifacts.NewLocalStore,
+		artifacts.StoreKindBlob:        artifacts.NewBlobStore,
+		artifacts.StoreKindGitHub:      artifacts.NewGitHubStore,
+		artifacts.StoreKindArtifactory: artifacts.NewArtifactoryStore,
+	}
+
+	for storeKind, constructor := range artifactStoreMap {
+		container.MustRegisterNamedScoped(string(storeKind), constructor)
+	}
+
+	container.MustRegisterScoped(artifacts.NewManager)
+
 	// cosmosdb
 	container.MustRegisterSingleton(cosmosdb.NewCosmosDbService)
 
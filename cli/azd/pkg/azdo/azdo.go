@@ -34,8 +34,6 @@ var (
 	AzurePipelineName = "Azure Dev Deploy"
 	// path to the azure pipeline yaml
 	AzurePipelineYamlPath = ".azdo/pipelines/azure-dev.yml"
-	// target Azure Cloud
-	CloudEnvironment = "AzureCloud"
 	// default branch for pipeline and branch policy
 	DefaultBranch = "main"
 	// azure devops project description
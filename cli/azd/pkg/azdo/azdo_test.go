@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/entraid"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
@@ -292,7 +293,7 @@ func TestCreateAzureRMServiceEndPointArgs_WorkloadIdentity(t *testing.T) {
 		// No ClientSecret -> WorkloadIdentityFederation path
 	}
 
-	args, err := createAzureRMServiceEndPointArgs(&projectId, &projectName, creds)
+	args, err := createAzureRMServiceEndPointArgs(&projectId, &projectName, creds, cloud.AzurePublic())
 	require.NoError(t, err)
 	require.NotNil(t, args.Endpoint)
 
@@ -315,7 +316,7 @@ func TestCreateAzureRMServiceEndPointArgs_WorkloadIdentity(t *testing.T) {
 	require.NotNil(t, ep.Data)
 	data := *ep.Data
 	assert.Equal(t, "sub-id", data["subscriptionId"])
-	assert.Equal(t, CloudEnvironment, data["environment"])
+	assert.Equal(t, cloud.AzurePublicName, data["environment"])
 	assert.Equal(t, "Subscription", data["scopeLevel"])
 	assert.Equal(t, "Manual", data["creationMode"])
 
@@ -328,6 +329,31 @@ func TestCreateAzureRMServiceEndPointArgs_WorkloadIdentity(t *testing.T) {
 	assert.Equal(t, projectId, refs[0].ProjectReference.Id.String())
 }
 
+func TestCreateAzureRMServiceEndPointArgs_SovereignClouds(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		cloud   *cloud.Cloud
+		wantUrl string
+	}{
+		{cloud: cloud.AzureChina(), wantUrl: "https://management.chinacloudapi.cn/"},
+		{cloud: cloud.AzureGovernment(), wantUrl: "https://management.usgovcloudapi.net/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cloud.Name, func(t *testing.T) {
+			projectId := uuid.New().String()
+			projectName := "demo-project"
+			creds := &entraid.AzureCredentials{SubscriptionId: "sub-id", TenantId: "tenant-id", ClientId: "client-id"}
+
+			args, err := createAzureRMServiceEndPointArgs(&projectId, &projectName, creds, tt.cloud)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUrl, *args.Endpoint.Url)
+			assert.Equal(t, tt.cloud.Name, (*args.Endpoint.Data)["environment"])
+		})
+	}
+}
+
 func TestCreateAzureDevPipelineArgs_Bicep(t *testing.T) {
 	t.Parallel()
 
@@ -153,8 +153,7 @@ func CreatePipeline(
 		return nil, err
 	}
 
-	// Add the name of the repo as part of the Pipeline name
-	name = fmt.Sprintf("%s (%s)", name, repoName)
+	name = pipelineName(name, repoName)
 	definition, err := getPipelineDefinition(ctx, client, &projectId, &name)
 	if err != nil {
 		return nil, fmt.Errorf("creating pipeline: validate name: %w", err)
@@ -323,16 +322,76 @@ func createAzureDevPipelineArgs(
 	return createDefinitionArgs, nil
 }
 
+// pipelineName returns the name of the pipeline created for the repository. The name of the repo is part of the
+// Pipeline name.
+func pipelineName(name string, repoName string) string {
+	return fmt.Sprintf("%s (%s)", name, repoName)
+}
+
+// GetPipeline returns the pipeline created by azd for the repository, or nil when the project has no such pipeline.
+func GetPipeline(
+	ctx context.Context,
+	connection *azuredevops.Connection,
+	projectId string,
+	repoName string,
+) (*build.BuildDefinition, error) {
+	client, err := build.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	name := pipelineName(AzurePipelineName, repoName)
+	definition, err := getPipelineDefinition(ctx, client, &projectId, &name)
+	if err != nil {
+		return nil, fmt.Errorf("getting pipeline %s: %w", name, err)
+	}
+	return definition, nil
+}
+
+// LatestBuild returns the most recently queued build of the pipeline for the branch, or nil when the pipeline has no
+// builds for the branch.
+func LatestBuild(
+	ctx context.Context,
+	connection *azuredevops.Connection,
+	projectId string,
+	buildDefinition *build.BuildDefinition,
+	branchName string) (*build.Build, error) {
+	client, err := build.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(branchName, "refs/") {
+		branchName = "refs/heads/" + branchName
+	}
+
+	builds, err := client.GetBuilds(ctx, build.GetBuildsArgs{
+		Project:     &projectId,
+		Definitions: &[]int{*buildDefinition.Id},
+		BranchName:  &branchName,
+		QueryOrder:  &build.BuildQueryOrderValues.QueueTimeDescending,
+		Top:         new(1),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting builds: %w", err)
+	}
+	if len(builds.Value) == 0 {
+		return nil, nil
+	}
+
+	return &builds.Value[0], nil
+}
+
 // run a pipeline. This is used to invoke the deploy pipeline after a successful push of the code
 func QueueBuild(
 	ctx context.Context,
 	connection *azuredevops.Connection,
 	projectId string,
 	buildDefinition *build.BuildDefinition,
-	branchName string) error {
+	branchName string) (*build.Build, error) {
 	client, err := build.NewClient(ctx, connection)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	definitionReference := &build.DefinitionReference{
 		Id: buildDefinition.Id,
@@ -347,10 +406,5 @@ func QueueBuild(
 		Build:   newBuild,
 	}
 
-	_, err = client.QueueBuild(ctx, queueBuildArgs)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return client.QueueBuild(ctx, queueBuildArgs)
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/entraid"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
@@ -92,7 +93,7 @@ func TestCreateServiceConnection_NewClientErrorPath(t *testing.T) {
 		TenantId:       "tenant",
 		ClientId:       "client",
 	}
-	ep, err := CreateServiceConnection(t.Context(), conn, "proj-id", "proj-name", env, creds, mockConsole)
+	ep, err := CreateServiceConnection(t.Context(), conn, "proj-id", "proj-name", env, creds, cloud.AzurePublic(), mockConsole)
 	require.Error(t, err)
 	assert.Nil(t, ep)
 }
@@ -146,8 +147,9 @@ func TestQueueBuild_NewClientErrorPath(t *testing.T) {
 	conn := newFailingConnection(t)
 	id := 1
 	def := &build.BuildDefinition{Id: &id}
-	err := QueueBuild(t.Context(), conn, "proj", def, "main")
+	queued, err := QueueBuild(t.Context(), conn, "proj", def, "main")
 	require.Error(t, err)
+	assert.Nil(t, queued)
 }
 
 func TestCreatePipeline_NewClientErrorPath(t *testing.T) {
@@ -179,8 +181,9 @@ func TestQueueBuild_SdkCallErrorPath(t *testing.T) {
 	conn := newHalfWorkingConnection(t, "queue-build")
 	id := 1
 	def := &build.BuildDefinition{Id: &id}
-	err := QueueBuild(t.Context(), conn, "proj", def, "main")
+	queued, err := QueueBuild(t.Context(), conn, "proj", def, "main")
 	require.Error(t, err)
+	assert.Nil(t, queued)
 }
 
 func TestCreateRepository_SdkCallErrorPath(t *testing.T) {
@@ -275,7 +278,23 @@ func TestCreateServiceConnection_SdkCallErrorPath(t *testing.T) {
 		TenantId:       "tenant",
 		ClientId:       "client",
 	}
-	ep, err := CreateServiceConnection(t.Context(), conn, "proj-id", "proj-name", env, creds, mockConsole)
+	ep, err := CreateServiceConnection(t.Context(), conn, "proj-id", "proj-name", env, creds, cloud.AzurePublic(), mockConsole)
 	require.Error(t, err)
 	assert.Nil(t, ep)
 }
+
+func TestGetPipeline_NewClientErrorPath(t *testing.T) {
+	conn := newFailingConnection(t)
+	def, err := GetPipeline(t.Context(), conn, "proj", "repo")
+	require.Error(t, err)
+	assert.Nil(t, def)
+}
+
+func TestLatestBuild_SdkCallErrorPath(t *testing.T) {
+	conn := newHalfWorkingConnection(t, "latest-build")
+	id := 1
+	def := &build.BuildDefinition{Id: &id}
+	latest, err := LatestBuild(t.Context(), conn, "proj", def, "main")
+	require.Error(t, err)
+	assert.Nil(t, latest)
+}
@@ -6,7 +6,9 @@ package azdo
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/entraid"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
@@ -99,6 +101,7 @@ func CreateServiceConnection(
 	projectName string,
 	azdEnvironment *environment.Environment,
 	credentials *entraid.AzureCredentials,
+	cloud *cloud.Cloud,
 	console input.Console) (*serviceendpoint.ServiceEndpoint, error) {
 
 	client, err := serviceendpoint.NewClient(ctx, connection)
@@ -111,7 +114,7 @@ func CreateServiceConnection(
 		return nil, fmt.Errorf("creating service connection: looking for existing connection: %w", err)
 	}
 
-	createServiceEndpointArgs, err := createAzureRMServiceEndPointArgs(&projectId, &projectName, credentials)
+	createServiceEndpointArgs, err := createAzureRMServiceEndPointArgs(&projectId, &projectName, credentials, cloud)
 	if err != nil {
 		return nil, fmt.Errorf("creating Azure DevOps endpoint: %w", err)
 	}
@@ -168,6 +171,7 @@ func createAzureRMServiceEndPointArgs(
 	projectId *string,
 	projectName *string,
 	credentials *entraid.AzureCredentials,
+	cloud *cloud.Cloud,
 ) (serviceendpoint.CreateServiceEndpointArgs, error) {
 	endpointScheme := "WorkloadIdentityFederation"
 	endpointAuthorizationParameters := map[string]string{
@@ -181,7 +185,7 @@ func createAzureRMServiceEndPointArgs(
 	}
 
 	endpointData := map[string]string{
-		"environment":      CloudEnvironment,
+		"environment":      cloud.Name,
 		"subscriptionId":   credentials.SubscriptionId,
 		"subscriptionName": "azure subscription", // fix? to sub name?
 		"scopeLevel":       "Subscription",
@@ -205,7 +209,7 @@ func createAzureRMServiceEndPointArgs(
 	serviceEndpoint := &serviceendpoint.ServiceEndpoint{
 		Type:                             new("azurerm"),
 		Owner:                            new("library"),
-		Url:                              new("https://management.azure.com/"),
+		Url:                              new(strings.TrimSuffix(cloud.ResourceManagerEndpoint(), "/") + "/"),
 		Name:                             &ServiceConnectionName,
 		IsShared:                         new(false),
 		Authorization:                    &endpointAuthorization,
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/entraid"
 )
 
@@ -25,7 +26,7 @@ func TestCreateAzureRMServiceEndPointArgs_ServicePrincipalKey(t *testing.T) {
 		ClientSecret:   "shh-secret",
 	}
 
-	args, err := createAzureRMServiceEndPointArgs(&projectId, &projectName, creds)
+	args, err := createAzureRMServiceEndPointArgs(&projectId, &projectName, creds, cloud.AzurePublic())
 	require.NoError(t, err)
 
 	ep := args.Endpoint
@@ -0,0 +1,317 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package appconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/auth"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+)
+
+// apiVersion is the version of the App Configuration data plane REST API.
+// See https://learn.microsoft.com/azure/azure-app-configuration/rest-api-key-value for more information.
+const apiVersion = "2023-11-01"
+
+// KeyVaultReferenceContentType is the content type of the key-values that reference a Key Vault secret. The value of
+// these key-values is a JSON object with the URI of the secret, see [KeyVaultReference].
+const KeyVaultReferenceContentType = "application/vnd.microsoft.appconfig.keyvaultref+json;charset=utf-8"
+
+// AnyLabel is the label filter that matches the key-values of all labels.
+const AnyLabel = "*"
+
+// StoreConfig is the configuration of the App Configuration store used to store remote environments, read from the
+// `state.remote.config` section of azure.yaml or of the user configuration.
+type StoreConfig struct {
+	// The name of the App Configuration store.
+	StoreName string `json:"storeName"`
+	// The endpoint of the App Configuration store. Defaults to https://<storeName>.azconfig.io in Azure public cloud.
+	Endpoint string `json:"endpoint"`
+	// The name of the Key Vault that stores the secret values of environments.
+	VaultName string `json:"vaultName"`
+	// The prefix of the keys of the key-values of all environments. Defaults to the project name.
+	KeyPrefix string `json:"keyPrefix"`
+	// The subscription of the App Configuration store and the Key Vault. Defaults to the default subscription.
+	SubscriptionId string `json:"subscriptionId"`
+}
+
+// KeyValue is a key-value of an App Configuration store.
+type KeyValue struct {
+	Key         string `json:"key"`
+	Label       string `json:"label,omitempty"`
+	Value       string `json:"value"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+// KeyVaultReference is the value of a key-value that references a Key Vault secret.
+type KeyVaultReference struct {
+	Uri string `json:"uri"`
+}
+
+// Client is the client of the key-values of an App Configuration store.
+type Client interface {
+	// List returns the key-values with a key that starts with keyPrefix and the given label. Use [AnyLabel] to list the
+	// key-values of all labels.
+	List(ctx context.Context, keyPrefix string, label string) ([]*KeyValue, error)
+	// Set creates or updates the key-value.
+	Set(ctx context.Context, keyValue *KeyValue) error
+	// Delete deletes the key-value with the given key and label, if it exists.
+	Delete(ctx context.Context, key string, label string) error
+}
+
+type client struct {
+	endpoint string
+	pipeline runtime.Pipeline
+
+	// syncTokens holds the latest sync token of each replica of the store, sent with every request so that reads
+	// always reflect the previous writes of the client.
+	syncTokens   map[string]syncToken
+	syncTokensMu sync.Mutex
+}
+
+type syncToken struct {
+	value    string
+	sequence int64
+}
+
+// NewClient creates a new App Configuration client for the store of the configuration, authenticated with the tenant
+// of the configured subscription, or of the default subscription.
+func NewClient(
+	credentialProvider auth.MultiTenantCredentialProvider,
+	storeConfig *StoreConfig,
+	userConfigManager config.UserConfigManager,
+	coreClientOptions *azcore.ClientOptions,
+	cloud *cloud.Cloud,
+	subscriptionResolver account.SubscriptionResolver,
+) (Client, error) {
+	if storeConfig.Endpoint == "" {
+		if storeConfig.StoreName == "" {
+			return nil, errors.New("remote state configuration is missing the 'storeName' of the App Configuration store")
+		}
+
+		storeConfig.Endpoint = fmt.Sprintf("https://%s.%s", storeConfig.StoreName, cloud.AppConfigurationEndpointSuffix)
+	}
+
+	// Determine if we have a subscriptionId either from the config, or the default subscription
+	if storeConfig.SubscriptionId == "" {
+		userConfig, err := userConfigManager.Load()
+		if err == nil {
+			userSubscription, exists := userConfig.GetString("defaults.subscription")
+			if exists && userSubscription != "" {
+				storeConfig.SubscriptionId = userSubscription
+			}
+		}
+	}
+
+	tenantId := ""
+	if storeConfig.SubscriptionId != "" {
+		subscription, err := subscriptionResolver.GetSubscription(context.Background(), storeConfig.SubscriptionId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get subscription '%s': %w", storeConfig.SubscriptionId, err)
+		}
+		tenantId = subscription.UserAccessTenantId
+	}
+
+	credential, err := credentialProvider.GetTokenCredential(context.Background(), tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := fmt.Sprintf("https://%s/.default", cloud.AppConfigurationEndpointSuffix)
+	pipeline := runtime.NewPipeline(
+		"azd-appconfig",
+		"1.0.0",
+		runtime.PipelineOptions{
+			PerRetry: []policy.Policy{runtime.NewBearerTokenPolicy(credential, []string{scope}, nil)},
+		},
+		coreClientOptions,
+	)
+
+	return newClient(storeConfig.Endpoint, pipeline), nil
+}
+
+func newClient(endpoint string, pipeline runtime.Pipeline) *client {
+	return &client{
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		pipeline:   pipeline,
+		syncTokens: map[string]syncToken{},
+	}
+}
+
+type keyValuePage struct {
+	Items    []*KeyValue `json:"items"`
+	NextLink string      `json:"@nextLink"`
+}
+
+func (c *client) List(ctx context.Context, keyPrefix string, label string) ([]*KeyValue, error) {
+	query := url.Values{}
+	query.Set("key", escapeFilter(keyPrefix)+"*")
+	if label != AnyLabel {
+		label = escapeFilter(label)
+	}
+	query.Set("label", label)
+	query.Set("api-version", apiVersion)
+
+	keyValues := []*KeyValue{}
+	next := fmt.Sprintf("%s/kv?%s", c.endpoint, query.Encode())
+
+	for next != "" {
+		request, err := c.newRequest(ctx, http.MethodGet, next)
+		if err != nil {
+			return nil, err
+		}
+		request.Raw().Header.Set("Accept", "application/vnd.microsoft.appconfig.kvset+json")
+
+		response, err := c.do(request, http.StatusOK)
+		if err != nil {
+			return nil, err
+		}
+
+		var page keyValuePage
+		if err := runtime.UnmarshalAsJSON(response, &page); err != nil {
+			return nil, fmt.Errorf("reading key-values: %w", err)
+		}
+
+		keyValues = append(keyValues, page.Items...)
+
+		// The next link is relative to the endpoint of the store.
+		next = ""
+		if page.NextLink != "" {
+			next = c.endpoint + page.NextLink
+		}
+	}
+
+	return keyValues, nil
+}
+
+func (c *client) Set(ctx context.Context, keyValue *KeyValue) error {
+	request, err := c.newRequest(ctx, http.MethodPut, c.keyValueUrl(keyValue.Key, keyValue.Label))
+	if err != nil {
+		return err
+	}
+
+	body := map[string]string{
+		"value":        keyValue.Value,
+		"content_type": keyValue.ContentType,
+	}
+	if err := runtime.MarshalAsJSON(request, body); err != nil {
+		return err
+	}
+	request.Raw().Header.Set("Content-Type", "application/vnd.microsoft.appconfig.kv+json")
+
+	response, err := c.do(request, http.StatusOK)
+	if err != nil {
+		return fmt.Errorf("setting key-value '%s': %w", keyValue.Key, err)
+	}
+
+	return response.Body.Close()
+}
+
+func (c *client) Delete(ctx context.Context, key string, label string) error {
+	request, err := c.newRequest(ctx, http.MethodDelete, c.keyValueUrl(key, label))
+	if err != nil {
+		return err
+	}
+
+	response, err := c.do(request, http.StatusOK, http.StatusNoContent)
+	if err != nil {
+		return fmt.Errorf("deleting key-value '%s': %w", key, err)
+	}
+
+	return response.Body.Close()
+}
+
+func (c *client) keyValueUrl(key string, label string) string {
+	query := url.Values{}
+	query.Set("label", label)
+	query.Set("api-version", apiVersion)
+
+	// Keys may contain slashes, which must be escaped to stay in a single segment of the path.
+	escapedKey := strings.ReplaceAll(url.PathEscape(key), "/", "%2F")
+	return fmt.Sprintf("%s/kv/%s?%s", c.endpoint, escapedKey, query.Encode())
+}
+
+func (c *client) newRequest(ctx context.Context, method string, endpoint string) (*policy.Request, error) {
+	request, err := runtime.NewRequest(ctx, method, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	c.syncTokensMu.Lock()
+	defer c.syncTokensMu.Unlock()
+
+	tokens := make([]string, 0, len(c.syncTokens))
+	for _, token := range c.syncTokens {
+		tokens = append(tokens, token.value)
+	}
+
+	if len(tokens) > 0 {
+		request.Raw().Header.Set("Sync-Token", strings.Join(tokens, ","))
+	}
+
+	return request, nil
+}
+
+// do sends the request, and returns an *azcore.ResponseError when the status code of the response isn't one of
+// statusCodes.
+func (c *client) do(request *policy.Request, statusCodes ...int) (*http.Response, error) {
+	response, err := c.pipeline.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	c.updateSyncTokens(response.Header.Get("Sync-Token"))
+
+	if !runtime.HasStatusCode(response, statusCodes...) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	return response, nil
+}
+
+// updateSyncTokens records the sync tokens of a response, in the `<id>=<value>;sn=<sequence>` format, keeping the most
+// recent token of each replica.
+func (c *client) updateSyncTokens(header string) {
+	if header == "" {
+		return
+	}
+
+	c.syncTokensMu.Lock()
+	defer c.syncTokensMu.Unlock()
+
+	for token := range strings.SplitSeq(header, ",") {
+		value, sequenceText, has := strings.Cut(strings.TrimSpace(token), ";sn=")
+		if !has {
+			continue
+		}
+
+		id, _, has := strings.Cut(value, "=")
+		sequence, err := strconv.ParseInt(sequenceText, 10, 64)
+		if !has || err != nil {
+			continue
+		}
+
+		if existing, has := c.syncTokens[id]; !has || existing.sequence < sequence {
+			c.syncTokens[id] = syncToken{value: value, sequence: sequence}
+		}
+	}
+}
+
+// escapeFilter escapes the characters that have a special meaning in the key and label filters.
+func escapeFilter(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `*`, `\*`, `,`, `\,`)
+	return replacer.Replace(value)
+}
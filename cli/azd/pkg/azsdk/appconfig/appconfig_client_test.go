@@ -0,0 +1,124 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package appconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	pipeline := runtime.NewPipeline("test", "1.0.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		Transport: server.Client(),
+		Retry:     policy.RetryOptions{MaxRetries: -1},
+	})
+
+	return newClient(server.URL+"/", pipeline)
+}
+
+func Test_Client_List(t *testing.T) {
+	requests := []*http.Request{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Query().Get("after") == "" {
+			w.Header().Set("Sync-Token", "replica1=token1;sn=1")
+			_, _ = w.Write([]byte(`{"items":[{"key":"app/values/A","label":"dev","value":"1"}],` +
+				`"@nextLink":"/kv?key=app%2F*&label=dev&after=next"}`))
+			return
+		}
+
+		w.Header().Set("Sync-Token", "replica1=token2;sn=2")
+		_, _ = w.Write([]byte(`{"items":[{"key":"app/values/B","label":"dev","value":"2"}]}`))
+	})
+
+	keyValues, err := client.List(t.Context(), "a*,b", "dev")
+	require.NoError(t, err)
+	require.Equal(t, []*KeyValue{
+		{Key: "app/values/A", Label: "dev", Value: "1"},
+		{Key: "app/values/B", Label: "dev", Value: "2"},
+	}, keyValues)
+
+	require.Len(t, requests, 2)
+	require.Equal(t, `a\*\,b*`, requests[0].URL.Query().Get("key"))
+	require.Equal(t, "dev", requests[0].URL.Query().Get("label"))
+	require.Equal(t, apiVersion, requests[0].URL.Query().Get("api-version"))
+	require.Empty(t, requests[0].Header.Get("Sync-Token"))
+	// The following requests carry the sync token of the previous response.
+	require.Equal(t, "replica1=token1", requests[1].Header.Get("Sync-Token"))
+
+	_, err = client.List(t.Context(), "app/", AnyLabel)
+	require.NoError(t, err)
+	require.Equal(t, "*", requests[2].URL.Query().Get("label"))
+	require.Equal(t, "replica1=token2", requests[2].Header.Get("Sync-Token"))
+}
+
+func Test_Client_Set(t *testing.T) {
+	var request *http.Request
+	var body map[string]string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		request = r
+		content, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(content, &body)
+		_, _ = w.Write(content)
+	})
+
+	err := client.Set(t.Context(), &KeyValue{
+		Key:         "app/values/API_KEY",
+		Label:       "dev",
+		Value:       `{"uri":"https://vault.vault.azure.net/secrets/api-key"}`,
+		ContentType: KeyVaultReferenceContentType,
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.MethodPut, request.Method)
+	require.Equal(t, "/kv/app%2Fvalues%2FAPI_KEY", request.URL.EscapedPath())
+	require.Equal(t, "dev", request.URL.Query().Get("label"))
+	require.Equal(t, map[string]string{
+		"value":        `{"uri":"https://vault.vault.azure.net/secrets/api-key"}`,
+		"content_type": KeyVaultReferenceContentType,
+	}, body)
+}
+
+func Test_Client_Delete(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("label") == "missing" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	require.NoError(t, client.Delete(t.Context(), "app/values/A", "missing"))
+
+	err := client.Delete(t.Context(), "app/values/A", "dev")
+	responseErr, ok := errors.AsType[*azcore.ResponseError](err)
+	require.True(t, ok)
+	require.Equal(t, http.StatusForbidden, responseErr.StatusCode)
+}
+
+func Test_UpdateSyncTokens(t *testing.T) {
+	client := newClient("https://store.azconfig.io", runtime.Pipeline{})
+	client.updateSyncTokens("replica1=b;sn=2, replica2=c;sn=1")
+	client.updateSyncTokens("replica1=a;sn=1,invalid")
+
+	require.Equal(t, map[string]syncToken{
+		"replica1": {value: "replica1=b", sequence: 2},
+		"replica2": {value: "replica2=c", sequence: 1},
+	}, client.syncTokens)
+}
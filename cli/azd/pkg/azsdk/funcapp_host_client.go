@@ -78,6 +78,8 @@ func (c *FuncAppHostClient) Publish(
 	rawRequest.Header.Set("Accept", "application/json")
 
 	query := rawRequest.URL.Query()
+	// Identifies azd as the deployer in the deployment history of the app
+	query.Set("Deployer", "azd")
 	if options.RemoteBuild {
 		query.Set("RemoteBuild", "true")
 	}
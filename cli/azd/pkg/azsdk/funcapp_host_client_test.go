@@ -118,6 +118,7 @@ func TestFuncAppHostClient_Publish_Success(t *testing.T) {
 	require.Equal(t, "application/zip", captured.Header.Get("Content-Type"))
 	require.Equal(t, "application/json", captured.Header.Get("Accept"))
 	require.Empty(t, captured.URL.Query().Get("RemoteBuild"))
+	require.Equal(t, "azd", captured.URL.Query().Get("Deployer"))
 }
 
 func TestFuncAppHostClient_Publish_RemoteBuildQueryParam(t *testing.T) {
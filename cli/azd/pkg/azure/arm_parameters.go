@@ -24,7 +24,7 @@ type ArmParameter struct {
 type KeyVaultParameterReference struct {
 	KeyVault      KeyVaultReference `json:"keyVault"`
 	SecretName    string            `json:"secretName"`
-	SecretVersion string            `json:"secretVersion"`
+	SecretVersion string            `json:"secretVersion,omitempty"`
 }
 
 // KeyVaultReference represents the Key Vault resource ID.
@@ -143,12 +143,22 @@ const AzdMetadataTypeGenerate AzdMetadataType = "generate"
 const AzdMetadataTypeGenerateOrManual AzdMetadataType = "generateOrManual"
 const AzdMetadataTypeResourceGroup AzdMetadataType = "resourceGroup"
 
+// AzdMetadataTypeKeyVaultSecret marks a secure string parameter whose value is read from an existing Key Vault secret.
+// azd prompts for the secret and passes a Key Vault reference to the deployment instead of the secret value.
+const AzdMetadataTypeKeyVaultSecret AzdMetadataType = "keyVaultSecret"
+
 type AzdMetadata struct {
 	Type               *AzdMetadataType `json:"type,omitempty"`
 	AutoGenerateConfig *AutoGenInput    `json:"config,omitempty"`
 	DefaultValueExpr   *string          `json:"defaultValueExpr,omitempty"`
 	Default            any              `json:"default,omitempty"`
 	UsageName          usageName        `json:"usageName,omitempty"`
+	// Existing restricts a resourceGroup parameter to resource groups that already exist, hiding the option to
+	// create a new one.
+	Existing bool `json:"existing,omitempty"`
+	// PromptIf is an expression over the other parameters (e.g. "useRedis == true"). When it is set, the parameter is
+	// only prompted for if the expression holds.
+	PromptIf string `json:"promptIf,omitempty"`
 }
 
 // usageName is a custom type that can be either a single string or an array of strings.
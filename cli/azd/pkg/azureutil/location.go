@@ -80,10 +80,12 @@ func PromptLocationWithFilter(
 	}
 
 	selectedIndex, err := console.Select(ctx, input.ConsoleOptions{
+		Key:          "location",
 		Message:      message,
 		Help:         help,
 		Options:      locationOptions,
 		DefaultValue: defaultOption,
+		FilterHint:   "Type part of a location name, like 'wus2' for West US 2",
 	})
 
 	if err != nil {
@@ -6,8 +6,11 @@ package cloud
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	// Registers the Azure Resource Manager endpoint of each cloud in the SDK cloud configurations.
+	_ "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
 )
 
 const (
@@ -16,9 +19,16 @@ const (
 	AzurePublicName       = "AzureCloud"
 	AzureChinaCloudName   = "AzureChinaCloud"
 	AzureUSGovernmentName = "AzureUSGovernment"
+
+	// GraphService is the name of Microsoft Graph in the cloud configuration of SDK clients.
+	GraphService cloud.ServiceName = "microsoftGraph"
 )
 
 type Cloud struct {
+	// The name of the cloud (e.g. AzureCloud for Azure public cloud), which is also the name of the cloud in ARM's
+	// environment() function and in Azure DevOps service connections.
+	Name string
+
 	Configuration cloud.Configuration
 
 	// The base URL for the cloud's portal (e.g. https://portal.azure.com for
@@ -36,6 +46,32 @@ type Cloud struct {
 	ContainerRegistryEndpointSuffix string
 
 	KeyVaultEndpointSuffix string
+
+	// The suffix for the cloud's App Service and Azure Functions host names (e.g. azurewebsites.net for Azure public
+	// cloud). The Kudu (SCM) host of an app is <app>.scm.<suffix>.
+	AppServiceEndpointSuffix string
+
+	// The suffix for the cloud's Service Bus and Event Hubs namespaces (e.g. servicebus.windows.net for Azure public
+	// cloud).
+	ServiceBusEndpointSuffix string
+
+	// The suffix for the cloud's App Configuration store endpoints (e.g. azconfig.io for Azure public cloud), which is
+	// also the audience of the tokens for the App Configuration data plane.
+	AppConfigurationEndpointSuffix string
+
+	// The Microsoft Graph endpoint of the cloud, without the API version.
+	GraphEndpoint string
+
+	// The Application Insights query API endpoint of the cloud (e.g. https://api.applicationinsights.io for Azure
+	// public cloud), which is also the audience of its tokens.
+	ApplicationInsightsEndpoint string
+
+	// The Log Analytics query API endpoint of the cloud (e.g. https://api.loganalytics.io for Azure public cloud), which
+	// is also the audience of its tokens.
+	LogAnalyticsEndpoint string
+
+	// The page where users enter the code of a device code sign in.
+	DeviceLoginUrl string
 }
 
 type Config struct {
@@ -67,34 +103,83 @@ func ParseCloudConfig(partialConfig any) (*Config, error) {
 
 func AzurePublic() *Cloud {
 	return &Cloud{
+		Name:                            AzurePublicName,
 		Configuration:                   cloud.AzurePublic,
 		PortalUrlBase:                   "https://portal.azure.com",
 		StorageEndpointSuffix:           "core.windows.net",
 		ContainerRegistryEndpointSuffix: "azurecr.io",
 		KeyVaultEndpointSuffix:          "vault.azure.net",
+		AppServiceEndpointSuffix:        "azurewebsites.net",
+		ServiceBusEndpointSuffix:        "servicebus.windows.net",
+		AppConfigurationEndpointSuffix:  "azconfig.io",
+		GraphEndpoint:                   "https://graph.microsoft.com",
+		ApplicationInsightsEndpoint:     "https://api.applicationinsights.io",
+		LogAnalyticsEndpoint:            "https://api.loganalytics.io",
+		DeviceLoginUrl:                  "https://microsoft.com/devicelogin",
 	}
 }
 
 func AzureGovernment() *Cloud {
 	return &Cloud{
+		Name:                            AzureUSGovernmentName,
 		Configuration:                   cloud.AzureGovernment,
 		PortalUrlBase:                   "https://portal.azure.us",
 		StorageEndpointSuffix:           "core.usgovcloudapi.net",
 		ContainerRegistryEndpointSuffix: "azurecr.us",
 		KeyVaultEndpointSuffix:          "vault.usgovcloudapi.net",
+		AppServiceEndpointSuffix:        "azurewebsites.us",
+		ServiceBusEndpointSuffix:        "servicebus.usgovcloudapi.net",
+		AppConfigurationEndpointSuffix:  "azconfig.azure.us",
+		GraphEndpoint:                   "https://graph.microsoft.us",
+		ApplicationInsightsEndpoint:     "https://api.applicationinsights.us",
+		LogAnalyticsEndpoint:            "https://api.loganalytics.us",
+		DeviceLoginUrl:                  "https://microsoft.com/deviceloginus",
 	}
 }
 
 func AzureChina() *Cloud {
 	return &Cloud{
+		Name:                            AzureChinaCloudName,
 		Configuration:                   cloud.AzureChina,
 		PortalUrlBase:                   "https://portal.azure.cn",
 		StorageEndpointSuffix:           "core.chinacloudapi.cn",
 		ContainerRegistryEndpointSuffix: "azurecr.cn",
 		KeyVaultEndpointSuffix:          "vault.azure.cn",
+		AppServiceEndpointSuffix:        "chinacloudsites.cn",
+		ServiceBusEndpointSuffix:        "servicebus.chinacloudapi.cn",
+		AppConfigurationEndpointSuffix:  "azconfig.azure.cn",
+		GraphEndpoint:                   "https://microsoftgraph.chinacloudapi.cn",
+		ApplicationInsightsEndpoint:     "https://api.applicationinsights.azure.cn",
+		LogAnalyticsEndpoint:            "https://api.loganalytics.azure.cn",
+		DeviceLoginUrl:                  "https://microsoft.com/deviceloginchina",
 	}
 }
 
+// ResourceManagerEndpoint returns the Azure Resource Manager endpoint of the cloud (e.g. https://management.azure.com/
+// for Azure public cloud).
+func (c *Cloud) ResourceManagerEndpoint() string {
+	return c.Configuration.Services[cloud.ResourceManager].Endpoint
+}
+
+// ClientConfiguration returns the cloud configuration of SDK clients that call services other than Azure Resource
+// Manager. It adds Microsoft Graph to the services of the configuration.
+func (c *Cloud) ClientConfiguration() cloud.Configuration {
+	configuration := c.Configuration
+	configuration.Services = maps.Clone(c.Configuration.Services)
+	if configuration.Services == nil {
+		configuration.Services = map[cloud.ServiceName]cloud.ServiceConfiguration{}
+	}
+
+	if c.GraphEndpoint != "" {
+		configuration.Services[GraphService] = cloud.ServiceConfiguration{
+			Audience: c.GraphEndpoint,
+			Endpoint: c.GraphEndpoint + "/v1.0",
+		}
+	}
+
+	return configuration
+}
+
 func parseCloudName(name string) (*Cloud, error) {
 	if name == AzurePublicName || name == "" {
 		return AzurePublic(), nil
@@ -4,6 +4,7 @@
 package cloud
 
 import (
+	"strings"
 	"testing"
 
 	azcloud "github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
@@ -228,3 +229,75 @@ func TestConstants(t *testing.T) {
 	assert.Equal(t, "AzureChinaCloud", AzureChinaCloudName)
 	assert.Equal(t, "AzureUSGovernment", AzureUSGovernmentName)
 }
+
+func TestCloud_ServiceEndpoints(t *testing.T) {
+	tests := []struct {
+		name                string
+		cloud               *Cloud
+		wantName            string
+		wantAppService      string
+		wantServiceBus      string
+		wantAppConfig       string
+		wantGraph           string
+		wantDeviceLogin     string
+		wantResourceManager string
+	}{
+		{
+			name:                "AzurePublic",
+			cloud:               AzurePublic(),
+			wantName:            AzurePublicName,
+			wantAppService:      "azurewebsites.net",
+			wantServiceBus:      "servicebus.windows.net",
+			wantAppConfig:       "azconfig.io",
+			wantGraph:           "https://graph.microsoft.com",
+			wantDeviceLogin:     "https://microsoft.com/devicelogin",
+			wantResourceManager: "https://management.azure.com",
+		},
+		{
+			name:                "AzureGovernment",
+			cloud:               AzureGovernment(),
+			wantName:            AzureUSGovernmentName,
+			wantAppService:      "azurewebsites.us",
+			wantServiceBus:      "servicebus.usgovcloudapi.net",
+			wantAppConfig:       "azconfig.azure.us",
+			wantGraph:           "https://graph.microsoft.us",
+			wantDeviceLogin:     "https://microsoft.com/deviceloginus",
+			wantResourceManager: "https://management.usgovcloudapi.net",
+		},
+		{
+			name:                "AzureChina",
+			cloud:               AzureChina(),
+			wantName:            AzureChinaCloudName,
+			wantAppService:      "chinacloudsites.cn",
+			wantServiceBus:      "servicebus.chinacloudapi.cn",
+			wantAppConfig:       "azconfig.azure.cn",
+			wantGraph:           "https://microsoftgraph.chinacloudapi.cn",
+			wantDeviceLogin:     "https://microsoft.com/deviceloginchina",
+			wantResourceManager: "https://management.chinacloudapi.cn",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantName, tt.cloud.Name)
+			assert.Equal(t, tt.wantAppService, tt.cloud.AppServiceEndpointSuffix)
+			assert.Equal(t, tt.wantServiceBus, tt.cloud.ServiceBusEndpointSuffix)
+			assert.Equal(t, tt.wantAppConfig, tt.cloud.AppConfigurationEndpointSuffix)
+			assert.Equal(t, tt.wantGraph, tt.cloud.GraphEndpoint)
+			assert.Equal(t, tt.wantDeviceLogin, tt.cloud.DeviceLoginUrl)
+			assert.Equal(t, tt.wantResourceManager, strings.TrimSuffix(tt.cloud.ResourceManagerEndpoint(), "/"))
+
+			config := tt.cloud.ClientConfiguration()
+			assert.Equal(t, tt.wantGraph+"/v1.0", config.Services[GraphService].Endpoint)
+			assert.Equal(
+				t,
+				tt.cloud.Configuration.Services[azcloud.ResourceManager],
+				config.Services[azcloud.ResourceManager],
+			)
+
+			// The shared SDK configuration isn't modified.
+			_, has := tt.cloud.Configuration.Services[GraphService]
+			assert.False(t, has)
+		})
+	}
+}
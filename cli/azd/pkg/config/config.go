@@ -66,6 +66,13 @@ func NewConfig(data map[string]any) Config {
 	}
 }
 
+// IsSecretReference reports whether value is a reference to a secret stored with [Config.SetSecret]. [Config.Raw]
+// returns the references of the secrets, while [Config.Get] resolves them.
+func IsSecretReference(value any) bool {
+	ref, isString := value.(string)
+	return isString && vaultPattern.MatchString(ref)
+}
+
 // Top level AZD configuration
 type config struct {
 	vaultId string
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"maps"
 	"net/http"
 	"slices"
 	"strings"
@@ -71,7 +72,8 @@ type ContainerAppService interface {
 		containerAppYaml []byte,
 		options *ContainerAppOptions,
 	) error
-	// Adds and activates a new revision to the specified container app
+	// Adds and activates a new revision to the specified container app. When settings isn't nil, its changes to the
+	// environment variables and secrets are applied to the new revision.
 	AddRevision(
 		ctx context.Context,
 		subscriptionId string,
@@ -79,6 +81,57 @@ type ContainerAppService interface {
 		appName string,
 		imageName string,
 		envVars map[string]string,
+		settings *AppSettings,
+		options *ContainerAppOptions,
+	) error
+	// Adds a new revision to the specified container app without promoting it: trafficPercent of the ingress traffic
+	// is routed to the new revision, and the rest keeps going to the revisions that served it before
+	StageRevision(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+		imageName string,
+		envVars map[string]string,
+		settings *AppSettings,
+		trafficPercent int32,
+		options *ContainerAppOptions,
+	) (*StagedRevision, error)
+	// Gets the environment variables of the container of the specified container app, with the values of the secrets
+	// they reference
+	GetAppSettings(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+		options *ContainerAppOptions,
+	) (map[string]AppSetting, error)
+	// Gets a revision of the specified container app
+	GetRevision(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+		revisionName string,
+		options *ContainerAppOptions,
+	) (*armappcontainers.Revision, error)
+	// Routes all the ingress traffic of the specified container app to a staged revision
+	PromoteRevision(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+		revision *StagedRevision,
+		options *ContainerAppOptions,
+	) error
+	// Routes the ingress traffic of the specified container app back to the revisions that served it before a
+	// revision was staged, and deactivates the staged revision
+	RollbackRevision(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+		revision *StagedRevision,
 		options *ContainerAppOptions,
 	) error
 	// GetContainerAppJob gets a Container App Job by name
@@ -100,6 +153,44 @@ type ContainerAppService interface {
 		envVars map[string]string,
 		options *ContainerAppOptions,
 	) error
+	// UpdateContainerAppJob updates the container image and environment variables of a Container App Job, and when
+	// configuration isn't nil, its trigger and replica configuration
+	UpdateContainerAppJob(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		jobName string,
+		imageName string,
+		envVars map[string]string,
+		configuration *armappcontainers.JobConfiguration,
+		options *ContainerAppOptions,
+	) error
+	// StartContainerAppJobExecution starts an execution of a Container App Job, and returns the name of the execution
+	StartContainerAppJobExecution(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		jobName string,
+		options *ContainerAppOptions,
+	) (string, error)
+	// GetContainerAppJobExecution gets an execution of a Container App Job by name
+	GetContainerAppJobExecution(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		jobName string,
+		executionName string,
+		options *ContainerAppOptions,
+	) (*armappcontainers.JobExecution, error)
+	// Exec runs a command in a container of a replica of the latest ready revision of the specified container app,
+	// connected to the input and the outputs of options, until the command exits
+	Exec(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+		options *ExecOptions,
+	) error
 }
 
 // NewContainerAppService creates a new ContainerAppService
@@ -134,6 +225,50 @@ type ContainerAppIngressConfiguration struct {
 	HostNames []string
 }
 
+// AppSettings are changes to the environment variables of the container of a container app, applied with a new
+// revision. The values of secret settings are stored as secrets of the container app, named with AppSettingSecretName,
+// and referenced by the environment variables.
+type AppSettings struct {
+	// Env are the environment variables added or changed.
+	Env map[string]string
+	// Secrets are the secret environment variables added or changed.
+	Secrets map[string]string
+	// Remove are the names of the environment variables removed.
+	Remove []string
+}
+
+// AppSetting is an environment variable of the container of a container app.
+type AppSetting struct {
+	// Value is the value of the environment variable, or of the secret it references.
+	Value string
+	// Secret is true when the environment variable references a secret.
+	Secret bool
+}
+
+// AppSettingSecretName returns the name of the secret of a container app that stores the value of a secret setting:
+// secret names are lower case alphanumeric characters and '-'.
+func AppSettingSecretName(name string) string {
+	return strings.Trim(strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return '-'
+		}
+	}, name), "-")
+}
+
+// StagedRevision is a revision added with StageRevision, which receives part of the ingress traffic until it is
+// promoted or rolled back.
+type StagedRevision struct {
+	// Name is the name of the revision.
+	Name string
+	// PreviousTraffic is the traffic split of the container app before the revision was staged.
+	PreviousTraffic []*armappcontainers.TrafficWeight
+}
+
 // Gets the ingress configuration for the specified container app
 func (cas *containerAppService) GetIngressConfiguration(
 	ctx context.Context,
@@ -336,21 +471,191 @@ func (cas *containerAppService) AddRevision(
 	appName string,
 	imageName string,
 	envVars map[string]string,
+	settings *AppSettings,
+	options *ContainerAppOptions,
+) error {
+	containerApp, newRevisionName, err := cas.newRevision(
+		ctx, subscriptionId, resourceGroupName, appName, imageName, envVars, settings, options)
+	if err != nil {
+		return err
+	}
+
+	revisionMode, ok := containerApp.GetString(pathConfigurationActiveRevisionsMode)
+	if !ok {
+		return fmt.Errorf("container app is missing active revisions mode configuration")
+	}
+
+	// If the container app is in multiple revision mode, update the traffic to point to the new revision.
+	if revisionMode == string(armappcontainers.ActiveRevisionsModeMultiple) {
+		trafficWeights := []*armappcontainers.TrafficWeight{
+			{
+				RevisionName: &newRevisionName,
+				Weight:       to.Ptr[int32](100),
+			},
+		}
+
+		if err := setTraffic(containerApp, trafficWeights); err != nil {
+			return err
+		}
+	}
+
+	err = cas.updateContainerApp(ctx, subscriptionId, resourceGroupName, appName, containerApp, options)
+	if err != nil {
+		return fmt.Errorf("updating container app revision: %w", err)
+	}
+
+	return nil
+}
+
+// Adds a new revision to the specified container app without promoting it
+func (cas *containerAppService) StageRevision(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	imageName string,
+	envVars map[string]string,
+	settings *AppSettings,
+	trafficPercent int32,
+	options *ContainerAppOptions,
+) (*StagedRevision, error) {
+	containerApp, newRevisionName, err := cas.newRevision(
+		ctx, subscriptionId, resourceGroupName, appName, imageName, envVars, settings, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var traffic []*armappcontainers.TrafficWeight
+	if ok, err := containerApp.GetSection(pathConfigurationIngressTraffic, &traffic); err != nil {
+		return nil, fmt.Errorf("getting traffic weights: %w", err)
+	} else if !ok {
+		return nil, fmt.Errorf("container app %s has no ingress to route traffic to the new revision", appName)
+	}
+
+	// The traffic of the latest revision moves to the new revision when it is created, so it is pinned to the
+	// revision that serves it now.
+	latestRevisionName, _ := containerApp.GetString(pathLatestRevisionName)
+	previousTraffic := pinTraffic(traffic, latestRevisionName)
+
+	// Traffic can only be split between revisions in multiple revision mode.
+	if err := containerApp.Set(
+		pathConfigurationActiveRevisionsMode, string(armappcontainers.ActiveRevisionsModeMultiple)); err != nil {
+		return nil, fmt.Errorf("setting active revisions mode: %w", err)
+	}
+
+	if err := setTraffic(containerApp, splitTraffic(previousTraffic, newRevisionName, trafficPercent)); err != nil {
+		return nil, err
+	}
+
+	err = cas.updateContainerApp(ctx, subscriptionId, resourceGroupName, appName, containerApp, options)
+	if err != nil {
+		return nil, fmt.Errorf("updating container app revision: %w", err)
+	}
+
+	return &StagedRevision{
+		Name:            newRevisionName,
+		PreviousTraffic: previousTraffic,
+	}, nil
+}
+
+// Gets a revision of the specified container app
+func (cas *containerAppService) GetRevision(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	revisionName string,
+	options *ContainerAppOptions,
+) (*armappcontainers.Revision, error) {
+	revisionsClient, err := cas.createRevisionsClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := revisionsClient.GetRevision(ctx, resourceGroupName, appName, revisionName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting revision %s: %w", revisionName, err)
+	}
+
+	return &res.Revision, nil
+}
+
+// Routes all the ingress traffic of the specified container app to a staged revision
+func (cas *containerAppService) PromoteRevision(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	revision *StagedRevision,
+	options *ContainerAppOptions,
+) error {
+	trafficWeights := []*armappcontainers.TrafficWeight{
+		{
+			RevisionName: &revision.Name,
+			Weight:       to.Ptr[int32](100),
+		},
+	}
+
+	if err := cas.updateTraffic(ctx, subscriptionId, resourceGroupName, appName, trafficWeights, options); err != nil {
+		return fmt.Errorf("promoting revision %s: %w", revision.Name, err)
+	}
+
+	return nil
+}
+
+// Routes the ingress traffic of the specified container app back to the revisions that served it before a revision
+// was staged, and deactivates the staged revision
+func (cas *containerAppService) RollbackRevision(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	revision *StagedRevision,
 	options *ContainerAppOptions,
 ) error {
+	err := cas.updateTraffic(ctx, subscriptionId, resourceGroupName, appName, revision.PreviousTraffic, options)
+	if err != nil {
+		return fmt.Errorf("rolling back revision %s: %w", revision.Name, err)
+	}
+
+	revisionsClient, err := cas.createRevisionsClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	if _, err := revisionsClient.DeactivateRevision(ctx, resourceGroupName, appName, revision.Name, nil); err != nil {
+		return fmt.Errorf("deactivating revision %s: %w", revision.Name, err)
+	}
+
+	return nil
+}
+
+// newRevision gets the specified container app, and updates its template with the image and environment variables
+// of a new revision. The container app must be updated to create the revision.
+func (cas *containerAppService) newRevision(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	imageName string,
+	envVars map[string]string,
+	settings *AppSettings,
+	options *ContainerAppOptions,
+) (config.Config, string, error) {
 	containerApp, err := cas.getContainerApp(ctx, subscriptionId, resourceGroupName, appName, options)
 	if err != nil {
-		return fmt.Errorf("getting container app: %w", err)
+		return nil, "", fmt.Errorf("getting container app: %w", err)
 	}
 
 	// Update the template with the new image name and suffix
-	if err := containerApp.Set(pathTemplateRevisionSuffix, fmt.Sprintf("azd-%d", cas.clock.Now().Unix())); err != nil {
-		return fmt.Errorf("setting revision suffix: %w", err)
+	revisionSuffix := fmt.Sprintf("azd-%d", cas.clock.Now().Unix())
+	if err := containerApp.Set(pathTemplateRevisionSuffix, revisionSuffix); err != nil {
+		return nil, "", fmt.Errorf("setting revision suffix: %w", err)
 	}
 
 	var containers []map[string]any
 	if ok, err := containerApp.GetSection(pathTemplateContainers, &containers); !ok || err != nil {
-		return fmt.Errorf("getting containers: %w", err)
+		return nil, "", fmt.Errorf("getting containers: %w", err)
 	}
 
 	containers[0]["image"] = imageName
@@ -387,49 +692,278 @@ func (cas *containerAppService) AddRevision(
 		containers[0]["env"] = mergedEnv
 	}
 
+	if settings != nil {
+		setAppSettingsEnv(containers[0], settings)
+	}
+
 	if err := containerApp.Set(pathTemplateContainers, containers); err != nil {
-		return fmt.Errorf("setting containers: %w", err)
+		return nil, "", fmt.Errorf("setting containers: %w", err)
 	}
 
 	containerApp, err = cas.syncSecrets(ctx, subscriptionId, resourceGroupName, appName, containerApp)
 	if err != nil {
-		return fmt.Errorf("syncing secrets: %w", err)
+		return nil, "", fmt.Errorf("syncing secrets: %w", err)
 	}
 
-	revisionMode, ok := containerApp.GetString(pathConfigurationActiveRevisionsMode)
-	if !ok {
-		return fmt.Errorf("container app is missing active revisions mode configuration")
+	if settings != nil {
+		if err := setAppSettingsSecrets(containerApp, containers, settings); err != nil {
+			return nil, "", err
+		}
 	}
 
-	// If the container app is in multiple revision mode, update the traffic to point to the new revision.
-	if revisionMode == string(armappcontainers.ActiveRevisionsModeMultiple) {
-		revisionSuffix, _ := containerApp.GetString(pathTemplateRevisionSuffix)
-		newRevisionName := fmt.Sprintf("%s--%s", appName, revisionSuffix)
+	return containerApp, fmt.Sprintf("%s--%s", appName, revisionSuffix), nil
+}
 
-		trafficWeights := []*armappcontainers.TrafficWeight{
-			{
-				RevisionName: &newRevisionName,
-				Weight:       to.Ptr[int32](100),
-			},
+// setAppSettingsEnv applies the changes of settings to the environment variables of a container, keeping the order of
+// the existing environment variables.
+func setAppSettingsEnv(container map[string]any, settings *AppSettings) {
+	entries := map[string]map[string]any{}
+	for name, value := range settings.Env {
+		entries[name] = map[string]any{"name": name, "value": value}
+	}
+	for name := range settings.Secrets {
+		entries[name] = map[string]any{"name": name, "secretRef": AppSettingSecretName(name)}
+	}
+
+	existingEnv, _ := container["env"].([]any)
+	env := make([]any, 0, len(existingEnv)+len(entries))
+	for _, item := range existingEnv {
+		name, _ := item.(map[string]any)["name"].(string)
+		if slices.Contains(settings.Remove, name) {
+			continue
 		}
 
-		trafficWeightsJson, err := convert.ToJsonArray(trafficWeights)
-		if err != nil {
-			return fmt.Errorf("converting traffic weights to JSON: %w", err)
+		if entry, has := entries[name]; has {
+			item = entry
+			delete(entries, name)
 		}
-		if err := containerApp.Set(pathConfigurationIngressTraffic, trafficWeightsJson); err != nil {
-			return fmt.Errorf("setting traffic weights: %w", err)
+
+		env = append(env, item)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(entries)) {
+		env = append(env, entries[name])
+	}
+
+	container["env"] = env
+}
+
+// setAppSettingsSecrets applies the changes of settings to the secrets of a container app: the secrets of secret
+// settings are added or updated, and the secrets of removed settings, or of settings that are no longer secret, are
+// removed when no container references them.
+func setAppSettingsSecrets(containerApp config.Config, containers []map[string]any, settings *AppSettings) error {
+	referenced := map[string]bool{}
+	for _, container := range containers {
+		env, _ := container["env"].([]any)
+		for _, item := range env {
+			if secretRef, ok := item.(map[string]any)["secretRef"].(string); ok {
+				referenced[secretRef] = true
+			}
 		}
 	}
 
-	err = cas.updateContainerApp(ctx, subscriptionId, resourceGroupName, appName, containerApp, options)
+	obsolete := map[string]bool{}
+	for _, name := range settings.Remove {
+		obsolete[AppSettingSecretName(name)] = true
+	}
+	for name := range settings.Env {
+		obsolete[AppSettingSecretName(name)] = true
+	}
+
+	values := map[string]string{}
+	for name, value := range settings.Secrets {
+		values[AppSettingSecretName(name)] = value
+	}
+
+	existingSecrets, _ := containerApp.GetSlice(pathConfigurationSecrets)
+	secrets := make([]any, 0, len(existingSecrets)+len(values))
+	for _, item := range existingSecrets {
+		secret, _ := item.(map[string]any)
+		name, _ := secret["name"].(string)
+		if obsolete[name] && !referenced[name] {
+			continue
+		}
+
+		if value, has := values[name]; has {
+			secret = map[string]any{"name": name, "value": value}
+			delete(values, name)
+		}
+
+		secrets = append(secrets, secret)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		secrets = append(secrets, map[string]any{"name": name, "value": values[name]})
+	}
+
+	if err := containerApp.Set(pathConfigurationSecrets, secrets); err != nil {
+		return fmt.Errorf("setting secrets: %w", err)
+	}
+
+	return nil
+}
+
+// Gets the environment variables of the container of the specified container app
+func (cas *containerAppService) GetAppSettings(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	options *ContainerAppOptions,
+) (map[string]AppSetting, error) {
+	containerApp, err := cas.getContainerApp(ctx, subscriptionId, resourceGroupName, appName, options)
 	if err != nil {
-		return fmt.Errorf("updating container app revision: %w", err)
+		return nil, fmt.Errorf("getting container app: %w", err)
+	}
+
+	var containers []map[string]any
+	if ok, err := containerApp.GetSection(pathTemplateContainers, &containers); !ok || err != nil || len(containers) == 0 {
+		return nil, fmt.Errorf("getting containers: %w", err)
+	}
+
+	settings := map[string]AppSetting{}
+	secretRefs := map[string]string{}
+	env, _ := containers[0]["env"].([]any)
+	for _, item := range env {
+		entry, _ := item.(map[string]any)
+		name, _ := entry["name"].(string)
+		if secretRef, ok := entry["secretRef"].(string); ok {
+			secretRefs[name] = secretRef
+			continue
+		}
+
+		value, _ := entry["value"].(string)
+		settings[name] = AppSetting{Value: value}
+	}
+
+	if len(secretRefs) == 0 {
+		return settings, nil
+	}
+
+	appClient, err := cas.createContainerAppsClient(ctx, subscriptionId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	secretsResponse, err := appClient.ListSecrets(ctx, resourceGroupName, appName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing secrets: %w", err)
+	}
+
+	values := map[string]string{}
+	for _, secret := range secretsResponse.Value {
+		if secret != nil && secret.Name != nil && secret.Value != nil {
+			values[*secret.Name] = *secret.Value
+		}
+	}
+
+	for name, secretRef := range secretRefs {
+		settings[name] = AppSetting{Value: values[secretRef], Secret: true}
+	}
+
+	return settings, nil
+}
+
+// updateTraffic updates the ingress traffic weights of the specified container app, without creating a revision.
+func (cas *containerAppService) updateTraffic(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	trafficWeights []*armappcontainers.TrafficWeight,
+	options *ContainerAppOptions,
+) error {
+	containerApp, err := cas.getContainerApp(ctx, subscriptionId, resourceGroupName, appName, options)
+	if err != nil {
+		return fmt.Errorf("getting container app: %w", err)
+	}
+
+	if err := setTraffic(containerApp, trafficWeights); err != nil {
+		return err
+	}
+
+	containerApp, err = cas.syncSecrets(ctx, subscriptionId, resourceGroupName, appName, containerApp)
+	if err != nil {
+		return fmt.Errorf("syncing secrets: %w", err)
+	}
+
+	return cas.updateContainerApp(ctx, subscriptionId, resourceGroupName, appName, containerApp, options)
+}
+
+func setTraffic(containerApp config.Config, trafficWeights []*armappcontainers.TrafficWeight) error {
+	trafficWeightsJson, err := convert.ToJsonArray(trafficWeights)
+	if err != nil {
+		return fmt.Errorf("converting traffic weights to JSON: %w", err)
+	}
+	if err := containerApp.Set(pathConfigurationIngressTraffic, trafficWeightsJson); err != nil {
+		return fmt.Errorf("setting traffic weights: %w", err)
 	}
 
 	return nil
 }
 
+// pinTraffic returns a copy of the traffic weights where the weight of the latest revision is assigned to the
+// revision named latestRevisionName.
+func pinTraffic(traffic []*armappcontainers.TrafficWeight, latestRevisionName string) []*armappcontainers.TrafficWeight {
+	pinned := make([]*armappcontainers.TrafficWeight, 0, len(traffic))
+	for _, weight := range traffic {
+		pinnedWeight := *weight
+		if weight.LatestRevision != nil && *weight.LatestRevision && latestRevisionName != "" {
+			pinnedWeight.LatestRevision = nil
+			pinnedWeight.RevisionName = to.Ptr(latestRevisionName)
+		}
+		pinned = append(pinned, &pinnedWeight)
+	}
+
+	return pinned
+}
+
+// splitTraffic returns the traffic weights that route percent of the traffic to a new revision, and the rest to the
+// revisions of the previous traffic weights, in the same proportions.
+func splitTraffic(
+	previous []*armappcontainers.TrafficWeight,
+	newRevisionName string,
+	percent int32,
+) []*armappcontainers.TrafficWeight {
+	var total int32
+	for _, weight := range previous {
+		if weight.Weight != nil {
+			total += *weight.Weight
+		}
+	}
+
+	// Without previous traffic, there is no other revision to route traffic to.
+	if total == 0 {
+		percent = 100
+	}
+
+	remaining := 100 - percent
+	split := make([]*armappcontainers.TrafficWeight, 0, len(previous)+1)
+	var assigned int32
+	first := -1
+	for _, weight := range previous {
+		splitWeight := *weight
+		if total > 0 && weight.Weight != nil && *weight.Weight > 0 {
+			splitWeight.Weight = to.Ptr(*weight.Weight * remaining / total)
+			assigned += *splitWeight.Weight
+			if first < 0 {
+				first = len(split)
+			}
+		}
+		split = append(split, &splitWeight)
+	}
+
+	// Weights must add up to 100, so the rounding remainder goes to the first revision with traffic.
+	if first >= 0 {
+		split[first].Weight = to.Ptr(*split[first].Weight + remaining - assigned)
+	}
+
+	return append(split, &armappcontainers.TrafficWeight{
+		RevisionName: to.Ptr(newRevisionName),
+		Weight:       to.Ptr(percent),
+	})
+}
+
 func (cas *containerAppService) syncSecrets(
 	ctx context.Context,
 	subscriptionId string,
@@ -590,6 +1124,23 @@ func (cas *containerAppService) createContainerAppsClient(
 	return client, nil
 }
 
+func (cas *containerAppService) createRevisionsClient(
+	ctx context.Context,
+	subscriptionId string,
+) (*armappcontainers.ContainerAppsRevisionsClient, error) {
+	credential, err := cas.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := armappcontainers.NewContainerAppsRevisionsClient(subscriptionId, credential, cas.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating ContainerAppsRevisions client: %w", err)
+	}
+
+	return client, nil
+}
+
 func (cas *containerAppService) createJobsClient(
 	ctx context.Context,
 	subscriptionId string,
@@ -673,6 +1224,21 @@ func (cas *containerAppService) UpdateContainerAppJobImage(
 	imageName string,
 	envVars map[string]string,
 	options *ContainerAppOptions,
+) error {
+	return cas.UpdateContainerAppJob(
+		ctx, subscriptionId, resourceGroupName, jobName, imageName, envVars, nil, options,
+	)
+}
+
+func (cas *containerAppService) UpdateContainerAppJob(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	jobName string,
+	imageName string,
+	envVars map[string]string,
+	configuration *armappcontainers.JobConfiguration,
+	options *ContainerAppOptions,
 ) error {
 	if imageName == "" {
 		return fmt.Errorf(
@@ -768,6 +1334,10 @@ func (cas *containerAppService) UpdateContainerAppJobImage(
 		},
 	}
 
+	if configuration != nil {
+		jobPatch.Properties.Configuration = mergeJobConfiguration(job.Properties.Configuration, configuration)
+	}
+
 	poller, err := jobsClient.BeginUpdate(
 		ctx, resourceGroupName, jobName, jobPatch, nil,
 	)
@@ -789,6 +1359,99 @@ func (cas *containerAppService) UpdateContainerAppJobImage(
 	return nil
 }
 
+// mergeJobConfiguration returns the configuration of a job with the trigger and replica settings of configuration.
+// The trigger configurations of the other trigger types are cleared, since a job has a single trigger. Secrets are
+// left out, because their values aren't returned with the job, and omitting them keeps the existing secrets.
+func mergeJobConfiguration(
+	current *armappcontainers.JobConfiguration,
+	configuration *armappcontainers.JobConfiguration,
+) *armappcontainers.JobConfiguration {
+	merged := armappcontainers.JobConfiguration{}
+	if current != nil {
+		merged = *current
+	}
+	merged.Secrets = nil
+
+	if configuration.TriggerType != nil {
+		merged.TriggerType = configuration.TriggerType
+		merged.ManualTriggerConfig = configuration.ManualTriggerConfig
+		merged.ScheduleTriggerConfig = configuration.ScheduleTriggerConfig
+		merged.EventTriggerConfig = configuration.EventTriggerConfig
+	}
+
+	if configuration.ReplicaTimeout != nil {
+		merged.ReplicaTimeout = configuration.ReplicaTimeout
+	}
+
+	if configuration.ReplicaRetryLimit != nil {
+		merged.ReplicaRetryLimit = configuration.ReplicaRetryLimit
+	}
+
+	return &merged
+}
+
+func (cas *containerAppService) StartContainerAppJobExecution(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	jobName string,
+	options *ContainerAppOptions,
+) (string, error) {
+	jobsClient, err := cas.createJobsClient(ctx, subscriptionId, createApiVersionPolicy(options))
+	if err != nil {
+		return "", fmt.Errorf("creating jobs client: %w", err)
+	}
+
+	poller, err := jobsClient.BeginStart(ctx, resourceGroupName, jobName, nil)
+	if err != nil {
+		return "", fmt.Errorf("starting container app job %s: %w", jobName, err)
+	}
+
+	response, err := poller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{
+		Frequency: containerAppPollFrequency,
+	})
+	if err != nil {
+		return "", fmt.Errorf("waiting for container app job %s to start: %w", jobName, err)
+	}
+
+	if response.Name == nil {
+		return "", fmt.Errorf("starting container app job %s: no execution was returned", jobName)
+	}
+
+	return *response.Name, nil
+}
+
+func (cas *containerAppService) GetContainerAppJobExecution(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	jobName string,
+	executionName string,
+	options *ContainerAppOptions,
+) (*armappcontainers.JobExecution, error) {
+	credential, err := cas.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	clientOptions := *cas.armClientOptions
+	if apiVersionPolicy := createApiVersionPolicy(options); apiVersionPolicy != nil {
+		clientOptions.PerCallPolicies = append(slices.Clone(clientOptions.PerCallPolicies), apiVersionPolicy)
+	}
+
+	client, err := armappcontainers.NewContainerAppsAPIClient(subscriptionId, credential, &clientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating ContainerAppsAPI client: %w", err)
+	}
+
+	response, err := client.JobExecution(ctx, resourceGroupName, jobName, executionName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting execution %s of container app job %s: %w", executionName, jobName, err)
+	}
+
+	return &response.JobExecution, nil
+}
+
 type containerAppCustomApiVersionAndBodyPolicy struct {
 	apiVersion string
 	body       *json.RawMessage
@@ -225,7 +225,7 @@ func Test_AddRevision_ARMCallCount(t *testing.T) {
 
 			err := cas.AddRevision(
 				*mockContext.Context, subscriptionId, resourceGroup, appName,
-				"new-image", nil, nil)
+				"new-image", nil, nil, nil)
 			require.NoError(t, err)
 
 			totalCalls := int(getCalls.Load() + secretsCalls.Load() +
@@ -290,7 +290,7 @@ func Test_AddRevision_MultiRevision_CombinedPatch(t *testing.T) {
 
 	err := cas.AddRevision(
 		*mockContext.Context, subscriptionId, resourceGroup, appName,
-		"new-image", nil, nil)
+		"new-image", nil, nil, nil)
 	require.NoError(t, err)
 
 	// Exactly 1 PATCH call
@@ -366,7 +366,7 @@ func Benchmark_AddRevision(b *testing.B) {
 	for range b.N {
 		err := cas.AddRevision(
 			*mockContext.Context, subscriptionId, resourceGroup, appName,
-			"new-image", nil, nil)
+			"new-image", nil, nil, nil)
 		if err != nil {
 			b.Fatal(err)
 		}
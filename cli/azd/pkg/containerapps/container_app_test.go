@@ -128,7 +128,7 @@ func Test_ContainerApp_AddRevision(t *testing.T) {
 		mockContext.ArmClientOptions,
 		mockContext.AlphaFeaturesManager,
 	)
-	err := cas.AddRevision(*mockContext.Context, subscriptionId, resourceGroup, appName, updatedImageName, nil, nil)
+	err := cas.AddRevision(*mockContext.Context, subscriptionId, resourceGroup, appName, updatedImageName, nil, nil, nil)
 	require.NoError(t, err)
 
 	// Verify container image is updated
@@ -209,7 +209,7 @@ func Test_ContainerApp_AddRevision_MultipleRevisionMode(t *testing.T) {
 		mockContext.ArmClientOptions,
 		mockContext.AlphaFeaturesManager,
 	)
-	err := cas.AddRevision(*mockContext.Context, subscriptionId, resourceGroup, appName, updatedImageName, nil, nil)
+	err := cas.AddRevision(*mockContext.Context, subscriptionId, resourceGroup, appName, updatedImageName, nil, nil, nil)
 	require.NoError(t, err)
 	require.Equal(t, 1, updateCallCount)
 
@@ -285,7 +285,7 @@ func Test_ContainerApp_AddRevision_WithEnvVars(t *testing.T) {
 	err := cas.AddRevision(*mockContext.Context, subscriptionId, resourceGroup, appName, updatedImageName, map[string]string{
 		"OVERRIDE": newOverrideValue,
 		"NEW":      newValue,
-	}, nil)
+	}, nil, nil)
 	require.NoError(t, err)
 
 	var updatedContainerApp *armappcontainers.ContainerApp
@@ -307,6 +307,101 @@ func Test_ContainerApp_AddRevision_WithEnvVars(t *testing.T) {
 	}, actualEnv)
 }
 
+func Test_ContainerApp_AddRevision_WithAppSettings(t *testing.T) {
+	subscriptionId := "SUBSCRIPTION_ID"
+	resourceGroup := "RESOURCE_GROUP"
+	appName := "APP_NAME"
+
+	containerApp := &armappcontainers.ContainerApp{
+		Location: to.Ptr("eastus2"),
+		Name:     &appName,
+		Properties: &armappcontainers.ContainerAppProperties{
+			Configuration: &armappcontainers.Configuration{
+				ActiveRevisionsMode: to.Ptr(armappcontainers.ActiveRevisionsModeSingle),
+				Secrets: []*armappcontainers.Secret{
+					{Name: to.Ptr("api-key")},
+					{Name: to.Ptr("old-token")},
+				},
+			},
+			Template: &armappcontainers.Template{
+				Containers: []*armappcontainers.Container{
+					{
+						Image: to.Ptr("ORIGINAL_IMAGE_NAME"),
+						Env: []*armappcontainers.EnvironmentVar{
+							{Name: to.Ptr("KEEP"), Value: to.Ptr("keep")},
+							{Name: to.Ptr("API_KEY"), SecretRef: to.Ptr("api-key")},
+							{Name: to.Ptr("OLD_TOKEN"), SecretRef: to.Ptr("old-token")},
+							{Name: to.Ptr("LOG_LEVEL"), Value: to.Ptr("info")},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	mockContext := mocks.NewMockContext(t.Context())
+	_ = mockazsdk.MockContainerAppGet(mockContext, subscriptionId, resourceGroup, appName, containerApp)
+	_ = mockazsdk.MockContainerAppSecretsList(mockContext, subscriptionId, resourceGroup, appName,
+		&armappcontainers.SecretsCollection{
+			Value: []*armappcontainers.ContainerAppSecret{
+				{Name: to.Ptr("api-key"), Value: to.Ptr("old-key")},
+				{Name: to.Ptr("old-token"), Value: to.Ptr("token")},
+			},
+		})
+	updateContainerAppRequest := mockazsdk.MockContainerAppUpdate(
+		mockContext, subscriptionId, resourceGroup, appName, containerApp)
+
+	cas := NewContainerAppService(
+		mockContext.SubscriptionCredentialProvider,
+		clock.NewMock(),
+		mockContext.ArmClientOptions,
+		mockContext.AlphaFeaturesManager,
+	)
+
+	settings, err := cas.GetAppSettings(*mockContext.Context, subscriptionId, resourceGroup, appName, nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]AppSetting{
+		"KEEP":      {Value: "keep"},
+		"API_KEY":   {Value: "old-key", Secret: true},
+		"OLD_TOKEN": {Value: "token", Secret: true},
+		"LOG_LEVEL": {Value: "info"},
+	}, settings)
+
+	err = cas.AddRevision(
+		*mockContext.Context, subscriptionId, resourceGroup, appName, "UPDATED_IMAGE_NAME", nil,
+		&AppSettings{
+			Env:     map[string]string{"LOG_LEVEL": "debug"},
+			Secrets: map[string]string{"API_KEY": "new-key", "DB_PASSWORD": "p@ss"},
+			Remove:  []string{"OLD_TOKEN"},
+		}, nil)
+	require.NoError(t, err)
+
+	var updatedContainerApp *armappcontainers.ContainerApp
+	err = mocks.ReadHttpBody(updateContainerAppRequest.Body, &updatedContainerApp)
+	require.NoError(t, err)
+
+	var env []string
+	for _, envVar := range updatedContainerApp.Properties.Template.Containers[0].Env {
+		if envVar.SecretRef != nil {
+			env = append(env, *envVar.Name+"->"+*envVar.SecretRef)
+		} else {
+			env = append(env, *envVar.Name+"="+*envVar.Value)
+		}
+	}
+	require.Equal(t, []string{"KEEP=keep", "API_KEY->api-key", "LOG_LEVEL=debug", "DB_PASSWORD->db-password"}, env)
+
+	secrets := map[string]string{}
+	for _, secret := range updatedContainerApp.Properties.Configuration.Secrets {
+		secrets[*secret.Name] = *secret.Value
+	}
+	require.Equal(t, map[string]string{"api-key": "new-key", "db-password": "p@ss"}, secrets)
+}
+
+func Test_AppSettingSecretName(t *testing.T) {
+	require.Equal(t, "api-key", AppSettingSecretName("API_KEY"))
+	require.Equal(t, "connectionstrings--db", AppSettingSecretName("ConnectionStrings__Db"))
+}
+
 func Test_ContainerApp_DeployYaml(t *testing.T) {
 	mockContext := mocks.NewMockContext(t.Context())
 
@@ -500,6 +595,116 @@ func Test_ContainerAppJob_UpdateImage(t *testing.T) {
 	require.Equal(t, updatedImage, *patchBody.Properties.Template.Containers[0].Image)
 }
 
+func Test_ContainerAppJob_UpdateConfiguration(t *testing.T) {
+	subscriptionId := "SUBSCRIPTION_ID"
+	resourceGroup := "RESOURCE_GROUP"
+	jobName := "MY_JOB"
+
+	job := &armappcontainers.Job{
+		Name: new(jobName),
+		Properties: &armappcontainers.JobProperties{
+			Configuration: &armappcontainers.JobConfiguration{
+				TriggerType:    to.Ptr(armappcontainers.TriggerTypeManual),
+				ReplicaTimeout: to.Ptr[int32](1800),
+				ManualTriggerConfig: &armappcontainers.JobConfigurationManualTriggerConfig{
+					Parallelism: to.Ptr[int32](1),
+				},
+				Registries: []*armappcontainers.RegistryCredentials{
+					{Server: to.Ptr("myregistry.azurecr.io"), Identity: to.Ptr("system")},
+				},
+				Secrets: []*armappcontainers.Secret{{Name: to.Ptr("registry-password")}},
+			},
+			Template: &armappcontainers.JobTemplate{
+				Containers: []*armappcontainers.Container{
+					{Name: new(jobName), Image: to.Ptr("myregistry.azurecr.io/myimage:v1")},
+				},
+			},
+		},
+	}
+
+	mockContext := mocks.NewMockContext(t.Context())
+	_ = mockazsdk.MockContainerAppJobGet(
+		mockContext, subscriptionId, resourceGroup, jobName, job,
+	)
+	mockUpdate := mockazsdk.MockContainerAppJobUpdate(
+		mockContext, subscriptionId, resourceGroup, jobName, job,
+	)
+
+	cas := NewContainerAppService(
+		mockContext.SubscriptionCredentialProvider,
+		clock.NewMock(),
+		mockContext.ArmClientOptions,
+		mockContext.AlphaFeaturesManager,
+	)
+
+	err := cas.UpdateContainerAppJob(
+		*mockContext.Context, subscriptionId, resourceGroup,
+		jobName, "myregistry.azurecr.io/myimage:v2", nil,
+		&armappcontainers.JobConfiguration{
+			TriggerType: to.Ptr(armappcontainers.TriggerTypeSchedule),
+			ScheduleTriggerConfig: &armappcontainers.JobConfigurationScheduleTriggerConfig{
+				CronExpression: to.Ptr("0 2 * * *"),
+			},
+		},
+		nil,
+	)
+	require.NoError(t, err)
+
+	var patchBody armappcontainers.JobPatchProperties
+	err = mocks.ReadHttpBody(mockUpdate.Body, &patchBody)
+	require.NoError(t, err)
+
+	configuration := patchBody.Properties.Configuration
+	require.NotNil(t, configuration)
+	require.Equal(t, armappcontainers.TriggerTypeSchedule, *configuration.TriggerType)
+	require.Equal(t, "0 2 * * *", *configuration.ScheduleTriggerConfig.CronExpression)
+	// The configuration of the previous trigger is cleared, and the other settings of the job are kept.
+	require.Nil(t, configuration.ManualTriggerConfig)
+	require.Equal(t, int32(1800), *configuration.ReplicaTimeout)
+	require.Len(t, configuration.Registries, 1)
+	require.Nil(t, configuration.Secrets)
+	require.Equal(t, "myregistry.azurecr.io/myimage:v2", *patchBody.Properties.Template.Containers[0].Image)
+}
+
+func Test_ContainerAppJob_StartExecution(t *testing.T) {
+	subscriptionId := "SUBSCRIPTION_ID"
+	resourceGroup := "RESOURCE_GROUP"
+	jobName := "MY_JOB"
+	executionName := "MY_JOB-abc123"
+
+	mockContext := mocks.NewMockContext(t.Context())
+	_ = mockazsdk.MockContainerAppJobStart(
+		mockContext, subscriptionId, resourceGroup, jobName, executionName,
+	)
+	_ = mockazsdk.MockContainerAppJobExecutionGet(
+		mockContext, subscriptionId, resourceGroup, jobName, &armappcontainers.JobExecution{
+			Name: to.Ptr(executionName),
+			Properties: &armappcontainers.JobExecutionProperties{
+				Status: to.Ptr(armappcontainers.JobExecutionRunningStateSucceeded),
+			},
+		},
+	)
+
+	cas := NewContainerAppService(
+		mockContext.SubscriptionCredentialProvider,
+		clock.NewMock(),
+		mockContext.ArmClientOptions,
+		mockContext.AlphaFeaturesManager,
+	)
+
+	name, err := cas.StartContainerAppJobExecution(
+		*mockContext.Context, subscriptionId, resourceGroup, jobName, nil,
+	)
+	require.NoError(t, err)
+	require.Equal(t, executionName, name)
+
+	execution, err := cas.GetContainerAppJobExecution(
+		*mockContext.Context, subscriptionId, resourceGroup, jobName, name, nil,
+	)
+	require.NoError(t, err)
+	require.Equal(t, armappcontainers.JobExecutionRunningStateSucceeded, *execution.Properties.Status)
+}
+
 func Test_ContainerAppJob_UpdateImage_NilContainers(t *testing.T) {
 	subscriptionId := "SUBSCRIPTION_ID"
 	resourceGroup := "RESOURCE_GROUP"
@@ -1184,3 +1389,190 @@ func TestContainerAppIngressConfiguration(t *testing.T) {
 		require.Len(t, config.HostNames, 2)
 	})
 }
+
+func Test_ContainerApp_StageRevision(t *testing.T) {
+	subscriptionId := "SUBSCRIPTION_ID"
+	location := "eastus2"
+	resourceGroup := "RESOURCE_GROUP"
+	appName := "APP_NAME"
+	latestRevisionName := fmt.Sprintf("%s--azd-1", appName)
+
+	containerApp := &armappcontainers.ContainerApp{
+		Location: &location,
+		Name:     &appName,
+		Properties: &armappcontainers.ContainerAppProperties{
+			LatestRevisionName: &latestRevisionName,
+			Configuration: &armappcontainers.Configuration{
+				ActiveRevisionsMode: to.Ptr(armappcontainers.ActiveRevisionsModeSingle),
+				Ingress: &armappcontainers.Ingress{
+					Traffic: []*armappcontainers.TrafficWeight{
+						{
+							LatestRevision: to.Ptr(true),
+							Weight:         to.Ptr[int32](100),
+						},
+					},
+				},
+			},
+			Template: &armappcontainers.Template{
+				Containers: []*armappcontainers.Container{
+					{
+						Image: to.Ptr("ORIGINAL_IMAGE_NAME"),
+					},
+				},
+			},
+		},
+	}
+
+	mockContext := mocks.NewMockContext(t.Context())
+	_ = mockazsdk.MockContainerAppGet(mockContext, subscriptionId, resourceGroup, appName, containerApp)
+	_ = mockazsdk.MockContainerAppSecretsList(
+		mockContext, subscriptionId, resourceGroup, appName, &armappcontainers.SecretsCollection{})
+	updateRequest := mockazsdk.MockContainerAppUpdate(mockContext, subscriptionId, resourceGroup, appName, containerApp)
+
+	cas := NewContainerAppService(
+		mockContext.SubscriptionCredentialProvider,
+		clock.NewMock(),
+		mockContext.ArmClientOptions,
+		mockContext.AlphaFeaturesManager,
+	)
+	revision, err := cas.StageRevision(
+		*mockContext.Context, subscriptionId, resourceGroup, appName, "UPDATED_IMAGE_NAME", nil, nil, 20, nil)
+	require.NoError(t, err)
+
+	newRevisionName := fmt.Sprintf("%s--azd-0", appName)
+	require.Equal(t, newRevisionName, revision.Name)
+	require.Len(t, revision.PreviousTraffic, 1)
+	require.Equal(t, latestRevisionName, *revision.PreviousTraffic[0].RevisionName)
+	require.Equal(t, int32(100), *revision.PreviousTraffic[0].Weight)
+
+	var updatedContainerApp *armappcontainers.ContainerApp
+	require.NoError(t, mocks.ReadHttpBody(updateRequest.Body, &updatedContainerApp))
+	require.Equal(t, "UPDATED_IMAGE_NAME", *updatedContainerApp.Properties.Template.Containers[0].Image)
+	require.Equal(t,
+		armappcontainers.ActiveRevisionsModeMultiple, *updatedContainerApp.Properties.Configuration.ActiveRevisionsMode)
+
+	traffic := updatedContainerApp.Properties.Configuration.Ingress.Traffic
+	require.Len(t, traffic, 2)
+	require.Equal(t, latestRevisionName, *traffic[0].RevisionName)
+	require.Equal(t, int32(80), *traffic[0].Weight)
+	require.Equal(t, newRevisionName, *traffic[1].RevisionName)
+	require.Equal(t, int32(20), *traffic[1].Weight)
+}
+
+func Test_ContainerApp_RollbackRevision(t *testing.T) {
+	subscriptionId := "SUBSCRIPTION_ID"
+	location := "eastus2"
+	resourceGroup := "RESOURCE_GROUP"
+	appName := "APP_NAME"
+	previousRevisionName := fmt.Sprintf("%s--azd-1", appName)
+	stagedRevisionName := fmt.Sprintf("%s--azd-2", appName)
+
+	containerApp := &armappcontainers.ContainerApp{
+		Location: &location,
+		Name:     &appName,
+		Properties: &armappcontainers.ContainerAppProperties{
+			Configuration: &armappcontainers.Configuration{
+				ActiveRevisionsMode: to.Ptr(armappcontainers.ActiveRevisionsModeMultiple),
+				Ingress:             &armappcontainers.Ingress{},
+			},
+			Template: &armappcontainers.Template{},
+		},
+	}
+
+	mockContext := mocks.NewMockContext(t.Context())
+	_ = mockazsdk.MockContainerAppGet(mockContext, subscriptionId, resourceGroup, appName, containerApp)
+	_ = mockazsdk.MockContainerAppSecretsList(
+		mockContext, subscriptionId, resourceGroup, appName, &armappcontainers.SecretsCollection{})
+	updateRequest := mockazsdk.MockContainerAppUpdate(mockContext, subscriptionId, resourceGroup, appName, containerApp)
+
+	deactivated := ""
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, "/deactivate")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		deactivated = request.URL.Path
+		return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+	})
+
+	cas := NewContainerAppService(
+		mockContext.SubscriptionCredentialProvider,
+		clock.NewMock(),
+		mockContext.ArmClientOptions,
+		mockContext.AlphaFeaturesManager,
+	)
+	err := cas.RollbackRevision(*mockContext.Context, subscriptionId, resourceGroup, appName, &StagedRevision{
+		Name: stagedRevisionName,
+		PreviousTraffic: []*armappcontainers.TrafficWeight{
+			{
+				RevisionName: &previousRevisionName,
+				Weight:       to.Ptr[int32](100),
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	var updatedContainerApp *armappcontainers.ContainerApp
+	require.NoError(t, mocks.ReadHttpBody(updateRequest.Body, &updatedContainerApp))
+	traffic := updatedContainerApp.Properties.Configuration.Ingress.Traffic
+	require.Len(t, traffic, 1)
+	require.Equal(t, previousRevisionName, *traffic[0].RevisionName)
+	require.Equal(t, int32(100), *traffic[0].Weight)
+	require.Contains(t, deactivated, fmt.Sprintf("/revisions/%s/deactivate", stagedRevisionName))
+}
+
+func Test_splitTraffic(t *testing.T) {
+	weight := func(revisionName string, weight int32) *armappcontainers.TrafficWeight {
+		return &armappcontainers.TrafficWeight{RevisionName: to.Ptr(revisionName), Weight: to.Ptr(weight)}
+	}
+
+	weights := func(traffic []*armappcontainers.TrafficWeight) map[string]int32 {
+		result := map[string]int32{}
+		for _, w := range traffic {
+			result[*w.RevisionName] = *w.Weight
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		previous []*armappcontainers.TrafficWeight
+		percent  int32
+		expected map[string]int32
+	}{
+		{
+			name:     "SingleRevision",
+			previous: []*armappcontainers.TrafficWeight{weight("v1", 100)},
+			percent:  10,
+			expected: map[string]int32{"v1": 90, "v2": 10},
+		},
+		{
+			name:     "Proportional",
+			previous: []*armappcontainers.TrafficWeight{weight("v1", 50), weight("v0", 50)},
+			percent:  20,
+			expected: map[string]int32{"v1": 40, "v0": 40, "v2": 20},
+		},
+		{
+			name:     "RoundingRemainder",
+			previous: []*armappcontainers.TrafficWeight{weight("v1", 50), weight("v0", 50)},
+			percent:  25,
+			expected: map[string]int32{"v1": 38, "v0": 37, "v2": 25},
+		},
+		{
+			name:     "BlueGreen",
+			previous: []*armappcontainers.TrafficWeight{weight("v1", 100)},
+			percent:  0,
+			expected: map[string]int32{"v1": 100, "v2": 0},
+		},
+		{
+			name:     "NoPreviousTraffic",
+			previous: nil,
+			percent:  10,
+			expected: map[string]int32{"v2": 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, weights(splitTraffic(tt.previous, "v2", tt.percent)))
+		})
+	}
+}
@@ -0,0 +1,313 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package containerapps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/gorilla/websocket"
+)
+
+// The exec endpoint of a container app prefixes the messages it sends with the stream they're from, and the messages
+// sent to it with a control byte and the kind of the message.
+const (
+	execStdout byte = 1
+	execStderr byte = 2
+	execStatus byte = 3
+
+	execControl byte = 0
+	execInput   byte = 0
+	execResize  byte = 4
+)
+
+// ExecOptions configure a command run in a container app with Exec.
+type ExecOptions struct {
+	// Command is the command to run and its arguments, /bin/sh by default.
+	Command []string
+	// Replica is the replica of the latest ready revision to run the command in, the first one by default.
+	Replica string
+	// Container is the container to run the command in. It defaults to the container named after the app, or else the
+	// first container.
+	Container string
+	// TerminalSize is the size of the terminal of the command, when it's run in a terminal.
+	TerminalSize *ExecTerminalSize
+
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// ExecTerminalSize is the size of the terminal of a command run with Exec.
+type ExecTerminalSize struct {
+	Width  int `json:"Width"`
+	Height int `json:"Height"`
+}
+
+func (cas *containerAppService) Exec(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	options *ExecOptions,
+) error {
+	appClient, err := cas.createContainerAppsClient(ctx, subscriptionId, nil)
+	if err != nil {
+		return err
+	}
+
+	app, err := appClient.Get(ctx, resourceGroupName, appName, nil)
+	if err != nil {
+		return fmt.Errorf("getting container app: %w", err)
+	}
+
+	if app.Properties == nil || app.Properties.EventStreamEndpoint == nil ||
+		app.Properties.LatestReadyRevisionName == nil || *app.Properties.LatestReadyRevisionName == "" {
+		return fmt.Errorf("container app %s has no ready revision to run a command in", appName)
+	}
+	revisionName := *app.Properties.LatestReadyRevisionName
+
+	credential, err := cas.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	replicasClient, err := armappcontainers.NewContainerAppsRevisionReplicasClient(
+		subscriptionId, credential, cas.armClientOptions)
+	if err != nil {
+		return fmt.Errorf("creating ContainerAppsRevisionReplicas client: %w", err)
+	}
+
+	replicas, err := replicasClient.ListReplicas(ctx, resourceGroupName, appName, revisionName, nil)
+	if err != nil {
+		return fmt.Errorf("listing replicas of revision %s: %w", revisionName, err)
+	}
+
+	replica, container, err := execTarget(appName, revisionName, replicas.Value, options)
+	if err != nil {
+		return err
+	}
+
+	token, err := appClient.GetAuthToken(ctx, resourceGroupName, appName, nil)
+	if err != nil {
+		return fmt.Errorf("getting exec token: %w", err)
+	}
+	if token.Properties == nil || token.Properties.Token == nil {
+		return fmt.Errorf("container app %s didn't return an exec token", appName)
+	}
+
+	endpoint, err := execEndpoint(
+		*app.Properties.EventStreamEndpoint,
+		fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/containerApps/%s/revisions/%s/replicas/%s/exec/%s",
+			subscriptionId, resourceGroupName, appName, revisionName, replica, container),
+		options.Command)
+	if err != nil {
+		return err
+	}
+
+	conn, res, err := websocket.DefaultDialer.DialContext(ctx, endpoint, http.Header{
+		"Authorization": []string{"Bearer " + *token.Properties.Token},
+	})
+	if res != nil && res.Body != nil {
+		defer res.Body.Close()
+	}
+	if err != nil {
+		if res != nil {
+			return fmt.Errorf("connecting to replica %s of container app %s: %s: %w", replica, appName, res.Status, err)
+		}
+		return fmt.Errorf("connecting to replica %s of container app %s: %w", replica, appName, err)
+	}
+
+	return runExec(ctx, conn, options)
+}
+
+// execTarget returns the replica and the container to run a command in.
+func execTarget(
+	appName string,
+	revisionName string,
+	replicas []*armappcontainers.Replica,
+	options *ExecOptions,
+) (string, string, error) {
+	var replica *armappcontainers.Replica
+	for _, candidate := range replicas {
+		if candidate.Name != nil && (options.Replica == "" || *candidate.Name == options.Replica) {
+			replica = candidate
+			break
+		}
+	}
+
+	if replica == nil {
+		if options.Replica != "" {
+			return "", "", fmt.Errorf(
+				"replica %s isn't a replica of revision %s of container app %s", options.Replica, revisionName, appName)
+		}
+		return "", "", fmt.Errorf(
+			"revision %s of container app %s has no running replica, it may be scaled to zero", revisionName, appName)
+	}
+
+	container := ""
+	if replica.Properties != nil {
+		for _, candidate := range replica.Properties.Containers {
+			if candidate.Name == nil {
+				continue
+			}
+
+			if options.Container != "" {
+				if *candidate.Name == options.Container {
+					container = *candidate.Name
+				}
+			} else if container == "" || strings.EqualFold(*candidate.Name, appName) {
+				container = *candidate.Name
+			}
+		}
+	}
+
+	if container == "" {
+		if options.Container != "" {
+			return "", "", fmt.Errorf("replica %s of container app %s has no container named %s",
+				*replica.Name, appName, options.Container)
+		}
+		return "", "", fmt.Errorf("replica %s of container app %s has no containers", *replica.Name, appName)
+	}
+
+	return *replica.Name, container, nil
+}
+
+// execEndpoint returns the websocket URL of the exec endpoint of a replica, served by the host of the event stream
+// endpoint of the app.
+func execEndpoint(eventStreamEndpoint string, path string, command []string) (string, error) {
+	base, err := url.Parse(eventStreamEndpoint)
+	if err != nil || base.Host == "" {
+		return "", fmt.Errorf("invalid event stream endpoint '%s'", eventStreamEndpoint)
+	}
+
+	scheme := "wss"
+	if base.Scheme == "http" {
+		scheme = "ws"
+	}
+
+	commandLine := "/bin/sh"
+	if len(command) > 0 {
+		quoted := make([]string, 0, len(command))
+		for _, arg := range command {
+			quoted = append(quoted, quoteExecArg(arg))
+		}
+		commandLine = strings.Join(quoted, " ")
+	}
+
+	endpoint := url.URL{Scheme: scheme, Host: base.Host, Path: path}
+	endpoint.RawQuery = url.Values{"command": []string{commandLine}}.Encode()
+
+	return endpoint.String(), nil
+}
+
+// quoteExecArg quotes arg like a POSIX shell argument when it's empty or contains spaces, quotes or other characters
+// special to the shell, so that it stays a single argument of the command line sent to the exec endpoint.
+func quoteExecArg(arg string) string {
+	special := func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune("'\"\\$`|&;<>()*?[]{}#~!", r)
+	}
+
+	if arg != "" && !strings.ContainsFunc(arg, special) {
+		return arg
+	}
+
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// runExec copies the input of the command to the connection, and the output of the command from the connection, until
+// the command exits or ctx is done.
+func runExec(ctx context.Context, conn *websocket.Conn, options *ExecOptions) error {
+	var writeMu sync.Mutex
+	send := func(kind byte, data []byte) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+
+		return conn.WriteMessage(websocket.BinaryMessage, append([]byte{execControl, kind}, data...))
+	}
+
+	if options.TerminalSize != nil {
+		size, err := json.Marshal(options.TerminalSize)
+		if err != nil {
+			return err
+		}
+		if err := send(execResize, size); err != nil {
+			return fmt.Errorf("sending terminal size: %w", err)
+		}
+	}
+
+	// Closing the connection ends the reads from it when ctx is done.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+	defer conn.Close()
+
+	if options.Stdin != nil {
+		go func() {
+			buffer := make([]byte, 4096)
+			for {
+				n, err := options.Stdin.Read(buffer)
+				if n > 0 {
+					if sendErr := send(execInput, buffer[:n]); sendErr != nil {
+						return
+					}
+				}
+				if err != nil {
+					// End of transmission, so the shell exits once its input ends.
+					_ = send(execInput, []byte{4})
+					return
+				}
+			}
+		}()
+	}
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			// The endpoint closes the connection when the command exits, without always closing the websocket first.
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseAbnormalClosure) ||
+				errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("reading command output: %w", err)
+		}
+
+		// Messages from the exec endpoint itself, rather than from the command, start with the control byte.
+		if len(message) > 1 && message[0] == execControl {
+			message = message[1:]
+		}
+		if len(message) == 0 {
+			continue
+		}
+
+		switch message[0] {
+		case execStdout:
+			if options.Stdout != nil {
+				_, _ = options.Stdout.Write(message[1:])
+			}
+		case execStderr, execStatus:
+			if options.Stderr != nil {
+				_, _ = options.Stderr.Write(message[1:])
+			}
+		}
+	}
+}
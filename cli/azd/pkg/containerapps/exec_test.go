@@ -0,0 +1,166 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package containerapps
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/benbjohnson/clock"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContainerApp_Exec(t *testing.T) {
+	subscriptionId := "SUBSCRIPTION_ID"
+	resourceGroup := "RESOURCE_GROUP"
+	appName := "api"
+
+	var received [][]byte
+	var execRequest *http.Request
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		execRequest = r
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// The terminal size, the input, and the end of the input.
+		for range 3 {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			received = append(received, message)
+		}
+
+		_ = conn.WriteMessage(websocket.BinaryMessage, []byte{execControl, execStdout, 'o', 'k'})
+		_ = conn.WriteMessage(websocket.BinaryMessage, []byte{execStderr, 'e', 'r', 'r'})
+		_ = conn.WriteMessage(
+			websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	}))
+	defer server.Close()
+
+	mockContext := mocks.NewMockContext(t.Context())
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/containerApps/api")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappcontainers.ContainerApp{
+			Properties: &armappcontainers.ContainerAppProperties{
+				EventStreamEndpoint:     new(server.URL + "/subscriptions/SUBSCRIPTION_ID/eventstream"),
+				LatestReadyRevisionName: new("api--rev2"),
+			},
+		})
+	})
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/revisions/api--rev2/replicas")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappcontainers.ReplicaCollection{
+			Value: []*armappcontainers.Replica{{
+				Name: new("api--rev2-abc"),
+				Properties: &armappcontainers.ReplicaProperties{
+					Containers: []*armappcontainers.ReplicaContainer{{Name: new("sidecar")}, {Name: new("api")}},
+				},
+			}},
+		})
+	})
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, "/getAuthtoken")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappcontainers.ContainerAppAuthToken{
+			Properties: &armappcontainers.ContainerAppAuthTokenProperties{Token: new("APP_TOKEN")},
+		})
+	})
+
+	cas := NewContainerAppService(
+		mockContext.SubscriptionCredentialProvider,
+		clock.NewMock(),
+		mockContext.ArmClientOptions,
+		mockContext.AlphaFeaturesManager,
+	)
+
+	var stdout, stderr bytes.Buffer
+	err := cas.Exec(*mockContext.Context, subscriptionId, resourceGroup, appName, &ExecOptions{
+		Command:      []string{"/bin/bash"},
+		TerminalSize: &ExecTerminalSize{Width: 80, Height: 24},
+		Stdin:        strings.NewReader("ls\n"),
+		Stdout:       &stdout,
+		Stderr:       &stderr,
+	})
+	require.NoError(t, err)
+
+	require.NotNil(t, execRequest)
+	require.Equal(t,
+		"/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/containerApps/api/revisions/api--rev2"+
+			"/replicas/api--rev2-abc/exec/api",
+		execRequest.URL.Path)
+	require.Equal(t, "/bin/bash", execRequest.URL.Query().Get("command"))
+	require.Equal(t, "Bearer APP_TOKEN", execRequest.Header.Get("Authorization"))
+
+	require.Equal(t, [][]byte{
+		append([]byte{execControl, execResize}, []byte(`{"Width":80,"Height":24}`)...),
+		{execControl, execInput, 'l', 's', '\n'},
+		{execControl, execInput, 4},
+	}, received)
+	require.Equal(t, "ok", stdout.String())
+	require.Equal(t, "err", stderr.String())
+}
+
+func Test_execTarget(t *testing.T) {
+	replicas := []*armappcontainers.Replica{
+		{
+			Name: new("api--rev2-abc"),
+			Properties: &armappcontainers.ReplicaProperties{
+				Containers: []*armappcontainers.ReplicaContainer{{Name: new("main")}, {Name: new("sidecar")}},
+			},
+		},
+		{Name: new("api--rev2-def")},
+	}
+
+	replica, container, err := execTarget("api", "api--rev2", replicas, &ExecOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "api--rev2-abc", replica)
+	require.Equal(t, "main", container)
+
+	_, container, err = execTarget("api", "api--rev2", replicas, &ExecOptions{Container: "sidecar"})
+	require.NoError(t, err)
+	require.Equal(t, "sidecar", container)
+
+	_, _, err = execTarget("api", "api--rev2", replicas, &ExecOptions{Replica: "api--rev2-def"})
+	require.ErrorContains(t, err, "replica api--rev2-def of container app api has no containers")
+
+	_, _, err = execTarget("api", "api--rev2", replicas, &ExecOptions{Replica: "missing"})
+	require.ErrorContains(t, err, "replica missing isn't a replica of revision api--rev2")
+
+	_, _, err = execTarget("api", "api--rev2", nil, &ExecOptions{})
+	require.ErrorContains(t, err, "has no running replica")
+}
+
+func Test_execEndpoint(t *testing.T) {
+	const eventStream = "https://api.example.azurecontainerapps.dev/subscriptions/sub/eventstream"
+
+	command := func(t *testing.T, argv []string) string {
+		endpoint, err := execEndpoint(eventStream, "/exec/api", argv)
+		require.NoError(t, err)
+
+		parsed, err := url.Parse(endpoint)
+		require.NoError(t, err)
+		require.Equal(t, "wss", parsed.Scheme)
+		return parsed.Query().Get("command")
+	}
+
+	require.Equal(t, "/bin/sh", command(t, nil))
+	require.Equal(t, "ls -la /app", command(t, []string{"ls", "-la", "/app"}))
+	require.Equal(t, `sh -c 'echo "hello world" > /tmp/out'`,
+		command(t, []string{"sh", "-c", `echo "hello world" > /tmp/out`}))
+	require.Equal(t, `echo 'it'\''s' ''`, command(t, []string{"echo", "it's", ""}))
+}
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"os"
 	"slices"
@@ -173,12 +174,15 @@ func (r *RemoteBuildManager) RunDockerBuildRequestWithLogs(
 		return err
 	}
 
+	runID := *runResp.Properties.RunID
+	log.Printf("remote build run '%s' scheduled on registry '%s'", runID, registryName)
+
 	runClient, err := armcontainerregistry.NewRunsClient(subscriptionID, cred, r.armClientOptions)
 	if err != nil {
 		return err
 	}
 
-	logRes, err := runClient.GetLogSasURL(ctx, resourceGroupName, registryName, *runResp.Properties.RunID, nil)
+	logRes, err := runClient.GetLogSasURL(ctx, resourceGroupName, registryName, runID, nil)
 	if err != nil {
 		return err
 	}
@@ -194,9 +198,12 @@ func (r *RemoteBuildManager) RunDockerBuildRequestWithLogs(
 
 	err = streamLogs(ctx, logBlobClient, io.MultiWriter(&buildLog, writer))
 	if err != nil {
-		return err
+		return fmt.Errorf("streaming logs of remote build run '%s': %w", runID, err)
 	}
 
+	// The writer usually only shows the last lines of the log, so the full log is kept in the debug log.
+	log.Printf("remote build run '%s' log:\n%s", runID, buildLog.String())
+
 	// Poll until the run is complete - we do this ourselves because the poller returned by BeginScheduleRun treats all
 	// states as terminal and so calling `PollUntilDone` will return even if if the run is still progressing.
 	//
@@ -204,7 +211,7 @@ func (r *RemoteBuildManager) RunDockerBuildRequestWithLogs(
 	// available shortly, and so we used an exponential backoff with a capped duration and a short initial delay.
 	return retry.Do(ctx, retry.WithCappedDuration(30*time.Second, retry.NewExponential(1*time.Second)),
 		func(ctx context.Context) error {
-			runRes, err := runClient.Get(ctx, resourceGroupName, registryName, *runResp.Properties.RunID, nil)
+			runRes, err := runClient.Get(ctx, resourceGroupName, registryName, runID, nil)
 			if err != nil {
 				return err
 			}
@@ -214,7 +221,8 @@ func (r *RemoteBuildManager) RunDockerBuildRequestWithLogs(
 			}
 
 			if *runRes.Properties.Status != armcontainerregistry.RunStatusSucceeded {
-				return fmt.Errorf("remote build failed: %v", buildLog.String())
+				return fmt.Errorf(
+					"remote build failed (run '%s', status %s): %v", runID, *runRes.Properties.Status, buildLog.String())
 			}
 
 			return nil
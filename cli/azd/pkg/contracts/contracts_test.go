@@ -312,6 +312,28 @@ func TestShowResult_JSON_ingress_omitted_when_empty(t *testing.T) {
 	assert.NotContains(t, parsed, "ingressUrl")
 }
 
+func TestShowService_JSON_status(t *testing.T) {
+	lastDeployed := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	svc := ShowService{
+		Project: ShowServiceProject{Path: "./src/api", Type: ShowTypePython},
+		Status: &ShowServiceStatus{
+			State:        ShowServiceStateRunning,
+			Replicas:     new(2),
+			Endpoints:    []string{"https://api.example.com/"},
+			LastDeployed: &lastDeployed,
+		},
+	}
+	data, err := json.Marshal(svc)
+	require.NoError(t, err)
+	assert.Contains(t, string(data),
+		`"status":{"state":"running","replicas":2,"endpoints":["https://api.example.com/"],`+
+			`"lastDeployed":"2024-01-02T15:04:05Z"}`)
+
+	data, err = json.Marshal(ShowService{Project: svc.Project})
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "status")
+}
+
 func TestShowService_JSON_nil_target(t *testing.T) {
 	result := ShowService{
 		Project: ShowServiceProject{
@@ -6,6 +6,8 @@ package contracts
 type EnvRefreshResult struct {
 	Outputs   map[string]EnvRefreshOutputParameter `json:"outputs"`
 	Resources []EnvRefreshResource                 `json:"resources"`
+	// Timings is only set by `azd provision --timings`.
+	Timings []EnvRefreshTiming `json:"timings,omitempty"`
 }
 
 // EvnRefreshOutputType are the values for the "type" property of an output.
@@ -30,3 +32,27 @@ type EnvRefreshOutputParameter struct {
 type EnvRefreshResource struct {
 	Id string `json:"id"`
 }
+
+// EnvRefreshTiming is the contract for an entry in the "timings" array, which reports how long a resource or module
+// took to deploy.
+type EnvRefreshTiming struct {
+	Name            string  `json:"name"`
+	Type            string  `json:"type"`
+	Module          bool    `json:"module"`
+	State           string  `json:"state"`
+	DurationSeconds float64 `json:"durationSeconds"`
+}
+
+// EnvRefreshCheckResult is the contract for the output of `azd env refresh --check`.
+type EnvRefreshCheckResult struct {
+	// Stale is true when at least one environment value is out of date with the provisioning outputs.
+	Stale   bool                    `json:"stale"`
+	Outputs []EnvRefreshStaleOutput `json:"outputs"`
+}
+
+// EnvRefreshStaleOutput is the contract for an entry in the "outputs" array of an EnvRefreshCheckResult.
+type EnvRefreshStaleOutput struct {
+	Name string `json:"name"`
+	// Reason is one of "missing", "changed" or "outdated".
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,16 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package contracts
+
+import "time"
+
+// EnvSnapshot is the contract for a snapshot in the output of `azd env snapshot list`. The values and configuration
+// of the snapshot aren't included, since they can contain secrets.
+type EnvSnapshot struct {
+	Id          string            `json:"id"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	Description string            `json:"description,omitempty"`
+	ValueCount  int               `json:"valueCount"`
+	Artifacts   map[string]string `json:"artifacts,omitempty"`
+}
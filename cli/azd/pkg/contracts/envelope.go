@@ -9,6 +9,7 @@ type EventDataType string
 
 const (
 	ConsoleMessageEventDataType EventDataType = "consoleMessage"
+	ProgressEventDataType       EventDataType = "progress"
 )
 
 type EventEnvelope struct {
@@ -0,0 +1,25 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package contracts
+
+// InfraDiff is the contract for the output of `azd infra diff`.
+type InfraDiff struct {
+	// Drift is true when provisioning would change at least one resource in any layer.
+	Drift  bool             `json:"drift"`
+	Layers []InfraDiffLayer `json:"layers"`
+}
+
+// InfraDiffLayer lists the resources that provisioning a layer would change.
+type InfraDiffLayer struct {
+	// Name is the name of the layer, or empty for the infrastructure of a project without layers.
+	Name    string            `json:"name,omitempty"`
+	Changes []InfraDiffChange `json:"changes"`
+}
+
+// InfraDiffChange is a change that provisioning would make to a resource.
+type InfraDiffChange struct {
+	ChangeType   string `json:"changeType"`
+	ResourceType string `json:"resourceType"`
+	Name         string `json:"name"`
+}
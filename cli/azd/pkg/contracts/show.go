@@ -2,7 +2,10 @@
 // Licensed under the MIT License.
 package contracts
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
 
 // ShowType are the values for the language property of a ShowServiceProject
 type ShowType string
@@ -33,6 +36,8 @@ type ShowService struct {
 	// it is emitted under both "ingresUrl" (back-compat) and "ingressUrl"
 	// (correctly spelled, preferred). Only this field needs to be set.
 	IngresUrl string `json:"-"`
+	// Status is the current state of the resource of the service, queried with `azd show --live`.
+	Status *ShowServiceStatus `json:"status,omitempty"`
 }
 
 // MarshalJSON implements json.Marshaler for ShowService.
@@ -45,12 +50,14 @@ func (s ShowService) MarshalJSON() ([]byte, error) {
 		Target     *ShowTargetArm     `json:"target,omitempty"`
 		IngresUrl  string             `json:"ingresUrl,omitempty"`
 		IngressUrl string             `json:"ingressUrl,omitempty"`
+		Status     *ShowServiceStatus `json:"status,omitempty"`
 	}
 	return json.Marshal(alias{
 		Project:    s.Project,
 		Target:     s.Target,
 		IngresUrl:  s.IngresUrl,
 		IngressUrl: s.IngresUrl,
+		Status:     s.Status,
 	})
 }
 
@@ -68,3 +75,29 @@ type ShowServiceProject struct {
 type ShowTargetArm struct {
 	ResourceIds []string `json:"resourceIds"`
 }
+
+// ShowServiceState is the state of the resource of a service, as returned by `azd show --live`.
+type ShowServiceState string
+
+const (
+	ShowServiceStateRunning ShowServiceState = "running"
+	ShowServiceStateStopped ShowServiceState = "stopped"
+	// ShowServiceStateUnknown is the state of resources whose state azd can't query.
+	ShowServiceStateUnknown ShowServiceState = "unknown"
+)
+
+// ShowServiceStatus is the contract for the current state of the resource of a service, as returned by
+// `azd show --live`.
+type ShowServiceStatus struct {
+	// State is the state of the resource, such as "running" or "stopped". Other states reported by Azure, such as
+	// "progressing", are passed through in lower case.
+	State ShowServiceState `json:"state"`
+	// Replicas is the number of running replicas or instances, when the resource reports it.
+	Replicas *int `json:"replicas,omitempty"`
+	// Endpoints are the endpoints of the service.
+	Endpoints []string `json:"endpoints,omitempty"`
+	// LastDeployed is the time of the last deployment of the resource, when the resource reports it.
+	LastDeployed *time.Time `json:"lastDeployed,omitempty"`
+	// Error is the reason the state couldn't be queried.
+	Error string `json:"error,omitempty"`
+}
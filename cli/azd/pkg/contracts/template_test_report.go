@@ -0,0 +1,33 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package contracts
+
+// TemplateTestReport is the contract for the report of `azd template test`.
+type TemplateTestReport struct {
+	// Environment is the name of the environment the template was tested in.
+	Environment  string `json:"environment"`
+	Subscription string `json:"subscription,omitempty"`
+	Location     string `json:"location,omitempty"`
+	// Passed is whether every step and smoke test passed, including the teardown.
+	Passed          bool                   `json:"passed"`
+	DurationSeconds float64                `json:"durationSeconds"`
+	Steps           []TemplateTestResult   `json:"steps"`
+	SmokeTests      []TemplateTestResult   `json:"smokeTests"`
+	Resources       []TemplateTestResource `json:"resources"`
+}
+
+// TemplateTestResult is the contract for an entry in the "steps" and "smokeTests" arrays.
+type TemplateTestResult struct {
+	Name            string  `json:"name"`
+	Passed          bool    `json:"passed"`
+	DurationSeconds float64 `json:"durationSeconds"`
+	Error           string  `json:"error,omitempty"`
+}
+
+// TemplateTestResource is the contract for an entry in the "resources" array, which counts the resources of each type
+// that were deployed. It's a snapshot of what the template costs to run, captured before the teardown.
+type TemplateTestResource struct {
+	Type  string `json:"type"`
+	Count int    `json:"count"`
+}
@@ -0,0 +1,91 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cosign
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+)
+
+// Cli is a wrapper around the Cosign CLI
+type Cli struct {
+	commandRunner exec.CommandRunner
+}
+
+// NewCli creates a new instance of the Cosign CLI wrapper
+func NewCli(commandRunner exec.CommandRunner) *Cli {
+	return &Cli{
+		commandRunner: commandRunner,
+	}
+}
+
+// Gets the name of the Tool
+func (cli *Cli) Name() string {
+	return "Cosign"
+}
+
+// Returns the installation URL to install the Cosign CLI
+func (cli *Cli) InstallUrl() string {
+	return "https://docs.sigstore.dev/cosign/system_config/installation/"
+}
+
+// Checks whether or not the Cosign CLI is installed and available within the PATH
+func (cli *Cli) CheckInstalled(ctx context.Context) error {
+	return cli.commandRunner.ToolInPath("cosign")
+}
+
+// KeyVaultKeyUri converts the ID of a key of Azure Key Vault, such as 'https://<vault>.vault.azure.net/keys/<name>', to
+// the URI of the key for cosign. Cosign signs with the current version of the key, so IDs with a version are rejected.
+func KeyVaultKeyUri(keyId string) (string, error) {
+	parsed, err := url.Parse(keyId)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return "", fmt.Errorf("'%s' is not the ID of a Key Vault key", keyId)
+	}
+
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	switch {
+	case len(segments) == 2 && segments[0] == "keys":
+		return fmt.Sprintf("azurekms://%s/%s", parsed.Host, segments[1]), nil
+	case len(segments) == 3 && segments[0] == "keys":
+		return "", fmt.Errorf(
+			"cosign signs with the current version of the key, remove the version from the key ID '%s'", keyId)
+	default:
+		return "", fmt.Errorf("'%s' is not the ID of a Key Vault key", keyId)
+	}
+}
+
+// Sign signs the image with the key, for example a key of Azure Key Vault as 'azurekms://<vault host>/<name>'.
+func (cli *Cli) Sign(ctx context.Context, image string, key string) error {
+	runArgs := exec.NewRunArgs("cosign", "sign", "--key", key, "--yes", image)
+	if _, err := cli.commandRunner.Run(ctx, runArgs); err != nil {
+		return fmt.Errorf("signing image %s: %w", image, err)
+	}
+
+	return nil
+}
+
+// Verify verifies the signature of the image with the key.
+func (cli *Cli) Verify(ctx context.Context, image string, key string) error {
+	runArgs := exec.NewRunArgs("cosign", "verify", "--key", key, image)
+	if _, err := cli.commandRunner.Run(ctx, runArgs); err != nil {
+		return fmt.Errorf("verifying signature of image %s: %w", image, err)
+	}
+
+	return nil
+}
+
+// SignatureReference returns the reference of the signature of the image in the registry, as
+// '<repository>:sha256-<image digest>.sig'.
+func (cli *Cli) SignatureReference(ctx context.Context, image string) (string, error) {
+	res, err := cli.commandRunner.Run(ctx, exec.NewRunArgs("cosign", "triangulate", "--type", "signature", image))
+	if err != nil {
+		return "", fmt.Errorf("finding signature of image %s: %w", image, err)
+	}
+
+	return strings.TrimSpace(res.Stdout), nil
+}
@@ -0,0 +1,60 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cosign
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+const testImage = "contoso.azurecr.io/app/web@sha256:4f1c"
+
+func TestKeyVaultKeyUri(t *testing.T) {
+	uri, err := KeyVaultKeyUri("https://contoso.vault.azure.net/keys/signing")
+	require.NoError(t, err)
+	require.Equal(t, "azurekms://contoso.vault.azure.net/signing", uri)
+
+	_, err = KeyVaultKeyUri("https://contoso.vault.azure.net/keys/signing/0a1b2c")
+	require.ErrorContains(t, err, "remove the version from the key ID")
+
+	for _, keyId := range []string{
+		"signing",
+		"https://contoso.vault.azure.net/secrets/signing",
+		"azurekms://contoso/signing",
+	} {
+		_, err = KeyVaultKeyUri(keyId)
+		require.ErrorContains(t, err, "is not the ID of a Key Vault key", keyId)
+	}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	var commands [][]string
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "cosign"
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		commands = append(commands, args.Args)
+		if args.Args[0] == "triangulate" {
+			return exec.NewRunResult(0, "contoso.azurecr.io/app/web:sha256-4f1c.sig\n", ""), nil
+		}
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	cli := NewCli(mockContext.CommandRunner)
+	key := "azurekms://contoso.vault.azure.net/signing"
+	require.NoError(t, cli.Sign(*mockContext.Context, testImage, key))
+	require.NoError(t, cli.Verify(*mockContext.Context, testImage, key))
+	signature, err := cli.SignatureReference(*mockContext.Context, testImage)
+	require.NoError(t, err)
+	require.Equal(t, "contoso.azurecr.io/app/web:sha256-4f1c.sig", signature)
+
+	require.Equal(t, [][]string{
+		{"sign", "--key", key, "--yes", testImage},
+		{"verify", "--key", key, testImage},
+		{"triangulate", "--type", "signature", testImage},
+	}, commands)
+}
@@ -36,6 +36,7 @@ func Test_DevCenter_Client(t *testing.T) {
 		auth.ExternalAuthConfiguration{},
 		azCli,
 		"",
+		nil,
 	)
 	require.NoError(t, err)
 
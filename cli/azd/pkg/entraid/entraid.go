@@ -92,6 +92,34 @@ type EntraIdService interface {
 		clientId string,
 		federatedCredentials []*graphsdk.FederatedIdentityCredential,
 	) ([]*graphsdk.FederatedIdentityCredential, error)
+	GetApplication(
+		ctx context.Context,
+		subscriptionId string,
+		appIdOrName string,
+	) (*graphsdk.Application, error)
+	ListApplications(
+		ctx context.Context,
+		subscriptionId string,
+		displayNamePrefix string,
+	) ([]graphsdk.Application, error)
+	ListFederatedCredentials(
+		ctx context.Context,
+		subscriptionId string,
+		application *graphsdk.Application,
+	) ([]graphsdk.FederatedIdentityCredential, error)
+	UpdateFederatedCredential(
+		ctx context.Context,
+		subscriptionId string,
+		application *graphsdk.Application,
+		credential *graphsdk.FederatedIdentityCredential,
+	) error
+	DeleteFederatedCredential(
+		ctx context.Context,
+		subscriptionId string,
+		application *graphsdk.Application,
+		credentialId string,
+	) error
+	DeleteApplication(ctx context.Context, subscriptionId string, application *graphsdk.Application) error
 	CreateRbac(ctx context.Context, subscriptionId string, scope, roleId, principalId string) error
 	EnsureRoleAssignments(
 		ctx context.Context,
@@ -295,6 +323,130 @@ func (ad *entraIdService) ApplyFederatedCredentials(
 	return createdCredentials, nil
 }
 
+// GetApplication gets the application with the specified application (client) ID or display name
+func (ad *entraIdService) GetApplication(
+	ctx context.Context,
+	subscriptionId string,
+	appIdOrName string,
+) (*graphsdk.Application, error) {
+	return ad.getApplicationByNameOrId(ctx, subscriptionId, appIdOrName)
+}
+
+// ListApplications lists the applications whose display name starts with the specified prefix
+func (ad *entraIdService) ListApplications(
+	ctx context.Context,
+	subscriptionId string,
+	displayNamePrefix string,
+) ([]graphsdk.Application, error) {
+	graphClient, err := ad.getOrCreateGraphClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	matchingItems, err := graphClient.
+		Applications().
+		Filter(fmt.Sprintf("startswith(displayName, '%s')", displayNamePrefix)).
+		Top(999).
+		Get(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving application list: %w", err)
+	}
+
+	return matchingItems.Value, nil
+}
+
+// ListFederatedCredentials lists the federated identity credentials of the application
+func (ad *entraIdService) ListFederatedCredentials(
+	ctx context.Context,
+	subscriptionId string,
+	application *graphsdk.Application,
+) ([]graphsdk.FederatedIdentityCredential, error) {
+	graphClient, err := ad.getOrCreateGraphClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := graphClient.
+		ApplicationById(*application.Id).
+		FederatedIdentityCredentials().
+		Get(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving federated credentials of '%s': %w", application.DisplayName, err)
+	}
+
+	return response.Value, nil
+}
+
+// UpdateFederatedCredential updates the issuer, subject and audiences of an existing federated identity credential of
+// the application. The name of a federated identity credential can't be changed.
+func (ad *entraIdService) UpdateFederatedCredential(
+	ctx context.Context,
+	subscriptionId string,
+	application *graphsdk.Application,
+	credential *graphsdk.FederatedIdentityCredential,
+) error {
+	graphClient, err := ad.getOrCreateGraphClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	err = graphClient.
+		ApplicationById(*application.Id).
+		FederatedIdentityCredentialById(*credential.Id).
+		Update(ctx, credential)
+
+	if err != nil {
+		return fmt.Errorf("failed updating federated credential '%s' of '%s': %w",
+			credential.Name, application.DisplayName, err)
+	}
+
+	return nil
+}
+
+// DeleteFederatedCredential removes the federated identity credential from the application
+func (ad *entraIdService) DeleteFederatedCredential(
+	ctx context.Context,
+	subscriptionId string,
+	application *graphsdk.Application,
+	credentialId string,
+) error {
+	graphClient, err := ad.getOrCreateGraphClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	err = graphClient.
+		ApplicationById(*application.Id).
+		FederatedIdentityCredentialById(credentialId).
+		Delete(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed deleting federated credential from '%s': %w", application.DisplayName, err)
+	}
+
+	return nil
+}
+
+// DeleteApplication deletes the application. Microsoft Entra ID deletes the service principal of the application with it.
+func (ad *entraIdService) DeleteApplication(
+	ctx context.Context,
+	subscriptionId string,
+	application *graphsdk.Application,
+) error {
+	graphClient, err := ad.getOrCreateGraphClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	if err := graphClient.ApplicationById(*application.Id).Delete(ctx); err != nil {
+		return fmt.Errorf("failed deleting application '%s': %w", application.DisplayName, err)
+	}
+
+	return nil
+}
+
 func (ad *entraIdService) getApplicationByNameOrId(
 	ctx context.Context,
 	subscriptionId string,
@@ -0,0 +1,417 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk/appconfig"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+)
+
+const (
+	// appConfigValuesKey is the segment of the keys of the key-values that hold the `.env` values of an environment:
+	// <keyPrefix>values/<name>.
+	appConfigValuesKey = "values/"
+	// appConfigConfigKey is the segment of the key of the key-value that holds the config.json of an environment.
+	appConfigConfigKey = "config.json"
+	// keyVaultSecretNameMaxLength is the maximum length of the name of a Key Vault secret.
+	keyVaultSecretNameMaxLength = 127
+)
+
+// secretNameSeparators matches the runs of characters replaced with a dash in the names of Key Vault secrets, which only
+// allow alphanumeric characters and dashes.
+var secretNameSeparators = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// AppConfigDataStore stores environments in an Azure App Configuration store. Each environment is a label: its `.env`
+// values are the key-values under <keyPrefix>values/, and its config.json is the <keyPrefix>config.json key-value. The
+// secret values are stored in a Key Vault, and referenced from the App Configuration store with Key Vault references,
+// so they can be consumed by the applications and the tools that already read from the store.
+type AppConfigDataStore struct {
+	configManager config.Manager
+	client        appconfig.Client
+	storeConfig   *appconfig.StoreConfig
+	kvService     keyvault.KeyVaultService
+	cloud         *cloud.Cloud
+
+	// resolvedSecrets holds the values of the Key Vault references resolved or written by the store, keyed by the URI of
+	// the secret, so that unchanged secrets aren't written again on each save.
+	resolvedSecrets   map[string]string
+	resolvedSecretsMu sync.Mutex
+}
+
+func NewAppConfigDataStore(
+	configManager config.Manager,
+	client appconfig.Client,
+	storeConfig *appconfig.StoreConfig,
+	kvService keyvault.KeyVaultService,
+	cloud *cloud.Cloud,
+) RemoteDataStore {
+	return &AppConfigDataStore{
+		configManager:   configManager,
+		client:          client,
+		storeConfig:     storeConfig,
+		kvService:       kvService,
+		cloud:           cloud,
+		resolvedSecrets: map[string]string{},
+	}
+}
+
+// EnvPath returns the key filter and label of the key-values that hold the .env values of the given environment
+func (acd *AppConfigDataStore) EnvPath(env *Environment) string {
+	return fmt.Sprintf("%s*?label=%s", acd.valuesKeyPrefix(), env.name)
+}
+
+// ConfigPath returns the key and label of the key-value that holds the config.json of the given environment
+func (acd *AppConfigDataStore) ConfigPath(env *Environment) string {
+	return fmt.Sprintf("%s?label=%s", acd.configKey(), env.name)
+}
+
+func (acd *AppConfigDataStore) List(ctx context.Context) ([]*contracts.EnvListEnvironment, error) {
+	keyValues, err := acd.listKeyValues(ctx, appconfig.AnyLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	envMap := map[string]*contracts.EnvListEnvironment{}
+	for _, keyValue := range keyValues {
+		env, has := envMap[keyValue.Label]
+		if !has {
+			env = &contracts.EnvListEnvironment{
+				Name: keyValue.Label,
+			}
+			envMap[keyValue.Label] = env
+		}
+
+		envRef := &Environment{name: keyValue.Label}
+		if keyValue.Key == acd.configKey() {
+			env.ConfigPath = acd.ConfigPath(envRef)
+		} else {
+			env.DotEnvPath = acd.EnvPath(envRef)
+		}
+	}
+
+	envs := slices.Collect(maps.Values(envMap))
+	slices.SortFunc(envs, func(a, b *contracts.EnvListEnvironment) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return envs, nil
+}
+
+func (acd *AppConfigDataStore) Get(ctx context.Context, name string) (*Environment, error) {
+	keyValues, err := acd.listKeyValues(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keyValues) == 0 {
+		return nil, fmt.Errorf("'%s': %w", name, ErrNotFound)
+	}
+
+	env := &Environment{
+		name: name,
+	}
+
+	if err := acd.load(ctx, env, keyValues); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+func (acd *AppConfigDataStore) Reload(ctx context.Context, env *Environment) error {
+	keyValues, err := acd.listKeyValues(ctx, env.name)
+	if err != nil {
+		return err
+	}
+
+	return acd.load(ctx, env, keyValues)
+}
+
+// load replaces the values and the config of the environment with the ones of its key-values.
+func (acd *AppConfigDataStore) load(ctx context.Context, env *Environment, keyValues []*appconfig.KeyValue) error {
+	envMap := map[string]string{}
+	env.Config = config.NewEmptyConfig()
+
+	for _, keyValue := range keyValues {
+		switch {
+		case keyValue.Key == acd.configKey():
+			cfg, err := acd.configManager.Load(strings.NewReader(keyValue.Value))
+			if err != nil {
+				return fmt.Errorf("loading config: %w", err)
+			}
+
+			env.Config = cfg
+		case strings.HasPrefix(keyValue.Key, acd.valuesKeyPrefix()):
+			key := strings.TrimPrefix(keyValue.Key, acd.valuesKeyPrefix())
+			value := keyValue.Value
+
+			if keyValue.ContentType == appconfig.KeyVaultReferenceContentType {
+				secretValue, err := acd.resolveSecret(ctx, keyValue.Value)
+				if err != nil {
+					return fmt.Errorf("resolving the Key Vault reference of '%s': %w", key, err)
+				}
+
+				value = secretValue
+			}
+
+			envMap[key] = value
+		}
+	}
+
+	env.replaceState(envMap, make(map[string]struct{}))
+
+	if env.Name() != "" {
+		tracing.SetUsageAttributes(fields.StringHashed(fields.EnvNameKey, env.Name()))
+	}
+
+	return nil
+}
+
+func (acd *AppConfigDataStore) Save(ctx context.Context, env *Environment, options *SaveOptions) error {
+	existing, err := acd.listKeyValues(ctx, env.name)
+	if err != nil {
+		return err
+	}
+
+	existingMap := map[string]*appconfig.KeyValue{}
+	for _, keyValue := range existing {
+		existingMap[keyValue.Key] = keyValue
+	}
+
+	env.mu.RLock()
+	values := maps.Clone(env.dotenv)
+	env.mu.RUnlock()
+
+	for key, value := range values {
+		keyValue := &appconfig.KeyValue{
+			Key:   acd.valuesKeyPrefix() + key,
+			Label: env.name,
+			Value: value,
+		}
+
+		current := existingMap[keyValue.Key]
+		delete(existingMap, keyValue.Key)
+
+		// Values referenced from Key Vault stay secret, even when the environment doesn't record them as secrets.
+		isReference := current != nil && current.ContentType == appconfig.KeyVaultReferenceContentType
+		if isReference && acd.isResolvedSecret(current.Value, value) {
+			continue
+		}
+
+		if env.IsSecret(key) || isReference {
+			reference, err := acd.saveSecret(ctx, env, key, value)
+			if err != nil {
+				return err
+			}
+
+			keyValue.Value = reference
+			keyValue.ContentType = appconfig.KeyVaultReferenceContentType
+		}
+
+		if current != nil && current.Value == keyValue.Value && current.ContentType == keyValue.ContentType {
+			continue
+		}
+
+		if err := acd.client.Set(ctx, keyValue); err != nil {
+			return describeAppConfigError(err)
+		}
+	}
+
+	cfgWriter := new(bytes.Buffer)
+	if err := acd.configManager.Save(env.Config, cfgWriter); err != nil {
+		return fmt.Errorf("saving config: %w", err)
+	}
+
+	delete(existingMap, acd.configKey())
+	err = acd.client.Set(ctx, &appconfig.KeyValue{
+		Key:         acd.configKey(),
+		Label:       env.name,
+		Value:       cfgWriter.String(),
+		ContentType: "application/json",
+	})
+	if err != nil {
+		return describeAppConfigError(err)
+	}
+
+	// Remove the values deleted from the environment. The secrets they reference are kept in Key Vault, where they can
+	// be recovered.
+	for _, keyValue := range existingMap {
+		if err := acd.client.Delete(ctx, keyValue.Key, env.name); err != nil {
+			return describeAppConfigError(err)
+		}
+	}
+
+	tracing.SetUsageAttributes(fields.StringHashed(fields.EnvNameKey, env.Name()))
+	return nil
+}
+
+func (acd *AppConfigDataStore) Delete(ctx context.Context, name string) error {
+	keyValues, err := acd.listKeyValues(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if len(keyValues) == 0 {
+		return fmt.Errorf("'%s': %w", name, ErrNotFound)
+	}
+
+	for _, keyValue := range keyValues {
+		if err := acd.client.Delete(ctx, keyValue.Key, name); err != nil {
+			return describeAppConfigError(err)
+		}
+	}
+
+	return nil
+}
+
+// listKeyValues returns the key-values of the environments with the given label.
+func (acd *AppConfigDataStore) listKeyValues(ctx context.Context, label string) ([]*appconfig.KeyValue, error) {
+	keyValues, err := acd.client.List(ctx, acd.storeConfig.KeyPrefix, label)
+	if err != nil {
+		return nil, fmt.Errorf("listing key-values: %w", describeAppConfigError(err))
+	}
+
+	// Other keys may share the prefix, and unlabeled key-values don't belong to an environment.
+	return slices.DeleteFunc(keyValues, func(keyValue *appconfig.KeyValue) bool {
+		return keyValue.Label == "" ||
+			(keyValue.Key != acd.configKey() && !strings.HasPrefix(keyValue.Key, acd.valuesKeyPrefix()))
+	}), nil
+}
+
+func (acd *AppConfigDataStore) valuesKeyPrefix() string {
+	return acd.storeConfig.KeyPrefix + appConfigValuesKey
+}
+
+func (acd *AppConfigDataStore) configKey() string {
+	return acd.storeConfig.KeyPrefix + appConfigConfigKey
+}
+
+// saveSecret stores the secret value in the Key Vault of the configuration, and returns the value of the key-value
+// that references it.
+func (acd *AppConfigDataStore) saveSecret(ctx context.Context, env *Environment, key string, value string) (string, error) {
+	if acd.storeConfig.VaultName == "" {
+		return "", fmt.Errorf(
+			"the value of '%s' is a secret, but the remote state configuration has no 'vaultName' to store it in", key)
+	}
+
+	secretName := keyVaultSecretName(acd.storeConfig.KeyPrefix, env.name, key)
+	secretUri := fmt.Sprintf(
+		"https://%s.%s/secrets/%s", acd.storeConfig.VaultName, acd.cloud.KeyVaultEndpointSuffix, secretName)
+
+	if !acd.isResolvedSecretUri(secretUri, value) {
+		err := acd.kvService.CreateKeyVaultSecret(
+			ctx, acd.storeConfig.SubscriptionId, acd.storeConfig.VaultName, secretName, value)
+		if err != nil {
+			return "", fmt.Errorf("storing the secret value of '%s' in Key Vault: %w", key, err)
+		}
+
+		acd.resolvedSecretsMu.Lock()
+		acd.resolvedSecrets[secretUri] = value
+		acd.resolvedSecretsMu.Unlock()
+	}
+
+	reference, err := json.Marshal(appconfig.KeyVaultReference{Uri: secretUri})
+	if err != nil {
+		return "", err
+	}
+
+	return string(reference), nil
+}
+
+// resolveSecret returns the value of the secret of a Key Vault reference.
+func (acd *AppConfigDataStore) resolveSecret(ctx context.Context, reference string) (string, error) {
+	var ref appconfig.KeyVaultReference
+	if err := json.Unmarshal([]byte(reference), &ref); err != nil {
+		return "", fmt.Errorf("parsing Key Vault reference: %w", err)
+	}
+
+	secretUrl, err := url.Parse(ref.Uri)
+	if err != nil {
+		return "", fmt.Errorf("parsing Key Vault reference: %w", err)
+	}
+
+	// The URI is https://<vault>.<suffix>/secrets/<name>, optionally followed by the version of the secret.
+	segments := strings.Split(strings.Trim(secretUrl.Path, "/"), "/")
+	if secretUrl.Host == "" || len(segments) < 2 || segments[0] != "secrets" {
+		return "", fmt.Errorf("'%s' is not the URI of a Key Vault secret", ref.Uri)
+	}
+
+	vaultUrl := fmt.Sprintf("https://%s", secretUrl.Host)
+	secret, err := acd.kvService.GetKeyVaultSecret(ctx, acd.storeConfig.SubscriptionId, vaultUrl, segments[1])
+	if err != nil {
+		return "", err
+	}
+
+	acd.resolvedSecretsMu.Lock()
+	acd.resolvedSecrets[ref.Uri] = secret.Value
+	acd.resolvedSecretsMu.Unlock()
+
+	return secret.Value, nil
+}
+
+// isResolvedSecret reports whether the Key Vault reference was resolved to value.
+func (acd *AppConfigDataStore) isResolvedSecret(reference string, value string) bool {
+	var ref appconfig.KeyVaultReference
+	if err := json.Unmarshal([]byte(reference), &ref); err != nil {
+		return false
+	}
+
+	return acd.isResolvedSecretUri(ref.Uri, value)
+}
+
+func (acd *AppConfigDataStore) isResolvedSecretUri(secretUri string, value string) bool {
+	acd.resolvedSecretsMu.Lock()
+	defer acd.resolvedSecretsMu.Unlock()
+
+	resolved, has := acd.resolvedSecrets[secretUri]
+	return has && resolved == value
+}
+
+// keyVaultSecretName returns the name of the Key Vault secret for the value of key in the environment. Names only
+// allow alphanumeric characters and dashes, so a hash of the original names keeps the names of different values
+// unique.
+func keyVaultSecretName(keyPrefix string, envName string, key string) string {
+	hash := sha256.Sum256([]byte(keyPrefix + "\n" + envName + "\n" + key))
+	suffix := hex.EncodeToString(hash[:4])
+
+	name := strings.Trim(secretNameSeparators.ReplaceAllString(keyPrefix+"-"+envName+"-"+key, "-"), "-")
+	if maxLength := keyVaultSecretNameMaxLength - len(suffix) - 1; len(name) > maxLength {
+		name = name[:maxLength]
+	}
+
+	return name + "-" + suffix
+}
+
+func describeAppConfigError(err error) error {
+	if responseErr, ok := errors.AsType[*azcore.ResponseError](err); ok {
+		switch responseErr.StatusCode {
+		case http.StatusForbidden:
+			errorMsg := "Ensure your Azure account has the `App Configuration Data Owner` role on the store."
+			return fmt.Errorf("access denied connecting to the App Configuration store. %s %w", errorMsg, err)
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,191 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk/appconfig"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAppConfigClient stores key-values in memory, keyed by label and key.
+type fakeAppConfigClient struct {
+	keyValues map[string]map[string]*appconfig.KeyValue
+	sets      int
+}
+
+func (c *fakeAppConfigClient) List(ctx context.Context, keyPrefix string, label string) ([]*appconfig.KeyValue, error) {
+	result := []*appconfig.KeyValue{}
+	for keyValueLabel, keyValues := range c.keyValues {
+		if label != appconfig.AnyLabel && label != keyValueLabel {
+			continue
+		}
+
+		for key, keyValue := range keyValues {
+			if strings.HasPrefix(key, keyPrefix) {
+				clone := *keyValue
+				result = append(result, &clone)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (c *fakeAppConfigClient) Set(ctx context.Context, keyValue *appconfig.KeyValue) error {
+	if c.keyValues[keyValue.Label] == nil {
+		c.keyValues[keyValue.Label] = map[string]*appconfig.KeyValue{}
+	}
+
+	clone := *keyValue
+	c.keyValues[keyValue.Label][keyValue.Key] = &clone
+	c.sets++
+	return nil
+}
+
+func (c *fakeAppConfigClient) Delete(ctx context.Context, key string, label string) error {
+	delete(c.keyValues[label], key)
+	return nil
+}
+
+// fakeKeyVaultService stores secrets in memory, keyed by vault and name.
+type fakeKeyVaultService struct {
+	keyvault.KeyVaultService
+	secrets map[string]string
+	creates int
+}
+
+func (s *fakeKeyVaultService) CreateKeyVaultSecret(
+	ctx context.Context, subscriptionId string, vaultName string, secretName string, secretValue string) error {
+	s.secrets["https://"+vaultName+".vault.azure.net/"+secretName] = secretValue
+	s.creates++
+	return nil
+}
+
+func (s *fakeKeyVaultService) GetKeyVaultSecret(
+	ctx context.Context, subscriptionId string, vaultName string, secretName string) (*keyvault.Secret, error) {
+	value, has := s.secrets[vaultName+"/"+secretName]
+	if !has {
+		return nil, keyvault.ErrAzCliSecretNotFound
+	}
+
+	return &keyvault.Secret{Name: secretName, Value: value}, nil
+}
+
+func newTestAppConfigDataStore() (*AppConfigDataStore, *fakeAppConfigClient, *fakeKeyVaultService) {
+	client := &fakeAppConfigClient{keyValues: map[string]map[string]*appconfig.KeyValue{}}
+	kvService := &fakeKeyVaultService{secrets: map[string]string{}}
+	storeConfig := &appconfig.StoreConfig{StoreName: "store", VaultName: "vault", KeyPrefix: "app/"}
+	dataStore := NewAppConfigDataStore(config.NewManager(), client, storeConfig, kvService, cloud.AzurePublic())
+
+	return dataStore.(*AppConfigDataStore), client, kvService
+}
+
+func Test_AppConfigDataStore(t *testing.T) {
+	t.Run("SaveAndGet", func(t *testing.T) {
+		dataStore, client, kvService := newTestAppConfigDataStore()
+
+		env := New("dev")
+		env.DotenvSet("API_URL", "https://contoso.com")
+		env.DotenvSet("API_KEY", "secret")
+		require.NoError(t, env.MarkSecret("API_KEY"))
+		require.NoError(t, env.Config.Set("infra.parameters.size", "small"))
+		require.NoError(t, dataStore.Save(t.Context(), env, &SaveOptions{IsNew: true}))
+
+		keyValues := client.keyValues["dev"]
+		require.Equal(t, "https://contoso.com", keyValues["app/values/API_URL"].Value)
+		require.Equal(t, "dev", keyValues["app/values/AZURE_ENV_NAME"].Value)
+		require.Contains(t, keyValues, "app/config.json")
+
+		// The secret is stored in Key Vault, and referenced from App Configuration.
+		secretKeyValue := keyValues["app/values/API_KEY"]
+		require.Equal(t, appconfig.KeyVaultReferenceContentType, secretKeyValue.ContentType)
+		require.NotContains(t, secretKeyValue.Value, "secret\"")
+
+		var reference appconfig.KeyVaultReference
+		require.NoError(t, json.Unmarshal([]byte(secretKeyValue.Value), &reference))
+		secretName := keyVaultSecretName("app/", "dev", "API_KEY")
+		require.Equal(t, "https://vault.vault.azure.net/secrets/"+secretName, reference.Uri)
+		require.Equal(t, "secret", kvService.secrets[strings.Replace(reference.Uri, "/secrets/", "/", 1)])
+
+		// A new store resolves the reference.
+		otherStore := NewAppConfigDataStore(
+			dataStore.configManager, client, dataStore.storeConfig, kvService, cloud.AzurePublic())
+		loaded, err := otherStore.Get(t.Context(), "dev")
+		require.NoError(t, err)
+		require.Equal(t, "secret", loaded.Getenv("API_KEY"))
+		require.Equal(t, "https://contoso.com", loaded.Getenv("API_URL"))
+		require.True(t, loaded.IsSecret("API_KEY"))
+		size, _ := loaded.Config.GetString("infra.parameters.size")
+		require.Equal(t, "small", size)
+
+		// Saving unchanged values doesn't write them again.
+		sets, creates := client.sets, kvService.creates
+		require.NoError(t, otherStore.Save(t.Context(), loaded, nil))
+		require.Equal(t, sets+1, client.sets, "only config.json is written")
+		require.Equal(t, creates, kvService.creates)
+
+		// Changed secrets get a new version, and deleted values are removed.
+		loaded.DotenvSet("API_KEY", "rotated")
+		loaded.DotenvDelete("API_URL")
+		require.NoError(t, otherStore.Save(t.Context(), loaded, nil))
+		require.Equal(t, creates+1, kvService.creates)
+		require.NotContains(t, client.keyValues["dev"], "app/values/API_URL")
+
+		loaded, err = dataStore.Get(t.Context(), "dev")
+		require.NoError(t, err)
+		require.Equal(t, "rotated", loaded.Getenv("API_KEY"))
+	})
+
+	t.Run("List", func(t *testing.T) {
+		dataStore, client, _ := newTestAppConfigDataStore()
+		require.NoError(t, dataStore.Save(t.Context(), New("prod"), nil))
+		require.NoError(t, dataStore.Save(t.Context(), New("dev"), nil))
+		// Key-values of other prefixes, or without a label, aren't environments.
+		client.keyValues["other"] = map[string]*appconfig.KeyValue{"app2/values/A": {Key: "app2/values/A"}}
+		client.keyValues[""] = map[string]*appconfig.KeyValue{"app/values/A": {Key: "app/values/A"}}
+
+		envs, err := dataStore.List(t.Context())
+		require.NoError(t, err)
+		require.Len(t, envs, 2)
+		require.Equal(t, "dev", envs[0].Name)
+		require.Equal(t, "app/values/*?label=dev", envs[0].DotEnvPath)
+		require.Equal(t, "app/config.json?label=dev", envs[0].ConfigPath)
+		require.Equal(t, "prod", envs[1].Name)
+
+		require.NoError(t, dataStore.Delete(t.Context(), "prod"))
+		_, err = dataStore.Get(t.Context(), "prod")
+		require.ErrorIs(t, err, ErrNotFound)
+		require.ErrorIs(t, dataStore.Delete(t.Context(), "prod"), ErrNotFound)
+	})
+
+	t.Run("SecretWithoutVault", func(t *testing.T) {
+		dataStore, _, _ := newTestAppConfigDataStore()
+		dataStore.storeConfig.VaultName = ""
+
+		env := New("dev")
+		env.DotenvSet("API_KEY", "secret")
+		require.NoError(t, env.MarkSecret("API_KEY"))
+		err := dataStore.Save(t.Context(), env, nil)
+		require.ErrorContains(t, err, "vaultName")
+	})
+}
+
+func Test_KeyVaultSecretName(t *testing.T) {
+	name := keyVaultSecretName("my_app/", "dev.(1)", "API_KEY")
+	require.Regexp(t, `^my-app-dev-1-API-KEY-[0-9a-f]{8}$`, name)
+
+	// Names that only differ by invalid characters stay unique.
+	require.NotEqual(t, name, keyVaultSecretName("my_app/", "dev-1", "API_KEY"))
+
+	long := keyVaultSecretName("app/", strings.Repeat("e", 64), strings.Repeat("K", 100))
+	require.Len(t, long, keyVaultSecretNameMaxLength)
+}
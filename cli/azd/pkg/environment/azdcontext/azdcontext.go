@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/internal/names"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
@@ -129,6 +130,54 @@ func (c *AzdContext) ClearCopilotSession() error {
 	return c.SetCopilotSession(nil)
 }
 
+// environmentLastUsedPrecision is how often the last use of an environment is recorded, to avoid writing the config file
+// on every command.
+const environmentLastUsedPrecision = time.Minute
+
+// SetEnvironmentLastUsed records when the environment with the given name was last used by a command. A zero time
+// removes the record, for example when the environment is deleted.
+func (c *AzdContext) SetEnvironmentLastUsed(name string, lastUsed time.Time) error {
+	config := c.readConfig()
+	recorded, has := config.EnvironmentsLastUsed[name]
+
+	if lastUsed.IsZero() {
+		if !has {
+			return nil
+		}
+
+		delete(config.EnvironmentsLastUsed, name)
+	} else {
+		if previous, err := time.Parse(time.RFC3339, recorded); err == nil &&
+			lastUsed.Sub(previous).Abs() < environmentLastUsedPrecision {
+			return nil
+		}
+
+		if config.EnvironmentsLastUsed == nil {
+			config.EnvironmentsLastUsed = map[string]string{}
+		}
+
+		config.EnvironmentsLastUsed[name] = lastUsed.UTC().Format(time.RFC3339)
+	}
+
+	config.Version = ConfigFileVersion
+	return writeConfig(filepath.Join(c.EnvironmentDirectory(), ConfigFileName), config)
+}
+
+// EnvironmentsLastUsed returns when each environment was last used by a command, by name. Environments that were never
+// used since they were recorded aren't included.
+func (c *AzdContext) EnvironmentsLastUsed() map[string]time.Time {
+	config := c.readConfig()
+	lastUsed := make(map[string]time.Time, len(config.EnvironmentsLastUsed))
+
+	for name, value := range config.EnvironmentsLastUsed {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			lastUsed[name] = t
+		}
+	}
+
+	return lastUsed
+}
+
 // readConfig reads the current config file, returning an empty config if it doesn't exist.
 func (c *AzdContext) readConfig() configFile {
 	path := filepath.Join(c.EnvironmentDirectory(), ConfigFileName)
@@ -221,6 +270,8 @@ type configFile struct {
 	Version            int             `json:"version"`
 	DefaultEnvironment string          `json:"defaultEnvironment,omitempty"`
 	CopilotSession     *CopilotSession `json:"copilotSession,omitempty"`
+	// EnvironmentsLastUsed holds when each environment was last used by a command, by name, in RFC3339 format.
+	EnvironmentsLastUsed map[string]string `json:"environmentsLastUsed,omitempty"`
 }
 
 // CopilotSession tracks an in-progress Copilot agent session for resume support.
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -213,3 +214,28 @@ func TestProjectName(t *testing.T) {
 		})
 	}
 }
+
+func TestEnvironmentLastUsed(t *testing.T) {
+	azdCtx := NewAzdContextWithDirectory(t.TempDir())
+	require.NoError(t, azdCtx.SetProjectState(ProjectState{DefaultEnvironment: "dev"}))
+	require.Empty(t, azdCtx.EnvironmentsLastUsed())
+
+	lastUsed := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, azdCtx.SetEnvironmentLastUsed("dev", lastUsed))
+	// Uses within the precision of the record don't update it.
+	require.NoError(t, azdCtx.SetEnvironmentLastUsed("dev", lastUsed.Add(30*time.Second)))
+	require.NoError(t, azdCtx.SetEnvironmentLastUsed("prod", lastUsed.Add(time.Hour)))
+
+	recorded := azdCtx.EnvironmentsLastUsed()
+	require.Len(t, recorded, 2)
+	require.True(t, lastUsed.Equal(recorded["dev"]))
+	require.True(t, lastUsed.Add(time.Hour).Equal(recorded["prod"]))
+
+	// The default environment is kept.
+	defaultEnv, err := azdCtx.GetDefaultEnvironmentName()
+	require.NoError(t, err)
+	require.Equal(t, "dev", defaultEnv)
+
+	require.NoError(t, azdCtx.SetEnvironmentLastUsed("prod", time.Time{}))
+	require.NotContains(t, azdCtx.EnvironmentsLastUsed(), "prod")
+}
@@ -15,10 +15,12 @@ type RemoteKind string
 
 const (
 	RemoteKindAzureBlobStorage RemoteKind = "AzureBlobStorage"
+	RemoteKindAzureAppConfig   RemoteKind = "AppConfig"
 )
 
 var ValidRemoteKinds = []string{
 	string(RemoteKindAzureBlobStorage),
+	string(RemoteKindAzureAppConfig),
 }
 
 // SaveOptions provide additional metadata for the save operation
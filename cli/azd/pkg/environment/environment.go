@@ -75,11 +75,15 @@ type Environment struct {
 	// dotenv is a map of keys to values, persisted to the `.env` file stored in this environment's [Root].
 	dotenv map[string]string
 
+	// common is a map of keys to values shared by all the environments of the project, loaded from the
+	// [CommonDotEnvFileName] file. Values in dotenv take precedence, and common values are never persisted to `.env`.
+	common map[string]string
+
 	// deletedKeys keeps track of deleted keys from the `.env` to be reapplied before a merge operation
 	// happens in Save
 	deletedKeys map[string]struct{}
 
-	// mu guards dotenv and deletedKeys against concurrent access from multiple
+	// mu guards dotenv, common and deletedKeys against concurrent access from multiple
 	// goroutines (parallel service hooks, parallel deploy/publish, etc.).
 	mu sync.RWMutex
 
@@ -185,34 +189,67 @@ func CleanName(name string) string {
 	return result.String()
 }
 
-// Getenv behaves like os.Getenv, except that any keys in the `.env` file associated with this environment are considered
-// first.
+// Getenv behaves like os.Getenv, except that any keys in the `.env` file associated with this environment, and then in
+// the common values of the project, are considered first.
 func (e *Environment) Getenv(key string) string {
-	e.mu.RLock()
-	v, has := e.dotenv[key]
-	e.mu.RUnlock()
-	if has {
-		return v
-	}
-
-	return os.Getenv(key)
+	v, _ := e.LookupEnv(key)
+	return v
 }
 
-// LookupEnv behaves like os.LookupEnv, except that any keys in the `.env` file associated with this environment are
-// considered first.
+// LookupEnv behaves like os.LookupEnv, except that any keys in the `.env` file associated with this environment, and
+// then in the common values of the project, are considered first.
 func (e *Environment) LookupEnv(key string) (string, bool) {
-	e.mu.RLock()
-	v, has := e.dotenv[key]
-	e.mu.RUnlock()
-	if has {
+	if v, source := e.lookupDotenv(key); source != "" {
 		return v, true
 	}
 
 	return os.LookupEnv(key)
 }
 
+// ValueSource is the layer of the environment a value comes from.
+type ValueSource string
+
+const (
+	// ValueSourceEnvironment is the `.env` file of the environment.
+	ValueSourceEnvironment ValueSource = "environment"
+	// ValueSourceCommon is the [CommonDotEnvFileName] file shared by all the environments of the project.
+	ValueSourceCommon ValueSource = "common"
+)
+
+// DotenvSource returns the layer the value of key comes from, and whether the environment has a value for key. A
+// value of the `.env` file of the environment overrides the common value of the same key.
+func (e *Environment) DotenvSource(key string) (ValueSource, bool) {
+	_, source := e.lookupDotenv(key)
+	return source, source != ""
+}
+
+// CommonDotenv returns a copy of the common values of the project, including the values overridden by the `.env` file
+// of the environment.
+func (e *Environment) CommonDotenv() map[string]string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return maps.Clone(e.common)
+}
+
+// lookupDotenv returns the value of key from the `.env` file of the environment, or else from the common values, with
+// the layer it comes from. The source is empty when neither has a value for key.
+func (e *Environment) lookupDotenv(key string) (string, ValueSource) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if v, has := e.dotenv[key]; has {
+		return v, ValueSourceEnvironment
+	}
+
+	if v, has := e.common[key]; has {
+		return v, ValueSourceCommon
+	}
+
+	return "", ""
+}
+
 // DotenvDelete removes the given key from the .env file in the environment, it is a no-op if the key
-// does not exist. [Save] should be called to ensure this change is persisted.
+// does not exist. [Save] should be called to ensure this change is persisted. A common value of the key, if any, applies
+// once the key is removed.
 func (e *Environment) DotenvDelete(key string) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
@@ -220,7 +257,8 @@ func (e *Environment) DotenvDelete(key string) {
 	e.deletedKeys[key] = struct{}{}
 }
 
-// Dotenv returns a copy of the key value pairs from the .env file in the environment.
+// Dotenv returns a copy of the key value pairs from the .env file in the environment, merged over the common values of
+// the project.
 //
 // Dynamic linker/loader control variables (names in the reserved LD_/DYLD_ namespace, e.g.
 // LD_PRELOAD or DYLD_INSERT_LIBRARIES) are excluded, because these values flow into the
@@ -231,13 +269,15 @@ func (e *Environment) DotenvDelete(key string) {
 func (e *Environment) Dotenv() map[string]string {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
-	myDotenv := make(map[string]string, len(e.dotenv))
-	for k, v := range e.dotenv {
-		upper := strings.ToUpper(k)
-		if strings.HasPrefix(upper, "LD_") || strings.HasPrefix(upper, "DYLD_") {
-			continue
+	myDotenv := make(map[string]string, len(e.common)+len(e.dotenv))
+	for _, layer := range []map[string]string{e.common, e.dotenv} {
+		for k, v := range layer {
+			upper := strings.ToUpper(k)
+			if strings.HasPrefix(upper, "LD_") || strings.HasPrefix(upper, "DYLD_") {
+				continue
+			}
+			myDotenv[k] = v
 		}
-		myDotenv[k] = v
 	}
 	return myDotenv
 }
@@ -251,6 +291,13 @@ func (e *Environment) DotenvSet(key string, value string) {
 	delete(e.deletedKeys, key)
 }
 
+// setCommon replaces the common values of the project.
+func (e *Environment) setCommon(common map[string]string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.common = common
+}
+
 // replaceState atomically replaces the dotenv and deletedKeys maps. Used by
 // data stores during Reload so that concurrent readers (Getenv, Dotenv) never
 // observe a partially-loaded state and never race with the assignment itself.
@@ -374,14 +421,29 @@ func fixupUnquotedDotenv(values map[string]string, dotenv string) string {
 
 // Prepare dotenv for saving and returns a marshalled string that can be save to the underlying data store
 // Instead of calling `godotenv.Write` directly, we need to save the file ourselves, so we can fixup any numeric values
-// that were incorrectly unquoted.
-func marshallDotEnv(env *Environment) (string, error) {
+// that were incorrectly unquoted. When secrets isn't nil, the secret values are encrypted with it.
+func marshallDotEnv(env *Environment, secrets SecretCipher) (string, error) {
 	// Snapshot under read lock so a concurrent DotenvSet/Delete can't tear the
 	// map mid-iteration inside godotenv.Marshal (which range-iterates).
 	env.mu.RLock()
 	snapshot := maps.Clone(env.dotenv)
 	env.mu.RUnlock()
 
+	if secrets != nil {
+		for key, value := range snapshot {
+			if !env.IsSecret(key) || IsEncryptedValue(value) {
+				continue
+			}
+
+			encrypted, err := secrets.Encrypt(value)
+			if err != nil {
+				return "", fmt.Errorf("encrypting the value of %s: %w", key, err)
+			}
+
+			snapshot[key] = encrypted
+		}
+	}
+
 	marshalled, err := godotenv.Marshal(snapshot)
 	if err != nil {
 		return "", fmt.Errorf("marshalling .env: %w", err)
@@ -0,0 +1,67 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// expiresOnConfigKey is the environment config path of the time after which the environment is no longer needed, and
+// can be pruned with `azd env prune`.
+const expiresOnConfigKey = "expiresOn"
+
+// expiresOnDateLayout is the layout of expiry times given as a date, which expire at the start of that day in UTC.
+const expiresOnDateLayout = time.DateOnly
+
+// ParseExpiresOn parses the expiry time of an environment, either a date (2006-01-02), which expires at the start of that
+// day in UTC, or a time in RFC3339 format (2006-01-02T15:04:05Z07:00).
+func ParseExpiresOn(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	if t, err := time.Parse(expiresOnDateLayout, value); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf(
+		"invalid expiry time '%s', use a date like 2026-12-31 or a time like 2026-12-31T18:00:00Z", value)
+}
+
+// ExpiresOn returns when the environment expires, if it has an expiry time.
+func (e *Environment) ExpiresOn() (time.Time, bool) {
+	value, has := e.Config.GetString(expiresOnConfigKey)
+	if !has || value == "" {
+		return time.Time{}, false
+	}
+
+	expiresOn, err := ParseExpiresOn(value)
+	if err != nil {
+		log.Printf("ignoring the expiry of environment '%s': %v", e.name, err)
+		return time.Time{}, false
+	}
+
+	return expiresOn, true
+}
+
+// SetExpiresOn sets when the environment expires. A zero time removes the expiry time. [Save] should be called to ensure
+// this change is persisted.
+func (e *Environment) SetExpiresOn(expiresOn time.Time) error {
+	if expiresOn.IsZero() {
+		return e.Config.Unset(expiresOnConfigKey)
+	}
+
+	return e.Config.Set(expiresOnConfigKey, expiresOn.UTC().Format(time.RFC3339))
+}
+
+// IsExpired returns whether the environment has an expiry time that is before now.
+func (e *Environment) IsExpired(now time.Time) bool {
+	expiresOn, has := e.ExpiresOn()
+	return has && !now.Before(expiresOn)
+}
@@ -0,0 +1,48 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExpiresOn(t *testing.T) {
+	expiresOn, err := ParseExpiresOn("2026-12-31")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), expiresOn)
+
+	expiresOn, err = ParseExpiresOn(" 2026-12-31T18:00:00+02:00 ")
+	require.NoError(t, err)
+	require.True(t, time.Date(2026, 12, 31, 16, 0, 0, 0, time.UTC).Equal(expiresOn))
+
+	_, err = ParseExpiresOn("next week")
+	require.ErrorContains(t, err, "invalid expiry time 'next week'")
+}
+
+func TestExpiresOn(t *testing.T) {
+	env := New("dev")
+	_, has := env.ExpiresOn()
+	require.False(t, has)
+	require.False(t, env.IsExpired(time.Now()))
+
+	expiresOn := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, env.SetExpiresOn(expiresOn))
+	value, has := env.Config.GetString(expiresOnConfigKey)
+	require.True(t, has)
+	require.Equal(t, "2026-12-31T00:00:00Z", value)
+
+	require.False(t, env.IsExpired(expiresOn.Add(-time.Second)))
+	require.True(t, env.IsExpired(expiresOn))
+
+	require.NoError(t, env.SetExpiresOn(time.Time{}))
+	_, has = env.ExpiresOn()
+	require.False(t, has)
+
+	// Invalid values, e.g. edited by hand, are ignored.
+	require.NoError(t, env.Config.Set(expiresOnConfigKey, "someday"))
+	require.False(t, env.IsExpired(time.Now()))
+}
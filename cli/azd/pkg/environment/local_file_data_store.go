@@ -30,13 +30,36 @@ import (
 type LocalFileDataStore struct {
 	azdContext    *azdcontext.AzdContext
 	configManager config.FileConfigManager
+
+	// secrets decrypts the encrypted values of the .env files, and encrypts the secret values when encryptSecrets is
+	// true.
+	secrets        SecretCipher
+	encryptSecrets bool
 }
 
-// NewLocalFileDataStore creates a new LocalFileDataStore instance
+// NewLocalFileDataStore creates a new LocalFileDataStore instance. Encrypted values are decrypted when environments are
+// loaded, but secret values are written unencrypted.
 func NewLocalFileDataStore(azdContext *azdcontext.AzdContext, configManager config.FileConfigManager) LocalDataStore {
 	return &LocalFileDataStore{
 		azdContext:    azdContext,
 		configManager: configManager,
+		secrets:       NewLocalSecretCipher(),
+	}
+}
+
+// NewLocalFileDataStoreWithSecretEncryption creates a new LocalFileDataStore instance that encrypts the secret values of
+// the environments with secrets before they are written to their .env file. Environments with unencrypted secret values
+// are migrated when they are loaded.
+func NewLocalFileDataStoreWithSecretEncryption(
+	azdContext *azdcontext.AzdContext,
+	configManager config.FileConfigManager,
+	secrets SecretCipher,
+) LocalDataStore {
+	return &LocalFileDataStore{
+		azdContext:     azdContext,
+		configManager:  configManager,
+		secrets:        secrets,
+		encryptSecrets: true,
 	}
 }
 
@@ -151,9 +174,57 @@ func (fs *LocalFileDataStore) Get(ctx context.Context, name string) (*Environmen
 		return nil, err
 	}
 
+	if fs.encryptSecrets && fs.hasUnencryptedSecrets(env) {
+		// Saving encrypts the secret values that were written before the encryption was turned on.
+		if err := fs.Save(ctx, env, nil); err != nil {
+			return nil, fmt.Errorf("encrypting secret values: %w", err)
+		}
+
+		log.Printf("encrypted the secret values of environment '%s'", name)
+	}
+
 	return env, nil
 }
 
+// hasUnencryptedSecrets reports whether the .env file of the environment has secret values that aren't encrypted.
+func (fs *LocalFileDataStore) hasUnencryptedSecrets(env *Environment) bool {
+	values, err := godotenv.Read(fs.EnvPath(env))
+	if err != nil {
+		return false
+	}
+
+	for key, value := range values {
+		if env.IsSecret(key) && !IsEncryptedValue(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CommonEnvPath returns the path to the file holding the values shared by all the environments of the project
+func (fs *LocalFileDataStore) CommonEnvPath() string {
+	return filepath.Join(fs.azdContext.EnvironmentDirectory(), CommonDotEnvFileName)
+}
+
+// readCommonDotenv reads the values shared by all the environments of the project. The name of an environment can't be
+// shared, so a common AZURE_ENV_NAME is ignored.
+func (fs *LocalFileDataStore) readCommonDotenv() (map[string]string, error) {
+	common, err := godotenv.Read(fs.CommonEnvPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("loading %s: %w", CommonDotEnvFileName, err)
+	}
+
+	if _, has := common[EnvNameEnvVarName]; has {
+		log.Printf("ignoring %s in %s", EnvNameEnvVarName, fs.CommonEnvPath())
+		delete(common, EnvNameEnvVarName)
+	}
+
+	return common, nil
+}
+
 // Reload reloads the environment from the persistent data store
 func (fs *LocalFileDataStore) Reload(ctx context.Context, env *Environment) error {
 	// Serialize against concurrent cross-process Save (e.g. parallel
@@ -180,8 +251,20 @@ func (fs *LocalFileDataStore) reloadLocked(ctx context.Context, env *Environment
 	} else {
 		newDotenv = envMap
 	}
+	if err := decryptValues(fs.secrets, newDotenv); err != nil {
+		return fmt.Errorf(
+			"loading .env: %w. Encrypted values can only be decrypted on the machine that encrypted them: remove them "+
+				"from %s, then set them again with 'azd env set --secret' or run 'azd provision'",
+			err, fs.EnvPath(env))
+	}
 	env.replaceState(newDotenv, make(map[string]struct{}))
 
+	common, err := fs.readCommonDotenv()
+	if err != nil {
+		return err
+	}
+	env.setCommon(common)
+
 	// Reload env config
 	if cfg, err := fs.configManager.Load(fs.ConfigPath(env)); errors.Is(err, os.ErrNotExist) {
 		env.Config = config.NewEmptyConfig()
@@ -254,7 +337,12 @@ func (fs *LocalFileDataStore) Save(ctx context.Context, env *Environment, option
 	}
 	env.mu.Unlock()
 
-	marshalled, err := marshallDotEnv(env)
+	var secrets SecretCipher
+	if fs.encryptSecrets {
+		secrets = fs.secrets
+	}
+
+	marshalled, err := marshallDotEnv(env, secrets)
 	if err != nil {
 		return fmt.Errorf("marshalling .env: %w", err)
 	}
@@ -6,13 +6,16 @@ package environment
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
 	"sync"
 	"testing"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/joho/godotenv"
 	"github.com/stretchr/testify/require"
 )
 
@@ -153,3 +156,55 @@ func Test_LocalFileDataStore_ConcurrentSave_NoLostUpdate(t *testing.T) {
 		}
 	}
 }
+
+func Test_LocalFileDataStore_CommonValues(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+	fileConfigManager := config.NewFileConfigManager(config.NewManager())
+	dataStore := NewLocalFileDataStore(azdContext, fileConfigManager)
+
+	env := New("env1")
+	env.DotenvSet("OVERRIDDEN", "env")
+	require.NoError(t, dataStore.Save(*mockContext.Context, env, nil))
+
+	commonPath := filepath.Join(azdContext.EnvironmentDirectory(), CommonDotEnvFileName)
+	err := os.WriteFile(
+		commonPath, []byte("SHARED=common\nOVERRIDDEN=common\nAZURE_ENV_NAME=shared\n"), osutil.PermissionFile)
+	require.NoError(t, err)
+
+	env, err = dataStore.Get(*mockContext.Context, "env1")
+	require.NoError(t, err)
+
+	require.Equal(t, "common", env.Getenv("SHARED"))
+	require.Equal(t, "env", env.Getenv("OVERRIDDEN"))
+	require.Equal(t, "env1", env.Getenv(EnvNameEnvVarName))
+	require.Equal(t, map[string]string{
+		"SHARED":          "common",
+		"OVERRIDDEN":      "env",
+		EnvNameEnvVarName: "env1",
+	}, env.Dotenv())
+
+	source, has := env.DotenvSource("SHARED")
+	require.True(t, has)
+	require.Equal(t, ValueSourceCommon, source)
+	source, _ = env.DotenvSource("OVERRIDDEN")
+	require.Equal(t, ValueSourceEnvironment, source)
+	_, has = env.DotenvSource("MISSING")
+	require.False(t, has)
+
+	// Common values are never written to the .env file of the environment.
+	env.DotenvSet("OWN", "value")
+	require.NoError(t, dataStore.Save(*mockContext.Context, env, nil))
+
+	dotenv, err := godotenv.Read(dataStore.EnvPath(env))
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"OVERRIDDEN":      "env",
+		"OWN":             "value",
+		EnvNameEnvVarName: "env1",
+	}, dotenv)
+
+	// Deleting the value of the environment exposes the common value.
+	env.DotenvDelete("OVERRIDDEN")
+	require.Equal(t, "common", env.Getenv("OVERRIDDEN"))
+}
@@ -12,6 +12,7 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
@@ -33,6 +34,14 @@ type Description struct {
 	HasRemote bool
 	// Specifies when the environment is the default environment
 	IsDefault bool
+	// The tags of the environment. Only set by `azd env list`, for environments that exist locally
+	Tags map[string]string
+	// When the environment was last used by a command on this machine, if known
+	LastUsed *time.Time
+	// When the environment expires, if it has an expiry time. Only set by `azd env list`
+	ExpiresOn *time.Time
+	// Specifies when the environment is past its expiry time. Only set by `azd env list`
+	Expired bool
 }
 
 // Spec is the specification for creating a new environment
@@ -47,6 +56,10 @@ type Spec struct {
 const DotEnvFileName = ".env"
 const ConfigFileName = "config.json"
 
+// CommonDotEnvFileName is the name of the file, in the environment directory of a project, holding the values shared by
+// all its environments. The `.env` file of an environment overrides the common values.
+const CommonDotEnvFileName = "common.env"
+
 var (
 	// Error returned when an environment with the specified name already exists
 	ErrExists = errors.New("environment already exists")
@@ -420,9 +433,13 @@ func (m *manager) List(ctx context.Context) ([]*Description, error) {
 
 	}
 
+	lastUsed := m.azdContext.EnvironmentsLastUsed()
 	allEnvs := []*Description{}
 	for _, env := range envMap {
 		env.IsDefault = env.Name == defaultEnvName
+		if t, has := lastUsed[env.Name]; has {
+			env.LastUsed = &t
+		}
 		allEnvs = append(allEnvs, env)
 	}
 
@@ -582,15 +599,25 @@ func (m *manager) Delete(ctx context.Context, name string) error {
 		}
 	}
 
+	if err := m.azdContext.SetEnvironmentLastUsed(name, time.Time{}); err != nil {
+		return fmt.Errorf("clearing last use of environment: %w", err)
+	}
+
 	return nil
 }
 
 // ensureValidEnvironmentName ensures the environment name is valid, if it is not, an error is printed
 // and the user is prompted for a new name.
-// In --no-prompt mode, when no name is provided, the name is auto-generated from the working directory basename.
+// In --no-prompt mode, when no name is provided, the name is taken from the answers file, or else auto-generated from
+// the working directory basename.
 func (m *manager) ensureValidEnvironmentName(ctx context.Context, spec *Spec) error {
-	// In --no-prompt mode with no name provided, generate from working directory
-	if spec.Name == "" && m.console.IsNoPromptMode() {
+	namePrompt := input.ConsoleOptions{
+		Key:     "environment",
+		Message: "Enter a unique environment name",
+	}
+
+	// In --no-prompt mode with no name provided or answered, generate from working directory
+	if spec.Name == "" && m.console.IsNoPromptMode() && !input.HasAnswer(m.console, namePrompt) {
 		// Prefer the project directory from azdContext, fall back to process working directory.
 		cwd := ""
 		if m.azdContext != nil {
@@ -643,7 +670,8 @@ func (m *manager) ensureValidEnvironmentName(ctx context.Context, spec *Spec) er
 
 	for !IsValidEnvironmentName(spec.Name) {
 		userInput, err := m.console.Prompt(ctx, input.ConsoleOptions{
-			Message: "Enter a unique environment name",
+			Key:     namePrompt.Key,
+			Message: namePrompt.Message,
 			Help: heredoc.Doc(`
 			A unique string that can be used to differentiate copies of your application in Azure.
 
@@ -659,6 +687,10 @@ func (m *manager) ensureValidEnvironmentName(ctx context.Context, spec *Spec) er
 
 		spec.Name = userInput
 
+		if !IsValidEnvironmentName(spec.Name) && m.console.IsNoPromptMode() {
+			return InvalidEnvironmentNameError(spec.Name)
+		}
+
 		if !IsValidEnvironmentName(spec.Name) {
 			m.console.Message(ctx, InvalidEnvironmentNameError(spec.Name).Error())
 		}
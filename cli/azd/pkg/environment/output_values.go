@@ -0,0 +1,191 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// outputsConfigKey is the environment config path under which the metadata of values written from provisioning outputs
+// is stored. The .env file can only hold strings, so the original type of each output is kept alongside it.
+const outputsConfigKey = "infra.outputs"
+
+// outputsSyncConfigKey is the environment config path under which azd records when each value was last written from a
+// provisioning output, to detect values that are out of date with a newer deployment.
+const outputsSyncConfigKey = "infra.outputsSync"
+
+// RedactedValue replaces the value of secure outputs when values are shown with redaction.
+const RedactedValue = "<redacted>"
+
+// Output value types, matching the provisioning parameter types.
+const (
+	OutputTypeString  = "string"
+	OutputTypeNumber  = "number"
+	OutputTypeBoolean = "bool"
+	OutputTypeObject  = "object"
+	OutputTypeArray   = "array"
+)
+
+// OutputMetadata describes a .env value that was written from a provisioning output.
+type OutputMetadata struct {
+	// Type is the type of the output (one of the OutputType* constants).
+	Type string
+	// Secure is true when the provider marked the output as sensitive.
+	Secure bool
+}
+
+// SetOutputMetadata records the type and sensitivity of the .env value for key. Plain string values carry no metadata,
+// so any previously recorded metadata is removed for them. [Save] should be called to ensure this change is persisted.
+func (e *Environment) SetOutputMetadata(key string, metadata OutputMetadata) error {
+	path := outputsConfigKey + "." + key
+	if (metadata.Type == "" || metadata.Type == OutputTypeString) && !metadata.Secure {
+		return e.Config.Unset(path)
+	}
+
+	value := map[string]any{"type": metadata.Type}
+	if metadata.Secure {
+		value["secure"] = true
+	}
+
+	return e.Config.Set(path, value)
+}
+
+// OutputMetadata returns the metadata recorded for the .env value for key, if any.
+func (e *Environment) OutputMetadata(key string) (OutputMetadata, bool) {
+	values, has := e.Config.GetMap(outputsConfigKey + "." + key)
+	if !has {
+		return OutputMetadata{}, false
+	}
+
+	var metadata OutputMetadata
+	metadata.Type, _ = values["type"].(string)
+	metadata.Secure, _ = values["secure"].(bool)
+
+	return metadata, true
+}
+
+// ClearOutputMetadata removes the metadata and the sync time recorded for the .env value for key.
+func (e *Environment) ClearOutputMetadata(key string) error {
+	if err := e.Config.Unset(outputsSyncConfigKey + "." + key); err != nil {
+		return err
+	}
+
+	return e.Config.Unset(outputsConfigKey + "." + key)
+}
+
+// OutputSync records when a .env value was last written from a provisioning output.
+type OutputSync struct {
+	// SyncedAt is the time the value was written.
+	SyncedAt time.Time
+	// DeployedAt is the time of the deployment the value came from. It's zero when the provisioning provider doesn't
+	// report it, for example when the value is written by `azd provision`.
+	DeployedAt time.Time
+}
+
+// Since returns the time after which a deployment is newer than the value: the time of the deployment the value came
+// from, or the time it was written when that isn't known.
+func (s OutputSync) Since() time.Time {
+	if !s.DeployedAt.IsZero() {
+		return s.DeployedAt
+	}
+
+	return s.SyncedAt
+}
+
+// SetOutputSync records when the .env value for key was written from a provisioning output. [Save] should be called to
+// ensure this change is persisted.
+func (e *Environment) SetOutputSync(key string, sync OutputSync) error {
+	value := map[string]any{"syncedAt": sync.SyncedAt.UTC().Format(time.RFC3339Nano)}
+	if !sync.DeployedAt.IsZero() {
+		value["deployedAt"] = sync.DeployedAt.UTC().Format(time.RFC3339Nano)
+	}
+
+	return e.Config.Set(outputsSyncConfigKey+"."+key, value)
+}
+
+// OutputSync returns when the .env value for key was last written from a provisioning output, if it was.
+func (e *Environment) OutputSync(key string) (OutputSync, bool) {
+	values, has := e.Config.GetMap(outputsSyncConfigKey + "." + key)
+	if !has {
+		return OutputSync{}, false
+	}
+
+	var sync OutputSync
+	var err error
+	syncedAt, _ := values["syncedAt"].(string)
+	if sync.SyncedAt, err = time.Parse(time.RFC3339, syncedAt); err != nil {
+		return OutputSync{}, false
+	}
+
+	if deployedAt, has := values["deployedAt"].(string); has {
+		if sync.DeployedAt, err = time.Parse(time.RFC3339, deployedAt); err != nil {
+			return OutputSync{}, false
+		}
+	}
+
+	return sync, true
+}
+
+// TypedDotenv returns the key value pairs from the .env file, with values that came from provisioning outputs converted
+// back to their original type (numbers, booleans, objects and arrays). Values that no longer parse as their recorded
+// type, for example after being changed with `azd env set`, are returned as strings. When redactSecure is true, the
+// values of secure outputs are replaced with [RedactedValue].
+func (e *Environment) TypedDotenv(redactSecure bool) map[string]any {
+	dotenv := e.Dotenv()
+	typed := make(map[string]any, len(dotenv))
+
+	for key, value := range dotenv {
+		metadata, has := e.OutputMetadata(key)
+		if !has {
+			typed[key] = value
+			continue
+		}
+
+		if metadata.Secure && redactSecure {
+			typed[key] = RedactedValue
+			continue
+		}
+
+		typed[key] = typedOutputValue(metadata.Type, value)
+	}
+
+	return typed
+}
+
+// typedOutputValue converts the string form of an output value back to the given type, returning the string unchanged
+// when it can't be converted.
+func typedOutputValue(outputType string, value string) any {
+	switch outputType {
+	case OutputTypeNumber:
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			return json.Number(value)
+		}
+	case OutputTypeBoolean:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case OutputTypeObject, OutputTypeArray:
+		decoder := json.NewDecoder(bytes.NewReader([]byte(value)))
+		decoder.UseNumber()
+
+		var v any
+		if err := decoder.Decode(&v); err == nil {
+			switch v.(type) {
+			case map[string]any:
+				if outputType == OutputTypeObject {
+					return v
+				}
+			case []any:
+				if outputType == OutputTypeArray {
+					return v
+				}
+			}
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,97 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputMetadata(t *testing.T) {
+	env := NewWithValues("test", nil)
+
+	require.NoError(t, env.SetOutputMetadata("COUNT", OutputMetadata{Type: OutputTypeNumber}))
+	metadata, has := env.OutputMetadata("COUNT")
+	require.True(t, has)
+	require.Equal(t, OutputMetadata{Type: OutputTypeNumber}, metadata)
+
+	// Plain strings don't need metadata, so setting one clears what was recorded before.
+	require.NoError(t, env.SetOutputMetadata("COUNT", OutputMetadata{Type: OutputTypeString}))
+	_, has = env.OutputMetadata("COUNT")
+	require.False(t, has)
+
+	require.NoError(t, env.SetOutputMetadata("TOKEN", OutputMetadata{Type: OutputTypeString, Secure: true}))
+	metadata, has = env.OutputMetadata("TOKEN")
+	require.True(t, has)
+	require.True(t, metadata.Secure)
+
+	require.NoError(t, env.ClearOutputMetadata("TOKEN"))
+	_, has = env.OutputMetadata("TOKEN")
+	require.False(t, has)
+}
+
+func TestOutputSync(t *testing.T) {
+	env := NewWithValues("test", nil)
+
+	_, has := env.OutputSync("API_URL")
+	require.False(t, has)
+
+	syncedAt := time.Date(2026, 3, 1, 10, 0, 0, 123456789, time.UTC)
+	require.NoError(t, env.SetOutputSync("API_URL", OutputSync{SyncedAt: syncedAt}))
+	sync, has := env.OutputSync("API_URL")
+	require.True(t, has)
+	require.True(t, syncedAt.Equal(sync.SyncedAt))
+	require.True(t, syncedAt.Equal(sync.Since()))
+
+	// The deployment time, when known, is the time the value is current since.
+	deployedAt := syncedAt.Add(-time.Hour)
+	require.NoError(t, env.SetOutputSync("API_URL", OutputSync{SyncedAt: syncedAt, DeployedAt: deployedAt}))
+	sync, has = env.OutputSync("API_URL")
+	require.True(t, has)
+	require.True(t, deployedAt.Equal(sync.Since()))
+
+	require.NoError(t, env.ClearOutputMetadata("API_URL"))
+	_, has = env.OutputSync("API_URL")
+	require.False(t, has)
+}
+
+func TestTypedDotenv(t *testing.T) {
+	env := NewWithValues("test", map[string]string{
+		"NAME":     "web",
+		"COUNT":    "1000000",
+		"ENABLED":  "true",
+		"SETTINGS": `{"tier":"basic","size":1}`,
+		"HOSTS":    `["a","b"]`,
+		"EDITED":   "not-a-number",
+		"TOKEN":    "secret",
+	})
+
+	metadata := map[string]OutputMetadata{
+		"COUNT":    {Type: OutputTypeNumber},
+		"ENABLED":  {Type: OutputTypeBoolean},
+		"SETTINGS": {Type: OutputTypeObject},
+		"HOSTS":    {Type: OutputTypeArray},
+		"EDITED":   {Type: OutputTypeNumber},
+		"TOKEN":    {Type: OutputTypeString, Secure: true},
+	}
+	for key, m := range metadata {
+		require.NoError(t, env.SetOutputMetadata(key, m))
+	}
+
+	typed := env.TypedDotenv(true)
+	require.Equal(t, map[string]any{
+		"NAME":     "web",
+		"COUNT":    json.Number("1000000"),
+		"ENABLED":  true,
+		"SETTINGS": map[string]any{"tier": "basic", "size": json.Number("1")},
+		"HOSTS":    []any{"a", "b"},
+		"EDITED":   "not-a-number",
+		"TOKEN":    RedactedValue,
+	}, typed)
+
+	require.Equal(t, "secret", env.TypedDotenv(false)["TOKEN"])
+}
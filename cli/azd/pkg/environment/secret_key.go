@@ -0,0 +1,111 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/gofrs/flock"
+)
+
+const (
+	// secretKeyService and secretKeyAccount identify the key that encrypts secret values in the keychain of the user.
+	secretKeyService = "azd-environment-secrets"
+	secretKeyAccount = "default"
+
+	// secretKeyFileName is the file, in the azd config directory, that holds the key when it isn't stored in a keychain.
+	secretKeyFileName = "env-secrets.key"
+)
+
+// secretKeyStore reads and writes the key that encrypts secret values. Read returns errSecretKeyNotFound when the key
+// doesn't exist.
+type secretKeyStore struct {
+	read  func() ([]byte, error)
+	write func(key []byte) error
+}
+
+// loadOrCreate returns the key of the store. When create is true and the store has no key, a new key is created. The
+// creation holds a file lock, so concurrent azd processes don't create different keys.
+func (s secretKeyStore) loadOrCreate(create bool) ([]byte, error) {
+	key, err := s.read()
+	if !errors.Is(err, errSecretKeyNotFound) || !create {
+		return key, err
+	}
+
+	configDir, err := config.GetUserConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	lock := flock.New(filepath.Join(configDir, secretKeyFileName+".lock"))
+	if err := lock.Lock(); err != nil {
+		return nil, fmt.Errorf("locking secret encryption key: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	// Another process may have created the key while this one waited for the lock.
+	if key, err := s.read(); !errors.Is(err, errSecretKeyNotFound) {
+		return key, err
+	}
+
+	key, err = newSecretKey()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.write(key); err != nil {
+		return nil, fmt.Errorf("storing secret encryption key: %w", err)
+	}
+
+	return key, nil
+}
+
+// secretKeyFilePath returns the path of the file that holds the key when it isn't stored in a keychain.
+func secretKeyFilePath() (string, error) {
+	configDir, err := config.GetUserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, secretKeyFileName), nil
+}
+
+// readSecretKeyFile returns the base64 decoded content of the key file, or errSecretKeyNotFound when it doesn't exist.
+func readSecretKeyFile() ([]byte, error) {
+	path, err := secretKeyFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	//nolint:gosec // G304: path is in the azd config directory
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errSecretKeyNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+
+	return data, nil
+}
+
+// writeSecretKeyFile writes data, base64 encoded, to the key file, which only the user can read.
+func writeSecretKeyFile(data []byte) error {
+	path, err := secretKeyFilePath()
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(data)), osutil.PermissionFileOwnerOnly)
+}
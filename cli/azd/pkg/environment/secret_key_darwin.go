@@ -0,0 +1,59 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+//go:build darwin
+
+package environment
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// securityItemNotFoundExitCode is the exit code of the security tool when the keychain has no matching item.
+const securityItemNotFoundExitCode = 44
+
+// loadSecretKey returns the key that encrypts secret values, stored as a generic password in the login keychain of the
+// user.
+func loadSecretKey(create bool) ([]byte, error) {
+	return secretKeyStore{
+		read: func() ([]byte, error) {
+			//nolint:gosec // G204: the arguments are constants
+			cmd := exec.Command(
+				"/usr/bin/security", "find-generic-password", "-s", secretKeyService, "-a", secretKeyAccount, "-w")
+			output, err := cmd.Output()
+			if exitErr, ok := errors.AsType[*exec.ExitError](err); ok &&
+				exitErr.ExitCode() == securityItemNotFoundExitCode {
+				return nil, errSecretKeyNotFound
+			} else if err != nil {
+				return nil, fmt.Errorf("reading keychain: %w", err)
+			}
+
+			return base64.StdEncoding.DecodeString(strings.TrimSpace(string(output)))
+		},
+		write: func(key []byte) error {
+			// The command is read from stdin, so the key never appears in the arguments of a process.
+			command := fmt.Sprintf(
+				"add-generic-password -s %s -a %s -l \"Azure Developer CLI environment secrets\" -w %s\n",
+				secretKeyService, secretKeyAccount, base64.StdEncoding.EncodeToString(key))
+			cmd := exec.Command("/usr/bin/security", "-i")
+			cmd.Stdin = strings.NewReader(command)
+			var stderr bytes.Buffer
+			cmd.Stderr = &stderr
+			if err := cmd.Run(); err != nil {
+				return fmt.Errorf("writing keychain: %w", err)
+			}
+
+			// In interactive mode, the security tool reports the commands that fail without failing itself.
+			if stderr.Len() > 0 {
+				return fmt.Errorf("writing keychain: %s", strings.TrimSpace(stderr.String()))
+			}
+
+			return nil
+		},
+	}.loadOrCreate(create)
+}
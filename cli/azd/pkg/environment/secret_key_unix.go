@@ -0,0 +1,68 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+//go:build unix && !darwin
+
+package environment
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"log"
+	"os/exec"
+	"strings"
+)
+
+// loadSecretKey returns the key that encrypts secret values, stored in the Secret Service keyring of the user (GNOME
+// Keyring, KWallet) through the secret-tool command. On machines without a keyring, such as containers and CI agents,
+// the key is stored in the azd config directory instead, in a file only the user can read.
+func loadSecretKey(create bool) ([]byte, error) {
+	return secretKeyStore{
+		read: func() ([]byte, error) {
+			// A key created without a keyring keeps being used once a keyring is available.
+			if key, err := readSecretKeyFile(); !errors.Is(err, errSecretKeyNotFound) {
+				return key, err
+			}
+
+			if _, err := exec.LookPath("secret-tool"); err != nil {
+				return nil, errSecretKeyNotFound
+			}
+
+			//nolint:gosec // G204: the arguments are constants
+			cmd := exec.Command("secret-tool", "lookup", "service", secretKeyService, "account", secretKeyAccount)
+			var stderr bytes.Buffer
+			cmd.Stderr = &stderr
+			output, err := cmd.Output()
+			// secret-tool fails without output when the keyring has no matching item.
+			if err != nil && stderr.Len() > 0 {
+				log.Printf(
+					"could not read the secret encryption key from the keyring: %s", strings.TrimSpace(stderr.String()))
+				return nil, errSecretKeyNotFound
+			} else if err != nil || len(output) == 0 {
+				return nil, errSecretKeyNotFound
+			}
+
+			return base64.StdEncoding.DecodeString(strings.TrimSpace(string(output)))
+		},
+		write: func(key []byte) error {
+			if _, err := exec.LookPath("secret-tool"); err == nil {
+				//nolint:gosec // G204: the arguments are constants
+				cmd := exec.Command("secret-tool", "store",
+					"--label=Azure Developer CLI environment secrets",
+					"service", secretKeyService, "account", secretKeyAccount)
+				// secret-tool reads the secret from stdin, so the key never appears in the arguments of a process.
+				cmd.Stdin = strings.NewReader(base64.StdEncoding.EncodeToString(key))
+				output, err := cmd.CombinedOutput()
+				if err == nil {
+					return nil
+				}
+
+				log.Printf("could not store the secret encryption key in the keyring, storing it in a file: %v: %s",
+					err, strings.TrimSpace(string(output)))
+			}
+
+			return writeSecretKeyFile(key)
+		},
+	}.loadOrCreate(create)
+}
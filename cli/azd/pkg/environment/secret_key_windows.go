@@ -0,0 +1,79 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+//go:build windows
+
+package environment
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// loadSecretKey returns the key that encrypts secret values. The key is stored in the azd config directory, protected
+// with CryptProtectData, so only the current user can decrypt it. See
+// https://learn.microsoft.com/windows/win32/api/dpapi/nf-dpapi-cryptprotectdata for more information.
+func loadSecretKey(create bool) ([]byte, error) {
+	return secretKeyStore{
+		read: func() ([]byte, error) {
+			protected, err := readSecretKeyFile()
+			if err != nil {
+				return nil, err
+			}
+
+			return unprotectData(protected)
+		},
+		write: func(key []byte) error {
+			protected, err := protectData(key)
+			if err != nil {
+				return err
+			}
+
+			return writeSecretKeyFile(protected)
+		},
+	}.loadOrCreate(create)
+}
+
+func protectData(data []byte) ([]byte, error) {
+	//nolint:gosec // G115: integer overflow conversion int -> uint32
+	plaintext := windows.DataBlob{Size: uint32(len(data)), Data: &data[0]}
+	var encrypted windows.DataBlob
+
+	if err := windows.CryptProtectData(&plaintext, nil, nil, uintptr(0), nil, 0, &encrypted); err != nil {
+		return nil, fmt.Errorf("failed to encrypt data: %w", err)
+	}
+
+	return copyAndFreeBlob(encrypted)
+}
+
+func unprotectData(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, errSecretKeyNotFound
+	}
+
+	//nolint:gosec // G115: integer overflow conversion int -> uint32
+	encrypted := windows.DataBlob{Size: uint32(len(data)), Data: &data[0]}
+	var plaintext windows.DataBlob
+
+	if err := windows.CryptUnprotectData(&encrypted, nil, nil, uintptr(0), nil, 0, &plaintext); err != nil {
+		return nil, fmt.Errorf("failed to decrypt data: %w", err)
+	}
+
+	return copyAndFreeBlob(plaintext)
+}
+
+// copyAndFreeBlob copies the data of a blob allocated by CryptProtectData or CryptUnprotectData, and frees it.
+func copyAndFreeBlob(blob windows.DataBlob) ([]byte, error) {
+	// #nosec G103
+	data := make([]byte, blob.Size)
+	copy(data, unsafe.Slice(blob.Data, blob.Size))
+
+	// #nosec G103
+	if _, err := windows.LocalFree(windows.Handle(unsafe.Pointer(blob.Data))); err != nil {
+		return nil, fmt.Errorf("failed to free data: %w", err)
+	}
+
+	return data, nil
+}
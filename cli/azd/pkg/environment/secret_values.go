@@ -0,0 +1,203 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+)
+
+// EncryptSecretsFeature encrypts the secret values of local environments in their `.env` file.
+var EncryptSecretsFeature = alpha.MustFeatureKey("env.encryptSecrets")
+
+// encryptedValuePrefix marks the values of the `.env` file that are encrypted. The version allows the format or the
+// algorithm to change later.
+const encryptedValuePrefix = "azdenc:v1:"
+
+// secretKeyLength is the length of the AES-256 key that encrypts secret values.
+const secretKeyLength = 32
+
+// IsEncryptedValue reports whether the `.env` value is encrypted by a [SecretCipher].
+func IsEncryptedValue(value string) bool {
+	return strings.HasPrefix(value, encryptedValuePrefix)
+}
+
+// IsSecret reports whether the value for key is a secret: a secure provisioning output, or a value set with
+// `azd env set --secret`.
+func (e *Environment) IsSecret(key string) bool {
+	metadata, has := e.OutputMetadata(key)
+	return has && metadata.Secure
+}
+
+// MarkSecret records that the value for key is a secret, keeping the type recorded for it, if any. [Save] should be
+// called to ensure this change is persisted.
+func (e *Environment) MarkSecret(key string) error {
+	metadata, _ := e.OutputMetadata(key)
+	if metadata.Type == "" {
+		metadata.Type = OutputTypeString
+	}
+
+	metadata.Secure = true
+	return e.SetOutputMetadata(key, metadata)
+}
+
+// SecretCipher encrypts the secret values of local environments before they are written to their `.env` file, and
+// decrypts them when the environment is loaded. Values are only decrypted with the key of the user that encrypted them,
+// on the same machine.
+type SecretCipher interface {
+	// Encrypt returns the encrypted form of value, as stored in the `.env` file.
+	Encrypt(value string) (string, error)
+	// Decrypt returns the value that was encrypted by Encrypt.
+	Decrypt(encrypted string) (string, error)
+}
+
+// NewLocalSecretCipher returns the SecretCipher that uses a key protected by the operating system: with DPAPI on
+// Windows, in the login keychain on macOS and in the Secret Service keyring on Linux. The key is created the first time
+// a value is encrypted, and only loaded when a value is encrypted or decrypted.
+func NewLocalSecretCipher() SecretCipher {
+	return newAesSecretCipher(loadSecretKey)
+}
+
+// aesSecretCipher encrypts values with AES-GCM, with a random nonce stored ahead of each encrypted value.
+type aesSecretCipher struct {
+	loadKey func(create bool) ([]byte, error)
+
+	mu   sync.Mutex
+	aead cipher.AEAD
+}
+
+// newAesSecretCipher returns an aesSecretCipher with the key returned by loadKey. When create is true, loadKey creates
+// the key if it doesn't exist yet, and otherwise returns errSecretKeyNotFound.
+func newAesSecretCipher(loadKey func(create bool) ([]byte, error)) *aesSecretCipher {
+	return &aesSecretCipher{loadKey: loadKey}
+}
+
+// errSecretKeyNotFound is returned when the key that encrypts secret values hasn't been created on this machine.
+var errSecretKeyNotFound = errors.New("secret encryption key not found")
+
+func (c *aesSecretCipher) Encrypt(value string) (string, error) {
+	aead, err := c.cipher(true)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generating nonce: %w", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(value), nil)
+	return encryptedValuePrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (c *aesSecretCipher) Decrypt(encrypted string) (string, error) {
+	if !IsEncryptedValue(encrypted) {
+		return "", errors.New("value is not encrypted")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(encrypted, encryptedValuePrefix))
+	if err != nil {
+		return "", fmt.Errorf("decoding encrypted value: %w", err)
+	}
+
+	aead, err := c.cipher(false)
+	if err != nil {
+		return "", err
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("encrypted value is too short")
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	value, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypting value: %w", err)
+	}
+
+	return string(value), nil
+}
+
+// cipher returns the AES-GCM cipher, loading the key the first time it's needed.
+func (c *aesSecretCipher) cipher(create bool) (cipher.AEAD, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.aead != nil {
+		return c.aead, nil
+	}
+
+	key, err := c.loadKey(create)
+	if err != nil {
+		return nil, fmt.Errorf("loading secret encryption key: %w", err)
+	}
+
+	if len(key) != secretKeyLength {
+		return nil, fmt.Errorf("secret encryption key has %d bytes, expected %d", len(key), secretKeyLength)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	c.aead = aead
+	return aead, nil
+}
+
+// newSecretKey returns a new random key to encrypt secret values.
+func newSecretKey() ([]byte, error) {
+	key := make([]byte, secretKeyLength)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generating secret encryption key: %w", err)
+	}
+
+	return key, nil
+}
+
+// ErrSecretNotDecrypted is returned when an encrypted value can't be decrypted, for example because the `.env` file
+// was copied from another machine.
+var ErrSecretNotDecrypted = errors.New("encrypted value can't be decrypted")
+
+// decryptValues decrypts the encrypted values in place. Values that can't be decrypted are never used encrypted, so an
+// error wrapping ErrSecretNotDecrypted and naming their keys is returned instead.
+func decryptValues(secrets SecretCipher, values map[string]string) error {
+	var failed []string
+	for key, value := range values {
+		if !IsEncryptedValue(value) {
+			continue
+		}
+
+		decrypted, err := secrets.Decrypt(value)
+		if err != nil {
+			log.Printf("could not decrypt the value of '%s': %v", key, err)
+			failed = append(failed, key)
+			continue
+		}
+
+		values[key] = decrypted
+	}
+
+	if len(failed) > 0 {
+		slices.Sort(failed)
+		return fmt.Errorf("%s: %w", strings.Join(failed, ", "), ErrSecretNotDecrypted)
+	}
+
+	return nil
+}
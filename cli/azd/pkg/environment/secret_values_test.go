@@ -0,0 +1,180 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSecretCipher(key []byte) *aesSecretCipher {
+	return newAesSecretCipher(func(create bool) ([]byte, error) {
+		return key, nil
+	})
+}
+
+func Test_AesSecretCipher(t *testing.T) {
+	cipher := newTestSecretCipher(bytes.Repeat([]byte{1}, secretKeyLength))
+
+	encrypted, err := cipher.Encrypt("P@ssw0rd")
+	require.NoError(t, err)
+	require.True(t, IsEncryptedValue(encrypted))
+	require.NotContains(t, encrypted, "P@ssw0rd")
+
+	// Each encryption uses a new nonce.
+	again, err := cipher.Encrypt("P@ssw0rd")
+	require.NoError(t, err)
+	require.NotEqual(t, encrypted, again)
+
+	decrypted, err := cipher.Decrypt(encrypted)
+	require.NoError(t, err)
+	require.Equal(t, "P@ssw0rd", decrypted)
+
+	_, err = newTestSecretCipher(bytes.Repeat([]byte{2}, secretKeyLength)).Decrypt(encrypted)
+	require.Error(t, err)
+
+	_, err = cipher.Decrypt("P@ssw0rd")
+	require.Error(t, err)
+}
+
+func Test_AesSecretCipher_CreatesKeyOnlyToEncrypt(t *testing.T) {
+	creates := []bool{}
+	cipher := newAesSecretCipher(func(create bool) ([]byte, error) {
+		creates = append(creates, create)
+		if !create {
+			return nil, errSecretKeyNotFound
+		}
+
+		return bytes.Repeat([]byte{1}, secretKeyLength), nil
+	})
+
+	_, err := cipher.Decrypt(encryptedValuePrefix + "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA")
+	require.ErrorIs(t, err, errSecretKeyNotFound)
+
+	_, err = cipher.Encrypt("value")
+	require.NoError(t, err)
+	require.Equal(t, []bool{false, true}, creates)
+}
+
+func Test_SecretKeyStore_LoadOrCreate(t *testing.T) {
+	t.Setenv("AZD_CONFIG_DIR", t.TempDir())
+
+	var stored []byte
+	store := secretKeyStore{
+		read: func() ([]byte, error) {
+			if stored == nil {
+				return nil, errSecretKeyNotFound
+			}
+
+			return stored, nil
+		},
+		write: func(key []byte) error {
+			stored = key
+			return nil
+		},
+	}
+
+	_, err := store.loadOrCreate(false)
+	require.ErrorIs(t, err, errSecretKeyNotFound)
+
+	key, err := store.loadOrCreate(true)
+	require.NoError(t, err)
+	require.Len(t, key, secretKeyLength)
+
+	again, err := store.loadOrCreate(true)
+	require.NoError(t, err)
+	require.Equal(t, key, again)
+}
+
+func Test_LocalFileDataStore_SecretEncryption(t *testing.T) {
+	cipher := newTestSecretCipher(bytes.Repeat([]byte{1}, secretKeyLength))
+	azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+	fileConfigManager := config.NewFileConfigManager(config.NewManager())
+
+	readDotenv := func(env *Environment) map[string]string {
+		values, err := godotenv.Read(filepath.Join(azdContext.EnvironmentRoot(env.Name()), DotEnvFileName))
+		require.NoError(t, err)
+		return values
+	}
+
+	t.Run("EncryptsSecretValues", func(t *testing.T) {
+		dataStore := NewLocalFileDataStoreWithSecretEncryption(azdContext, fileConfigManager, cipher)
+
+		env := New("encrypted")
+		env.DotenvSet("API_URL", "https://contoso.com")
+		env.DotenvSet("API_KEY", "secret")
+		require.NoError(t, env.MarkSecret("API_KEY"))
+		require.NoError(t, dataStore.Save(t.Context(), env, nil))
+
+		values := readDotenv(env)
+		require.Equal(t, "https://contoso.com", values["API_URL"])
+		require.True(t, IsEncryptedValue(values["API_KEY"]))
+
+		env, err := dataStore.Get(t.Context(), "encrypted")
+		require.NoError(t, err)
+		require.Equal(t, "secret", env.Getenv("API_KEY"))
+		require.Equal(t, "secret", env.Dotenv()["API_KEY"])
+
+		// Stores without encryption still decrypt the values, and write them back unencrypted.
+		plainStore := NewLocalFileDataStore(azdContext, fileConfigManager)
+		plainStore.(*LocalFileDataStore).secrets = cipher
+		env, err = plainStore.Get(t.Context(), "encrypted")
+		require.NoError(t, err)
+		require.Equal(t, "secret", env.Getenv("API_KEY"))
+
+		require.NoError(t, plainStore.Save(t.Context(), env, nil))
+		require.Equal(t, "secret", readDotenv(env)["API_KEY"])
+	})
+
+	t.Run("MigratesUnencryptedSecrets", func(t *testing.T) {
+		plainStore := NewLocalFileDataStore(azdContext, fileConfigManager)
+		env := New("migrated")
+		env.DotenvSet("API_KEY", "secret")
+		require.NoError(t, env.MarkSecret("API_KEY"))
+		require.NoError(t, plainStore.Save(t.Context(), env, nil))
+		require.Equal(t, "secret", readDotenv(env)["API_KEY"])
+
+		dataStore := NewLocalFileDataStoreWithSecretEncryption(azdContext, fileConfigManager, cipher)
+		env, err := dataStore.Get(t.Context(), "migrated")
+		require.NoError(t, err)
+		require.Equal(t, "secret", env.Getenv("API_KEY"))
+		require.True(t, IsEncryptedValue(readDotenv(env)["API_KEY"]))
+	})
+
+	t.Run("FailsOnValuesThatCannotBeDecrypted", func(t *testing.T) {
+		otherCipher := newTestSecretCipher(bytes.Repeat([]byte{2}, secretKeyLength))
+		encrypted, err := otherCipher.Encrypt("secret")
+		require.NoError(t, err)
+
+		env := New("copied")
+		require.NoError(t, os.MkdirAll(azdContext.EnvironmentRoot(env.Name()), osutil.PermissionDirectory))
+		require.NoError(t, godotenv.Write(
+			map[string]string{"API_KEY": encrypted}, filepath.Join(azdContext.EnvironmentRoot(env.Name()), DotEnvFileName)))
+
+		dataStore := NewLocalFileDataStoreWithSecretEncryption(azdContext, fileConfigManager, cipher)
+		_, err = dataStore.Get(t.Context(), "copied")
+		require.ErrorIs(t, err, ErrSecretNotDecrypted)
+		require.ErrorContains(t, err, "API_KEY")
+	})
+}
+
+func Test_MarkSecret_KeepsType(t *testing.T) {
+	env := New("test")
+	require.NoError(t, env.SetOutputMetadata("PORT", OutputMetadata{Type: OutputTypeNumber}))
+	require.NoError(t, env.MarkSecret("PORT"))
+
+	metadata, has := env.OutputMetadata("PORT")
+	require.True(t, has)
+	require.Equal(t, OutputMetadata{Type: OutputTypeNumber, Secure: true}, metadata)
+	require.True(t, env.IsSecret("PORT"))
+	require.False(t, env.IsSecret("OTHER"))
+}
@@ -0,0 +1,285 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// SnapshotDirectoryName is the name of the directory, in the root of an environment, that holds its snapshots.
+const SnapshotDirectoryName = "snapshots"
+
+// snapshotIdLayout is the layout of the creation time that a snapshot id starts with.
+const snapshotIdLayout = "20060102-150405"
+
+// ErrSnapshotNotFound is returned when a snapshot with a given id doesn't exist.
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+// Snapshot is a point in time copy of the values and configuration of an environment.
+type Snapshot struct {
+	Id          string    `json:"id"`
+	CreatedAt   time.Time `json:"createdAt"`
+	Description string    `json:"description,omitempty"`
+	// Values are the values from the .env file, without the common values of the project.
+	Values map[string]string `json:"values"`
+	// Config is the content of config.json, which includes the answers to infrastructure parameter prompts.
+	Config map[string]any `json:"config"`
+	// Artifacts are the identifiers of the artifacts deployed for each service, such as container images, keyed by the
+	// environment value that holds them. They're also part of Values, and are listed apart so they're easy to review.
+	Artifacts map[string]string `json:"artifacts,omitempty"`
+}
+
+// artifactProperties are the service properties that identify a deployed artifact.
+var artifactProperties = []string{"IMAGE_NAME", "GITOPS_COMMIT"}
+
+// SnapshotStore stores the snapshots of environments in .azure/<environment>/snapshots. Snapshots are always local,
+// even when the environment itself is stored remotely.
+type SnapshotStore struct {
+	azdContext *azdcontext.AzdContext
+	now        func() time.Time
+
+	// secrets decrypts the encrypted values of the snapshots, and encrypts the secret values when encryptSecrets is
+	// true.
+	secrets        SecretCipher
+	encryptSecrets bool
+}
+
+// NewSnapshotStore creates a new SnapshotStore. Encrypted values are decrypted when snapshots are loaded, but secret
+// values are written unencrypted.
+func NewSnapshotStore(azdContext *azdcontext.AzdContext) *SnapshotStore {
+	return &SnapshotStore{
+		azdContext: azdContext,
+		now:        time.Now,
+		secrets:    NewLocalSecretCipher(),
+	}
+}
+
+// NewSnapshotStoreWithSecretEncryption creates a new SnapshotStore that encrypts the secret values of the environments
+// before they are written to a snapshot, like [NewLocalFileDataStoreWithSecretEncryption] does for their .env file.
+func NewSnapshotStoreWithSecretEncryption(azdContext *azdcontext.AzdContext, secrets SecretCipher) *SnapshotStore {
+	return &SnapshotStore{
+		azdContext:     azdContext,
+		now:            time.Now,
+		secrets:        secrets,
+		encryptSecrets: true,
+	}
+}
+
+func (s *SnapshotStore) directory(envName string) string {
+	return filepath.Join(s.azdContext.EnvironmentRoot(envName), SnapshotDirectoryName)
+}
+
+// Create captures the current values and configuration of env in a new snapshot.
+func (s *SnapshotStore) Create(env *Environment, description string) (*Snapshot, error) {
+	// Common values are shared by all the environments of the project, so they aren't part of the snapshot.
+	values := environmentValues(env)
+
+	configJson, err := json.Marshal(env.Config.Raw())
+	if err != nil {
+		return nil, fmt.Errorf("marshalling config: %w", err)
+	}
+
+	var configValues map[string]any
+	if err := json.Unmarshal(configJson, &configValues); err != nil {
+		return nil, fmt.Errorf("copying config: %w", err)
+	}
+
+	artifacts := map[string]string{}
+	for key, value := range values {
+		if strings.HasPrefix(key, "SERVICE_") && slices.ContainsFunc(artifactProperties, func(property string) bool {
+			return strings.HasSuffix(key, "_"+property)
+		}) {
+			artifacts[key] = value
+		}
+	}
+
+	storedValues, err := s.storedValues(env, values)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := s.directory(env.Name())
+	if err := os.MkdirAll(dir, osutil.PermissionDirectoryOwnerOnly); err != nil {
+		return nil, fmt.Errorf("creating snapshot directory: %w", err)
+	}
+
+	createdAt := s.now().UTC().Truncate(time.Second)
+	snapshot := &Snapshot{
+		CreatedAt:   createdAt,
+		Description: description,
+		Values:      values,
+		Config:      configValues,
+		Artifacts:   artifacts,
+	}
+
+	// Several snapshots can be created in the same second, such as the one taken before a restore.
+	baseId := createdAt.Format(snapshotIdLayout)
+	for attempt := 1; ; attempt++ {
+		snapshot.Id = baseId
+		if attempt > 1 {
+			snapshot.Id = fmt.Sprintf("%s-%d", baseId, attempt)
+		}
+
+		stored := *snapshot
+		stored.Values = storedValues
+		content, err := json.MarshalIndent(&stored, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("marshalling snapshot: %w", err)
+		}
+
+		// The values can include secrets, so the file is only readable by the current user.
+		file, err := os.OpenFile(
+			filepath.Join(dir, snapshot.Id+".json"), os.O_CREATE|os.O_EXCL|os.O_WRONLY, osutil.PermissionFileOwnerOnly)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("creating snapshot: %w", err)
+		}
+
+		_, writeErr := file.Write(content)
+		if err := errors.Join(writeErr, file.Close()); err != nil {
+			return nil, fmt.Errorf("writing snapshot: %w", err)
+		}
+
+		return snapshot, nil
+	}
+}
+
+// List returns the snapshots of the environment, newest first.
+func (s *SnapshotStore) List(envName string) ([]*Snapshot, error) {
+	entries, err := os.ReadDir(s.directory(envName))
+	if errors.Is(err, os.ErrNotExist) {
+		return []*Snapshot{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("listing snapshots: %w", err)
+	}
+
+	snapshots := []*Snapshot{}
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if entry.IsDir() || !ok {
+			continue
+		}
+
+		snapshot, err := s.Get(envName, id)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	slices.SortFunc(snapshots, func(a, b *Snapshot) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+
+		return strings.Compare(b.Id, a.Id)
+	})
+
+	return snapshots, nil
+}
+
+// Get returns the snapshot of the environment with the given id. If it doesn't exist, ErrSnapshotNotFound is
+// returned.
+func (s *SnapshotStore) Get(envName string, id string) (*Snapshot, error) {
+	// Ids are file names, so they can't point outside of the snapshot directory.
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.HasPrefix(id, ".") {
+		return nil, fmt.Errorf("snapshot '%s': %w", id, ErrSnapshotNotFound)
+	}
+
+	content, err := os.ReadFile(filepath.Join(s.directory(envName), id+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("snapshot '%s': %w", id, ErrSnapshotNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("reading snapshot '%s': %w", id, err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(content, &snapshot); err != nil {
+		return nil, fmt.Errorf("parsing snapshot '%s': %w", id, err)
+	}
+	snapshot.Id = id
+	if err := decryptValues(s.secrets, snapshot.Values); err != nil {
+		return nil, fmt.Errorf("snapshot '%s': %w", id, err)
+	}
+
+	return &snapshot, nil
+}
+
+// storedValues returns the values as they are written to a snapshot: with the secret values encrypted when
+// encryptSecrets is true.
+func (s *SnapshotStore) storedValues(env *Environment, values map[string]string) (map[string]string, error) {
+	if !s.encryptSecrets {
+		return values, nil
+	}
+
+	stored := maps.Clone(values)
+	for key, value := range stored {
+		if !env.IsSecret(key) || IsEncryptedValue(value) {
+			continue
+		}
+
+		encrypted, err := s.secrets.Encrypt(value)
+		if err != nil {
+			return nil, fmt.Errorf("encrypting the value of %s: %w", key, err)
+		}
+
+		stored[key] = encrypted
+	}
+
+	return stored, nil
+}
+
+// Restore replaces the values and configuration of env with the ones in the snapshot. Values that were added after
+// the snapshot was created are removed. The common values of the project are left as they are. The caller is
+// responsible for saving the environment.
+func (s *Snapshot) Restore(env *Environment) {
+	for key := range environmentValues(env) {
+		if _, has := s.Values[key]; !has {
+			env.DotenvDelete(key)
+		}
+	}
+
+	common := env.CommonDotenv()
+	for _, key := range slices.Sorted(maps.Keys(s.Values)) {
+		// Older snapshots include the common values. Copying them to the .env file would hide later changes of
+		// common.env, so values that still come from it are skipped.
+		if source, _ := env.DotenvSource(key); source == ValueSourceCommon && common[key] == s.Values[key] {
+			continue
+		}
+
+		env.DotenvSet(key, s.Values[key])
+	}
+
+	configValues := s.Config
+	if configValues == nil {
+		configValues = map[string]any{}
+	}
+	env.Config = config.NewConfig(configValues)
+}
+
+// environmentValues returns the values of the .env file of env, without the common values of the project.
+func environmentValues(env *Environment) map[string]string {
+	values := env.Dotenv()
+	for key := range values {
+		if source, _ := env.DotenvSource(key); source != ValueSourceEnvironment {
+			delete(values, key)
+		}
+	}
+
+	return values
+}
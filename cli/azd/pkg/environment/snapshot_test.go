@@ -0,0 +1,242 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSnapshotStore(t *testing.T, now time.Time) *SnapshotStore {
+	store := NewSnapshotStore(azdcontext.NewAzdContextWithDirectory(t.TempDir()))
+	store.now = func() time.Time { return now }
+	return store
+}
+
+func Test_SnapshotStore_Create(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 600, time.UTC)
+	store := newTestSnapshotStore(t, now)
+
+	env := NewWithValues("dev", map[string]string{
+		"AZURE_LOCATION":                "westus2",
+		"SERVICE_API_IMAGE_NAME":        "registry.azurecr.io/api:azd-deploy-1",
+		"SERVICE_WEB_GITOPS_COMMIT":     "0123abc",
+		"SERVICE_WEB_ENDPOINT_URL":      "https://web.example.com",
+		"SERVICE_API_IMAGE_NAME_BACKUP": "not an artifact",
+		"CUSTOM_IMAGE_NAME":             "not an artifact",
+	})
+	env.Config = config.NewConfig(map[string]any{
+		"infra": map[string]any{"parameters": map[string]any{"sku": "S1"}},
+	})
+
+	snapshot, err := store.Create(env, "before upgrade")
+	require.NoError(t, err)
+
+	require.Equal(t, "20240102-030405", snapshot.Id)
+	require.Equal(t, now.Truncate(time.Second), snapshot.CreatedAt)
+	require.Equal(t, "before upgrade", snapshot.Description)
+	require.Equal(t, env.Dotenv(), snapshot.Values)
+	require.Equal(t, map[string]string{
+		"SERVICE_API_IMAGE_NAME":    "registry.azurecr.io/api:azd-deploy-1",
+		"SERVICE_WEB_GITOPS_COMMIT": "0123abc",
+	}, snapshot.Artifacts)
+
+	path := filepath.Join(store.directory("dev"), "20240102-030405.json")
+	require.FileExists(t, path)
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		require.Equal(t, osutil.PermissionFileOwnerOnly, info.Mode().Perm())
+	}
+
+	// Changing the environment doesn't change the snapshot.
+	require.NoError(t, env.Config.Set("infra.parameters.sku", "P1"))
+
+	saved, err := store.Get("dev", snapshot.Id)
+	require.NoError(t, err)
+	require.Equal(t, snapshot.Values, saved.Values)
+	require.Equal(t, map[string]any{
+		"infra": map[string]any{"parameters": map[string]any{"sku": "S1"}},
+	}, saved.Config)
+}
+
+func Test_SnapshotStore_CreateSameSecond(t *testing.T) {
+	store := newTestSnapshotStore(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	env := NewWithValues("dev", map[string]string{"KEY": "value"})
+
+	var ids []string
+	for range 3 {
+		snapshot, err := store.Create(env, "")
+		require.NoError(t, err)
+		ids = append(ids, snapshot.Id)
+	}
+
+	require.Equal(t, []string{"20240102-030405", "20240102-030405-2", "20240102-030405-3"}, ids)
+}
+
+func Test_SnapshotStore_List(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	store := newTestSnapshotStore(t, now)
+	env := NewWithValues("dev", map[string]string{"KEY": "value"})
+
+	t.Run("Empty", func(t *testing.T) {
+		snapshots, err := store.List("dev")
+		require.NoError(t, err)
+		require.NotNil(t, snapshots)
+		require.Empty(t, snapshots)
+	})
+
+	t.Run("NewestFirst", func(t *testing.T) {
+		for i := range 3 {
+			store.now = func() time.Time { return now.Add(time.Duration(i) * time.Hour) }
+			_, err := store.Create(env, "")
+			require.NoError(t, err)
+		}
+
+		// Files that aren't snapshots are ignored.
+		require.NoError(t, os.WriteFile(filepath.Join(store.directory("dev"), "notes.txt"), []byte("notes"), 0600))
+
+		snapshots, err := store.List("dev")
+		require.NoError(t, err)
+
+		var ids []string
+		for _, snapshot := range snapshots {
+			ids = append(ids, snapshot.Id)
+		}
+		require.Equal(t, []string{"20240102-050405", "20240102-040405", "20240102-030405"}, ids)
+
+		other, err := store.List("other")
+		require.NoError(t, err)
+		require.Empty(t, other)
+	})
+}
+
+func Test_SnapshotStore_Get(t *testing.T) {
+	store := newTestSnapshotStore(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	_, err := store.Create(NewWithValues("dev", map[string]string{"KEY": "value"}), "")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		id   string
+	}{
+		{name: "Missing", id: "20240101-000000"},
+		{name: "Empty", id: ""},
+		{name: "Separator", id: "../dev/.env"},
+		{name: "Hidden", id: ".env"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := store.Get("dev", tt.id)
+			require.ErrorIs(t, err, ErrSnapshotNotFound)
+		})
+	}
+}
+
+func Test_Snapshot_Restore(t *testing.T) {
+	snapshot := &Snapshot{
+		Values: map[string]string{
+			"KEY":                    "old",
+			"REMOVED":                "restored",
+			"SERVICE_API_IMAGE_NAME": "api:1",
+		},
+		Config: map[string]any{
+			"infra": map[string]any{"parameters": map[string]any{"sku": "S1"}},
+		},
+	}
+
+	env := NewWithValues("dev", map[string]string{
+		"KEY":                    "new",
+		"ADDED":                  "value",
+		"SERVICE_API_IMAGE_NAME": "api:2",
+	})
+	env.Config = config.NewConfig(map[string]any{
+		"infra": map[string]any{"parameters": map[string]any{"sku": "P1", "replicas": 3}},
+	})
+
+	snapshot.Restore(env)
+
+	require.Equal(t, snapshot.Values, env.Dotenv())
+	require.Contains(t, env.deletedKeys, "ADDED")
+
+	sku, has := env.Config.GetString("infra.parameters.sku")
+	require.True(t, has)
+	require.Equal(t, "S1", sku)
+
+	_, has = env.Config.Get("infra.parameters.replicas")
+	require.False(t, has)
+}
+
+func Test_Snapshot_CommonValues(t *testing.T) {
+	store := newTestSnapshotStore(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	env := NewWithValues("dev", map[string]string{"KEY": "value", "SHARED_OVERRIDE": "dev"})
+	env.setCommon(map[string]string{"SHARED": "v1", "SHARED_OVERRIDE": "common"})
+
+	snapshot, err := store.Create(env, "")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"KEY": "value", "SHARED_OVERRIDE": "dev"}, snapshot.Values)
+
+	legacy := &Snapshot{Values: map[string]string{"KEY": "value", "SHARED": "v1", "SHARED_OVERRIDE": "dev"}}
+	for _, snapshot := range []*Snapshot{snapshot, legacy} {
+		env.DotenvSet("KEY", "changed")
+		env.DotenvDelete("SHARED_OVERRIDE")
+
+		snapshot.Restore(env)
+		require.Equal(t, "value", env.Getenv("KEY"))
+		require.Equal(t, "dev", env.Getenv("SHARED_OVERRIDE"))
+
+		source, _ := env.DotenvSource("SHARED")
+		require.Equal(t, ValueSourceCommon, source)
+
+		// Changes to common.env after the restore still apply.
+		env.setCommon(map[string]string{"SHARED": "v2", "SHARED_OVERRIDE": "common"})
+		require.Equal(t, "v2", env.Getenv("SHARED"))
+		env.setCommon(map[string]string{"SHARED": "v1", "SHARED_OVERRIDE": "common"})
+	}
+}
+
+func Test_SnapshotStore_SecretEncryption(t *testing.T) {
+	cipher := newTestSecretCipher(bytes.Repeat([]byte{1}, secretKeyLength))
+	azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+	store := NewSnapshotStoreWithSecretEncryption(azdContext, cipher)
+
+	env := NewWithValues("dev", map[string]string{"API_URL": "https://contoso.com", "API_KEY": "s3cr3t-value"})
+	require.NoError(t, env.MarkSecret("API_KEY"))
+
+	snapshot, err := store.Create(env, "")
+	require.NoError(t, err)
+	require.Equal(t, "s3cr3t-value", snapshot.Values["API_KEY"])
+
+	content, err := os.ReadFile(filepath.Join(store.directory("dev"), snapshot.Id+".json"))
+	require.NoError(t, err)
+	require.NotContains(t, string(content), "s3cr3t-value")
+	require.Contains(t, string(content), "https://contoso.com")
+
+	saved, err := store.Get("dev", snapshot.Id)
+	require.NoError(t, err)
+	require.Equal(t, "s3cr3t-value", saved.Values["API_KEY"])
+
+	// Stores without encryption still decrypt the values.
+	plainStore := NewSnapshotStore(azdContext)
+	plainStore.secrets = cipher
+	saved, err = plainStore.Get("dev", snapshot.Id)
+	require.NoError(t, err)
+	require.Equal(t, "s3cr3t-value", saved.Values["API_KEY"])
+
+	// Snapshots encrypted with another key aren't restored with their values encrypted.
+	plainStore.secrets = newTestSecretCipher(bytes.Repeat([]byte{2}, secretKeyLength))
+	_, err = plainStore.Get("dev", snapshot.Id)
+	require.ErrorIs(t, err, ErrSecretNotDecrypted)
+}
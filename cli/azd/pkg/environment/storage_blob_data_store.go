@@ -132,7 +132,7 @@ func (sbd *StorageBlobDataStore) Save(ctx context.Context, env *Environment, opt
 		return fmt.Errorf("uploading config: %w", describeError(err))
 	}
 
-	marshalled, err := marshallDotEnv(env)
+	marshalled, err := marshallDotEnv(env, nil)
 	if err != nil {
 		return fmt.Errorf("marshalling .env: %w", err)
 	}
@@ -0,0 +1,119 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// tagsConfigKey is the environment config path under which the tags of the environment are stored.
+const tagsConfigKey = "tags"
+
+// Well-known tags of environments, shown in the help of `azd env tag`. Any other tag name can be used too.
+const (
+	TagOwner   = "owner"
+	TagPurpose = "purpose"
+	TagExpiry  = "expiry"
+)
+
+// Tags returns the tags of the environment, an empty map when it has none.
+func (e *Environment) Tags() map[string]string {
+	tags := map[string]string{}
+
+	values, has := e.Config.GetMap(tagsConfigKey)
+	if !has {
+		return tags
+	}
+
+	for name, value := range values {
+		if text, ok := value.(string); ok {
+			tags[name] = text
+		}
+	}
+
+	return tags
+}
+
+// SetTag sets the tag of the environment with the given name to value. [Save] should be called to ensure this change is
+// persisted.
+func (e *Environment) SetTag(name string, value string) error {
+	if err := validateTagName(name); err != nil {
+		return err
+	}
+
+	return e.Config.Set(tagsConfigKey+"."+name, value)
+}
+
+// UnsetTag removes the tag of the environment with the given name, if it exists. [Save] should be called to ensure this
+// change is persisted.
+func (e *Environment) UnsetTag(name string) error {
+	if err := validateTagName(name); err != nil {
+		return err
+	}
+
+	if err := e.Config.Unset(tagsConfigKey + "." + name); err != nil {
+		return err
+	}
+
+	// Don't leave an empty tags object behind.
+	if values, has := e.Config.GetMap(tagsConfigKey); has && len(values) == 0 {
+		return e.Config.Unset(tagsConfigKey)
+	}
+
+	return nil
+}
+
+// validateTagName returns an error when name can't be used as the name of a tag. Tags are stored in the environment
+// config, where dots separate the segments of a path.
+func validateTagName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.New("tag name can't be empty")
+	}
+
+	if strings.ContainsAny(name, ".=") {
+		return fmt.Errorf("tag name '%s' can't contain '.' or '='", name)
+	}
+
+	return nil
+}
+
+// TagFilter matches the environments that have all the given tags, with the given values.
+type TagFilter map[string]string
+
+// ParseTagFilter parses filters in the `<tag>=<value>` format into a [TagFilter]. It returns a nil filter, which
+// matches all environments, when there are no filters.
+func ParseTagFilter(filters []string) (TagFilter, error) {
+	if len(filters) == 0 {
+		return nil, nil
+	}
+
+	filter := TagFilter{}
+	for _, f := range filters {
+		name, value, has := strings.Cut(f, "=")
+		if !has {
+			return nil, fmt.Errorf("invalid filter '%s', use the <tag>=<value> format", f)
+		}
+
+		if err := validateTagName(name); err != nil {
+			return nil, fmt.Errorf("invalid filter '%s': %w", f, err)
+		}
+
+		filter[name] = value
+	}
+
+	return filter, nil
+}
+
+// Matches returns whether the tags contain all the tags of the filter, with the same values.
+func (f TagFilter) Matches(tags map[string]string) bool {
+	for name, value := range f {
+		if tag, has := tags[name]; !has || tag != value {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,46 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTags(t *testing.T) {
+	env := New("dev")
+	require.Empty(t, env.Tags())
+
+	require.NoError(t, env.SetTag(TagOwner, "alice"))
+	require.NoError(t, env.SetTag(TagExpiry, "2026-12-31"))
+	require.Equal(t, map[string]string{"owner": "alice", "expiry": "2026-12-31"}, env.Tags())
+
+	require.ErrorContains(t, env.SetTag("team.name", "web"), "can't contain")
+	require.ErrorContains(t, env.SetTag(" ", "web"), "can't be empty")
+
+	require.NoError(t, env.UnsetTag(TagOwner))
+	require.NoError(t, env.UnsetTag(TagExpiry))
+	require.Empty(t, env.Tags())
+	_, has := env.Config.Get(tagsConfigKey)
+	require.False(t, has)
+}
+
+func TestTagFilter(t *testing.T) {
+	filter, err := ParseTagFilter(nil)
+	require.NoError(t, err)
+	require.Nil(t, filter)
+	require.True(t, filter.Matches(nil))
+
+	filter, err = ParseTagFilter([]string{"owner=alice", "purpose=demo=1"})
+	require.NoError(t, err)
+	require.Equal(t, TagFilter{"owner": "alice", "purpose": "demo=1"}, filter)
+
+	require.True(t, filter.Matches(map[string]string{"owner": "alice", "purpose": "demo=1", "expiry": "never"}))
+	require.False(t, filter.Matches(map[string]string{"owner": "alice"}))
+	require.False(t, filter.Matches(map[string]string{"owner": "bob", "purpose": "demo=1"}))
+
+	_, err = ParseTagFilter([]string{"owner"})
+	require.ErrorContains(t, err, "<tag>=<value>")
+}
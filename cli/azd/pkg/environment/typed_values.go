@@ -0,0 +1,101 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Value types accepted by `azd env set --type`.
+const (
+	ValueTypeString = "string"
+	ValueTypeInt    = "int"
+	ValueTypeBool   = "bool"
+	ValueTypeJson   = "json"
+)
+
+// ValueTypes are the value types accepted by `azd env set --type`.
+var ValueTypes = []string{ValueTypeString, ValueTypeInt, ValueTypeBool, ValueTypeJson}
+
+// NormalizeValue validates that value is of the given value type (one of the ValueType* constants), and returns its
+// normalized form, as written to the .env file, along with the output type recorded for it:
+//
+//   - int values are written in decimal notation, without leading zeros or a plus sign.
+//   - bool values are written as `true` or `false`.
+//   - json values are written as compact JSON. JSON strings are written without their quotes.
+func NormalizeValue(valueType string, value string) (string, string, error) {
+	switch valueType {
+	case ValueTypeString:
+		return value, OutputTypeString, nil
+	case ValueTypeInt:
+		i, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return "", "", fmt.Errorf("'%s' is not an integer", value)
+		}
+
+		return strconv.FormatInt(i, 10), OutputTypeNumber, nil
+	case ValueTypeBool:
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return "", "", fmt.Errorf("'%s' is not a boolean, use 'true' or 'false'", value)
+		}
+
+		return strconv.FormatBool(b), OutputTypeBoolean, nil
+	case ValueTypeJson:
+		return normalizeJsonValue([]byte(value))
+	default:
+		return "", "", fmt.Errorf(
+			"unsupported value type '%s', use one of: %s", valueType, strings.Join(ValueTypes, ", "))
+	}
+}
+
+// normalizeJsonValue compacts the JSON document in data, and returns it with the output type of its top-level value.
+func normalizeJsonValue(data []byte) (string, string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return "", "", fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	if decoder.More() {
+		return "", "", errors.New("invalid JSON: unexpected content after the value")
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, bytes.TrimSpace(data)); err != nil {
+		return "", "", fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		return compact.String(), OutputTypeObject, nil
+	case []any:
+		return compact.String(), OutputTypeArray, nil
+	case json.Number:
+		return v.String(), OutputTypeNumber, nil
+	case bool:
+		return strconv.FormatBool(v), OutputTypeBoolean, nil
+	case string:
+		return v, OutputTypeString, nil
+	default:
+		return "", "", errors.New("JSON null is not a supported value")
+	}
+}
+
+// SetValueType records the output type of the .env value for key, keeping whether it's a secret, so that typed views of
+// the environment, like `azd env get-values --output json`, return it as a value of that type. [Save] should be called
+// to ensure this change is persisted.
+func (e *Environment) SetValueType(key string, outputType string) error {
+	metadata, _ := e.OutputMetadata(key)
+	metadata.Type = outputType
+
+	return e.SetOutputMetadata(key, metadata)
+}
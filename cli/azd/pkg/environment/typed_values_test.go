@@ -0,0 +1,61 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeValue(t *testing.T) {
+	tests := []struct {
+		valueType  string
+		value      string
+		want       string
+		outputType string
+		wantErr    string
+	}{
+		{valueType: ValueTypeString, value: " 007 ", want: " 007 ", outputType: OutputTypeString},
+		{valueType: ValueTypeInt, value: " +007", want: "7", outputType: OutputTypeNumber},
+		{valueType: ValueTypeInt, value: "1.5", wantErr: "not an integer"},
+		{valueType: ValueTypeBool, value: "TRUE", want: "true", outputType: OutputTypeBoolean},
+		{valueType: ValueTypeBool, value: "yes", wantErr: "not a boolean"},
+		{valueType: ValueTypeJson, value: "{ \"a\": [1, 2] }\n", want: `{"a":[1,2]}`, outputType: OutputTypeObject},
+		{valueType: ValueTypeJson, value: `[ ]`, want: `[]`, outputType: OutputTypeArray},
+		{valueType: ValueTypeJson, value: `1e3`, want: `1e3`, outputType: OutputTypeNumber},
+		{valueType: ValueTypeJson, value: `"text"`, want: `text`, outputType: OutputTypeString},
+		{valueType: ValueTypeJson, value: `{} {}`, wantErr: "unexpected content"},
+		{valueType: ValueTypeJson, value: `{"a":`, wantErr: "invalid JSON"},
+		{valueType: ValueTypeJson, value: `null`, wantErr: "null"},
+		{valueType: "float", value: "1", wantErr: "unsupported value type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.valueType+"/"+tt.value, func(t *testing.T) {
+			value, outputType, err := NormalizeValue(tt.valueType, tt.value)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, value)
+			require.Equal(t, tt.outputType, outputType)
+		})
+	}
+}
+
+func TestSetValueType(t *testing.T) {
+	env := NewWithValues("test", map[string]string{"COUNT": "3"})
+	require.NoError(t, env.MarkSecret("COUNT"))
+
+	// The type is recorded without losing the secret mark, and resetting it to string keeps the mark too.
+	require.NoError(t, env.SetValueType("COUNT", OutputTypeNumber))
+	metadata, _ := env.OutputMetadata("COUNT")
+	require.Equal(t, OutputMetadata{Type: OutputTypeNumber, Secure: true}, metadata)
+
+	require.NoError(t, env.SetValueType("COUNT", OutputTypeString))
+	require.True(t, env.IsSecret("COUNT"))
+}
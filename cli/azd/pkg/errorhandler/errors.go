@@ -11,12 +11,39 @@ type ErrorLink struct {
 	Title string
 }
 
+// ErrorCategory is the area of azd an error comes from.
+type ErrorCategory string
+
+const (
+	// ErrorCategoryAuth is for errors signing in to Azure, or with the permissions of the signed in account.
+	ErrorCategoryAuth ErrorCategory = "auth"
+	// ErrorCategoryProvisioning is for errors creating or updating Azure resources.
+	ErrorCategoryProvisioning ErrorCategory = "provisioning"
+	// ErrorCategoryDeployment is for errors deploying services to their Azure resources.
+	ErrorCategoryDeployment ErrorCategory = "deployment"
+	// ErrorCategoryTooling is for errors with azd itself, its configuration, or the tools it runs, like hooks and Docker.
+	ErrorCategoryTooling ErrorCategory = "tooling"
+)
+
+// ErrorCategories lists all the error categories.
+var ErrorCategories = []ErrorCategory{
+	ErrorCategoryAuth,
+	ErrorCategoryProvisioning,
+	ErrorCategoryDeployment,
+	ErrorCategoryTooling,
+}
+
 // ErrorWithSuggestion is a custom error type that includes user-friendly messaging.
 // It wraps an original error with a human-readable message, actionable suggestion,
 // and optional reference links.
 type ErrorWithSuggestion struct {
 	// Err is the original underlying error
 	Err error
+	// Code is a stable, machine-readable error code in lowercase snake_case (optional), like "insufficient_quota".
+	// Support and automation key off codes instead of messages, so a code never changes once released.
+	Code string
+	// Category is the area of the error (optional)
+	Category ErrorCategory
 	// Message is a user-friendly explanation of what went wrong
 	Message string
 	// Suggestion is actionable next steps to resolve the issue
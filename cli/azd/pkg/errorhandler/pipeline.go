@@ -141,6 +141,8 @@ func (p *ErrorHandlerPipeline) evaluateRule(
 
 	return &ErrorWithSuggestion{
 		Err:        err,
+		Code:       rule.Code,
+		Category:   rule.Category,
 		Message:    rule.Message,
 		Suggestion: rule.Suggestion,
 		Links:      links,
@@ -159,5 +161,17 @@ func (p *ErrorHandlerPipeline) invokeHandler(
 		return nil
 	}
 
-	return handler.Handle(ctx, err, rule)
+	suggestion := handler.Handle(ctx, err, rule)
+	if suggestion != nil {
+		// Handlers compute the message, but the code and category of the error come from the rule, unless the
+		// handler sets its own.
+		if suggestion.Code == "" {
+			suggestion.Code = rule.Code
+		}
+		if suggestion.Category == "" {
+			suggestion.Category = rule.Category
+		}
+	}
+
+	return suggestion
 }
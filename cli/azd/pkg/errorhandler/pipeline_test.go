@@ -21,6 +21,8 @@ func TestPipeline_PatternMatching(t *testing.T) {
 		rules: []ErrorSuggestionRule{
 			{
 				Patterns:   []string{"quota exceeded"},
+				Code:       "quota_exceeded",
+				Category:   ErrorCategoryProvisioning,
 				Message:    "Quota limit reached.",
 				Suggestion: "Request a quota increase.",
 			},
@@ -30,6 +32,8 @@ func TestPipeline_PatternMatching(t *testing.T) {
 
 	result := pipeline.Process(t.Context(), errors.New("deployment failed: quota exceeded"))
 	require.NotNil(t, result)
+	assert.Equal(t, "quota_exceeded", result.Code)
+	assert.Equal(t, ErrorCategoryProvisioning, result.Category)
 	assert.Equal(t, "Quota limit reached.", result.Message)
 	assert.Equal(t, "Request a quota increase.", result.Suggestion)
 }
@@ -164,6 +168,8 @@ func TestPipeline_Handler(t *testing.T) {
 			{
 				ErrorType: "testDeploymentError",
 				Handler:   "testHandler",
+				Code:      "test_deployment_failed",
+				Category:  ErrorCategoryDeployment,
 			},
 		},
 		matcher: NewPatternMatcher(),
@@ -181,6 +187,9 @@ func TestPipeline_Handler(t *testing.T) {
 	assert.True(t, handlerCalled)
 	assert.Equal(t, "Dynamic message", result.Message)
 	assert.Equal(t, "Dynamic suggestion", result.Suggestion)
+	// The code and category come from the rule
+	assert.Equal(t, "test_deployment_failed", result.Code)
+	assert.Equal(t, ErrorCategoryDeployment, result.Category)
 }
 
 func TestPipeline_HandlerNotFound(t *testing.T) {
@@ -667,6 +676,7 @@ func TestErrorSuggestionsYaml_IsValid(t *testing.T) {
 	require.NoError(t, err, "error_suggestions.yaml must be valid YAML")
 	require.NotEmpty(t, config.Rules, "error_suggestions.yaml must contain at least one rule")
 
+	categories := map[string]ErrorCategory{}
 	for i, rule := range config.Rules {
 		label := fmt.Sprintf("rule[%d]", i)
 
@@ -705,6 +715,19 @@ func TestErrorSuggestionsYaml_IsValid(t *testing.T) {
 			assert.NotEmpty(t, link.URL,
 				"%s: links[%d] must have a 'url'", label, j)
 		}
+
+		// Every rule must have a stable code and a known category
+		assert.Regexp(t, `^[a-z][a-z0-9]*(_[a-z0-9]+)*$`, rule.Code,
+			"%s: 'code' must be set in lowercase snake_case", label)
+		assert.Contains(t, ErrorCategories, rule.Category,
+			"%s: 'category' must be one of %v", label, ErrorCategories)
+
+		// Rules sharing a code describe the same error, so they share its category
+		if category, has := categories[rule.Code]; has {
+			assert.Equal(t, category, rule.Category,
+				"%s: code %q is used with different categories", label, rule.Code)
+		}
+		categories[rule.Code] = rule.Category
 	}
 }
 
@@ -745,4 +768,6 @@ func TestErrorSuggestionsYaml_AADSTS_GenericRule(t *testing.T) {
 	assert.Equal(t, "Authentication with Azure failed.", result.Message)
 	assert.Contains(t, result.Suggestion, "azd auth login")
 	assert.Contains(t, result.Suggestion, "automatically clears cached authentication data")
+	assert.Equal(t, "auth_failed", result.Code)
+	assert.Equal(t, ErrorCategoryAuth, result.Category)
 }
@@ -32,6 +32,13 @@ type ErrorSuggestionRule struct {
 	// instead of using the static message/suggestion/links fields.
 	Handler string `yaml:"handler,omitempty"`
 
+	// Code is the stable error code of the errors matching the rule, in lowercase snake_case.
+	// Rules describing the same error share a code.
+	Code string `yaml:"code,omitempty"`
+
+	// Category is the area of the errors matching the rule.
+	Category ErrorCategory `yaml:"category,omitempty"`
+
 	// Message is a user-friendly error message.
 	Message string `yaml:"message,omitempty"`
 
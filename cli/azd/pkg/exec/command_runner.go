@@ -113,7 +113,7 @@ func (r *commandRunner) Run(ctx context.Context, args RunArgs) (RunResult, error
 
 	var stdout, stderr bytes.Buffer
 
-	cmd.Env = appendEnv(args.Env)
+	cmd.Env = commandEnv(args)
 
 	if args.Interactive {
 		cmd.Stdin = r.stdin
@@ -199,7 +199,7 @@ func (r *commandRunner) RunList(ctx context.Context, commands []string, args Run
 	}
 
 	process.Cmd.Dir = args.Cwd
-	process.Env = appendEnv(args.Env)
+	process.Env = commandEnv(args)
 
 	var stdOutBuf bytes.Buffer
 	var stdErrBuf bytes.Buffer
@@ -268,6 +268,15 @@ func (r *commandRunner) ToolInPath(name string) error {
 	}
 }
 
+// commandEnv returns the environment for a child process, honoring the [EnvIsolation] requested by args.
+func commandEnv(args RunArgs) []string {
+	if args.EnvIsolation != nil {
+		return isolatedEnv(args.EnvIsolation, args.Env)
+	}
+
+	return appendEnv(args.Env)
+}
+
 func appendEnv(env []string) []string {
 	if len(env) > 0 {
 		return append(os.Environ(), env...)
@@ -0,0 +1,142 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package exec
+
+import (
+	"context"
+	"os"
+	"path"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// EnvIsolation restricts the environment that child processes receive to an explicit allow-list instead of the
+// full azd process environment plus the azd environment values.
+//
+// Only keys matching an entry in Allow are passed through. Entries may use shell-style wildcards (for example
+// `VITE_*`). A small set of system variables required for tools to start (PATH, HOME, TEMP, ...) is always kept.
+type EnvIsolation struct {
+	// Allow lists the environment variable names (or wildcard patterns) passed to the child process.
+	Allow []string `yaml:"allow,omitempty" json:"allow,omitempty"`
+}
+
+// baselineEnvKeys are always inherited from the azd process, even when isolation is enabled, since most tools
+// cannot locate executables, home directories or temporary storage without them.
+var baselineEnvKeys = []string{
+	"PATH",
+	"HOME",
+	"USER",
+	"LANG",
+	"TERM",
+	"TMPDIR",
+	"TEMP",
+	"TMP",
+	"SHELL",
+	"SYSTEMROOT",
+	"SYSTEMDRIVE",
+	"WINDIR",
+	"COMSPEC",
+	"PATHEXT",
+	"USERPROFILE",
+	"APPDATA",
+	"LOCALAPPDATA",
+	"PROGRAMDATA",
+	"PROGRAMFILES",
+	"PROGRAMFILES(X86)",
+	"HOMEDRIVE",
+	"HOMEPATH",
+}
+
+// Allows reports whether the given environment variable name passes the isolation filter.
+func (i *EnvIsolation) Allows(key string) bool {
+	for _, baseline := range baselineEnvKeys {
+		if envKeyEqual(key, baseline) {
+			return true
+		}
+	}
+
+	for _, pattern := range i.Allow {
+		if strings.ContainsAny(pattern, "*?[") {
+			name := key
+			if runtime.GOOS == "windows" {
+				pattern, name = strings.ToUpper(pattern), strings.ToUpper(key)
+			}
+
+			if matched, err := path.Match(pattern, name); err == nil && matched {
+				return true
+			}
+			continue
+		}
+
+		if envKeyEqual(key, pattern) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Filter returns the subset of env (in KEY=VALUE form) allowed by the isolation settings.
+func (i *EnvIsolation) Filter(env []string) []string {
+	filtered := make([]string, 0, len(env))
+	for _, kv := range env {
+		key, _, _ := strings.Cut(kv, "=")
+		if i.Allows(key) {
+			filtered = append(filtered, kv)
+		}
+	}
+
+	return filtered
+}
+
+// With returns a copy of the isolation settings that also allows keys, or nil when i is nil. Tools use it to keep
+// the variables they set themselves.
+func (i *EnvIsolation) With(keys ...string) *EnvIsolation {
+	if i == nil {
+		return nil
+	}
+
+	return &EnvIsolation{Allow: append(slices.Clone(i.Allow), keys...)}
+}
+
+// envKeyEqual compares environment variable names, ignoring case on Windows where names are case-insensitive.
+func envKeyEqual(a, b string) bool {
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(a, b)
+	}
+
+	return a == b
+}
+
+type envIsolationContextKey struct{}
+
+// WithEnvIsolation returns a context carrying the isolation settings of the service being restored, built or packaged.
+// The settings only apply to the commands that run the project's own build (for example `npm run build`), which opt in
+// with [RunArgs.WithEnvIsolation]; other tools azd runs with the same context keep the full environment.
+// A nil isolation returns ctx unchanged.
+func WithEnvIsolation(ctx context.Context, isolation *EnvIsolation) context.Context {
+	if isolation == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, envIsolationContextKey{}, isolation)
+}
+
+// EnvIsolationFromContext returns the isolation settings carried by ctx, or nil when project commands inherit the
+// full environment.
+func EnvIsolationFromContext(ctx context.Context) *EnvIsolation {
+	if isolation, ok := ctx.Value(envIsolationContextKey{}).(*EnvIsolation); ok {
+		return isolation
+	}
+
+	return nil
+}
+
+// isolatedEnv builds the environment for a child process when isolation is enabled. Unlike appendEnv, the result is
+// never nil so the child process does not fall back to inheriting the full azd process environment.
+func isolatedEnv(isolation *EnvIsolation, env []string) []string {
+	result := isolation.Filter(os.Environ())
+	return append(result, isolation.Filter(env)...)
+}
@@ -0,0 +1,80 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package exec
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvIsolationAllows(t *testing.T) {
+	isolation := &EnvIsolation{Allow: []string{"API_URL", "VITE_*"}}
+
+	tests := []struct {
+		key      string
+		expected bool
+	}{
+		{key: "API_URL", expected: true},
+		{key: "VITE_APP_TITLE", expected: true},
+		{key: "PATH", expected: true},
+		{key: "HOME", expected: true},
+		{key: "AZURE_CLIENT_SECRET", expected: false},
+		{key: "API_URL_2", expected: false},
+		{key: "MY_VITE_VAR", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			require.Equal(t, tt.expected, isolation.Allows(tt.key))
+		})
+	}
+}
+
+func TestEnvIsolationFilter(t *testing.T) {
+	isolation := &EnvIsolation{Allow: []string{"KEEP"}}
+
+	filtered := isolation.Filter([]string{"KEEP=1", "DROP=2", "PATH=/usr/bin", "KEEP_NOT=3"})
+	require.Equal(t, []string{"KEEP=1", "PATH=/usr/bin"}, filtered)
+}
+
+func TestEnvIsolationContext(t *testing.T) {
+	ctx := t.Context()
+	require.Nil(t, EnvIsolationFromContext(ctx))
+	require.Equal(t, ctx, WithEnvIsolation(ctx, nil))
+
+	isolation := &EnvIsolation{Allow: []string{"KEEP"}}
+	require.Same(t, isolation, EnvIsolationFromContext(WithEnvIsolation(ctx, isolation)))
+}
+
+func TestCommandEnvIsolated(t *testing.T) {
+	t.Setenv("AZD_ISOLATION_PARENT_SECRET", "secret")
+	t.Setenv("AZD_ISOLATION_PARENT_ALLOWED", "allowed")
+
+	args := NewRunArgs("tool").
+		WithEnv([]string{"AZD_ISOLATION_ENV_ALLOWED=1", "AZD_ISOLATION_ENV_SECRET=2"}).
+		WithEnvIsolation(&EnvIsolation{Allow: []string{"AZD_ISOLATION_*ALLOWED"}})
+
+	env := commandEnv(args)
+	require.NotNil(t, env)
+	require.True(t, slices.Contains(env, "AZD_ISOLATION_PARENT_ALLOWED=allowed"))
+	require.True(t, slices.Contains(env, "AZD_ISOLATION_ENV_ALLOWED=1"))
+	require.False(t, slices.Contains(env, "AZD_ISOLATION_PARENT_SECRET=secret"))
+	require.False(t, slices.Contains(env, "AZD_ISOLATION_ENV_SECRET=2"))
+
+	// Without additional env values the isolated environment still replaces the inherited one.
+	env = commandEnv(args.WithEnv(nil))
+	require.NotNil(t, env)
+	require.False(t, slices.Contains(env, "AZD_ISOLATION_PARENT_SECRET=secret"))
+}
+
+func TestCommandEnvNotIsolated(t *testing.T) {
+	t.Setenv("AZD_ISOLATION_PARENT_SECRET", "secret")
+
+	// Commands that don't request isolation inherit the full environment.
+	env := commandEnv(NewRunArgs("tool").WithEnv([]string{"AZD_ISOLATION_ENV_SECRET=2"}))
+	require.True(t, slices.Contains(env, "AZD_ISOLATION_PARENT_SECRET=secret"))
+	require.True(t, slices.Contains(env, "AZD_ISOLATION_ENV_SECRET=2"))
+}
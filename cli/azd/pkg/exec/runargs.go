@@ -35,6 +35,9 @@ type RunArgs struct {
 
 	// When set will call the command with the specified StdOut
 	StdOut io.Writer
+
+	// When set the command only receives the environment variables allowed by the isolation settings
+	EnvIsolation *EnvIsolation
 }
 
 // NewRunArgs creates a new instance with the specified cmd and args
@@ -73,6 +76,12 @@ func (b RunArgs) WithEnv(env []string) RunArgs {
 	return b
 }
 
+// Updates the environment isolation settings for the command. A nil isolation passes the full environment.
+func (b RunArgs) WithEnvIsolation(isolation *EnvIsolation) RunArgs {
+	b.EnvIsolation = isolation
+	return b
+}
+
 // Updates whether or not this will be an interactive commands
 // Interactive command sets stdin, stdout & stderr to the OS console/terminal
 func (b RunArgs) WithInteractive(interactive bool) RunArgs {
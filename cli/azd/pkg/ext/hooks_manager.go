@@ -84,12 +84,7 @@ func (h *HooksManager) GetByParams(
 	validHookNames := map[string]struct{}{}
 
 	for _, commandName := range commands {
-		// Convert things like `azd env list` => 'envlist`
-		commandName = strings.TrimPrefix(commandName, "azd")
-		commandName = strings.TrimSpace(commandName)
-		commandName = strings.ReplaceAll(commandName, " ", "")
-		commandName = strings.ToLower(string(prefix) + commandName)
-		validHookNames[commandName] = struct{}{}
+		validHookNames[string(prefix)+hookCommandName(commandName)] = struct{}{}
 	}
 
 	predicate := func(scriptName string, hookConfig *HookConfig) bool {
@@ -100,6 +95,14 @@ func (h *HooksManager) GetByParams(
 	return h.filterConfigs(hooks, predicate)
 }
 
+// hookCommandName converts a command name to the form used in hook names, like `azd env list` => `envlist`
+func hookCommandName(commandName string) string {
+	commandName = strings.TrimPrefix(commandName, "azd")
+	commandName = strings.TrimSpace(commandName)
+	commandName = strings.ReplaceAll(commandName, " ", "")
+	return strings.ToLower(commandName)
+}
+
 // Filters the specified hook configurations based on the predicate
 // Will return an error if any configuration errors are found
 func (h *HooksManager) filterConfigs(
@@ -5,10 +5,15 @@ package ext
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"maps"
 	"os"
+	"slices"
 	"strings"
+	"unicode"
 
 	"github.com/azure/azure-dev/cli/azd/internal/tracing"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/events"
@@ -72,27 +77,67 @@ func NewHooksRunner(
 
 // Invoke runs any registered pre and post script hooks around the specified action.
 // The hookType parameter identifies the hook scope for telemetry (project, layer, or service).
+// When the action or one of its hooks fails, the onerror hooks run before the error is returned.
 func (h *HooksRunner) Invoke(
 	ctx context.Context, commands []string, hookType string, actionFn InvokeFn,
 ) error {
 	err := h.RunHooks(ctx, HookTypePre, hookType, nil, commands...)
 	if err != nil {
-		return fmt.Errorf("failed running pre hooks: %w", err)
+		return h.runErrorHooks(ctx, hookType, commands, HookTypePre, fmt.Errorf("failed running pre hooks: %w", err))
 	}
 
 	err = actionFn()
 	if err != nil {
-		return err
+		return h.runErrorHooks(ctx, hookType, commands, HookTypeNone, err)
 	}
 
 	err = h.RunHooks(ctx, HookTypePost, hookType, nil, commands...)
 	if err != nil {
-		return fmt.Errorf("failed running post hooks: %w", err)
+		return h.runErrorHooks(ctx, hookType, commands, HookTypePost, fmt.Errorf("failed running post hooks: %w", err))
 	}
 
 	return nil
 }
 
+// errorHooksRanError marks an error for which the onerror hooks already ran, so they run once when the error goes
+// through nested commands, like the steps of `azd up`.
+type errorHooksRanError struct {
+	error
+}
+
+func (e *errorHooksRanError) Unwrap() error {
+	return e.error
+}
+
+// runErrorHooks runs the onerror hooks for the failure of the command at the given stage, and returns cause.
+// A failure of the onerror hooks is reported as a warning, since the error of the command is the relevant one.
+func (h *HooksRunner) runErrorHooks(
+	ctx context.Context, hookType string, commands []string, stage HookType, cause error,
+) error {
+	if len(h.hooks[OnErrorHookName]) == 0 || len(commands) == 0 || errors.Is(cause, context.Canceled) {
+		return cause
+	}
+	if _, ran := errors.AsType[*errorHooksRanError](cause); ran {
+		return cause
+	}
+
+	commandName := hookCommandName(commands[0])
+	options := &tools.ExecutionContext{
+		EnvVars: []string{
+			fmt.Sprintf("%s=%s", HookEnvErrorCommand, commandName),
+			fmt.Sprintf("%s=%s%s", HookEnvErrorStage, stage, commandName),
+			fmt.Sprintf("%s=%s", HookEnvErrorMessage, cause.Error()),
+		},
+	}
+
+	if err := h.RunHooks(ctx, HookTypeNone, hookType, options, OnErrorHookName); err != nil {
+		h.console.Message(ctx, output.WithWarningFormat("WARNING: failed running onerror hooks: %s", err.Error()))
+		log.Printf("failed running onerror hooks: %v", err)
+	}
+
+	return &errorHooksRanError{cause}
+}
+
 // RunHooks invokes any registered script hooks for the specified hook type and command.
 // The hookType parameter identifies the hook scope for telemetry (project, layer, or service).
 func (h *HooksRunner) RunHooks(
@@ -159,6 +204,13 @@ func (h *HooksRunner) execHook(
 	}
 
 	hookEnv := environment.NewWithValues("temp", h.env.Dotenv())
+	// Values set by azd for the hook, like the details of the error for onerror hooks.
+	payloadKeys := make([]string, 0, len(options.EnvVars))
+	for _, envVar := range options.EnvVars {
+		key, value, _ := strings.Cut(envVar, "=")
+		hookEnv.DotenvSet(key, value)
+		payloadKeys = append(payloadKeys, key)
+	}
 	if len(hookConfig.Secrets) > 0 {
 		err := h.serviceLocator.Invoke(func(keyvaultService keyvault.KeyVaultService) error {
 			for key, value := range hookConfig.Secrets {
@@ -214,8 +266,50 @@ func (h *HooksRunner) execHook(
 
 	scriptPath := hookConfig.resolvedScriptPath
 
+	// Hooks with `output: file` write their outputs to a temp file named by AZD_HOOK_OUTPUT.
+	outputFile := ""
+	if hookConfig.Output == HookOutputFile {
+		file, err := os.CreateTemp("", "azd-hook-output-*.json")
+		if err != nil {
+			statusCode = "hook.output_failed"
+			return fmt.Errorf("creating output file for hook '%s': %w", hookConfig.Name, err)
+		}
+		outputFile = file.Name()
+		file.Close()
+		defer os.Remove(outputFile)
+
+		hookEnv.DotenvSet(HookEnvOutputFile, outputFile)
+		payloadKeys = append(payloadKeys, HookEnvOutputFile)
+	}
+
 	envVars := hookEnv.Environ()
 
+	// With isolation enabled the hook only sees the declared keys. The isolation is passed to the executor so the
+	// command runner does not re-add the azd process environment when the script is executed.
+	isolation := hookEnvIsolation(hookConfig)
+	if isolation != nil {
+		isolation.Allow = append(isolation.Allow, payloadKeys...)
+		envVars = isolation.Filter(envVars)
+	}
+
+	if missing := missingHookInputs(hookConfig.Inputs, envVars, isolation); len(missing) > 0 {
+		statusCode = "hook.input_missing"
+		return &errorhandler.ErrorWithSuggestion{
+			Err: fmt.Errorf(
+				"hook '%s' requires %s: %w",
+				hookConfig.Name, strings.Join(missing, ", "), ErrHookInputMissing,
+			),
+			Message: fmt.Sprintf(
+				"Hook '%s' requires values that are not set: %s.",
+				hookConfig.Name, strings.Join(missing, ", "),
+			),
+			Suggestion: fmt.Sprintf(
+				"Set them with 'azd env set %s <value>', or from the outputs of an earlier hook.",
+				missing[0],
+			),
+		}
+	}
+
 	// Build execution context.
 	execCtx := tools.ExecutionContext{
 		Cwd:          cwd,
@@ -224,6 +318,7 @@ func (h *HooksRunner) execHook(
 		InlineScript: hookConfig.inlineScript,
 		HookName:     hookConfig.Name,
 		Config:       hookConfig.Config,
+		EnvIsolation: isolation,
 	}
 
 	// Merge caller-provided overrides (e.g. forced interactive from 'azd hooks run').
@@ -300,11 +395,156 @@ func (h *HooksRunner) execHook(
 			statusCode = "hook.execution_failed"
 			return execErr
 		}
+
+		return nil
+	}
+
+	if err := h.saveHookOutputs(ctx, hookConfig, res.Stdout, outputFile); err != nil {
+		statusCode = "hook.output_failed"
+		return err
+	}
+
+	return nil
+}
+
+// missingHookInputs returns the inputs of the hook that have no value in its environment: the variables passed to
+// the hook, or the variables of the azd process the isolation lets through.
+func missingHookInputs(inputs []string, envVars []string, isolation *exec.EnvIsolation) []string {
+	if len(inputs) == 0 {
+		return nil
+	}
+
+	values := make(map[string]string, len(envVars))
+	for _, envVar := range envVars {
+		key, value, _ := strings.Cut(envVar, "=")
+		values[key] = value
+	}
+
+	var missing []string
+	for _, input := range inputs {
+		if values[input] != "" {
+			continue
+		}
+		if isolation == nil || isolation.Allows(input) {
+			if os.Getenv(input) != "" {
+				continue
+			}
+		}
+
+		missing = append(missing, input)
+	}
+
+	return missing
+}
+
+// saveHookOutputs sets the outputs of the hook, read from its output file or its stdout, in the azd environment.
+func (h *HooksRunner) saveHookOutputs(
+	ctx context.Context, hookConfig *HookConfig, stdout string, outputFile string,
+) error {
+	var content string
+	switch hookConfig.Output {
+	case HookOutputFile:
+		data, err := os.ReadFile(outputFile)
+		if err != nil {
+			return fmt.Errorf("reading outputs of hook '%s': %w", hookConfig.Name, err)
+		}
+		content = string(data)
+	case HookOutputStdout:
+		content = stdout
+	default:
+		return nil
+	}
+
+	outputs, err := parseHookOutputs(content)
+	if err != nil {
+		return fmt.Errorf("hook '%s': %w", hookConfig.Name, err)
+	}
+	if len(outputs) == 0 {
+		return nil
+	}
+
+	// The hook may have changed the environment itself, like with `azd env set`.
+	if err := h.envManager.Reload(ctx, h.env); err != nil {
+		return fmt.Errorf("reloading environment before saving hook outputs: %w", err)
+	}
+
+	keys := slices.Sorted(maps.Keys(outputs))
+	for _, key := range keys {
+		h.env.DotenvSet(key, outputs[key])
+	}
+
+	if err := h.envManager.Save(ctx, h.env); err != nil {
+		return fmt.Errorf("saving outputs of hook '%s': %w", hookConfig.Name, err)
 	}
 
+	log.Printf("hook '%s' set %s\n", hookConfig.Name, strings.Join(keys, ", "))
 	return nil
 }
 
+// parseHookOutputs parses the outputs of a hook, a JSON object. String values are used as-is, other values are
+// kept as JSON, and null values are ignored. Empty content means the hook has no outputs.
+func parseHookOutputs(content string) (map[string]string, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, nil
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(content))
+	decoder.UseNumber()
+
+	var values map[string]any
+	if err := decoder.Decode(&values); err != nil {
+		return nil, fmt.Errorf("%w: expected a JSON object: %w", ErrInvalidHookOutput, err)
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("%w: unexpected content after the JSON object", ErrInvalidHookOutput)
+	}
+
+	outputs := make(map[string]string, len(values))
+	for key, value := range values {
+		if !isValidHookOutputKey(key) {
+			return nil, fmt.Errorf("%w: %q isn't a valid environment variable name", ErrInvalidHookOutput, key)
+		}
+
+		switch value := value.(type) {
+		case nil:
+			continue
+		case string:
+			outputs[key] = value
+		default:
+			jsonValue, err := json.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: value of '%s': %w", ErrInvalidHookOutput, key, err)
+			}
+			outputs[key] = string(jsonValue)
+		}
+	}
+
+	return outputs, nil
+}
+
+// isValidHookOutputKey returns true when key can be saved as the name of an environment variable: it isn't empty, and
+// doesn't contain '=', whitespace or control characters, which would corrupt the .env file of the environment.
+func isValidHookOutputKey(key string) bool {
+	return key != "" && !strings.ContainsFunc(key, func(r rune) bool {
+		return r == '=' || unicode.IsSpace(r) || unicode.IsControl(r)
+	})
+}
+
+// hookEnvIsolation returns the environment isolation for the hook, or nil when the hook inherits the full
+// environment. Secrets and inputs declared on the hook are always allowed since they are explicitly requested.
+func hookEnvIsolation(hookConfig *HookConfig) *exec.EnvIsolation {
+	if hookConfig.EnvIsolation == nil {
+		return nil
+	}
+
+	allow := slices.Clone(hookConfig.EnvIsolation.Allow)
+	allow = append(allow, slices.Sorted(maps.Keys(hookConfig.Secrets))...)
+	allow = append(allow, hookConfig.Inputs...)
+
+	return &exec.EnvIsolation{Allow: allow}
+}
+
 // configureExecContext resolves interactive mode and sets up the
 // console previewer for non-interactive hooks that have no custom
 // stdout. Returns true when a previewer was started; the caller must
@@ -5,6 +5,8 @@ package ext
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -1247,6 +1249,336 @@ func Test_ExecHook_ConfigThreading(t *testing.T) {
 	})
 }
 
+func Test_ExecHook_EnvIsolation(t *testing.T) {
+	cwd := t.TempDir()
+	ostest.Chdir(t, cwd)
+
+	env := environment.NewWithValues(
+		"test", map[string]string{
+			"API_URL":             "https://example.com",
+			"VITE_TITLE":          "hello",
+			"AZURE_CLIENT_SECRET": "secret",
+		},
+	)
+
+	require.NoError(t, os.MkdirAll(
+		filepath.Join(cwd, "scripts"),
+		osutil.PermissionDirectory,
+	))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(cwd, "scripts", "hook.sh"),
+		nil, osutil.PermissionExecutableFile,
+	))
+
+	hooksMap := map[string][]*HookConfig{
+		"predeploy": {
+			{
+				Name:  "predeploy",
+				Shell: string(language.HookKindBash),
+				Run:   "scripts/hook.sh",
+				EnvIsolation: &exec.EnvIsolation{
+					Allow: []string{"API_URL", "VITE_*"},
+				},
+			},
+		},
+	}
+
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On(
+		"Reload", mock.Anything, env,
+	).Return(nil)
+
+	var capturedCtx tools.ExecutionContext
+	mockContext := mocks.NewMockContext(
+		t.Context(),
+	)
+
+	mockContext.Container.MustRegisterNamedTransient(
+		string(language.HookKindBash),
+		func() tools.HookExecutor {
+			return &configCaptureExecutor{
+				onExecute: func(
+					ctx tools.ExecutionContext,
+				) {
+					capturedCtx = ctx
+				},
+			}
+		},
+	)
+
+	hooksManager := NewHooksManager(
+		HooksManagerOptions{Cwd: cwd}, mockContext.CommandRunner,
+	)
+	runner := NewHooksRunner(
+		hooksManager,
+		mockContext.CommandRunner,
+		envManager,
+		mockContext.Console,
+		cwd,
+		hooksMap,
+		env,
+		mockContext.Container,
+	)
+
+	err := runner.RunHooks(
+		*mockContext.Context, HookTypePre,
+		"project", nil,
+		"deploy",
+	)
+	require.NoError(t, err)
+
+	require.Contains(t, capturedCtx.EnvVars, "API_URL=https://example.com")
+	require.Contains(t, capturedCtx.EnvVars, "VITE_TITLE=hello")
+	require.NotContains(t, capturedCtx.EnvVars, "AZURE_CLIENT_SECRET=secret")
+	require.NotContains(t, capturedCtx.EnvVars, "AZURE_ENV_NAME=test")
+	require.NotNil(t, capturedCtx.EnvIsolation)
+	require.True(t, capturedCtx.EnvIsolation.Allows("VITE_TITLE"))
+	require.False(t, capturedCtx.EnvIsolation.Allows("AZURE_CLIENT_SECRET"))
+}
+
+func Test_HookEnvIsolation(t *testing.T) {
+	require.Nil(t, hookEnvIsolation(&HookConfig{}))
+
+	isolation := hookEnvIsolation(&HookConfig{
+		EnvIsolation: &exec.EnvIsolation{Allow: []string{"API_URL"}},
+		Secrets: map[string]string{
+			"DB_PASSWORD": "DATABASE_PASSWORD",
+			"API_KEY":     "API_KEY_SECRET",
+		},
+	})
+	require.Equal(t, []string{"API_URL", "API_KEY", "DB_PASSWORD"}, isolation.Allow)
+
+	isolation = hookEnvIsolation(&HookConfig{
+		EnvIsolation: &exec.EnvIsolation{Allow: []string{"API_URL"}},
+		Inputs:       []string{"DB_HOST"},
+	})
+	require.Equal(t, []string{"API_URL", "DB_HOST"}, isolation.Allow)
+}
+
+func Test_Hooks_InputsOutputs(t *testing.T) {
+	cwd := t.TempDir()
+	ostest.Chdir(t, cwd)
+
+	newRunner := func(
+		t *testing.T, hookConfig *HookConfig, respond func(args exec.RunArgs) (exec.RunResult, error),
+	) (*HooksRunner, *environment.Environment, *mockenv.MockEnvManager, *bool) {
+		env := environment.NewWithValues("test", map[string]string{"DB_NAME": "todo"})
+		envManager := &mockenv.MockEnvManager{}
+		envManager.On("Reload", mock.Anything, env).Return(nil)
+		envManager.On("Save", mock.Anything, env).Return(nil)
+
+		hookConfig.Shell = string(language.HookKindBash)
+		hookConfig.Run = "scripts/predeploy.sh"
+		hooksMap := map[string][]*HookConfig{"predeploy": {hookConfig}}
+		ensureScriptsExist(t, hooksMap)
+
+		mockContext := mocks.NewMockContext(t.Context())
+		registerHookExecutors(mockContext)
+		ran := false
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "predeploy.sh")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			ran = true
+			return respond(args)
+		})
+
+		hooksManager := NewHooksManager(HooksManagerOptions{Cwd: cwd, ProjectDir: cwd}, mockContext.CommandRunner)
+		runner := NewHooksRunner(
+			hooksManager,
+			mockContext.CommandRunner,
+			envManager,
+			mockContext.Console,
+			cwd,
+			hooksMap,
+			env,
+			mockContext.Container,
+		)
+		return runner, env, envManager, &ran
+	}
+
+	t.Run("MissingInput", func(t *testing.T) {
+		runner, _, _, ran := newRunner(t, &HookConfig{Inputs: []string{"DB_NAME", "DB_HOST"}},
+			func(args exec.RunArgs) (exec.RunResult, error) {
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+		err := runner.RunHooks(t.Context(), HookTypePre, "project", nil, "deploy")
+		require.ErrorIs(t, err, ErrHookInputMissing)
+		require.ErrorContains(t, err, "requires DB_HOST")
+		require.False(t, *ran)
+	})
+
+	t.Run("InputsSet", func(t *testing.T) {
+		runner, _, _, ran := newRunner(t, &HookConfig{Inputs: []string{"DB_NAME"}},
+			func(args exec.RunArgs) (exec.RunResult, error) {
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+		require.NoError(t, runner.RunHooks(t.Context(), HookTypePre, "project", nil, "deploy"))
+		require.True(t, *ran)
+	})
+
+	t.Run("OutputFile", func(t *testing.T) {
+		runner, env, envManager, _ := newRunner(t, &HookConfig{Output: HookOutputFile},
+			func(args exec.RunArgs) (exec.RunResult, error) {
+				outputFile := envSliceToMap(args.Env)[HookEnvOutputFile]
+				err := os.WriteFile(outputFile, []byte(`{"DB_HOST": "db.local", "DB_PORT": 5432, "SKIPPED": null}`),
+					osutil.PermissionFile)
+				return exec.NewRunResult(0, "", ""), err
+			})
+
+		require.NoError(t, runner.RunHooks(t.Context(), HookTypePre, "project", nil, "deploy"))
+		require.Equal(t, "db.local", env.Getenv("DB_HOST"))
+		require.Equal(t, "5432", env.Getenv("DB_PORT"))
+		_, hasSkipped := env.LookupEnv("SKIPPED")
+		require.False(t, hasSkipped)
+		envManager.AssertCalled(t, "Save", mock.Anything, env)
+	})
+
+	t.Run("OutputStdout", func(t *testing.T) {
+		runner, env, _, _ := newRunner(t, &HookConfig{Output: HookOutputStdout},
+			func(args exec.RunArgs) (exec.RunResult, error) {
+				return exec.NewRunResult(0, `{"API_URL": "https://api.local"}`+"\n", ""), nil
+			})
+
+		require.NoError(t, runner.RunHooks(t.Context(), HookTypePre, "project", nil, "deploy"))
+		require.Equal(t, "https://api.local", env.Getenv("API_URL"))
+	})
+
+	t.Run("InvalidOutput", func(t *testing.T) {
+		runner, _, envManager, _ := newRunner(t, &HookConfig{Output: HookOutputStdout},
+			func(args exec.RunArgs) (exec.RunResult, error) {
+				return exec.NewRunResult(0, "seeded the database", ""), nil
+			})
+
+		err := runner.RunHooks(t.Context(), HookTypePre, "project", nil, "deploy")
+		require.ErrorIs(t, err, ErrInvalidHookOutput)
+		envManager.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("InvalidOutputKey", func(t *testing.T) {
+		for _, key := range []string{"", "API=URL", "API URL", "API_URL\nINJECTED", "\tAPI_URL"} {
+			stdout, err := json.Marshal(map[string]string{key: "https://api.local"})
+			require.NoError(t, err)
+
+			runner, _, envManager, _ := newRunner(t, &HookConfig{Output: HookOutputStdout},
+				func(args exec.RunArgs) (exec.RunResult, error) {
+					return exec.NewRunResult(0, string(stdout), ""), nil
+				})
+
+			err = runner.RunHooks(t.Context(), HookTypePre, "project", nil, "deploy")
+			require.ErrorIs(t, err, ErrInvalidHookOutput, "key %q", key)
+			require.ErrorContains(t, err, "isn't a valid environment variable name")
+			envManager.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		}
+	})
+}
+
+func Test_Hooks_Invoke_OnError(t *testing.T) {
+	cwd := t.TempDir()
+	ostest.Chdir(t, cwd)
+
+	env := environment.NewWithValues("test", map[string]string{"a": "apple"})
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Reload", mock.Anything, env).Return(nil)
+
+	hooksMap := map[string][]*HookConfig{
+		"precommand": {{Shell: string(language.HookKindBash), Run: "scripts/precommand.sh"}},
+		"onerror":    {{Shell: string(language.HookKindBash), Run: "scripts/onerror.sh"}},
+	}
+	ensureScriptsExist(t, hooksMap)
+
+	newRunner := func(t *testing.T, preHookErr error) (*HooksRunner, *[][]string) {
+		mockContext := mocks.NewMockContext(t.Context())
+		registerHookExecutors(mockContext)
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "precommand.sh")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			if preHookErr != nil {
+				return exec.NewRunResult(1, "", ""), preHookErr
+			}
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		errorHookEnvs := &[][]string{}
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "onerror.sh")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			*errorHookEnvs = append(*errorHookEnvs, args.Env)
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		hooksManager := NewHooksManager(HooksManagerOptions{Cwd: cwd, ProjectDir: cwd}, mockContext.CommandRunner)
+		runner := NewHooksRunner(
+			hooksManager,
+			mockContext.CommandRunner,
+			envManager,
+			mockContext.Console,
+			cwd,
+			hooksMap,
+			env,
+			mockContext.Container,
+		)
+		return runner, errorHookEnvs
+	}
+
+	t.Run("ActionFails", func(t *testing.T) {
+		runner, errorHookEnvs := newRunner(t, nil)
+		actionErr := errors.New("deployment failed")
+
+		err := runner.Invoke(t.Context(), []string{"azd command"}, "project", func() error {
+			return actionErr
+		})
+		require.ErrorIs(t, err, actionErr)
+		require.Equal(t, "deployment failed", err.Error())
+		require.Len(t, *errorHookEnvs, 1)
+		require.Contains(t, (*errorHookEnvs)[0], "a=apple")
+		require.Contains(t, (*errorHookEnvs)[0], "AZD_ERROR_COMMAND=command")
+		require.Contains(t, (*errorHookEnvs)[0], "AZD_ERROR_STAGE=command")
+		require.Contains(t, (*errorHookEnvs)[0], "AZD_ERROR_MESSAGE=deployment failed")
+	})
+
+	t.Run("PreHookFails", func(t *testing.T) {
+		runner, errorHookEnvs := newRunner(t, errors.New("exit code 1"))
+
+		actionRan := false
+		err := runner.Invoke(t.Context(), []string{"command"}, "project", func() error {
+			actionRan = true
+			return nil
+		})
+		require.Error(t, err)
+		require.False(t, actionRan)
+		require.Len(t, *errorHookEnvs, 1)
+		require.Contains(t, (*errorHookEnvs)[0], "AZD_ERROR_STAGE=precommand")
+	})
+
+	t.Run("RunsOnceForNestedCommands", func(t *testing.T) {
+		runner, errorHookEnvs := newRunner(t, nil)
+		actionErr := errors.New("provisioning failed")
+
+		err := runner.Invoke(t.Context(), []string{"up"}, "project", func() error {
+			return runner.Invoke(t.Context(), []string{"provision"}, "project", func() error {
+				return actionErr
+			})
+		})
+		require.ErrorIs(t, err, actionErr)
+		require.Len(t, *errorHookEnvs, 1)
+		require.Contains(t, (*errorHookEnvs)[0], "AZD_ERROR_COMMAND=provision")
+	})
+
+	t.Run("NoErrorHooks", func(t *testing.T) {
+		runner, errorHookEnvs := newRunner(t, nil)
+		runner.hooks = map[string][]*HookConfig{}
+		actionErr := errors.New("deployment failed")
+
+		err := runner.Invoke(t.Context(), []string{"command"}, "project", func() error {
+			return actionErr
+		})
+		require.Same(t, actionErr, err)
+		require.Empty(t, *errorHookEnvs)
+	})
+}
+
 // TestHooksRunner_Telemetry exercises the telemetry span creation and
 // attribute-setting code paths in execHook. The tests verify that the
 // telemetry instrumentation doesn't panic or error on success, error,
@@ -26,6 +26,7 @@ var knownHookNames = map[string]bool{
 	"postdeploy":    true,
 	"predown":       true,
 	"postdown":      true,
+	"onerror":       true,
 	"prepackage":    true,
 	"postpackage":   true,
 	"preprovision":  true,
@@ -17,6 +17,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/language"
 )
@@ -39,6 +40,37 @@ const (
 	HookPlatformPosix   HookPlatformType = "posix"
 )
 
+// OnErrorHookName is the name of the hook that runs when a command, or one of its pre or post hooks, fails.
+const OnErrorHookName = "onerror"
+
+// Environment variables set by azd for hooks, in addition to the values of the azd environment.
+const (
+	// Name of the service, set for service hooks.
+	HookEnvServiceName = "AZD_SERVICE_NAME"
+	// Name of the failed command, like `provision`, set for onerror hooks.
+	HookEnvErrorCommand = "AZD_ERROR_COMMAND"
+	// Stage of the command that failed, set for onerror hooks: the name of the failed pre or post hook, like
+	// `preprovision`, or the name of the command when the command itself failed.
+	HookEnvErrorStage = "AZD_ERROR_STAGE"
+	// Message of the error, set for onerror hooks.
+	HookEnvErrorMessage = "AZD_ERROR_MESSAGE"
+	// Path of the file the hook writes its outputs to, set for hooks with `output: file`.
+	HookEnvOutputFile = "AZD_HOOK_OUTPUT"
+)
+
+// HookOutput identifies where a hook writes its outputs, a JSON object whose values are merged into the azd
+// environment once the hook succeeds.
+type HookOutput string
+
+const (
+	// The hook has no outputs.
+	HookOutputNone HookOutput = ""
+	// The hook writes its outputs to the file named by AZD_HOOK_OUTPUT.
+	HookOutputFile HookOutput = "file"
+	// The hook prints its outputs on stdout.
+	HookOutputStdout HookOutput = "stdout"
+)
+
 var (
 	// ErrScriptTypeUnknown indicates the hook kind could not be inferred
 	// from the script path and was not explicitly configured.
@@ -61,6 +93,11 @@ var (
 			"Alternatively, set 'kind' (e.g. kind: python) " +
 			"or 'shell' (e.g. shell: sh)",
 	)
+	// ErrHookInputMissing indicates an input declared by the hook has no value in the environment of the hook.
+	ErrHookInputMissing error = errors.New("hook input missing")
+	// ErrInvalidHookOutput indicates the output of a hook is not a JSON object, or has a key that isn't a valid
+	// environment variable name.
+	ErrInvalidHookOutput error = errors.New("invalid hook output")
 )
 
 // Generic action function that may return an error
@@ -133,10 +170,19 @@ type HookConfig struct {
 	// Environment variables in this list are added to the hook script and if the value is a akvs:// reference
 	// it will be resolved to the secret value
 	Secrets map[string]string `yaml:"secrets,omitempty"`
+	// When set, the hook only receives the environment variables named in the allow-list (plus any secrets declared
+	// above) instead of the full azd process and azd environment values.
+	EnvIsolation *exec.EnvIsolation `yaml:"envIsolation,omitempty"`
 	// Config is an optional property bag of executor-specific settings,
 	// discriminated by Kind. Each executor may unmarshal this into a
 	// strongly-typed struct for its own configuration needs.
 	Config map[string]any `yaml:"config,omitempty"`
+	// Inputs lists the environment variables the hook requires. The hook fails before it runs when one of them is
+	// missing or empty.
+	Inputs []string `yaml:"inputs,omitempty"`
+	// Output specifies where the hook writes its outputs: "file" or "stdout". The outputs are a JSON object whose
+	// values are set in the azd environment, so they are available to later hooks and commands.
+	Output HookOutput `yaml:"output,omitempty"`
 }
 
 // validate normalizes and validates the hook configuration. It resolves
@@ -180,6 +226,9 @@ func (hc *HookConfig) validate() error {
 	if err := hc.resolveKind(dirExplicit); err != nil {
 		return err
 	}
+	if err := hc.validateOutput(); err != nil {
+		return err
+	}
 	if err := hc.resolvePaths(dirExplicit); err != nil {
 		return err
 	}
@@ -330,6 +379,37 @@ func (hc *HookConfig) resolveKind(dirExplicit bool) error {
 	return nil
 }
 
+// validateOutput rejects outputs that the hook can't return: the output file of docker hooks is on the host,
+// outside of the container, and the stdout of interactive hooks is bound to the console instead of captured.
+func (hc *HookConfig) validateOutput() error {
+	switch hc.Output {
+	case HookOutputNone:
+		return nil
+	case HookOutputFile:
+		if hc.Kind == language.HookKindDocker {
+			return fmt.Errorf(
+				"output '%s' is not supported for %s hooks. Set 'output: %s' and print the outputs instead",
+				HookOutputFile, language.HookKindDocker, HookOutputStdout,
+			)
+		}
+	case HookOutputStdout:
+		if hc.Interactive {
+			return fmt.Errorf(
+				"output '%s' is not supported for interactive hooks. Set 'output: %s' and write the outputs "+
+					"to the file named by %s instead",
+				HookOutputStdout, HookOutputFile, HookEnvOutputFile,
+			)
+		}
+	default:
+		return fmt.Errorf(
+			"output '%s' is not valid. Supported values: %s, %s",
+			hc.Output, HookOutputFile, HookOutputStdout,
+		)
+	}
+
+	return nil
+}
+
 // resolvePaths computes absolute resolvedDir and resolvedScriptPath
 // from the hook's Dir, relativeScriptPath, and inputCwd fields.
 // After this method, resolvedDir and resolvedScriptPath are the
@@ -590,6 +670,23 @@ func appendHookConfigSignature(builder *strings.Builder, hookConfig *HookConfig)
 		builder.WriteByte('\x00')
 	}
 
+	if len(hookConfig.Inputs) > 0 {
+		builder.WriteString("inputs=")
+		builder.WriteString(strings.Join(hookConfig.Inputs, ","))
+		builder.WriteByte('\x00')
+	}
+	if hookConfig.Output != HookOutputNone {
+		builder.WriteString("output=")
+		builder.WriteString(string(hookConfig.Output))
+		builder.WriteByte('\x00')
+	}
+
+	if hookConfig.EnvIsolation != nil {
+		builder.WriteString("envIsolation=")
+		builder.WriteString(strings.Join(hookConfig.EnvIsolation.Allow, ","))
+		builder.WriteByte('\x00')
+	}
+
 	// Config is a map[string]any — use JSON for deterministic key ordering.
 	if len(hookConfig.Config) > 0 {
 		configJSON, err := json.Marshal(hookConfig.Config)
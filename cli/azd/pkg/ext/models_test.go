@@ -121,6 +121,7 @@ func TestHookKind_Constants(t *testing.T) {
 		{"TypeScript", language.HookKindTypeScript, "ts"},
 		{"Python", language.HookKindPython, "python"},
 		{"DotNet", language.HookKindDotNet, "dotnet"},
+		{"Docker", language.HookKindDocker, "docker"},
 	}
 
 	for _, tt := range tests {
@@ -171,6 +172,17 @@ func TestHookConfig_ValidateKindResolution(t *testing.T) {
 			expectedKind: language.HookKindBash,
 			isShell:      true,
 		},
+		{
+			name: "ShellAliasDockerAllowsInline",
+			config: HookConfig{
+				Name:   "test",
+				Shell:  string(language.HookKindDocker),
+				Run:    "az account show",
+				Config: map[string]any{"image": "mcr.microsoft.com/azure-cli"},
+			},
+			expectedKind: language.HookKindDocker,
+			isShell:      true,
+		},
 		{
 			name: "InferPythonFromExtension",
 			config: HookConfig{
@@ -574,6 +586,7 @@ func TestHookKind_IsShell(t *testing.T) {
 		{"DotNet", language.HookKindDotNet, false},
 		{"Bash", language.HookKindBash, true},
 		{"PowerShell", language.HookKindPowerShell, true},
+		{"Docker", language.HookKindDocker, true},
 		{"Unknown", language.HookKindUnknown, false},
 	}
 
@@ -1493,3 +1506,45 @@ func TestHooksConfigSignature_IncludesConfig(t *testing.T) {
 		"signature should differ for different Config values",
 	)
 }
+
+func TestHookConfig_InputsOutput(t *testing.T) {
+	var config HookConfig
+	err := yaml.Unmarshal([]byte("run: echo hi\nshell: sh\ninputs: [DB_HOST, DB_NAME]\noutput: stdout\n"), &config)
+	require.NoError(t, err)
+	require.Equal(t, []string{"DB_HOST", "DB_NAME"}, config.Inputs)
+	require.Equal(t, HookOutputStdout, config.Output)
+	require.NoError(t, config.validate())
+
+	invalid := HookConfig{Name: "test", Shell: "sh", Run: "echo hi", Output: "env"}
+	require.ErrorContains(t, invalid.validate(), "output 'env' is not valid")
+
+	dockerFile := HookConfig{
+		Name:   "test",
+		Kind:   language.HookKindDocker,
+		Run:    "az account show",
+		Config: map[string]any{"image": "mcr.microsoft.com/azure-cli"},
+		Output: HookOutputFile,
+	}
+	require.ErrorContains(t, dockerFile.validate(), "output 'file' is not supported for docker hooks")
+
+	dockerStdout := HookConfig{
+		Name:   "test",
+		Kind:   language.HookKindDocker,
+		Run:    "az account show",
+		Config: map[string]any{"image": "mcr.microsoft.com/azure-cli"},
+		Output: HookOutputStdout,
+	}
+	require.NoError(t, dockerStdout.validate())
+
+	interactiveStdout := HookConfig{Name: "test", Shell: "sh", Run: "echo hi", Interactive: true, Output: HookOutputStdout}
+	require.ErrorContains(t, interactiveStdout.validate(), "output 'stdout' is not supported for interactive hooks")
+
+	interactiveFile := HookConfig{Name: "test", Shell: "sh", Run: "echo hi", Interactive: true, Output: HookOutputFile}
+	require.NoError(t, interactiveFile.validate())
+
+	base := map[string][]*HookConfig{"predeploy": {{Run: "echo hi", Shell: "sh"}}}
+	withInputs := map[string][]*HookConfig{"predeploy": {{Run: "echo hi", Shell: "sh", Inputs: []string{"DB_HOST"}}}}
+	withOutput := map[string][]*HookConfig{"predeploy": {{Run: "echo hi", Shell: "sh", Output: HookOutputFile}}}
+	require.NotEqual(t, HooksConfigSignature(base), HooksConfigSignature(withInputs))
+	require.NotEqual(t, HooksConfigSignature(base), HooksConfigSignature(withOutput))
+}
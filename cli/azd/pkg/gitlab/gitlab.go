@@ -0,0 +1,247 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package gitlab provides a minimal client for the GitLab REST API.
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+const (
+	// DefaultHost is the host of GitLab.com, used when GITLAB_HOST isn't set.
+	DefaultHost = "gitlab.com"
+	// HostEnvVarName is the environment variable that sets the host of a self-managed GitLab instance.
+	HostEnvVarName = "GITLAB_HOST"
+	// TokenEnvVarName is the environment variable that holds the GitLab personal access token.
+	TokenEnvVarName = "GITLAB_TOKEN"
+)
+
+// ErrNotFound is returned when a GitLab resource doesn't exist, or isn't visible to the token.
+var ErrNotFound = errors.New("not found")
+
+// Client calls the GitLab REST API (v4) of a GitLab host with a personal access token.
+type Client struct {
+	baseUrl     string
+	token       string
+	transporter policy.Transporter
+}
+
+// NewClient creates a client for the GitLab instance at host, like gitlab.com.
+func NewClient(host string, token string, transporter policy.Transporter) *Client {
+	return &Client{
+		baseUrl:     fmt.Sprintf("https://%s/api/v4", host),
+		token:       token,
+		transporter: transporter,
+	}
+}
+
+// User is a GitLab user.
+type User struct {
+	Id       int    `json:"id"`
+	Username string `json:"username"`
+}
+
+// Project is a GitLab project.
+type Project struct {
+	Id                int    `json:"id"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	WebUrl            string `json:"web_url"`
+	HttpUrlToRepo     string `json:"http_url_to_repo"`
+	DefaultBranch     string `json:"default_branch"`
+	// BuildsAccessLevel is `disabled` when CI/CD is disabled for the project.
+	BuildsAccessLevel string `json:"builds_access_level"`
+}
+
+// Variable is a CI/CD variable of a project.
+type Variable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	// Masked hides the value in job logs. GitLab only accepts masking values of 8 characters or more, see
+	// [CanMask].
+	Masked bool `json:"masked"`
+	// Raw disables the expansion of variable references like `$OTHER` in the value.
+	Raw bool `json:"raw"`
+}
+
+// Pipeline is a CI/CD pipeline run of a project.
+type Pipeline struct {
+	Id     int    `json:"id"`
+	Status string `json:"status"`
+	Ref    string `json:"ref"`
+	Sha    string `json:"sha"`
+	WebUrl string `json:"web_url"`
+}
+
+// maskableValueRegex matches the values GitLab accepts to mask: a single line of 8 characters or more from the
+// Base64 alphabet (RFC4648), `@`, `:`, `.` and `~`.
+var maskableValueRegex = regexp.MustCompile(`^[A-Za-z0-9+/=@:.~_-]{8,}$`)
+
+// CanMask returns whether GitLab accepts to mask the value of a variable.
+func CanMask(value string) bool {
+	return maskableValueRegex.MatchString(value)
+}
+
+// CurrentUser returns the user the token belongs to.
+func (c *Client) CurrentUser(ctx context.Context) (*User, error) {
+	var user User
+	if err := c.send(ctx, http.MethodGet, "/user", nil, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// GetProject returns the project at the path, like `group/subgroup/project`.
+func (c *Client) GetProject(ctx context.Context, path string) (*Project, error) {
+	var project Project
+	if err := c.send(ctx, http.MethodGet, "/projects/"+url.PathEscape(path), nil, &project); err != nil {
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+// CreateProject creates a private project named name in the personal namespace of the user.
+func (c *Client) CreateProject(ctx context.Context, name string) (*Project, error) {
+	var project Project
+	body := map[string]string{
+		"name":       name,
+		"visibility": "private",
+	}
+	if err := c.send(ctx, http.MethodPost, "/projects", body, &project); err != nil {
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+// SetVariable creates or updates a CI/CD variable of the project.
+func (c *Client) SetVariable(ctx context.Context, projectId int, variable Variable) error {
+	path := fmt.Sprintf("/projects/%d/variables/%s", projectId, url.PathEscape(variable.Key))
+	err := c.send(ctx, http.MethodPut, path, variable, nil)
+	if errors.Is(err, ErrNotFound) {
+		err = c.send(ctx, http.MethodPost, fmt.Sprintf("/projects/%d/variables", projectId), variable, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("setting variable %s: %w", variable.Key, err)
+	}
+
+	return nil
+}
+
+// ListPipelines lists the most recent pipelines of the project for the ref and commit sha.
+func (c *Client) ListPipelines(ctx context.Context, projectId int, ref string, sha string) ([]Pipeline, error) {
+	query := url.Values{}
+	query.Set("ref", ref)
+	query.Set("sha", sha)
+
+	var pipelines []Pipeline
+	path := fmt.Sprintf("/projects/%d/pipelines?%s", projectId, query.Encode())
+	if err := c.send(ctx, http.MethodGet, path, nil, &pipelines); err != nil {
+		return nil, err
+	}
+
+	return pipelines, nil
+}
+
+// send sends a request with a JSON body, when set, and decodes the JSON response into result, when set.
+func (c *Client) send(ctx context.Context, method string, path string, body any, result any) error {
+	var reader io.Reader
+	if body != nil {
+		content, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(content)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseUrl+path, reader)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("PRIVATE-TOKEN", c.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.transporter.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s %s: %w", method, strings.SplitN(path, "?", 2)[0], ErrNotFound)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		content, _ := io.ReadAll(res.Body)
+		return &ResponseError{StatusCode: res.StatusCode, Message: errorMessage(content)}
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return json.NewDecoder(res.Body).Decode(result)
+}
+
+// ResponseError is returned when GitLab responds with an error.
+type ResponseError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *ResponseError) Error() string {
+	switch e.StatusCode {
+	case http.StatusUnauthorized:
+		return "the GitLab token is invalid or expired (401)"
+	case http.StatusForbidden:
+		return "the GitLab token isn't allowed to perform this operation, it needs the 'api' scope and at least the " +
+			"Maintainer role on the project (403)"
+	}
+
+	if e.Message == "" {
+		return "GitLab responded with status code " + strconv.Itoa(e.StatusCode)
+	}
+
+	return fmt.Sprintf("GitLab responded with status code %d: %s", e.StatusCode, e.Message)
+}
+
+// errorMessage extracts the message of a GitLab error response, which is either {"message": ...} or {"error": ...}.
+func errorMessage(content []byte) string {
+	var response struct {
+		Message any    `json:"message"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal(content, &response); err != nil {
+		return strings.TrimSpace(string(content))
+	}
+
+	if response.Message != nil {
+		if message, isString := response.Message.(string); isString {
+			return message
+		}
+
+		// Validation errors are objects, like {"name": ["has already been taken"]}
+		formatted, _ := json.Marshal(response.Message)
+		return string(formatted)
+	}
+
+	return response.Error
+}
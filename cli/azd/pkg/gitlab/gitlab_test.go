@@ -0,0 +1,104 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package gitlab
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockhttp"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CanMask(t *testing.T) {
+	require.True(t, CanMask("abcdefgh"))
+	require.True(t, CanMask("a1b2C3+/=@:.~_-"))
+	require.False(t, CanMask("short"))
+	require.False(t, CanMask("has spaces in it"))
+	require.False(t, CanMask("line1234\nline5678"))
+}
+
+func Test_Client_SetVariable(t *testing.T) {
+	t.Run("Update", func(t *testing.T) {
+		httpClient := mockhttp.NewMockHttpUtil()
+		httpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPut &&
+				request.URL.Path == "/api/v4/projects/42/variables/AZURE_CLIENT_ID"
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			require.Equal(t, "token", request.Header.Get("PRIVATE-TOKEN"))
+
+			var variable Variable
+			require.NoError(t, json.NewDecoder(request.Body).Decode(&variable))
+			require.Equal(t, Variable{Key: "AZURE_CLIENT_ID", Value: "id", Raw: true}, variable)
+
+			return jsonResponse(request, http.StatusOK, "{}"), nil
+		})
+
+		client := NewClient(DefaultHost, "token", httpClient)
+		err := client.SetVariable(t.Context(), 42, Variable{Key: "AZURE_CLIENT_ID", Value: "id", Raw: true})
+		require.NoError(t, err)
+	})
+
+	t.Run("Create", func(t *testing.T) {
+		created := false
+		httpClient := mockhttp.NewMockHttpUtil()
+		httpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPut
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return jsonResponse(request, http.StatusNotFound, `{"message":"404 Variable Not Found"}`), nil
+		})
+		httpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost && request.URL.Path == "/api/v4/projects/42/variables"
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			created = true
+			return jsonResponse(request, http.StatusCreated, "{}"), nil
+		})
+
+		client := NewClient(DefaultHost, "token", httpClient)
+		err := client.SetVariable(t.Context(), 42, Variable{Key: "AZURE_CLIENT_ID", Value: "id"})
+		require.NoError(t, err)
+		require.True(t, created)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		httpClient := mockhttp.NewMockHttpUtil()
+		httpClient.When(func(request *http.Request) bool {
+			return true
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return jsonResponse(request, http.StatusBadRequest, `{"message":{"value":["is invalid"]}}`), nil
+		})
+
+		client := NewClient(DefaultHost, "token", httpClient)
+		err := client.SetVariable(t.Context(), 42, Variable{Key: "KEY", Value: "value", Masked: true})
+		require.EqualError(t, err, `setting variable KEY: GitLab responded with status code 400: {"value":["is invalid"]}`)
+	})
+}
+
+func Test_Client_Unauthorized(t *testing.T) {
+	httpClient := mockhttp.NewMockHttpUtil()
+	httpClient.When(func(request *http.Request) bool {
+		return request.URL.Path == "/api/v4/user"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return jsonResponse(request, http.StatusUnauthorized, `{"message":"401 Unauthorized"}`), nil
+	})
+
+	_, err := NewClient("gitlab.contoso.com", "token", httpClient).CurrentUser(t.Context())
+
+	var responseErr *ResponseError
+	require.ErrorAs(t, err, &responseErr)
+	require.Equal(t, http.StatusUnauthorized, responseErr.StatusCode)
+	require.Contains(t, err.Error(), "invalid or expired")
+}
+
+func jsonResponse(request *http.Request, statusCode int, body string) *http.Response {
+	return &http.Response{
+		Request:    request,
+		StatusCode: statusCode,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
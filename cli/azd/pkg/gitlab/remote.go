@@ -0,0 +1,41 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package gitlab
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var ErrRemoteHostIsNotGitLab = errors.New("not a gitlab host")
+
+// gitLabRemoteGitUrlRegex matches scp-like ssh remotes, like git@gitlab.com:group/project.git
+var gitLabRemoteGitUrlRegex = regexp.MustCompile(`^[A-Za-z0-9._-]+@([A-Za-z0-9.-]+):(.*?)(?:\.git)?/?$`)
+
+// GetProjectPathForRemote returns the path of the project (`<group>/<subgroup>/<project>`) of a remote hosted on the
+// GitLab instance at host. Both https and ssh remotes are supported.
+func GetProjectPathForRemote(remoteUrl string, host string) (string, error) {
+	var remoteHost, path string
+	if captures := gitLabRemoteGitUrlRegex.FindStringSubmatch(remoteUrl); captures != nil {
+		remoteHost, path = captures[1], captures[2]
+	} else if parsed, err := url.Parse(remoteUrl); err == nil &&
+		(parsed.Scheme == "https" || parsed.Scheme == "ssh") {
+		remoteHost = parsed.Hostname()
+		path = strings.TrimSuffix(strings.Trim(parsed.Path, "/"), ".git")
+	}
+
+	if !strings.EqualFold(remoteHost, host) {
+		return "", ErrRemoteHostIsNotGitLab
+	}
+
+	// A project is always in a namespace (a user or a group).
+	if segments := strings.Split(path, "/"); len(segments) < 2 || slices.Contains(segments, "") {
+		return "", ErrRemoteHostIsNotGitLab
+	}
+
+	return path, nil
+}
@@ -0,0 +1,42 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetProjectPathForRemote(t *testing.T) {
+	cases := []struct {
+		remote  string
+		host    string
+		result  string
+		isError bool
+	}{
+		{remote: "git@gitlab.com:foo/bar.git", host: DefaultHost, result: "foo/bar"},
+		{remote: "https://gitlab.com/foo/bar.git", host: DefaultHost, result: "foo/bar"},
+		{remote: "https://gitlab.com/foo/sub/bar", host: DefaultHost, result: "foo/sub/bar"},
+		{remote: "ssh://git@gitlab.com/foo/bar.git", host: DefaultHost, result: "foo/bar"},
+		{remote: "https://GitLab.contoso.com/foo/bar/", host: "gitlab.contoso.com", result: "foo/bar"},
+
+		{remote: "https://github.com/foo/bar.git", host: DefaultHost, isError: true},
+		{remote: "https://gitlab.com/foo/bar.git", host: "gitlab.contoso.com", isError: true},
+		{remote: "https://gitlab.com/bar.git", host: DefaultHost, isError: true},
+		{remote: "not-a-remote", host: DefaultHost, isError: true},
+		{remote: "", host: DefaultHost, isError: true},
+	}
+
+	for _, tst := range cases {
+		path, err := GetProjectPathForRemote(tst.remote, tst.host)
+
+		if tst.isError {
+			require.ErrorIs(t, err, ErrRemoteHostIsNotGitLab, "expected error for %s", tst.remote)
+		} else {
+			require.NoError(t, err, "expected no error for %s", tst.remote)
+			require.Equal(t, tst.result, path)
+		}
+	}
+}
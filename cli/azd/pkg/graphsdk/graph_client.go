@@ -6,6 +6,7 @@ package graphsdk
 import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 )
 
 type GraphClient struct {
@@ -22,11 +23,17 @@ func NewGraphClient(
 		options = &azcore.ClientOptions{}
 	}
 
-	pipeline := NewPipeline(credential, ServiceConfig, options)
+	// Microsoft Graph has a different endpoint in sovereign clouds. Public cloud is the default.
+	serviceConfig := ServiceConfig
+	if cloudServiceConfig, has := options.Cloud.Services[cloud.GraphService]; has {
+		serviceConfig = cloudServiceConfig
+	}
+
+	pipeline := NewPipeline(credential, serviceConfig, options)
 
 	return &GraphClient{
 		pipeline: pipeline,
-		host:     ServiceConfig.Endpoint,
+		host:     serviceConfig.Endpoint,
 	}, nil
 }
 
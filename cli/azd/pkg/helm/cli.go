@@ -6,13 +6,19 @@ package helm
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 )
 
+// ErrReleaseNotFound is returned by Status when the release isn't installed
+var ErrReleaseNotFound = errors.New("release not found")
+
 type Cli struct {
 	commandRunner exec.CommandRunner
 }
@@ -100,6 +106,10 @@ func (c *Cli) Upgrade(ctx context.Context, release *Release) error {
 		runArgs = runArgs.AppendParams("--values", release.Values)
 	}
 
+	for _, valuesFile := range release.ValuesFiles {
+		runArgs = runArgs.AppendParams("--values", valuesFile)
+	}
+
 	if release.Namespace != "" {
 		runArgs = runArgs.AppendParams(
 			"--namespace", release.Namespace,
@@ -124,6 +134,10 @@ func (c *Cli) Status(ctx context.Context, release *Release) (*StatusResult, erro
 
 	runResult, err := c.commandRunner.Run(ctx, runArgs)
 	if err != nil {
+		if strings.Contains(runResult.Stderr, "release: not found") || strings.Contains(err.Error(), "release: not found") {
+			return nil, fmt.Errorf("helm release %s: %w", release.Name, ErrReleaseNotFound)
+		}
+
 		return nil, fmt.Errorf("failed to query status for helm chart %s: %w", release.Chart, err)
 	}
 
@@ -135,6 +149,36 @@ func (c *Cli) Status(ctx context.Context, release *Release) (*StatusResult, erro
 	return result, nil
 }
 
+// Rollback rolls back a helm release to the specified revision
+func (c *Cli) Rollback(ctx context.Context, release *Release, revision int) error {
+	runArgs := exec.NewRunArgs("helm", "rollback", release.Name, strconv.Itoa(revision), "--wait")
+	if release.Namespace != "" {
+		runArgs = runArgs.AppendParams("--namespace", release.Namespace)
+	}
+
+	_, err := c.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed to roll back helm release %s: %w", release.Name, err)
+	}
+
+	return nil
+}
+
+// Uninstall removes a helm release and the resources it installed
+func (c *Cli) Uninstall(ctx context.Context, release *Release) error {
+	runArgs := exec.NewRunArgs("helm", "uninstall", release.Name, "--wait")
+	if release.Namespace != "" {
+		runArgs = runArgs.AppendParams("--namespace", release.Namespace)
+	}
+
+	_, err := c.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed to uninstall helm release %s: %w", release.Name, err)
+	}
+
+	return nil
+}
+
 func (cli *Cli) getClientVersion(ctx context.Context) (string, error) {
 	runArgs := exec.NewRunArgs("helm", "version", "--template", "{{.Version}}")
 	versionResult, err := cli.commandRunner.Run(ctx, runArgs)
@@ -282,6 +282,40 @@ func Test_Cli_Upgrade(t *testing.T) {
 		}, runArgs.Args)
 	})
 
+	t.Run("WithValuesFiles", func(t *testing.T) {
+		var runArgs exec.RunArgs
+
+		releaseWithValues := *release
+		releaseWithValues.Values = "values.yaml"
+		releaseWithValues.ValuesFiles = []string{"values.prod.yaml"}
+
+		mockContext := mocks.NewMockContext(t.Context())
+		mockContext.CommandRunner.
+			When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "helm upgrade")
+			}).
+			RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				runArgs = args
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+		cli := NewCli(mockContext.CommandRunner)
+		err := cli.Upgrade(*mockContext.Context, &releaseWithValues)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{
+			"upgrade",
+			"test",
+			"test/chart",
+			"--install",
+			"--wait",
+			"--values",
+			"values.yaml",
+			"--values",
+			"values.prod.yaml",
+		}, runArgs.Args)
+	})
+
 	t.Run("WithVersion", func(t *testing.T) {
 		ran := false
 		var runArgs exec.RunArgs
@@ -473,4 +507,103 @@ func Test_Cli_Status(t *testing.T) {
 		require.Error(t, err)
 		require.ErrorContains(t, err, "failed to get status")
 	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		mockContext.CommandRunner.
+			When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "helm status")
+			}).
+			RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				return exec.NewRunResult(1, "", "Error: release: not found"), errors.New("exit code: 1")
+			})
+
+		cli := NewCli(mockContext.CommandRunner)
+		_, err := cli.Status(*mockContext.Context, release)
+
+		require.ErrorIs(t, err, ErrReleaseNotFound)
+	})
+}
+
+func Test_Cli_Rollback(t *testing.T) {
+	release := &Release{
+		Name:      "test",
+		Namespace: "test-namespace",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		var runArgs exec.RunArgs
+
+		mockContext := mocks.NewMockContext(t.Context())
+		mockContext.CommandRunner.
+			When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "helm rollback")
+			}).
+			RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				runArgs = args
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+		cli := NewCli(mockContext.CommandRunner)
+		err := cli.Rollback(*mockContext.Context, release, 3)
+		require.NoError(t, err)
+
+		require.Equal(t, "helm", runArgs.Cmd)
+		require.Equal(t, []string{
+			"rollback",
+			"test",
+			"3",
+			"--wait",
+			"--namespace",
+			"test-namespace",
+		}, runArgs.Args)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		mockContext.CommandRunner.
+			When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "helm rollback")
+			}).
+			RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				return exec.NewRunResult(1, "", ""), errors.New("release has no 3 version")
+			})
+
+		cli := NewCli(mockContext.CommandRunner)
+		err := cli.Rollback(*mockContext.Context, release, 3)
+
+		require.ErrorContains(t, err, "failed to roll back helm release test")
+	})
+}
+
+func Test_Cli_Uninstall(t *testing.T) {
+	release := &Release{
+		Name:      "test",
+		Namespace: "test-namespace",
+	}
+
+	var runArgs exec.RunArgs
+
+	mockContext := mocks.NewMockContext(t.Context())
+	mockContext.CommandRunner.
+		When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "helm uninstall")
+		}).
+		RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			runArgs = args
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+	cli := NewCli(mockContext.CommandRunner)
+	err := cli.Uninstall(*mockContext.Context, release)
+	require.NoError(t, err)
+
+	require.Equal(t, "helm", runArgs.Cmd)
+	require.Equal(t, []string{
+		"uninstall",
+		"test",
+		"--wait",
+		"--namespace",
+		"test-namespace",
+	}, runArgs.Args)
 }
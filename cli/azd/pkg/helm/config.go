@@ -19,4 +19,6 @@ type Release struct {
 	Version   string `yaml:"version"`
 	Namespace string `yaml:"namespace"`
 	Values    string `yaml:"values"`
+	// ValuesFiles are additional values files, applied in order after Values
+	ValuesFiles []string `yaml:"valuesFiles,omitempty"`
 }
@@ -25,7 +25,6 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
-	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/cognitiveservices/armcognitiveservices/v2"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/drone/envsubst"
 
@@ -54,6 +53,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/password"
 	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
+	"github.com/azure/azure-dev/cli/azd/pkg/redact"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/bicep"
 	"golang.org/x/sync/errgroup"
 )
@@ -276,6 +276,7 @@ type BicepProvider struct {
 	curPrincipal        provisioning.CurrentPrincipalIdProvider
 	portalUrlBase       string
 	keyvaultService     keyvault.KeyVaultService
+	purgeService        *azapi.PurgeService
 	subscriptionManager *account.SubscriptionsManager
 	aiModelService      *ai.AiModelService
 	serviceLocator      ioc.ServiceLocator
@@ -557,7 +558,7 @@ func (p *BicepProvider) State(ctx context.Context, options *provisioning.StateOp
 		Message: fmt.Sprintf("Retrieving Azure deployment (%s)", output.WithHighLightFormat(deployment.Name)),
 	})
 
-	state := provisioning.State{}
+	state := provisioning.State{DeployedAt: deployment.Timestamp}
 	state.Resources = make([]provisioning.Resource, len(deployment.Resources))
 
 	for idx, res := range deployment.Resources {
@@ -571,6 +572,13 @@ func (p *BicepProvider) State(ctx context.Context, options *provisioning.StateOp
 		azapi.CreateDeploymentOutput(deployment.Outputs),
 	)
 
+	// A state retrieved to check the environment doesn't update it.
+	if options.Check() {
+		return &provisioning.StateResult{
+			State: &state,
+		}, nil
+	}
+
 	p.console.MessageUxItem(ctx, &ux.DoneMessage{
 		Message: fmt.Sprintf("Updated %d environment variables", len(state.Outputs)),
 	})
@@ -941,6 +949,11 @@ func (p *BicepProvider) Deploy(ctx context.Context) (*provisioning.DeployResult,
 		p.console.StopSpinner(ctx, "", input.StepDone)
 	}
 
+	deploymentTimeout, err := p.options.Timeout()
+	if err != nil {
+		return nil, err
+	}
+
 	progressCtx, cancelProgress := context.WithCancel(ctx)
 	var wg sync.WaitGroup
 	wg.Add(1)
@@ -952,6 +965,37 @@ func (p *BicepProvider) Deploy(ctx context.Context) (*provisioning.DeployResult,
 		p.console.StopSpinner(ctx, "", input.StepDone)
 	}()
 
+	// Start the deployment
+	p.console.ShowSpinner(ctx, "Creating/Updating resources", input.Step)
+
+	// If AZD_DEPLOYMENT_ID_FILE is set, expose the ARM deployment ID to the caller now
+	// that we are actually about to start the ARM deployment. Doing this here (rather
+	// than immediately after generating the deployment object) avoids advertising a
+	// deployment ID that never exists in Azure when the run short-circuits via the
+	// deployment-state cache or is canceled by provision validation.
+	writeDeploymentIdFile(deployment, p.layer)
+
+	deployCtx, interruptStarted, interruptCh, markDeployCompleted, interruptCleanup, abortDeployment :=
+		p.installDeploymentInterruptHandler(ctx, deployment, cancelProgress)
+	cleanupOnce := sync.OnceFunc(interruptCleanup)
+	defer cleanupOnce()
+
+	progressDisplay := p.deploymentManager.ProgressDisplay(deployment)
+	queryStartTime := time.Now()
+
+	// reportMu keeps the prompts of the watchdog from being interleaved with the progress display.
+	var reportMu sync.Mutex
+	var running []*armresources.DeploymentOperation
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		p.runDeploymentWatchdog(
+			ctx, progressCtx, newDeploymentWatchdog(queryStartTime, deploymentTimeout), deployment, &reportMu,
+			func() []*armresources.DeploymentOperation { return running },
+			abortDeployment)
+	}()
+
 	go func() {
 		defer wg.Done()
 		// Disable reporting progress if needed
@@ -962,9 +1006,6 @@ func (p *BicepProvider) Deploy(ctx context.Context) (*provisioning.DeployResult,
 		}
 
 		// Report incremental progress
-		progressDisplay := p.deploymentManager.ProgressDisplay(deployment)
-		queryStartTime := time.Now()
-
 		poller := newAdaptivePoller()
 		delay := poller.minInterval
 
@@ -976,7 +1017,15 @@ func (p *BicepProvider) Deploy(ctx context.Context) (*provisioning.DeployResult,
 				timer.Stop()
 				return
 			case <-timer.C:
+				reportMu.Lock()
 				err := progressDisplay.ReportProgress(progressCtx, &queryStartTime)
+				// Operations of a failed report are unknown, rather than still running.
+				running = nil
+				if err == nil {
+					running = progressDisplay.RunningOperations()
+				}
+				reportMu.Unlock()
+
 				if err != nil {
 					// We don't want to fail the whole deployment if a progress reporting error occurs
 					log.Printf("error while reporting progress: %v", err)
@@ -1004,21 +1053,6 @@ func (p *BicepProvider) Deploy(ctx context.Context) (*provisioning.DeployResult,
 		}
 	}()
 
-	// Start the deployment
-	p.console.ShowSpinner(ctx, "Creating/Updating resources", input.Step)
-
-	// If AZD_DEPLOYMENT_ID_FILE is set, expose the ARM deployment ID to the caller now
-	// that we are actually about to start the ARM deployment. Doing this here (rather
-	// than immediately after generating the deployment object) avoids advertising a
-	// deployment ID that never exists in Azure when the run short-circuits via the
-	// deployment-state cache or is canceled by provision validation.
-	writeDeploymentIdFile(deployment, p.layer)
-
-	deployCtx, interruptStarted, interruptCh, markDeployCompleted, interruptCleanup :=
-		p.installDeploymentInterruptHandler(ctx, deployment, cancelProgress)
-	cleanupOnce := sync.OnceFunc(interruptCleanup)
-	defer cleanupOnce()
-
 	deployResult, err := p.deployModule(
 		deployCtx,
 		deployment,
@@ -1059,11 +1093,51 @@ func (p *BicepProvider) Deploy(ctx context.Context) (*provisioning.DeployResult,
 		azapi.CreateDeploymentOutput(deployResult.Outputs),
 	)
 
+	var timings []provisioning.ResourceTiming
+	if p.options.CollectTimings {
+		// Stop polling before reading the operations recorded by the progress display.
+		cancelProgress()
+		wg.Wait()
+
+		// Timings are informational, a failure to read them doesn't fail the deployment.
+		if err := progressDisplay.RefreshCompletedOperations(ctx, &queryStartTime); err != nil {
+			log.Printf("failed to refresh deployment operations for timings: %v", err)
+		}
+
+		timings = resourceTimings(progressDisplay.CompletedOperations())
+	}
+
 	return &provisioning.DeployResult{
 		Deployment: &result,
+		Timings:    timings,
 	}, nil
 }
 
+// resourceTimings converts completed deployment operations into timings, slowest first.
+func resourceTimings(operations []*armresources.DeploymentOperation) []provisioning.ResourceTiming {
+	timings := make([]provisioning.ResourceTiming, 0, len(operations))
+	for _, operation := range operations {
+		duration, err := convert.ParseDuration(*operation.Properties.Duration)
+		if err != nil {
+			log.Printf("invalid duration '%s' for operation %s: %v", *operation.Properties.Duration, *operation.ID, err)
+			continue
+		}
+
+		target := operation.Properties.TargetResource
+		resourceType := convert.ToValueWithDefault(target.ResourceType, "")
+		timings = append(timings, provisioning.ResourceTiming{
+			Name:     convert.ToValueWithDefault(target.ResourceName, ""),
+			Type:     resourceType,
+			Module:   resourceType == string(azapi.AzureResourceTypeDeployment),
+			State:    convert.ToValueWithDefault(operation.Properties.ProvisioningState, ""),
+			Duration: duration.Truncate(time.Millisecond),
+		})
+	}
+
+	provisioning.SortTimings(timings)
+	return timings
+}
+
 // Preview runs deploy using the what-if argument
 func (p *BicepProvider) Preview(ctx context.Context) (*provisioning.DeployPreviewResult, error) {
 	planned, err := p.plan(ctx)
@@ -1192,13 +1266,6 @@ func convertPropertyChanges(changes []*armresources.WhatIfPropertyChange) []prov
 	return result
 }
 
-type itemToPurge struct {
-	resourceType      string
-	count             int
-	purge             func(skipPurge bool, self *itemToPurge) error
-	cognitiveAccounts []cognitiveAccount
-}
-
 func (p *BicepProvider) scopeForTemplate(t azure.ArmTemplate) (infra.Scope, error) {
 	deploymentScope, err := t.TargetScope()
 	if err != nil {
@@ -1280,6 +1347,9 @@ func (p *BicepProvider) Destroy(
 		return nil, fmt.Errorf("mapping resources to resource groups: %w", err)
 	}
 
+	// Protected resources are retained instead of being deleted.
+	var protectedIds []string
+
 	// If no resources found, we still need to void the deployment state.
 	// This can happen when resources have been manually deleted before running azd down.
 	// Voiding the state ensures that subsequent azd provision commands work correctly
@@ -1291,48 +1361,49 @@ func (p *BicepProvider) Destroy(
 			return nil, fmt.Errorf("voiding deployment state: %w", err)
 		}
 	} else {
-		keyVaults, err := p.getKeyVaultsToPurge(ctx, groupedResources)
-		if err != nil {
-			return nil, fmt.Errorf("getting key vaults to purge: %w", err)
-		}
-
-		managedHSMs, err := p.getManagedHSMsToPurge(ctx, groupedResources)
+		protectedIds, err = p.protectedResources(ctx, options, groupedResources)
 		if err != nil {
-			return nil, fmt.Errorf("getting managed hsms to purge: %w", err)
+			return nil, fmt.Errorf("finding protected resources: %w", err)
 		}
 
-		appConfigs, err := p.getAppConfigsToPurge(ctx, groupedResources)
+		keptIds, err := p.resourcesToKeep(ctx, options, groupedResources, protectedIds)
 		if err != nil {
-			return nil, fmt.Errorf("getting app configurations to purge: %w", err)
+			return nil, err
 		}
 
-		apiManagements, err := p.getApiManagementsToPurge(ctx, groupedResources)
-		if err != nil {
-			return nil, fmt.Errorf("getting API managements to purge: %w", err)
+		// When resources are kept, only the resources to delete are listed, confirmed and purged.
+		groupedResources, keptResources := splitKeptResources(groupedResources, keptIds)
+		resourceCount := len(resourcesToDelete)
+		if len(keptResources) > 0 {
+			resourceCount = countResources(groupedResources)
 		}
 
-		cognitiveAccounts, err := p.getCognitiveAccountsToPurge(ctx, groupedResources)
-		if err != nil {
-			return nil, fmt.Errorf("getting cognitive accounts to purge: %w", err)
+		if resourceCount == 0 {
+			p.console.StopSpinner(ctx, "", input.StepDone)
+			p.console.MessageUxItem(ctx, &ux.MultilineMessage{
+				Lines: p.generateKeptResources(ctx, keptResources, protectedIds)},
+			)
+			p.console.Message(ctx, "All resources are kept. No resources were deleted.")
+			return &provisioning.DestroyResult{SkippedDeletion: true, RetainedResources: protectedIds}, nil
 		}
 
-		logAnalyticsWorkspaces, err := p.getLogAnalyticsWorkspacesToPurge(ctx, groupedResources)
+		purgeTargets, err := p.purgeTargets(ctx, groupedResources)
 		if err != nil {
-			return nil, fmt.Errorf("getting log analytics workspaces to purge: %w", err)
+			return nil, err
 		}
 
 		p.console.StopSpinner(ctx, "", input.StepDone)
 
 		// For resource-group-scoped deployments, check if the resource group was created by azd.
 		// If not, warn the user that deleting the RG will remove ALL resources, including those
-		// not deployed by azd.
-		if targetScope == azure.DeploymentScopeResourceGroup {
+		// not deployed by azd. When resources are kept, the RG isn't deleted.
+		if targetScope == azure.DeploymentScopeResourceGroup && len(keptResources) == 0 {
 			rgName := p.env.Getenv(environment.ResourceGroupEnvVarName)
 			// warnExternalResourceGroup returns (userAlreadyConfirmed, error).
 			// When true, user confirmed via the external-RG warning so we skip promptDeletion.
 			// When false with nil error, the RG is azd-owned and normal prompt applies.
 			userAlreadyConfirmed, err := p.warnExternalResourceGroup(
-				ctx, options, rgName, groupedResources, len(resourcesToDelete),
+				ctx, options, rgName, groupedResources, resourceCount,
 			)
 			if err != nil {
 				return nil, fmt.Errorf("checking resource group ownership: %w", err)
@@ -1343,7 +1414,7 @@ func (p *BicepProvider) Destroy(
 			if !userAlreadyConfirmed {
 				// Prompt for confirmation before deleting resources
 				if err := p.promptDeletion(
-					ctx, options, groupedResources, len(resourcesToDelete),
+					ctx, options, groupedResources, keptResources, protectedIds, resourceCount,
 				); err != nil {
 					return nil, err
 				}
@@ -1351,7 +1422,7 @@ func (p *BicepProvider) Destroy(
 		} else {
 			// Prompt for confirmation before deleting resources
 			if err := p.promptDeletion(
-				ctx, options, groupedResources, len(resourcesToDelete),
+				ctx, options, groupedResources, keptResources, protectedIds, resourceCount,
 			); err != nil {
 				return nil, err
 			}
@@ -1359,74 +1430,38 @@ func (p *BicepProvider) Destroy(
 
 		p.console.Message(ctx, output.WithGrayFormat("Deleting your resources can take some time.\n"))
 
-		// Force delete Log Analytics Workspaces first if purge is enabled
-		// This must happen before deleting resource groups since force delete requires the workspace to exist
-		if options.Purge() && len(logAnalyticsWorkspaces) > 0 {
-			if err := p.forceDeleteLogAnalyticsWorkspaces(ctx, logAnalyticsWorkspaces); err != nil {
-				return nil, fmt.Errorf("force deleting log analytics workspaces: %w", err)
-			}
-		}
-
-		if err := p.destroyDeployment(ctx, deploymentToDelete); err != nil {
-			return nil, fmt.Errorf("deleting resource groups: %w", err)
-		}
-
-		keyVaultsPurge := itemToPurge{
-			resourceType: "Key Vault",
-			count:        len(keyVaults),
-			purge: func(skipPurge bool, self *itemToPurge) error {
-				return p.purgeKeyVaults(ctx, keyVaults, skipPurge)
-			},
-		}
-		managedHSMsPurge := itemToPurge{
-			resourceType: "Managed HSM",
-			count:        len(managedHSMs),
-			purge: func(skipPurge bool, self *itemToPurge) error {
-				return p.purgeManagedHSMs(ctx, managedHSMs, skipPurge)
-			},
-		}
-		appConfigsPurge := itemToPurge{
-			resourceType: "App Configuration",
-			count:        len(appConfigs),
-			purge: func(skipPurge bool, self *itemToPurge) error {
-				return p.purgeAppConfigs(ctx, appConfigs, skipPurge)
-			},
-		}
-		aPIManagement := itemToPurge{
-			resourceType: "API Management",
-			count:        len(apiManagements),
-			purge: func(skipPurge bool, self *itemToPurge) error {
-				return p.purgeAPIManagement(ctx, apiManagements, skipPurge)
-			},
+		if err := p.prepareDeletion(ctx, options, groupedResources, keptResources); err != nil {
+			return nil, err
 		}
 
-		var purgeItem []itemToPurge
-		for _, item := range []itemToPurge{keyVaultsPurge, managedHSMsPurge, appConfigsPurge, aPIManagement} {
-			if item.count > 0 {
-				purgeItem = append(purgeItem, item)
+		// Resources purged by force deleting them must be purged before their resource group is deleted.
+		beforeDelete, afterDelete := splitPurgeTargets(purgeTargets)
+		if options.Purge() && len(beforeDelete) > 0 {
+			if err := p.purgeResources(ctx, beforeDelete, false); err != nil {
+				return nil, fmt.Errorf("purging resources before deletion: %w", err)
 			}
 		}
 
-		// cognitive services are grouped by resource group because the name of the resource group is required to purge
-		groupByKind := cognitiveAccountsByKind(cognitiveAccounts)
-		for name, cogAccounts := range groupByKind {
-			addPurgeItem := itemToPurge{
-				resourceType: name,
-				count:        len(cogAccounts),
-				purge: func(skipPurge bool, self *itemToPurge) error {
-					return p.purgeCognitiveAccounts(ctx, self.cognitiveAccounts, skipPurge)
-				},
-				cognitiveAccounts: groupByKind[name],
+		if len(keptResources) > 0 {
+			if err := p.destroyResources(ctx, scope, targetScope, groupedResources, keptResources); err != nil {
+				return nil, fmt.Errorf("deleting resources: %w", err)
 			}
-			purgeItem = append(purgeItem, addPurgeItem)
+		} else if err := p.destroyDeployment(ctx, deploymentToDelete); err != nil {
+			return nil, fmt.Errorf("deleting resource groups: %w", err)
 		}
 
-		if err := p.purgeItems(ctx, purgeItem, options); err != nil {
+		afterDelete, alreadyDeleted := splitAlreadyDeleted(afterDelete)
+		if err := p.purgeItems(ctx, afterDelete, options); err != nil {
 			return nil, fmt.Errorf("purging resources: %w", err)
 		}
+
+		if err := p.purgeAlreadyDeleted(ctx, alreadyDeleted); err != nil {
+			return nil, fmt.Errorf("purging deleted resources: %w", err)
+		}
 	}
 
 	destroyResult := &provisioning.DestroyResult{
+		RetainedResources: protectedIds,
 		InvalidatedEnvKeys: slices.Collect(maps.Keys(provisioning.OutputParametersFromArmOutputs(
 			compileResult.Template.Outputs,
 			azapi.CreateDeploymentOutput(mostRecentDeployment.Outputs),
@@ -1436,41 +1471,6 @@ func (p *BicepProvider) Destroy(
 	return destroyResult, nil
 }
 
-// A local type for adding the resource group to a cognitive account as it is required for purging
-type cognitiveAccount struct {
-	account       armcognitiveservices.Account
-	resourceGroup string
-}
-
-// transform a map of resourceGroup and accounts to group by kind in all resource groups but keeping the resource group
-// on each account
-func cognitiveAccountsByKind(
-	accountsByResourceGroup map[string][]armcognitiveservices.Account) map[string][]cognitiveAccount {
-	result := make(map[string][]cognitiveAccount)
-	for resourceGroup, cogAccounts := range accountsByResourceGroup {
-		for _, cogAccount := range cogAccounts {
-			kindName := *cogAccount.Kind
-			// Replace "FormRecognizer" with "DocumentIntelligence"
-			if kindName == "FormRecognizer" {
-				kindName = "Document Intelligence"
-			}
-			_, exists := result[kindName]
-			if exists {
-				result[kindName] = append(result[kindName], cognitiveAccount{
-					account:       cogAccount,
-					resourceGroup: resourceGroup,
-				})
-			} else {
-				result[kindName] = []cognitiveAccount{{
-					account:       cogAccount,
-					resourceGroup: resourceGroup,
-				}}
-			}
-		}
-	}
-	return result
-}
-
 func getDeploymentOptions(deployments []*azapi.ResourceDeployment) []string {
 	promptValues := []string{}
 	for index, deployment := range deployments {
@@ -1489,7 +1489,16 @@ func (p *BicepProvider) generateResourcesToDelete(
 	ctx context.Context,
 	groupedResources map[string][]*azapi.Resource,
 ) []string {
-	lines := []string{"Resource(s) to be deleted:"}
+	return append(p.generateResourceLines(ctx, "Resource(s) to be deleted:", groupedResources), "\n")
+}
+
+// generateResourceLines lists the resources grouped by resource group under the title.
+func (p *BicepProvider) generateResourceLines(
+	ctx context.Context,
+	title string,
+	groupedResources map[string][]*azapi.Resource,
+) []string {
+	lines := []string{title}
 
 	for resourceGroupName, resources := range groupedResources {
 		lines = append(lines, "")
@@ -1528,7 +1537,7 @@ func (p *BicepProvider) generateResourcesToDelete(
 		}
 	}
 
-	return append(lines, "\n")
+	return lines
 }
 
 // promptDeletion prompts the user for confirmation before deleting resources.
@@ -1537,15 +1546,18 @@ func (p *BicepProvider) promptDeletion(
 	ctx context.Context,
 	options provisioning.DestroyOptions,
 	groupedResources map[string][]*azapi.Resource,
+	keptResources map[string][]*azapi.Resource,
+	protectedIds []string,
 	resourceCount int,
 ) error {
 	if options.Force() {
 		return nil
 	}
 
-	p.console.MessageUxItem(ctx, &ux.MultilineMessage{
-		Lines: p.generateResourcesToDelete(ctx, groupedResources)},
-	)
+	lines := append(
+		p.generateResourcesToDelete(ctx, groupedResources), p.generateKeptResources(ctx, keptResources, protectedIds)...)
+
+	p.console.MessageUxItem(ctx, &ux.MultilineMessage{Lines: lines})
 	confirmDestroy, err := p.console.Confirm(ctx, input.ConsoleOptions{
 		Message: fmt.Sprintf(
 			"Total resources to %s: %d, are you sure you want to continue?",
@@ -1677,28 +1689,141 @@ func (p *BicepProvider) destroyDeployment(
 	return nil
 }
 
-func itemsCountAsText(items []itemToPurge) string {
-	count := len(items)
-	if count < 1 {
+func itemsCountAsText(targets []*azapi.PurgeTarget) string {
+	if len(targets) < 1 {
 		log.Panic("calling itemsCountAsText() with empty list.")
 	}
 
 	var tokens []string
-	for _, item := range items {
-		if item.count > 0 {
-			tokens = append(tokens, fmt.Sprintf("%d %s", item.count, item.resourceType))
+	for _, displayType := range purgeDisplayTypes(targets) {
+		count := 0
+		for _, target := range targets {
+			if target.DisplayType == displayType {
+				count++
+			}
 		}
+
+		tokens = append(tokens, fmt.Sprintf("%d %s", count, displayType))
 	}
 
 	return ux.ListAsText(tokens)
 }
 
+// purgeDisplayTypes returns the display types of the targets, in the order they first appear.
+func purgeDisplayTypes(targets []*azapi.PurgeTarget) []string {
+	displayTypes := []string{}
+	for _, target := range targets {
+		if !slices.Contains(displayTypes, target.DisplayType) {
+			displayTypes = append(displayTypes, target.DisplayType)
+		}
+	}
+
+	return displayTypes
+}
+
+// splitPurgeTargets splits the targets into the targets purged before their resource group is deleted, and the
+// targets purged after.
+func splitPurgeTargets(targets []*azapi.PurgeTarget) ([]*azapi.PurgeTarget, []*azapi.PurgeTarget) {
+	beforeDelete := []*azapi.PurgeTarget{}
+	afterDelete := []*azapi.PurgeTarget{}
+	for _, target := range targets {
+		if target.BeforeDelete {
+			beforeDelete = append(beforeDelete, target)
+		} else {
+			afterDelete = append(afterDelete, target)
+		}
+	}
+
+	return beforeDelete, afterDelete
+}
+
+// splitAlreadyDeleted splits the targets into the resources deleted by this deletion, and the resources that were
+// already soft-deleted.
+func splitAlreadyDeleted(targets []*azapi.PurgeTarget) ([]*azapi.PurgeTarget, []*azapi.PurgeTarget) {
+	deleting := []*azapi.PurgeTarget{}
+	alreadyDeleted := []*azapi.PurgeTarget{}
+	for _, target := range targets {
+		if target.Deleted {
+			alreadyDeleted = append(alreadyDeleted, target)
+		} else {
+			deleting = append(deleting, target)
+		}
+	}
+
+	return deleting, alreadyDeleted
+}
+
+// purgeAlreadyDeleted purges the resources that were already soft-deleted in the resource groups of the deployment.
+// They are only matched by resource group, and may not have been created by the deployment, so they are listed and
+// only purged when the user confirms it, even with --purge. They're never purged without prompting.
+func (p *BicepProvider) purgeAlreadyDeleted(ctx context.Context, targets []*azapi.PurgeTarget) error {
+	if len(targets) == 0 {
+		return nil
+	}
+
+	p.console.MessageUxItem(ctx, &ux.WarningMessage{
+		Description: fmt.Sprintf(
+			"The resource groups of this environment also contain %s that were deleted before, and may not have been "+
+				"created by azd:",
+			itemsCountAsText(targets),
+		),
+	})
+	for _, target := range targets {
+		p.console.Message(ctx, fmt.Sprintf("  - %s: %s", target.DisplayType, output.WithHighLightFormat(target.Name)))
+	}
+	p.console.Message(ctx, "")
+
+	purge := false
+	if p.console.IsNoPromptMode() {
+		p.console.Message(ctx, "They aren't purged without prompting. Run 'azd down' interactively to purge them.\n")
+	} else {
+		confirmed, err := p.console.Confirm(ctx, input.ConsoleOptions{
+			Message: fmt.Sprintf(
+				"Would you like to %s these resources too?", output.WithErrorFormat("permanently delete")),
+			DefaultValue: false,
+		})
+		p.console.Message(ctx, "")
+		if err != nil {
+			return fmt.Errorf("prompting for confirmation: %w", err)
+		}
+
+		purge = confirmed
+	}
+
+	return p.purgeResources(ctx, targets, !purge)
+}
+
+// purgeTargets returns the resources to purge once the resources grouped by resource group are deleted, with the
+// resources of these resource groups that are already soft-deleted, for example by a previous `azd down` that didn't
+// purge them.
+func (p *BicepProvider) purgeTargets(
+	ctx context.Context,
+	groupedResources map[string][]*azapi.Resource,
+) ([]*azapi.PurgeTarget, error) {
+	targets, err := p.purgeService.Targets(ctx, groupedResources)
+	if err != nil {
+		return nil, fmt.Errorf("getting resources to purge: %w", err)
+	}
+
+	deleted := p.purgeService.DeletedTargets(
+		ctx, p.env.GetSubscriptionId(), slices.Sorted(maps.Keys(groupedResources)))
+	for _, target := range deleted {
+		if !slices.ContainsFunc(targets, func(existing *azapi.PurgeTarget) bool {
+			return strings.EqualFold(existing.Id, target.Id)
+		}) {
+			targets = append(targets, target)
+		}
+	}
+
+	return targets, nil
+}
+
 func (p *BicepProvider) purgeItems(
 	ctx context.Context,
-	items []itemToPurge,
+	targets []*azapi.PurgeTarget,
 	options provisioning.DestroyOptions,
 ) error {
-	if len(items) == 0 {
+	if len(targets) == 0 {
 		// nothing to purge
 		return nil
 	}
@@ -1709,7 +1834,7 @@ func (p *BicepProvider) purgeItems(
 		p.console.MessageUxItem(ctx, &ux.WarningMessage{
 			Description: fmt.Sprintf(
 				"The following operation will delete %s.",
-				itemsCountAsText(items),
+				itemsCountAsText(targets),
 			),
 		})
 		p.console.Message(ctx, fmt.Sprintf(
@@ -1734,203 +1859,32 @@ func (p *BicepProvider) purgeItems(
 			skipPurge = true
 		}
 	}
-	for index, item := range items {
-		if err := item.purge(skipPurge, &items[index]); err != nil {
-			return fmt.Errorf("failed to purge %s: %w", item.resourceType, err)
-		}
-	}
-
-	return nil
-}
-
-func (p *BicepProvider) getKeyVaults(
-	ctx context.Context,
-	groupedResources map[string][]*azapi.Resource,
-) ([]*keyvault.KeyVault, error) {
-	vaults := []*keyvault.KeyVault{}
-
-	for resourceGroup, groupResources := range groupedResources {
-		for _, resource := range groupResources {
-			if resource.Type == string(azapi.AzureResourceTypeKeyVault) {
-				vault, err := p.keyvaultService.GetKeyVault(
-					ctx, azure.SubscriptionFromRID(resource.Id), resourceGroup, resource.Name)
-				if err != nil {
-					return nil, fmt.Errorf("listing key vault %s properties: %w", resource.Name, err)
-				}
-				vaults = append(vaults, vault)
-			}
-		}
-	}
 
-	return vaults, nil
+	return p.purgeResources(ctx, targets, skipPurge)
 }
 
-func (p *BicepProvider) getKeyVaultsToPurge(
-	ctx context.Context,
-	groupedResources map[string][]*azapi.Resource,
-) ([]*keyvault.KeyVault, error) {
-	vaults, err := p.getKeyVaults(ctx, groupedResources)
-	if err != nil {
-		return nil, err
-	}
-
-	vaultsToPurge := []*keyvault.KeyVault{}
-	for _, v := range vaults {
-		if v.Properties.EnableSoftDelete && !v.Properties.EnablePurgeProtection {
-			vaultsToPurge = append(vaultsToPurge, v)
-		}
-	}
-
-	return vaultsToPurge, nil
-}
+// purgeResources purges the targets one type after the other, reporting the progress of each type.
+func (p *BicepProvider) purgeResources(ctx context.Context, targets []*azapi.PurgeTarget, skip bool) error {
+	for _, displayType := range purgeDisplayTypes(targets) {
+		typeTargets := slices.DeleteFunc(slices.Clone(targets), func(target *azapi.PurgeTarget) bool {
+			return target.DisplayType != displayType
+		})
 
-func (p *BicepProvider) getManagedHSMs(
-	ctx context.Context,
-	groupedResources map[string][]*azapi.Resource,
-) ([]*azapi.AzCliManagedHSM, error) {
-	managedHSMs := []*azapi.AzCliManagedHSM{}
-
-	for resourceGroup, groupResources := range groupedResources {
-		for _, resource := range groupResources {
-			if resource.Type == string(azapi.AzureResourceTypeManagedHSM) {
-				managedHSM, err := p.azapi.GetManagedHSM(
-					ctx,
-					azure.SubscriptionFromRID(resource.Id),
-					resourceGroup,
-					resource.Name,
-				)
-				if err != nil {
-					return nil, fmt.Errorf("listing managed hsm %s properties: %w", resource.Name, err)
-				}
-				managedHSMs = append(managedHSMs, managedHSM)
+		for index, target := range typeTargets {
+			purgeType := displayType
+			if len(typeTargets) > 1 {
+				purgeType = fmt.Sprintf("%s (%d/%d)", displayType, index+1, len(typeTargets))
 			}
-		}
-	}
-
-	return managedHSMs, nil
-}
-
-func (p *BicepProvider) getManagedHSMsToPurge(
-	ctx context.Context,
-	groupedResources map[string][]*azapi.Resource,
-) ([]*azapi.AzCliManagedHSM, error) {
-	managedHSMs, err := p.getManagedHSMs(ctx, groupedResources)
-	if err != nil {
-		return nil, err
-	}
-
-	managedHSMsToPurge := []*azapi.AzCliManagedHSM{}
-	for _, v := range managedHSMs {
-		if v.Properties.EnableSoftDelete && !v.Properties.EnablePurgeProtection {
-			managedHSMsToPurge = append(managedHSMsToPurge, v)
-		}
-	}
 
-	return managedHSMsToPurge, nil
-}
-
-func (p *BicepProvider) getCognitiveAccountsToPurge(
-	ctx context.Context,
-	groupedResources map[string][]*azapi.Resource,
-) (map[string][]armcognitiveservices.Account, error) {
-	result := make(map[string][]armcognitiveservices.Account)
-
-	for resourceGroup, groupResources := range groupedResources {
-		cognitiveAccounts := []armcognitiveservices.Account{}
-		for _, resource := range groupResources {
-			if resource.Type == string(azapi.AzureResourceTypeCognitiveServiceAccount) {
-				account, err := p.azapi.GetCognitiveAccount(
-					ctx, azure.SubscriptionFromRID(resource.Id), resourceGroup, resource.Name)
-				if err != nil {
-					return nil, fmt.Errorf("getting cognitive account %s: %w", resource.Name, err)
-				}
-				cognitiveAccounts = append(cognitiveAccounts, account)
-			}
-			if len(cognitiveAccounts) > 0 {
-				result[resourceGroup] = cognitiveAccounts
+			err := p.runPurgeAsStep(ctx, purgeType, target.Name, func() error {
+				return p.purgeService.Purge(ctx, target)
+			}, skip)
+			if err != nil {
+				return fmt.Errorf("purging %s %s: %w", displayType, target.Name, err)
 			}
 		}
 	}
 
-	return result, nil
-}
-
-// Azure KeyVaults have a "soft delete" functionality (now enabled by default) where a vault may be marked
-// such that when it is deleted it can be recovered for a period of time. During that time, the name may
-// not be reused.
-//
-// This means that running `azd provision`, then `azd down` and finally `azd provision`
-// again would lead to a deployment error since the vault name is in use.
-//
-// Since that's behavior we'd like to support, we run a purge operation for each KeyVault after
-// it has been deleted.
-//
-// See
-// https://docs.microsoft.com/azure/key-vault/general/key-vault-recovery?tabs=azure-portal#what-are-soft-delete-and-purge-protection
-// for more information on this feature.
-//
-//nolint:lll
-func (p *BicepProvider) purgeKeyVaults(
-	ctx context.Context,
-	keyVaults []*keyvault.KeyVault,
-	skip bool,
-) error {
-	for _, keyVault := range keyVaults {
-		err := p.runPurgeAsStep(ctx, "Key Vault", keyVault.Name, func() error {
-			return p.keyvaultService.PurgeKeyVault(
-				ctx, azure.SubscriptionFromRID(keyVault.Id), keyVault.Name, keyVault.Location)
-		}, skip)
-		if err != nil {
-			return fmt.Errorf("purging key vault %s: %w", keyVault.Name, err)
-		}
-	}
-	return nil
-}
-
-func (p *BicepProvider) purgeManagedHSMs(
-	ctx context.Context,
-	managedHSMs []*azapi.AzCliManagedHSM,
-	skip bool,
-) error {
-	for _, managedHSM := range managedHSMs {
-		err := p.runPurgeAsStep(ctx, "Managed HSM", managedHSM.Name, func() error {
-			return p.azapi.PurgeManagedHSM(
-				ctx, azure.SubscriptionFromRID(managedHSM.Id), managedHSM.Name, managedHSM.Location)
-		}, skip)
-		if err != nil {
-			return fmt.Errorf("purging managed hsm %s: %w", managedHSM.Name, err)
-		}
-	}
-	return nil
-}
-
-func (p *BicepProvider) purgeCognitiveAccounts(
-	ctx context.Context,
-	cognitiveAccounts []cognitiveAccount,
-	skip bool,
-) error {
-	for _, cogAccount := range cognitiveAccounts {
-		accountName := cogAccount.account.Name
-		if accountName == nil {
-			return fmt.Errorf("Cognitive account without a name")
-		}
-		accountId := cogAccount.account.ID
-		if accountId == nil {
-			return fmt.Errorf("Cognitive account without an id")
-		}
-		location := cogAccount.account.Location
-		if location == nil {
-			return fmt.Errorf("Cognitive account without a location")
-		}
-
-		err := p.runPurgeAsStep(ctx, "Cognitive Account", *accountName, func() error {
-			return p.azapi.PurgeCognitiveAccount(
-				ctx, azure.SubscriptionFromRID(*accountId), *location, cogAccount.resourceGroup, *accountName)
-		}, skip)
-		if err != nil {
-			return fmt.Errorf("purging cognitive account %s: %w", *accountName, err)
-		}
-	}
 	return nil
 }
 
@@ -1950,159 +1904,6 @@ func (p *BicepProvider) runPurgeAsStep(
 	return err
 }
 
-func (p *BicepProvider) getAppConfigsToPurge(
-	ctx context.Context,
-	groupedResources map[string][]*azapi.Resource,
-) ([]*azapi.AzCliAppConfig, error) {
-	configs := []*azapi.AzCliAppConfig{}
-
-	for resourceGroup, groupResources := range groupedResources {
-		for _, resource := range groupResources {
-			if resource.Type == string(azapi.AzureResourceTypeAppConfig) {
-				config, err := p.azapi.GetAppConfig(
-					ctx,
-					azure.SubscriptionFromRID(resource.Id),
-					resourceGroup,
-					resource.Name,
-				)
-				if err != nil {
-					return nil, fmt.Errorf("listing app configuration %s properties: %w", resource.Name, err)
-				}
-
-				if !config.Properties.EnablePurgeProtection {
-					configs = append(configs, config)
-				}
-			}
-		}
-	}
-
-	return configs, nil
-}
-
-func (p *BicepProvider) getApiManagementsToPurge(
-	ctx context.Context,
-	groupedResources map[string][]*azapi.Resource,
-) ([]*azapi.AzCliApim, error) {
-	apims := []*azapi.AzCliApim{}
-
-	for resourceGroup, groupResources := range groupedResources {
-		for _, resource := range groupResources {
-			if resource.Type == string(azapi.AzureResourceTypeApim) {
-				apim, err := p.azapi.GetApim(ctx, azure.SubscriptionFromRID(resource.Id), resourceGroup, resource.Name)
-				if err != nil {
-					return nil, fmt.Errorf("listing api management service %s properties: %w", resource.Name, err)
-				}
-
-				//No filtering needed like it does in key vaults or app configuration
-				//as soft-delete happens for all Api Management resources
-				apims = append(apims, apim)
-			}
-		}
-	}
-
-	return apims, nil
-}
-
-func (p *BicepProvider) getLogAnalyticsWorkspacesToPurge(
-	ctx context.Context,
-	groupedResources map[string][]*azapi.Resource,
-) ([]*azapi.AzCliLogAnalyticsWorkspace, error) {
-	workspaces := []*azapi.AzCliLogAnalyticsWorkspace{}
-
-	for resourceGroup, groupResources := range groupedResources {
-		for _, resource := range groupResources {
-			if resource.Type == string(azapi.AzureResourceTypeLogAnalyticsWorkspace) {
-				workspace, err := p.azapi.GetLogAnalyticsWorkspace(
-					ctx,
-					azure.SubscriptionFromRID(resource.Id),
-					resourceGroup,
-					resource.Name,
-				)
-				if err != nil {
-					return nil, fmt.Errorf("listing log analytics workspace %s properties: %w", resource.Name, err)
-				}
-
-				workspaces = append(workspaces, workspace)
-			}
-		}
-	}
-
-	return workspaces, nil
-}
-
-// Azure AppConfigurations have a "soft delete" functionality (now enabled by default) where a configuration store
-// may be marked such that when it is deleted it can be recovered for a period of time. During that time,
-// the name may not be reused.
-//
-// This means that running `azd provision`, then `azd down` and finally `azd provision`
-// again would lead to a deployment error since the configuration name is in use.
-//
-// Since that's behavior we'd like to support, we run a purge operation for each AppConfiguration after it has been deleted.
-//
-// See https://learn.microsoft.com/azure/azure-app-configuration/concept-soft-delete for more information
-// on this feature.
-func (p *BicepProvider) purgeAppConfigs(
-	ctx context.Context,
-	appConfigs []*azapi.AzCliAppConfig,
-	skip bool,
-) error {
-	for _, appConfig := range appConfigs {
-		err := p.runPurgeAsStep(ctx, "app config", appConfig.Name, func() error {
-			return p.azapi.PurgeAppConfig(
-				ctx, azure.SubscriptionFromRID(appConfig.Id), appConfig.Name, appConfig.Location)
-		}, skip)
-		if err != nil {
-			return fmt.Errorf("purging app configuration %s: %w", appConfig.Name, err)
-		}
-	}
-
-	return nil
-}
-
-func (p *BicepProvider) purgeAPIManagement(
-	ctx context.Context,
-	apims []*azapi.AzCliApim,
-	skip bool,
-) error {
-	for _, apim := range apims {
-		err := p.runPurgeAsStep(ctx, "apim", apim.Name, func() error {
-			return p.azapi.PurgeApim(ctx, azure.SubscriptionFromRID(apim.Id), apim.Name, apim.Location)
-		}, skip)
-		if err != nil {
-			return fmt.Errorf("purging api management service %s: %w", apim.Name, err)
-		}
-	}
-
-	return nil
-}
-
-// Handle Log Analytics Workspaces separately with Force option when purge is enabled.
-// Unlike many other resources, Log Analytics Workspaces are not able to be purged after soft-delete
-// because purge function only support for purge tables not a whole workspace and force delete must happen
-// when their resource group is not deleted, so we must purge them explicitly before deleting the resource
-func (p *BicepProvider) forceDeleteLogAnalyticsWorkspaces(
-	ctx context.Context,
-	workspaces []*azapi.AzCliLogAnalyticsWorkspace,
-) error {
-	for _, workspace := range workspaces {
-		message := fmt.Sprintf("Purging Log Analytics Workspace: %s", output.WithHighLightFormat(workspace.Name))
-		p.console.ShowSpinner(ctx, message, input.Step)
-
-		err := p.azapi.PurgeLogAnalyticsWorkspace(
-			ctx,
-			azure.SubscriptionFromRID(workspace.Id),
-			*azure.GetResourceGroupName(workspace.Id),
-			workspace.Name,
-		)
-
-		p.console.StopSpinner(ctx, message, input.GetStepResultFormat(err))
-		if err != nil {
-			return fmt.Errorf("purging log analytics workspace %s: %w", workspace.Name, err)
-		}
-	}
-	return nil
-}
-
 type loadParametersResult struct {
 	parameters     map[string]azure.ArmParameter
 	locationParams []string
@@ -2406,7 +2207,9 @@ func (p *BicepProvider) compileBicep(ctx context.Context) (*compileBicepResult,
 			}
 			azdEnv = append(azdEnv, fmt.Sprintf("%s=%s", environment.PrincipalIdEnvVarName, currentPrincipalId))
 		}
-		compiledResult, err := p.bicepCli.BuildBicepParam(ctx, p.path, azdEnv)
+		compiledResult, err := p.buildWithRegistryAuth(ctx, func() (bicep.BuildResult, error) {
+			return p.bicepCli.BuildBicepParam(ctx, p.path, azdEnv)
+		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to compile bicepparam template: %w", err)
 		}
@@ -2425,7 +2228,9 @@ func (p *BicepProvider) compileBicep(ctx context.Context) (*compileBicepResult,
 		}
 		parameters = params.Parameters
 	} else {
-		res, err := p.bicepCli.Build(ctx, p.path)
+		res, err := p.buildWithRegistryAuth(ctx, func() (bicep.BuildResult, error) {
+			return p.bicepCli.Build(ctx, p.path)
+		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to compile bicep template: %w", err)
 		}
@@ -2702,6 +2507,16 @@ func (p *BicepProvider) runLocalProvisionValidation(
 		Fn:     p.checkReservedResourceNames,
 	})
 
+	validator.AddCheck(ProvisionValidationCheck{
+		RuleID: "policy_restrictions",
+		Fn:     p.checkPolicyRestrictions,
+	})
+
+	validator.AddCheck(ProvisionValidationCheck{
+		RuleID: "regional_capacity",
+		Fn:     p.checkRegionalCapacity,
+	})
+
 	valCtx, results, err := validator.validate(ctx, p.console, armTemplate, armParameters)
 	if err != nil {
 		p.setProvisionValidationOutcome(span, provisionValidationOutcomeError, nil)
@@ -3572,8 +3387,9 @@ func (p *BicepProvider) ensureParameters(
 			continue
 		}
 
-		if param.Nullable != nil && *param.Nullable {
-			// If the parameter is nullable, we can skip prompting for it.
+		// A nullable parameter isn't prompted for, unless it has a promptIf condition: it's then prompted for when the
+		// condition holds, and left unset otherwise.
+		if param.Nullable != nil && *param.Nullable && !hasPromptIf(param) {
 			continue
 		}
 
@@ -3582,7 +3398,14 @@ func (p *BicepProvider) ensureParameters(
 		configKey := fmt.Sprintf("infra.parameters.%s", key)
 
 		if v, has := p.env.Config.Get(configKey); has {
-			if isValueAssignableToParameterType(parameterType, v) {
+			if isKeyVaultSecretParameter(param) {
+				if ref, isRef := keyVaultReferenceFromConfig(v); isRef {
+					configuredParameters[key] = azure.ArmParameter{
+						KeyVaultReference: ref,
+					}
+					continue
+				}
+			} else if isValueAssignableToParameterType(parameterType, v) {
 				configuredParameters[key] = azure.ArmParameter{
 					Value: v,
 				}
@@ -3619,59 +3442,85 @@ func (p *BicepProvider) ensureParameters(
 		}{key: key, param: param})
 	}
 
-	// If in no-prompt mode and there are missing parameters, return an error with all missing inputs
-	if len(parameterPrompts) > 0 && p.console.IsNoPromptMode() {
-		return nil, p.buildMissingInputsError(parameterPrompts, parametersResult.envMapping)
+	conditions, err := promptConditions(template, parameterPrompts)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(parameterPrompts) > 0 {
-		if p.console.SupportsPromptDialog() {
-
-			dialog := input.PromptDialog{
-				Title: "Configure required deployment parameters",
-				Description: "The following parameters are required for deployment. " +
-					"Provide values for each parameter. They will be saved for future deployments.",
-			}
+	// If in no-prompt mode and there are missing parameters, return an error with all missing inputs. Parameters with a
+	// promptIf condition that can already be evaluated, and doesn't hold, are not missing, and neither are parameters
+	// answered by the answers file, which are still prompted for below so the answers console answers them.
+	if len(parameterPrompts) > 0 && p.console.IsNoPromptMode() {
+		isAnswered := func(prompt struct {
+			key   string
+			param azure.ArmTemplateParameterDefinition
+		}) bool {
+			return p.parameterAnswered(prompt.key, prompt.param)
+		}
 
-			for _, prompt := range parameterPrompts {
-				dialog.Prompts = append(dialog.Prompts, p.promptDialogItemForParameter(prompt.key, prompt.param))
-			}
+		round, deferred := nextPromptRound(parameterPrompts, conditions)
+		missing := append(
+			skipUnneededPrompts(round, conditions, conditionValues(template, configuredParameters)),
+			deferred...)
+		missing = slices.DeleteFunc(slices.Clone(missing), isAnswered)
+		if len(missing) > 0 {
+			return nil, p.buildMissingInputsError(missing, parametersResult.envMapping)
+		}
+		parameterPrompts = slices.DeleteFunc(parameterPrompts, func(prompt struct {
+			key   string
+			param azure.ArmTemplateParameterDefinition
+		}) bool {
+			return !isAnswered(prompt)
+		})
+	}
 
-			values, err := p.console.PromptDialog(ctx, dialog)
-			if err != nil {
-				return nil, fmt.Errorf("prompting for values: %w", err)
-			}
+	if len(parameterPrompts) > 0 && !p.console.IsNoPromptMode() {
+		// Offer to reuse answers from another environment before prompting for each parameter.
+		seeded, err := p.seedParametersFromEnvironment(ctx, parameterPrompts, locationParameters)
+		if err != nil {
+			return nil, err
+		}
 
-			for _, prompt := range parameterPrompts {
-				key := prompt.key
-				value := values[prompt.key]
-				mustSetParamAsConfig(key, value, p.env.Config, prompt.param.Secure())
-				configModified = true
-				configuredParameters[key] = azure.ArmParameter{
-					Value: value,
+		if len(seeded) > 0 {
+			parameterPrompts = slices.DeleteFunc(parameterPrompts, func(prompt struct {
+				key   string
+				param azure.ArmTemplateParameterDefinition
+			}) bool {
+				value, has := seeded[prompt.key]
+				if has {
+					mustSetParamAsConfig(prompt.key, value, p.env.Config, false)
+					configuredParameters[prompt.key] = azure.ArmParameter{
+						Value: value,
+					}
 				}
-			}
-		} else {
-			for _, prompt := range parameterPrompts {
-				key := prompt.key
+				return has
+			})
+			configModified = true
+		}
+	}
 
-				// Otherwise, prompt for the value.
-				value, err := p.promptForParameter(ctx, key, prompt.param, locationParameters)
-				if err != nil {
-					return nil, fmt.Errorf("prompting for value: %w", err)
-				}
+	// Prompt in rounds: a parameter with a promptIf condition is prompted for once the parameters its condition
+	// references have values, and skipped when the condition doesn't hold.
+	for pending := parameterPrompts; len(pending) > 0; {
+		round, deferred := nextPromptRound(pending, conditions)
+		if len(round) == 0 {
+			keys := make([]string, len(deferred))
+			for i, prompt := range deferred {
+				keys[i] = prompt.key
+			}
+			return nil, fmt.Errorf(
+				"the promptIf conditions of parameters %s depend on each other", strings.Join(keys, ", "))
+		}
 
-				if key != "location" {
-					// location param is special.
-					// It is not persisted in config, it is set in the .env directly
-					mustSetParamAsConfig(key, value, p.env.Config, prompt.param.Secure())
-				}
-				configModified = true
-				configuredParameters[key] = azure.ArmParameter{
-					Value: value,
-				}
+		round = skipUnneededPrompts(round, conditions, conditionValues(template, configuredParameters))
+		if len(round) > 0 {
+			if err := p.promptForParameters(ctx, round, locationParameters, configuredParameters); err != nil {
+				return nil, err
 			}
+			configModified = true
 		}
+
+		pending = deferred
 	}
 
 	if configModified {
@@ -3679,9 +3528,109 @@ func (p *BicepProvider) ensureParameters(
 			return nil, fmt.Errorf("saving prompt values: %w", err)
 		}
 	}
+
+	registerSecureParameterValues(template, configuredParameters)
 	return configuredParameters, nil
 }
 
+// registerSecureParameterValues registers the values of secureString and secureObject parameters with the redact
+// package, so they are masked wherever azd logs them, whether they were prompted for, read from a parameters file,
+// resolved from Key Vault or loaded from the environment config.
+func registerSecureParameterValues(template azure.ArmTemplate, parameters azure.ArmParameters) {
+	for key, parameter := range parameters {
+		if definition, has := template.Parameters[key]; has && definition.Secure() && parameter.Value != nil {
+			redact.RegisterValue(parameter.Value)
+		}
+	}
+}
+
+// promptForParameters prompts for the values of the given parameters, saving them in the environment config and adding
+// them to configuredParameters.
+func (p *BicepProvider) promptForParameters(
+	ctx context.Context,
+	parameterPrompts []struct {
+		key   string
+		param azure.ArmTemplateParameterDefinition
+	},
+	locationParameters []string,
+	configuredParameters azure.ArmParameters,
+) error {
+	// Parameters sourced from Key Vault are configured with a reference to a secret rather than a value, so they are
+	// prompted for before the other parameters.
+	remainingPrompts := parameterPrompts[:0]
+	for _, prompt := range parameterPrompts {
+		if !isKeyVaultSecretParameter(prompt.param) {
+			remainingPrompts = append(remainingPrompts, prompt)
+			continue
+		}
+
+		ref, err := p.promptForKeyVaultSecret(ctx, prompt.key, prompt.param)
+		if err != nil {
+			return fmt.Errorf("prompting for Key Vault secret: %w", err)
+		}
+
+		mustSetParamAsConfig(prompt.key, keyVaultReferenceConfigValue(ref), p.env.Config, false)
+		configuredParameters[prompt.key] = azure.ArmParameter{
+			KeyVaultReference: ref,
+		}
+	}
+	parameterPrompts = remainingPrompts
+
+	if len(parameterPrompts) == 0 {
+		return nil
+	}
+
+	if p.console.SupportsPromptDialog() {
+
+		dialog := input.PromptDialog{
+			Title: "Configure required deployment parameters",
+			Description: "The following parameters are required for deployment. " +
+				"Provide values for each parameter. They will be saved for future deployments.",
+		}
+
+		for _, prompt := range parameterPrompts {
+			dialog.Prompts = append(dialog.Prompts, p.promptDialogItemForParameter(prompt.key, prompt.param))
+		}
+
+		values, err := p.console.PromptDialog(ctx, dialog)
+		if err != nil {
+			return fmt.Errorf("prompting for values: %w", err)
+		}
+
+		for _, prompt := range parameterPrompts {
+			key := prompt.key
+			value := values[prompt.key]
+			mustSetParamAsConfig(key, value, p.env.Config, prompt.param.Secure())
+			configuredParameters[key] = azure.ArmParameter{
+				Value: value,
+			}
+		}
+
+		return nil
+	}
+
+	for _, prompt := range parameterPrompts {
+		key := prompt.key
+
+		// Otherwise, prompt for the value.
+		value, err := p.promptForParameter(ctx, key, prompt.param, locationParameters)
+		if err != nil {
+			return fmt.Errorf("prompting for value: %w", err)
+		}
+
+		if key != "location" {
+			// location param is special.
+			// It is not persisted in config, it is set in the .env directly
+			mustSetParamAsConfig(key, value, p.env.Config, prompt.param.Secure())
+		}
+		configuredParameters[key] = azure.ArmParameter{
+			Value: value,
+		}
+	}
+
+	return nil
+}
+
 var configInfraParametersKey = "infra.parameters."
 
 // mustSetParamAsConfig sets the specified key-value pair in the given config.Config object.
@@ -3797,6 +3746,7 @@ func NewBicepProvider(
 	prompters prompt.Prompter,
 	curPrincipal provisioning.CurrentPrincipalIdProvider,
 	keyvaultService keyvault.KeyVaultService,
+	purgeService *azapi.PurgeService,
 	cloud *cloud.Cloud,
 	subscriptionManager *account.SubscriptionsManager,
 	aiModelService *ai.AiModelService,
@@ -3814,6 +3764,7 @@ func NewBicepProvider(
 		prompters:           prompters,
 		curPrincipal:        curPrincipal,
 		keyvaultService:     keyvaultService,
+		purgeService:        purgeService,
 		portalUrlBase:       cloud.PortalUrlBase,
 		subscriptionManager: subscriptionManager,
 		aiModelService:      aiModelService,
@@ -15,6 +15,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -23,7 +24,6 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/apimanagement/armapimanagement"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appconfiguration/armappconfiguration"
-	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/cognitiveservices/armcognitiveservices/v2"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/keyvault/armkeyvault"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/stretchr/testify/assert"
@@ -38,12 +38,14 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
 	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
+	"github.com/azure/azure-dev/cli/azd/pkg/redact"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/bicep"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockaccount"
@@ -230,6 +232,70 @@ func TestBicepDestroy(t *testing.T) {
 		require.Contains(t, consoleOutput[0], "Deleting your resources can take some time")
 		require.Contains(t, consoleOutput[1], "")
 	})
+	t.Run("PurgesSoftDeletedResources", func(t *testing.T) {
+		deletedVault := func(resourceGroup string, name string) *armkeyvault.DeletedVault {
+			return &armkeyvault.DeletedVault{
+				Name: new(name),
+				Properties: &armkeyvault.DeletedVaultProperties{
+					VaultID: new(fmt.Sprintf(
+						"/subscriptions/SUBSCRIPTION_ID/resourceGroups/%s/providers/Microsoft.KeyVault/vaults/%s",
+						resourceGroup, name)),
+					Location: new("eastus2"),
+				},
+			}
+		}
+
+		destroy := func(t *testing.T, noPrompt bool, confirm bool) []string {
+			mockContext := mocks.NewMockContext(t.Context())
+			prepareBicepMocks(mockContext)
+			prepareStateMocks(mockContext)
+			prepareDestroyMocks(mockContext)
+			mockDeletedResources(mockContext,
+				deletedVault("RESOURCE_GROUP", "kv-old"),
+				deletedVault("OTHER_RESOURCE_GROUP", "kv-other"),
+			)
+			mockContext.Console.SetNoPromptMode(noPrompt)
+			mockContext.Console.WhenConfirm(func(options input.ConsoleOptions) bool {
+				return strings.Contains(options.Message, "these resources too")
+			}).Respond(confirm)
+
+			purged := []string{}
+			mockContext.HttpClient.When(func(request *http.Request) bool {
+				return request.Method == http.MethodPost &&
+					(strings.HasSuffix(request.URL.Path, "deletedVaults/kv-old/purge") ||
+						strings.HasSuffix(request.URL.Path, "deletedVaults/kv-other/purge"))
+			}).RespondFn(func(request *http.Request) (*http.Response, error) {
+				purged = append(purged, request.URL.Path)
+				return httpRespondFn(request)
+			})
+
+			infraProvider := createBicepProvider(t, mockContext)
+
+			destroyOptions := provisioning.NewDestroyOptions(true, true)
+			destroyResult, err := infraProvider.Destroy(*mockContext.Context, destroyOptions)
+
+			require.NoError(t, err)
+			require.NotNil(t, destroyResult)
+			require.Contains(t, strings.Join(mockContext.Console.Output(), "\n"), "kv-old")
+			return purged
+		}
+
+		t.Run("Confirmed", func(t *testing.T) {
+			// Only the soft-deleted key vault of the resource group of the deployment is purged.
+			purged := destroy(t, false, true)
+			require.Len(t, purged, 1)
+			require.Contains(t, purged[0], "deletedVaults/kv-old/purge")
+		})
+
+		t.Run("Declined", func(t *testing.T) {
+			// --purge alone doesn't purge the resources that were deleted before.
+			require.Empty(t, destroy(t, false, false))
+		})
+
+		t.Run("NoPrompt", func(t *testing.T) {
+			require.Empty(t, destroy(t, true, true))
+		})
+	})
 }
 
 func TestBicepDestroyLogAnalyticsWorkspace(t *testing.T) {
@@ -554,6 +620,7 @@ func createBicepProvider(t *testing.T, mockContext *mocks.MockContext) *BicepPro
 	envManager := &mockenv.MockEnvManager{}
 	envManager.On("Save", mock.Anything, mock.Anything).Return(nil)
 	envManager.On("Reload", mock.Anything, mock.Anything).Return(nil)
+	envManager.On("List", mock.Anything).Return([]*environment.Description{}, nil)
 
 	bicepCli := bicep.NewCli(mockContext.Console, mockContext.CommandRunner)
 	azCli := mockazapi.NewAzureClientFromMockContext(mockContext)
@@ -597,6 +664,7 @@ func createBicepProvider(t *testing.T, mockContext *mocks.MockContext) *BicepPro
 			mockContext.CoreClientOptions,
 			cloud.AzurePublic(),
 		),
+		azapi.NewPurgeService(azCli),
 		cloud.AzurePublic(),
 		nil,
 		nil,
@@ -707,6 +775,26 @@ func prepareStateMocks(mockContext *mocks.MockContext) {
 	})
 }
 
+// mockDeletedResources mocks the listing of the soft-deleted resources of the subscription, with the soft-deleted key
+// vaults.
+func mockDeletedResources(mockContext *mocks.MockContext, deletedVaults ...*armkeyvault.DeletedVault) {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && slices.ContainsFunc([]string{
+			"/deletedVaults", "/deletedManagedHSMs", "/deletedConfigurationStores", "/deletedservices", "/deletedAccounts",
+		}, func(suffix string) bool {
+			return strings.HasSuffix(request.URL.Path, suffix)
+		})
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(request.URL.Path, "/deletedVaults") {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armkeyvault.DeletedVaultListResult{
+				Value: deletedVaults,
+			})
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{"value": []any{}})
+	})
+}
+
 func prepareDestroyMocks(mockContext *mocks.MockContext) {
 	makeItem := func(resourceType azapi.AzureResourceType, resourceName string) *armresources.GenericResourceExpanded {
 		id := fmt.Sprintf("/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/%s/%s",
@@ -764,6 +852,8 @@ func prepareDestroyMocks(mockContext *mocks.MockContext) {
 		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, resourceList)
 	})
 
+	mockDeletedResources(mockContext)
+
 	// Get Key Vault
 	getKeyVaultMock(mockContext, "/vaults/kv-123", "kv-123", "eastus2")
 	getKeyVaultMock(mockContext, "/vaults/kv2-123", "kv2-123", "eastus2")
@@ -1035,6 +1125,8 @@ func prepareLogAnalyticsDestroyMocks(mockContext *mocks.MockContext) {
 		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, resourceList)
 	})
 
+	mockDeletedResources(mockContext)
+
 	getLogAnalyticsMock(mockContext, "/workspaces/la-workspace-123", "la-workspace-123")
 	getLogAnalyticsMock(mockContext, "/workspaces/la-workspace2-123", "la-workspace2-123")
 
@@ -1273,6 +1365,7 @@ func TestUserDefinedTypes(t *testing.T) {
 			mockContext.CoreClientOptions,
 			cloud.AzurePublic(),
 		),
+		azapi.NewPurgeService(azCli),
 		cloud.AzurePublic(),
 		nil,
 		nil,
@@ -1896,6 +1989,7 @@ func createBicepProviderWithEnvAndMode(
 	envManager := &mockenv.MockEnvManager{}
 	envManager.On("Save", mock.Anything, mock.Anything).Return(nil)
 	envManager.On("Reload", mock.Anything, mock.Anything).Return(nil)
+	envManager.On("List", mock.Anything).Return([]*environment.Description{}, nil)
 
 	bicepCli := bicep.NewCli(mockContext.Console, mockContext.CommandRunner)
 	azCli := mockazapi.NewAzureClientFromMockContext(mockContext)
@@ -1939,6 +2033,7 @@ func createBicepProviderWithEnvAndMode(
 			mockContext.CoreClientOptions,
 			cloud.AzurePublic(),
 		),
+		azapi.NewPurgeService(azCli),
 		cloud.AzurePublic(),
 		nil,
 		nil,
@@ -2356,6 +2451,55 @@ func TestEnsureParametersSkipsVirtualEnvMappedRequiredParameters(t *testing.T) {
 	require.NotContains(t, configuredParameters, "compositeValue")
 }
 
+func TestAnswersFileInNoPromptMode(t *testing.T) {
+	// `azd up --answers answers.yaml --no-prompt` takes the environment name and the missing parameters from the
+	// answers file, instead of generating the name from the directory and failing with missing inputs.
+	mockContext := mocks.NewMockContext(t.Context())
+	mockContext.Console.SetNoPromptMode(true)
+	console := input.NewAnswersConsole(mockContext.Console, input.NewAnswers(map[string]any{
+		"environment": "pr-42",
+		"parameters": map[string]any{
+			"vmSize": "Standard_B2s",
+		},
+	}))
+
+	azdCtx := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+	envManager, err := environment.NewManager(
+		mockContext.Container,
+		azdCtx,
+		console,
+		environment.NewLocalFileDataStore(azdCtx, config.NewFileConfigManager(config.NewManager())),
+		nil,
+	)
+	require.NoError(t, err)
+
+	env, err := envManager.Create(*mockContext.Context, environment.Spec{})
+	require.NoError(t, err)
+	require.Equal(t, "pr-42", env.Name())
+
+	armTemplate := azure.ArmTemplate{
+		Schema:         "https://schema.management.azure.com/schemas/2018-05-01/subscriptionDeploymentTemplate.json#",
+		ContentVersion: "1.0.0.0",
+		Parameters: azure.ArmTemplateParameterDefinitions{
+			"environmentName": {Type: "string"},
+			"location":        {Type: "string"},
+			"vmSize":          {Type: "string"},
+		},
+		Outputs: azure.ArmTemplateOutputs{},
+	}
+
+	infraProvider := createBicepProviderWithEnvAndMode(
+		t, mockContext, armTemplate, map[string]string{}, provisioning.ModeDestroy)
+	infraProvider.console = console
+
+	compileResult, err := infraProvider.compileBicep(*mockContext.Context)
+	require.NoError(t, err)
+
+	configuredParameters, err := infraProvider.ensureParameters(*mockContext.Context, compileResult.Template)
+	require.NoError(t, err)
+	require.Equal(t, "Standard_B2s", configuredParameters["vmSize"].Value)
+}
+
 func TestPlannedOutputsSkipsSecureOutputs(t *testing.T) {
 	mockContext := mocks.NewMockContext(t.Context())
 
@@ -2585,20 +2729,20 @@ func TestItemsCountAsText(t *testing.T) {
 		})
 	})
 
-	t.Run("SkipsZeroCountItems", func(t *testing.T) {
+	t.Run("CountsByDisplayType", func(t *testing.T) {
 		t.Parallel()
-		text := itemsCountAsText([]itemToPurge{
-			{resourceType: "Key Vaults", count: 0},
-			{resourceType: "App Configurations", count: 2},
+		text := itemsCountAsText([]*azapi.PurgeTarget{
+			{Name: "ac1", DisplayType: "App Configurations"},
+			{Name: "kv1", DisplayType: "Key Vaults"},
+			{Name: "ac2", DisplayType: "App Configurations"},
 		})
-		require.Contains(t, text, "2 App Configurations")
-		require.NotContains(t, text, "Key Vaults")
+		require.Equal(t, "2 App Configurations and 1 Key Vaults", text)
 	})
 
 	t.Run("FormatsSingleItem", func(t *testing.T) {
 		t.Parallel()
-		text := itemsCountAsText([]itemToPurge{
-			{resourceType: "Managed HSMs", count: 1},
+		text := itemsCountAsText([]*azapi.PurgeTarget{
+			{Name: "hsm1", DisplayType: "Managed HSMs"},
 		})
 		require.Contains(t, text, "1 Managed HSMs")
 	})
@@ -2962,29 +3106,6 @@ func mustMarshal(t *testing.T, v any) json.RawMessage {
 	return b
 }
 
-// TestCognitiveAccountsByKind verifies grouping by kind and the FormRecognizer rename.
-func TestCognitiveAccountsByKind(t *testing.T) {
-	t.Parallel()
-
-	input := map[string][]armcognitiveservices.Account{
-		"rg1": {
-			{Kind: new("OpenAI")},
-			{Kind: new("FormRecognizer")},
-		},
-		"rg2": {
-			{Kind: new("OpenAI")},
-		},
-	}
-
-	got := cognitiveAccountsByKind(input)
-
-	require.Contains(t, got, "OpenAI")
-	require.Len(t, got["OpenAI"], 2)
-	require.Contains(t, got, "Document Intelligence")
-	require.Len(t, got["Document Intelligence"], 1)
-	require.NotContains(t, got, "FormRecognizer")
-}
-
 // TestAutoGenerate covers missing-config error and a successful generation path.
 func TestAutoGenerate(t *testing.T) {
 	t.Parallel()
@@ -3436,7 +3557,7 @@ func TestValidateErrors(t *testing.T) {
 }
 
 // TestPurgeHelpersEmptyInputs exercises the fast-path (empty input) and
-// skip-path branches of the purge/getToPurge helpers. None of these paths
+// skip-path branches of the purge helpers. None of these paths
 // require HTTP mocks.
 func TestPurgeHelpersEmptyInputs(t *testing.T) {
 	t.Parallel()
@@ -3445,60 +3566,9 @@ func TestPurgeHelpersEmptyInputs(t *testing.T) {
 	prepareBicepMocks(mockContext)
 	p := createBicepProvider(t, mockContext)
 	ctx := t.Context()
-	empty := map[string][]*azapi.Resource{}
 
-	t.Run("getKeyVaults", func(t *testing.T) {
-		got, err := p.getKeyVaults(ctx, empty)
-		require.NoError(t, err)
-		require.Empty(t, got)
-	})
-	t.Run("getKeyVaultsToPurge", func(t *testing.T) {
-		got, err := p.getKeyVaultsToPurge(ctx, empty)
-		require.NoError(t, err)
-		require.Empty(t, got)
-	})
-	t.Run("getManagedHSMs", func(t *testing.T) {
-		got, err := p.getManagedHSMs(ctx, empty)
-		require.NoError(t, err)
-		require.Empty(t, got)
-	})
-	t.Run("getManagedHSMsToPurge", func(t *testing.T) {
-		got, err := p.getManagedHSMsToPurge(ctx, empty)
-		require.NoError(t, err)
-		require.Empty(t, got)
-	})
-	t.Run("getCognitiveAccountsToPurge", func(t *testing.T) {
-		got, err := p.getCognitiveAccountsToPurge(ctx, empty)
-		require.NoError(t, err)
-		require.Empty(t, got)
-	})
-	t.Run("getAppConfigsToPurge", func(t *testing.T) {
-		got, err := p.getAppConfigsToPurge(ctx, empty)
-		require.NoError(t, err)
-		require.Empty(t, got)
-	})
-	t.Run("getApiManagementsToPurge", func(t *testing.T) {
-		got, err := p.getApiManagementsToPurge(ctx, empty)
-		require.NoError(t, err)
-		require.Empty(t, got)
-	})
-	t.Run("purgeKeyVaultsEmpty", func(t *testing.T) {
-		require.NoError(t, p.purgeKeyVaults(ctx, nil, true))
-	})
-	t.Run("purgeManagedHSMsEmpty", func(t *testing.T) {
-		require.NoError(t, p.purgeManagedHSMs(ctx, nil, true))
-	})
-	t.Run("purgeCognitiveAccountsEmpty", func(t *testing.T) {
-		require.NoError(t, p.purgeCognitiveAccounts(ctx, nil, true))
-	})
-	t.Run("purgeAppConfigsEmpty", func(t *testing.T) {
-		require.NoError(t, p.purgeAppConfigs(ctx, nil, true))
-	})
-	t.Run("purgeAPIManagementEmpty", func(t *testing.T) {
-		require.NoError(t, p.purgeAPIManagement(ctx, nil, true))
-	})
-	t.Run("forceDeleteLogAnalyticsWorkspacesEmpty", func(t *testing.T) {
-		require.NoError(t, p.forceDeleteLogAnalyticsWorkspaces(ctx, nil))
+	t.Run("purgeResourcesEmpty", func(t *testing.T) {
+		require.NoError(t, p.purgeResources(ctx, nil, true))
 	})
 	t.Run("purgeItemsEmpty", func(t *testing.T) {
 		require.NoError(t, p.purgeItems(ctx, nil, provisioning.NewDestroyOptions(false, false)))
@@ -3523,35 +3593,83 @@ func TestPurgeHelpersEmptyInputs(t *testing.T) {
 	})
 }
 
-// TestPurgeCognitiveAccountsValidationErrors verifies early-return errors for
-// accounts missing required fields.
-func TestPurgeCognitiveAccountsValidationErrors(t *testing.T) {
+func TestSplitPurgeTargets(t *testing.T) {
 	t.Parallel()
 
-	mockContext := mocks.NewMockContext(t.Context())
-	prepareBicepMocks(mockContext)
-	p := createBicepProvider(t, mockContext)
-	ctx := t.Context()
-
-	t.Run("missingName", func(t *testing.T) {
-		err := p.purgeCognitiveAccounts(ctx, []cognitiveAccount{
-			{account: armcognitiveservices.Account{}, resourceGroup: "rg"},
-		}, false)
-		require.Error(t, err)
+	beforeDelete, afterDelete := splitPurgeTargets([]*azapi.PurgeTarget{
+		{Name: "kv1", DisplayType: "Key Vault"},
+		{Name: "la1", DisplayType: "Log Analytics Workspace", BeforeDelete: true},
+		{Name: "ac1", DisplayType: "App Configuration"},
 	})
-	t.Run("missingId", func(t *testing.T) {
-		err := p.purgeCognitiveAccounts(ctx, []cognitiveAccount{
-			{account: armcognitiveservices.Account{Name: new("n")}, resourceGroup: "rg"},
-		}, false)
-		require.Error(t, err)
+
+	require.Len(t, beforeDelete, 1)
+	require.Equal(t, "la1", beforeDelete[0].Name)
+	require.Len(t, afterDelete, 2)
+	require.Equal(t, []string{"Key Vault", "App Configuration"}, purgeDisplayTypes(afterDelete))
+}
+
+func TestRegisterSecureParameterValues(t *testing.T) {
+	template := azure.ArmTemplate{
+		Parameters: azure.ArmTemplateParameterDefinitions{
+			"adminPassword": {Type: "securestring"},
+			"connection":    {Type: "secureObject"},
+			"appName":       {Type: "string"},
+		},
+	}
+
+	registerSecureParameterValues(template, azure.ArmParameters{
+		"adminPassword": {Value: "register-secure-password"},
+		"connection":    {Value: map[string]any{"key": "register-secure-object-key"}},
+		"appName":       {Value: "register-secure-app-name"},
 	})
-	t.Run("missingLocation", func(t *testing.T) {
-		err := p.purgeCognitiveAccounts(ctx, []cognitiveAccount{
-			{account: armcognitiveservices.Account{
-				Name: new("n"),
-				ID:   new("/subscriptions/x/resourceGroups/rg/providers/p/accounts/n"),
-			}, resourceGroup: "rg"},
-		}, false)
-		require.Error(t, err)
+
+	require.Equal(t, redact.Replacement, redact.String("register-secure-password"))
+	require.Equal(t, redact.Replacement, redact.String("register-secure-object-key"))
+	require.Equal(t, "register-secure-app-name", redact.String("register-secure-app-name"))
+}
+
+func TestResourceTimings(t *testing.T) {
+	operation := func(id, name, resourceType, state, duration string) *armresources.DeploymentOperation {
+		return &armresources.DeploymentOperation{
+			ID: new(id),
+			Properties: &armresources.DeploymentOperationProperties{
+				ProvisioningOperation: to.Ptr(armresources.ProvisioningOperationCreate),
+				ProvisioningState:     new(state),
+				Duration:              new(duration),
+				TargetResource: &armresources.TargetResource{
+					ResourceType: new(resourceType),
+					ResourceName: new(name),
+				},
+			},
+		}
+	}
+
+	timings := resourceTimings([]*armresources.DeploymentOperation{
+		operation("1", "kv-web", string(azapi.AzureResourceTypeKeyVault), "Failed", "PT21.4567S"),
+		operation("2", "resources", string(azapi.AzureResourceTypeDeployment), "Succeeded", "PT4M12S"),
+		operation("3", "invalid", string(azapi.AzureResourceTypeWebSite), "Succeeded", "not-a-duration"),
+		operation("4", "app-web", string(azapi.AzureResourceTypeWebSite), "Succeeded", "PT1M32S"),
 	})
+
+	require.Equal(t, []provisioning.ResourceTiming{
+		{
+			Name:     "resources",
+			Type:     string(azapi.AzureResourceTypeDeployment),
+			Module:   true,
+			State:    "Succeeded",
+			Duration: 4*time.Minute + 12*time.Second,
+		},
+		{
+			Name:     "app-web",
+			Type:     string(azapi.AzureResourceTypeWebSite),
+			State:    "Succeeded",
+			Duration: 92 * time.Second,
+		},
+		{
+			Name:     "kv-web",
+			Type:     string(azapi.AzureResourceTypeKeyVault),
+			State:    "Failed",
+			Duration: 21*time.Second + 456*time.Millisecond,
+		},
+	}, timings)
 }
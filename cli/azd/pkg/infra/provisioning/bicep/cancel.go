@@ -0,0 +1,208 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// Cancel finds the in-flight deployment for the current environment and layer, submits an ARM cancel request and
+// waits for the deployment (and any nested deployments) to reach a terminal state. The returned result reports the
+// final state of every resource the deployment touched, so the user can tell which resources were partially created.
+func (p *BicepProvider) Cancel(ctx context.Context) (*provisioning.CancelResult, error) {
+	p.console.ShowSpinner(ctx, "Finding in-progress Azure deployment", input.Step)
+	compileResult, err := p.compileBicep(ctx)
+	if err != nil {
+		p.console.StopSpinner(ctx, "", input.StepFailed)
+		return nil, fmt.Errorf("compiling bicep template: %w", err)
+	}
+
+	scope, err := p.scopeForTemplate(compileResult.Template)
+	if err != nil {
+		p.console.StopSpinner(ctx, "", input.StepFailed)
+		return nil, fmt.Errorf("computing deployment scope: %w", err)
+	}
+
+	deployments, err := scope.ListDeployments(ctx)
+	if err != nil {
+		p.console.StopSpinner(ctx, "", input.StepFailed)
+		return nil, fmt.Errorf("listing deployments: %w", err)
+	}
+
+	active := findActiveDeployment(deployments, p.env.Name(), p.layer)
+	if active == nil {
+		p.console.StopSpinner(ctx, "", input.StepDone)
+		return nil, provisioning.ErrNoActiveDeployment
+	}
+
+	deployment, err := p.createDeploymentFromArmDeployment(scope, active.Name)
+	if err != nil {
+		p.console.StopSpinner(ctx, "", input.StepFailed)
+		return nil, err
+	}
+
+	result := &provisioning.CancelResult{
+		DeploymentName:    active.Name,
+		ProvisioningState: string(active.ProvisioningState),
+		PortalUrl:         p.deploymentUrl(ctx, deployment),
+	}
+
+	spinnerMessage := fmt.Sprintf("Canceling Azure deployment (%s)", output.WithHighLightFormat(active.Name))
+	p.console.ShowSpinner(ctx, spinnerMessage, input.Step)
+
+	cancelReqCtx, cancelReqDone := context.WithTimeout(ctx, cancelRequestTimeout)
+	defer cancelReqDone()
+
+	if err := deployment.Cancel(cancelReqCtx); err != nil {
+		p.console.StopSpinner(ctx, spinnerMessage, input.StepFailed)
+		if errors.Is(err, azapi.ErrCancelNotSupported) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("%w: %w", provisioning.ErrDeploymentCancelFailed, err)
+	}
+
+	pollCtx, pollDone := context.WithTimeout(ctx, cancelOverallTimeout)
+	defer pollDone()
+
+	state, timedOut := p.awaitTopLevelTerminal(pollCtx, deployment)
+	switch {
+	case timedOut:
+		result.ProvisioningState = string(azapi.DeploymentProvisioningStateCanceling)
+		p.console.StopSpinner(ctx, spinnerMessage, input.StepWarning)
+		p.console.Message(ctx, output.WithWarningFormat(
+			"Azure didn't confirm cancellation within %s. Cancellation may still complete.", cancelOverallTimeout))
+	case state == azapi.DeploymentProvisioningStateCanceled:
+		result.ProvisioningState = string(state)
+		if stuck := p.cancelAndAwaitNested(pollCtx, deployment); len(stuck) > 0 {
+			p.console.StopSpinner(ctx, spinnerMessage, input.StepWarning)
+			for _, d := range stuck {
+				p.console.Message(ctx, output.WithWarningFormat(
+					"Nested deployment %s did not finish canceling within %s.", d.Name(), cancelOverallTimeout))
+			}
+		} else {
+			p.console.StopSpinner(ctx, spinnerMessage, input.StepDone)
+		}
+	default:
+		// The deployment reached Succeeded/Failed/Deleted before the cancel request took effect.
+		result.ProvisioningState = string(state)
+		p.console.StopSpinner(ctx, spinnerMessage, input.StepWarning)
+		p.console.Message(ctx, output.WithWarningFormat(
+			"The deployment reached the %s state before the cancel request took effect.", state))
+	}
+
+	var operations []*armresources.DeploymentOperation
+	err = p.resourceManager.WalkDeploymentOperations(ctx, deployment,
+		func(ctx context.Context, operation *armresources.DeploymentOperation) error {
+			operations = append(operations, operation)
+			return nil
+		})
+	if err != nil {
+		// The cancel itself succeeded; failing to enumerate the resources only degrades the report.
+		log.Printf("cancel: failed to list deployment operations: %v", err)
+	}
+	result.Resources = canceledResources(operations)
+
+	return result, nil
+}
+
+// findActiveDeployment returns the most recent deployment tagged for the given environment and layer that is not in a
+// terminal provisioning state, or nil when there is none.
+func findActiveDeployment(
+	deployments []*azapi.ResourceDeployment,
+	envName string,
+	layerName string,
+) *azapi.ResourceDeployment {
+	var active []*azapi.ResourceDeployment
+	for _, deployment := range deployments {
+		if deployment == nil || isTerminalProvisioningState(deployment.ProvisioningState) {
+			continue
+		}
+
+		envTag, has := deployment.Tags[azure.TagKeyAzdEnvName]
+		if !has || envTag == nil || *envTag != envName {
+			continue
+		}
+
+		layerTag := ""
+		if tag, has := deployment.Tags[azure.TagKeyAzdLayerName]; has && tag != nil {
+			layerTag = *tag
+		}
+		if layerTag != layerName {
+			continue
+		}
+
+		active = append(active, deployment)
+	}
+
+	if len(active) == 0 {
+		return nil
+	}
+
+	slices.SortFunc(active, func(x, y *azapi.ResourceDeployment) int {
+		return y.Timestamp.Compare(x.Timestamp)
+	})
+
+	return active[0]
+}
+
+// canceledResources converts the operations of a canceled deployment into the list of resources it touched, keeping
+// the most recent state of each resource. Nested deployments are omitted since their resources are listed directly.
+func canceledResources(operations []*armresources.DeploymentOperation) []provisioning.CanceledResource {
+	latest := map[string]*armresources.DeploymentOperation{}
+	for _, op := range operations {
+		if op == nil || op.Properties == nil || op.Properties.TargetResource == nil ||
+			op.Properties.TargetResource.ID == nil || op.Properties.TargetResource.ResourceType == nil ||
+			op.Properties.ProvisioningState == nil {
+			continue
+		}
+
+		if strings.EqualFold(*op.Properties.TargetResource.ResourceType, string(azapi.AzureResourceTypeDeployment)) {
+			continue
+		}
+
+		id := *op.Properties.TargetResource.ID
+		if prev, has := latest[id]; has && prev.Properties.Timestamp != nil && op.Properties.Timestamp != nil &&
+			prev.Properties.Timestamp.After(*op.Properties.Timestamp) {
+			continue
+		}
+		latest[id] = op
+	}
+
+	resources := make([]provisioning.CanceledResource, 0, len(latest))
+	for id, op := range latest {
+		name := id
+		if op.Properties.TargetResource.ResourceName != nil {
+			name = *op.Properties.TargetResource.ResourceName
+		}
+
+		resources = append(resources, provisioning.CanceledResource{
+			Id:                id,
+			Name:              name,
+			Type:              *op.Properties.TargetResource.ResourceType,
+			ProvisioningState: *op.Properties.ProvisioningState,
+		})
+	}
+
+	slices.SortFunc(resources, func(a, b provisioning.CanceledResource) int {
+		return strings.Compare(a.Id, b.Id)
+	})
+
+	return resources
+}
+
+// Ensure BicepProvider supports canceling in-flight deployments.
+var _ provisioning.Canceler = (*BicepProvider)(nil)
@@ -0,0 +1,82 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindActiveDeployment(t *testing.T) {
+	now := time.Now()
+	deployment := func(
+		name string, state azapi.DeploymentProvisioningState, env string, layer string, age time.Duration,
+	) *azapi.ResourceDeployment {
+		tags := map[string]*string{azure.TagKeyAzdEnvName: new(env)}
+		if layer != "" {
+			tags[azure.TagKeyAzdLayerName] = new(layer)
+		}
+
+		return &azapi.ResourceDeployment{
+			Name:              name,
+			ProvisioningState: state,
+			Tags:              tags,
+			Timestamp:         now.Add(-age),
+		}
+	}
+
+	deployments := []*azapi.ResourceDeployment{
+		deployment("done", azapi.DeploymentProvisioningStateSucceeded, "dev", "", 0),
+		deployment("other-env", azapi.DeploymentProvisioningStateRunning, "prod", "", 0),
+		deployment("older", azapi.DeploymentProvisioningStateRunning, "dev", "", time.Hour),
+		deployment("newest", azapi.DeploymentProvisioningStateAccepted, "dev", "", time.Minute),
+		deployment("network-layer", azapi.DeploymentProvisioningStateRunning, "dev", "network", 0),
+	}
+
+	active := findActiveDeployment(deployments, "dev", "")
+	require.NotNil(t, active)
+	require.Equal(t, "newest", active.Name)
+
+	active = findActiveDeployment(deployments, "dev", "network")
+	require.NotNil(t, active)
+	require.Equal(t, "network-layer", active.Name)
+
+	require.Nil(t, findActiveDeployment(deployments, "test", ""))
+}
+
+func TestCanceledResources(t *testing.T) {
+	now := time.Now()
+	op := func(id, resourceType, state string, at time.Time) *armresources.DeploymentOperation {
+		return &armresources.DeploymentOperation{
+			Properties: &armresources.DeploymentOperationProperties{
+				ProvisioningState: new(state),
+				Timestamp:         new(at),
+				TargetResource: &armresources.TargetResource{
+					ID:           new(id),
+					ResourceName: new(id),
+					ResourceType: new(resourceType),
+				},
+			},
+		}
+	}
+
+	resources := canceledResources([]*armresources.DeploymentOperation{
+		op("web", "Microsoft.Web/sites", "Running", now.Add(-time.Minute)),
+		op("web", "Microsoft.Web/sites", "Canceled", now),
+		op("db", "Microsoft.DBforPostgreSQL/flexibleServers", "Succeeded", now),
+		op("nested", string(azapi.AzureResourceTypeDeployment), "Canceled", now),
+		{Properties: &armresources.DeploymentOperationProperties{}},
+	})
+
+	require.Equal(t, []provisioning.CanceledResource{
+		{Id: "db", Name: "db", Type: "Microsoft.DBforPostgreSQL/flexibleServers", ProvisioningState: "Succeeded"},
+		{Id: "web", Name: "web", Type: "Microsoft.Web/sites", ProvisioningState: "Canceled"},
+	}, resources)
+}
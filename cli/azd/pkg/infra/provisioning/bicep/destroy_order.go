@@ -0,0 +1,278 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/errorhandler"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+)
+
+// Resources are deleted in stages, so a resource is only deleted once the resources that depend on it are deleted.
+const (
+	// deletionStageDependents are the resources that attach to the network of other resources, and that keep their
+	// subnets or DNS zones in use. They are deleted before any resource group, since they can be in another resource
+	// group than the network they use.
+	deletionStageDependents = iota
+	// deletionStageWorkloads are the resources that no other resource depends on.
+	deletionStageWorkloads
+	// deletionStageNetworks are the virtual networks, deleted once the resources in their subnets are deleted.
+	deletionStageNetworks
+	// deletionStageNetworkAttachments are the resources attached to subnets, deleted once their networks are deleted.
+	deletionStageNetworkAttachments
+)
+
+// deletionStages maps the resource types, in lower case, that aren't workloads to their deletion stage.
+var deletionStages = map[string]int{
+	"microsoft.network/privateendpoints":                    deletionStageDependents,
+	"microsoft.network/privatednszones/virtualnetworklinks": deletionStageDependents,
+	"microsoft.network/virtualnetworks":                     deletionStageNetworks,
+	"microsoft.network/networksecuritygroups":               deletionStageNetworkAttachments,
+	"microsoft.network/routetables":                         deletionStageNetworkAttachments,
+	"microsoft.network/natgateways":                         deletionStageNetworkAttachments,
+}
+
+// deletionStage returns the stage the resources of the type are deleted in.
+func deletionStage(resourceType string) int {
+	if stage, has := deletionStages[strings.ToLower(resourceType)]; has {
+		return stage
+	}
+
+	return deletionStageWorkloads
+}
+
+// sortByDeletionOrder sorts the resources by the stage they are deleted in, keeping the order of the resources of the
+// same stage.
+func sortByDeletionOrder(resources []*azapi.Resource) {
+	slices.SortStableFunc(resources, func(a, b *azapi.Resource) int {
+		return deletionStage(a.Type) - deletionStage(b.Type)
+	})
+}
+
+// prepareDeletion removes what would make the deletion of the resources fail, or would outlive them: the locks of the
+// resources, their diagnostic settings and the resources that depend on their network. The ResourceCleanupService is
+// resolved lazily via the service locator, and the resources are deleted as before when it can't be resolved.
+func (p *BicepProvider) prepareDeletion(
+	ctx context.Context,
+	options provisioning.DestroyOptions,
+	toDelete map[string][]*azapi.Resource,
+	kept map[string][]*azapi.Resource,
+) error {
+	var cleanupService *azapi.ResourceCleanupService
+	if err := p.serviceLocator.Resolve(&cleanupService); err != nil {
+		log.Printf("could not resolve ResourceCleanupService, skipping deletion preparation: %v", err)
+		return nil
+	}
+
+	if err := p.removeLocks(ctx, cleanupService, options, toDelete, kept); err != nil {
+		return err
+	}
+
+	p.deleteDiagnosticSettings(ctx, cleanupService, toDelete)
+
+	dependents := []*azapi.Resource{}
+	for _, resourceGroupName := range slices.Sorted(maps.Keys(toDelete)) {
+		for _, resource := range toDelete[resourceGroupName] {
+			if deletionStage(resource.Type) == deletionStageDependents {
+				dependents = append(dependents, resource)
+			}
+		}
+	}
+
+	if err := p.deleteResourcesInPasses(ctx, dependents); err != nil {
+		return fmt.Errorf("deleting dependent resources: %w", err)
+	}
+
+	return nil
+}
+
+// removeLocks finds the locks that prevent the deletion of the resources, and removes them once the user confirms.
+// Locks are set on purpose to protect resources, so they are never removed without confirmation, even with --force.
+func (p *BicepProvider) removeLocks(
+	ctx context.Context,
+	cleanupService *azapi.ResourceCleanupService,
+	options provisioning.DestroyOptions,
+	toDelete map[string][]*azapi.Resource,
+	kept map[string][]*azapi.Resource,
+) error {
+	subscriptionId := p.env.GetSubscriptionId()
+
+	locks := []*azapi.ManagementLock{}
+	for _, resourceGroupName := range slices.Sorted(maps.Keys(toDelete)) {
+		resourceGroupLocks, err := cleanupService.ListResourceGroupLocks(ctx, subscriptionId, resourceGroupName)
+		if err != nil {
+			// The deletion reports the locks that prevent it, so the locks that can't be listed aren't an error.
+			log.Printf("could not list locks of resource group '%s': %v", resourceGroupName, err)
+			continue
+		}
+
+		resourceGroupId := azure.ResourceGroupRID(subscriptionId, resourceGroupName)
+		_, keepsResources := kept[resourceGroupName]
+		for _, lock := range resourceGroupLocks {
+			if locksDeletion(lock, resourceGroupId, keepsResources, toDelete[resourceGroupName]) {
+				locks = append(locks, lock)
+			}
+		}
+	}
+
+	if len(locks) == 0 {
+		return nil
+	}
+
+	lines := []string{"Resource locks prevent the deletion of your resources:", ""}
+	for _, lock := range locks {
+		lines = append(lines, fmt.Sprintf("  • %s (%s) on %s", lock.Name, lock.Level, lockScopeName(lock)))
+	}
+	p.console.MessageUxItem(ctx, &ux.MultilineMessage{Lines: append(lines, "")})
+
+	removeLocks := false
+	if !options.Force() {
+		confirmed, err := p.console.Confirm(ctx, input.ConsoleOptions{
+			Message: fmt.Sprintf(
+				"Remove %d lock(s) to continue deleting your resources?", len(locks)),
+			DefaultValue: false,
+		})
+		if err != nil {
+			return fmt.Errorf("prompting to remove locks: %w", err)
+		}
+
+		removeLocks = confirmed
+	}
+
+	if !removeLocks {
+		return &errorhandler.ErrorWithSuggestion{
+			Err:        fmt.Errorf("%d resource lock(s) prevent deletion: %w", len(locks), provisioning.ErrResourcesLocked),
+			Message:    "No resources were deleted.",
+			Suggestion: "Run 'azd down' interactively to remove the locks, or remove them in the Azure Portal.",
+		}
+	}
+
+	for _, lock := range locks {
+		message := fmt.Sprintf("Removing lock %s", output.WithHighLightFormat(lock.Name))
+		p.console.ShowSpinner(ctx, message, input.Step)
+		if err := cleanupService.DeleteLock(ctx, subscriptionId, lock); err != nil {
+			p.console.StopSpinner(ctx, message, input.StepFailed)
+			return err
+		}
+
+		p.console.StopSpinner(ctx,
+			fmt.Sprintf("Removed lock %s", output.WithHighLightFormat(lock.Name)), input.StepDone)
+	}
+
+	return nil
+}
+
+// locksDeletion reports whether the lock prevents the deletion of the resources of the resource group. When the
+// resource group is deleted, every lock in it does. Otherwise, only the locks of the resource group and of the
+// resources to delete, or of their parent resources, do. Locks inherited from the subscription are never removed.
+func locksDeletion(
+	lock *azapi.ManagementLock,
+	resourceGroupId string,
+	keepsResources bool,
+	toDelete []*azapi.Resource,
+) bool {
+	scope := strings.ToLower(lock.Scope())
+	resourceGroupId = strings.ToLower(resourceGroupId)
+	if scope != resourceGroupId && !strings.HasPrefix(scope, resourceGroupId+"/") {
+		return false
+	}
+
+	if !keepsResources || scope == resourceGroupId {
+		return true
+	}
+
+	return slices.ContainsFunc(toDelete, func(resource *azapi.Resource) bool {
+		return strings.HasPrefix(strings.ToLower(resource.Id)+"/", scope+"/")
+	})
+}
+
+// lockScopeName returns the name of the resource group or the resource the lock is applied to.
+func lockScopeName(lock *azapi.ManagementLock) string {
+	scope := lock.Scope()
+	return scope[strings.LastIndex(scope, "/")+1:]
+}
+
+// deleteDiagnosticSettings deletes the diagnostic settings of the resources. Diagnostic settings aren't deleted with
+// their resource, and would conflict with the settings of the resources created by the next provision. The settings
+// that can't be listed or deleted are logged and left, since they don't prevent the deletion of the resources.
+func (p *BicepProvider) deleteDiagnosticSettings(
+	ctx context.Context,
+	cleanupService *azapi.ResourceCleanupService,
+	toDelete map[string][]*azapi.Resource,
+) {
+	subscriptionId := p.env.GetSubscriptionId()
+	message := "Deleting diagnostic settings"
+	p.console.ShowSpinner(ctx, message, input.Step)
+
+	deleted := 0
+	for _, resourceGroupName := range slices.Sorted(maps.Keys(toDelete)) {
+		for _, resource := range toDelete[resourceGroupName] {
+			if !isTopLevelResource(resource) {
+				continue
+			}
+
+			// Resources of types without diagnostic settings fail to list them.
+			settings, err := cleanupService.ListDiagnosticSettings(ctx, subscriptionId, resource.Id)
+			if err != nil {
+				log.Printf("could not list diagnostic settings of '%s': %v", resource.Id, err)
+				continue
+			}
+
+			for _, setting := range settings {
+				if err := cleanupService.DeleteDiagnosticSetting(ctx, subscriptionId, setting); err != nil {
+					log.Printf("could not delete diagnostic setting '%s': %v", setting.Id, err)
+					continue
+				}
+
+				deleted++
+			}
+		}
+	}
+
+	if deleted == 0 {
+		p.console.StopSpinner(ctx, "", input.StepDone)
+		return
+	}
+
+	p.console.StopSpinner(ctx, fmt.Sprintf("Deleted %d diagnostic setting(s)", deleted), input.StepDone)
+}
+
+// deleteResourcesInPasses deletes the resources in their deletion order. A resource that fails to delete, for example
+// because a resource that depends on it isn't deleted yet, is deleted again in the next pass.
+func (p *BicepProvider) deleteResourcesInPasses(ctx context.Context, resources []*azapi.Resource) error {
+	pending := slices.Clone(resources)
+	sortByDeletionOrder(pending)
+
+	for attempt := 1; len(pending) > 0; attempt++ {
+		failed := []*azapi.Resource{}
+		errs := []error{}
+		for _, resource := range pending {
+			if err := p.deleteResource(ctx, resource); err != nil {
+				failed = append(failed, resource)
+				errs = append(errs, err)
+			}
+		}
+
+		// Stop when no resource could be deleted in this pass, since the next pass wouldn't delete more.
+		if len(failed) > 0 && (len(failed) == len(pending) || attempt == maxResourceDeletionAttempts) {
+			return errors.Join(errs...)
+		}
+
+		pending = failed
+	}
+
+	return nil
+}
@@ -0,0 +1,229 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/errorhandler"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testNetworkResourceGroup = "/subscriptions/SUB/resourceGroups/rg-network"
+
+func testNetworkResources() map[string][]*azapi.Resource {
+	return map[string][]*azapi.Resource{
+		"rg-network": {
+			{
+				Id:   testNetworkResourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet",
+				Name: "vnet",
+				Type: "Microsoft.Network/virtualNetworks",
+			},
+			{
+				Id:   testNetworkResourceGroup + "/providers/Microsoft.Network/networkSecurityGroups/nsg",
+				Name: "nsg",
+				Type: "Microsoft.Network/networkSecurityGroups",
+			},
+			{
+				Id:   testNetworkResourceGroup + "/providers/Microsoft.Web/sites/app",
+				Name: "app",
+				Type: "Microsoft.Web/sites",
+			},
+			{
+				Id:   testNetworkResourceGroup + "/providers/Microsoft.Network/privateEndpoints/pe",
+				Name: "pe",
+				Type: "Microsoft.Network/privateEndpoints",
+			},
+		},
+	}
+}
+
+func TestSortByDeletionOrder(t *testing.T) {
+	resources := testNetworkResources()["rg-network"]
+	sortByDeletionOrder(resources)
+
+	names := []string{}
+	for _, resource := range resources {
+		names = append(names, resource.Name)
+	}
+
+	assert.Equal(t, []string{"pe", "app", "vnet", "nsg"}, names)
+}
+
+func TestLocksDeletion(t *testing.T) {
+	lock := func(scope string) *azapi.ManagementLock {
+		return &azapi.ManagementLock{Id: scope + "/providers/Microsoft.Authorization/locks/lock", Name: "lock"}
+	}
+	toDelete := []*azapi.Resource{
+		{Id: testNetworkResourceGroup + "/providers/Microsoft.Web/sites/app"},
+	}
+
+	tests := map[string]struct {
+		lock           *azapi.ManagementLock
+		keepsResources bool
+		expected       bool
+	}{
+		"ResourceGroup": {
+			lock:           lock(testNetworkResourceGroup),
+			keepsResources: true,
+			expected:       true,
+		},
+		"ResourceInDeletedResourceGroup": {
+			lock:     lock(testNetworkResourceGroup + "/providers/Microsoft.Storage/storageAccounts/st"),
+			expected: true,
+		},
+		"DeletedResource": {
+			lock:           lock(testNetworkResourceGroup + "/providers/Microsoft.Web/sites/APP"),
+			keepsResources: true,
+			expected:       true,
+		},
+		"KeptResource": {
+			lock:           lock(testNetworkResourceGroup + "/providers/Microsoft.Storage/storageAccounts/st"),
+			keepsResources: true,
+			expected:       false,
+		},
+		"Subscription": {
+			lock:     lock("/subscriptions/SUB"),
+			expected: false,
+		},
+		"OtherResourceGroup": {
+			lock:     lock("/subscriptions/SUB/resourceGroups/rg-network-2"),
+			expected: false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.expected, locksDeletion(tt.lock, testNetworkResourceGroup, tt.keepsResources, toDelete))
+		})
+	}
+}
+
+// mockDeletionPreparation mocks the lock of the resource group, the diagnostic setting of the app and the deletion of
+// the private endpoint, and returns the names of the deleted resources in order.
+func mockDeletionPreparation(mockContext *mocks.MockContext) *[]string {
+	ioc.RegisterInstance(mockContext.Container, azapi.NewResourceCleanupService(
+		mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions))
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			request.URL.Path == testNetworkResourceGroup+"/providers/Microsoft.Authorization/locks"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"value": []map[string]any{
+				{
+					"id":         testNetworkResourceGroup + "/providers/Microsoft.Authorization/locks/do-not-delete",
+					"name":       "do-not-delete",
+					"properties": map[string]any{"level": "CanNotDelete"},
+				},
+			},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			strings.HasSuffix(request.URL.Path, "/providers/Microsoft.Insights/diagnosticSettings")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		if !strings.Contains(request.URL.Path, "/Microsoft.Web/sites/app/") {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusBadRequest)
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"value": []map[string]any{
+				{
+					"id": testNetworkResourceGroup +
+						"/providers/Microsoft.Web/sites/app/providers/Microsoft.Insights/diagnosticSettings/logs",
+					"name": "logs",
+				},
+			},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && request.URL.Path == "/subscriptions/SUB/providers/Microsoft.Network"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armresources.Provider{
+			Namespace: new("Microsoft.Network"),
+			ResourceTypes: []*armresources.ProviderResourceType{
+				{ResourceType: new("privateEndpoints"), APIVersions: []*string{new("2024-05-01")}},
+			},
+		})
+	})
+
+	deleted := []string{}
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodDelete
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		deleted = append(deleted, request.URL.Path[strings.LastIndex(request.URL.Path, "/")+1:])
+		return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+	})
+
+	return &deleted
+}
+
+func newDeletionPreparationProvider(mockContext *mocks.MockContext) *BicepProvider {
+	return &BicepProvider{
+		env:     environment.NewWithValues("test-env", map[string]string{"AZURE_SUBSCRIPTION_ID": "SUB"}),
+		console: mockContext.Console,
+		resourceService: azapi.NewResourceService(
+			mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions),
+		serviceLocator: mockContext.Container,
+	}
+}
+
+func TestPrepareDeletion(t *testing.T) {
+	t.Run("RemovesLocksAndDependents", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		deleted := mockDeletionPreparation(mockContext)
+		mockContext.Console.WhenConfirm(func(options input.ConsoleOptions) bool {
+			return strings.Contains(options.Message, "Remove 1 lock(s)")
+		}).Respond(true)
+
+		provider := newDeletionPreparationProvider(mockContext)
+		err := provider.prepareDeletion(
+			t.Context(), provisioning.NewDestroyOptions(false, false), testNetworkResources(), nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"do-not-delete", "logs", "pe"}, *deleted)
+	})
+
+	t.Run("LocksKeptWhenDeclined", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		deleted := mockDeletionPreparation(mockContext)
+		mockContext.Console.WhenConfirm(func(options input.ConsoleOptions) bool {
+			return strings.Contains(options.Message, "Remove 1 lock(s)")
+		}).Respond(false)
+
+		provider := newDeletionPreparationProvider(mockContext)
+		err := provider.prepareDeletion(
+			t.Context(), provisioning.NewDestroyOptions(false, false), testNetworkResources(), nil)
+
+		_, ok := errors.AsType[*errorhandler.ErrorWithSuggestion](err)
+		require.True(t, ok)
+		assert.Empty(t, *deleted)
+	})
+
+	t.Run("LocksKeptWithForce", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		deleted := mockDeletionPreparation(mockContext)
+
+		provider := newDeletionPreparationProvider(mockContext)
+		err := provider.prepareDeletion(
+			t.Context(), provisioning.NewDestroyOptions(true, false), testNetworkResources(), nil)
+
+		require.ErrorIs(t, err, provisioning.ErrResourcesLocked)
+		assert.Empty(t, *deleted)
+	})
+}
@@ -92,6 +92,10 @@ const (
 //     returns false and the caller must wait for the outcome.
 //   - cleanup: must be called (via defer) to unregister the interrupt handler
 //     and release the deploy context.
+//   - abort: claims the "interrupting" state on behalf of a caller other than
+//     Ctrl+C (e.g. the stuck-deployment watchdog) and runs the supplied flow to
+//     produce the outcome. Returns false if the deployment already completed
+//     or another interrupt is already being handled.
 //
 // onInterruptStart, if non-nil, is invoked synchronously at the start of the
 // interrupt handler before any prompt is shown. Callers use this hook to stop
@@ -107,6 +111,7 @@ func (p *BicepProvider) installDeploymentInterruptHandler(
 	outcomeCh <-chan interruptOutcome,
 	markCompleted func() bool,
 	cleanup func(),
+	abort func(run func() interruptOutcome) bool,
 ) {
 	deployCtx, cancelDeploy := context.WithCancel(ctx)
 	ch := make(chan interruptOutcome, 1)
@@ -114,7 +119,7 @@ func (p *BicepProvider) installDeploymentInterruptHandler(
 
 	var state atomic.Int32 // deployState values
 
-	pop := input.PushInterruptHandler(sync.OnceValue(func() bool {
+	abort = func(run func() interruptOutcome) bool {
 		// Try to claim the "interrupting" state. If Deploy already set
 		// "completed", the prompt is unnecessary — the deployment finished
 		// naturally and the success path should run instead.
@@ -137,13 +142,18 @@ func (p *BicepProvider) installDeploymentInterruptHandler(
 		// Stop the in-progress spinner so we can render the prompt cleanly.
 		p.console.StopSpinner(ctx, "", input.Step)
 
-		outcome := p.runInterruptPrompt(ctx, deployment)
-		ch <- outcome
+		ch <- run()
+		return true
+	}
+
+	pop := input.PushInterruptHandler(sync.OnceValue(func() bool {
 		// Returning true tells the runtime that we own the shutdown sequence.
 		// We don't actually os.Exit here — Deploy will return the typed
 		// sentinel error and the action / error middleware translates that
 		// into the user-facing exit message.
-		return true
+		return abort(func() interruptOutcome {
+			return p.runInterruptPrompt(ctx, deployment)
+		})
 	}))
 
 	markCompleted = func() bool {
@@ -157,7 +167,7 @@ func (p *BicepProvider) installDeploymentInterruptHandler(
 		pop()
 		cancelDeploy()
 	}
-	return deployCtx, started, ch, markCompleted, cleanup
+	return deployCtx, started, ch, markCompleted, cleanup, abort
 }
 
 // printLeaveRunningMessage emits the standard "Azure deployment will continue
@@ -183,14 +193,11 @@ func (p *BicepProvider) printLeaveRunningMessage(
 					"Subscription → Deployments (look for %q).",
 				deployment.Name()))
 	}
-	// Hint for cancelling from another shell — works for both subscription
-	// and resource-group scopes (the CLI sub-command differs but the hint
-	// is short enough to mention both).
+	// Hint for cancelling from another shell — 'azd provision cancel' finds
+	// the deployment by its environment tags, at either scope.
 	p.console.Message(ctx,
 		output.WithGrayFormat(
-			"To cancel later: az deployment sub cancel --name %s "+
-				"(or `az deployment group cancel --name %s --resource-group <rg>`)",
-			deployment.Name(), deployment.Name()))
+			"To cancel later: azd provision cancel (deployment %s)", deployment.Name()))
 }
 
 // runInterruptPrompt presents the user with the choice of cancelling the
@@ -384,7 +384,7 @@ func TestInstallDeploymentInterruptHandler_MarkCompletedWinsRace(t *testing.T) {
 	provider := newTestProvider(mockContext)
 	deployment := &fakeDeployment{}
 
-	deployCtx, started, _, markCompleted, cleanup := provider.installDeploymentInterruptHandler(
+	deployCtx, started, _, markCompleted, cleanup, _ := provider.installDeploymentInterruptHandler(
 		t.Context(), deployment, nil)
 	defer cleanup()
 
@@ -422,7 +422,7 @@ func TestInstallDeploymentInterruptHandler_InterruptWinsRace(t *testing.T) {
 	deployment := &fakeDeployment{deploymentUrl: "https://portal/x"}
 
 	onStartCalled := false
-	deployCtx, started, outcomeCh, markCompleted, cleanup := provider.installDeploymentInterruptHandler(
+	deployCtx, started, outcomeCh, markCompleted, cleanup, _ := provider.installDeploymentInterruptHandler(
 		t.Context(), deployment, func() { onStartCalled = true })
 	defer cleanup()
 
@@ -0,0 +1,349 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// maxResourceDeletionAttempts is the number of passes made over the resources that failed to delete, since a resource
+// may not be deleted before the resources that depend on it are.
+const maxResourceDeletionAttempts = 3
+
+// CanKeepResources returns true since resources of Bicep deployments can be deleted one by one.
+func (p *BicepProvider) CanKeepResources() bool {
+	return true
+}
+
+var _ provisioning.ResourceKeeper = (*BicepProvider)(nil)
+
+// splitKeptResources splits the resources grouped by resource group into the resources to delete and the resources to
+// keep. A resource is kept when its ID is in keptIds, or when it's a child resource of a kept resource. Resource groups
+// without resources to delete aren't in the resources to delete.
+func splitKeptResources(
+	groupedResources map[string][]*azapi.Resource,
+	keptIds []string,
+) (map[string][]*azapi.Resource, map[string][]*azapi.Resource) {
+	isKept := func(resource *azapi.Resource) bool {
+		return slices.ContainsFunc(keptIds, func(keptId string) bool {
+			return strings.EqualFold(resource.Id, keptId) ||
+				strings.HasPrefix(strings.ToLower(resource.Id), strings.ToLower(keptId)+"/")
+		})
+	}
+
+	toDelete := map[string][]*azapi.Resource{}
+	kept := map[string][]*azapi.Resource{}
+	for resourceGroupName, resources := range groupedResources {
+		for _, resource := range resources {
+			if isKept(resource) {
+				kept[resourceGroupName] = append(kept[resourceGroupName], resource)
+			} else {
+				toDelete[resourceGroupName] = append(toDelete[resourceGroupName], resource)
+			}
+		}
+
+		// A resource group without resources is deleted with the deployment.
+		if len(resources) == 0 {
+			toDelete[resourceGroupName] = resources
+		}
+	}
+
+	return toDelete, kept
+}
+
+// protectedResources returns the IDs of the top-level resources that match the protected resources of the options.
+// Protected resources matched by tag are listed per resource group, since the resources of the deployment don't
+// include their tags.
+func (p *BicepProvider) protectedResources(
+	ctx context.Context,
+	options provisioning.DestroyOptions,
+	groupedResources map[string][]*azapi.Resource,
+) ([]string, error) {
+	protectedIds := []string{}
+	for _, resourceGroupName := range slices.Sorted(maps.Keys(groupedResources)) {
+		for _, protected := range options.Protect() {
+			var taggedIds []string
+			if protected.Tag != "" {
+				ids, err := p.taggedResources(ctx, resourceGroupName, protected)
+				if err != nil {
+					return nil, err
+				}
+
+				taggedIds = ids
+			}
+
+			for _, resource := range groupedResources[resourceGroupName] {
+				// Child resources are retained with their parent resource.
+				if !isTopLevelResource(resource) || !protected.MatchesName(resource.Name) {
+					continue
+				}
+
+				if protected.Tag != "" && !slices.ContainsFunc(taggedIds, func(id string) bool {
+					return strings.EqualFold(id, resource.Id)
+				}) {
+					continue
+				}
+
+				if !slices.Contains(protectedIds, resource.Id) {
+					protectedIds = append(protectedIds, resource.Id)
+				}
+			}
+		}
+	}
+
+	return protectedIds, nil
+}
+
+// taggedResources returns the IDs of the resources of the resource group that have the tag of the protected resource.
+func (p *BicepProvider) taggedResources(
+	ctx context.Context,
+	resourceGroupName string,
+	protected provisioning.ProtectedResource,
+) ([]string, error) {
+	tagName, tagValue, hasValue := protected.TagFilter()
+	filter := fmt.Sprintf("tagName eq '%s'", odataEscape(tagName))
+	if hasValue {
+		filter += fmt.Sprintf(" and tagValue eq '%s'", odataEscape(tagValue))
+	}
+
+	resources, err := p.resourceService.ListResourceGroupResources(
+		ctx, p.env.GetSubscriptionId(), resourceGroupName, &azapi.ListResourceGroupResourcesOptions{Filter: &filter})
+	if err != nil {
+		return nil, fmt.Errorf("listing resources tagged '%s' in resource group %s: %w", protected.Tag, resourceGroupName, err)
+	}
+
+	ids := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		ids = append(ids, resource.Id)
+	}
+
+	return ids, nil
+}
+
+// odataEscape escapes a string literal of an OData filter expression.
+func odataEscape(value string) string {
+	return strings.ReplaceAll(value, "'", "''")
+}
+
+// resourcesToKeep returns the IDs of the resources to keep, which are the protected resources and the resources that
+// match the resources to keep of the options. When the options are selectable and the console is interactive, the user
+// is prompted to deselect more resources to keep. Protected resources aren't offered for deletion.
+func (p *BicepProvider) resourcesToKeep(
+	ctx context.Context,
+	options provisioning.DestroyOptions,
+	groupedResources map[string][]*azapi.Resource,
+	protectedIds []string,
+) ([]string, error) {
+	keptIds := slices.Clone(protectedIds)
+	for _, resourceGroupName := range slices.Sorted(maps.Keys(groupedResources)) {
+		for _, resource := range groupedResources[resourceGroupName] {
+			if options.Keeps(resource.Id, resource.Type, resource.Name) && !slices.Contains(keptIds, resource.Id) {
+				keptIds = append(keptIds, resource.Id)
+			}
+		}
+	}
+
+	if !options.Selectable() || options.Force() || p.console.IsNoPromptMode() {
+		return keptIds, nil
+	}
+
+	toDelete, _ := splitKeptResources(groupedResources, keptIds)
+	choices := []string{}
+	choiceIds := map[string]string{}
+	for _, resourceGroupName := range slices.Sorted(maps.Keys(toDelete)) {
+		for _, resource := range toDelete[resourceGroupName] {
+			if !isTopLevelResource(resource) {
+				continue
+			}
+
+			choice := fmt.Sprintf("%s: %s (%s)", resourceGroupName, resource.Name, resource.Type)
+			choices = append(choices, choice)
+			choiceIds[choice] = resource.Id
+		}
+	}
+
+	if len(choices) == 0 {
+		return keptIds, nil
+	}
+
+	p.console.StopSpinner(ctx, "", input.StepDone)
+	selected, err := p.console.MultiSelect(ctx, input.ConsoleOptions{
+		Message:      "Select the resources to delete (deselect the resources to keep):",
+		Options:      choices,
+		DefaultValue: slices.Clone(choices),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("prompting for resources to delete: %w", err)
+	}
+
+	for _, choice := range choices {
+		if !slices.Contains(selected, choice) {
+			keptIds = append(keptIds, choiceIds[choice])
+		}
+	}
+
+	return keptIds, nil
+}
+
+// generateKeptResources lists the resources that are kept, with the protected resources listed apart as retained.
+func (p *BicepProvider) generateKeptResources(
+	ctx context.Context,
+	keptResources map[string][]*azapi.Resource,
+	protectedIds []string,
+) []string {
+	kept, retained := splitKeptResources(keptResources, protectedIds)
+	lines := []string{}
+	if countResources(kept) > 0 {
+		lines = append(lines, p.generateResourceLines(ctx, "Resource(s) to be kept:", kept)...)
+		lines = append(lines, "\n")
+	}
+
+	if len(retained) > 0 {
+		lines = append(lines,
+			p.generateResourceLines(ctx, "Resource(s) retained (protected in azure.yaml):", retained)...)
+		lines = append(lines, "\n")
+	}
+
+	return lines
+}
+
+// isTopLevelResource returns whether the resource isn't a child resource of another resource.
+func isTopLevelResource(resource *azapi.Resource) bool {
+	resourceId, err := arm.ParseResourceID(resource.Id)
+	if err != nil {
+		return true
+	}
+
+	return len(resourceId.ResourceType.Types) == 1
+}
+
+// countResources returns the number of resources grouped by resource group.
+func countResources(groupedResources map[string][]*azapi.Resource) int {
+	count := 0
+	for _, resources := range groupedResources {
+		count += len(resources)
+	}
+
+	return count
+}
+
+// destroyResources deletes the resources of the deployment that aren't kept. The resource groups without kept
+// resources are deleted, and the other resources are deleted one by one. Since the deployment still references the
+// deleted resources, its state is then voided with an empty deployment.
+func (p *BicepProvider) destroyResources(
+	ctx context.Context,
+	scope infra.Scope,
+	targetScope azure.DeploymentScope,
+	toDelete map[string][]*azapi.Resource,
+	kept map[string][]*azapi.Resource,
+) error {
+	pending := []*azapi.Resource{}
+	for _, resourceGroupName := range slices.Sorted(maps.Keys(toDelete)) {
+		if _, has := kept[resourceGroupName]; !has {
+			message := fmt.Sprintf("Deleting resource group %s", output.WithHighLightFormat(resourceGroupName))
+			p.console.ShowSpinner(ctx, message, input.Step)
+			err := p.resourceService.DeleteResourceGroup(ctx, p.env.GetSubscriptionId(), resourceGroupName)
+			if err != nil {
+				p.console.StopSpinner(ctx, message, input.StepFailed)
+				return err
+			}
+
+			p.console.StopSpinner(ctx,
+				fmt.Sprintf("Deleted resource group %s", output.WithHighLightFormat(resourceGroupName)), input.StepDone)
+			continue
+		}
+
+		// Child resources are deleted with their parent resource.
+		for _, resource := range toDelete[resourceGroupName] {
+			if isTopLevelResource(resource) {
+				pending = append(pending, resource)
+			}
+		}
+	}
+
+	if err := p.deleteResourcesInPasses(ctx, pending); err != nil {
+		return err
+	}
+
+	p.console.Message(ctx, "")
+
+	return p.voidDeploymentState(ctx, scope, targetScope)
+}
+
+// deleteResource deletes a single resource. A resource that fails to delete is retried in the next pass, so the
+// failure is logged and returned without being reported as a failed step.
+func (p *BicepProvider) deleteResource(ctx context.Context, resource *azapi.Resource) error {
+	resourceId, err := arm.ParseResourceID(resource.Id)
+	if err != nil {
+		return fmt.Errorf("parsing resource ID: %w", err)
+	}
+
+	p.console.ShowSpinner(ctx, fmt.Sprintf("Deleting %s", output.WithHighLightFormat(resource.Name)), input.Step)
+	if err := p.resourceService.DeleteResource(ctx, resourceId); err != nil {
+		log.Printf("deleting resource '%s' failed: %v", resource.Id, err)
+		p.console.StopSpinner(ctx, "", input.StepDone)
+		return fmt.Errorf("deleting resource %s: %w", resource.Name, err)
+	}
+
+	p.console.StopSpinner(ctx, fmt.Sprintf("Deleted %s", output.WithHighLightFormat(resource.Name)), input.StepDone)
+	return nil
+}
+
+// voidDeploymentState deploys an empty template to the scope, so the next provision doesn't skip the deployment of
+// the deleted resources because the template and parameters didn't change.
+func (p *BicepProvider) voidDeploymentState(
+	ctx context.Context,
+	scope infra.Scope,
+	targetScope azure.DeploymentScope,
+) error {
+	optionsMap, err := convert.ToMap(p.options)
+	if err != nil {
+		return err
+	}
+
+	tags := map[string]*string{
+		azure.TagKeyAzdEnvName:   new(p.env.Name()),
+		azure.TagKeyAzdLayerName: &p.layer,
+		"azd-deploy-reason":      new("down"),
+	}
+
+	deployment := scope.Deployment(p.deploymentManager.GenerateDeploymentName(p.env.Name()))
+	if _, err := deployment.Deploy(
+		ctx, emptyArmTemplate(targetScope), azure.ArmParameters{}, tags, optionsMap); err != nil {
+		return fmt.Errorf("deploying empty template: %w", err)
+	}
+
+	return nil
+}
+
+// emptyArmTemplate returns a template without resources for the deployment scope.
+func emptyArmTemplate(targetScope azure.DeploymentScope) azure.RawArmTemplate {
+	schema := "https://schema.management.azure.com/schemas/2019-04-01/deploymentTemplate.json#"
+	if targetScope == azure.DeploymentScopeSubscription {
+		schema = "https://schema.management.azure.com/schemas/2018-05-01/subscriptionDeploymentTemplate.json#"
+	}
+
+	template, _ := json.Marshal(map[string]any{
+		"$schema":        schema,
+		"contentVersion": "1.0.0.0",
+		"resources":      []any{},
+	})
+
+	return template
+}
@@ -0,0 +1,195 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKeepResources() map[string][]*azapi.Resource {
+	return map[string][]*azapi.Resource{
+		"rg-app": {
+			{
+				Id:   "/subscriptions/SUB/resourceGroups/rg-app/providers/Microsoft.Web/sites/app1",
+				Name: "app1",
+				Type: "Microsoft.Web/sites",
+			},
+			{
+				Id:   "/subscriptions/SUB/resourceGroups/rg-app/providers/Microsoft.KeyVault/vaults/kv1",
+				Name: "kv1",
+				Type: "Microsoft.KeyVault/vaults",
+			},
+			{
+				Id:   "/subscriptions/SUB/resourceGroups/rg-app/providers/Microsoft.KeyVault/vaults/kv1/secrets/s1",
+				Name: "s1",
+				Type: "Microsoft.KeyVault/vaults/secrets",
+			},
+		},
+		"rg-data": {
+			{
+				Id:   "/subscriptions/SUB/resourceGroups/rg-data/providers/Microsoft.Sql/servers/sql1",
+				Name: "sql1",
+				Type: "Microsoft.Sql/servers",
+			},
+		},
+	}
+}
+
+func TestSplitKeptResources(t *testing.T) {
+	t.Run("KeepsChildResources", func(t *testing.T) {
+		toDelete, kept := splitKeptResources(testKeepResources(), []string{
+			"/subscriptions/SUB/resourceGroups/rg-app/providers/Microsoft.KeyVault/vaults/KV1",
+		})
+
+		require.Len(t, toDelete, 2)
+		assert.Len(t, toDelete["rg-app"], 1)
+		assert.Equal(t, "app1", toDelete["rg-app"][0].Name)
+		assert.Len(t, toDelete["rg-data"], 1)
+
+		require.Len(t, kept, 1)
+		assert.Len(t, kept["rg-app"], 2)
+		assert.Equal(t, 2, countResources(toDelete))
+	})
+
+	t.Run("NothingKept", func(t *testing.T) {
+		toDelete, kept := splitKeptResources(testKeepResources(), nil)
+		assert.Empty(t, kept)
+		assert.Equal(t, 4, countResources(toDelete))
+	})
+
+	t.Run("EmptyResourceGroup", func(t *testing.T) {
+		toDelete, kept := splitKeptResources(map[string][]*azapi.Resource{"rg-empty": {}}, nil)
+		assert.Empty(t, kept)
+		assert.Contains(t, toDelete, "rg-empty")
+	})
+}
+
+func TestResourcesToKeep(t *testing.T) {
+	t.Run("Flags", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		provider := &BicepProvider{console: mockContext.Console}
+		options := provisioning.NewDestroyOptions(false, false).WithKeep([]string{"Microsoft.KeyVault/vaults"}, false)
+
+		keptIds, err := provider.resourcesToKeep(*mockContext.Context, options, testKeepResources(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"/subscriptions/SUB/resourceGroups/rg-app/providers/Microsoft.KeyVault/vaults/kv1",
+		}, keptIds)
+	})
+
+	t.Run("Deselected", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		var choices []string
+		mockContext.Console.WhenMultiSelect(func(options input.ConsoleOptions) bool {
+			return true
+		}).RespondFn(func(options input.ConsoleOptions) (any, error) {
+			choices = options.Options
+			return []string{"rg-app: app1 (Microsoft.Web/sites)"}, nil
+		})
+
+		provider := &BicepProvider{console: mockContext.Console}
+		options := provisioning.NewDestroyOptions(false, false).WithKeep([]string{"kv1"}, true)
+
+		keptIds, err := provider.resourcesToKeep(*mockContext.Context, options, testKeepResources(), nil)
+		require.NoError(t, err)
+
+		// Resources kept with --keep and child resources aren't choices.
+		assert.Equal(t, []string{"rg-app: app1 (Microsoft.Web/sites)", "rg-data: sql1 (Microsoft.Sql/servers)"}, choices)
+		assert.Equal(t, []string{
+			"/subscriptions/SUB/resourceGroups/rg-app/providers/Microsoft.KeyVault/vaults/kv1",
+			"/subscriptions/SUB/resourceGroups/rg-data/providers/Microsoft.Sql/servers/sql1",
+		}, keptIds)
+	})
+
+	t.Run("ForceDoesNotPrompt", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		provider := &BicepProvider{console: mockContext.Console}
+		options := provisioning.NewDestroyOptions(true, false).WithKeep(nil, true)
+
+		// The mock console panics on the multi-select prompt since no response is registered.
+		keptIds, err := provider.resourcesToKeep(*mockContext.Context, options, testKeepResources(), nil)
+		require.NoError(t, err)
+		assert.Empty(t, keptIds)
+	})
+}
+
+func TestProtectedResources(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	var filters []string
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/resourceGroups/rg-data/resources")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		filters = append(filters, request.URL.Query().Get("$filter"))
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"value": []map[string]any{
+				{
+					"id":       "/subscriptions/SUB/resourceGroups/rg-data/providers/Microsoft.Sql/servers/sql1",
+					"name":     "sql1",
+					"type":     "Microsoft.Sql/servers",
+					"location": "eastus2",
+				},
+			},
+		})
+	})
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/resourceGroups/rg-app/resources")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{"value": []any{}})
+	})
+
+	provider := &BicepProvider{
+		console: mockContext.Console,
+		env:     environment.NewWithValues("test-env", map[string]string{"AZURE_SUBSCRIPTION_ID": "SUB"}),
+		resourceService: azapi.NewResourceService(
+			mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions),
+	}
+	options := provisioning.NewDestroyOptions(true, false).WithProtect([]provisioning.ProtectedResource{
+		{Name: "KV*"},
+		{Name: "s?"},
+		{Tag: "shared=it's"},
+	})
+
+	protectedIds, err := provider.protectedResources(*mockContext.Context, options, testKeepResources())
+	require.NoError(t, err)
+
+	// Child resources aren't matched by name, since they're retained with their parent resource.
+	assert.Equal(t, []string{
+		"/subscriptions/SUB/resourceGroups/rg-app/providers/Microsoft.KeyVault/vaults/kv1",
+		"/subscriptions/SUB/resourceGroups/rg-data/providers/Microsoft.Sql/servers/sql1",
+	}, protectedIds)
+	assert.Equal(t, []string{"tagName eq 'shared' and tagValue eq 'it''s'"}, filters)
+
+	// Protected resources are kept without being offered for deletion.
+	keptIds, err := provider.resourcesToKeep(*mockContext.Context, options, testKeepResources(), protectedIds)
+	require.NoError(t, err)
+	assert.Equal(t, protectedIds, keptIds)
+}
+
+func TestEmptyArmTemplate(t *testing.T) {
+	for scope, schema := range map[azure.DeploymentScope]string{
+		azure.DeploymentScopeResourceGroup: "https://schema.management.azure.com/schemas/2019-04-01/deploymentTemplate.json#",
+		azure.DeploymentScopeSubscription: "https://schema.management.azure.com/schemas/2018-05-01/" +
+			"subscriptionDeploymentTemplate.json#",
+	} {
+		var template azure.ArmTemplate
+		require.NoError(t, json.Unmarshal(emptyArmTemplate(scope), &template))
+		assert.Equal(t, schema, template.Schema)
+
+		targetScope, err := template.TargetScope()
+		require.NoError(t, err)
+		assert.Equal(t, scope, targetScope)
+	}
+}
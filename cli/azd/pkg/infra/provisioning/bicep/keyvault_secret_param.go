@@ -0,0 +1,220 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// isKeyVaultSecretParameter returns true when the parameter is tagged with the keyVaultSecret azd metadata type, meaning
+// its value is read by Azure Resource Manager from an existing Key Vault secret instead of being passed by azd.
+func isKeyVaultSecretParameter(param azure.ArmTemplateParameterDefinition) bool {
+	azdMetadata, has := param.AzdMetadata()
+	return has && azdMetadata.Type != nil && *azdMetadata.Type == azure.AzdMetadataTypeKeyVaultSecret
+}
+
+// keyVaultReferenceConfigValue is the value saved in the environment config for a parameter sourced from Key Vault. It
+// has the same shape as a Key Vault reference in a parameters file. Only the location of the secret is saved, never
+// its value.
+func keyVaultReferenceConfigValue(ref *azure.KeyVaultParameterReference) map[string]any {
+	reference := map[string]any{
+		"keyVault":   map[string]any{"id": ref.KeyVault.ID},
+		"secretName": ref.SecretName,
+	}
+	if ref.SecretVersion != "" {
+		reference["secretVersion"] = ref.SecretVersion
+	}
+
+	return map[string]any{"reference": reference}
+}
+
+// keyVaultReferenceFromConfig reads a Key Vault reference saved by [keyVaultReferenceConfigValue].
+func keyVaultReferenceFromConfig(v any) (*azure.KeyVaultParameterReference, bool) {
+	if _, isMap := v.(map[string]any); !isMap {
+		return nil, false
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, false
+	}
+
+	var param azure.ArmParameter
+	if err := json.Unmarshal(raw, &param); err != nil || param.KeyVaultReference == nil {
+		return nil, false
+	}
+
+	if param.KeyVaultReference.KeyVault.ID == "" || param.KeyVaultReference.SecretName == "" {
+		return nil, false
+	}
+
+	return param.KeyVaultReference, true
+}
+
+// promptForKeyVaultSecret asks which Key Vault secret holds the value of a keyVaultSecret parameter and returns a
+// reference to it. The secret can be picked from the vaults of the environment's subscription, or given as a secret
+// URI (https://<vault>.vault.azure.net/secrets/<name>[/<version>]) or an akvs:// reference.
+func (p *BicepProvider) promptForKeyVaultSecret(
+	ctx context.Context,
+	key string,
+	param azure.ArmTemplateParameterDefinition,
+) (*azure.KeyVaultParameterReference, error) {
+	if !strings.EqualFold(param.Type, "securestring") {
+		return nil, fmt.Errorf(
+			"parameter '%s' uses the azd metadata type '%s', which requires a secure string parameter",
+			key, azure.AzdMetadataTypeKeyVaultSecret)
+	}
+
+	p.console.Message(ctx, fmt.Sprintf("Parameter %s is read from an Azure Key Vault secret.",
+		output.WithUnderline("%s", key)))
+
+	help := "The secret value is read by Azure Resource Manager during the deployment and is never stored by azd. " +
+		"The Key Vault must allow access for template deployment."
+	if description, has := param.Description(); has {
+		help = description + "\n" + help
+	}
+
+	options := []string{"Select a Key Vault and secret", "Enter a secret URI"}
+	choice, err := p.console.Select(ctx, input.ConsoleOptions{
+		Message:      fmt.Sprintf("How do you want to provide the Key Vault secret for '%s'?", key),
+		Help:         help,
+		Options:      options,
+		DefaultValue: options[0],
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if choice == 1 {
+		return p.promptForKeyVaultSecretUri(ctx, key)
+	}
+
+	subscriptionId := p.env.GetSubscriptionId()
+	p.console.ShowSpinner(ctx, "Finding Key Vaults", input.Step)
+	vaults, err := p.keyvaultService.ListSubscriptionVaults(ctx, subscriptionId)
+	p.console.StopSpinner(ctx, "", input.Step)
+	if err != nil {
+		return nil, fmt.Errorf("listing Key Vaults: %w", err)
+	}
+
+	if len(vaults) == 0 {
+		p.console.Message(ctx, output.WithWarningFormat("No Key Vaults were found in the current subscription."))
+		return p.promptForKeyVaultSecretUri(ctx, key)
+	}
+
+	vaultOptions := make([]string, len(vaults))
+	for i, vault := range vaults {
+		vaultOptions[i] = vault.Name
+	}
+	vaultIndex, err := p.console.Select(ctx, input.ConsoleOptions{
+		Message:      "Select the Key Vault that holds the secret",
+		Options:      vaultOptions,
+		DefaultValue: vaultOptions[0],
+	})
+	if err != nil {
+		return nil, err
+	}
+	vault := vaults[vaultIndex]
+
+	secrets, err := p.keyvaultService.ListKeyVaultSecrets(ctx, subscriptionId, vault.Name)
+	if err != nil {
+		return nil, fmt.Errorf("listing secrets of Key Vault %s: %w", vault.Name, err)
+	}
+	if len(secrets) == 0 {
+		return nil, fmt.Errorf("the Key Vault %s has no secrets", vault.Name)
+	}
+
+	secretIndex, err := p.console.Select(ctx, input.ConsoleOptions{
+		Message:      "Select the secret",
+		Options:      secrets,
+		DefaultValue: secrets[0],
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &azure.KeyVaultParameterReference{
+		KeyVault:   azure.KeyVaultReference{ID: vault.Id},
+		SecretName: secrets[secretIndex],
+	}, nil
+}
+
+// promptForKeyVaultSecretUri prompts for a secret URI until one that resolves to a Key Vault is entered.
+func (p *BicepProvider) promptForKeyVaultSecretUri(
+	ctx context.Context,
+	key string,
+) (*azure.KeyVaultParameterReference, error) {
+	for {
+		uri, err := p.console.Prompt(ctx, input.ConsoleOptions{
+			Message: fmt.Sprintf("Enter the Key Vault secret URI for '%s':", key),
+			Help: "A secret URI like https://<vault>.vault.azure.net/secrets/<name>, optionally followed by the " +
+				"secret version, or an akvs://<subscription-id>/<vault>/<name> reference.",
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		ref, err := p.keyVaultReferenceFromUri(ctx, strings.TrimSpace(uri))
+		if err == nil {
+			return ref, nil
+		}
+
+		p.console.Message(ctx, output.WithErrorFormat("%s", err.Error()))
+	}
+}
+
+// keyVaultReferenceFromUri converts a secret URI or akvs:// reference into a Key Vault parameter reference. Azure
+// Resource Manager references the vault by resource ID, so the vault is looked up by name in the referenced
+// subscription (or the environment's subscription for secret URIs).
+func (p *BicepProvider) keyVaultReferenceFromUri(
+	ctx context.Context,
+	uri string,
+) (*azure.KeyVaultParameterReference, error) {
+	subscriptionId := p.env.GetSubscriptionId()
+	var vaultName, secretName, secretVersion string
+
+	if keyvault.IsAzureKeyVaultSecret(uri) {
+		akvs, err := keyvault.ParseAzureKeyVaultSecret(uri)
+		if err != nil {
+			return nil, err
+		}
+		subscriptionId, vaultName, secretName = akvs.SubscriptionId, akvs.VaultName, akvs.SecretName
+	} else {
+		appRef := uri
+		if !keyvault.IsKeyVaultAppReference(uri) {
+			appRef = fmt.Sprintf("@Microsoft.KeyVault(SecretUri=%s)", uri)
+		}
+
+		parsed, err := keyvault.ParseKeyVaultAppReference(appRef)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a valid Key Vault secret URI", uri)
+		}
+		vaultName, secretName, secretVersion = parsed.VaultName, parsed.SecretName, parsed.SecretVersion
+	}
+
+	vaults, err := p.keyvaultService.ListSubscriptionVaults(ctx, subscriptionId)
+	if err != nil {
+		return nil, fmt.Errorf("listing Key Vaults: %w", err)
+	}
+
+	for _, vault := range vaults {
+		if strings.EqualFold(vault.Name, vaultName) {
+			return &azure.KeyVaultParameterReference{
+				KeyVault:      azure.KeyVaultReference{ID: vault.Id},
+				SecretName:    secretName,
+				SecretVersion: secretVersion,
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("the Key Vault %s was not found in subscription %s", vaultName, subscriptionId)
+}
@@ -0,0 +1,151 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+const testVaultId = "/subscriptions/sub-123/resourceGroups/rg/providers/Microsoft.KeyVault/vaults/kv-shared"
+
+// fakeKeyVaultService serves a fixed set of vaults and secrets.
+type fakeKeyVaultService struct {
+	keyvault.KeyVaultService
+	vaults  map[string][]keyvault.Vault
+	secrets map[string][]string
+}
+
+func (f *fakeKeyVaultService) ListSubscriptionVaults(_ context.Context, subscriptionId string) ([]keyvault.Vault, error) {
+	return f.vaults[subscriptionId], nil
+}
+
+func (f *fakeKeyVaultService) ListKeyVaultSecrets(_ context.Context, _ string, vaultName string) ([]string, error) {
+	return f.secrets[vaultName], nil
+}
+
+func newKeyVaultSecretProvider(mockContext *mocks.MockContext) *BicepProvider {
+	return &BicepProvider{
+		console: mockContext.Console,
+		env: environment.NewWithValues("test", map[string]string{
+			environment.SubscriptionIdEnvVarName: "sub-123",
+		}),
+		keyvaultService: &fakeKeyVaultService{
+			vaults: map[string][]keyvault.Vault{
+				"sub-123": {{Id: testVaultId, Name: "kv-shared"}},
+			},
+			secrets: map[string][]string{"kv-shared": {"db-password", "api-key"}},
+		},
+	}
+}
+
+var keyVaultSecretParam = azure.ArmTemplateParameterDefinition{
+	Type:     "securestring",
+	Metadata: map[string]json.RawMessage{"azd": json.RawMessage(`{"type":"keyVaultSecret"}`)},
+}
+
+func TestPromptForKeyVaultSecret(t *testing.T) {
+	t.Run("select vault and secret", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.Console.WhenSelect(func(o input.ConsoleOptions) bool {
+			return strings.HasPrefix(o.Message, "How do you want")
+		}).Respond(0)
+		mockContext.Console.WhenSelect(func(o input.ConsoleOptions) bool {
+			return o.Message == "Select the Key Vault that holds the secret"
+		}).Respond(0)
+		mockContext.Console.WhenSelect(func(o input.ConsoleOptions) bool {
+			return o.Message == "Select the secret"
+		}).Respond(1)
+
+		provider := newKeyVaultSecretProvider(mockContext)
+		ref, err := provider.promptForKeyVaultSecret(t.Context(), "apiKey", keyVaultSecretParam)
+		require.NoError(t, err)
+		require.Equal(t, &azure.KeyVaultParameterReference{
+			KeyVault:   azure.KeyVaultReference{ID: testVaultId},
+			SecretName: "api-key",
+		}, ref)
+	})
+
+	t.Run("secret uri", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.Console.WhenSelect(func(o input.ConsoleOptions) bool { return true }).Respond(1)
+
+		uris := []string{
+			"https://kv-missing.vault.azure.net/secrets/db-password",
+			"https://kv-shared.vault.azure.net/secrets/db-password/0123abcd",
+		}
+		mockContext.Console.WhenPrompt(func(o input.ConsoleOptions) bool { return true }).
+			RespondFn(func(o input.ConsoleOptions) (any, error) {
+				uri := uris[0]
+				uris = uris[1:]
+				return uri, nil
+			})
+
+		provider := newKeyVaultSecretProvider(mockContext)
+		ref, err := provider.promptForKeyVaultSecret(t.Context(), "dbPassword", keyVaultSecretParam)
+		require.NoError(t, err)
+		require.Equal(t, &azure.KeyVaultParameterReference{
+			KeyVault:      azure.KeyVaultReference{ID: testVaultId},
+			SecretName:    "db-password",
+			SecretVersion: "0123abcd",
+		}, ref)
+		require.Contains(t, strings.Join(mockContext.Console.Output(), "\n"),
+			"the Key Vault kv-missing was not found in subscription sub-123")
+	})
+
+	t.Run("requires a secure string", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		provider := newKeyVaultSecretProvider(mockContext)
+
+		param := keyVaultSecretParam
+		param.Type = "string"
+		_, err := provider.promptForKeyVaultSecret(t.Context(), "apiKey", param)
+		require.ErrorContains(t, err, "requires a secure string parameter")
+	})
+}
+
+func TestKeyVaultReferenceFromUri(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	provider := newKeyVaultSecretProvider(mockContext)
+
+	ref, err := provider.keyVaultReferenceFromUri(t.Context(), "akvs://sub-123/kv-shared/db-password")
+	require.NoError(t, err)
+	require.Equal(t, testVaultId, ref.KeyVault.ID)
+	require.Equal(t, "db-password", ref.SecretName)
+
+	_, err = provider.keyVaultReferenceFromUri(t.Context(), "https://example.com/secrets/db-password")
+	require.ErrorContains(t, err, "is not a valid Key Vault secret URI")
+}
+
+func TestKeyVaultReferenceConfigRoundTrip(t *testing.T) {
+	ref := &azure.KeyVaultParameterReference{
+		KeyVault:      azure.KeyVaultReference{ID: testVaultId},
+		SecretName:    "db-password",
+		SecretVersion: "0123abcd",
+	}
+
+	// Values read back from the environment config are decoded from JSON.
+	raw, err := json.Marshal(keyVaultReferenceConfigValue(ref))
+	require.NoError(t, err)
+	var saved any
+	require.NoError(t, json.Unmarshal(raw, &saved))
+
+	actual, isRef := keyVaultReferenceFromConfig(saved)
+	require.True(t, isRef)
+	require.Equal(t, ref, actual)
+
+	_, isRef = keyVaultReferenceFromConfig("plain value")
+	require.False(t, isRef)
+	_, isRef = keyVaultReferenceFromConfig(map[string]any{"reference": map[string]any{"secretName": "x"}})
+	require.False(t, isRef)
+}
@@ -0,0 +1,132 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// parameterSeedSource is an existing environment holding saved values for some of the parameters azd is about to
+// prompt for.
+type parameterSeedSource struct {
+	envName string
+	values  map[string]any
+}
+
+// seedParametersFromEnvironment offers to reuse the parameter values saved by another environment of the project, so
+// setting up dev/test/prod environments doesn't require answering the same prompts again. It is only offered for
+// environments that have not saved any parameter values yet. Secure parameters are never copied.
+//
+// It returns the values that were reused, keyed by parameter name. Parameters not in the returned map still need to
+// be prompted for.
+func (p *BicepProvider) seedParametersFromEnvironment(
+	ctx context.Context,
+	parameterPrompts []struct {
+		key   string
+		param azure.ArmTemplateParameterDefinition
+	},
+	locationParameters []string,
+) (map[string]any, error) {
+	if _, has := p.env.Config.Get(strings.TrimSuffix(configInfraParametersKey, ".")); has {
+		return nil, nil
+	}
+
+	seedable := map[string]provisioning.ParameterType{}
+	for _, prompt := range parameterPrompts {
+		if prompt.param.Secure() || prompt.key == "location" || slices.Contains(locationParameters, prompt.key) {
+			continue
+		}
+		seedable[prompt.key] = provisioning.ParameterTypeFromArmType(prompt.param.Type)
+	}
+
+	if len(seedable) == 0 {
+		return nil, nil
+	}
+
+	sources, err := p.parameterSeedSources(ctx, seedable)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sources) == 0 {
+		return nil, nil
+	}
+
+	options := make([]string, 0, len(sources)+1)
+	options = append(options, "No, enter new values")
+	for _, source := range sources {
+		options = append(options,
+			fmt.Sprintf("%s (%d of %d values)", source.envName, len(source.values), len(seedable)))
+	}
+
+	selected, err := p.console.Select(ctx, input.ConsoleOptions{
+		Message: "Reuse infrastructure parameter values from an existing environment?",
+		Help: "Values for parameters already answered in another environment of this project are copied into " +
+			"the new environment. Secure values are never copied and will still be prompted for.",
+		Options:      options,
+		DefaultValue: options[0],
+	})
+	if err != nil {
+		return nil, fmt.Errorf("prompting for parameter source environment: %w", err)
+	}
+
+	if selected == 0 {
+		return nil, nil
+	}
+
+	source := sources[selected-1]
+	p.console.Message(ctx, fmt.Sprintf("Reusing %d parameter value(s) from environment %s.",
+		len(source.values), output.WithHighLightFormat(source.envName)))
+
+	return source.values, nil
+}
+
+// parameterSeedSources returns the other environments of the project that have saved, still valid values for at least
+// one of the given parameters, in the order reported by the environment manager.
+func (p *BicepProvider) parameterSeedSources(
+	ctx context.Context,
+	parameters map[string]provisioning.ParameterType,
+) ([]parameterSeedSource, error) {
+	envs, err := p.envManager.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing environments: %w", err)
+	}
+
+	var sources []parameterSeedSource
+	for _, desc := range envs {
+		if desc.Name == p.env.Name() || !desc.HasLocal {
+			continue
+		}
+
+		env, err := p.envManager.Get(ctx, desc.Name)
+		if err != nil {
+			// An unreadable environment shouldn't block provisioning; it's just not offered as a source.
+			log.Printf("skipping environment '%s' as a parameter source: %v", desc.Name, err)
+			continue
+		}
+
+		values := map[string]any{}
+		for key, paramType := range parameters {
+			v, has := env.Config.Get(configInfraParametersKey + key)
+			if has && isValueAssignableToParameterType(paramType, v) {
+				values[key] = v
+			}
+		}
+
+		if len(values) > 0 {
+			sources = append(sources, parameterSeedSource{envName: desc.Name, values: values})
+		}
+	}
+
+	return sources, nil
+}
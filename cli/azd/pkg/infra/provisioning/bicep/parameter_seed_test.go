@@ -0,0 +1,85 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeedParametersFromEnvironment(t *testing.T) {
+	prompts := []struct {
+		key   string
+		param azure.ArmTemplateParameterDefinition
+	}{
+		{key: "appName", param: azure.ArmTemplateParameterDefinition{Type: "string"}},
+		{key: "replicas", param: azure.ArmTemplateParameterDefinition{Type: "int"}},
+		{key: "adminPassword", param: azure.ArmTemplateParameterDefinition{Type: "securestring"}},
+		{key: "region", param: azure.ArmTemplateParameterDefinition{Type: "string"}},
+	}
+
+	devConfig := config.NewEmptyConfig()
+	require.NoError(t, devConfig.Set("infra.parameters.appName", "contoso"))
+	require.NoError(t, devConfig.Set("infra.parameters.replicas", "not-a-number"))
+	require.NoError(t, devConfig.Set("infra.parameters.adminPassword", "hunter2"))
+	require.NoError(t, devConfig.Set("infra.parameters.region", "westus"))
+	dev := environment.NewWithValues("dev", nil)
+	dev.Config = devConfig
+
+	newProvider := func(mockContext *mocks.MockContext, env *environment.Environment) *BicepProvider {
+		envManager := &mockenv.MockEnvManager{}
+		envManager.On("List", mock.Anything).Return([]*environment.Description{
+			{Name: "dev", HasLocal: true},
+			{Name: "remote-only", HasRemote: true},
+			{Name: env.Name(), HasLocal: true},
+		}, nil)
+		envManager.On("Get", mock.Anything, "dev").Return(dev, nil)
+
+		return &BicepProvider{console: mockContext.Console, env: env, envManager: envManager}
+	}
+
+	t.Run("reuses non-secure values from the selected environment", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.Console.WhenSelect(func(o input.ConsoleOptions) bool { return true }).
+			RespondFn(func(o input.ConsoleOptions) (any, error) {
+				require.Equal(t, []string{"No, enter new values", "dev (1 of 2 values)"}, o.Options)
+				return 1, nil
+			})
+
+		provider := newProvider(mockContext, environment.NewWithValues("test", nil))
+		seeded, err := provider.seedParametersFromEnvironment(t.Context(), prompts, []string{"region"})
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"appName": "contoso"}, seeded)
+	})
+
+	t.Run("declined", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.Console.WhenSelect(func(o input.ConsoleOptions) bool { return true }).Respond(0)
+
+		provider := newProvider(mockContext, environment.NewWithValues("test", nil))
+		seeded, err := provider.seedParametersFromEnvironment(t.Context(), prompts, nil)
+		require.NoError(t, err)
+		require.Empty(t, seeded)
+	})
+
+	t.Run("not offered when the environment already has parameter values", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		env := environment.NewWithValues("test", nil)
+		require.NoError(t, env.Config.Set("infra.parameters.other", "value"))
+
+		provider := &BicepProvider{console: mockContext.Console, env: env, envManager: &mockenv.MockEnvManager{}}
+		seeded, err := provider.seedParametersFromEnvironment(t.Context(), prompts, nil)
+		require.NoError(t, err)
+		require.Empty(t, seeded)
+	})
+}
@@ -0,0 +1,179 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+)
+
+// checkPolicyRestrictions is a ProvisionValidationCheckFn that evaluates each resource predicted by the Bicep snapshot
+// against the Azure Policy assignments of the scope it is deployed to, and reports an error for each policy that would
+// deny the resource. ARM only reports the first denied resource, after the deployment has started, so reporting them
+// all up front lets users fix the template in a single pass. The PolicyRestrictionsService is resolved lazily via the
+// service locator so it is only instantiated when actually needed.
+func (p *BicepProvider) checkPolicyRestrictions(
+	ctx context.Context, valCtx *validationContext,
+) ([]ProvisionValidationCheckResult, error) {
+	if len(valCtx.SnapshotResources) == 0 {
+		return nil, nil
+	}
+
+	var policyService *azapi.PolicyRestrictionsService
+	if err := p.serviceLocator.Resolve(&policyService); err != nil {
+		log.Printf("could not resolve PolicyRestrictionsService, skipping policy restrictions check: %v", err)
+		return nil, nil
+	}
+
+	subscriptionId := p.env.GetSubscriptionId()
+	if subscriptionId == "" {
+		log.Printf("no subscription ID set, skipping policy restrictions check")
+		return nil, nil
+	}
+
+	const docsLink = "https://learn.microsoft.com/azure/azure-resource-manager/troubleshooting/" +
+		"error-policy-requestdisallowedbypolicy"
+
+	var results []ProvisionValidationCheckResult
+	for _, resource := range valCtx.SnapshotResources {
+		if strings.EqualFold(resource.Type, "Microsoft.Resources/deployments") {
+			continue
+		}
+
+		resourceSubscriptionId, resourceGroupName := policyScope(resource, subscriptionId)
+		scope := azure.SubscriptionRID(resourceSubscriptionId)
+		if resourceGroupName != "" {
+			scope = azure.ResourceGroupRID(resourceSubscriptionId, resourceGroupName)
+		}
+
+		evaluations, err := policyService.CheckRestrictions(
+			ctx, resourceSubscriptionId, resourceGroupName, azapi.PolicyResourceDetails{
+				ResourceContent: policyResourceContent(resource, valCtx.EnvLocation),
+				ApiVersion:      resource.APIVersion,
+				Scope:           scope,
+			})
+		if err != nil {
+			// Users without Microsoft.PolicyInsights permissions can still deploy; ARM enforces the policies.
+			log.Printf("error checking policy restrictions of %s, skipping check: %v", resource.Name, err)
+			return nil, nil
+		}
+
+		for _, evaluation := range evaluations {
+			if !evaluation.IsDenied() {
+				continue
+			}
+
+			results = append(results, ProvisionValidationCheckResult{
+				Severity:     ProvisionValidationCheckError,
+				DiagnosticID: "policy_denied",
+				Message: fmt.Sprintf(
+					"Resource %s %s is denied by policy assignment %s\n"+
+						"Azure Policy will reject the resource and the deployment will fail.",
+					output.WithHighLightFormat("%q", resource.Name),
+					output.WithGrayFormat("(%s)", resource.Type),
+					output.WithHighLightFormat(policyAssignmentName(evaluation.PolicyInfo)),
+				),
+				Suggestion: fmt.Sprintf(
+					"Change the resource to comply with the policy %s, or ask an administrator of the"+
+						" subscription for a policy exemption.",
+					output.WithGrayFormat(evaluation.PolicyInfo.PolicyDefinitionId),
+				),
+				Links: []ux.ProvisionValidationReportLink{
+					{
+						URL:   docsLink,
+						Title: "Resolve errors for request disallowed by policy",
+					},
+				},
+			})
+		}
+	}
+
+	return results, nil
+}
+
+// policyScope returns the subscription and resource group the resource is deployed to, from its resolved resource ID.
+// Resources without a resolved ID, and resources deployed at the subscription scope, return an empty resource group.
+func policyScope(resource armTemplateResource, defaultSubscriptionId string) (string, string) {
+	if resource.Id == "" {
+		return defaultSubscriptionId, ""
+	}
+
+	resourceId, err := arm.ParseResourceID(resource.Id)
+	if err != nil {
+		return defaultSubscriptionId, ""
+	}
+
+	subscriptionId := resourceId.SubscriptionID
+	if subscriptionId == "" {
+		subscriptionId = defaultSubscriptionId
+	}
+
+	resourceGroupName := resourceId.ResourceGroupName
+	if strings.EqualFold(resource.Type, arm.ResourceGroupResourceType.String()) {
+		// A resource group is created in its subscription, not in itself.
+		resourceGroupName = ""
+	}
+
+	return subscriptionId, resourceGroupName
+}
+
+// policyResourceContent returns the resource content evaluated by Azure Policy: the fields of the resource that ARM
+// sends to the resource provider. Resources without a location are evaluated in fallbackLocation.
+func policyResourceContent(resource armTemplateResource, fallbackLocation string) map[string]any {
+	content := map[string]any{
+		"type": resource.Type,
+		"name": resource.Name,
+	}
+
+	location := resource.Location
+	if location == "" {
+		location = fallbackLocation
+	}
+	if location != "" {
+		content["location"] = location
+	}
+
+	if resource.Kind != "" {
+		content["kind"] = resource.Kind
+	}
+
+	fields := map[string]json.RawMessage{
+		"tags":     resource.Tags.Raw(),
+		"sku":      resource.SKU.Raw(),
+		"plan":     resource.Plan.Raw(),
+		"identity": resource.Identity.Raw(),
+		"zones":    resource.Zones.Raw(),
+	}
+	for name, raw := range fields {
+		if len(raw) > 0 && string(raw) != "null" {
+			content[name] = raw
+		}
+	}
+
+	if len(resource.Properties) > 0 {
+		content["properties"] = resource.Properties
+	}
+
+	return content
+}
+
+// policyAssignmentName returns the name of the policy assignment of the evaluation, or the name of its policy when the
+// assignment isn't known.
+func policyAssignmentName(policy azapi.PolicyReference) string {
+	if policy.PolicyAssignmentId != "" {
+		return path.Base(policy.PolicyAssignmentId)
+	}
+
+	return path.Base(policy.PolicyDefinitionId)
+}
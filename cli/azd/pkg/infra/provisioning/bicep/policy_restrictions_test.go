@@ -0,0 +1,120 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckPolicyRestrictions(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	ioc.RegisterInstance(mockContext.Container, azapi.NewPolicyRestrictionsService(
+		mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions))
+
+	var requestedPaths []string
+	var locations []any
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return strings.HasSuffix(request.URL.Path, "/providers/Microsoft.PolicyInsights/checkPolicyRestrictions")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		requestedPaths = append(requestedPaths, request.URL.Path)
+
+		contents, err := io.ReadAll(request.Body)
+		require.NoError(t, err)
+		var body struct {
+			ResourceDetails azapi.PolicyResourceDetails `json:"resourceDetails"`
+		}
+		require.NoError(t, json.Unmarshal(contents, &body))
+		locations = append(locations, body.ResourceDetails.ResourceContent["location"])
+
+		evaluations := []map[string]any{}
+		if body.ResourceDetails.ResourceContent["type"] == "Microsoft.Storage/storageAccounts" {
+			evaluations = append(evaluations, map[string]any{
+				"policyInfo": map[string]any{
+					"policyDefinitionId": "/providers/Microsoft.Authorization/policyDefinitions/allowed-locations",
+					"policyAssignmentId": "/subscriptions/SUB/providers/" +
+						"Microsoft.Authorization/policyAssignments/allowed-locations-assignment",
+				},
+				"evaluationResult": "NonCompliant",
+				"effectDetails":    map[string]any{"policyEffect": "Deny"},
+			})
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"contentEvaluationResult": map[string]any{"policyEvaluations": evaluations},
+		})
+	})
+
+	provider := &BicepProvider{
+		env:            environment.NewWithValues("test-env", map[string]string{"AZURE_SUBSCRIPTION_ID": "SUB"}),
+		serviceLocator: mockContext.Container,
+	}
+
+	results, err := provider.checkPolicyRestrictions(t.Context(), &validationContext{
+		EnvLocation: "eastus2",
+		SnapshotResources: []armTemplateResource{
+			{
+				Id:       "/subscriptions/SUB/resourceGroups/rg-app",
+				Type:     "Microsoft.Resources/resourceGroups",
+				Name:     "rg-app",
+				Location: "westus",
+			},
+			{
+				Id:   "/subscriptions/SUB/resourceGroups/rg-app/providers/Microsoft.Storage/storageAccounts/st1",
+				Type: "Microsoft.Storage/storageAccounts",
+				Name: "st1",
+			},
+			{
+				Type: "Microsoft.Resources/deployments",
+				Name: "resources",
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, ProvisionValidationCheckError, results[0].Severity)
+	require.Equal(t, "policy_denied", results[0].DiagnosticID)
+	require.Contains(t, results[0].Message, "st1")
+	require.Contains(t, results[0].Message, "allowed-locations-assignment")
+
+	require.Equal(t, []string{
+		"/subscriptions/SUB/providers/Microsoft.PolicyInsights/checkPolicyRestrictions",
+		"/subscriptions/SUB/resourceGroups/rg-app/providers/Microsoft.PolicyInsights/checkPolicyRestrictions",
+	}, requestedPaths)
+	require.Equal(t, []any{"westus", "eastus2"}, locations)
+}
+
+func TestCheckPolicyRestrictions_SkipsOnError(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	ioc.RegisterInstance(mockContext.Container, azapi.NewPolicyRestrictionsService(
+		mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions))
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return strings.HasSuffix(request.URL.Path, "/checkPolicyRestrictions")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateEmptyHttpResponse(request, http.StatusForbidden)
+	})
+
+	provider := &BicepProvider{
+		env:            environment.NewWithValues("test-env", map[string]string{"AZURE_SUBSCRIPTION_ID": "SUB"}),
+		serviceLocator: mockContext.Container,
+	}
+
+	results, err := provider.checkPolicyRestrictions(t.Context(), &validationContext{
+		SnapshotResources: []armTemplateResource{
+			{Type: "Microsoft.Storage/storageAccounts", Name: "st1"},
+		},
+	})
+	require.NoError(t, err)
+	require.Empty(t, results)
+}
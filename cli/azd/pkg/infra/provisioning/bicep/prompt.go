@@ -162,6 +162,28 @@ func usageNameDetailsFromString(usageName string) (usageNameDetails, error) {
 	}, nil
 }
 
+// parameterAnswered reports whether the answers file answers the prompt for the parameter key, either with
+// `parameters.<key>`, or with `location` and `resourceGroup` for location and resource group parameters.
+func (p *BicepProvider) parameterAnswered(key string, param azure.ArmTemplateParameterDefinition) bool {
+	if input.HasAnswer(p.console, input.ConsoleOptions{Key: "parameters." + key}) {
+		return true
+	}
+
+	azdMetadata, _ := param.AzdMetadata()
+	if provisioning.ParameterTypeFromArmType(param.Type) != provisioning.ParameterTypeString || azdMetadata.Type == nil {
+		return false
+	}
+
+	switch *azdMetadata.Type {
+	case azure.AzdMetadataTypeLocation:
+		return input.HasAnswer(p.console, input.ConsoleOptions{Key: "location"})
+	case azure.AzdMetadataTypeResourceGroup:
+		return input.HasAnswer(p.console, input.ConsoleOptions{Key: "resourceGroup"})
+	default:
+		return false
+	}
+}
+
 func (p *BicepProvider) promptForParameter(
 	ctx context.Context,
 	key string,
@@ -174,6 +196,7 @@ func (p *BicepProvider) promptForParameter(
 		securedParam = "secured parameter"
 	}
 	msg := fmt.Sprintf("Enter a value for the '%s' infrastructure %s:", key, securedParam)
+	answerKey := "parameters." + key
 	help, _ := param.Description()
 	azdMetadata, _ := param.AzdMetadata()
 	paramType := provisioning.ParameterTypeFromArmType(param.Type)
@@ -233,10 +256,16 @@ func (p *BicepProvider) promptForParameter(
 		azdMetadata.Type != nil &&
 		*azdMetadata.Type == azure.AzdMetadataTypeResourceGroup {
 
-		p.console.Message(ctx, fmt.Sprintf(
-			"Parameter %s requires an %s resource group.", output.WithUnderline("%s", key), output.WithBold("existing")))
+		// Lists the resource groups in the selected subscription. Unless the parameter is restricted to existing
+		// groups, the first option creates a new resource group.
+		if azdMetadata.Existing {
+			p.console.Message(ctx, fmt.Sprintf(
+				"Parameter %s requires an %s resource group.", output.WithUnderline("%s", key), output.WithBold("existing")))
+		} else {
+			p.console.Message(ctx, fmt.Sprintf("Parameter %s requires a resource group.", output.WithUnderline("%s", key)))
+		}
 		rgName, err := p.prompters.PromptResourceGroup(ctx, prompt.PromptResourceOptions{
-			DisableCreateNew: true,
+			DisableCreateNew: azdMetadata.Existing,
 		})
 		if err != nil {
 			return nil, err
@@ -262,6 +291,7 @@ func (p *BicepProvider) promptForParameter(
 
 		if manualUserInput {
 			resultValue, err := promptWithValidation(ctx, p.console, input.ConsoleOptions{
+				Key:        answerKey,
 				Message:    msg,
 				Help:       help,
 				IsPassword: isSecuredParam,
@@ -300,6 +330,7 @@ func (p *BicepProvider) promptForParameter(
 		}
 
 		choice, err := p.console.Select(ctx, input.ConsoleOptions{
+			Key:          answerKey,
 			Message:      msg,
 			Help:         help,
 			Options:      options,
@@ -326,6 +357,7 @@ func (p *BicepProvider) promptForParameter(
 				}
 			}
 			choice, err := p.console.Select(ctx, input.ConsoleOptions{
+				Key:          answerKey,
 				Message:      msg,
 				Help:         help,
 				Options:      options,
@@ -347,6 +379,7 @@ func (p *BicepProvider) promptForParameter(
 				}
 			}
 			userValue, err := promptWithValidation(ctx, p.console, input.ConsoleOptions{
+				Key:          answerKey,
 				Message:      msg,
 				Help:         help,
 				DefaultValue: defaultValueForPrompt,
@@ -357,6 +390,7 @@ func (p *BicepProvider) promptForParameter(
 			value = userValue
 		case provisioning.ParameterTypeString:
 			userValue, err := promptWithValidation(ctx, p.console, input.ConsoleOptions{
+				Key:          answerKey,
 				Message:      msg,
 				Help:         help,
 				IsPassword:   isSecuredParam,
@@ -368,6 +402,7 @@ func (p *BicepProvider) promptForParameter(
 			value = userValue
 		case provisioning.ParameterTypeArray:
 			userValue, err := promptWithValidation(ctx, p.console, input.ConsoleOptions{
+				Key:     answerKey,
 				Message: msg,
 				Help:    help,
 			}, convertJson[[]any], validateJsonArray)
@@ -377,6 +412,7 @@ func (p *BicepProvider) promptForParameter(
 			value = userValue
 		case provisioning.ParameterTypeObject:
 			userValue, err := promptWithValidation(ctx, p.console, input.ConsoleOptions{
+				Key:     answerKey,
 				Message: msg,
 				Help:    help,
 			}, convertJson[map[string]any], validateJsonObject)
@@ -0,0 +1,527 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+)
+
+// promptCondition is a parsed `promptIf` azd metadata expression. A parameter with a condition is only prompted for
+// when the condition holds for the values of the other parameters.
+//
+// Expressions compare parameters with literals or other parameters, for example:
+//
+//	useRedis == true
+//	tier != 'Basic' && replicas > 1
+//	!(useRedis || useCosmos)
+//
+// Supported operators are ==, !=, <, <=, >, >=, &&, || and !. Literals are true, false, null, numbers and single or
+// double quoted strings. A parameter used without an operator is true when it has a non-empty, non-zero value.
+type promptCondition struct {
+	expr conditionExpr
+	refs []string
+}
+
+// conditionExpr is a node of a parsed promptIf expression.
+type conditionExpr interface {
+	eval(values map[string]any) any
+}
+
+type conditionLiteral struct{ value any }
+
+type conditionRef struct{ name string }
+
+type conditionNot struct{ operand conditionExpr }
+
+type conditionBinary struct {
+	op          string
+	left, right conditionExpr
+}
+
+func (e conditionLiteral) eval(map[string]any) any { return e.value }
+
+func (e conditionRef) eval(values map[string]any) any { return normalizeConditionValue(values[e.name]) }
+
+func (e conditionNot) eval(values map[string]any) any {
+	return !conditionTruthy(e.operand.eval(values))
+}
+
+func (e conditionBinary) eval(values map[string]any) any {
+	switch e.op {
+	case "&&":
+		return conditionTruthy(e.left.eval(values)) && conditionTruthy(e.right.eval(values))
+	case "||":
+		return conditionTruthy(e.left.eval(values)) || conditionTruthy(e.right.eval(values))
+	}
+
+	left, right := e.left.eval(values), e.right.eval(values)
+	switch e.op {
+	case "==":
+		return conditionEqual(left, right)
+	case "!=":
+		return !conditionEqual(left, right)
+	}
+
+	// Ordering comparisons are only defined between two numbers or two strings.
+	var cmp int
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			return false
+		}
+		cmp = compareFloat(l, r)
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return false
+		}
+		cmp = strings.Compare(l, r)
+	default:
+		return false
+	}
+
+	switch e.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+func compareFloat(l, r float64) int {
+	switch {
+	case l < r:
+		return -1
+	case l > r:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// normalizeConditionValue converts parameter values to the types literals evaluate to, so a number parameter compares
+// equal to a number literal regardless of how it was decoded.
+func normalizeConditionValue(v any) any {
+	switch value := v.(type) {
+	case int:
+		return float64(value)
+	case int32:
+		return float64(value)
+	case int64:
+		return float64(value)
+	case float32:
+		return float64(value)
+	case json.Number:
+		if f, err := value.Float64(); err == nil {
+			return f
+		}
+		return value.String()
+	}
+
+	return v
+}
+
+func conditionEqual(left, right any) bool {
+	switch l := left.(type) {
+	case nil:
+		return right == nil
+	case bool, float64, string:
+		return l == right
+	default:
+		// Objects and arrays compare by their JSON form.
+		lj, lerr := json.Marshal(left)
+		rj, rerr := json.Marshal(right)
+		return lerr == nil && rerr == nil && string(lj) == string(rj)
+	}
+}
+
+func conditionTruthy(v any) bool {
+	switch value := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return value
+	case float64:
+		return value != 0
+	case string:
+		return value != ""
+	case map[string]any:
+		return len(value) > 0
+	case []any:
+		return len(value) > 0
+	default:
+		return true
+	}
+}
+
+// evaluate reports whether the condition holds for the given parameter values.
+func (c *promptCondition) evaluate(values map[string]any) bool {
+	return conditionTruthy(c.expr.eval(values))
+}
+
+// parsePromptCondition parses a promptIf expression.
+func parsePromptCondition(expression string) (*promptCondition, error) {
+	tokens, err := tokenizeCondition(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := &conditionParser{tokens: tokens}
+	expr, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if parser.pos < len(parser.tokens) {
+		return nil, fmt.Errorf("unexpected '%s'", parser.tokens[parser.pos].text)
+	}
+
+	return &promptCondition{expr: expr, refs: parser.refs}, nil
+}
+
+type conditionTokenKind int
+
+const (
+	conditionTokenIdent conditionTokenKind = iota
+	conditionTokenString
+	conditionTokenNumber
+	conditionTokenOperator
+)
+
+type conditionToken struct {
+	kind conditionTokenKind
+	text string
+}
+
+// conditionOperators lists the operators, longest first so that "<=" is not read as "<".
+var conditionOperators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")"}
+
+func tokenizeCondition(expression string) ([]conditionToken, error) {
+	var tokens []conditionToken
+	runes := []rune(expression)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"':
+			end := slices.Index(runes[i+1:], r)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string starting at position %d", i+1)
+			}
+			tokens = append(tokens, conditionToken{kind: conditionTokenString, text: string(runes[i+1 : i+1+end])})
+			i += end + 2
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, conditionToken{kind: conditionTokenNumber, text: string(runes[start:i])})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, conditionToken{kind: conditionTokenIdent, text: string(runes[start:i])})
+		default:
+			rest := string(runes[i:])
+			op := ""
+			for _, candidate := range conditionOperators {
+				if strings.HasPrefix(rest, candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character '%c' at position %d", r, i+1)
+			}
+			tokens = append(tokens, conditionToken{kind: conditionTokenOperator, text: op})
+			i += len([]rune(op))
+		}
+	}
+
+	return tokens, nil
+}
+
+// conditionParser is a recursive descent parser for promptIf expressions:
+//
+//	or         := and ("||" and)*
+//	and        := unary ("&&" unary)*
+//	unary      := "!" unary | comparison
+//	comparison := operand (("==" | "!=" | "<" | "<=" | ">" | ">=") operand)?
+//	operand    := "(" or ")" | identifier | literal
+type conditionParser struct {
+	tokens []conditionToken
+	pos    int
+	refs   []string
+}
+
+func (p *conditionParser) peekOperator(ops ...string) (string, bool) {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != conditionTokenOperator {
+		return "", false
+	}
+	if slices.Contains(ops, p.tokens[p.pos].text) {
+		return p.tokens[p.pos].text, true
+	}
+	return "", false
+}
+
+func (p *conditionParser) parseOr() (conditionExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.peekOperator("||"); !ok {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = conditionBinary{op: "||", left: left, right: right}
+	}
+}
+
+func (p *conditionParser) parseAnd() (conditionExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.peekOperator("&&"); !ok {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = conditionBinary{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *conditionParser) parseUnary() (conditionExpr, error) {
+	if _, ok := p.peekOperator("!"); ok {
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return conditionNot{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *conditionParser) parseComparison() (conditionExpr, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.peekOperator("==", "!=", "<", "<=", ">", ">=")
+	if !ok {
+		return left, nil
+	}
+	p.pos++
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return conditionBinary{op: op, left: left, right: right}, nil
+}
+
+func (p *conditionParser) parseOperand() (conditionExpr, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	token := p.tokens[p.pos]
+	p.pos++
+
+	switch token.kind {
+	case conditionTokenString:
+		return conditionLiteral{value: token.text}, nil
+	case conditionTokenNumber:
+		f, err := strconv.ParseFloat(token.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number '%s'", token.text)
+		}
+		return conditionLiteral{value: f}, nil
+	case conditionTokenIdent:
+		switch token.text {
+		case "true":
+			return conditionLiteral{value: true}, nil
+		case "false":
+			return conditionLiteral{value: false}, nil
+		case "null":
+			return conditionLiteral{value: nil}, nil
+		}
+		if !slices.Contains(p.refs, token.text) {
+			p.refs = append(p.refs, token.text)
+		}
+		return conditionRef{name: token.text}, nil
+	}
+
+	if token.text == "(" {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.peekOperator(")"); !ok {
+			return nil, fmt.Errorf("missing ')'")
+		}
+		p.pos++
+		return expr, nil
+	}
+
+	return nil, fmt.Errorf("unexpected '%s'", token.text)
+}
+
+// promptConditions parses the promptIf metadata of the parameters about to be prompted for, keyed by parameter name.
+// Conditions may only reference parameters of the template, and a parameter can't depend on itself.
+func promptConditions(
+	template azure.ArmTemplate,
+	parameterPrompts []struct {
+		key   string
+		param azure.ArmTemplateParameterDefinition
+	},
+) (map[string]*promptCondition, error) {
+	conditions := map[string]*promptCondition{}
+
+	for _, prompt := range parameterPrompts {
+		azdMetadata, has := prompt.param.AzdMetadata()
+		if !has || strings.TrimSpace(azdMetadata.PromptIf) == "" {
+			continue
+		}
+
+		condition, err := parsePromptCondition(azdMetadata.PromptIf)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"invalid promptIf expression '%s' for parameter '%s': %w", azdMetadata.PromptIf, prompt.key, err)
+		}
+
+		for _, ref := range condition.refs {
+			if _, has := template.Parameters[ref]; !has || ref == prompt.key {
+				return nil, fmt.Errorf(
+					"invalid promptIf expression '%s' for parameter '%s': '%s' is not another parameter of the template",
+					azdMetadata.PromptIf, prompt.key, ref)
+			}
+		}
+
+		conditions[prompt.key] = condition
+	}
+
+	return conditions, nil
+}
+
+// nextPromptRound splits the pending prompts into the ones that can be prompted for now, because their promptIf
+// condition only references parameters that are not pending, and the ones that have to wait for a later round.
+func nextPromptRound(
+	pending []struct {
+		key   string
+		param azure.ArmTemplateParameterDefinition
+	},
+	conditions map[string]*promptCondition,
+) (round, deferred []struct {
+	key   string
+	param azure.ArmTemplateParameterDefinition
+}) {
+	for _, prompt := range pending {
+		condition, has := conditions[prompt.key]
+		ready := !has || !slices.ContainsFunc(condition.refs, func(ref string) bool {
+			return slices.ContainsFunc(pending, func(other struct {
+				key   string
+				param azure.ArmTemplateParameterDefinition
+			}) bool {
+				return other.key == ref
+			})
+		})
+
+		if ready {
+			round = append(round, prompt)
+		} else {
+			deferred = append(deferred, prompt)
+		}
+	}
+
+	return round, deferred
+}
+
+// skipUnneededPrompts returns the prompts whose promptIf condition holds for values, or that have no condition. The
+// parameters that are skipped are left unset, and aren't saved in the environment, so that the condition is evaluated
+// again on the next provision.
+func skipUnneededPrompts(
+	prompts []struct {
+		key   string
+		param azure.ArmTemplateParameterDefinition
+	},
+	conditions map[string]*promptCondition,
+	values map[string]any,
+) []struct {
+	key   string
+	param azure.ArmTemplateParameterDefinition
+} {
+	var needed []struct {
+		key   string
+		param azure.ArmTemplateParameterDefinition
+	}
+
+	for _, prompt := range prompts {
+		if condition, has := conditions[prompt.key]; has && !condition.evaluate(values) {
+			log.Printf("skipping prompt for parameter '%s': its promptIf condition does not hold", prompt.key)
+			continue
+		}
+		needed = append(needed, prompt)
+	}
+
+	return needed
+}
+
+// hasPromptIf reports whether the parameter declares a promptIf condition.
+func hasPromptIf(param azure.ArmTemplateParameterDefinition) bool {
+	azdMetadata, has := param.AzdMetadata()
+	return has && strings.TrimSpace(azdMetadata.PromptIf) != ""
+}
+
+// conditionValues returns the values promptIf conditions are evaluated over: the parameters configured so far, and the
+// literal default values of the template. Key Vault references count as a non-empty value.
+func conditionValues(template azure.ArmTemplate, configuredParameters azure.ArmParameters) map[string]any {
+	values := map[string]any{}
+
+	for key, param := range template.Parameters {
+		if param.DefaultValue == nil {
+			continue
+		}
+		// Skip ARM template expressions such as "[resourceGroup().location]"; they are only evaluated by ARM.
+		if s, isString := param.DefaultValue.(string); isString && strings.HasPrefix(s, "[") {
+			continue
+		}
+		values[key] = param.DefaultValue
+	}
+
+	for key, param := range configuredParameters {
+		if param.KeyVaultReference != nil {
+			values[key] = param.KeyVaultReference.SecretName
+			continue
+		}
+		values[key] = param.Value
+	}
+
+	return values
+}
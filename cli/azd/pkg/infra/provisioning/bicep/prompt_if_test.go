@@ -0,0 +1,177 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromptConditionEvaluate(t *testing.T) {
+	values := map[string]any{
+		"useRedis":  true,
+		"useCosmos": false,
+		"tier":      "Premium",
+		"replicas":  3,
+		"count":     json.Number("2"),
+		"empty":     "",
+	}
+
+	tests := []struct {
+		expression string
+		want       bool
+	}{
+		{"useRedis == true", true},
+		{"useRedis", true},
+		{"!useRedis", false},
+		{"useCosmos == true", false},
+		{"tier == 'Premium'", true},
+		{`tier != "Basic"`, true},
+		{"replicas > 1", true},
+		{"replicas >= 3 && replicas <= 3", true},
+		{"count == 2", true},
+		{"replicas < count", false},
+		{"useCosmos || tier == 'Premium'", true},
+		{"!(useRedis || useCosmos)", false},
+		{"useRedis && useCosmos || replicas == 3", true},
+		{"empty", false},
+		{"missing == null", true},
+		{"missing", false},
+		{"tier > 1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			condition, err := parsePromptCondition(tt.expression)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, condition.evaluate(values))
+		})
+	}
+}
+
+func TestParsePromptConditionErrors(t *testing.T) {
+	tests := map[string]string{
+		"useRedis ==":        "unexpected end of expression",
+		"(useRedis == true":  "missing ')'",
+		"tier == 'Premium":   "unterminated string",
+		"useRedis = true":    "unexpected character '='",
+		"useRedis true":      "unexpected 'true'",
+		"useRedis == && foo": "unexpected '&&'",
+	}
+
+	for expression, wantErr := range tests {
+		t.Run(expression, func(t *testing.T) {
+			_, err := parsePromptCondition(expression)
+			require.ErrorContains(t, err, wantErr)
+		})
+	}
+}
+
+func TestPromptConditionRefs(t *testing.T) {
+	condition, err := parsePromptCondition("useRedis && (tier == 'Premium' || useRedis == false)")
+	require.NoError(t, err)
+	require.Equal(t, []string{"useRedis", "tier"}, condition.refs)
+}
+
+func promptIfParam(paramType string, promptIf string) azure.ArmTemplateParameterDefinition {
+	param := azure.ArmTemplateParameterDefinition{Type: paramType}
+	if promptIf != "" {
+		metadata, _ := json.Marshal(map[string]any{"promptIf": promptIf})
+		param.Metadata = map[string]json.RawMessage{"azd": metadata}
+	}
+	return param
+}
+
+func TestPromptConditionRounds(t *testing.T) {
+	template := azure.ArmTemplate{
+		Parameters: azure.ArmTemplateParameterDefinitions{
+			"redisSku":   promptIfParam("string", "useRedis"),
+			"redisCount": promptIfParam("int", "redisSku == 'Premium'"),
+			"useRedis":   promptIfParam("bool", ""),
+			"tier":       promptIfParam("string", "mode != 'dev'"),
+			"mode":       {Type: "string", DefaultValue: "dev"},
+		},
+	}
+
+	var prompts []struct {
+		key   string
+		param azure.ArmTemplateParameterDefinition
+	}
+	for _, key := range []string{"redisCount", "redisSku", "tier", "useRedis"} {
+		prompts = append(prompts, struct {
+			key   string
+			param azure.ArmTemplateParameterDefinition
+		}{key: key, param: template.Parameters[key]})
+	}
+
+	conditions, err := promptConditions(template, prompts)
+	require.NoError(t, err)
+	require.Len(t, conditions, 3)
+
+	keys := func(prompts []struct {
+		key   string
+		param azure.ArmTemplateParameterDefinition
+	}) []string {
+		var result []string
+		for _, prompt := range prompts {
+			result = append(result, prompt.key)
+		}
+		return result
+	}
+
+	// The first round holds the parameters whose conditions don't depend on other pending parameters.
+	round, deferred := nextPromptRound(prompts, conditions)
+	require.Equal(t, []string{"tier", "useRedis"}, keys(round))
+	require.Equal(t, []string{"redisCount", "redisSku"}, keys(deferred))
+
+	// "mode" defaults to "dev", so "tier" is skipped and left unset.
+	configured := azure.ArmParameters{}
+	needed := skipUnneededPrompts(round, conditions, conditionValues(template, configured))
+	require.Equal(t, []string{"useRedis"}, keys(needed))
+	require.NotContains(t, configured, "tier")
+
+	// Answering "no" to useRedis skips both redis parameters over the next rounds.
+	configured["useRedis"] = azure.ArmParameter{Value: false}
+	round, deferred = nextPromptRound(deferred, conditions)
+	require.Equal(t, []string{"redisSku"}, keys(round))
+	require.Empty(t, skipUnneededPrompts(round, conditions, conditionValues(template, configured)))
+
+	round, deferred = nextPromptRound(deferred, conditions)
+	require.Equal(t, []string{"redisCount"}, keys(round))
+	require.Empty(t, deferred)
+	require.Empty(t, skipUnneededPrompts(round, conditions, conditionValues(template, configured)))
+	require.NotContains(t, configured, "redisCount")
+}
+
+func TestHasPromptIf(t *testing.T) {
+	require.True(t, hasPromptIf(promptIfParam("string", "useRedis")))
+	require.False(t, hasPromptIf(promptIfParam("string", "")))
+}
+
+func TestPromptConditionsValidation(t *testing.T) {
+	template := azure.ArmTemplate{
+		Parameters: azure.ArmTemplateParameterDefinitions{
+			"self":    promptIfParam("string", "self == 'x'"),
+			"unknown": promptIfParam("string", "nope"),
+			"invalid": promptIfParam("string", "a =="),
+		},
+	}
+
+	for key, wantErr := range map[string]string{
+		"self":    "'self' is not another parameter of the template",
+		"unknown": "'nope' is not another parameter of the template",
+		"invalid": "invalid promptIf expression 'a ==' for parameter 'invalid'",
+	} {
+		t.Run(key, func(t *testing.T) {
+			_, err := promptConditions(template, []struct {
+				key   string
+				param azure.ArmTemplateParameterDefinition
+			}{{key: key, param: template.Parameters[key]}})
+			require.ErrorContains(t, err, wantErr)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+// resourceGroupPrompter records the options of PromptResourceGroup calls.
+type resourceGroupPrompter struct {
+	prompt.Prompter
+	options []prompt.PromptResourceOptions
+}
+
+func (p *resourceGroupPrompter) PromptResourceGroup(
+	ctx context.Context, options prompt.PromptResourceOptions) (string, error) {
+	p.options = append(p.options, options)
+	return "rg-selected", nil
+}
+
+func TestPromptForParameterResourceGroup(t *testing.T) {
+	tests := []struct {
+		name             string
+		metadata         string
+		disableCreateNew bool
+	}{
+		{name: "offers creating a new group", metadata: `{"type":"resourceGroup"}`},
+		{name: "existing only", metadata: `{"type":"resourceGroup","existing":true}`, disableCreateNew: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			prompter := &resourceGroupPrompter{}
+			provider := &BicepProvider{console: mockContext.Console, prompters: prompter}
+
+			value, err := provider.promptForParameter(t.Context(), "rgName", azure.ArmTemplateParameterDefinition{
+				Type:     "string",
+				Metadata: map[string]json.RawMessage{"azd": json.RawMessage(tt.metadata)},
+			}, nil)
+			require.NoError(t, err)
+			require.Equal(t, "rg-selected", value)
+			require.Equal(t, []prompt.PromptResourceOptions{{DisableCreateNew: tt.disableCreateNew}}, prompter.options)
+		})
+	}
+}
@@ -83,6 +83,8 @@ func (f armField[T]) MarshalJSON() ([]byte, error) {
 // It follows the schema defined at:
 // https://learn.microsoft.com/azure/azure-resource-manager/templates/resource-declaration
 type armTemplateResource struct {
+	// Id is the resolved resource ID of a resource predicted by the Bicep snapshot. It is empty in ARM templates.
+	Id string `json:"id,omitempty"`
 	// Type is the resource type including namespace (e.g. "Microsoft.Storage/storageAccounts").
 	Type string `json:"type"`
 	// APIVersion is the REST API version to use for the resource (e.g. "2023-01-01").
@@ -0,0 +1,388 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+)
+
+// vmDemand is the number of virtual machines of a size that a resource of the template deploys.
+type vmDemand struct {
+	// ResourceName is the name of the resource that deploys the virtual machines.
+	ResourceName string
+	// ResourceType is the type of the resource that deploys the virtual machines.
+	ResourceType string
+	// VmSize is the name of the virtual machine size, for example Standard_D2s_v3.
+	VmSize string
+	// Count is the number of virtual machines.
+	Count int
+	// Location is the Azure region of the virtual machines.
+	Location string
+}
+
+// appServicePlanDemand is an App Service plan deployed by the template.
+type appServicePlanDemand struct {
+	// ResourceName is the name of the App Service plan.
+	ResourceName string
+	// Tier is the pricing tier of the plan, for example PremiumV3.
+	Tier string
+	// Location is the Azure region of the plan.
+	Location string
+}
+
+// checkRegionalCapacity is a ProvisionValidationCheckFn that verifies the virtual machine sizes and App Service plan
+// tiers of the template are offered in the region they are deployed to, and that the subscription has enough vCPU
+// quota for the virtual machines. Model deployment capacity is validated by checkAiModelQuota. The
+// RegionalCapacityService is resolved lazily via the service locator so it is only instantiated when actually needed.
+func (p *BicepProvider) checkRegionalCapacity(
+	ctx context.Context, valCtx *validationContext,
+) ([]ProvisionValidationCheckResult, error) {
+	vmDemands, planDemands := analyzeCapacityDemands(valCtx.SnapshotResources, strings.ToLower(valCtx.EnvLocation))
+	if len(vmDemands) == 0 && len(planDemands) == 0 {
+		return nil, nil
+	}
+
+	var capacityService *azapi.RegionalCapacityService
+	if err := p.serviceLocator.Resolve(&capacityService); err != nil {
+		log.Printf("could not resolve RegionalCapacityService, skipping regional capacity check: %v", err)
+		return nil, nil
+	}
+
+	subscriptionId := p.env.GetSubscriptionId()
+	if subscriptionId == "" {
+		log.Printf("no subscription ID set, skipping regional capacity check")
+		return nil, nil
+	}
+
+	results := p.checkVmCapacity(ctx, capacityService, subscriptionId, vmDemands)
+	results = append(results, p.checkAppServicePlanAvailability(ctx, capacityService, subscriptionId, planDemands)...)
+
+	return results, nil
+}
+
+// checkVmCapacity reports the virtual machine sizes that aren't offered in their region, and the quotas the virtual
+// machines of the template would exceed.
+func (p *BicepProvider) checkVmCapacity(
+	ctx context.Context,
+	capacityService *azapi.RegionalCapacityService,
+	subscriptionId string,
+	demands []vmDemand,
+) []ProvisionValidationCheckResult {
+	// Group demands by location to minimize API calls.
+	byLocation := map[string][]vmDemand{}
+	for _, demand := range demands {
+		byLocation[demand.Location] = append(byLocation[demand.Location], demand)
+	}
+
+	changeLocation := output.WithHighLightFormat("azd env set AZURE_LOCATION <location>")
+	var results []ProvisionValidationCheckResult
+
+	for _, location := range slices.Sorted(maps.Keys(byLocation)) {
+		skus, err := capacityService.ListVmSkus(ctx, subscriptionId, location)
+		if err != nil {
+			log.Printf("failed to fetch VM sizes for location %s, skipping: %v", location, err)
+			continue
+		}
+
+		skusByName := map[string]azapi.VmSku{}
+		for _, sku := range skus {
+			skusByName[strings.ToLower(sku.Name)] = sku
+		}
+
+		// Aggregate the required vCPUs per quota so that resources sharing a quota are checked against their
+		// combined demand.
+		requiredByUsage := map[string]float64{}
+		for _, demand := range byLocation[location] {
+			sku, has := skusByName[strings.ToLower(demand.VmSize)]
+			if !has || sku.Restricted {
+				reason := "is not offered in"
+				if has {
+					reason = "is not available to the subscription in"
+				}
+
+				results = append(results, ProvisionValidationCheckResult{
+					Severity:     ProvisionValidationCheckWarning,
+					DiagnosticID: "vm_size_unavailable",
+					Message: fmt.Sprintf(
+						"VM size %s of %s %s %s %s\n"+
+							"Provisioning will likely fail.",
+						output.WithHighLightFormat("%q", demand.VmSize),
+						output.WithHighLightFormat("%q", demand.ResourceName),
+						output.WithGrayFormat("(%s)", demand.ResourceType),
+						reason,
+						output.WithHighLightFormat(location),
+					),
+					Suggestion: fmt.Sprintf(
+						"Choose another VM size or change your deployment location via %s.", changeLocation),
+					Links: []ux.ProvisionValidationReportLink{
+						{
+							URL: "https://learn.microsoft.com/troubleshoot/azure/azure-kubernetes/" +
+								"error-codes/skunotavailable-error",
+							Title: "Resolve errors for SKU not available",
+						},
+					},
+				})
+				continue
+			}
+
+			vCpus := float64(sku.VCpus * demand.Count)
+			requiredByUsage[azapi.TotalRegionalVCpusUsageName] += vCpus
+			if sku.Family != "" {
+				requiredByUsage[sku.Family] += vCpus
+			}
+		}
+
+		if len(requiredByUsage) == 0 {
+			continue
+		}
+
+		usages, err := capacityService.ListComputeUsages(ctx, subscriptionId, location)
+		if err != nil {
+			log.Printf("failed to fetch compute quota for location %s, skipping: %v", location, err)
+			continue
+		}
+
+		for _, usage := range usages {
+			required, has := requiredByUsage[usage.Name]
+			if !has {
+				continue
+			}
+
+			remaining := usage.Limit - usage.CurrentValue
+			if remaining >= required {
+				continue
+			}
+
+			quotaName := fmt.Sprintf("%s vCPUs", usage.Name)
+			if usage.Name == azapi.TotalRegionalVCpusUsageName {
+				quotaName = "Total Regional vCPUs"
+			}
+
+			results = append(results, ProvisionValidationCheckResult{
+				Severity:     ProvisionValidationCheckWarning,
+				DiagnosticID: "vm_quota_exceeded",
+				Message: fmt.Sprintf(
+					"Insufficient %s quota in %s\n"+
+						"Requested: %.0f · Available: %.0f",
+					output.WithHighLightFormat(quotaName),
+					output.WithHighLightFormat(location),
+					required,
+					max(remaining, 0),
+				),
+				Suggestion: fmt.Sprintf(
+					"Use smaller VM sizes, change your deployment location via %s,"+
+						" or request a quota increase in the Azure portal.",
+					changeLocation,
+				),
+				Links: []ux.ProvisionValidationReportLink{
+					{
+						URL:   "https://learn.microsoft.com/azure/quotas/per-vm-quota-requests",
+						Title: "Increase VM-family vCPU quotas",
+					},
+				},
+			})
+		}
+	}
+
+	return results
+}
+
+// checkAppServicePlanAvailability reports the App Service plans whose pricing tier isn't offered in their region.
+func (p *BicepProvider) checkAppServicePlanAvailability(
+	ctx context.Context,
+	capacityService *azapi.RegionalCapacityService,
+	subscriptionId string,
+	demands []appServicePlanDemand,
+) []ProvisionValidationCheckResult {
+	locationsByTier := map[string][]string{}
+	var results []ProvisionValidationCheckResult
+
+	for _, demand := range demands {
+		locations, has := locationsByTier[demand.Tier]
+		if !has {
+			var err error
+			locations, err = capacityService.ListAppServiceLocations(ctx, subscriptionId, demand.Tier)
+			if err != nil {
+				log.Printf("failed to fetch App Service locations for tier %s, skipping: %v", demand.Tier, err)
+				continue
+			}
+
+			locationsByTier[demand.Tier] = locations
+		}
+
+		// An empty list means the tier is unknown to the API, rather than offered nowhere.
+		if len(locations) == 0 || slices.Contains(locations, demand.Location) {
+			continue
+		}
+
+		suggested := locations
+		if len(suggested) > 5 {
+			suggested = suggested[:5]
+		}
+
+		results = append(results, ProvisionValidationCheckResult{
+			Severity:     ProvisionValidationCheckWarning,
+			DiagnosticID: "app_service_tier_unavailable",
+			Message: fmt.Sprintf(
+				"App Service plan %s uses the %s tier, which is not offered in %s\n"+
+					"Provisioning will likely fail.",
+				output.WithHighLightFormat("%q", demand.ResourceName),
+				output.WithHighLightFormat(demand.Tier),
+				output.WithHighLightFormat(demand.Location),
+			),
+			Suggestion: fmt.Sprintf(
+				"Choose another pricing tier or change your deployment location via %s,"+
+					" for example to one of: %s.",
+				output.WithHighLightFormat("azd env set AZURE_LOCATION <location>"),
+				strings.Join(suggested, ", "),
+			),
+			Links: []ux.ProvisionValidationReportLink{
+				{
+					URL:   "https://azure.microsoft.com/explore/global-infrastructure/products-by-region",
+					Title: "Azure products available by region",
+				},
+			},
+		})
+	}
+
+	return results
+}
+
+// vmSizeProperties mirrors the properties of resources that deploy virtual machines: the hardware profile of
+// Microsoft.Compute/virtualMachines, the agent pools of Microsoft.ContainerService/managedClusters and the properties
+// of Microsoft.ContainerService/managedClusters/agentPools.
+type vmSizeProperties struct {
+	HardwareProfile struct {
+		VmSize string `json:"vmSize"`
+	} `json:"hardwareProfile"`
+	AgentPoolProfiles []agentPoolProperties `json:"agentPoolProfiles"`
+	agentPoolProperties
+}
+
+type agentPoolProperties struct {
+	Name   string `json:"name"`
+	VmSize string `json:"vmSize"`
+	Count  *int   `json:"count"`
+}
+
+// analyzeCapacityDemands returns the virtual machines and App Service plans deployed by the snapshot resources.
+// Resources without a location are deployed to fallbackLocation.
+func analyzeCapacityDemands(
+	resources []armTemplateResource, fallbackLocation string,
+) ([]vmDemand, []appServicePlanDemand) {
+	var vms []vmDemand
+	var plans []appServicePlanDemand
+
+	for _, r := range resources {
+		location := strings.ToLower(r.Location)
+		if location == "" {
+			location = fallbackLocation
+		}
+		if location == "" {
+			continue
+		}
+
+		var props vmSizeProperties
+		if len(r.Properties) > 0 {
+			if err := json.Unmarshal(r.Properties, &props); err != nil {
+				log.Printf("skipping capacity check of %s: %v", r.Name, err)
+				continue
+			}
+		}
+
+		addVms := func(vmSize string, count int) {
+			if vmSize == "" || count <= 0 {
+				return
+			}
+
+			vms = append(vms, vmDemand{
+				ResourceName: r.Name,
+				ResourceType: r.Type,
+				VmSize:       vmSize,
+				Count:        count,
+				Location:     location,
+			})
+		}
+
+		switch strings.ToLower(r.Type) {
+		case "microsoft.compute/virtualmachines":
+			addVms(props.HardwareProfile.VmSize, 1)
+		case "microsoft.compute/virtualmachinescalesets":
+			if sku, has := r.SKU.Value(); has {
+				addVms(sku.Name, poolCount(sku.Capacity))
+			}
+		case "microsoft.containerservice/managedclusters":
+			for _, pool := range props.AgentPoolProfiles {
+				addVms(pool.VmSize, poolCount(pool.Count))
+			}
+		case "microsoft.containerservice/managedclusters/agentpools":
+			addVms(props.VmSize, poolCount(props.Count))
+		case "microsoft.web/serverfarms":
+			if sku, has := r.SKU.Value(); has {
+				if tier := appServicePlanTier(sku); tier != "" {
+					plans = append(plans, appServicePlanDemand{
+						ResourceName: r.Name,
+						Tier:         tier,
+						Location:     location,
+					})
+				}
+			}
+		}
+	}
+
+	return vms, plans
+}
+
+// poolCount returns the number of virtual machines of a pool, which defaults to one.
+func poolCount(count *int) int {
+	if count == nil {
+		return 1
+	}
+
+	return *count
+}
+
+// appServicePlanTierPrefixes maps the prefixes of App Service plan SKU names to their pricing tier, most specific
+// first.
+var appServicePlanTierPrefixes = []struct {
+	prefix string
+	suffix string
+	tier   string
+}{
+	{prefix: "EP", tier: "ElasticPremium"},
+	{prefix: "Y", tier: "Dynamic"},
+	{prefix: "F1", tier: "Free"},
+	{prefix: "D", tier: "Shared"},
+	{prefix: "B", tier: "Basic"},
+	{prefix: "S", tier: "Standard"},
+	{prefix: "P", suffix: "V3", tier: "PremiumV3"},
+	{prefix: "P", suffix: "V2", tier: "PremiumV2"},
+	{prefix: "I", suffix: "V2", tier: "IsolatedV2"},
+}
+
+// appServicePlanTier returns the pricing tier of an App Service plan SKU, or an empty string when it isn't known.
+func appServicePlanTier(sku armTemplateSKU) string {
+	if sku.Tier != "" {
+		return sku.Tier
+	}
+
+	name := strings.ToUpper(sku.Name)
+	for _, mapping := range appServicePlanTierPrefixes {
+		if strings.HasPrefix(name, mapping.prefix) && strings.HasSuffix(name, mapping.suffix) {
+			return mapping.tier
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,145 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRegionalCapacity(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	ioc.RegisterInstance(mockContext.Container, azapi.NewRegionalCapacityService(
+		mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions))
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return strings.HasSuffix(request.URL.Path, "/providers/Microsoft.Compute/skus")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"value": []map[string]any{
+				{
+					"resourceType": "virtualMachines",
+					"name":         "Standard_D4s_v3",
+					"family":       "standardDSv3Family",
+					"capabilities": []map[string]any{{"name": "vCPUs", "value": "4"}},
+				},
+				{
+					"resourceType": "virtualMachines",
+					"name":         "Standard_NC6s_v3",
+					"family":       "standardNCSv3Family",
+					"capabilities": []map[string]any{{"name": "vCPUs", "value": "6"}},
+					"restrictions": []map[string]any{
+						{"type": "Location", "values": []string{"eastus"}, "reasonCode": "NotAvailableForSubscription"},
+					},
+				},
+			},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return strings.HasSuffix(request.URL.Path, "/providers/Microsoft.Compute/locations/eastus/usages")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"value": []map[string]any{
+				{"currentValue": 0, "limit": 100, "name": map[string]any{"value": "cores"}},
+				{"currentValue": 4, "limit": 10, "name": map[string]any{"value": "standardDSv3Family"}},
+			},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return strings.HasSuffix(request.URL.Path, "/providers/Microsoft.Web/geoRegions")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"value": []map[string]any{{"name": "West US 3"}},
+		})
+	})
+
+	provider := &BicepProvider{
+		env:            environment.NewWithValues("test-env", map[string]string{"AZURE_SUBSCRIPTION_ID": "SUB"}),
+		serviceLocator: mockContext.Container,
+	}
+
+	results, err := provider.checkRegionalCapacity(t.Context(), &validationContext{
+		EnvLocation: "eastus",
+		SnapshotResources: []armTemplateResource{
+			{
+				Type: "Microsoft.ContainerService/managedClusters",
+				Name: "aks",
+				Properties: json.RawMessage(
+					`{"agentPoolProfiles":[{"name":"system","vmSize":"Standard_D4s_v3","count":2}]}`),
+			},
+			{
+				Type:       "Microsoft.Compute/virtualMachines",
+				Name:       "gpu-vm",
+				Properties: json.RawMessage(`{"hardwareProfile":{"vmSize":"Standard_NC6s_v3"}}`),
+			},
+			{
+				Type: "Microsoft.Web/serverfarms",
+				Name: "plan",
+				SKU:  mustArmField(armTemplateSKU{Name: "P1v3"}),
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	require.Equal(t, "vm_size_unavailable", results[0].DiagnosticID)
+	require.Contains(t, results[0].Message, "Standard_NC6s_v3")
+	require.Contains(t, results[0].Message, "is not available to the subscription in")
+
+	require.Equal(t, "vm_quota_exceeded", results[1].DiagnosticID)
+	require.Contains(t, results[1].Message, "standardDSv3Family")
+	require.Contains(t, results[1].Message, "Requested: 8 · Available: 6")
+
+	require.Equal(t, "app_service_tier_unavailable", results[2].DiagnosticID)
+	require.Contains(t, results[2].Message, "PremiumV3")
+	require.Contains(t, results[2].Suggestion, "westus3")
+}
+
+func TestCheckRegionalCapacity_NoDemands(t *testing.T) {
+	provider := &BicepProvider{}
+
+	results, err := provider.checkRegionalCapacity(t.Context(), &validationContext{
+		EnvLocation: "eastus",
+		SnapshotResources: []armTemplateResource{
+			{Type: "Microsoft.Storage/storageAccounts", Name: "st1"},
+		},
+	})
+	require.NoError(t, err)
+	require.Empty(t, results)
+}
+
+func TestAppServicePlanTier(t *testing.T) {
+	tests := map[string]string{
+		"F1":    "Free",
+		"D1":    "Shared",
+		"B1":    "Basic",
+		"S2":    "Standard",
+		"P1v2":  "PremiumV2",
+		"P1v3":  "PremiumV3",
+		"P1mv3": "PremiumV3",
+		"I1v2":  "IsolatedV2",
+		"EP1":   "ElasticPremium",
+		"Y1":    "Dynamic",
+		"FC1":   "",
+		"P1":    "",
+	}
+
+	for name, tier := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tier, appServicePlanTier(armTemplateSKU{Name: name}))
+		})
+	}
+
+	require.Equal(t, "Basic", appServicePlanTier(armTemplateSKU{Name: "custom", Tier: "Basic"}))
+}
@@ -0,0 +1,124 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/errorhandler"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/bicep"
+)
+
+// User-facing labels for the registry sign-in prompt.
+const (
+	registryAuthOptionAzCli      = "Sign in with the Azure CLI (az login)"
+	registryAuthOptionPowerShell = "Sign in with Azure PowerShell (Connect-AzAccount)"
+	registryAuthOptionRetry      = "I've signed in another way, try again"
+	registryAuthOptionCancel     = "Cancel"
+)
+
+// buildWithRegistryAuth runs build and, when bicep isn't authorized to restore modules from a private registry, lets
+// the user sign in with a credential bicep can use and tries again. Failed builds aren't cached, so each attempt
+// restores the modules again.
+func (p *BicepProvider) buildWithRegistryAuth(
+	ctx context.Context,
+	build func() (bicep.BuildResult, error),
+) (bicep.BuildResult, error) {
+	for {
+		res, err := build()
+
+		var authErr *bicep.RegistryAuthError
+		if err == nil || !errors.As(err, &authErr) {
+			return res, err
+		}
+
+		if p.console.IsNoPromptMode() {
+			return res, registryAuthErrorWithSuggestion(authErr, err)
+		}
+
+		retry, promptErr := p.promptRegistrySignIn(ctx, authErr)
+		if promptErr != nil {
+			return res, promptErr
+		}
+
+		if !retry {
+			return res, registryAuthErrorWithSuggestion(authErr, err)
+		}
+	}
+}
+
+// promptRegistrySignIn asks the user how to sign in to the registries in authErr and runs the sign-in they pick. It
+// returns false when the user cancels.
+func (p *BicepProvider) promptRegistrySignIn(
+	ctx context.Context,
+	authErr *bicep.RegistryAuthError,
+) (bool, error) {
+	var commandRunner exec.CommandRunner
+	if err := p.serviceLocator.Resolve(&commandRunner); err != nil {
+		return false, fmt.Errorf("resolving command runner: %w", err)
+	}
+
+	options := []string{}
+	if commandRunner.ToolInPath("az") == nil {
+		options = append(options, registryAuthOptionAzCli)
+	}
+	if commandRunner.ToolInPath("pwsh") == nil {
+		options = append(options, registryAuthOptionPowerShell)
+	}
+	options = append(options, registryAuthOptionRetry, registryAuthOptionCancel)
+
+	p.console.MessageUxItem(ctx, &ux.WarningMessage{
+		Description: fmt.Sprintf(
+			"Bicep isn't authorized to restore modules from %s.", strings.Join(authErr.Registries, ", ")),
+	})
+
+	choice, err := p.console.Select(ctx, input.ConsoleOptions{
+		Message: "How do you want to sign in to the registry?",
+		Help: "Bicep restores modules with its own credentials, the Azure CLI or Azure PowerShell sign-in by default, " +
+			"rather than the azd sign-in. The account needs the AcrPull role on the registry.",
+		Options:      options,
+		DefaultValue: options[0],
+	})
+	if err != nil {
+		return false, err
+	}
+
+	var runArgs exec.RunArgs
+	switch options[choice] {
+	case registryAuthOptionAzCli:
+		runArgs = exec.NewRunArgs("az", "login")
+	case registryAuthOptionPowerShell:
+		runArgs = exec.NewRunArgs("pwsh", "-NoProfile", "-Command", "Connect-AzAccount")
+	case registryAuthOptionRetry:
+		return true, nil
+	default:
+		return false, nil
+	}
+
+	if _, err := commandRunner.Run(ctx, runArgs.WithInteractive(true)); err != nil {
+		return false, fmt.Errorf("signing in: %w", err)
+	}
+
+	p.console.Message(ctx, output.WithGrayFormat("Restoring modules again..."))
+	return true, nil
+}
+
+// registryAuthErrorWithSuggestion explains how to give bicep access to the registries in authErr.
+func registryAuthErrorWithSuggestion(authErr *bicep.RegistryAuthError, err error) error {
+	return &errorhandler.ErrorWithSuggestion{
+		Err: err,
+		Message: fmt.Sprintf(
+			"Bicep isn't authorized to restore modules from %s.", strings.Join(authErr.Registries, ", ")),
+		Suggestion: "Bicep restores modules with the Azure CLI or Azure PowerShell sign-in rather than the azd " +
+			"sign-in. Run 'az login' with an account that has the AcrPull role on the registry, or configure " +
+			"'credentialPrecedence' in bicepconfig.json, then run the command again.",
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/errorhandler"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/bicep"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildWithRegistryAuth(t *testing.T) {
+	authErr := fmt.Errorf("failed running bicep build: %w", &bicep.RegistryAuthError{
+		Registries: []string{"contoso.azurecr.io"},
+		Err:        errors.New("exit code: 1"),
+	})
+
+	tests := []struct {
+		name     string
+		noPrompt bool
+		choice   string
+		// failures is the number of builds that fail with authErr before one succeeds.
+		failures   int
+		builds     int
+		signIn     []string
+		suggestion bool
+	}{
+		{name: "AzCli", choice: registryAuthOptionAzCli, failures: 1, builds: 2, signIn: []string{"az", "login"}},
+		{
+			name:     "PowerShell",
+			choice:   registryAuthOptionPowerShell,
+			failures: 1,
+			builds:   2,
+			signIn:   []string{"pwsh", "-NoProfile", "-Command", "Connect-AzAccount"},
+		},
+		{name: "Retry", choice: registryAuthOptionRetry, failures: 2, builds: 3},
+		{name: "Cancel", choice: registryAuthOptionCancel, failures: 1, builds: 1, suggestion: true},
+		{name: "NoPrompt", noPrompt: true, failures: 1, builds: 1, suggestion: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			mockContext.Console.SetNoPromptMode(tt.noPrompt)
+			mockContext.CommandRunner.MockToolInPath("az", nil)
+			mockContext.CommandRunner.MockToolInPath("pwsh", nil)
+			mockContext.Console.WhenSelect(func(options input.ConsoleOptions) bool { return true }).
+				RespondFn(func(options input.ConsoleOptions) (any, error) {
+					for i, option := range options.Options {
+						if option == tt.choice {
+							return i, nil
+						}
+					}
+					return 0, fmt.Errorf("option %q not offered", tt.choice)
+				})
+
+			var signIn []string
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool { return true }).
+				RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+					require.True(t, args.Interactive)
+					signIn = append([]string{args.Cmd}, args.Args...)
+					return exec.RunResult{}, nil
+				})
+
+			provider := &BicepProvider{console: mockContext.Console, serviceLocator: mockContext.Container}
+
+			builds := 0
+			res, err := provider.buildWithRegistryAuth(t.Context(), func() (bicep.BuildResult, error) {
+				builds++
+				if builds <= tt.failures {
+					return bicep.BuildResult{}, authErr
+				}
+				return bicep.BuildResult{Compiled: "{}"}, nil
+			})
+
+			require.Equal(t, tt.builds, builds)
+			require.Equal(t, tt.signIn, signIn)
+
+			if tt.suggestion {
+				var suggestionErr *errorhandler.ErrorWithSuggestion
+				require.ErrorAs(t, err, &suggestionErr)
+				require.Contains(t, suggestionErr.Message, "contoso.azurecr.io")
+				require.ErrorIs(t, err, authErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, "{}", res.Compiled)
+		})
+	}
+
+	t.Run("OtherError", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		provider := &BicepProvider{console: mockContext.Console, serviceLocator: mockContext.Container}
+
+		buildErr := errors.New("BCP007")
+		_, err := provider.buildWithRegistryAuth(t.Context(), func() (bicep.BuildResult, error) {
+			return bicep.BuildResult{}, buildErr
+		})
+		require.Equal(t, buildErr, err)
+	})
+}
@@ -0,0 +1,362 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/cli/browser"
+)
+
+// defaultStuckResourceThreshold is how long a resource may stay in a non-terminal provisioning state before azd
+// considers it stuck, when no resource-type-specific threshold is known.
+var defaultStuckResourceThreshold = 30 * time.Minute
+
+// stuckResourceThresholds holds thresholds for resource types that routinely take longer than the default to
+// provision. Keys are lower-cased resource types.
+var stuckResourceThresholds = map[string]time.Duration{
+	"microsoft.apimanagement/service":                       90 * time.Minute,
+	"microsoft.network/virtualnetworkgateways":              75 * time.Minute,
+	"microsoft.network/azurefirewalls":                      45 * time.Minute,
+	"microsoft.network/applicationgateways":                 45 * time.Minute,
+	"microsoft.cache/redis":                                 60 * time.Minute,
+	"microsoft.cache/redisenterprise":                       60 * time.Minute,
+	"microsoft.containerservice/managedclusters":            45 * time.Minute,
+	"microsoft.dbforpostgresql/flexibleservers":             45 * time.Minute,
+	"microsoft.dbformysql/flexibleservers":                  45 * time.Minute,
+	"microsoft.sql/managedinstances":                        6 * time.Hour,
+	"microsoft.documentdb/databaseaccounts":                 40 * time.Minute,
+	"microsoft.app/managedenvironments":                     20 * time.Minute,
+	"microsoft.web/sites":                                   15 * time.Minute,
+	"microsoft.web/serverfarms":                             15 * time.Minute,
+	"microsoft.keyvault/vaults":                             10 * time.Minute,
+	"microsoft.storage/storageaccounts":                     10 * time.Minute,
+	"microsoft.operationalinsights/workspaces":              10 * time.Minute,
+	"microsoft.cognitiveservices/accounts":                  20 * time.Minute,
+	"microsoft.cognitiveservices/accounts/deployments":      20 * time.Minute,
+	"microsoft.machinelearningservices/workspaces":          30 * time.Minute,
+	"microsoft.containerregistry/registries":                10 * time.Minute,
+	"microsoft.signalrservice/signalr":                      20 * time.Minute,
+	"microsoft.servicebus/namespaces":                       20 * time.Minute,
+	"microsoft.eventhub/namespaces":                         20 * time.Minute,
+	"microsoft.search/searchservices":                       20 * time.Minute,
+	"microsoft.network/privateendpoints":                    15 * time.Minute,
+	"microsoft.network/frontdoors":                          45 * time.Minute,
+	"microsoft.cdn/profiles":                                45 * time.Minute,
+	"microsoft.insights/components":                         10 * time.Minute,
+	"microsoft.managedidentity/userassignedidentities":      5 * time.Minute,
+	"microsoft.authorization/roleassignments":               10 * time.Minute,
+	"microsoft.network/virtualnetworks":                     10 * time.Minute,
+	"microsoft.network/publicipaddresses":                   10 * time.Minute,
+	"microsoft.network/networksecuritygroups":               10 * time.Minute,
+	"microsoft.network/privatednszones":                     10 * time.Minute,
+	"microsoft.network/privatednszones/virtualnetworklinks": 15 * time.Minute,
+}
+
+// User-facing labels for the stuck-deployment prompt.
+const (
+	stuckOptionKeepWaiting = "Keep waiting"
+	stuckOptionOpenPortal  = "Open the deployment in the Azure Portal and keep waiting"
+	stuckOptionCancel      = "Cancel the Azure deployment"
+)
+
+// openBrowser opens a URL in the default browser. It is a var so tests can stub it.
+var openBrowser = browser.OpenURL
+
+// stuckResourceThreshold returns how long a resource of the given type may stay in a non-terminal state before it
+// is reported as stuck.
+func stuckResourceThreshold(resourceType string) time.Duration {
+	if threshold, has := stuckResourceThresholds[strings.ToLower(resourceType)]; has {
+		return threshold
+	}
+
+	return defaultStuckResourceThreshold
+}
+
+// stuckResource describes a resource that has been in a non-terminal provisioning state for longer than its
+// threshold.
+type stuckResource struct {
+	id           string
+	name         string
+	resourceType string
+	state        string
+	elapsed      time.Duration
+}
+
+// deploymentWatchdog tracks the age of an in-flight deployment and of each resource operation observed in a
+// non-terminal state, so azd can ask the user what to do instead of waiting indefinitely.
+//
+// The watchdog is not safe for concurrent use; it is owned by the progress reporting goroutine.
+type deploymentWatchdog struct {
+	// timeout is the overall deployment timeout. Zero disables the timeout check.
+	timeout time.Duration
+	// deadline is when the deployment is next considered timed out.
+	deadline time.Time
+	// since records when the operation of each resource started, or when it was first observed in a non-terminal state
+	// when the operation has no timestamp, or when the user last chose to keep waiting for it, keyed by resource ID.
+	since map[string]time.Time
+}
+
+func newDeploymentWatchdog(start time.Time, timeout time.Duration) *deploymentWatchdog {
+	return &deploymentWatchdog{
+		timeout:  timeout,
+		deadline: start.Add(timeout),
+		since:    map[string]time.Time{},
+	}
+}
+
+// observe records the currently running operations and returns the resources that exceeded their stuck threshold,
+// along with whether the overall deployment timeout elapsed.
+func (w *deploymentWatchdog) observe(
+	running []*armresources.DeploymentOperation,
+	now time.Time,
+) (stuck []stuckResource, timedOut bool) {
+	seen := map[string]struct{}{}
+	for _, op := range running {
+		if op == nil || op.Properties == nil || op.Properties.TargetResource == nil ||
+			op.Properties.TargetResource.ID == nil || op.Properties.TargetResource.ResourceType == nil {
+			continue
+		}
+
+		id := *op.Properties.TargetResource.ID
+		seen[id] = struct{}{}
+		since, has := w.since[id]
+		if !has {
+			// The operation may have started well before azd first saw it running, so its own timestamp starts the
+			// clock when it has one.
+			since = now
+			if timestamp := op.Properties.Timestamp; timestamp != nil && timestamp.Before(now) {
+				since = *timestamp
+			}
+			w.since[id] = since
+		}
+
+		resourceType := *op.Properties.TargetResource.ResourceType
+		elapsed := now.Sub(since)
+		if elapsed < stuckResourceThreshold(resourceType) {
+			continue
+		}
+
+		name := id
+		if op.Properties.TargetResource.ResourceName != nil {
+			name = *op.Properties.TargetResource.ResourceName
+		}
+		state := ""
+		if op.Properties.ProvisioningState != nil {
+			state = *op.Properties.ProvisioningState
+		}
+
+		stuck = append(stuck, stuckResource{
+			id:           id,
+			name:         name,
+			resourceType: resourceType,
+			state:        state,
+			elapsed:      elapsed,
+		})
+	}
+
+	// Forget resources that are no longer running so a later retry of the same resource starts a fresh clock.
+	for id := range w.since {
+		if _, has := seen[id]; !has {
+			delete(w.since, id)
+		}
+	}
+
+	slices.SortFunc(stuck, func(a, b stuckResource) int {
+		return cmp.Compare(b.elapsed, a.elapsed)
+	})
+
+	return stuck, w.timeout > 0 && !now.Before(w.deadline)
+}
+
+// extend restarts the clock for the given stuck resources and, when the deployment timed out, pushes the deadline
+// out by another timeout period. It is called when the user chooses to keep waiting.
+func (w *deploymentWatchdog) extend(stuck []stuckResource, timedOut bool, now time.Time) {
+	for _, r := range stuck {
+		w.since[r.id] = now
+	}
+
+	if timedOut {
+		w.deadline = now.Add(w.timeout)
+	}
+}
+
+// deploymentWatchdogInterval is how often the watchdog checks the deployment, independently of progress reporting.
+var deploymentWatchdogInterval = 10 * time.Second
+
+// runDeploymentWatchdog checks the deployment every deploymentWatchdogInterval until progressCtx is done, so that the
+// deployment timeout applies even when progress isn't reported. running returns the operations observed by the last
+// successful progress report, and lock is held while the deployment is checked, so a prompt isn't interleaved with the
+// progress display.
+func (p *BicepProvider) runDeploymentWatchdog(
+	ctx context.Context,
+	progressCtx context.Context,
+	watchdog *deploymentWatchdog,
+	deployment infra.Deployment,
+	lock sync.Locker,
+	running func() []*armresources.DeploymentOperation,
+	abort func(run func() interruptOutcome) bool,
+) {
+	ticker := time.NewTicker(deploymentWatchdogInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-progressCtx.Done():
+			return
+		case <-ticker.C:
+			lock.Lock()
+			canceling := p.checkDeploymentWatchdog(ctx, progressCtx, watchdog, deployment, running(), abort)
+			lock.Unlock()
+			if canceling {
+				return
+			}
+		}
+	}
+}
+
+// checkDeploymentWatchdog feeds the running operations into the watchdog and, when a resource is stuck or the
+// deployment timed out, asks the user how to proceed. The prompt is abandoned when progressCtx is done, which is when
+// the deployment finished. It returns true when the deployment is being canceled.
+func (p *BicepProvider) checkDeploymentWatchdog(
+	ctx context.Context,
+	progressCtx context.Context,
+	watchdog *deploymentWatchdog,
+	deployment infra.Deployment,
+	running []*armresources.DeploymentOperation,
+	abort func(run func() interruptOutcome) bool,
+) bool {
+	now := time.Now()
+	stuck, timedOut := watchdog.observe(running, now)
+	if len(stuck) == 0 && !timedOut {
+		return false
+	}
+
+	for _, r := range stuck {
+		log.Printf("deployment watchdog: %s (%s) has been %s for %s",
+			r.name, r.resourceType, r.state, r.elapsed.Truncate(time.Second))
+	}
+
+	if p.console.IsNoPromptMode() {
+		if !timedOut {
+			// Without a user to ask, a stuck resource is only worth a warning; the configured timeout (if any) is
+			// what decides when to give up.
+			p.console.StopSpinner(ctx, "", input.Step)
+			p.printStuckResources(ctx, stuck)
+			p.console.ShowSpinner(ctx, "Creating/Updating resources", input.Step)
+			watchdog.extend(stuck, false, now)
+			return false
+		}
+
+		return abort(func() interruptOutcome {
+			p.console.Message(ctx, output.WithWarningFormat(
+				"The deployment exceeded the configured timeout of %s and will be canceled.", watchdog.timeout))
+			return timedOutOutcome(p.cancelAndAwaitTerminal(ctx, deployment, p.deploymentUrl(ctx, deployment)))
+		})
+	}
+
+	p.console.StopSpinner(ctx, "", input.Step)
+	if timedOut {
+		p.console.Message(ctx, output.WithWarningFormat(
+			"The deployment has been running longer than the configured timeout of %s.", watchdog.timeout))
+	}
+	p.printStuckResources(ctx, stuck)
+
+	for {
+		choice, err := p.console.Select(progressCtx, input.ConsoleOptions{
+			Message: "The Azure deployment is taking longer than expected. What do you want to do?",
+			Options: []string{
+				stuckOptionKeepWaiting,
+				stuckOptionOpenPortal,
+				stuckOptionCancel,
+			},
+			DefaultValue: stuckOptionKeepWaiting,
+		})
+		if err != nil && progressCtx.Err() != nil {
+			// The deployment finished while the prompt was shown.
+			return false
+		} else if err != nil {
+			log.Printf("deployment watchdog: failed to show prompt, continuing to wait: %v", err)
+			choice = 0
+		}
+
+		switch choice {
+		case 1: // open portal
+			portalUrl := p.deploymentUrl(ctx, deployment)
+			if portalUrl == "" {
+				p.console.Message(ctx, output.WithWarningFormat(
+					"Unable to resolve the deployment URL. Find it in the Azure Portal under "+
+						"Subscription → Deployments (look for %q).", deployment.Name()))
+				continue
+			}
+
+			p.console.Message(ctx, fmt.Sprintf("Opening %s", output.WithLinkFormat(portalUrl)))
+			if err := openBrowser(portalUrl); err != nil {
+				log.Printf("deployment watchdog: failed to open browser: %v", err)
+			}
+			fallthrough
+		case 0: // keep waiting
+			watchdog.extend(stuck, timedOut, time.Now())
+			p.console.ShowSpinner(ctx, "Creating/Updating resources", input.Step)
+			return false
+		case 2: // cancel
+			return abort(func() interruptOutcome {
+				return p.cancelAndAwaitTerminal(ctx, deployment, p.deploymentUrl(ctx, deployment))
+			})
+		}
+	}
+}
+
+// printStuckResources lists the resources that have exceeded their stuck threshold.
+func (p *BicepProvider) printStuckResources(ctx context.Context, stuck []stuckResource) {
+	if len(stuck) == 0 {
+		return
+	}
+
+	p.console.Message(ctx, output.WithWarningFormat(
+		"The following resources have not finished provisioning within the expected time:"))
+	for _, r := range stuck {
+		typeName := azapi.GetResourceTypeDisplayName(azapi.AzureResourceType(r.resourceType))
+		if typeName == "" {
+			typeName = r.resourceType
+		}
+
+		p.console.Message(ctx, fmt.Sprintf("  - %s: %s (%s for %s)",
+			typeName, r.name, r.state, r.elapsed.Truncate(time.Second)))
+	}
+}
+
+// deploymentUrl resolves the portal URL of the deployment, returning an empty string on failure.
+func (p *BicepProvider) deploymentUrl(ctx context.Context, deployment infra.Deployment) string {
+	urlCtx, urlDone := context.WithTimeout(context.WithoutCancel(ctx), cancelRequestTimeout)
+	defer urlDone()
+
+	portalUrl, err := deployment.DeploymentUrl(urlCtx)
+	if err != nil {
+		log.Printf("deployment watchdog: failed to fetch deployment URL: %v", err)
+		return ""
+	}
+
+	return portalUrl
+}
+
+// timedOutOutcome tags an interrupt outcome produced by an automatic timeout cancellation with
+// provisioning.ErrDeploymentTimedOut, preserving the original outcome in the error chain.
+func timedOutOutcome(outcome interruptOutcome) interruptOutcome {
+	outcome.err = fmt.Errorf("%w: %w", provisioning.ErrDeploymentTimedOut, outcome.err)
+	return outcome
+}
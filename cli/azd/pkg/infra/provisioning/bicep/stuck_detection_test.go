@@ -0,0 +1,254 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func runningOp(id, resourceType string) *armresources.DeploymentOperation {
+	return &armresources.DeploymentOperation{
+		Properties: &armresources.DeploymentOperationProperties{
+			ProvisioningState: new("Running"),
+			TargetResource: &armresources.TargetResource{
+				ID:           new(id),
+				ResourceName: new(id),
+				ResourceType: new(resourceType),
+			},
+		},
+	}
+}
+
+func TestStuckResourceThreshold(t *testing.T) {
+	require.Equal(t, 90*time.Minute, stuckResourceThreshold("Microsoft.ApiManagement/service"))
+	require.Equal(t, 15*time.Minute, stuckResourceThreshold("microsoft.web/SITES"))
+	require.Equal(t, defaultStuckResourceThreshold, stuckResourceThreshold("Contoso.Widgets/widgets"))
+}
+
+func TestDeploymentWatchdog_Observe(t *testing.T) {
+	start := time.Now()
+
+	t.Run("resource exceeds its threshold", func(t *testing.T) {
+		w := newDeploymentWatchdog(start, 0)
+		ops := []*armresources.DeploymentOperation{
+			runningOp("site", "Microsoft.Web/sites"),
+			runningOp("apim", "Microsoft.ApiManagement/service"),
+		}
+
+		stuck, timedOut := w.observe(ops, start)
+		require.Empty(t, stuck)
+		require.False(t, timedOut)
+
+		stuck, timedOut = w.observe(ops, start.Add(20*time.Minute))
+		require.False(t, timedOut)
+		require.Len(t, stuck, 1)
+		require.Equal(t, "site", stuck[0].name)
+		require.Equal(t, 20*time.Minute, stuck[0].elapsed)
+	})
+
+	t.Run("operation timestamp starts the clock", func(t *testing.T) {
+		w := newDeploymentWatchdog(start, 0)
+		op := runningOp("site", "Microsoft.Web/sites")
+		op.Properties.Timestamp = new(start.Add(-20 * time.Minute))
+
+		stuck, _ := w.observe([]*armresources.DeploymentOperation{op}, start)
+		require.Len(t, stuck, 1)
+		require.Equal(t, 20*time.Minute, stuck[0].elapsed)
+
+		w.extend(stuck, false, start)
+		stuck, _ = w.observe([]*armresources.DeploymentOperation{op}, start.Add(time.Minute))
+		require.Empty(t, stuck)
+	})
+
+	t.Run("finished resources are forgotten", func(t *testing.T) {
+		w := newDeploymentWatchdog(start, 0)
+		op := runningOp("site", "Microsoft.Web/sites")
+
+		w.observe([]*armresources.DeploymentOperation{op}, start)
+		w.observe(nil, start.Add(10*time.Minute))
+		stuck, _ := w.observe([]*armresources.DeploymentOperation{op}, start.Add(20*time.Minute))
+		require.Empty(t, stuck)
+	})
+
+	t.Run("extend restarts the clocks", func(t *testing.T) {
+		w := newDeploymentWatchdog(start, time.Hour)
+		ops := []*armresources.DeploymentOperation{runningOp("site", "Microsoft.Web/sites")}
+
+		w.observe(ops, start)
+		now := start.Add(time.Hour)
+		stuck, timedOut := w.observe(ops, now)
+		require.Len(t, stuck, 1)
+		require.True(t, timedOut)
+
+		w.extend(stuck, timedOut, now)
+		stuck, timedOut = w.observe(ops, now.Add(time.Minute))
+		require.Empty(t, stuck)
+		require.False(t, timedOut)
+
+		_, timedOut = w.observe(ops, now.Add(time.Hour))
+		require.True(t, timedOut)
+	})
+}
+
+func TestCheckDeploymentWatchdog(t *testing.T) {
+	start := time.Now().Add(-time.Hour)
+	ops := []*armresources.DeploymentOperation{runningOp("site", "Microsoft.Web/sites")}
+
+	newStuckWatchdog := func(timeout time.Duration) *deploymentWatchdog {
+		w := newDeploymentWatchdog(start, timeout)
+		w.observe(ops, start)
+		return w
+	}
+
+	t.Run("keep waiting", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.Console.WhenSelect(func(o input.ConsoleOptions) bool { return true }).
+			RespondFn(func(o input.ConsoleOptions) (any, error) {
+				require.Equal(t, stuckOptionKeepWaiting, o.Options[0])
+				return 0, nil
+			})
+
+		provider := newTestProvider(mockContext)
+		aborted := false
+		canceling := provider.checkDeploymentWatchdog(
+			t.Context(), t.Context(), newStuckWatchdog(0), &fakeDeployment{}, ops,
+			func(run func() interruptOutcome) bool {
+				aborted = true
+				return true
+			})
+
+		require.False(t, canceling)
+		require.False(t, aborted)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		withFastInterruptPolling(t)
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.Console.WhenSelect(func(o input.ConsoleOptions) bool { return true }).
+			Respond(2)
+
+		provider := newTestProvider(mockContext)
+		deployment := &fakeDeployment{
+			getFn: func(ctx context.Context, n int32) (*azapi.ResourceDeployment, error) {
+				return &azapi.ResourceDeployment{ProvisioningState: azapi.DeploymentProvisioningStateCanceled}, nil
+			},
+		}
+
+		var outcome interruptOutcome
+		canceling := provider.checkDeploymentWatchdog(
+			t.Context(), t.Context(), newStuckWatchdog(0), deployment, ops,
+			func(run func() interruptOutcome) bool {
+				outcome = run()
+				return true
+			})
+
+		require.True(t, canceling)
+		require.Equal(t, int32(1), deployment.cancelCalls.Load())
+		require.ErrorIs(t, outcome.err, provisioning.ErrDeploymentCanceledByUser)
+	})
+
+	t.Run("timeout without prompting cancels", func(t *testing.T) {
+		withFastInterruptPolling(t)
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.Console.SetNoPromptMode(true)
+
+		provider := newTestProvider(mockContext)
+		deployment := &fakeDeployment{
+			getFn: func(ctx context.Context, n int32) (*azapi.ResourceDeployment, error) {
+				return &azapi.ResourceDeployment{ProvisioningState: azapi.DeploymentProvisioningStateCanceled}, nil
+			},
+		}
+
+		var outcome interruptOutcome
+		canceling := provider.checkDeploymentWatchdog(
+			t.Context(), t.Context(), newStuckWatchdog(30*time.Minute), deployment, ops,
+			func(run func() interruptOutcome) bool {
+				outcome = run()
+				return true
+			})
+
+		require.True(t, canceling)
+		require.ErrorIs(t, outcome.err, provisioning.ErrDeploymentTimedOut)
+		require.ErrorIs(t, outcome.err, provisioning.ErrDeploymentCanceledByUser)
+	})
+
+	t.Run("stuck resource without prompting only warns", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.Console.SetNoPromptMode(true)
+
+		provider := newTestProvider(mockContext)
+		canceling := provider.checkDeploymentWatchdog(
+			t.Context(), t.Context(), newStuckWatchdog(0), &fakeDeployment{}, ops,
+			func(run func() interruptOutcome) bool {
+				t.Fatal("abort must not be called")
+				return false
+			})
+
+		require.False(t, canceling)
+		require.Contains(t, mockContext.Console.Output(), "  - Web App: site (Running for 1h0m0s)")
+	})
+	t.Run("finished deployment abandons the prompt", func(t *testing.T) {
+		progressCtx, cancelProgress := context.WithCancel(t.Context())
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.Console.WhenSelect(func(o input.ConsoleOptions) bool { return true }).
+			RespondFn(func(o input.ConsoleOptions) (any, error) {
+				cancelProgress()
+				return 0, context.Canceled
+			})
+
+		provider := newTestProvider(mockContext)
+		watchdog := newStuckWatchdog(0)
+		canceling := provider.checkDeploymentWatchdog(
+			t.Context(), progressCtx, watchdog, &fakeDeployment{}, ops,
+			func(run func() interruptOutcome) bool {
+				t.Fatal("abort must not be called")
+				return false
+			})
+
+		require.False(t, canceling)
+		// The clock of the resource isn't restarted, since no choice was made.
+		require.Equal(t, start, watchdog.since["site"])
+	})
+}
+
+func TestRunDeploymentWatchdog(t *testing.T) {
+	withFastInterruptPolling(t)
+	original := deploymentWatchdogInterval
+	deploymentWatchdogInterval = time.Millisecond
+	t.Cleanup(func() { deploymentWatchdogInterval = original })
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.Console.SetNoPromptMode(true)
+
+	provider := newTestProvider(mockContext)
+	deployment := &fakeDeployment{
+		getFn: func(ctx context.Context, n int32) (*azapi.ResourceDeployment, error) {
+			return &azapi.ResourceDeployment{ProvisioningState: azapi.DeploymentProvisioningStateCanceled}, nil
+		},
+	}
+
+	// No progress is reported, yet the deployment times out.
+	var outcome interruptOutcome
+	var lock sync.Mutex
+	provider.runDeploymentWatchdog(
+		t.Context(), t.Context(), newDeploymentWatchdog(time.Now().Add(-time.Hour), 30*time.Minute), deployment, &lock,
+		func() []*armresources.DeploymentOperation { return nil },
+		func(run func() interruptOutcome) bool {
+			outcome = run()
+			return true
+		})
+
+	require.ErrorIs(t, outcome.err, provisioning.ErrDeploymentTimedOut)
+	require.Equal(t, int32(1), deployment.cancelCalls.Load())
+}
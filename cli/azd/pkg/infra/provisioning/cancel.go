@@ -40,4 +40,27 @@ var (
 	// errors.As.
 	ErrDeploymentCancelFailed = errors.New(
 		"deployment cancel request failed")
+
+	// ErrDeploymentTimedOut is returned when the deployment exceeded the
+	// configured infra deploymentTimeout and azd canceled it without
+	// prompting (for example, when running with --no-prompt). It is
+	// combined with the cancellation outcome so callers can inspect both.
+	ErrDeploymentTimedOut = errors.New(
+		"deployment exceeded the configured timeout")
+
+	// ErrNoActiveDeployment is returned by Canceler implementations when the
+	// environment has no deployment in a non-terminal state.
+	ErrNoActiveDeployment = errors.New(
+		"no in-progress deployment was found for the environment")
+
+	// ErrCancelNotSupportedByProvider is returned when the provisioning
+	// provider does not implement Canceler.
+	ErrCancelNotSupportedByProvider = errors.New(
+		"the provisioning provider does not support canceling deployments")
+
+	// ErrKeepNotSupportedByProvider is returned when resources to keep or
+	// protected resources are specified for a provisioning provider that does
+	// not implement ResourceKeeper.
+	ErrKeepNotSupportedByProvider = errors.New(
+		"the provisioning provider does not support keeping resources")
 )
@@ -9,6 +9,7 @@ import (
 	"maps"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
@@ -58,6 +59,9 @@ type InputParameter struct {
 type OutputParameter struct {
 	Type  ParameterType
 	Value any
+	// Secure is true when the provider marked the output as sensitive. Secure outputs are redacted when environment
+	// values are displayed.
+	Secure bool
 }
 
 // OutputParametersFromArmOutputs converts the outputs from an ARM deployment to a map of provisioning.OutputParameter.
@@ -113,6 +117,8 @@ type State struct {
 	Outputs map[string]OutputParameter
 	// The resources that make up the application.
 	Resources []Resource
+	// The time of the most recent deployment. It's zero when the provider doesn't report it.
+	DeployedAt time.Time
 }
 
 // MergeInto merges other on top of s, i.e. if a key exists in both s and other, the value from other will be used.
@@ -123,6 +129,10 @@ func (s *State) MergeInto(other State) {
 
 	maps.Copy(s.Outputs, other.Outputs)
 
+	if other.DeployedAt.After(s.DeployedAt) {
+		s.DeployedAt = other.DeployedAt
+	}
+
 	for _, res := range other.Resources {
 		if i := slices.IndexFunc(s.Resources, func(r Resource) bool { return r.Id == res.Id }); i != -1 {
 			s.Resources[i] = res
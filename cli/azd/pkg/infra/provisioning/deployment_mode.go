@@ -0,0 +1,50 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+)
+
+// DeploymentMode selects how azd submits ARM deployments.
+type DeploymentMode string
+
+const (
+	// DeploymentModeStandard uses plain ARM deployments. Resources removed from the template are left in place.
+	DeploymentModeStandard DeploymentMode = "standard"
+	// DeploymentModeStacks deploys through Azure Deployment Stacks. Resources removed from the template are handled
+	// according to the stack's actionOnUnmanage setting on the next provision, and `azd down` deletes the stack
+	// together with its managed resources.
+	DeploymentModeStacks DeploymentMode = "stacks"
+)
+
+func (m DeploymentMode) validate() error {
+	switch m {
+	case "", DeploymentModeStandard, DeploymentModeStacks:
+		return nil
+	default:
+		return fmt.Errorf(
+			"'deploymentMode' must be '%s' or '%s', got '%s'", DeploymentModeStandard, DeploymentModeStacks, m)
+	}
+}
+
+// DeploymentType returns the deployment service type used for the given infra options. An explicit deploymentMode in
+// azure.yaml wins; otherwise deployment stacks are used when the deployment.stacks alpha feature is enabled.
+func DeploymentType(options Options, featureManager *alpha.FeatureManager) azapi.DeploymentType {
+	switch options.DeploymentMode {
+	case DeploymentModeStacks:
+		return azapi.DeploymentTypeStacks
+	case DeploymentModeStandard:
+		return azapi.DeploymentTypeStandard
+	}
+
+	if featureManager != nil && featureManager.IsEnabled(azapi.FeatureDeploymentStacks) {
+		return azapi.DeploymentTypeStacks
+	}
+
+	return azapi.DeploymentTypeStandard
+}
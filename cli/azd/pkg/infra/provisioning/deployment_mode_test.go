@@ -0,0 +1,43 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeploymentType(t *testing.T) {
+	stacksEnabled := alpha.NewFeaturesManagerWithConfig(config.NewConfig(map[string]any{
+		"alpha": map[string]any{
+			"all": "on",
+		},
+	}))
+	stacksDisabled := alpha.NewFeaturesManagerWithConfig(config.NewEmptyConfig())
+
+	tests := []struct {
+		name           string
+		mode           DeploymentMode
+		featureManager *alpha.FeatureManager
+		expected       azapi.DeploymentType
+	}{
+		{name: "Default", featureManager: stacksDisabled, expected: azapi.DeploymentTypeStandard},
+		{name: "AlphaFeature", featureManager: stacksEnabled, expected: azapi.DeploymentTypeStacks},
+		{name: "ProjectStacks", mode: DeploymentModeStacks, featureManager: stacksDisabled,
+			expected: azapi.DeploymentTypeStacks},
+		{name: "ProjectStandardOverridesAlpha", mode: DeploymentModeStandard, featureManager: stacksEnabled,
+			expected: azapi.DeploymentTypeStandard},
+		{name: "NoFeatureManager", mode: DeploymentModeStacks, expected: azapi.DeploymentTypeStacks},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, DeploymentType(Options{DeploymentMode: tt.mode}, tt.featureManager))
+		})
+	}
+}
@@ -12,7 +12,10 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/internal/tracing"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
@@ -277,6 +280,17 @@ func bindMountOperation(
 
 // Preview generates the list of changes to be applied as part of the provisioning.
 func (m *Manager) Preview(ctx context.Context) (*DeployPreviewResult, error) {
+	return m.preview(ctx, false)
+}
+
+// Diff previews the changes that provisioning would make, to detect drift between the templates and the deployed
+// resources. Unlike Preview, changes to resource types that don't have a display name are kept, so drift in any
+// resource is reported.
+func (m *Manager) Diff(ctx context.Context) (*DeployPreviewResult, error) {
+	return m.preview(ctx, true)
+}
+
+func (m *Manager) preview(ctx context.Context, allResourceTypes bool) (*DeployPreviewResult, error) {
 	// Apply the infrastructure deployment
 	deployResult, err := m.provider.Preview(ctx)
 
@@ -293,12 +307,21 @@ func (m *Manager) Preview(ctx context.Context) (*DeployPreviewResult, error) {
 	}
 
 	for index, result := range deployResult.Preview.Properties.Changes {
+		// Types that aren't ARM resource types, such as terraform resource types, are kept as is.
+		if !strings.Contains(result.ResourceType, "/") {
+			filteredResult.Preview.Properties.Changes = append(
+				filteredResult.Preview.Properties.Changes, deployResult.Preview.Properties.Changes[index])
+			continue
+		}
+
 		mappingName := azapi.GetResourceTypeDisplayName(azapi.AzureResourceType(result.ResourceType))
-		if mappingName == "" {
+		if mappingName == "" && !allResourceTypes {
 			// ignore
 			continue
 		}
-		deployResult.Preview.Properties.Changes[index].ResourceType = mappingName
+		if mappingName != "" {
+			deployResult.Preview.Properties.Changes[index].ResourceType = mappingName
+		}
 		filteredResult.Preview.Properties.Changes = append(
 			filteredResult.Preview.Properties.Changes, deployResult.Preview.Properties.Changes[index])
 	}
@@ -309,8 +332,30 @@ func (m *Manager) Preview(ctx context.Context) (*DeployPreviewResult, error) {
 	return &filteredResult, nil
 }
 
+// Cancel cancels the in-flight deployment for the current environment, when the provider supports it.
+func (m *Manager) Cancel(ctx context.Context) (*CancelResult, error) {
+	canceler, ok := m.provider.(Canceler)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrCancelNotSupportedByProvider, m.provider.Name())
+	}
+
+	return canceler.Cancel(ctx)
+}
+
 // Destroys the Azure infrastructure for the specified project
 func (m *Manager) Destroy(ctx context.Context, options DestroyOptions) (*DestroyResult, error) {
+	for _, protected := range options.Protect() {
+		if err := protected.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(options.Keep()) > 0 || len(options.Protect()) > 0 {
+		if keeper, ok := m.provider.(ResourceKeeper); !ok || !keeper.CanKeepResources() {
+			return nil, fmt.Errorf("%w: %s", ErrKeepNotSupportedByProvider, m.provider.Name())
+		}
+	}
+
 	destroyResult, err := m.provider.Destroy(ctx, options)
 	if err != nil {
 		return nil, fmt.Errorf("error deleting Azure resources: %w", err)
@@ -320,6 +365,9 @@ func (m *Manager) Destroy(ctx context.Context, options DestroyOptions) (*Destroy
 	// invalidated them all.
 	for _, key := range destroyResult.InvalidatedEnvKeys {
 		m.env.DotenvDelete(key)
+		if err := m.env.ClearOutputMetadata(key); err != nil {
+			return nil, fmt.Errorf("clearing output metadata for '%s': %w", key, err)
+		}
 	}
 
 	// Update environment files to remove invalid infrastructure parameters
@@ -335,18 +383,50 @@ func UpdateEnvironment(
 	outputs map[string]OutputParameter,
 	env *environment.Environment,
 	envManager environment.Manager,
+) error {
+	return updateEnvironment(ctx, outputs, time.Time{}, env, envManager)
+}
+
+// UpdateEnvironmentFromState is like [UpdateEnvironment] for the outputs of the state, and records the time of the
+// deployment the outputs come from, so later deployments can be detected by [FindStaleOutputs].
+func UpdateEnvironmentFromState(
+	ctx context.Context,
+	state *State,
+	env *environment.Environment,
+	envManager environment.Manager,
+) error {
+	return updateEnvironment(ctx, state.Outputs, state.DeployedAt, env, envManager)
+}
+
+func updateEnvironment(
+	ctx context.Context,
+	outputs map[string]OutputParameter,
+	deployedAt time.Time,
+	env *environment.Environment,
+	envManager environment.Manager,
 ) error {
 	if len(outputs) > 0 {
+		syncedAt := time.Now()
+
 		for key, param := range outputs {
-			// Complex types marshalled as JSON strings, simple types marshalled as simple strings
-			if param.Type == ParameterTypeArray || param.Type == ParameterTypeObject {
-				bytes, err := json.Marshal(param.Value)
-				if err != nil {
-					return fmt.Errorf("invalid value for output parameter '%s' (%s): %w", key, string(param.Type), err)
-				}
-				env.DotenvSet(key, string(bytes))
-			} else {
-				env.DotenvSet(key, fmt.Sprintf("%v", param.Value))
+			value, err := outputEnvValue(key, param)
+			if err != nil {
+				return err
+			}
+			env.DotenvSet(key, value)
+
+			// The .env file only holds strings; keep the output type so it can be restored when values are read back.
+			err = env.SetOutputMetadata(key, environment.OutputMetadata{
+				Type:   string(param.Type),
+				Secure: param.Secure,
+			})
+			if err != nil {
+				return fmt.Errorf("recording type of output parameter '%s': %w", key, err)
+			}
+
+			err = env.SetOutputSync(key, environment.OutputSync{SyncedAt: syncedAt, DeployedAt: deployedAt})
+			if err != nil {
+				return fmt.Errorf("recording sync time of output parameter '%s': %w", key, err)
 			}
 		}
 
@@ -358,6 +438,23 @@ func UpdateEnvironment(
 	return nil
 }
 
+// outputEnvValue returns the .env value for an output parameter.
+func outputEnvValue(key string, param OutputParameter) (string, error) {
+	// Complex types marshalled as JSON strings, simple types marshalled as simple strings
+	if param.Type == ParameterTypeArray || param.Type == ParameterTypeObject {
+		bytes, err := json.Marshal(param.Value)
+		if err != nil {
+			return "", fmt.Errorf("invalid value for output parameter '%s' (%s): %w", key, string(param.Type), err)
+		}
+		return string(bytes), nil
+	} else if number, isFloat := param.Value.(float64); isFloat {
+		// JSON numbers decode as float64; avoid exponent notation (e.g. 1e+06) for large values.
+		return strconv.FormatFloat(number, 'f', -1, 64), nil
+	}
+
+	return fmt.Sprintf("%v", param.Value), nil
+}
+
 type EnsureSubscriptionAndLocationOptions struct {
 	// LocationFilterPredicate is a function to filter the locations being displayed if prompting the user for the location.
 	LocationFiler prompt.LocationFilterPredicate
@@ -98,6 +98,20 @@ func TestManagerPreview(t *testing.T) {
 
 	require.NotNil(t, deploymentPlan)
 	require.Nil(t, err)
+
+	// Resource types without a display name aren't previewed.
+	require.Len(t, deploymentPlan.Preview.Properties.Changes, 1)
+	require.Equal(t, "Web App", deploymentPlan.Preview.Properties.Changes[0].ResourceType)
+
+	// They're kept in a diff, so drift in any resource is reported.
+	diff, err := mgr.Diff(*mockContext.Context)
+	require.NoError(t, err)
+
+	var resourceTypes []string
+	for _, change := range diff.Preview.Properties.Changes {
+		resourceTypes = append(resourceTypes, change.ResourceType)
+	}
+	require.Equal(t, []string{"Web App", "Microsoft.Authorization/roleAssignments"}, resourceTypes)
 }
 
 func TestManagerGetState(t *testing.T) {
@@ -231,6 +245,36 @@ func TestManagerDestroyWithNegativeConfirmation(t *testing.T) {
 	require.Contains(t, mockContext.Console.Output(), "Are you sure you want to destroy?")
 }
 
+func TestManagerDestroyKeepNotSupported(t *testing.T) {
+	env := environment.NewWithValues("test-env", map[string]string{
+		"AZURE_SUBSCRIPTION_ID": "SUBSCRIPTION_ID",
+		"AZURE_LOCATION":        "eastus2",
+	})
+
+	mockContext := mocks.NewMockContext(t.Context())
+	registerContainerDependencies(mockContext, env)
+
+	envManager := &mockenv.MockEnvManager{}
+	mgr := provisioning.NewManager(
+		mockContext.Container,
+		defaultProvider,
+		envManager,
+		env,
+		mockContext.Console,
+		mockContext.AlphaFeaturesManager,
+		nil,
+		cloud.AzurePublic(),
+	)
+	err := mgr.Initialize(*mockContext.Context, "", provisioning.Options{Provider: "test"})
+	require.NoError(t, err)
+
+	destroyOptions := provisioning.NewDestroyOptions(true, false).WithKeep([]string{"kv1"}, false)
+	destroyResult, err := mgr.Destroy(*mockContext.Context, destroyOptions)
+
+	require.Nil(t, destroyResult)
+	require.ErrorIs(t, err, provisioning.ErrKeepNotSupportedByProvider)
+}
+
 func TestEnsureSubscriptionAndLocation_NoPromptMissingSubscriptionReturnsPromptRequiredError(t *testing.T) {
 	env := environment.NewWithValues("test-env", nil)
 
@@ -648,3 +692,31 @@ func TestRecordInfraProviderUsage_DoesNotLeakToSiblingSpans(t *testing.T) {
 	require.False(t, hasInfraProvider(tracing.GetUsageAttributes()),
 		"infra.provider must not be written to the process-global usage bag")
 }
+
+func TestUpdateEnvironmentRecordsOutputTypes(t *testing.T) {
+	env := environment.NewWithValues("test-env", nil)
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	err := provisioning.UpdateEnvironment(t.Context(), map[string]provisioning.OutputParameter{
+		"NAME":     {Type: provisioning.ParameterTypeString, Value: "web"},
+		"COUNT":    {Type: provisioning.ParameterTypeNumber, Value: float64(1000000)},
+		"HOSTS":    {Type: provisioning.ParameterTypeArray, Value: []any{"a", "b"}},
+		"PASSWORD": {Type: provisioning.ParameterTypeString, Value: "hunter2", Secure: true},
+	}, env, envManager)
+	require.NoError(t, err)
+
+	require.Equal(t, "1000000", env.Getenv("COUNT"))
+	require.Equal(t, `["a","b"]`, env.Getenv("HOSTS"))
+
+	_, has := env.OutputMetadata("NAME")
+	require.False(t, has)
+
+	metadata, has := env.OutputMetadata("COUNT")
+	require.True(t, has)
+	require.Equal(t, environment.OutputMetadata{Type: environment.OutputTypeNumber}, metadata)
+
+	metadata, has = env.OutputMetadata("PASSWORD")
+	require.True(t, has)
+	require.True(t, metadata.Secure)
+}
@@ -3,7 +3,12 @@
 
 package provisioning
 
-import "github.com/azure/azure-dev/cli/azd/pkg/output"
+import (
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
 
 type ActionOptions struct {
 	// The desired console output format
@@ -34,11 +39,19 @@ type DestroyOptions struct {
 	force bool
 	// Whether or not to purge any key vaults associated with the deployment
 	purge bool
+	// The names, types or IDs of the resources to keep instead of deleting them
+	keep []string
+	// Whether or not the user may select more resources to keep before deleting resources
+	selectable bool
+	// The resources that are retained because they're protected in azure.yaml
+	protect []ProtectedResource
 }
 
 type StateOptions struct {
 	// A value used to lookup the state of a specific deployment
 	hint string
+	// Whether the state is only compared with the environment, which isn't updated from it
+	check bool
 }
 
 func NewStateOptions(hint string) *StateOptions {
@@ -47,10 +60,23 @@ func NewStateOptions(hint string) *StateOptions {
 	}
 }
 
+// NewCheckStateOptions creates the options to get the state of a deployment only to compare it with the environment, as
+// `azd env refresh --check` does. Providers don't report the environment as updated from such a state.
+func NewCheckStateOptions(hint string) *StateOptions {
+	return &StateOptions{
+		hint:  hint,
+		check: true,
+	}
+}
+
 func (o *StateOptions) Hint() string {
 	return o.hint
 }
 
+func (o *StateOptions) Check() bool {
+	return o.check
+}
+
 func (o *DestroyOptions) Purge() bool {
 	return o.purge
 }
@@ -59,6 +85,30 @@ func (o *DestroyOptions) Force() bool {
 	return o.force
 }
 
+// Keep returns the names, types or IDs of the resources to keep.
+func (o *DestroyOptions) Keep() []string {
+	return o.keep
+}
+
+// Selectable returns whether the user may select more resources to keep before deleting resources.
+func (o *DestroyOptions) Selectable() bool {
+	return o.selectable
+}
+
+// Keeps returns whether the resource matches one of the resources to keep, by name, type or ID.
+func (o *DestroyOptions) Keeps(resourceId string, resourceType string, resourceName string) bool {
+	return slices.ContainsFunc(o.keep, func(keep string) bool {
+		return strings.EqualFold(keep, resourceId) ||
+			strings.EqualFold(keep, resourceType) ||
+			strings.EqualFold(keep, resourceName)
+	})
+}
+
+// Protect returns the resources that are retained because they're protected in azure.yaml.
+func (o *DestroyOptions) Protect() []ProtectedResource {
+	return o.protect
+}
+
 func NewDestroyOptions(force bool, purge bool) DestroyOptions {
 	return DestroyOptions{
 		force: force,
@@ -66,6 +116,20 @@ func NewDestroyOptions(force bool, purge bool) DestroyOptions {
 	}
 }
 
+// WithKeep returns a copy of the options that keeps the resources matching keep, which are resource names, types or
+// IDs. When selectable is true, the user may select more resources to keep before resources are deleted.
+func (o DestroyOptions) WithKeep(keep []string, selectable bool) DestroyOptions {
+	o.keep = keep
+	o.selectable = selectable
+	return o
+}
+
+// WithProtect returns a copy of the options that retains the protected resources.
+func (o DestroyOptions) WithProtect(protect []ProtectedResource) DestroyOptions {
+	o.protect = protect
+	return o
+}
+
 func NewActionOptions(formatter output.Formatter, interactive bool) ActionOptions {
 	return ActionOptions{
 		formatter:   formatter,
@@ -32,6 +32,25 @@ func TestNewDestroyOptions(t *testing.T) {
 	}
 }
 
+func TestDestroyOptionsKeep(t *testing.T) {
+	opts := NewDestroyOptions(false, true).WithKeep([]string{"kv1", "Microsoft.Sql/servers"}, true)
+	assert.False(t, opts.Force())
+	assert.True(t, opts.Purge())
+	assert.True(t, opts.Selectable())
+	assert.Equal(t, []string{"kv1", "Microsoft.Sql/servers"}, opts.Keep())
+
+	kvId := "/subscriptions/SUB/resourceGroups/rg/providers/Microsoft.KeyVault/vaults/kv1"
+	assert.True(t, opts.Keeps(kvId, "Microsoft.KeyVault/vaults", "KV1"))
+	assert.True(t, opts.Keeps("/subscriptions/SUB/resourceGroups/rg/providers/Microsoft.Sql/servers/sql1",
+		"microsoft.sql/servers", "sql1"))
+	assert.False(t, opts.Keeps("/subscriptions/SUB/resourceGroups/rg/providers/Microsoft.Web/sites/app1",
+		"Microsoft.Web/sites", "app1"))
+
+	byId := NewDestroyOptions(false, false).WithKeep([]string{kvId}, false)
+	assert.True(t, byId.Keeps(kvId, "Microsoft.KeyVault/vaults", "kv1"))
+	assert.False(t, byId.Selectable())
+}
+
 func TestNewStateOptions(t *testing.T) {
 	t.Run("stores hint", func(t *testing.T) {
 		opts := NewStateOptions("my-hint")
@@ -255,6 +274,41 @@ func TestOptionsValidate(t *testing.T) {
 			)
 		})
 
+	t.Run("deploymentMode set at top level with layers is valid",
+		func(t *testing.T) {
+			opts := &Options{
+				DeploymentMode: DeploymentModeStacks,
+				Layers: []Options{
+					{Name: "l1", Path: "infra/l1"},
+				},
+			}
+			require.NoError(t, opts.Validate())
+		})
+
+	t.Run("deploymentMode set on a layer is invalid",
+		func(t *testing.T) {
+			opts := &Options{
+				Layers: []Options{
+					{Name: "l1", Path: "infra/l1", DeploymentMode: DeploymentModeStacks},
+				},
+			}
+			err := opts.Validate()
+			require.Error(t, err)
+			assert.Contains(
+				t, err.Error(), "'deploymentMode' can only be declared on 'infra'",
+			)
+		})
+
+	t.Run("unknown deploymentMode is invalid",
+		func(t *testing.T) {
+			opts := &Options{DeploymentMode: "blueprint"}
+			err := opts.Validate()
+			require.Error(t, err)
+			assert.Contains(
+				t, err.Error(), "'deploymentMode' must be 'standard' or 'stacks'",
+			)
+		})
+
 	t.Run("multiple valid layers", func(t *testing.T) {
 		opts := &Options{
 			Layers: []Options{
@@ -0,0 +1,78 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ProtectedResource matches resources that `azd down` retains instead of deleting, for example shared infrastructure
+// such as a container registry used by every environment. A resource matches when it matches every field that is set.
+type ProtectedResource struct {
+	// Name is a pattern matched against the resource name, case-insensitively. It supports the `*` and `?` wildcards,
+	// for example "acr*".
+	Name string `yaml:"name,omitempty"`
+	// Tag is a resource tag, either a tag name ("shared") or a tag name and value ("shared=true").
+	Tag string `yaml:"tag,omitempty"`
+}
+
+// Validate returns an error when the protected resource matches nothing or has an invalid name pattern.
+func (r ProtectedResource) Validate() error {
+	if r.Name == "" && r.Tag == "" {
+		return fmt.Errorf("protected resource must have a name or a tag")
+	}
+
+	if _, err := path.Match(r.Name, ""); err != nil {
+		return fmt.Errorf("invalid protected resource name pattern '%s': %w", r.Name, err)
+	}
+
+	if tagName, _, _ := r.TagFilter(); r.Tag != "" && tagName == "" {
+		return fmt.Errorf("invalid protected resource tag '%s': the tag name is empty", r.Tag)
+	}
+
+	return nil
+}
+
+// MatchesName returns whether the resource name matches the name pattern. It's true when there's no name pattern.
+func (r ProtectedResource) MatchesName(resourceName string) bool {
+	if r.Name == "" {
+		return true
+	}
+
+	matched, err := path.Match(strings.ToLower(r.Name), strings.ToLower(resourceName))
+	return err == nil && matched
+}
+
+// TagFilter returns the tag name and value of the tag. hasValue is false when only the tag name must be present.
+func (r ProtectedResource) TagFilter() (tagName string, tagValue string, hasValue bool) {
+	tagName, tagValue, hasValue = strings.Cut(r.Tag, "=")
+	return strings.TrimSpace(tagName), strings.TrimSpace(tagValue), hasValue
+}
+
+// ProtectedResources returns the protected resources of the layer, which are the protected resources declared on the
+// root infra options followed by the ones declared on the layer.
+func (o *Options) ProtectedResources(layer Options) []ProtectedResource {
+	if len(o.Layers) == 0 {
+		return o.Protect
+	}
+
+	return append(append([]ProtectedResource{}, o.Protect...), layer.Protect...)
+}
+
+// HasProtectedResources returns whether protected resources are declared on the root infra options or on any layer.
+func (o *Options) HasProtectedResources() bool {
+	if len(o.Protect) > 0 {
+		return true
+	}
+
+	for _, layer := range o.Layers {
+		if len(layer.Protect) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
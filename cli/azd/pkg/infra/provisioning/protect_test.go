@@ -0,0 +1,64 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtectedResource_MatchesName(t *testing.T) {
+	tests := []struct {
+		name         string
+		pattern      string
+		resourceName string
+		want         bool
+	}{
+		{name: "Exact", pattern: "acr1", resourceName: "acr1", want: true},
+		{name: "CaseInsensitive", pattern: "ACR*", resourceName: "acrshared", want: true},
+		{name: "SingleCharacter", pattern: "kv?", resourceName: "kv1", want: true},
+		{name: "NoMatch", pattern: "acr*", resourceName: "kv1"},
+		{name: "NoPattern", resourceName: "kv1", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, ProtectedResource{Name: tt.pattern}.MatchesName(tt.resourceName))
+		})
+	}
+}
+
+func TestProtectedResource_TagFilter(t *testing.T) {
+	tagName, tagValue, hasValue := ProtectedResource{Tag: "shared = true"}.TagFilter()
+	require.Equal(t, "shared", tagName)
+	require.Equal(t, "true", tagValue)
+	require.True(t, hasValue)
+
+	tagName, _, hasValue = ProtectedResource{Tag: "shared"}.TagFilter()
+	require.Equal(t, "shared", tagName)
+	require.False(t, hasValue)
+}
+
+func TestProtectedResource_Validate(t *testing.T) {
+	require.NoError(t, ProtectedResource{Name: "acr*"}.Validate())
+	require.NoError(t, ProtectedResource{Tag: "shared=true"}.Validate())
+	require.Error(t, ProtectedResource{}.Validate())
+	require.Error(t, ProtectedResource{Name: "acr["}.Validate())
+	require.Error(t, ProtectedResource{Tag: "=true"}.Validate())
+}
+
+func TestOptions_ProtectedResources(t *testing.T) {
+	single := Options{Protect: []ProtectedResource{{Name: "acr*"}}}
+	require.Equal(t, []ProtectedResource{{Name: "acr*"}}, single.ProtectedResources(single))
+	require.True(t, single.HasProtectedResources())
+
+	layer := Options{Name: "shared", Protect: []ProtectedResource{{Tag: "shared"}}}
+	layered := Options{Protect: []ProtectedResource{{Name: "acr*"}}, Layers: []Options{layer, {Name: "app"}}}
+	require.Equal(t, []ProtectedResource{{Name: "acr*"}, {Tag: "shared"}}, layered.ProtectedResources(layer))
+	require.Equal(t, []ProtectedResource{{Name: "acr*"}}, layered.ProtectedResources(layered.Layers[1]))
+
+	require.True(t, (&Options{Layers: []Options{layer}}).HasProtectedResources())
+	require.False(t, (&Options{Layers: []Options{{Name: "app"}}}).HasProtectedResources())
+}
@@ -5,9 +5,11 @@ package provisioning
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"dario.cat/mergo"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing"
@@ -33,6 +35,8 @@ const (
 	ModeDeploy Mode = ""
 	// Mode for destroying the deployment.
 	ModeDestroy Mode = "destroy"
+	// Mode for canceling an in-flight deployment.
+	ModeCancel Mode = "cancel"
 )
 
 // Options for a provisioning provider.
@@ -43,6 +47,12 @@ type Options struct {
 	Name             string         `yaml:"name,omitempty"`
 	Hooks            HooksConfig    `yaml:"hooks,omitempty"`
 	DeploymentStacks map[string]any `yaml:"deploymentStacks,omitempty"`
+	// DeploymentMode selects plain ARM deployments ("standard") or Azure Deployment Stacks ("stacks"). It applies to
+	// every layer and can only be declared on the root infra options.
+	DeploymentMode DeploymentMode `yaml:"deploymentMode,omitempty"`
+	// DeploymentTimeout is the maximum time azd waits for a deployment to complete (for example "45m") before
+	// asking the user whether to keep waiting or cancel the deployment. Empty means no timeout.
+	DeploymentTimeout string `yaml:"deploymentTimeout,omitempty"`
 	// Config holds provider-specific configuration options
 	Config map[string]any `yaml:"config,omitempty"`
 	// DependsOn lists the names of other layers this layer must wait for
@@ -53,6 +63,9 @@ type Options struct {
 	// .parameters.json contents alone. Only valid on layer entries under
 	// the `infra.layers` array.
 	DependsOn []string `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	// Protect lists the resources that `azd down` retains instead of deleting, by name pattern or tag. Resources
+	// protected on the root infra options are protected in every layer.
+	Protect []ProtectedResource `yaml:"protect,omitempty"`
 	// Provisioning options for each individually defined layer.
 	Layers []Options `yaml:"layers,omitempty"`
 
@@ -60,6 +73,8 @@ type Options struct {
 
 	// IgnoreDeploymentState when true, skips the deployment state check.
 	IgnoreDeploymentState bool `yaml:"-"`
+	// CollectTimings when true, reports how long each resource and module took to deploy in the DeployResult.
+	CollectTimings bool `yaml:"-"`
 	// The mode in which the deployment is being run.
 	Mode Mode `yaml:"-"`
 	// Environment variables that should be considered as resolved when prompting for parameters.
@@ -152,7 +167,8 @@ func (o *Options) Validate() error {
 
 	if len(o.Layers) > 0 {
 		anyIncompatibleFieldsSet := func() bool {
-			return o.Name != "" || o.Module != "" || o.Path != "" || o.DeploymentStacks != nil
+			return o.Name != "" || o.Module != "" || o.Path != "" || o.DeploymentStacks != nil ||
+				o.DeploymentTimeout != ""
 		}
 
 		if anyIncompatibleFieldsSet() {
@@ -164,9 +180,32 @@ func (o *Options) Validate() error {
 		}
 	}
 
+	if _, err := o.Timeout(); err != nil {
+		return wrapValidateErr("infra", err)
+	}
+
+	if err := o.DeploymentMode.validate(); err != nil {
+		return wrapValidateErr("infra", err)
+	}
+
 	return nil
 }
 
+// Timeout returns the parsed DeploymentTimeout, or zero when no timeout is configured.
+func (o *Options) Timeout() (time.Duration, error) {
+	if o.DeploymentTimeout == "" {
+		return 0, nil
+	}
+
+	timeout, err := time.ParseDuration(o.DeploymentTimeout)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf(
+			"'deploymentTimeout' must be a positive duration such as '30m' or '1h', got '%s'", o.DeploymentTimeout)
+	}
+
+	return timeout, nil
+}
+
 func wrapValidateErr(scope string, err error) error {
 	if err == nil {
 		return nil
@@ -210,6 +249,14 @@ func (o *Options) validateLayers() error {
 		if err := validateHooks(layer.Name, layer.Hooks); err != nil {
 			return err
 		}
+
+		if _, err := layer.Timeout(); err != nil {
+			return fmt.Errorf("%s: %w", layer.Name, err)
+		}
+
+		if layer.DeploymentMode != "" {
+			return fmt.Errorf("%s: 'deploymentMode' can only be declared on 'infra'", layer.Name)
+		}
 	}
 
 	return nil
@@ -225,6 +272,9 @@ const (
 type DeployResult struct {
 	Deployment    *Deployment
 	SkippedReason SkippedReasonType
+	// Timings holds the deployment duration of each resource and module, slowest first. It is only populated when
+	// Options.CollectTimings is set and the provider supports it.
+	Timings []ResourceTiming
 }
 
 // DeployPreviewResult defines one deployment in preview mode, displaying what changes would it be performed, without
@@ -239,6 +289,8 @@ type DestroyResult struct {
 	// SkippedDeletion is true when the provider intentionally did not delete any resources (for example, when
 	// running with --no-prompt in a CI/CD environment without --force, where only a destroy preview is shown).
 	SkippedDeletion bool
+	// RetainedResources are the IDs of the protected resources that weren't deleted.
+	RetainedResources []string
 }
 
 type StateResult struct {
@@ -262,6 +314,41 @@ type PlannedOutput struct {
 	Name string
 }
 
+// CancelResult describes the outcome of canceling an in-flight deployment.
+type CancelResult struct {
+	// DeploymentName is the name of the deployment that was canceled.
+	DeploymentName string `json:"deploymentName"`
+	// ProvisioningState is the last observed provisioning state of the deployment after the cancel request.
+	ProvisioningState string `json:"provisioningState"`
+	// PortalUrl links to the deployment in the Azure Portal, when it could be resolved.
+	PortalUrl string `json:"portalUrl,omitempty"`
+	// Resources lists the resources the deployment touched, with their final provisioning state.
+	Resources []CanceledResource `json:"resources"`
+}
+
+// CanceledResource is a resource touched by a canceled deployment.
+type CanceledResource struct {
+	Id                string `json:"id"`
+	Name              string `json:"name"`
+	Type              string `json:"type"`
+	ProvisioningState string `json:"provisioningState"`
+}
+
+// Canceler is implemented by providers that can cancel an in-flight deployment for the current environment.
+type Canceler interface {
+	Cancel(ctx context.Context) (*CancelResult, error)
+}
+
+// ResourceKeeper is implemented by providers that can keep some of the resources of the environment when they destroy
+// it. See [DestroyOptions.Keep].
+type ResourceKeeper interface {
+	CanKeepResources() bool
+}
+
+// ErrResourcesLocked is returned by Destroy when resource locks prevent the deletion of the resources and the user
+// didn't agree to remove them.
+var ErrResourcesLocked = errors.New("resources are locked")
+
 type Provider interface {
 	Name() string
 	Initialize(ctx context.Context, projectPath string, options Options) error
@@ -6,6 +6,7 @@ package provisioning
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -482,3 +483,44 @@ func TestOptions_Validate_Hooks(t *testing.T) {
 		require.EqualError(t, err, "validating infra: 'hooks' can only be declared under 'infra.layers[]'")
 	})
 }
+
+func TestOptions_Timeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeout  string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{name: "unset", timeout: "", expected: 0},
+		{name: "minutes", timeout: "45m", expected: 45 * time.Minute},
+		{name: "hours", timeout: "1h30m", expected: 90 * time.Minute},
+		{name: "invalid", timeout: "soon", wantErr: true},
+		{name: "negative", timeout: "-5m", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := &Options{DeploymentTimeout: tt.timeout}
+			timeout, err := options.Timeout()
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Error(t, options.Validate())
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, timeout)
+			require.NoError(t, options.Validate())
+		})
+	}
+
+	t.Run("invalid layer timeout", func(t *testing.T) {
+		err := (&Options{
+			Layers: []Options{
+				{Name: "infra-core", Path: "infra/core", DeploymentTimeout: "later"},
+			},
+		}).Validate()
+
+		require.ErrorContains(t, err, "infra-core: 'deploymentTimeout' must be a positive duration")
+	})
+}
@@ -0,0 +1,94 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+)
+
+// StaleOutputReason explains why an environment value is out of date with a provisioning output.
+type StaleOutputReason string
+
+const (
+	// StaleOutputMissing is the reason of an output that has no value in the environment.
+	StaleOutputMissing StaleOutputReason = "missing"
+	// StaleOutputChanged is the reason of an output with a value that differs from the value in the environment.
+	StaleOutputChanged StaleOutputReason = "changed"
+	// StaleOutputOutdated is the reason of an output deployed after its value was written to the environment.
+	StaleOutputOutdated StaleOutputReason = "outdated"
+)
+
+// syncClockSkew is the tolerance between the local clock and the clock of the provider when comparing the time of a
+// deployment with the time its outputs were written to the environment.
+const syncClockSkew = 5 * time.Minute
+
+// StaleOutput is an environment value that is out of date with the outputs of the most recent deployment.
+type StaleOutput struct {
+	Name   string
+	Reason StaleOutputReason
+}
+
+// FindStaleOutputs compares the outputs of the state with the values of the environment, and returns the values that
+// are missing, that differ from the outputs, or that were written before the most recent deployment, sorted by name.
+func FindStaleOutputs(state *State, env *environment.Environment) ([]StaleOutput, error) {
+	stale := []StaleOutput{}
+
+	for key, param := range state.Outputs {
+		expected, err := outputEnvValue(key, param)
+		if err != nil {
+			return nil, err
+		}
+
+		value, has := env.LookupEnv(key)
+		switch {
+		case !has:
+			stale = append(stale, StaleOutput{Name: key, Reason: StaleOutputMissing})
+		case value != expected:
+			stale = append(stale, StaleOutput{Name: key, Reason: StaleOutputChanged})
+		default:
+			sync, synced := env.OutputSync(key)
+			if !synced {
+				continue
+			}
+
+			since := sync.Since()
+			if sync.DeployedAt.IsZero() {
+				// The sync time comes from the local clock, which may be behind the clock of the provider.
+				since = since.Add(syncClockSkew)
+			}
+
+			if state.DeployedAt.After(since) {
+				stale = append(stale, StaleOutput{Name: key, Reason: StaleOutputOutdated})
+			}
+		}
+	}
+
+	slices.SortFunc(stale, func(a, b StaleOutput) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return stale, nil
+}
+
+// NewEnvRefreshCheckResult creates the result of `azd env refresh --check` from the stale outputs.
+func NewEnvRefreshCheckResult(stale []StaleOutput) contracts.EnvRefreshCheckResult {
+	result := contracts.EnvRefreshCheckResult{
+		Stale:   len(stale) > 0,
+		Outputs: make([]contracts.EnvRefreshStaleOutput, len(stale)),
+	}
+
+	for idx, output := range stale {
+		result.Outputs[idx] = contracts.EnvRefreshStaleOutput{
+			Name:   output.Name,
+			Reason: string(output.Reason),
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,69 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindStaleOutputs(t *testing.T) {
+	deployedAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	state := &provisioning.State{
+		DeployedAt: deployedAt,
+		Outputs: map[string]provisioning.OutputParameter{
+			"API_URL": {Type: provisioning.ParameterTypeString, Value: "https://api"},
+			"COUNT":   {Type: provisioning.ParameterTypeNumber, Value: float64(2)},
+			"HOSTS":   {Type: provisioning.ParameterTypeArray, Value: []any{"a"}},
+		},
+	}
+
+	env := environment.NewWithValues("test-env", nil)
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", mock.Anything, mock.Anything).Return(nil)
+	require.NoError(t, provisioning.UpdateEnvironmentFromState(t.Context(), state, env, envManager))
+
+	// The values were just refreshed from the state.
+	stale, err := provisioning.FindStaleOutputs(state, env)
+	require.NoError(t, err)
+	require.Empty(t, stale)
+
+	sync, has := env.OutputSync("API_URL")
+	require.True(t, has)
+	require.True(t, deployedAt.Equal(sync.DeployedAt))
+
+	env.DotenvSet("COUNT", "3")
+	env.DotenvDelete("HOSTS")
+	// A newer deployment makes the values refreshed from the previous one outdated, even when they didn't change.
+	state.DeployedAt = deployedAt.Add(time.Hour)
+
+	stale, err = provisioning.FindStaleOutputs(state, env)
+	require.NoError(t, err)
+	require.Equal(t, []provisioning.StaleOutput{
+		{Name: "API_URL", Reason: provisioning.StaleOutputOutdated},
+		{Name: "COUNT", Reason: provisioning.StaleOutputChanged},
+		{Name: "HOSTS", Reason: provisioning.StaleOutputMissing},
+	}, stale)
+
+	result := provisioning.NewEnvRefreshCheckResult(stale)
+	require.True(t, result.Stale)
+	require.Len(t, result.Outputs, 3)
+	require.Equal(t, "outdated", result.Outputs[0].Reason)
+
+	require.False(t, provisioning.NewEnvRefreshCheckResult(nil).Stale)
+
+	// Without the time of the deployment, the local sync time tolerates some clock skew.
+	err = provisioning.UpdateEnvironment(t.Context(), state.Outputs, env, envManager)
+	require.NoError(t, err)
+	state.DeployedAt = time.Now().Add(time.Minute)
+	stale, err = provisioning.FindStaleOutputs(state, env)
+	require.NoError(t, err)
+	require.Empty(t, stale)
+}
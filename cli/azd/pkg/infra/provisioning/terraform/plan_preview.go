@@ -0,0 +1,240 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package terraform
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+)
+
+const (
+	// sensitiveValue replaces the values terraform marks as sensitive in a plan.
+	sensitiveValue = "(sensitive value)"
+	// unknownValue replaces the values that terraform only knows after the plan is applied.
+	unknownValue = "(known after apply)"
+)
+
+// terraformPlanOutput is a model type for the output of `terraform show` for a plan file.
+// see https://developer.hashicorp.com/terraform/internals/json-format#plan-representation for more information on
+// the shape of the JSON data
+type terraformPlanOutput struct {
+	FormatVersion   string                    `json:"format_version"`
+	ResourceChanges []terraformResourceChange `json:"resource_changes"`
+}
+
+// terraformResourceChange is the model type for the planned change of a single resource instance.
+type terraformResourceChange struct {
+	Address       string `json:"address"`
+	ModuleAddress string `json:"module_address"`
+	// "mode" can be "managed", for resources, or "data", for data resources
+	Mode   string          `json:"mode"`
+	Type   string          `json:"type"`
+	Name   string          `json:"name"`
+	Change terraformChange `json:"change"`
+}
+
+// terraformChange is the model type for a change representation. The sensitive and unknown markers mirror the
+// shape of the before and after values, with `true` for every value that is sensitive or unknown.
+type terraformChange struct {
+	Actions         []string `json:"actions"`
+	Before          any      `json:"before"`
+	After           any      `json:"after"`
+	AfterUnknown    any      `json:"after_unknown"`
+	BeforeSensitive any      `json:"before_sensitive"`
+	AfterSensitive  any      `json:"after_sensitive"`
+}
+
+// previewChanges converts the resource changes of a terraform plan to the changes of a deployment preview. Data
+// sources are skipped because reading them doesn't change anything. Sensitive values are redacted.
+func previewChanges(plan terraformPlanOutput) []*provisioning.DeploymentPreviewChange {
+	var changes []*provisioning.DeploymentPreviewChange
+	for _, resourceChange := range plan.ResourceChanges {
+		if resourceChange.Mode != terraformModeManaged {
+			continue
+		}
+
+		change := resourceChange.Change
+		changeType := planChangeType(change.Actions)
+
+		previewChange := &provisioning.DeploymentPreviewChange{
+			ChangeType:   changeType,
+			ResourceType: resourceChange.Type,
+			Name:         resourceChange.Address,
+			Before:       redact(change.Before, change.BeforeSensitive),
+			After:        redact(change.After, change.AfterSensitive),
+		}
+
+		if changeType == provisioning.ChangeTypeModify || changeType == provisioning.ChangeTypeReplace {
+			previewChange.Delta = propertyChanges(
+				"", change.Before, change.After, change.BeforeSensitive, change.AfterSensitive, change.AfterUnknown)
+		}
+
+		changes = append(changes, previewChange)
+	}
+
+	return changes
+}
+
+// planChangeType maps the actions terraform plans for a resource to a preview change type.
+// see https://developer.hashicorp.com/terraform/internals/json-format#change-representation
+func planChangeType(actions []string) provisioning.ChangeType {
+	switch {
+	case slices.Equal(actions, []string{"no-op"}):
+		return provisioning.ChangeTypeNoChange
+	case slices.Equal(actions, []string{"create"}):
+		return provisioning.ChangeTypeCreate
+	case slices.Equal(actions, []string{"update"}):
+		return provisioning.ChangeTypeModify
+	case slices.Equal(actions, []string{"delete"}):
+		return provisioning.ChangeTypeDelete
+	case slices.Equal(actions, []string{"delete", "create"}), slices.Equal(actions, []string{"create", "delete"}):
+		return provisioning.ChangeTypeReplace
+	case slices.Equal(actions, []string{"read"}), slices.Equal(actions, []string{"forget"}):
+		return provisioning.ChangeTypeIgnore
+	default:
+		return provisioning.ChangeTypeUnsupported
+	}
+}
+
+// propertyChanges compares the before and after values of a resource and returns a change for every leaf property
+// that differs, keyed by its dot separated path. Lists are compared as a whole.
+func propertyChanges(
+	path string,
+	before, after any,
+	beforeSensitive, afterSensitive, afterUnknown any,
+) []provisioning.DeploymentPreviewPropertyChange {
+	unknown := afterUnknown == true
+	beforeMap, beforeIsMap := before.(map[string]any)
+	afterMap, afterIsMap := after.(map[string]any)
+	unknownMap, _ := afterUnknown.(map[string]any)
+
+	if !unknown && (beforeIsMap || before == nil) && (afterIsMap || after == nil) && (beforeIsMap || afterIsMap) {
+		keys := map[string]struct{}{}
+		for _, m := range []map[string]any{beforeMap, afterMap, unknownMap} {
+			for key := range m {
+				keys[key] = struct{}{}
+			}
+		}
+
+		var changes []provisioning.DeploymentPreviewPropertyChange
+		for _, key := range slices.Sorted(maps.Keys(keys)) {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+
+			changes = append(changes, propertyChanges(
+				childPath,
+				beforeMap[key],
+				afterMap[key],
+				child(beforeSensitive, key),
+				child(afterSensitive, key),
+				child(afterUnknown, key),
+			)...)
+		}
+
+		return changes
+	}
+
+	if !unknown && reflect.DeepEqual(before, after) {
+		return nil
+	}
+
+	change := provisioning.DeploymentPreviewPropertyChange{
+		Path:   path,
+		Before: before,
+		After:  after,
+	}
+
+	switch {
+	case before == nil:
+		change.ChangeType = provisioning.PropertyChangeTypeCreate
+	case after == nil && !unknown:
+		change.ChangeType = provisioning.PropertyChangeTypeDelete
+	case isList(before) || isList(after):
+		change.ChangeType = provisioning.PropertyChangeTypeArray
+	default:
+		change.ChangeType = provisioning.PropertyChangeTypeModify
+	}
+
+	if hasMarker(beforeSensitive) && change.Before != nil {
+		change.Before = sensitiveValue
+	}
+	if hasMarker(afterSensitive) && change.After != nil {
+		change.After = sensitiveValue
+	}
+	if unknown {
+		change.After = unknownValue
+	}
+
+	return []provisioning.DeploymentPreviewPropertyChange{change}
+}
+
+// redact returns a copy of value where every value that marker flags as sensitive is replaced.
+func redact(value any, marker any) any {
+	if value == nil {
+		return nil
+	}
+
+	if marker == true {
+		return sensitiveValue
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		redacted := make(map[string]any, len(v))
+		for key, item := range v {
+			redacted[key] = redact(item, child(marker, key))
+		}
+		return redacted
+	case []any:
+		markers, _ := marker.([]any)
+		redacted := make([]any, len(v))
+		for i, item := range v {
+			var itemMarker any
+			if i < len(markers) {
+				itemMarker = markers[i]
+			}
+			redacted[i] = redact(item, itemMarker)
+		}
+		return redacted
+	default:
+		return value
+	}
+}
+
+// child returns the marker of the property key of an object marker.
+func child(marker any, key string) any {
+	if marker == true {
+		return true
+	}
+
+	if m, ok := marker.(map[string]any); ok {
+		return m[key]
+	}
+
+	return nil
+}
+
+// hasMarker reports whether marker flags the value, or any value nested in it.
+func hasMarker(marker any) bool {
+	switch m := marker.(type) {
+	case bool:
+		return m
+	case map[string]any:
+		return slices.ContainsFunc(slices.Collect(maps.Values(m)), hasMarker)
+	case []any:
+		return slices.ContainsFunc(m, hasMarker)
+	default:
+		return false
+	}
+}
+
+func isList(value any) bool {
+	_, ok := value.([]any)
+	return ok
+}
@@ -0,0 +1,138 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package terraform
+
+import (
+	_ "embed"
+	"encoding/json"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/terraform_plan_mock.json
+var terraformPlanMockOutput string
+
+func Test_previewChanges(t *testing.T) {
+	var plan terraformPlanOutput
+	require.NoError(t, json.Unmarshal([]byte(terraformPlanMockOutput), &plan))
+
+	changes := previewChanges(plan)
+
+	// The data source is skipped.
+	require.Len(t, changes, 5)
+
+	var summary []string
+	for _, change := range changes {
+		summary = append(summary, string(change.ChangeType)+" "+change.ResourceType+" "+change.Name)
+	}
+	require.Equal(t, []string{
+		"NoChange azurerm_resource_group azurerm_resource_group.rg",
+		"Modify azurerm_linux_web_app module.web.azurerm_linux_web_app.web",
+		"Create azurerm_key_vault azurerm_key_vault.kv",
+		"Replace azurerm_storage_account azurerm_storage_account.storage",
+		"Delete azurerm_log_analytics_workspace azurerm_log_analytics_workspace.logs",
+	}, summary)
+
+	require.Empty(t, changes[0].Delta)
+	require.Equal(t, []provisioning.DeploymentPreviewPropertyChange{
+		{
+			ChangeType: provisioning.PropertyChangeTypeModify,
+			Path:       "app_settings.API_KEY",
+			Before:     sensitiveValue,
+			After:      sensitiveValue,
+		},
+		{
+			ChangeType: provisioning.PropertyChangeTypeModify,
+			Path:       "app_settings.MODE",
+			Before:     "debug",
+			After:      "release",
+		},
+		{
+			ChangeType: provisioning.PropertyChangeTypeModify,
+			Path:       "https_only",
+			Before:     false,
+			After:      true,
+		},
+		{
+			ChangeType: provisioning.PropertyChangeTypeCreate,
+			Path:       "outbound_ip_addresses",
+			After:      unknownValue,
+		},
+		{
+			ChangeType: provisioning.PropertyChangeTypeArray,
+			Path:       "site_config.ip_restriction",
+			Before:     []any{},
+			After:      []any{map[string]any{"ip_address": "10.0.0.0/24"}},
+		},
+		{
+			ChangeType: provisioning.PropertyChangeTypeDelete,
+			Path:       "tags.env",
+			Before:     "test",
+		},
+	}, changes[1].Delta)
+
+	// Creates don't list property changes, and sensitive values are redacted from the planned values.
+	require.Empty(t, changes[2].Delta)
+	require.Equal(t, map[string]any{"name": "kv-test-env", "tenant_id": sensitiveValue}, changes[2].After)
+
+	require.Equal(t, []provisioning.DeploymentPreviewPropertyChange{
+		{
+			ChangeType: provisioning.PropertyChangeTypeModify,
+			Path:       "location",
+			Before:     "westus2",
+			After:      "eastus2",
+		},
+	}, changes[3].Delta)
+
+	require.Empty(t, changes[4].Delta)
+	require.Nil(t, changes[4].After)
+}
+
+func Test_planChangeType(t *testing.T) {
+	tests := []struct {
+		actions []string
+		want    provisioning.ChangeType
+	}{
+		{actions: []string{"no-op"}, want: provisioning.ChangeTypeNoChange},
+		{actions: []string{"create"}, want: provisioning.ChangeTypeCreate},
+		{actions: []string{"update"}, want: provisioning.ChangeTypeModify},
+		{actions: []string{"delete"}, want: provisioning.ChangeTypeDelete},
+		{actions: []string{"delete", "create"}, want: provisioning.ChangeTypeReplace},
+		{actions: []string{"create", "delete"}, want: provisioning.ChangeTypeReplace},
+		{actions: []string{"read"}, want: provisioning.ChangeTypeIgnore},
+		{actions: []string{"forget"}, want: provisioning.ChangeTypeIgnore},
+		{actions: []string{"import"}, want: provisioning.ChangeTypeUnsupported},
+		{actions: nil, want: provisioning.ChangeTypeUnsupported},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.want, planChangeType(tt.actions), "actions: %v", tt.actions)
+	}
+}
+
+func Test_redact(t *testing.T) {
+	value := map[string]any{
+		"name":    "app",
+		"secrets": []any{"a", "b"},
+		"nested":  map[string]any{"key": "value", "other": "visible"},
+	}
+	marker := map[string]any{
+		"secrets": []any{false, true},
+		"nested":  map[string]any{"key": true},
+	}
+
+	require.Equal(t, map[string]any{
+		"name":    "app",
+		"secrets": []any{"a", sensitiveValue},
+		"nested":  map[string]any{"key": sensitiveValue, "other": "visible"},
+	}, redact(value, marker))
+
+	require.Equal(t, sensitiveValue, redact(value, true))
+	require.Nil(t, redact(nil, true))
+
+	// The original value isn't changed.
+	require.Equal(t, "value", value["nested"].(map[string]any)["key"])
+}
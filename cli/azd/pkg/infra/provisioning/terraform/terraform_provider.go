@@ -145,6 +145,14 @@ func (t *TerraformProvider) EnsureEnv(ctx context.Context) error {
 
 // Previews the infrastructure through terraform plan
 func (t *TerraformProvider) plan(ctx context.Context) (*provisioning.Deployment, *terraformDeploymentDetails, error) {
+	return t.planWith(ctx, t.cli.Plan)
+}
+
+// planWith is like plan, but runs `terraform plan` through runPlan, which is either Cli.Plan or Cli.PlanQuiet.
+func (t *TerraformProvider) planWith(
+	ctx context.Context,
+	runPlan func(ctx context.Context, modulePath string, planFilePath string, args ...string) (string, error),
+) (*provisioning.Deployment, *terraformDeploymentDetails, error) {
 	isRemoteBackendConfig, err := t.isRemoteBackendConfig()
 	if err != nil {
 		return nil, nil, fmt.Errorf("reading backend config: %w", err)
@@ -169,7 +177,7 @@ func (t *TerraformProvider) plan(ctx context.Context) (*provisioning.Deployment,
 	}
 
 	planArgs := t.createPlanArgs(isRemoteBackendConfig)
-	runResult, err := t.cli.Plan(ctx, modulePath, t.planFilePath(), planArgs...)
+	runResult, err := runPlan(ctx, modulePath, t.planFilePath(), planArgs...)
 	if err != nil {
 		return nil, nil, fmt.Errorf("terraform plan failed:%s err %w", runResult, err)
 	}
@@ -229,17 +237,28 @@ func (t *TerraformProvider) Deploy(ctx context.Context) (*provisioning.DeployRes
 }
 
 func (t *TerraformProvider) Preview(ctx context.Context) (*provisioning.DeployPreviewResult, error) {
-	// terraform uses plan() to display the what-if output
-	// no changes are added to the properties
-	_, _, err := t.plan(ctx)
+	// The plan output isn't streamed. Instead, the saved plan is read back as JSON and rendered like a bicep preview.
+	_, deploymentDetails, err := t.planWith(ctx, t.cli.PlanQuiet)
 	if err != nil {
 		return nil, err
 	}
 
+	planJson, err := t.cli.Show(ctx, t.modulePath(), deploymentDetails.PlanFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading terraform plan: %w", err)
+	}
+
+	var plan terraformPlanOutput
+	if err := json.Unmarshal([]byte(planJson), &plan); err != nil {
+		return nil, fmt.Errorf("parsing terraform plan: %w", err)
+	}
+
 	return &provisioning.DeployPreviewResult{
 		Preview: &provisioning.DeploymentPreview{
-			Status:     "done",
-			Properties: &provisioning.DeploymentPreviewProperties{},
+			Status: "done",
+			Properties: &provisioning.DeploymentPreviewProperties{
+				Changes: previewChanges(plan),
+			},
 		},
 	}, nil
 }
@@ -501,8 +520,9 @@ func (t *TerraformProvider) convertOutputs(outputMap map[string]terraformOutput)
 		}
 
 		outputParameters[k] = provisioning.OutputParameter{
-			Type:  t.mapTerraformTypeToInterfaceType(v.Type),
-			Value: v.Value,
+			Type:   t.mapTerraformTypeToInterfaceType(v.Type),
+			Value:  v.Value,
+			Secure: v.Sensitive,
 		}
 	}
 	return outputParameters
@@ -63,6 +63,41 @@ func TestTerraformPlan(t *testing.T) {
 	require.NotEmpty(t, deploymentPlan.localStateFilePath)
 }
 
+func TestTerraformPreview(t *testing.T) {
+	skipIfTerraformNotInstalled(t)
+	mockContext := mocks.NewMockContext(t.Context())
+	prepareGenericMocks(mockContext.CommandRunner)
+	preparePlanningMocks(mockContext.CommandRunner)
+
+	var planArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "terraform" && strings.Contains(command, " plan ")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		planArgs = args
+		return exec.NewRunResult(0, "Plan: 1 to add, 1 to change, 1 to destroy.", ""), nil
+	})
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "terraform" && strings.Contains(command, " show ") && strings.HasSuffix(command, ".tfplan")
+	}).Respond(exec.RunResult{
+		Stdout: terraformPlanMockOutput,
+	})
+
+	infraProvider := createTerraformProvider(t, mockContext)
+	previewResult, err := infraProvider.Preview(*mockContext.Context)
+	require.NoError(t, err)
+
+	// The plan output is captured so azd can render the changes itself.
+	require.False(t, planArgs.Interactive)
+	require.Contains(t, planArgs.Args, "-input=false")
+
+	changes := previewResult.Preview.Properties.Changes
+	require.Len(t, changes, 5)
+	require.Equal(t, provisioning.ChangeTypeModify, changes[1].ChangeType)
+	require.Equal(t, "module.web.azurerm_linux_web_app.web", changes[1].Name)
+	require.Equal(t, "azurerm_linux_web_app", changes[1].ResourceType)
+}
+
 func TestTerraformDestroy(t *testing.T) {
 	skipIfTerraformNotInstalled(t)
 	// Clear CI variables so this interactive-destroy test does not inherit the runner's CI state and take
@@ -101,8 +101,21 @@ func (p *TestProvider) Deploy(ctx context.Context) (*provisioning.DeployResult,
 func (p *TestProvider) Preview(ctx context.Context) (*provisioning.DeployPreviewResult, error) {
 	return &provisioning.DeployPreviewResult{
 		Preview: &provisioning.DeploymentPreview{
-			Status:     "Completed",
-			Properties: &provisioning.DeploymentPreviewProperties{},
+			Status: "Completed",
+			Properties: &provisioning.DeploymentPreviewProperties{
+				Changes: []*provisioning.DeploymentPreviewChange{
+					{
+						ChangeType:   provisioning.ChangeTypeModify,
+						ResourceType: "Microsoft.Web/sites",
+						Name:         "app-web",
+					},
+					{
+						ChangeType:   provisioning.ChangeTypeCreate,
+						ResourceType: "Microsoft.Authorization/roleAssignments",
+						Name:         "role-assignment",
+					},
+				},
+			},
 		},
 	}, nil
 }
@@ -0,0 +1,53 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+)
+
+// ResourceTiming is how long a single resource or module took to deploy.
+type ResourceTiming struct {
+	// Name is the resource or module name.
+	Name string
+	// Type is the Azure resource type, for example "Microsoft.Web/sites".
+	Type string
+	// Module is true when the entry is a nested deployment (a module) rather than a resource.
+	Module bool
+	// State is the terminal provisioning state, for example "Succeeded".
+	State string
+	// Duration is how long the deployment operation took.
+	Duration time.Duration
+}
+
+// SortTimings orders timings by duration, slowest first. Entries with the same duration are ordered by name.
+func SortTimings(timings []ResourceTiming) {
+	slices.SortStableFunc(timings, func(a, b ResourceTiming) int {
+		if c := cmp.Compare(b.Duration, a.Duration); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.Name, b.Name)
+	})
+}
+
+// NewEnvRefreshTimings converts timings to their JSON contract.
+func NewEnvRefreshTimings(timings []ResourceTiming) []contracts.EnvRefreshTiming {
+	result := make([]contracts.EnvRefreshTiming, len(timings))
+	for idx, timing := range timings {
+		result[idx] = contracts.EnvRefreshTiming{
+			Name:            timing.Name,
+			Type:            timing.Type,
+			Module:          timing.Module,
+			State:           timing.State,
+			DurationSeconds: timing.Duration.Seconds(),
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,41 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortTimings(t *testing.T) {
+	timings := []ResourceTiming{
+		{Name: "b", Duration: 10 * time.Second},
+		{Name: "c", Duration: time.Minute},
+		{Name: "a", Duration: 10 * time.Second},
+		{Name: "d", Duration: 500 * time.Millisecond},
+	}
+
+	SortTimings(timings)
+
+	names := []string{}
+	for _, timing := range timings {
+		names = append(names, timing.Name)
+	}
+	require.Equal(t, []string{"c", "a", "b", "d"}, names)
+}
+
+func TestNewEnvRefreshTimings(t *testing.T) {
+	timings := NewEnvRefreshTimings([]ResourceTiming{
+		{Name: "resources", Type: "Microsoft.Resources/deployments", Module: true, State: "Succeeded", Duration: 90 * time.Second},
+		{Name: "app", Type: "Microsoft.Web/sites", State: "Failed", Duration: 1500 * time.Millisecond},
+	})
+
+	require.Equal(t, []contracts.EnvRefreshTiming{
+		{Name: "resources", Type: "Microsoft.Resources/deployments", Module: true, State: "Succeeded", DurationSeconds: 90},
+		{Name: "app", Type: "Microsoft.Web/sites", State: "Failed", DurationSeconds: 1.5},
+	}, timings)
+}
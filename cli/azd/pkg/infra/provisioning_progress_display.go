@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"maps"
 	"os"
 	"slices"
 	"strconv"
@@ -34,6 +35,14 @@ type ProvisioningProgressDisplay struct {
 	terminalOperationPollCounts map[string]int
 	// The last recorded spinner message, used to avoid unnecessary updates to the spinner
 	lastSpinnerMessage string
+	// Operations observed in the Running state during the most recent poll
+	runningOperations []*armresources.DeploymentOperation
+	// Operations observed in a terminal state, including nested deployments, keyed by operation ID
+	completedOperations map[string]*armresources.DeploymentOperation
+	// Progress of the nested deployments in each resource group, for subscription scope deployments
+	resourceGroups *resourceGroupProgress
+	// The resource group of the section the last resource was displayed in, once progress is grouped
+	lastResourceGroup *string
 
 	resourceManager ResourceManager
 	console         input.Console
@@ -45,10 +54,17 @@ func NewProvisioningProgressDisplay(
 	console input.Console,
 	deployment Deployment,
 ) *ProvisioningProgressDisplay {
+	var resourceGroups *resourceGroupProgress
+	if _, ok := deployment.(*SubscriptionDeployment); ok {
+		resourceGroups = newResourceGroupProgress()
+	}
+
 	return &ProvisioningProgressDisplay{
+		resourceGroups:              resourceGroups,
 		displayedResources:          map[string]bool{},
 		resourceDisplayNames:        map[string]string{},
 		terminalOperationPollCounts: map[string]int{},
+		completedOperations:         map[string]*armresources.DeploymentOperation{},
 		deployment:                  deployment,
 		resourceManager:             rm,
 		console:                     console,
@@ -61,6 +77,53 @@ func (display *ProvisioningProgressDisplay) DisplayedResourceCount() int {
 	return len(display.displayedResources)
 }
 
+// RunningOperations returns the deployment operations that were observed in the Running state during the most
+// recent call to ReportProgress.
+func (display *ProvisioningProgressDisplay) RunningOperations() []*armresources.DeploymentOperation {
+	return display.runningOperations
+}
+
+// CompletedOperations returns the create operations, including nested deployments, that were observed in a
+// terminal state with a recorded duration since progress reporting started. The result is ordered by operation ID.
+func (display *ProvisioningProgressDisplay) CompletedOperations() []*armresources.DeploymentOperation {
+	operations := make([]*armresources.DeploymentOperation, 0, len(display.completedOperations))
+	for _, id := range slices.Sorted(maps.Keys(display.completedOperations)) {
+		operations = append(operations, display.completedOperations[id])
+	}
+
+	return operations
+}
+
+// RefreshCompletedOperations walks all the deployment operations once more and records the ones that completed
+// since queryStart. Polling stops as soon as the deployment finishes, so this picks up the operations that
+// completed after the last call to ReportProgress.
+func (display *ProvisioningProgressDisplay) RefreshCompletedOperations(
+	ctx context.Context, queryStart *time.Time) error {
+	return display.resourceManager.WalkDeploymentOperations(ctx, display.deployment,
+		func(ctx context.Context, operation *armresources.DeploymentOperation) error {
+			display.recordCompletedOperation(operation, queryStart)
+			return nil
+		})
+}
+
+// recordCompletedOperation records a create operation when it reached a terminal state after queryStart.
+func (display *ProvisioningProgressDisplay) recordCompletedOperation(
+	operation *armresources.DeploymentOperation, queryStart *time.Time) {
+	if operation.ID == nil ||
+		operation.Properties == nil ||
+		operation.Properties.Timestamp == nil ||
+		operation.Properties.Duration == nil ||
+		operation.Properties.TargetResource == nil ||
+		operation.Properties.ProvisioningOperation == nil ||
+		*operation.Properties.ProvisioningOperation != armresources.ProvisioningOperationCreate ||
+		!isTerminalProvisioningState(operation.Properties.ProvisioningState) ||
+		!operation.Properties.Timestamp.After(*queryStart) {
+		return
+	}
+
+	display.completedOperations[*operation.ID] = operation
+}
+
 // getResourceTypeDisplayName returns the display name for a resource type, using a cache to avoid repeated lookups.
 func (display *ProvisioningProgressDisplay) getResourceTypeDisplayName(
 	ctx context.Context,
@@ -140,7 +203,13 @@ func (display *ProvisioningProgressDisplay) ReportProgress(
 
 	err := display.resourceManager.WalkDeploymentOperations(ctx, display.deployment,
 		func(ctx context.Context, operation *armresources.DeploymentOperation) error {
+			display.recordCompletedOperation(operation, queryStart)
+
 			if isNestedDeployment(operation) {
+				if display.resourceGroups != nil {
+					display.resourceGroups.trackDeployment(operation)
+				}
+
 				if isTerminalProvisioningState(operation.Properties.ProvisioningState) {
 					display.terminalOperationPollCounts[*operation.ID]++
 					if display.terminalOperationPollCounts[*operation.ID] >= 2 {
@@ -197,16 +266,84 @@ func (display *ProvisioningProgressDisplay) ReportProgress(
 		return a.Properties.Timestamp.Compare(*b.Properties.Timestamp)
 	})
 
+	display.runningOperations = runningDeployments
+
 	displayedResources := append(newlyDeployedResources, newlyFailedResources...)
+	if display.groupProgress() {
+		// Display the resources of each group together, in the order the groups started.
+		slices.SortStableFunc(displayedResources, func(
+			a *armresources.DeploymentOperation,
+			b *armresources.DeploymentOperation,
+		) int {
+			return display.resourceGroups.index(resourceGroupOf(a)) - display.resourceGroups.index(resourceGroupOf(b))
+		})
+	}
+
 	display.logNewlyCreatedResources(ctx, displayedResources, runningDeployments)
 	return nil
 }
+
+// groupProgress returns whether progress is displayed per resource group, which is the case for subscription scope
+// deployments that deploy to more than one resource group.
+func (display *ProvisioningProgressDisplay) groupProgress() bool {
+	return display.resourceGroups != nil && display.resourceGroups.enabled()
+}
+
+// logResourceGroupSection displays a section header when a resource is displayed in a different resource group than
+// the previous one. Resources outside of a resource group are displayed in a subscription section.
+func (display *ProvisioningProgressDisplay) logResourceGroupSection(ctx context.Context, resourceGroup string) {
+	if display.lastResourceGroup != nil && *display.lastResourceGroup == resourceGroup {
+		return
+	}
+
+	display.lastResourceGroup = &resourceGroup
+	header := "Subscription:"
+	if resourceGroup != "" {
+		header = fmt.Sprintf("Resource group %s:", output.WithHighLightFormat(resourceGroup))
+	}
+
+	display.console.EnsureBlankLine(ctx)
+	display.console.Message(ctx, "  "+header)
+}
+
+// logFinishedResourceGroups displays a summary for each resource group whose nested deployments finished since the
+// previous poll.
+func (display *ProvisioningProgressDisplay) logFinishedResourceGroups(ctx context.Context) {
+	for _, name := range display.resourceGroups.finished() {
+		state := display.resourceGroups.group(name)
+		state.reported = true
+
+		summary := fmt.Sprintf("  Resource group %s finished: %d resource(s) created", name, state.created)
+		if state.failed > 0 {
+			summary += fmt.Sprintf(", %d failed", state.failed)
+		}
+		if state.duration > 0 {
+			summary += fmt.Sprintf(" (%s)", state.duration.Truncate(time.Millisecond))
+		}
+
+		log.Println(strings.TrimSpace(summary))
+		display.console.Message(ctx, output.WithGrayFormat(summary))
+	}
+}
 func (display *ProvisioningProgressDisplay) logNewlyCreatedResources(
 	ctx context.Context,
 	resources []*armresources.DeploymentOperation,
 	inProgressResources []*armresources.DeploymentOperation,
 ) {
+	grouped := display.groupProgress()
 	for _, resource := range resources {
+		if grouped {
+			resourceGroup := resourceGroupOf(resource)
+			display.logResourceGroupSection(ctx, resourceGroup)
+			if resourceGroup != "" {
+				if *resource.Properties.ProvisioningState == string(armresources.ProvisioningStateFailed) {
+					display.resourceGroups.group(resourceGroup).failed++
+				} else {
+					display.resourceGroups.group(resourceGroup).created++
+				}
+			}
+		}
+
 		resourceTypeName := *resource.Properties.TargetResource.ResourceType
 		resourceTypeDisplayName := display.getResourceTypeDisplayName(
 			ctx,
@@ -215,8 +352,13 @@ func (display *ProvisioningProgressDisplay) logNewlyCreatedResources(
 			*resource.Properties.TargetResource.ID,
 		)
 
-		// Don't log resource types for Azure resources that we do not have a translation of the resource type for.
+		// Don't log resource types for Azure resources that we do not have a translation of the resource type for,
+		// unless the output is verbose, where they're displayed by their resource type.
 		// This will be improved on in a future iteration.
+		if resourceTypeDisplayName == "" && display.console.Verbosity() >= input.VerbosityVerbose {
+			resourceTypeDisplayName = resourceTypeName
+		}
+
 		if resourceTypeDisplayName != "" {
 			duration, err := convert.ParseDuration(*resource.Properties.Duration)
 			if err != nil {
@@ -251,6 +393,10 @@ func (display *ProvisioningProgressDisplay) logNewlyCreatedResources(
 
 		display.displayedResources[dedupKey] = true
 	}
+	if grouped {
+		display.resourceGroups.resetRunning()
+	}
+
 	// update progress
 	inProgress := []string{}
 	for _, inProgResource := range inProgressResources {
@@ -266,9 +412,17 @@ func (display *ProvisioningProgressDisplay) logNewlyCreatedResources(
 		// This will be improved on in a future iteration.
 		if resourceTypeDisplayName != "" {
 			inProgress = append(inProgress, resourceTypeDisplayName)
+			if resourceGroup := resourceGroupOf(inProgResource); grouped && resourceGroup != "" {
+				state := display.resourceGroups.group(resourceGroup)
+				state.running = append(state.running, resourceTypeDisplayName)
+			}
 		}
 	}
 
+	if grouped {
+		display.logFinishedResourceGroups(ctx)
+	}
+
 	if !display.console.IsSpinnerInteractive() {
 		// If non-interactive, we simply do not want to display spinner messages that ends up
 		// being individual lines of messages on the console
@@ -279,7 +433,11 @@ func (display *ProvisioningProgressDisplay) logNewlyCreatedResources(
 	slices.Sort(inProgress)
 
 	message := "Creating/Updating resources"
-	if len(inProgress) > 0 {
+	if grouped {
+		if sections := display.resourceGroups.spinnerMessage(); sections != "" {
+			message = fmt.Sprintf("%s (%s)", message, sections)
+		}
+	} else if len(inProgress) > 0 {
 		message = fmt.Sprintf("%s (%s)", message, strings.Join(inProgress, ", "))
 	}
 
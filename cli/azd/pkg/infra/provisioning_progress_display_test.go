@@ -17,6 +17,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazapi"
 	"github.com/stretchr/testify/assert"
@@ -261,3 +262,226 @@ func TestReportProgressSkipsExpansionAfterTwoTerminalPolls(t *testing.T) {
 
 	require.Equal(t, 1, walkRm.childVisits)
 }
+
+func TestRefreshCompletedOperations(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	deploymentService := mockazapi.NewDeploymentsServiceFromMockContext(mockContext)
+
+	scope := newSubscriptionScope(deploymentService, "SUBSCRIPTION_ID", "eastus2")
+	deployment := NewSubscriptionDeployment(scope, "DEPLOYMENT_NAME")
+
+	startTime := time.Now().Add(-time.Minute)
+	operation := func(
+		id string,
+		kind armresources.ProvisioningOperation,
+		state armresources.ProvisioningState,
+		resourceType azapi.AzureResourceType,
+		timestamp time.Time,
+	) *armresources.DeploymentOperation {
+		return &armresources.DeploymentOperation{
+			ID: new(id),
+			Properties: &armresources.DeploymentOperationProperties{
+				ProvisioningOperation: to.Ptr(kind),
+				ProvisioningState:     to.Ptr(string(state)),
+				Duration:              new("PT12.5S"),
+				TargetResource: &armresources.TargetResource{
+					ResourceType: to.Ptr(string(resourceType)),
+					ID:           new("resource-" + id),
+					ResourceName: new("name-" + id),
+				},
+				Timestamp: new(timestamp),
+			},
+		}
+	}
+
+	now := time.Now().UTC()
+	mockResourceManager := mockResourceManager{
+		operations: []*armresources.DeploymentOperation{
+			operation("module", armresources.ProvisioningOperationCreate, armresources.ProvisioningStateSucceeded,
+				azapi.AzureResourceTypeDeployment, now),
+			operation("site", armresources.ProvisioningOperationCreate, armresources.ProvisioningStateSucceeded,
+				azapi.AzureResourceTypeWebSite, now),
+			operation("vault", armresources.ProvisioningOperationCreate, armresources.ProvisioningStateFailed,
+				azapi.AzureResourceTypeKeyVault, now),
+			operation("running", armresources.ProvisioningOperationCreate, armresources.ProvisioningStateRunning,
+				azapi.AzureResourceTypeWebSite, now),
+			operation("existing", armresources.ProvisioningOperationRead, armresources.ProvisioningStateSucceeded,
+				azapi.AzureResourceTypeStorageAccount, now),
+			operation("previous", armresources.ProvisioningOperationCreate, armresources.ProvisioningStateSucceeded,
+				azapi.AzureResourceTypeWebSite, startTime.Add(-time.Hour)),
+		},
+	}
+
+	progressDisplay := NewProvisioningProgressDisplay(&mockResourceManager, mockContext.Console, deployment)
+	require.Empty(t, progressDisplay.CompletedOperations())
+
+	err := progressDisplay.RefreshCompletedOperations(*mockContext.Context, &startTime)
+	require.NoError(t, err)
+
+	ids := []string{}
+	for _, operation := range progressDisplay.CompletedOperations() {
+		ids = append(ids, *operation.ID)
+	}
+	require.Equal(t, []string{"module", "site", "vault"}, ids)
+}
+
+func newResourceGroupOperation(
+	id string, resourceGroup string, resourceType azapi.AzureResourceType, name string, state string, timestamp time.Time,
+) *armresources.DeploymentOperation {
+	return &armresources.DeploymentOperation{
+		ID: new(id),
+		Properties: &armresources.DeploymentOperationProperties{
+			ProvisioningOperation: to.Ptr(armresources.ProvisioningOperationCreate),
+			ProvisioningState:     new(state),
+			Duration:              new("PT1S"),
+			TargetResource: &armresources.TargetResource{
+				ResourceType: to.Ptr(string(resourceType)),
+				ID: new(fmt.Sprintf("/subscriptions/SUBSCRIPTION_ID/resourceGroups/%s/providers/%s/%s",
+					resourceGroup, resourceType, name)),
+				ResourceName: new(name),
+			},
+			Timestamp: new(timestamp),
+		},
+	}
+}
+
+func TestReportProgressGroupsResourceGroups(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	mockContext.Console.SetTerminal(true)
+	deploymentService := mockazapi.NewDeploymentsServiceFromMockContext(mockContext)
+
+	scope := newSubscriptionScope(deploymentService, "SUBSCRIPTION_ID", "eastus2")
+	deployment := NewSubscriptionDeployment(scope, "DEPLOYMENT_NAME")
+	mockAzDeploymentShow(t, *mockContext)
+
+	startTime := time.Now().Add(-time.Minute)
+	running := string(armresources.ProvisioningStateRunning)
+	succeeded := string(armresources.ProvisioningStateSucceeded)
+	apiDeployment := newResourceGroupOperation(
+		"api-deployment", "rg-api", azapi.AzureResourceTypeDeployment, "api", running, startTime)
+	dataDeployment := newResourceGroupOperation(
+		"data-deployment", "rg-data", azapi.AzureResourceTypeDeployment, "data", running, startTime)
+	rm := &mockResourceManager{operations: []*armresources.DeploymentOperation{
+		apiDeployment,
+		dataDeployment,
+		newResourceGroupOperation(
+			"site", "rg-api", azapi.AzureResourceTypeWebSite, "site", succeeded, startTime.Add(time.Second)),
+		newResourceGroupOperation(
+			"storage", "rg-data", azapi.AzureResourceTypeStorageAccount, "storage", succeeded, startTime.Add(2*time.Second)),
+		newResourceGroupOperation(
+			"plan", "rg-api", azapi.AzureResourceTypeServicePlan, "plan", succeeded, startTime.Add(3*time.Second)),
+		newResourceGroupOperation(
+			"vault", "rg-data", azapi.AzureResourceTypeKeyVault, "vault", running, startTime.Add(4*time.Second)),
+	}}
+
+	progressDisplay := NewProvisioningProgressDisplay(rm, mockContext.Console, deployment)
+	err := progressDisplay.ReportProgress(*mockContext.Context, &startTime)
+	require.NoError(t, err)
+
+	// The resources of each group are displayed together, under a section for the group.
+	displayed := mockContext.Console.Output()[1:]
+	require.Len(t, displayed, 5)
+	assert.Contains(t, displayed[0], "Resource group rg-api:")
+	assert.Contains(t, displayed[1], "site")
+	assert.Contains(t, displayed[2], "plan")
+	assert.Contains(t, displayed[3], "Resource group rg-data:")
+	assert.Contains(t, displayed[4], "storage")
+
+	spinnerOps := mockContext.Console.SpinnerOps()
+	require.NotEmpty(t, spinnerOps)
+	assert.Equal(t,
+		fmt.Sprintf("Creating/Updating resources (rg-api: waiting; rg-data: %s)", azapi.AzureResourceTypeKeyVault),
+		spinnerOps[len(spinnerOps)-1].Message)
+
+	apiDeployment.Properties.ProvisioningState = new(succeeded)
+	apiDeployment.Properties.Duration = new("PT2M")
+	err = progressDisplay.ReportProgress(*mockContext.Context, &startTime)
+	require.NoError(t, err)
+
+	// A finished group is summarized once, and no longer shown by the spinner.
+	displayed = mockContext.Console.Output()[6:]
+	require.Len(t, displayed, 1)
+	assert.Contains(t, displayed[0], "Resource group rg-api finished: 2 resource(s) created (2m0s)")
+
+	spinnerOps = mockContext.Console.SpinnerOps()
+	assert.Equal(t,
+		fmt.Sprintf("Creating/Updating resources (rg-data: %s)", azapi.AzureResourceTypeKeyVault),
+		spinnerOps[len(spinnerOps)-1].Message)
+
+	err = progressDisplay.ReportProgress(*mockContext.Context, &startTime)
+	require.NoError(t, err)
+	assert.Len(t, mockContext.Console.Output(), 7)
+}
+
+func TestReportProgressSingleResourceGroup(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	deploymentService := mockazapi.NewDeploymentsServiceFromMockContext(mockContext)
+
+	scope := newSubscriptionScope(deploymentService, "SUBSCRIPTION_ID", "eastus2")
+	deployment := NewSubscriptionDeployment(scope, "DEPLOYMENT_NAME")
+	mockAzDeploymentShow(t, *mockContext)
+
+	startTime := time.Now().Add(-time.Minute)
+	succeeded := string(armresources.ProvisioningStateSucceeded)
+	rm := &mockResourceManager{operations: []*armresources.DeploymentOperation{
+		newResourceGroupOperation(
+			"api-deployment", "rg-api", azapi.AzureResourceTypeDeployment, "api", succeeded, startTime),
+		newResourceGroupOperation(
+			"site", "rg-api", azapi.AzureResourceTypeWebSite, "site", succeeded, startTime.Add(time.Second)),
+	}}
+
+	progressDisplay := NewProvisioningProgressDisplay(rm, mockContext.Console, deployment)
+	err := progressDisplay.ReportProgress(*mockContext.Context, &startTime)
+	require.NoError(t, err)
+
+	// Progress isn't grouped when the deployment targets a single resource group.
+	displayed := mockContext.Console.Output()[1:]
+	require.Len(t, displayed, 1)
+	assert.Contains(t, displayed[0], "site")
+}
+
+// untranslatedResourceManager is a resource manager without display names for resource types.
+type untranslatedResourceManager struct {
+	mockResourceManager
+}
+
+func (mock *untranslatedResourceManager) GetResourceTypeDisplayName(
+	ctx context.Context,
+	subscriptionId string,
+	resourceId string,
+	resourceType azapi.AzureResourceType,
+) (string, error) {
+	return "", nil
+}
+
+func TestReportProgressVerbose(t *testing.T) {
+	for _, verbosity := range []input.Verbosity{input.VerbosityNormal, input.VerbosityVerbose} {
+		mockContext := mocks.NewMockContext(t.Context())
+		mockContext.Console.SetVerbosity(verbosity)
+		deploymentService := mockazapi.NewDeploymentsServiceFromMockContext(mockContext)
+
+		scope := newSubscriptionScope(deploymentService, "SUBSCRIPTION_ID", "eastus2")
+		deployment := NewSubscriptionDeployment(scope, "DEPLOYMENT_NAME")
+		mockAzDeploymentShow(t, *mockContext)
+
+		startTime := time.Now().Add(-time.Minute)
+		succeeded := string(armresources.ProvisioningStateSucceeded)
+		rm := &untranslatedResourceManager{mockResourceManager{operations: []*armresources.DeploymentOperation{
+			newResourceGroupOperation(
+				"widget", "rg-api", "Microsoft.Contoso/widgets", "widget", succeeded, startTime.Add(time.Second)),
+		}}}
+
+		progressDisplay := NewProvisioningProgressDisplay(rm, mockContext.Console, deployment)
+		err := progressDisplay.ReportProgress(*mockContext.Context, &startTime)
+		require.NoError(t, err)
+
+		// Resources without a display name for their type are only shown, by their type, in verbose output.
+		displayed := mockContext.Console.Output()[1:]
+		if verbosity == input.VerbosityVerbose {
+			require.Len(t, displayed, 1)
+			assert.Contains(t, displayed[0], "Microsoft.Contoso/widgets: widget")
+		} else {
+			require.Empty(t, displayed)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package infra
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+)
+
+// resourceGroupProgress plans how the progress of a subscription scope deployment is reported when it deploys to more
+// than one resource group. ARM runs the nested deployments of independent resource groups at the same time, so their
+// resources complete interleaved. Grouping the progress by resource group keeps clear which group is doing what.
+//
+// The groups are discovered from the nested deployment operations, in the order they start. A group is finished when
+// every nested deployment observed for it reached a terminal state.
+type resourceGroupProgress struct {
+	// order is the names of the resource groups, in the order their first nested deployment was observed.
+	order  []string
+	groups map[string]*resourceGroupState
+}
+
+// resourceGroupState is the progress of the nested deployments that target a resource group.
+type resourceGroupState struct {
+	// deployments are the provisioning states of the nested deployments targeting the group, keyed by operation ID.
+	deployments map[string]string
+	// duration is the longest duration of the nested deployments targeting the group.
+	duration time.Duration
+	// created and failed are the counts of resources reported as created or failed in the group.
+	created int
+	failed  int
+	// running are the display names of the resources being created in the group during the most recent poll.
+	running []string
+	// reported is whether the group was reported as finished.
+	reported bool
+}
+
+func newResourceGroupProgress() *resourceGroupProgress {
+	return &resourceGroupProgress{groups: map[string]*resourceGroupState{}}
+}
+
+// enabled returns whether the deployment targets more than one resource group, so progress is grouped.
+func (p *resourceGroupProgress) enabled() bool {
+	return len(p.order) > 1
+}
+
+// group returns the state of the named resource group, adding it when it wasn't observed before.
+func (p *resourceGroupProgress) group(name string) *resourceGroupState {
+	state, has := p.groups[name]
+	if !has {
+		state = &resourceGroupState{deployments: map[string]string{}}
+		p.groups[name] = state
+		p.order = append(p.order, name)
+	}
+
+	return state
+}
+
+// trackDeployment records the state of a nested deployment operation.
+func (p *resourceGroupProgress) trackDeployment(operation *armresources.DeploymentOperation) {
+	name := resourceGroupOf(operation)
+	if name == "" || operation.ID == nil || operation.Properties.ProvisioningState == nil {
+		return
+	}
+
+	state := p.group(name)
+	state.deployments[*operation.ID] = *operation.Properties.ProvisioningState
+	if operation.Properties.Duration != nil {
+		if duration, err := convert.ParseDuration(*operation.Properties.Duration); err == nil && duration > state.duration {
+			state.duration = duration
+		}
+	}
+}
+
+// resetRunning clears the resources being created in each group, before a poll records them again.
+func (p *resourceGroupProgress) resetRunning() {
+	for _, state := range p.groups {
+		state.running = nil
+	}
+}
+
+// index returns the position of a resource group in the order groups were observed. Resources outside of a resource
+// group come first.
+func (p *resourceGroupProgress) index(name string) int {
+	if name == "" {
+		return -1
+	}
+
+	if i := slices.Index(p.order, name); i >= 0 {
+		return i
+	}
+
+	return len(p.order)
+}
+
+// finished returns the groups whose nested deployments all reached a terminal state and that weren't reported as
+// finished yet, in the order they were observed.
+func (p *resourceGroupProgress) finished() []string {
+	var names []string
+	for _, name := range p.order {
+		state := p.groups[name]
+		if state.reported || len(state.deployments) == 0 {
+			continue
+		}
+
+		done := true
+		for _, provisioningState := range state.deployments {
+			done = done && isTerminalProvisioningState(&provisioningState)
+		}
+
+		if done {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// spinnerMessage describes the resources being created in each group that isn't finished, such as
+// "rg-api: Key Vault, Web App; rg-data: Storage account".
+func (p *resourceGroupProgress) spinnerMessage() string {
+	var sections []string
+	for _, name := range p.order {
+		state := p.groups[name]
+		if state.reported {
+			continue
+		}
+
+		running := slices.Clone(state.running)
+		slices.Sort(running)
+		running = slices.Compact(running)
+		if len(running) == 0 {
+			sections = append(sections, fmt.Sprintf("%s: waiting", name))
+			continue
+		}
+
+		sections = append(sections, fmt.Sprintf("%s: %s", name, strings.Join(running, ", ")))
+	}
+
+	return strings.Join(sections, "; ")
+}
+
+// resourceGroupOf returns the name of the resource group targeted by an operation, or an empty string when the target
+// isn't in a resource group.
+func resourceGroupOf(operation *armresources.DeploymentOperation) string {
+	if operation.Properties == nil ||
+		operation.Properties.TargetResource == nil ||
+		operation.Properties.TargetResource.ID == nil {
+		return ""
+	}
+
+	resourceId, err := arm.ParseResourceID(*operation.Properties.TargetResource.ID)
+	if err != nil {
+		return ""
+	}
+
+	return resourceId.ResourceGroupName
+}
@@ -46,3 +46,9 @@ func ResourceIdName(name string) string {
 func KeyVaultName(env *environment.Environment) string {
 	return env.Getenv("AZURE_KEY_VAULT_NAME")
 }
+
+// AppConfigurationEndpoint returns the endpoint of the App Configuration store that holds the feature flags of the
+// environment.
+func AppConfigurationEndpoint(env *environment.Environment) string {
+	return env.Getenv("AZURE_APPCONFIGURATION_ENDPOINT")
+}
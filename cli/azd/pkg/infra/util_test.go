@@ -134,3 +134,17 @@ func TestKeyVaultName(t *testing.T) {
 		assert.Equal(t, "", KeyVaultName(env))
 	})
 }
+
+func TestAppConfigurationEndpoint(t *testing.T) {
+	t.Run("returns value when set", func(t *testing.T) {
+		env := environment.NewWithValues("test", map[string]string{
+			"AZURE_APPCONFIGURATION_ENDPOINT": "https://appcs-test.azconfig.io",
+		})
+		assert.Equal(t, "https://appcs-test.azconfig.io", AppConfigurationEndpoint(env))
+	})
+
+	t.Run("returns empty string when not set", func(t *testing.T) {
+		env := environment.New("test")
+		assert.Equal(t, "", AppConfigurationEndpoint(env))
+	})
+}
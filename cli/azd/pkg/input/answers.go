@@ -0,0 +1,312 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package input
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/braydonk/yaml"
+)
+
+// AnswersFileEnvVarName is the environment variable with the path of the answers file, used when `--answers` isn't set.
+const AnswersFileEnvVarName = "AZD_ANSWERS_FILE"
+
+// Answers are predefined answers to prompts, loaded from an answers file (`azd --answers <file>`), which let commands run
+// without a terminal. A prompt is answered by its [ConsoleOptions.Key], like `subscription` or `parameters.<name>`, or
+// else by its message.
+//
+// Each prompt is answered once: when the same prompt is shown again, for example because the answer was rejected by a
+// validation, it is prompted for, or fails in no-prompt mode.
+type Answers struct {
+	path   string
+	values map[string]any
+
+	mu   sync.Mutex
+	used map[string]bool
+}
+
+// LoadAnswers loads the answers file at path, a YAML (or JSON) object mapping prompt keys to answers.
+func LoadAnswers(path string) (*Answers, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading answers file: %w", err)
+	}
+
+	values := map[string]any{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("parsing answers file %s: %w", path, err)
+	}
+
+	answers := NewAnswers(values)
+	answers.path = path
+
+	return answers, nil
+}
+
+// NewAnswers creates answers from values, mapping prompt keys to answers. Nested maps answer the prompts with dotted
+// keys, like `parameters.<name>`.
+func NewAnswers(values map[string]any) *Answers {
+	return &Answers{
+		values: values,
+		used:   map[string]bool{},
+	}
+}
+
+// HasAnswer reports whether console answers the prompt with the given options from an answers file. Code that skips a
+// prompt in no-prompt mode, like using a default instead, checks it first so that the answer is used.
+func HasAnswer(console Console, options ConsoleOptions) bool {
+	answers, ok := console.(*answersConsole)
+	if !ok {
+		return false
+	}
+
+	_, _, has := answers.answers.find(options, false)
+	return has
+}
+
+// lookup returns the answer to the prompt with the given options, and the key it was found with, marking it as used.
+func (a *Answers) lookup(options ConsoleOptions) (string, any, bool) {
+	return a.find(options, true)
+}
+
+// find returns the answer to the prompt with the given options, and the key it was found with. When use is set, the
+// answer is marked as used.
+func (a *Answers) find(options ConsoleOptions, use bool) (string, any, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	prompt := options.Key + "\x00" + options.Message
+	if a.used[prompt] {
+		return "", nil, false
+	}
+
+	if options.Key != "" {
+		if value, has := a.value(options.Key); has {
+			a.used[prompt] = use
+			return options.Key, value, true
+		}
+	}
+
+	message := strings.TrimSpace(options.Message)
+	if value, has := a.values[message]; has && message != "" {
+		a.used[prompt] = use
+		return message, value, true
+	}
+
+	return "", nil, false
+}
+
+// value returns the answer with the given key, either a top-level key or a dotted path through nested maps.
+func (a *Answers) value(key string) (any, bool) {
+	if value, has := a.values[key]; has {
+		return value, true
+	}
+
+	var current any = a.values
+	for segment := range strings.SplitSeq(key, ".") {
+		values, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		if current, ok = values[segment]; !ok {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+// invalid returns the error for an answer that can't be used for its prompt.
+func (a *Answers) invalid(key string, err error) error {
+	source := "the answers file"
+	if a.path != "" {
+		source = a.path
+	}
+
+	return fmt.Errorf("invalid answer for '%s' in %s: %w", key, source, err)
+}
+
+// answersConsole is a [Console] that answers prompts from [Answers], and shows the other prompts as usual.
+type answersConsole struct {
+	consoleDecorator
+	answers *Answers
+}
+
+// NewAnswersConsole returns a console that answers prompts from answers before prompting with console.
+func NewAnswersConsole(console Console, answers *Answers) Console {
+	return &answersConsole{
+		consoleDecorator: consoleDecorator{console},
+		answers:          answers,
+	}
+}
+
+func (c *answersConsole) Prompt(ctx context.Context, options ConsoleOptions) (string, error) {
+	key, value, has := c.answers.lookup(options)
+	if !has {
+		return c.Console.Prompt(ctx, options)
+	}
+
+	log.Printf("answering prompt '%s' from the answers file", key)
+	answer, err := answerString(value)
+	if err != nil {
+		return "", c.answers.invalid(key, err)
+	}
+
+	return answer, nil
+}
+
+func (c *answersConsole) PromptFs(ctx context.Context, options ConsoleOptions, fsOptions FsOptions) (string, error) {
+	key, value, has := c.answers.lookup(options)
+	if !has {
+		return c.Console.PromptFs(ctx, options, fsOptions)
+	}
+
+	log.Printf("answering prompt '%s' from the answers file", key)
+	answer, err := answerString(value)
+	if err != nil {
+		return "", c.answers.invalid(key, err)
+	}
+
+	return answer, nil
+}
+
+func (c *answersConsole) Select(ctx context.Context, options ConsoleOptions) (int, error) {
+	key, value, has := c.answers.lookup(options)
+	if !has {
+		return c.Console.Select(ctx, options)
+	}
+
+	log.Printf("answering prompt '%s' from the answers file", key)
+	answer, err := answerString(value)
+	if err != nil {
+		return -1, c.answers.invalid(key, err)
+	}
+
+	index, err := answerOption(options.Options, answer)
+	if err != nil {
+		return -1, c.answers.invalid(key, err)
+	}
+
+	return index, nil
+}
+
+func (c *answersConsole) MultiSelect(ctx context.Context, options ConsoleOptions) ([]string, error) {
+	key, value, has := c.answers.lookup(options)
+	if !has {
+		return c.Console.MultiSelect(ctx, options)
+	}
+
+	log.Printf("answering prompt '%s' from the answers file", key)
+	values, ok := value.([]any)
+	if !ok {
+		values = []any{value}
+	}
+
+	selected := []string{}
+	for _, value := range values {
+		answer, err := answerString(value)
+		if err != nil {
+			return nil, c.answers.invalid(key, err)
+		}
+
+		index, err := answerOption(options.Options, answer)
+		if err != nil {
+			return nil, c.answers.invalid(key, err)
+		}
+
+		selected = append(selected, options.Options[index])
+	}
+
+	return selected, nil
+}
+
+func (c *answersConsole) Confirm(ctx context.Context, options ConsoleOptions) (bool, error) {
+	key, value, has := c.answers.lookup(options)
+	if !has {
+		return c.Console.Confirm(ctx, options)
+	}
+
+	log.Printf("answering prompt '%s' from the answers file", key)
+	switch value := value.(type) {
+	case bool:
+		return value, nil
+	case string:
+		confirmed, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, c.answers.invalid(key, fmt.Errorf("'%s' is not a boolean, use true or false", value))
+		}
+
+		return confirmed, nil
+	default:
+		return false, c.answers.invalid(key, fmt.Errorf("'%v' is not a boolean, use true or false", value))
+	}
+}
+
+// answerString returns the text of an answer. Lists and objects, like the values of array and object parameters, are
+// returned as JSON.
+func answerString(value any) (string, error) {
+	switch value := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return value, nil
+	case []any, map[string]any:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+
+		return string(data), nil
+	default:
+		return fmt.Sprint(value), nil
+	}
+}
+
+// optionNumberRegex matches the number prefix of numbered options, like ` 1. `.
+var optionNumberRegex = regexp.MustCompile(`^\s*\d+\.\s+`)
+
+// answerOption returns the index of the option answered by answer, ignoring case. Besides the option itself, the
+// answer can be the option without its number prefix, the text before its parenthesized suffix, or that suffix, like
+// `West US 2` or `westus2` for the option ` 3. West US 2 (westus2)`.
+func answerOption(options []string, answer string) (int, error) {
+	answer = strings.TrimSpace(answer)
+
+	matches := []int{}
+	for i, option := range options {
+		if strings.EqualFold(strings.TrimSpace(option), answer) {
+			return i, nil
+		}
+
+		name := optionNumberRegex.ReplaceAllString(strings.TrimSpace(option), "")
+		forms := []string{name}
+		if open := strings.LastIndex(name, " ("); open >= 0 && strings.HasSuffix(name, ")") {
+			forms = append(forms, name[:open], name[open+2:len(name)-1])
+		}
+
+		for _, form := range forms {
+			if strings.EqualFold(form, answer) {
+				matches = append(matches, i)
+				break
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		return -1, fmt.Errorf("'%s' is not one of the options: %s", answer, strings.Join(options, ", "))
+	default:
+		return -1, fmt.Errorf("'%s' matches several options, use one of: %s", answer, strings.Join(options, ", "))
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadAnswers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "answers.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+subscription: My Subscription
+location: westus2
+parameters:
+  vmSize: Standard_B2s
+  tags:
+    team: web
+"Do you want to continue?": true
+`), 0600))
+
+	answers, err := LoadAnswers(path)
+	require.NoError(t, err)
+
+	key, value, has := answers.lookup(ConsoleOptions{Key: "parameters.vmSize", Message: "Enter a value"})
+	require.True(t, has)
+	require.Equal(t, "parameters.vmSize", key)
+	require.Equal(t, "Standard_B2s", value)
+
+	key, value, has = answers.lookup(ConsoleOptions{Message: " Do you want to continue? "})
+	require.True(t, has)
+	require.Equal(t, "Do you want to continue?", key)
+	require.Equal(t, true, value)
+
+	_, _, has = answers.lookup(ConsoleOptions{Key: "parameters.missing", Message: "Enter a value"})
+	require.False(t, has)
+
+	_, err = LoadAnswers(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Error(t, err)
+}
+
+func TestAnswersConsole(t *testing.T) {
+	console, _ := newTestConsole(t, true, nil)
+	answers := NewAnswers(map[string]any{
+		"environment":  "dev",
+		"subscription": "00000000-0000-0000-0000-000000000001",
+		"location":     "West US 2",
+		"parameters": map[string]any{
+			"zones":   []any{"1", "2"},
+			"enabled": true,
+		},
+		"services":  []any{"api", "web"},
+		"Continue?": "yes",
+	})
+	c := NewAnswersConsole(console, answers)
+	ctx := t.Context()
+
+	value, err := c.Prompt(ctx, ConsoleOptions{Key: "environment", Message: "Enter a unique environment name"})
+	require.NoError(t, err)
+	require.Equal(t, "dev", value)
+
+	// Each prompt is answered once, the console prompts when it's shown again. This console is in no-prompt mode.
+	_, err = c.Prompt(ctx, ConsoleOptions{Key: "environment", Message: "Enter a unique environment name"})
+	require.Error(t, err)
+
+	value, err = c.Prompt(ctx, ConsoleOptions{Key: "parameters.zones", Message: "Enter a value for zones"})
+	require.NoError(t, err)
+	require.Equal(t, `["1","2"]`, value)
+
+	index, err := c.Select(ctx, ConsoleOptions{
+		Key:     "subscription",
+		Message: "Select a subscription",
+		Options: []string{
+			" 1. Dev (00000000-0000-0000-0000-000000000000)",
+			" 2. Test (00000000-0000-0000-0000-000000000001)",
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, index)
+
+	index, err = c.Select(ctx, ConsoleOptions{
+		Key:     "location",
+		Message: "Select a location",
+		Options: []string{" 1. West US (westus)", " 2. West US 2 (westus2)"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, index)
+
+	index, err = c.Select(ctx, ConsoleOptions{
+		Key:     "parameters.enabled",
+		Message: "Enter a value for enabled",
+		Options: []string{"False", "True"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, index)
+
+	selected, err := c.MultiSelect(ctx, ConsoleOptions{
+		Key:     "services",
+		Message: "Select the services to deploy",
+		Options: []string{"api", "web", "worker"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"api", "web"}, selected)
+
+	_, err = c.Confirm(ctx, ConsoleOptions{Message: "Continue?"})
+	require.ErrorContains(t, err, "invalid answer for 'Continue?'")
+
+	// Prompts without an answer are shown by the console, which takes the default in no-prompt mode.
+	confirmed, err := c.Confirm(ctx, ConsoleOptions{Message: "Delete?", DefaultValue: true})
+	require.NoError(t, err)
+	require.True(t, confirmed)
+}
+
+func TestAnswerOption(t *testing.T) {
+	options := []string{" 1. East US (eastus)", " 2. East US 2 (eastus2)", " 3. eastus"}
+
+	index, err := answerOption(options, "EASTUS2")
+	require.NoError(t, err)
+	require.Equal(t, 1, index)
+
+	index, err = answerOption(options, " 1. East US (eastus)")
+	require.NoError(t, err)
+	require.Equal(t, 0, index)
+
+	_, err = answerOption(options, "eastus")
+	require.ErrorContains(t, err, "matches several options")
+
+	_, err = answerOption(options, "westus")
+	require.ErrorContains(t, err, "'westus' is not one of the options")
+}
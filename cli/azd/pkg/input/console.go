@@ -29,6 +29,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/redact"
 	tm "github.com/buger/goterm"
 	"github.com/nathan-fiscaletti/consolesize-go"
 	"github.com/theckman/yacspin"
@@ -70,6 +71,24 @@ type PreviewerPauser interface {
 	ResumePreviewer()
 }
 
+// consoleDecorator is embedded by the consoles that decorate another console, like the answers console. Besides the
+// methods of [Console], it forwards the optional interfaces of the decorated console, like [PreviewerPauser].
+type consoleDecorator struct {
+	Console
+}
+
+func (c consoleDecorator) PausePreviewer() {
+	if pauser, ok := c.Console.(PreviewerPauser); ok {
+		pauser.PausePreviewer()
+	}
+}
+
+func (c consoleDecorator) ResumePreviewer() {
+	if pauser, ok := c.Console.(PreviewerPauser); ok {
+		pauser.ResumePreviewer()
+	}
+}
+
 type PromptDialog struct {
 	Title       string
 	Description string
@@ -118,6 +137,9 @@ type Console interface {
 	IsSpinnerInteractive() bool
 	// IsNoPromptMode returns true when --no-prompt is active and interactive prompts are disabled.
 	IsNoPromptMode() bool
+	// Verbosity returns how much output the console writes. Output written with a context from [WithOutputVerbosity]
+	// is only written at that verbosity or above.
+	Verbosity() Verbosity
 	SupportsPromptDialog() bool
 	PromptDialog(ctx context.Context, dialog PromptDialog) (map[string]any, error)
 	// Prompts the user for a single value
@@ -166,6 +188,13 @@ type AskerConsole struct {
 	promptClient *externalPromptClient
 	// noPromptDialog when true, disables SupportsPromptDialog() even when promptClient is set.
 	noPromptDialog bool
+	// accessible when true, replaces the spinner and the previewer with plain, timestamped status lines, which don't move
+	// the cursor or redraw the screen. See EnableAccessibleMode.
+	accessible bool
+	// accessibleSpinnerRunning tracks whether a spinner is shown as a status line in accessible mode.
+	accessibleSpinnerRunning bool
+	// verbosity is how much output the console writes. See SetVerbosity.
+	verbosity Verbosity
 
 	showProgressMu sync.Mutex // ensures atomicity when swapping the current progress renderer (spinner or previewer)
 
@@ -188,6 +217,10 @@ type AskerConsole struct {
 }
 
 type ConsoleOptions struct {
+	// Key identifies the prompt in answers files (`azd --answers`), like `subscription`. Prompts without a key are
+	// answered by their message.
+	Key string
+
 	Message string
 	Help    string
 	Options []string
@@ -198,6 +231,12 @@ type ConsoleOptions struct {
 
 	// Prompt-only options
 	IsPassword bool
+
+	// Select-only options
+
+	// FilterHint is an optional hint displayed in the filter of interactive selections until the user types, like what
+	// the options can be filtered by. Options are filtered by fuzzy matching what the user types.
+	FilterHint string
 }
 
 type ConsoleHandles struct {
@@ -226,6 +265,10 @@ func (c *AskerConsole) IsUnformatted() bool {
 
 // Prints out a message to the underlying console write
 func (c *AskerConsole) Message(ctx context.Context, message string) {
+	if !c.writes(ctx) {
+		return
+	}
+
 	// In JSON mode, emit structured event output instead of plain text.
 	if c.formatter != nil && c.formatter.Kind() == output.JsonFormat {
 		// Empty messages are visual separators (blank lines) in text mode.
@@ -294,6 +337,10 @@ func shouldWarn() bool {
 }
 
 func (c *AskerConsole) MessageUxItem(ctx context.Context, item ux.UxItem) {
+	if !c.writes(ctx) {
+		return
+	}
+
 	if c.formatter != nil && c.formatter.Kind() == output.JsonFormat {
 		// no need to check the spinner for json format, as the spinner won't start when using json format
 		// instead, there would be a message about starting spinner
@@ -347,6 +394,15 @@ func (c *AskerConsole) ShowPreviewer(ctx context.Context, options *ShowPreviewer
 		return io.Discard
 	}
 
+	if !c.writes(ctx) {
+		return io.Discard
+	}
+
+	if c.accessible || c.verbosity >= VerbosityVerbose {
+		// The output is written as is, instead of redrawing its last lines.
+		return c.writer
+	}
+
 	c.showProgressMu.Lock()
 	defer c.showProgressMu.Unlock()
 
@@ -489,6 +545,10 @@ func (c *AskerConsole) ShowSpinner(ctx context.Context, title string, format Spi
 		return
 	}
 
+	if !c.writes(ctx) {
+		return
+	}
+
 	if p := c.previewer.Load(); p != nil {
 		// spinner is not compatible with previewer.
 		p.Header(c.currentIndent.Load() + title)
@@ -496,6 +556,18 @@ func (c *AskerConsole) ShowSpinner(ctx context.Context, title string, format Spi
 	}
 
 	c.spinnerLineMu.Lock()
+	if c.accessible {
+		// Only changes of the title are written, like a spinner only redraws its line when its title changes.
+		if !c.accessibleSpinnerRunning || title != c.spinnerCurrentTitle {
+			c.accessibleSpinnerRunning = true
+			c.spinnerCurrentTitle = title
+			c.statusLine(c.getIndent() + title)
+		}
+
+		c.spinnerLineMu.Unlock()
+		return
+	}
+
 	c.spinnerCurrentTitle = title
 
 	indentPrefix := c.getIndent()
@@ -561,6 +633,24 @@ func (c *AskerConsole) StopSpinner(ctx context.Context, lastMessage string, form
 		return
 	}
 
+	if c.accessible {
+		c.spinnerLineMu.Lock()
+		defer c.spinnerLineMu.Unlock()
+
+		// Do nothing when it is already stopped
+		if !c.accessibleSpinnerRunning {
+			return
+		}
+
+		c.accessibleSpinnerRunning = false
+		c.spinnerCurrentTitle = ""
+		if lastMessage != "" {
+			c.statusLine(c.getStopChar(format) + " " + lastMessage)
+		}
+
+		return
+	}
+
 	// Do nothing when it is already stopped
 	if c.spinner.Status() == yacspin.SpinnerStopped {
 		return
@@ -583,11 +673,33 @@ func (c *AskerConsole) StopSpinner(ctx context.Context, lastMessage string, form
 }
 
 func (c *AskerConsole) IsSpinnerRunning(ctx context.Context) bool {
+	if c.accessible {
+		c.spinnerLineMu.Lock()
+		defer c.spinnerLineMu.Unlock()
+
+		return c.accessibleSpinnerRunning
+	}
+
 	return c.spinner.Status() != yacspin.SpinnerStopped
 }
 
 func (c *AskerConsole) IsSpinnerInteractive() bool {
-	return c.spinnerTerminalMode&yacspin.ForceTTYMode > 0
+	return !c.accessible && c.spinnerTerminalMode&yacspin.ForceTTYMode > 0
+}
+
+// EnableAccessibleMode replaces the spinner, and the previewer of the output of tools, with plain status lines prefixed
+// with the time they were written at. The status lines are written in sequence, without moving the cursor or redrawing
+// the screen, which works better with screen readers and captured logs. The results of steps, like
+// `(✓) Done: <step>`, are written as usual.
+//
+// It must be called before the console is used.
+func (c *AskerConsole) EnableAccessibleMode() {
+	c.accessible = true
+}
+
+// statusLine writes a status line of accessible mode, prefixed with the current time.
+func (c *AskerConsole) statusLine(message string) {
+	fmt.Fprintf(c.writer, "[%s] %s\n", time.Now().Format(time.TimeOnly), message)
 }
 
 var donePrefix string = output.WithSuccessFormat("(✓) Done:")
@@ -641,6 +753,24 @@ func (c *AskerConsole) IsNoPromptMode() bool {
 	return c.noPrompt
 }
 
+func (c *AskerConsole) Verbosity() Verbosity {
+	return c.verbosity
+}
+
+// SetVerbosity sets how much output the console writes. Quiet consoles only write the output written with a context from
+// WithOutputVerbosity(ctx, VerbosityQuiet), like the result of the command and errors, along with prompts. Verbose
+// consoles write the full output of tools, instead of previewing its last lines.
+//
+// It must be called before the console is used.
+func (c *AskerConsole) SetVerbosity(verbosity Verbosity) {
+	c.verbosity = verbosity
+}
+
+// writes returns whether the console writes the output written with ctx, according to its verbosity.
+func (c *AskerConsole) writes(ctx context.Context) bool {
+	return c.verbosity >= outputVerbosity(ctx)
+}
+
 func (c *AskerConsole) SupportsPromptDialog() bool {
 	return c.promptClient != nil && !c.noPromptDialog
 }
@@ -686,6 +816,16 @@ func (c *AskerConsole) PromptDialog(ctx context.Context, dialog PromptDialog) (m
 
 // Prompts the user for a single value
 func (c *AskerConsole) Prompt(ctx context.Context, options ConsoleOptions) (string, error) {
+	response, err := c.prompt(ctx, options)
+	if err == nil && options.IsPassword {
+		// Keep password answers out of debug logs and trace files.
+		redact.Register(response)
+	}
+
+	return response, err
+}
+
+func (c *AskerConsole) prompt(ctx context.Context, options ConsoleOptions) (string, error) {
 	var response string
 
 	if c.promptClient != nil {
@@ -869,6 +1009,16 @@ func (c *AskerConsole) MultiSelect(ctx context.Context, options ConsoleOptions)
 	surveyOptions := make([]string, len(options.Options))
 	surveyDefault := options.DefaultValue
 	surveyDefaultAsArr, surveyDefaultIsArr := surveyDefault.([]string)
+	if surveyDefaultIsArr {
+		// Copy the default value, so that adding the details below doesn't modify the caller's options
+		surveyDefaultAsArr = slices.Clone(surveyDefaultAsArr)
+		surveyDefault = surveyDefaultAsArr
+	}
+
+	// Survey returns the selected options as displayed, including any details. Keep track of the option each of them
+	// was displayed for, so that the response only contains option values.
+	optionsBySurveyOption := make(map[string]string, len(options.Options))
+
 	// Modify the options and default value to include any details
 	for i, option := range options.Options {
 		surveyOptions[i] = option
@@ -878,6 +1028,8 @@ func (c *AskerConsole) MultiSelect(ctx context.Context, options ConsoleOptions)
 			surveyOptions[i] += fmt.Sprintf("\n  %s\n", detailString)
 		}
 
+		optionsBySurveyOption[surveyOptions[i]] = option
+
 		if surveyDefaultIsArr {
 			for idx, defaultOption := range surveyDefaultAsArr {
 				if defaultOption == option {
@@ -901,6 +1053,12 @@ func (c *AskerConsole) MultiSelect(ctx context.Context, options ConsoleOptions)
 		return nil, err
 	}
 
+	for i, selected := range response {
+		if option, has := optionsBySurveyOption[selected]; has {
+			response[i] = option
+		}
+	}
+
 	return response, nil
 }
 
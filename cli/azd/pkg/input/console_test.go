@@ -17,7 +17,9 @@ import (
 
 	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/redact"
 	"github.com/stretchr/testify/require"
+	"github.com/theckman/yacspin"
 )
 
 type lineCapturer struct {
@@ -106,6 +108,91 @@ func TestAskerConsole_Spinner_NonTty(t *testing.T) {
 	require.Eventually(t, func() bool { return len(lines.lines()) == 5 }, waitTimeout, pollInterval)
 }
 
+func TestAskerConsole_AccessibleMode(t *testing.T) {
+	formatter, err := output.NewFormatter(string(output.NoneFormat))
+	require.NoError(t, err)
+
+	lines := &lineCapturer{}
+	c := NewConsole(
+		false,
+		false,
+		Writers{Output: lines},
+		ConsoleHandles{
+			Stderr: os.Stderr,
+			Stdin:  os.Stdin,
+			Stdout: lines,
+		},
+		formatter,
+		nil,
+	).(*AskerConsole)
+	c.EnableAccessibleMode()
+
+	ctx := t.Context()
+	require.False(t, c.IsSpinnerInteractive())
+	require.False(t, c.IsSpinnerRunning(ctx))
+
+	// Status lines are written right away, once per title.
+	c.ShowSpinner(ctx, "Packaging service api", Step)
+	c.ShowSpinner(ctx, "Packaging service api", Step)
+	require.True(t, c.IsSpinnerRunning(ctx))
+
+	w := c.ShowPreviewer(ctx, nil)
+	_, err = fmt.Fprintln(w, "Step 1/2 : FROM node")
+	require.NoError(t, err)
+	c.StopPreviewer(ctx, false)
+
+	c.StopSpinner(ctx, "Packaging service api", StepDone)
+	require.False(t, c.IsSpinnerRunning(ctx))
+
+	// Stopping a stopped spinner writes nothing.
+	c.StopSpinner(ctx, "Packaging service api", StepDone)
+
+	got := lines.lines()
+	require.Len(t, got, 3)
+	require.Regexp(t, `^\[\d{2}:\d{2}:\d{2}\]   Packaging service api$`, got[0])
+	require.Equal(t, "Step 1/2 : FROM node", got[1])
+	require.Regexp(t, `^\[\d{2}:\d{2}:\d{2}\] .*Done:.* Packaging service api$`, got[2])
+}
+
+func TestAskerConsole_Verbosity(t *testing.T) {
+	formatter, err := output.NewFormatter(string(output.NoneFormat))
+	require.NoError(t, err)
+
+	lines := &lineCapturer{}
+	c := NewConsole(
+		false,
+		false,
+		Writers{Output: lines},
+		ConsoleHandles{
+			Stderr: os.Stderr,
+			Stdin:  os.Stdin,
+			Stdout: lines,
+		},
+		formatter,
+		nil,
+	).(*AskerConsole)
+	c.SetVerbosity(VerbosityQuiet)
+
+	// Quiet consoles only write the output that's written at quiet verbosity, like results and errors.
+	ctx := t.Context()
+	c.ShowSpinner(ctx, "Packaging service api", Step)
+	c.StopSpinner(ctx, "Packaging service api", StepDone)
+	c.Message(ctx, "Some progress.")
+	_, err = fmt.Fprintln(c.ShowPreviewer(ctx, nil), "Step 1/2 : FROM node")
+	require.NoError(t, err)
+	c.StopPreviewer(ctx, false)
+	c.Message(WithOutputVerbosity(ctx, VerbosityQuiet), "Some result.")
+	require.Equal(t, []string{"Some result."}, lines.lines())
+
+	// Verbose consoles write the full output of tools.
+	c.SetVerbosity(VerbosityVerbose)
+	require.Equal(t, VerbosityVerbose, c.Verbosity())
+	_, err = fmt.Fprintln(c.ShowPreviewer(ctx, nil), "Step 1/2 : FROM node")
+	require.NoError(t, err)
+	c.StopPreviewer(ctx, false)
+	require.Equal(t, []string{"Some result.", "Step 1/2 : FROM node"}, lines.lines())
+}
+
 func TestAskerConsoleExternalPrompt(t *testing.T) {
 	newConsole := func(externalPromptCfg *ExternalPromptConfiguration) Console {
 		return NewConsole(
@@ -171,6 +258,30 @@ func TestAskerConsoleExternalPrompt(t *testing.T) {
 		require.Equal(t, "John Doe", res)
 	})
 
+	t.Run("PromptPassword", func(t *testing.T) {
+		server := newTestExternalPromptServer(func(body promptOptions) json.RawMessage {
+			require.Equal(t, "password", body.Type)
+
+			return json.RawMessage(`"external-prompt-password"`)
+		})
+		t.Cleanup(server.Close)
+
+		externalPromptCfg := &ExternalPromptConfiguration{
+			Endpoint:    server.URL,
+			Key:         "fake-key-for-testing",
+			Transporter: http.DefaultClient,
+		}
+
+		c := newConsole(externalPromptCfg)
+
+		res, err := c.Prompt(t.Context(), ConsoleOptions{Message: "Password?", IsPassword: true})
+		require.NoError(t, err)
+		require.Equal(t, "external-prompt-password", res)
+
+		// Password answers are masked in logs.
+		require.Equal(t, redact.Replacement, redact.String("external-prompt-password"))
+	})
+
 	t.Run("Select", func(t *testing.T) {
 		server := newTestExternalPromptServer(func(body promptOptions) json.RawMessage {
 			require.Equal(t, "select", body.Type)
@@ -277,6 +388,35 @@ func newTestExternalPromptServer(handler func(promptOptions) json.RawMessage) *h
 	}))
 }
 
+func TestAskerConsole_MultiSelect_OptionDetails(t *testing.T) {
+	c := NewConsole(
+		true,
+		false,
+		Writers{Output: io.Discard},
+		ConsoleHandles{
+			Stderr: os.Stderr,
+			Stdin:  os.Stdin,
+			Stdout: io.Discard,
+		},
+		&output.NoneFormatter{},
+		nil,
+	).(*AskerConsole)
+
+	// Details are only displayed with the options when the spinner is interactive.
+	c.spinnerTerminalMode = yacspin.ForceTTYMode
+
+	defaultValue := []string{"Blue", "Red"}
+	res, err := c.MultiSelect(t.Context(), ConsoleOptions{
+		Message:       "What are your favorite colors?",
+		Options:       []string{"Red", "Green", "Blue"},
+		OptionDetails: []string{"RedDetails", "GreenDetails", "BlueDetails"},
+		DefaultValue:  defaultValue,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Blue", "Red"}, res)
+	require.Equal(t, []string{"Blue", "Red"}, defaultValue)
+}
+
 func TestAskerConsole_Message_JsonQueryFilter(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -120,6 +120,7 @@ func newSelectOptions(writer io.Writer, options ConsoleOptions) *uxlib.SelectOpt
 		HelpMessage:   options.Help,
 		Choices:       choices,
 		SelectedIndex: new(selectedIndex),
+		FilterHint:    options.FilterHint,
 	}
 }
 
@@ -241,6 +241,7 @@ func TestNewSelectOptions(t *testing.T) {
 		Message:      "message",
 		Options:      []string{"alpha", "beta", "gamma"},
 		DefaultValue: "gamma",
+		FilterHint:   "Type a letter",
 	})
 
 	require.Equal(t, buf, opts.Writer)
@@ -248,6 +249,7 @@ func TestNewSelectOptions(t *testing.T) {
 	require.Len(t, opts.Choices, 3)
 	require.NotNil(t, opts.SelectedIndex)
 	require.Equal(t, 2, *opts.SelectedIndex)
+	require.Equal(t, "Type a letter", opts.FilterHint)
 }
 
 func TestNewConfirmOptions(t *testing.T) {
@@ -27,6 +27,7 @@ const (
 	InputSourceFlag        InputSourceKind = "flag"
 	InputSourceEnvironment InputSourceKind = "environment"
 	InputSourceConfig      InputSourceKind = "config"
+	InputSourceAnswers     InputSourceKind = "answers"
 )
 
 // InputSource describes one way a required input can be supplied.
@@ -205,6 +206,8 @@ func sourceKindLabel(kind InputSourceKind) string {
 		return "Environment"
 	case InputSourceConfig:
 		return "Config"
+	case InputSourceAnswers:
+		return "Answers file"
 	default:
 		return "Source"
 	}
@@ -0,0 +1,194 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package input
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// PromptTimeoutEnvVarName is the environment variable with the prompt timeout in seconds, used when `--prompt-timeout`
+// isn't set.
+const PromptTimeoutEnvVarName = "AZD_PROMPT_TIMEOUT"
+
+// promptTimeoutGracePeriod is how long a timed out prompt is given to stop, after its context is cancelled. Terminal
+// prompts stop right away, but prompts reading a plain stdin can't be interrupted.
+const promptTimeoutGracePeriod = 500 * time.Millisecond
+
+// timeoutConsole is a [Console] whose prompts take their default value when they aren't answered within a timeout, or
+// fail with a [PromptRequiredError] when they don't have one.
+type timeoutConsole struct {
+	consoleDecorator
+	timeout time.Duration
+}
+
+// NewTimeoutConsole returns a console that waits at most timeout for the answer to each prompt of console. An unanswered
+// prompt takes its default value, like in no-prompt mode, so that an unexpected prompt can't hang a CI job.
+func NewTimeoutConsole(console Console, timeout time.Duration) Console {
+	return &timeoutConsole{
+		consoleDecorator: consoleDecorator{console},
+		timeout:          timeout,
+	}
+}
+
+func (c *timeoutConsole) Prompt(ctx context.Context, options ConsoleOptions) (string, error) {
+	return promptWithTimeout(ctx, c, options,
+		func(ctx context.Context) (string, error) {
+			return c.Console.Prompt(ctx, options)
+		},
+		func() (string, bool) {
+			value, ok := options.DefaultValue.(string)
+			return value, ok && value != "" && !options.IsPassword
+		},
+	)
+}
+
+func (c *timeoutConsole) PromptFs(ctx context.Context, options ConsoleOptions, fsOptions FsOptions) (string, error) {
+	return promptWithTimeout(ctx, c, options,
+		func(ctx context.Context) (string, error) {
+			return c.Console.PromptFs(ctx, options, fsOptions)
+		},
+		func() (string, bool) {
+			value, ok := options.DefaultValue.(string)
+			return value, ok && value != ""
+		},
+	)
+}
+
+func (c *timeoutConsole) Select(ctx context.Context, options ConsoleOptions) (int, error) {
+	return promptWithTimeout(ctx, c, options,
+		func(ctx context.Context) (int, error) {
+			return c.Console.Select(ctx, options)
+		},
+		func() (int, bool) {
+			value, ok := options.DefaultValue.(string)
+			if !ok {
+				return -1, false
+			}
+
+			for i, option := range options.Options {
+				if option == value {
+					return i, true
+				}
+			}
+
+			return -1, false
+		},
+	)
+}
+
+func (c *timeoutConsole) MultiSelect(ctx context.Context, options ConsoleOptions) ([]string, error) {
+	return promptWithTimeout(ctx, c, options,
+		func(ctx context.Context) ([]string, error) {
+			return c.Console.MultiSelect(ctx, options)
+		},
+		func() ([]string, bool) {
+			value, ok := options.DefaultValue.([]string)
+			return value, ok
+		},
+	)
+}
+
+func (c *timeoutConsole) Confirm(ctx context.Context, options ConsoleOptions) (bool, error) {
+	return promptWithTimeout(ctx, c, options,
+		func(ctx context.Context) (bool, error) {
+			return c.Console.Confirm(ctx, options)
+		},
+		func() (bool, bool) {
+			// Like in no-prompt mode, confirmations without a default aren't confirmed.
+			value, _ := options.DefaultValue.(bool)
+			return value, true
+		},
+	)
+}
+
+// promptWithTimeout runs prompt, and returns its default value when it isn't answered within the timeout of the console.
+// Prompts without a default value, and prompts that can't be interrupted, fail with a [PromptRequiredError] naming the
+// missing input.
+func promptWithTimeout[T any](
+	ctx context.Context,
+	c *timeoutConsole,
+	options ConsoleOptions,
+	prompt func(ctx context.Context) (T, error),
+	defaultValue func() (T, bool),
+) (T, error) {
+	type result struct {
+		value T
+		err   error
+	}
+
+	promptCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Buffered, so that a prompt which can't be interrupted doesn't block once it's answered.
+	results := make(chan result, 1)
+	go func() {
+		value, err := prompt(promptCtx)
+		results <- result{value, err}
+	}()
+
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+
+	select {
+	case result := <-results:
+		return result.value, result.err
+	case <-timer.C:
+	}
+
+	cancel()
+	stopped := false
+	select {
+	case <-results:
+		stopped = true
+	case <-time.After(promptTimeoutGracePeriod):
+	}
+
+	message := strings.TrimSpace(options.Message)
+	log.Printf("prompt '%s' timed out after %s", message, c.timeout)
+
+	// A prompt that didn't stop, like one reading a plain stdin, keeps reading the input, so the command can't go on
+	// with the default: the input meant for the next prompts would be read by this one.
+	value, has := defaultValue()
+	if !has || !stopped {
+		var zero T
+		return zero, promptTimedOutError(options, c.timeout)
+	}
+
+	// Show the option selected by default, rather than its index.
+	shown := any(value)
+	if index, ok := shown.(int); ok && index >= 0 && index < len(options.Options) {
+		shown = strings.TrimSpace(options.Options[index])
+	}
+
+	c.Console.Message(ctx, output.WithWarningFormat(
+		"WARNING: No answer to '%s' after %s, using the default: %v", message, c.timeout, shown))
+
+	return value, nil
+}
+
+// promptTimedOutError returns the error for a prompt without a default value that wasn't answered within timeout. The
+// prompt can be answered with its key, or else its message, in an answers file.
+func promptTimedOutError(options ConsoleOptions, timeout time.Duration) error {
+	input := RequiredInput{
+		Name:        options.Key,
+		Description: strings.TrimSpace(options.Message),
+	}
+	if input.Name == "" {
+		input.Name = input.Description
+		input.Description = ""
+	}
+
+	input.Sources = []InputSource{{Kind: InputSourceAnswers, Name: input.Name}}
+
+	return &PromptRequiredError{
+		Message: fmt.Sprintf("This command cannot continue (prompt timed out after %s)", timeout),
+		Inputs:  []RequiredInput{input},
+	}
+}
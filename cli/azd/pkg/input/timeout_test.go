@@ -0,0 +1,149 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package input
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingConsole is a console whose prompts are never answered.
+type blockingConsole struct {
+	Console
+}
+
+func (c *blockingConsole) Prompt(ctx context.Context, options ConsoleOptions) (string, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func (c *blockingConsole) Select(ctx context.Context, options ConsoleOptions) (int, error) {
+	<-ctx.Done()
+	return -1, ctx.Err()
+}
+
+func (c *blockingConsole) MultiSelect(ctx context.Context, options ConsoleOptions) ([]string, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (c *blockingConsole) Confirm(ctx context.Context, options ConsoleOptions) (bool, error) {
+	<-ctx.Done()
+	return false, ctx.Err()
+}
+
+func TestTimeoutConsole(t *testing.T) {
+	console, buf := newTestConsole(t, false, &output.NoneFormatter{})
+	c := NewTimeoutConsole(&blockingConsole{Console: console}, 10*time.Millisecond)
+	ctx := t.Context()
+
+	value, err := c.Prompt(ctx, ConsoleOptions{Message: "Enter a name", DefaultValue: "dev"})
+	require.NoError(t, err)
+	require.Equal(t, "dev", value)
+	require.Contains(t, buf.String(), "No answer to 'Enter a name' after 10ms, using the default: dev")
+
+	index, err := c.Select(ctx, ConsoleOptions{
+		Message:      "Select a location",
+		Options:      []string{"eastus", "westus2"},
+		DefaultValue: "westus2",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, index)
+	require.Contains(t, buf.String(), "using the default: westus2")
+
+	selected, err := c.MultiSelect(ctx, ConsoleOptions{
+		Message:      "Select services",
+		Options:      []string{"api", "web"},
+		DefaultValue: []string{"web"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"web"}, selected)
+
+	confirmed, err := c.Confirm(ctx, ConsoleOptions{Message: "Continue?"})
+	require.NoError(t, err)
+	require.False(t, confirmed)
+
+	// Prompts without a default fail with the missing input.
+	_, err = c.Prompt(ctx, ConsoleOptions{Key: "parameters.vmSize", Message: "Enter a value for vmSize"})
+	var promptErr *PromptRequiredError
+	require.True(t, errors.As(err, &promptErr))
+	require.Equal(t, []RequiredInput{
+		{
+			Name:        "parameters.vmSize",
+			Description: "Enter a value for vmSize",
+			Sources:     []InputSource{{Kind: InputSourceAnswers, Name: "parameters.vmSize"}},
+		},
+	}, promptErr.Inputs)
+	require.Contains(t, promptErr.ToString(""), "Answers file: parameters.vmSize")
+
+	data, err := json.Marshal(promptErr)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"type":"missingRequiredInputs"`)
+
+	_, err = c.Prompt(ctx, ConsoleOptions{Message: "Enter a password", DefaultValue: "secret", IsPassword: true})
+	require.True(t, errors.As(err, &promptErr))
+	require.Equal(t, "Enter a password", promptErr.Inputs[0].Name)
+
+	_, err = c.Select(ctx, ConsoleOptions{Message: "Select a subscription", Options: []string{"Dev", "Test"}})
+	require.True(t, errors.As(err, &promptErr))
+}
+
+// stdinConsole is a console whose prompts read a plain stdin, so they can't be interrupted.
+type stdinConsole struct {
+	Console
+	stdin chan string
+}
+
+func (c *stdinConsole) Prompt(ctx context.Context, options ConsoleOptions) (string, error) {
+	return <-c.stdin, nil
+}
+
+func TestTimeoutConsole_NotInterruptible(t *testing.T) {
+	console, _ := newTestConsole(t, false, &output.NoneFormatter{})
+	stdin := make(chan string)
+	t.Cleanup(func() { close(stdin) })
+	c := NewTimeoutConsole(&stdinConsole{Console: console, stdin: stdin}, 10*time.Millisecond)
+
+	// The default isn't used, since the prompt would keep reading the input of the next prompts.
+	_, err := c.Prompt(t.Context(), ConsoleOptions{Key: "name", Message: "Enter a name", DefaultValue: "dev"})
+	var promptErr *PromptRequiredError
+	require.True(t, errors.As(err, &promptErr))
+	require.Equal(t, "name", promptErr.Inputs[0].Name)
+}
+
+func TestTimeoutConsole_Answered(t *testing.T) {
+	console, _ := newTestConsole(t, true, nil)
+	c := NewTimeoutConsole(console, time.Minute)
+
+	// Prompts answered before the timeout, here by their default in no-prompt mode, return right away.
+	value, err := c.Prompt(t.Context(), ConsoleOptions{Message: "Enter a name", DefaultValue: "dev"})
+	require.NoError(t, err)
+	require.Equal(t, "dev", value)
+
+	_, err = c.Prompt(t.Context(), ConsoleOptions{Message: "Enter a name"})
+	var promptErr *PromptRequiredError
+	require.True(t, errors.As(err, &promptErr))
+	require.Equal(t, "Enter a name", promptErr.PromptMessage)
+}
+
+func TestTimeoutConsole_PreviewerPauser(t *testing.T) {
+	console, _ := newTestConsole(t, true, nil)
+	c := NewAnswersConsole(NewTimeoutConsole(console, time.Minute), NewAnswers(map[string]any{}))
+
+	// The decorated console can still pause its previewer, like during the progress table of deploy.
+	pauser, ok := c.(PreviewerPauser)
+	require.True(t, ok)
+
+	pauser.PausePreviewer()
+	require.True(t, console.previewerSuppressed.Load())
+
+	pauser.ResumePreviewer()
+	require.False(t, console.previewerSuppressed.Load())
+}
@@ -0,0 +1,38 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package input
+
+import "context"
+
+// Verbosity is how much output a [Console] writes.
+type Verbosity int
+
+const (
+	// VerbosityQuiet only writes the result of commands and errors. Set with `--quiet`.
+	VerbosityQuiet Verbosity = -1
+	// VerbosityNormal writes the progress of commands, like spinners and the resources being provisioned.
+	VerbosityNormal Verbosity = 0
+	// VerbosityVerbose also writes details, like resources without a display name and the full output of tools. Set with
+	// `--verbose`.
+	VerbosityVerbose Verbosity = 1
+	// VerbosityDebug writes the same output as [VerbosityVerbose], along with debug logs. Set with `--debug`.
+	VerbosityDebug Verbosity = 2
+)
+
+type outputVerbosityKey struct{}
+
+// WithOutputVerbosity returns a context for output that consoles only write at verbosity or above. Output is written at
+// [VerbosityNormal] by default. Results and errors are written at [VerbosityQuiet], so that they're always written.
+func WithOutputVerbosity(ctx context.Context, verbosity Verbosity) context.Context {
+	return context.WithValue(ctx, outputVerbosityKey{}, verbosity)
+}
+
+// outputVerbosity returns the verbosity the output written with ctx requires.
+func outputVerbosity(ctx context.Context) Verbosity {
+	if verbosity, ok := ctx.Value(outputVerbosityKey{}).(Verbosity); ok {
+		return verbosity
+	}
+
+	return VerbosityNormal
+}
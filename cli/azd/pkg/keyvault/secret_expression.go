@@ -0,0 +1,129 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package keyvault
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SecretExpression is a ${keyvault:<vault-name>/<secret-name>} reference to a Key Vault secret in the configuration
+// of a service, such as its env or app settings.
+type SecretExpression struct {
+	VaultName  string
+	SecretName string
+}
+
+const secretExpressionPrefix = "${keyvault:"
+
+var secretExpressionRegex = regexp.MustCompile(`\$\{keyvault:([^}]*)\}`)
+
+// vaultNameRegex matches the names of Key Vaults: 3 to 24 letters, digits and hyphens, starting with a letter.
+var vaultNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9-]{2,23}$`)
+
+// String returns the expression in the ${keyvault:<vault-name>/<secret-name>} format.
+func (e SecretExpression) String() string {
+	return secretExpressionPrefix + e.VaultName + "/" + e.SecretName + "}"
+}
+
+// AppServiceReference returns the @Microsoft.KeyVault reference that App Service and Azure Functions resolve with
+// the managed identity of the app, so the value of the secret is never stored in the app settings.
+func (e SecretExpression) AppServiceReference() string {
+	return fmt.Sprintf("@Microsoft.KeyVault(VaultName=%s;SecretName=%s)", e.VaultName, e.SecretName)
+}
+
+// HasSecretExpression reports whether s contains a ${keyvault:...} expression.
+func HasSecretExpression(s string) bool {
+	return strings.Contains(s, secretExpressionPrefix)
+}
+
+// IsSecretExpression reports whether s is exactly one ${keyvault:...} expression, without other text around it.
+func IsSecretExpression(s string) bool {
+	match := secretExpressionRegex.FindStringIndex(s)
+	return match != nil && match[0] == 0 && match[1] == len(s)
+}
+
+// ParseSecretExpression parses s, which must be exactly one ${keyvault:<vault-name>/<secret-name>} expression.
+func ParseSecretExpression(s string) (SecretExpression, error) {
+	if !IsSecretExpression(s) {
+		return SecretExpression{}, fmt.Errorf(
+			"invalid Key Vault secret reference %q. Expected format: %s<vault-name>/<secret-name>}",
+			s, secretExpressionPrefix)
+	}
+
+	return parseSecretExpressionBody(s, secretExpressionRegex.FindStringSubmatch(s)[1])
+}
+
+func parseSecretExpressionBody(s string, body string) (SecretExpression, error) {
+	vaultName, secretName, ok := strings.Cut(body, "/")
+	if !ok {
+		return SecretExpression{}, fmt.Errorf(
+			"invalid Key Vault secret reference %q. Expected format: %s<vault-name>/<secret-name>}",
+			s, secretExpressionPrefix)
+	}
+
+	if !vaultNameRegex.MatchString(vaultName) {
+		return SecretExpression{}, fmt.Errorf(
+			"invalid Key Vault secret reference %q: '%s' isn't a valid Key Vault name", s, vaultName)
+	}
+
+	if !IsValidSecretName(secretName) {
+		return SecretExpression{}, fmt.Errorf(
+			"invalid Key Vault secret reference %q: '%s' isn't a valid secret name", s, secretName)
+	}
+
+	return SecretExpression{VaultName: vaultName, SecretName: secretName}, nil
+}
+
+// ParseSecretExpressions returns the ${keyvault:...} expressions in s, in order. It returns an error when one of them
+// is malformed, or when s has a ${keyvault: prefix that isn't closed.
+func ParseSecretExpressions(s string) ([]SecretExpression, error) {
+	var expressions []SecretExpression
+	matches := secretExpressionRegex.FindAllStringSubmatchIndex(s, -1)
+	for _, match := range matches {
+		expression, err := parseSecretExpressionBody(s[match[0]:match[1]], s[match[2]:match[3]])
+		if err != nil {
+			return nil, err
+		}
+
+		expressions = append(expressions, expression)
+	}
+
+	if strings.Count(s, secretExpressionPrefix) != len(matches) {
+		return nil, fmt.Errorf("invalid Key Vault secret reference in %q: missing '}'", s)
+	}
+
+	return expressions, nil
+}
+
+// ReplaceSecretExpressions returns s with each of its ${keyvault:...} expressions replaced with the result of
+// replace.
+func ReplaceSecretExpressions(s string, replace func(SecretExpression) (string, error)) (string, error) {
+	expressions, err := ParseSecretExpressions(s)
+	if err != nil {
+		return "", err
+	}
+
+	var replaceErr error
+	i := 0
+	result := secretExpressionRegex.ReplaceAllStringFunc(s, func(string) string {
+		expression := expressions[i]
+		i++
+		if replaceErr != nil {
+			return ""
+		}
+
+		value, err := replace(expression)
+		if err != nil {
+			replaceErr = err
+		}
+		return value
+	})
+	if replaceErr != nil {
+		return "", replaceErr
+	}
+
+	return result, nil
+}
@@ -0,0 +1,77 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package keyvault
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSecretExpression(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expected  SecretExpression
+		expectErr string
+	}{
+		{"valid", "${keyvault:my-vault/db-password}", SecretExpression{"my-vault", "db-password"}, ""},
+		{"text around", "x${keyvault:my-vault/db-password}", SecretExpression{}, "Expected format"},
+		{"missing secret", "${keyvault:my-vault}", SecretExpression{}, "Expected format"},
+		{"invalid vault", "${keyvault:1vault/secret}", SecretExpression{}, "'1vault' isn't a valid Key Vault name"},
+		{"invalid secret", "${keyvault:my-vault/db_password}", SecretExpression{}, "isn't a valid secret name"},
+		{"version", "${keyvault:my-vault/secret/v1}", SecretExpression{}, "isn't a valid secret name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expression, err := ParseSecretExpression(tt.input)
+			if tt.expectErr != "" {
+				require.ErrorContains(t, err, tt.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, expression)
+			assert.Equal(t, tt.input, expression.String())
+		})
+	}
+}
+
+func TestSecretExpression_AppServiceReference(t *testing.T) {
+	assert.Equal(t,
+		"@Microsoft.KeyVault(VaultName=my-vault;SecretName=db-password)",
+		SecretExpression{VaultName: "my-vault", SecretName: "db-password"}.AppServiceReference())
+}
+
+func TestIsSecretExpression(t *testing.T) {
+	assert.True(t, IsSecretExpression("${keyvault:my-vault/secret}"))
+	assert.False(t, IsSecretExpression("Password=${keyvault:my-vault/secret}"))
+	assert.False(t, IsSecretExpression("${AZURE_KEYVAULT_NAME}"))
+	assert.True(t, HasSecretExpression("Password=${keyvault:my-vault/secret}"))
+	assert.False(t, HasSecretExpression("${AZURE_KEYVAULT_NAME}"))
+}
+
+func TestReplaceSecretExpressions(t *testing.T) {
+	value, err := ReplaceSecretExpressions(
+		"Server=${SERVER};User=${keyvault:my-vault/user};Password=${keyvault:other-vault/password}",
+		func(e SecretExpression) (string, error) {
+			return strings.ToUpper(e.VaultName + ":" + e.SecretName), nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, "Server=${SERVER};User=MY-VAULT:USER;Password=OTHER-VAULT:PASSWORD", value)
+
+	_, err = ReplaceSecretExpressions("${keyvault:my-vault/user", func(e SecretExpression) (string, error) {
+		return "", nil
+	})
+	require.ErrorContains(t, err, "missing '}'")
+
+	_, err = ReplaceSecretExpressions("${keyvault:my-vault/user}", func(e SecretExpression) (string, error) {
+		return "", errors.New("access denied")
+	})
+	require.ErrorContains(t, err, "access denied")
+}
@@ -0,0 +1,154 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package lockfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// FileName is the name of the lock file, stored next to azure.yaml.
+const FileName = "azd.lock"
+
+// SchemaVersion is the version of the lock file format written by azd.
+const SchemaVersion = 1
+
+var (
+	ErrLockFileNotFound    = errors.New("lock file not found")
+	ErrUnsupportedSchema   = errors.New("unsupported lock file version")
+	ErrDigestMismatch      = errors.New("artifact digest doesn't match the lock file")
+	ErrSourceMismatch      = errors.New("extension source doesn't match the lock file")
+	ErrLockedVersionAbsent = errors.New("locked version isn't available")
+	ErrToolVersionMismatch = errors.New("installed tool version doesn't match the lock file")
+)
+
+// LockFile captures the exact versions and digests of the extensions, tools and template used by a project, so that
+// `azd restore` can reproduce the same toolset on another machine or CI agent.
+type LockFile struct {
+	// Version is the version of the lock file format.
+	Version int `json:"version"`
+	// Template is the template the project was created from.
+	Template *Template `json:"template,omitempty"`
+	// Sources are the extension sources the locked extensions are installed from.
+	Sources []Source `json:"sources,omitempty"`
+	// Extensions are the extensions required by the project, and the extensions they depend on.
+	Extensions []Extension `json:"extensions,omitempty"`
+	// Tools are the external tools required by the project, at the version installed on the machine the lock file was
+	// written on.
+	Tools []Tool `json:"tools,omitempty"`
+}
+
+// Template identifies a template and its version. Repository and Commit pin the exact version of the template the
+// project was initialized from, since the version of the slug can be moved to other commits.
+type Template struct {
+	Name       string `json:"name"`
+	Version    string `json:"version,omitempty"`
+	Repository string `json:"repository,omitempty"`
+	Commit     string `json:"commit,omitempty"`
+}
+
+// Source is an extension source.
+type Source struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Location string `json:"location"`
+}
+
+// Extension is an extension pinned to an exact version.
+type Extension struct {
+	Id      string `json:"id"`
+	Version string `json:"version"`
+	Source  string `json:"source"`
+	// Artifacts are the digests of the artifacts of the version, keyed by platform (for example "linux/amd64"), so
+	// that the lock file verifies the extension on every platform.
+	Artifacts map[string]Digest `json:"artifacts,omitempty"`
+}
+
+// Digest is the hash of an artifact.
+type Digest struct {
+	Algorithm string `json:"algorithm"`
+	Value     string `json:"value"`
+}
+
+// String returns the digest as "<algorithm>:<value>".
+func (d Digest) String() string {
+	return fmt.Sprintf("%s:%s", d.Algorithm, d.Value)
+}
+
+// Tool is an external tool pinned to an exact version.
+type Tool struct {
+	Id      string `json:"id"`
+	Version string `json:"version"`
+}
+
+// ParseTemplate parses a template slug of the form "<name>@<version>", as stored in the metadata of azure.yaml.
+func ParseTemplate(slug string) *Template {
+	if slug == "" {
+		return nil
+	}
+
+	name, version, _ := strings.Cut(slug, "@")
+	return &Template{Name: name, Version: version}
+}
+
+// String returns the template as "<name>@<version>".
+func (t *Template) String() string {
+	if t.Version == "" {
+		return t.Name
+	}
+
+	return fmt.Sprintf("%s@%s", t.Name, t.Version)
+}
+
+// Path returns the path of the lock file of the project in projectDir.
+func Path(projectDir string) string {
+	return filepath.Join(projectDir, FileName)
+}
+
+// Load reads the lock file at path. Returns ErrLockFileNotFound when the file doesn't exist.
+func Load(path string) (*LockFile, error) {
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrLockFileNotFound, path)
+	} else if err != nil {
+		return nil, fmt.Errorf("reading lock file: %w", err)
+	}
+
+	var lock LockFile
+	if err := json.Unmarshal(contents, &lock); err != nil {
+		return nil, fmt.Errorf("parsing lock file %s: %w", path, err)
+	}
+
+	if lock.Version != SchemaVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedSchema, lock.Version)
+	}
+
+	return &lock, nil
+}
+
+// Save writes the lock file to path. Entries are sorted so that the file only changes when the toolset changes.
+func Save(path string, lock *LockFile) error {
+	lock.Version = SchemaVersion
+	slices.SortFunc(lock.Sources, func(a, b Source) int { return strings.Compare(a.Name, b.Name) })
+	slices.SortFunc(lock.Extensions, func(a, b Extension) int { return strings.Compare(a.Id, b.Id) })
+	slices.SortFunc(lock.Tools, func(a, b Tool) int { return strings.Compare(a.Id, b.Id) })
+
+	contents, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling lock file: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(contents, '\n'), osutil.PermissionFile); err != nil {
+		return fmt.Errorf("writing lock file: %w", err)
+	}
+
+	return nil
+}
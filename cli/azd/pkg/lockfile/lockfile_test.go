@@ -0,0 +1,55 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package lockfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	path := Path(t.TempDir())
+	lock := &LockFile{
+		Template: ParseTemplate("todo-nodejs-mongo@0.0.1-beta"),
+		Extensions: []Extension{
+			{Id: "microsoft.azd.extensions", Version: "0.4.0", Source: "azd"},
+			{Id: "azure.ai.agents", Version: "1.0.0-beta.7", Source: "azd", Artifacts: map[string]Digest{
+				"linux/amd64": {Algorithm: "sha256", Value: "abc"},
+			}},
+		},
+		Tools: []Tool{{Id: "kubectl", Version: "1.31.0"}, {Id: "az-cli", Version: "2.70.0"}},
+	}
+
+	require.NoError(t, Save(path, lock))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, SchemaVersion, loaded.Version)
+	require.Equal(t, &Template{Name: "todo-nodejs-mongo", Version: "0.0.1-beta"}, loaded.Template)
+
+	// Entries are sorted so the file is stable.
+	require.Equal(t, "azure.ai.agents", loaded.Extensions[0].Id)
+	require.Equal(t, "az-cli", loaded.Tools[0].Id)
+	require.Equal(t, "sha256:abc", loaded.Extensions[0].Artifacts["linux/amd64"].String())
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := Load(Path(dir))
+	require.ErrorIs(t, err, ErrLockFileNotFound)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, FileName), []byte(`{"version": 2}`), 0600))
+	_, err = Load(Path(dir))
+	require.ErrorIs(t, err, ErrUnsupportedSchema)
+}
+
+func TestParseTemplate(t *testing.T) {
+	require.Nil(t, ParseTemplate(""))
+	require.Equal(t, "todo-python-mongo", ParseTemplate("todo-python-mongo").String())
+	require.Equal(t, "todo-python-mongo@1.0.0", ParseTemplate("todo-python-mongo@1.0.0").String())
+}
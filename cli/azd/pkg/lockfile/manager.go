@@ -0,0 +1,400 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package lockfile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/azure/azure-dev/cli/azd/pkg/extensions"
+	"github.com/azure/azure-dev/cli/azd/pkg/tool"
+)
+
+// Manager writes lock files from the toolset installed on the machine, and restores the toolset of lock files.
+type Manager struct {
+	extensionManager *extensions.Manager
+	sourceManager    *extensions.SourceManager
+	toolManager      *tool.Manager
+}
+
+// NewManager creates a new lock file manager.
+func NewManager(
+	extensionManager *extensions.Manager,
+	sourceManager *extensions.SourceManager,
+	toolManager *tool.Manager,
+) *Manager {
+	return &Manager{
+		extensionManager: extensionManager,
+		sourceManager:    sourceManager,
+		toolManager:      toolManager,
+	}
+}
+
+// CaptureOptions are the options of [Manager.Capture].
+type CaptureOptions struct {
+	// Template is the template slug of the project, of the form "<name>@<version>".
+	Template string
+	// TemplateRepository and TemplateCommit pin the version of the template the project was initialized from.
+	TemplateRepository string
+	TemplateCommit     string
+	// RequiredExtensions are the ids of the extensions required by the project.
+	RequiredExtensions []string
+	// RequiredTools are the ids of the tools required by the services and the infrastructure provider of the project.
+	RequiredTools []string
+}
+
+// RestoreOptions are the options of [Manager.Restore].
+type RestoreOptions struct {
+	// AllowToolVersionMismatch reports the tools that can't be installed at their locked version in
+	// [RestoreResult.MismatchedTools], instead of failing with ErrToolVersionMismatch.
+	AllowToolVersionMismatch bool
+}
+
+// RestoreResult reports the changes made to the toolset of the machine to match a lock file.
+type RestoreResult struct {
+	// Extensions are the extensions that were installed at their locked version.
+	Extensions []Extension `json:"extensions"`
+	// Tools are the tools that were installed because they were missing, or upgraded to their locked version.
+	Tools []Tool `json:"tools"`
+	// MismatchedTools are the tools that are installed at another version than their locked version, when
+	// [RestoreOptions.AllowToolVersionMismatch] is set.
+	MismatchedTools []ToolMismatch `json:"mismatchedTools"`
+	// NewerTools are the tools that are installed at a newer version than their locked version that is compatible with
+	// it, which don't fail the restore.
+	NewerTools []ToolMismatch `json:"newerTools"`
+}
+
+// ToolMismatch is a tool that is installed at another version than its locked version.
+type ToolMismatch struct {
+	Id               string `json:"id"`
+	Version          string `json:"version"`
+	InstalledVersion string `json:"installedVersion"`
+}
+
+// Capture returns a lock file of the template, of the required extensions and the extensions they depend on, and of
+// the required tools at their installed version. The required extensions and tools must be installed.
+func (m *Manager) Capture(ctx context.Context, options CaptureOptions) (*LockFile, error) {
+	lock := &LockFile{
+		Version:  SchemaVersion,
+		Template: ParseTemplate(options.Template),
+	}
+
+	if lock.Template != nil {
+		lock.Template.Repository = options.TemplateRepository
+		lock.Template.Commit = options.TemplateCommit
+	}
+
+	sourceNames := map[string]struct{}{}
+	pending := slices.Clone(options.RequiredExtensions)
+	locked := map[string]struct{}{}
+	for len(pending) > 0 {
+		id := pending[0]
+		pending = pending[1:]
+		if _, has := locked[id]; has {
+			continue
+		}
+		locked[id] = struct{}{}
+
+		installed, err := m.extensionManager.GetInstalled(extensions.FilterOptions{Id: id})
+		if err != nil {
+			return nil, fmt.Errorf("extension '%s' required by the project isn't installed: %w", id, err)
+		}
+
+		_, version, err := m.findVersion(ctx, installed.Id, installed.Source, installed.Version)
+		if err != nil {
+			return nil, err
+		}
+
+		lock.Extensions = append(lock.Extensions, Extension{
+			Id:        installed.Id,
+			Version:   installed.Version,
+			Source:    installed.Source,
+			Artifacts: artifactDigests(version),
+		})
+		sourceNames[installed.Source] = struct{}{}
+
+		for _, dependency := range version.Dependencies {
+			pending = append(pending, dependency.Id)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(sourceNames)) {
+		source, err := m.sourceManager.Get(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("getting extension source '%s': %w", name, err)
+		}
+
+		lock.Sources = append(lock.Sources, Source{
+			Name:     source.Name,
+			Type:     string(source.Type),
+			Location: source.Location,
+		})
+	}
+
+	requiredTools := slices.Clone(options.RequiredTools)
+	slices.Sort(requiredTools)
+	for _, id := range slices.Compact(requiredTools) {
+		status, err := m.toolManager.DetectTool(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("detecting tool '%s': %w", id, err)
+		}
+
+		if !status.Installed || status.InstalledVersion == "" {
+			return nil, fmt.Errorf("tool '%s' required by the project isn't installed", id)
+		}
+
+		lock.Tools = append(lock.Tools, Tool{Id: id, Version: status.InstalledVersion})
+	}
+
+	return lock, nil
+}
+
+// Restore installs the extensions of the lock file at their locked version, after verifying that the artifacts of
+// the version still have their locked digests, and installs the tools of the lock file at their locked version. Extension
+// sources of the lock file that aren't configured are added.
+//
+// Tools are installed with package managers that install their latest version, so a tool that is missing or older than
+// its locked version is installed or upgraded. A tool that ends up at a newer version compatible with its locked version
+// is reported in [RestoreResult.NewerTools], and ErrToolVersionMismatch is returned for any other version, unless
+// options.AllowToolVersionMismatch is set.
+func (m *Manager) Restore(ctx context.Context, lock *LockFile, options RestoreOptions) (*RestoreResult, error) {
+	result := &RestoreResult{}
+
+	for _, source := range lock.Sources {
+		if err := m.ensureSource(ctx, source); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, locked := range lock.Extensions {
+		metadata, version, err := m.findVersion(ctx, locked.Id, locked.Source, locked.Version)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := verifyArtifacts(locked, version); err != nil {
+			return nil, err
+		}
+
+		installed, err := m.extensionManager.GetInstalled(extensions.FilterOptions{Id: locked.Id})
+		if err == nil && installed.Version == locked.Version && strings.EqualFold(installed.Source, locked.Source) {
+			continue
+		}
+
+		// Dependencies are locked themselves, so only the extension is installed.
+		if err == nil {
+			_, _, err = m.extensionManager.Upgrade(ctx, metadata, extensions.UpgradeOptions{
+				VersionPreference: locked.Version,
+				SkipDependencies:  true,
+			})
+		} else if errors.Is(err, extensions.ErrInstalledExtensionNotFound) {
+			_, err = m.extensionManager.InstallWithOptions(ctx, metadata, extensions.InstallOptions{
+				VersionPreference: locked.Version,
+				SkipDependencies:  true,
+			})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("installing extension '%s' version %s: %w", locked.Id, locked.Version, err)
+		}
+
+		result.Extensions = append(result.Extensions, locked)
+	}
+
+	for _, locked := range lock.Tools {
+		installedVersion, changed, err := m.restoreTool(ctx, locked)
+		if err != nil {
+			return nil, err
+		}
+
+		if changed {
+			result.Tools = append(result.Tools, locked)
+		}
+
+		if installedVersion == "" || installedVersion == locked.Version {
+			continue
+		}
+
+		mismatch := ToolMismatch{Id: locked.Id, Version: locked.Version, InstalledVersion: installedVersion}
+		if isNewerCompatibleVersion(installedVersion, locked.Version) {
+			result.NewerTools = append(result.NewerTools, mismatch)
+			continue
+		}
+
+		result.MismatchedTools = append(result.MismatchedTools, mismatch)
+	}
+
+	if len(result.MismatchedTools) > 0 && !options.AllowToolVersionMismatch {
+		mismatches := make([]string, 0, len(result.MismatchedTools))
+		for _, mismatch := range result.MismatchedTools {
+			mismatches = append(mismatches, fmt.Sprintf("'%s' is locked to version %s, but version %s is installed",
+				mismatch.Id, mismatch.Version, mismatch.InstalledVersion))
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrToolVersionMismatch, strings.Join(mismatches, "; "))
+	}
+
+	return result, nil
+}
+
+// restoreTool installs the locked tool when it's missing, and upgrades it when it's older than its locked version. It
+// returns the version of the tool installed on the machine afterwards, and whether the tool was installed or upgraded.
+func (m *Manager) restoreTool(ctx context.Context, locked Tool) (string, bool, error) {
+	status, err := m.toolManager.DetectTool(ctx, locked.Id)
+	if err != nil {
+		return "", false, fmt.Errorf("detecting tool '%s': %w", locked.Id, err)
+	}
+
+	var install func(ctx context.Context, ids []string, opts ...tool.InstallOption) ([]*tool.InstallResult, error)
+	switch {
+	case !status.Installed:
+		install = m.toolManager.InstallTools
+	case status.InstalledVersion != "" && isOlderVersion(status.InstalledVersion, locked.Version):
+		install = m.toolManager.UpgradeTools
+	default:
+		return status.InstalledVersion, false, nil
+	}
+
+	results, err := install(ctx, []string{locked.Id})
+	if err != nil {
+		return "", false, fmt.Errorf("installing tool '%s': %w", locked.Id, err)
+	}
+
+	for _, installResult := range results {
+		if installResult.Error != nil {
+			return "", false, fmt.Errorf("installing tool '%s': %w", installResult.Tool.Id, installResult.Error)
+		}
+	}
+
+	if status, err = m.toolManager.DetectTool(ctx, locked.Id); err != nil {
+		return "", false, fmt.Errorf("detecting tool '%s': %w", locked.Id, err)
+	}
+
+	return status.InstalledVersion, true, nil
+}
+
+// isOlderVersion returns true when installed is an older version than locked. Versions that can't be parsed aren't
+// ordered, so they are never older.
+func isOlderVersion(installed string, locked string) bool {
+	installedVersion, err := semver.NewVersion(installed)
+	if err != nil {
+		return false
+	}
+
+	lockedVersion, err := semver.NewVersion(locked)
+	if err != nil {
+		return false
+	}
+
+	return installedVersion.LessThan(lockedVersion)
+}
+
+// isNewerCompatibleVersion returns true when installed is a newer version than locked that is compatible with it, as
+// defined by a caret constraint on locked: the same major version, or the same minor version for 0.x versions.
+func isNewerCompatibleVersion(installed string, locked string) bool {
+	installedVersion, err := semver.NewVersion(installed)
+	if err != nil {
+		return false
+	}
+
+	compatible, err := semver.NewConstraint("^" + locked)
+	if err != nil {
+		return false
+	}
+
+	return compatible.Check(installedVersion)
+}
+
+// ensureSource adds the extension source when it isn't configured, and returns ErrSourceMismatch when a source with
+// the same name is configured with another location.
+func (m *Manager) ensureSource(ctx context.Context, source Source) error {
+	configured, err := m.sourceManager.Get(ctx, source.Name)
+	if errors.Is(err, extensions.ErrSourceNotFound) {
+		log.Printf("adding extension source '%s' from the lock file", source.Name)
+		return m.sourceManager.Add(ctx, source.Name, &extensions.SourceConfig{
+			Name:     source.Name,
+			Type:     extensions.SourceKind(source.Type),
+			Location: source.Location,
+		})
+	} else if err != nil {
+		return fmt.Errorf("getting extension source '%s': %w", source.Name, err)
+	}
+
+	if configured.Location != source.Location {
+		return fmt.Errorf(
+			"%w: source '%s' is configured with location '%s', but the lock file has '%s'",
+			ErrSourceMismatch, source.Name, configured.Location, source.Location)
+	}
+
+	return nil
+}
+
+// findVersion returns the metadata of the extension in the source, and its version.
+func (m *Manager) findVersion(
+	ctx context.Context,
+	id string,
+	source string,
+	version string,
+) (*extensions.ExtensionMetadata, *extensions.ExtensionVersion, error) {
+	matches, err := m.extensionManager.FindExtensions(ctx, &extensions.FilterOptions{Id: id, Source: source})
+	if err != nil {
+		return nil, nil, fmt.Errorf("finding extension '%s': %w", id, err)
+	}
+
+	if len(matches) == 0 {
+		return nil, nil, fmt.Errorf("%w: '%s' in source '%s'", extensions.ErrExtensionNotFound, id, source)
+	}
+
+	for i := range matches[0].Versions {
+		if matches[0].Versions[i].Version == version {
+			return matches[0], &matches[0].Versions[i], nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("%w: extension '%s' version %s in source '%s'",
+		ErrLockedVersionAbsent, id, version, source)
+}
+
+// artifactDigests returns the digests of the artifacts of the version, keyed by platform.
+func artifactDigests(version *extensions.ExtensionVersion) map[string]Digest {
+	if len(version.Artifacts) == 0 {
+		return nil
+	}
+
+	digests := map[string]Digest{}
+	for platform, artifact := range version.Artifacts {
+		if artifact.Checksum.Value == "" {
+			continue
+		}
+
+		digests[platform] = Digest{
+			Algorithm: strings.ToLower(artifact.Checksum.Algorithm),
+			Value:     strings.ToLower(artifact.Checksum.Value),
+		}
+	}
+
+	return digests
+}
+
+// verifyArtifacts returns ErrDigestMismatch when an artifact of the version doesn't have its locked digest, which
+// means the artifact was republished since the lock file was written.
+func verifyArtifacts(locked Extension, version *extensions.ExtensionVersion) error {
+	digests := artifactDigests(version)
+	for _, platform := range slices.Sorted(maps.Keys(locked.Artifacts)) {
+		expected := locked.Artifacts[platform]
+		actual, has := digests[platform]
+		if !has || !strings.EqualFold(actual.String(), expected.String()) {
+			return fmt.Errorf(
+				"%w: extension '%s' version %s for %s is locked to %s", ErrDigestMismatch,
+				locked.Id, locked.Version, platform, expected)
+		}
+	}
+
+	return nil
+}
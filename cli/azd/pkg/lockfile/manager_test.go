@@ -0,0 +1,187 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package lockfile
+
+import (
+	"context"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/extensions"
+	"github.com/azure/azure-dev/cli/azd/pkg/tool"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyArtifacts(t *testing.T) {
+	version := &extensions.ExtensionVersion{
+		Version: "1.0.0",
+		Artifacts: map[string]extensions.ExtensionArtifact{
+			"linux/amd64": {
+				URL:      "https://example.com/ext-linux-amd64.tar.gz",
+				Checksum: extensions.ExtensionChecksum{Algorithm: "SHA256", Value: "ABC"},
+			},
+			"windows/amd64": {URL: "https://example.com/ext-windows-amd64.zip"},
+		},
+	}
+
+	// Artifacts without a checksum aren't pinned.
+	digests := artifactDigests(version)
+	require.Equal(t, map[string]Digest{"linux/amd64": {Algorithm: "sha256", Value: "abc"}}, digests)
+
+	locked := Extension{Id: "ext", Version: "1.0.0", Artifacts: digests}
+	require.NoError(t, verifyArtifacts(locked, version))
+
+	t.Run("Republished", func(t *testing.T) {
+		republished := Extension{Id: "ext", Version: "1.0.0", Artifacts: map[string]Digest{
+			"linux/amd64": {Algorithm: "sha256", Value: "def"},
+		}}
+		require.ErrorIs(t, verifyArtifacts(republished, version), ErrDigestMismatch)
+	})
+
+	t.Run("PlatformRemoved", func(t *testing.T) {
+		removed := Extension{Id: "ext", Version: "1.0.0", Artifacts: map[string]Digest{
+			"darwin/arm64": {Algorithm: "sha256", Value: "abc"},
+		}}
+		require.ErrorIs(t, verifyArtifacts(removed, version), ErrDigestMismatch)
+	})
+}
+
+// fakeTools is a tool detector and installer for a machine where each tool is installed at the version in installed,
+// and installing or upgrading a tool installs the version in latest.
+type fakeTools struct {
+	installed map[string]string
+	latest    map[string]string
+	upgraded  []string
+}
+
+func (f *fakeTools) DetectTool(_ context.Context, def *tool.ToolDefinition) (*tool.ToolStatus, error) {
+	version, has := f.installed[def.Id]
+	return &tool.ToolStatus{Tool: def, Installed: has, InstalledVersion: version}, nil
+}
+
+func (f *fakeTools) DetectAll(ctx context.Context, defs []*tool.ToolDefinition) ([]*tool.ToolStatus, error) {
+	var statuses []*tool.ToolStatus
+	for _, def := range defs {
+		status, _ := f.DetectTool(ctx, def)
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (f *fakeTools) DetectSkillAgents(context.Context, *tool.ToolDefinition) ([]tool.InstalledSkillAgent, error) {
+	return nil, nil
+}
+
+func (f *fakeTools) Install(
+	_ context.Context, def *tool.ToolDefinition, _ ...tool.InstallOption) (*tool.InstallResult, error) {
+	f.installed[def.Id] = f.latest[def.Id]
+	return &tool.InstallResult{Tool: def, Success: true, InstalledVersion: f.latest[def.Id]}, nil
+}
+
+func (f *fakeTools) Upgrade(
+	ctx context.Context, def *tool.ToolDefinition, opts ...tool.InstallOption) (*tool.InstallResult, error) {
+	f.upgraded = append(f.upgraded, def.Id)
+	return f.Install(ctx, def, opts...)
+}
+
+func (f *fakeTools) AvailableSkillAgents(context.Context, *tool.ToolDefinition) ([]string, []string) {
+	return nil, nil
+}
+
+func (f *fakeTools) Uninstall(
+	_ context.Context, def *tool.ToolDefinition, _ ...tool.InstallOption) (*tool.InstallResult, error) {
+	delete(f.installed, def.Id)
+	return &tool.InstallResult{Tool: def, Success: true}, nil
+}
+
+func TestRestoreTools(t *testing.T) {
+	lock := &LockFile{Version: SchemaVersion, Tools: []Tool{{Id: "bicep-cli", Version: "0.45.15"}}}
+
+	restore := func(
+		t *testing.T, installed map[string]string, latest string, options RestoreOptions,
+	) (*RestoreResult, *fakeTools, error) {
+		fake := &fakeTools{installed: installed, latest: map[string]string{"bicep-cli": latest}}
+		manager := NewManager(nil, nil, tool.NewManager(fake, fake, nil))
+		result, err := manager.Restore(t.Context(), lock, options)
+		return result, fake, err
+	}
+
+	t.Run("UpToDate", func(t *testing.T) {
+		result, fake, err := restore(t, map[string]string{"bicep-cli": "0.45.15"}, "0.46.0", RestoreOptions{})
+		require.NoError(t, err)
+		require.Empty(t, result.Tools)
+		require.Empty(t, fake.upgraded)
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		result, _, err := restore(t, map[string]string{}, "0.45.15", RestoreOptions{})
+		require.NoError(t, err)
+		require.Equal(t, lock.Tools, result.Tools)
+	})
+
+	t.Run("Older", func(t *testing.T) {
+		result, fake, err := restore(t, map[string]string{"bicep-cli": "0.44.0"}, "0.45.15", RestoreOptions{})
+		require.NoError(t, err)
+		require.Equal(t, lock.Tools, result.Tools)
+		require.Equal(t, []string{"bicep-cli"}, fake.upgraded)
+		require.Equal(t, "0.45.15", fake.installed["bicep-cli"])
+	})
+
+	t.Run("UpgradePastLockedVersion", func(t *testing.T) {
+		_, _, err := restore(t, map[string]string{"bicep-cli": "0.44.0"}, "0.46.0", RestoreOptions{})
+		require.ErrorIs(t, err, ErrToolVersionMismatch)
+		require.ErrorContains(t, err, "'bicep-cli' is locked to version 0.45.15, but version 0.46.0 is installed")
+	})
+
+	t.Run("Newer", func(t *testing.T) {
+		_, fake, err := restore(t, map[string]string{"bicep-cli": "0.46.0"}, "0.46.0", RestoreOptions{})
+		require.ErrorIs(t, err, ErrToolVersionMismatch)
+		require.Empty(t, fake.upgraded)
+	})
+
+	t.Run("NewerCompatible", func(t *testing.T) {
+		result, fake, err := restore(t, map[string]string{"bicep-cli": "0.45.16"}, "0.45.16", RestoreOptions{})
+		require.NoError(t, err)
+		require.Empty(t, fake.upgraded)
+		require.Empty(t, result.MismatchedTools)
+		require.Equal(t, []ToolMismatch{
+			{Id: "bicep-cli", Version: "0.45.15", InstalledVersion: "0.45.16"},
+		}, result.NewerTools)
+	})
+
+	t.Run("NewerAllowed", func(t *testing.T) {
+		result, _, err := restore(
+			t, map[string]string{"bicep-cli": "0.46.0"}, "0.46.0", RestoreOptions{AllowToolVersionMismatch: true})
+		require.NoError(t, err)
+		require.Equal(t, []ToolMismatch{
+			{Id: "bicep-cli", Version: "0.45.15", InstalledVersion: "0.46.0"},
+		}, result.MismatchedTools)
+	})
+}
+
+func TestCaptureTools(t *testing.T) {
+	fake := &fakeTools{installed: map[string]string{"bicep-cli": "0.45.15", "helm": "3.17.0", "kubectl": "1.32.0"}}
+	manager := NewManager(nil, nil, tool.NewManager(fake, fake, nil))
+
+	t.Run("RequiredOnly", func(t *testing.T) {
+		lock, err := manager.Capture(t.Context(), CaptureOptions{
+			Template:           "todo-nodejs-mongo@0.0.1-beta",
+			TemplateRepository: "https://github.com/Azure-Samples/todo-nodejs-mongo",
+			TemplateCommit:     "0123456789abcdef0123456789abcdef01234567",
+			RequiredTools:      []string{"kubectl", "bicep-cli", "kubectl"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []Tool{{Id: "bicep-cli", Version: "0.45.15"}, {Id: "kubectl", Version: "1.32.0"}}, lock.Tools)
+		require.Equal(t, &Template{
+			Name:       "todo-nodejs-mongo",
+			Version:    "0.0.1-beta",
+			Repository: "https://github.com/Azure-Samples/todo-nodejs-mongo",
+			Commit:     "0123456789abcdef0123456789abcdef01234567",
+		}, lock.Template)
+	})
+
+	t.Run("NotInstalled", func(t *testing.T) {
+		_, err := manager.Capture(t.Context(), CaptureOptions{RequiredTools: []string{"terraform"}})
+		require.ErrorContains(t, err, "tool 'terraform' required by the project isn't installed")
+	})
+}
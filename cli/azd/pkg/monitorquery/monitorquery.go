@@ -0,0 +1,289 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package monitorquery runs KQL queries against the Application Insights resources and the Log Analytics workspaces
+// of an environment.
+package monitorquery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/operationalinsights/armoperationalinsights/v2"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+)
+
+// appInsightsApiVersion is the API version used to read the application ID of an Application Insights component.
+const appInsightsApiVersion = "2020-02-02"
+
+// ErrUnsupportedResource is returned when a query is run against a resource that isn't an Application Insights
+// component or a Log Analytics workspace.
+var ErrUnsupportedResource = errors.New("querying this resource type isn't supported")
+
+// Source is the kind of resource a query runs against.
+type Source string
+
+const (
+	// SourceAppInsights queries the tables of an Application Insights component, such as requests and traces.
+	SourceAppInsights Source = "appInsights"
+	// SourceLogAnalytics queries the tables of a Log Analytics workspace, such as ContainerAppConsoleLogs_CL.
+	SourceLogAnalytics Source = "logAnalytics"
+)
+
+// ResourceType returns the type of the resources that queries of the source run against.
+func (s Source) ResourceType() azapi.AzureResourceType {
+	if s == SourceLogAnalytics {
+		return azapi.AzureResourceTypeLogAnalyticsWorkspace
+	}
+
+	return azapi.AzureResourceTypeAppInsightComponent
+}
+
+// Query is a named KQL query.
+type Query struct {
+	Name        string
+	Description string
+	Source      Source
+	Kql         string
+}
+
+// Result is the first table returned by a query.
+type Result struct {
+	Columns []string
+	Rows    [][]any
+}
+
+// Records returns the rows of the result as objects keyed by column name.
+func (r *Result) Records() []map[string]any {
+	records := make([]map[string]any, len(r.Rows))
+	for i, row := range r.Rows {
+		record := make(map[string]any, len(r.Columns))
+		for j, column := range r.Columns {
+			if j < len(row) {
+				record[column] = row[j]
+			}
+		}
+		records[i] = record
+	}
+
+	return records
+}
+
+// queryResponse is the response of the query APIs of Application Insights and Log Analytics, which share a format.
+type queryResponse struct {
+	Tables []struct {
+		Columns []struct {
+			Name string `json:"name"`
+		} `json:"columns"`
+		Rows [][]any `json:"rows"`
+	} `json:"tables"`
+}
+
+// Client runs queries against Application Insights components and Log Analytics workspaces.
+type Client struct {
+	credential azcore.TokenCredential
+	cloud      *cloud.Cloud
+	clientOpts *arm.ClientOptions
+}
+
+// NewClient creates a Client that authenticates with credential.
+func NewClient(credential azcore.TokenCredential, cloud *cloud.Cloud, clientOpts *arm.ClientOptions) *Client {
+	return &Client{
+		credential: credential,
+		cloud:      cloud,
+		clientOpts: clientOpts,
+	}
+}
+
+// Run runs kql against the Application Insights component or the Log Analytics workspace with the given resource ID.
+func (c *Client) Run(ctx context.Context, resourceId string, kql string) (*Result, error) {
+	id, err := arm.ParseResourceID(resourceId)
+	if err != nil {
+		return nil, fmt.Errorf("parsing resource ID: %w", err)
+	}
+
+	switch {
+	case strings.EqualFold(id.ResourceType.String(), string(azapi.AzureResourceTypeAppInsightComponent)):
+		appId, err := c.applicationId(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.query(ctx, c.cloud.ApplicationInsightsEndpoint,
+			fmt.Sprintf("%s/v1/apps/%s/query", c.cloud.ApplicationInsightsEndpoint, url.PathEscape(appId)), kql)
+	case strings.EqualFold(id.ResourceType.String(), string(azapi.AzureResourceTypeLogAnalyticsWorkspace)):
+		workspaceId, err := c.workspaceId(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.query(ctx, c.cloud.LogAnalyticsEndpoint,
+			fmt.Sprintf("%s/v1/workspaces/%s/query", c.cloud.LogAnalyticsEndpoint, url.PathEscape(workspaceId)), kql)
+	default:
+		return nil, fmt.Errorf("%s: %w", id.ResourceType.String(), ErrUnsupportedResource)
+	}
+}
+
+// applicationId returns the application ID of an Application Insights component, which identifies it in the query API.
+func (c *Client) applicationId(ctx context.Context, id *arm.ResourceID) (string, error) {
+	client, err := armresources.NewClient(id.SubscriptionID, c.credential, c.clientOpts)
+	if err != nil {
+		return "", fmt.Errorf("creating resources client: %w", err)
+	}
+
+	res, err := client.GetByID(ctx, id.String(), appInsightsApiVersion, nil)
+	if err != nil {
+		return "", fmt.Errorf("getting Application Insights component %s: %w", id.Name, err)
+	}
+
+	if properties, ok := res.Properties.(map[string]any); ok {
+		if appId, ok := properties["AppId"].(string); ok && appId != "" {
+			return appId, nil
+		}
+	}
+
+	return "", fmt.Errorf("the Application Insights component %s has no application ID", id.Name)
+}
+
+// workspaceId returns the customer ID of a Log Analytics workspace, which identifies it in the query API.
+func (c *Client) workspaceId(ctx context.Context, id *arm.ResourceID) (string, error) {
+	client, err := armoperationalinsights.NewWorkspacesClient(id.SubscriptionID, c.credential, c.clientOpts)
+	if err != nil {
+		return "", fmt.Errorf("creating workspaces client: %w", err)
+	}
+
+	res, err := client.Get(ctx, id.ResourceGroupName, id.Name, nil)
+	if err != nil {
+		return "", fmt.Errorf("getting Log Analytics workspace %s: %w", id.Name, err)
+	}
+
+	if res.Properties == nil || res.Properties.CustomerID == nil || *res.Properties.CustomerID == "" {
+		return "", fmt.Errorf("the Log Analytics workspace %s has no workspace ID", id.Name)
+	}
+
+	return *res.Properties.CustomerID, nil
+}
+
+// query posts kql to the query API at endpoint, authenticating with a token for audience.
+func (c *Client) query(ctx context.Context, audience string, endpoint string, kql string) (*Result, error) {
+	clientOptions := &policy.ClientOptions{}
+	if c.clientOpts != nil {
+		clientOptions = &c.clientOpts.ClientOptions
+	}
+	pipeline := runtime.NewPipeline("monitorquery", "1.0.0", runtime.PipelineOptions{
+		PerRetry: []policy.Policy{
+			runtime.NewBearerTokenPolicy(c.credential, []string{audience + "/.default"}, nil),
+		},
+	}, clientOptions)
+
+	req, err := runtime.NewRequest(ctx, http.MethodPost, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("creating query request: %w", err)
+	}
+	if err := runtime.MarshalAsJSON(req, map[string]string{"query": kql}); err != nil {
+		return nil, err
+	}
+
+	res, err := pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !runtime.HasStatusCode(res, http.StatusOK) {
+		return nil, runtime.NewResponseError(res)
+	}
+
+	var response queryResponse
+	if err := runtime.UnmarshalAsJSON(res, &response); err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	if len(response.Tables) > 0 {
+		for _, column := range response.Tables[0].Columns {
+			result.Columns = append(result.Columns, column.Name)
+		}
+		result.Rows = response.Tables[0].Rows
+	}
+
+	return result, nil
+}
+
+// Predefined are the queries available to every project, run against its Application Insights component.
+var Predefined = []Query{
+	{
+		Name:        "requests",
+		Description: "Requests of the last hour by service and operation, with their failures and average duration.",
+		Source:      SourceAppInsights,
+		Kql: "requests" +
+			" | where timestamp > ago(1h)" +
+			" | summarize requests = count(), failed = countif(success == false), avgDurationMs = round(avg(duration), 1)" +
+			" by service = cloud_RoleName, operation = name" +
+			" | order by requests desc" +
+			" | take 50",
+	},
+	{
+		Name:        "failures",
+		Description: "The most recent failed requests of the last hour.",
+		Source:      SourceAppInsights,
+		Kql: "requests" +
+			" | where timestamp > ago(1h) and success == false" +
+			" | project timestamp, service = cloud_RoleName, operation = name, resultCode, durationMs = round(duration, 1)" +
+			" | order by timestamp desc" +
+			" | take 50",
+	},
+	{
+		Name:        "exceptions",
+		Description: "Exceptions of the last hour by service and type.",
+		Source:      SourceAppInsights,
+		Kql: "exceptions" +
+			" | where timestamp > ago(1h)" +
+			" | summarize exceptions = count(), lastSeen = max(timestamp), message = any(outerMessage)" +
+			" by service = cloud_RoleName, type" +
+			" | order by exceptions desc" +
+			" | take 50",
+	},
+	{
+		Name:        "traces",
+		Description: "The most recent traces of the last hour.",
+		Source:      SourceAppInsights,
+		Kql: "traces" +
+			" | where timestamp > ago(1h)" +
+			" | project timestamp, service = cloud_RoleName, severityLevel, message" +
+			" | order by timestamp desc" +
+			" | take 100",
+	},
+	{
+		Name:        "dependencies",
+		Description: "Dependency calls of the last hour by service and target, with their failures and average duration.",
+		Source:      SourceAppInsights,
+		Kql: "dependencies" +
+			" | where timestamp > ago(1h)" +
+			" | summarize calls = count(), failed = countif(success == false), avgDurationMs = round(avg(duration), 1)" +
+			" by service = cloud_RoleName, type, target" +
+			" | order by calls desc" +
+			" | take 50",
+	},
+}
+
+// Find returns the query with the given name from queries, or else from the predefined queries.
+func Find(name string, queries []Query) (Query, bool) {
+	for _, candidates := range [][]Query{queries, Predefined} {
+		if index := slices.IndexFunc(candidates, func(q Query) bool { return q.Name == name }); index >= 0 {
+			return candidates[index], true
+		}
+	}
+
+	return Query{}, false
+}
@@ -0,0 +1,109 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package monitorquery
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/operationalinsights/armoperationalinsights/v2"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func mockQuery(mockContext *mocks.MockContext, host string, path string, query *string) {
+	mockContext.HttpClient.When(func(req *http.Request) bool {
+		return req.Method == http.MethodPost && req.URL.Host == host && req.URL.Path == path
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		var body map[string]string
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+		*query = body["query"]
+
+		return mocks.CreateHttpResponseWithBody(req, http.StatusOK, map[string]any{
+			"tables": []any{map[string]any{
+				"name":    "PrimaryResult",
+				"columns": []any{map[string]any{"name": "operation"}, map[string]any{"name": "requests"}},
+				"rows":    [][]any{{"GET /", 12}, {"GET /health", 3}},
+			}},
+		})
+	})
+}
+
+func TestRun(t *testing.T) {
+	t.Run("AppInsights", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		mockContext.HttpClient.When(func(req *http.Request) bool {
+			return req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "/components/appi")
+		}).RespondFn(func(req *http.Request) (*http.Response, error) {
+			require.Equal(t, appInsightsApiVersion, req.URL.Query().Get("api-version"))
+			return mocks.CreateHttpResponseWithBody(req, http.StatusOK, armresources.GenericResource{
+				Properties: map[string]any{"AppId": "APP_ID"},
+			})
+		})
+
+		var query string
+		mockQuery(mockContext, "api.applicationinsights.io", "/v1/apps/APP_ID/query", &query)
+
+		client := NewClient(mockContext.Credentials, cloud.AzurePublic(), mockContext.ArmClientOptions)
+		result, err := client.Run(*mockContext.Context,
+			"/subscriptions/SUB/resourceGroups/RG/providers/Microsoft.Insights/components/appi", "requests | take 2")
+		require.NoError(t, err)
+		require.Equal(t, "requests | take 2", query)
+		require.Equal(t, []string{"operation", "requests"}, result.Columns)
+		require.Equal(t, []map[string]any{
+			{"operation": "GET /", "requests": float64(12)},
+			{"operation": "GET /health", "requests": float64(3)},
+		}, result.Records())
+	})
+
+	t.Run("LogAnalytics", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		mockContext.HttpClient.When(func(req *http.Request) bool {
+			return req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "/workspaces/log")
+		}).RespondFn(func(req *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(req, http.StatusOK, armoperationalinsights.Workspace{
+				Properties: &armoperationalinsights.WorkspaceProperties{CustomerID: new("WORKSPACE_ID")},
+			})
+		})
+
+		var query string
+		mockQuery(mockContext, "api.loganalytics.io", "/v1/workspaces/WORKSPACE_ID/query", &query)
+
+		client := NewClient(mockContext.Credentials, cloud.AzurePublic(), mockContext.ArmClientOptions)
+		result, err := client.Run(*mockContext.Context,
+			"/subscriptions/SUB/resourceGroups/RG/providers/Microsoft.OperationalInsights/workspaces/log",
+			"ContainerAppConsoleLogs_CL")
+		require.NoError(t, err)
+		require.Equal(t, "ContainerAppConsoleLogs_CL", query)
+		require.Len(t, result.Rows, 2)
+	})
+
+	t.Run("UnsupportedResource", func(t *testing.T) {
+		client := NewClient(nil, cloud.AzurePublic(), nil)
+		_, err := client.Run(t.Context(),
+			"/subscriptions/SUB/resourceGroups/RG/providers/Microsoft.App/containerApps/api", "requests")
+		require.ErrorIs(t, err, ErrUnsupportedResource)
+	})
+}
+
+func TestFind(t *testing.T) {
+	custom := []Query{{Name: "requests", Kql: "requests | take 1"}, {Name: "slow", Kql: "requests"}}
+
+	query, found := Find("requests", custom)
+	require.True(t, found)
+	require.Equal(t, "requests | take 1", query.Kql)
+
+	query, found = Find("exceptions", custom)
+	require.True(t, found)
+	require.Equal(t, SourceAppInsights, query.Source)
+
+	_, found = Find("missing", custom)
+	require.False(t, found)
+}
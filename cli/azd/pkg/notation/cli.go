@@ -0,0 +1,107 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package notation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+)
+
+// The notation plugin signing with keys of Azure Key Vault
+const azureKeyVaultPlugin = "azure-kv"
+
+// Cli is a wrapper around the Notation CLI
+type Cli struct {
+	commandRunner exec.CommandRunner
+}
+
+// NewCli creates a new instance of the Notation CLI wrapper
+func NewCli(commandRunner exec.CommandRunner) *Cli {
+	return &Cli{
+		commandRunner: commandRunner,
+	}
+}
+
+// Gets the name of the Tool
+func (cli *Cli) Name() string {
+	return "Notation"
+}
+
+// Returns the installation URL to install the Notation CLI
+func (cli *Cli) InstallUrl() string {
+	return "https://notaryproject.dev/docs/user-guides/installation/cli/"
+}
+
+// Checks whether or not the Notation CLI is installed and available within the PATH
+func (cli *Cli) CheckInstalled(ctx context.Context) error {
+	return cli.commandRunner.ToolInPath("notation")
+}
+
+// Sign signs the image with a key of Azure Key Vault, through the azure-kv plugin. pluginConfig is passed to the plugin,
+// for example 'self_signed=true' for self-signed certificates.
+func (cli *Cli) Sign(ctx context.Context, image string, keyId string, pluginConfig map[string]string) error {
+	runArgs := exec.NewRunArgs(
+		"notation", "sign", "--signature-format", "cose", "--plugin", azureKeyVaultPlugin, "--id", keyId,
+	)
+	for _, key := range slices.Sorted(maps.Keys(pluginConfig)) {
+		runArgs = runArgs.AppendParams("--plugin-config", fmt.Sprintf("%s=%s", key, pluginConfig[key]))
+	}
+
+	if _, err := cli.commandRunner.Run(ctx, runArgs.AppendParams(image)); err != nil {
+		return fmt.Errorf("signing image %s: %w", image, err)
+	}
+
+	return nil
+}
+
+// Verify verifies the signatures of the image with the trust policy of the user.
+func (cli *Cli) Verify(ctx context.Context, image string) error {
+	if _, err := cli.commandRunner.Run(ctx, exec.NewRunArgs("notation", "verify", image)); err != nil {
+		return fmt.Errorf("verifying signature of image %s: %w", image, err)
+	}
+
+	return nil
+}
+
+// inspectResult is the JSON output of 'notation inspect'
+type inspectResult struct {
+	Signatures []struct {
+		Digest           string `json:"digest"`
+		SignedAttributes struct {
+			SigningTime time.Time `json:"signingTime"`
+		} `json:"signedAttributes"`
+	} `json:"signatures"`
+}
+
+// LatestSignature returns the digest of the latest signature of the image.
+func (cli *Cli) LatestSignature(ctx context.Context, image string) (string, error) {
+	res, err := cli.commandRunner.Run(ctx, exec.NewRunArgs("notation", "inspect", "--output", "json", image))
+	if err != nil {
+		return "", fmt.Errorf("inspecting signatures of image %s: %w", image, err)
+	}
+
+	var result inspectResult
+	if err := json.Unmarshal([]byte(res.Stdout), &result); err != nil {
+		return "", fmt.Errorf("reading signatures of image %s: %w", image, err)
+	}
+
+	if len(result.Signatures) == 0 {
+		return "", fmt.Errorf("image %s has no signatures", image)
+	}
+
+	latest := result.Signatures[0]
+	for _, signature := range result.Signatures[1:] {
+		if signature.SignedAttributes.SigningTime.After(latest.SignedAttributes.SigningTime) {
+			latest = signature
+		}
+	}
+
+	return latest.Digest, nil
+}
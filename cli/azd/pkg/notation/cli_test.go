@@ -0,0 +1,63 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package notation
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+const testImage = "contoso.azurecr.io/app/web@sha256:4f1c"
+
+func TestSign(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	var signArgs []string
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "notation" && args.Args[0] == "sign"
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		signArgs = args.Args
+		return exec.NewRunResult(0, "Successfully signed "+testImage, ""), nil
+	})
+
+	cli := NewCli(mockContext.CommandRunner)
+	err := cli.Sign(*mockContext.Context, testImage, "https://contoso.vault.azure.net/keys/signing/1", map[string]string{
+		"self_signed":     "true",
+		"credential_type": "azurecli",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"sign", "--signature-format", "cose", "--plugin", "azure-kv",
+		"--id", "https://contoso.vault.azure.net/keys/signing/1",
+		"--plugin-config", "credential_type=azurecli",
+		"--plugin-config", "self_signed=true",
+		testImage,
+	}, signArgs)
+}
+
+func TestLatestSignature(t *testing.T) {
+	inspect := func(t *testing.T, stdout string) (string, error) {
+		mockContext := mocks.NewMockContext(t.Context())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return args.Cmd == "notation" && args.Args[0] == "inspect"
+		}).Respond(exec.NewRunResult(0, stdout, ""))
+
+		return NewCli(mockContext.CommandRunner).LatestSignature(*mockContext.Context, testImage)
+	}
+
+	digest, err := inspect(t, `{
+		"signatures": [
+			{"digest": "sha256:older", "signedAttributes": {"signingTime": "2026-10-01T10:00:00Z"}},
+			{"digest": "sha256:latest", "signedAttributes": {"signingTime": "2026-10-18T10:00:00Z"}},
+			{"digest": "sha256:old", "signedAttributes": {"signingTime": "2026-10-02T10:00:00Z"}}
+		]
+	}`)
+	require.NoError(t, err)
+	require.Equal(t, "sha256:latest", digest)
+
+	_, err = inspect(t, `{"signatures": []}`)
+	require.ErrorContains(t, err, "image "+testImage+" has no signatures")
+}
@@ -44,6 +44,11 @@ func (e ExpandableString) IsZero() bool {
 	return e.Empty()
 }
 
+// Template returns the template of the string, without evaluating it.
+func (e ExpandableString) Template() string {
+	return e.template
+}
+
 // Envsubst evaluates the template, substituting values as [envsubst.Eval] would.
 func (e ExpandableString) Envsubst(mapping func(string) string) (string, error) {
 	return envsubst.Eval(e.template, mapping)
@@ -16,6 +16,7 @@ const (
 	outputFlagName               = "output"
 	queryFlagName                = "query"
 	supportedFormatterAnnotation = "github.com/azure/azure-dev/cli/azd/pkg/output/supportedOutputFormatters"
+	jmesPathQueryAnnotation      = "github.com/azure/azure-dev/cli/azd/pkg/output/jmesPathQuery"
 )
 
 func AddOutputFlag(f *pflag.FlagSet, s *string, supportedFormats []Format, defaultFormat Format) {
@@ -40,8 +41,13 @@ func AddOutputParam(cmd *cobra.Command, supportedFormats []Format, defaultFormat
 }
 
 // AddQueryParam adds a hidden --query flag to the command for JMESPath filtering.
-// This should only be called for commands that support JSON output format.
+// This should only be called for commands that support JSON output format. Commands that define their own --query
+// flag, such as azd monitor, don't support JMESPath filtering.
 func AddQueryParam(cmd *cobra.Command) {
+	if cmd.Flags().Lookup(queryFlagName) != nil {
+		return
+	}
+
 	cmd.Flags().String(
 		queryFlagName,
 		"",
@@ -49,6 +55,7 @@ func AddQueryParam(cmd *cobra.Command) {
 	)
 	//preview:flag hide --query
 	_ = cmd.Flags().MarkHidden(queryFlagName)
+	_ = cmd.Flags().SetAnnotation(queryFlagName, jmesPathQueryAnnotation, []string{"true"})
 }
 
 func GetCommandFormatter(cmd *cobra.Command) (Formatter, error) {
@@ -72,7 +79,7 @@ func GetCommandFormatter(cmd *cobra.Command) (Formatter, error) {
 
 	// Check for --query flag and validate it requires JSON output
 	queryVal, queryErr := cmd.Flags().GetString(queryFlagName)
-	if queryErr == nil && queryVal != "" {
+	if queryErr == nil && queryVal != "" && isJmesPathQuery(cmd) {
 		if desiredFormatter != string(JsonFormat) {
 			return nil, fmt.Errorf("--query requires --output json or -o json")
 		}
@@ -81,3 +88,9 @@ func GetCommandFormatter(cmd *cobra.Command) (Formatter, error) {
 
 	return NewFormatter(desiredFormatter)
 }
+
+// isJmesPathQuery returns whether the --query flag of the command is the JMESPath query added by AddQueryParam.
+func isJmesPathQuery(cmd *cobra.Command) bool {
+	_, has := cmd.Flags().Lookup(queryFlagName).Annotations[jmesPathQueryAnnotation]
+	return has
+}
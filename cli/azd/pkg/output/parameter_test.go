@@ -31,3 +31,17 @@ func TestGetCommandFormatter_QueryWithJson(t *testing.T) {
 	require.True(t, ok)
 	require.Equal(t, "items[0]", jf.Query)
 }
+
+func TestAddQueryParam_KeepsCommandFlag(t *testing.T) {
+	t.Parallel()
+	cmd := &cobra.Command{Use: "x"}
+	cmd.Flags().String("query", "", "The name of the query to run.")
+	AddOutputParam(cmd, []Format{JsonFormat, TableFormat}, TableFormat)
+	AddQueryParam(cmd)
+	require.False(t, cmd.Flags().Lookup("query").Hidden)
+
+	require.NoError(t, cmd.ParseFlags([]string{"--query", "failures"}))
+	f, err := GetCommandFormatter(cmd)
+	require.NoError(t, err)
+	require.Equal(t, TableFormat, f.Kind())
+}
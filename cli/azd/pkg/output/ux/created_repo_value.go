@@ -21,6 +21,15 @@ type CreatedRepoValue struct {
 	Name   string
 	Kind   GitHubValueKind
 	Action string
+	// Environment is set for values scoped to a deployment environment of the repo.
+	Environment string
+}
+
+func (cr *CreatedRepoValue) scope() string {
+	if cr.Environment != "" {
+		return cr.Environment + " environment"
+	}
+	return "repo"
 }
 
 func (cr *CreatedRepoValue) ToString(currentIndentation string) string {
@@ -28,11 +37,11 @@ func (cr *CreatedRepoValue) ToString(currentIndentation string) string {
 	if action == "" {
 		action = "Setting"
 	}
-	return fmt.Sprintf("%s%s %s %s repo %s", currentIndentation, donePrefix, action, cr.Name, cr.Kind)
+	return fmt.Sprintf("%s%s %s %s %s %s", currentIndentation, donePrefix, action, cr.Name, cr.scope(), cr.Kind)
 }
 
 func (cr *CreatedRepoValue) MarshalJSON() ([]byte, error) {
 	// reusing the same envelope from console messages
 	return json.Marshal(output.EventForMessage(
-		fmt.Sprintf("%s Setting %s repo %s", donePrefix, cr.Name, cr.Kind)))
+		fmt.Sprintf("%s Setting %s %s %s", donePrefix, cr.Name, cr.scope(), cr.Kind)))
 }
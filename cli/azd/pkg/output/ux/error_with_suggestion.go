@@ -18,10 +18,17 @@ import (
 //  2. Suggestion (actionable next steps)
 //  3. Reference links (optional, as a list)
 //  4. Original error (grey, de-emphasized technical details)
+//  5. Error code and category (grey, optional)
 type ErrorWithSuggestion struct {
 	// Err is the original underlying error
 	Err error
 
+	// Code is the stable error code (optional)
+	Code string
+
+	// Category is the area of the error (optional)
+	Category errorhandler.ErrorCategory
+
 	// Message is a user-friendly explanation of what went wrong
 	Message string
 
@@ -69,6 +76,15 @@ func (e *ErrorWithSuggestion) ToString(currentIndentation string) string {
 			output.WithGrayFormat(e.Err.Error())))
 	}
 
+	// 5. Error code, for support and searching
+	if e.Code != "" {
+		code := fmt.Sprintf("Error code: %s", e.Code)
+		if e.Category != "" {
+			code += fmt.Sprintf(" (%s)", e.Category)
+		}
+		sb.WriteString(fmt.Sprintf("\n%s\n", output.WithGrayFormat(code)))
+	}
+
 	return sb.String()
 }
 
@@ -89,12 +105,16 @@ func (e *ErrorWithSuggestion) MarshalJSON() ([]byte, error) {
 	}
 
 	result := struct {
-		Error      string     `json:"error"`
-		Message    string     `json:"message,omitempty"`
-		Suggestion string     `json:"suggestion,omitempty"`
-		Links      []jsonLink `json:"links,omitempty"`
+		Error      string                     `json:"error"`
+		Code       string                     `json:"code,omitempty"`
+		Category   errorhandler.ErrorCategory `json:"category,omitempty"`
+		Message    string                     `json:"message,omitempty"`
+		Suggestion string                     `json:"suggestion,omitempty"`
+		Links      []jsonLink                 `json:"links,omitempty"`
 	}{
 		Error:      errStr,
+		Code:       e.Code,
+		Category:   e.Category,
 		Message:    e.Message,
 		Suggestion: e.Suggestion,
 		Links:      links,
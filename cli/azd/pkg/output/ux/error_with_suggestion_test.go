@@ -149,3 +149,32 @@ func TestErrorWithSuggestion_MarshalJSON_OmitsEmpty(t *testing.T) {
 	assert.False(t, hasSuggestion)
 	assert.False(t, hasLinks)
 }
+
+func TestErrorWithSuggestion_Code(t *testing.T) {
+	err := &ErrorWithSuggestion{
+		Err:        errors.New("InsufficientQuota: raw error details here"),
+		Code:       "insufficient_quota",
+		Category:   errorhandler.ErrorCategoryProvisioning,
+		Message:    "Your subscription has insufficient quota for this resource.",
+		Suggestion: "Request a quota increase.",
+	}
+
+	assert.Contains(t, err.ToString(""), "Error code: insufficient_quota (provisioning)")
+
+	data, marshalErr := json.Marshal(err)
+	require.NoError(t, marshalErr)
+
+	var result map[string]any
+	require.NoError(t, json.Unmarshal(data, &result))
+	assert.Equal(t, "insufficient_quota", result["code"])
+	assert.Equal(t, "provisioning", result["category"])
+
+	// Errors without a code don't show one.
+	err.Code = ""
+	err.Category = ""
+	assert.NotContains(t, err.ToString(""), "Error code:")
+
+	data, marshalErr = json.Marshal(err)
+	require.NoError(t, marshalErr)
+	assert.NotContains(t, string(data), `"code"`)
+}
@@ -29,6 +29,7 @@ const (
 	OperationTypeIgnore      OperationType = "Ignore"
 	OperationTypeModify      OperationType = "Modify"
 	OperationTypeNoChange    OperationType = "NoChange"
+	OperationTypeReplace     OperationType = "Replace"
 	OperationTypeUnsupported OperationType = "Unsupported"
 )
 
@@ -68,7 +69,8 @@ func colorType(opType OperationType) func(string, ...any) string {
 	case OperationTypeNoChange,
 		OperationTypeIgnore:
 		final = output.WithGrayFormat
-	case OperationTypeDelete:
+	case OperationTypeDelete,
+		OperationTypeReplace:
 		final = color.RedString
 	case OperationTypeModify:
 		final = color.YellowString
@@ -0,0 +1,87 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package ux
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// ProvisionTimings defines a ux item for displaying how long each resource and module took to deploy.
+type ProvisionTimings struct {
+	// Resources in display order, usually slowest first.
+	Resources []*TimedResource
+}
+
+// TimedResource is a resource or module with its deployment duration.
+type TimedResource struct {
+	Name     string
+	Type     string
+	Module   bool
+	Failed   bool
+	Duration time.Duration
+}
+
+func (pt *ProvisionTimings) ToString(currentIndentation string) string {
+	if len(pt.Resources) == 0 {
+		return ""
+	}
+
+	durations := make([]string, len(pt.Resources))
+	types := make([]string, len(pt.Resources))
+
+	var maxDurationLen int
+	var maxTypeLen int
+	for index, resource := range pt.Resources {
+		durations[index] = timingDuration(resource.Duration)
+		types[index] = resource.Type
+		if resource.Module {
+			types[index] = "Module"
+		}
+
+		maxDurationLen = max(maxDurationLen, len(durations[index]))
+		maxTypeLen = max(maxTypeLen, len(types[index]))
+	}
+
+	lines := make([]string, len(pt.Resources))
+	for index, resource := range pt.Resources {
+		line := fmt.Sprintf("%s%*s  %-*s  %s",
+			currentIndentation,
+			maxDurationLen,
+			durations[index],
+			maxTypeLen,
+			types[index],
+			resource.Name,
+		)
+		if resource.Failed {
+			line += output.WithErrorFormat(" (failed)")
+		}
+
+		lines[index] = line
+	}
+
+	return fmt.Sprintf("%sDeployment timings (slowest first):\n\n%s", currentIndentation, strings.Join(lines, "\n"))
+}
+
+// timingDuration formats d to the second, or to the millisecond when it is shorter than a second.
+func timingDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+
+	return d.Round(time.Second).String()
+}
+
+func (pt *ProvisionTimings) MarshalJSON() ([]byte, error) {
+	return json.Marshal(contracts.EventEnvelope{
+		Type:      contracts.ConsoleMessageEventDataType,
+		Timestamp: time.Now(),
+		Data:      pt.Resources,
+	})
+}
@@ -0,0 +1,32 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package ux
+
+import (
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/test/snapshot"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvisionTimings(t *testing.T) {
+	pt := &ProvisionTimings{
+		Resources: []*TimedResource{
+			{Name: "resources", Type: "Microsoft.Resources/deployments", Module: true, Duration: 4*time.Minute + 12*time.Second},
+			{Name: "app-web", Type: "Microsoft.Web/sites", Duration: 92*time.Second + 400*time.Millisecond},
+			{Name: "kv-web", Type: "Microsoft.KeyVault/vaults", Failed: true, Duration: 21 * time.Second},
+			{Name: "id-web", Type: "Microsoft.ManagedIdentity/userAssignedIdentities", Duration: 850 * time.Millisecond},
+		},
+	}
+
+	output := pt.ToString("  ")
+	snapshot.SnapshotT(t, output)
+}
+
+func TestProvisionTimingsEmpty(t *testing.T) {
+	pt := &ProvisionTimings{}
+
+	require.Equal(t, "", pt.ToString("  "))
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/fatih/color"
@@ -17,6 +18,33 @@ type ShowService struct {
 	IngresUrl   string
 	Env         map[string]string
 	DisplayType string
+
+	// State, Replicas and LastDeployed are the live status of the service, shown by `azd show --live`.
+	State        string
+	Replicas     *int
+	LastDeployed *time.Time
+}
+
+// liveStatus returns the live status of the service, such as "(running, 2 replicas, deployed 2024-01-02 15:04 UTC)",
+// or an empty string when it wasn't queried.
+func (s *ShowService) liveStatus() string {
+	if s.State == "" {
+		return ""
+	}
+
+	parts := []string{s.State}
+	if s.Replicas != nil {
+		if *s.Replicas == 1 {
+			parts = append(parts, "1 replica")
+		} else {
+			parts = append(parts, fmt.Sprintf("%d replicas", *s.Replicas))
+		}
+	}
+	if s.LastDeployed != nil {
+		parts = append(parts, "deployed "+s.LastDeployed.UTC().Format("2006-01-02 15:04 UTC"))
+	}
+
+	return "(" + strings.Join(parts, ", ") + ")"
 }
 
 func (s *ShowService) ToString(currentIndentation string) string {
@@ -109,6 +137,9 @@ func services(services []*ShowService) string {
 			output.WithHighLightFormat(service.Name),
 			output.WithLinkFormat(service.IngresUrl),
 		)
+		if status := service.liveStatus(); status != "" {
+			lines[index] += "  " + output.WithGrayFormat(status)
+		}
 	}
 	return strings.Join(lines, "\n")
 }
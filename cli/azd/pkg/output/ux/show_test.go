@@ -5,6 +5,7 @@ package ux
 
 import (
 	"testing"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/test/snapshot"
 )
@@ -104,3 +105,30 @@ func TestShowRemoteAndCurrent(t *testing.T) {
 	output := pp.ToString("")
 	snapshot.SnapshotT(t, output)
 }
+
+func TestShowLiveStatus(t *testing.T) {
+	lastDeployed := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	pp := &Show{
+		AppName: "Foo",
+		Services: []*ShowService{
+			{
+				Name:         "api",
+				IngresUrl:    "bar",
+				State:        "running",
+				Replicas:     new(2),
+				LastDeployed: &lastDeployed,
+			},
+			{
+				Name:      "worker",
+				IngresUrl: "baz",
+				State:     "stopped",
+				Replicas:  new(1),
+			},
+		},
+		Environments:    []*ShowEnvironment{},
+		AzurePortalLink: "foo.com",
+	}
+
+	output := pp.ToString("")
+	snapshot.SnapshotT(t, output)
+}
@@ -0,0 +1,69 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package ux
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// TemplateTestReport defines a ux item for displaying the report of `azd template test`.
+type TemplateTestReport struct {
+	Report *contracts.TemplateTestReport
+}
+
+func (tr *TemplateTestReport) ToString(currentIndentation string) string {
+	sections := []string{
+		templateTestSection(currentIndentation, "Steps", tr.Report.Steps),
+	}
+
+	if len(tr.Report.SmokeTests) > 0 {
+		sections = append(sections, templateTestSection(currentIndentation, "Smoke tests", tr.Report.SmokeTests))
+	}
+
+	if len(tr.Report.Resources) > 0 {
+		lines := []string{fmt.Sprintf("%sDeployed resources:", currentIndentation)}
+		for _, resource := range tr.Report.Resources {
+			lines = append(lines, fmt.Sprintf("%s  %3d  %s", currentIndentation, resource.Count, resource.Type))
+		}
+		sections = append(sections, strings.Join(lines, "\n"))
+	}
+
+	return strings.Join(sections, "\n\n")
+}
+
+// templateTestSection formats a titled list of results, with the error of the ones that failed.
+func templateTestSection(currentIndentation string, title string, results []contracts.TemplateTestResult) string {
+	lines := []string{fmt.Sprintf("%s%s:", currentIndentation, title)}
+	for _, result := range results {
+		prefix := donePrefix
+		if !result.Passed {
+			prefix = failedPrefix
+		}
+
+		duration := time.Duration(result.DurationSeconds * float64(time.Second))
+		line := fmt.Sprintf("%s  %s %s%s", currentIndentation, prefix, result.Name,
+			output.WithGrayFormat(" (%s)", timingDuration(duration)))
+		if result.Error != "" {
+			line += fmt.Sprintf("\n%s      %s", currentIndentation, result.Error)
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (tr *TemplateTestReport) MarshalJSON() ([]byte, error) {
+	return json.Marshal(contracts.EventEnvelope{
+		Type:      contracts.ConsoleMessageEventDataType,
+		Timestamp: time.Now(),
+		Data:      tr.Report,
+	})
+}
@@ -0,0 +1,35 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package ux
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/test/snapshot"
+)
+
+func TestTemplateTestReport(t *testing.T) {
+	tr := &TemplateTestReport{
+		Report: &contracts.TemplateTestReport{
+			Environment: "azdtest-20261017120000",
+			Steps: []contracts.TemplateTestResult{
+				{Name: "Create environment", Passed: true, DurationSeconds: 0.42},
+				{Name: "Provision and deploy", Passed: true, DurationSeconds: 312.6},
+				{Name: "Tear down", Passed: false, DurationSeconds: 95, Error: "deleting resource group: conflict"},
+			},
+			SmokeTests: []contracts.TemplateTestResult{
+				{Name: "web responds", Passed: true, DurationSeconds: 12},
+				{Name: "api health", Passed: false, DurationSeconds: 3, Error: "exit code: 1"},
+			},
+			Resources: []contracts.TemplateTestResource{
+				{Type: "Microsoft.KeyVault/vaults", Count: 1},
+				{Type: "Microsoft.Web/sites", Count: 2},
+			},
+		},
+	}
+
+	output := tr.ToString("  ")
+	snapshot.SnapshotT(t, output)
+}
@@ -8,9 +8,11 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/azdo"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/entraid"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
@@ -658,7 +660,7 @@ func (p *AzdoScmProvider) GitPush(
 		return err
 	}
 
-	err = azdo.QueueBuild(
+	_, err = azdo.QueueBuild(
 		ctx, connection, p.repoDetails.projectId, p.repoDetails.buildDefinition, branchName)
 	if err != nil {
 		return err
@@ -675,6 +677,7 @@ type AzdoCiProvider struct {
 	credentials   *entraid.AzureCredentials
 	console       input.Console
 	commandRunner exec.CommandRunner
+	cloud         *cloud.Cloud
 }
 
 func NewAzdoCiProvider(
@@ -683,6 +686,7 @@ func NewAzdoCiProvider(
 	azdContext *azdcontext.AzdContext,
 	console input.Console,
 	commandRunner exec.CommandRunner,
+	cloud *cloud.Cloud,
 ) CiProvider {
 	return &AzdoCiProvider{
 		envManager:    envManager,
@@ -690,6 +694,7 @@ func NewAzdoCiProvider(
 		AzdContext:    azdContext,
 		console:       console,
 		commandRunner: commandRunner,
+		cloud:         cloud,
 	}
 }
 
@@ -764,7 +769,7 @@ func (p *AzdoCiProvider) credentialOptions(
 			return nil, err
 		}
 		sConnection, err := azdo.CreateServiceConnection(
-			ctx, connection, details.projectId, details.projectName, p.Env, p.credentials, p.console)
+			ctx, connection, details.projectId, details.projectName, p.Env, p.credentials, p.cloud, p.console)
 		if err != nil {
 			return nil, err
 		}
@@ -817,7 +822,7 @@ func (p *AzdoCiProvider) configureConnection(
 		return err
 	}
 	_, err = azdo.CreateServiceConnection(
-		ctx, connection, details.projectId, details.projectName, p.Env, p.credentials, p.console)
+		ctx, connection, details.projectId, details.projectName, p.Env, p.credentials, p.cloud, p.console)
 	return err
 }
 
@@ -864,6 +869,113 @@ func (p *AzdoCiProvider) configurePipeline(
 	}, nil
 }
 
+// runPipeline queues a build of the pipeline created by azd for the repository, on the branch.
+func (p *AzdoCiProvider) runPipeline(
+	ctx context.Context,
+	repoDetails *gitRepositoryDetails,
+	_ string,
+	branch string,
+) (*PipelineRun, error) {
+	details := repoDetails.details.(*AzdoRepositoryDetails)
+	connection, definition, err := p.pipelineDefinition(ctx, details)
+	if err != nil {
+		return nil, err
+	}
+
+	queued, err := azdo.QueueBuild(ctx, connection, details.projectId, definition, branch)
+	if err != nil {
+		return nil, fmt.Errorf("queuing build: %w", err)
+	}
+
+	return azdoPipelineRun(details, queued), nil
+}
+
+// latestPipelineRun returns the latest build of the pipeline created by azd for the repository, on the branch.
+func (p *AzdoCiProvider) latestPipelineRun(
+	ctx context.Context,
+	repoDetails *gitRepositoryDetails,
+	_ string,
+	branch string,
+) (*PipelineRun, error) {
+	details := repoDetails.details.(*AzdoRepositoryDetails)
+	connection, definition, err := p.pipelineDefinition(ctx, details)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := azdo.LatestBuild(ctx, connection, details.projectId, definition, branch)
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil {
+		return nil, nil
+	}
+
+	return azdoPipelineRun(details, latest), nil
+}
+
+// pipelineDefinition returns the pipeline created by `azd pipeline config` for the repository.
+func (p *AzdoCiProvider) pipelineDefinition(
+	ctx context.Context, details *AzdoRepositoryDetails) (*azuredevops.Connection, *build.BuildDefinition, error) {
+	org, _, err := azdo.EnsureOrgNameExists(ctx, p.envManager, p.Env, p.console)
+	if err != nil {
+		return nil, nil, err
+	}
+	pat, _, err := azdo.EnsurePatExists(ctx, p.Env, p.console)
+	if err != nil {
+		return nil, nil, err
+	}
+	connection, err := azdo.GetConnection(ctx, org, pat)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	definition, err := azdo.GetPipeline(ctx, connection, details.projectId, details.repoName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if definition == nil {
+		return nil, nil, fmt.Errorf(
+			"the pipeline of repository %s was not found. Run 'azd pipeline config' to create it", details.repoName)
+	}
+
+	return connection, definition, nil
+}
+
+// azdoPipelineRun converts an Azure DevOps build to a PipelineRun.
+func azdoPipelineRun(details *AzdoRepositoryDetails, queued *build.Build) *PipelineRun {
+	run := &PipelineRun{
+		Status: PipelineRunQueued,
+	}
+	if queued.Id != nil {
+		run.Id = strconv.Itoa(*queued.Id)
+		repoPrefix := strings.Split(details.repoWebUrl, "_git")[0]
+		run.Url = fmt.Sprintf("%s_build/results?buildId=%d", repoPrefix, *queued.Id)
+	}
+	if queued.BuildNumber != nil {
+		run.Name = *queued.BuildNumber
+	}
+	if queued.SourceBranch != nil {
+		run.Branch = strings.TrimPrefix(*queued.SourceBranch, "refs/heads/")
+	}
+	if queued.Status != nil {
+		switch *queued.Status {
+		case build.BuildStatusValues.InProgress, build.BuildStatusValues.Cancelling:
+			run.Status = PipelineRunInProgress
+		case build.BuildStatusValues.Completed:
+			run.Status = PipelineRunCompleted
+		}
+	}
+	if queued.Result != nil && run.Status == PipelineRunCompleted {
+		run.Result = string(*queued.Result)
+	}
+	if queued.QueueTime != nil {
+		run.QueuedAt = &queued.QueueTime.Time
+	}
+
+	return run
+}
+
 // pipeline is the implementation for a CiPipeline for Azure DevOps
 type pipeline struct {
 	repoDetails *AzdoRepositoryDetails
@@ -0,0 +1,189 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/entraid"
+	"github.com/azure/azure-dev/cli/azd/pkg/graphsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/github"
+)
+
+// defaultApplicationNamePrefix is the prefix of the applications created by `azd pipeline config` when no service
+// principal is specified.
+const defaultApplicationNamePrefix = "az-dev-"
+
+// StaleCredential is a federated identity credential created by `azd pipeline config` for a GitHub repository, or an
+// environment of a repository, that no longer exists.
+type StaleCredential struct {
+	Application *graphsdk.Application
+	Credential  graphsdk.FederatedIdentityCredential
+	// Reason describes why the credential is stale.
+	Reason string
+}
+
+// CleanupPlan holds the identities that `azd pipeline cleanup` removes.
+type CleanupPlan struct {
+	// Credentials are the stale federated identity credentials of applications that are still in use.
+	Credentials []StaleCredential
+	// Applications are the applications whose federated identity credentials are all stale. Deleting an application
+	// also deletes its service principal and federated identity credentials.
+	Applications []*graphsdk.Application
+}
+
+// Empty reports whether there is nothing to remove.
+func (p *CleanupPlan) Empty() bool {
+	return len(p.Credentials) == 0 && len(p.Applications) == 0
+}
+
+// CleanupManager finds and removes the identities created by `azd pipeline config` that are no longer used by any
+// pipeline.
+type CleanupManager struct {
+	entraIdService entraid.EntraIdService
+	ghCli          *github.Cli
+}
+
+// NewCleanupManager creates a new CleanupManager.
+func NewCleanupManager(entraIdService entraid.EntraIdService, ghCli *github.Cli) *CleanupManager {
+	return &CleanupManager{
+		entraIdService: entraIdService,
+		ghCli:          ghCli,
+	}
+}
+
+// Plan finds the applications created by `azd pipeline config` in the tenant of the subscription, and the federated
+// identity credentials of those applications that trust GitHub repositories or environments that were deleted.
+// Applications without federated identity credentials use client secrets and are never part of the plan, since azd
+// can't tell whether a pipeline still uses them. The GitHub CLI must be installed.
+func (m *CleanupManager) Plan(ctx context.Context, subscriptionId string) (*CleanupPlan, error) {
+	applications, err := m.entraIdService.ListApplications(ctx, subscriptionId, defaultApplicationNamePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &CleanupPlan{}
+	// The same repository is usually trusted by several credentials, for the main branch and pull requests.
+	checked := map[string]string{}
+
+	for i := range applications {
+		application := &applications[i]
+		credentials, err := m.entraIdService.ListFederatedCredentials(ctx, subscriptionId, application)
+		if err != nil {
+			return nil, err
+		}
+
+		var stale []StaleCredential
+		for _, credential := range credentials {
+			reason, err := m.staleReason(ctx, credential, checked)
+			if err != nil {
+				return nil, err
+			}
+
+			if reason != "" {
+				stale = append(stale, StaleCredential{
+					Application: application,
+					Credential:  credential,
+					Reason:      reason,
+				})
+			}
+		}
+
+		if len(credentials) > 0 && len(stale) == len(credentials) {
+			plan.Applications = append(plan.Applications, application)
+		} else {
+			plan.Credentials = append(plan.Credentials, stale...)
+		}
+	}
+
+	return plan, nil
+}
+
+// Apply removes the stale credentials and applications of the plan.
+func (m *CleanupManager) Apply(ctx context.Context, subscriptionId string, plan *CleanupPlan) error {
+	for _, stale := range plan.Credentials {
+		if stale.Credential.Id == nil {
+			continue
+		}
+
+		if err := m.entraIdService.DeleteFederatedCredential(
+			ctx, subscriptionId, stale.Application, *stale.Credential.Id); err != nil {
+			return err
+		}
+	}
+
+	for _, application := range plan.Applications {
+		if err := m.entraIdService.DeleteApplication(ctx, subscriptionId, application); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// staleReason returns why the credential is stale, or an empty string when the credential is in use or trusts an
+// identity provider azd can't check. checked caches the reasons of the repositories and environments already checked.
+func (m *CleanupManager) staleReason(
+	ctx context.Context,
+	credential graphsdk.FederatedIdentityCredential,
+	checked map[string]string,
+) (string, error) {
+	if credential.Issuer != federatedIdentityIssuer {
+		return "", nil
+	}
+
+	repoSlug, environmentName, has := parseGitHubSubject(credential.Subject)
+	if !has {
+		// Subjects built from customized OIDC claims identify repositories by ID and can't be checked.
+		log.Printf("skipping federated credential '%s' with subject '%s'", credential.Name, credential.Subject)
+		return "", nil
+	}
+
+	apiPath := "/repos/" + repoSlug
+	reason := fmt.Sprintf("repository %s was deleted", repoSlug)
+	if environmentName != "" {
+		apiPath += "/environments/" + url.PathEscape(environmentName)
+		reason = fmt.Sprintf("environment %s of repository %s was deleted", environmentName, repoSlug)
+	}
+
+	if cached, has := checked[apiPath]; has {
+		return cached, nil
+	}
+
+	_, err := m.ghCli.ApiCall(ctx, github.GitHubHostName, apiPath, github.ApiCallOptions{})
+	if apiErr, ok := errors.AsType[*github.ApiError](err); ok && apiErr.IsNotFound() {
+		checked[apiPath] = reason
+		return reason, nil
+	} else if err != nil {
+		return "", fmt.Errorf("checking federated credential '%s': %w", credential.Name, err)
+	}
+
+	checked[apiPath] = ""
+	return "", nil
+}
+
+// parseGitHubSubject returns the repository, and the environment when there is one, of a GitHub Actions OIDC subject
+// with the default format: repo:<owner>/<name>:<context>.
+func parseGitHubSubject(subject string) (string, string, bool) {
+	rest, has := strings.CutPrefix(subject, "repo:")
+	if !has {
+		return "", "", false
+	}
+
+	repoSlug, subjectContext, has := strings.Cut(rest, ":")
+	if !has || strings.Count(repoSlug, "/") != 1 {
+		return "", "", false
+	}
+
+	if environmentName, isEnvironment := strings.CutPrefix(subjectContext, "environment:"); isEnvironment {
+		return repoSlug, environmentName, true
+	}
+
+	return repoSlug, "", true
+}
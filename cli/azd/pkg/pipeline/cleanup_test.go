@@ -0,0 +1,175 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/entraid"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/graphsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/github"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+type cleanupEntraIdService struct {
+	entraid.EntraIdService
+	applications       []graphsdk.Application
+	credentials        map[string][]graphsdk.FederatedIdentityCredential
+	deletedCredentials []string
+	deletedApps        []string
+}
+
+func (s *cleanupEntraIdService) ListApplications(
+	_ context.Context, _ string, displayNamePrefix string,
+) ([]graphsdk.Application, error) {
+	var applications []graphsdk.Application
+	for _, application := range s.applications {
+		if strings.HasPrefix(application.DisplayName, displayNamePrefix) {
+			applications = append(applications, application)
+		}
+	}
+
+	return applications, nil
+}
+
+func (s *cleanupEntraIdService) ListFederatedCredentials(
+	_ context.Context, _ string, application *graphsdk.Application,
+) ([]graphsdk.FederatedIdentityCredential, error) {
+	return s.credentials[*application.Id], nil
+}
+
+func (s *cleanupEntraIdService) DeleteFederatedCredential(
+	_ context.Context, _ string, _ *graphsdk.Application, credentialId string,
+) error {
+	s.deletedCredentials = append(s.deletedCredentials, credentialId)
+	return nil
+}
+
+func (s *cleanupEntraIdService) DeleteApplication(
+	_ context.Context, _ string, application *graphsdk.Application,
+) error {
+	s.deletedApps = append(s.deletedApps, application.DisplayName)
+	return nil
+}
+
+func gitHubCredential(id, subject string) graphsdk.FederatedIdentityCredential {
+	return graphsdk.FederatedIdentityCredential{
+		Id:      new(id),
+		Name:    id,
+		Issuer:  federatedIdentityIssuer,
+		Subject: subject,
+	}
+}
+
+func Test_CleanupManager(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	ghApiCalls := 0
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return len(args.Args) > 0 && args.Args[0] == "api"
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		ghApiCalls++
+		if strings.HasSuffix(args.Args[1], "/repos/owner/deleted") ||
+			strings.HasSuffix(args.Args[1], "/repos/owner/live/environments/old") {
+			return exec.NewRunResult(1, "", "gh: Not Found (HTTP 404)"), errors.New("exit code: 1")
+		}
+
+		return exec.NewRunResult(0, "{}", ""), nil
+	})
+
+	entraIdService := &cleanupEntraIdService{
+		applications: []graphsdk.Application{
+			{Id: new("app-deleted"), DisplayName: "az-dev-01-01-2024-00-00-00"},
+			{Id: new("app-live"), DisplayName: "az-dev-02-01-2024-00-00-00"},
+			{Id: new("app-secret"), DisplayName: "az-dev-03-01-2024-00-00-00"},
+			{Id: new("app-custom"), DisplayName: "my-pipeline"},
+		},
+		credentials: map[string][]graphsdk.FederatedIdentityCredential{
+			"app-deleted": {
+				gitHubCredential("deleted-main", "repo:owner/deleted:ref:refs/heads/main"),
+				gitHubCredential("deleted-pr", "repo:owner/deleted:pull_request"),
+			},
+			"app-live": {
+				gitHubCredential("live-main", "repo:owner/live:ref:refs/heads/main"),
+				gitHubCredential("live-env", "repo:owner/live:environment:old"),
+				gitHubCredential("live-custom", "repository_owner_id:1:repository_id:2:pull_request"),
+				{Id: new("azdo"), Issuer: "https://vstoken.dev.azure.com/org", Subject: "sc://org/project/conn"},
+			},
+			"app-custom": {
+				gitHubCredential("custom-main", "repo:owner/deleted:ref:refs/heads/main"),
+			},
+		},
+	}
+
+	manager := NewCleanupManager(entraIdService, github.NewGitHubCli(mockContext.Console, mockContext.CommandRunner))
+
+	plan, err := manager.Plan(*mockContext.Context, "SUBSCRIPTION_ID")
+	require.NoError(t, err)
+	require.False(t, plan.Empty())
+
+	// The deleted repository is checked once for both of its credentials.
+	require.Equal(t, 3, ghApiCalls)
+
+	require.Len(t, plan.Applications, 1)
+	require.Equal(t, "az-dev-01-01-2024-00-00-00", plan.Applications[0].DisplayName)
+
+	require.Len(t, plan.Credentials, 1)
+	require.Equal(t, "live-env", plan.Credentials[0].Credential.Name)
+	require.Equal(t, "environment old of repository owner/live was deleted", plan.Credentials[0].Reason)
+
+	err = manager.Apply(*mockContext.Context, "SUBSCRIPTION_ID", plan)
+	require.NoError(t, err)
+	require.Equal(t, []string{"live-env"}, entraIdService.deletedCredentials)
+	require.Equal(t, []string{"az-dev-01-01-2024-00-00-00"}, entraIdService.deletedApps)
+}
+
+func Test_CleanupManager_CheckFails(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return len(args.Args) > 0 && args.Args[0] == "api"
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		return exec.NewRunResult(1, "", "gh: Bad credentials (HTTP 401)"), errors.New("exit code: 1")
+	})
+
+	entraIdService := &cleanupEntraIdService{
+		applications: []graphsdk.Application{{Id: new("app"), DisplayName: "az-dev-01-01-2024-00-00-00"}},
+		credentials: map[string][]graphsdk.FederatedIdentityCredential{
+			"app": {gitHubCredential("main", "repo:owner/repo:ref:refs/heads/main")},
+		},
+	}
+
+	manager := NewCleanupManager(entraIdService, github.NewGitHubCli(mockContext.Console, mockContext.CommandRunner))
+
+	// Credentials are only removed when GitHub confirms the repository is gone.
+	_, err := manager.Plan(*mockContext.Context, "SUBSCRIPTION_ID")
+	require.Error(t, err)
+}
+
+func Test_parseGitHubSubject(t *testing.T) {
+	tests := []struct {
+		subject     string
+		repoSlug    string
+		environment string
+		ok          bool
+	}{
+		{"repo:owner/repo:ref:refs/heads/main", "owner/repo", "", true},
+		{"repo:owner/repo:pull_request", "owner/repo", "", true},
+		{"repo:owner/repo:environment:prod", "owner/repo", "prod", true},
+		{"repository_owner_id:1:repository_id:2:pull_request", "", "", false},
+		{"repo:owner:pull_request", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.subject, func(t *testing.T) {
+			repoSlug, environment, ok := parseGitHubSubject(tt.subject)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.repoSlug, repoSlug)
+			require.Equal(t, tt.environment, environment)
+		})
+	}
+}
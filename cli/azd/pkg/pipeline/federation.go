@@ -0,0 +1,417 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	msi "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/msi/armmsi"
+	"github.com/azure/azure-dev/cli/azd/pkg/armmsi"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/pkg/entraid"
+	"github.com/azure/azure-dev/cli/azd/pkg/gitlab"
+	"github.com/azure/azure-dev/cli/azd/pkg/graphsdk"
+)
+
+// FederationIssuer is an identity provider whose tokens can be exchanged for Azure tokens through a federated identity
+// credential.
+type FederationIssuer string
+
+const (
+	FederationIssuerGitHub FederationIssuer = "github"
+	FederationIssuerAzDo   FederationIssuer = "azdo"
+	FederationIssuerGitLab FederationIssuer = "gitlab"
+	// FederationIssuerCustom is any other OpenID Connect issuer, like a Kubernetes cluster.
+	FederationIssuerCustom FederationIssuer = "custom"
+)
+
+// FederationIssuers are the supported issuers.
+var FederationIssuers = []FederationIssuer{
+	FederationIssuerGitHub,
+	FederationIssuerAzDo,
+	FederationIssuerGitLab,
+	FederationIssuerCustom,
+}
+
+// ErrFederatedCredentialNotFound is returned when the target has no federated identity credential with the name.
+var ErrFederatedCredentialNotFound = errors.New("not found")
+
+// maxFederatedCredentialNameLength is the maximum length of the name of a federated identity credential.
+const maxFederatedCredentialNameLength = 120
+
+// FederatedCredential is a federated identity credential of an application or a user-assigned managed identity.
+type FederatedCredential struct {
+	// Id is the object ID of the credential of an application, or the resource ID of the credential of a managed
+	// identity.
+	Id        string   `json:"id"`
+	Name      string   `json:"name"`
+	Issuer    string   `json:"issuer"`
+	Subject   string   `json:"subject"`
+	Audiences []string `json:"audiences"`
+}
+
+// FederatedCredentialOptions describes the workloads trusted by a federated identity credential. The issuer and the
+// subject are built from the repository and the branch, environment or service connection, unless they are set.
+type FederatedCredentialOptions struct {
+	IssuerKind FederationIssuer
+	// Issuer is the URL of the issuer. Required for Azure DevOps and custom issuers. For GitLab, defaults to
+	// https://gitlab.com.
+	Issuer string
+	// Subject overrides the subject built from the other options. Required for custom issuers.
+	Subject string
+	// Repository is `<owner>/<repo>` for GitHub, `<group>/<project>` for GitLab and `<organization>/<project>` for
+	// Azure DevOps.
+	Repository string
+	// Branch is the branch trusted on GitHub and GitLab. Defaults to main.
+	Branch string
+	// Environment is the GitHub environment trusted, instead of a branch.
+	Environment string
+	// PullRequest trusts the pull request workflows of a GitHub repository, instead of a branch.
+	PullRequest bool
+	// ServiceConnection is the name of the Azure DevOps service connection trusted.
+	ServiceConnection string
+	// Name is the name of the credential. Defaults to the subject, sanitized.
+	Name string
+	// Audience defaults to api://AzureADTokenExchange.
+	Audience string
+}
+
+// Credential builds the federated identity credential from the options.
+func (o *FederatedCredentialOptions) Credential() (*FederatedCredential, error) {
+	issuer := o.Issuer
+	subject := o.Subject
+
+	requireRepository := func() error {
+		if subject == "" && o.Repository == "" {
+			return fmt.Errorf("a repository or a subject is required for %s", o.IssuerKind)
+		}
+
+		return nil
+	}
+
+	branch := o.Branch
+	if branch == "" {
+		branch = "main"
+	}
+
+	switch o.IssuerKind {
+	case FederationIssuerGitHub:
+		if err := requireRepository(); err != nil {
+			return nil, err
+		}
+		if issuer == "" {
+			issuer = federatedIdentityIssuer
+		}
+		if subject == "" {
+			switch {
+			case o.Environment != "" && o.PullRequest:
+				return nil, errors.New("an environment and pull requests can't be trusted by the same credential")
+			case o.Environment != "":
+				subject = fmt.Sprintf("repo:%s:environment:%s", o.Repository, o.Environment)
+			case o.PullRequest:
+				subject = fmt.Sprintf("repo:%s:pull_request", o.Repository)
+			default:
+				subject = fmt.Sprintf("repo:%s:ref:refs/heads/%s", o.Repository, branch)
+			}
+		}
+	case FederationIssuerGitLab:
+		if err := requireRepository(); err != nil {
+			return nil, err
+		}
+		if issuer == "" {
+			issuer = "https://" + gitlab.DefaultHost
+		}
+		if subject == "" {
+			subject = gitLabFederatedSubject(o.Repository, branch)
+		}
+	case FederationIssuerAzDo:
+		// The issuer contains the ID of the organization, which is shown in the details of the service connection.
+		if issuer == "" {
+			return nil, errors.New(
+				"the issuer is required for azdo, copy it from the details of the service connection")
+		}
+		if subject == "" {
+			if o.Repository == "" || o.ServiceConnection == "" {
+				return nil, errors.New(
+					"a subject, or an organization/project repository and a service connection, are required for azdo")
+			}
+			subject = fmt.Sprintf("sc://%s/%s", o.Repository, o.ServiceConnection)
+		}
+	case FederationIssuerCustom:
+		if issuer == "" || subject == "" {
+			return nil, errors.New("the issuer and the subject are required for custom issuers")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported issuer '%s'", o.IssuerKind)
+	}
+
+	name := o.Name
+	if name == "" {
+		name = federatedCredentialName(subject)
+	}
+
+	audience := o.Audience
+	if audience == "" {
+		audience = federatedIdentityAudience
+	}
+
+	return &FederatedCredential{
+		Name:      name,
+		Issuer:    issuer,
+		Subject:   subject,
+		Audiences: []string{audience},
+	}, nil
+}
+
+// gitLabFederatedSubject returns the subject of the ID tokens of the GitLab CI/CD jobs running for a branch of the
+// project.
+func gitLabFederatedSubject(projectPath string, branch string) string {
+	return fmt.Sprintf("project_path:%s:ref_type:branch:ref:%s", projectPath, branch)
+}
+
+var repeatedDashes = regexp.MustCompile(`-{2,}`)
+
+// federatedCredentialName returns a valid name of a federated identity credential for the subject.
+func federatedCredentialName(subject string) string {
+	name := repeatedDashes.ReplaceAllString(credentialNameSanitizer.ReplaceAllString(subject, "-"), "-")
+	name = strings.Trim(name, "-_")
+	if len(name) > maxFederatedCredentialNameLength {
+		name = strings.TrimRight(name[:maxFederatedCredentialNameLength], "-_")
+	}
+
+	return name
+}
+
+// FederationTarget is the identity that federated identity credentials are added to: either the application of a
+// service principal, or a user-assigned managed identity.
+type FederationTarget struct {
+	// ClientId is the application (client) ID or the display name of the application.
+	ClientId string
+	// IdentityId is the resource ID of the user-assigned managed identity.
+	IdentityId string
+}
+
+// FederationManager manages the federated identity credentials of applications and user-assigned managed identities,
+// independently of the configuration of a pipeline.
+type FederationManager struct {
+	entraIdService entraid.EntraIdService
+	msiService     armmsi.ArmMsiService
+}
+
+// NewFederationManager creates a new FederationManager.
+func NewFederationManager(entraIdService entraid.EntraIdService, msiService armmsi.ArmMsiService) *FederationManager {
+	return &FederationManager{
+		entraIdService: entraIdService,
+		msiService:     msiService,
+	}
+}
+
+// List lists the federated identity credentials of the target. The subscription selects the tenant of applications.
+func (m *FederationManager) List(
+	ctx context.Context,
+	subscriptionId string,
+	target FederationTarget,
+) ([]FederatedCredential, error) {
+	if target.IdentityId != "" {
+		credentials, err := m.msiService.ListFederatedCredentials(ctx, target.IdentityId)
+		if err != nil {
+			return nil, err
+		}
+
+		result := make([]FederatedCredential, 0, len(credentials))
+		for _, credential := range credentials {
+			result = append(result, fromArmFederatedCredential(credential))
+		}
+
+		return result, nil
+	}
+
+	application, err := m.entraIdService.GetApplication(ctx, subscriptionId, target.ClientId)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := m.entraIdService.ListFederatedCredentials(ctx, subscriptionId, application)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]FederatedCredential, 0, len(credentials))
+	for _, credential := range credentials {
+		result = append(result, fromGraphFederatedCredential(credential))
+	}
+
+	return result, nil
+}
+
+// Create adds the federated identity credentials to the target, and returns the credentials that were created.
+// Credentials whose subject is already trusted by the target are skipped.
+func (m *FederationManager) Create(
+	ctx context.Context,
+	subscriptionId string,
+	target FederationTarget,
+	credentials []FederatedCredential,
+) ([]FederatedCredential, error) {
+	result := []FederatedCredential{}
+
+	if target.IdentityId != "" {
+		armCredentials := make([]msi.FederatedIdentityCredential, len(credentials))
+		for i, credential := range credentials {
+			armCredentials[i] = msi.FederatedIdentityCredential{
+				Name: new(credential.Name),
+				Properties: &msi.FederatedIdentityCredentialProperties{
+					Subject:   new(credential.Subject),
+					Issuer:    new(credential.Issuer),
+					Audiences: to.SliceOfPtrs(credential.Audiences...),
+				},
+			}
+		}
+
+		created, err := m.msiService.ApplyFederatedCredentials(ctx, subscriptionId, target.IdentityId, armCredentials)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, credential := range created {
+			result = append(result, fromArmFederatedCredential(credential))
+		}
+
+		return result, nil
+	}
+
+	graphCredentials := make([]*graphsdk.FederatedIdentityCredential, len(credentials))
+	for i, credential := range credentials {
+		graphCredentials[i] = &graphsdk.FederatedIdentityCredential{
+			Name:        credential.Name,
+			Issuer:      credential.Issuer,
+			Subject:     credential.Subject,
+			Description: new("Created by Azure Developer CLI"),
+			Audiences:   credential.Audiences,
+		}
+	}
+
+	created, err := m.entraIdService.ApplyFederatedCredentials(ctx, subscriptionId, target.ClientId, graphCredentials)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, credential := range created {
+		result = append(result, fromGraphFederatedCredential(*credential))
+	}
+
+	return result, nil
+}
+
+// Rotate replaces the issuer, subject and audiences of the federated identity credential of the target named name,
+// and returns the credential before and after the change. The workloads trusted by the previous subject can't get
+// tokens anymore once the credential is updated.
+func (m *FederationManager) Rotate(
+	ctx context.Context,
+	subscriptionId string,
+	target FederationTarget,
+	name string,
+	replacement FederatedCredential,
+) (*FederatedCredential, *FederatedCredential, error) {
+	if target.IdentityId != "" {
+		credentials, err := m.msiService.ListFederatedCredentials(ctx, target.IdentityId)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, credential := range credentials {
+			if credential.Name == nil || !strings.EqualFold(*credential.Name, name) {
+				continue
+			}
+
+			existing := fromArmFederatedCredential(credential)
+			identity, err := arm.ParseResourceID(target.IdentityId)
+			if err != nil {
+				return nil, nil, fmt.Errorf("parsing MSI resource id: %w", err)
+			}
+
+			// Creating a credential with the name of an existing one replaces it.
+			result, err := m.msiService.CreateFederatedCredential(
+				ctx,
+				identity.SubscriptionID,
+				identity.ResourceGroupName,
+				identity.Name,
+				existing.Name,
+				replacement.Subject,
+				replacement.Issuer,
+				replacement.Audiences,
+			)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			rotated := fromArmFederatedCredential(result)
+			return &existing, &rotated, nil
+		}
+
+		return nil, nil, fmt.Errorf("federated credential '%s': %w", name, ErrFederatedCredentialNotFound)
+	}
+
+	application, err := m.entraIdService.GetApplication(ctx, subscriptionId, target.ClientId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	credentials, err := m.entraIdService.ListFederatedCredentials(ctx, subscriptionId, application)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, credential := range credentials {
+		if !strings.EqualFold(credential.Name, name) {
+			continue
+		}
+
+		existing := fromGraphFederatedCredential(credential)
+		credential.Issuer = replacement.Issuer
+		credential.Subject = replacement.Subject
+		credential.Audiences = replacement.Audiences
+
+		if err := m.entraIdService.UpdateFederatedCredential(ctx, subscriptionId, application, &credential); err != nil {
+			return nil, nil, err
+		}
+
+		rotated := fromGraphFederatedCredential(credential)
+		return &existing, &rotated, nil
+	}
+
+	return nil, nil, fmt.Errorf("federated credential '%s': %w", name, ErrFederatedCredentialNotFound)
+}
+
+func fromGraphFederatedCredential(credential graphsdk.FederatedIdentityCredential) FederatedCredential {
+	return FederatedCredential{
+		Id:        convert.ToValueWithDefault(credential.Id, ""),
+		Name:      credential.Name,
+		Issuer:    credential.Issuer,
+		Subject:   credential.Subject,
+		Audiences: credential.Audiences,
+	}
+}
+
+func fromArmFederatedCredential(credential msi.FederatedIdentityCredential) FederatedCredential {
+	result := FederatedCredential{
+		Id:   convert.ToValueWithDefault(credential.ID, ""),
+		Name: convert.ToValueWithDefault(credential.Name, ""),
+	}
+
+	if credential.Properties != nil {
+		result.Issuer = convert.ToValueWithDefault(credential.Properties.Issuer, "")
+		result.Subject = convert.ToValueWithDefault(credential.Properties.Subject, "")
+		for _, audience := range credential.Properties.Audiences {
+			result.Audiences = append(result.Audiences, convert.ToValueWithDefault(audience, ""))
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,287 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	msi "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/msi/armmsi"
+	"github.com/azure/azure-dev/cli/azd/pkg/armmsi"
+	"github.com/azure/azure-dev/cli/azd/pkg/entraid"
+	"github.com/azure/azure-dev/cli/azd/pkg/graphsdk"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FederatedCredentialOptions_Credential(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  FederatedCredentialOptions
+		expected *FederatedCredential
+		errMatch string
+	}{
+		{
+			name:    "GitHubBranch",
+			options: FederatedCredentialOptions{IssuerKind: FederationIssuerGitHub, Repository: "owner/app"},
+			expected: &FederatedCredential{
+				Name:      "repo-owner-app-ref-refs-heads-main",
+				Issuer:    federatedIdentityIssuer,
+				Subject:   "repo:owner/app:ref:refs/heads/main",
+				Audiences: []string{federatedIdentityAudience},
+			},
+		},
+		{
+			name: "GitHubEnvironment",
+			options: FederatedCredentialOptions{
+				IssuerKind: FederationIssuerGitHub, Repository: "owner/app", Environment: "prod", Name: "app-prod",
+			},
+			expected: &FederatedCredential{
+				Name:      "app-prod",
+				Issuer:    federatedIdentityIssuer,
+				Subject:   "repo:owner/app:environment:prod",
+				Audiences: []string{federatedIdentityAudience},
+			},
+		},
+		{
+			name: "GitHubPullRequest",
+			options: FederatedCredentialOptions{
+				IssuerKind: FederationIssuerGitHub, Repository: "owner/app", PullRequest: true,
+			},
+			expected: &FederatedCredential{
+				Name:      "repo-owner-app-pull_request",
+				Issuer:    federatedIdentityIssuer,
+				Subject:   "repo:owner/app:pull_request",
+				Audiences: []string{federatedIdentityAudience},
+			},
+		},
+		{
+			name: "GitLabSelfManaged",
+			options: FederatedCredentialOptions{
+				IssuerKind: FederationIssuerGitLab,
+				Issuer:     "https://gitlab.contoso.com",
+				Repository: "group/app",
+				Branch:     "release",
+			},
+			expected: &FederatedCredential{
+				Name:      "project_path-group-app-ref_type-branch-ref-release",
+				Issuer:    "https://gitlab.contoso.com",
+				Subject:   "project_path:group/app:ref_type:branch:ref:release",
+				Audiences: []string{federatedIdentityAudience},
+			},
+		},
+		{
+			name: "AzDo",
+			options: FederatedCredentialOptions{
+				IssuerKind:        FederationIssuerAzDo,
+				Issuer:            "https://vstoken.dev.azure.com/org-id",
+				Repository:        "org/project",
+				ServiceConnection: "azconnection",
+			},
+			expected: &FederatedCredential{
+				Name:      "sc-org-project-azconnection",
+				Issuer:    "https://vstoken.dev.azure.com/org-id",
+				Subject:   "sc://org/project/azconnection",
+				Audiences: []string{federatedIdentityAudience},
+			},
+		},
+		{
+			name: "Custom",
+			options: FederatedCredentialOptions{
+				IssuerKind: FederationIssuerCustom,
+				Issuer:     "https://oidc.contoso.com",
+				Subject:    "system:serviceaccount:default:app",
+				Audience:   "custom-audience",
+			},
+			expected: &FederatedCredential{
+				Name:      "system-serviceaccount-default-app",
+				Issuer:    "https://oidc.contoso.com",
+				Subject:   "system:serviceaccount:default:app",
+				Audiences: []string{"custom-audience"},
+			},
+		},
+		{
+			name:     "GitHubNoRepository",
+			options:  FederatedCredentialOptions{IssuerKind: FederationIssuerGitHub},
+			errMatch: "a repository or a subject is required",
+		},
+		{
+			name: "GitHubEnvironmentAndPullRequest",
+			options: FederatedCredentialOptions{
+				IssuerKind: FederationIssuerGitHub, Repository: "owner/app", Environment: "prod", PullRequest: true,
+			},
+			errMatch: "can't be trusted by the same credential",
+		},
+		{
+			name:     "AzDoNoIssuer",
+			options:  FederatedCredentialOptions{IssuerKind: FederationIssuerAzDo, Subject: "sc://org/project/conn"},
+			errMatch: "the issuer is required for azdo",
+		},
+		{
+			name:     "CustomNoSubject",
+			options:  FederatedCredentialOptions{IssuerKind: FederationIssuerCustom, Issuer: "https://oidc"},
+			errMatch: "the issuer and the subject are required",
+		},
+		{
+			name:     "Unsupported",
+			options:  FederatedCredentialOptions{IssuerKind: "jenkins"},
+			errMatch: "unsupported issuer 'jenkins'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			credential, err := tt.options.Credential()
+			if tt.errMatch != "" {
+				require.ErrorContains(t, err, tt.errMatch)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, credential)
+		})
+	}
+}
+
+func Test_federatedCredentialName(t *testing.T) {
+	require.Equal(t, "repo-owner-app-ref-refs-heads-main", federatedCredentialName("repo:owner/app:ref:refs/heads/main"))
+
+	name := federatedCredentialName("repo:owner/" + strings.Repeat("a", 200))
+	require.Len(t, name, maxFederatedCredentialNameLength)
+}
+
+type federationEntraIdService struct {
+	entraid.EntraIdService
+	credentials []graphsdk.FederatedIdentityCredential
+	applied     []*graphsdk.FederatedIdentityCredential
+	updated     *graphsdk.FederatedIdentityCredential
+}
+
+func (s *federationEntraIdService) GetApplication(
+	_ context.Context, _ string, appIdOrName string,
+) (*graphsdk.Application, error) {
+	return &graphsdk.Application{Id: new("object-id"), AppId: new(appIdOrName), DisplayName: "app"}, nil
+}
+
+func (s *federationEntraIdService) ListFederatedCredentials(
+	_ context.Context, _ string, _ *graphsdk.Application,
+) ([]graphsdk.FederatedIdentityCredential, error) {
+	return s.credentials, nil
+}
+
+func (s *federationEntraIdService) ApplyFederatedCredentials(
+	_ context.Context, _ string, _ string, credentials []*graphsdk.FederatedIdentityCredential,
+) ([]*graphsdk.FederatedIdentityCredential, error) {
+	s.applied = credentials
+	return credentials, nil
+}
+
+func (s *federationEntraIdService) UpdateFederatedCredential(
+	_ context.Context, _ string, _ *graphsdk.Application, credential *graphsdk.FederatedIdentityCredential,
+) error {
+	s.updated = credential
+	return nil
+}
+
+func Test_FederationManager_Application(t *testing.T) {
+	entraIdService := &federationEntraIdService{
+		credentials: []graphsdk.FederatedIdentityCredential{
+			gitHubCredential("owner-app-main", "repo:owner/app:ref:refs/heads/main"),
+		},
+	}
+	manager := NewFederationManager(entraIdService, armmsi.ArmMsiService{})
+	target := FederationTarget{ClientId: "client-id"}
+
+	credentials, err := manager.List(t.Context(), "subscription-id", target)
+	require.NoError(t, err)
+	require.Equal(t, []FederatedCredential{{
+		Id:      "owner-app-main",
+		Name:    "owner-app-main",
+		Issuer:  federatedIdentityIssuer,
+		Subject: "repo:owner/app:ref:refs/heads/main",
+	}}, credentials)
+
+	created, err := manager.Create(t.Context(), "subscription-id", target, []FederatedCredential{{
+		Name:      "owner-app-pr",
+		Issuer:    federatedIdentityIssuer,
+		Subject:   "repo:owner/app:pull_request",
+		Audiences: []string{federatedIdentityAudience},
+	}})
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	require.Equal(t, "repo:owner/app:pull_request", entraIdService.applied[0].Subject)
+	require.Equal(t, "Created by Azure Developer CLI", *entraIdService.applied[0].Description)
+
+	previous, rotated, err := manager.Rotate(t.Context(), "subscription-id", target, "OWNER-APP-MAIN", FederatedCredential{
+		Issuer:    federatedIdentityIssuer,
+		Subject:   "repo:owner/renamed:ref:refs/heads/main",
+		Audiences: []string{federatedIdentityAudience},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "repo:owner/app:ref:refs/heads/main", previous.Subject)
+	require.Equal(t, "repo:owner/renamed:ref:refs/heads/main", rotated.Subject)
+	require.Equal(t, "owner-app-main", entraIdService.updated.Name)
+	require.Equal(t, "owner-app-main", *entraIdService.updated.Id)
+
+	_, _, err = manager.Rotate(t.Context(), "subscription-id", target, "missing", FederatedCredential{})
+	require.ErrorIs(t, err, ErrFederatedCredentialNotFound)
+}
+
+func Test_FederationManager_ManagedIdentity(t *testing.T) {
+	const identityId = "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/" +
+		"Microsoft.ManagedIdentity/userAssignedIdentities/MSI_NAME"
+
+	mockContext := mocks.NewMockContext(t.Context())
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/federatedIdentityCredentials")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, msi.FederatedIdentityCredentialsListResult{
+			Value: []*msi.FederatedIdentityCredential{
+				{
+					ID:   new(identityId + "/federatedIdentityCredentials/group-app-main"),
+					Name: new("group-app-main"),
+					Properties: &msi.FederatedIdentityCredentialProperties{
+						Issuer:    new("https://gitlab.com"),
+						Subject:   new("project_path:group/app:ref_type:branch:ref:main"),
+						Audiences: []*string{new(federatedIdentityAudience)},
+					},
+				},
+			},
+		})
+	})
+
+	var put msi.FederatedIdentityCredential
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut &&
+			strings.HasSuffix(request.URL.Path, "/federatedIdentityCredentials/group-app-main")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		require.NoError(t, json.NewDecoder(request.Body).Decode(&put))
+		put.Name = new("group-app-main")
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, put)
+	})
+
+	manager := NewFederationManager(
+		nil, armmsi.NewArmMsiService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions))
+	target := FederationTarget{IdentityId: identityId}
+
+	credentials, err := manager.List(*mockContext.Context, "SUBSCRIPTION_ID", target)
+	require.NoError(t, err)
+	require.Len(t, credentials, 1)
+	require.Equal(t, "project_path:group/app:ref_type:branch:ref:main", credentials[0].Subject)
+	require.Equal(t, []string{federatedIdentityAudience}, credentials[0].Audiences)
+
+	previous, rotated, err := manager.Rotate(*mockContext.Context, "SUBSCRIPTION_ID", target, "group-app-main",
+		FederatedCredential{
+			Issuer:    "https://gitlab.com",
+			Subject:   "project_path:group/renamed:ref_type:branch:ref:main",
+			Audiences: []string{federatedIdentityAudience},
+		})
+	require.NoError(t, err)
+	require.Equal(t, "project_path:group/app:ref_type:branch:ref:main", previous.Subject)
+	require.Equal(t, "project_path:group/renamed:ref_type:branch:ref:main", rotated.Subject)
+	require.Equal(t, "project_path:group/renamed:ref_type:branch:ref:main", *put.Properties.Subject)
+}
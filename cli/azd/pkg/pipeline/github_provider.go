@@ -9,11 +9,14 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"log"
 	"maps"
 	"path/filepath"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/entraid"
@@ -27,6 +30,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/github"
+	"github.com/sethvargo/go-retry"
 )
 
 // GitHubScmProvider implements ScmProvider using GitHub as the provider
@@ -789,7 +793,7 @@ func (p *GitHubCiProvider) configureClientCredentialsAuth(
 		return fmt.Errorf("failed marshalling azure credentials: %w", err)
 	}
 
-	if err := p.ghCli.SetSecret(ctx, repoSlug, secretName, string(credsJson)); err != nil {
+	if err := p.ghCli.SetSecret(ctx, repoSlug, secretName, string(credsJson), nil); err != nil {
 		return fmt.Errorf("failed setting %s secret: %w", secretName, err)
 	}
 	p.console.MessageUxItem(ctx, &ux.CreatedRepoValue{
@@ -815,7 +819,7 @@ func (p *GitHubCiProvider) configureClientCredentialsAuth(
 					Kind: ux.GitHubVariable,
 				})
 			} else {
-				if err := p.ghCli.SetSecret(ctx, repoSlug, key, info.value); err != nil {
+				if err := p.ghCli.SetSecret(ctx, repoSlug, key, info.value, nil); err != nil {
 					return fmt.Errorf("setting github secret %s:: %w", key, err)
 				}
 				p.console.MessageUxItem(ctx, &ux.CreatedRepoValue{
@@ -1238,7 +1242,7 @@ func (p *GitHubCiProvider) configurePipeline(
 
 	// set the new variables and secrets
 	for key, value := range toBeSetSecrets {
-		if err := p.ghCli.SetSecret(ctx, repoSlug, key, value); err != nil {
+		if err := p.ghCli.SetSecret(ctx, repoSlug, key, value, nil); err != nil {
 			procErr = fmt.Errorf("failed setting %s secret: %w", key, err)
 			return nil, procErr
 		}
@@ -1256,6 +1260,214 @@ func (p *GitHubCiProvider) configurePipeline(
 	}, nil
 }
 
+// stageCredentialOptions returns a federated credential for the GitHub environment of each stage. The jobs of a
+// multi-stage workflow target an environment, so their OIDC token has the environment as subject instead of the branch.
+func (p *GitHubCiProvider) stageCredentialOptions(
+	ctx context.Context,
+	repoDetails *gitRepositoryDetails,
+	stages []*pipelineStage,
+) ([]*graphsdk.FederatedIdentityCredential, error) {
+	repoSlug := repoDetails.owner + "/" + repoDetails.repoName
+	credentialSafeName := credentialNameSanitizer.ReplaceAllString(repoSlug, "-")
+
+	oidcConfig, repoInfo, err := p.detectOIDCConfig(ctx, repoSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	federatedCredentials := make([]*graphsdk.FederatedIdentityCredential, 0, len(stages))
+	for _, stage := range stages {
+		subject, err := github.BuildOIDCSubject(repoSlug, repoInfo, oidcConfig, "environment:"+stage.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build OIDC subject for environment %s: %w", stage.name, err)
+		}
+
+		federatedCredentials = append(federatedCredentials, &graphsdk.FederatedIdentityCredential{
+			Name: fmt.Sprintf(
+				"%s-environment-%s", credentialSafeName, credentialNameSanitizer.ReplaceAllString(stage.name, "-")),
+			Issuer:      federatedIdentityIssuer,
+			Subject:     subject,
+			Description: new("Created by Azure Developer CLI"),
+			Audiences:   []string{federatedIdentityAudience},
+		})
+	}
+
+	return federatedCredentials, nil
+}
+
+// configureStages creates a GitHub environment for each stage and sets the stage variables and secrets on it. Values of
+// an environment take precedence over the repository ones for the jobs targeting the environment. When a stage has
+// reviewers, its job waits for the approval of one of them before running.
+func (p *GitHubCiProvider) configureStages(
+	ctx context.Context,
+	repoDetails *gitRepositoryDetails,
+	infraOptions provisioning.Options,
+	stages []*pipelineStage,
+) error {
+	repoSlug := repoDetails.owner + "/" + repoDetails.repoName
+
+	for _, stage := range stages {
+		if err := p.ghCli.CreateEnvironmentIfNotExist(ctx, repoSlug, stage.name); err != nil {
+			return fmt.Errorf("failed creating environment %s: %w", stage.name, err)
+		}
+
+		environmentResource := &ux.DisplayedResource{Type: "GitHub environment", Name: stage.name}
+		if len(stage.reviewers) > 0 {
+			if err := p.ghCli.SetEnvironmentReviewers(ctx, repoSlug, stage.name, stage.reviewers); err != nil {
+				return err
+			}
+			environmentResource.Name += fmt.Sprintf(" (approval from %s)", strings.Join(stage.reviewers, ", "))
+		}
+		p.console.MessageUxItem(ctx, environmentResource)
+
+		variables := map[string]string{
+			environment.EnvNameEnvVarName:        stage.env.Name(),
+			environment.LocationEnvVarName:       stage.env.GetLocation(),
+			environment.SubscriptionIdEnvVarName: stage.env.GetSubscriptionId(),
+		}
+		if infraOptions.Provider == provisioning.Terraform {
+			for _, key := range []string{"RS_RESOURCE_GROUP", "RS_STORAGE_ACCOUNT", "RS_CONTAINER_NAME"} {
+				variables[key] = stage.env.Getenv(key)
+			}
+		}
+		maps.Copy(variables, stage.variables)
+
+		for _, name := range slices.Sorted(maps.Keys(variables)) {
+			if variables[name] == "" {
+				continue
+			}
+			options := &github.SetVariableOptions{Environment: stage.name}
+			if err := p.ghCli.SetVariable(ctx, repoSlug, name, variables[name], options); err != nil {
+				return fmt.Errorf("failed setting %s variable for environment %s: %w", name, stage.name, err)
+			}
+			p.console.MessageUxItem(ctx, &ux.CreatedRepoValue{
+				Name:        name,
+				Kind:        ux.GitHubVariable,
+				Environment: stage.name,
+			})
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(stage.secrets)) {
+			options := &github.SetSecretOptions{Environment: stage.name}
+			if err := p.ghCli.SetSecret(ctx, repoSlug, name, stage.secrets[name], options); err != nil {
+				return fmt.Errorf("failed setting %s secret for environment %s: %w", name, stage.name, err)
+			}
+			p.console.MessageUxItem(ctx, &ux.CreatedRepoValue{
+				Name:        name,
+				Kind:        ux.GitHubSecret,
+				Environment: stage.name,
+			})
+		}
+	}
+
+	return nil
+}
+
+// gitHubRunPollAttempts is the number of times the runs of a workflow are listed, after triggering it, to find the new
+// run. GitHub creates the run asynchronously.
+const gitHubRunPollAttempts = 10
+
+// gitHubRunPollInterval is the time between the lists of the runs of a workflow, after triggering it.
+var gitHubRunPollInterval = 2 * time.Second
+
+// runPipeline triggers the workflow on the branch, through its workflow_dispatch trigger.
+func (p *GitHubCiProvider) runPipeline(
+	ctx context.Context,
+	repoDetails *gitRepositoryDetails,
+	definitionFile string,
+	branch string,
+) (*PipelineRun, error) {
+	repoSlug := repoDetails.owner + "/" + repoDetails.repoName
+	workflowName := filepath.Base(definitionFile)
+
+	// runs created before the workflow was triggered are ignored. The margin covers the clock skew with GitHub.
+	triggeredAt := time.Now().Add(-time.Minute)
+	if err := p.ghCli.RunWorkflow(ctx, repoSlug, workflowName, branch); err != nil {
+		if errors.Is(err, github.ErrWorkflowNotDispatchable) {
+			return nil, fmt.Errorf(
+				"%w. Add the workflow_dispatch trigger to %s and push it to run the workflow from azd", err, definitionFile)
+		}
+		return nil, err
+	}
+
+	var run *PipelineRun
+	err := retry.Do(
+		ctx,
+		retry.WithMaxRetries(gitHubRunPollAttempts-1, retry.NewConstant(gitHubRunPollInterval)),
+		func(ctx context.Context) error {
+			runs, err := p.ghCli.ListWorkflowRuns(ctx, repoSlug, workflowName, branch, 1)
+			if err != nil {
+				return err
+			}
+			if len(runs) == 0 || runs[0].Event != "workflow_dispatch" || runs[0].CreatedAt.Before(triggeredAt) {
+				return retry.RetryableError(errors.New("workflow run not created yet"))
+			}
+			run = gitHubPipelineRun(runs[0])
+			return nil
+		})
+	if err != nil {
+		// the workflow was triggered, so the run is reported without its details
+		log.Printf("finding the run of workflow %s: %v", workflowName, err)
+		return &PipelineRun{
+			Branch: branch,
+			Status: PipelineRunQueued,
+			Url:    fmt.Sprintf("%s/actions/workflows/%s", repoDetails.url, workflowName),
+		}, nil
+	}
+
+	return run, nil
+}
+
+// latestPipelineRun returns the latest run of the workflow on the branch.
+func (p *GitHubCiProvider) latestPipelineRun(
+	ctx context.Context,
+	repoDetails *gitRepositoryDetails,
+	definitionFile string,
+	branch string,
+) (*PipelineRun, error) {
+	repoSlug := repoDetails.owner + "/" + repoDetails.repoName
+	runs, err := p.ghCli.ListWorkflowRuns(ctx, repoSlug, filepath.Base(definitionFile), branch, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, nil
+	}
+
+	return gitHubPipelineRun(runs[0]), nil
+}
+
+// gitHubPipelineRun converts a GitHub Actions workflow run to a PipelineRun.
+func gitHubPipelineRun(run github.WorkflowRun) *PipelineRun {
+	status := PipelineRunQueued
+	switch run.Status {
+	case "in_progress":
+		status = PipelineRunInProgress
+	case "completed":
+		status = PipelineRunCompleted
+	}
+
+	result := run.Conclusion
+	switch run.Conclusion {
+	case "success":
+		result = "succeeded"
+	case "failure":
+		result = "failed"
+	case "cancelled":
+		result = "canceled"
+	}
+
+	return &PipelineRun{
+		Id:       strconv.FormatInt(run.DatabaseId, 10),
+		Name:     run.DisplayTitle,
+		Branch:   run.HeadBranch,
+		Status:   status,
+		Result:   result,
+		Url:      run.Url,
+		QueuedAt: &run.CreatedAt,
+	}
+}
+
 // workflow is the implementation for a CiPipeline for GitHub
 type workflow struct {
 	repoDetails *gitRepositoryDetails
@@ -308,3 +308,84 @@ func Test_credentialOptions_withOIDCCustomSubject(t *testing.T) {
 		)
 	})
 }
+
+func Test_gitHub_provider_stages(t *testing.T) {
+	repoDetails := &gitRepositoryDetails{
+		owner:    "Azure-Samples",
+		repoName: "my-repo",
+		branch:   "main",
+	}
+
+	stages := []*pipelineStage{
+		{
+			name: "dev",
+			env: environment.NewWithValues("app-dev", map[string]string{
+				environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_DEV",
+				environment.LocationEnvVarName:       "eastus2",
+			}),
+			variables: map[string]string{"API_URL": "https://dev.contoso.com"},
+		},
+		{
+			name: "prod",
+			env: environment.NewWithValues("app-prod", map[string]string{
+				environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_PROD",
+				environment.LocationEnvVarName:       "westus3",
+			}),
+			reviewers: []string{"octocat"},
+			secrets:   map[string]string{"API_KEY": "secret"},
+		},
+	}
+
+	t.Run("stageCredentialOptions", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		setupGithubCliMocks(mockContext)
+		mockContext.CommandRunner.When(func(args exec.RunArgs, cmd string) bool {
+			return strings.Contains(cmd, "oidc/customization/sub")
+		}).Respond(exec.NewRunResult(0, `{"use_default": true, "include_claim_keys": []}`, ""))
+
+		provider := createGitHubCiProvider(t, mockContext).(*GitHubCiProvider)
+		credentials, err := provider.stageCredentialOptions(t.Context(), repoDetails, stages)
+		require.NoError(t, err)
+		require.Len(t, credentials, 2)
+		require.Equal(t, "Azure-Samples-my-repo-environment-dev", credentials[0].Name)
+		require.Equal(t, "repo:Azure-Samples/my-repo:environment:dev", credentials[0].Subject)
+		require.Equal(t, "Azure-Samples-my-repo-environment-prod", credentials[1].Name)
+		require.Equal(t, "repo:Azure-Samples/my-repo:environment:prod", credentials[1].Subject)
+	})
+
+	t.Run("configureStages", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		setupGithubCliMocks(mockContext)
+
+		var commands []string
+		mockContext.CommandRunner.When(func(args exec.RunArgs, cmd string) bool {
+			return strings.Contains(cmd, "environments/") ||
+				strings.Contains(cmd, "variable set") ||
+				strings.Contains(cmd, "secret set")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			commands = append(commands, strings.Join(args.Args, " "))
+			return exec.NewRunResult(0, "", ""), nil
+		})
+		mockContext.CommandRunner.When(func(args exec.RunArgs, cmd string) bool {
+			return strings.Contains(cmd, "api /users/octocat")
+		}).Respond(exec.NewRunResult(0, "583231", ""))
+
+		provider := createGitHubCiProvider(t, mockContext).(*GitHubCiProvider)
+		err := provider.configureStages(t.Context(), repoDetails, provisioning.Options{}, stages)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{
+			"api -X PUT /repos/Azure-Samples/my-repo/environments/dev -H Accept: application/vnd.github+json",
+			"-R Azure-Samples/my-repo variable set API_URL --env dev",
+			"-R Azure-Samples/my-repo variable set AZURE_ENV_NAME --env dev",
+			"-R Azure-Samples/my-repo variable set AZURE_LOCATION --env dev",
+			"-R Azure-Samples/my-repo variable set AZURE_SUBSCRIPTION_ID --env dev",
+			"api -X PUT /repos/Azure-Samples/my-repo/environments/prod -H Accept: application/vnd.github+json",
+			"api -X PUT /repos/Azure-Samples/my-repo/environments/prod -H Accept: application/vnd.github+json --input -",
+			"-R Azure-Samples/my-repo variable set AZURE_ENV_NAME --env prod",
+			"-R Azure-Samples/my-repo variable set AZURE_LOCATION --env prod",
+			"-R Azure-Samples/my-repo variable set AZURE_SUBSCRIPTION_ID --env prod",
+			"-R Azure-Samples/my-repo secret set API_KEY --env prod",
+		}, commands)
+	})
+}
@@ -0,0 +1,627 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/azure/azure-dev/cli/azd/pkg/entraid"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/gitlab"
+	"github.com/azure/azure-dev/cli/azd/pkg/graphsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+)
+
+// GitLabRepositoryDetails provides extra state needed for GitLab projects.
+type GitLabRepositoryDetails struct {
+	// Host is the host of the GitLab instance, like gitlab.com.
+	Host string
+	// ProjectPath is the full path of the project, like group/subgroup/project.
+	ProjectPath string
+}
+
+// gitLabHost returns the host of the GitLab instance, from GITLAB_HOST or gitlab.com.
+func gitLabHost(env *environment.Environment) string {
+	if host, has := env.LookupEnv(gitlab.HostEnvVarName); has && host != "" {
+		return strings.TrimSuffix(strings.TrimPrefix(host, "https://"), "/")
+	}
+
+	return gitlab.DefaultHost
+}
+
+// ensureGitLabToken ensures a GitLab personal access token exists either in .env or system environment variables,
+// prompting for it otherwise, and returns a client for the GitLab instance. The returned bool is true when the token
+// was prompted for.
+func ensureGitLabToken(
+	ctx context.Context,
+	env *environment.Environment,
+	console input.Console,
+	transporter policy.Transporter,
+) (*gitlab.Client, bool, error) {
+	host := gitLabHost(env)
+	token, has := env.LookupEnv(gitlab.TokenEnvVarName)
+	updated := false
+	if !has || token == "" {
+		console.Message(ctx, fmt.Sprintf(
+			"You need a %s with the %s scope. Create one at %s",
+			output.WithWarningFormat("GitLab personal access token"),
+			output.WithHighLightFormat("api"),
+			output.WithLinkFormat("https://%s/-/user_settings/personal_access_tokens?name=azd&scopes=api", host)))
+		console.Message(ctx, fmt.Sprintf("(%s this prompt by setting the token to env var: %s)",
+			output.WithWarningFormat("%s", "skip"),
+			output.WithHighLightFormat("%s", gitlab.TokenEnvVarName)))
+
+		value, err := console.Prompt(ctx, input.ConsoleOptions{
+			Message:    "Personal access token:",
+			IsPassword: true,
+		})
+		if err != nil {
+			return nil, false, fmt.Errorf("asking for GitLab token: %w", err)
+		}
+
+		// set the token as an environment variable for this cmd run, so the other provider finds it
+		os.Setenv(gitlab.TokenEnvVarName, value)
+		token = value
+		updated = true
+	}
+
+	client := gitlab.NewClient(host, token, transporter)
+	if _, err := client.CurrentUser(ctx); err != nil {
+		return nil, updated, fmt.Errorf("validating the GitLab token for %s: %w", host, err)
+	}
+
+	return client, updated, nil
+}
+
+// GitLabScmProvider implements ScmProvider using GitLab as the provider
+// for source control manager.
+type GitLabScmProvider struct {
+	env         *environment.Environment
+	console     input.Console
+	gitCli      *git.Cli
+	transporter policy.Transporter
+	client      *gitlab.Client
+	// pipelineTimeout is how long GitPush waits for the first pipeline run.
+	pipelineTimeout time.Duration
+	// pipelinePollInterval is how often GitPush checks the first pipeline run.
+	pipelinePollInterval time.Duration
+}
+
+func NewGitLabScmProvider(
+	env *environment.Environment,
+	console input.Console,
+	gitCli *git.Cli,
+	transporter policy.Transporter,
+) ScmProvider {
+	return &GitLabScmProvider{
+		env:                  env,
+		console:              console,
+		gitCli:               gitCli,
+		transporter:          transporter,
+		pipelineTimeout:      2 * time.Minute,
+		pipelinePollInterval: 5 * time.Second,
+	}
+}
+
+// ***  subareaProvider implementation ******
+
+// requiredTools return the list of external tools required by
+// GitLab provider during its execution.
+func (p *GitLabScmProvider) requiredTools(_ context.Context) ([]tools.ExternalTool, error) {
+	return []tools.ExternalTool{}, nil
+}
+
+// preConfigureCheck ensures a GitLab token is available.
+func (p *GitLabScmProvider) preConfigureCheck(
+	ctx context.Context,
+	pipelineManagerArgs PipelineManagerArgs,
+	infraOptions provisioning.Options,
+	projectPath string,
+) (bool, error) {
+	client, updated, err := ensureGitLabToken(ctx, p.env, p.console, p.transporter)
+	if err != nil {
+		return updated, err
+	}
+
+	p.client = client
+	return updated, nil
+}
+
+// name returns the name of the provider
+func (p *GitLabScmProvider) Name() string {
+	return gitLabDisplayName
+}
+
+// ***  scmProvider implementation ******
+
+// configureGitRemote creates a new private GitLab project, or uses the URL of an existing one, as the remote url
+// for the local git project
+func (p *GitLabScmProvider) configureGitRemote(
+	ctx context.Context,
+	repoPath string,
+	remoteName string,
+) (string, error) {
+	idx, err := p.console.Select(ctx, input.ConsoleOptions{
+		Message: "How would you like to configure your git remote to GitLab?",
+		Options: []string{
+			"Create a new private GitLab project",
+			"Enter the URL of an existing GitLab project",
+		},
+		DefaultValue: "Create a new private GitLab project",
+	})
+	if err != nil {
+		return "", fmt.Errorf("prompting for remote configuration type: %w", err)
+	}
+
+	host := gitLabHost(p.env)
+
+	if idx == 0 {
+		name, err := p.console.Prompt(ctx, input.ConsoleOptions{
+			Message:      "Enter the name for your new GitLab project:",
+			DefaultValue: filepath.Base(repoPath),
+		})
+		if err != nil {
+			return "", fmt.Errorf("prompting for project name: %w", err)
+		}
+
+		project, err := p.client.CreateProject(ctx, name)
+		if err != nil {
+			return "", fmt.Errorf("creating GitLab project: %w", err)
+		}
+
+		p.console.MessageUxItem(ctx, &ux.DisplayedResource{
+			Type: "GitLab project",
+			Name: project.PathWithNamespace,
+		})
+
+		return project.HttpUrlToRepo, nil
+	}
+
+	for {
+		remoteUrl, err := p.console.Prompt(ctx, input.ConsoleOptions{
+			Message: fmt.Sprintf("Enter the url to use for remote %s:", remoteName),
+		})
+		if err != nil {
+			return "", fmt.Errorf("prompting for remote url: %w", err)
+		}
+
+		projectPath, err := gitlab.GetProjectPathForRemote(remoteUrl, host)
+		if err != nil {
+			p.console.Message(ctx, fmt.Sprintf("error: \"%s\" is not a valid URL of a project on %s.", remoteUrl, host))
+			continue
+		}
+
+		if _, err := p.client.GetProject(ctx, projectPath); err != nil {
+			return "", fmt.Errorf("getting GitLab project %s: %w", projectPath, err)
+		}
+
+		return remoteUrl, nil
+	}
+}
+
+// gitRepoDetails extracts the information from a GitLab remote url into general scm concepts
+// like owner, name and path
+func (p *GitLabScmProvider) gitRepoDetails(ctx context.Context, remoteUrl string) (*gitRepositoryDetails, error) {
+	host := gitLabHost(p.env)
+	projectPath, err := gitlab.GetProjectPathForRemote(remoteUrl, host)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"remote %s isn't a project on %s. Set %s to use a self-managed GitLab instance: %w",
+			remoteUrl, host, gitlab.HostEnvVarName, err)
+	}
+
+	separator := strings.LastIndex(projectPath, "/")
+	return &gitRepositoryDetails{
+		owner:    projectPath[:separator],
+		repoName: projectPath[separator+1:],
+		remote:   remoteUrl,
+		url:      fmt.Sprintf("https://%s/%s", host, projectPath),
+		details: &GitLabRepositoryDetails{
+			Host:        host,
+			ProjectPath: projectPath,
+		},
+	}, nil
+}
+
+// preventGitPush warns when CI/CD is disabled for the project, as the pipeline wouldn't run.
+func (p *GitLabScmProvider) preventGitPush(
+	ctx context.Context,
+	gitRepo *gitRepositoryDetails,
+	remoteName string,
+	branchName string) (bool, error) {
+	details := gitRepo.details.(*GitLabRepositoryDetails)
+	project, err := p.client.GetProject(ctx, details.ProjectPath)
+	if err != nil {
+		return false, fmt.Errorf("getting GitLab project %s: %w", details.ProjectPath, err)
+	}
+
+	if project.BuildsAccessLevel != "disabled" {
+		return false, nil
+	}
+
+	p.console.MessageUxItem(ctx, &ux.WarningMessage{
+		Description: fmt.Sprintf(
+			"CI/CD is disabled for %s. Enable it in Settings > General > Visibility, project features, permissions: %s",
+			details.ProjectPath,
+			output.WithLinkFormat("%s/edit", project.WebUrl)),
+	})
+
+	push, err := p.console.Confirm(ctx, input.ConsoleOptions{
+		Message:      "Push the changes anyway?",
+		DefaultValue: false,
+	})
+	if err != nil {
+		return false, fmt.Errorf("prompting to push: %w", err)
+	}
+
+	return !push, nil
+}
+
+// GitPush pushes the changes, then waits for the pipeline run of the pushed commit to be picked up by a runner.
+func (p *GitLabScmProvider) GitPush(
+	ctx context.Context,
+	gitRepo *gitRepositoryDetails,
+	remoteName string,
+	branchName string) error {
+	if err := p.gitCli.PushUpstream(ctx, gitRepo.gitProjectPath, remoteName, branchName); err != nil {
+		return err
+	}
+
+	// Verifying the pipeline run is best effort: the changes were pushed, so pushing again wouldn't help.
+	if err := p.verifyPipelineRun(ctx, gitRepo, branchName); err != nil {
+		log.Printf("verifying the GitLab pipeline run: %v", err)
+		p.console.MessageUxItem(ctx, &ux.WarningMessage{
+			Description: fmt.Sprintf("Couldn't verify the pipeline run: %s", err),
+		})
+	}
+
+	return nil
+}
+
+// pipelinePendingStatuses are the statuses of a pipeline that wasn't picked up by a runner yet.
+var pipelinePendingStatuses = []string{"created", "waiting_for_resource", "preparing", "pending", "scheduled"}
+
+// verifyPipelineRun waits for the pipeline run of the pushed commit to start, and warns when it doesn't.
+func (p *GitLabScmProvider) verifyPipelineRun(
+	ctx context.Context,
+	gitRepo *gitRepositoryDetails,
+	branchName string,
+) error {
+	details := gitRepo.details.(*GitLabRepositoryDetails)
+	project, err := p.client.GetProject(ctx, details.ProjectPath)
+	if err != nil {
+		return err
+	}
+
+	commit, err := p.gitCli.GetHeadCommit(ctx, gitRepo.gitProjectPath)
+	if err != nil {
+		return err
+	}
+
+	message := "Waiting for the pipeline run to start"
+	p.console.ShowSpinner(ctx, message, input.Step)
+
+	var pipeline *gitlab.Pipeline
+	deadline := time.Now().Add(p.pipelineTimeout)
+	for {
+		pipelines, err := p.client.ListPipelines(ctx, project.Id, branchName, commit)
+		if err != nil {
+			p.console.StopSpinner(ctx, message, input.StepFailed)
+			return err
+		}
+
+		if len(pipelines) > 0 {
+			pipeline = &pipelines[0]
+			if !slices.Contains(pipelinePendingStatuses, pipeline.Status) {
+				break
+			}
+		}
+
+		if time.Now().After(deadline) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			p.console.StopSpinner(ctx, message, input.StepFailed)
+			return ctx.Err()
+		case <-time.After(p.pipelinePollInterval):
+		}
+	}
+
+	switch {
+	case pipeline == nil:
+		p.console.StopSpinner(ctx, message, input.StepWarning)
+		p.console.MessageUxItem(ctx, &ux.WarningMessage{
+			Description: fmt.Sprintf(
+				"No pipeline was started for %s. Check that %s is in the default branch of the project, or in %s, "+
+					"and that its rules run on pushes to %s.",
+				commit[:min(len(commit), 8)], gitLabPipelineFile, branchName, branchName),
+		})
+	case slices.Contains(pipelinePendingStatuses, pipeline.Status):
+		p.console.StopSpinner(ctx, message, input.StepWarning)
+		p.console.MessageUxItem(ctx, &ux.WarningMessage{
+			Description: fmt.Sprintf(
+				"Pipeline #%d is still %s. Check that runners are available for the project: %s",
+				pipeline.Id, pipeline.Status, output.WithLinkFormat("%s/-/settings/ci_cd", project.WebUrl)),
+		})
+	default:
+		p.console.StopSpinner(ctx, message, input.StepDone)
+		p.console.MessageUxItem(ctx, &ux.DisplayedResource{
+			Type: fmt.Sprintf("Pipeline #%d (%s)", pipeline.Id, pipeline.Status),
+			Name: pipeline.WebUrl,
+		})
+	}
+
+	return nil
+}
+
+// GitLabCiProvider implements a CiProvider using GitLab CI/CD to run the pipeline defined in .gitlab-ci.yml.
+type GitLabCiProvider struct {
+	env         *environment.Environment
+	console     input.Console
+	transporter policy.Transporter
+	client      *gitlab.Client
+}
+
+func NewGitLabCiProvider(
+	env *environment.Environment,
+	console input.Console,
+	transporter policy.Transporter,
+) CiProvider {
+	return &GitLabCiProvider{
+		env:         env,
+		console:     console,
+		transporter: transporter,
+	}
+}
+
+// ***  subareaProvider implementation ******
+
+// requiredTools defines the requires tools for GitLab to be used as CI manager
+func (p *GitLabCiProvider) requiredTools(_ context.Context) ([]tools.ExternalTool, error) {
+	return []tools.ExternalTool{}, nil
+}
+
+// preConfigureCheck ensures a GitLab token is available.
+func (p *GitLabCiProvider) preConfigureCheck(
+	ctx context.Context,
+	pipelineManagerArgs PipelineManagerArgs,
+	infraOptions provisioning.Options,
+	projectPath string,
+) (bool, error) {
+	client, updated, err := ensureGitLabToken(ctx, p.env, p.console, p.transporter)
+	if err != nil {
+		return updated, err
+	}
+
+	p.client = client
+	return updated, nil
+}
+
+// name returns the name of the provider.
+func (p *GitLabCiProvider) Name() string {
+	return gitLabDisplayName
+}
+
+// ***  ciProvider implementation ******
+
+func (p *GitLabCiProvider) credentialOptions(
+	ctx context.Context,
+	repoDetails *gitRepositoryDetails,
+	infraOptions provisioning.Options,
+	authType PipelineAuthType,
+	credentials *entraid.AzureCredentials,
+) (*CredentialOptions, error) {
+	if authType == AuthTypeClientCredentials {
+		return &CredentialOptions{
+			EnableClientCredentials: true,
+		}, nil
+	}
+
+	// If not specified default to federated credentials
+	if authType == "" || authType == AuthTypeFederated {
+		details := repoDetails.details.(*GitLabRepositoryDetails)
+
+		// Configure federated auth for both main branch and current branch
+		branches := []string{repoDetails.branch}
+		if !slices.Contains(branches, "main") {
+			branches = append(branches, "main")
+		}
+
+		credentialSafeName := credentialNameSanitizer.ReplaceAllString(details.ProjectPath, "-")
+		federatedCredentials := []*graphsdk.FederatedIdentityCredential{}
+		for _, branch := range branches {
+			federatedCredentials = append(federatedCredentials, &graphsdk.FederatedIdentityCredential{
+				Name: fmt.Sprintf(
+					"%s-%s", credentialSafeName, credentialNameSanitizer.ReplaceAllString(branch, "-")),
+				// The ID tokens of GitLab CI/CD jobs are issued by the GitLab instance.
+				Issuer:      "https://" + details.Host,
+				Subject:     gitLabFederatedSubject(details.ProjectPath, branch),
+				Description: new("Created by Azure Developer CLI"),
+				Audiences:   []string{federatedIdentityAudience},
+			})
+		}
+
+		return &CredentialOptions{
+			EnableFederatedCredentials: true,
+			FederatedCredentialOptions: federatedCredentials,
+		}, nil
+	}
+
+	return &CredentialOptions{
+		EnableClientCredentials:    false,
+		EnableFederatedCredentials: false,
+	}, nil
+}
+
+// configureConnection sets the CI/CD variables GitLab CI/CD jobs use to log in to Azure, and the variables azd needs
+// to provision the infrastructure.
+func (p *GitLabCiProvider) configureConnection(
+	ctx context.Context,
+	repoDetails *gitRepositoryDetails,
+	infraOptions provisioning.Options,
+	authConfig *authConfiguration,
+	credentialOptions *CredentialOptions,
+) error {
+	details := repoDetails.details.(*GitLabRepositoryDetails)
+	project, err := p.client.GetProject(ctx, details.ProjectPath)
+	if err != nil {
+		return fmt.Errorf("getting GitLab project %s: %w", details.ProjectPath, err)
+	}
+
+	variables := map[string]string{
+		environment.EnvNameEnvVarName:        p.env.Name(),
+		environment.LocationEnvVarName:       p.env.GetLocation(),
+		environment.SubscriptionIdEnvVarName: p.env.GetSubscriptionId(),
+		environment.TenantIdEnvVarName:       authConfig.TenantId,
+		"AZURE_CLIENT_ID":                    authConfig.ClientId,
+	}
+	secrets := map[string]string{}
+
+	if credentialOptions.EnableClientCredentials {
+		/* #nosec G101 - Potential hardcoded credentials - false positive */
+		secrets["AZURE_CLIENT_SECRET"] = authConfig.ClientSecret
+		if infraOptions.Provider == provisioning.Terraform {
+			secrets["ARM_CLIENT_SECRET"] = authConfig.ClientSecret
+		}
+	}
+
+	if infraOptions.Provider == provisioning.Terraform {
+		for _, key := range []string{"RS_RESOURCE_GROUP", "RS_STORAGE_ACCOUNT", "RS_CONTAINER_NAME"} {
+			value, ok := p.env.LookupEnv(key)
+			if !ok || strings.TrimSpace(value) == "" {
+				p.console.StopSpinner(ctx, "Configuring terraform", input.StepWarning)
+				p.console.MessageUxItem(ctx, &ux.WarningMessage{
+					Description: "Terraform Remote State configuration is invalid",
+					HidePrefix:  true,
+				})
+				p.console.Message(
+					ctx,
+					fmt.Sprintf(
+						"Visit %s for more information on configuring Terraform remote state",
+						output.WithLinkFormat("https://aka.ms/azure-dev/terraform"),
+					),
+				)
+				p.console.Message(ctx, "")
+				return errors.New("terraform remote state is not correctly configured")
+			}
+			variables[key] = value
+		}
+	}
+
+	if infraOptions.Provider == provisioning.Bicep {
+		if rgName, has := p.env.LookupEnv(environment.ResourceGroupEnvVarName); has {
+			variables[environment.ResourceGroupEnvVarName] = rgName
+		}
+	}
+
+	return p.setVariables(ctx, project.Id, variables, secrets)
+}
+
+// setVariables sets the variables and secrets as CI/CD variables of the project. Secrets are masked when GitLab
+// supports masking their value.
+func (p *GitLabCiProvider) setVariables(
+	ctx context.Context,
+	projectId int,
+	variables map[string]string,
+	secrets map[string]string,
+) error {
+	for _, key := range slices.Sorted(maps.Keys(variables)) {
+		if err := p.client.SetVariable(ctx, projectId, gitlab.Variable{
+			Key:   key,
+			Value: variables[key],
+			Raw:   true,
+		}); err != nil {
+			return err
+		}
+		p.console.MessageUxItem(ctx, &ux.CreatedRepoValue{
+			Name: key,
+			Kind: ux.GitHubVariable,
+		})
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(secrets)) {
+		value := secrets[key]
+		masked := gitlab.CanMask(value)
+		if err := p.client.SetVariable(ctx, projectId, gitlab.Variable{
+			Key:    key,
+			Value:  value,
+			Masked: masked,
+			Raw:    true,
+		}); err != nil {
+			return err
+		}
+		p.console.MessageUxItem(ctx, &ux.CreatedRepoValue{
+			Name: key,
+			Kind: ux.GitHubSecret,
+		})
+
+		if !masked {
+			p.console.MessageUxItem(ctx, &ux.WarningMessage{
+				Description: fmt.Sprintf(
+					"The value of %s can't be masked in job logs by GitLab. Masked values must have 8 characters or "+
+						"more, and only contain letters, digits and the characters @:.~+/=_-", key),
+			})
+		}
+	}
+
+	return nil
+}
+
+// configurePipeline sets the variables and secrets of the project as CI/CD variables. The pipeline itself is
+// defined by .gitlab-ci.yml, and runs on push.
+func (p *GitLabCiProvider) configurePipeline(
+	ctx context.Context,
+	repoDetails *gitRepositoryDetails,
+	options *configurePipelineOptions,
+) (CiPipeline, error) {
+	if len(options.variables) > 0 || len(options.secrets) > 0 {
+		details := repoDetails.details.(*GitLabRepositoryDetails)
+		project, err := p.client.GetProject(ctx, details.ProjectPath)
+		if err != nil {
+			return nil, fmt.Errorf("getting GitLab project %s: %w", details.ProjectPath, err)
+		}
+
+		message := "Setting up project's variables to be used in the pipeline"
+		p.console.ShowSpinner(ctx, message, input.Step)
+		err = p.setVariables(ctx, project.Id, options.variables, options.secrets)
+		p.console.StopSpinner(ctx, message, input.GetStepResultFormat(err))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &gitLabPipeline{
+		repoDetails: repoDetails,
+	}, nil
+}
+
+// gitLabPipeline is the implementation for a CiPipeline for GitLab
+type gitLabPipeline struct {
+	repoDetails *gitRepositoryDetails
+}
+
+func (p *gitLabPipeline) name() string {
+	return "pipelines"
+}
+
+func (p *gitLabPipeline) url() string {
+	return p.repoDetails.url + "/-/pipelines"
+}
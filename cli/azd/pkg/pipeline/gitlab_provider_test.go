@@ -0,0 +1,177 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/gitlab"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GitLabScmProvider_gitRepoDetails(t *testing.T) {
+	t.Run("SelfManaged", func(t *testing.T) {
+		provider := &GitLabScmProvider{
+			env: environment.NewWithValues("test", map[string]string{
+				gitlab.HostEnvVarName: "https://gitlab.contoso.com/",
+			}),
+		}
+
+		details, err := provider.gitRepoDetails(t.Context(), "git@gitlab.contoso.com:group/sub/app.git")
+		require.NoError(t, err)
+		require.Equal(t, "group/sub", details.owner)
+		require.Equal(t, "app", details.repoName)
+		require.Equal(t, "https://gitlab.contoso.com/group/sub/app", details.url)
+		require.Equal(t, &GitLabRepositoryDetails{
+			Host:        "gitlab.contoso.com",
+			ProjectPath: "group/sub/app",
+		}, details.details)
+	})
+
+	t.Run("OtherHost", func(t *testing.T) {
+		provider := &GitLabScmProvider{env: environment.NewWithValues("test", nil)}
+
+		_, err := provider.gitRepoDetails(t.Context(), "https://github.com/owner/app.git")
+		require.ErrorIs(t, err, gitlab.ErrRemoteHostIsNotGitLab)
+	})
+}
+
+func Test_GitLabCiProvider_credentialOptions(t *testing.T) {
+	provider := &GitLabCiProvider{}
+	repoDetails := &gitRepositoryDetails{
+		branch: "feature/x",
+		details: &GitLabRepositoryDetails{
+			Host:        gitlab.DefaultHost,
+			ProjectPath: "group/app",
+		},
+	}
+
+	options, err := provider.credentialOptions(t.Context(), repoDetails, provisioning.Options{}, AuthTypeFederated, nil)
+	require.NoError(t, err)
+	require.True(t, options.EnableFederatedCredentials)
+	require.Len(t, options.FederatedCredentialOptions, 2)
+
+	credential := options.FederatedCredentialOptions[0]
+	require.Equal(t, "group-app-feature-x", credential.Name)
+	require.Equal(t, "https://gitlab.com", credential.Issuer)
+	require.Equal(t, "project_path:group/app:ref_type:branch:ref:feature/x", credential.Subject)
+	require.Equal(t, []string{federatedIdentityAudience}, credential.Audiences)
+	require.Equal(t,
+		"project_path:group/app:ref_type:branch:ref:main", options.FederatedCredentialOptions[1].Subject)
+
+	options, err = provider.credentialOptions(
+		t.Context(), repoDetails, provisioning.Options{}, AuthTypeClientCredentials, nil)
+	require.NoError(t, err)
+	require.True(t, options.EnableClientCredentials)
+	require.False(t, options.EnableFederatedCredentials)
+}
+
+func Test_GitLabCiProvider_setVariables(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+
+	set := map[string]gitlab.Variable{}
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && strings.HasPrefix(request.URL.Path, "/api/v4/projects/7/variables/")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		var variable gitlab.Variable
+		require.NoError(t, json.NewDecoder(request.Body).Decode(&variable))
+		set[variable.Key] = variable
+		return gitLabResponse(request, http.StatusOK, "{}"), nil
+	})
+
+	provider := &GitLabCiProvider{
+		console: mockContext.Console,
+		client:  gitlab.NewClient(gitlab.DefaultHost, "token", mockContext.HttpClient),
+	}
+
+	err := provider.setVariables(*mockContext.Context, 7,
+		map[string]string{"AZURE_ENV_NAME": "dev"},
+		map[string]string{"AZURE_CLIENT_SECRET": "s3cr3t~value", "SHORT": "abc"},
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, gitlab.Variable{Key: "AZURE_ENV_NAME", Value: "dev", Raw: true}, set["AZURE_ENV_NAME"])
+	require.True(t, set["AZURE_CLIENT_SECRET"].Masked)
+	require.False(t, set["SHORT"].Masked)
+	require.Contains(t, strings.Join(mockContext.Console.Output(), "\n"), "The value of SHORT can't be masked")
+}
+
+func Test_GitLabScmProvider_verifyPipelineRun(t *testing.T) {
+	cases := []struct {
+		name      string
+		pipelines string
+		expected  string
+	}{
+		{
+			name:      "Started",
+			pipelines: `[{"id": 12, "status": "running", "web_url": "https://gitlab.com/group/app/-/pipelines/12"}]`,
+			expected:  "https://gitlab.com/group/app/-/pipelines/12",
+		},
+		{
+			name:      "Pending",
+			pipelines: `[{"id": 12, "status": "pending"}]`,
+			expected:  "Pipeline #12 is still pending",
+		},
+		{
+			name:      "NotStarted",
+			pipelines: `[]`,
+			expected:  "No pipeline was started for 0123abcd",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(t.Context())
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "rev-parse HEAD")
+			}).Respond(exec.NewRunResult(0, "0123abcdef\n", ""))
+
+			mockContext.HttpClient.When(func(request *http.Request) bool {
+				return request.URL.Path == "/api/v4/projects/group/app"
+			}).RespondFn(func(request *http.Request) (*http.Response, error) {
+				return gitLabResponse(request, http.StatusOK, `{"id": 7, "web_url": "https://gitlab.com/group/app"}`), nil
+			})
+			mockContext.HttpClient.When(func(request *http.Request) bool {
+				return request.URL.Path == "/api/v4/projects/7/pipelines"
+			}).RespondFn(func(request *http.Request) (*http.Response, error) {
+				require.Equal(t, "main", request.URL.Query().Get("ref"))
+				require.Equal(t, "0123abcdef", request.URL.Query().Get("sha"))
+				return gitLabResponse(request, http.StatusOK, tc.pipelines), nil
+			})
+
+			provider := &GitLabScmProvider{
+				console:              mockContext.Console,
+				gitCli:               git.NewCli(mockContext.CommandRunner),
+				client:               gitlab.NewClient(gitlab.DefaultHost, "token", mockContext.HttpClient),
+				pipelineTimeout:      10 * time.Millisecond,
+				pipelinePollInterval: time.Millisecond,
+			}
+
+			err := provider.verifyPipelineRun(*mockContext.Context, &gitRepositoryDetails{
+				details: &GitLabRepositoryDetails{Host: gitlab.DefaultHost, ProjectPath: "group/app"},
+			}, "main")
+			require.NoError(t, err)
+			require.Contains(t, strings.Join(mockContext.Console.Output(), "\n"), tc.expected)
+		})
+	}
+}
+
+func gitLabResponse(request *http.Request, statusCode int, body string) *http.Response {
+	return &http.Response{
+		Request:    request,
+		StatusCode: statusCode,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
@@ -283,7 +283,11 @@ const (
 	azdoRoot          string = ".azdo"
 	azdoRootAlt       string = ".azuredevops"
 	azdoPipelines     string = "pipelines"
-	envPersistedKey   string = "AZD_PIPELINE_PROVIDER"
+	gitLabDisplayName string = "GitLab"
+	gitLabCode               = "gitlab"
+	// gitLabPipelineFile is the file GitLab CI/CD reads the pipeline from, at the root of the repository.
+	gitLabPipelineFile string = ".gitlab-ci.yml"
+	envPersistedKey    string = "AZD_PIPELINE_PROVIDER"
 )
 
 var (
@@ -314,6 +318,14 @@ var (
 			DefaultFile: pipelineFileNames[0],
 			DisplayName: azdoDisplayName,
 		},
+		ciProviderGitLab: {
+			// The pipeline is defined at the root of the repository, not in a directory of its own.
+			RootDirectories:     []string{},
+			PipelineDirectories: []string{""},
+			Files:               []string{gitLabPipelineFile},
+			DefaultFile:         gitLabPipelineFile,
+			DisplayName:         gitLabDisplayName,
+		},
 	}
 )
 
@@ -332,11 +344,12 @@ type ciProviderType string
 const (
 	ciProviderGitHubActions ciProviderType = gitHubCode
 	ciProviderAzureDevOps   ciProviderType = azdoCode
+	ciProviderGitLab        ciProviderType = gitLabCode
 )
 
 func toCiProviderType(provider string) (ciProviderType, error) {
 	result := ciProviderType(provider)
-	if result == ciProviderGitHubActions || result == ciProviderAzureDevOps {
+	if result == ciProviderGitHubActions || result == ciProviderAzureDevOps || result == ciProviderGitLab {
 		return result, nil
 	}
 	return "", fmt.Errorf("invalid ci provider type %s", provider)
@@ -368,7 +381,10 @@ type projectProperties struct {
 	Variables             []string
 	Secrets               []string
 	RequiredAlphaFeatures []string
-	providerParameters    []provisioning.Parameter
+	DeployTags            []string
+	// Stages are the names of the stages of a multi-stage pipeline, in promotion order.
+	Stages             []string
+	providerParameters []provisioning.Parameter
 }
 
 type authConfiguration struct {
@@ -0,0 +1,361 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/braydonk/yaml"
+	"github.com/fatih/color"
+	dmp "github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// definitionEdit is a change to the lines of a pipeline definition. Editing the lines, instead of encoding the parsed
+// definition again, keeps the formatting and the comments of the rest of the file.
+type definitionEdit struct {
+	// line is the 1-based line of the edit. The inserted lines go before it, and a removal removes it.
+	line   int
+	insert []string
+	remove bool
+}
+
+// pipelineEnvEntry formats the env entry of a pipeline definition that passes the variable or secret name to azd.
+type pipelineEnvEntry func(name string) string
+
+// gitHubValueRegex matches the GitHub Actions expressions reading a variable or a secret, like ${{ vars.NAME }}.
+var gitHubValueRegex = regexp.MustCompile(`^\$\{\{\s*(vars|secrets)\.([A-Za-z0-9_]+)\s*\}\}$`)
+
+// updatePipelineDefinition returns the contents of an existing pipeline definition, updated to pass the variables and
+// secrets of the project to azd. Missing entries are added, and entries of a variable that became a secret, or the
+// other way around, are moved. Other contents of the definition are kept as-is.
+func updatePipelineDefinition(props projectProperties, contents []byte) ([]byte, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(contents, &document); err != nil {
+		return nil, fmt.Errorf("parsing pipeline definition: %w", err)
+	}
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return contents, nil
+	}
+
+	variables, secrets := pipelineVariablesAndSecrets(props)
+
+	var edits []definitionEdit
+	switch props.CiProvider {
+	case ciProviderGitHubActions:
+		edits = gitHubWorkflowEdits(document.Content[0], variables, secrets)
+	case ciProviderAzureDevOps:
+		edits = azdoPipelineEdits(document.Content[0], append(variables, secrets...))
+	}
+
+	return applyDefinitionEdits(contents, edits), nil
+}
+
+// gitHubWorkflowEdits updates the jobs running azd. Variables are passed to the whole job from the GitHub variables, and
+// secrets are passed to the azd steps only, from the GitHub secrets.
+func gitHubWorkflowEdits(workflow *yaml.Node, variables []string, secrets []string) []definitionEdit {
+	jobs := mappingValue(workflow, "jobs")
+	if jobs == nil || jobs.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	variableEntry := func(name string) string { return fmt.Sprintf("%s: ${{ vars.%s }}", name, name) }
+	secretEntry := func(name string) string { return fmt.Sprintf("%s: ${{ secrets.%s }}", name, name) }
+
+	var edits []definitionEdit
+	for i := 1; i < len(jobs.Content); i += 2 {
+		job := jobs.Content[i]
+		steps := azdSteps(mappingValue(job, "steps"), func(step *yaml.Node) string {
+			return scalarValue(mappingValue(step, "run"))
+		})
+		if len(steps) == 0 {
+			continue
+		}
+
+		staleJobEntry := func(name string, value string) bool {
+			return !slices.Contains(variables, name) && slices.Contains(secrets, name) &&
+				gitHubValueRef(value) == "vars."+name
+		}
+		edits = append(edits, envEdits(job, "steps", variables, variableEntry, staleJobEntry)...)
+
+		// secrets set for the whole job are already available to the steps
+		jobEnv := mappingValue(job, "env")
+		missingSecrets := slices.DeleteFunc(slices.Clone(secrets), func(name string) bool {
+			value := mappingValue(jobEnv, name)
+			return value != nil && !staleJobEntry(name, value.Value)
+		})
+
+		for _, step := range steps {
+			edits = append(edits, envEdits(step, "run", missingSecrets, secretEntry,
+				func(name string, value string) bool {
+					return !slices.Contains(secrets, name) && slices.Contains(variables, name) &&
+						gitHubValueRef(value) == "secrets."+name
+				})...)
+		}
+	}
+
+	return edits
+}
+
+// azdoPipelineEdits updates the azd steps of the pipeline, which get both the variables and the secrets from the
+// pipeline variables. Steps are looked up at any level, to support pipelines with stages and jobs.
+func azdoPipelineEdits(pipeline *yaml.Node, names []string) []definitionEdit {
+	entry := func(name string) string { return fmt.Sprintf("%s: $(%s)", name, name) }
+
+	var edits []definitionEdit
+	var walk func(node *yaml.Node)
+	walk = func(node *yaml.Node) {
+		if node.Kind != yaml.MappingNode {
+			for _, child := range node.Content {
+				walk(child)
+			}
+			return
+		}
+
+		steps := azdSteps(mappingValue(node, "steps"), func(step *yaml.Node) string {
+			for _, key := range []string{"script", "bash", "pwsh", "powershell"} {
+				if script := scalarValue(mappingValue(step, key)); script != "" {
+					return script
+				}
+			}
+			return scalarValue(mappingValue(mappingValue(step, "inputs"), "inlineScript"))
+		})
+		for _, step := range steps {
+			edits = append(edits, envEdits(step, "inputs", names, entry, nil)...)
+		}
+
+		for i := 1; i < len(node.Content); i += 2 {
+			walk(node.Content[i])
+		}
+	}
+	walk(pipeline)
+
+	return edits
+}
+
+// azdSteps returns the steps running azd provision or azd deploy.
+func azdSteps(steps *yaml.Node, script func(step *yaml.Node) string) []*yaml.Node {
+	if steps == nil || steps.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	var result []*yaml.Node
+	for _, step := range steps.Content {
+		if step.Kind != yaml.MappingNode {
+			continue
+		}
+		if run := script(step); strings.Contains(run, "azd provision") || strings.Contains(run, "azd deploy") {
+			result = append(result, step)
+		}
+	}
+	return result
+}
+
+// envEdits returns the edits adding the missing names to the env mapping of parent, and removing the entries matching
+// stale. When parent has no env mapping, one is added next to the anchor key, or before another key of parent.
+func envEdits(
+	parent *yaml.Node,
+	anchor string,
+	names []string,
+	entry pipelineEnvEntry,
+	stale func(name string, value string) bool,
+) []definitionEdit {
+	env := mappingValue(parent, "env")
+	existing := envNames(env)
+	var missing []string
+	for _, name := range names {
+		if !slices.Contains(existing, name) && !slices.Contains(missing, name) {
+			missing = append(missing, name)
+		}
+	}
+
+	if env == nil {
+		if len(missing) == 0 {
+			return nil
+		}
+
+		key, line := mappingKey(parent, anchor), 0
+		if value := mappingValue(parent, anchor); value != nil && isSingleLine(key, value) {
+			// after the anchor, like in the generated definitions
+			line = value.Line + 1
+		} else if key != nil && key != parent.Content[0] {
+			line = key.Line
+		} else if len(parent.Content) >= 4 {
+			// the first key may be on the line of the sequence item, so add the env mapping before the second key
+			key = parent.Content[2]
+			line = key.Line
+		} else {
+			return nil
+		}
+
+		indent := strings.Repeat(" ", key.Column-1)
+		lines := []string{indent + "env:"}
+		for _, name := range missing {
+			lines = append(lines, indent+"  "+entry(name))
+		}
+		return []definitionEdit{{line: line, insert: lines}}
+	}
+
+	if env.Kind != yaml.MappingNode || env.Style&yaml.FlowStyle != 0 || len(env.Content) == 0 {
+		return nil
+	}
+
+	var edits []definitionEdit
+	removed := 0
+	for i := 0; i+1 < len(env.Content); i += 2 {
+		key, value := env.Content[i], env.Content[i+1]
+		if stale != nil && isSingleLine(key, value) && stale(key.Value, value.Value) {
+			edits = append(edits, definitionEdit{line: key.Line, remove: true})
+			removed++
+		}
+	}
+
+	if len(missing) > 0 {
+		indent := strings.Repeat(" ", env.Content[0].Column-1)
+		lines := make([]string, len(missing))
+		for i, name := range missing {
+			lines[i] = indent + entry(name)
+		}
+
+		// add the entries after the last one, unless its value spans several lines
+		line := env.Content[0].Line
+		if lastKey, lastValue := env.Content[len(env.Content)-2], env.Content[len(env.Content)-1]; isSingleLine(
+			lastKey, lastValue) {
+			line = lastValue.Line + 1
+		}
+		edits = append(edits, definitionEdit{line: line, insert: lines})
+	} else if removed > 0 && removed == len(env.Content)/2 {
+		// don't leave an empty env mapping behind
+		edits = append(edits, definitionEdit{line: mappingKey(parent, "env").Line, remove: true})
+	}
+
+	return edits
+}
+
+// applyDefinitionEdits applies the edits to the lines of contents.
+func applyDefinitionEdits(contents []byte, edits []definitionEdit) []byte {
+	if len(edits) == 0 {
+		return contents
+	}
+
+	newline := "\n"
+	if bytes.Contains(contents, []byte("\r\n")) {
+		newline = "\r\n"
+	}
+
+	inserts := map[int][]string{}
+	removes := map[int]bool{}
+	for _, edit := range edits {
+		inserts[edit.line] = append(inserts[edit.line], edit.insert...)
+		if edit.remove {
+			removes[edit.line] = true
+		}
+	}
+
+	text := strings.TrimSuffix(string(contents), newline)
+	lines := strings.Split(text, newline)
+	var result []string
+	for i, line := range lines {
+		result = append(result, inserts[i+1]...)
+		if !removes[i+1] {
+			result = append(result, line)
+		}
+	}
+	result = append(result, inserts[len(lines)+1]...)
+
+	updated := strings.Join(result, newline)
+	if strings.HasSuffix(string(contents), newline) {
+		updated += newline
+	}
+	return []byte(updated)
+}
+
+// definitionDiff returns the changed lines between old and new, with the removed lines in red and the added lines in
+// green.
+func definitionDiff(old string, new string) string {
+	diffObj := dmp.New()
+	oldChars, newChars, lines := diffObj.DiffLinesToChars(old, new)
+	diffs := diffObj.DiffCharsToLines(diffObj.DiffMain(oldChars, newChars, false), lines)
+
+	var sb strings.Builder
+	for _, diff := range diffs {
+		for line := range strings.SplitSeq(strings.TrimSuffix(diff.Text, "\n"), "\n") {
+			switch diff.Type {
+			case dmp.DiffInsert:
+				sb.WriteString(color.GreenString("+ %s\n", line))
+			case dmp.DiffDelete:
+				sb.WriteString(color.RedString("- %s\n", line))
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// isSingleLine reports whether the mapping entry of key and value is on a single line.
+func isSingleLine(key *yaml.Node, value *yaml.Node) bool {
+	return key.Line == value.Line && value.Kind == yaml.ScalarNode &&
+		value.Style&(yaml.LiteralStyle|yaml.FoldedStyle) == 0
+}
+
+// gitHubValueRef returns the variable or secret read by a GitHub Actions expression, like vars.NAME.
+func gitHubValueRef(value string) string {
+	match := gitHubValueRegex.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return ""
+	}
+	return match[1] + "." + match[2]
+}
+
+// envNames returns the names set by an env mapping.
+func envNames(env *yaml.Node) []string {
+	if env == nil || env.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	names := make([]string, 0, len(env.Content)/2)
+	for i := 0; i < len(env.Content); i += 2 {
+		names = append(names, env.Content[i].Value)
+	}
+	return names
+}
+
+// mappingKey returns the key node of key in the mapping node, or nil when the key isn't set.
+func mappingKey(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i]
+		}
+	}
+	return nil
+}
+
+// mappingValue returns the value node of key in the mapping node, or nil when the key isn't set.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// scalarValue returns the value of a scalar node, or an empty string for any other node.
+func scalarValue(node *yaml.Node) string {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return node.Value
+}
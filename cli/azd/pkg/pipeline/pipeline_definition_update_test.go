@@ -0,0 +1,174 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/test/snapshot"
+	"github.com/stretchr/testify/require"
+)
+
+// generateDefinition returns the pipeline definition generated for props.
+func generateDefinition(t *testing.T, props projectProperties) []byte {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "azure-dev.yml")
+	require.NoError(t, generatePipelineDefinition(path, props))
+
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return contents
+}
+
+func Test_updatePipelineDefinition(t *testing.T) {
+	gitHub := projectProperties{
+		CiProvider:    ciProviderGitHubActions,
+		InfraProvider: infraProviderBicep,
+		BranchName:    "main",
+		AuthType:      AuthTypeFederated,
+	}
+	azdo := projectProperties{
+		CiProvider:    ciProviderAzureDevOps,
+		InfraProvider: infraProviderBicep,
+		BranchName:    "main",
+		AuthType:      AuthTypeFederated,
+	}
+
+	t.Run("generated definitions are up to date", func(t *testing.T) {
+		withValues := func(props projectProperties) projectProperties {
+			props.Variables = []string{"API_URL"}
+			props.Secrets = []string{"API_KEY"}
+			return props
+		}
+		terraform := withValues(gitHub)
+		terraform.InfraProvider = infraProviderTerraform
+		terraform.AuthType = AuthTypeClientCredentials
+		stages := withValues(gitHub)
+		stages.Stages = []string{"dev", "prod"}
+
+		for _, props := range []projectProperties{
+			gitHub, withValues(gitHub), terraform, stages, azdo, withValues(azdo),
+		} {
+			contents := generateDefinition(t, props)
+			updated, err := updatePipelineDefinition(props, contents)
+			require.NoError(t, err)
+			require.Equal(t, string(contents), string(updated))
+		}
+	})
+
+	t.Run("github adds variables and secrets", func(t *testing.T) {
+		contents := generateDefinition(t, gitHub)
+
+		props := gitHub
+		props.Variables = []string{"API_URL"}
+		props.Secrets = []string{"API_KEY"}
+		updated, err := updatePipelineDefinition(props, contents)
+		require.NoError(t, err)
+		snapshot.SnapshotT(t, normalizeEOL(updated))
+
+		// updating again is a no-op
+		again, err := updatePipelineDefinition(props, updated)
+		require.NoError(t, err)
+		require.Equal(t, string(updated), string(again))
+	})
+
+	t.Run("github moves a variable that became a secret", func(t *testing.T) {
+		props := gitHub
+		props.Variables = []string{"API_URL", "API_KEY"}
+		contents := generateDefinition(t, props)
+
+		props.Variables = []string{"API_URL"}
+		props.Secrets = []string{"API_KEY"}
+		updated, err := updatePipelineDefinition(props, contents)
+		require.NoError(t, err)
+		require.Equal(t, string(generateDefinition(t, props)), string(updated))
+	})
+
+	t.Run("github moves a secret that became a variable", func(t *testing.T) {
+		props := gitHub
+		props.Secrets = []string{"API_URL"}
+		contents := generateDefinition(t, props)
+
+		props.Secrets = nil
+		props.Variables = []string{"API_URL"}
+		updated, err := updatePipelineDefinition(props, contents)
+		require.NoError(t, err)
+		require.Equal(t, string(generateDefinition(t, props)), string(updated))
+	})
+
+	t.Run("azdo adds variables and secrets", func(t *testing.T) {
+		contents := generateDefinition(t, azdo)
+
+		props := azdo
+		props.Variables = []string{"API_URL"}
+		props.Secrets = []string{"API_KEY"}
+		updated, err := updatePipelineDefinition(props, contents)
+		require.NoError(t, err)
+		require.Equal(t, string(generateDefinition(t, props)), string(updated))
+	})
+
+	t.Run("keeps comments, custom entries and line endings", func(t *testing.T) {
+		contents := strings.Join([]string{
+			"# Deploy the app",
+			"on: push",
+			"jobs:",
+			"  deploy:",
+			"    runs-on: ubuntu-latest",
+			"    steps:",
+			"      - uses: actions/checkout@v4",
+			"      # provision and deploy",
+			"      - name: Deploy",
+			"        run: |",
+			"          azd provision --no-prompt",
+			"          azd deploy --no-prompt",
+			"        env:",
+			"          CUSTOM: ${{ vars.CUSTOM_VALUE }}",
+			"",
+		}, "\r\n")
+
+		props := gitHub
+		props.Variables = []string{"API_URL"}
+		props.Secrets = []string{"API_KEY", "CUSTOM"}
+		updated, err := updatePipelineDefinition(props, []byte(contents))
+		require.NoError(t, err)
+		require.Equal(t, strings.Join([]string{
+			"# Deploy the app",
+			"on: push",
+			"jobs:",
+			"  deploy:",
+			"    runs-on: ubuntu-latest",
+			"    env:",
+			"      API_URL: ${{ vars.API_URL }}",
+			"    steps:",
+			"      - uses: actions/checkout@v4",
+			"      # provision and deploy",
+			"      - name: Deploy",
+			"        run: |",
+			"          azd provision --no-prompt",
+			"          azd deploy --no-prompt",
+			"        env:",
+			"          CUSTOM: ${{ vars.CUSTOM_VALUE }}",
+			"          API_KEY: ${{ secrets.API_KEY }}",
+			"",
+		}, "\r\n"), string(updated))
+	})
+
+	t.Run("skips definitions without azd steps", func(t *testing.T) {
+		contents := []byte("on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: go test\n")
+
+		props := gitHub
+		props.Variables = []string{"API_URL"}
+		updated, err := updatePipelineDefinition(props, contents)
+		require.NoError(t, err)
+		require.Equal(t, string(contents), string(updated))
+	})
+
+	t.Run("invalid definition", func(t *testing.T) {
+		_, err := updatePipelineDefinition(gitHub, []byte("jobs: [\n"))
+		require.Error(t, err)
+	})
+}
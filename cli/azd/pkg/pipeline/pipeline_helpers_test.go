@@ -46,6 +46,11 @@ func Test_toCiProviderType(t *testing.T) {
 			input: "azdo",
 			want:  ciProviderAzureDevOps,
 		},
+		{
+			name:  "gitlab",
+			input: "gitlab",
+			want:  ciProviderGitLab,
+		},
 		{
 			name:     "invalid",
 			input:    "jenkins",
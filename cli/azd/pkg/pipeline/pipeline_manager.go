@@ -4,6 +4,7 @@
 package pipeline
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -16,7 +17,6 @@ import (
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
-	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	msi "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/msi/armmsi"
 	"github.com/azure/azure-dev/cli/azd/pkg/armmsi"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
@@ -70,6 +70,8 @@ type PipelineManagerArgs struct {
 	PipelineProvider             string
 	PipelineAuthTypeName         string
 	ServiceManagementReference   string
+	// PipelineStages are the names of the stages of a multi-stage pipeline, in promotion order.
+	PipelineStages []string
 }
 
 // CredentialOptions represents the options for configuring credentials for a pipeline.
@@ -107,6 +109,8 @@ type PipelineManager struct {
 	msiService        armmsi.ArmMsiService
 	prompter          prompt.Prompter
 	dotnetCli         *dotnet.Cli
+	// stages of a multi-stage pipeline. Empty for a single-stage pipeline.
+	stages []*pipelineStage
 }
 
 func NewPipelineManager(
@@ -254,6 +258,27 @@ func (pm *PipelineManager) Configure(
 		return result, err
 	}
 
+	// multi-stage pipelines are configured on top of the single-stage setup by the providers supporting them
+	stageDefinitions, err := resolveStageDefinitions(pm.args.PipelineStages, pm.prjConfig.Pipeline.Stages)
+	if err != nil {
+		return result, err
+	}
+	var stagedProvider stagedCiProvider
+	if len(stageDefinitions) > 0 {
+		provider, supported := pm.ciProvider.(stagedCiProvider)
+		if !supported {
+			return result, fmt.Errorf(
+				"multi-stage pipelines are not supported by %s. Use %s or remove the pipeline stages",
+				pm.ciProvider.Name(), gitHubDisplayName)
+		}
+		stagedProvider = provider
+
+		pm.stages, err = pm.loadStages(ctx, stageDefinitions)
+		if err != nil {
+			return result, err
+		}
+	}
+
 	// pipeline definition files
 	err = pm.ensurePipelineDefinition(ctx)
 	if err != nil {
@@ -577,6 +602,22 @@ func (pm *PipelineManager) Configure(
 			return result, fmt.Errorf("failed to get credential options: %w", err)
 		}
 
+		if stagedProvider != nil {
+			err := pm.ensureStageRoleAssignments(ctx, subscriptionId, authConfig)
+			if err != nil {
+				return result, err
+			}
+
+			if credentialOptions.EnableFederatedCredentials {
+				stageCredentials, err := stagedProvider.stageCredentialOptions(ctx, gitRepoInfo, pm.stages)
+				if err != nil {
+					return result, fmt.Errorf("failed to get credential options for pipeline stages: %w", err)
+				}
+				credentialOptions.FederatedCredentialOptions = append(
+					credentialOptions.FederatedCredentialOptions, stageCredentials...)
+			}
+		}
+
 		// Enable client credentials if requested
 		if credentialOptions.EnableClientCredentials {
 			spinnerMessage := "Configuring client credentials for service principal"
@@ -593,51 +634,25 @@ func (pm *PipelineManager) Configure(
 
 		// Enable federated credentials if requested
 		if credentialOptions.EnableFederatedCredentials {
-			type fedCredentialData struct{ Name, Subject, Issuer string }
-			var createdCredentials []fedCredentialData
+			target := FederationTarget{ClientId: authConfig.ClientId}
 			if usingMsi {
-				// convert fedCredentials from msGraph to armmsi.FederatedIdentityCredential
-				armFedCreds := make([]msi.FederatedIdentityCredential, len(credentialOptions.FederatedCredentialOptions))
-				for i, fedCred := range credentialOptions.FederatedCredentialOptions {
-					armFedCreds[i] = msi.FederatedIdentityCredential{
-						Name: new(fedCred.Name),
-						Properties: &msi.FederatedIdentityCredentialProperties{
-							Subject:   new(fedCred.Subject),
-							Issuer:    new(fedCred.Issuer),
-							Audiences: to.SliceOfPtrs(fedCred.Audiences...),
-						},
-					}
-				}
+				target = FederationTarget{IdentityId: *authConfig.msi.ID}
+			}
 
-				creds, err := pm.msiService.ApplyFederatedCredentials(ctx, subscriptionId, *authConfig.msi.ID, armFedCreds)
-				if err != nil {
-					return result, fmt.Errorf("failed to create federated credentials: %w", err)
+			requestedCredentials := make([]FederatedCredential, len(credentialOptions.FederatedCredentialOptions))
+			for i, fedCred := range credentialOptions.FederatedCredentialOptions {
+				requestedCredentials[i] = FederatedCredential{
+					Name:      fedCred.Name,
+					Issuer:    fedCred.Issuer,
+					Subject:   fedCred.Subject,
+					Audiences: fedCred.Audiences,
 				}
+			}
 
-				// Convert the armmsi.FederatedIdentityCredential to fedCredentialData for display
-				for _, c := range creds {
-					createdCredentials = append(createdCredentials, fedCredentialData{
-						Name:    *c.Name,
-						Subject: *c.Properties.Subject,
-						Issuer:  *c.Properties.Issuer,
-					})
-				}
-			} else {
-				creds, err := pm.entraIdService.ApplyFederatedCredentials(
-					ctx, subscriptionId,
-					authConfig.ClientId,
-					credentialOptions.FederatedCredentialOptions,
-				)
-				if err != nil {
-					return result, fmt.Errorf("failed to create federated credentials: %w", err)
-				}
-				for _, c := range creds {
-					createdCredentials = append(createdCredentials, fedCredentialData{
-						Name:    c.Name,
-						Subject: c.Subject,
-						Issuer:  c.Issuer,
-					})
-				}
+			createdCredentials, err := NewFederationManager(pm.entraIdService, pm.msiService).
+				Create(ctx, subscriptionId, target, requestedCredentials)
+			if err != nil {
+				return result, fmt.Errorf("failed to create federated credentials: %w", err)
 			}
 
 			for _, credential := range createdCredentials {
@@ -680,83 +695,97 @@ func (pm *PipelineManager) Configure(
 	if err != nil {
 		return result, fmt.Errorf("failed to merge variables and secrets: %w", err)
 	}
+	if err := pm.mergeStageVariablesAndSecrets(); err != nil {
+		return result, fmt.Errorf("failed to merge variables and secrets: %w", err)
+	}
+
+	allSecrets := []map[string]string{pm.configOptions.secrets}
+	allVariables := []map[string]string{pm.configOptions.variables}
+	for _, stage := range pm.stages {
+		allSecrets = append(allSecrets, stage.secrets)
+		allVariables = append(allVariables, stage.variables)
+	}
 
 	// resolve akvs secrets
 	// For each akvs in the secrets array:
 	// azd gets the value from Azure Key Vault and use it as a secret in the pipeline
-	for key, value := range pm.configOptions.secrets {
-		if !strings.HasPrefix(value, "akvs://") {
-			continue
-		}
-		kvSecret, err := pm.keyVaultService.SecretFromAkvs(ctx, value)
-		if err != nil {
-			return result, fmt.Errorf("failed to resolve akvs '%s': %w", key, err)
+	for _, secrets := range allSecrets {
+		for key, value := range secrets {
+			if !strings.HasPrefix(value, "akvs://") {
+				continue
+			}
+			kvSecret, err := pm.keyVaultService.SecretFromAkvs(ctx, value)
+			if err != nil {
+				return result, fmt.Errorf("failed to resolve akvs '%s': %w", key, err)
+			}
+			secrets[key] = kvSecret
 		}
-		pm.configOptions.secrets[key] = kvSecret
 	}
 	// For each akvs in the variables array:
 	// azd must grant read access role to the pipelines's identity to read the akvs
 	displayMsg := "Assigning read access role for Key Vault"
 	pm.console.ShowSpinner(ctx, displayMsg, input.Step)
 	kvAccounts := make(map[string]struct{})
-	for key, value := range pm.configOptions.variables {
-		if !strings.HasPrefix(value, "akvs://") {
-			continue
-		}
+	for _, variables := range allVariables {
+		for key, value := range variables {
+			if !strings.HasPrefix(value, "akvs://") {
+				continue
+			}
 
-		if skipAuth {
-			continue
-		}
+			if skipAuth {
+				continue
+			}
 
-		akvs, err := keyvault.ParseAzureKeyVaultSecret(value)
-		if err != nil {
-			return result, fmt.Errorf("failed to parse akvs '%s': %w", key, err)
-		}
-		kvId := akvs.SubscriptionId + akvs.VaultName
-		if _, ok := kvAccounts[kvId]; ok {
-			// skip if already assigned role for this key vault
-			continue
-		}
+			akvs, err := keyvault.ParseAzureKeyVaultSecret(value)
+			if err != nil {
+				return result, fmt.Errorf("failed to parse akvs '%s': %w", key, err)
+			}
+			kvId := akvs.SubscriptionId + akvs.VaultName
+			if _, ok := kvAccounts[kvId]; ok {
+				// skip if already assigned role for this key vault
+				continue
+			}
 
-		// can't use keyvaultService.Get() because it requires the resource group name and we don't save it for akvs
-		allKvFromSub, err := pm.keyVaultService.ListSubscriptionVaults(ctx, akvs.SubscriptionId)
-		if err != nil {
-			return result, fmt.Errorf(
-				"assigning read access role for Key Vault for auth: %w", err)
-		}
-		var vaultResourceId string
-		foundKeyVault := slices.ContainsFunc(allKvFromSub, func(kv keyvault.Vault) bool {
-			if kv.Name == akvs.VaultName {
-				vaultResourceId = kv.Id
-				return true
+			// can't use keyvaultService.Get() because it requires the resource group name and we don't save it for akvs
+			allKvFromSub, err := pm.keyVaultService.ListSubscriptionVaults(ctx, akvs.SubscriptionId)
+			if err != nil {
+				return result, fmt.Errorf(
+					"assigning read access role for Key Vault for auth: %w", err)
+			}
+			var vaultResourceId string
+			foundKeyVault := slices.ContainsFunc(allKvFromSub, func(kv keyvault.Vault) bool {
+				if kv.Name == akvs.VaultName {
+					vaultResourceId = kv.Id
+					return true
+				}
+				return false
+			})
+			if !foundKeyVault {
+				return result, fmt.Errorf(
+					"assigning read access role for Key Vault to service principal: "+
+						"key vault '%s' not found in subscription '%s'", akvs.VaultName, akvs.SubscriptionId)
 			}
-			return false
-		})
-		if !foundKeyVault {
-			return result, fmt.Errorf(
-				"assigning read access role for Key Vault to service principal: "+
-					"key vault '%s' not found in subscription '%s'", akvs.VaultName, akvs.SubscriptionId)
-		}
 
-		var spId string
-		if usingMsi {
-			spId = *authConfig.msi.Properties.PrincipalID
-		} else if usingAppRegistration {
-			spId = *authConfig.sp.Id
-		} else {
-			continue
-		}
-		// CreateRbac uses the azure-sdk RoleAssignmentsClient.Create() which creates or updates the role assignment
-		// We don't need to check if the role assignment already exists, the method will handle it.
-		err = pm.entraIdService.CreateRbac(
-			ctx, akvs.SubscriptionId, vaultResourceId, keyvault.RoleIdKeyVaultSecretsUser, spId)
-		if err != nil {
-			return result, fmt.Errorf(
-				"assigning read access role for Key Vault to service principal: %w", err)
-		}
+			var spId string
+			if usingMsi {
+				spId = *authConfig.msi.Properties.PrincipalID
+			} else if usingAppRegistration {
+				spId = *authConfig.sp.Id
+			} else {
+				continue
+			}
+			// CreateRbac uses the azure-sdk RoleAssignmentsClient.Create() which creates or updates the role assignment
+			// We don't need to check if the role assignment already exists, the method will handle it.
+			err = pm.entraIdService.CreateRbac(
+				ctx, akvs.SubscriptionId, vaultResourceId, keyvault.RoleIdKeyVaultSecretsUser, spId)
+			if err != nil {
+				return result, fmt.Errorf(
+					"assigning read access role for Key Vault to service principal: %w", err)
+			}
 
-		// save the kvId to avoid assigning the role multiple times for the same key vault
-		kvAccounts[kvId] = struct{}{}
+			// save the kvId to avoid assigning the role multiple times for the same key vault
+			kvAccounts[kvId] = struct{}{}
+		}
 	}
 	pm.console.StopSpinner(ctx, displayMsg, input.GetStepResultFormat(err))
 
@@ -766,6 +795,12 @@ func (pm *PipelineManager) Configure(
 		return result, err
 	}
 
+	if stagedProvider != nil {
+		if err := stagedProvider.configureStages(ctx, gitRepoInfo, infra.Options, pm.stages); err != nil {
+			return result, fmt.Errorf("configuring pipeline stages: %w", err)
+		}
+	}
+
 	// The CI pipeline should be set-up and ready at this point.
 	// azd offers to push changes to the scm to start a new pipeline run
 	doPush, err := pm.console.Confirm(ctx, input.ConsoleOptions{
@@ -1049,9 +1084,9 @@ func (pm *PipelineManager) resolveProviderAndDetermine(
 // Logic:
 //   - If the user specifies a provider through the arguments, that provider is used.
 //   - If no provider is specified:
-//   - If both GitHub and Azure DevOps configurations are detected, prompt the user to choose which one to use.
-//   - If only GitHub configuration is found, use GitHub Actions.
-//   - If only Azure DevOps configuration is found, use Azure DevOps.
+//   - If the configurations of several providers (GitHub, Azure DevOps, GitLab) are detected, prompt the user to
+//     choose which one to use.
+//   - If the configuration of a single provider is found, use it.
 //   - If no configuration is found, prompt the user to select which one to set up.
 //   - Default to GitHub Actions if no provider is specified or selected.
 //   - Prompt the user to confirm adding the azure-dev file if it’s missing, and inform them where the file is created.
@@ -1095,11 +1130,16 @@ func (pm *PipelineManager) initialize(ctx context.Context, override string) erro
 	}
 
 	var scmProviderName, ciProviderName, displayName string
-	if pipelineProvider == ciProviderAzureDevOps {
+	switch pipelineProvider {
+	case ciProviderAzureDevOps:
 		scmProviderName = string(ciProviderAzureDevOps)
 		ciProviderName = scmProviderName
 		displayName = azdoDisplayName
-	} else {
+	case ciProviderGitLab:
+		scmProviderName = string(ciProviderGitLab)
+		ciProviderName = scmProviderName
+		displayName = gitLabDisplayName
+	default:
 		scmProviderName = string(ciProviderGitHubActions)
 		ciProviderName = scmProviderName
 		displayName = gitHubDisplayName
@@ -1146,6 +1186,25 @@ func (pm *PipelineManager) checkAndPromptForProviderFiles(ctx context.Context, p
 			return err
 		}
 		log.Println("Prompt for CI files completed successfully.")
+	} else if err := pm.updatePipelineDefinitions(ctx, props); err != nil {
+		return err
+	}
+
+	if props.CiProvider == ciProviderGitLab {
+		// GitLab reads the pipeline from a single file at the root of the repository.
+		if hasPipelineFile(props.CiProvider, props.RepoRoot) {
+			return nil
+		}
+
+		message := fmt.Sprintf(
+			"%s provider selected, but %s was not found at the root of the repository.\n"+
+				"Please add a pipeline definition.",
+			gitLabDisplayName,
+			gitLabPipelineFile)
+		log.Println("Info:", message)
+		pm.console.Message(ctx, message)
+		pm.console.Message(ctx, "")
+		return nil
 	}
 
 	var dirPaths []string
@@ -1215,7 +1274,7 @@ func (pm *PipelineManager) promptForCiFiles(ctx context.Context, props projectPr
 		ctx,
 		fmt.Sprintf(
 			"The default %s file, which contains a basic workflow to help you get started, is missing from your project.",
-			output.WithHighLightFormat("azure-dev.yml"),
+			output.WithHighLightFormat(pipelineProviderFiles[props.CiProvider].DefaultFile),
 		),
 	)
 	pm.console.Message(ctx, "")
@@ -1275,6 +1334,63 @@ func (pm *PipelineManager) promptForCiFiles(ctx context.Context, props projectPr
 	return nil
 }
 
+// updatePipelineDefinitions offers to update the existing pipeline definitions of the provider that don't pass all of
+// the variables and secrets of the project to azd, showing the changes before writing them.
+func (pm *PipelineManager) updatePipelineDefinitions(ctx context.Context, props projectProperties) error {
+	for _, file := range pipelineProviderFiles[props.CiProvider].Files {
+		path := filepath.Join(props.RepoRoot, file)
+		if !osutil.FileExists(path) {
+			continue
+		}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+
+		updated, err := updatePipelineDefinition(props, contents)
+		if err != nil {
+			// definitions azd can't parse are used as-is, the provider reports their errors when running them
+			log.Printf("skipping update of %s: %v", path, err)
+			continue
+		}
+		if bytes.Equal(contents, updated) {
+			log.Printf("%s is up to date", path)
+			continue
+		}
+
+		pm.console.Message(ctx, "")
+		pm.console.Message(ctx, fmt.Sprintf(
+			"The %s file doesn't pass all of the variables and secrets of the project to azd. Changes to apply:",
+			output.WithHighLightFormat(file)))
+		pm.console.Message(ctx, "")
+		pm.console.Message(ctx, definitionDiff(string(contents), string(updated)))
+
+		confirm, err := pm.console.Confirm(ctx, input.ConsoleOptions{
+			Message:      fmt.Sprintf("Would you like to update %s?", file),
+			DefaultValue: true,
+		})
+		if err != nil {
+			return fmt.Errorf("prompting to update file: %w", err)
+		}
+		pm.console.Message(ctx, "")
+		if !confirm {
+			log.Printf("User declined the update of %s", path)
+			continue
+		}
+
+		log.Printf("Updating file %s", path)
+		if err := os.WriteFile(path, updated, osutil.PermissionFile); err != nil {
+			return fmt.Errorf("updating file %s: %w", path, err)
+		}
+		pm.console.Message(ctx,
+			fmt.Sprintf("The %s file has been updated.", output.WithHighLightFormat(file)))
+		pm.console.Message(ctx, "")
+	}
+
+	return nil
+}
+
 func generatePipelineDefinition(path string, props projectProperties) error {
 	embedFilePath := fmt.Sprintf("pipeline/.%s/azure-dev.ymlt", props.CiProvider)
 	tmpl, err := template.
@@ -1285,6 +1401,12 @@ func generatePipelineDefinition(path string, props projectProperties) error {
 		return fmt.Errorf("parsing embedded file %s: %w", embedFilePath, err)
 	}
 	builder := strings.Builder{}
+	variables, secrets := pipelineVariablesAndSecrets(props)
+	type stageContext struct {
+		Job         string
+		Environment string
+		Needs       string
+	}
 	tmplContext := struct {
 		BranchName             string
 		FedCredLogIn           bool
@@ -1293,48 +1415,77 @@ func generatePipelineDefinition(path string, props projectProperties) error {
 		Secrets                []string
 		AlphaFeatures          []string
 		IsTerraform            bool
+		DeployTags             []string
+		Stages                 []stageContext
 	}{
 		BranchName:             props.BranchName,
 		FedCredLogIn:           props.AuthType == AuthTypeFederated,
 		InstallDotNetForAspire: props.HasAppHost,
-		Variables:              props.Variables,
-		Secrets:                props.Secrets,
+		Variables:              variables,
+		Secrets:                secrets,
 		AlphaFeatures:          props.RequiredAlphaFeatures,
 		IsTerraform:            props.InfraProvider == infraProviderTerraform,
+		DeployTags:             props.DeployTags,
+	}
+
+	// Each stage is a job that runs after the previous one succeeds, so changes are promoted through the stages in order
+	for i, stage := range props.Stages {
+		stageCtx := stageContext{Job: stage, Environment: stage}
+		if i > 0 {
+			stageCtx.Needs = props.Stages[i-1]
+		}
+		tmplContext.Stages = append(tmplContext.Stages, stageCtx)
+	}
+
+	err = tmpl.Execute(&builder, tmplContext)
+	if err != nil {
+		return fmt.Errorf("executing template: %w", err)
+	}
+
+	contents := []byte(builder.String())
+	log.Printf("Creating file %s", path)
+	if err := os.WriteFile(path, contents, osutil.PermissionFile); err != nil {
+		return fmt.Errorf("creating file %s: %w", path, err)
 	}
+	return nil
+}
+
+// pipelineVariablesAndSecrets returns the names of the variables and secrets that the pipeline definition passes to azd.
+func pipelineVariablesAndSecrets(props projectProperties) (variables []string, secrets []string) {
+	variables = slices.Clone(props.Variables)
+	secrets = slices.Clone(props.Secrets)
 
 	// Apply provider parameters
 	for _, param := range props.providerParameters {
 		for _, envVarName := range param.EnvVarMapping {
 			if param.Secret {
-				tmplContext.Secrets = append(tmplContext.Secrets, envVarName)
+				secrets = append(secrets, envVarName)
 			} else {
-				tmplContext.Variables = append(tmplContext.Variables, envVarName)
+				variables = append(variables, envVarName)
 			}
 		}
 	}
 
 	if props.InfraProvider == infraProviderTerraform {
 		// terraform provider does not resolve this variables automatically, AZD needs to define them
-		tmplContext.Variables = append(tmplContext.Variables, "AZURE_LOCATION")
-		tmplContext.Variables = append(tmplContext.Variables, "AZURE_ENV_NAME")
+		variables = append(variables, "AZURE_LOCATION")
+		variables = append(variables, "AZURE_ENV_NAME")
 
 		if props.AuthType == AuthTypeClientCredentials {
-			tmplContext.Secrets = append(tmplContext.Secrets, "AZURE_CLIENT_SECRET")
+			secrets = append(secrets, "AZURE_CLIENT_SECRET")
 		}
 	}
 
-	err = tmpl.Execute(&builder, tmplContext)
-	if err != nil {
-		return fmt.Errorf("executing template: %w", err)
+	if len(props.Stages) > 0 {
+		// each stage deploys the azd environment set on its CI environment
+		for _, name := range []string{environment.EnvNameEnvVarName, environment.LocationEnvVarName} {
+			if !slices.Contains(variables, name) {
+				variables = append(variables, name)
+			}
+		}
 	}
 
-	contents := []byte(builder.String())
-	log.Printf("Creating file %s", path)
-	if err := os.WriteFile(path, contents, osutil.PermissionFile); err != nil {
-		return fmt.Errorf("creating file %s: %w", path, err)
-	}
-	return nil
+	return variables, secrets
 }
 
 // hasPipelineFile checks if any pipeline files exist for the given provider in the specified repository root.
@@ -1352,33 +1503,23 @@ func (pm *PipelineManager) determineProvider(ctx context.Context, repoRoot strin
 	log.Printf("Checking for CI/CD YAML files in the repository root: %s", repoRoot)
 
 	// Check for existence of official YAML files in the repo root
-	hasGitHubYml := hasPipelineFile(ciProviderGitHubActions, repoRoot)
-	hasAzDevOpsYml := hasPipelineFile(ciProviderAzureDevOps, repoRoot)
-
-	log.Printf("GitHub Actions YAML exists: %v", hasGitHubYml)
-	log.Printf("Azure DevOps YAML exists: %v", hasAzDevOpsYml)
-
-	switch {
-	case (!hasGitHubYml && !hasAzDevOpsYml) || (hasGitHubYml && hasAzDevOpsYml):
-		// No official YAML files found for either provider or both are found
-		log.Printf("Neither or both YAML files found. Prompting user for provider selection.")
-		return pm.promptForProvider(ctx)
-
-	case hasGitHubYml && !hasAzDevOpsYml:
-		// GitHub Actions YAML found, Azure DevOps YAML not found
-		log.Printf("Only GitHub Actions YAML found. Selecting GitHub Actions as the provider.")
-		return ciProviderGitHubActions, nil
-
-	case hasAzDevOpsYml && !hasGitHubYml:
-		// Azure DevOps YAML found, GitHub Actions YAML not found
-		log.Printf("Only Azure DevOps YAML found. Selecting Azure DevOps as the provider.")
-		return ciProviderAzureDevOps, nil
+	var detected []ciProviderType
+	for _, provider := range []ciProviderType{ciProviderGitHubActions, ciProviderAzureDevOps, ciProviderGitLab} {
+		hasYml := hasPipelineFile(provider, repoRoot)
+		log.Printf("%s YAML exists: %v", pipelineProviderFiles[provider].DisplayName, hasYml)
+		if hasYml {
+			detected = append(detected, provider)
+		}
+	}
 
-	default:
-		// Default to GitHub Actions if no provider is specified
-		log.Printf("Defaulting to GitHub Actions as the provider.")
-		return ciProviderGitHubActions, nil
+	if len(detected) == 1 {
+		log.Printf("Only %s YAML found. Selecting it as the provider.", pipelineProviderFiles[detected[0]].DisplayName)
+		return detected[0], nil
 	}
+
+	// No official YAML files found for any provider, or found for several of them
+	log.Printf("No or several YAML files found. Prompting user for provider selection.")
+	return pm.promptForProvider(ctx)
 }
 
 // promptForProvider prompts the user to select a CI/CD provider.
@@ -1387,7 +1528,7 @@ func (pm *PipelineManager) promptForProvider(ctx context.Context) (ciProviderTyp
 	pm.console.Message(ctx, "")
 	choice, err := pm.console.Select(ctx, input.ConsoleOptions{
 		Message: "Select a provider:",
-		Options: []string{gitHubDisplayName, azdoDisplayName},
+		Options: []string{gitHubDisplayName, azdoDisplayName, gitLabDisplayName},
 	})
 	if err != nil {
 		return "", fmt.Errorf("prompting for CI/CD provider: %w", err)
@@ -1395,10 +1536,13 @@ func (pm *PipelineManager) promptForProvider(ctx context.Context) (ciProviderTyp
 
 	log.Printf("User selected choice: %d", choice)
 
-	if choice == 0 {
+	switch choice {
+	case 0:
 		return ciProviderGitHubActions, nil
-	} else if choice == 1 {
+	case 1:
 		return ciProviderAzureDevOps, nil
+	case 2:
+		return ciProviderGitLab, nil
 	}
 
 	return "", nil // This case should never occur with the current options.
@@ -1486,6 +1630,8 @@ func (pm *PipelineManager) ensurePipelineDefinition(ctx context.Context) error {
 			Variables:             pm.prjConfig.Pipeline.Variables,
 			Secrets:               pm.prjConfig.Pipeline.Secrets,
 			RequiredAlphaFeatures: requiredAlphaFeatures,
+			DeployTags:            pm.prjConfig.Pipeline.Tags,
+			Stages:                stageNames(pm.stages),
 			providerParameters:    pm.configOptions.providerParameters,
 		})
 	if err != nil {
@@ -0,0 +1,139 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+)
+
+// PipelineRunStatus is the status of a pipeline run, common to all the CI providers.
+type PipelineRunStatus string
+
+const (
+	PipelineRunQueued     PipelineRunStatus = "queued"
+	PipelineRunInProgress PipelineRunStatus = "inProgress"
+	PipelineRunCompleted  PipelineRunStatus = "completed"
+)
+
+// PipelineRun is a run of the CI pipeline of the project.
+type PipelineRun struct {
+	// Id of the run in the CI provider. Empty when the provider hasn't reported the run yet.
+	Id string `json:"id,omitempty"`
+	// Name is the title or the number of the run.
+	Name   string            `json:"name,omitempty"`
+	Branch string            `json:"branch"`
+	Status PipelineRunStatus `json:"status"`
+	// Result of a completed run: succeeded, failed, canceled, or another value specific to the CI provider.
+	Result string `json:"result,omitempty"`
+	// Url of the run logs, or of the pipeline when the provider hasn't reported the run yet.
+	Url      string     `json:"url"`
+	QueuedAt *time.Time `json:"queuedAt,omitempty"`
+}
+
+// runnableCiProvider is implemented by the CI providers that can trigger the pipeline and report its runs.
+type runnableCiProvider interface {
+	// runPipeline triggers the pipeline defined by definitionFile on the branch, and returns the new run.
+	runPipeline(
+		ctx context.Context,
+		repoDetails *gitRepositoryDetails,
+		definitionFile string,
+		branch string,
+	) (*PipelineRun, error)
+	// latestPipelineRun returns the latest run of the pipeline defined by definitionFile on the branch, or nil when the
+	// pipeline has no runs on the branch.
+	latestPipelineRun(
+		ctx context.Context,
+		repoDetails *gitRepositoryDetails,
+		definitionFile string,
+		branch string,
+	) (*PipelineRun, error)
+}
+
+// RunPipeline triggers the pipeline of the project on the branch, the current branch when empty, and returns the new
+// run.
+func (pm *PipelineManager) RunPipeline(ctx context.Context, branch string) (*PipelineRun, error) {
+	provider, repoDetails, definitionFile, err := pm.runnableCiProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if branch == "" {
+		branch = repoDetails.branch
+	}
+
+	return provider.runPipeline(ctx, repoDetails, definitionFile, branch)
+}
+
+// LatestPipelineRun returns the latest run of the pipeline of the project on the branch, the current branch when
+// empty, or nil when the pipeline has no runs on the branch.
+func (pm *PipelineManager) LatestPipelineRun(ctx context.Context, branch string) (*PipelineRun, error) {
+	provider, repoDetails, definitionFile, err := pm.runnableCiProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if branch == "" {
+		branch = repoDetails.branch
+	}
+
+	return provider.latestPipelineRun(ctx, repoDetails, definitionFile, branch)
+}
+
+// runnableCiProvider returns the CI provider of the project, with the details of the repository and the pipeline
+// definition file, relative to the root of the repository. The repository must have been set up by
+// `azd pipeline config`, or manually, since running the pipeline doesn't create any remote.
+func (pm *PipelineManager) runnableCiProvider(
+	ctx context.Context) (runnableCiProvider, *gitRepositoryDetails, string, error) {
+	provider, supported := pm.ciProvider.(runnableCiProvider)
+	if !supported {
+		return nil, nil, "", fmt.Errorf(
+			"running pipelines is not supported by %s. Use %s or %s",
+			pm.ciProvider.Name(), gitHubDisplayName, azdoDisplayName)
+	}
+
+	requiredTools, err := pm.requiredTools(ctx)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if err := tools.EnsureInstalled(ctx, requiredTools...); err != nil {
+		return nil, nil, "", err
+	}
+
+	repoDetails, err := pm.ensureRemote(ctx, pm.azdCtx.ProjectDirectory(), pm.args.PipelineRemoteName)
+	if errors.Is(err, git.ErrNotRepository) || errors.Is(err, git.ErrNoSuchRemote) {
+		return nil, nil, "", fmt.Errorf(
+			"the pipeline repository is not configured. Run 'azd pipeline config' to set it up: %w", err)
+	}
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	return provider, repoDetails, pm.pipelineDefinitionFile(ctx), nil
+}
+
+// pipelineDefinitionFile returns the pipeline definition of the provider found in the repository, or the default
+// definition file when the repository has none.
+func (pm *PipelineManager) pipelineDefinitionFile(ctx context.Context) string {
+	repoRoot, err := pm.gitCli.GetRepoRoot(ctx, pm.azdCtx.ProjectDirectory())
+	if err != nil {
+		log.Printf("using project root as repo root, since git repo wasn't available: %s", err)
+		repoRoot = pm.azdCtx.ProjectDirectory()
+	}
+
+	providerFiles := pipelineProviderFiles[pm.ciProviderType]
+	for _, file := range providerFiles.Files {
+		if osutil.FileExists(filepath.Join(repoRoot, file)) {
+			return file
+		}
+	}
+
+	return filepath.Join(providerFiles.PipelineDirectories[0], providerFiles.DefaultFile)
+}
@@ -0,0 +1,142 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/build"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_gitHub_provider_runPipeline(t *testing.T) {
+	repoDetails := &gitRepositoryDetails{
+		owner:    "Azure",
+		repoName: "azure-dev",
+		url:      "https://github.com/Azure/azure-dev",
+	}
+
+	mockRuns := func(mockContext *mocks.MockContext, runs string) (workflowArgs *[]string) {
+		workflowArgs = &[]string{}
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "workflow run")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			*workflowArgs = args.Args
+			return exec.NewRunResult(0, "", ""), nil
+		})
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "run list")
+		}).Respond(exec.NewRunResult(0, runs, ""))
+		return workflowArgs
+	}
+
+	t.Run("returns the new run", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		workflowArgs := mockRuns(mockContext, fmt.Sprintf(
+			`[{"databaseId":42,"displayTitle":"Deploy","headBranch":"dev","event":"workflow_dispatch",`+
+				`"status":"in_progress","url":"https://github.com/Azure/azure-dev/actions/runs/42","createdAt":"%s"}]`,
+			time.Now().UTC().Format(time.RFC3339)))
+		provider := createGitHubCiProvider(t, mockContext).(*GitHubCiProvider)
+
+		run, err := provider.runPipeline(t.Context(), repoDetails, ".github/workflows/azure-dev.yaml", "dev")
+		require.NoError(t, err)
+		require.Equal(t,
+			[]string{"-R", "Azure/azure-dev", "workflow", "run", "azure-dev.yaml", "--ref", "dev"}, *workflowArgs)
+		require.Equal(t, "42", run.Id)
+		require.Equal(t, "dev", run.Branch)
+		require.Equal(t, PipelineRunInProgress, run.Status)
+		require.Equal(t, "https://github.com/Azure/azure-dev/actions/runs/42", run.Url)
+	})
+
+	t.Run("links the workflow when the run isn't created yet", func(t *testing.T) {
+		interval := gitHubRunPollInterval
+		gitHubRunPollInterval = time.Millisecond
+		t.Cleanup(func() { gitHubRunPollInterval = interval })
+
+		mockContext := mocks.NewMockContext(t.Context())
+		// the latest run was triggered before
+		mockRuns(mockContext,
+			`[{"databaseId":41,"headBranch":"main","event":"workflow_dispatch","status":"completed",`+
+				`"conclusion":"success","createdAt":"2024-05-01T10:00:00Z"}]`)
+		provider := createGitHubCiProvider(t, mockContext).(*GitHubCiProvider)
+
+		run, err := provider.runPipeline(t.Context(), repoDetails, ".github/workflows/azure-dev.yml", "main")
+		require.NoError(t, err)
+		require.Equal(t, &PipelineRun{
+			Branch: "main",
+			Status: PipelineRunQueued,
+			Url:    "https://github.com/Azure/azure-dev/actions/workflows/azure-dev.yml",
+		}, run)
+	})
+}
+
+func Test_gitHub_provider_latestPipelineRun(t *testing.T) {
+	repoDetails := &gitRepositoryDetails{owner: "Azure", repoName: "azure-dev"}
+
+	t.Run("completed run", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "run list")
+		}).Respond(exec.NewRunResult(0,
+			`[{"databaseId":41,"displayTitle":"Update app","headBranch":"main","event":"push","status":"completed",`+
+				`"conclusion":"failure","url":"https://github.com/Azure/azure-dev/actions/runs/41",`+
+				`"createdAt":"2024-05-01T10:00:00Z"}]`, ""))
+		provider := createGitHubCiProvider(t, mockContext).(*GitHubCiProvider)
+
+		run, err := provider.latestPipelineRun(t.Context(), repoDetails, ".github/workflows/azure-dev.yml", "main")
+		require.NoError(t, err)
+		require.Equal(t, "Update app", run.Name)
+		require.Equal(t, PipelineRunCompleted, run.Status)
+		require.Equal(t, "failed", run.Result)
+		require.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), *run.QueuedAt)
+	})
+
+	t.Run("no runs", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "run list")
+		}).Respond(exec.NewRunResult(0, "[]", ""))
+		provider := createGitHubCiProvider(t, mockContext).(*GitHubCiProvider)
+
+		run, err := provider.latestPipelineRun(t.Context(), repoDetails, ".github/workflows/azure-dev.yml", "main")
+		require.NoError(t, err)
+		require.Nil(t, run)
+	})
+}
+
+func Test_azdoPipelineRun(t *testing.T) {
+	details := &AzdoRepositoryDetails{repoWebUrl: "https://dev.azure.com/org/project/_git/repo"}
+	queueTime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	run := azdoPipelineRun(details, &build.Build{
+		Id:           new(7),
+		BuildNumber:  new("20240501.1"),
+		SourceBranch: new("refs/heads/main"),
+		Status:       &build.BuildStatusValues.Completed,
+		Result:       &build.BuildResultValues.PartiallySucceeded,
+		QueueTime:    &azuredevops.Time{Time: queueTime},
+	})
+	require.Equal(t, &PipelineRun{
+		Id:       "7",
+		Name:     "20240501.1",
+		Branch:   "main",
+		Status:   PipelineRunCompleted,
+		Result:   "partiallySucceeded",
+		Url:      "https://dev.azure.com/org/project/_build/results?buildId=7",
+		QueuedAt: &queueTime,
+	}, run)
+
+	queued := azdoPipelineRun(details, &build.Build{
+		Id:     new(8),
+		Status: &build.BuildStatusValues.NotStarted,
+	})
+	require.Equal(t, PipelineRunQueued, queued.Status)
+	require.Empty(t, queued.Result)
+}
@@ -0,0 +1,193 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/graphsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+)
+
+// stageNameRegex matches the stage names that can be used both as a CI job id and as a CI environment name.
+var stageNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// pipelineStage is a stage of a multi-stage pipeline, resolved against the azd environment it deploys.
+type pipelineStage struct {
+	// name of the stage and of the CI environment that scopes its variables, secrets and approvals.
+	name string
+	// env is the azd environment deployed by the stage.
+	env *environment.Environment
+	// reviewers must approve the promotion before the stage runs.
+	reviewers []string
+	// variables and secrets are the values from env to be set for the stage, merged like the ones of a single-stage
+	// pipeline.
+	variables map[string]string
+	secrets   map[string]string
+}
+
+// stagedCiProvider is implemented by the CI providers that can configure multi-stage promotion pipelines.
+type stagedCiProvider interface {
+	// stageCredentialOptions returns the federated credentials used by the jobs of the stages to log in to Azure.
+	stageCredentialOptions(
+		ctx context.Context,
+		repoDetails *gitRepositoryDetails,
+		stages []*pipelineStage,
+	) ([]*graphsdk.FederatedIdentityCredential, error)
+	// configureStages creates the CI environment of each stage, with its approvals, variables and secrets.
+	configureStages(
+		ctx context.Context,
+		repoDetails *gitRepositoryDetails,
+		infraOptions provisioning.Options,
+		stages []*pipelineStage,
+	) error
+}
+
+// resolveStageDefinitions returns the stages of the pipeline, in promotion order. Stage names from --stages take
+// precedence over the stages from azure.yaml, which still provide the environment and reviewers of a stage with the
+// same name. A nil result means the pipeline has a single stage.
+func resolveStageDefinitions(
+	stageNames []string, projectStages []project.PipelineStage) ([]project.PipelineStage, error) {
+	stages := projectStages
+	if len(stageNames) > 0 {
+		stages = make([]project.PipelineStage, 0, len(stageNames))
+		for _, name := range stageNames {
+			stage := project.PipelineStage{Name: strings.TrimSpace(name)}
+			for _, projectStage := range projectStages {
+				if projectStage.Name == stage.Name {
+					stage = projectStage
+					break
+				}
+			}
+			stages = append(stages, stage)
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, stage := range stages {
+		if !stageNameRegex.MatchString(stage.Name) {
+			return nil, fmt.Errorf(
+				"invalid pipeline stage name '%s': stage names must start with a letter or '_' and contain only "+
+					"alphanumeric characters, '-' and '_'", stage.Name)
+		}
+		if seen[stage.Name] {
+			return nil, fmt.Errorf("pipeline stage '%s' is defined more than once", stage.Name)
+		}
+		seen[stage.Name] = true
+	}
+
+	return stages, nil
+}
+
+// loadStages resolves the azd environment of each stage. The current environment is reused when a stage deploys it.
+func (pm *PipelineManager) loadStages(
+	ctx context.Context, definitions []project.PipelineStage) ([]*pipelineStage, error) {
+	stages := make([]*pipelineStage, 0, len(definitions))
+	for _, definition := range definitions {
+		envName := definition.Environment
+		if envName == "" {
+			envName = definition.Name
+		}
+
+		env := pm.env
+		if envName != pm.env.Name() {
+			stageEnv, err := pm.envManager.Get(ctx, envName)
+			if errors.Is(err, environment.ErrNotFound) {
+				return nil, fmt.Errorf(
+					"azd environment '%s' for pipeline stage '%s' was not found. Create it with 'azd env new %s' and "+
+						"run 'azd provision' once to set its subscription and location",
+					envName, definition.Name, envName)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("loading environment '%s' for pipeline stage '%s': %w", envName, definition.Name, err)
+			}
+			env = stageEnv
+		}
+
+		if env.GetSubscriptionId() == "" {
+			return nil, fmt.Errorf(
+				"azd environment '%s' for pipeline stage '%s' has no subscription. Set %s in the environment",
+				envName, definition.Name, environment.SubscriptionIdEnvVarName)
+		}
+
+		stages = append(stages, &pipelineStage{
+			name:      definition.Name,
+			env:       env,
+			reviewers: definition.Reviewers,
+		})
+	}
+
+	return stages, nil
+}
+
+// mergeStageVariablesAndSecrets collects the variables and secrets of each stage from its azd environment.
+func (pm *PipelineManager) mergeStageVariablesAndSecrets() error {
+	for _, stage := range pm.stages {
+		defaultAzdVariables := map[string]string{}
+		if rgGroup, exists := stage.env.LookupEnv(environment.ResourceGroupEnvVarName); exists {
+			defaultAzdVariables[environment.ResourceGroupEnvVarName] = rgGroup
+		}
+
+		variables, secrets, err := mergeProjectVariablesAndSecrets(
+			pm.configOptions.projectVariables, pm.configOptions.projectSecrets,
+			defaultAzdVariables, map[string]string{}, pm.configOptions.providerParameters, stage.env.Dotenv())
+		if err != nil {
+			return fmt.Errorf("pipeline stage '%s': %w", stage.name, err)
+		}
+		stage.variables = variables
+		stage.secrets = secrets
+	}
+
+	return nil
+}
+
+// ensureStageRoleAssignments assigns the pipeline roles to the pipeline identity on the subscription of each stage,
+// since the same identity deploys all of the stages.
+func (pm *PipelineManager) ensureStageRoleAssignments(
+	ctx context.Context, subscriptionId string, authConfig *authConfiguration) error {
+	principal := authConfig.sp
+	if authConfig.msi != nil {
+		principal = &graphsdk.ServicePrincipal{
+			Id:          authConfig.msi.Properties.PrincipalID,
+			DisplayName: *authConfig.msi.Name,
+		}
+	}
+
+	assigned := map[string]bool{subscriptionId: true}
+	for _, stage := range pm.stages {
+		stageSubscriptionId := stage.env.GetSubscriptionId()
+		if assigned[stageSubscriptionId] {
+			continue
+		}
+		assigned[stageSubscriptionId] = true
+
+		displayMsg := fmt.Sprintf(
+			"Assigning roles on subscription %s for pipeline stage %s", stageSubscriptionId, stage.name)
+		pm.console.ShowSpinner(ctx, displayMsg, input.Step)
+		err := pm.entraIdService.EnsureRoleAssignments(
+			ctx, stageSubscriptionId, pm.args.PipelineRoleNames, principal, nil)
+		pm.console.StopSpinner(ctx, displayMsg, input.GetStepResultFormat(err))
+		if err != nil {
+			return fmt.Errorf("assigning roles for pipeline stage '%s': %w", stage.name, err)
+		}
+	}
+
+	return nil
+}
+
+// stageNames returns the names of the stages, in promotion order.
+func stageNames(stages []*pipelineStage) []string {
+	names := make([]string, len(stages))
+	for i, stage := range stages {
+		names[i] = stage.name
+	}
+	return names
+}
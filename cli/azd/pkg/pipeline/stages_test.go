@@ -0,0 +1,72 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_resolveStageDefinitions(t *testing.T) {
+	projectStages := []project.PipelineStage{
+		{Name: "dev", Environment: "app-dev"},
+		{Name: "prod", Environment: "app-prod", Reviewers: []string{"octocat"}},
+	}
+
+	tests := []struct {
+		name          string
+		stageNames    []string
+		projectStages []project.PipelineStage
+		expected      []project.PipelineStage
+		expectedErr   string
+	}{
+		{
+			name: "single stage",
+		},
+		{
+			name:          "from azure.yaml",
+			projectStages: projectStages,
+			expected:      projectStages,
+		},
+		{
+			name:       "from flag",
+			stageNames: []string{"dev", " staging", "prod"},
+			expected:   []project.PipelineStage{{Name: "dev"}, {Name: "staging"}, {Name: "prod"}},
+		},
+		{
+			name:          "flag selects azure.yaml stages",
+			stageNames:    []string{"staging", "prod"},
+			projectStages: projectStages,
+			expected: []project.PipelineStage{
+				{Name: "staging"},
+				{Name: "prod", Environment: "app-prod", Reviewers: []string{"octocat"}},
+			},
+		},
+		{
+			name:        "invalid name",
+			stageNames:  []string{"dev", "my stage"},
+			expectedErr: "invalid pipeline stage name 'my stage'",
+		},
+		{
+			name:        "duplicate name",
+			stageNames:  []string{"dev", "prod", "dev"},
+			expectedErr: "pipeline stage 'dev' is defined more than once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stages, err := resolveStageDefinitions(tt.stageNames, tt.projectStages)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, stages)
+		})
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package progress defines the structured progress reporting used by long running commands (restore, build, package,
+// publish, provision and deploy). Operations report [Event] values through a [Reporter], and each frontend (the
+// interactive console, the JSON event stream and the extension / IDE APIs) renders the same events in its own way.
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+)
+
+// Phase is the command phase an event belongs to.
+type Phase string
+
+const (
+	PhaseRestore   Phase = "restore"
+	PhaseBuild     Phase = "build"
+	PhasePackage   Phase = "package"
+	PhasePublish   Phase = "publish"
+	PhaseProvision Phase = "provision"
+	PhaseDeploy    Phase = "deploy"
+)
+
+// Kind is the kind of a progress event.
+type Kind string
+
+const (
+	// KindStepStarted is reported when a step (or sub-step) starts.
+	KindStepStarted Kind = "stepStarted"
+	// KindStepUpdated is reported with intermediate progress of a running step.
+	KindStepUpdated Kind = "stepUpdated"
+	// KindWarning is reported when a step hits a non fatal problem.
+	KindWarning Kind = "warning"
+	// KindStepCompleted is reported when a step finishes, with its final status.
+	KindStepCompleted Kind = "stepCompleted"
+)
+
+// Status is the status of a step.
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusSkipped   Status = "skipped"
+)
+
+// Event is a single progress update.
+type Event struct {
+	Kind  Kind  `json:"kind"`
+	Phase Phase `json:"phase"`
+	// Step identifies the step the event belongs to. It is stable for the lifetime of the step, e.g. "package/api".
+	Step string `json:"step"`
+	// Parent is the identifier of the parent step of a sub-step.
+	Parent string `json:"parent,omitempty"`
+	// Title is the human readable name of the step, e.g. "Packaging service api".
+	Title   string `json:"title"`
+	Message string `json:"message,omitempty"`
+	// Percent is the completion percentage of the step (0-100), when known.
+	Percent   *int      `json:"percent,omitempty"`
+	Status    Status    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ToString renders the event as a single line of text, for frontends that only support plain messages.
+func (e Event) ToString(currentIndentation string) string {
+	var text string
+	switch e.Kind {
+	case KindStepStarted:
+		text = e.Title
+	case KindStepUpdated:
+		text = fmt.Sprintf("%s (%s)", e.Title, e.detail())
+	case KindWarning:
+		text = fmt.Sprintf("%s: WARNING: %s", e.Title, e.Message)
+	case KindStepCompleted:
+		text = fmt.Sprintf("%s: %s", e.Title, e.Status)
+		if e.Message != "" {
+			text = fmt.Sprintf("%s (%s)", text, e.Message)
+		}
+	}
+
+	return currentIndentation + text
+}
+
+// MarshalJSON writes the event in the envelope used for the JSON event stream.
+func (e Event) MarshalJSON() ([]byte, error) {
+	type event Event
+	return json.Marshal(contracts.EventEnvelope{
+		Type:      contracts.ProgressEventDataType,
+		Timestamp: e.Timestamp,
+		Data:      event(e),
+	})
+}
+
+// detail is the message of an update event including its percentage.
+func (e Event) detail() string {
+	switch {
+	case e.Percent == nil:
+		return e.Message
+	case e.Message == "":
+		return fmt.Sprintf("%d%%", *e.Percent)
+	default:
+		return fmt.Sprintf("%s, %d%%", e.Message, *e.Percent)
+	}
+}
+
+// Reporter receives progress events. Implementations must be safe for concurrent use, since steps of a command can
+// run in parallel.
+type Reporter interface {
+	Report(ctx context.Context, event Event)
+}
+
+// ReporterFunc adapts a function to the [Reporter] interface.
+type ReporterFunc func(ctx context.Context, event Event)
+
+// Report calls f(ctx, event).
+func (f ReporterFunc) Report(ctx context.Context, event Event) {
+	f(ctx, event)
+}
+
+// Discard is a [Reporter] that ignores all events.
+var Discard Reporter = ReporterFunc(func(context.Context, Event) {})
+
+// multiReporter forwards events to several reporters.
+type multiReporter []Reporter
+
+func (m multiReporter) Report(ctx context.Context, event Event) {
+	for _, r := range m {
+		r.Report(ctx, event)
+	}
+}
+
+// Multi returns a [Reporter] that forwards each event to all the given reporters, in order. Nil reporters are
+// ignored.
+func Multi(reporters ...Reporter) Reporter {
+	var m multiReporter
+	for _, r := range reporters {
+		switch r := r.(type) {
+		case nil:
+		case multiReporter:
+			m = append(m, r...)
+		default:
+			m = append(m, r)
+		}
+	}
+
+	if len(m) == 1 {
+		return m[0]
+	}
+
+	return m
+}
+
+// Headless returns r without its interactive console renderer. Operations that own a custom console view of their
+// progress (e.g. the deploy progress table) report through the headless reporter, so the JSON event stream and the
+// extension / IDE APIs still receive their events without rendering them twice on the console.
+func Headless(r Reporter) Reporter {
+	switch r := r.(type) {
+	case nil, *consoleRenderer:
+		return Discard
+	case multiReporter:
+		var m multiReporter
+		for _, inner := range r {
+			if _, isConsole := inner.(*consoleRenderer); !isConsole {
+				m = append(m, inner)
+			}
+		}
+		return Multi(m...)
+	default:
+		return r
+	}
+}
+
+// Step is a running unit of work that reports its progress. Steps are created with [Start] or [Step.SubStep] and must
+// be finished with [Step.Done] or [Step.Skip]. The methods of a finished step do nothing.
+type Step struct {
+	ctx      context.Context
+	reporter Reporter
+	phase    Phase
+	id       string
+	parent   string
+	title    string
+
+	mu       sync.Mutex
+	finished bool
+}
+
+// Start reports the start of a step and returns it. id identifies the step within the phase; title is the human
+// readable description shown to users.
+func Start(ctx context.Context, reporter Reporter, phase Phase, id string, title string) *Step {
+	if reporter == nil {
+		reporter = Discard
+	}
+
+	s := &Step{
+		ctx:      ctx,
+		reporter: reporter,
+		phase:    phase,
+		id:       string(phase) + "/" + id,
+		title:    title,
+	}
+	s.report(KindStepStarted, StatusRunning, "", nil)
+
+	return s
+}
+
+// SubStep reports the start of a step nested in s.
+func (s *Step) SubStep(id string, title string) *Step {
+	sub := &Step{
+		ctx:      s.ctx,
+		reporter: s.reporter,
+		phase:    s.phase,
+		id:       s.id + "/" + id,
+		parent:   s.id,
+		title:    title,
+	}
+	sub.report(KindStepStarted, StatusRunning, "", nil)
+
+	return sub
+}
+
+// Update reports intermediate progress of the step, e.g. "Pushing image". Empty messages are ignored.
+func (s *Step) Update(message string) {
+	if message == "" {
+		return
+	}
+
+	s.report(KindStepUpdated, StatusRunning, message, nil)
+}
+
+// SetPercent reports the completion percentage of the step, with an optional message.
+func (s *Step) SetPercent(percent int, message string) {
+	percent = min(max(percent, 0), 100)
+	s.report(KindStepUpdated, StatusRunning, message, &percent)
+}
+
+// Warn reports a non fatal problem hit by the step.
+func (s *Step) Warn(message string) {
+	s.report(KindWarning, StatusRunning, message, nil)
+}
+
+// Done finishes the step, as failed when err is not nil.
+func (s *Step) Done(err error) {
+	if err != nil {
+		s.finish(StatusFailed, err.Error())
+		return
+	}
+
+	s.finish(StatusSucceeded, "")
+}
+
+// Skip finishes the step as skipped, with an optional reason.
+func (s *Step) Skip(reason string) {
+	s.finish(StatusSkipped, reason)
+}
+
+func (s *Step) finish(status Status, message string) {
+	s.mu.Lock()
+	if s.finished {
+		s.mu.Unlock()
+		return
+	}
+	s.finished = true
+	s.mu.Unlock()
+
+	s.emit(KindStepCompleted, status, message, nil)
+}
+
+func (s *Step) report(kind Kind, status Status, message string, percent *int) {
+	s.mu.Lock()
+	finished := s.finished
+	s.mu.Unlock()
+
+	if !finished {
+		s.emit(kind, status, message, percent)
+	}
+}
+
+func (s *Step) emit(kind Kind, status Status, message string, percent *int) {
+	s.reporter.Report(s.ctx, Event{
+		Kind:      kind,
+		Phase:     s.phase,
+		Step:      s.id,
+		Parent:    s.parent,
+		Title:     s.title,
+		Message:   strings.TrimSpace(message),
+		Percent:   percent,
+		Status:    status,
+		Timestamp: time.Now(),
+	})
+}
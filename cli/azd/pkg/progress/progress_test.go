@@ -0,0 +1,156 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is a Reporter that records the events it receives.
+type recorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (r *recorder) Report(_ context.Context, event Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func TestStepLifecycle(t *testing.T) {
+	r := &recorder{}
+
+	step := Start(t.Context(), r, PhasePackage, "api", "Packaging service api")
+	step.Update("")
+	step.Update("Building image")
+	step.SetPercent(150, "")
+	step.Warn("image is large")
+	sub := step.SubStep("push", "Pushing image")
+	sub.Done(nil)
+	step.Done(errors.New("boom"))
+
+	// A finished step doesn't report anymore.
+	step.Update("ignored")
+	step.Skip("ignored")
+
+	require.Len(t, r.events, 7)
+
+	kinds := make([]Kind, len(r.events))
+	for i, e := range r.events {
+		kinds[i] = e.Kind
+		require.Equal(t, PhasePackage, e.Phase)
+		require.False(t, e.Timestamp.IsZero())
+	}
+	require.Equal(t, []Kind{
+		KindStepStarted, KindStepUpdated, KindStepUpdated, KindWarning, KindStepStarted, KindStepCompleted,
+		KindStepCompleted,
+	}, kinds)
+
+	require.Equal(t, "package/api", r.events[0].Step)
+	require.Equal(t, StatusRunning, r.events[0].Status)
+	require.Equal(t, "Building image", r.events[1].Message)
+	require.Equal(t, 100, *r.events[2].Percent)
+
+	require.Equal(t, "package/api/push", r.events[4].Step)
+	require.Equal(t, "package/api", r.events[4].Parent)
+	require.Equal(t, StatusSucceeded, r.events[5].Status)
+
+	require.Equal(t, StatusFailed, r.events[6].Status)
+	require.Equal(t, "boom", r.events[6].Message)
+}
+
+func TestStartNilReporter(t *testing.T) {
+	step := Start(t.Context(), nil, PhaseDeploy, "api", "Deploying service api")
+	step.Update("running")
+	step.Done(nil)
+}
+
+func TestEventToString(t *testing.T) {
+	percent := 42
+	tests := []struct {
+		name  string
+		event Event
+		want  string
+	}{
+		{"Started", Event{Kind: KindStepStarted, Title: "Deploying service api"}, "Deploying service api"},
+		{
+			"Updated",
+			Event{Kind: KindStepUpdated, Title: "Deploying service api", Message: "Pushing image"},
+			"Deploying service api (Pushing image)",
+		},
+		{
+			"Percent",
+			Event{Kind: KindStepUpdated, Title: "Deploying service api", Message: "Uploading", Percent: &percent},
+			"Deploying service api (Uploading, 42%)",
+		},
+		{
+			"Warning",
+			Event{Kind: KindWarning, Title: "Deploying service api", Message: "slow"},
+			"Deploying service api: WARNING: slow",
+		},
+		{
+			"Completed",
+			Event{Kind: KindStepCompleted, Title: "Deploying service api", Status: StatusFailed, Message: "boom"},
+			"Deploying service api: failed (boom)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.event.ToString(""))
+		})
+	}
+}
+
+func TestEventMarshalJSON(t *testing.T) {
+	percent := 10
+	event := Event{
+		Kind:    KindStepUpdated,
+		Phase:   PhaseProvision,
+		Step:    "provision/infra",
+		Title:   "Provisioning layer infra",
+		Percent: &percent,
+		Status:  StatusRunning,
+	}
+
+	raw, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	var envelope map[string]any
+	require.NoError(t, json.Unmarshal(raw, &envelope))
+	require.Equal(t, "progress", envelope["type"])
+
+	data := envelope["data"].(map[string]any)
+	require.Equal(t, "stepUpdated", data["kind"])
+	require.Equal(t, "provision", data["phase"])
+	require.Equal(t, "provision/infra", data["step"])
+	require.Equal(t, float64(10), data["percent"])
+	require.NotContains(t, data, "parent")
+}
+
+func TestMultiAndHeadless(t *testing.T) {
+	first, second := &recorder{}, &recorder{}
+	console := NewConsoleRenderer(nil)
+
+	multi := Multi(first, nil, Multi(second, console))
+	require.Len(t, multi, 3)
+	require.Same(t, first, Multi(first))
+
+	headless := Headless(multi)
+	headless.Report(t.Context(), Event{Kind: KindWarning})
+	require.Len(t, first.events, 1)
+	require.Len(t, second.events, 1)
+
+	require.Same(t, first, Headless(first))
+	require.NotNil(t, Headless(console))
+	require.NotNil(t, Headless(nil))
+	Headless(console).Report(t.Context(), Event{Kind: KindWarning})
+}
@@ -0,0 +1,106 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package progress
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// NewReporter returns the [Reporter] for the output format of the current command: the JSON event stream when the
+// output format is JSON, and the interactive console renderer otherwise.
+func NewReporter(console input.Console, formatter output.Formatter) Reporter {
+	if formatter != nil && formatter.Kind() == output.JsonFormat {
+		return NewJsonRenderer(console)
+	}
+
+	return NewConsoleRenderer(console)
+}
+
+// consoleRenderer renders progress with the console spinner. A step shows a spinner with its title, updates and
+// sub-steps are shown next to the title, and the spinner is stopped with the final status of the step.
+type consoleRenderer struct {
+	console input.Console
+
+	mu     sync.Mutex
+	titles map[string]string
+}
+
+// NewConsoleRenderer returns a [Reporter] that renders progress with the spinner of console.
+func NewConsoleRenderer(console input.Console) Reporter {
+	return &consoleRenderer{
+		console: console,
+		titles:  map[string]string{},
+	}
+}
+
+func (r *consoleRenderer) Report(ctx context.Context, event Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Sub-steps are shown as details of their top level step, since the console has a single spinner.
+	title := event.Title
+	if event.Parent != "" {
+		if parentTitle, has := r.titles[event.Parent]; has {
+			title = parentTitle
+		}
+	}
+
+	switch event.Kind {
+	case KindStepStarted:
+		r.titles[event.Step] = title
+		if event.Parent != "" {
+			r.console.ShowSpinner(ctx, fmt.Sprintf("%s (%s)", title, event.Title), input.Step)
+		} else {
+			r.console.ShowSpinner(ctx, title, input.Step)
+		}
+	case KindStepUpdated:
+		r.console.ShowSpinner(ctx, fmt.Sprintf("%s (%s)", title, event.detail()), input.Step)
+	case KindWarning:
+		r.console.Message(ctx, output.WithWarningFormat("WARNING: %s", event.Message))
+	case KindStepCompleted:
+		delete(r.titles, event.Step)
+		if event.Parent != "" {
+			// Go back to the title of the parent step, which is still running.
+			r.console.ShowSpinner(ctx, title, input.Step)
+			return
+		}
+
+		r.console.StopSpinner(ctx, title, spinnerResultFormat(event.Status))
+	}
+}
+
+func spinnerResultFormat(status Status) input.SpinnerUxType {
+	switch status {
+	case StatusFailed:
+		return input.StepFailed
+	case StatusSkipped:
+		return input.StepSkipped
+	default:
+		return input.StepDone
+	}
+}
+
+// jsonRenderer writes each event as a line of the JSON event stream.
+type jsonRenderer struct {
+	console input.Console
+	mu      sync.Mutex
+}
+
+// NewJsonRenderer returns a [Reporter] that writes each event to console as a JSON event envelope of type
+// "progress", next to the console messages of the JSON event stream.
+func NewJsonRenderer(console input.Console) Reporter {
+	return &jsonRenderer{console: console}
+}
+
+func (r *jsonRenderer) Report(ctx context.Context, event Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.console.MessageUxItem(ctx, event)
+}
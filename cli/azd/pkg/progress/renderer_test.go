@@ -0,0 +1,61 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package progress
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReporter(t *testing.T) {
+	console := mockinput.NewMockConsole()
+
+	require.IsType(t, &consoleRenderer{}, NewReporter(console, &output.NoneFormatter{}))
+	require.IsType(t, &jsonRenderer{}, NewReporter(console, &output.JsonFormatter{}))
+}
+
+func TestConsoleRenderer(t *testing.T) {
+	console := mockinput.NewMockConsole()
+	reporter := NewConsoleRenderer(console)
+
+	step := Start(t.Context(), reporter, PhasePublish, "api", "Publishing service api")
+	sub := step.SubStep("package", "Packaging service api")
+	sub.Update("Building image")
+	sub.Done(nil)
+	step.SetPercent(50, "Pushing image")
+	step.Warn("registry is slow")
+	step.Done(errors.New("boom"))
+
+	skipped := Start(t.Context(), reporter, PhasePublish, "web", "Publishing service web")
+	skipped.Skip("")
+
+	require.Equal(t, []mockinput.SpinnerOp{
+		{Op: mockinput.SpinnerOpShow, Message: "Publishing service api", Format: input.Step},
+		{Op: mockinput.SpinnerOpShow, Message: "Publishing service api (Packaging service api)", Format: input.Step},
+		{Op: mockinput.SpinnerOpShow, Message: "Publishing service api (Building image)", Format: input.Step},
+		{Op: mockinput.SpinnerOpShow, Message: "Publishing service api", Format: input.Step},
+		{Op: mockinput.SpinnerOpShow, Message: "Publishing service api (Pushing image, 50%)", Format: input.Step},
+		{Op: mockinput.SpinnerOpStop, Message: "Publishing service api", Format: input.StepFailed},
+		{Op: mockinput.SpinnerOpShow, Message: "Publishing service web", Format: input.Step},
+		{Op: mockinput.SpinnerOpStop, Message: "Publishing service web", Format: input.StepSkipped},
+	}, console.SpinnerOps())
+
+	require.Len(t, console.Output(), 1)
+	require.Contains(t, console.Output()[0], "WARNING: registry is slow")
+}
+
+func TestJsonRenderer(t *testing.T) {
+	console := mockinput.NewMockConsole()
+
+	step := Start(t.Context(), NewJsonRenderer(console), PhaseBuild, "api", "Building service api")
+	step.Done(nil)
+
+	require.Empty(t, console.SpinnerOps())
+	require.Equal(t, []string{"Building service api", "Building service api: succeeded"}, console.Output())
+}
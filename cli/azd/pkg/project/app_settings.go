@@ -0,0 +1,298 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// appSettingsProperty is the service property of the azd environment that records the names of the app settings
+// synced by the last deployment, so the settings removed from azure.yaml are removed from the app.
+const appSettingsProperty = "APP_SETTINGS"
+
+// AppSettingsOptions maps keys of the azd environment to the app settings of an App Service, function app or
+// container app service. On deploy, the app settings that differ from the azd environment are updated, and the ones
+// that were synced before but are no longer listed are removed.
+type AppSettingsOptions struct {
+	// Settings maps the names of app settings to the keys of the azd environment their values come from, or to
+	// ${keyvault:<vault-name>/<secret-name>} references to Key Vault secrets.
+	Settings map[string]string `yaml:"settings,omitempty"`
+	// Secrets maps the names of secret app settings to the keys of the azd environment their values come from. Their
+	// values are never shown, and are stored as secrets of container apps.
+	Secrets map[string]string `yaml:"secrets,omitempty"`
+}
+
+// values returns the values of the app settings, read from the azd environment, or returned by secretValue for the
+// settings that reference Key Vault secrets. It returns an error listing the keys that aren't set in the environment.
+func (o *AppSettingsOptions) values(
+	env *environment.Environment, secretValue func(keyvault.SecretExpression) (string, error),
+) (map[string]string, error) {
+	values := map[string]string{}
+	var missing []string
+	for _, mapping := range []map[string]string{o.Settings, o.Secrets} {
+		for _, name := range slices.Sorted(maps.Keys(mapping)) {
+			key := mapping[name]
+			if keyvault.HasSecretExpression(key) {
+				expression, err := keyvault.ParseSecretExpression(key)
+				if err != nil {
+					return nil, fmt.Errorf("app setting %s: %w", name, err)
+				}
+
+				value, err := secretValue(expression)
+				if err != nil {
+					return nil, fmt.Errorf("app setting %s: %w", name, err)
+				}
+
+				values[name] = value
+				continue
+			}
+
+			value, has := env.LookupEnv(key)
+			if !has {
+				missing = append(missing, key)
+				continue
+			}
+
+			values[name] = value
+		}
+	}
+
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return nil, fmt.Errorf(
+			"app settings reference keys that aren't set in the environment: %s", strings.Join(missing, ", "))
+	}
+
+	return values, nil
+}
+
+// validateAppSettingsOptions returns the problems of the app settings of a service.
+func validateAppSettingsOptions(
+	options *AppSettingsOptions, host ServiceTargetKind, env osutil.ExpandableMap, scope string,
+) []string {
+	if options == nil {
+		return nil
+	}
+
+	var problems []string
+	if host != AppServiceTarget && host != AzureFunctionTarget && host != ContainerAppTarget {
+		problems = append(problems, fmt.Sprintf(
+			"%s: appSettings is only supported for services with host '%s', '%s' or '%s'",
+			scope, AppServiceTarget, AzureFunctionTarget, ContainerAppTarget))
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(options.Settings)) {
+		if _, has := options.Secrets[name]; has {
+			problems = append(problems, fmt.Sprintf(
+				"%s: app setting '%s' is both in appSettings.settings and appSettings.secrets", scope, name))
+		}
+	}
+
+	for _, mapping := range []map[string]string{options.Settings, options.Secrets} {
+		for _, name := range slices.Sorted(maps.Keys(mapping)) {
+			if strings.TrimSpace(name) == "" || strings.TrimSpace(mapping[name]) == "" {
+				problems = append(problems, fmt.Sprintf(
+					"%s: app settings require a name and an environment key, got '%s: %s'", scope, name, mapping[name]))
+			}
+
+			if _, has := env[name]; has {
+				problems = append(problems, fmt.Sprintf(
+					"%s: app setting '%s' is also set in env", scope, name))
+			}
+		}
+	}
+
+	return problems
+}
+
+// diffAppSettings returns the app settings of desired that are missing from current or have another value, and the
+// names of the previously synced settings that are no longer desired but are still in current.
+func diffAppSettings(
+	current map[string]string, desired map[string]string, previous []string,
+) (map[string]string, []string) {
+	changed := map[string]string{}
+	for name, value := range desired {
+		if currentValue, has := current[name]; !has || currentValue != value {
+			changed[name] = value
+		}
+	}
+
+	var removed []string
+	for _, name := range previous {
+		if _, has := desired[name]; has {
+			continue
+		}
+
+		if _, has := current[name]; has {
+			removed = append(removed, name)
+		}
+	}
+	slices.Sort(removed)
+
+	return changed, removed
+}
+
+// previousAppSettings returns the names of the app settings synced by the last deployment of a service.
+func previousAppSettings(env *environment.Environment, serviceName string) []string {
+	value := env.GetServiceProperty(serviceName, appSettingsProperty)
+	if value == "" {
+		return nil
+	}
+
+	return strings.Split(value, ",")
+}
+
+// recordAppSettings records the names of the app settings synced by a deployment of a service. The environment is
+// saved by the deploy action.
+func recordAppSettings(env *environment.Environment, serviceName string, desired map[string]string) {
+	env.SetServiceProperty(serviceName, appSettingsProperty, strings.Join(slices.Sorted(maps.Keys(desired)), ","))
+}
+
+// appSettingsProgress returns the progress message of an update of app settings. The names of the settings are logged,
+// their values never are.
+func appSettingsProgress(changed map[string]string, removed []string) string {
+	log.Printf("updating app settings %v, removing app settings %v", slices.Sorted(maps.Keys(changed)), removed)
+	return fmt.Sprintf("Updating app settings (%d changed, %d removed)", len(changed), len(removed))
+}
+
+// syncWebAppSettings updates the app settings of an App Service or a function app, or of its deployment slot when
+// slotName isn't empty, that differ from the azd environment, and removes the ones that are no longer in azure.yaml.
+// The settings are only written when one of them changed.
+func syncWebAppSettings(
+	ctx context.Context,
+	cli *azapi.AzureClient,
+	env *environment.Environment,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	slotName string,
+	progress *async.Progress[ServiceProgress],
+) error {
+	if serviceConfig.AppSettings == nil {
+		return nil
+	}
+
+	// App Service resolves the Key Vault references with the managed identity of the app.
+	desired, err := serviceConfig.AppSettings.values(env, func(e keyvault.SecretExpression) (string, error) {
+		return e.AppServiceReference(), nil
+	})
+	if err != nil {
+		return fmt.Errorf("service %s: %w", serviceConfig.Name, err)
+	}
+
+	progress.SetProgress(NewServiceProgress("Comparing app settings"))
+	existing, err := cli.GetAppServiceAppSettings(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		slotName,
+	)
+	if err != nil {
+		return err
+	}
+
+	current := map[string]string{}
+	for name, value := range existing {
+		if value != nil {
+			current[name] = *value
+		}
+	}
+
+	changed, removed := diffAppSettings(current, desired, previousAppSettings(env, serviceConfig.Name))
+	if len(changed) > 0 || len(removed) > 0 {
+		progress.SetProgress(NewServiceProgress(appSettingsProgress(changed, removed)))
+		for name, value := range changed {
+			existing[name] = &value
+		}
+		for _, name := range removed {
+			delete(existing, name)
+		}
+
+		err := cli.ReplaceAppServiceAppSettings(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+			slotName,
+			existing,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	recordAppSettings(env, serviceConfig.Name, desired)
+	return nil
+}
+
+// containerAppSettings returns the changes to the environment variables of a container app that sync them with the app
+// settings of the service, and the desired app settings to record once they're deployed. It returns nil changes when
+// the service has no app settings, or none of them changed.
+func (at *containerAppTarget) containerAppSettings(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	appName string,
+	options *containerapps.ContainerAppOptions,
+) (*containerapps.AppSettings, map[string]string, error) {
+	if serviceConfig.AppSettings == nil {
+		return nil, nil, nil
+	}
+
+	desired, err := serviceConfig.AppSettings.values(at.env, func(e keyvault.SecretExpression) (string, error) {
+		return resolveSecretExpression(ctx, at.keyvaultService, targetResource.SubscriptionId(), e)
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("service %s: %w", serviceConfig.Name, err)
+	}
+
+	existing, err := at.containerAppService.GetAppSettings(
+		ctx, targetResource.SubscriptionId(), targetResource.ResourceGroupName(), appName, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A setting that moves between settings and secrets changes even when its value doesn't.
+	current := map[string]string{}
+	for name, setting := range existing {
+		_, secret := serviceConfig.AppSettings.Secrets[name]
+		if _, desired := desired[name]; !desired || setting.Secret == secret {
+			current[name] = setting.Value
+		}
+	}
+
+	changed, removed := diffAppSettings(current, desired, previousAppSettings(at.env, serviceConfig.Name))
+	if len(changed) == 0 && len(removed) == 0 {
+		return nil, desired, nil
+	}
+
+	log.Print(appSettingsProgress(changed, removed))
+
+	settings := &containerapps.AppSettings{
+		Env:     map[string]string{},
+		Secrets: map[string]string{},
+		Remove:  removed,
+	}
+	for name, value := range changed {
+		if _, secret := serviceConfig.AppSettings.Secrets[name]; secret {
+			settings.Secrets[name] = value
+		} else {
+			settings.Env[name] = value
+		}
+	}
+
+	return settings, desired, nil
+}
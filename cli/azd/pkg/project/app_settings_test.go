@@ -0,0 +1,214 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazapi"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateAppSettingsOptions(t *testing.T) {
+	scope := "service 'api'"
+	require.Nil(t, validateAppSettingsOptions(nil, AppServiceTarget, nil, scope))
+	require.Empty(t, validateAppSettingsOptions(&AppSettingsOptions{
+		Settings: map[string]string{"DATABASE_HOST": "AZURE_POSTGRES_HOST"},
+		Secrets:  map[string]string{"DATABASE_PASSWORD": "POSTGRES_PASSWORD"},
+	}, ContainerAppTarget, nil, scope))
+
+	problems := validateAppSettingsOptions(&AppSettingsOptions{
+		Settings: map[string]string{"API_KEY": "KEY", "LOG_LEVEL": "", "PORT": "PORT"},
+		Secrets:  map[string]string{"API_KEY": "KEY"},
+	}, StaticWebAppTarget, osutil.ExpandableMap{"PORT": osutil.NewExpandableString("80")}, scope)
+	require.Len(t, problems, 4)
+	require.Contains(t, problems[0], "only supported for services with host 'appservice', 'function' or 'containerapp'")
+	require.Contains(t, problems[1], "'API_KEY' is both in appSettings.settings and appSettings.secrets")
+	require.Contains(t, problems[2], "require a name and an environment key, got 'LOG_LEVEL: '")
+	require.Contains(t, problems[3], "app setting 'PORT' is also set in env")
+}
+
+func Test_AppSettingsOptions_Values(t *testing.T) {
+	env := environment.NewWithValues("test", map[string]string{
+		"AZURE_POSTGRES_HOST": "db.contoso.com",
+		"POSTGRES_PASSWORD":   "p@ss",
+		"EMPTY":               "",
+	})
+
+	values, err := (&AppSettingsOptions{
+		Settings: map[string]string{"DATABASE_HOST": "AZURE_POSTGRES_HOST", "FEATURE_FLAGS": "EMPTY"},
+		Secrets:  map[string]string{"DATABASE_PASSWORD": "POSTGRES_PASSWORD"},
+	}).values(env, nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"DATABASE_HOST":     "db.contoso.com",
+		"DATABASE_PASSWORD": "p@ss",
+		"FEATURE_FLAGS":     "",
+	}, values)
+
+	values, err = (&AppSettingsOptions{
+		Secrets: map[string]string{"API_KEY": "${keyvault:my-vault/api-key}"},
+	}).values(env, func(e keyvault.SecretExpression) (string, error) {
+		return e.AppServiceReference(), nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"API_KEY": "@Microsoft.KeyVault(VaultName=my-vault;SecretName=api-key)",
+	}, values)
+
+	_, err = (&AppSettingsOptions{
+		Settings: map[string]string{"A": "MISSING_B", "B": "MISSING_A"},
+	}).values(env, nil)
+	require.ErrorContains(t, err, "keys that aren't set in the environment: MISSING_A, MISSING_B")
+}
+
+func Test_diffAppSettings(t *testing.T) {
+	changed, removed := diffAppSettings(
+		map[string]string{"SAME": "1", "CHANGED": "old", "REMOVED": "x", "UNMANAGED": "y"},
+		map[string]string{"SAME": "1", "CHANGED": "new", "ADDED": "z"},
+		[]string{"SAME", "CHANGED", "REMOVED", "ALREADY_GONE"},
+	)
+	require.Equal(t, map[string]string{"CHANGED": "new", "ADDED": "z"}, changed)
+	require.Equal(t, []string{"REMOVED"}, removed)
+
+	changed, removed = diffAppSettings(map[string]string{"SAME": "1"}, map[string]string{"SAME": "1"}, nil)
+	require.Empty(t, changed)
+	require.Empty(t, removed)
+}
+
+func Test_syncWebAppSettings(t *testing.T) {
+	sync := func(t *testing.T, existing map[string]*string) (*environment.Environment, *string, error) {
+		mockContext := mocks.NewMockContext(t.Context())
+		mockContext.HttpClient.When(func(req *http.Request) bool {
+			return req.Method == http.MethodPost && strings.Contains(req.URL.Path, "/config/appsettings/list")
+		}).RespondFn(func(req *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(req, http.StatusOK, armappservice.StringDictionary{
+				Properties: existing,
+			})
+		})
+
+		var body *string
+		mockContext.HttpClient.When(func(req *http.Request) bool {
+			return req.Method == http.MethodPut && strings.Contains(req.URL.Path, "/config/appsettings")
+		}).RespondFn(func(req *http.Request) (*http.Response, error) {
+			bytes, _ := io.ReadAll(req.Body)
+			body = new(string(bytes))
+			return mocks.CreateHttpResponseWithBody(req, http.StatusOK, armappservice.StringDictionary{})
+		})
+
+		env := environment.NewWithValues("test", map[string]string{
+			"AZURE_POSTGRES_HOST":      "db.contoso.com",
+			"SERVICE_API_APP_SETTINGS": "DATABASE_HOST,OLD_SETTING",
+		})
+		serviceConfig := &ServiceConfig{
+			Name: "api",
+			AppSettings: &AppSettingsOptions{
+				Settings: map[string]string{"DATABASE_HOST": "AZURE_POSTGRES_HOST"},
+			},
+		}
+		targetResource := environment.NewTargetResource(
+			"SUB_ID", "RG_ID", "WEB_APP_NAME", string(azapi.AzureResourceTypeWebSite))
+
+		err := syncWebAppSettings(
+			*mockContext.Context,
+			mockazapi.NewAzureClientFromMockContext(mockContext),
+			env,
+			serviceConfig,
+			targetResource,
+			"",
+			async.NewNoopProgress[ServiceProgress](),
+		)
+		return env, body, err
+	}
+
+	t.Run("Changed", func(t *testing.T) {
+		env, body, err := sync(t, map[string]*string{
+			"DATABASE_HOST": new("localhost"),
+			"OLD_SETTING":   new("x"),
+			"UNMANAGED":     new("y"),
+		})
+		require.NoError(t, err)
+		require.NotNil(t, body)
+		require.Contains(t, *body, `"DATABASE_HOST":"db.contoso.com"`)
+		require.Contains(t, *body, `"UNMANAGED":"y"`)
+		require.NotContains(t, *body, "OLD_SETTING")
+		require.Equal(t, "DATABASE_HOST", env.GetServiceProperty("api", appSettingsProperty))
+	})
+
+	t.Run("Unchanged", func(t *testing.T) {
+		_, body, err := sync(t, map[string]*string{"DATABASE_HOST": new("db.contoso.com")})
+		require.NoError(t, err)
+		require.Nil(t, body, "the app settings aren't written when none changed")
+	})
+}
+
+// fakeAppSettingsContainerAppService returns the environment variables of a container app.
+type fakeAppSettingsContainerAppService struct {
+	containerapps.ContainerAppService
+
+	settings map[string]containerapps.AppSetting
+}
+
+func (f *fakeAppSettingsContainerAppService) GetAppSettings(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	options *containerapps.ContainerAppOptions,
+) (map[string]containerapps.AppSetting, error) {
+	return f.settings, nil
+}
+
+func Test_ContainerApp_AppSettings(t *testing.T) {
+	env := environment.NewWithValues("test", map[string]string{
+		"AZURE_POSTGRES_HOST": "db.contoso.com",
+		"POSTGRES_PASSWORD":   "p@ss",
+		"LOG_LEVEL":           "info",
+	})
+	env.SetServiceProperty("api", appSettingsProperty, "DATABASE_HOST,DATABASE_PASSWORD,LOG_LEVEL,OLD_SETTING")
+
+	target := &containerAppTarget{
+		env: env,
+		containerAppService: &fakeAppSettingsContainerAppService{
+			settings: map[string]containerapps.AppSetting{
+				"DATABASE_HOST":     {Value: "db.contoso.com"},
+				"DATABASE_PASSWORD": {Value: "p@ss"},
+				"LOG_LEVEL":         {Value: "debug"},
+				"OLD_SETTING":       {Value: "x", Secret: true},
+			},
+		},
+	}
+	serviceConfig := &ServiceConfig{
+		Name: "api",
+		AppSettings: &AppSettingsOptions{
+			Settings: map[string]string{"DATABASE_HOST": "AZURE_POSTGRES_HOST", "LOG_LEVEL": "LOG_LEVEL"},
+			Secrets:  map[string]string{"DATABASE_PASSWORD": "POSTGRES_PASSWORD"},
+		},
+	}
+	targetResource := environment.NewTargetResource(
+		"SUB_ID", "RG_ID", "api", string(azapi.AzureResourceTypeContainerApp))
+
+	settings, desired, err := target.containerAppSettings(
+		t.Context(), serviceConfig, targetResource, "api", &containerapps.ContainerAppOptions{})
+	require.NoError(t, err)
+	require.Len(t, desired, 3)
+	// DATABASE_PASSWORD becomes a secret, even though its value didn't change.
+	require.Equal(t, &containerapps.AppSettings{
+		Env:     map[string]string{"LOG_LEVEL": "info"},
+		Secrets: map[string]string{"DATABASE_PASSWORD": "p@ss"},
+		Remove:  []string{"OLD_SETTING"},
+	}, settings)
+}
@@ -0,0 +1,76 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// AppServiceSlotOptions configures the deployment of an App Service through a staging slot: the service is deployed to
+// the slot, which is created when it doesn't exist, validated, and then swapped with production.
+type AppServiceSlotOptions struct {
+	// Name is the name of the deployment slot.
+	Name string `yaml:"name"`
+	// Swap is whether the slot is swapped with production once the deployment is validated. Defaults to true.
+	Swap *bool `yaml:"swap,omitempty"`
+	// HealthPath is the HTTP path of the slot that must respond with a success status before the slot is swapped.
+	// When empty, the slot is swapped as soon as it's deployed.
+	HealthPath string `yaml:"healthPath,omitempty"`
+	// HealthTimeout is how long, in seconds, the slot has to respond on its health path before the deployment fails.
+	// Defaults to 300.
+	HealthTimeout int `yaml:"healthTimeout,omitempty"`
+}
+
+// swap returns whether the slot is swapped with production after the deployment.
+func (o *AppServiceSlotOptions) swap() bool {
+	return o.Swap == nil || *o.Swap
+}
+
+// healthTimeout returns how long the slot has to respond on its health path.
+func (o *AppServiceSlotOptions) healthTimeout() time.Duration {
+	if o.HealthTimeout == 0 {
+		return defaultHealthTimeout
+	}
+
+	return time.Duration(o.HealthTimeout) * time.Second
+}
+
+var slotNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*$`)
+
+// validateAppServiceSlotOptions returns the problems of the deployment slot of a service.
+func validateAppServiceSlotOptions(options *AppServiceSlotOptions, host ServiceTargetKind, scope string) []string {
+	if options == nil {
+		return nil
+	}
+
+	var problems []string
+	if host != AppServiceTarget {
+		problems = append(problems, fmt.Sprintf(
+			"%s: deployment slots are only supported for services with host '%s'", scope, AppServiceTarget))
+	}
+
+	switch {
+	case options.Name == "":
+		problems = append(problems, fmt.Sprintf("%s: slot.name is required", scope))
+	case strings.EqualFold(options.Name, productionSlotName):
+		problems = append(problems, fmt.Sprintf(
+			"%s: slot.name can't be '%s', which is the name of the main app", scope, productionSlotName))
+	case !slotNameRegex.MatchString(options.Name):
+		problems = append(problems, fmt.Sprintf(
+			"%s: slot.name '%s' may only contain letters, numbers and hyphens", scope, options.Name))
+	}
+
+	if options.HealthPath != "" && !strings.HasPrefix(options.HealthPath, "/") {
+		problems = append(problems, fmt.Sprintf("%s: slot.healthPath must start with '/'", scope))
+	}
+
+	if options.HealthTimeout < 0 {
+		problems = append(problems, fmt.Sprintf("%s: slot.healthTimeout must be a positive number of seconds", scope))
+	}
+
+	return problems
+}
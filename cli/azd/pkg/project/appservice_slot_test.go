@@ -0,0 +1,184 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazapi"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateAppServiceSlotOptions(t *testing.T) {
+	scope := "service 'web'"
+	require.Nil(t, validateAppServiceSlotOptions(nil, AppServiceTarget, scope))
+	require.Empty(t, validateAppServiceSlotOptions(&AppServiceSlotOptions{
+		Name:          "staging",
+		HealthPath:    "/health",
+		HealthTimeout: 120,
+	}, AppServiceTarget, scope))
+
+	problems := validateAppServiceSlotOptions(&AppServiceSlotOptions{
+		HealthPath:    "health",
+		HealthTimeout: -1,
+	}, ContainerAppTarget, scope)
+	require.Len(t, problems, 4)
+	require.Contains(t, problems[0], "only supported for services with host 'appservice'")
+	require.Contains(t, problems[1], "slot.name is required")
+	require.Contains(t, problems[2], "slot.healthPath must start with '/'")
+	require.Contains(t, problems[3], "slot.healthTimeout must be a positive number")
+
+	problems = validateAppServiceSlotOptions(&AppServiceSlotOptions{Name: "Production"}, AppServiceTarget, scope)
+	require.Len(t, problems, 1)
+	require.Contains(t, problems[0], "can't be 'production'")
+
+	problems = validateAppServiceSlotOptions(&AppServiceSlotOptions{Name: "my_slot"}, AppServiceTarget, scope)
+	require.Len(t, problems, 1)
+	require.Contains(t, problems[0], "may only contain letters, numbers and hyphens")
+}
+
+func Test_appServiceTarget_Deploy_StagingSlot(t *testing.T) {
+	setup := func(t *testing.T, healthStatus int) (*mocks.MockContext, *httptest.Server, *bool, *bool) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/health", r.URL.Path)
+			w.WriteHeader(healthStatus)
+		}))
+		t.Cleanup(server.Close)
+
+		mockContext := mocks.NewMockContext(t.Context())
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet &&
+				strings.Contains(request.URL.Path, "/sites/WEB_APP_NAME") &&
+				!strings.Contains(request.URL.Path, "/slots")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			response := armappservice.WebAppsClientGetResponse{
+				Site: armappservice.Site{
+					Location: new("eastus2"),
+					Kind:     new("app,linux,container"),
+					Properties: &armappservice.SiteProperties{
+						DefaultHostName: new("webapp.azurewebsites.net"),
+						SiteConfig: &armappservice.SiteConfig{
+							LinuxFxVersion: new("DOCKER|placeholder:latest"),
+						},
+					},
+				},
+			}
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+		})
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, "/config/appsettings/list")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(
+				request, http.StatusOK, armappservice.WebAppsClientListApplicationSettingsResponse{})
+		})
+
+		// The slot doesn't exist until it's created.
+		created := false
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/slots")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			response := armappservice.WebAppsClientListSlotsResponse{}
+			if created {
+				response.Value = []*armappservice.Site{{Name: new("WEB_APP_NAME/staging")}}
+			}
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+		})
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return (request.Method == http.MethodPut || request.Method == http.MethodPatch ||
+				request.Method == http.MethodGet) && strings.HasSuffix(request.URL.Path, "/slots/staging")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			if request.Method == http.MethodPut {
+				created = true
+			}
+			site := armappservice.Site{
+				Properties: &armappservice.SiteProperties{
+					DefaultHostName: new(strings.TrimPrefix(server.URL, "https://")),
+				},
+			}
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, site)
+		})
+
+		swapped := false
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, "/slotsswap")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			swapped = true
+			return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+		})
+
+		return mockContext, server, &created, &swapped
+	}
+
+	deploy := func(
+		t *testing.T,
+		mockContext *mocks.MockContext,
+		server *httptest.Server,
+		slot *AppServiceSlotOptions,
+	) error {
+		sctx := NewServiceContext()
+		require.NoError(t, sctx.Publish.Add(&Artifact{
+			Kind:         ArtifactKindContainer,
+			Location:     "myregistry.azurecr.io/myapp:abc123",
+			LocationKind: LocationKindRemote,
+		}))
+
+		st := &appServiceTarget{
+			env:        environment.New("test"),
+			cli:        mockazapi.NewAzureClientFromMockContext(mockContext),
+			console:    mockContext.Console,
+			httpClient: server.Client(),
+		}
+
+		targetResource := environment.NewTargetResource(
+			"SUB_ID", "RG_ID", "WEB_APP_NAME", string(azapi.AzureResourceTypeWebSite),
+		)
+		serviceConfig := &ServiceConfig{Name: "web", Host: AppServiceTarget, Slot: slot}
+		_, err := st.Deploy(
+			*mockContext.Context, serviceConfig, sctx, targetResource, async.NewNoopProgress[ServiceProgress]())
+		return err
+	}
+
+	t.Run("CreatesValidatesAndSwaps", func(t *testing.T) {
+		mockContext, server, created, swapped := setup(t, http.StatusOK)
+		err := deploy(t, mockContext, server, &AppServiceSlotOptions{Name: "staging", HealthPath: "/health"})
+		require.NoError(t, err)
+		require.True(t, *created)
+		require.True(t, *swapped)
+	})
+
+	t.Run("UnhealthyNotSwapped", func(t *testing.T) {
+		pollInterval := slotHealthPollInterval
+		slotHealthPollInterval = 10 * time.Millisecond
+		t.Cleanup(func() { slotHealthPollInterval = pollInterval })
+
+		mockContext, server, _, swapped := setup(t, http.StatusInternalServerError)
+		err := deploy(t, mockContext, server, &AppServiceSlotOptions{
+			Name:          "staging",
+			HealthPath:    "/health",
+			HealthTimeout: 1,
+		})
+		require.ErrorContains(t, err, "production was not changed")
+		require.ErrorContains(t, err, "status 500")
+		require.False(t, *swapped)
+	})
+
+	t.Run("SwapDisabled", func(t *testing.T) {
+		mockContext, server, _, swapped := setup(t, http.StatusOK)
+		err := deploy(t, mockContext, server, &AppServiceSlotOptions{Name: "staging", Swap: new(false)})
+		require.NoError(t, err)
+		require.False(t, *swapped)
+	})
+}
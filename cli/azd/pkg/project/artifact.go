@@ -22,6 +22,27 @@ const (
 
 	// MetadataKeyNote adds a note line below the artifact output.
 	MetadataKeyNote = "note"
+
+	// MetadataKeyReference is the artifact store reference of a package that was pushed to the artifact store.
+	MetadataKeyReference = "reference"
+
+	// MetadataKeyImageManifestReference is the artifact store reference of the image manifest of a container image that
+	// was pushed to the artifact store.
+	MetadataKeyImageManifestReference = "imageManifestReference"
+
+	// MetadataKeyImageDigest is the digest of a container image that was signed after it was pushed to a registry.
+	MetadataKeyImageDigest = "imageDigest"
+
+	// MetadataKeySignature is the signature of a container image: the digest of the signature with notation, or the
+	// reference of the signature with cosign.
+	MetadataKeySignature = "signature"
+
+	// MetadataKeySbom is the path of the software bill of materials (SBOM) generated for a package.
+	MetadataKeySbom = "sbom"
+
+	// MetadataKeySbomReference is the artifact store reference of the SBOM of a package that was pushed to the artifact
+	// store.
+	MetadataKeySbomReference = "sbomReference"
 )
 
 // ArtifactKind represents well-known artifact types in the Azure Developer CLI
@@ -133,15 +154,32 @@ func (a *Artifact) ToString(currentIndentation string) string {
 
 	case ArtifactKindContainer:
 		if a.LocationKind == LocationKindRemote {
-			return fmt.Sprintf("%s- Remote Image: %s", currentIndentation, output.WithLinkFormat(location))
+			result := fmt.Sprintf("%s- Remote Image: %s", currentIndentation, output.WithLinkFormat(location))
+			if signature, has := a.Metadata[MetadataKeySignature]; has && signature != "" {
+				result += fmt.Sprintf("\n%s- Signature: %s", currentIndentation, output.WithHighLightFormat(signature))
+			}
+
+			return result
 		}
-		return fmt.Sprintf("%s- Container: %s", currentIndentation, output.WithLinkFormat(location))
+		result := fmt.Sprintf("%s- Container: %s", currentIndentation, output.WithLinkFormat(location))
+		if reference, has := a.Metadata[MetadataKeyImageManifestReference]; has && reference != "" {
+			result += fmt.Sprintf(
+				"\n%s- Image Manifest Reference: %s", currentIndentation, output.WithHighLightFormat(reference))
+		}
+
+		return result + a.sbomString(currentIndentation)
 
 	case ArtifactKindArchive:
-		return fmt.Sprintf("%s- Package Output: %s", currentIndentation, output.WithHyperlink(location, a.Location))
+		result := fmt.Sprintf("%s- Package Output: %s", currentIndentation, output.WithHyperlink(location, a.Location))
+		if reference, has := a.Metadata[MetadataKeyReference]; has && reference != "" {
+			result += fmt.Sprintf("\n%s- Package Reference: %s", currentIndentation, output.WithHighLightFormat(reference))
+		}
+
+		return result + a.sbomString(currentIndentation)
 
 	case ArtifactKindDirectory:
-		return fmt.Sprintf("%s- Build Output: %s", currentIndentation, output.WithHyperlink(location, a.Location))
+		return fmt.Sprintf("%s- Build Output: %s", currentIndentation, output.WithHyperlink(location, a.Location)) +
+			a.sbomString(currentIndentation)
 
 	// Ignore other artifact kinds for now
 	default:
@@ -149,6 +187,21 @@ func (a *Artifact) ToString(currentIndentation string) string {
 	}
 }
 
+// sbomString returns the lines of the SBOM of a package artifact, when it has one.
+func (a *Artifact) sbomString(currentIndentation string) string {
+	sbom, has := a.Metadata[MetadataKeySbom]
+	if !has || sbom == "" {
+		return ""
+	}
+
+	result := fmt.Sprintf("\n%s- SBOM: %s", currentIndentation, output.WithHyperlink(sbom, sbom))
+	if reference, has := a.Metadata[MetadataKeySbomReference]; has && reference != "" {
+		result += fmt.Sprintf("\n%s- SBOM Reference: %s", currentIndentation, output.WithHighLightFormat(reference))
+	}
+
+	return result
+}
+
 // MarshalJSON implements the UxItem interface JSON marshaling
 func (a *Artifact) MarshalJSON() ([]byte, error) {
 	return json.Marshal(*a)
@@ -182,6 +182,26 @@ func TestArtifactToString_OtherKinds(t *testing.T) {
 			},
 			contains: "- Package Output:",
 		},
+		{
+			name: "archive pushed",
+			artifact: &Artifact{
+				Kind:         ArtifactKindArchive,
+				Location:     "/path/to/output.zip",
+				LocationKind: LocationKindLocal,
+				Metadata:     map[string]string{MetadataKeyReference: "sha256:0123/output.zip"},
+			},
+			contains: "- Package Reference:",
+		},
+		{
+			name: "container image manifest pushed",
+			artifact: &Artifact{
+				Kind:         ArtifactKindContainer,
+				Location:     "api:latest",
+				LocationKind: LocationKindLocal,
+				Metadata:     map[string]string{MetadataKeyImageManifestReference: "sha256:0123/api-latest.image.json"},
+			},
+			contains: "- Image Manifest Reference:",
+		},
 		{
 			name: "directory",
 			artifact: &Artifact{
@@ -598,7 +618,7 @@ func Test_usingSwaConfig(t *testing.T) {
 
 func Test_staticWebAppTarget_Package(t *testing.T) {
 	t.Run("WithSwaConfig", func(t *testing.T) {
-		target := NewStaticWebAppTarget(nil, nil, nil)
+		target := NewStaticWebAppTarget(nil, nil, nil, nil, nil)
 		svcCtx := NewServiceContext()
 		require.NoError(t, svcCtx.Package.Add(&Artifact{
 			Kind:         ArtifactKindConfig,
@@ -613,7 +633,7 @@ func Test_staticWebAppTarget_Package(t *testing.T) {
 	})
 
 	t.Run("WithBuildOutput", func(t *testing.T) {
-		target := NewStaticWebAppTarget(nil, nil, nil)
+		target := NewStaticWebAppTarget(nil, nil, nil, nil, nil)
 		svcCtx := NewServiceContext()
 		require.NoError(t, svcCtx.Package.Add(&Artifact{
 			Kind:         ArtifactKindDirectory,
@@ -629,7 +649,7 @@ func Test_staticWebAppTarget_Package(t *testing.T) {
 	})
 
 	t.Run("WithOutputPath", func(t *testing.T) {
-		target := NewStaticWebAppTarget(nil, nil, nil)
+		target := NewStaticWebAppTarget(nil, nil, nil, nil, nil)
 		svcCtx := NewServiceContext()
 		require.NoError(t, svcCtx.Package.Add(&Artifact{
 			Kind:         ArtifactKindDirectory,
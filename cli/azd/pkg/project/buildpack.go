@@ -0,0 +1,91 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// BuildpackOptions configures the build of a container image from source with Cloud Native Buildpacks, used when the
+// service has no Dockerfile.
+type BuildpackOptions struct {
+	// Builder is the builder image. Overrides AZD_BUILDER_IMAGE and the default Oryx builder. The Oryx specific
+	// defaults, such as the runtime port, aren't applied to other builders.
+	Builder osutil.ExpandableString `yaml:"builder,omitempty" json:"builder,omitempty"`
+	// Env are the build-time environment variables, as NAME=value. Values support environment variable substitution,
+	// and a NAME without a value is read from the azd environment or the environment of the process.
+	Env []osutil.ExpandableString `yaml:"env,omitempty" json:"env,omitempty"`
+	// CacheVolume is the docker volume holding the build cache, kept between builds and shared by the services that
+	// use the same volume. Defaults to a volume named after the image.
+	CacheVolume string `yaml:"cacheVolume,omitempty" json:"cacheVolume,omitempty"`
+}
+
+// The names docker allows for volumes
+var dockerVolumeNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]+$`)
+
+// validateBuildpackOptions returns the problems of the buildpack options of a service.
+func validateBuildpackOptions(docker DockerProjectOptions, scope string) []string {
+	options := docker.Buildpack
+	if options == nil {
+		return nil
+	}
+
+	var problems []string
+	if docker.RemoteBuild {
+		problems = append(problems, fmt.Sprintf(
+			"%s: docker.buildpack cannot be used with docker.remoteBuild, remote builds require a Dockerfile", scope))
+	}
+
+	if docker.Path != "" {
+		problems = append(problems, fmt.Sprintf(
+			"%s: docker.buildpack cannot be used with docker.path, buildpacks are only used without a Dockerfile", scope))
+	}
+
+	if options.CacheVolume != "" && !dockerVolumeNameRegex.MatchString(options.CacheVolume) {
+		problems = append(problems, fmt.Sprintf(
+			"%s: docker.buildpack.cacheVolume '%s' is not a valid docker volume name", scope, options.CacheVolume))
+	}
+
+	return problems
+}
+
+// resolveBuildpackEnv resolves the build-time environment variables of a buildpack build. Variables without a value
+// are read from the azd environment, then from the environment of the process.
+func resolveBuildpackEnv(options *BuildpackOptions, env *environment.Environment) ([]string, error) {
+	if options == nil || len(options.Env) == 0 {
+		return nil, nil
+	}
+
+	resolved, err := resolveDockerBuildArgs(options.Env, env)
+	if err != nil {
+		return nil, fmt.Errorf("resolving buildpack env: %w", err)
+	}
+
+	for i, envVar := range resolved {
+		name, _, hasValue := strings.Cut(envVar, "=")
+		if name == "" {
+			return nil, fmt.Errorf("buildpack env at index %d has an empty name", i)
+		}
+
+		if hasValue {
+			continue
+		}
+
+		value, has := env.LookupEnv(name)
+		if !has {
+			return nil, fmt.Errorf(
+				"resolving buildpack env %q: environment variable is not set; use %s=<value> or set %s in the "+
+					"azd environment", name, name, name)
+		}
+
+		resolved[i] = name + "=" + value
+	}
+
+	return resolved, nil
+}
@@ -0,0 +1,71 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateBuildpackOptions(t *testing.T) {
+	scope := "service 'web'"
+	require.Nil(t, validateBuildpackOptions(DockerProjectOptions{}, scope))
+	require.Empty(t, validateBuildpackOptions(DockerProjectOptions{
+		Buildpack: &BuildpackOptions{
+			Builder:     osutil.NewExpandableString("paketobuildpacks/builder-jammy-base"),
+			CacheVolume: "azd-web-cache",
+		},
+	}, scope))
+
+	problems := validateBuildpackOptions(DockerProjectOptions{
+		Path:        "./Dockerfile",
+		RemoteBuild: true,
+		Buildpack:   &BuildpackOptions{CacheVolume: "/var/cache"},
+	}, scope)
+	require.Len(t, problems, 3)
+	require.Contains(t, problems[0], "docker.buildpack cannot be used with docker.remoteBuild")
+	require.Contains(t, problems[1], "docker.buildpack cannot be used with docker.path")
+	require.Contains(t, problems[2], "docker.buildpack.cacheVolume '/var/cache' is not a valid docker volume name")
+}
+
+func Test_resolveBuildpackEnv(t *testing.T) {
+	t.Setenv("FROM_OS", "os-value")
+	env := environment.NewWithValues("dev", map[string]string{
+		"FROM_ENV":     "env-value",
+		"API_ENDPOINT": "https://api.contoso.com",
+	})
+
+	resolved, err := resolveBuildpackEnv(nil, env)
+	require.NoError(t, err)
+	require.Nil(t, resolved)
+
+	resolved, err = resolveBuildpackEnv(&BuildpackOptions{
+		Env: []osutil.ExpandableString{
+			osutil.NewExpandableString("BP_NODE_VERSION=20"),
+			osutil.NewExpandableString("VITE_API_URL=${API_ENDPOINT}/v1"),
+			osutil.NewExpandableString("FROM_ENV"),
+			osutil.NewExpandableString("FROM_OS"),
+		},
+	}, env)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"BP_NODE_VERSION=20",
+		"VITE_API_URL=https://api.contoso.com/v1",
+		"FROM_ENV=env-value",
+		"FROM_OS=os-value",
+	}, resolved)
+
+	_, err = resolveBuildpackEnv(&BuildpackOptions{
+		Env: []osutil.ExpandableString{osutil.NewExpandableString("MISSING")},
+	}, env)
+	require.ErrorContains(t, err, `resolving buildpack env "MISSING": environment variable is not set`)
+
+	_, err = resolveBuildpackEnv(&BuildpackOptions{
+		Env: []osutil.ExpandableString{osutil.NewExpandableString("=value")},
+	}, env)
+	require.ErrorContains(t, err, "buildpack env at index 0 has an empty name")
+}
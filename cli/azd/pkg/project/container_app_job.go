@@ -0,0 +1,345 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+)
+
+// ContainerAppJobTriggerKind is how the executions of a Container Apps job are started.
+type ContainerAppJobTriggerKind string
+
+const (
+	// ContainerAppJobTriggerManual starts executions on demand only.
+	ContainerAppJobTriggerManual ContainerAppJobTriggerKind = "manual"
+	// ContainerAppJobTriggerSchedule starts executions on a cron schedule.
+	ContainerAppJobTriggerSchedule ContainerAppJobTriggerKind = "schedule"
+	// ContainerAppJobTriggerEvent starts executions when the scale rules of the job report pending events.
+	ContainerAppJobTriggerEvent ContainerAppJobTriggerKind = "event"
+)
+
+const defaultJobExecutionTimeout = 10 * time.Minute
+
+// ContainerAppJobOptions configures the Container Apps job a container app service is deployed to: the trigger that
+// starts its executions, how its replicas run, and whether an execution is started to validate a deployment.
+type ContainerAppJobOptions struct {
+	// Trigger is the trigger type of the job, manual, schedule or event. When empty, the trigger of the job isn't
+	// changed.
+	Trigger ContainerAppJobTriggerKind `yaml:"trigger,omitempty"`
+	// CronExpression is the schedule of the executions, in the five fields cron format, for the schedule trigger.
+	CronExpression string `yaml:"cronExpression,omitempty"`
+	// Parallelism is the number of replicas run in parallel by an execution.
+	Parallelism int `yaml:"parallelism,omitempty"`
+	// ReplicaCompletionCount is the number of replicas that must complete successfully for an execution to succeed.
+	ReplicaCompletionCount int `yaml:"replicaCompletionCount,omitempty"`
+	// ReplicaTimeout is the maximum number of seconds a replica can run.
+	ReplicaTimeout int `yaml:"replicaTimeout,omitempty"`
+	// ReplicaRetryLimit is the maximum number of times a failed replica is retried.
+	ReplicaRetryLimit *int `yaml:"replicaRetryLimit,omitempty"`
+	// Scale configures the scale rules that start executions, for the event trigger.
+	Scale *ContainerAppJobScaleOptions `yaml:"scale,omitempty"`
+	// StartExecution starts an execution of the job after it is deployed, and fails the deployment when the execution
+	// doesn't succeed.
+	StartExecution bool `yaml:"startExecution,omitempty"`
+	// ExecutionTimeout is how long, in seconds, the execution started after the deployment has to succeed.
+	// Defaults to 600.
+	ExecutionTimeout int `yaml:"executionTimeout,omitempty"`
+}
+
+// ContainerAppJobScaleOptions configures how the executions of an event driven job are started.
+type ContainerAppJobScaleOptions struct {
+	// MinExecutions is the minimum number of executions started per polling interval.
+	MinExecutions int `yaml:"minExecutions,omitempty"`
+	// MaxExecutions is the maximum number of executions started per polling interval.
+	MaxExecutions int `yaml:"maxExecutions,omitempty"`
+	// PollingInterval is the number of seconds between two checks of the scale rules.
+	PollingInterval int `yaml:"pollingInterval,omitempty"`
+	// Rules are the KEDA scale rules that report the pending events.
+	Rules []ContainerAppJobScaleRule `yaml:"rules,omitempty"`
+}
+
+// ContainerAppJobScaleRule is a KEDA scale rule of an event driven job.
+type ContainerAppJobScaleRule struct {
+	// Name is the name of the rule.
+	Name string `yaml:"name"`
+	// Type is the KEDA scaler of the rule, such as azure-servicebus or azure-queue.
+	Type string `yaml:"type"`
+	// Metadata is the metadata of the scaler.
+	Metadata map[string]string `yaml:"metadata,omitempty"`
+	// Identity is the resource ID of the user assigned identity the scaler authenticates with, or 'system'.
+	Identity string `yaml:"identity,omitempty"`
+	// Auth maps the trigger parameters of the scaler to secrets of the job.
+	Auth []ContainerAppJobScaleRuleAuth `yaml:"auth,omitempty"`
+}
+
+// ContainerAppJobScaleRuleAuth maps a trigger parameter of a scaler to a secret of the job.
+type ContainerAppJobScaleRuleAuth struct {
+	// SecretRef is the name of the secret of the job.
+	SecretRef string `yaml:"secretRef"`
+	// TriggerParameter is the trigger parameter of the scaler that receives the secret.
+	TriggerParameter string `yaml:"triggerParameter"`
+}
+
+// executionTimeout returns how long the execution started after the deployment has to succeed.
+func (o *ContainerAppJobOptions) executionTimeout() time.Duration {
+	if o.ExecutionTimeout == 0 {
+		return defaultJobExecutionTimeout
+	}
+
+	return time.Duration(o.ExecutionTimeout) * time.Second
+}
+
+// configuration returns the job configuration applied when the job is updated, or nil when the options don't change
+// the configuration of the job.
+func (o *ContainerAppJobOptions) configuration() *armappcontainers.JobConfiguration {
+	if o == nil || (o.Trigger == "" && o.ReplicaTimeout == 0 && o.ReplicaRetryLimit == nil) {
+		return nil
+	}
+
+	configuration := &armappcontainers.JobConfiguration{
+		ReplicaTimeout:    optionalInt32(o.ReplicaTimeout),
+		ReplicaRetryLimit: optionalInt32Ptr(o.ReplicaRetryLimit),
+	}
+
+	parallelism := optionalInt32(o.Parallelism)
+	completionCount := optionalInt32(o.ReplicaCompletionCount)
+
+	switch o.Trigger {
+	case ContainerAppJobTriggerManual:
+		configuration.TriggerType = to.Ptr(armappcontainers.TriggerTypeManual)
+		configuration.ManualTriggerConfig = &armappcontainers.JobConfigurationManualTriggerConfig{
+			Parallelism:            parallelism,
+			ReplicaCompletionCount: completionCount,
+		}
+	case ContainerAppJobTriggerSchedule:
+		configuration.TriggerType = to.Ptr(armappcontainers.TriggerTypeSchedule)
+		configuration.ScheduleTriggerConfig = &armappcontainers.JobConfigurationScheduleTriggerConfig{
+			CronExpression:         to.Ptr(o.CronExpression),
+			Parallelism:            parallelism,
+			ReplicaCompletionCount: completionCount,
+		}
+	case ContainerAppJobTriggerEvent:
+		configuration.TriggerType = to.Ptr(armappcontainers.TriggerTypeEvent)
+		configuration.EventTriggerConfig = &armappcontainers.JobConfigurationEventTriggerConfig{
+			Parallelism:            parallelism,
+			ReplicaCompletionCount: completionCount,
+			Scale:                  o.Scale.jobScale(),
+		}
+	}
+
+	return configuration
+}
+
+// jobScale returns the scale of an event driven job.
+func (s *ContainerAppJobScaleOptions) jobScale() *armappcontainers.JobScale {
+	if s == nil {
+		return nil
+	}
+
+	scale := &armappcontainers.JobScale{
+		MinExecutions:   optionalInt32(s.MinExecutions),
+		MaxExecutions:   optionalInt32(s.MaxExecutions),
+		PollingInterval: optionalInt32(s.PollingInterval),
+	}
+
+	for _, rule := range s.Rules {
+		jobRule := &armappcontainers.JobScaleRule{
+			Name: to.Ptr(rule.Name),
+			Type: to.Ptr(rule.Type),
+		}
+
+		if len(rule.Metadata) > 0 {
+			jobRule.Metadata = rule.Metadata
+		}
+
+		if rule.Identity != "" {
+			jobRule.Identity = to.Ptr(rule.Identity)
+		}
+
+		for _, auth := range rule.Auth {
+			jobRule.Auth = append(jobRule.Auth, &armappcontainers.ScaleRuleAuth{
+				SecretRef:        to.Ptr(auth.SecretRef),
+				TriggerParameter: to.Ptr(auth.TriggerParameter),
+			})
+		}
+
+		scale.Rules = append(scale.Rules, jobRule)
+	}
+
+	return scale
+}
+
+// optionalInt32 returns nil for zero, which leaves the setting to its default.
+func optionalInt32(value int) *int32 {
+	if value == 0 {
+		return nil
+	}
+
+	return to.Ptr(int32(value))
+}
+
+// optionalInt32Ptr returns nil for nil, and the value as an int32 otherwise, so that zero can be set explicitly.
+func optionalInt32Ptr(value *int) *int32 {
+	if value == nil {
+		return nil
+	}
+
+	return to.Ptr(int32(*value))
+}
+
+// validateContainerAppJobOptions returns the problems of the job options of a service.
+func validateContainerAppJobOptions(options *ContainerAppJobOptions, host ServiceTargetKind, scope string) []string {
+	if options == nil {
+		return nil
+	}
+
+	var problems []string
+	if host != ContainerAppTarget {
+		problems = append(problems, fmt.Sprintf(
+			"%s: job is only supported for services with host '%s'", scope, ContainerAppTarget))
+	}
+
+	switch options.Trigger {
+	case "":
+		if options.Parallelism != 0 || options.ReplicaCompletionCount != 0 {
+			problems = append(problems, fmt.Sprintf(
+				"%s: job.parallelism and job.replicaCompletionCount require job.trigger", scope))
+		}
+	case ContainerAppJobTriggerManual, ContainerAppJobTriggerEvent:
+	case ContainerAppJobTriggerSchedule:
+		if len(strings.Fields(options.CronExpression)) != 5 {
+			problems = append(problems, fmt.Sprintf(
+				"%s: job.cronExpression must be a cron expression with five fields, got '%s'",
+				scope, options.CronExpression))
+		}
+	default:
+		problems = append(problems, fmt.Sprintf(
+			"%s: job.trigger '%s' is not supported, supported triggers: %s, %s, %s",
+			scope, options.Trigger,
+			ContainerAppJobTriggerManual, ContainerAppJobTriggerSchedule, ContainerAppJobTriggerEvent))
+	}
+
+	if options.CronExpression != "" && options.Trigger != ContainerAppJobTriggerSchedule {
+		problems = append(problems, fmt.Sprintf(
+			"%s: job.cronExpression is only supported by the '%s' trigger", scope, ContainerAppJobTriggerSchedule))
+	}
+
+	if options.Scale != nil && options.Trigger != ContainerAppJobTriggerEvent {
+		problems = append(problems, fmt.Sprintf(
+			"%s: job.scale is only supported by the '%s' trigger", scope, ContainerAppJobTriggerEvent))
+	}
+
+	if options.Trigger == ContainerAppJobTriggerEvent && (options.Scale == nil || len(options.Scale.Rules) == 0) {
+		problems = append(problems, fmt.Sprintf(
+			"%s: job.scale.rules is required by the '%s' trigger", scope, ContainerAppJobTriggerEvent))
+	}
+
+	if options.Parallelism < 0 || options.ReplicaCompletionCount < 0 {
+		problems = append(problems, fmt.Sprintf(
+			"%s: job.parallelism and job.replicaCompletionCount must be positive numbers", scope))
+	}
+
+	if options.ReplicaTimeout < 0 {
+		problems = append(problems, fmt.Sprintf("%s: job.replicaTimeout must be a positive number of seconds", scope))
+	}
+
+	if options.ExecutionTimeout < 0 {
+		problems = append(problems, fmt.Sprintf("%s: job.executionTimeout must be a positive number of seconds", scope))
+	}
+
+	if options.ReplicaRetryLimit != nil && *options.ReplicaRetryLimit < 0 {
+		problems = append(problems, fmt.Sprintf("%s: job.replicaRetryLimit must not be negative", scope))
+	}
+
+	if scale := options.Scale; scale != nil {
+		if scale.MinExecutions < 0 || scale.MaxExecutions < 0 || scale.PollingInterval < 0 {
+			problems = append(problems, fmt.Sprintf(
+				"%s: job.scale.minExecutions, maxExecutions and pollingInterval must not be negative", scope))
+		}
+
+		if scale.MaxExecutions > 0 && scale.MinExecutions > scale.MaxExecutions {
+			problems = append(problems, fmt.Sprintf(
+				"%s: job.scale.minExecutions must not be greater than job.scale.maxExecutions", scope))
+		}
+
+		for i, rule := range scale.Rules {
+			if rule.Name == "" || rule.Type == "" {
+				problems = append(problems, fmt.Sprintf(
+					"%s: job.scale.rules[%d] requires a name and a type", scope, i))
+			}
+		}
+	}
+
+	return problems
+}
+
+// jobExecutionPollInterval is how often the status of the execution started after a deployment is checked.
+var jobExecutionPollInterval = 10 * time.Second
+
+// runJobExecution starts an execution of a Container Apps job, and waits for it to succeed. It returns an error when
+// the execution fails or is stopped, or doesn't succeed before the execution timeout of the job options.
+func (at *containerAppTarget) runJobExecution(
+	ctx context.Context,
+	job *ContainerAppJobOptions,
+	targetResource *environment.TargetResource,
+	jobName string,
+	options *containerapps.ContainerAppOptions,
+) error {
+	executionName, err := at.containerAppService.StartContainerAppJobExecution(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		jobName,
+		options,
+	)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, job.executionTimeout())
+	defer cancel()
+
+	var lastStatus armappcontainers.JobExecutionRunningState
+	for {
+		execution, err := at.containerAppService.GetContainerAppJobExecution(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			jobName,
+			executionName,
+			options,
+		)
+		if err == nil && execution.Properties != nil && execution.Properties.Status != nil {
+			lastStatus = *execution.Properties.Status
+		}
+
+		switch lastStatus {
+		case armappcontainers.JobExecutionRunningStateSucceeded:
+			return nil
+		case armappcontainers.JobExecutionRunningStateFailed,
+			armappcontainers.JobExecutionRunningStateStopped,
+			armappcontainers.JobExecutionRunningStateDegraded:
+			return fmt.Errorf("execution %s of container app job %s: %s", executionName, jobName, lastStatus)
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastStatus == "" {
+				lastStatus = armappcontainers.JobExecutionRunningStateUnknown
+			}
+
+			return fmt.Errorf("execution %s of container app job %s didn't succeed after %s, last status: %s",
+				executionName, jobName, job.executionTimeout(), lastStatus)
+		case <-time.After(jobExecutionPollInterval):
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateContainerAppJobOptions(t *testing.T) {
+	scope := "service 'worker'"
+	require.Nil(t, validateContainerAppJobOptions(nil, ContainerAppTarget, scope))
+	require.Empty(t, validateContainerAppJobOptions(&ContainerAppJobOptions{
+		Trigger:        ContainerAppJobTriggerSchedule,
+		CronExpression: "*/5 * * * *",
+		Parallelism:    2,
+		StartExecution: true,
+	}, ContainerAppTarget, scope))
+	require.Empty(t, validateContainerAppJobOptions(&ContainerAppJobOptions{
+		Trigger: ContainerAppJobTriggerEvent,
+		Scale: &ContainerAppJobScaleOptions{
+			MaxExecutions: 10,
+			Rules:         []ContainerAppJobScaleRule{{Name: "queue", Type: "azure-queue"}},
+		},
+	}, ContainerAppTarget, scope))
+
+	problems := validateContainerAppJobOptions(&ContainerAppJobOptions{
+		Trigger:        ContainerAppJobTriggerSchedule,
+		CronExpression: "@hourly",
+		ReplicaTimeout: -1,
+	}, AppServiceTarget, scope)
+	require.Len(t, problems, 3)
+	require.Contains(t, problems[0], "only supported for services with host 'containerapp'")
+	require.Contains(t, problems[1], "cron expression with five fields")
+	require.Contains(t, problems[2], "replicaTimeout must be a positive number")
+
+	problems = validateContainerAppJobOptions(&ContainerAppJobOptions{
+		Trigger:        ContainerAppJobTriggerEvent,
+		CronExpression: "0 * * * *",
+	}, ContainerAppTarget, scope)
+	require.Len(t, problems, 2)
+	require.Contains(t, problems[0], "cronExpression is only supported by the 'schedule' trigger")
+	require.Contains(t, problems[1], "scale.rules is required by the 'event' trigger")
+
+	problems = validateContainerAppJobOptions(&ContainerAppJobOptions{
+		Parallelism: 2,
+		Scale: &ContainerAppJobScaleOptions{
+			MinExecutions: 5,
+			MaxExecutions: 1,
+			Rules:         []ContainerAppJobScaleRule{{Name: "queue"}},
+		},
+	}, ContainerAppTarget, scope)
+	require.Len(t, problems, 4)
+	require.Contains(t, problems[0], "require job.trigger")
+	require.Contains(t, problems[1], "scale is only supported by the 'event' trigger")
+	require.Contains(t, problems[2], "minExecutions must not be greater than")
+	require.Contains(t, problems[3], "rules[0] requires a name and a type")
+
+	problems = validateContainerAppJobOptions(&ContainerAppJobOptions{Trigger: "cron"}, ContainerAppTarget, scope)
+	require.Len(t, problems, 1)
+	require.Contains(t, problems[0], "job.trigger 'cron' is not supported")
+}
+
+func Test_ContainerAppJobOptions_Configuration(t *testing.T) {
+	var nilOptions *ContainerAppJobOptions
+	require.Nil(t, nilOptions.configuration())
+	require.Nil(t, (&ContainerAppJobOptions{StartExecution: true}).configuration())
+
+	configuration := (&ContainerAppJobOptions{
+		Trigger:           ContainerAppJobTriggerSchedule,
+		CronExpression:    "0 2 * * *",
+		Parallelism:       3,
+		ReplicaRetryLimit: to.Ptr(0),
+	}).configuration()
+	require.Equal(t, armappcontainers.TriggerTypeSchedule, *configuration.TriggerType)
+	require.Equal(t, "0 2 * * *", *configuration.ScheduleTriggerConfig.CronExpression)
+	require.Equal(t, int32(3), *configuration.ScheduleTriggerConfig.Parallelism)
+	require.Nil(t, configuration.ScheduleTriggerConfig.ReplicaCompletionCount)
+	require.Equal(t, int32(0), *configuration.ReplicaRetryLimit)
+	require.Nil(t, configuration.ReplicaTimeout)
+
+	configuration = (&ContainerAppJobOptions{
+		Trigger: ContainerAppJobTriggerEvent,
+		Scale: &ContainerAppJobScaleOptions{
+			PollingInterval: 30,
+			Rules: []ContainerAppJobScaleRule{{
+				Name:     "queue",
+				Type:     "azure-servicebus",
+				Metadata: map[string]string{"queueName": "orders"},
+				Auth:     []ContainerAppJobScaleRuleAuth{{SecretRef: "sb", TriggerParameter: "connection"}},
+			}},
+		},
+	}).configuration()
+	require.Equal(t, armappcontainers.TriggerTypeEvent, *configuration.TriggerType)
+	scale := configuration.EventTriggerConfig.Scale
+	require.Equal(t, int32(30), *scale.PollingInterval)
+	require.Len(t, scale.Rules, 1)
+	require.Equal(t, map[string]string{"queueName": "orders"}, scale.Rules[0].Metadata)
+	require.Equal(t, "connection", *scale.Rules[0].Auth[0].TriggerParameter)
+}
+
+// fakeJobContainerAppService records the job operations of a deployment.
+type fakeJobContainerAppService struct {
+	containerapps.ContainerAppService
+
+	statuses []armappcontainers.JobExecutionRunningState
+	started  bool
+}
+
+func (f *fakeJobContainerAppService) StartContainerAppJobExecution(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	jobName string,
+	options *containerapps.ContainerAppOptions,
+) (string, error) {
+	f.started = true
+	return jobName + "-abc123", nil
+}
+
+func (f *fakeJobContainerAppService) GetContainerAppJobExecution(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	jobName string,
+	executionName string,
+	options *containerapps.ContainerAppOptions,
+) (*armappcontainers.JobExecution, error) {
+	status := f.statuses[0]
+	if len(f.statuses) > 1 {
+		f.statuses = f.statuses[1:]
+	}
+
+	return &armappcontainers.JobExecution{
+		Name:       to.Ptr(executionName),
+		Properties: &armappcontainers.JobExecutionProperties{Status: to.Ptr(status)},
+	}, nil
+}
+
+func Test_ContainerApp_RunJobExecution(t *testing.T) {
+	pollInterval := jobExecutionPollInterval
+	jobExecutionPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { jobExecutionPollInterval = pollInterval })
+
+	run := func(t *testing.T, job *ContainerAppJobOptions, service *fakeJobContainerAppService) error {
+		target := &containerAppTarget{containerAppService: service}
+		targetResource := environment.NewTargetResource(
+			"SUBSCRIPTION_ID", "RESOURCE_GROUP", "worker", string(azapi.AzureResourceTypeContainerAppJob))
+
+		return target.runJobExecution(t.Context(), job, targetResource, "worker", &containerapps.ContainerAppOptions{})
+	}
+
+	t.Run("Succeeded", func(t *testing.T) {
+		service := &fakeJobContainerAppService{statuses: []armappcontainers.JobExecutionRunningState{
+			armappcontainers.JobExecutionRunningStateProcessing,
+			armappcontainers.JobExecutionRunningStateRunning,
+			armappcontainers.JobExecutionRunningStateSucceeded,
+		}}
+		require.NoError(t, run(t, &ContainerAppJobOptions{StartExecution: true}, service))
+		require.True(t, service.started)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		service := &fakeJobContainerAppService{statuses: []armappcontainers.JobExecutionRunningState{
+			armappcontainers.JobExecutionRunningStateRunning,
+			armappcontainers.JobExecutionRunningStateFailed,
+		}}
+		err := run(t, &ContainerAppJobOptions{StartExecution: true}, service)
+		require.ErrorContains(t, err, "execution worker-abc123 of container app job worker: Failed")
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		service := &fakeJobContainerAppService{statuses: []armappcontainers.JobExecutionRunningState{
+			armappcontainers.JobExecutionRunningStateRunning,
+		}}
+		err := run(t, &ContainerAppJobOptions{StartExecution: true, ExecutionTimeout: 1}, service)
+		require.ErrorContains(t, err, "didn't succeed after 1s, last status: Running")
+	})
+}
@@ -13,11 +13,11 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
-	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerregistry/armcontainerregistry"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/internal/appdetect"
@@ -39,6 +39,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/pack"
+	"github.com/azure/azure-dev/cli/azd/pkg/trivy"
 	"github.com/benbjohnson/clock"
 	"github.com/sethvargo/go-retry"
 	"go.opentelemetry.io/otel/attribute"
@@ -264,7 +265,16 @@ func (ch *ContainerHelper) RequiredExternalTools(ctx context.Context, serviceCon
 		return []tools.ExternalTool{ch.dotNetCli}
 	}
 
-	return []tools.ExternalTool{ch.docker}
+	requiredTools := []tools.ExternalTool{ch.docker}
+	if serviceConfig.Docker.Scan != nil {
+		requiredTools = append(requiredTools, trivy.NewCli(ch.commandRunner))
+	}
+
+	if serviceConfig.Docker.Sign != nil {
+		requiredTools = append(requiredTools, ch.imageSigningTool(serviceConfig.Docker.Sign))
+	}
+
+	return requiredTools
 }
 
 // Login logs into the container registry specified by AZURE_CONTAINER_REGISTRY_ENDPOINT in the environment. On success,
@@ -289,6 +299,43 @@ func (ch *ContainerHelper) Login(
 	return registryName, nil
 }
 
+// VerifyRemoteImage verifies that an image referenced by digest, such as the image of `azd deploy --image`, exists in
+// its registry, logging into the registry first when it's an Azure Container Registry.
+func (ch *ContainerHelper) VerifyRemoteImage(
+	ctx context.Context,
+	env *environment.Environment,
+	image string,
+	progress *async.Progress[ServiceProgress],
+) error {
+	name, _, ok := docker.SplitImageDigest(image)
+	if !ok {
+		return fmt.Errorf("image %s is not referenced by a sha256 digest", image)
+	}
+
+	parsedImage, err := docker.ParseContainerImage(name)
+	if err != nil {
+		return fmt.Errorf("parsing image %s: %w", image, err)
+	}
+
+	if strings.HasSuffix(parsedImage.Registry, ch.cloud.ContainerRegistryEndpointSuffix) {
+		progress.SetProgress(NewServiceProgress("Logging into container registry"))
+		if err := ch.containerRegistryService.Login(ctx, env.GetSubscriptionId(), parsedImage.Registry); err != nil {
+			return err
+		}
+	}
+
+	progress.SetProgress(NewServiceProgress("Verifying container image"))
+	if _, err := ch.docker.ManifestInspect(ctx, image); err != nil {
+		return &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("image %s was not found in registry %s: %w", image, parsedImage.Registry, err),
+			Suggestion: "Check the digest of the image. Log in to registries other than Azure Container Registry " +
+				"with 'docker login <registry>' first.",
+		}
+	}
+
+	return nil
+}
+
 var defaultCredentialsRetryInitialDelay = 2 * time.Second
 
 func (ch *ContainerHelper) Credentials(
@@ -411,7 +458,7 @@ func (ch *ContainerHelper) Build(
 		// 1. No Dockerfile path is specified, and
 		// 2. <service directory>/Dockerfile doesn't exist
 		progress.SetProgress(NewServiceProgress("Building Docker image from source"))
-		res, err := ch.packBuild(ctx, serviceConfig, dockerOptions, imageName)
+		res, err := ch.packBuild(ctx, serviceConfig, env, dockerOptions, imageName)
 		if err != nil {
 			return nil, err
 		}
@@ -655,6 +702,11 @@ func (ch *ContainerHelper) Publish(
 				ctx, serviceConfig, serviceContext, env, progress, imageOverride)
 		}
 	} else if useDotnetPublishForDockerBuild(serviceConfig) {
+		if serviceConfig.Docker.Scan != nil || serviceConfig.Docker.Sign != nil {
+			return nil, fmt.Errorf("docker.scan and docker.sign are not supported for images built with " +
+				"'dotnet publish', which pushes the image directly")
+		}
+
 		remoteImage, err = ch.runDotnetPublish(ctx, serviceConfig, targetResource, env, progress)
 	} else {
 		remoteImage, err = ch.publishLocalImage(ctx, serviceConfig, serviceContext, env, progress, imageOverride)
@@ -673,6 +725,16 @@ func (ch *ContainerHelper) Publish(
 		},
 	}
 
+	if serviceConfig.Docker.Sign != nil {
+		signature, err := ch.signImage(ctx, serviceConfig, env, remoteImage, progress)
+		if err != nil {
+			return nil, err
+		}
+
+		publishArtifact.Metadata[MetadataKeyImageDigest] = signature.Digest
+		publishArtifact.Metadata[MetadataKeySignature] = signature.Signature
+	}
+
 	return &ServicePublishResult{
 		Artifacts: ArtifactCollection{publishArtifact},
 	}, nil
@@ -749,6 +811,12 @@ func (ch *ContainerHelper) publishLocalImage(
 				return "", err
 			}
 
+			if serviceConfig.Docker.Scan != nil {
+				if err := ch.scanImage(ctx, serviceConfig, remoteImage, progress); err != nil {
+					return "", err
+				}
+			}
+
 			log.Printf("logging into container registry '%s'\n", registryName)
 			progress.SetProgress(NewServiceProgress("Logging into container registry"))
 
@@ -818,8 +886,9 @@ func (ch *ContainerHelper) runRemoteBuild(
 	dockerOptions := getDockerOptionsWithDefaults(serviceConfig.Docker)
 	resolveDockerPaths(serviceConfig, &dockerOptions)
 
-	if dockerOptions.Platform != "linux/amd64" {
-		return "", fmt.Errorf("remote build only supports the linux/amd64 platform")
+	platform, err := acrPlatform(dockerOptions.Platform)
+	if err != nil {
+		return "", err
 	}
 
 	resolvedBuildArgs, err := resolveDockerBuildArgs(dockerOptions.BuildArgs, env)
@@ -895,10 +964,7 @@ func (ch *ContainerHelper) runRemoteBuild(
 		DockerFilePath: &dockerPath,
 		IsPushEnabled:  new(true),
 		ImageNames:     []*string{new(imageName)},
-		Platform: &armcontainerregistry.PlatformProperties{
-			OS:           to.Ptr(armcontainerregistry.OSLinux),
-			Architecture: to.Ptr(armcontainerregistry.ArchitectureAmd64),
-		},
+		Platform:       platform,
 	}
 	if len(acrBuildArgs) > 0 {
 		buildRequest.Arguments = acrBuildArgs
@@ -920,6 +986,37 @@ func (ch *ContainerHelper) runRemoteBuild(
 	return imageName, nil
 }
 
+// acrPlatform converts a docker platform, such as 'linux/arm64' or 'linux/arm/v7', to the platform of an ACR run.
+func acrPlatform(platform string) (*armcontainerregistry.PlatformProperties, error) {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("invalid docker platform '%s', expected 'os/arch' or 'os/arch/variant'", platform)
+	}
+
+	// ACR spells the OS in title case, for example 'Linux'
+	osIndex := slices.IndexFunc(armcontainerregistry.PossibleOSValues(), func(value armcontainerregistry.OS) bool {
+		return strings.EqualFold(string(value), parts[0])
+	})
+	arch := armcontainerregistry.Architecture(parts[1])
+	if osIndex == -1 || !slices.Contains(armcontainerregistry.PossibleArchitectureValues(), arch) {
+		return nil, fmt.Errorf("remote build doesn't support the '%s' platform", platform)
+	}
+
+	runOS := armcontainerregistry.PossibleOSValues()[osIndex]
+
+	properties := &armcontainerregistry.PlatformProperties{OS: &runOS, Architecture: &arch}
+	if len(parts) == 3 {
+		variant := armcontainerregistry.Variant(parts[2])
+		if !slices.Contains(armcontainerregistry.PossibleVariantValues(), variant) {
+			return nil, fmt.Errorf("remote build doesn't support the '%s' platform", platform)
+		}
+
+		properties.Variant = &variant
+	}
+
+	return properties, nil
+}
+
 func dockerBuildArgsToAcrArguments(
 	buildArgs []string,
 	lookupEnv func(string) (string, bool),
@@ -1037,6 +1134,7 @@ const DefaultBuilderImage = "mcr.microsoft.com/oryx/builder:debian-bullseye-2024
 func (ch *ContainerHelper) packBuild(
 	ctx context.Context,
 	svc *ServiceConfig,
+	env *environment.Environment,
 	dockerOptions DockerProjectOptions,
 	imageName string) (*ServiceBuildResult, error) {
 	packCli := pack.NewCli(ch.console, ch.commandRunner)
@@ -1054,6 +1152,21 @@ func (ch *ContainerHelper) packBuild(
 		userDefinedImage = true
 	}
 
+	buildpack := dockerOptions.Buildpack
+	if buildpack != nil && !buildpack.Builder.Empty() {
+		builder, err = buildpack.Builder.Envsubst(env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("resolving buildpack builder: %w", err)
+		}
+
+		userDefinedImage = true
+	}
+
+	buildpackEnv, err := resolveBuildpackEnv(buildpack, env)
+	if err != nil {
+		return nil, err
+	}
+
 	svcPath := svc.Path()
 	buildContext := svcPath
 
@@ -1111,6 +1224,14 @@ func (ch *ContainerHelper) packBuild(
 		}
 	}
 
+	// Variables of the service are passed last, so they override the defaults above
+	environ = append(environ, buildpackEnv...)
+
+	cacheVolume := ""
+	if buildpack != nil {
+		cacheVolume = buildpack.CacheVolume
+	}
+
 	previewer := ch.console.ShowPreviewer(ctx,
 		&input.ShowPreviewerOptions{
 			Prefix:       "  ",
@@ -1142,6 +1263,7 @@ func (ch *ContainerHelper) packBuild(
 		builder,
 		imageName,
 		environ,
+		cacheVolume,
 		previewer)
 	ch.console.StopPreviewer(ctx, false)
 	if err != nil {
@@ -23,6 +23,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
@@ -449,6 +450,32 @@ func Test_DockerBuildArgsToAcrArguments_EmptyName(t *testing.T) {
 	require.ErrorContains(t, err, "empty name")
 }
 
+func Test_AcrPlatform(t *testing.T) {
+	platform, err := acrPlatform("linux/amd64")
+	require.NoError(t, err)
+	require.Equal(t, armcontainerregistry.OSLinux, *platform.OS)
+	require.Equal(t, armcontainerregistry.ArchitectureAmd64, *platform.Architecture)
+	require.Nil(t, platform.Variant)
+
+	platform, err = acrPlatform("linux/arm/v7")
+	require.NoError(t, err)
+	require.Equal(t, armcontainerregistry.ArchitectureArm, *platform.Architecture)
+	require.Equal(t, armcontainerregistry.VariantV7, *platform.Variant)
+
+	platform, err = acrPlatform("windows/amd64")
+	require.NoError(t, err)
+	require.Equal(t, armcontainerregistry.OSWindows, *platform.OS)
+
+	_, err = acrPlatform("linux")
+	require.ErrorContains(t, err, "invalid docker platform 'linux'")
+
+	_, err = acrPlatform("linux/s390x")
+	require.ErrorContains(t, err, "remote build doesn't support the 'linux/s390x' platform")
+
+	_, err = acrPlatform("linux/arm/v5")
+	require.ErrorContains(t, err, "remote build doesn't support the 'linux/arm/v5' platform")
+}
+
 func Test_DockerBuildArgEnvResolver(t *testing.T) {
 	t.Setenv("FROM_OS", "os-value")
 	t.Setenv("OVERRIDDEN", "os-value")
@@ -562,6 +589,7 @@ func Test_ContainerHelper_RunRemoteBuild_PassesBuildArgs(t *testing.T) {
 	serviceConfig.Project.Path = projectRoot
 	serviceConfig.Docker.Registry = osutil.NewExpandableString("contoso.azurecr.io")
 	serviceConfig.Docker.RemoteBuild = true
+	serviceConfig.Docker.Platform = "linux/arm64"
 	serviceConfig.Docker.BuildArgs = []osutil.ExpandableString{
 		osutil.NewExpandableString("EXPLICIT=value"),
 		osutil.NewExpandableString("MULTI=a=b"),
@@ -596,8 +624,14 @@ func Test_ContainerHelper_RunRemoteBuild_PassesBuildArgs(t *testing.T) {
 			Value    string `json:"value"`
 			IsSecret bool   `json:"isSecret"`
 		} `json:"arguments"`
+		Platform struct {
+			OS           string `json:"os"`
+			Architecture string `json:"architecture"`
+		} `json:"platform"`
 	}
 	require.NoError(t, json.Unmarshal(scheduleRunBody, &requestBody))
+	require.Equal(t, "Linux", requestBody.Platform.OS)
+	require.Equal(t, "arm64", requestBody.Platform.Architecture)
 	require.Equal(t, map[string]string{
 		"EXPLICIT":       "value",
 		"MULTI":          "a=b",
@@ -961,6 +995,72 @@ func Test_ContainerHelper_Deploy(t *testing.T) {
 	}
 }
 
+func Test_ContainerHelper_VerifyRemoteImage(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("ab", 32)
+
+	verify := func(t *testing.T, image string, manifestErr error) (*mockContainerRegistryService, bool, error) {
+		mockContext := mocks.NewMockContext(t.Context())
+		env := environment.NewWithValues("dev", map[string]string{})
+
+		inspected := false
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "docker manifest inspect")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			inspected = true
+			require.Equal(t, []string{"manifest", "inspect", image}, args.Args)
+			if manifestErr != nil {
+				return exec.NewRunResult(1, "", "manifest unknown"), manifestErr
+			}
+			return exec.NewRunResult(0, "{}", ""), nil
+		})
+
+		mockContainerRegistryService := &mockContainerRegistryService{}
+		setupContainerRegistryMocks(mockContext, &mockContainerRegistryService.Mock)
+
+		containerHelper := NewContainerHelper(
+			clock.NewMock(),
+			mockContainerRegistryService,
+			nil,
+			mockContext.CommandRunner,
+			docker.NewCli(mockContext.CommandRunner),
+			dotnet.NewCli(mockContext.CommandRunner),
+			mockContext.Console,
+			cloud.AzurePublic(),
+		)
+
+		err := containerHelper.VerifyRemoteImage(
+			*mockContext.Context, env, image, async.NewNoopProgress[ServiceProgress]())
+		return mockContainerRegistryService, inspected, err
+	}
+
+	t.Run("AzureContainerRegistry", func(t *testing.T) {
+		registryService, inspected, err := verify(t, "myregistry.azurecr.io/api@"+digest, nil)
+		require.NoError(t, err)
+		require.True(t, inspected)
+		registryService.AssertCalled(t, "Login", mock.Anything, mock.Anything, "myregistry.azurecr.io")
+	})
+
+	t.Run("OtherRegistry", func(t *testing.T) {
+		registryService, inspected, err := verify(t, "ghcr.io/contoso/api@"+digest, nil)
+		require.NoError(t, err)
+		require.True(t, inspected)
+		registryService.AssertNotCalled(t, "Login")
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, _, err := verify(t, "myregistry.azurecr.io/api@"+digest, errors.New("exit code: 1"))
+		var errWithSuggestion *internal.ErrorWithSuggestion
+		require.ErrorAs(t, err, &errWithSuggestion)
+		require.ErrorContains(t, err, "was not found in registry myregistry.azurecr.io")
+	})
+
+	t.Run("NotByDigest", func(t *testing.T) {
+		_, inspected, err := verify(t, "myregistry.azurecr.io/api:latest", nil)
+		require.ErrorContains(t, err, "is not referenced by a sha256 digest")
+		require.False(t, inspected)
+	})
+}
+
 func Test_ContainerHelper_ConfiguredImage(t *testing.T) {
 	mockContext := mocks.NewMockContext(t.Context())
 	env := environment.NewWithValues("dev", map[string]string{})
@@ -0,0 +1,208 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+)
+
+const (
+	// DeployedPackageProperty is the service property of the environment that records the package of the last
+	// successful deployment of a service, as a container image or the path of a saved package archive.
+	DeployedPackageProperty = "DEPLOYED_PACKAGE"
+
+	// PreviousPackageProperty is the service property of the environment that records the package deployed before
+	// the last successful deployment of a service, which `azd deploy --rollback` deploys again.
+	PreviousPackageProperty = "PREVIOUS_PACKAGE"
+)
+
+// DeploymentHistory records the packages deployed to each service, so that a service can be rolled back to the
+// package it ran before its last deployment.
+//
+// Container images pushed to a registry are recorded by reference. Package archives are temporary, so a copy of each
+// recorded archive is saved in the history directory, and removed once it is no longer the deployed or previous package.
+type DeploymentHistory struct {
+	env *environment.Environment
+	dir string
+}
+
+// NewDeploymentHistory creates a DeploymentHistory that records the packages in env, and saves the package archives in
+// the deployments directory of envRoot, the directory of the environment in the .azure folder.
+func NewDeploymentHistory(env *environment.Environment, envRoot string) *DeploymentHistory {
+	return &DeploymentHistory{
+		env: env,
+		dir: filepath.Join(envRoot, "deployments"),
+	}
+}
+
+// Record records the package of a successful deployment of a service, and makes the package deployed before it the
+// previous package. When the package can't be deployed again, for example a static web app built remotely, the
+// history of the service is cleared.
+func (h *DeploymentHistory) Record(serviceConfig *ServiceConfig, serviceContext *ServiceContext) error {
+	pkg, err := h.savePackage(serviceConfig, serviceContext)
+	if err != nil {
+		return err
+	}
+
+	current := h.env.GetServiceProperty(serviceConfig.Name, DeployedPackageProperty)
+	switch {
+	case pkg == "":
+		h.deleteProperty(serviceConfig.Name, DeployedPackageProperty)
+		h.deleteProperty(serviceConfig.Name, PreviousPackageProperty)
+	case pkg != current:
+		if current == "" {
+			h.deleteProperty(serviceConfig.Name, PreviousPackageProperty)
+		} else {
+			h.env.SetServiceProperty(serviceConfig.Name, PreviousPackageProperty, current)
+		}
+		h.env.SetServiceProperty(serviceConfig.Name, DeployedPackageProperty, pkg)
+	}
+
+	return h.prune(serviceConfig.Name)
+}
+
+// Previous returns the package deployed to a service before its last deployment, or an empty string when there is
+// none.
+func (h *DeploymentHistory) Previous(serviceName string) string {
+	return h.env.GetServiceProperty(serviceName, PreviousPackageProperty)
+}
+
+// Clear removes the history of a service, including its saved package archives.
+func (h *DeploymentHistory) Clear(serviceName string) error {
+	h.deleteProperty(serviceName, DeployedPackageProperty)
+	h.deleteProperty(serviceName, PreviousPackageProperty)
+
+	if err := os.RemoveAll(h.serviceDir(serviceName)); err != nil {
+		return fmt.Errorf("removing saved packages of service %s: %w", serviceName, err)
+	}
+
+	return nil
+}
+
+// savePackage returns the reference of the deployed package that can be deployed again with `azd deploy
+// --from-package`, saving a copy of the package archive when needed. An empty reference is returned when there is none.
+func (h *DeploymentHistory) savePackage(serviceConfig *ServiceConfig, serviceContext *ServiceContext) (string, error) {
+	// Container apps and app services deploy an image that is already in a registry without pushing it again.
+	if serviceConfig.Host == ContainerAppTarget || serviceConfig.Host == AppServiceTarget {
+		image, found := serviceContext.Publish.FindFirst(
+			WithKind(ArtifactKindContainer), WithLocationKind(LocationKindRemote))
+		if found && image.Location != "" {
+			return image.Location, nil
+		}
+	}
+
+	archive, found := serviceContext.Package.FindFirst(
+		WithKind(ArtifactKindArchive), WithLocationKind(LocationKindLocal))
+	if !found || archive.Location == "" {
+		return "", nil
+	}
+
+	serviceDir := h.serviceDir(serviceConfig.Name)
+	if filepath.Dir(archive.Location) == serviceDir {
+		// The service was deployed from a saved package, such as with `azd deploy --rollback`.
+		return archive.Location, nil
+	}
+
+	hash, err := fileHash(archive.Location)
+	if err != nil {
+		return "", fmt.Errorf("hashing package %s: %w", archive.Location, err)
+	}
+
+	// Packages with the same content are saved once.
+	saved := filepath.Join(serviceDir, hash[:16]+"-"+filepath.Base(archive.Location))
+	if _, err := os.Stat(saved); err == nil {
+		return saved, nil
+	}
+
+	if err := os.MkdirAll(serviceDir, 0755); err != nil {
+		return "", fmt.Errorf("creating directory for saved packages: %w", err)
+	}
+
+	if err := copyFile(archive.Location, saved); err != nil {
+		return "", fmt.Errorf("saving package %s: %w", archive.Location, err)
+	}
+
+	return saved, nil
+}
+
+// prune removes the saved package archives of a service that are neither the deployed nor the previous package.
+func (h *DeploymentHistory) prune(serviceName string) error {
+	serviceDir := h.serviceDir(serviceName)
+	entries, err := os.ReadDir(serviceDir)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("reading saved packages of service %s: %w", serviceName, err)
+	}
+
+	keep := []string{
+		h.env.GetServiceProperty(serviceName, DeployedPackageProperty),
+		h.env.GetServiceProperty(serviceName, PreviousPackageProperty),
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(serviceDir, entry.Name())
+		if slices.Contains(keep, path) {
+			continue
+		}
+
+		if err := os.RemoveAll(path); err != nil {
+			return fmt.Errorf("removing saved package %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+func (h *DeploymentHistory) serviceDir(serviceName string) string {
+	return filepath.Join(h.dir, serviceName)
+}
+
+func (h *DeploymentHistory) deleteProperty(serviceName string, propertyName string) {
+	h.env.DotenvDelete(fmt.Sprintf("SERVICE_%s_%s", environment.Key(serviceName), propertyName))
+}
+
+// fileHash returns the hex-encoded SHA-256 hash of the content of a file.
+func fileHash(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func copyFile(src string, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,103 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DeploymentHistory(t *testing.T) {
+	archiveContext := func(t *testing.T, content string) *ServiceContext {
+		path := filepath.Join(t.TempDir(), "api.zip")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+		sc := NewServiceContext()
+		require.NoError(t, sc.Package.Add(&Artifact{
+			Kind:         ArtifactKindArchive,
+			Location:     path,
+			LocationKind: LocationKindLocal,
+		}))
+		return sc
+	}
+
+	imageContext := func(t *testing.T, image string) *ServiceContext {
+		sc := NewServiceContext()
+		require.NoError(t, sc.Publish.Add(&Artifact{
+			Kind:         ArtifactKindContainer,
+			Location:     image,
+			LocationKind: LocationKindRemote,
+		}))
+		return sc
+	}
+
+	t.Run("Archive", func(t *testing.T) {
+		env := environment.New("test")
+		history := NewDeploymentHistory(env, t.TempDir())
+		serviceConfig := &ServiceConfig{Name: "api", Host: AzureFunctionTarget}
+
+		require.NoError(t, history.Record(serviceConfig, archiveContext(t, "v1")))
+		require.Empty(t, history.Previous("api"))
+		v1 := env.GetServiceProperty("api", DeployedPackageProperty)
+
+		// Redeploying the same package keeps the previous package.
+		require.NoError(t, history.Record(serviceConfig, archiveContext(t, "v1")))
+		require.Equal(t, v1, env.GetServiceProperty("api", DeployedPackageProperty))
+		require.Empty(t, history.Previous("api"))
+
+		require.NoError(t, history.Record(serviceConfig, archiveContext(t, "v2")))
+		require.Equal(t, v1, history.Previous("api"))
+		v2 := env.GetServiceProperty("api", DeployedPackageProperty)
+
+		content, err := os.ReadFile(v1)
+		require.NoError(t, err)
+		require.Equal(t, "v1", string(content))
+
+		// Only the deployed and previous packages are kept.
+		require.NoError(t, history.Record(serviceConfig, archiveContext(t, "v3")))
+		require.Equal(t, v2, history.Previous("api"))
+		require.NoFileExists(t, v1)
+
+		require.NoError(t, history.Clear("api"))
+		require.Empty(t, history.Previous("api"))
+		require.Empty(t, env.GetServiceProperty("api", DeployedPackageProperty))
+		require.NoFileExists(t, v2)
+	})
+
+	t.Run("ContainerImage", func(t *testing.T) {
+		env := environment.New("test")
+		history := NewDeploymentHistory(env, t.TempDir())
+		serviceConfig := &ServiceConfig{Name: "api", Host: ContainerAppTarget}
+
+		require.NoError(t, history.Record(serviceConfig, imageContext(t, "contoso.azurecr.io/api:azd-deploy-1")))
+		require.NoError(t, history.Record(serviceConfig, imageContext(t, "contoso.azurecr.io/api:azd-deploy-2")))
+		require.Equal(t, "contoso.azurecr.io/api:azd-deploy-1", history.Previous("api"))
+		require.Equal(t, "contoso.azurecr.io/api:azd-deploy-2", env.GetServiceProperty("api", DeployedPackageProperty))
+	})
+
+	t.Run("NotRedeployable", func(t *testing.T) {
+		env := environment.New("test")
+		history := NewDeploymentHistory(env, t.TempDir())
+		serviceConfig := &ServiceConfig{Name: "web", Host: StaticWebAppTarget}
+
+		require.NoError(t, history.Record(serviceConfig, archiveContext(t, "v1")))
+		require.NoError(t, history.Record(serviceConfig, archiveContext(t, "v2")))
+		require.NotEmpty(t, history.Previous("web"))
+
+		// A package directory isn't saved, so the history no longer describes the deployed service.
+		sc := NewServiceContext()
+		require.NoError(t, sc.Package.Add(&Artifact{
+			Kind:         ArtifactKindDirectory,
+			Location:     t.TempDir(),
+			LocationKind: LocationKindLocal,
+		}))
+		require.NoError(t, history.Record(serviceConfig, sc))
+		require.Empty(t, history.Previous("web"))
+		require.Empty(t, env.GetServiceProperty("web", DeployedPackageProperty))
+	})
+}
@@ -0,0 +1,125 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DeploymentStrategyKind is the way a new version of a service is rolled out.
+type DeploymentStrategyKind string
+
+const (
+	// DeploymentStrategyCanary routes part of the traffic to the new revision, and promotes it once it is healthy.
+	DeploymentStrategyCanary DeploymentStrategyKind = "canary"
+	// DeploymentStrategyBlueGreen creates the new revision without traffic, and switches all the traffic to it once it
+	// is healthy.
+	DeploymentStrategyBlueGreen DeploymentStrategyKind = "blueGreen"
+)
+
+const (
+	defaultCanaryTrafficPercent = 10
+	defaultHealthTimeout        = 5 * time.Minute
+)
+
+// DeploymentStrategyOptions configures how a new revision of a container app service is rolled out: the revision
+// is created next to the revision that serves the traffic, checked for health, and then promoted or rolled back.
+type DeploymentStrategyOptions struct {
+	// Strategy is the deployment strategy, canary or blueGreen.
+	Strategy DeploymentStrategyKind `yaml:"strategy"`
+	// TrafficPercent is the percentage of the traffic routed to the new revision while its health is checked, for the
+	// canary strategy. Defaults to 10.
+	TrafficPercent int `yaml:"trafficPercent,omitempty"`
+	// HealthPath is the HTTP path of the new revision that must respond with a success status before the revision is
+	// promoted. When empty, only the health state reported by the revision is checked.
+	HealthPath string `yaml:"healthPath,omitempty"`
+	// HealthTimeout is how long, in seconds, the new revision has to become healthy before it is rolled back.
+	// Defaults to 300.
+	HealthTimeout int `yaml:"healthTimeout,omitempty"`
+}
+
+// trafficPercent returns the percentage of the traffic routed to the new revision before it is promoted.
+func (o *DeploymentStrategyOptions) trafficPercent() int32 {
+	if o.Strategy == DeploymentStrategyBlueGreen {
+		return 0
+	}
+
+	if o.TrafficPercent == 0 {
+		return defaultCanaryTrafficPercent
+	}
+
+	return int32(o.TrafficPercent)
+}
+
+// healthTimeout returns how long the new revision has to become healthy.
+func (o *DeploymentStrategyOptions) healthTimeout() time.Duration {
+	if o.HealthTimeout == 0 {
+		return defaultHealthTimeout
+	}
+
+	return time.Duration(o.HealthTimeout) * time.Second
+}
+
+// validateDeploymentStrategyOptions returns the problems of the deployment strategy of a service.
+func validateDeploymentStrategyOptions(options *DeploymentStrategyOptions, host ServiceTargetKind, scope string) []string {
+	if options == nil {
+		return nil
+	}
+
+	var problems []string
+	if host != ContainerAppTarget {
+		problems = append(problems, fmt.Sprintf(
+			"%s: deployment strategies are only supported for services with host '%s'", scope, ContainerAppTarget))
+	}
+
+	switch options.Strategy {
+	case DeploymentStrategyCanary:
+		if options.TrafficPercent < 0 || options.TrafficPercent >= 100 {
+			problems = append(problems, fmt.Sprintf(
+				"%s: rollout.trafficPercent must be between 1 and 99, got %d", scope, options.TrafficPercent))
+		}
+	case DeploymentStrategyBlueGreen:
+		if options.TrafficPercent != 0 {
+			problems = append(problems, fmt.Sprintf(
+				"%s: rollout.trafficPercent is only supported by the '%s' strategy", scope, DeploymentStrategyCanary))
+		}
+	default:
+		problems = append(problems, fmt.Sprintf(
+			"%s: rollout.strategy '%s' is not supported, supported strategies: %s, %s",
+			scope, options.Strategy, DeploymentStrategyCanary, DeploymentStrategyBlueGreen))
+	}
+
+	if options.HealthPath != "" && !strings.HasPrefix(options.HealthPath, "/") {
+		problems = append(problems, fmt.Sprintf("%s: rollout.healthPath must start with '/'", scope))
+	}
+
+	if options.HealthTimeout < 0 {
+		problems = append(problems, fmt.Sprintf("%s: rollout.healthTimeout must be a positive number of seconds", scope))
+	}
+
+	return problems
+}
+
+// checkHealthPath sends a request to the health path of a deployed app, and returns an error unless it responds with a
+// success status. When client is nil, a default client is used.
+func checkHealthPath(ctx context.Context, client *http.Client, url string) error {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	status, err := probeStatus(ctx, client, url)
+	if err != nil {
+		return fmt.Errorf("checking %s: %w", url, err)
+	}
+
+	if status < 200 || status >= 400 {
+		return fmt.Errorf("checking %s: status %d", url, status)
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateDeploymentStrategyOptions(t *testing.T) {
+	scope := "service 'api'"
+	require.Nil(t, validateDeploymentStrategyOptions(nil, ContainerAppTarget, scope))
+	require.Empty(t, validateDeploymentStrategyOptions(&DeploymentStrategyOptions{
+		Strategy:       DeploymentStrategyCanary,
+		TrafficPercent: 20,
+		HealthPath:     "/health",
+		HealthTimeout:  120,
+	}, ContainerAppTarget, scope))
+	require.Empty(t, validateDeploymentStrategyOptions(&DeploymentStrategyOptions{
+		Strategy: DeploymentStrategyBlueGreen,
+	}, ContainerAppTarget, scope))
+
+	problems := validateDeploymentStrategyOptions(&DeploymentStrategyOptions{
+		Strategy:       DeploymentStrategyCanary,
+		TrafficPercent: 100,
+		HealthPath:     "health",
+		HealthTimeout:  -1,
+	}, AppServiceTarget, scope)
+	require.Len(t, problems, 4)
+	require.Contains(t, problems[0], "only supported for services with host 'containerapp'")
+	require.Contains(t, problems[1], "trafficPercent must be between 1 and 99")
+	require.Contains(t, problems[2], "healthPath must start with '/'")
+	require.Contains(t, problems[3], "healthTimeout must be a positive number")
+
+	problems = validateDeploymentStrategyOptions(&DeploymentStrategyOptions{
+		Strategy:       DeploymentStrategyBlueGreen,
+		TrafficPercent: 10,
+	}, ContainerAppTarget, scope)
+	require.Len(t, problems, 1)
+	require.Contains(t, problems[0], "only supported by the 'canary' strategy")
+
+	problems = validateDeploymentStrategyOptions(&DeploymentStrategyOptions{
+		Strategy: "rolling",
+	}, ContainerAppTarget, scope)
+	require.Len(t, problems, 1)
+	require.Contains(t, problems[0], "rollout.strategy 'rolling' is not supported")
+}
+
+// fakeStrategyContainerAppService records the revision operations of a deployment strategy.
+type fakeStrategyContainerAppService struct {
+	containerapps.ContainerAppService
+
+	revision       *armappcontainers.Revision
+	trafficPercent int32
+	promoted       bool
+	rolledBack     bool
+}
+
+func (f *fakeStrategyContainerAppService) StageRevision(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	imageName string,
+	envVars map[string]string,
+	settings *containerapps.AppSettings,
+	trafficPercent int32,
+	options *containerapps.ContainerAppOptions,
+) (*containerapps.StagedRevision, error) {
+	f.trafficPercent = trafficPercent
+	return &containerapps.StagedRevision{Name: appName + "--azd-1"}, nil
+}
+
+func (f *fakeStrategyContainerAppService) GetRevision(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	revisionName string,
+	options *containerapps.ContainerAppOptions,
+) (*armappcontainers.Revision, error) {
+	return f.revision, nil
+}
+
+func (f *fakeStrategyContainerAppService) PromoteRevision(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	revision *containerapps.StagedRevision,
+	options *containerapps.ContainerAppOptions,
+) error {
+	f.promoted = true
+	return nil
+}
+
+func (f *fakeStrategyContainerAppService) RollbackRevision(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	revision *containerapps.StagedRevision,
+	options *containerapps.ContainerAppOptions,
+) error {
+	f.rolledBack = true
+	return nil
+}
+
+func Test_ContainerApp_DeployWithStrategy(t *testing.T) {
+	healthyRevision := func(fqdn string) *armappcontainers.Revision {
+		return &armappcontainers.Revision{
+			Properties: &armappcontainers.RevisionProperties{
+				ProvisioningState: to.Ptr(armappcontainers.RevisionProvisioningStateProvisioned),
+				RunningState:      to.Ptr(armappcontainers.RevisionRunningStateRunning),
+				HealthState:       to.Ptr(armappcontainers.RevisionHealthStateHealthy),
+				Fqdn:              to.Ptr(fqdn),
+			},
+		}
+	}
+
+	deploy := func(
+		t *testing.T,
+		strategy *DeploymentStrategyOptions,
+		service *fakeStrategyContainerAppService,
+		httpClient *http.Client,
+	) error {
+		target := &containerAppTarget{containerAppService: service, httpClient: httpClient}
+		targetResource := environment.NewTargetResource(
+			"SUBSCRIPTION_ID", "RESOURCE_GROUP", "api", string(azapi.AzureResourceTypeContainerApp))
+
+		progress := async.NewProgress[ServiceProgress]()
+		go func() {
+			for range progress.Progress() {
+			}
+		}()
+		defer progress.Done()
+
+		return target.deployWithStrategy(
+			t.Context(), strategy, targetResource, "api", "IMAGE_NAME", nil, nil, &containerapps.ContainerAppOptions{}, progress)
+	}
+
+	t.Run("Canary", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/health", r.URL.Path)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		service := &fakeStrategyContainerAppService{
+			revision: healthyRevision(strings.TrimPrefix(server.URL, "https://")),
+		}
+		err := deploy(t, &DeploymentStrategyOptions{
+			Strategy:   DeploymentStrategyCanary,
+			HealthPath: "/health",
+		}, service, server.Client())
+		require.NoError(t, err)
+		require.Equal(t, int32(defaultCanaryTrafficPercent), service.trafficPercent)
+		require.True(t, service.promoted)
+		require.False(t, service.rolledBack)
+	})
+
+	t.Run("FailedRevision", func(t *testing.T) {
+		service := &fakeStrategyContainerAppService{
+			revision: &armappcontainers.Revision{
+				Properties: &armappcontainers.RevisionProperties{
+					ProvisioningState: to.Ptr(armappcontainers.RevisionProvisioningStateProvisioned),
+					RunningState:      to.Ptr(armappcontainers.RevisionRunningStateFailed),
+				},
+			},
+		}
+		err := deploy(t, &DeploymentStrategyOptions{Strategy: DeploymentStrategyBlueGreen}, service, nil)
+		require.ErrorContains(t, err, "is unhealthy and was rolled back")
+		require.Equal(t, int32(0), service.trafficPercent)
+		require.False(t, service.promoted)
+		require.True(t, service.rolledBack)
+	})
+
+	t.Run("HealthPathTimeout", func(t *testing.T) {
+		pollInterval := revisionHealthPollInterval
+		revisionHealthPollInterval = 10 * time.Millisecond
+		t.Cleanup(func() { revisionHealthPollInterval = pollInterval })
+
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		service := &fakeStrategyContainerAppService{
+			revision: healthyRevision(strings.TrimPrefix(server.URL, "https://")),
+		}
+		err := deploy(t, &DeploymentStrategyOptions{
+			Strategy:      DeploymentStrategyCanary,
+			HealthPath:    "/health",
+			HealthTimeout: 1,
+		}, service, server.Client())
+		require.ErrorContains(t, err, "status 503")
+		require.False(t, service.promoted)
+		require.True(t, service.rolledBack)
+	})
+}
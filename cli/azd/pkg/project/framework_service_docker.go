@@ -29,6 +29,9 @@ type DockerProjectOptions struct {
 	RemoteBuild bool                      `yaml:"remoteBuild,omitempty"  json:"remoteBuild,omitempty"`
 	Network     string                    `yaml:"network,omitempty"     json:"network,omitempty"`
 	BuildArgs   []osutil.ExpandableString `yaml:"buildArgs,omitempty"   json:"buildArgs,omitempty"`
+	Buildpack   *BuildpackOptions         `yaml:"buildpack,omitempty"   json:"buildpack,omitempty"`
+	Scan        *ImageScanOptions         `yaml:"scan,omitempty"        json:"scan,omitempty"`
+	Sign        *ImageSigningOptions      `yaml:"sign,omitempty"        json:"sign,omitempty"`
 	// not supported from azure.yaml directly yet. Adding it for Aspire to use it, initially.
 	// Aspire would pass the secret keys, which are env vars that azd will set just to run docker build.
 	BuildSecrets []string `yaml:"-"                     json:"-"`
@@ -0,0 +1,264 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// FunctionAppOptions configures the deployment of a function app on a Flex Consumption plan.
+type FunctionAppOptions struct {
+	// DeploymentStorage is the blob container the app reads its deployment package from. When set, it's configured on
+	// the app before the package is deployed. When empty, the app must already have deployment storage configured.
+	DeploymentStorage *FunctionAppDeploymentStorageOptions `yaml:"deploymentStorage,omitempty"`
+	// HostTimeout is how long, in seconds, the function host has to be running after the package is deployed.
+	// Defaults to 300.
+	HostTimeout int `yaml:"hostTimeout,omitempty"`
+}
+
+// FunctionAppDeploymentStorageOptions is the deployment storage of a Flex Consumption app.
+type FunctionAppDeploymentStorageOptions struct {
+	// Container is the URL of the blob container, for example
+	// 'https://<account>.blob.core.windows.net/deployments'.
+	Container osutil.ExpandableString `yaml:"container"`
+	// Authentication is how the app authenticates to the storage: 'systemAssignedIdentity' (the default),
+	// 'userAssignedIdentity' or 'storageAccountConnectionString'.
+	Authentication string `yaml:"authentication,omitempty"`
+	// Identity is the resource ID of the user-assigned identity, when authenticating with 'userAssignedIdentity'.
+	Identity osutil.ExpandableString `yaml:"identity,omitempty"`
+	// ConnectionStringSetting is the name of the app setting holding the connection string of the storage account,
+	// when authenticating with 'storageAccountConnectionString'.
+	ConnectionStringSetting string `yaml:"connectionStringSetting,omitempty"`
+}
+
+// The authentication types of the deployment storage, as written in azure.yaml
+const (
+	storageAuthenticationSystemAssignedIdentity         = "systemAssignedIdentity"
+	storageAuthenticationUserAssignedIdentity           = "userAssignedIdentity"
+	storageAuthenticationStorageAccountConnectionString = "storageAccountConnectionString"
+)
+
+// hostTimeout returns how long the function host has to be running after the package is deployed.
+func (o *FunctionAppOptions) hostTimeout() time.Duration {
+	if o == nil || o.HostTimeout == 0 {
+		return defaultHealthTimeout
+	}
+
+	return time.Duration(o.HostTimeout) * time.Second
+}
+
+// validateFunctionAppOptions returns the problems of the function app options of a service.
+func validateFunctionAppOptions(options *FunctionAppOptions, host ServiceTargetKind, scope string) []string {
+	if options == nil {
+		return nil
+	}
+
+	var problems []string
+	if host != AzureFunctionTarget {
+		problems = append(problems, fmt.Sprintf(
+			"%s: functionApp is only supported for services with host '%s'", scope, AzureFunctionTarget))
+	}
+
+	if options.HostTimeout < 0 {
+		problems = append(problems, fmt.Sprintf(
+			"%s: functionApp.hostTimeout must be a positive number of seconds", scope))
+	}
+
+	storage := options.DeploymentStorage
+	if storage == nil {
+		return problems
+	}
+
+	if storage.Container.Empty() {
+		problems = append(problems, fmt.Sprintf("%s: functionApp.deploymentStorage.container is required", scope))
+	}
+
+	switch storage.Authentication {
+	case "", storageAuthenticationSystemAssignedIdentity:
+	case storageAuthenticationUserAssignedIdentity:
+		if storage.Identity.Empty() {
+			problems = append(problems, fmt.Sprintf(
+				"%s: functionApp.deploymentStorage.identity is required with authentication '%s'",
+				scope, storageAuthenticationUserAssignedIdentity))
+		}
+	case storageAuthenticationStorageAccountConnectionString:
+		if storage.ConnectionStringSetting == "" {
+			problems = append(problems, fmt.Sprintf(
+				"%s: functionApp.deploymentStorage.connectionStringSetting is required with authentication '%s'",
+				scope, storageAuthenticationStorageAccountConnectionString))
+		}
+	default:
+		problems = append(problems, fmt.Sprintf(
+			"%s: functionApp.deploymentStorage.authentication must be one of '%s', '%s' or '%s', got '%s'",
+			scope,
+			storageAuthenticationSystemAssignedIdentity,
+			storageAuthenticationUserAssignedIdentity,
+			storageAuthenticationStorageAccountConnectionString,
+			storage.Authentication,
+		))
+	}
+
+	return problems
+}
+
+// functionHostPollInterval is how often the function host is checked while waiting for it to be running.
+var functionHostPollInterval = 5 * time.Second
+
+// configureDeploymentStorage sets the deployment storage of a Flex Consumption app from the service configuration, or
+// checks that the app already has deployment storage when the service doesn't configure it.
+func (f *functionAppTarget) configureDeploymentStorage(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	progress *async.Progress[ServiceProgress],
+) error {
+	var options *FunctionAppDeploymentStorageOptions
+	if serviceConfig.FunctionApp != nil {
+		options = serviceConfig.FunctionApp.DeploymentStorage
+	}
+
+	if options == nil {
+		storage, err := f.cli.GetFunctionAppDeploymentStorage(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+		)
+		if err != nil {
+			return err
+		}
+
+		if storage == nil {
+			return &internal.ErrorWithSuggestion{
+				Err: fmt.Errorf(
+					"function app '%s' has no deployment storage configured", targetResource.ResourceName()),
+				Suggestion: "Flex Consumption apps are deployed through a blob container. Set " +
+					"'functionAppConfig.deployment.storage' in the infrastructure, or " +
+					"'functionApp.deploymentStorage' on the service in azure.yaml.",
+			}
+		}
+
+		return nil
+	}
+
+	storage, err := f.resolveDeploymentStorage(options)
+	if err != nil {
+		return err
+	}
+
+	progress.SetProgress(NewServiceProgress("Configuring deployment storage"))
+	return f.cli.UpdateFunctionAppDeploymentStorage(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		storage,
+	)
+}
+
+// resolveDeploymentStorage expands the deployment storage options with the environment.
+func (f *functionAppTarget) resolveDeploymentStorage(
+	options *FunctionAppDeploymentStorageOptions,
+) (*azapi.FunctionAppDeploymentStorage, error) {
+	container, err := options.Container.Envsubst(f.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("failed resolving deployment storage container: %w", err)
+	}
+
+	authentication := &azapi.FunctionAppStorageAuthentication{
+		Type: azapi.FunctionAppStorageAuthenticationSystemAssignedIdentity,
+	}
+
+	switch options.Authentication {
+	case storageAuthenticationUserAssignedIdentity:
+		identity, err := options.Identity.Envsubst(f.env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("failed resolving deployment storage identity: %w", err)
+		}
+
+		authentication.Type = azapi.FunctionAppStorageAuthenticationUserAssignedIdentity
+		authentication.UserAssignedIdentityResourceId = identity
+	case storageAuthenticationStorageAccountConnectionString:
+		authentication.Type = azapi.FunctionAppStorageAuthenticationStorageAccountConnectionString
+		authentication.StorageAccountConnectionStringName = options.ConnectionStringSetting
+	}
+
+	return &azapi.FunctionAppDeploymentStorage{
+		Type:           azapi.FunctionAppDeploymentStorageTypeBlobContainer,
+		Value:          strings.TrimSuffix(container, "/"),
+		Authentication: authentication,
+	}, nil
+}
+
+// waitForFunctionHost waits for the function host of the app to be running with the deployed package. Errors reading
+// the host status are retried, since the host restarts after a deployment.
+func (f *functionAppTarget) waitForFunctionHost(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	progress *async.Progress[ServiceProgress],
+) error {
+	timeout := serviceConfig.FunctionApp.hostTimeout()
+	progress.SetProgress(NewServiceProgress("Waiting for function host"))
+	stopProgress := startPollingProgress(progress, "Waiting for function host", 15*time.Second)
+	defer stopProgress()
+
+	deadline := time.Now().Add(timeout)
+	var lastStatus *azapi.FunctionAppHostStatus
+	for {
+		status, err := f.cli.GetFunctionAppHostStatus(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+		)
+		switch {
+		case err != nil:
+			log.Printf("reading status of function host '%s': %v", targetResource.ResourceName(), err)
+		case status.State == azapi.FunctionAppHostStateRunning:
+			return nil
+		default:
+			log.Printf("function host '%s' state: %s", targetResource.ResourceName(), status.State)
+			lastStatus = status
+		}
+
+		if time.Now().After(deadline) {
+			return functionHostNotRunningError(targetResource.ResourceName(), timeout, lastStatus)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(functionHostPollInterval):
+		}
+	}
+}
+
+// functionHostNotRunningError describes a function host that isn't running after a deployment, with the errors
+// reported by the host.
+func functionHostNotRunningError(appName string, timeout time.Duration, status *azapi.FunctionAppHostStatus) error {
+	err := fmt.Errorf("the function host of '%s' isn't running after %s", appName, timeout)
+	if status != nil {
+		err = fmt.Errorf("%w, state: %s", err, status.State)
+		if len(status.Errors) > 0 {
+			err = fmt.Errorf("%w, errors: %s", err, strings.Join(status.Errors, "; "))
+		}
+	}
+
+	return &internal.ErrorWithSuggestion{
+		Err: err,
+		Suggestion: "Check the logs of the function app in Application Insights or the Azure portal, or increase " +
+			"'functionApp.hostTimeout' on the service in azure.yaml.",
+	}
+}
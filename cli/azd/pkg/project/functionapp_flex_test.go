@@ -0,0 +1,218 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazapi"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateFunctionAppOptions(t *testing.T) {
+	scope := "service 'api'"
+	require.Nil(t, validateFunctionAppOptions(nil, AzureFunctionTarget, scope))
+	require.Empty(t, validateFunctionAppOptions(&FunctionAppOptions{
+		DeploymentStorage: &FunctionAppDeploymentStorageOptions{
+			Container:      osutil.NewExpandableString("${DEPLOYMENT_CONTAINER_URL}"),
+			Authentication: storageAuthenticationUserAssignedIdentity,
+			Identity:       osutil.NewExpandableString("${AZURE_FUNCTION_IDENTITY_ID}"),
+		},
+		HostTimeout: 600,
+	}, AzureFunctionTarget, scope))
+
+	problems := validateFunctionAppOptions(&FunctionAppOptions{
+		DeploymentStorage: &FunctionAppDeploymentStorageOptions{Authentication: "accessKey"},
+		HostTimeout:       -1,
+	}, AppServiceTarget, scope)
+	require.Len(t, problems, 4)
+	require.Contains(t, problems[0], "functionApp is only supported for services with host 'function'")
+	require.Contains(t, problems[1], "functionApp.hostTimeout must be a positive number")
+	require.Contains(t, problems[2], "functionApp.deploymentStorage.container is required")
+	require.Contains(t, problems[3], "must be one of 'systemAssignedIdentity', 'userAssignedIdentity' or")
+
+	problems = validateFunctionAppOptions(&FunctionAppOptions{
+		DeploymentStorage: &FunctionAppDeploymentStorageOptions{
+			Container:      osutil.NewExpandableString("https://account.blob.core.windows.net/deployments"),
+			Authentication: storageAuthenticationStorageAccountConnectionString,
+		},
+	}, AzureFunctionTarget, scope)
+	require.Len(t, problems, 1)
+	require.Contains(t, problems[0], "functionApp.deploymentStorage.connectionStringSetting is required")
+}
+
+func Test_functionAppTarget_Deploy_FlexConsumption(t *testing.T) {
+	pollInterval := functionHostPollInterval
+	functionHostPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { functionHostPollInterval = pollInterval })
+
+	setup := func(
+		t *testing.T, deploymentStorage map[string]any, hostStates []string,
+	) (*mocks.MockContext, *map[string]any) {
+		mockContext := mocks.NewMockContext(t.Context())
+
+		// Serves both the App Service SDK and the raw function app configuration requests
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/sites/FUNC_APP_NAME")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			site := map[string]any{
+				"properties": map[string]any{
+					"defaultHostName": "FUNC_APP_NAME.azurewebsites.net",
+					"serverFarmId": "/subscriptions/SUB_ID/resourceGroups/RG_ID/providers/" +
+						"Microsoft.Web/serverfarms/FUNC_APP_PLAN",
+					"hostNameSslStates": []map[string]any{
+						{"name": "FUNC_APP_NAME.scm.azurewebsites.net", "hostType": "Repository"},
+					},
+					"functionAppConfig": map[string]any{
+						"deployment": map[string]any{"storage": deploymentStorage},
+						"runtime":    map[string]any{"name": "python", "version": "3.11"},
+					},
+				},
+			}
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, site)
+		})
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/serverfarms/FUNC_APP_PLAN")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			plan := map[string]any{"sku": map[string]any{"name": "FC1", "tier": "FlexConsumption"}}
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, plan)
+		})
+
+		patched := map[string]any{}
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPatch && strings.HasSuffix(request.URL.Path, "/sites/FUNC_APP_NAME")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(request.Body)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(body, &patched))
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, patched)
+		})
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost && strings.Contains(request.URL.Path, "/api/publish")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusAccepted, "deploy-1")
+		})
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && strings.Contains(request.URL.Path, "/api/deployments/deploy-1")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			deployment := azsdk.PublishResponse{Id: "deploy-1", Status: azsdk.PublishStatusSuccess, Complete: true}
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, deployment)
+		})
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/hostruntime/admin/host/status")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			status := azapi.FunctionAppHostStatus{State: hostStates[0]}
+			if len(hostStates) > 1 {
+				hostStates = hostStates[1:]
+			}
+			if status.State == azapi.FunctionAppHostStateError {
+				status.Errors = []string{"Worker was unable to load entry point 'function_app.py'"}
+			}
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, status)
+		})
+
+		return mockContext, &patched
+	}
+
+	deploy := func(t *testing.T, mockContext *mocks.MockContext, options *FunctionAppOptions) error {
+		zipFilePath := filepath.Join(t.TempDir(), "api.zip")
+		require.NoError(t, os.WriteFile(zipFilePath, []byte("zip"), osutil.PermissionFile))
+
+		serviceContext := NewServiceContext()
+		require.NoError(t, serviceContext.Package.Add(&Artifact{
+			Kind:         ArtifactKindArchive,
+			Location:     zipFilePath,
+			LocationKind: LocationKindLocal,
+		}))
+
+		env := environment.NewWithValues("test", map[string]string{
+			"DEPLOYMENT_CONTAINER_URL": "https://account.blob.core.windows.net/deployments/",
+		})
+		target := &functionAppTarget{
+			env:     env,
+			cli:     mockazapi.NewAzureClientFromMockContext(mockContext),
+			console: mockContext.Console,
+		}
+
+		targetResource := environment.NewTargetResource(
+			"SUB_ID", "RG_ID", "FUNC_APP_NAME", string(azapi.AzureResourceTypeWebSite),
+		)
+		serviceConfig := &ServiceConfig{
+			Name:        "api",
+			Host:        AzureFunctionTarget,
+			Language:    ServiceLanguagePython,
+			FunctionApp: options,
+		}
+		_, err := target.Deploy(
+			*mockContext.Context, serviceConfig, serviceContext, targetResource, async.NewNoopProgress[ServiceProgress]())
+		return err
+	}
+
+	t.Run("ConfiguresDeploymentStorage", func(t *testing.T) {
+		mockContext, patched := setup(t, nil, []string{"Initialized", "Running"})
+		err := deploy(t, mockContext, &FunctionAppOptions{
+			DeploymentStorage: &FunctionAppDeploymentStorageOptions{
+				Container: osutil.NewExpandableString("${DEPLOYMENT_CONTAINER_URL}"),
+			},
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, map[string]any{
+			"properties": map[string]any{
+				"functionAppConfig": map[string]any{
+					"deployment": map[string]any{
+						"storage": map[string]any{
+							"type":           "blobContainer",
+							"value":          "https://account.blob.core.windows.net/deployments",
+							"authentication": map[string]any{"type": "SystemAssignedIdentity"},
+						},
+					},
+					"runtime": map[string]any{"name": "python", "version": "3.11"},
+				},
+			},
+		}, *patched)
+	})
+
+	t.Run("ExistingDeploymentStorage", func(t *testing.T) {
+		mockContext, patched := setup(t, map[string]any{
+			"type":  "blobContainer",
+			"value": "https://account.blob.core.windows.net/deployments",
+		}, []string{"Running"})
+		require.NoError(t, deploy(t, mockContext, nil))
+		require.Empty(t, *patched)
+	})
+
+	t.Run("NoDeploymentStorage", func(t *testing.T) {
+		mockContext, _ := setup(t, nil, []string{"Running"})
+		err := deploy(t, mockContext, nil)
+		require.ErrorContains(t, err, "function app 'FUNC_APP_NAME' has no deployment storage configured")
+	})
+
+	t.Run("HostNotRunning", func(t *testing.T) {
+		mockContext, _ := setup(t, map[string]any{
+			"type":  "blobContainer",
+			"value": "https://account.blob.core.windows.net/deployments",
+		}, []string{"Error"})
+		err := deploy(t, mockContext, &FunctionAppOptions{HostTimeout: 1})
+		require.ErrorContains(t, err, "the function host of 'FUNC_APP_NAME' isn't running after 1s, state: Error")
+		require.ErrorContains(t, err, "Worker was unable to load entry point 'function_app.py'")
+	})
+}
@@ -0,0 +1,196 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// HealthCheckOptions configures the health check of a service, run after the service is deployed.
+type HealthCheckOptions struct {
+	// Path is the HTTP path of the service endpoint that is checked.
+	Path string `yaml:"path"`
+	// ExpectedStatus is the HTTP status the health path must respond with. When zero, any success status is healthy.
+	ExpectedStatus int `yaml:"expectedStatus,omitempty"`
+	// Timeout is how long, in seconds, the service has to become healthy. Defaults to 300.
+	Timeout int `yaml:"timeout,omitempty"`
+	// Rollback is whether a service that doesn't become healthy is rolled back to the package deployed before.
+	Rollback bool `yaml:"rollback,omitempty"`
+}
+
+// timeout returns how long the service has to become healthy.
+func (o *HealthCheckOptions) timeout() time.Duration {
+	if o.Timeout == 0 {
+		return defaultHealthTimeout
+	}
+
+	return time.Duration(o.Timeout) * time.Second
+}
+
+// healthy reports whether a response status is the status expected by the health check.
+func (o *HealthCheckOptions) healthy(status int) bool {
+	if o.ExpectedStatus != 0 {
+		return status == o.ExpectedStatus
+	}
+
+	return status >= 200 && status < 400
+}
+
+// validateHealthCheckOptions returns the problems of the health check of a service.
+func validateHealthCheckOptions(options *HealthCheckOptions, scope string) []string {
+	if options == nil {
+		return nil
+	}
+
+	var problems []string
+	if !strings.HasPrefix(options.Path, "/") {
+		problems = append(problems, fmt.Sprintf("%s: healthCheck.path must start with '/'", scope))
+	}
+
+	if options.ExpectedStatus != 0 && (options.ExpectedStatus < 100 || options.ExpectedStatus > 599) {
+		problems = append(problems, fmt.Sprintf(
+			"%s: healthCheck.expectedStatus must be an HTTP status code, got %d", scope, options.ExpectedStatus))
+	}
+
+	if options.Timeout < 0 {
+		problems = append(problems, fmt.Sprintf("%s: healthCheck.timeout must be a positive number of seconds", scope))
+	}
+
+	return problems
+}
+
+// healthCheckPollInterval is how often the health path of a deployed service is checked.
+var healthCheckPollInterval = 5 * time.Second
+
+// HealthCheckResult is the outcome of the health check of a deployed service.
+type HealthCheckResult struct {
+	// Url is the URL that was checked.
+	Url string `json:"url"`
+	// Healthy is whether the service responded with the expected status before the timeout.
+	Healthy bool `json:"healthy"`
+	// Status is the HTTP status of the last response, or zero when no response was received.
+	Status int `json:"status,omitempty"`
+	// Attempts is the number of requests sent to the health path.
+	Attempts int `json:"attempts"`
+	// Duration is how long the health check took.
+	Duration time.Duration `json:"-"`
+	// Error is the reason why the last request failed, when the service isn't healthy.
+	Error string `json:"error,omitempty"`
+	// RolledBack is whether the unhealthy service was rolled back to the package deployed before.
+	RolledBack bool `json:"rolledBack,omitempty"`
+}
+
+// ToString implements the UxItem interface.
+func (r *HealthCheckResult) ToString(currentIndentation string) string {
+	attempts := "1 attempt"
+	if r.Attempts != 1 {
+		attempts = fmt.Sprintf("%d attempts", r.Attempts)
+	}
+	details := fmt.Sprintf("%s, %s", attempts, r.Duration.Round(time.Second))
+
+	if r.Healthy {
+		return fmt.Sprintf("%s- Health check: %s returned %d (%s)",
+			currentIndentation, r.Url, r.Status, details)
+	}
+
+	result := fmt.Sprintf("%s- Health check: %s %s (%s)",
+		currentIndentation, r.Url, output.WithErrorFormat("failed"), details)
+	if r.Error != "" {
+		result += fmt.Sprintf("\n%s  %s", currentIndentation, r.Error)
+	}
+
+	return result
+}
+
+// MarshalJSON implements the UxItem interface JSON marshaling.
+func (r *HealthCheckResult) MarshalJSON() ([]byte, error) {
+	type healthCheckResult HealthCheckResult
+	return json.Marshal(struct {
+		*healthCheckResult
+		DurationSeconds float64 `json:"durationSeconds"`
+	}{
+		healthCheckResult: (*healthCheckResult)(r),
+		DurationSeconds:   r.Duration.Seconds(),
+	})
+}
+
+// CheckHealth polls the health path of a deployed service until it responds with the expected status, or the timeout
+// of the health check is reached. The health path is checked on the first endpoint of the deployment result.
+func CheckHealth(
+	ctx context.Context,
+	options *HealthCheckOptions,
+	deployResult *ServiceDeployResult,
+	client *http.Client,
+) *HealthCheckResult {
+	start := time.Now()
+	result := &HealthCheckResult{}
+	defer func() { result.Duration = time.Since(start) }()
+
+	endpoint, found := deployResult.Artifacts.FindFirst(WithKind(ArtifactKindEndpoint))
+	if !found || endpoint.Location == "" {
+		result.Url = options.Path
+		result.Error = "the service has no endpoint to check"
+		return result
+	}
+	result.Url = strings.TrimSuffix(endpoint.Location, "/") + options.Path
+
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, options.timeout())
+	defer cancel()
+
+	var lastErr error
+	for {
+		result.Attempts++
+		status, err := probeStatus(ctx, client, result.Url)
+		if err == nil {
+			result.Status = status
+			if options.healthy(status) {
+				result.Healthy = true
+				result.Error = ""
+				return result
+			}
+
+			err = fmt.Errorf("unexpected status %d", status)
+		}
+
+		// A request interrupted by the timeout doesn't replace the reason of the previous failed request.
+		if lastErr == nil || ctx.Err() == nil {
+			lastErr = err
+			result.Error = err.Error()
+		}
+
+		select {
+		case <-ctx.Done():
+			result.Error = fmt.Sprintf("not healthy after %s: %s", options.timeout(), lastErr)
+			return result
+		case <-time.After(healthCheckPollInterval):
+		}
+	}
+}
+
+// probeStatus sends a request to a health path, and returns the status of the response.
+func probeStatus(ctx context.Context, client *http.Client, url string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	return res.StatusCode, nil
+}
@@ -0,0 +1,110 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateHealthCheckOptions(t *testing.T) {
+	scope := "service 'api'"
+	require.Nil(t, validateHealthCheckOptions(nil, scope))
+	require.Empty(t, validateHealthCheckOptions(&HealthCheckOptions{
+		Path:           "/health",
+		ExpectedStatus: 204,
+		Timeout:        60,
+	}, scope))
+
+	problems := validateHealthCheckOptions(&HealthCheckOptions{
+		Path:           "health",
+		ExpectedStatus: 42,
+		Timeout:        -1,
+	}, scope)
+	require.Len(t, problems, 3)
+	require.Contains(t, problems[0], "healthCheck.path must start with '/'")
+	require.Contains(t, problems[1], "healthCheck.expectedStatus must be an HTTP status code, got 42")
+	require.Contains(t, problems[2], "healthCheck.timeout must be a positive number")
+}
+
+func Test_CheckHealth(t *testing.T) {
+	pollInterval := healthCheckPollInterval
+	healthCheckPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { healthCheckPollInterval = pollInterval })
+
+	deployResult := func(endpoint string) *ServiceDeployResult {
+		result := &ServiceDeployResult{}
+		if endpoint != "" {
+			require.NoError(t, result.Artifacts.Add(&Artifact{
+				Kind:         ArtifactKindEndpoint,
+				Location:     endpoint,
+				LocationKind: LocationKindRemote,
+			}))
+		}
+		return result
+	}
+
+	t.Run("BecomesHealthy", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/health", r.URL.Path)
+			if requests.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		t.Cleanup(server.Close)
+
+		result := CheckHealth(t.Context(), &HealthCheckOptions{
+			Path:           "/health",
+			ExpectedStatus: http.StatusNoContent,
+		}, deployResult(server.URL+"/"), server.Client())
+
+		require.True(t, result.Healthy)
+		require.Equal(t, server.URL+"/health", result.Url)
+		require.Equal(t, http.StatusNoContent, result.Status)
+		require.Equal(t, 3, result.Attempts)
+		require.Empty(t, result.Error)
+		require.Contains(t, result.ToString(""), "- Health check: "+server.URL+"/health returned 204 (3 attempts")
+
+		data, err := json.Marshal(result)
+		require.NoError(t, err)
+		require.Contains(t, string(data), `"healthy":true`)
+		require.Contains(t, string(data), `"durationSeconds":`)
+	})
+
+	t.Run("UnexpectedStatus", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		t.Cleanup(server.Close)
+
+		result := CheckHealth(t.Context(), &HealthCheckOptions{
+			Path:           "/health",
+			ExpectedStatus: http.StatusNoContent,
+			Timeout:        1,
+		}, deployResult(server.URL), server.Client())
+
+		require.False(t, result.Healthy)
+		require.Equal(t, http.StatusOK, result.Status)
+		require.Greater(t, result.Attempts, 1)
+		require.Equal(t, "not healthy after 1s: unexpected status 200", result.Error)
+		require.Contains(t, result.ToString(""), "not healthy after 1s: unexpected status 200")
+	})
+
+	t.Run("NoEndpoint", func(t *testing.T) {
+		result := CheckHealth(t.Context(), &HealthCheckOptions{Path: "/health"}, deployResult(""), nil)
+
+		require.False(t, result.Healthy)
+		require.Zero(t, result.Attempts)
+		require.Equal(t, "the service has no endpoint to check", result.Error)
+	})
+}
@@ -0,0 +1,131 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/trivy"
+)
+
+// ImageScanOptions configures the vulnerability scan of the container image of a service, run with Trivy before the
+// image is pushed to the registry.
+type ImageScanOptions struct {
+	// Severity is the lowest severity of the vulnerabilities that fail the deployment: LOW, MEDIUM, HIGH or CRITICAL.
+	// Defaults to HIGH.
+	Severity string `yaml:"severity,omitempty" json:"severity,omitempty"`
+	// IgnoreUnfixed ignores the vulnerabilities that have no fixed version yet.
+	IgnoreUnfixed bool `yaml:"ignoreUnfixed,omitempty" json:"ignoreUnfixed,omitempty"`
+}
+
+// The severities of vulnerabilities, from the lowest to the highest
+var imageScanSeverities = []string{"LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+const defaultImageScanSeverity = "HIGH"
+
+// The number of vulnerabilities listed when a scan fails
+const maxListedVulnerabilities = 10
+
+// failingSeverities returns the severities of the vulnerabilities that fail the deployment.
+func (o *ImageScanOptions) failingSeverities() []string {
+	severity := defaultImageScanSeverity
+	if o.Severity != "" {
+		severity = strings.ToUpper(o.Severity)
+	}
+
+	return imageScanSeverities[slices.Index(imageScanSeverities, severity):]
+}
+
+// validateImageScanOptions returns the problems of the image scan options of a service.
+func validateImageScanOptions(docker DockerProjectOptions, scope string) []string {
+	options := docker.Scan
+	if options == nil {
+		return nil
+	}
+
+	var problems []string
+	if docker.RemoteBuild {
+		problems = append(problems, fmt.Sprintf(
+			"%s: docker.scan cannot be used with docker.remoteBuild, images are scanned before they are pushed", scope))
+	}
+
+	if options.Severity != "" && !slices.Contains(imageScanSeverities, strings.ToUpper(options.Severity)) {
+		problems = append(problems, fmt.Sprintf(
+			"%s: docker.scan.severity must be one of %s, got '%s'",
+			scope, strings.Join(imageScanSeverities, ", "), options.Severity))
+	}
+
+	return problems
+}
+
+// scanImage scans the local image of the service, and fails when it has vulnerabilities of the configured severities.
+func (ch *ContainerHelper) scanImage(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	image string,
+	progress *async.Progress[ServiceProgress],
+) error {
+	options := serviceConfig.Docker.Scan
+	severities := options.failingSeverities()
+
+	progress.SetProgress(NewServiceProgress("Scanning container image"))
+	scanOptions := trivy.ScanOptions{
+		Severities:    severities,
+		IgnoreUnfixed: options.IgnoreUnfixed,
+	}
+	if ch.ContainerEngine() == "podman" {
+		scanOptions.ImageSource = "podman"
+	}
+
+	report, err := trivy.NewCli(ch.commandRunner).ScanImage(ctx, resolveServiceDir(serviceConfig), image, scanOptions)
+	if err != nil {
+		return err
+	}
+
+	vulnerabilities := report.Vulnerabilities()
+	if len(vulnerabilities) == 0 {
+		log.Printf("image %s has no vulnerabilities of severity %s", image, strings.Join(severities, ", "))
+		return nil
+	}
+
+	// Most severe first
+	slices.SortStableFunc(vulnerabilities, func(a, b trivy.Vulnerability) int {
+		return slices.Index(imageScanSeverities, b.Severity) - slices.Index(imageScanSeverities, a.Severity)
+	})
+
+	lines := []string{fmt.Sprintf("Vulnerabilities of image %s:", output.WithHighLightFormat(image))}
+	for _, vulnerability := range vulnerabilities[:min(len(vulnerabilities), maxListedVulnerabilities)] {
+		fixed := "no fix"
+		if vulnerability.FixedVersion != "" {
+			fixed = "fixed in " + vulnerability.FixedVersion
+		}
+
+		lines = append(lines, fmt.Sprintf("  %-8s %s %s %s (%s)",
+			vulnerability.Severity,
+			vulnerability.VulnerabilityID,
+			vulnerability.PkgName,
+			vulnerability.InstalledVersion,
+			fixed,
+		))
+	}
+	if len(vulnerabilities) > maxListedVulnerabilities {
+		lines = append(lines, fmt.Sprintf("  and %d more", len(vulnerabilities)-maxListedVulnerabilities))
+	}
+	ch.console.Message(ctx, strings.Join(lines, "\n"))
+
+	return &internal.ErrorWithSuggestion{
+		Err: fmt.Errorf(
+			"image of service '%s' has %d vulnerabilities of severity %s or higher",
+			serviceConfig.Name, len(vulnerabilities), severities[0]),
+		Suggestion: "Update the base image or the vulnerable packages, list accepted vulnerabilities in a " +
+			"'.trivyignore' file in the service directory, or change 'docker.scan.severity' in azure.yaml.",
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/trivy"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateImageScanOptions(t *testing.T) {
+	scope := "service 'web'"
+	require.Nil(t, validateImageScanOptions(DockerProjectOptions{}, scope))
+	require.Empty(t, validateImageScanOptions(
+		DockerProjectOptions{Scan: &ImageScanOptions{Severity: "critical", IgnoreUnfixed: true}}, scope))
+
+	problems := validateImageScanOptions(DockerProjectOptions{
+		RemoteBuild: true,
+		Scan:        &ImageScanOptions{Severity: "SEVERE"},
+	}, scope)
+	require.Len(t, problems, 2)
+	require.Contains(t, problems[0], "docker.scan cannot be used with docker.remoteBuild")
+	require.Contains(t, problems[1], "docker.scan.severity must be one of LOW, MEDIUM, HIGH, CRITICAL, got 'SEVERE'")
+}
+
+func Test_ImageScanOptions_failingSeverities(t *testing.T) {
+	require.Equal(t, []string{"HIGH", "CRITICAL"}, (&ImageScanOptions{}).failingSeverities())
+	require.Equal(t, []string{"MEDIUM", "HIGH", "CRITICAL"}, (&ImageScanOptions{Severity: "medium"}).failingSeverities())
+	require.Equal(t, []string{"CRITICAL"}, (&ImageScanOptions{Severity: "CRITICAL"}).failingSeverities())
+}
+
+func Test_ContainerHelper_scanImage(t *testing.T) {
+	t.Setenv("AZD_CONTAINER_RUNTIME", "docker")
+
+	scan := func(t *testing.T, vulnerabilities []trivy.Vulnerability) ([]string, *mocks.MockContext, error) {
+		mockContext := mocks.NewMockContext(t.Context())
+		var scanArgs []string
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return args.Cmd == "trivy"
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			scanArgs = args.Args
+			report, err := json.Marshal(trivy.Report{
+				Results: []trivy.Result{{Target: "web (debian 12.5)", Vulnerabilities: vulnerabilities}},
+			})
+			require.NoError(t, err)
+			return exec.NewRunResult(0, string(report), ""), nil
+		})
+
+		containerHelper := &ContainerHelper{
+			commandRunner: mockContext.CommandRunner,
+			console:       mockContext.Console,
+			docker:        docker.NewCli(mockContext.CommandRunner),
+		}
+		serviceConfig := createTestServiceConfig("./src/web", ContainerAppTarget, ServiceLanguageTypeScript)
+		serviceConfig.Project.Path = t.TempDir()
+		serviceConfig.Docker.Scan = &ImageScanOptions{IgnoreUnfixed: true}
+
+		err := containerHelper.scanImage(
+			*mockContext.Context, serviceConfig, "contoso.azurecr.io/web:1", async.NewNoopProgress[ServiceProgress]())
+		return scanArgs, mockContext, err
+	}
+
+	t.Run("NoVulnerabilities", func(t *testing.T) {
+		scanArgs, _, err := scan(t, nil)
+		require.NoError(t, err)
+		require.Equal(t, "HIGH,CRITICAL", scanArgs[slices.Index(scanArgs, "--severity")+1])
+		require.Contains(t, scanArgs, "--ignore-unfixed")
+		require.NotContains(t, scanArgs, "--image-src")
+	})
+
+	t.Run("Vulnerabilities", func(t *testing.T) {
+		var vulnerabilities []trivy.Vulnerability
+		for i := range 12 {
+			vulnerabilities = append(vulnerabilities, trivy.Vulnerability{
+				VulnerabilityID:  fmt.Sprintf("CVE-2024-%04d", i),
+				PkgName:          "openssl",
+				InstalledVersion: "3.0.11-1",
+				Severity:         "HIGH",
+			})
+		}
+		vulnerabilities[11].Severity = "CRITICAL"
+		vulnerabilities[11].FixedVersion = "3.0.13-1"
+
+		_, mockContext, err := scan(t, vulnerabilities)
+		require.ErrorContains(t, err, "image of service 'api' has 12 vulnerabilities of severity HIGH or higher")
+
+		consoleOutput := mockContext.Console.Output()
+		require.Contains(t, consoleOutput[0], "CRITICAL CVE-2024-0011 openssl 3.0.11-1 (fixed in 3.0.13-1)")
+		require.Contains(t, consoleOutput[0], "HIGH     CVE-2024-0000 openssl 3.0.11-1 (no fix)")
+		require.Contains(t, consoleOutput[0], "and 2 more")
+	})
+}
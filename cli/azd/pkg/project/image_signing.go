@@ -0,0 +1,185 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/cosign"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/notation"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+)
+
+// ImageSigningOptions configures the signing of the container image of a service with a key of Azure Key Vault, after
+// the image is pushed to the registry.
+type ImageSigningOptions struct {
+	// Tool is the tool signing the image: 'notation' or 'cosign'.
+	Tool string `yaml:"tool" json:"tool"`
+	// Key is the ID of the Key Vault key, such as 'https://<vault>.vault.azure.net/keys/<name>/<version>'. Supports
+	// environment variable substitution.
+	Key osutil.ExpandableString `yaml:"key" json:"key"`
+	// Verify verifies the signature after the image is signed.
+	Verify bool `yaml:"verify,omitempty" json:"verify,omitempty"`
+	// PluginConfig is passed to the Azure Key Vault plugin of notation, for example 'self_signed: "true"'.
+	PluginConfig map[string]string `yaml:"pluginConfig,omitempty" json:"pluginConfig,omitempty"`
+}
+
+// The tools signing images
+const (
+	imageSigningToolNotation = "notation"
+	imageSigningToolCosign   = "cosign"
+)
+
+// validateImageSigningOptions returns the problems of the image signing options of a service.
+func validateImageSigningOptions(docker DockerProjectOptions, scope string) []string {
+	options := docker.Sign
+	if options == nil {
+		return nil
+	}
+
+	var problems []string
+	if docker.RemoteBuild {
+		problems = append(problems, fmt.Sprintf("%s: docker.sign cannot be used with docker.remoteBuild", scope))
+	}
+
+	switch options.Tool {
+	case imageSigningToolNotation:
+	case imageSigningToolCosign:
+		if len(options.PluginConfig) > 0 {
+			problems = append(problems, fmt.Sprintf(
+				"%s: docker.sign.pluginConfig is only supported with tool '%s'", scope, imageSigningToolNotation))
+		}
+	default:
+		problems = append(problems, fmt.Sprintf(
+			"%s: docker.sign.tool must be '%s' or '%s', got '%s'",
+			scope, imageSigningToolNotation, imageSigningToolCosign, options.Tool))
+	}
+
+	if options.Key.Empty() {
+		problems = append(problems, fmt.Sprintf("%s: docker.sign.key is required", scope))
+	}
+
+	return problems
+}
+
+// imageSigningTool returns the tool signing the images of the service.
+func (ch *ContainerHelper) imageSigningTool(options *ImageSigningOptions) tools.ExternalTool {
+	if options.Tool == imageSigningToolCosign {
+		return cosign.NewCli(ch.commandRunner)
+	}
+
+	return notation.NewCli(ch.commandRunner)
+}
+
+// imageSignature is the signature of a pushed image
+type imageSignature struct {
+	// The digest of the image
+	Digest string
+	// The digest or the reference of the signature
+	Signature string
+}
+
+// signImage signs the pushed image of the service, by digest, and verifies the signature when configured.
+func (ch *ContainerHelper) signImage(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	env *environment.Environment,
+	image string,
+	progress *async.Progress[ServiceProgress],
+) (*imageSignature, error) {
+	options := serviceConfig.Docker.Sign
+	key, err := options.Key.Envsubst(env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("resolving signing key: %w", err)
+	}
+
+	imageByDigest, err := ch.imageDigestReference(ctx, image)
+	if err != nil {
+		return nil, err
+	}
+
+	progress.SetProgress(NewServiceProgress("Signing container image"))
+	var signature string
+	switch options.Tool {
+	case imageSigningToolCosign:
+		cosignCli := cosign.NewCli(ch.commandRunner)
+		keyUri, err := cosign.KeyVaultKeyUri(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := cosignCli.Sign(ctx, imageByDigest, keyUri); err != nil {
+			return nil, err
+		}
+
+		if options.Verify {
+			progress.SetProgress(NewServiceProgress("Verifying image signature"))
+			if err := cosignCli.Verify(ctx, imageByDigest, keyUri); err != nil {
+				return nil, err
+			}
+		}
+
+		signature, err = cosignCli.SignatureReference(ctx, imageByDigest)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		notationCli := notation.NewCli(ch.commandRunner)
+		if err := notationCli.Sign(ctx, imageByDigest, key, options.PluginConfig); err != nil {
+			return nil, err
+		}
+
+		if options.Verify {
+			progress.SetProgress(NewServiceProgress("Verifying image signature"))
+			if err := notationCli.Verify(ctx, imageByDigest); err != nil {
+				return nil, err
+			}
+		}
+
+		signature, err = notationCli.LatestSignature(ctx, imageByDigest)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, digest, _ := strings.Cut(imageByDigest, "@")
+	return &imageSignature{Digest: digest, Signature: signature}, nil
+}
+
+// imageDigestReference returns the reference by digest of an image pushed to a registry, such as
+// 'contoso.azurecr.io/app/web@sha256:...', so that the signature can't be moved to another image with the tag.
+func (ch *ContainerHelper) imageDigestReference(ctx context.Context, image string) (string, error) {
+	parsed, err := docker.ParseContainerImage(image)
+	if err != nil {
+		return "", err
+	}
+
+	repository := (&docker.ContainerImage{Registry: parsed.Registry, Repository: parsed.Repository}).Remote()
+
+	out, err := ch.docker.Inspect(ctx, image, "{{json .RepoDigests}}")
+	if err != nil {
+		return "", err
+	}
+
+	var repoDigests []string
+	if err := json.Unmarshal([]byte(out), &repoDigests); err != nil {
+		return "", fmt.Errorf("reading digests of image %s: %w", image, err)
+	}
+
+	for _, repoDigest := range repoDigests {
+		if strings.HasPrefix(repoDigest, repository+"@") {
+			return repoDigest, nil
+		}
+	}
+
+	return "", fmt.Errorf(
+		"image %s has no digest in repository %s, only images pushed to a registry can be signed", image, repository)
+}
@@ -0,0 +1,145 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateImageSigningOptions(t *testing.T) {
+	scope := "service 'web'"
+	require.Nil(t, validateImageSigningOptions(DockerProjectOptions{}, scope))
+	require.Empty(t, validateImageSigningOptions(DockerProjectOptions{
+		Sign: &ImageSigningOptions{
+			Tool:         imageSigningToolNotation,
+			Key:          osutil.NewExpandableString("${AZURE_SIGNING_KEY_ID}"),
+			PluginConfig: map[string]string{"self_signed": "true"},
+		},
+	}, scope))
+
+	problems := validateImageSigningOptions(DockerProjectOptions{
+		RemoteBuild: true,
+		Sign:        &ImageSigningOptions{Tool: "gpg"},
+	}, scope)
+	require.Len(t, problems, 3)
+	require.Contains(t, problems[0], "docker.sign cannot be used with docker.remoteBuild")
+	require.Contains(t, problems[1], "docker.sign.tool must be 'notation' or 'cosign', got 'gpg'")
+	require.Contains(t, problems[2], "docker.sign.key is required")
+
+	problems = validateImageSigningOptions(DockerProjectOptions{
+		Sign: &ImageSigningOptions{
+			Tool:         imageSigningToolCosign,
+			Key:          osutil.NewExpandableString("https://contoso.vault.azure.net/keys/signing"),
+			PluginConfig: map[string]string{"self_signed": "true"},
+		},
+	}, scope)
+	require.Len(t, problems, 1)
+	require.Contains(t, problems[0], "docker.sign.pluginConfig is only supported with tool 'notation'")
+}
+
+func Test_ContainerHelper_signImage(t *testing.T) {
+	t.Setenv("AZD_CONTAINER_RUNTIME", "docker")
+
+	const image = "contoso.azurecr.io/app/web:azd-deploy-1"
+	const imageByDigest = "contoso.azurecr.io/app/web@sha256:4f1c"
+
+	sign := func(
+		t *testing.T, options *ImageSigningOptions, repoDigests string,
+	) (*imageSignature, [][]string, error) {
+		mockContext := mocks.NewMockContext(t.Context())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return slices.Equal(args.Args[:2], []string{"image", "inspect"})
+		}).Respond(exec.NewRunResult(0, repoDigests, ""))
+
+		var commands [][]string
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return args.Cmd == "notation" || args.Cmd == "cosign"
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			commands = append(commands, append([]string{args.Cmd}, args.Args...))
+			switch args.Args[0] {
+			case "inspect":
+				return exec.NewRunResult(0, `{"signatures": [{"digest": "sha256:9e2d"}]}`, ""), nil
+			case "triangulate":
+				return exec.NewRunResult(0, "contoso.azurecr.io/app/web:sha256-4f1c.sig", ""), nil
+			}
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		containerHelper := &ContainerHelper{
+			commandRunner: mockContext.CommandRunner,
+			docker:        docker.NewCli(mockContext.CommandRunner),
+		}
+		serviceConfig := createTestServiceConfig("./src/web", ContainerAppTarget, ServiceLanguageTypeScript)
+		serviceConfig.Docker.Sign = options
+		env := environment.NewWithValues("dev", map[string]string{
+			"AZURE_SIGNING_KEY_ID": "https://contoso.vault.azure.net/keys/signing",
+		})
+
+		signature, err := containerHelper.signImage(
+			*mockContext.Context, serviceConfig, env, image, async.NewNoopProgress[ServiceProgress]())
+		return signature, commands, err
+	}
+
+	repoDigests := `["mcr.microsoft.com/app/web@sha256:0000","` + imageByDigest + `"]`
+
+	t.Run("Notation", func(t *testing.T) {
+		signature, commands, err := sign(t, &ImageSigningOptions{
+			Tool:   imageSigningToolNotation,
+			Key:    osutil.NewExpandableString("${AZURE_SIGNING_KEY_ID}/1"),
+			Verify: true,
+		}, repoDigests)
+		require.NoError(t, err)
+		require.Equal(t, &imageSignature{Digest: "sha256:4f1c", Signature: "sha256:9e2d"}, signature)
+		require.Equal(t, [][]string{
+			{
+				"notation", "sign", "--signature-format", "cose", "--plugin", "azure-kv",
+				"--id", "https://contoso.vault.azure.net/keys/signing/1", imageByDigest,
+			},
+			{"notation", "verify", imageByDigest},
+			{"notation", "inspect", "--output", "json", imageByDigest},
+		}, commands)
+	})
+
+	t.Run("Cosign", func(t *testing.T) {
+		signature, commands, err := sign(t, &ImageSigningOptions{
+			Tool: imageSigningToolCosign,
+			Key:  osutil.NewExpandableString("${AZURE_SIGNING_KEY_ID}"),
+		}, repoDigests)
+		require.NoError(t, err)
+		require.Equal(t, "contoso.azurecr.io/app/web:sha256-4f1c.sig", signature.Signature)
+		require.Equal(t, []string{
+			"cosign", "sign", "--key", "azurekms://contoso.vault.azure.net/signing", "--yes", imageByDigest,
+		}, commands[0])
+	})
+
+	t.Run("NotPushed", func(t *testing.T) {
+		_, commands, err := sign(t, &ImageSigningOptions{
+			Tool: imageSigningToolNotation,
+			Key:  osutil.NewExpandableString("${AZURE_SIGNING_KEY_ID}/1"),
+		}, "[]")
+		require.ErrorContains(t, err, "image "+image+" has no digest in repository contoso.azurecr.io/app/web")
+		require.Empty(t, commands)
+	})
+}
+
+func Test_Artifact_ToString_Signature(t *testing.T) {
+	artifact := &Artifact{
+		Kind:         ArtifactKindContainer,
+		Location:     "contoso.azurecr.io/app/web:azd-deploy-1",
+		LocationKind: LocationKindRemote,
+		Metadata:     map[string]string{MetadataKeySignature: "sha256:9e2d"},
+	}
+
+	require.Contains(t, artifact.ToString("  "), "\n  - Signature: ")
+	require.Contains(t, artifact.ToString("  "), "sha256:9e2d")
+}
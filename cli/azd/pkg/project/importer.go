@@ -165,7 +165,7 @@ func (im *ImportManager) sortServicesByDependencies(
 	// Check if any service has dependencies
 	hasDependencies := false
 	for _, svc := range services {
-		if len(svc.Uses) > 0 {
+		if len(svc.Uses) > 0 || len(svc.DependsOn) > 0 {
 			hasDependencies = true
 			break
 		}
@@ -191,7 +191,7 @@ func (im *ImportManager) sortServicesByDependencies(
 
 	// Build dependency edges
 	for _, svc := range services {
-		for _, dependency := range svc.Uses {
+		for _, dependency := range svc.DeployDependencies() {
 			// Only consider service-to-service dependencies for ordering
 			// (service-to-resource dependencies are declarative only)
 			if _, isService := graph[dependency]; isService {
@@ -241,7 +241,8 @@ func (im *ImportManager) sortServicesByDependencies(
 	return result, nil
 }
 
-// validateServiceDependencies ensures all dependencies referenced in service Uses exist
+// validateServiceDependencies ensures all dependencies referenced in service Uses exist, and that the entries of
+// DependsOn are services
 func (im *ImportManager) validateServiceDependencies(services []*ServiceConfig, projectConfig *ProjectConfig) error {
 	serviceNames := make(map[string]bool)
 	for _, svc := range services {
@@ -259,6 +260,16 @@ func (im *ImportManager) validateServiceDependencies(services []*ServiceConfig,
 				)
 			}
 		}
+
+		for _, dependency := range svc.DependsOn {
+			if !serviceNames[dependency] {
+				return fmt.Errorf(
+					"service '%s' depends on '%s' which does not exist as a service",
+					svc.Name,
+					dependency,
+				)
+			}
+		}
 	}
 
 	return nil
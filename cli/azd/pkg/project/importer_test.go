@@ -586,6 +586,37 @@ func TestImportManagerServiceStableWithDependencyOrdering(t *testing.T) {
 			shouldError: true,
 			errorMsg:    "does not exist as a service or resource",
 		},
+		{
+			name: "dependsOn chain",
+			services: map[string]*ServiceConfig{
+				"frontend": {Name: "frontend", DependsOn: []string{"backend"}},
+				"backend":  {Name: "backend", Uses: []string{"database"}},
+				"database": {Name: "database"},
+			},
+			expectedVariations: [][]string{
+				{"database", "backend", "frontend"},
+			},
+		},
+		{
+			name: "circular dependsOn",
+			services: map[string]*ServiceConfig{
+				"service1": {Name: "service1", DependsOn: []string{"service2"}},
+				"service2": {Name: "service2", Uses: []string{"service1"}},
+			},
+			shouldError: true,
+			errorMsg:    "circular dependency detected",
+		},
+		{
+			name: "dependsOn resource",
+			services: map[string]*ServiceConfig{
+				"api": {Name: "api", DependsOn: []string{"database"}},
+			},
+			resources: map[string]*ResourceConfig{
+				"database": {Name: "database", Type: "db.postgres"},
+			},
+			shouldError: true,
+			errorMsg:    "does not exist as a service",
+		},
 	}
 
 	for _, tt := range tests {
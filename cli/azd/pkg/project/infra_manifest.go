@@ -0,0 +1,112 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// InfraManifestFile is the name of the manifest, in the infra directory, that tracks the files written by
+// `azd infra generate`.
+const InfraManifestFile = ".azd-infra.json"
+
+// InfraManifest tracks which files of the infra directory are generated by azd from azure.yaml, and which are owned by
+// the user.
+type InfraManifest struct {
+	// Generated are the files written by `azd infra generate`, relative to the infra directory, with the SHA-256 hash of
+	// their contents. A generated file whose contents no longer match its hash was edited, and is owned by the user.
+	Generated map[string]string `json:"generated,omitempty"`
+
+	// Ejected are the generated files, relative to the infra directory, that were written for customization while azd
+	// keeps generating the rest of the infrastructure. They replace the generated versions when provisioning.
+	Ejected []string `json:"ejected,omitempty"`
+}
+
+// LoadInfraManifest loads the manifest of the infra directory infraRoot. The manifest is empty when there's none.
+func LoadInfraManifest(infraRoot string) (*InfraManifest, error) {
+	manifest := &InfraManifest{}
+
+	contents, err := os.ReadFile(filepath.Join(infraRoot, InfraManifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return manifest, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading infra manifest: %w", err)
+	}
+
+	if err := json.Unmarshal(contents, manifest); err != nil {
+		return nil, fmt.Errorf("parsing infra manifest %s: %w", InfraManifestFile, err)
+	}
+
+	return manifest, nil
+}
+
+// Save writes the manifest to the infra directory infraRoot.
+func (m *InfraManifest) Save(infraRoot string) error {
+	contents, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling infra manifest: %w", err)
+	}
+
+	if err := os.MkdirAll(infraRoot, osutil.PermissionDirectory); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(infraRoot, InfraManifestFile), append(contents, '\n'), osutil.PermissionFile)
+}
+
+// SetGenerated records that path was generated with contents.
+func (m *InfraManifest) SetGenerated(path string, contents []byte) {
+	if m.Generated == nil {
+		m.Generated = map[string]string{}
+	}
+
+	path = filepath.ToSlash(path)
+	m.Generated[path] = contentHash(contents)
+	m.Ejected = slices.DeleteFunc(m.Ejected, func(ejected string) bool { return ejected == path })
+}
+
+// SetUserOwned records that path is owned by the user, and is no longer generated.
+func (m *InfraManifest) SetUserOwned(path string) {
+	delete(m.Generated, filepath.ToSlash(path))
+}
+
+// Eject records that path was generated for customization, and replaces the generated version when provisioning.
+func (m *InfraManifest) Eject(path string) {
+	path = filepath.ToSlash(path)
+	delete(m.Generated, path)
+	if !slices.Contains(m.Ejected, path) {
+		m.Ejected = append(m.Ejected, path)
+		slices.Sort(m.Ejected)
+	}
+}
+
+// Unmodified reports whether the file path of the infra directory infraRoot is a generated file that wasn't edited
+// since it was generated.
+func (m *InfraManifest) Unmodified(infraRoot string, path string) bool {
+	hash, has := m.Generated[filepath.ToSlash(path)]
+	if !has {
+		return false
+	}
+
+	contents, err := os.ReadFile(filepath.Join(infraRoot, path))
+	if err != nil {
+		return false
+	}
+
+	return contentHash(contents) == hash
+}
+
+func contentHash(contents []byte) string {
+	hash := sha256.Sum256(contents)
+	return hex.EncodeToString(hash[:])
+}
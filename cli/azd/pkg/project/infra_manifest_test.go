@@ -0,0 +1,74 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_InfraManifest(t *testing.T) {
+	infraRoot := t.TempDir()
+
+	manifest, err := LoadInfraManifest(infraRoot)
+	require.NoError(t, err)
+	require.Empty(t, manifest.Generated)
+
+	for _, file := range []string{"main.bicep", "resources.bicep"} {
+		require.NoError(t, os.WriteFile(filepath.Join(infraRoot, file), []byte(file), 0600))
+		manifest.SetGenerated(file, []byte(file))
+	}
+	require.True(t, manifest.Unmodified(infraRoot, "main.bicep"))
+
+	// edited files are owned by the user
+	require.NoError(t, os.WriteFile(filepath.Join(infraRoot, "main.bicep"), []byte("edited"), 0600))
+	require.False(t, manifest.Unmodified(infraRoot, "main.bicep"))
+	require.False(t, manifest.Unmodified(infraRoot, "network.bicep"))
+
+	manifest.Eject("resources.bicep")
+	require.False(t, manifest.Unmodified(infraRoot, "resources.bicep"))
+	require.NoError(t, manifest.Save(infraRoot))
+
+	loaded, err := LoadInfraManifest(infraRoot)
+	require.NoError(t, err)
+	require.Equal(t, []string{"resources.bicep"}, loaded.Ejected)
+	require.Equal(t, []string{"main.bicep"}, slices.Sorted(maps.Keys(loaded.Generated)))
+
+	// generating an ejected file again makes it generated
+	loaded.SetGenerated("resources.bicep", []byte("resources.bicep"))
+	require.Empty(t, loaded.Ejected)
+}
+
+func Test_OverlayEjectedInfra(t *testing.T) {
+	prjDir := t.TempDir()
+	infraRoot := filepath.Join(prjDir, "infra")
+	require.NoError(t, os.MkdirAll(infraRoot, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(infraRoot, "resources.bicep"), []byte("custom"), 0600))
+
+	manifest := &InfraManifest{}
+	manifest.Eject("resources.bicep")
+	// ejected files that were deleted use the generated version
+	manifest.Eject("modules/missing.bicep")
+	require.NoError(t, manifest.Save(infraRoot))
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.bicep"), []byte("generated"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "resources.bicep"), []byte("generated"), 0600))
+
+	require.NoError(t, overlayEjectedInfra(&ProjectConfig{Path: prjDir}, dir))
+
+	contents, err := os.ReadFile(filepath.Join(dir, "resources.bicep"))
+	require.NoError(t, err)
+	require.Equal(t, "custom", string(contents))
+
+	contents, err = os.ReadFile(filepath.Join(dir, "main.bicep"))
+	require.NoError(t, err)
+	require.Equal(t, "generated", string(contents))
+	require.NoFileExists(t, filepath.Join(dir, "modules", "missing.bicep"))
+}
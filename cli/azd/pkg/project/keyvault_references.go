@@ -0,0 +1,257 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// validateSecretExpressions returns the problems of the ${keyvault:<vault-name>/<secret-name>} references in the env
+// and the app settings of a service.
+func validateSecretExpressions(svc *ServiceConfig, scope string) []string {
+	var problems []string
+	for _, name := range slices.Sorted(maps.Keys(svc.Environment)) {
+		template := svc.Environment[name].Template()
+		if !keyvault.HasSecretExpression(template) {
+			continue
+		}
+
+		if svc.Host != AppServiceTarget && svc.Host != ContainerAppTarget {
+			problems = append(problems, fmt.Sprintf(
+				"%s: env '%s': Key Vault secret references are only supported in env for services with host '%s' or '%s'",
+				scope, name, AppServiceTarget, ContainerAppTarget))
+			continue
+		}
+
+		if _, err := keyvault.ParseSecretExpressions(template); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: env '%s': %v", scope, name, err))
+		}
+	}
+
+	if svc.AppSettings == nil {
+		return problems
+	}
+
+	for _, mapping := range []map[string]string{svc.AppSettings.Settings, svc.AppSettings.Secrets} {
+		for _, name := range slices.Sorted(maps.Keys(mapping)) {
+			if !keyvault.HasSecretExpression(mapping[name]) {
+				continue
+			}
+
+			if _, err := keyvault.ParseSecretExpression(mapping[name]); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: app setting '%s': %v", scope, name, err))
+			}
+		}
+	}
+
+	return problems
+}
+
+// resolveSecretExpression reads the value of the secret referenced by a ${keyvault:...} expression with the account
+// azd is signed in with. The errors explain how to grant access to the secret.
+func resolveSecretExpression(
+	ctx context.Context,
+	keyvaultService keyvault.KeyVaultService,
+	subscriptionId string,
+	expression keyvault.SecretExpression,
+) (string, error) {
+	secret, err := keyvaultService.GetKeyVaultSecret(ctx, subscriptionId, expression.VaultName, expression.SecretName)
+	if errors.Is(err, keyvault.ErrAzCliSecretNotFound) {
+		return "", &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf(
+				"resolving %s: secret '%s' was not found in Key Vault '%s'",
+				expression, expression.SecretName, expression.VaultName),
+			Suggestion: fmt.Sprintf(
+				"Create the secret with 'az keyvault secret set --vault-name %s --name %s --value <value>', or fix "+
+					"the reference in azure.yaml.", expression.VaultName, expression.SecretName),
+		}
+	}
+
+	if respErr, ok := errors.AsType[*azcore.ResponseError](err); ok && respErr.StatusCode == http.StatusForbidden {
+		return "", &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("resolving %s: access to Key Vault '%s' was denied: %w", expression, expression.VaultName, err),
+			Suggestion: fmt.Sprintf(
+				"Assign the 'Key Vault Secrets User' role on Key Vault '%s' to the account azd is signed in with, "+
+					"or, when the vault uses access policies, allow the account to get secrets.",
+				expression.VaultName),
+		}
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("resolving %s: %w", expression, err)
+	}
+
+	return secret.Value, nil
+}
+
+// secretValueEscaper escapes the values of secrets, so they aren't expanded again with the env of a service.
+var secretValueEscaper = strings.NewReplacer(`\`, `\\`, "$", "$$")
+
+// expandServiceEnvironment expands the env of a service, like [osutil.ExpandableMap.Expand], and replaces its
+// ${keyvault:...} expressions. When reference isn't nil, a value that is exactly one expression is replaced with the
+// Key Vault reference the host resolves itself. The other expressions are replaced with the values of their secrets,
+// read by azd.
+func expandServiceEnvironment(
+	ctx context.Context,
+	keyvaultService keyvault.KeyVaultService,
+	env *environment.Environment,
+	serviceConfig *ServiceConfig,
+	subscriptionId string,
+	reference func(keyvault.SecretExpression) string,
+) (map[string]string, error) {
+	envVars := make(map[string]string, len(serviceConfig.Environment))
+	for name, value := range serviceConfig.Environment {
+		template := value.Template()
+		if !keyvault.HasSecretExpression(template) {
+			expanded, err := value.Envsubst(env.Getenv)
+			if err != nil {
+				return nil, fmt.Errorf("expanding %s: %w", name, err)
+			}
+
+			envVars[name] = expanded
+			continue
+		}
+
+		if reference != nil && keyvault.IsSecretExpression(template) {
+			expression, err := keyvault.ParseSecretExpression(template)
+			if err != nil {
+				return nil, fmt.Errorf("expanding %s: %w", name, err)
+			}
+
+			envVars[name] = reference(expression)
+			continue
+		}
+
+		template, err := keyvault.ReplaceSecretExpressions(template, func(e keyvault.SecretExpression) (string, error) {
+			value, err := resolveSecretExpression(ctx, keyvaultService, subscriptionId, e)
+			return secretValueEscaper.Replace(value), err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("expanding %s: %w", name, err)
+		}
+
+		expanded, err := osutil.NewExpandableString(template).Envsubst(env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("expanding %s: %w", name, err)
+		}
+
+		envVars[name] = expanded
+	}
+
+	return envVars, nil
+}
+
+// webAppKeyVaultReferences returns the names of the app settings of an App Service or a function app that azd sets to
+// Key Vault references.
+func webAppKeyVaultReferences(serviceConfig *ServiceConfig) []string {
+	var names []string
+	if serviceConfig.Host == AppServiceTarget {
+		for name, value := range serviceConfig.Environment {
+			if keyvault.IsSecretExpression(value.Template()) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	if serviceConfig.AppSettings != nil {
+		for _, mapping := range []map[string]string{serviceConfig.AppSettings.Settings, serviceConfig.AppSettings.Secrets} {
+			for name, value := range mapping {
+				if keyvault.IsSecretExpression(value) {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+
+	slices.Sort(names)
+	return names
+}
+
+// checkWebAppKeyVaultReferences checks that App Service resolves the Key Vault references in the app settings of an
+// App Service or a function app, or of its deployment slot when slotName isn't empty. The errors explain how to grant
+// the managed identity of the app access to the secrets. References that App Service hasn't resolved yet are skipped.
+func checkWebAppKeyVaultReferences(
+	ctx context.Context,
+	cli *azapi.AzureClient,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	slotName string,
+) error {
+	appName := targetResource.ResourceName()
+	for _, name := range webAppKeyVaultReferences(serviceConfig) {
+		reference, err := cli.GetAppServiceKeyVaultReference(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			appName,
+			slotName,
+			name,
+		)
+		if err != nil {
+			return err
+		}
+
+		var status armappservice.ResolveStatus
+		if reference.Status != nil {
+			status = *reference.Status
+		}
+
+		vaultName := "<vault>"
+		if reference.VaultName != nil {
+			vaultName = *reference.VaultName
+		}
+
+		var suggestion string
+		switch status {
+		case armappservice.ResolveStatusResolved:
+			continue
+		case "", armappservice.ResolveStatusInitialized:
+			log.Printf("the Key Vault reference of app setting %s of %s isn't resolved yet", name, appName)
+			continue
+		case armappservice.ResolveStatusAccessToKeyVaultDenied, armappservice.ResolveStatusUnauthorizedClient:
+			suggestion = fmt.Sprintf(
+				"Assign the 'Key Vault Secrets User' role on Key Vault '%s' to the managed identity of '%s', or, when "+
+					"the vault uses access policies, allow the identity to get secrets.", vaultName, appName)
+		case armappservice.ResolveStatusMSINotEnabled:
+			suggestion = fmt.Sprintf(
+				"Enable a managed identity on '%s', and assign it the 'Key Vault Secrets User' role on Key Vault '%s'.",
+				appName, vaultName)
+		case armappservice.ResolveStatusVaultNotFound,
+			armappservice.ResolveStatusSecretNotFound,
+			armappservice.ResolveStatusSecretVersionNotFound:
+			suggestion = "Check the names of the Key Vault and the secret in azure.yaml."
+		default:
+			suggestion = "Check the Key Vault references of the app in the Azure portal, under 'Environment variables'."
+		}
+
+		details := string(status)
+		if reference.Details != nil && *reference.Details != "" {
+			details = *reference.Details
+		}
+
+		return &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf(
+				"app setting %s of %s references a Key Vault secret that App Service can't resolve: %s",
+				name, appName, details),
+			Suggestion: suggestion,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazapi"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSecretsKeyVaultService returns the secrets of a Key Vault, and denies access to the vault named 'denied'.
+type fakeSecretsKeyVaultService struct {
+	keyvault.KeyVaultService
+
+	secrets map[string]string
+}
+
+func (f *fakeSecretsKeyVaultService) GetKeyVaultSecret(
+	ctx context.Context, subscriptionId string, vaultName string, secretName string,
+) (*keyvault.Secret, error) {
+	if vaultName == "denied" {
+		return nil, &azcore.ResponseError{StatusCode: http.StatusForbidden, ErrorCode: "Forbidden"}
+	}
+
+	value, has := f.secrets[vaultName+"/"+secretName]
+	if !has {
+		return nil, keyvault.ErrAzCliSecretNotFound
+	}
+
+	return &keyvault.Secret{Name: secretName, Value: value}, nil
+}
+
+func Test_validateSecretExpressions(t *testing.T) {
+	scope := "service 'api'"
+	require.Empty(t, validateSecretExpressions(&ServiceConfig{
+		Host: AppServiceTarget,
+		Environment: osutil.ExpandableMap{
+			"DATABASE_PASSWORD": osutil.NewExpandableString("${keyvault:my-vault/db-password}"),
+			"PORT":              osutil.NewExpandableString("${PORT}"),
+		},
+		AppSettings: &AppSettingsOptions{
+			Secrets: map[string]string{"API_KEY": "${keyvault:my-vault/api-key}"},
+		},
+	}, scope))
+
+	problems := validateSecretExpressions(&ServiceConfig{
+		Host: ContainerAppTarget,
+		Environment: osutil.ExpandableMap{
+			"DATABASE_PASSWORD": osutil.NewExpandableString("${keyvault:my-vault}"),
+		},
+		AppSettings: &AppSettingsOptions{
+			Secrets: map[string]string{"API_KEY": "key=${keyvault:my-vault/api-key}"},
+		},
+	}, scope)
+	require.Len(t, problems, 2)
+	require.Contains(t, problems[0], "env 'DATABASE_PASSWORD': invalid Key Vault secret reference")
+	require.Contains(t, problems[1], "app setting 'API_KEY': invalid Key Vault secret reference")
+
+	problems = validateSecretExpressions(&ServiceConfig{
+		Host: AzureFunctionTarget,
+		Environment: osutil.ExpandableMap{
+			"DATABASE_PASSWORD": osutil.NewExpandableString("${keyvault:my-vault/db-password}"),
+		},
+	}, scope)
+	require.Len(t, problems, 1)
+	require.Contains(t, problems[0], "only supported in env for services with host 'appservice' or 'containerapp'")
+}
+
+func Test_expandServiceEnvironment(t *testing.T) {
+	env := environment.NewWithValues("test", map[string]string{"DATABASE_HOST": "db.contoso.com"})
+	keyvaultService := &fakeSecretsKeyVaultService{
+		secrets: map[string]string{"my-vault/db-password": "p@$$"},
+	}
+	serviceConfig := &ServiceConfig{
+		Name: "api",
+		Environment: osutil.ExpandableMap{
+			"DATABASE_HOST":     osutil.NewExpandableString("${DATABASE_HOST}"),
+			"DATABASE_PASSWORD": osutil.NewExpandableString("${keyvault:my-vault/db-password}"),
+			"CONNECTION_STRING": osutil.NewExpandableString(
+				"Host=${DATABASE_HOST};Password=${keyvault:my-vault/db-password}"),
+		},
+	}
+
+	t.Run("Resolved", func(t *testing.T) {
+		envVars, err := expandServiceEnvironment(t.Context(), keyvaultService, env, serviceConfig, "SUB_ID", nil)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"DATABASE_HOST":     "db.contoso.com",
+			"DATABASE_PASSWORD": "p@$$",
+			"CONNECTION_STRING": "Host=db.contoso.com;Password=p@$$",
+		}, envVars)
+	})
+
+	t.Run("Referenced", func(t *testing.T) {
+		envVars, err := expandServiceEnvironment(
+			t.Context(), keyvaultService, env, serviceConfig, "SUB_ID", keyvault.SecretExpression.AppServiceReference)
+		require.NoError(t, err)
+		require.Equal(t, "@Microsoft.KeyVault(VaultName=my-vault;SecretName=db-password)", envVars["DATABASE_PASSWORD"])
+		require.Equal(t, "Host=db.contoso.com;Password=p@$$", envVars["CONNECTION_STRING"])
+	})
+
+	t.Run("AccessDenied", func(t *testing.T) {
+		_, err := expandServiceEnvironment(t.Context(), keyvaultService, env, &ServiceConfig{
+			Environment: osutil.ExpandableMap{"KEY": osutil.NewExpandableString("${keyvault:denied/key}")},
+		}, "SUB_ID", nil)
+		suggestion, ok := errors.AsType[*internal.ErrorWithSuggestion](err)
+		require.True(t, ok)
+		require.ErrorContains(t, err, "access to Key Vault 'denied' was denied")
+		require.Contains(t, suggestion.Suggestion, "'Key Vault Secrets User' role on Key Vault 'denied'")
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := expandServiceEnvironment(t.Context(), keyvaultService, env, &ServiceConfig{
+			Environment: osutil.ExpandableMap{"KEY": osutil.NewExpandableString("${keyvault:my-vault/missing}")},
+		}, "SUB_ID", nil)
+		require.ErrorContains(t, err, "secret 'missing' was not found in Key Vault 'my-vault'")
+	})
+}
+
+func Test_checkWebAppKeyVaultReferences(t *testing.T) {
+	check := func(t *testing.T, status armappservice.ResolveStatus) error {
+		mockContext := mocks.NewMockContext(t.Context())
+		mockContext.HttpClient.When(func(req *http.Request) bool {
+			return req.Method == http.MethodGet &&
+				strings.HasSuffix(req.URL.Path, "/config/configreferences/appsettings/DATABASE_PASSWORD")
+		}).RespondFn(func(req *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(req, http.StatusOK, armappservice.APIKVReference{
+				Properties: &armappservice.APIKVReferenceProperties{
+					Status:    &status,
+					VaultName: new("my-vault"),
+				},
+			})
+		})
+
+		serviceConfig := &ServiceConfig{
+			Name: "api",
+			Host: AppServiceTarget,
+			Environment: osutil.ExpandableMap{
+				"DATABASE_PASSWORD": osutil.NewExpandableString("${keyvault:my-vault/db-password}"),
+				"LOG_LEVEL":         osutil.NewExpandableString("info"),
+			},
+		}
+		targetResource := environment.NewTargetResource(
+			"SUB_ID", "RG_ID", "WEB_APP_NAME", string(azapi.AzureResourceTypeWebSite))
+
+		return checkWebAppKeyVaultReferences(
+			*mockContext.Context,
+			mockazapi.NewAzureClientFromMockContext(mockContext),
+			serviceConfig,
+			targetResource,
+			"",
+		)
+	}
+
+	require.NoError(t, check(t, armappservice.ResolveStatusResolved))
+	require.NoError(t, check(t, armappservice.ResolveStatusInitialized))
+
+	err := check(t, armappservice.ResolveStatusAccessToKeyVaultDenied)
+	suggestion, ok := errors.AsType[*internal.ErrorWithSuggestion](err)
+	require.True(t, ok)
+	require.ErrorContains(t, err, "app setting DATABASE_PASSWORD of WEB_APP_NAME references a Key Vault secret")
+	require.Contains(t, suggestion.Suggestion, "to the managed identity of 'WEB_APP_NAME'")
+}
@@ -0,0 +1,197 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/node"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/python"
+)
+
+// ErrNoLocalRunCommand is returned when the command that starts a service locally can't be inferred from its language
+// and host, and isn't set in the run section of the service.
+var ErrNoLocalRunCommand = errors.New("no command to run the service locally")
+
+// LocalRunOptions configure how `azd run` starts a service locally, set in the run section of a service in azure.yaml.
+type LocalRunOptions struct {
+	// Command starts the service, run in a shell from the directory of the service. When empty, the command is
+	// inferred from the language and the host of the service.
+	Command string `yaml:"command,omitempty"`
+	// Port is the port the service listens on. It's set in the PORT environment variable of the service, and the
+	// services that use the service are started once it accepts connections.
+	Port int `yaml:"port,omitempty"`
+}
+
+// LocalRunPort returns the port the service listens on when it's run locally, or zero when it isn't known.
+func (sc *ServiceConfig) LocalRunPort() int {
+	if sc.Run == nil {
+		return 0
+	}
+
+	return sc.Run.Port
+}
+
+// LocalRunCommand returns the shell command that starts the service locally, and the directory it's run from. The
+// command is the run.command of the service, or else it's inferred from the host and the language of the service:
+//   - Function apps: func start, with the Azure Functions Core Tools.
+//   - .NET: dotnet run.
+//   - JavaScript and TypeScript: the start script, or else the dev script, of package.json, with the package manager
+//     of the project.
+//   - Python: manage.py runserver for Django apps, uvicorn for FastAPI apps, flask run for Flask apps, or else the
+//     main.py or app.py script, with the Python of the virtual environment of the service when there's one.
+//   - Java: the spring-boot:run goal of Maven, or the bootRun task of Gradle.
+//   - Go: go run.
+func (sc *ServiceConfig) LocalRunCommand() (string, string, error) {
+	path := sc.Path()
+	dir := path
+	projectFile := ""
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		dir = filepath.Dir(path)
+		projectFile = filepath.Base(path)
+	}
+
+	if sc.Run != nil && sc.Run.Command != "" {
+		return sc.Run.Command, dir, nil
+	}
+
+	if sc.Host == AzureFunctionTarget {
+		return "func start", dir, nil
+	}
+
+	port := sc.LocalRunPort()
+	command := ""
+	switch sc.Language {
+	case ServiceLanguageDotNet, ServiceLanguageCsharp, ServiceLanguageFsharp:
+		command = "dotnet run"
+		if projectFile != "" {
+			command += " --project " + strconv.Quote(projectFile)
+		}
+	case ServiceLanguageJavaScript, ServiceLanguageTypeScript:
+		command = nodeRunCommand(dir)
+	case ServiceLanguagePython:
+		command = pythonRunCommand(dir, port)
+	case ServiceLanguageJava:
+		command = javaRunCommand(dir)
+	case ServiceLanguageGo:
+		command = "go run ."
+	}
+
+	if command == "" {
+		return "", dir, fmt.Errorf("service '%s' with language '%s': %w", sc.Name, sc.Language, ErrNoLocalRunCommand)
+	}
+
+	return command, dir, nil
+}
+
+// nodeRunCommand returns the command that runs the start script, or else the dev script, of the package.json of dir.
+func nodeRunCommand(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return ""
+	}
+
+	var packageJson struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal(data, &packageJson); err != nil {
+		return ""
+	}
+
+	packageManager := string(node.DetectPackageManager(dir))
+	for _, script := range []string{"start", "dev"} {
+		if _, has := packageJson.Scripts[script]; has {
+			return packageManager + " run " + script
+		}
+	}
+
+	return ""
+}
+
+// pythonRunCommand returns the command that runs the Python app of dir, listening on port when it's not zero.
+func pythonRunCommand(dir string, port int) string {
+	interpreter := "python"
+	for _, venv := range []string{python.VenvNameForDir(dir), ".venv"} {
+		venvPython := python.VenvPythonPath(filepath.Join(dir, venv))
+		if _, err := os.Stat(venvPython); err == nil {
+			interpreter = strconv.Quote(venvPython)
+			break
+		}
+	}
+
+	if fileExists(filepath.Join(dir, "manage.py")) {
+		command := interpreter + " manage.py runserver"
+		if port != 0 {
+			command += " " + strconv.Itoa(port)
+		}
+		return command
+	}
+
+	module := ""
+	for _, script := range []string{"main.py", "app.py"} {
+		if fileExists(filepath.Join(dir, script)) {
+			module = strings.TrimSuffix(script, ".py")
+			break
+		}
+	}
+	if module == "" {
+		return ""
+	}
+
+	dependencies := ""
+	for _, file := range []string{"requirements.txt", "pyproject.toml"} {
+		if data, err := os.ReadFile(filepath.Join(dir, file)); err == nil {
+			dependencies += strings.ToLower(string(data))
+		}
+	}
+
+	portArg := ""
+	if port != 0 {
+		portArg = " --port " + strconv.Itoa(port)
+	}
+
+	switch {
+	case strings.Contains(dependencies, "fastapi") || strings.Contains(dependencies, "uvicorn"):
+		return fmt.Sprintf("%s -m uvicorn %s:app --reload%s", interpreter, module, portArg)
+	case strings.Contains(dependencies, "flask"):
+		return fmt.Sprintf("%s -m flask --app %s run%s", interpreter, module, portArg)
+	default:
+		return interpreter + " " + module + ".py"
+	}
+}
+
+// javaRunCommand returns the command that runs the Spring Boot app of dir with its Maven or Gradle build.
+func javaRunCommand(dir string) string {
+	wrapper := func(name string) string {
+		if runtime.GOOS == "windows" {
+			return name + ".cmd"
+		}
+		return "./" + name
+	}
+
+	switch {
+	case fileExists(filepath.Join(dir, "mvnw")):
+		return wrapper("mvnw") + " spring-boot:run"
+	case fileExists(filepath.Join(dir, "pom.xml")):
+		return "mvn spring-boot:run"
+	case fileExists(filepath.Join(dir, "gradlew")):
+		return wrapper("gradlew") + " bootRun"
+	case fileExists(filepath.Join(dir, "build.gradle")), fileExists(filepath.Join(dir, "build.gradle.kts")):
+		return "gradle bootRun"
+	default:
+		return ""
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
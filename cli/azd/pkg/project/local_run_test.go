@@ -0,0 +1,165 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/python"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalRunCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		language ServiceLanguageKind
+		host     ServiceTargetKind
+		files    map[string]string
+		run      *LocalRunOptions
+		want     string
+	}{
+		{
+			name:     "Command",
+			language: ServiceLanguagePython,
+			run:      &LocalRunOptions{Command: "make dev"},
+			want:     "make dev",
+		},
+		{
+			name:     "FunctionApp",
+			language: ServiceLanguagePython,
+			host:     AzureFunctionTarget,
+			want:     "func start",
+		},
+		{
+			name:     "DotNet",
+			language: ServiceLanguageCsharp,
+			want:     "dotnet run",
+		},
+		{
+			name:     "NodeStart",
+			language: ServiceLanguageTypeScript,
+			files: map[string]string{
+				"package.json":   `{"scripts": {"dev": "vite", "start": "node server.js"}}`,
+				"pnpm-lock.yaml": "",
+			},
+			want: "pnpm run start",
+		},
+		{
+			name:     "NodeDev",
+			language: ServiceLanguageJavaScript,
+			files:    map[string]string{"package.json": `{"scripts": {"dev": "vite"}}`},
+			want:     "npm run dev",
+		},
+		{
+			name:     "FastApi",
+			language: ServiceLanguagePython,
+			files:    map[string]string{"main.py": "", "requirements.txt": "FastAPI==0.110\nuvicorn"},
+			run:      &LocalRunOptions{Port: 8000},
+			want:     "python -m uvicorn main:app --reload --port 8000",
+		},
+		{
+			name:     "Flask",
+			language: ServiceLanguagePython,
+			files:    map[string]string{"app.py": "", "requirements.txt": "flask"},
+			want:     "python -m flask --app app run",
+		},
+		{
+			name:     "Django",
+			language: ServiceLanguagePython,
+			files:    map[string]string{"manage.py": "", "app.py": ""},
+			run:      &LocalRunOptions{Port: 8000},
+			want:     "python manage.py runserver 8000",
+		},
+		{
+			name:     "Maven",
+			language: ServiceLanguageJava,
+			files:    map[string]string{"pom.xml": ""},
+			want:     "mvn spring-boot:run",
+		},
+		{
+			name:     "Go",
+			language: ServiceLanguageGo,
+			want:     "go run .",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+			}
+
+			svc := &ServiceConfig{
+				Name:         "api",
+				Project:      &ProjectConfig{Path: dir},
+				RelativePath: ".",
+				Language:     tt.language,
+				Host:         tt.host,
+				Run:          tt.run,
+			}
+
+			command, commandDir, err := svc.LocalRunCommand()
+			require.NoError(t, err)
+			require.Equal(t, tt.want, command)
+			require.Equal(t, dir, commandDir)
+		})
+	}
+}
+
+func TestLocalRunCommand_ProjectFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Api.csproj"), nil, 0600))
+
+	svc := &ServiceConfig{
+		Name:         "api",
+		Project:      &ProjectConfig{Path: dir},
+		RelativePath: "Api.csproj",
+		Language:     ServiceLanguageDotNet,
+	}
+
+	command, commandDir, err := svc.LocalRunCommand()
+	require.NoError(t, err)
+	require.Equal(t, `dotnet run --project "Api.csproj"`, command)
+	require.Equal(t, dir, commandDir)
+}
+
+func TestLocalRunCommand_PythonVirtualEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the virtual environment layout differs on Windows")
+	}
+
+	dir := t.TempDir()
+	venvPython := python.VenvPythonPath(filepath.Join(dir, ".venv"))
+	require.NoError(t, os.MkdirAll(filepath.Dir(venvPython), 0700))
+	require.NoError(t, os.WriteFile(venvPython, nil, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.py"), nil, 0600))
+
+	svc := &ServiceConfig{
+		Name:         "api",
+		Project:      &ProjectConfig{Path: dir},
+		RelativePath: ".",
+		Language:     ServiceLanguagePython,
+	}
+
+	command, _, err := svc.LocalRunCommand()
+	require.NoError(t, err)
+	require.Equal(t, strconv.Quote(venvPython)+" main.py", command)
+}
+
+func TestLocalRunCommand_NotInferred(t *testing.T) {
+	svc := &ServiceConfig{
+		Name:         "api",
+		Project:      &ProjectConfig{Path: t.TempDir()},
+		RelativePath: ".",
+		Language:     ServiceLanguageDocker,
+	}
+
+	_, _, err := svc.LocalRunCommand()
+	require.ErrorIs(t, err, ErrNoLocalRunCommand)
+}
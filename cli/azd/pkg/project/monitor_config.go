@@ -0,0 +1,78 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/monitorquery"
+)
+
+// MonitorConfig is the configuration used by `azd monitor`, set in the monitor section of azure.yaml.
+type MonitorConfig struct {
+	// Queries are the KQL queries run with `azd monitor --query <name>`, by name. A query replaces the predefined
+	// query with the same name.
+	Queries map[string]*MonitorQueryConfig `yaml:"queries,omitempty"`
+}
+
+// MonitorQueryConfig is a KQL query declared in azure.yaml.
+type MonitorQueryConfig struct {
+	// Description is shown when the available queries are listed.
+	Description string `yaml:"description,omitempty"`
+	// Source is the resource the query runs against, appInsights by default or logAnalytics.
+	Source monitorquery.Source `yaml:"source,omitempty"`
+	// Query is the KQL query.
+	Query string `yaml:"query"`
+}
+
+// Validate returns an error when a query has no KQL or an unknown source.
+func (c *MonitorConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(c.Queries)) {
+		query := c.Queries[name]
+		if query == nil || query.Query == "" {
+			return fmt.Errorf("query '%s' must set 'query'", name)
+		}
+
+		switch query.Source {
+		case "", monitorquery.SourceAppInsights, monitorquery.SourceLogAnalytics:
+		default:
+			return fmt.Errorf("query '%s' has an invalid source '%s', expected '%s' or '%s'",
+				name, query.Source, monitorquery.SourceAppInsights, monitorquery.SourceLogAnalytics)
+		}
+	}
+
+	return nil
+}
+
+// MonitorQueries returns the queries declared in the monitor section of azure.yaml, sorted by name.
+func (p *ProjectConfig) MonitorQueries() []monitorquery.Query {
+	if p.Monitor == nil {
+		return nil
+	}
+
+	var queries []monitorquery.Query
+	for _, name := range slices.Sorted(maps.Keys(p.Monitor.Queries)) {
+		query := p.Monitor.Queries[name]
+
+		source := query.Source
+		if source == "" {
+			source = monitorquery.SourceAppInsights
+		}
+
+		queries = append(queries, monitorquery.Query{
+			Name:        name,
+			Description: query.Description,
+			Source:      source,
+			Kql:         query.Query,
+		})
+	}
+
+	return queries
+}
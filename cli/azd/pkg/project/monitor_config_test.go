@@ -0,0 +1,83 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/monitorquery"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMonitorConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name: "Valid",
+			yaml: `
+name: test-proj
+monitor:
+  queries:
+    slow-requests:
+      description: Requests slower than a second
+      query: requests | where duration > 1000
+    console:
+      source: logAnalytics
+      query: ContainerAppConsoleLogs_CL | take 50
+`,
+		},
+		{
+			name: "NoQuery",
+			yaml: `
+name: test-proj
+monitor:
+  queries:
+    slow-requests:
+      description: Requests slower than a second
+`,
+			wantErr: "query 'slow-requests' must set 'query'",
+		},
+		{
+			name: "InvalidSource",
+			yaml: `
+name: test-proj
+monitor:
+  queries:
+    console:
+      source: storage
+      query: ContainerAppConsoleLogs_CL
+`,
+			wantErr: "query 'console' has an invalid source 'storage'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectConfig, err := Parse(t.Context(), tt.yaml)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, "parsing monitor")
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, []monitorquery.Query{
+				{
+					Name:   "console",
+					Source: monitorquery.SourceLogAnalytics,
+					Kql:    "ContainerAppConsoleLogs_CL | take 50",
+				},
+				{
+					Name:        "slow-requests",
+					Description: "Requests slower than a second",
+					Source:      monitorquery.SourceAppInsights,
+					Kql:         "requests | where duration > 1000",
+				},
+			}, projectConfig.MonitorQueries())
+		})
+	}
+}
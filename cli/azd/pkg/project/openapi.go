@@ -0,0 +1,279 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kiota"
+	"github.com/braydonk/yaml"
+)
+
+// OpenApiOptions declares the OpenAPI description of the API implemented by a service. The description is validated and
+// the declared clients are generated when the service is packaged, and the deployed API is published to a developer
+// portal when the service is deployed.
+type OpenApiOptions struct {
+	// Spec is the path of the OpenAPI description, relative to the service project.
+	Spec string `yaml:"spec"`
+	// Clients are the API clients generated from the description when the service is packaged.
+	Clients []OpenApiClientOptions `yaml:"clients,omitempty"`
+	// Publish configures where the deployed API is published.
+	Publish *OpenApiPublishOptions `yaml:"publish,omitempty"`
+}
+
+// OpenApiClientOptions is an API client generated with Kiota.
+type OpenApiClientOptions struct {
+	// Language is the language of the client, for example typescript or python.
+	Language string `yaml:"language"`
+	// Output is the directory the client is generated in, relative to the service project.
+	Output string `yaml:"output"`
+	// ClassName is the name of the client class.
+	ClassName string `yaml:"className,omitempty"`
+}
+
+// OpenApiPublishOptions configures the developer portal the deployed API is published to.
+type OpenApiPublishOptions struct {
+	// ApiCenter is the resource ID of the Azure API Center service the API is registered in.
+	ApiCenter osutil.ExpandableString `yaml:"apiCenter"`
+	// Name is the name of the API in API Center. Defaults to the name of the service.
+	Name string `yaml:"name,omitempty"`
+}
+
+// openApiDocument holds the parts of an OpenAPI description that azd validates.
+type openApiDocument struct {
+	OpenApi string `yaml:"openapi"`
+	Swagger string `yaml:"swagger"`
+	Info    struct {
+		Title   string `yaml:"title"`
+		Version string `yaml:"version"`
+	} `yaml:"info"`
+	// Paths maps each path to its path item. Path items hold operations keyed by HTTP method, alongside fields such as
+	// parameters and summary.
+	Paths map[string]map[string]any `yaml:"paths"`
+}
+
+// openApiMethods are the keys of the operations of an OpenAPI path item.
+var openApiMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// validateOpenApiOptions checks the OpenAPI options of a service for problems that can be detected when azure.yaml is
+// parsed.
+func validateOpenApiOptions(options *OpenApiOptions, scope string) []string {
+	if options == nil {
+		return nil
+	}
+
+	var problems []string
+	if options.Spec == "" {
+		problems = append(problems, fmt.Sprintf("%s: openapi.spec must be specified", scope))
+	}
+
+	for i, client := range options.Clients {
+		if !slices.Contains(kiota.Languages, client.Language) {
+			problems = append(problems, fmt.Sprintf(
+				"%s: openapi.clients[%d].language '%s' is not supported, supported languages: %s",
+				scope, i, client.Language, strings.Join(kiota.Languages, ", ")))
+		}
+
+		if client.Output == "" {
+			problems = append(problems, fmt.Sprintf("%s: openapi.clients[%d].output must be specified", scope, i))
+		}
+	}
+
+	if options.Publish != nil && options.Publish.ApiCenter.Empty() {
+		problems = append(problems, fmt.Sprintf("%s: openapi.publish.apiCenter must be specified", scope))
+	}
+
+	return problems
+}
+
+// loadOpenApiSpec reads and validates the OpenAPI description of the service.
+func loadOpenApiSpec(serviceConfig *ServiceConfig) (*openApiDocument, []byte, error) {
+	specPath := openApiPath(serviceConfig, serviceConfig.OpenApi.Spec)
+	contents, err := os.ReadFile(specPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading OpenAPI description: %w", err)
+	}
+
+	// YAML is a superset of JSON, so this parses descriptions in both formats.
+	var document openApiDocument
+	if err := yaml.Unmarshal(contents, &document); err != nil {
+		return nil, nil, fmt.Errorf("parsing OpenAPI description %s: %w", specPath, err)
+	}
+
+	if problems := document.validate(); len(problems) > 0 {
+		return nil, nil, fmt.Errorf("OpenAPI description %s is invalid:\n  - %s", specPath, strings.Join(problems, "\n  - "))
+	}
+
+	return &document, contents, nil
+}
+
+// validate returns the problems of the description that prevent generating clients and server stubs from it.
+func (d *openApiDocument) validate() []string {
+	var problems []string
+	if d.Swagger != "" {
+		problems = append(problems, "Swagger 2.0 descriptions are not supported, convert the description to OpenAPI 3")
+	} else if !strings.HasPrefix(d.OpenApi, "3.") {
+		problems = append(problems, "the 'openapi' field must declare an OpenAPI 3 version")
+	}
+
+	if d.Info.Title == "" {
+		problems = append(problems, "info.title must be specified")
+	}
+
+	if d.Info.Version == "" {
+		problems = append(problems, "info.version must be specified")
+	}
+
+	if len(d.Paths) == 0 {
+		problems = append(problems, "the description has no paths")
+	}
+
+	// Clients and server stubs name their methods and handlers after the operation IDs.
+	operationIds := map[string]string{}
+	for _, path := range slices.Sorted(maps.Keys(d.Paths)) {
+		for _, method := range openApiMethods {
+			operation, has := d.Paths[path][method].(map[string]any)
+			if !has {
+				continue
+			}
+
+			operationId, _ := operation["operationId"].(string)
+			if operationId == "" {
+				problems = append(problems,
+					fmt.Sprintf("operation %s %s must have an operationId", strings.ToUpper(method), path))
+				continue
+			}
+
+			if previous, has := operationIds[operationId]; has {
+				problems = append(problems, fmt.Sprintf(
+					"operationId '%s' of %s %s is already used by %s",
+					operationId, strings.ToUpper(method), path, previous))
+				continue
+			}
+
+			operationIds[operationId] = fmt.Sprintf("%s %s", strings.ToUpper(method), path)
+		}
+	}
+
+	return problems
+}
+
+// packageOpenApi validates the OpenAPI description of the service and generates its clients.
+func (sm *serviceManager) packageOpenApi(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	progress *async.Progress[ServiceProgress],
+) error {
+	if serviceConfig.OpenApi == nil {
+		return nil
+	}
+
+	progress.SetProgress(NewServiceProgress("Validating OpenAPI description"))
+	if _, _, err := loadOpenApiSpec(serviceConfig); err != nil {
+		return err
+	}
+
+	if len(serviceConfig.OpenApi.Clients) == 0 {
+		return nil
+	}
+
+	var kiotaCli *kiota.Cli
+	if err := sm.serviceLocator.Resolve(&kiotaCli); err != nil {
+		return fmt.Errorf("resolving kiota: %w", err)
+	}
+
+	for _, client := range serviceConfig.OpenApi.Clients {
+		progress.SetProgress(NewServiceProgress(fmt.Sprintf("Generating %s client", client.Language)))
+		if err := kiotaCli.Generate(ctx, kiota.GenerateOptions{
+			SpecPath:   openApiPath(serviceConfig, serviceConfig.OpenApi.Spec),
+			Language:   client.Language,
+			OutputPath: openApiPath(serviceConfig, client.Output),
+			ClassName:  client.ClassName,
+		}, nil); err != nil {
+			return fmt.Errorf("generating %s client: %w", client.Language, err)
+		}
+	}
+
+	return nil
+}
+
+// publishOpenApi registers the deployed API, with its OpenAPI description and endpoints, in the developer portal of the
+// service.
+func (sm *serviceManager) publishOpenApi(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	deployResult *ServiceDeployResult,
+	progress *async.Progress[ServiceProgress],
+) error {
+	if serviceConfig.OpenApi == nil || serviceConfig.OpenApi.Publish == nil {
+		return nil
+	}
+
+	apiCenterId, err := serviceConfig.OpenApi.Publish.ApiCenter.Envsubst(sm.env.Getenv)
+	if err != nil {
+		return fmt.Errorf("expanding openapi.publish.apiCenter: %w", err)
+	}
+
+	if apiCenterId == "" {
+		return errors.New("openapi.publish.apiCenter is empty after expanding environment variables")
+	}
+
+	document, contents, err := loadOpenApiSpec(serviceConfig)
+	if err != nil {
+		return err
+	}
+
+	var runtimeUris []string
+	for _, endpoint := range deployResult.Artifacts.Find(WithKind(ArtifactKindEndpoint)) {
+		runtimeUris = append(runtimeUris, endpoint.Location)
+	}
+
+	name := serviceConfig.OpenApi.Publish.Name
+	if name == "" {
+		name = serviceConfig.Name
+	}
+
+	var apiCenterService *azapi.ApiCenterService
+	if err := sm.serviceLocator.Resolve(&apiCenterService); err != nil {
+		return fmt.Errorf("resolving API Center service: %w", err)
+	}
+
+	progress.SetProgress(NewServiceProgress("Publishing API to API Center"))
+	return apiCenterService.PublishApi(ctx, apiCenterId, azapi.ApiCenterApi{
+		Name:                 apiCenterName(name),
+		Title:                document.Info.Title,
+		Version:              apiCenterName(document.Info.Version),
+		Environment:          apiCenterName(sm.env.Name()),
+		Specification:        string(contents),
+		SpecificationVersion: document.OpenApi,
+		RuntimeUris:          runtimeUris,
+	})
+}
+
+// openApiPath resolves a path of the OpenAPI options against the service project.
+func openApiPath(serviceConfig *ServiceConfig, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(serviceConfig.Path(), filepath.FromSlash(path))
+}
+
+var apiCenterNameInvalidChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// apiCenterName converts a value to a name accepted by API Center, which only allows letters, digits and hyphens.
+func apiCenterName(value string) string {
+	return strings.Trim(apiCenterNameInvalidChars.ReplaceAllString(strings.ToLower(value), "-"), "-")
+}
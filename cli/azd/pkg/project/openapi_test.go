@@ -0,0 +1,267 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kiota"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+)
+
+const testOpenApiSpec = `openapi: 3.0.3
+info:
+  title: Todo API
+  version: 1.0.0
+paths:
+  /items:
+    parameters:
+      - name: filter
+        in: query
+    get:
+      operationId: listItems
+    post:
+      operationId: createItem
+  /items/{id}:
+    get:
+      operationId: getItem
+`
+
+func Test_validateOpenApiOptions(t *testing.T) {
+	require.Nil(t, validateOpenApiOptions(nil, "service 'api'"))
+	require.Empty(t, validateOpenApiOptions(&OpenApiOptions{
+		Spec:    "openapi.yaml",
+		Clients: []OpenApiClientOptions{{Language: "typescript", Output: "../web/client"}},
+		Publish: &OpenApiPublishOptions{ApiCenter: osutil.NewExpandableString("${API_CENTER_ID}")},
+	}, "service 'api'"))
+
+	problems := validateOpenApiOptions(&OpenApiOptions{
+		Clients: []OpenApiClientOptions{{Language: "cobol"}},
+		Publish: &OpenApiPublishOptions{},
+	}, "service 'api'")
+	require.Equal(t, []string{
+		"service 'api': openapi.spec must be specified",
+		"service 'api': openapi.clients[0].language 'cobol' is not supported, " +
+			"supported languages: csharp, dart, go, java, php, python, ruby, typescript",
+		"service 'api': openapi.clients[0].output must be specified",
+		"service 'api': openapi.publish.apiCenter must be specified",
+	}, problems)
+}
+
+func Test_loadOpenApiSpec(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		problems []string
+	}{
+		{
+			name: "Yaml",
+			spec: testOpenApiSpec,
+		},
+		{
+			name: "Json",
+			spec: `{"openapi": "3.1.0", "info": {"title": "Todo API", "version": "1"},` +
+				` "paths": {"/items": {"get": {"operationId": "listItems"}}}}`,
+		},
+		{
+			name: "Swagger",
+			spec: `{"swagger": "2.0", "info": {"title": "Todo API", "version": "1"},` +
+				` "paths": {"/items": {"get": {"operationId": "listItems"}}}}`,
+			problems: []string{"Swagger 2.0 descriptions are not supported"},
+		},
+		{
+			name: "MissingInfoAndPaths",
+			spec: `openapi: 3.0.3`,
+			problems: []string{
+				"info.title must be specified",
+				"info.version must be specified",
+				"the description has no paths",
+			},
+		},
+		{
+			name: "OperationIds",
+			spec: `openapi: 3.0.3
+info:
+  title: Todo API
+  version: 1.0.0
+paths:
+  /items:
+    get:
+      operationId: listItems
+    delete:
+      summary: Delete all items
+  /todos:
+    get:
+      operationId: listItems
+`,
+			problems: []string{
+				"operation DELETE /items must have an operationId",
+				"operationId 'listItems' of GET /todos is already used by GET /items",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "openapi.yaml"), []byte(tt.spec), osutil.PermissionFile))
+
+			serviceConfig := createTestServiceConfig(dir, ServiceTargetFake, ServiceLanguageFake)
+			serviceConfig.OpenApi = &OpenApiOptions{Spec: "openapi.yaml"}
+
+			document, contents, err := loadOpenApiSpec(serviceConfig)
+			if len(tt.problems) > 0 {
+				for _, problem := range tt.problems {
+					require.ErrorContains(t, err, problem)
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, "Todo API", document.Info.Title)
+			require.Equal(t, tt.spec, string(contents))
+		})
+	}
+}
+
+func Test_apiCenterName(t *testing.T) {
+	require.Equal(t, "1-0-0", apiCenterName("1.0.0"))
+	require.Equal(t, "todo-api", apiCenterName("Todo_API"))
+	require.Equal(t, "v2", apiCenterName("(v2)"))
+}
+
+func Test_ServiceManager_Package_OpenApi(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	setupMocksForServiceManager(mockContext)
+	mockContext.Container.MustRegisterSingleton(kiota.NewCli)
+
+	var generateArgs []string
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.HasPrefix(command, "kiota generate")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		generateArgs = args.Args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "openapi.yaml"), []byte(testOpenApiSpec), osutil.PermissionFile))
+
+	env := environment.New("test")
+	sm := createServiceManager(mockContext, env, ServiceOperationCache{})
+	serviceConfig := createTestServiceConfig(dir, ServiceTargetFake, ServiceLanguageFake)
+	serviceConfig.OpenApi = &OpenApiOptions{
+		Spec:    "openapi.yaml",
+		Clients: []OpenApiClientOptions{{Language: "typescript", Output: "../web/client", ClassName: "TodoClient"}},
+	}
+
+	requiredTools, err := sm.GetRequiredTools(*mockContext.Context, serviceConfig)
+	require.NoError(t, err)
+	require.True(t, slices.ContainsFunc(requiredTools, func(tool tools.ExternalTool) bool {
+		return tool.Name() == "Kiota"
+	}))
+
+	ctx := context.WithValue(*mockContext.Context, frameworkPackageCalled, new(false))
+	ctx = context.WithValue(ctx, serviceTargetPackageCalled, new(false))
+	_, err = logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServicePackageResult, error) {
+		return sm.Package(ctx, serviceConfig, nil, progress, nil)
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		"generate",
+		"--openapi", filepath.Join(dir, "openapi.yaml"),
+		"--language", "typescript",
+		"--output", filepath.Join(filepath.Dir(dir), "web", "client"),
+		"--clean-output",
+		"--class-name", "TodoClient",
+	}, generateArgs)
+}
+
+func Test_ServiceManager_Package_OpenApiInvalid(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	setupMocksForServiceManager(mockContext)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "openapi.yaml"), []byte("swagger: '2.0'"), osutil.PermissionFile))
+
+	env := environment.New("test")
+	sm := createServiceManager(mockContext, env, ServiceOperationCache{})
+	serviceConfig := createTestServiceConfig(dir, ServiceTargetFake, ServiceLanguageFake)
+	serviceConfig.OpenApi = &OpenApiOptions{Spec: "openapi.yaml"}
+
+	frameworkCalled := new(false)
+	ctx := context.WithValue(*mockContext.Context, frameworkPackageCalled, frameworkCalled)
+	_, err := logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServicePackageResult, error) {
+		return sm.Package(ctx, serviceConfig, nil, progress, nil)
+	})
+	require.ErrorContains(t, err, "Swagger 2.0 descriptions are not supported")
+	require.False(t, *frameworkCalled)
+}
+
+func Test_ServiceManager_Deploy_OpenApiPublish(t *testing.T) {
+	const apiCenterId = "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/" +
+		"Microsoft.ApiCenter/services/apic"
+
+	mockContext := mocks.NewMockContext(t.Context())
+	setupMocksForServiceManager(mockContext)
+	mockContext.Container.MustRegisterSingleton(func() *azapi.ApiCenterService {
+		return azapi.NewApiCenterService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+	})
+
+	var deployment map[string]any
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return strings.HasPrefix(request.URL.Path, apiCenterId+"/")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(request.URL.Path, "/apis/todo/deployments/test") {
+			contents, err := io.ReadAll(request.Body)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(contents, &deployment))
+		}
+
+		return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+	})
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "openapi.yaml"), []byte(testOpenApiSpec), osutil.PermissionFile))
+
+	env := environment.NewWithValues("test", map[string]string{
+		environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+		"API_CENTER_ID":                      apiCenterId,
+	})
+	sm := createServiceManager(mockContext, env, ServiceOperationCache{})
+	serviceConfig := createTestServiceConfig(dir, ServiceTargetFake, ServiceLanguageFake)
+	serviceConfig.OpenApi = &OpenApiOptions{
+		Spec: "openapi.yaml",
+		Publish: &OpenApiPublishOptions{
+			ApiCenter: osutil.NewExpandableString("${API_CENTER_ID}"),
+			Name:      "todo",
+		},
+	}
+
+	ctx := context.WithValue(*mockContext.Context, serviceTargetDeployCalled, new(false))
+	_, err := logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+		return sm.Deploy(ctx, serviceConfig, NewServiceContext(), progress)
+	})
+	require.NoError(t, err)
+
+	require.NotNil(t, deployment)
+	properties := deployment["properties"].(map[string]any)
+	require.Equal(t, "/workspaces/default/apis/todo/versions/1-0-0/definitions/openapi", properties["definitionId"])
+}
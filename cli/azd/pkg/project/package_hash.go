@@ -0,0 +1,260 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/braydonk/yaml"
+)
+
+// PackageHashProperty is the service property of the environment that records the [DeployHash] of the last successful
+// deployment of a service.
+const PackageHashProperty = "PACKAGE_HASH"
+
+// PackageHash computes a content hash of a service package from the configuration of the service and the content of its
+// package artifacts, so that two packages with the same hash deploy the same thing.
+//
+// An empty hash is returned when the content of an artifact can't be determined, for example for container images that
+// are built remotely or pulled from an external registry.
+func PackageHash(serviceConfig *ServiceConfig, artifacts ArtifactCollection) (string, error) {
+	if len(artifacts) == 0 {
+		return "", nil
+	}
+
+	config, err := yaml.Marshal(serviceConfig)
+	if err != nil {
+		return "", fmt.Errorf("marshalling service configuration: %w", err)
+	}
+
+	h := sha256.New()
+	_, _ = h.Write(config)
+
+	for _, artifact := range artifacts {
+		fmt.Fprintf(h, "\x00%s\x00", artifact.Kind)
+
+		switch {
+		case artifact.Kind == ArtifactKindContainer:
+			// Local images are identified by the content-addressed ID of the image.
+			imageHash := artifact.Metadata["imageHash"]
+			if imageHash == "" {
+				return "", nil
+			}
+			_, _ = io.WriteString(h, imageHash)
+		case (artifact.Kind == ArtifactKindArchive || artifact.Kind == ArtifactKindDirectory) &&
+			artifact.LocationKind == LocationKindLocal:
+			if err := hashPath(h, artifact.Location); err != nil {
+				return "", fmt.Errorf("hashing package %s: %w", artifact.Location, err)
+			}
+		default:
+			return "", nil
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// DeployHash computes the hash that tells whether a service is unchanged since its last deploy, from the [PackageHash]
+// of its package and the inputs its deployment reads:
+//
+//   - the values of its env and appSettings,
+//   - the files its service target reads when it deploys, such as Kubernetes manifests, Helm values files and Container
+//     Apps templates, along with the values of env these files can reference,
+//   - the resource it's deployed to, identified by its ID and its creation time, so that a resource that's deleted and
+//     created again is deployed to.
+//
+// Unlike the package, these inputs change without the service changing, for example after `azd env set` or after
+// provisioning a new resource.
+//
+// An empty hash is returned when packageHash is empty, when the creation time of target isn't known, or when the
+// service references Key Vault secrets, whose values are only read when the service is deployed.
+func DeployHash(
+	packageHash string,
+	serviceConfig *ServiceConfig,
+	env *environment.Environment,
+	target *environment.TargetResource,
+	targetCreatedTime time.Time,
+) (string, error) {
+	if packageHash == "" || (target != nil && targetCreatedTime.IsZero()) {
+		return "", nil
+	}
+
+	h := sha256.New()
+	_, _ = io.WriteString(h, packageHash)
+
+	for _, name := range slices.Sorted(maps.Keys(serviceConfig.Environment)) {
+		value := serviceConfig.Environment[name]
+		if keyvault.HasSecretExpression(value.Template()) {
+			return "", nil
+		}
+
+		expanded, err := value.Envsubst(env.Getenv)
+		if err != nil {
+			return "", fmt.Errorf("expanding %s: %w", name, err)
+		}
+		fmt.Fprintf(h, "\x00env\x00%s\x00%s", name, expanded)
+	}
+
+	if serviceConfig.AppSettings != nil {
+		hasSecretExpression := false
+		values, err := serviceConfig.AppSettings.values(env, func(keyvault.SecretExpression) (string, error) {
+			hasSecretExpression = true
+			return "", nil
+		})
+		if err != nil {
+			return "", err
+		}
+		if hasSecretExpression {
+			return "", nil
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(values)) {
+			fmt.Fprintf(h, "\x00appSettings\x00%s\x00%s", name, values[name])
+		}
+	}
+
+	if inputs := deployInputPaths(serviceConfig, env); len(inputs) > 0 {
+		for _, path := range inputs {
+			fmt.Fprintf(h, "\x00input\x00%s\x00", filepath.ToSlash(path))
+			if err := hashPath(h, path); errors.Is(err, os.ErrNotExist) {
+				_, _ = io.WriteString(h, "\x00missing")
+			} else if err != nil {
+				return "", fmt.Errorf("hashing %s: %w", path, err)
+			}
+		}
+
+		// The SERVICE_ values are recorded by azd for the services, such as their package hash and image name, and
+		// change with every deploy.
+		values := env.Dotenv()
+		for _, name := range slices.Sorted(maps.Keys(values)) {
+			if !strings.HasPrefix(name, "SERVICE_") {
+				fmt.Fprintf(h, "\x00environ\x00%s\x00%s", name, values[name])
+			}
+		}
+	}
+
+	if target != nil {
+		fmt.Fprintf(h, "\x00target\x00%s\x00%s\x00%s\x00%s\x00%s",
+			target.SubscriptionId(), target.ResourceGroupName(), target.ResourceType(), target.ResourceName(),
+			targetCreatedTime.UTC().Format(time.RFC3339Nano))
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// deployInputPaths returns the files and directories, other than its package, that the service target of serviceConfig
+// reads when it deploys the service. The paths don't necessarily exist.
+func deployInputPaths(serviceConfig *ServiceConfig, env *environment.Environment) []string {
+	if serviceConfig.Project == nil {
+		return nil
+	}
+
+	var paths []string
+
+	switch serviceConfig.Host {
+	case AksTarget:
+		deploymentPath := serviceConfig.K8s.DeploymentPath
+		if deploymentPath == "" {
+			deploymentPath = defaultDeploymentPath
+		}
+		paths = append(paths, filepath.Join(serviceConfig.Path(), deploymentPath))
+
+		if serviceConfig.K8s.Helm != nil {
+			for _, release := range serviceConfig.K8s.Helm.Releases {
+				// Charts from a repository or a registry aren't on disk, and don't exist at the project path.
+				files := helmValuesFiles(release)
+				if release.Chart != "" && !strings.Contains(release.Chart, "://") {
+					files = append(files, release.Chart)
+				}
+
+				for _, file := range files {
+					if !filepath.IsAbs(file) {
+						file = filepath.Join(serviceConfig.Project.Path, file)
+					}
+					paths = append(paths, file)
+				}
+			}
+		}
+
+		if serviceConfig.K8s.Kustomize != nil {
+			if overlayPath, err := serviceConfig.K8s.Kustomize.Directory.Envsubst(env.Getenv); err == nil {
+				paths = append(paths, filepath.Join(serviceConfig.Project.Path, serviceConfig.RelativePath, overlayPath))
+			}
+		}
+	case ContainerAppTarget:
+		// The optional module that deploys the revisions of the container app.
+		moduleName := serviceConfig.Module
+		if moduleName == "" {
+			moduleName = serviceConfig.Name
+		}
+
+		if infraOptions, err := serviceConfig.Project.Infra.GetWithDefaults(); err == nil {
+			infraRoot := infraOptions.Path
+			if !filepath.IsAbs(infraRoot) {
+				infraRoot = filepath.Join(serviceConfig.Project.Path, infraRoot)
+			}
+
+			modulePath := filepath.Join(infraRoot, moduleName)
+			paths = append(paths, modulePath+".bicep", modulePath+".parameters.json", modulePath+".bicepparam")
+		}
+	case DotNetContainerAppTarget:
+		if serviceConfig.DotNetContainerApp == nil {
+			break
+		}
+
+		appHostRoot := serviceConfig.DotNetContainerApp.AppHostPath
+		if f, err := os.Stat(appHostRoot); err == nil && !f.IsDir() {
+			appHostRoot = filepath.Dir(appHostRoot)
+		}
+
+		projectName := serviceConfig.DotNetContainerApp.ProjectName
+		paths = append(paths,
+			filepath.Join(appHostRoot, "infra", fmt.Sprintf("%s.tmpl.yaml", projectName)),
+			filepath.Join(appHostRoot, "infra", projectName, fmt.Sprintf("%s.tmpl.bicepparam", projectName)))
+	}
+
+	return paths
+}
+
+// hashPath writes the content of a file, or the relative paths and contents of the files in a directory, to h.
+func hashPath(h hash.Hash, root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "\x00%s\x00", filepath.ToSlash(rel))
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(h, file)
+		return err
+	})
+}
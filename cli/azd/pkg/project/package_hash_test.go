@@ -0,0 +1,254 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/helm"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PackageHash(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "app", "static"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app", "main.py"), []byte("print('hello')"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app", "static", "index.html"), []byte("<html/>"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.zip"), []byte("zip content"), 0600))
+
+	serviceConfig := &ServiceConfig{Name: "api", Host: AppServiceTarget, Language: ServiceLanguagePython}
+	directory := ArtifactCollection{{
+		Kind:         ArtifactKindDirectory,
+		Location:     filepath.Join(dir, "app"),
+		LocationKind: LocationKindLocal,
+	}}
+	archive := ArtifactCollection{{
+		Kind:         ArtifactKindArchive,
+		Location:     filepath.Join(dir, "app.zip"),
+		LocationKind: LocationKindLocal,
+	}}
+
+	t.Run("Stable", func(t *testing.T) {
+		first, err := PackageHash(serviceConfig, directory)
+		require.NoError(t, err)
+		require.NotEmpty(t, first)
+
+		second, err := PackageHash(serviceConfig, directory)
+		require.NoError(t, err)
+		require.Equal(t, first, second)
+
+		archiveHash, err := PackageHash(serviceConfig, archive)
+		require.NoError(t, err)
+		require.NotEmpty(t, archiveHash)
+		require.NotEqual(t, first, archiveHash)
+	})
+
+	t.Run("ContentChanged", func(t *testing.T) {
+		before, err := PackageHash(serviceConfig, archive)
+		require.NoError(t, err)
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "app.zip"), []byte("new zip content"), 0600))
+		after, err := PackageHash(serviceConfig, archive)
+		require.NoError(t, err)
+		require.NotEqual(t, before, after)
+	})
+
+	t.Run("ConfigChanged", func(t *testing.T) {
+		before, err := PackageHash(serviceConfig, directory)
+		require.NoError(t, err)
+
+		changed := *serviceConfig
+		changed.Host = ContainerAppTarget
+		after, err := PackageHash(&changed, directory)
+		require.NoError(t, err)
+		require.NotEqual(t, before, after)
+	})
+
+	t.Run("ContainerImage", func(t *testing.T) {
+		local := ArtifactCollection{{
+			Kind:         ArtifactKindContainer,
+			Location:     "api:azd-deploy-1",
+			LocationKind: LocationKindLocal,
+			Metadata:     map[string]string{"imageHash": "sha256:1234"},
+		}}
+		first, err := PackageHash(serviceConfig, local)
+		require.NoError(t, err)
+		require.NotEmpty(t, first)
+
+		// The local tag changes on every package, but the image is the same.
+		local[0].Location = "api:azd-deploy-2"
+		second, err := PackageHash(serviceConfig, local)
+		require.NoError(t, err)
+		require.Equal(t, first, second)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		for _, artifacts := range []ArtifactCollection{
+			nil,
+			{{Kind: ArtifactKindContainer, Location: "nginx:latest", LocationKind: LocationKindLocal}},
+			{{Kind: ArtifactKindArchive, Location: "https://contoso.com/app.zip", LocationKind: LocationKindRemote}},
+		} {
+			hash, err := PackageHash(serviceConfig, artifacts)
+			require.NoError(t, err)
+			require.Empty(t, hash)
+		}
+	})
+
+	t.Run("MissingPackage", func(t *testing.T) {
+		_, err := PackageHash(serviceConfig, ArtifactCollection{{
+			Kind:         ArtifactKindArchive,
+			Location:     filepath.Join(dir, "missing.zip"),
+			LocationKind: LocationKindLocal,
+		}})
+		require.Error(t, err)
+	})
+}
+
+func Test_DeployHash(t *testing.T) {
+	serviceConfig := &ServiceConfig{
+		Name: "api",
+		Host: AppServiceTarget,
+		Environment: osutil.ExpandableMap{
+			"API_URL": osutil.NewExpandableString("${API_URL}"),
+		},
+		AppSettings: &AppSettingsOptions{
+			Settings: map[string]string{"GREETING": "GREETING"},
+		},
+	}
+	env := environment.NewWithValues("test-env", map[string]string{
+		"API_URL":  "https://api.contoso.com",
+		"GREETING": "hello",
+	})
+	target := environment.NewTargetResource("sub", "rg", "app-api", "Microsoft.Web/sites")
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	hash, err := DeployHash("package-hash", serviceConfig, env, target, created)
+	require.NoError(t, err)
+	require.NotEmpty(t, hash)
+
+	t.Run("Stable", func(t *testing.T) {
+		again, err := DeployHash("package-hash", serviceConfig, env, target, created)
+		require.NoError(t, err)
+		require.Equal(t, hash, again)
+	})
+
+	t.Run("InputChanged", func(t *testing.T) {
+		changes := map[string]func() (string, error){
+			"Package": func() (string, error) {
+				return DeployHash("other-package-hash", serviceConfig, env, target, created)
+			},
+			"Env": func() (string, error) {
+				changed := environment.NewWithValues("test-env", map[string]string{
+					"API_URL":  "https://api2.contoso.com",
+					"GREETING": "hello",
+				})
+				return DeployHash("package-hash", serviceConfig, changed, target, created)
+			},
+			"AppSettings": func() (string, error) {
+				changed := environment.NewWithValues("test-env", map[string]string{
+					"API_URL":  "https://api.contoso.com",
+					"GREETING": "bonjour",
+				})
+				return DeployHash("package-hash", serviceConfig, changed, target, created)
+			},
+			"Target": func() (string, error) {
+				changed := environment.NewTargetResource("sub", "rg-2", "app-api", "Microsoft.Web/sites")
+				return DeployHash("package-hash", serviceConfig, env, changed, created)
+			},
+			"TargetRecreated": func() (string, error) {
+				return DeployHash("package-hash", serviceConfig, env, target, created.Add(time.Hour))
+			},
+		}
+
+		for name, change := range changes {
+			t.Run(name, func(t *testing.T) {
+				changed, err := change()
+				require.NoError(t, err)
+				require.NotEmpty(t, changed)
+				require.NotEqual(t, hash, changed)
+			})
+		}
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		empty, err := DeployHash("", serviceConfig, env, target, created)
+		require.NoError(t, err)
+		require.Empty(t, empty)
+
+		// A resource whose creation time isn't known may have been recreated since the last deploy.
+		empty, err = DeployHash("package-hash", serviceConfig, env, target, time.Time{})
+		require.NoError(t, err)
+		require.Empty(t, empty)
+
+		// Key Vault secrets are only read on deploy, so a service that references them is always deployed.
+		secretEnv := *serviceConfig
+		secretEnv.Environment = osutil.ExpandableMap{
+			"PASSWORD": osutil.NewExpandableString("${keyvault:kv-contoso/password}"),
+		}
+		empty, err = DeployHash("package-hash", &secretEnv, env, target, created)
+		require.NoError(t, err)
+		require.Empty(t, empty)
+
+		secretSetting := *serviceConfig
+		secretSetting.AppSettings = &AppSettingsOptions{
+			Settings: map[string]string{"PASSWORD": "${keyvault:kv-contoso/password}"},
+		}
+		empty, err = DeployHash("package-hash", &secretSetting, env, target, created)
+		require.NoError(t, err)
+		require.Empty(t, empty)
+	})
+}
+
+func Test_DeployHash_Inputs(t *testing.T) {
+	projectPath := t.TempDir()
+	serviceConfig := &ServiceConfig{
+		Name:         "api",
+		Host:         AksTarget,
+		RelativePath: "src/api",
+		Project:      &ProjectConfig{Path: projectPath},
+		K8s: AksOptions{
+			Helm: &helm.Config{
+				Releases: []*helm.Release{
+					{Name: "api", Chart: "oci://contoso.azurecr.io/charts/api", Values: "values.yaml"},
+				},
+			},
+		},
+	}
+	manifestPath := filepath.Join(projectPath, "src", "api", "manifests", "deployment.yaml")
+	valuesPath := filepath.Join(projectPath, "values.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(manifestPath), osutil.PermissionDirectory))
+	require.NoError(t, os.WriteFile(manifestPath, []byte("image: ${SERVICE_API_IMAGE_NAME}"), osutil.PermissionFile))
+	require.NoError(t, os.WriteFile(valuesPath, []byte("replicas: 1"), osutil.PermissionFile))
+
+	env := environment.NewWithValues("test-env", map[string]string{"NAMESPACE": "api"})
+	deployHash := func() string {
+		hash, err := DeployHash("package-hash", serviceConfig, env, nil, time.Time{})
+		require.NoError(t, err)
+		require.NotEmpty(t, hash)
+		return hash
+	}
+
+	hash := deployHash()
+
+	// The values azd records for the services change with every deploy, and aren't inputs.
+	env.DotenvSet("SERVICE_API_IMAGE_NAME", "contoso.azurecr.io/api:azd-deploy-1")
+	require.Equal(t, hash, deployHash())
+
+	require.NoError(t, os.WriteFile(manifestPath, []byte("image: contoso/api"), osutil.PermissionFile))
+	changed := deployHash()
+	require.NotEqual(t, hash, changed)
+
+	require.NoError(t, os.WriteFile(valuesPath, []byte("replicas: 2"), osutil.PermissionFile))
+	require.NotEqual(t, changed, deployHash())
+	changed = deployHash()
+
+	// The files can reference any value of the environment.
+	env.DotenvSet("NAMESPACE", "api-2")
+	require.NotEqual(t, changed, deployHash())
+}
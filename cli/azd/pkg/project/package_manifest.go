@@ -0,0 +1,258 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// PackageManifestFileName is the name of the manifest `azd package` writes to the package output directory.
+const PackageManifestFileName = "azd-package.json"
+
+// packageManifestVersion is the version of the format of the package manifest
+const packageManifestVersion = 1
+
+// PackageManifest describes the packages produced by `azd package`, so that `azd deploy --from-package` deploys the
+// same packages later, for example in another stage of a pipeline.
+type PackageManifest struct {
+	// Version is the version of the format of the manifest.
+	Version int `json:"version"`
+	// CreatedAt is when the services were packaged.
+	CreatedAt time.Time `json:"createdAt"`
+	// Project is the name of the project.
+	Project string `json:"project"`
+	// Git is the commit the services were packaged from, when the project is in a git repository.
+	Git *PackageManifestGit `json:"git,omitempty"`
+	// Services are the packages of the services, by service name.
+	Services map[string]*PackageManifestService `json:"services"`
+}
+
+// PackageManifestGit is the git commit services were packaged from.
+type PackageManifestGit struct {
+	Commit string `json:"commit"`
+	Branch string `json:"branch,omitempty"`
+	// Dirty is whether the working tree had uncommitted changes.
+	Dirty bool `json:"dirty,omitempty"`
+}
+
+// PackageManifestService holds the artifacts produced by packaging a service. The last artifact is the package that
+// is deployed.
+type PackageManifestService struct {
+	Host      ServiceTargetKind          `json:"host"`
+	Artifacts []*PackageManifestArtifact `json:"artifacts"`
+}
+
+// PackageManifestArtifact is an artifact produced by packaging a service.
+type PackageManifestArtifact struct {
+	Kind ArtifactKind `json:"kind"`
+	// Path is the path of a zip package or a directory, relative to the manifest when it's in the directory of the
+	// manifest.
+	Path string `json:"path,omitempty"`
+	// Image is the tag of a container image.
+	Image string `json:"image,omitempty"`
+	// Digest is the SHA-256 digest of a zip package or of the files of a directory, or the ID of a container image.
+	Digest string `json:"digest,omitempty"`
+	// Metadata is the metadata of the artifact, such as the artifact store reference of a pushed package.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// NewPackageManifestService describes the package artifacts of a service. The paths of the artifacts in manifestDir are
+// recorded relative to manifestDir, so that the directory can be moved with its manifest.
+func NewPackageManifestService(
+	serviceConfig *ServiceConfig,
+	artifacts ArtifactCollection,
+	manifestDir string,
+) (*PackageManifestService, error) {
+	service := &PackageManifestService{Host: serviceConfig.Host}
+	for _, artifact := range artifacts {
+		manifestArtifact := &PackageManifestArtifact{
+			Kind:     artifact.Kind,
+			Metadata: maps.Clone(artifact.Metadata),
+		}
+
+		switch {
+		case artifact.Kind == ArtifactKindContainer:
+			manifestArtifact.Image = artifact.Location
+			manifestArtifact.Digest = artifact.Metadata["imageHash"]
+		case artifact.LocationKind == LocationKindLocal:
+			digest, err := packageDigest(artifact.Kind, artifact.Location)
+			if err != nil {
+				return nil, fmt.Errorf("computing digest of package %s: %w", artifact.Location, err)
+			}
+
+			manifestArtifact.Path = manifestPath(manifestDir, artifact.Location)
+			manifestArtifact.Digest = digest
+		default:
+			manifestArtifact.Path = artifact.Location
+		}
+
+		service.Artifacts = append(service.Artifacts, manifestArtifact)
+	}
+
+	return service, nil
+}
+
+// Package returns the artifact of the package of the service that is deployed.
+func (s *PackageManifestService) Package() (*PackageManifestArtifact, error) {
+	if len(s.Artifacts) == 0 {
+		return nil, errors.New("the service has no package in the package manifest")
+	}
+
+	return s.Artifacts[len(s.Artifacts)-1], nil
+}
+
+// Location returns the tag of a container image, or the path of a zip package or a directory, resolved against the
+// directory of the manifest.
+func (a *PackageManifestArtifact) Location(manifestDir string) string {
+	if a.Kind == ArtifactKindContainer {
+		return a.Image
+	}
+
+	location := filepath.FromSlash(a.Path)
+	if !filepath.IsAbs(location) {
+		location = filepath.Join(manifestDir, location)
+	}
+
+	return location
+}
+
+// LocalArtifact returns the package at location as a local artifact. The digest of a zip package or a directory is
+// verified, so that a package that changed since it was packaged isn't deployed. The error wraps os.ErrNotExist when
+// the package doesn't exist.
+func (a *PackageManifestArtifact) LocalArtifact(location string) (*Artifact, error) {
+	if a.Kind != ArtifactKindContainer {
+		if err := a.VerifyDigest(location); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Artifact{
+		Kind:         a.Kind,
+		Location:     location,
+		LocationKind: LocationKindLocal,
+		Metadata:     maps.Clone(a.Metadata),
+	}, nil
+}
+
+// VerifyDigest verifies that the zip package or the directory at path has the digest of the artifact.
+func (a *PackageManifestArtifact) VerifyDigest(path string) error {
+	digest, err := packageDigest(a.Kind, path)
+	if err != nil {
+		return fmt.Errorf("computing digest of package %s: %w", path, err)
+	}
+
+	if a.Digest != "" && digest != a.Digest {
+		return fmt.Errorf("package %s has digest %s, the package manifest expects %s", path, digest, a.Digest)
+	}
+
+	return nil
+}
+
+// Save writes the manifest as JSON to path.
+func (m *PackageManifest) Save(path string) error {
+	m.Version = packageManifestVersion
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling package manifest: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory); err != nil {
+		return fmt.Errorf("creating directory of package manifest: %w", err)
+	}
+
+	if err := os.WriteFile(path, content, osutil.PermissionFile); err != nil {
+		return fmt.Errorf("writing package manifest: %w", err)
+	}
+
+	return nil
+}
+
+// IsPackageManifest returns whether a --from-package value is the path of a package manifest.
+func IsPackageManifest(path string) bool {
+	if !strings.EqualFold(filepath.Ext(path), ".json") {
+		return false
+	}
+
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// LoadPackageManifest reads the package manifest at path.
+func LoadPackageManifest(path string) (*PackageManifest, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading package manifest: %w", err)
+	}
+
+	var manifest PackageManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing package manifest %s: %w", path, err)
+	}
+
+	if manifest.Version != packageManifestVersion {
+		return nil, fmt.Errorf(
+			"package manifest %s has version %d, expected %d", path, manifest.Version, packageManifestVersion)
+	}
+
+	return &manifest, nil
+}
+
+// packageDigest returns the SHA-256 digest of the content of a zip package, or of the relative paths and contents of
+// the files of a directory.
+func packageDigest(kind ArtifactKind, path string) (string, error) {
+	h := sha256.New()
+	if kind == ArtifactKindDirectory {
+		if err := hashPath(h, path); err != nil {
+			return "", err
+		}
+	} else {
+		file, err := os.Open(path)
+		if err != nil {
+			return "", err
+		}
+		defer file.Close()
+
+		if _, err := io.Copy(h, file); err != nil {
+			return "", err
+		}
+	}
+
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// manifestPath returns path relative to manifestDir when it's in manifestDir, or path otherwise.
+func manifestPath(manifestDir string, path string) string {
+	if manifestDir == "" {
+		return path
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+
+	absManifestDir, err := filepath.Abs(manifestDir)
+	if err != nil {
+		return path
+	}
+
+	rel, err := filepath.Rel(absManifestDir, absPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return absPath
+	}
+
+	return filepath.ToSlash(rel)
+}
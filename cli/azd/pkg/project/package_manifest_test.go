@@ -0,0 +1,105 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+func Test_PackageManifest_SaveAndLoad(t *testing.T) {
+	distDir := t.TempDir()
+	packagePath := filepath.Join(distDir, "api.zip")
+	require.NoError(t, os.WriteFile(packagePath, []byte("package content"), osutil.PermissionFile))
+
+	serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageJavaScript)
+	service, err := NewPackageManifestService(serviceConfig, ArtifactCollection{
+		{
+			Kind:         ArtifactKindArchive,
+			Location:     packagePath,
+			LocationKind: LocationKindLocal,
+			Metadata:     map[string]string{MetadataKeyReference: "sha256:9f86/api.zip"},
+		},
+	}, distDir)
+	require.NoError(t, err)
+
+	web, err := NewPackageManifestService(
+		createTestServiceConfig("./src/web", ContainerAppTarget, ServiceLanguageTypeScript),
+		ArtifactCollection{{
+			Kind:         ArtifactKindContainer,
+			Location:     "web:azd-deploy-1",
+			LocationKind: LocationKindLocal,
+			Metadata:     map[string]string{"imageHash": "sha256:4f1c"},
+		}},
+		distDir,
+	)
+	require.NoError(t, err)
+
+	manifestPath := filepath.Join(distDir, PackageManifestFileName)
+	manifest := &PackageManifest{
+		Project:  "todo",
+		Git:      &PackageManifestGit{Commit: "0a1b2c", Branch: "main"},
+		Services: map[string]*PackageManifestService{"api": service, "web": web},
+	}
+	require.NoError(t, manifest.Save(manifestPath))
+	require.True(t, IsPackageManifest(manifestPath))
+	require.False(t, IsPackageManifest(packagePath))
+
+	loaded, err := LoadPackageManifest(manifestPath)
+	require.NoError(t, err)
+	require.Equal(t, "0a1b2c", loaded.Git.Commit)
+
+	apiPackage, err := loaded.Services["api"].Package()
+	require.NoError(t, err)
+	require.Equal(t, "api.zip", apiPackage.Path)
+	digest, err := packageDigest(ArtifactKindArchive, packagePath)
+	require.NoError(t, err)
+	require.Equal(t, digest, apiPackage.Digest)
+
+	// The directory of the manifest can be moved with its packages.
+	movedDir := filepath.Join(t.TempDir(), "dist")
+	require.NoError(t, os.Rename(distDir, movedDir))
+	artifact, err := apiPackage.LocalArtifact(apiPackage.Location(movedDir))
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(movedDir, "api.zip"), artifact.Location)
+	require.Equal(t, ArtifactKindArchive, artifact.Kind)
+	require.Equal(t, "sha256:9f86/api.zip", artifact.Metadata[MetadataKeyReference])
+
+	webPackage, err := loaded.Services["web"].Package()
+	require.NoError(t, err)
+	require.Equal(t, "sha256:4f1c", webPackage.Digest)
+	artifact, err = webPackage.LocalArtifact(webPackage.Location(movedDir))
+	require.NoError(t, err)
+	require.Equal(t, "web:azd-deploy-1", artifact.Location)
+}
+
+func Test_PackageManifestArtifact_LocalArtifact(t *testing.T) {
+	dir := t.TempDir()
+	packagePath := filepath.Join(dir, "api.zip")
+	require.NoError(t, os.WriteFile(packagePath, []byte("package content"), osutil.PermissionFile))
+
+	digest, err := packageDigest(ArtifactKindArchive, packagePath)
+	require.NoError(t, err)
+	manifestArtifact := &PackageManifestArtifact{Kind: ArtifactKindArchive, Path: "api.zip", Digest: digest}
+
+	require.NoError(t, os.WriteFile(packagePath, []byte("changed content"), osutil.PermissionFile))
+	_, err = manifestArtifact.LocalArtifact(manifestArtifact.Location(dir))
+	require.ErrorContains(t, err, "the package manifest expects "+digest)
+
+	_, err = manifestArtifact.LocalArtifact(filepath.Join(dir, "missing.zip"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func Test_LoadPackageManifest_Version(t *testing.T) {
+	manifestPath := filepath.Join(t.TempDir(), PackageManifestFileName)
+	require.NoError(t, os.WriteFile(manifestPath, []byte(`{"version": 2}`), osutil.PermissionFile))
+
+	_, err := LoadPackageManifest(manifestPath)
+	require.ErrorContains(t, err, "has version 2, expected 1")
+}
@@ -79,6 +79,18 @@ func Parse(ctx context.Context, yamlContent string) (*ProjectConfig, error) {
 		return nil, err
 	}
 
+	if _, err := ParseServiceTagFilter(projectConfig.Pipeline.Tags); err != nil {
+		return nil, fmt.Errorf("parsing pipeline tags: %w", err)
+	}
+
+	if err := projectConfig.Test.Validate(); err != nil {
+		return nil, fmt.Errorf("parsing test: %w", err)
+	}
+
+	if err := projectConfig.Monitor.Validate(); err != nil {
+		return nil, fmt.Errorf("parsing monitor: %w", err)
+	}
+
 	var err error
 	projectConfig.Infra.Provider, err = provisioning.ParseProvider(projectConfig.Infra.Provider)
 	if err != nil {
@@ -126,6 +138,12 @@ func Parse(ctx context.Context, yamlContent string) (*ProjectConfig, error) {
 			return nil, fmt.Errorf("parsing service %s: %w", svc.Name, err)
 		}
 
+		for _, tag := range svc.Tags {
+			if err := validateServiceTag(tag); err != nil {
+				return nil, fmt.Errorf("parsing service %s: invalid tag '%s': %w", svc.Name, tag, err)
+			}
+		}
+
 		if strings.Contains(svc.Infra.Path, "\\") && !strings.Contains(svc.Infra.Path, "/") {
 			svc.Infra.Path = strings.ReplaceAll(svc.Infra.Path, "\\", "/")
 		}
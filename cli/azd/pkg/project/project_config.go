@@ -6,6 +6,7 @@ package project
 import (
 	"context"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/artifacts"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
@@ -39,6 +40,9 @@ type ProjectConfig struct {
 	Workflows         workflow.WorkflowMap       `yaml:"workflows,omitempty"`
 	Cloud             *cloud.Config              `yaml:"cloud,omitempty"`
 	Resources         map[string]*ResourceConfig `yaml:"resources,omitempty"`
+	Test              *TestConfig                `yaml:"test,omitempty"`
+	Artifacts         *artifacts.Config          `yaml:"artifacts,omitempty"`
+	Monitor           *MonitorConfig             `yaml:"monitor,omitempty"`
 
 	// AdditionalProperties captures any unknown YAML fields for extension support
 	AdditionalProperties map[string]any `yaml:",inline"`
@@ -59,6 +63,20 @@ type PipelineOptions struct {
 	Provider  string   `yaml:"provider"`
 	Variables []string `yaml:"variables"`
 	Secrets   []string `yaml:"secrets"`
+	// Tags filters the services deployed by the generated pipeline, using the same expressions as `azd deploy --tag`.
+	Tags []string `yaml:"tags,omitempty"`
+	// Stages turns the generated pipeline into a multi-stage pipeline that promotes changes through the stages in order.
+	Stages []PipelineStage `yaml:"stages,omitempty"`
+}
+
+// PipelineStage is a deployment stage of a multi-stage pipeline.
+type PipelineStage struct {
+	// Name of the stage. The stage variables, secrets and approvals are scoped to a CI environment with this name.
+	Name string `yaml:"name"`
+	// Environment is the azd environment deployed by the stage. Defaults to the stage name.
+	Environment string `yaml:"environment,omitempty"`
+	// Reviewers must approve the promotion before the stage runs.
+	Reviewers []string `yaml:"reviewers,omitempty"`
 }
 
 // Project lifecycle event arguments
@@ -75,6 +93,21 @@ type ProjectMetadata struct {
 	// in every template that we ship.
 	// ex: todo-python-mongo@version
 	Template string
+
+	// TemplateSource is the version of the template the project was initialized from, recorded by azd init.
+	TemplateSource *TemplateSource `yaml:"templateSource,omitempty"`
+}
+
+// TemplateSource identifies the version of a template in its git repository.
+type TemplateSource struct {
+	// Repository is the url of the git repository of the template.
+	Repository string `yaml:"repository"`
+
+	// Branch is the branch of the template. The default branch of the repository is used when empty.
+	Branch string `yaml:"branch,omitempty"`
+
+	// Commit is the commit of the template.
+	Commit string `yaml:"commit"`
 }
 
 // HooksConfig aliases ext.HooksConfig for compatibility with existing project package references.
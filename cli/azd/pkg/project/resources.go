@@ -27,6 +27,7 @@ func AllResourceTypes() []ResourceType {
 		ResourceTypeAiProject,
 		ResourceTypeAiSearch,
 		ResourceTypeKeyVault,
+		ResourceTypeFeatureFlags,
 	}
 }
 
@@ -45,6 +46,7 @@ const (
 	ResourceTypeAiProject           ResourceType = "ai.project"
 	ResourceTypeAiSearch            ResourceType = "ai.search"
 	ResourceTypeKeyVault            ResourceType = "keyvault"
+	ResourceTypeFeatureFlags        ResourceType = "featureflags"
 )
 
 func (r ResourceType) String() string {
@@ -77,6 +79,8 @@ func (r ResourceType) String() string {
 		return "AI Search"
 	case ResourceTypeKeyVault:
 		return "Key Vault"
+	case ResourceTypeFeatureFlags:
+		return "Feature Flags"
 	}
 
 	return ""
@@ -114,6 +118,8 @@ func (r ResourceType) AzureResourceType() string {
 		return "Microsoft.Storage/storageAccounts"
 	case ResourceTypeKeyVault:
 		return "Microsoft.KeyVault/vaults"
+	case ResourceTypeFeatureFlags:
+		return "Microsoft.AppConfiguration/configurationStores"
 	case ResourceTypeAiProject:
 		return "Microsoft.CognitiveServices/accounts/projects"
 	case ResourceTypeAiSearch:
@@ -176,6 +182,8 @@ func (r *ResourceConfig) MarshalYAML() (any, error) {
 			errMarshal = marshalRawProps(raw.Props.(AppServiceProps))
 		case ResourceTypeHostContainerApp:
 			errMarshal = marshalRawProps(raw.Props.(ContainerAppProps))
+		case ResourceTypeDbPostgres, ResourceTypeDbRedis:
+			errMarshal = marshalRawProps(raw.Props.(DatabaseProps))
 		case ResourceTypeDbCosmos:
 			errMarshal = marshalRawProps(raw.Props.(CosmosDBProps))
 		case ResourceTypeMessagingEventHubs:
@@ -236,6 +244,12 @@ func (r *ResourceConfig) UnmarshalYAML(value *yaml.Node) error {
 			return err
 		}
 		raw.Props = cap
+	case ResourceTypeDbPostgres, ResourceTypeDbRedis:
+		dp := DatabaseProps{}
+		if err := unmarshalProps(&dp); err != nil {
+			return err
+		}
+		raw.Props = dp
 	case ResourceTypeDbCosmos:
 		cdp := CosmosDBProps{}
 		if err := unmarshalProps(&cdp); err != nil {
@@ -306,6 +320,7 @@ type ServiceEnvVar struct {
 
 type AIModelProps struct {
 	Model AIModelPropsModel `yaml:"model,omitempty"`
+	Sku   AIModelPropsSku   `yaml:"sku,omitempty"`
 }
 
 type AIModelPropsModel struct {
@@ -313,6 +328,20 @@ type AIModelPropsModel struct {
 	Version string `yaml:"version,omitempty"`
 }
 
+// AIModelPropsSku is the SKU of a model deployment. The Standard SKU with a capacity of 20 is used when it isn't set.
+type AIModelPropsSku struct {
+	Name string `yaml:"name,omitempty"`
+	// Capacity is the capacity of the deployment, in thousands of tokens per minute.
+	Capacity int32 `yaml:"capacity,omitempty"`
+}
+
+// DatabaseProps are the properties of the databases that support private networking, db.postgres and db.redis.
+type DatabaseProps struct {
+	// Private makes the database only reachable through a private endpoint, in a virtual network that the host
+	// services are joined to.
+	Private bool `yaml:"private,omitempty"`
+}
+
 type CosmosDBProps struct {
 	Containers []CosmosDBContainerProps `yaml:"containers,omitempty"`
 }
@@ -35,6 +35,7 @@ func Test_AllResourceTypes(t *testing.T) {
 		ResourceTypeAiProject,
 		ResourceTypeAiSearch,
 		ResourceTypeKeyVault,
+		ResourceTypeFeatureFlags,
 	}
 
 	require.Equal(t, expected, all)
@@ -84,6 +85,7 @@ func Test_ResourceType_String(t *testing.T) {
 		{"Foundry", ResourceTypeAiProject, "Foundry"},
 		{"AI Search", ResourceTypeAiSearch, "AI Search"},
 		{"Key Vault", ResourceTypeKeyVault, "Key Vault"},
+		{"Feature Flags", ResourceTypeFeatureFlags, "Feature Flags"},
 		{
 			"unknown returns empty",
 			ResourceType("unknown.type"),
@@ -164,6 +166,11 @@ func Test_ResourceType_AzureResourceType(t *testing.T) {
 			ResourceTypeKeyVault,
 			"Microsoft.KeyVault/vaults",
 		},
+		{
+			"FeatureFlags",
+			ResourceTypeFeatureFlags,
+			"Microsoft.AppConfiguration/configurationStores",
+		},
 		{
 			"AiProject",
 			ResourceTypeAiProject,
@@ -317,6 +324,25 @@ model:
 	assert.Equal(t, "2024-08-06", props.Model.Version)
 }
 
+func Test_ResourceConfig_UnmarshalYAML_OpenAiModelSku(t *testing.T) {
+	yamlData := `
+type: ai.openai.model
+model:
+  name: gpt-4o
+  version: "2024-08-06"
+sku:
+  name: Standard
+  capacity: 50
+`
+	var rc ResourceConfig
+	err := yaml.Unmarshal([]byte(yamlData), &rc)
+	require.NoError(t, err)
+
+	props, ok := rc.Props.(AIModelProps)
+	require.True(t, ok)
+	assert.Equal(t, AIModelPropsSku{Name: "Standard", Capacity: 50}, props.Sku)
+}
+
 func Test_ResourceConfig_UnmarshalYAML_Storage(t *testing.T) {
 	yamlData := `
 type: storage
@@ -644,6 +670,17 @@ func Test_infraSpec_KeyVault(t *testing.T) {
 	require.NotNil(t, spec.KeyVault)
 }
 
+func Test_infraSpec_FeatureFlags(t *testing.T) {
+	prj := &ProjectConfig{
+		Resources: map[string]*ResourceConfig{
+			"flags": {Name: "flags", Type: ResourceTypeFeatureFlags},
+		},
+	}
+	spec, err := infraSpec(prj)
+	require.NoError(t, err)
+	require.NotNil(t, spec.FeatureFlags)
+}
+
 func Test_infraSpec_AiSearch(t *testing.T) {
 	prj := &ProjectConfig{
 		Resources: map[string]*ResourceConfig{
@@ -960,6 +997,13 @@ func Test_mapHostUses_NonExistingResources(t *testing.T) {
 				require.NotNil(t, s.KeyVault)
 			},
 		},
+		{
+			"FeatureFlags",
+			ResourceTypeFeatureFlags,
+			func(t *testing.T, s *scaffold.ServiceSpec) {
+				require.NotNil(t, s.FeatureFlags)
+			},
+		},
 	}
 
 	for _, tt := range tests {
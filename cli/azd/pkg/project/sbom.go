@@ -0,0 +1,139 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/syft"
+)
+
+// SbomOptions configures the software bill of materials (SBOM) generated with Syft when the service is packaged.
+type SbomOptions struct {
+	// Format is the format of the SBOM: spdx or cyclonedx. Defaults to spdx.
+	Format string `yaml:"format,omitempty" json:"format,omitempty"`
+}
+
+// The formats of SBOMs
+const (
+	sbomFormatSpdx      = "spdx"
+	sbomFormatCycloneDx = "cyclonedx"
+)
+
+// syftFormat returns the Syft output format of the SBOM, and the extension of the SBOM file.
+func (o *SbomOptions) syftFormat() (string, string) {
+	if o != nil && o.Format == sbomFormatCycloneDx {
+		return syft.FormatCycloneDxJson, ".cdx.json"
+	}
+
+	return syft.FormatSpdxJson, ".spdx.json"
+}
+
+// validateSbomOptions returns the problems of the SBOM options of a service.
+func validateSbomOptions(options *SbomOptions, scope string) []string {
+	if options == nil {
+		return nil
+	}
+
+	if options.Format != "" && options.Format != sbomFormatSpdx && options.Format != sbomFormatCycloneDx {
+		return []string{fmt.Sprintf(
+			"%s: sbom.format must be '%s' or '%s', got '%s'", scope, sbomFormatSpdx, sbomFormatCycloneDx, options.Format)}
+	}
+
+	return nil
+}
+
+// generateSbom generates the SBOM of the package of the service, and records the path of the SBOM file in the metadata
+// of the package artifact. The SBOM of a zip package is written next to it, and the SBOM of a container image or a
+// directory is written to outputPath, or to a temporary directory when outputPath is empty.
+func (sm *serviceManager) generateSbom(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageArtifact *Artifact,
+	outputPath string,
+	progress *async.Progress[ServiceProgress],
+) error {
+	if packageArtifact.LocationKind != LocationKindLocal {
+		log.Printf("skipping SBOM of service %s, its package %s isn't local", serviceConfig.Name, packageArtifact.Location)
+		return nil
+	}
+
+	var source string
+	switch packageArtifact.Kind {
+	case ArtifactKindArchive:
+		source = syft.FileSource(packageArtifact.Location)
+	case ArtifactKindDirectory:
+		source = syft.DirectorySource(packageArtifact.Location)
+	case ArtifactKindContainer:
+		var dockerCli *docker.Cli
+		if err := sm.serviceLocator.Resolve(&dockerCli); err != nil {
+			return fmt.Errorf("resolving docker: %w", err)
+		}
+
+		source = syft.ImageSource(dockerCli.ContainerEngine(), packageArtifact.Location)
+	default:
+		log.Printf("skipping SBOM of service %s, packages of kind %s aren't supported",
+			serviceConfig.Name, packageArtifact.Kind)
+		return nil
+	}
+
+	sbomDir := filepath.Dir(packageArtifact.Location)
+	if packageArtifact.Kind != ArtifactKindArchive {
+		var err error
+		if sbomDir, err = sbomOutputDir(outputPath); err != nil {
+			return err
+		}
+	}
+
+	var syftCli *syft.Cli
+	if err := sm.serviceLocator.Resolve(&syftCli); err != nil {
+		return fmt.Errorf("resolving syft: %w", err)
+	}
+
+	format, extension := serviceConfig.Sbom.syftFormat()
+	sbomPath := filepath.Join(sbomDir, serviceConfig.Name+extension)
+
+	progress.SetProgress(NewServiceProgress("Generating SBOM"))
+	if err := syftCli.Generate(ctx, source, format, sbomPath); err != nil {
+		return fmt.Errorf("generating SBOM of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	if packageArtifact.Metadata == nil {
+		packageArtifact.Metadata = map[string]string{}
+	}
+	packageArtifact.Metadata[MetadataKeySbom] = sbomPath
+
+	return nil
+}
+
+// sbomOutputDir returns the directory the SBOMs of container images and directories are written to: the directory of
+// the package output path, or a new temporary directory when the output path is empty.
+func sbomOutputDir(outputPath string) (string, error) {
+	if outputPath == "" {
+		tempDir, err := os.MkdirTemp("", "azd-sbom")
+		if err != nil {
+			return "", fmt.Errorf("creating SBOM directory: %w", err)
+		}
+
+		return tempDir, nil
+	}
+
+	sbomDir := outputPath
+	if filepath.Ext(outputPath) != "" {
+		sbomDir = filepath.Dir(outputPath)
+	}
+
+	if err := os.MkdirAll(sbomDir, osutil.PermissionDirectory); err != nil {
+		return "", fmt.Errorf("creating SBOM directory '%s': %w", sbomDir, err)
+	}
+
+	return sbomDir, nil
+}
@@ -0,0 +1,154 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/syft"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+)
+
+func Test_validateSbomOptions(t *testing.T) {
+	scope := "service 'web'"
+	require.Nil(t, validateSbomOptions(nil, scope))
+	require.Nil(t, validateSbomOptions(&SbomOptions{}, scope))
+	require.Nil(t, validateSbomOptions(&SbomOptions{Format: "cyclonedx"}, scope))
+
+	problems := validateSbomOptions(&SbomOptions{Format: "spdx-json"}, scope)
+	require.Len(t, problems, 1)
+	require.Contains(t, problems[0], "sbom.format must be 'spdx' or 'cyclonedx', got 'spdx-json'")
+}
+
+func Test_ServiceManager_Package_Sbom(t *testing.T) {
+	packageWithSbom := func(
+		t *testing.T, serviceConfig *ServiceConfig, options *PackageOptions,
+	) (*ServicePackageResult, []string, error) {
+		mockContext := mocks.NewMockContext(t.Context())
+		setupMocksForServiceManager(mockContext)
+		mockContext.Container.MustRegisterSingleton(syft.NewCli)
+		mockContext.Container.MustRegisterSingleton(docker.NewCli)
+
+		var scanArgs []string
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.HasPrefix(command, "syft scan")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			scanArgs = args.Args
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		sm := createServiceManager(mockContext, environment.New("test"), ServiceOperationCache{})
+		if serviceConfig.Sbom != nil {
+			requiredTools, err := sm.GetRequiredTools(*mockContext.Context, serviceConfig)
+			require.NoError(t, err)
+			require.True(t, slices.ContainsFunc(requiredTools, func(tool tools.ExternalTool) bool {
+				return tool.Name() == "Syft"
+			}))
+		}
+
+		ctx := context.WithValue(*mockContext.Context, frameworkPackageCalled, new(false))
+		ctx = context.WithValue(ctx, serviceTargetPackageCalled, new(false))
+		result, err := logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServicePackageResult, error) {
+			return sm.Package(ctx, serviceConfig, nil, progress, options)
+		})
+		return result, scanArgs, err
+	}
+
+	t.Run("Configured", func(t *testing.T) {
+		serviceConfig := createTestServiceConfig(t.TempDir(), ServiceTargetFake, ServiceLanguageFake)
+		serviceConfig.Sbom = &SbomOptions{Format: sbomFormatCycloneDx}
+
+		result, scanArgs, err := packageWithSbom(t, serviceConfig, nil)
+		require.NoError(t, err)
+
+		sbomPath := filepath.Join(filepath.Dir("/fake/service-target/package/path"), "api.cdx.json")
+		require.Equal(t, []string{
+			"scan", "file:/fake/service-target/package/path", "--output", "cyclonedx-json=" + sbomPath, "--quiet",
+		}, scanArgs)
+
+		packageArtifact, has := result.Artifacts.FindLast()
+		require.True(t, has)
+		require.Equal(t, sbomPath, packageArtifact.Metadata[MetadataKeySbom])
+	})
+
+	t.Run("Flag", func(t *testing.T) {
+		serviceConfig := createTestServiceConfig(t.TempDir(), ServiceTargetFake, ServiceLanguageFake)
+
+		_, scanArgs, err := packageWithSbom(t, serviceConfig, &PackageOptions{Sbom: true})
+		require.NoError(t, err)
+		sbomPath := filepath.Join(filepath.Dir("/fake/service-target/package/path"), "api.spdx.json")
+		require.Contains(t, scanArgs, "spdx-json="+sbomPath)
+	})
+
+	t.Run("NotConfigured", func(t *testing.T) {
+		serviceConfig := createTestServiceConfig(t.TempDir(), ServiceTargetFake, ServiceLanguageFake)
+
+		_, scanArgs, err := packageWithSbom(t, serviceConfig, nil)
+		require.NoError(t, err)
+		require.Nil(t, scanArgs)
+	})
+}
+
+func Test_ServiceManager_generateSbom_Container(t *testing.T) {
+	t.Setenv("AZD_CONTAINER_RUNTIME", "podman")
+
+	mockContext := mocks.NewMockContext(t.Context())
+	mockContext.Container.MustRegisterSingleton(syft.NewCli)
+	mockContext.Container.MustRegisterSingleton(docker.NewCli)
+
+	var scanArgs []string
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.HasPrefix(command, "syft scan")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		scanArgs = args.Args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	sm := createServiceManager(mockContext, environment.New("test"), ServiceOperationCache{}).(*serviceManager)
+	serviceConfig := createTestServiceConfig(t.TempDir(), ContainerAppTarget, ServiceLanguageTypeScript)
+	packageArtifact := &Artifact{
+		Kind:         ArtifactKindContainer,
+		Location:     "api:azd-deploy-1",
+		LocationKind: LocationKindLocal,
+	}
+	outputPath := filepath.Join(t.TempDir(), "dist")
+
+	err := sm.generateSbom(
+		*mockContext.Context, serviceConfig, packageArtifact, outputPath, async.NewNoopProgress[ServiceProgress]())
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"scan", "podman:api:azd-deploy-1", "--output", "spdx-json=" + filepath.Join(outputPath, "api.spdx.json"), "--quiet",
+	}, scanArgs)
+	require.DirExists(t, outputPath)
+	require.Equal(t, filepath.Join(outputPath, "api.spdx.json"), packageArtifact.Metadata[MetadataKeySbom])
+}
+
+func Test_Artifact_ToString_Sbom(t *testing.T) {
+	artifact := &Artifact{
+		Kind:         ArtifactKindContainer,
+		Location:     "api:azd-deploy-1",
+		LocationKind: LocationKindLocal,
+		Metadata: map[string]string{
+			MetadataKeySbom:          "/dist/api.spdx.json",
+			MetadataKeySbomReference: "sha256:9f86/api.spdx.json",
+		},
+	}
+
+	result := artifact.ToString("  ")
+	require.Contains(t, result, "\n  - SBOM: ")
+	require.Contains(t, result, "/dist/api.spdx.json")
+	require.Contains(t, result, "\n  - SBOM Reference: ")
+	require.Contains(t, result, "sha256:9f86/api.spdx.json")
+}
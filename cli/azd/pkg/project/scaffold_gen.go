@@ -5,8 +5,10 @@ package project
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
+	"log"
 	"maps"
 	"os"
 	"path/filepath"
@@ -80,6 +82,10 @@ func tempInfra(
 		return nil, fmt.Errorf("writing infrastructure: %w", err)
 	}
 
+	if err := overlayEjectedInfra(prjConfig, tmpDir); err != nil {
+		return nil, err
+	}
+
 	return &Infra{
 		Options: provisioning.Options{
 			Provider: provisioning.Bicep,
@@ -91,6 +97,45 @@ func tempInfra(
 	}, nil
 }
 
+// overlayEjectedInfra replaces the generated files in dir with the files ejected to the infra directory of the project
+// by `azd infra generate --only`.
+func overlayEjectedInfra(prjConfig *ProjectConfig, dir string) error {
+	infraRoot := DefaultProvisioningOptions.Path
+	if prjConfig.Infra.Path != "" {
+		infraRoot = prjConfig.Infra.Path
+	}
+	if !filepath.IsAbs(infraRoot) {
+		infraRoot = filepath.Join(prjConfig.Path, infraRoot)
+	}
+
+	manifest, err := LoadInfraManifest(infraRoot)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range manifest.Ejected {
+		contents, err := os.ReadFile(filepath.Join(infraRoot, filepath.FromSlash(path)))
+		if errors.Is(err, os.ErrNotExist) {
+			log.Printf("ejected infrastructure file %s no longer exists, using the generated version", path)
+			continue
+		} else if err != nil {
+			return fmt.Errorf("reading ejected infrastructure file: %w", err)
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(target), osutil.PermissionDirectoryOwnerOnly); err != nil {
+			return err
+		}
+
+		log.Printf("using ejected infrastructure file %s", path)
+		if err := os.WriteFile(target, contents, osutil.PermissionFile); err != nil {
+			return fmt.Errorf("writing ejected infrastructure file: %w", err)
+		}
+	}
+
+	return nil
+}
+
 // Generates the filesystem of all infrastructure files to be placed, rooted at the project directory.
 // The content only includes `./infra` currently.
 func infraFsForProject(ctx context.Context, prjConfig *ProjectConfig) (fs.FS, error) {
@@ -174,8 +219,8 @@ func infraSpec(projectConfig *ProjectConfig) (*scaffold.InfraSpec, error) {
 				existing.ResourceType = resourceMeta.ParentForEval
 			}
 
-			if res.Type == ResourceTypeKeyVault {
-				// For Key Vault, we grant read access to secrets by default
+			if res.Type == ResourceTypeKeyVault || res.Type == ResourceTypeFeatureFlags {
+				// For Key Vault and feature flags, we grant read access by default
 				existing.RoleAssignments = resourceMeta.RoleAssignments.Read
 			}
 
@@ -196,7 +241,10 @@ func infraSpec(projectConfig *ProjectConfig) (*scaffold.InfraSpec, error) {
 
 		switch res.Type {
 		case ResourceTypeDbRedis:
-			infraSpec.DbRedis = &scaffold.DatabaseRedis{}
+			props, _ := res.Props.(DatabaseProps)
+			infraSpec.DbRedis = &scaffold.DatabaseRedis{
+				Private: props.Private,
+			}
 		case ResourceTypeDbMongo:
 			infraSpec.DbCosmosMongo = &scaffold.DatabaseCosmosMongo{
 				DatabaseName: res.Name,
@@ -215,8 +263,10 @@ func infraSpec(projectConfig *ProjectConfig) (*scaffold.InfraSpec, error) {
 				Containers:   containers,
 			}
 		case ResourceTypeDbPostgres:
+			props, _ := res.Props.(DatabaseProps)
 			infraSpec.DbPostgres = &scaffold.DatabasePostgres{
 				DatabaseName: res.Name,
+				Private:      props.Private,
 			}
 		case ResourceTypeDbMySql:
 			infraSpec.DbMySql = &scaffold.DatabaseMysql{
@@ -274,12 +324,24 @@ func infraSpec(projectConfig *ProjectConfig) (*scaffold.InfraSpec, error) {
 				return nil, fmt.Errorf("resources.%s.version is required", res.Name)
 			}
 
+			sku := scaffold.AIModelSku{
+				Name:     props.Sku.Name,
+				Capacity: props.Sku.Capacity,
+			}
+			if sku.Name == "" {
+				sku.Name = "Standard"
+			}
+			if sku.Capacity == 0 {
+				sku.Capacity = 20
+			}
+
 			infraSpec.AIModels = append(infraSpec.AIModels, scaffold.AIModel{
 				Name: res.Name,
 				Model: scaffold.AIModelModel{
 					Name:    props.Model.Name,
 					Version: props.Model.Version,
 				},
+				Sku: sku,
 			})
 		case ResourceTypeMessagingEventHubs:
 			if infraSpec.EventHubs != nil {
@@ -333,11 +395,20 @@ func infraSpec(projectConfig *ProjectConfig) (*scaffold.InfraSpec, error) {
 			infraSpec.AiFoundryProject = &foundrySpec
 		case ResourceTypeKeyVault:
 			infraSpec.KeyVault = &scaffold.KeyVault{}
+		case ResourceTypeFeatureFlags:
+			infraSpec.FeatureFlags = &scaffold.FeatureFlags{}
 		case ResourceTypeAiSearch:
 			infraSpec.AISearch = &scaffold.AISearch{}
 		}
 	}
 
+	// databases with private networking are reached through private endpoints in a virtual network, which the
+	// services are joined to
+	if (infraSpec.DbRedis != nil && infraSpec.DbRedis.Private) ||
+		(infraSpec.DbPostgres != nil && infraSpec.DbPostgres.Private) {
+		infraSpec.VirtualNetwork = &scaffold.VirtualNetwork{}
+	}
+
 	// create reverse frontends -> backends mapping
 	for i := range infraSpec.Services {
 		svc := &infraSpec.Services[i]
@@ -552,6 +623,8 @@ func mapHostUses(
 			svcSpec.AISearch = &scaffold.AISearchReference{}
 		case ResourceTypeKeyVault:
 			svcSpec.KeyVault = &scaffold.KeyVaultReference{}
+		case ResourceTypeFeatureFlags:
+			svcSpec.FeatureFlags = &scaffold.FeatureFlagsReference{}
 		}
 	}
 
@@ -7,9 +7,11 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"sync"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/apphost"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
@@ -56,6 +58,10 @@ type ServiceConfig struct {
 	Hooks HooksConfig `yaml:"hooks,omitempty"`
 	// Dependencies on other services and resources
 	Uses []string `yaml:"uses,omitempty"`
+	// Services to deploy before this service. Unlike Uses, only orders the deployment of the services.
+	DependsOn []string `yaml:"dependsOn,omitempty"`
+	// Tags used to select a subset of services, for example with `azd deploy --tag frontend`
+	Tags []string `yaml:"tags,omitempty"`
 	// Options specific to the DotNetContainerApp target. These are set by the importer and
 	// can not be controlled via the project file today.
 	DotNetContainerApp *DotNetContainerAppOptions `yaml:"-,omitempty"`
@@ -66,12 +72,38 @@ type ServiceConfig struct {
 	useDotNetPublishForDockerBuild *bool
 	// Environment variables to set for the service
 	Environment osutil.ExpandableMap `yaml:"env,omitempty"`
+	// App settings synced from the azd environment when the service is deployed
+	AppSettings *AppSettingsOptions `yaml:"appSettings,omitempty"`
 	// Condition for deploying the service. When evaluated, the service is only deployed if the value
 	// is a truthy boolean (1, true, TRUE, True, yes). If not defined, the service is enabled by default.
 	Condition osutil.ExpandableString `yaml:"condition,omitempty"`
 	// Whether to build the service remotely. Only applicable to function app services.
 	// When set to nil (unset), the default behavior based on language is used.
 	RemoteBuild *bool `yaml:"remoteBuild,omitempty"`
+	// When set, the tools invoked to restore, build and package the service only receive the environment variables
+	// named in the allow-list instead of the full azd process and azd environment values.
+	EnvIsolation *exec.EnvIsolation `yaml:"envIsolation,omitempty"`
+	// The OpenAPI description of the API implemented by the service, used to generate API clients when the service is
+	// packaged and to publish the API when the service is deployed
+	OpenApi *OpenApiOptions `yaml:"openapi,omitempty"`
+	// How a new revision of a container app service is rolled out, for example with a canary or blue/green deployment
+	Rollout *DeploymentStrategyOptions `yaml:"rollout,omitempty"`
+	// The trigger and replica configuration of a container app service deployed to a Container Apps job, and whether
+	// an execution is started to validate the deployment
+	Job *ContainerAppJobOptions `yaml:"job,omitempty"`
+	// The staging slot an App Service is deployed to, validated in and swapped with production from
+	Slot *AppServiceSlotOptions `yaml:"slot,omitempty"`
+	// The deployment storage and host readiness settings of a function app on a Flex Consumption plan
+	FunctionApp *FunctionAppOptions `yaml:"functionApp,omitempty"`
+	// The preview environment a Static Web App service is deployed to, instead of production
+	StaticWebApp *StaticWebAppOptions `yaml:"staticWebApp,omitempty"`
+	// The HTTP health check run after the service is deployed, which fails the deployment or rolls the service back
+	// when the service doesn't become healthy
+	HealthCheck *HealthCheckOptions `yaml:"healthCheck,omitempty"`
+	// The software bill of materials (SBOM) generated when the service is packaged
+	Sbom *SbomOptions `yaml:"sbom,omitempty"`
+	// How `azd run` starts the service locally, when it can't be inferred from its language, or listens on a port
+	Run *LocalRunOptions `yaml:"run,omitempty"`
 
 	// AdditionalProperties captures any unknown YAML fields for extension support
 	AdditionalProperties map[string]any `yaml:",inline"`
@@ -133,6 +165,19 @@ func (sc *ServiceConfig) IsEnabled(getenv func(string) string) (bool, error) {
 	return isConditionTrue(value), nil
 }
 
+// DeployDependencies returns the names of the services and resources the service is deployed after: the entries of
+// Uses, followed by the entries of DependsOn, without duplicates. Entries naming resources don't order deployment.
+func (sc *ServiceConfig) DeployDependencies() []string {
+	dependencies := make([]string, 0, len(sc.Uses)+len(sc.DependsOn))
+	for _, name := range slices.Concat(sc.Uses, sc.DependsOn) {
+		if !slices.Contains(dependencies, name) {
+			dependencies = append(dependencies, name)
+		}
+	}
+
+	return dependencies
+}
+
 // isConditionTrue parses a string value as a boolean condition.
 // Returns true for: "1", "true", "TRUE", "True", "yes", "YES", "Yes"
 // Returns false for all other values.
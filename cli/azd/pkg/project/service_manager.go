@@ -22,11 +22,14 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kiota"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/swa"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/syft"
 )
 
 const (
@@ -199,6 +202,24 @@ func (sm *serviceManager) GetRequiredTools(ctx context.Context, serviceConfig *S
 	requiredTools = append(requiredTools, frameworkService.RequiredExternalTools(ctx, serviceConfig)...)
 	requiredTools = append(requiredTools, serviceTarget.RequiredExternalTools(ctx, serviceConfig)...)
 
+	if serviceConfig.OpenApi != nil && len(serviceConfig.OpenApi.Clients) > 0 {
+		var kiotaCli *kiota.Cli
+		if err := sm.serviceLocator.Resolve(&kiotaCli); err != nil {
+			return nil, fmt.Errorf("resolving kiota: %w", err)
+		}
+
+		requiredTools = append(requiredTools, kiotaCli)
+	}
+
+	if serviceConfig.Sbom != nil {
+		var syftCli *syft.Cli
+		if err := sm.serviceLocator.Resolve(&syftCli); err != nil {
+			return nil, fmt.Errorf("resolving syft: %w", err)
+		}
+
+		requiredTools = append(requiredTools, syftCli)
+	}
+
 	return tools.Unique(requiredTools), nil
 }
 
@@ -286,7 +307,7 @@ func (sm *serviceManager) Restore(
 		serviceConfig,
 		serviceContext,
 		func() (*ServiceRestoreResult, error) {
-			return frameworkService.Restore(ctx, serviceConfig, serviceContext, progress)
+			return frameworkService.Restore(serviceToolContext(ctx, serviceConfig), serviceConfig, serviceContext, progress)
 		},
 	)
 
@@ -338,7 +359,7 @@ func (sm *serviceManager) Build(
 		serviceConfig,
 		serviceContext,
 		func() (*ServiceBuildResult, error) {
-			return frameworkService.Build(ctx, serviceConfig, serviceContext, progress)
+			return frameworkService.Build(serviceToolContext(ctx, serviceConfig), serviceConfig, serviceContext, progress)
 		},
 	)
 
@@ -418,7 +439,12 @@ func (sm *serviceManager) Package(
 		serviceConfig,
 		serviceContext,
 		func() (*ServicePackageResult, error) {
-			frameworkPackageResult, err := frameworkService.Package(ctx, serviceConfig, serviceContext, progress)
+			if err := sm.packageOpenApi(ctx, serviceConfig, progress); err != nil {
+				return nil, err
+			}
+
+			toolCtx := serviceToolContext(ctx, serviceConfig)
+			frameworkPackageResult, err := frameworkService.Package(toolCtx, serviceConfig, serviceContext, progress)
 			if err != nil {
 				return nil, err
 			}
@@ -428,7 +454,7 @@ func (sm *serviceManager) Package(
 				return nil, fmt.Errorf("failed to add framework package artifacts to service context: %w", err)
 			}
 
-			serviceTargetPackageResult, err := serviceTarget.Package(ctx, serviceConfig, serviceContext, progress)
+			serviceTargetPackageResult, err := serviceTarget.Package(toolCtx, serviceConfig, serviceContext, progress)
 			if err != nil {
 				return nil, err
 			}
@@ -487,6 +513,12 @@ func (sm *serviceManager) Package(
 
 			packageArtifact.Location = destFilePath
 		}
+
+		if options.Sbom || serviceConfig.Sbom != nil {
+			if err := sm.generateSbom(ctx, serviceConfig, packageArtifact, options.OutputPath, progress); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	return packageResult, nil
@@ -644,6 +676,18 @@ func (sm *serviceManager) Deploy(
 		}
 	}
 
+	// The signature of the deployed image is part of the deploy result, for the supply-chain records of the deployment
+	if image, found := serviceContext.Publish.FindFirst(WithKind(ArtifactKindContainer)); found &&
+		image.Metadata[MetadataKeySignature] != "" {
+		if err := deployResult.Artifacts.Add(image); err != nil {
+			return nil, fmt.Errorf("failed to add signed image artifact: %w", err)
+		}
+	}
+
+	if err := sm.publishOpenApi(ctx, serviceConfig, deployResult, progress); err != nil {
+		return nil, fmt.Errorf("failed publishing API of service '%s': %w", serviceConfig.Name, err)
+	}
+
 	sm.setOperationResult(serviceConfig, ServiceEventDeploy, deployResult)
 	return deployResult, nil
 }
@@ -946,6 +990,13 @@ func appendOperationArtifacts(serviceContext *ServiceContext, eventType ext.Even
 	return nil
 }
 
+// serviceToolContext carries the service's environment isolation settings, if any, into its restore, build and package
+// operations. Only the commands that run the project's own build (npm scripts, dotnet build, mvn package, ...) apply
+// them; azd's other tools (docker, az, ...) and the service's lifecycle hooks are not affected.
+func serviceToolContext(ctx context.Context, serviceConfig *ServiceConfig) context.Context {
+	return exec.WithEnvIsolation(ctx, serviceConfig.EnvIsolation)
+}
+
 func runCommand[T any](
 	ctx context.Context,
 	eventName ext.Event,
@@ -39,6 +39,7 @@ const (
 
 	frameworkRestoreCalled     contextKey = "frameworkRestoreCalled"
 	frameworkBuildCalled       contextKey = "frameworkBuildCalled"
+	frameworkBuildIsolation    contextKey = "frameworkBuildIsolation"
 	frameworkPackageCalled     contextKey = "frameworkPackageCalled"
 	serviceTargetPackageCalled contextKey = "serviceTargetPackageCalled"
 	serviceTargetDeployCalled  contextKey = "serviceTargetDeployCalled"
@@ -189,6 +190,35 @@ func Test_ServiceManager_Build(t *testing.T) {
 	require.True(t, raisedPostBuildEvent)
 }
 
+func Test_ServiceManager_Build_EnvIsolation(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	setupMocksForServiceManager(mockContext)
+	env := environment.New("test")
+	sm := createServiceManager(mockContext, env, ServiceOperationCache{})
+	serviceConfig := createTestServiceConfig("./src/api", ServiceTargetFake, ServiceLanguageFake)
+	serviceConfig.EnvIsolation = &exec.EnvIsolation{Allow: []string{"VITE_*"}}
+
+	var hookIsolation *exec.EnvIsolation
+	_ = serviceConfig.AddHandler(
+		*mockContext.Context,
+		"prebuild",
+		func(ctx context.Context, args ServiceLifecycleEventArgs) error {
+			hookIsolation = exec.EnvIsolationFromContext(ctx)
+			return nil
+		})
+
+	var buildIsolation *exec.EnvIsolation
+	ctx := context.WithValue(*mockContext.Context, frameworkBuildIsolation, &buildIsolation)
+
+	_, err := logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServiceBuildResult, error) {
+		return sm.Build(ctx, serviceConfig, nil, progress)
+	})
+
+	require.NoError(t, err)
+	require.Same(t, serviceConfig.EnvIsolation, buildIsolation)
+	require.Nil(t, hookIsolation)
+}
+
 func Test_ServiceManager_Package(t *testing.T) {
 	mockContext := mocks.NewMockContext(t.Context())
 	setupMocksForServiceManager(mockContext)
@@ -658,6 +688,10 @@ func (f *fakeFramework) Build(
 		*buildCalled = true
 	}
 
+	if isolation, ok := ctx.Value(frameworkBuildIsolation).(**exec.EnvIsolation); ok {
+		*isolation = exec.EnvIsolationFromContext(ctx)
+	}
+
 	runArgs := exec.NewRunArgs("fake-framework", "build")
 	result, err := f.commandRunner.Run(ctx, runArgs)
 	if err != nil {
@@ -66,6 +66,8 @@ type ServiceBuildResult struct {
 
 type PackageOptions struct {
 	OutputPath string
+	// Sbom generates the SBOM of the package, even when the service doesn't configure one
+	Sbom bool
 }
 
 // ServicePackageResult is the result of a successful Package operation
@@ -81,4 +83,6 @@ type ServicePublishResult struct {
 // ServiceDeployResult is the result of a successful Deploy operation
 type ServiceDeployResult struct {
 	Artifacts ArtifactCollection `json:"artifacts"`
+	// HealthCheck is the result of the health check of the deployed service, when it has one
+	HealthCheck *HealthCheckResult `json:"healthCheck,omitempty"`
 }
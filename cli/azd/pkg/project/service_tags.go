@@ -0,0 +1,114 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// serviceTagRegex matches a valid service tag, for example "frontend" or "tier-1".
+var serviceTagRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ServiceTagFilter selects services by their tags. A service matches when it has at least one of the included tags,
+// or when there are no included tags, and none of the excluded tags. Tags are compared case-insensitively.
+//
+// A nil *ServiceTagFilter matches every service.
+type ServiceTagFilter struct {
+	include []string
+	exclude []string
+}
+
+// ParseServiceTagFilter creates a filter from tag expressions, such as the values of a `--tag` flag. An expression is
+// a tag to include, for example "frontend", or a tag prefixed with "!" to exclude, for example "!critical". Returns
+// nil when there are no expressions.
+func ParseServiceTagFilter(expressions []string) (*ServiceTagFilter, error) {
+	if len(expressions) == 0 {
+		return nil, nil
+	}
+
+	filter := &ServiceTagFilter{}
+	for _, expression := range expressions {
+		expression = strings.TrimSpace(expression)
+		tag, excluded := strings.CutPrefix(expression, "!")
+		if err := validateServiceTag(tag); err != nil {
+			return nil, fmt.Errorf("invalid tag filter '%s': %w", expression, err)
+		}
+
+		if excluded {
+			filter.exclude = append(filter.exclude, tag)
+		} else {
+			filter.include = append(filter.include, tag)
+		}
+	}
+
+	return filter, nil
+}
+
+// Matches returns true when the service is selected by the filter.
+func (f *ServiceTagFilter) Matches(serviceConfig *ServiceConfig) bool {
+	if f == nil {
+		return true
+	}
+
+	if slices.ContainsFunc(f.exclude, serviceConfig.HasTag) {
+		return false
+	}
+
+	return len(f.include) == 0 || slices.ContainsFunc(f.include, serviceConfig.HasTag)
+}
+
+// Filter returns the services selected by the filter, preserving their order. Returns an error when the filter
+// doesn't select any service.
+func (f *ServiceTagFilter) Filter(services []*ServiceConfig) ([]*ServiceConfig, error) {
+	if f == nil {
+		return services, nil
+	}
+
+	filtered := make([]*ServiceConfig, 0, len(services))
+	for _, serviceConfig := range services {
+		if f.Matches(serviceConfig) {
+			filtered = append(filtered, serviceConfig)
+		}
+	}
+
+	if len(filtered) == 0 {
+		return nil, fmt.Errorf("no services match the tag filter '%s'", f)
+	}
+
+	return filtered, nil
+}
+
+// String returns the filter as comma separated tag expressions, for example "frontend, !critical".
+func (f *ServiceTagFilter) String() string {
+	if f == nil {
+		return ""
+	}
+
+	expressions := slices.Clone(f.include)
+	for _, tag := range f.exclude {
+		expressions = append(expressions, "!"+tag)
+	}
+
+	return strings.Join(expressions, ", ")
+}
+
+// HasTag returns true when the service is tagged with tag, ignoring case.
+func (sc *ServiceConfig) HasTag(tag string) bool {
+	return slices.ContainsFunc(sc.Tags, func(serviceTag string) bool {
+		return strings.EqualFold(serviceTag, tag)
+	})
+}
+
+// validateServiceTag returns an error when tag can't be used as a service tag.
+func validateServiceTag(tag string) error {
+	if !serviceTagRegex.MatchString(tag) {
+		return fmt.Errorf(
+			"tags must start with a letter or digit and only contain letters, digits, '.', '_' and '-'")
+	}
+
+	return nil
+}
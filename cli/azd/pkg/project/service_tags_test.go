@@ -0,0 +1,154 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ServiceTagFilter(t *testing.T) {
+	services := []*ServiceConfig{
+		{Name: "web", Tags: []string{"frontend"}},
+		{Name: "api", Tags: []string{"backend", "Critical"}},
+		{Name: "worker", Tags: []string{"backend"}},
+		{Name: "docs"},
+	}
+
+	tests := []struct {
+		name        string
+		expressions []string
+		want        []string
+		wantErr     string
+	}{
+		{
+			name: "NoFilter",
+			want: []string{"web", "api", "worker", "docs"},
+		},
+		{
+			name:        "Include",
+			expressions: []string{"backend"},
+			want:        []string{"api", "worker"},
+		},
+		{
+			name:        "IncludeAny",
+			expressions: []string{"frontend", "backend"},
+			want:        []string{"web", "api", "worker"},
+		},
+		{
+			name:        "Exclude",
+			expressions: []string{"!critical"},
+			want:        []string{"web", "worker", "docs"},
+		},
+		{
+			name:        "IncludeAndExclude",
+			expressions: []string{"backend", " !CRITICAL "},
+			want:        []string{"worker"},
+		},
+		{
+			name:        "NoMatch",
+			expressions: []string{"database"},
+			wantErr:     "no services match the tag filter 'database'",
+		},
+		{
+			name:        "Invalid",
+			expressions: []string{"!"},
+			wantErr:     "invalid tag filter '!'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := ParseServiceTagFilter(tt.expressions)
+			if err == nil {
+				var filtered []*ServiceConfig
+				filtered, err = filter.Filter(services)
+				if err == nil {
+					names := []string{}
+					for _, serviceConfig := range filtered {
+						names = append(names, serviceConfig.Name)
+					}
+					require.Equal(t, tt.want, names)
+				}
+			}
+
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_ServiceTagFilter_String(t *testing.T) {
+	filter, err := ParseServiceTagFilter([]string{"!critical", "frontend", "backend"})
+	require.NoError(t, err)
+	require.Equal(t, "frontend, backend, !critical", filter.String())
+
+	var nilFilter *ServiceTagFilter
+	require.Equal(t, "", nilFilter.String())
+	require.True(t, nilFilter.Matches(&ServiceConfig{}))
+}
+
+func Test_Parse_ServiceTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name: "Valid",
+			yaml: `
+name: test-proj
+pipeline:
+  tags: [frontend, "!critical"]
+services:
+  web:
+    project: src/web
+    language: js
+    host: appservice
+    tags: [frontend, tier-1]
+`,
+		},
+		{
+			name: "InvalidServiceTag",
+			yaml: `
+name: test-proj
+services:
+  web:
+    project: src/web
+    language: js
+    host: appservice
+    tags: ["front end"]
+`,
+			wantErr: "parsing service web: invalid tag 'front end'",
+		},
+		{
+			name: "InvalidPipelineTag",
+			yaml: `
+name: test-proj
+pipeline:
+  tags: ["!"]
+`,
+			wantErr: "parsing pipeline tags",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectConfig, err := Parse(t.Context(), tt.yaml)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, []string{"frontend", "tier-1"}, projectConfig.Services["web"].Tags)
+			require.Equal(t, []string{"frontend", "!critical"}, projectConfig.Pipeline.Tags)
+		})
+	}
+}
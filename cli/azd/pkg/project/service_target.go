@@ -121,6 +121,29 @@ type ServiceTarget interface {
 	) ([]string, error)
 }
 
+// ServiceShell is implemented by service targets that can run a command, such as a shell, in a running instance of a
+// service, attached to the console.
+type ServiceShell interface {
+	Shell(
+		ctx context.Context,
+		serviceConfig *ServiceConfig,
+		targetResource *environment.TargetResource,
+		options *ShellOptions,
+	) error
+}
+
+// ShellOptions configure a command run with ServiceShell.
+type ShellOptions struct {
+	// Command is the command to run, /bin/sh by default.
+	Command []string
+	// Instance is the replica or the pod to run the command in. The service target picks one when it's empty.
+	Instance string
+	// Container is the container of the instance to run the command in. The service target picks one when it's empty.
+	Container string
+	// Tty runs the command in a terminal, for interactive use.
+	Tty bool
+}
+
 func resourceTypeMismatchError(
 	resourceName string,
 	resourceType string,
@@ -18,6 +18,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/internal/tracing"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/events"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/armmsi"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/convert"
@@ -30,8 +31,8 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
-	"github.com/sethvargo/go-retry"
 	"go.opentelemetry.io/otel/attribute"
 )
 
@@ -60,6 +61,12 @@ type AksOptions struct {
 	Helm *helm.Config `yaml:"helm"`
 	// The kustomize configuration options
 	Kustomize *kustomize.Config `yaml:"kustomize"`
+	// The Azure Kubernetes Fleet Manager configuration options
+	Fleet *AksFleetOptions `yaml:"fleet,omitempty"`
+	// The GitOps configuration options
+	GitOps *AksGitOpsOptions `yaml:"gitops,omitempty"`
+	// The workload identity configuration options
+	WorkloadIdentity *AksWorkloadIdentityOptions `yaml:"workloadIdentity,omitempty"`
 }
 
 // The AKS ingress options
@@ -83,11 +90,14 @@ type aksTarget struct {
 	envManager             environment.Manager
 	console                input.Console
 	managedClustersService azapi.ManagedClustersService
+	fleetsService          azapi.FleetsService
+	msiService             armmsi.ArmMsiService
 	resourceManager        ResourceManager
 	kubectl                *kubectl.Cli
 	kubeLoginCli           *kubelogin.Cli
 	helmCli                *helm.Cli
 	kustomizeCli           *kustomize.Cli
+	gitCli                 *git.Cli
 	containerHelper        *ContainerHelper
 	featureManager         *alpha.FeatureManager
 }
@@ -98,11 +108,14 @@ func NewAksTarget(
 	envManager environment.Manager,
 	console input.Console,
 	managedClustersService azapi.ManagedClustersService,
+	fleetsService azapi.FleetsService,
+	msiService armmsi.ArmMsiService,
 	resourceManager ResourceManager,
 	kubectlCli *kubectl.Cli,
 	kubeLoginCli *kubelogin.Cli,
 	helmCli *helm.Cli,
 	kustomizeCli *kustomize.Cli,
+	gitCli *git.Cli,
 	containerHelper *ContainerHelper,
 	featureManager *alpha.FeatureManager,
 ) ServiceTarget {
@@ -111,11 +124,14 @@ func NewAksTarget(
 		envManager:             envManager,
 		console:                console,
 		managedClustersService: managedClustersService,
+		fleetsService:          fleetsService,
+		msiService:             msiService,
 		resourceManager:        resourceManager,
 		kubectl:                kubectlCli,
 		kubeLoginCli:           kubeLoginCli,
 		helmCli:                helmCli,
 		kustomizeCli:           kustomizeCli,
+		gitCli:                 gitCli,
 		containerHelper:        containerHelper,
 		featureManager:         featureManager,
 	}
@@ -135,6 +151,10 @@ func (t *aksTarget) RequiredExternalTools(ctx context.Context, serviceConfig *Se
 		allTools = append(allTools, t.kustomizeCli)
 	}
 
+	if serviceConfig.K8s.GitOps != nil {
+		allTools = append(allTools, t.gitCli)
+	}
+
 	return allTools
 }
 
@@ -251,9 +271,37 @@ func (t *aksTarget) Deploy(
 
 	artifacts := ArtifactCollection{}
 
+	// The workload identity stores the service account in the environment, so it's configured before the
+	// environment is synced for the manifest templates.
+	var serviceAccountManifest string
+	if serviceConfig.K8s.WorkloadIdentity != nil {
+		manifest, err := t.configureWorkloadIdentity(ctx, serviceConfig, targetResource, progress)
+		if err != nil {
+			return nil, fmt.Errorf("workload identity configuration failed: %w", err)
+		}
+
+		serviceAccountManifest = manifest
+	}
+
 	// Sync environment
 	t.kubectl.SetEnv(t.env.Dotenv())
 
+	// In GitOps mode the cluster is never accessed, the rendered manifests are committed to a repository instead
+	if serviceConfig.K8s.GitOps != nil {
+		return t.deployGitOps(ctx, serviceConfig, targetResource, serviceAccountManifest, progress)
+	}
+
+	// The service account is applied first, so the workloads of the service can reference it
+	if serviceAccountManifest != "" {
+		if err := t.ensureNamespace(ctx, t.getK8sNamespace(serviceConfig)); err != nil {
+			return nil, err
+		}
+
+		if _, err := t.kubectl.ApplyWithStdIn(ctx, serviceAccountManifest, nil); err != nil {
+			return nil, fmt.Errorf("failed applying kube service account: %w", err)
+		}
+	}
+
 	// Deploy k8s resources in the following order:
 	// 1. Helm
 	// 2. Kustomize
@@ -293,6 +341,16 @@ func (t *aksTarget) Deploy(
 		return nil, errors.New("no deployment manifests found")
 	}
 
+	// With a fleet the resources were applied to the hub cluster and still need to be rolled out to the member
+	// clusters. The hub does not run workloads, so there are no endpoints to fetch.
+	if serviceConfig.K8s.Fleet != nil {
+		if err := t.rolloutFleet(ctx, serviceConfig, targetResource, progress); err != nil {
+			return nil, err
+		}
+
+		return t.deployResult(targetResource, artifacts)
+	}
+
 	progress.SetProgress(NewServiceProgress("Fetching endpoints for AKS service"))
 	endpointArtifacts, err := t.getEndpointArtifacts(ctx, serviceConfig)
 	if err != nil {
@@ -313,6 +371,14 @@ func (t *aksTarget) Deploy(
 		}
 	}
 
+	return t.deployResult(targetResource, artifacts)
+}
+
+// deployResult adds the target resource to the deployment artifacts
+func (t *aksTarget) deployResult(
+	targetResource *environment.TargetResource,
+	artifacts ArtifactCollection,
+) (*ServiceDeployResult, error) {
 	var resourceArtifact *Artifact
 	if err := mapper.Convert(targetResource, &resourceArtifact); err == nil {
 		if err := artifacts.Add(resourceArtifact); err != nil {
@@ -353,6 +419,11 @@ func (t *aksTarget) deployManifests(
 		return false, nil, fmt.Errorf("failed applying kube manifests: %w", err)
 	}
 
+	// Deployments are not scheduled on a fleet hub cluster, the rollout is tracked by the fleet update run instead
+	if serviceConfig.K8s.Fleet != nil {
+		return true, nil, nil
+	}
+
 	deploymentName := serviceConfig.K8s.Deployment.Name
 	if deploymentName == "" {
 		deploymentName = serviceConfig.Name
@@ -391,16 +462,34 @@ func (t *aksTarget) deployKustomize(
 	}
 
 	task.SetProgress(NewServiceProgress("Applying k8s manifests with Kustomize"))
+	kustomizeDir, cleanup, err := t.prepareKustomize(ctx, serviceConfig)
+	if err != nil {
+		return false, err
+	}
+	defer cleanup()
+
+	// Finally apply manifests with kustomize using the -k flag
+	if err := t.kubectl.ApplyWithKustomize(ctx, kustomizeDir, nil); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// prepareKustomize resolves the kustomize directory and runs the configured env file generation and edits.
+// The returned cleanup function removes the generated .env file.
+func (t *aksTarget) prepareKustomize(ctx context.Context, serviceConfig *ServiceConfig) (string, func(), error) {
+	cleanup := func() {}
 	overlayPath, err := serviceConfig.K8s.Kustomize.Directory.Envsubst(t.env.Getenv)
 	if err != nil {
-		return false, fmt.Errorf("failed to envsubst kustomize directory: %w", err)
+		return "", cleanup, fmt.Errorf("failed to envsubst kustomize directory: %w", err)
 	}
 
 	// When deploying with kustomize we need to specify the full path to the kustomize directory.
 	// This can either be a base or overlay directory but must contain a kustomization.yaml file
 	kustomizeDir := filepath.Join(serviceConfig.Project.Path, serviceConfig.RelativePath, overlayPath)
 	if _, err := os.Stat(kustomizeDir); os.IsNotExist(err) {
-		return false, fmt.Errorf("kustomize directory '%s' does not exist: %w", kustomizeDir, err)
+		return "", cleanup, fmt.Errorf("kustomize directory '%s' does not exist: %w", kustomizeDir, err)
 	}
 
 	// Kustomize does not have a built in way to specify environment variables
@@ -410,7 +499,7 @@ func (t *aksTarget) deployKustomize(
 	if len(serviceConfig.K8s.Kustomize.Env) > 0 {
 		expandedEnvVars, err := serviceConfig.K8s.Kustomize.Env.Expand(t.env.Getenv)
 		if err != nil {
-			return false, fmt.Errorf("failed to expand kustomize environment variables: %w", err)
+			return "", cleanup, fmt.Errorf("failed to expand kustomize environment variables: %w", err)
 		}
 
 		builder := strings.Builder{}
@@ -422,10 +511,10 @@ func (t *aksTarget) deployKustomize(
 		// The godotenv library will quote values when writing the file without an option to disable
 		envFilePath := filepath.Join(kustomizeDir, ".env")
 		if err := os.WriteFile(envFilePath, []byte(builder.String()), osutil.PermissionFile); err != nil {
-			return false, fmt.Errorf("failed to write kustomize .env: %w", err)
+			return "", cleanup, fmt.Errorf("failed to write kustomize .env: %w", err)
 		}
 
-		defer os.Remove(envFilePath)
+		cleanup = func() { os.Remove(envFilePath) }
 	}
 
 	// Another common scenario is to use the kustomize edit commands to modify the kustomization.yaml
@@ -434,93 +523,19 @@ func (t *aksTarget) deployKustomize(
 	for _, edit := range serviceConfig.K8s.Kustomize.Edits {
 		editArgs, err := edit.Envsubst(t.env.Getenv)
 		if err != nil {
-			return false, fmt.Errorf("failed to envsubst kustomize edit: %w", err)
+			cleanup()
+			return "", func() {}, fmt.Errorf("failed to envsubst kustomize edit: %w", err)
 		}
 
 		if err := t.kustomizeCli.
 			WithCwd(kustomizeDir).
 			Edit(ctx, strings.Split(editArgs, " ")...); err != nil {
-			return false, err
-		}
-	}
-
-	// Finally apply manifests with kustomize using the -k flag
-	if err := t.kubectl.ApplyWithKustomize(ctx, kustomizeDir, nil); err != nil {
-		return false, err
-	}
-
-	return true, nil
-}
-
-// deployHelmCharts deploys helm charts to the k8s cluster
-func (t *aksTarget) deployHelmCharts(
-	ctx context.Context, serviceConfig *ServiceConfig,
-	task *async.Progress[ServiceProgress],
-) (bool, error) {
-	if serviceConfig.K8s.Helm == nil {
-		return false, nil
-	}
-
-	if !t.featureManager.IsEnabled(featureHelm) {
-		return false, fmt.Errorf("Helm support is not enabled. Run '%s' to enable it.", alpha.GetEnableCommand(featureHelm))
-	}
-
-	for _, repo := range serviceConfig.K8s.Helm.Repositories {
-		task.SetProgress(NewServiceProgress(fmt.Sprintf("Configuring helm repo: %s", repo.Name)))
-		if err := t.helmCli.AddRepo(ctx, repo); err != nil {
-			return false, err
-		}
-
-		if err := t.helmCli.UpdateRepo(ctx, repo.Name); err != nil {
-			return false, err
-		}
-	}
-
-	for _, release := range serviceConfig.K8s.Helm.Releases {
-		if release.Namespace == "" {
-			release.Namespace = t.getK8sNamespace(serviceConfig)
-		}
-
-		if err := t.ensureNamespace(ctx, release.Namespace); err != nil {
-			return false, err
-		}
-
-		task.SetProgress(NewServiceProgress(fmt.Sprintf("Installing helm release: %s", release.Name)))
-		if err := t.helmCli.Upgrade(ctx, release); err != nil {
-			return false, err
-		}
-
-		task.SetProgress(NewServiceProgress(fmt.Sprintf("Checking helm release status: %s", release.Name)))
-		stopProgress := startPollingProgress(
-			task, fmt.Sprintf("Waiting for helm release: %s", release.Name), 15*time.Second,
-		)
-		err := retry.Do(
-			ctx,
-			retry.WithMaxDuration(10*time.Minute, retry.NewConstant(5*time.Second)),
-			func(ctx context.Context) error {
-				status, err := t.helmCli.Status(ctx, release)
-				if err != nil {
-					return err
-				}
-
-				if status.Info.Status != helm.StatusKindDeployed {
-					fmt.Printf("Status: %s\n", status.Info.Status)
-					return retry.RetryableError(
-						fmt.Errorf("helm release '%s' is not ready, %w", release.Name, err),
-					)
-				}
-
-				return nil
-			},
-		)
-		stopProgress()
-
-		if err != nil {
-			return false, err
+			cleanup()
+			return "", func() {}, err
 		}
 	}
 
-	return true, nil
+	return kustomizeDir, cleanup, nil
 }
 
 // Gets the service endpoints for the AKS service target
@@ -529,6 +544,12 @@ func (t *aksTarget) Endpoints(
 	serviceConfig *ServiceConfig,
 	targetResource *environment.TargetResource,
 ) ([]string, error) {
+	// Workloads placed through a fleet run on the member clusters, which expose their own endpoints.
+	// In GitOps mode azd has no access to the cluster.
+	if serviceConfig.K8s.Fleet != nil || serviceConfig.K8s.GitOps != nil {
+		return []string{}, nil
+	}
+
 	artifactEndpoints, err := t.getEndpointArtifacts(ctx, serviceConfig)
 	if err != nil {
 		return nil, err
@@ -603,6 +624,10 @@ func (t *aksTarget) ensureClusterContext(
 		return kubeConfigPath, nil
 	}
 
+	if serviceConfig.K8s.Fleet != nil {
+		return t.ensureFleetContext(ctx, serviceConfig, targetResource, defaultNamespace)
+	}
+
 	// Login to AKS cluster
 	clusterName, err := t.resolveClusterName(serviceConfig, targetResource)
 	if err != nil {
@@ -631,9 +656,42 @@ func (t *aksTarget) ensureClusterContext(
 		)
 	}
 
+	// Get the provisioned cluster properties to inspect configuration
+	managedCluster, err := t.managedClustersService.Get(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		clusterName,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed retrieving managed cluster, %w", err)
+	}
+
+	azureRbacEnabled := managedCluster.Properties.AADProfile != nil &&
+		convert.ToValueWithDefault(managedCluster.Properties.AADProfile.EnableAzureRBAC, false)
+	localAccountsDisabled := convert.ToValueWithDefault(managedCluster.Properties.DisableLocalAccounts, false)
+
 	// The kubeConfig that we care about will also be at position 0
 	// I don't know if there is a valid use case where this credential results would container multiple configs
-	kubeConfig, err := kubectl.ParseKubeConfig(ctx, clusterCreds.Kubeconfigs[0].Value)
+	return t.configureKubeContext(
+		ctx,
+		clusterName,
+		clusterCreds.Kubeconfigs[0].Value,
+		defaultNamespace,
+		azureRbacEnabled || localAccountsDisabled,
+	)
+}
+
+// configureKubeContext adds the kube config returned for a cluster as a named context and makes it the current
+// context. When useAzdAuth is set, the kube config is converted to authenticate with azd.
+func (t *aksTarget) configureKubeContext(
+	ctx context.Context,
+	contextName string,
+	rawKubeConfig []byte,
+	defaultNamespace string,
+	useAzdAuth bool,
+) (string, error) {
+	kubeConfig, err := kubectl.ParseKubeConfig(ctx, rawKubeConfig)
 	if err != nil {
 		return "", fmt.Errorf(
 			"failed parsing kube config. Ensure your configuration is valid yaml. %w",
@@ -649,30 +707,15 @@ func (t *aksTarget) ensureClusterContext(
 		return "", err
 	}
 
-	// Create or update the kube config/context for the AKS cluster
-	kubeConfigPath, err = kubeConfigManager.AddOrUpdateContext(ctx, clusterName, kubeConfig)
+	// Create or update the kube config/context for the cluster
+	kubeConfigPath, err := kubeConfigManager.AddOrUpdateContext(ctx, contextName, kubeConfig)
 	if err != nil {
 		return "", fmt.Errorf("failed adding/updating kube context, %w", err)
 	}
 
-	// Get the provisioned cluster properties to inspect configuration
-	managedCluster, err := t.managedClustersService.Get(
-		ctx,
-		targetResource.SubscriptionId(),
-		targetResource.ResourceGroupName(),
-		clusterName,
-	)
-	if err != nil {
-		return "", fmt.Errorf("failed retrieving managed cluster, %w", err)
-	}
-
-	azureRbacEnabled := managedCluster.Properties.AADProfile != nil &&
-		convert.ToValueWithDefault(managedCluster.Properties.AADProfile.EnableAzureRBAC, false)
-	localAccountsDisabled := convert.ToValueWithDefault(managedCluster.Properties.DisableLocalAccounts, false)
-
 	// If we're connecting to a cluster with RBAC enabled and local accounts disabled
 	// then we need to convert the kube config to use the exec auth module with azd auth
-	if azureRbacEnabled || localAccountsDisabled {
+	if useAzdAuth {
 		convertOptions := &kubelogin.ConvertOptions{
 			Login:      "azd",
 			KubeConfig: kubeConfigPath,
@@ -689,15 +732,15 @@ func (t *aksTarget) ensureClusterContext(
 	}
 
 	// Merge the cluster config/context into the default kube config
-	kubeConfigPath, err = kubeConfigManager.MergeConfigs(ctx, "config", clusterName)
+	kubeConfigPath, err = kubeConfigManager.MergeConfigs(ctx, "config", contextName)
 	if err != nil {
 		return "", err
 	}
 
-	// Setup the default kube context to use the AKS cluster context
-	if _, err := t.kubectl.ConfigUseContext(ctx, clusterName, nil); err != nil {
+	// Setup the default kube context to use the cluster context
+	if _, err := t.kubectl.ConfigUseContext(ctx, contextName, nil); err != nil {
 		return "", fmt.Errorf(
-			"failed setting kube context '%s'. Ensure the specified context exists. %w", clusterName,
+			"failed setting kube context '%s'. Ensure the specified context exists. %w", contextName,
 			err,
 		)
 	}
@@ -903,6 +946,56 @@ func (t *aksTarget) getIngressEndpoints(
 	return artifacts, nil
 }
 
+// Shell runs a command with `kubectl exec` in a pod of the deployment of the service, or in options.Instance, attached
+// to the console.
+func (t *aksTarget) Shell(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	options *ShellOptions,
+) error {
+	if serviceConfig.K8s.GitOps != nil || serviceConfig.K8s.Fleet != nil {
+		return fmt.Errorf(
+			"service '%s' is deployed with GitOps or to a fleet, so azd doesn't connect to the cluster that runs it",
+			serviceConfig.Name)
+	}
+
+	if err := t.validateTargetResource(targetResource); err != nil {
+		return err
+	}
+
+	if err := t.setK8sContext(ctx, serviceConfig, ""); err != nil {
+		return err
+	}
+
+	target := options.Instance
+	if target == "" {
+		deploymentName := serviceConfig.K8s.Deployment.Name
+		if deploymentName == "" {
+			deploymentName = serviceConfig.Name
+		}
+		target = "deployment/" + deploymentName
+	}
+
+	args := []string{"exec", "-i"}
+	if options.Tty {
+		args = append(args, "-t")
+	}
+	args = append(args, "-n", t.getK8sNamespace(serviceConfig))
+	if options.Container != "" {
+		args = append(args, "-c", options.Container)
+	}
+	args = append(args, target, "--")
+
+	if len(options.Command) == 0 {
+		args = append(args, "/bin/sh")
+	} else {
+		args = append(args, options.Command...)
+	}
+
+	return t.kubectl.ExecInteractive(ctx, args...)
+}
+
 func (t *aksTarget) getK8sNamespace(serviceConfig *ServiceConfig) string {
 	namespace := serviceConfig.K8s.Namespace
 	if namespace == "" {
@@ -917,6 +1010,11 @@ func (t *aksTarget) setK8sContext(
 	serviceConfig *ServiceConfig,
 	eventName ext.Event,
 ) error {
+	// In GitOps mode azd does not require access to the cluster
+	if serviceConfig.K8s.GitOps != nil {
+		return nil
+	}
+
 	t.kubectl.SetEnv(t.env.Dotenv())
 	hasCustomKubeConfig := false
 
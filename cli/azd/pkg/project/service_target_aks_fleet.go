@@ -0,0 +1,507 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/sethvargo/go-retry"
+)
+
+var featureFleet alpha.FeatureId = alpha.MustFeatureKey("aks.fleet")
+
+const (
+	// The label azd sets on fleet MemberCluster objects to select clusters by update group
+	fleetUpdateGroupLabel = "azd.azure.com/update-group"
+	// The default amount of time to wait for a fleet rollout to complete
+	defaultFleetRolloutTimeout = 30 * time.Minute
+	// The polling interval used while waiting for a fleet rollout
+	fleetRolloutPollInterval = 10 * time.Second
+)
+
+// The Azure Kubernetes Fleet Manager configuration options.
+// When set, manifests are applied to the fleet hub cluster and rolled out to the member clusters in stages.
+type AksFleetOptions struct {
+	// The name of the fleet resource. Supports environment variable substitution.
+	Name osutil.ExpandableString `yaml:"name"`
+	// The resource group of the fleet resource. Defaults to the resource group of the service.
+	ResourceGroup osutil.ExpandableString `yaml:"resourceGroup,omitempty"`
+	// The name of the ClusterResourcePlacement created on the hub. Defaults to the k8s namespace.
+	Placement string `yaml:"placement,omitempty"`
+	// The rollout stages, run in order. When empty all member clusters are updated in a single stage.
+	Stages []AksFleetStage `yaml:"stages,omitempty"`
+	// The maximum amount of time to wait for the rollout to complete, ex) 45m. Defaults to 30m.
+	Timeout string `yaml:"timeout,omitempty"`
+}
+
+// A single stage of a fleet rollout
+type AksFleetStage struct {
+	// The name of the stage
+	Name string `yaml:"name"`
+	// The fleet update groups whose member clusters are updated in this stage.
+	// When empty the stage selects every member cluster.
+	Groups []string `yaml:"groups,omitempty"`
+	// The amount of time to wait after the stage completes before starting the next stage, ex) 10m
+	WaitAfter string `yaml:"waitAfter,omitempty"`
+}
+
+// Validate checks the fleet options for values that would be rejected by the fleet hub.
+func (o *AksFleetOptions) Validate() error {
+	if o.Name.Empty() {
+		return errors.New("'k8s.fleet.name' is required")
+	}
+
+	if o.Timeout != "" {
+		if _, err := time.ParseDuration(o.Timeout); err != nil {
+			return fmt.Errorf("invalid 'k8s.fleet.timeout' value '%s': %w", o.Timeout, err)
+		}
+	}
+
+	stageNames := map[string]struct{}{}
+	for i, stage := range o.Stages {
+		if stage.Name == "" {
+			return fmt.Errorf("'k8s.fleet.stages[%d].name' is required", i)
+		}
+
+		if _, has := stageNames[stage.Name]; has {
+			return fmt.Errorf("duplicate fleet stage name '%s'", stage.Name)
+		}
+		stageNames[stage.Name] = struct{}{}
+
+		if stage.WaitAfter != "" {
+			if _, err := time.ParseDuration(stage.WaitAfter); err != nil {
+				return fmt.Errorf("invalid 'waitAfter' value '%s' for fleet stage '%s': %w", stage.WaitAfter, stage.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveFleet resolves the fleet name and resource group for the service
+func (t *aksTarget) resolveFleet(
+	fleet *AksFleetOptions,
+	targetResource *environment.TargetResource,
+) (string, string, error) {
+	fleetName, err := fleet.Name.Envsubst(t.env.Getenv)
+	if err != nil {
+		return "", "", fmt.Errorf("failed resolving fleet name: %w", err)
+	}
+
+	resourceGroupName, err := fleet.ResourceGroup.Envsubst(t.env.Getenv)
+	if err != nil {
+		return "", "", fmt.Errorf("failed resolving fleet resource group: %w", err)
+	}
+
+	if resourceGroupName == "" {
+		resourceGroupName = targetResource.ResourceGroupName()
+	}
+
+	return fleetName, resourceGroupName, nil
+}
+
+// ensureFleetContext configures the kube context for the hub cluster of the fleet
+func (t *aksTarget) ensureFleetContext(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	defaultNamespace string,
+) (string, error) {
+	if !t.featureManager.IsEnabled(featureFleet) {
+		return "", fmt.Errorf(
+			"AKS fleet support is not enabled. Run '%s' to enable it.", alpha.GetEnableCommand(featureFleet))
+	}
+
+	fleet := serviceConfig.K8s.Fleet
+	if err := fleet.Validate(); err != nil {
+		return "", err
+	}
+
+	fleetName, resourceGroupName, err := t.resolveFleet(fleet, targetResource)
+	if err != nil {
+		return "", err
+	}
+
+	log.Printf("getting credentials for fleet hub '%s'\n", fleetName)
+	credentials, err := t.fleetsService.GetCredentials(
+		ctx,
+		targetResource.SubscriptionId(),
+		resourceGroupName,
+		fleetName,
+	)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed retrieving fleet hub credentials. Ensure the current principal has been granted rights to the fleet, %w",
+			err,
+		)
+	}
+
+	if len(credentials.Kubeconfigs) == 0 {
+		return "", errors.New(
+			"fleet hub credentials are empty. Ensure the current principal has been granted rights to the fleet")
+	}
+
+	// Fleet hub clusters always use Microsoft Entra ID authentication
+	return t.configureKubeContext(ctx, fleetName, credentials.Kubeconfigs[0].Value, defaultNamespace, true)
+}
+
+// rolloutFleet rolls out the resources applied to the fleet hub to the member clusters and waits for the rollout to
+// complete, reporting the state of each member cluster as progress.
+func (t *aksTarget) rolloutFleet(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	progress *async.Progress[ServiceProgress],
+) error {
+	fleet := serviceConfig.K8s.Fleet
+	fleetName, resourceGroupName, err := t.resolveFleet(fleet, targetResource)
+	if err != nil {
+		return err
+	}
+
+	timeout := defaultFleetRolloutTimeout
+	if fleet.Timeout != "" {
+		// Already validated when the fleet context was configured
+		timeout, _ = time.ParseDuration(fleet.Timeout)
+	}
+
+	namespace := t.getK8sNamespace(serviceConfig)
+	placementName := fleet.Placement
+	if placementName == "" {
+		placementName = namespace
+	}
+
+	if fleetUsesGroups(fleet) {
+		progress.SetProgress(NewServiceProgress("Labeling fleet member clusters"))
+		if err := t.labelFleetMembers(ctx, targetResource.SubscriptionId(), resourceGroupName, fleetName); err != nil {
+			return err
+		}
+	}
+
+	progress.SetProgress(NewServiceProgress("Configuring fleet placement"))
+	manifests := []any{
+		fleetPlacementManifest(placementName, namespace),
+		fleetUpdateStrategyManifest(placementName, fleet.Stages),
+	}
+	for _, manifest := range manifests {
+		if err := t.applyFleetManifest(ctx, manifest); err != nil {
+			return err
+		}
+	}
+
+	snapshotIndex, err := t.latestFleetSnapshotIndex(ctx, placementName)
+	if err != nil {
+		return err
+	}
+
+	updateRunName := fmt.Sprintf("%s-%d", placementName, time.Now().Unix())
+	if err := t.applyFleetManifest(
+		ctx,
+		fleetUpdateRunManifest(updateRunName, placementName, snapshotIndex, placementName),
+	); err != nil {
+		return err
+	}
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Rolling out to fleet '%s'", fleetName)))
+	err = retry.Do(
+		ctx,
+		retry.WithMaxDuration(timeout, retry.NewConstant(fleetRolloutPollInterval)),
+		func(ctx context.Context) error {
+			updateRun, err := kubectl.GetResource[kubectl.ClusterStagedUpdateRun](
+				ctx, t.kubectl, kubectl.ResourceTypeClusterStagedUpdateRun, updateRunName, nil,
+			)
+			if err != nil {
+				return err
+			}
+
+			if message := fleetRolloutProgress(&updateRun); message != "" {
+				progress.SetProgress(NewServiceProgress(message))
+			}
+
+			done, err := fleetRolloutResult(&updateRun)
+			if err != nil {
+				return err
+			}
+
+			if !done {
+				return retry.RetryableError(fmt.Errorf("fleet update run '%s' is in progress", updateRunName))
+			}
+
+			return nil
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("fleet rollout '%s' did not complete: %w", updateRunName, err)
+	}
+
+	return nil
+}
+
+// labelFleetMembers labels the MemberCluster objects on the hub with the update group of each fleet member so that
+// rollout stages can select member clusters by group.
+func (t *aksTarget) labelFleetMembers(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	fleetName string,
+) error {
+	members, err := t.fleetsService.ListMembers(ctx, subscriptionId, resourceGroupName, fleetName)
+	if err != nil {
+		return fmt.Errorf("failed listing fleet members: %w", err)
+	}
+
+	for _, member := range members {
+		if member.Properties.Group == "" {
+			continue
+		}
+
+		_, err := t.kubectl.Exec(
+			ctx,
+			nil,
+			"label",
+			string(kubectl.ResourceTypeMemberCluster),
+			member.Name,
+			fmt.Sprintf("%s=%s", fleetUpdateGroupLabel, member.Properties.Group),
+			"--overwrite",
+		)
+		if err != nil {
+			return fmt.Errorf("failed labeling fleet member '%s': %w", member.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// latestFleetSnapshotIndex returns the index of the latest resource snapshot taken for the placement
+func (t *aksTarget) latestFleetSnapshotIndex(ctx context.Context, placementName string) (string, error) {
+	selector := fmt.Sprintf(
+		"%s=%s,%s=true", kubectl.FleetPlacementTrackingLabel, placementName, kubectl.FleetLatestSnapshotLabel,
+	)
+
+	var snapshotIndex string
+	// The hub controller takes the snapshot asynchronously after the placement is applied
+	err := retry.Do(
+		ctx,
+		retry.WithMaxDuration(2*time.Minute, retry.NewConstant(5*time.Second)),
+		func(ctx context.Context) error {
+			res, err := t.kubectl.Exec(
+				ctx,
+				&kubectl.KubeCliFlags{Output: kubectl.OutputTypeJson},
+				"get", string(kubectl.ResourceTypeClusterResourceSnapshot), "-l", selector,
+			)
+			if err != nil {
+				return fmt.Errorf("failed getting fleet resource snapshots, %w", err)
+			}
+
+			var snapshots kubectl.List[kubectl.ClusterResourceSnapshot]
+			if err := json.Unmarshal([]byte(res.Stdout), &snapshots); err != nil {
+				return fmt.Errorf("failed unmarshalling fleet resource snapshots, %w", err)
+			}
+
+			for _, snapshot := range snapshots.Items {
+				if index := snapshot.Metadata.Labels[kubectl.FleetResourceIndexLabel]; index != "" {
+					snapshotIndex = index
+					return nil
+				}
+			}
+
+			return retry.RetryableError(
+				fmt.Errorf("resource snapshot for placement '%s', %w", placementName, kubectl.ErrResourceNotFound),
+			)
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return snapshotIndex, nil
+}
+
+func (t *aksTarget) applyFleetManifest(ctx context.Context, manifest any) error {
+	manifestJson, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	if _, err := t.kubectl.ApplyWithStdIn(ctx, string(manifestJson), nil); err != nil {
+		return fmt.Errorf("failed applying fleet manifest: %w", err)
+	}
+
+	return nil
+}
+
+func fleetUsesGroups(fleet *AksFleetOptions) bool {
+	return slices.ContainsFunc(fleet.Stages, func(stage AksFleetStage) bool {
+		return len(stage.Groups) > 0
+	})
+}
+
+// fleetPlacementManifest creates a ClusterResourcePlacement that places the service namespace on every member
+// cluster. The External strategy defers the rollout to a staged update run.
+func fleetPlacementManifest(placementName string, namespace string) map[string]any {
+	return map[string]any{
+		"apiVersion": kubectl.FleetPlacementApiVersion,
+		"kind":       "ClusterResourcePlacement",
+		"metadata": map[string]any{
+			"name": placementName,
+		},
+		"spec": map[string]any{
+			"resourceSelectors": []any{
+				map[string]any{
+					"group":   "",
+					"version": "v1",
+					"kind":    "Namespace",
+					"name":    namespace,
+				},
+			},
+			"policy": map[string]any{
+				"placementType": "PickAll",
+			},
+			"strategy": map[string]any{
+				"type": "External",
+			},
+		},
+	}
+}
+
+// fleetUpdateStrategyManifest creates a ClusterStagedUpdateStrategy from the configured stages
+func fleetUpdateStrategyManifest(strategyName string, stages []AksFleetStage) map[string]any {
+	if len(stages) == 0 {
+		stages = []AksFleetStage{{Name: "all"}}
+	}
+
+	stageManifests := []any{}
+	for _, stage := range stages {
+		labelSelector := map[string]any{}
+		if len(stage.Groups) > 0 {
+			labelSelector["matchExpressions"] = []any{
+				map[string]any{
+					"key":      fleetUpdateGroupLabel,
+					"operator": "In",
+					"values":   stage.Groups,
+				},
+			}
+		}
+
+		stageManifest := map[string]any{
+			"name":          stage.Name,
+			"labelSelector": labelSelector,
+		}
+
+		if stage.WaitAfter != "" {
+			stageManifest["afterStageTasks"] = []any{
+				map[string]any{
+					"type":     "TimedWait",
+					"waitTime": stage.WaitAfter,
+				},
+			}
+		}
+
+		stageManifests = append(stageManifests, stageManifest)
+	}
+
+	return map[string]any{
+		"apiVersion": kubectl.FleetPlacementApiVersion,
+		"kind":       "ClusterStagedUpdateStrategy",
+		"metadata": map[string]any{
+			"name": strategyName,
+		},
+		"spec": map[string]any{
+			"stages": stageManifests,
+		},
+	}
+}
+
+// fleetUpdateRunManifest creates a ClusterStagedUpdateRun that rolls out the resource snapshot to the member clusters
+func fleetUpdateRunManifest(
+	updateRunName string,
+	placementName string,
+	snapshotIndex string,
+	strategyName string,
+) map[string]any {
+	return map[string]any{
+		"apiVersion": kubectl.FleetPlacementApiVersion,
+		"kind":       "ClusterStagedUpdateRun",
+		"metadata": map[string]any{
+			"name": updateRunName,
+		},
+		"spec": map[string]any{
+			"placementName":             placementName,
+			"resourceSnapshotIndex":     snapshotIndex,
+			"stagedRolloutStrategyName": strategyName,
+		},
+	}
+}
+
+// fleetRolloutResult reports whether the update run has finished, and returns an error when it failed
+func fleetRolloutResult(updateRun *kubectl.ClusterStagedUpdateRun) (bool, error) {
+	succeeded := kubectl.FindCondition(updateRun.Status.Conditions, kubectl.FleetUpdateRunConditionSucceeded)
+	if succeeded == nil {
+		return false, nil
+	}
+
+	switch succeeded.Status {
+	case kubectl.FleetConditionStatusTrue:
+		return true, nil
+	case kubectl.FleetConditionStatusFalse:
+		return true, fmt.Errorf("fleet update run failed: %s", succeeded.Message)
+	default:
+		return false, nil
+	}
+}
+
+// fleetRolloutProgress describes the active stage of the update run and the state of each of its member clusters
+func fleetRolloutProgress(updateRun *kubectl.ClusterStagedUpdateRun) string {
+	stages := updateRun.Status.StagesStatus
+	for i, stage := range stages {
+		stageSucceeded := kubectl.FindCondition(stage.Conditions, kubectl.FleetUpdateRunConditionSucceeded)
+		if stageSucceeded != nil && stageSucceeded.Status == kubectl.FleetConditionStatusTrue && i < len(stages)-1 {
+			continue
+		}
+
+		clusters := make([]string, 0, len(stage.Clusters))
+		for _, cluster := range stage.Clusters {
+			clusters = append(clusters, fmt.Sprintf("%s: %s", cluster.ClusterName, fleetClusterState(cluster)))
+		}
+
+		message := fmt.Sprintf("Fleet rollout stage '%s' (%d/%d)", stage.StageName, i+1, len(stages))
+		if len(clusters) > 0 {
+			message += " - " + strings.Join(clusters, ", ")
+		}
+
+		return message
+	}
+
+	return ""
+}
+
+func fleetClusterState(cluster kubectl.ClusterUpdatingStatus) string {
+	if succeeded := kubectl.FindCondition(cluster.Conditions, kubectl.FleetClusterUpdateConditionSucceeded); succeeded != nil {
+		switch succeeded.Status {
+		case kubectl.FleetConditionStatusTrue:
+			return "succeeded"
+		case kubectl.FleetConditionStatusFalse:
+			return "failed"
+		}
+	}
+
+	if started := kubectl.FindCondition(cluster.Conditions, kubectl.FleetClusterUpdateConditionStarted); started != nil &&
+		started.Status == kubectl.FleetConditionStatusTrue {
+		return "updating"
+	}
+
+	return "pending"
+}
@@ -0,0 +1,408 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/braydonk/yaml"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Deploy_Fleet(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(t.Context())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	err = setupMocksForFleet(mockContext)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	applied := []map[string]any{}
+	labels := []string{}
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl apply -f -")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		var manifest map[string]any
+		if err := json.NewDecoder(args.StdIn).Decode(&manifest); err == nil {
+			mu.Lock()
+			applied = append(applied, manifest)
+			mu.Unlock()
+		}
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl label memberclusters")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		mu.Lock()
+		labels = append(labels, strings.Join(args.Args[2:4], " "))
+		mu.Unlock()
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.Fleet = &AksFleetOptions{
+		Name: osutil.NewExpandableString("FLEET"),
+		Stages: []AksFleetStage{
+			{Name: "canary", Groups: []string{"canary"}, WaitAfter: "10m"},
+			{Name: "prod", Groups: []string{"east", "west"}},
+		},
+	}
+
+	env := createEnv()
+	azdCtx := createTestAzdContext(t, env)
+
+	userConfig := config.NewConfig(nil)
+	_ = userConfig.Set("alpha.aks.fleet", "on")
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, userConfig, azdCtx)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+	require.NoError(t, err)
+
+	err = setupK8sManifests(t, serviceConfig)
+	require.NoError(t, err)
+
+	progressMessages := []string{}
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	deployResult, err := async.RunWithProgress(
+		func(p ServiceProgress) { progressMessages = append(progressMessages, p.Message) },
+		func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(*mockContext.Context, serviceConfig, NewServiceContext(), scope, progress)
+		},
+	)
+
+	require.NoError(t, err)
+	require.NotNil(t, deployResult)
+
+	// Endpoints are not fetched from the hub cluster
+	_, hasEndpoint := deployResult.Artifacts.FindFirst(WithKind(ArtifactKindEndpoint))
+	require.False(t, hasEndpoint)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.ElementsMatch(t, []string{"member-1 azd.azure.com/update-group=canary"}, labels)
+
+	kinds := []string{}
+	for _, manifest := range applied {
+		kinds = append(kinds, manifest["kind"].(string))
+	}
+	require.Equal(t, []string{
+		"ClusterResourcePlacement",
+		"ClusterStagedUpdateStrategy",
+		"ClusterStagedUpdateRun",
+	}, kinds)
+
+	updateRunSpec := applied[2]["spec"].(map[string]any)
+	require.Equal(t, "Test-App", updateRunSpec["placementName"])
+	require.Equal(t, "3", updateRunSpec["resourceSnapshotIndex"])
+	require.Contains(t, progressMessages, "Rolling out to fleet 'FLEET'")
+}
+
+func Test_Deploy_Fleet_Not_Enabled(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(t.Context())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.Fleet = &AksFleetOptions{
+		Name: osutil.NewExpandableString("FLEET"),
+	}
+
+	env := createEnv()
+	azdCtx := createTestAzdContext(t, env)
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil, azdCtx)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+	require.ErrorContains(t, err, "AKS fleet support is not enabled")
+}
+
+func Test_AksFleetOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options AksFleetOptions
+		wantErr string
+	}{
+		{
+			name:    "Valid",
+			options: AksFleetOptions{Name: osutil.NewExpandableString("fleet"), Timeout: "1h"},
+		},
+		{
+			name:    "MissingName",
+			options: AksFleetOptions{},
+			wantErr: "'k8s.fleet.name' is required",
+		},
+		{
+			name:    "InvalidTimeout",
+			options: AksFleetOptions{Name: osutil.NewExpandableString("fleet"), Timeout: "soon"},
+			wantErr: "invalid 'k8s.fleet.timeout'",
+		},
+		{
+			name: "MissingStageName",
+			options: AksFleetOptions{
+				Name:   osutil.NewExpandableString("fleet"),
+				Stages: []AksFleetStage{{Groups: []string{"canary"}}},
+			},
+			wantErr: "'k8s.fleet.stages[0].name' is required",
+		},
+		{
+			name: "DuplicateStage",
+			options: AksFleetOptions{
+				Name:   osutil.NewExpandableString("fleet"),
+				Stages: []AksFleetStage{{Name: "prod"}, {Name: "prod"}},
+			},
+			wantErr: "duplicate fleet stage name 'prod'",
+		},
+		{
+			name: "InvalidWaitAfter",
+			options: AksFleetOptions{
+				Name:   osutil.NewExpandableString("fleet"),
+				Stages: []AksFleetStage{{Name: "canary", WaitAfter: "10"}},
+			},
+			wantErr: "invalid 'waitAfter' value '10' for fleet stage 'canary'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_FleetUpdateStrategyManifest(t *testing.T) {
+	t.Run("DefaultStage", func(t *testing.T) {
+		manifest := fleetUpdateStrategyManifest("api", nil)
+		stages := manifest["spec"].(map[string]any)["stages"].([]any)
+		require.Len(t, stages, 1)
+		require.Equal(t, map[string]any{
+			"name":          "all",
+			"labelSelector": map[string]any{},
+		}, stages[0])
+	})
+
+	t.Run("GroupsAndWait", func(t *testing.T) {
+		manifest := fleetUpdateStrategyManifest("api", []AksFleetStage{
+			{Name: "canary", Groups: []string{"canary"}, WaitAfter: "15m"},
+		})
+		stages := manifest["spec"].(map[string]any)["stages"].([]any)
+		require.Len(t, stages, 1)
+		require.Equal(t, map[string]any{
+			"name": "canary",
+			"labelSelector": map[string]any{
+				"matchExpressions": []any{
+					map[string]any{
+						"key":      fleetUpdateGroupLabel,
+						"operator": "In",
+						"values":   []string{"canary"},
+					},
+				},
+			},
+			"afterStageTasks": []any{
+				map[string]any{"type": "TimedWait", "waitTime": "15m"},
+			},
+		}, stages[0])
+	})
+}
+
+func Test_FleetRolloutStatus(t *testing.T) {
+	succeeded := kubectl.Condition{
+		Type: kubectl.FleetUpdateRunConditionSucceeded, Status: kubectl.FleetConditionStatusTrue,
+	}
+	started := kubectl.Condition{
+		Type: kubectl.FleetClusterUpdateConditionStarted, Status: kubectl.FleetConditionStatusTrue,
+	}
+
+	tests := []struct {
+		name         string
+		status       kubectl.ClusterStagedUpdateRunStatus
+		wantDone     bool
+		wantErr      string
+		wantProgress string
+	}{
+		{
+			name: "NotStarted",
+		},
+		{
+			name: "InProgress",
+			status: kubectl.ClusterStagedUpdateRunStatus{
+				StagesStatus: []kubectl.StageUpdatingStatus{
+					{
+						StageName:  "canary",
+						Conditions: []kubectl.Condition{succeeded},
+						Clusters: []kubectl.ClusterUpdatingStatus{
+							{ClusterName: "member-1", Conditions: []kubectl.Condition{started, succeeded}},
+						},
+					},
+					{
+						StageName: "prod",
+						Clusters: []kubectl.ClusterUpdatingStatus{
+							{ClusterName: "member-2", Conditions: []kubectl.Condition{started}},
+							{ClusterName: "member-3"},
+						},
+					},
+				},
+			},
+			wantProgress: "Fleet rollout stage 'prod' (2/2) - member-2: updating, member-3: pending",
+		},
+		{
+			name: "Succeeded",
+			status: kubectl.ClusterStagedUpdateRunStatus{
+				Conditions: []kubectl.Condition{succeeded},
+				StagesStatus: []kubectl.StageUpdatingStatus{
+					{
+						StageName:  "all",
+						Conditions: []kubectl.Condition{succeeded},
+						Clusters: []kubectl.ClusterUpdatingStatus{
+							{ClusterName: "member-1", Conditions: []kubectl.Condition{started, succeeded}},
+						},
+					},
+				},
+			},
+			wantDone:     true,
+			wantProgress: "Fleet rollout stage 'all' (1/1) - member-1: succeeded",
+		},
+		{
+			name: "Failed",
+			status: kubectl.ClusterStagedUpdateRunStatus{
+				Conditions: []kubectl.Condition{
+					{
+						Type:    kubectl.FleetUpdateRunConditionSucceeded,
+						Status:  kubectl.FleetConditionStatusFalse,
+						Message: "cluster member-1 failed to apply",
+					},
+				},
+			},
+			wantDone: true,
+			wantErr:  "fleet update run failed: cluster member-1 failed to apply",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updateRun := &kubectl.ClusterStagedUpdateRun{Status: tt.status}
+
+			done, err := fleetRolloutResult(updateRun)
+			require.Equal(t, tt.wantDone, done)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, tt.wantProgress, fleetRolloutProgress(updateRun))
+		})
+	}
+}
+
+func setupMocksForFleet(mockContext *mocks.MockContext) error {
+	kubeConfig := createTestCluster("FLEET", "user1")
+	kubeConfigBytes, err := yaml.Marshal(kubeConfig)
+	if err != nil {
+		return err
+	}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost &&
+			strings.Contains(request.URL.Path, "Microsoft.ContainerService/fleets/FLEET/listCredentials")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armcontainerservice.CredentialResults{
+			Kubeconfigs: []*armcontainerservice.CredentialResult{
+				{
+					Name:  new("hub"),
+					Value: kubeConfigBytes,
+				},
+			},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			strings.Contains(request.URL.Path, "Microsoft.ContainerService/fleets/FLEET/members")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"value": []*azapi.FleetMember{
+				{Name: "member-1", Properties: azapi.FleetMemberProperties{Group: "canary"}},
+				{Name: "member-2"},
+			},
+		})
+	})
+
+	mockContext.CommandRunner.MockToolInPath("kubelogin", nil)
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubelogin convert-kubeconfig")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl get clusterresourcesnapshots")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		snapshot := kubectl.ClusterResourceSnapshot{
+			Resource: kubectl.Resource{
+				Metadata: kubectl.ResourceMetadata{
+					Name: "Test-App-3-snapshot",
+					Labels: map[string]string{
+						kubectl.FleetPlacementTrackingLabel: "Test-App",
+						kubectl.FleetResourceIndexLabel:     "3",
+					},
+				},
+			},
+		}
+		result, err := json.Marshal(createK8sResourceList(snapshot))
+		if err != nil {
+			return exec.NewRunResult(1, "", ""), err
+		}
+
+		return exec.NewRunResult(0, string(result), ""), nil
+	})
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl get clusterstagedupdateruns")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		updateRun := kubectl.ClusterStagedUpdateRun{
+			Status: kubectl.ClusterStagedUpdateRunStatus{
+				Conditions: []kubectl.Condition{
+					{Type: kubectl.FleetUpdateRunConditionSucceeded, Status: kubectl.FleetConditionStatusTrue},
+				},
+			},
+		}
+		result, err := json.Marshal(updateRun)
+		if err != nil {
+			return exec.NewRunResult(1, "", ""), err
+		}
+
+		return exec.NewRunResult(0, string(result), ""), nil
+	})
+
+	return nil
+}
@@ -0,0 +1,488 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/helm"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/braydonk/yaml"
+)
+
+var featureGitOps alpha.FeatureId = alpha.MustFeatureKey("aks.gitops")
+
+// GitOpsLayout controls how Helm releases are described in the GitOps repository
+type GitOpsLayout string
+
+const (
+	// Helm releases are written as Flux HelmRepository and HelmRelease objects
+	GitOpsLayoutFlux GitOpsLayout = "flux"
+	// Helm releases are written as Argo CD Application objects
+	GitOpsLayoutArgo GitOpsLayout = "argo"
+)
+
+const (
+	// The namespace Argo CD Application objects are created in
+	argoApplicationNamespace = "argocd"
+	// The environment property that records the last GitOps commit of a service
+	gitOpsCommitProperty = "GITOPS_COMMIT"
+)
+
+// The GitOps configuration options.
+// When set, azd renders the k8s manifests and Helm releases of the service and commits them to a git repository
+// instead of applying them to the cluster. A GitOps agent such as Flux or Argo CD running in the cluster applies them.
+type AksGitOpsOptions struct {
+	// The URL of the GitOps repository. Supports environment variable substitution.
+	Repository osutil.ExpandableString `yaml:"repository"`
+	// The branch to commit to. Defaults to the default branch of the repository.
+	Branch string `yaml:"branch,omitempty"`
+	// The directory within the repository the rendered files are written to. Supports environment variable
+	// substitution. Defaults to '<environment name>/<service name>'.
+	Path osutil.ExpandableString `yaml:"path,omitempty"`
+	// The layout of the rendered files, 'flux' or 'argo'. Defaults to 'flux'.
+	Layout GitOpsLayout `yaml:"layout,omitempty"`
+}
+
+// validateGitOps checks the GitOps options of the AKS configuration
+func (o *AksOptions) validateGitOps() error {
+	if o.GitOps == nil {
+		return nil
+	}
+
+	if o.GitOps.Repository.Empty() {
+		return errors.New("'k8s.gitops.repository' is required")
+	}
+
+	if o.GitOps.Layout != "" && !slices.Contains([]GitOpsLayout{GitOpsLayoutFlux, GitOpsLayoutArgo}, o.GitOps.Layout) {
+		return fmt.Errorf("invalid 'k8s.gitops.layout' value '%s', expected 'flux' or 'argo'", o.GitOps.Layout)
+	}
+
+	if o.Fleet != nil {
+		return errors.New("'k8s.gitops' and 'k8s.fleet' cannot be used together")
+	}
+
+	return nil
+}
+
+// deployGitOps renders the service manifests and commits them to the GitOps repository, together with the
+// workload identity service account when serviceAccountManifest is set.
+func (t *aksTarget) deployGitOps(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	serviceAccountManifest string,
+	progress *async.Progress[ServiceProgress],
+) (*ServiceDeployResult, error) {
+	if !t.featureManager.IsEnabled(featureGitOps) {
+		return nil, fmt.Errorf(
+			"AKS GitOps support is not enabled. Run '%s' to enable it.", alpha.GetEnableCommand(featureGitOps))
+	}
+
+	if err := serviceConfig.K8s.validateGitOps(); err != nil {
+		return nil, err
+	}
+
+	gitOps := serviceConfig.K8s.GitOps
+	repository, err := gitOps.Repository.Envsubst(t.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("failed resolving gitops repository: %w", err)
+	}
+
+	repoPath, err := gitOps.Path.Envsubst(t.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("failed resolving gitops path: %w", err)
+	}
+
+	if repoPath == "" {
+		repoPath = path.Join(t.env.Name(), serviceConfig.Name)
+	}
+
+	repoPath = filepath.FromSlash(repoPath)
+	if !filepath.IsLocal(repoPath) {
+		return nil, fmt.Errorf("'k8s.gitops.path' must be a relative path within the repository, got '%s'", repoPath)
+	}
+
+	progress.SetProgress(NewServiceProgress("Rendering k8s manifests"))
+	files, err := t.renderGitOpsFiles(ctx, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		return nil, errors.New("no deployment manifests found")
+	}
+
+	if serviceAccountManifest != "" {
+		files["service-account.yaml"] = serviceAccountManifest
+	}
+
+	cloneDir, err := os.MkdirTemp("", "azd-gitops-*")
+	if err != nil {
+		return nil, fmt.Errorf("creating temp folder: %w", err)
+	}
+	defer os.RemoveAll(cloneDir)
+
+	progress.SetProgress(NewServiceProgress("Cloning GitOps repository"))
+	if err := t.gitCli.ShallowClone(ctx, repository, gitOps.Branch, cloneDir); err != nil {
+		return nil, err
+	}
+
+	branch := gitOps.Branch
+	if branch == "" {
+		branch, err = t.gitCli.GetCurrentBranch(ctx, cloneDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Replace the previous contents of the service folder so that removed manifests are removed from the repository
+	targetDir := filepath.Join(cloneDir, repoPath)
+	if err := os.RemoveAll(targetDir); err != nil {
+		return nil, fmt.Errorf("removing previous gitops files: %w", err)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		filePath := filepath.Join(targetDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), osutil.PermissionDirectory); err != nil {
+			return nil, fmt.Errorf("creating gitops folder: %w", err)
+		}
+
+		if err := os.WriteFile(filePath, []byte(files[name]), osutil.PermissionFile); err != nil {
+			return nil, fmt.Errorf("writing gitops file '%s': %w", name, err)
+		}
+	}
+
+	if err := t.gitCli.AddFile(ctx, cloneDir, "--all"); err != nil {
+		return nil, err
+	}
+
+	changed, err := t.gitCli.IsDirty(ctx, cloneDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if changed {
+		progress.SetProgress(NewServiceProgress("Pushing manifests to GitOps repository"))
+		message := fmt.Sprintf("Deploy %s to %s", serviceConfig.Name, t.env.Name())
+		if err := t.gitCli.Commit(ctx, cloneDir, message); err != nil {
+			return nil, err
+		}
+
+		if err := t.gitCli.PushUpstream(ctx, cloneDir, "origin", branch); err != nil {
+			return nil, err
+		}
+	} else {
+		log.Printf("gitops: rendered manifests for service '%s' are unchanged, skipping commit", serviceConfig.Name)
+	}
+
+	commit, err := t.gitCli.GetHeadCommit(ctx, cloneDir)
+	if err != nil {
+		return nil, err
+	}
+
+	t.env.SetServiceProperty(serviceConfig.Name, gitOpsCommitProperty, commit)
+	if err := t.envManager.Save(ctx, t.env); err != nil {
+		return nil, fmt.Errorf("failed updating environment with gitops commit, %w", err)
+	}
+
+	artifacts := ArtifactCollection{}
+	if err := artifacts.Add(&Artifact{
+		Kind:         ArtifactKindDeployment,
+		Location:     commit,
+		LocationKind: LocationKindRemote,
+		Metadata: map[string]string{
+			"deploymentType": "gitops",
+			"serviceName":    serviceConfig.Name,
+			"repository":     repository,
+			"branch":         branch,
+			"path":           filepath.ToSlash(repoPath),
+			"commit":         commit,
+			"changed":        fmt.Sprint(changed),
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("failed to add gitops deployment artifact: %w", err)
+	}
+
+	return t.deployResult(targetResource, artifacts)
+}
+
+// renderGitOpsFiles renders the namespace, manifests, kustomize output and Helm releases of the service.
+// The returned map is keyed by the slash separated path of the file relative to the service folder.
+func (t *aksTarget) renderGitOpsFiles(ctx context.Context, serviceConfig *ServiceConfig) (map[string]string, error) {
+	files := map[string]string{}
+	namespace := t.getK8sNamespace(serviceConfig)
+
+	// Helm Support
+	if serviceConfig.K8s.Helm != nil {
+		if !t.featureManager.IsEnabled(featureHelm) {
+			return nil, fmt.Errorf(
+				"Helm support is not enabled. Run '%s' to enable it.", alpha.GetEnableCommand(featureHelm))
+		}
+
+		helmFiles, err := t.renderGitOpsHelm(serviceConfig, namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		for name, content := range helmFiles {
+			files[path.Join("helm", name)] = content
+		}
+	}
+
+	// Kustomize Support
+	if serviceConfig.K8s.Kustomize != nil {
+		if !t.featureManager.IsEnabled(featureKustomize) {
+			return nil, fmt.Errorf(
+				"Kustomize support is not enabled. Run '%s' to enable it.", alpha.GetEnableCommand(featureKustomize))
+		}
+
+		kustomizeDir, cleanup, err := t.prepareKustomize(ctx, serviceConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		rendered, err := t.kubectl.Kustomize(ctx, kustomizeDir)
+		cleanup()
+		if err != nil {
+			return nil, err
+		}
+
+		files["kustomize.yaml"] = rendered
+	}
+
+	// Vanilla k8s manifests with minimal templating support
+	deploymentPath := serviceConfig.K8s.DeploymentPath
+	if deploymentPath == "" {
+		deploymentPath = defaultDeploymentPath
+	}
+
+	deploymentPath = filepath.Join(serviceConfig.Path(), deploymentPath)
+	if _, err := os.Stat(deploymentPath); err == nil {
+		manifests, err := t.kubectl.Render(deploymentPath)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, manifest := range manifests {
+			files[path.Join("manifests", manifest.Path)] = manifest.Content
+		}
+	}
+
+	if len(files) == 0 {
+		return files, nil
+	}
+
+	namespaceManifest, err := yaml.Marshal(map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata": map[string]any{
+			"name": namespace,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	files["namespace.yaml"] = string(namespaceManifest)
+
+	return files, nil
+}
+
+// renderGitOpsHelm describes the Helm releases of the service using the objects of the configured GitOps layout
+func (t *aksTarget) renderGitOpsHelm(serviceConfig *ServiceConfig, namespace string) (map[string]string, error) {
+	files := map[string]string{}
+	layout := serviceConfig.K8s.GitOps.Layout
+	if layout == "" {
+		layout = GitOpsLayoutFlux
+	}
+
+	repositories := map[string]*helm.Repository{}
+	for _, repo := range serviceConfig.K8s.Helm.Repositories {
+		repositories[repo.Name] = repo
+
+		if layout == GitOpsLayoutFlux {
+			manifest := map[string]any{
+				"apiVersion": "source.toolkit.fluxcd.io/v1",
+				"kind":       "HelmRepository",
+				"metadata": map[string]any{
+					"name":      repo.Name,
+					"namespace": namespace,
+				},
+				"spec": fluxHelmRepositorySpec(repo.Url),
+			}
+
+			content, err := yaml.Marshal(manifest)
+			if err != nil {
+				return nil, err
+			}
+
+			files[repo.Name+"-repository.yaml"] = string(content)
+		}
+	}
+
+	for _, configRelease := range serviceConfig.K8s.Helm.Releases {
+		release, err := t.resolveHelmRelease(serviceConfig, configRelease, namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		repoName, chartName, found := strings.Cut(release.Chart, "/")
+		repo, has := repositories[repoName]
+		if !found || !has {
+			return nil, fmt.Errorf(
+				"helm release '%s': chart '%s' must reference a repository from 'k8s.helm.repositories' in GitOps mode",
+				release.Name,
+				release.Chart,
+			)
+		}
+
+		releaseNamespace := release.Namespace
+
+		values, err := t.readHelmValues(serviceConfig, release)
+		if err != nil {
+			return nil, err
+		}
+
+		var manifest map[string]any
+		var fileName string
+		switch layout {
+		case GitOpsLayoutArgo:
+			fileName = release.Name + "-application.yaml"
+			manifest = argoApplicationManifest(release, repo, chartName, releaseNamespace, values)
+		default:
+			fileName = release.Name + "-release.yaml"
+			manifest = fluxHelmReleaseManifest(release, repo, chartName, releaseNamespace, namespace, values)
+		}
+
+		content, err := yaml.Marshal(manifest)
+		if err != nil {
+			return nil, err
+		}
+
+		files[fileName] = string(content)
+	}
+
+	return files, nil
+}
+
+func fluxHelmRepositorySpec(url string) map[string]any {
+	spec := map[string]any{
+		"interval": "10m",
+		"url":      url,
+	}
+
+	if strings.HasPrefix(url, "oci://") {
+		spec["type"] = "oci"
+	}
+
+	return spec
+}
+
+func fluxHelmReleaseManifest(
+	release *helm.Release,
+	repo *helm.Repository,
+	chartName string,
+	releaseNamespace string,
+	sourceNamespace string,
+	values map[string]any,
+) map[string]any {
+	chartSpec := map[string]any{
+		"chart": chartName,
+		"sourceRef": map[string]any{
+			"kind":      "HelmRepository",
+			"name":      repo.Name,
+			"namespace": sourceNamespace,
+		},
+	}
+
+	if release.Version != "" {
+		chartSpec["version"] = release.Version
+	}
+
+	spec := map[string]any{
+		"interval":        "10m",
+		"releaseName":     release.Name,
+		"targetNamespace": releaseNamespace,
+		"install": map[string]any{
+			"createNamespace": true,
+		},
+		"chart": map[string]any{
+			"spec": chartSpec,
+		},
+	}
+
+	if len(values) > 0 {
+		spec["values"] = values
+	}
+
+	return map[string]any{
+		"apiVersion": "helm.toolkit.fluxcd.io/v2",
+		"kind":       "HelmRelease",
+		"metadata": map[string]any{
+			"name":      release.Name,
+			"namespace": sourceNamespace,
+		},
+		"spec": spec,
+	}
+}
+
+func argoApplicationManifest(
+	release *helm.Release,
+	repo *helm.Repository,
+	chartName string,
+	releaseNamespace string,
+	values map[string]any,
+) map[string]any {
+	helmSource := map[string]any{
+		"releaseName": release.Name,
+	}
+
+	if len(values) > 0 {
+		helmSource["valuesObject"] = values
+	}
+
+	targetRevision := release.Version
+	if targetRevision == "" {
+		targetRevision = "*"
+	}
+
+	return map[string]any{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Application",
+		"metadata": map[string]any{
+			"name":      release.Name,
+			"namespace": argoApplicationNamespace,
+		},
+		"spec": map[string]any{
+			"project": "default",
+			"source": map[string]any{
+				"repoURL":        strings.TrimPrefix(repo.Url, "oci://"),
+				"chart":          chartName,
+				"targetRevision": targetRevision,
+				"helm":           helmSource,
+			},
+			"destination": map[string]any{
+				"server":    "https://kubernetes.default.svc",
+				"namespace": releaseNamespace,
+			},
+			"syncPolicy": map[string]any{
+				"automated":   map[string]any{},
+				"syncOptions": []string{"CreateNamespace=true"},
+			},
+		},
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/helm"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/braydonk/yaml"
+	"github.com/stretchr/testify/require"
+)
+
+const testGitOpsCommit = "3f2c1a9e5b7d4c6a8e0f1b2d3c4e5f6a7b8c9d0e"
+
+func Test_Deploy_GitOps(t *testing.T) {
+	tests := []struct {
+		name       string
+		dirty      bool
+		wantPushed bool
+	}{
+		{name: "Changed", dirty: true, wantPushed: true},
+		{name: "Unchanged", dirty: false, wantPushed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			ostest.Chdir(t, tempDir)
+
+			mockContext := mocks.NewMockContext(t.Context())
+			gitCalls, repoFiles := setupMocksForGitOps(mockContext, tt.dirty)
+
+			serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+			serviceConfig.K8s.GitOps = &AksGitOpsOptions{
+				Repository: osutil.NewExpandableString("https://github.com/contoso/${GITOPS_REPO}.git"),
+				Branch:     "main",
+			}
+			serviceConfig.K8s.Helm = &helm.Config{
+				Repositories: []*helm.Repository{
+					{Name: "bitnami", Url: "https://charts.bitnami.com/bitnami"},
+				},
+				Releases: []*helm.Release{
+					{Name: "cache", Chart: "bitnami/redis", Version: "18.0.0", Values: "values.yaml"},
+				},
+			}
+
+			err := setupK8sManifests(t, serviceConfig)
+			require.NoError(t, err)
+			err = os.WriteFile(
+				filepath.Join(tempDir, "values.yaml"), []byte("architecture: standalone\n"), osutil.PermissionFile)
+			require.NoError(t, err)
+
+			env := createEnv()
+			env.DotenvSet("GITOPS_REPO", "clusters")
+			azdCtx := createTestAzdContext(t, env)
+
+			userConfig := config.NewConfig(nil)
+			_ = userConfig.Set("alpha.aks.gitops", "on")
+			_ = userConfig.Set("alpha.aks.helm", "on")
+
+			serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, userConfig, azdCtx)
+
+			// The cluster context is not configured in GitOps mode, any kubectl call would fail the test
+			err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+			require.NoError(t, err)
+
+			scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+			deployResult, err := logProgress(
+				t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+					return serviceTarget.Deploy(*mockContext.Context, serviceConfig, NewServiceContext(), scope, progress)
+				},
+			)
+			require.NoError(t, err)
+
+			require.True(t, strings.HasPrefix(
+				(*gitCalls)[0], "clone --depth 1 https://github.com/contoso/clusters.git --branch main "))
+			require.Equal(t, tt.wantPushed, slices.ContainsFunc(*gitCalls, func(call string) bool {
+				return strings.HasPrefix(call, "push")
+			}))
+
+			require.ElementsMatch(t, []string{
+				"test/api/namespace.yaml",
+				"test/api/manifests/deployment.yaml",
+				"test/api/manifests/service.yaml",
+				"test/api/manifests/ingress.yaml",
+				"test/api/helm/bitnami-repository.yaml",
+				"test/api/helm/cache-release.yaml",
+			}, slices.Collect(maps.Keys(*repoFiles)))
+
+			var release map[string]any
+			require.NoError(t, yaml.Unmarshal([]byte((*repoFiles)["test/api/helm/cache-release.yaml"]), &release))
+			require.Equal(t, "HelmRelease", release["kind"])
+			spec := release["spec"].(map[string]any)
+			require.Equal(t, map[string]any{"architecture": "standalone"}, spec["values"])
+
+			require.Equal(t, testGitOpsCommit, env.Getenv("SERVICE_API_GITOPS_COMMIT"))
+
+			artifact, found := deployResult.Artifacts.FindFirst(WithKind(ArtifactKindDeployment))
+			require.True(t, found)
+			require.Equal(t, testGitOpsCommit, artifact.Location)
+			require.Equal(t, "gitops", artifact.Metadata["deploymentType"])
+			require.Equal(t, "test/api", artifact.Metadata["path"])
+		})
+	}
+}
+
+func Test_Deploy_GitOps_Not_Enabled(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(t.Context())
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.GitOps = &AksGitOpsOptions{
+		Repository: osutil.NewExpandableString("https://github.com/contoso/clusters.git"),
+	}
+
+	env := createEnv()
+	azdCtx := createTestAzdContext(t, env)
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil, azdCtx)
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	_, err := logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(*mockContext.Context, serviceConfig, NewServiceContext(), scope, progress)
+		},
+	)
+	require.ErrorContains(t, err, "AKS GitOps support is not enabled")
+}
+
+func Test_AksOptions_ValidateGitOps(t *testing.T) {
+	repository := osutil.NewExpandableString("https://github.com/contoso/clusters.git")
+
+	tests := []struct {
+		name    string
+		options AksOptions
+		wantErr string
+	}{
+		{
+			name: "NotConfigured",
+		},
+		{
+			name:    "Valid",
+			options: AksOptions{GitOps: &AksGitOpsOptions{Repository: repository, Layout: GitOpsLayoutArgo}},
+		},
+		{
+			name:    "MissingRepository",
+			options: AksOptions{GitOps: &AksGitOpsOptions{}},
+			wantErr: "'k8s.gitops.repository' is required",
+		},
+		{
+			name:    "InvalidLayout",
+			options: AksOptions{GitOps: &AksGitOpsOptions{Repository: repository, Layout: "jenkins"}},
+			wantErr: "invalid 'k8s.gitops.layout' value 'jenkins'",
+		},
+		{
+			name: "WithFleet",
+			options: AksOptions{
+				GitOps: &AksGitOpsOptions{Repository: repository},
+				Fleet:  &AksFleetOptions{Name: osutil.NewExpandableString("fleet")},
+			},
+			wantErr: "'k8s.gitops' and 'k8s.fleet' cannot be used together",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.validateGitOps()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_RenderGitOpsHelm(t *testing.T) {
+	helmConfig := &helm.Config{
+		Repositories: []*helm.Repository{
+			{Name: "bitnami", Url: "oci://registry-1.docker.io/bitnamicharts"},
+		},
+		Releases: []*helm.Release{
+			{Name: "cache", Chart: "bitnami/redis", Namespace: "data"},
+		},
+	}
+
+	t.Run("Argo", func(t *testing.T) {
+		serviceConfig := createTestServiceConfig(t.TempDir(), AksTarget, ServiceLanguageTypeScript)
+		serviceConfig.K8s.Helm = helmConfig
+		serviceConfig.K8s.GitOps = &AksGitOpsOptions{Layout: GitOpsLayoutArgo}
+
+		target := &aksTarget{env: createEnv()}
+		files, err := target.renderGitOpsHelm(serviceConfig, "api")
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+
+		var application map[string]any
+		require.NoError(t, yaml.Unmarshal([]byte(files["cache-application.yaml"]), &application))
+		require.Equal(t, "Application", application["kind"])
+
+		spec := application["spec"].(map[string]any)
+		require.Equal(t, map[string]any{
+			"repoURL":        "registry-1.docker.io/bitnamicharts",
+			"chart":          "redis",
+			"targetRevision": "*",
+			"helm":           map[string]any{"releaseName": "cache"},
+		}, spec["source"])
+		require.Equal(t, "data", spec["destination"].(map[string]any)["namespace"])
+	})
+
+	t.Run("Flux", func(t *testing.T) {
+		serviceConfig := createTestServiceConfig(t.TempDir(), AksTarget, ServiceLanguageTypeScript)
+		serviceConfig.K8s.Helm = helmConfig
+		serviceConfig.K8s.GitOps = &AksGitOpsOptions{}
+
+		target := &aksTarget{env: createEnv()}
+		files, err := target.renderGitOpsHelm(serviceConfig, "api")
+		require.NoError(t, err)
+		require.Len(t, files, 2)
+
+		var repository map[string]any
+		require.NoError(t, yaml.Unmarshal([]byte(files["bitnami-repository.yaml"]), &repository))
+		require.Equal(t, "oci", repository["spec"].(map[string]any)["type"])
+	})
+
+	t.Run("UnknownRepository", func(t *testing.T) {
+		serviceConfig := createTestServiceConfig(t.TempDir(), AksTarget, ServiceLanguageTypeScript)
+		serviceConfig.K8s.Helm = &helm.Config{
+			Releases: []*helm.Release{{Name: "local", Chart: "./charts/local"}},
+		}
+		serviceConfig.K8s.GitOps = &AksGitOpsOptions{}
+
+		target := &aksTarget{env: createEnv()}
+		_, err := target.renderGitOpsHelm(serviceConfig, "api")
+		require.ErrorContains(t, err, "must reference a repository from 'k8s.helm.repositories'")
+	})
+}
+
+// setupMocksForGitOps mocks the git commands used by a GitOps deployment. It returns the git commands that were run
+// and the files present in the cloned repository when they were staged.
+func setupMocksForGitOps(mockContext *mocks.MockContext, dirty bool) (*[]string, *map[string]string) {
+	gitCalls := []string{}
+	repoFiles := map[string]string{}
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "git"
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		gitArgs := args.Args
+		if len(gitArgs) > 2 && gitArgs[0] == "-C" {
+			gitArgs = gitArgs[2:]
+		}
+		gitCalls = append(gitCalls, strings.Join(gitArgs, " "))
+
+		switch gitArgs[0] {
+		case "add":
+			repoDir := args.Args[1]
+			_ = filepath.WalkDir(repoDir, func(path string, entry fs.DirEntry, err error) error {
+				if err != nil || entry.IsDir() {
+					return err
+				}
+
+				content, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+
+				relativePath, _ := filepath.Rel(repoDir, path)
+				repoFiles[filepath.ToSlash(relativePath)] = string(content)
+				return nil
+			})
+		case "status":
+			if dirty {
+				return exec.NewRunResult(0, " M test/api/namespace.yaml\n", ""), nil
+			}
+		case "rev-parse":
+			return exec.NewRunResult(0, testGitOpsCommit+"\n", ""), nil
+		}
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	return &gitCalls, &repoFiles
+}
@@ -0,0 +1,294 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/helm"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/braydonk/yaml"
+	"github.com/sethvargo/go-retry"
+)
+
+// deployHelmCharts deploys helm charts to the k8s cluster
+func (t *aksTarget) deployHelmCharts(
+	ctx context.Context, serviceConfig *ServiceConfig,
+	task *async.Progress[ServiceProgress],
+) (bool, error) {
+	if serviceConfig.K8s.Helm == nil {
+		return false, nil
+	}
+
+	if !t.featureManager.IsEnabled(featureHelm) {
+		return false, fmt.Errorf("Helm support is not enabled. Run '%s' to enable it.", alpha.GetEnableCommand(featureHelm))
+	}
+
+	for _, repo := range serviceConfig.K8s.Helm.Repositories {
+		task.SetProgress(NewServiceProgress(fmt.Sprintf("Configuring helm repo: %s", repo.Name)))
+		if err := t.helmCli.AddRepo(ctx, repo); err != nil {
+			return false, err
+		}
+
+		if err := t.helmCli.UpdateRepo(ctx, repo.Name); err != nil {
+			return false, err
+		}
+	}
+
+	for _, release := range serviceConfig.K8s.Helm.Releases {
+		resolved, err := t.resolveHelmRelease(serviceConfig, release, t.getK8sNamespace(serviceConfig))
+		if err != nil {
+			return false, err
+		}
+
+		if err := t.deployHelmRelease(ctx, serviceConfig, resolved, task); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// resolveHelmRelease returns a copy of the release with its name, chart, version and namespace expanded with the
+// environment. A release without a name is named after the service and the environment, so that each environment
+// installs its own release. A local chart is resolved relative to the project directory.
+func (t *aksTarget) resolveHelmRelease(
+	serviceConfig *ServiceConfig,
+	release *helm.Release,
+	defaultNamespace string,
+) (*helm.Release, error) {
+	resolved := *release
+
+	fields := []*string{&resolved.Name, &resolved.Chart, &resolved.Version, &resolved.Namespace}
+	for _, field := range fields {
+		value, err := osutil.NewExpandableString(*field).Envsubst(t.env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to envsubst helm release '%s': %w", release.Name, err)
+		}
+
+		*field = value
+	}
+
+	if resolved.Name == "" {
+		resolved.Name = fmt.Sprintf("%s-%s", serviceConfig.Name, t.env.Name())
+	}
+
+	if resolved.Namespace == "" {
+		resolved.Namespace = defaultNamespace
+	}
+
+	// Charts are either a local chart directory or archive, a chart from a repository ('repo/chart') or an OCI
+	// reference ('oci://registry/chart'). Only local charts exist on disk.
+	if !strings.Contains(resolved.Chart, "://") {
+		chartPath := resolved.Chart
+		if !filepath.IsAbs(chartPath) {
+			chartPath = filepath.Join(serviceConfig.Project.Path, chartPath)
+		}
+
+		if _, err := os.Stat(chartPath); err == nil {
+			resolved.Chart = chartPath
+		}
+	}
+
+	return &resolved, nil
+}
+
+// helmValuesFiles returns the values files of the release, in the order they are applied
+func helmValuesFiles(release *helm.Release) []string {
+	var valuesFiles []string
+	if release.Values != "" {
+		valuesFiles = append(valuesFiles, release.Values)
+	}
+
+	return append(valuesFiles, release.ValuesFiles...)
+}
+
+// readHelmValuesFile reads a values file of the release, resolved relative to the project directory, and expands it
+// with the environment.
+func (t *aksTarget) readHelmValuesFile(
+	serviceConfig *ServiceConfig,
+	release *helm.Release,
+	valuesFile string,
+) (string, error) {
+	valuesPath := valuesFile
+	if !filepath.IsAbs(valuesPath) {
+		valuesPath = filepath.Join(serviceConfig.Project.Path, valuesPath)
+	}
+
+	valuesBytes, err := os.ReadFile(valuesPath)
+	if err != nil {
+		return "", fmt.Errorf("reading values for helm release '%s': %w", release.Name, err)
+	}
+
+	values, err := osutil.NewExpandableString(string(valuesBytes)).Envsubst(t.env.Getenv)
+	if err != nil {
+		return "", fmt.Errorf("failed to envsubst values '%s' for helm release '%s': %w", valuesFile, release.Name, err)
+	}
+
+	return values, nil
+}
+
+// readHelmValues reads the values files of the release, merged in the order they are applied
+func (t *aksTarget) readHelmValues(serviceConfig *ServiceConfig, release *helm.Release) (map[string]any, error) {
+	values := map[string]any{}
+	for _, valuesFile := range helmValuesFiles(release) {
+		content, err := t.readHelmValuesFile(serviceConfig, release, valuesFile)
+		if err != nil {
+			return nil, err
+		}
+
+		fileValues := map[string]any{}
+		if err := yaml.Unmarshal([]byte(content), &fileValues); err != nil {
+			return nil, fmt.Errorf("parsing values for helm release '%s': %w", release.Name, err)
+		}
+
+		mergeHelmValues(values, fileValues)
+	}
+
+	return values, nil
+}
+
+// mergeHelmValues merges src into dst the way helm merges values files: maps are merged recursively, and any other
+// value of src replaces the value of dst.
+func mergeHelmValues(dst map[string]any, src map[string]any) {
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]any)
+		dstMap, dstIsMap := dst[key].(map[string]any)
+		if srcIsMap && dstIsMap {
+			mergeHelmValues(dstMap, srcMap)
+			continue
+		}
+
+		dst[key] = value
+	}
+}
+
+// deployHelmRelease installs or upgrades a helm release, and waits for it to be deployed. When the release fails to
+// deploy, it is rolled back to the revision deployed before, or uninstalled when it didn't exist before.
+func (t *aksTarget) deployHelmRelease(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	release *helm.Release,
+	task *async.Progress[ServiceProgress],
+) error {
+	if err := t.ensureNamespace(ctx, release.Namespace); err != nil {
+		return err
+	}
+
+	// Helm reads the values files itself, so the expanded values are written to temporary files.
+	valuesDir, err := os.MkdirTemp("", "azd-helm-values")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(valuesDir)
+
+	valuesFiles := helmValuesFiles(release)
+	release.Values = ""
+	release.ValuesFiles = make([]string, 0, len(valuesFiles))
+	for i, valuesFile := range valuesFiles {
+		content, err := t.readHelmValuesFile(serviceConfig, release, valuesFile)
+		if err != nil {
+			return err
+		}
+
+		valuesPath := filepath.Join(valuesDir, fmt.Sprintf("values-%d.yaml", i))
+		if err := os.WriteFile(valuesPath, []byte(content), osutil.PermissionFileOwnerOnly); err != nil {
+			return err
+		}
+
+		release.ValuesFiles = append(release.ValuesFiles, valuesPath)
+	}
+
+	previous, err := t.helmCli.Status(ctx, release)
+	if err != nil && !errors.Is(err, helm.ErrReleaseNotFound) {
+		return err
+	}
+
+	task.SetProgress(NewServiceProgress(fmt.Sprintf("Installing helm release: %s", release.Name)))
+	err = t.helmCli.Upgrade(ctx, release)
+	if err == nil {
+		err = t.waitForHelmRelease(ctx, release, task)
+	}
+
+	if err != nil {
+		return t.rollbackHelmRelease(ctx, release, previous, err, task)
+	}
+
+	return nil
+}
+
+// waitForHelmRelease waits for a helm release to be deployed
+func (t *aksTarget) waitForHelmRelease(
+	ctx context.Context,
+	release *helm.Release,
+	task *async.Progress[ServiceProgress],
+) error {
+	task.SetProgress(NewServiceProgress(fmt.Sprintf("Checking helm release status: %s", release.Name)))
+	stopProgress := startPollingProgress(
+		task, fmt.Sprintf("Waiting for helm release: %s", release.Name), 15*time.Second,
+	)
+	defer stopProgress()
+
+	return retry.Do(
+		ctx,
+		retry.WithMaxDuration(10*time.Minute, retry.NewConstant(5*time.Second)),
+		func(ctx context.Context) error {
+			status, err := t.helmCli.Status(ctx, release)
+			if err != nil {
+				return err
+			}
+
+			if status.Info.Status != helm.StatusKindDeployed {
+				log.Printf("helm release '%s' status: %s", release.Name, status.Info.Status)
+				return retry.RetryableError(
+					fmt.Errorf("helm release '%s' is not ready, status: %s", release.Name, status.Info.Status),
+				)
+			}
+
+			return nil
+		},
+	)
+}
+
+// rollbackHelmRelease restores a helm release that failed to deploy. A release that was deployed before is rolled
+// back to its previous revision, and a release that didn't exist before is uninstalled. deployErr is returned with
+// the outcome of the rollback.
+func (t *aksTarget) rollbackHelmRelease(
+	ctx context.Context,
+	release *helm.Release,
+	previous *helm.StatusResult,
+	deployErr error,
+	task *async.Progress[ServiceProgress],
+) error {
+	switch {
+	case previous == nil:
+		task.SetProgress(NewServiceProgress(fmt.Sprintf("Uninstalling failed helm release: %s", release.Name)))
+		if err := t.helmCli.Uninstall(ctx, release); err != nil {
+			return fmt.Errorf("%w; uninstalling the failed release: %w", deployErr, err)
+		}
+
+		return fmt.Errorf("%w; the failed release was uninstalled", deployErr)
+	case previous.Info.Status == helm.StatusKindDeployed && previous.Version > 0:
+		revision := int(previous.Version)
+		task.SetProgress(NewServiceProgress(
+			fmt.Sprintf("Rolling back helm release: %s to revision %d", release.Name, revision)))
+		if err := t.helmCli.Rollback(ctx, release, revision); err != nil {
+			return fmt.Errorf("%w; rolling back to revision %d: %w", deployErr, revision, err)
+		}
+
+		return fmt.Errorf("%w; the release was rolled back to revision %d", deployErr, revision)
+	default:
+		// The release wasn't healthy before the deployment, so there is no revision to roll back to.
+		return deployErr
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/helm"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_resolveHelmRelease(t *testing.T) {
+	tempDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "charts", "api"), osutil.PermissionDirectory))
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.Project.Path = tempDir
+	env := createEnv()
+	env.DotenvSet("CHART_REGISTRY", "contoso.azurecr.io")
+	env.DotenvSet("CHART_VERSION", "1.2.3")
+	target := &aksTarget{env: env}
+
+	tests := []struct {
+		name    string
+		release *helm.Release
+		want    *helm.Release
+	}{
+		{
+			name:    "DefaultName",
+			release: &helm.Release{Chart: "bitnami/redis"},
+			want:    &helm.Release{Name: "api-test", Chart: "bitnami/redis", Namespace: "default-ns"},
+		},
+		{
+			name: "OciChart",
+			release: &helm.Release{
+				Name:      "api-${CHART_VERSION}",
+				Chart:     "oci://${CHART_REGISTRY}/charts/api",
+				Version:   "${CHART_VERSION}",
+				Namespace: "apps",
+			},
+			want: &helm.Release{
+				Name:      "api-1.2.3",
+				Chart:     "oci://contoso.azurecr.io/charts/api",
+				Version:   "1.2.3",
+				Namespace: "apps",
+			},
+		},
+		{
+			name:    "LocalChart",
+			release: &helm.Release{Name: "api", Chart: "charts/api"},
+			want:    &helm.Release{Name: "api", Chart: filepath.Join(tempDir, "charts", "api"), Namespace: "default-ns"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := target.resolveHelmRelease(serviceConfig, tt.release, "default-ns")
+			require.NoError(t, err)
+			require.Equal(t, tt.want, resolved)
+		})
+	}
+}
+
+func Test_readHelmValues(t *testing.T) {
+	tempDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "values.yaml"), []byte(
+		"image:\n  repository: api\n  tag: latest\nreplicas: 1\n"), osutil.PermissionFile))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "values.prod.yaml"), []byte(
+		"image:\n  tag: ${IMAGE_TAG}\nreplicas: 3\n"), osutil.PermissionFile))
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.Project.Path = tempDir
+	env := createEnv()
+	env.DotenvSet("IMAGE_TAG", "v2")
+	target := &aksTarget{env: env}
+
+	values, err := target.readHelmValues(serviceConfig, &helm.Release{
+		Name:        "api",
+		Values:      "values.yaml",
+		ValuesFiles: []string{"values.prod.yaml"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"image":    map[string]any{"repository": "api", "tag": "v2"},
+		"replicas": 3,
+	}, values)
+}
+
+func Test_Deploy_Helm_Rollback(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		statusErr  error
+		wantCall   string
+		wantErrMsg string
+	}{
+		{
+			name:       "RolledBack",
+			status:     `{"version": 3, "info": {"status": "deployed"}}`,
+			wantCall:   "rollback argocd 3 --wait",
+			wantErrMsg: "the release was rolled back to revision 3",
+		},
+		{
+			name:       "Uninstalled",
+			statusErr:  errors.New("Error: release: not found"),
+			wantCall:   "uninstall argocd --wait",
+			wantErrMsg: "the failed release was uninstalled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			ostest.Chdir(t, tempDir)
+
+			mockContext := mocks.NewMockContext(t.Context())
+			require.NoError(t, setupMocksForAksTarget(mockContext))
+			_, err := setupMocksForHelm(mockContext)
+			require.NoError(t, err)
+
+			var calls []string
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "helm status")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				if tt.statusErr != nil {
+					return exec.NewRunResult(1, "", tt.statusErr.Error()), tt.statusErr
+				}
+				return exec.NewRunResult(0, tt.status, ""), nil
+			})
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "helm upgrade")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				return exec.NewRunResult(1, "", "timed out waiting for the condition"), errors.New("exit code: 1")
+			})
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "helm rollback") || strings.Contains(command, "helm uninstall")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				calls = append(calls, strings.Join(args.Args, " "))
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+			serviceConfig.RelativePath = ""
+			serviceConfig.K8s.Helm = &helm.Config{
+				Releases: []*helm.Release{
+					{Name: "argocd", Chart: "argo/argo-cd"},
+				},
+			}
+
+			env := createEnv()
+			azdCtx := createTestAzdContext(t, env)
+			userConfig := config.NewConfig(nil)
+			_ = userConfig.Set("alpha.aks.helm", "on")
+
+			serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, userConfig, azdCtx)
+			require.NoError(t, simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig))
+
+			scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+			_, err = logProgress(
+				t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+					return serviceTarget.Deploy(*mockContext.Context, serviceConfig, NewServiceContext(), scope, progress)
+				},
+			)
+
+			require.ErrorContains(t, err, "failed to install helm chart argo/argo-cd")
+			require.ErrorContains(t, err, tt.wantErrMsg)
+			require.Len(t, calls, 1)
+			require.True(t, strings.HasPrefix(calls[0], tt.wantCall), calls[0])
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerregistry/armcontainerregistry"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v2"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/armmsi"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
@@ -33,6 +34,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockaccount"
@@ -1559,6 +1561,7 @@ func createAksServiceTargetWithResourceManager(
 
 	managedClustersService := azapi.NewManagedClustersService(
 		credentialProvider, mockContext.ArmClientOptions)
+	fleetsService := azapi.NewFleetsService(credentialProvider, mockContext.ArmClientOptions)
 	containerRegistryService := azapi.NewContainerRegistryService(
 		credentialProvider,
 		dockerCli,
@@ -1589,12 +1592,66 @@ func createAksServiceTargetWithResourceManager(
 		envManager,
 		mockContext.Console,
 		managedClustersService,
+		fleetsService,
+		armmsi.NewArmMsiService(credentialProvider, mockContext.ArmClientOptions),
 		resourceManager,
 		kubeCtl,
 		kubeLoginCli,
 		helmCli,
 		kustomizeCli,
+		git.NewCli(mockContext.CommandRunner),
 		containerHelper,
 		alpha.NewFeaturesManagerWithConfig(userConfig),
 	)
 }
+
+func Test_AKS_Shell(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(t.Context())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	err = setupListClusterUserCredentialsMock(mockContext, http.StatusOK)
+	require.NoError(t, err)
+
+	var execArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl exec")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		execArgs = args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	env := createEnv()
+
+	resourceManager := &MockResourceManager{}
+	targetResource := environment.NewTargetResource(
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"MY_AKS_CLUSTER",
+		string(azapi.AzureResourceTypeManagedCluster),
+	)
+	resourceManager.
+		On("GetTargetResource", *mockContext.Context, "SUBSCRIPTION_ID", serviceConfig).
+		Return(targetResource, nil)
+
+	serviceTarget := createAksServiceTargetWithResourceManager(mockContext, env, nil, resourceManager)
+	shell, ok := serviceTarget.(ServiceShell)
+	require.True(t, ok)
+
+	err = shell.Shell(*mockContext.Context, serviceConfig, targetResource, &ShellOptions{
+		Command:   []string{"/bin/bash", "-l"},
+		Container: "api",
+		Tty:       true,
+	})
+	require.NoError(t, err)
+
+	require.True(t, execArgs.Interactive)
+	require.Equal(t, []string{
+		"exec", "-i", "-t", "-n", serviceConfig.Project.Name, "-c", "api",
+		"deployment/" + serviceConfig.Name, "--", "/bin/bash", "-l",
+	}, execArgs.Args)
+}
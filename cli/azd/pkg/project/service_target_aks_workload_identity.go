@@ -0,0 +1,198 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/braydonk/yaml"
+)
+
+var featureWorkloadIdentity alpha.FeatureId = alpha.MustFeatureKey("aks.workloadIdentity")
+
+const (
+	// The audience of the tokens that AKS workload identity exchanges for Microsoft Entra tokens
+	workloadIdentityAudience = "api://AzureADTokenExchange"
+	// The service property holding the name of the k8s service account of the service
+	serviceAccountNameProperty = "SERVICE_ACCOUNT_NAME"
+	// The service property holding the client ID of the managed identity of the service account
+	workloadIdentityClientIdProperty = "WORKLOAD_IDENTITY_CLIENT_ID"
+	// Federated identity credential names are limited to 120 characters
+	maxFederatedCredentialNameLength = 120
+)
+
+// Characters that aren't allowed in the name of a federated identity credential
+var invalidFederatedCredentialNameChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// AksWorkloadIdentityOptions configures the workload identity of an AKS service.
+// When set, azd creates the federated identity credential that lets the k8s service account of the service
+// authenticate as a user-assigned managed identity, and the service account itself.
+type AksWorkloadIdentityOptions struct {
+	// The resource ID of the user-assigned managed identity, usually an output of the infrastructure
+	Identity osutil.ExpandableString `yaml:"identity"`
+	// The name of the k8s service account. Defaults to the service name.
+	ServiceAccount string `yaml:"serviceAccount,omitempty"`
+}
+
+// validateWorkloadIdentity checks the workload identity options of the AKS configuration
+func (o *AksOptions) validateWorkloadIdentity() error {
+	if o.WorkloadIdentity == nil {
+		return nil
+	}
+
+	if o.WorkloadIdentity.Identity.Empty() {
+		return errors.New("'k8s.workloadIdentity.identity' is required")
+	}
+
+	// Each member cluster of a fleet has its own OIDC issuer
+	if o.Fleet != nil {
+		return errors.New("'k8s.workloadIdentity' and 'k8s.fleet' cannot be used together")
+	}
+
+	return nil
+}
+
+// configureWorkloadIdentity creates the federated identity credential that trusts the k8s service account of the
+// service, and returns the manifest of the service account. The name of the service account and the client ID of the
+// managed identity are stored in the environment, so the manifests of the service can reference them.
+func (t *aksTarget) configureWorkloadIdentity(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	progress *async.Progress[ServiceProgress],
+) (string, error) {
+	if !t.featureManager.IsEnabled(featureWorkloadIdentity) {
+		return "", fmt.Errorf(
+			"AKS workload identity support is not enabled. Run '%s' to enable it.",
+			alpha.GetEnableCommand(featureWorkloadIdentity),
+		)
+	}
+
+	if err := serviceConfig.K8s.validateWorkloadIdentity(); err != nil {
+		return "", err
+	}
+
+	workloadIdentity := serviceConfig.K8s.WorkloadIdentity
+	identityId, err := workloadIdentity.Identity.Envsubst(t.env.Getenv)
+	if err != nil {
+		return "", fmt.Errorf("failed resolving workload identity: %w", err)
+	}
+
+	identityResourceId, err := arm.ParseResourceID(identityId)
+	if err != nil {
+		return "", fmt.Errorf(
+			"'k8s.workloadIdentity.identity' must be the resource ID of a user-assigned managed identity, got '%s': %w",
+			identityId,
+			err,
+		)
+	}
+
+	serviceAccount := workloadIdentity.ServiceAccount
+	if serviceAccount == "" {
+		serviceAccount = serviceConfig.Name
+	}
+
+	namespace := t.getK8sNamespace(serviceConfig)
+
+	clusterName, err := t.resolveClusterName(serviceConfig, targetResource)
+	if err != nil {
+		return "", err
+	}
+
+	progress.SetProgress(NewServiceProgress("Configuring workload identity"))
+	cluster, err := t.managedClustersService.Get(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		clusterName,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed retrieving AKS cluster '%s': %w", clusterName, err)
+	}
+
+	var issuer string
+	if cluster.Properties != nil && cluster.Properties.OidcIssuerProfile != nil &&
+		cluster.Properties.OidcIssuerProfile.IssuerURL != nil {
+		issuer = *cluster.Properties.OidcIssuerProfile.IssuerURL
+	}
+
+	if issuer == "" {
+		return "", &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("AKS cluster '%s' doesn't have an OIDC issuer", clusterName),
+			Suggestion: "Enable the OIDC issuer and workload identity on the cluster, with " +
+				"'oidcIssuerProfile.enabled' and 'securityProfile.workloadIdentity.enabled' in the infrastructure.",
+		}
+	}
+
+	identity, err := t.msiService.GetUserIdentity(ctx, identityId)
+	if err != nil {
+		return "", fmt.Errorf("failed retrieving managed identity '%s': %w", identityResourceId.Name, err)
+	}
+
+	if identity.Properties == nil || identity.Properties.ClientID == nil {
+		return "", fmt.Errorf("managed identity '%s' has no client ID", identityResourceId.Name)
+	}
+	clientId := *identity.Properties.ClientID
+
+	_, err = t.msiService.CreateFederatedCredential(
+		ctx,
+		identityResourceId.SubscriptionID,
+		identityResourceId.ResourceGroupName,
+		identityResourceId.Name,
+		federatedCredentialName(clusterName, namespace, serviceAccount),
+		fmt.Sprintf("system:serviceaccount:%s:%s", namespace, serviceAccount),
+		issuer,
+		[]string{workloadIdentityAudience},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	t.env.SetServiceProperty(serviceConfig.Name, serviceAccountNameProperty, serviceAccount)
+	t.env.SetServiceProperty(serviceConfig.Name, workloadIdentityClientIdProperty, clientId)
+	if err := t.envManager.Save(ctx, t.env); err != nil {
+		return "", fmt.Errorf("failed updating environment with workload identity, %w", err)
+	}
+
+	manifest, err := yaml.Marshal(map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ServiceAccount",
+		"metadata": map[string]any{
+			"name":      serviceAccount,
+			"namespace": namespace,
+			"annotations": map[string]any{
+				"azure.workload.identity/client-id": clientId,
+			},
+			"labels": map[string]any{
+				"azure.workload.identity/use": "true",
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(manifest), nil
+}
+
+// federatedCredentialName returns the name of the federated identity credential that trusts a k8s service account.
+// The name is unique per cluster, namespace and service account, so that deploying again updates the credential.
+func federatedCredentialName(clusterName string, namespace string, serviceAccount string) string {
+	name := invalidFederatedCredentialNameChars.ReplaceAllString(
+		fmt.Sprintf("azd-%s-%s-%s", clusterName, namespace, serviceAccount), "-")
+	if len(name) > maxFederatedCredentialNameLength {
+		name = name[:maxFederatedCredentialNameLength]
+	}
+
+	return name
+}
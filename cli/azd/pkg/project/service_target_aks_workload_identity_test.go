@@ -0,0 +1,191 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v2"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/msi/armmsi"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+const testWorkloadIdentityId = "/subscriptions/IDENTITY_SUB/resourceGroups/IDENTITY_RG/providers/" +
+	"Microsoft.ManagedIdentity/userAssignedIdentities/api-identity"
+
+func Test_Deploy_WorkloadIdentity(t *testing.T) {
+	setup := func(t *testing.T, issuer string) (*mocks.MockContext, *armmsi.FederatedIdentityCredential, *[]string) {
+		mockContext := mocks.NewMockContext(t.Context())
+		require.NoError(t, setupMocksForAksTarget(mockContext))
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && strings.Contains(
+				request.URL.Path, "Microsoft.ContainerService/managedClusters/AKS_CLUSTER")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			cluster := armcontainerservice.ManagedCluster{
+				Properties: &armcontainerservice.ManagedClusterProperties{
+					OidcIssuerProfile: &armcontainerservice.ManagedClusterOIDCIssuerProfile{
+						Enabled:   new(issuer != ""),
+						IssuerURL: new(issuer),
+					},
+				},
+			}
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, cluster)
+		})
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet &&
+				strings.HasSuffix(request.URL.Path, "/userAssignedIdentities/api-identity")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			identity := armmsi.Identity{
+				Properties: &armmsi.UserAssignedIdentityProperties{ClientID: new("CLIENT_ID")},
+			}
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, identity)
+		})
+
+		credential := &armmsi.FederatedIdentityCredential{}
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPut && strings.Contains(
+				request.URL.Path,
+				"/subscriptions/IDENTITY_SUB/resourceGroups/IDENTITY_RG/providers/Microsoft.ManagedIdentity/"+
+					"userAssignedIdentities/api-identity/federatedIdentityCredentials/",
+			)
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(request.Body)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(body, credential))
+			credential.Name = new(request.URL.Path[strings.LastIndex(request.URL.Path, "/")+1:])
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, credential)
+		})
+
+		var applied []string
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "kubectl apply -f -")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			input, err := io.ReadAll(args.StdIn)
+			require.NoError(t, err)
+			applied = append(applied, string(input))
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		return mockContext, credential, &applied
+	}
+
+	deploy := func(
+		t *testing.T, mockContext *mocks.MockContext, env *environment.Environment, enabled bool,
+	) (*ServiceDeployResult, error) {
+		tempDir := t.TempDir()
+		ostest.Chdir(t, tempDir)
+
+		serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+		serviceConfig.K8s.WorkloadIdentity = &AksWorkloadIdentityOptions{
+			Identity: osutil.NewExpandableString("${AZURE_AKS_IDENTITY_ID}"),
+		}
+		require.NoError(t, setupK8sManifests(t, serviceConfig))
+
+		userConfig := config.NewConfig(nil)
+		if enabled {
+			_ = userConfig.Set("alpha.aks.workloadIdentity", "on")
+		}
+
+		azdCtx := createTestAzdContext(t, env)
+		serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, userConfig, azdCtx)
+		require.NoError(t, simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig))
+
+		scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+		return logProgress(
+			t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+				return serviceTarget.Deploy(*mockContext.Context, serviceConfig, NewServiceContext(), scope, progress)
+			},
+		)
+	}
+
+	t.Run("Configured", func(t *testing.T) {
+		mockContext, credential, applied := setup(t, "https://eastus2.oic.prod-aks.azure.com/TENANT/CLUSTER/")
+		env := createEnv()
+		env.DotenvSet("AZURE_AKS_IDENTITY_ID", testWorkloadIdentityId)
+
+		_, err := deploy(t, mockContext, env, true)
+		require.NoError(t, err)
+
+		require.Equal(t, "azd-AKS_CLUSTER-Test-App-api", *credential.Name)
+		require.Equal(t, "system:serviceaccount:Test-App:api", *credential.Properties.Subject)
+		require.Equal(t, "https://eastus2.oic.prod-aks.azure.com/TENANT/CLUSTER/", *credential.Properties.Issuer)
+		require.Equal(t, []*string{new(workloadIdentityAudience)}, credential.Properties.Audiences)
+
+		require.Equal(t, "api", env.GetServiceProperty("api", serviceAccountNameProperty))
+		require.Equal(t, "CLIENT_ID", env.GetServiceProperty("api", workloadIdentityClientIdProperty))
+
+		require.Contains(t, *applied, strings.Join([]string{
+			"apiVersion: v1",
+			"kind: ServiceAccount",
+			"metadata:",
+			"    annotations:",
+			"        azure.workload.identity/client-id: CLIENT_ID",
+			"    labels:",
+			"        azure.workload.identity/use: \"true\"",
+			"    name: api",
+			"    namespace: Test-App",
+			"",
+		}, "\n"))
+	})
+
+	t.Run("NoOidcIssuer", func(t *testing.T) {
+		mockContext, _, _ := setup(t, "")
+		env := createEnv()
+		env.DotenvSet("AZURE_AKS_IDENTITY_ID", testWorkloadIdentityId)
+
+		_, err := deploy(t, mockContext, env, true)
+		require.ErrorContains(t, err, "AKS cluster 'AKS_CLUSTER' doesn't have an OIDC issuer")
+	})
+
+	t.Run("InvalidIdentity", func(t *testing.T) {
+		mockContext, _, _ := setup(t, "https://eastus2.oic.prod-aks.azure.com/TENANT/CLUSTER/")
+		env := createEnv()
+		env.DotenvSet("AZURE_AKS_IDENTITY_ID", "api-identity")
+
+		_, err := deploy(t, mockContext, env, true)
+		require.ErrorContains(t, err, "must be the resource ID of a user-assigned managed identity")
+	})
+
+	t.Run("NotEnabled", func(t *testing.T) {
+		mockContext, _, _ := setup(t, "https://eastus2.oic.prod-aks.azure.com/TENANT/CLUSTER/")
+
+		_, err := deploy(t, mockContext, createEnv(), false)
+		require.ErrorContains(t, err, "AKS workload identity support is not enabled")
+	})
+}
+
+func Test_AksOptions_validateWorkloadIdentity(t *testing.T) {
+	require.NoError(t, (&AksOptions{}).validateWorkloadIdentity())
+
+	err := (&AksOptions{WorkloadIdentity: &AksWorkloadIdentityOptions{}}).validateWorkloadIdentity()
+	require.ErrorContains(t, err, "'k8s.workloadIdentity.identity' is required")
+
+	err = (&AksOptions{
+		WorkloadIdentity: &AksWorkloadIdentityOptions{Identity: osutil.NewExpandableString(testWorkloadIdentityId)},
+		Fleet:            &AksFleetOptions{},
+	}).validateWorkloadIdentity()
+	require.ErrorContains(t, err, "cannot be used together")
+}
+
+func Test_federatedCredentialName(t *testing.T) {
+	require.Equal(t, "azd-cluster-apps-api", federatedCredentialName("cluster", "apps", "api"))
+	require.Equal(t, "azd-my-cluster-apps-api-v1", federatedCredentialName("my.cluster", "apps", "api.v1"))
+
+	name := federatedCredentialName(strings.Repeat("c", 120), "apps", "api")
+	require.Len(t, name, maxFederatedCredentialNameLength)
+}
@@ -5,17 +5,21 @@ package project
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/internal/mapper"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
 )
@@ -26,6 +30,11 @@ type appServiceTarget struct {
 	containerHelper *ContainerHelper
 	cli             *azapi.AzureClient
 	console         input.Console
+	keyvaultService keyvault.KeyVaultService
+
+	// httpClient checks the health path of the staging slot configured for the service. When nil, a default client is
+	// used.
+	httpClient *http.Client
 }
 
 // NewAppServiceTarget creates a new instance of the AppServiceTarget
@@ -35,6 +44,7 @@ func NewAppServiceTarget(
 	containerHelper *ContainerHelper,
 	azCli *azapi.AzureClient,
 	console input.Console,
+	keyvaultService keyvault.KeyVaultService,
 ) ServiceTarget {
 	return &appServiceTarget{
 		env:             env,
@@ -42,6 +52,7 @@ func NewAppServiceTarget(
 		containerHelper: containerHelper,
 		cli:             azCli,
 		console:         console,
+		keyvaultService: keyvaultService,
 	}
 }
 
@@ -139,6 +150,13 @@ func (st *appServiceTarget) Publish(
 	if artifact, found := serviceContext.Package.FindFirst(WithKind(ArtifactKindContainer)); found {
 		if parsedImage, parseErr := docker.ParseContainerImage(artifact.Location); parseErr == nil {
 			if parsedImage.Registry != "" {
+				// An image referenced by digest, such as with `azd deploy --image`, must exist in the registry
+				if _, _, byDigest := docker.SplitImageDigest(artifact.Location); byDigest {
+					if err := st.containerHelper.VerifyRemoteImage(ctx, st.env, artifact.Location, progress); err != nil {
+						return nil, err
+					}
+				}
+
 				publishResult = &ServicePublishResult{
 					Artifacts: ArtifactCollection{
 						{
@@ -266,22 +284,60 @@ func (st *appServiceTarget) containerDeploy(
 	// Apply environment variables as App Settings if configured
 	if len(serviceConfig.Environment) > 0 {
 		progress.SetProgress(NewServiceProgress("Updating application settings"))
-		envVars, err := serviceConfig.Environment.Expand(st.env.Getenv)
+		// App Service resolves the Key Vault references with the managed identity of the app.
+		envVars, err := expandServiceEnvironment(
+			ctx,
+			st.keyvaultService,
+			st.env,
+			serviceConfig,
+			targetResource.SubscriptionId(),
+			keyvault.SecretExpression.AppServiceReference,
+		)
 		if err != nil {
 			return nil, fmt.Errorf("expanding environment variables: %w", err)
 		}
 
-		if err := st.cli.UpdateAppServiceAppSettings(
-			ctx,
-			targetResource.SubscriptionId(),
-			targetResource.ResourceGroupName(),
-			targetResource.ResourceName(),
-			envVars,
-		); err != nil {
+		// Settings of a staging slot move to production with the swap.
+		if serviceConfig.Slot != nil {
+			err = st.cli.UpdateAppServiceSlotAppSettings(
+				ctx,
+				targetResource.SubscriptionId(),
+				targetResource.ResourceGroupName(),
+				targetResource.ResourceName(),
+				serviceConfig.Slot.Name,
+				envVars,
+			)
+		} else {
+			err = st.cli.UpdateAppServiceAppSettings(
+				ctx,
+				targetResource.SubscriptionId(),
+				targetResource.ResourceGroupName(),
+				targetResource.ResourceName(),
+				envVars,
+			)
+		}
+		if err != nil {
 			return nil, fmt.Errorf("updating app settings for service %s: %w", serviceConfig.Name, err)
 		}
 	}
 
+	// Settings of a staging slot move to production with the swap.
+	var slotName string
+	if serviceConfig.Slot != nil {
+		slotName = serviceConfig.Slot.Name
+	}
+	if err := syncWebAppSettings(ctx, st.cli, st.env, serviceConfig, targetResource, slotName, progress); err != nil {
+		return nil, fmt.Errorf("syncing app settings: %w", err)
+	}
+
+	if err := checkWebAppKeyVaultReferences(ctx, st.cli, serviceConfig, targetResource, slotName); err != nil {
+		return nil, err
+	}
+
+	if err := st.validateAndSwapSlot(ctx, serviceConfig, targetResource, progress); err != nil {
+		return nil, err
+	}
+
 	progress.SetProgress(NewServiceProgress("Fetching endpoints for app service"))
 	endpoints, err := st.Endpoints(ctx, serviceConfig, targetResource)
 	if err != nil {
@@ -399,6 +455,10 @@ func (st *appServiceTarget) zipDeploy(
 		}
 	}
 
+	if err := st.validateAndSwapSlot(ctx, serviceConfig, targetResource, progress); err != nil {
+		return nil, err
+	}
+
 	progress.SetProgress(NewServiceProgress("Fetching endpoints for app service"))
 	endpoints, err := st.Endpoints(ctx, serviceConfig, targetResource)
 	if err != nil {
@@ -446,6 +506,7 @@ type deploymentTarget struct {
 // determineDeploymentTargets determines which targets (main app and/or slots) to deploy to.
 //
 // Deployment target selection:
+//  0. A slot configured in azure.yaml is always the target, and is created when it doesn't exist.
 //  1. SLOT_NAME takes highest precedence — explicit intent always wins.
 //     "production" means the main app. Any other value must match an existing slot.
 //  2. No slots exist — deploy to main app.
@@ -457,6 +518,15 @@ func (st *appServiceTarget) determineDeploymentTargets(
 	targetResource *environment.TargetResource,
 	progress *async.Progress[ServiceProgress],
 ) ([]deploymentTarget, error) {
+	if serviceConfig.Slot != nil {
+		slotName, err := st.ensureSlot(ctx, serviceConfig.Slot.Name, targetResource, progress)
+		if err != nil {
+			return nil, err
+		}
+
+		return []deploymentTarget{{SlotName: slotName}}, nil
+	}
+
 	slotEnvVarName := slotEnvVarNameForService(serviceConfig.Name)
 
 	// Check SLOT_NAME first — explicit intent always wins
@@ -549,6 +619,133 @@ func (st *appServiceTarget) determineDeploymentTargets(
 	return []deploymentTarget{{SlotName: slots[selectedIndex-1].Name}}, nil
 }
 
+// slotHealthPollInterval is how often the health path of a staging slot is checked.
+var slotHealthPollInterval = 10 * time.Second
+
+// ensureSlot returns the name of the deployment slot of the App Service, creating the slot when it doesn't exist.
+func (st *appServiceTarget) ensureSlot(
+	ctx context.Context,
+	slotName string,
+	targetResource *environment.TargetResource,
+	progress *async.Progress[ServiceProgress],
+) (string, error) {
+	progress.SetProgress(NewServiceProgress("Checking deployment slots"))
+	slots, err := st.cli.GetAppServiceSlots(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+	)
+	if err != nil {
+		return "", fmt.Errorf("getting deployment slots: %w", err)
+	}
+
+	for _, slot := range slots {
+		if strings.EqualFold(slot.Name, slotName) {
+			return slot.Name, nil
+		}
+	}
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Creating deployment slot '%s'", slotName)))
+	if err := st.cli.CreateAppServiceSlot(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		slotName,
+	); err != nil {
+		return "", fmt.Errorf("creating deployment slot: %w", err)
+	}
+
+	return slotName, nil
+}
+
+// validateAndSwapSlot waits for the staging slot configured for the service to respond on its health path, and then
+// swaps it with production. It does nothing when the service has no staging slot.
+func (st *appServiceTarget) validateAndSwapSlot(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	progress *async.Progress[ServiceProgress],
+) error {
+	slot := serviceConfig.Slot
+	if slot == nil {
+		return nil
+	}
+
+	if slot.HealthPath != "" {
+		progress.SetProgress(NewServiceProgress(fmt.Sprintf("Validating slot '%s'", slot.Name)))
+		if err := st.waitForSlotHealth(ctx, slot, targetResource); err != nil {
+			return fmt.Errorf(
+				"slot '%s' of service %s is not healthy, production was not changed: %w", slot.Name, serviceConfig.Name, err)
+		}
+	}
+
+	if !slot.swap() {
+		return nil
+	}
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Swapping slot '%s' with production", slot.Name)))
+	if err := st.cli.SwapAppServiceSlot(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		slot.Name,
+	); err != nil {
+		return fmt.Errorf("swapping slot '%s' of service %s: %w", slot.Name, serviceConfig.Name, err)
+	}
+
+	return nil
+}
+
+// waitForSlotHealth waits for a deployment slot to respond on its health path with a success status, and returns an
+// error when it doesn't before the health timeout.
+func (st *appServiceTarget) waitForSlotHealth(
+	ctx context.Context,
+	slot *AppServiceSlotOptions,
+	targetResource *environment.TargetResource,
+) error {
+	properties, err := st.cli.GetAppServiceSlotProperties(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		slot.Name,
+	)
+	if err != nil {
+		return fmt.Errorf("fetching slot properties: %w", err)
+	}
+
+	if len(properties.HostNames) == 0 {
+		return errors.New("slot has no host name to check the health path")
+	}
+
+	url := fmt.Sprintf("https://%s%s", properties.HostNames[0], slot.HealthPath)
+
+	ctx, cancel := context.WithTimeout(ctx, slot.healthTimeout())
+	defer cancel()
+
+	var lastErr error
+	for {
+		err := checkHealthPath(ctx, st.httpClient, url)
+		if err == nil {
+			return nil
+		}
+
+		// A check interrupted by the timeout doesn't replace the reason of the previous failed check.
+		if lastErr == nil || ctx.Err() == nil {
+			lastErr = err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("not healthy after %s: %w", slot.healthTimeout(), lastErr)
+		case <-time.After(slotHealthPollInterval):
+		}
+	}
+}
+
 // slotEnvVarNameForService returns the environment variable name for setting the deployment slot
 // for a given service. The format is AZD_DEPLOY_{SERVICE_NAME}_SLOT_NAME where the service name
 // is normalized via environment.Key (uppercase, spaces/hyphens → underscores).
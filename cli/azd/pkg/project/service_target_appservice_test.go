@@ -389,13 +389,13 @@ func Test_appServiceTarget_Publish(t *testing.T) {
 
 func Test_NewAppServiceTarget(t *testing.T) {
 	env := environment.NewWithValues("test-env", nil)
-	target := NewAppServiceTarget(env, nil, nil, nil, nil)
+	target := NewAppServiceTarget(env, nil, nil, nil, nil, nil)
 	require.NotNil(t, target)
 }
 
 func Test_appServiceTarget_RequiredExternalTools(t *testing.T) {
 	t.Run("NonDocker_ReturnsEmpty", func(t *testing.T) {
-		target := NewAppServiceTarget(nil, nil, nil, nil, nil)
+		target := NewAppServiceTarget(nil, nil, nil, nil, nil, nil)
 		result := target.RequiredExternalTools(t.Context(), &ServiceConfig{
 			Language: ServiceLanguagePython,
 		})
@@ -405,20 +405,20 @@ func Test_appServiceTarget_RequiredExternalTools(t *testing.T) {
 
 func Test_appServiceTarget_Initialize(t *testing.T) {
 	t.Run("NonDocker_NoError", func(t *testing.T) {
-		target := NewAppServiceTarget(nil, nil, nil, nil, nil)
+		target := NewAppServiceTarget(nil, nil, nil, nil, nil, nil)
 		err := target.Initialize(t.Context(), &ServiceConfig{Language: ServiceLanguagePython})
 		require.NoError(t, err)
 	})
 
 	t.Run("Docker_NoError", func(t *testing.T) {
-		target := NewAppServiceTarget(nil, nil, nil, nil, nil)
+		target := NewAppServiceTarget(nil, nil, nil, nil, nil, nil)
 		err := target.Initialize(t.Context(), &ServiceConfig{Language: ServiceLanguageDocker})
 		require.NoError(t, err)
 	})
 
 	t.Run("PolyglotDocker_NoError", func(t *testing.T) {
 		// Polyglot containerization (python + docker.path) is now supported
-		target := NewAppServiceTarget(nil, nil, nil, nil, nil)
+		target := NewAppServiceTarget(nil, nil, nil, nil, nil, nil)
 		err := target.Initialize(t.Context(), &ServiceConfig{
 			Language: ServiceLanguagePython,
 			Docker:   DockerProjectOptions{Path: "./Dockerfile"},
@@ -9,12 +9,14 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
 	"github.com/azure/azure-dev/cli/azd/internal/mapper"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
@@ -27,9 +29,11 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/bicep"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"golang.org/x/term"
 )
 
 type containerAppTarget struct {
@@ -41,8 +45,13 @@ type containerAppTarget struct {
 	armDeployments      *azapi.StandardDeployments
 	console             input.Console
 	commandRunner       exec.CommandRunner
+	keyvaultService     keyvault.KeyVaultService
 
 	bicepCli func() (*bicep.Cli, error)
+
+	// httpClient checks the health path of the revisions deployed with a deployment strategy. When nil, a default
+	// client is used.
+	httpClient *http.Client
 }
 
 // NewContainerAppTarget creates the container app service target.
@@ -58,6 +67,7 @@ func NewContainerAppTarget(
 	deploymentService *azapi.StandardDeployments,
 	console input.Console,
 	commandRunner exec.CommandRunner,
+	keyvaultService keyvault.KeyVaultService,
 ) ServiceTarget {
 	return &containerAppTarget{
 		env:                 env,
@@ -68,6 +78,7 @@ func NewContainerAppTarget(
 		armDeployments:      deploymentService,
 		console:             console,
 		commandRunner:       commandRunner,
+		keyvaultService:     keyvaultService,
 	}
 }
 
@@ -126,6 +137,13 @@ func (at *containerAppTarget) Publish(
 	// such as when called through `azd deploy --from-package <image>`
 	if parsedImage, err := docker.ParseContainerImage(packagePath); err == nil {
 		if parsedImage.Registry != "" {
+			// An image referenced by digest, such as with `azd deploy --image`, must exist in the registry
+			if _, _, byDigest := docker.SplitImageDigest(packagePath); byDigest {
+				if err := at.containerHelper.VerifyRemoteImage(ctx, at.env, packagePath, progress); err != nil {
+					return nil, err
+				}
+			}
+
 			publishResult = &ServicePublishResult{
 				Artifacts: ArtifactCollection{
 					{
@@ -225,6 +243,24 @@ func (at *containerAppTarget) Deploy(
 		}
 	}
 
+	if serviceConfig.Rollout != nil && controlledRevision {
+		return nil, fmt.Errorf(
+			"the deployment strategy of service %s isn't supported when its revision is deployed with %s",
+			serviceConfig.Name, filepath.Base(mainPath))
+	}
+
+	if serviceConfig.AppSettings != nil && controlledRevision {
+		return nil, fmt.Errorf(
+			"the app settings of service %s aren't supported when its revision is deployed with %s",
+			serviceConfig.Name, filepath.Base(mainPath))
+	}
+
+	if serviceConfig.Job.configuration() != nil && controlledRevision {
+		return nil, fmt.Errorf(
+			"the job trigger of service %s can't be set in azure.yaml when it's deployed with %s, set it in the module",
+			serviceConfig.Name, filepath.Base(mainPath))
+	}
+
 	if controlledRevision {
 		tracing.AppendUsageAttributeUnique(fields.FeaturesKey.String(fields.FeatRevisionDeployment))
 
@@ -302,25 +338,38 @@ func (at *containerAppTarget) Deploy(
 
 		isJob := isJobResource(targetResource)
 
+		if isJob && serviceConfig.Rollout != nil {
+			return nil, fmt.Errorf(
+				"the deployment strategy of service %s isn't supported for container app jobs", serviceConfig.Name)
+		}
+
+		if isJob && serviceConfig.AppSettings != nil {
+			return nil, fmt.Errorf(
+				"the app settings of service %s aren't supported for container app jobs, use env instead",
+				serviceConfig.Name)
+		}
+
 		if isJob {
 			tracing.AppendUsageAttributeUnique(fields.FeaturesKey.String(fields.FeatJobDeployment))
 			resourceTypeContainer = azapi.AzureResourceTypeContainerAppJob
 
 			// Expand environment variables from service config
-			envVars, err := serviceConfig.Environment.Expand(at.env.Getenv)
+			envVars, err := expandServiceEnvironment(
+				ctx, at.keyvaultService, at.env, serviceConfig, targetResource.SubscriptionId(), nil)
 			if err != nil {
 				return nil, fmt.Errorf("expanding environment variables: %w", err)
 			}
 
 			progress.SetProgress(NewServiceProgress("Updating container app job image"))
 			stopProgress := startPollingProgress(progress, "Waiting for container app job update", 15*time.Second)
-			err = at.containerAppService.UpdateContainerAppJobImage(
+			err = at.containerAppService.UpdateContainerAppJob(
 				ctx,
 				targetResource.SubscriptionId(),
 				targetResource.ResourceGroupName(),
 				resourceName,
 				imageName,
 				envVars,
+				serviceConfig.Job.configuration(),
 				&containerAppOptions,
 			)
 			stopProgress()
@@ -328,27 +377,73 @@ func (at *containerAppTarget) Deploy(
 				return nil, fmt.Errorf("updating container app job: %w", err)
 			}
 		} else {
+			if serviceConfig.Job != nil {
+				return nil, fmt.Errorf(
+					"service %s configures a job, but %s is a container app, not a container app job",
+					serviceConfig.Name, resourceName)
+			}
+
 			// Expand environment variables from service config
-			envVars, err := serviceConfig.Environment.Expand(at.env.Getenv)
+			envVars, err := expandServiceEnvironment(
+				ctx, at.keyvaultService, at.env, serviceConfig, targetResource.SubscriptionId(), nil)
 			if err != nil {
 				return nil, fmt.Errorf("expanding environment variables: %w", err)
 			}
 
-			progress.SetProgress(NewServiceProgress("Updating container app revision"))
-			stopProgress := startPollingProgress(progress, "Waiting for container revision", 15*time.Second)
-			err = at.containerAppService.AddRevision(
-				ctx,
-				targetResource.SubscriptionId(),
-				targetResource.ResourceGroupName(),
-				resourceName,
-				imageName,
-				envVars,
-				&containerAppOptions,
-			)
-			stopProgress()
+			appSettings, desiredAppSettings, err := at.containerAppSettings(
+				ctx, serviceConfig, targetResource, resourceName, &containerAppOptions)
 			if err != nil {
-				return nil, fmt.Errorf("updating container app service: %w", err)
+				return nil, fmt.Errorf("syncing app settings: %w", err)
+			}
+
+			if serviceConfig.Rollout != nil {
+				err := at.deployWithStrategy(
+					ctx,
+					serviceConfig.Rollout,
+					targetResource,
+					resourceName,
+					imageName,
+					envVars,
+					appSettings,
+					&containerAppOptions,
+					progress,
+				)
+				if err != nil {
+					return nil, err
+				}
+			} else {
+				progress.SetProgress(NewServiceProgress("Updating container app revision"))
+				stopProgress := startPollingProgress(progress, "Waiting for container revision", 15*time.Second)
+				err = at.containerAppService.AddRevision(
+					ctx,
+					targetResource.SubscriptionId(),
+					targetResource.ResourceGroupName(),
+					resourceName,
+					imageName,
+					envVars,
+					appSettings,
+					&containerAppOptions,
+				)
+				stopProgress()
+				if err != nil {
+					return nil, fmt.Errorf("updating container app service: %w", err)
+				}
 			}
+
+			if desiredAppSettings != nil {
+				recordAppSettings(at.env, serviceConfig.Name, desiredAppSettings)
+			}
+		}
+	}
+
+	if serviceConfig.Job != nil && serviceConfig.Job.StartExecution &&
+		resourceTypeContainer == azapi.AzureResourceTypeContainerAppJob {
+		progress.SetProgress(NewServiceProgress("Running container app job execution"))
+		err := at.runJobExecution(ctx, serviceConfig.Job, targetResource, resourceName, &containerapps.ContainerAppOptions{
+			ApiVersion: serviceConfig.ApiVersion,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("validating container app job: %w", err)
 		}
 	}
 
@@ -392,6 +487,193 @@ func (at *containerAppTarget) Deploy(
 	}, nil
 }
 
+// revisionHealthPollInterval is how often the health of a staged revision is checked.
+var revisionHealthPollInterval = 10 * time.Second
+
+// deployWithStrategy rolls out a new revision of the container app with a deployment strategy: the revision is staged
+// with part or none of the traffic, and promoted once it is healthy. A revision that doesn't become healthy is rolled
+// back, so the traffic goes to the revisions that served it before.
+func (at *containerAppTarget) deployWithStrategy(
+	ctx context.Context,
+	strategy *DeploymentStrategyOptions,
+	targetResource *environment.TargetResource,
+	appName string,
+	imageName string,
+	envVars map[string]string,
+	settings *containerapps.AppSettings,
+	options *containerapps.ContainerAppOptions,
+	progress *async.Progress[ServiceProgress],
+) error {
+	trafficPercent := strategy.trafficPercent()
+	progress.SetProgress(NewServiceProgress(
+		fmt.Sprintf("Creating revision with %d%% of the traffic (%s)", trafficPercent, strategy.Strategy)))
+	stopProgress := startPollingProgress(progress, "Waiting for container revision", 15*time.Second)
+	staged, err := at.containerAppService.StageRevision(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		appName,
+		imageName,
+		envVars,
+		settings,
+		trafficPercent,
+		options,
+	)
+	stopProgress()
+	if err != nil {
+		return fmt.Errorf("updating container app service: %w", err)
+	}
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Checking health of revision %s", staged.Name)))
+	healthErr := at.waitForRevisionHealth(ctx, strategy, targetResource, appName, staged.Name, options)
+	if healthErr != nil {
+		progress.SetProgress(NewServiceProgress(fmt.Sprintf("Rolling back revision %s", staged.Name)))
+
+		// The revision is rolled back even when the deployment was canceled or timed out.
+		rollbackCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Minute)
+		defer cancel()
+
+		err := at.containerAppService.RollbackRevision(
+			rollbackCtx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			appName,
+			staged,
+			options,
+		)
+		if err != nil {
+			return fmt.Errorf("revision %s is unhealthy: %w, and could not be rolled back: %w", staged.Name, healthErr, err)
+		}
+
+		return fmt.Errorf("revision %s is unhealthy and was rolled back: %w", staged.Name, healthErr)
+	}
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Promoting revision %s", staged.Name)))
+	err = at.containerAppService.PromoteRevision(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		appName,
+		staged,
+		options,
+	)
+	if err != nil {
+		return fmt.Errorf("updating container app service: %w", err)
+	}
+
+	return nil
+}
+
+// waitForRevisionHealth waits for a revision to be provisioned and healthy, and when the strategy has a health path,
+// to respond to it with a success status. It returns an error when the revision fails, or isn't healthy before the
+// health timeout of the strategy.
+func (at *containerAppTarget) waitForRevisionHealth(
+	ctx context.Context,
+	strategy *DeploymentStrategyOptions,
+	targetResource *environment.TargetResource,
+	appName string,
+	revisionName string,
+	options *containerapps.ContainerAppOptions,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, strategy.healthTimeout())
+	defer cancel()
+
+	var lastErr error
+	for {
+		revision, err := at.containerAppService.GetRevision(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			appName,
+			revisionName,
+			options,
+		)
+		switch {
+		case err != nil:
+		case revisionFailed(revision):
+			return revisionHealth(revision)
+		default:
+			err = revisionHealth(revision)
+			if err == nil && strategy.HealthPath != "" {
+				err = at.probeRevision(ctx, revision, strategy.HealthPath)
+			}
+
+			if err == nil {
+				return nil
+			}
+		}
+
+		// A check interrupted by the timeout doesn't replace the reason of the previous failed check.
+		if lastErr == nil || ctx.Err() == nil {
+			lastErr = err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("not healthy after %s: %w", strategy.healthTimeout(), lastErr)
+		case <-time.After(revisionHealthPollInterval):
+		}
+	}
+}
+
+// revisionHealth returns nil when a revision is provisioned and healthy, or the reason why it isn't.
+func revisionHealth(revision *armappcontainers.Revision) error {
+	properties := revision.Properties
+	if properties == nil {
+		return errors.New("revision has no properties")
+	}
+
+	if revisionFailed(revision) {
+		return fmt.Errorf("revision failed: provisioning state %s, running state %s",
+			valueOrEmpty(properties.ProvisioningState), valueOrEmpty(properties.RunningState))
+	}
+
+	if valueOrEmpty(properties.ProvisioningState) != armappcontainers.RevisionProvisioningStateProvisioned {
+		return fmt.Errorf("revision is not provisioned: provisioning state %s",
+			valueOrEmpty(properties.ProvisioningState))
+	}
+
+	if valueOrEmpty(properties.HealthState) == armappcontainers.RevisionHealthStateUnhealthy {
+		return errors.New("revision health state is Unhealthy")
+	}
+
+	return nil
+}
+
+// revisionFailed reports whether a revision failed to be provisioned or to run, and won't become healthy.
+func revisionFailed(revision *armappcontainers.Revision) bool {
+	properties := revision.Properties
+	if properties == nil {
+		return false
+	}
+
+	provisioningFailed := valueOrEmpty(properties.ProvisioningState) == armappcontainers.RevisionProvisioningStateFailed
+	runningFailed := valueOrEmpty(properties.RunningState) == armappcontainers.RevisionRunningStateFailed
+	return provisioningFailed || runningFailed
+}
+
+// probeRevision sends a request to the health path of a revision, and returns an error unless it responds with a
+// success status.
+func (at *containerAppTarget) probeRevision(
+	ctx context.Context,
+	revision *armappcontainers.Revision,
+	healthPath string,
+) error {
+	if revision.Properties.Fqdn == nil || *revision.Properties.Fqdn == "" {
+		return errors.New("revision has no FQDN to check the health path, ingress must be enabled")
+	}
+
+	return checkHealthPath(ctx, at.httpClient, "https://"+*revision.Properties.Fqdn+healthPath)
+}
+
+func valueOrEmpty[T ~string](value *T) T {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
+
 // Gets endpoint for the container app service
 func (at *containerAppTarget) Endpoints(
 	ctx context.Context,
@@ -448,6 +730,63 @@ func (at *containerAppTarget) validateTargetResource(
 	return nil
 }
 
+// Shell runs a command in a replica of the latest ready revision of the container app, attached to the console. With
+// options.Tty, the console is switched to raw mode so the input is sent to the command as it's typed.
+func (at *containerAppTarget) Shell(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	options *ShellOptions,
+) error {
+	if err := at.validateTargetResource(targetResource); err != nil {
+		return err
+	}
+
+	if isJobResource(targetResource) {
+		return fmt.Errorf(
+			"'%s' is a container app job, which has no running replica to run a command in", targetResource.ResourceName())
+	}
+
+	handles := at.console.Handles()
+	execOptions := &containerapps.ExecOptions{
+		Command:   options.Command,
+		Replica:   options.Instance,
+		Container: options.Container,
+		Stdin:     handles.Stdin,
+		Stdout:    handles.Stdout,
+		Stderr:    handles.Stderr,
+	}
+
+	if options.Tty {
+		if stdout, ok := handles.Stdout.(interface{ Fd() uintptr }); ok {
+			//nolint:gosec // G115: file descriptors fit in int on all supported platforms
+			if width, height, err := term.GetSize(int(stdout.Fd())); err == nil {
+				execOptions.TerminalSize = &containerapps.ExecTerminalSize{Width: width, Height: height}
+			}
+		}
+
+		// Only terminal input is switched to raw mode, so redirected input is sent to the command as is.
+		//nolint:gosec // G115: file descriptors fit in int on all supported platforms
+		if stdin, ok := handles.Stdin.(interface{ Fd() uintptr }); ok && term.IsTerminal(int(stdin.Fd())) {
+			//nolint:gosec // G115: file descriptors fit in int on all supported platforms
+			state, err := term.MakeRaw(int(stdin.Fd()))
+			if err != nil {
+				return fmt.Errorf("switching the console to raw mode: %w", err)
+			}
+			//nolint:gosec // G115: file descriptors fit in int on all supported platforms
+			defer func() { _ = term.Restore(int(stdin.Fd()), state) }()
+		}
+	}
+
+	return at.containerAppService.Exec(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		execOptions,
+	)
+}
+
 // isJobResource returns true when the target resource is a Container App Job.
 func isJobResource(targetResource *environment.TargetResource) bool {
 	return strings.EqualFold(
@@ -5,6 +5,7 @@ package project
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -21,6 +22,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/containerregistry"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
@@ -238,6 +240,50 @@ func Test_ContainerApp_Publish(t *testing.T) {
 	require.Equal(t, "REGISTRY.azurecr.io/test-app/api-test:azd-deploy-0", publishArtifacts[0].Location)
 }
 
+func Test_ContainerApp_Publish_ImageByDigest(t *testing.T) {
+	image := "ghcr.io/contoso/api@sha256:" + strings.Repeat("ab", 32)
+	scope := environment.NewTargetResource(
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"CONTAINER_APP",
+		string(azapi.AzureResourceTypeContainerApp),
+	)
+
+	publish := func(t *testing.T, manifestErr error) (*ServicePublishResult, error) {
+		mockContext := mocks.NewMockContext(t.Context())
+		setupMocksForContainerAppTarget(mockContext)
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "docker manifest inspect "+image)
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			if manifestErr != nil {
+				return exec.NewRunResult(1, "", "manifest unknown"), manifestErr
+			}
+			return exec.NewRunResult(0, "{}", ""), nil
+		})
+
+		serviceConfig := createTestServiceConfig(t.TempDir(), ContainerAppTarget, ServiceLanguageTypeScript)
+		serviceTarget := createContainerAppServiceTarget(mockContext, createEnv())
+
+		serviceContext := NewServiceContext()
+		serviceContext.Package = ArtifactCollection{
+			{Kind: ArtifactKindContainer, Location: image, LocationKind: LocationKindRemote},
+		}
+
+		return logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServicePublishResult, error) {
+			return serviceTarget.Publish(
+				*mockContext.Context, serviceConfig, serviceContext, scope, progress, &PublishOptions{})
+		})
+	}
+
+	publishResult, err := publish(t, nil)
+	require.NoError(t, err)
+	require.Len(t, publishResult.Artifacts, 1)
+	require.Equal(t, image, publishResult.Artifacts[0].Location)
+
+	_, err = publish(t, errors.New("exit code: 1"))
+	require.ErrorContains(t, err, "was not found in registry ghcr.io")
+}
+
 func createContainerAppServiceTarget(
 	mockContext *mocks.MockContext,
 	env *environment.Environment,
@@ -302,6 +348,7 @@ func createContainerAppServiceTarget(
 		deploymentService,
 		mockContext.Console,
 		mockContext.CommandRunner,
+		nil,
 	)
 }
 
@@ -453,3 +500,66 @@ func Test_ContainerAppJob_Deploy(t *testing.T) {
 	endpointArtifacts := deployResult.Artifacts.Find(WithKind(ArtifactKindEndpoint))
 	require.Empty(t, endpointArtifacts)
 }
+
+func Test_ContainerAppJob_Deploy_WithJobOptions(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(t.Context())
+	setupMocksForContainerAppJobs(mockContext)
+
+	// The mocks registered last take precedence over the update mock of setupMocksForContainerAppJobs.
+	job := &armappcontainers.Job{Name: new("CONTAINER_APP_JOB")}
+	mockUpdate := mockazsdk.MockContainerAppJobUpdate(
+		mockContext, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "CONTAINER_APP_JOB", job)
+	mockStart := mockazsdk.MockContainerAppJobStart(
+		mockContext, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "CONTAINER_APP_JOB", "CONTAINER_APP_JOB-abc123")
+	mockazsdk.MockContainerAppJobExecutionGet(
+		mockContext, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "CONTAINER_APP_JOB", &armappcontainers.JobExecution{
+			Name: new("CONTAINER_APP_JOB-abc123"),
+			Properties: &armappcontainers.JobExecutionProperties{
+				Status: new(armappcontainers.JobExecutionRunningStateSucceeded),
+			},
+		})
+
+	serviceConfig := createTestServiceConfig(tempDir, ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.Job = &ContainerAppJobOptions{
+		Trigger:        ContainerAppJobTriggerSchedule,
+		CronExpression: "0 2 * * *",
+		StartExecution: true,
+	}
+	env := createEnv()
+
+	serviceTarget := createContainerAppServiceTarget(mockContext, env)
+
+	scope := environment.NewTargetResource(
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"CONTAINER_APP_JOB",
+		string(azapi.AzureResourceTypeContainerAppJob),
+	)
+
+	_, err := logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			serviceContext := NewServiceContext()
+			serviceContext.Publish = ArtifactCollection{
+				{
+					Kind:         ArtifactKindContainer,
+					Location:     "REGISTRY.azurecr.io/test-app/api-test:azd-deploy-0",
+					LocationKind: LocationKindRemote,
+					Metadata: map[string]string{
+						"remoteImage": "REGISTRY.azurecr.io/test-app/api-test:azd-deploy-0",
+					},
+				},
+			}
+			return serviceTarget.Deploy(*mockContext.Context, serviceConfig, serviceContext, scope, progress)
+		},
+	)
+	require.NoError(t, err)
+
+	var patchBody armappcontainers.JobPatchProperties
+	require.NoError(t, mocks.ReadHttpBody(mockUpdate.Body, &patchBody))
+	require.Equal(t, armappcontainers.TriggerTypeSchedule, *patchBody.Properties.Configuration.TriggerType)
+	require.Equal(t, "0 2 * * *", *patchBody.Properties.Configuration.ScheduleTriggerConfig.CronExpression)
+	require.NotNil(t, mockStart.URL, "an execution of the job is started")
+}
@@ -233,7 +233,9 @@ func (f *functionAppTarget) Deploy(
 		}
 	}
 
-	progress.SetProgress(NewServiceProgress("Uploading deployment package"))
+	if serviceConfig.FunctionApp != nil && !isFlexConsumption {
+		return nil, fmt.Errorf("'functionApp' is only supported for Flex Consumption plan function apps")
+	}
 
 	// Deploy to appropriate plan type
 	if isFlexConsumption {
@@ -242,6 +244,11 @@ func (f *functionAppTarget) Deploy(
 			return nil, buildErr
 		}
 
+		if err := f.configureDeploymentStorage(ctx, serviceConfig, targetResource, progress); err != nil {
+			return nil, err
+		}
+
+		progress.SetProgress(NewServiceProgress("Uploading deployment package"))
 		_, err = f.cli.DeployFunctionAppUsingZipFileFlexConsumption(
 			ctx,
 			targetResource.SubscriptionId(),
@@ -250,7 +257,11 @@ func (f *functionAppTarget) Deploy(
 			zipFile,
 			remoteBuild,
 		)
+		if err == nil {
+			err = f.waitForFunctionHost(ctx, serviceConfig, targetResource, progress)
+		}
 	} else {
+		progress.SetProgress(NewServiceProgress("Uploading deployment package"))
 		_, err = f.cli.DeployFunctionAppUsingZipFileRegular(
 			ctx,
 			targetResource.SubscriptionId(),
@@ -263,6 +274,14 @@ func (f *functionAppTarget) Deploy(
 		return nil, err
 	}
 
+	if err := syncWebAppSettings(ctx, f.cli, f.env, serviceConfig, targetResource, "", progress); err != nil {
+		return nil, fmt.Errorf("syncing app settings: %w", err)
+	}
+
+	if err := checkWebAppKeyVaultReferences(ctx, f.cli, serviceConfig, targetResource, ""); err != nil {
+		return nil, err
+	}
+
 	progress.SetProgress(NewServiceProgress("Fetching endpoints for function app"))
 	endpoints, err := f.Endpoints(ctx, serviceConfig, targetResource)
 	if err != nil {
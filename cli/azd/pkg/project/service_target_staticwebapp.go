@@ -15,6 +15,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/swa"
 )
 
@@ -30,21 +31,27 @@ const DefaultStaticWebAppEnvironmentName = "default"
 const swaCliProductionEnvironment = "production"
 
 type staticWebAppTarget struct {
-	env *environment.Environment
-	cli *azapi.AzureClient
-	swa *swa.Cli
+	env        *environment.Environment
+	envManager environment.Manager
+	cli        *azapi.AzureClient
+	swa        *swa.Cli
+	gitCli     *git.Cli
 }
 
 // NewStaticWebAppTarget creates a new instance of the Static Web App target
 func NewStaticWebAppTarget(
 	env *environment.Environment,
+	envManager environment.Manager,
 	azCli *azapi.AzureClient,
 	swaCli *swa.Cli,
+	gitCli *git.Cli,
 ) ServiceTarget {
 	return &staticWebAppTarget{
-		env: env,
-		cli: azCli,
-		swa: swaCli,
+		env:        env,
+		envManager: envManager,
+		cli:        azCli,
+		swa:        swaCli,
+		gitCli:     gitCli,
 	}
 }
 
@@ -144,6 +151,11 @@ func (at *staticWebAppTarget) Deploy(
 		return nil, fmt.Errorf("validating target resource: %w", err)
 	}
 
+	previewEnvironment, err := at.previewEnvironmentName(ctx, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get the static webapp deployment token
 	progress.SetProgress(NewServiceProgress("Retrieving deployment token"))
 	deploymentToken, err := at.cli.GetStaticWebAppApiKey(
@@ -189,8 +201,13 @@ func (at *staticWebAppTarget) Deploy(
 	// the environment from its own config (respecting the config file as source of truth).
 	// When no config file is present (opinionated mode), pass --env production to fix
 	// the BadRequest that occurred with the old --env default.
+	// A preview environment configured in azure.yaml takes precedence over swa-cli.config.json.
 	swaEnv := ""
-	if !usingSwaConfig(serviceContext.Package) {
+	apiEnvName := DefaultStaticWebAppEnvironmentName
+	if previewEnvironment != "" {
+		swaEnv = previewEnvironment
+		apiEnvName = previewEnvironment
+	} else if !usingSwaConfig(serviceContext.Package) {
 		swaEnv = swaCliProductionEnvironment
 	}
 
@@ -210,12 +227,12 @@ func (at *staticWebAppTarget) Deploy(
 	}
 
 	progress.SetProgress(NewServiceProgress("Verifying deployment"))
-	if err := at.verifyDeployment(ctx, targetResource); err != nil {
+	if err := at.verifyDeployment(ctx, targetResource, apiEnvName); err != nil {
 		return nil, err
 	}
 
 	progress.SetProgress(NewServiceProgress("Fetching endpoints for static web app"))
-	endpoints, err := at.Endpoints(ctx, serviceConfig, targetResource)
+	endpoints, err := at.environmentEndpoints(ctx, targetResource, apiEnvName)
 	if err != nil {
 		return nil, err
 	}
@@ -224,15 +241,34 @@ func (at *staticWebAppTarget) Deploy(
 
 	// Add endpoints as artifacts
 	for _, endpoint := range endpoints {
-		if err := artifacts.Add(&Artifact{
+		artifact := &Artifact{
 			Kind:         ArtifactKindEndpoint,
 			Location:     endpoint,
 			LocationKind: LocationKindRemote,
-		}); err != nil {
+		}
+
+		if previewEnvironment != "" {
+			artifact.Metadata = map[string]string{
+				"label":         "Preview",
+				"discriminator": fmt.Sprintf("(Environment: %s)", previewEnvironment),
+			}
+		}
+
+		if err := artifacts.Add(artifact); err != nil {
 			return nil, fmt.Errorf("failed to add endpoint artifact: %w", err)
 		}
 	}
 
+	// The preview environment is recorded so that 'azd down' deletes it
+	if previewEnvironment != "" && len(endpoints) > 0 {
+		at.env.SetServiceProperty(
+			serviceConfig.Name, previewEnvironmentIdProperty, previewEnvironmentId(targetResource, previewEnvironment))
+		at.env.SetServiceProperty(serviceConfig.Name, previewUrlProperty, endpoints[0])
+		if err := at.envManager.Save(ctx, at.env); err != nil {
+			return nil, fmt.Errorf("failed updating environment with preview environment, %w", err)
+		}
+	}
+
 	// Add resource artifact
 	resourceArtifact := Artifact{}
 	if err := mapper.Convert(targetResource, &resourceArtifact); err == nil {
@@ -252,9 +288,25 @@ func (at *staticWebAppTarget) Endpoints(
 	serviceConfig *ServiceConfig,
 	targetResource *environment.TargetResource,
 ) ([]string, error) {
-	// TODO: Enhance for multi-environment support
-	// https://github.com/Azure/azure-dev/issues/1152
 	apiEnvName := DefaultStaticWebAppEnvironmentName
+	previewEnvironment, err := at.previewEnvironmentName(ctx, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if previewEnvironment != "" {
+		apiEnvName = previewEnvironment
+	}
+
+	return at.environmentEndpoints(ctx, targetResource, apiEnvName)
+}
+
+// environmentEndpoints gets the endpoints of an environment of the static web app
+func (at *staticWebAppTarget) environmentEndpoints(
+	ctx context.Context,
+	targetResource *environment.TargetResource,
+	apiEnvName string,
+) ([]string, error) {
 	if envProps, err := at.cli.GetStaticWebAppEnvironmentProperties(
 		ctx,
 		targetResource.SubscriptionId(),
@@ -285,10 +337,10 @@ func (at *staticWebAppTarget) validateTargetResource(
 func (at *staticWebAppTarget) verifyDeployment(
 	ctx context.Context,
 	targetResource *environment.TargetResource,
+	apiEnvName string,
 ) error {
 	retries := 0
 	const maxRetries = 10
-	apiEnvName := DefaultStaticWebAppEnvironmentName
 
 	for {
 		envProps, err := at.cli.GetStaticWebAppEnvironmentProperties(
@@ -105,12 +105,12 @@ func TestStaticWebAppDeploy_EnvironmentSelection(t *testing.T) {
 
 func Test_NewStaticWebAppTarget(t *testing.T) {
 	env := environment.NewWithValues("test-env", nil)
-	target := NewStaticWebAppTarget(env, nil, nil)
+	target := NewStaticWebAppTarget(env, nil, nil, nil, nil)
 	require.NotNil(t, target)
 }
 
 func Test_staticWebAppTarget_RequiredExternalTools(t *testing.T) {
-	target := NewStaticWebAppTarget(nil, nil, nil)
+	target := NewStaticWebAppTarget(nil, nil, nil, nil, nil)
 	result := target.RequiredExternalTools(t.Context(), nil)
 	require.Len(t, result, 1)
 	// Contains the swa CLI (nil since we passed nil)
@@ -118,13 +118,13 @@ func Test_staticWebAppTarget_RequiredExternalTools(t *testing.T) {
 }
 
 func Test_staticWebAppTarget_Initialize(t *testing.T) {
-	target := NewStaticWebAppTarget(nil, nil, nil)
+	target := NewStaticWebAppTarget(nil, nil, nil, nil, nil)
 	err := target.Initialize(t.Context(), nil)
 	require.NoError(t, err)
 }
 
 func Test_staticWebAppTarget_Publish(t *testing.T) {
-	target := NewStaticWebAppTarget(nil, nil, nil)
+	target := NewStaticWebAppTarget(nil, nil, nil, nil, nil)
 	result, err := target.Publish(t.Context(), nil, nil, nil, nil, nil)
 	require.NoError(t, err)
 	require.NotNil(t, result)
@@ -0,0 +1,189 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// StaticWebAppOptions configures the deployment of a Static Web App service to a named preview environment, instead
+// of the production environment of the app.
+type StaticWebAppOptions struct {
+	// Environment is the name of the preview environment. Supports environment variable substitution, for example
+	// '${AZURE_ENV_NAME}' to deploy each azd environment to its own preview environment.
+	Environment osutil.ExpandableString `yaml:"environment,omitempty"`
+	// EnvironmentFromBranch names the preview environment after the current git branch.
+	EnvironmentFromBranch bool `yaml:"environmentFromBranch,omitempty"`
+}
+
+const (
+	// The service property holding the resource ID of the preview environment deployed from the azd environment
+	previewEnvironmentIdProperty = "PREVIEW_ENVIRONMENT_ID"
+	// The service property holding the URL of the preview environment deployed from the azd environment
+	previewUrlProperty = "PREVIEW_URL"
+)
+
+// Characters that aren't allowed in the name of a preview environment
+var invalidPreviewEnvironmentChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Environment names the SWA CLI or the Azure REST API reserve for the production environment
+var reservedPreviewEnvironmentNames = []string{DefaultStaticWebAppEnvironmentName, swaCliProductionEnvironment, "prod"}
+
+// validateStaticWebAppOptions returns the problems of the Static Web App options of a service.
+func validateStaticWebAppOptions(options *StaticWebAppOptions, host ServiceTargetKind, scope string) []string {
+	if options == nil {
+		return nil
+	}
+
+	var problems []string
+	if host != StaticWebAppTarget {
+		problems = append(problems, fmt.Sprintf(
+			"%s: staticWebApp is only supported for services with host '%s'", scope, StaticWebAppTarget))
+	}
+
+	switch {
+	case options.Environment.Empty() && !options.EnvironmentFromBranch:
+		problems = append(problems, fmt.Sprintf(
+			"%s: staticWebApp requires one of environment or environmentFromBranch", scope))
+	case !options.Environment.Empty() && options.EnvironmentFromBranch:
+		problems = append(problems, fmt.Sprintf(
+			"%s: staticWebApp.environment and staticWebApp.environmentFromBranch cannot be used together", scope))
+	}
+
+	return problems
+}
+
+// previewEnvironmentName returns the name of the preview environment the service is deployed to, or an empty string
+// when the service is deployed to production.
+func (at *staticWebAppTarget) previewEnvironmentName(ctx context.Context, serviceConfig *ServiceConfig) (string, error) {
+	options := serviceConfig.StaticWebApp
+	if options == nil {
+		return "", nil
+	}
+
+	var name string
+	if options.EnvironmentFromBranch {
+		branch, err := at.gitCli.GetCurrentBranch(ctx, serviceConfig.Project.Path)
+		if err != nil {
+			return "", fmt.Errorf("getting the git branch of the preview environment: %w", err)
+		}
+
+		if branch == "" {
+			return "", &internal.ErrorWithSuggestion{
+				Err: errors.New("the preview environment can't be named after the git branch in a detached HEAD"),
+				Suggestion: "Check out a branch, or name the environment from a variable of your CI system, such as " +
+					"'staticWebApp.environment: ${GITHUB_HEAD_REF}'.",
+			}
+		}
+
+		name = branch
+	} else {
+		expanded, err := options.Environment.Envsubst(at.env.Getenv)
+		if err != nil {
+			return "", fmt.Errorf("failed resolving preview environment name: %w", err)
+		}
+
+		name = expanded
+	}
+
+	sanitized := sanitizePreviewEnvironmentName(name)
+	if sanitized == "" {
+		return "", fmt.Errorf("'%s' is not a valid preview environment name", name)
+	}
+
+	if slices.Contains(reservedPreviewEnvironmentNames, sanitized) {
+		return "", fmt.Errorf(
+			"'%s' can't be used as a preview environment name, because it refers to the production environment", name)
+	}
+
+	return sanitized, nil
+}
+
+// sanitizePreviewEnvironmentName lower cases the name and replaces the characters that aren't letters or numbers with
+// hyphens, so that branch names like 'feature/login' can be used as preview environment names.
+func sanitizePreviewEnvironmentName(name string) string {
+	return strings.Trim(invalidPreviewEnvironmentChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// previewEnvironmentId returns the resource ID of a preview environment of a static web app.
+func previewEnvironmentId(targetResource *environment.TargetResource, environmentName string) string {
+	return fmt.Sprintf(
+		"/subscriptions/%s/resourceGroups/%s/providers/%s/%s/builds/%s",
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		azapi.AzureResourceTypeStaticWebSite,
+		targetResource.ResourceName(),
+		environmentName,
+	)
+}
+
+// previewEnvironmentKeys returns the environment keys holding the resource IDs of the Static Web App preview
+// environments deployed from the azd environment.
+func previewEnvironmentKeys(env *environment.Environment) []string {
+	var keys []string
+	for key := range env.Dotenv() {
+		if strings.HasPrefix(key, "SERVICE_") && strings.HasSuffix(key, "_"+previewEnvironmentIdProperty) {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+	return keys
+}
+
+// PreviewEnvironments returns the resource IDs of the Static Web App preview environments deployed from the azd
+// environment.
+func PreviewEnvironments(env *environment.Environment) []string {
+	var ids []string
+	for _, key := range previewEnvironmentKeys(env) {
+		if id := env.Getenv(key); id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// DeletePreviewEnvironments deletes the Static Web App preview environments deployed from the azd environment, and
+// removes them from the environment. Preview environments of static web apps that were already deleted are ignored.
+func DeletePreviewEnvironments(
+	ctx context.Context,
+	azCli *azapi.AzureClient,
+	env *environment.Environment,
+) error {
+	for _, key := range previewEnvironmentKeys(env) {
+		if id := env.Getenv(key); id != "" {
+			resourceId, err := arm.ParseResourceID(id)
+			if err != nil {
+				return fmt.Errorf("'%s' is not the resource ID of a static web app environment: %w", id, err)
+			}
+
+			if err := azCli.DeleteStaticWebAppEnvironment(
+				ctx,
+				resourceId.SubscriptionID,
+				resourceId.ResourceGroupName,
+				resourceId.Parent.Name,
+				resourceId.Name,
+			); err != nil {
+				return err
+			}
+		}
+
+		env.DotenvDelete(key)
+		env.DotenvDelete(strings.TrimSuffix(key, previewEnvironmentIdProperty) + previewUrlProperty)
+	}
+
+	return nil
+}
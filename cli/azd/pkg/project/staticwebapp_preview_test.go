@@ -0,0 +1,194 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/swa"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazapi"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testPreviewEnvironmentId = "/subscriptions/SUB_ID/resourceGroups/RG_ID/providers/Microsoft.Web/staticSites/" +
+	"SWA_NAME/builds/feature-login"
+
+func Test_validateStaticWebAppOptions(t *testing.T) {
+	scope := "service 'web'"
+	require.Nil(t, validateStaticWebAppOptions(nil, StaticWebAppTarget, scope))
+	require.Empty(t, validateStaticWebAppOptions(&StaticWebAppOptions{
+		Environment: osutil.NewExpandableString("${AZURE_ENV_NAME}"),
+	}, StaticWebAppTarget, scope))
+	require.Empty(t, validateStaticWebAppOptions(
+		&StaticWebAppOptions{EnvironmentFromBranch: true}, StaticWebAppTarget, scope))
+
+	problems := validateStaticWebAppOptions(&StaticWebAppOptions{}, AppServiceTarget, scope)
+	require.Len(t, problems, 2)
+	require.Contains(t, problems[0], "staticWebApp is only supported for services with host 'staticwebapp'")
+	require.Contains(t, problems[1], "staticWebApp requires one of environment or environmentFromBranch")
+
+	problems = validateStaticWebAppOptions(&StaticWebAppOptions{
+		Environment:           osutil.NewExpandableString("preview"),
+		EnvironmentFromBranch: true,
+	}, StaticWebAppTarget, scope)
+	require.Len(t, problems, 1)
+	require.Contains(t, problems[0], "cannot be used together")
+}
+
+func Test_staticWebAppTarget_previewEnvironmentName(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	branch := "Feature/Login"
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "git") && slices.Contains(args.Args, "--show-current")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		return exec.NewRunResult(0, branch+"\n", ""), nil
+	})
+
+	target := &staticWebAppTarget{
+		env:    environment.NewWithValues("dev", map[string]string{"AZURE_ENV_NAME": "dev"}),
+		gitCli: git.NewCli(mockContext.CommandRunner),
+	}
+
+	name := func(options *StaticWebAppOptions) (string, error) {
+		serviceConfig := &ServiceConfig{Project: &ProjectConfig{Path: t.TempDir()}, StaticWebApp: options}
+		return target.previewEnvironmentName(*mockContext.Context, serviceConfig)
+	}
+
+	previewName, err := name(nil)
+	require.NoError(t, err)
+	require.Empty(t, previewName)
+
+	previewName, err = name(&StaticWebAppOptions{Environment: osutil.NewExpandableString("pr-${AZURE_ENV_NAME}")})
+	require.NoError(t, err)
+	require.Equal(t, "pr-dev", previewName)
+
+	previewName, err = name(&StaticWebAppOptions{EnvironmentFromBranch: true})
+	require.NoError(t, err)
+	require.Equal(t, "feature-login", previewName)
+
+	_, err = name(&StaticWebAppOptions{Environment: osutil.NewExpandableString("Production")})
+	require.ErrorContains(t, err, "refers to the production environment")
+
+	branch = ""
+	_, err = name(&StaticWebAppOptions{EnvironmentFromBranch: true})
+	require.ErrorContains(t, err, "detached HEAD")
+}
+
+func Test_staticWebAppTarget_Deploy_PreviewEnvironment(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, "/staticSites/SWA_NAME/listSecrets")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		secrets := armappservice.StringDictionary{Properties: map[string]*string{"apiKey": new("TOKEN")}}
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, secrets)
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			strings.HasSuffix(request.URL.Path, "/staticSites/SWA_NAME/builds/feature-login")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		build := armappservice.StaticSiteBuildARMResource{
+			Properties: &armappservice.StaticSiteBuildARMResourceProperties{
+				Hostname: new("swa-name-feature-login.eastus2.azurestaticapps.net"),
+				Status:   new(armappservice.BuildStatusReady),
+			},
+		}
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, build)
+	})
+
+	var swaArgs []string
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return slices.Contains(args.Args, "deploy")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		swaArgs = args.Args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	env := environment.NewWithValues("dev", nil)
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", mock.Anything, env).Return(nil)
+
+	target := &staticWebAppTarget{
+		env:        env,
+		envManager: envManager,
+		cli:        mockazapi.NewAzureClientFromMockContext(mockContext),
+		swa:        swa.NewCli(mockContext.CommandRunner),
+	}
+
+	serviceConfig := &ServiceConfig{
+		Name:         "web",
+		Host:         StaticWebAppTarget,
+		RelativePath: "web",
+		Project:      &ProjectConfig{Path: t.TempDir()},
+		StaticWebApp: &StaticWebAppOptions{Environment: osutil.NewExpandableString("feature/login")},
+	}
+	serviceContext := NewServiceContext()
+	require.NoError(t, serviceContext.Package.Add(&Artifact{
+		Kind:         ArtifactKindDirectory,
+		Location:     "dist",
+		LocationKind: LocationKindLocal,
+	}))
+
+	targetResource := environment.NewTargetResource(
+		"SUB_ID", "RG_ID", "SWA_NAME", string(azapi.AzureResourceTypeStaticWebSite),
+	)
+	result, err := target.Deploy(
+		*mockContext.Context, serviceConfig, serviceContext, targetResource, async.NewNoopProgress[ServiceProgress]())
+	require.NoError(t, err)
+
+	envIndex := slices.Index(swaArgs, "--env")
+	require.NotEqual(t, -1, envIndex)
+	require.Equal(t, "feature-login", swaArgs[envIndex+1])
+
+	endpoint, found := result.Artifacts.FindFirst(WithKind(ArtifactKindEndpoint))
+	require.True(t, found)
+	require.Equal(t, "https://swa-name-feature-login.eastus2.azurestaticapps.net/", endpoint.Location)
+	require.Equal(t, "Preview", endpoint.Metadata["label"])
+
+	require.Equal(t, testPreviewEnvironmentId, env.GetServiceProperty("web", previewEnvironmentIdProperty))
+	require.Equal(t,
+		"https://swa-name-feature-login.eastus2.azurestaticapps.net/", env.GetServiceProperty("web", previewUrlProperty))
+	require.Equal(t, []string{testPreviewEnvironmentId}, PreviewEnvironments(env))
+}
+
+func Test_DeletePreviewEnvironments(t *testing.T) {
+	for _, status := range []int{http.StatusOK, http.StatusNotFound} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			mockContext := mocks.NewMockContext(t.Context())
+			deleted := false
+			mockContext.HttpClient.When(func(request *http.Request) bool {
+				return request.Method == http.MethodDelete &&
+					strings.HasSuffix(request.URL.Path, "/staticSites/SWA_NAME/builds/feature-login")
+			}).RespondFn(func(request *http.Request) (*http.Response, error) {
+				deleted = true
+				return mocks.CreateEmptyHttpResponse(request, status)
+			})
+
+			env := environment.NewWithValues("dev", nil)
+			env.SetServiceProperty("web", previewEnvironmentIdProperty, testPreviewEnvironmentId)
+			env.SetServiceProperty("web", previewUrlProperty, "https://swa-name-feature-login.azurestaticapps.net/")
+
+			err := DeletePreviewEnvironments(
+				*mockContext.Context, mockazapi.NewAzureClientFromMockContext(mockContext), env)
+			require.NoError(t, err)
+			require.True(t, deleted)
+			require.Empty(t, PreviewEnvironments(env))
+			require.Empty(t, env.GetServiceProperty("web", previewUrlProperty))
+		})
+	}
+}
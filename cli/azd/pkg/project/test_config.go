@@ -0,0 +1,83 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/ext"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// DefaultSmokeTestTimeout is how long a URL smoke test keeps retrying when it doesn't set a timeout. Endpoints often
+// take a few minutes to respond after they're deployed.
+const DefaultSmokeTestTimeout = 5 * time.Minute
+
+// TestConfig is the configuration used by `azd template test`, set in the test section of azure.yaml.
+type TestConfig struct {
+	// Smoke are the smoke tests run after the template is provisioned and deployed, in order.
+	Smoke []*SmokeTestConfig `yaml:"smoke,omitempty"`
+}
+
+// SmokeTestConfig is a smoke test. It either requests a URL, or runs a script in the same way as a hook, with the
+// values of the test environment.
+type SmokeTestConfig struct {
+	// Url is requested with GET until it responds with a success status code, or Timeout is reached. Environment
+	// variables such as ${SERVICE_WEB_URI} are expanded.
+	Url osutil.ExpandableString `yaml:"url,omitempty"`
+	// Timeout is how long a URL smoke test keeps retrying, for example "2m". Defaults to DefaultSmokeTestTimeout.
+	Timeout string `yaml:"timeout,omitempty"`
+
+	// HookConfig is the script of the smoke test, and its name.
+	ext.HookConfig `yaml:",inline"`
+}
+
+// IsUrl returns whether the smoke test requests a URL rather than running a script.
+func (s *SmokeTestConfig) IsUrl() bool {
+	return !s.Url.Empty()
+}
+
+// RequestTimeout returns how long a URL smoke test keeps retrying.
+func (s *SmokeTestConfig) RequestTimeout() (time.Duration, error) {
+	if s.Timeout == "" {
+		return DefaultSmokeTestTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(s.Timeout)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("'timeout' must be a positive duration such as '2m', got '%s'", s.Timeout)
+	}
+
+	return timeout, nil
+}
+
+// Validate checks that every smoke test has a name and either a url or a script to run.
+func (c *TestConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	names := map[string]bool{}
+	for i, smoke := range c.Smoke {
+		if smoke == nil || smoke.Name == "" {
+			return fmt.Errorf("smoke test %d must have a name", i+1)
+		}
+
+		if names[smoke.Name] {
+			return fmt.Errorf("smoke test '%s' is declared more than once", smoke.Name)
+		}
+		names[smoke.Name] = true
+
+		if smoke.IsUrl() == (smoke.Run != "") {
+			return fmt.Errorf("smoke test '%s' must set exactly one of 'url' or 'run'", smoke.Name)
+		}
+
+		if _, err := smoke.RequestTimeout(); err != nil {
+			return fmt.Errorf("smoke test '%s': %w", smoke.Name, err)
+		}
+	}
+
+	return nil
+}
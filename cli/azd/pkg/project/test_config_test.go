@@ -0,0 +1,110 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTestConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name: "Valid",
+			yaml: `
+name: test-proj
+test:
+  smoke:
+    - name: web
+      url: ${SERVICE_WEB_URI}/health
+      timeout: 2m
+    - name: api
+      run: ./scripts/smoke.sh
+      kind: sh
+`,
+		},
+		{
+			name: "NoName",
+			yaml: `
+name: test-proj
+test:
+  smoke:
+    - url: https://example.com
+`,
+			wantErr: "smoke test 1 must have a name",
+		},
+		{
+			name: "DuplicateName",
+			yaml: `
+name: test-proj
+test:
+  smoke:
+    - name: web
+      url: https://example.com
+    - name: web
+      run: ./scripts/smoke.sh
+`,
+			wantErr: "smoke test 'web' is declared more than once",
+		},
+		{
+			name: "UrlAndRun",
+			yaml: `
+name: test-proj
+test:
+  smoke:
+    - name: web
+      url: https://example.com
+      run: ./scripts/smoke.sh
+`,
+			wantErr: "must set exactly one of 'url' or 'run'",
+		},
+		{
+			name: "InvalidTimeout",
+			yaml: `
+name: test-proj
+test:
+  smoke:
+    - name: web
+      url: https://example.com
+      timeout: soon
+`,
+			wantErr: "smoke test 'web': 'timeout' must be a positive duration",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectConfig, err := Parse(t.Context(), tt.yaml)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, "parsing test")
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, projectConfig.Test.Smoke, 2)
+
+			web := projectConfig.Test.Smoke[0]
+			require.Equal(t, "web", web.Name)
+			require.True(t, web.IsUrl())
+			timeout, err := web.RequestTimeout()
+			require.NoError(t, err)
+			require.Equal(t, 2*time.Minute, timeout)
+
+			api := projectConfig.Test.Smoke[1]
+			require.Equal(t, "api", api.Name)
+			require.False(t, api.IsUrl())
+			require.Equal(t, "./scripts/smoke.sh", api.Run)
+			timeout, err = api.RequestTimeout()
+			require.NoError(t, err)
+			require.Equal(t, DefaultSmokeTestTimeout, timeout)
+		})
+	}
+}
@@ -41,6 +41,20 @@ func validateParsedConfig(config *ProjectConfig) error {
 		}
 
 		problems = append(problems, validateHooks(svc.Hooks, "service '"+key+"'")...)
+		problems = append(problems, validateOpenApiOptions(svc.OpenApi, "service '"+key+"'")...)
+		problems = append(problems, validateDeploymentStrategyOptions(svc.Rollout, svc.Host, "service '"+key+"'")...)
+		problems = append(problems,
+			validateAppSettingsOptions(svc.AppSettings, svc.Host, svc.Environment, "service '"+key+"'")...)
+		problems = append(problems, validateSecretExpressions(svc, "service '"+key+"'")...)
+		problems = append(problems, validateContainerAppJobOptions(svc.Job, svc.Host, "service '"+key+"'")...)
+		problems = append(problems, validateAppServiceSlotOptions(svc.Slot, svc.Host, "service '"+key+"'")...)
+		problems = append(problems, validateFunctionAppOptions(svc.FunctionApp, svc.Host, "service '"+key+"'")...)
+		problems = append(problems, validateStaticWebAppOptions(svc.StaticWebApp, svc.Host, "service '"+key+"'")...)
+		problems = append(problems, validateHealthCheckOptions(svc.HealthCheck, "service '"+key+"'")...)
+		problems = append(problems, validateBuildpackOptions(svc.Docker, "service '"+key+"'")...)
+		problems = append(problems, validateImageScanOptions(svc.Docker, "service '"+key+"'")...)
+		problems = append(problems, validateImageSigningOptions(svc.Docker, "service '"+key+"'")...)
+		problems = append(problems, validateSbomOptions(svc.Sbom, "service '"+key+"'")...)
 	}
 
 	for key, res := range config.Resources {
@@ -95,7 +95,7 @@ func (p *DefaultPrompter) PromptSubscription(ctx context.Context, msg string) (s
 
 	// Tenant selection: if multiple tenants, prompt user to pick one
 	var selectedTenantId string
-	if !p.console.IsNoPromptMode() {
+	if !p.console.IsNoPromptMode() || input.HasAnswer(p.console, tenantPrompt) {
 		subscriptionInfos, selectedTenantId, err = promptAndFilterByTenant(
 			ctx, p.console, subscriptionInfos, p.accountManager.GetTenantDisplayNames)
 		if err != nil {
@@ -153,9 +153,11 @@ func (p *DefaultPrompter) PromptSubscription(ctx context.Context, msg string) (s
 
 	for subscriptionId == "" {
 		subscriptionSelectionIndex, err := p.console.Select(ctx, input.ConsoleOptions{
+			Key:          "subscription",
 			Message:      msg,
 			Options:      subscriptionOptions,
 			DefaultValue: defaultSubscription,
+			FilterHint:   subscriptionFilterHint,
 		})
 
 		if err != nil {
@@ -181,9 +183,11 @@ func (p *DefaultPrompter) PromptSubscription(ctx context.Context, msg string) (s
 		for subscriptionId == "" {
 			idx, selErr := p.console.Select(
 				ctx, input.ConsoleOptions{
+					Key:          "subscription",
 					Message:      msg,
 					Options:      allOpts,
 					DefaultValue: allDefault,
+					FilterHint:   subscriptionFilterHint,
 				})
 			if selErr != nil {
 				return "", fmt.Errorf(
@@ -203,6 +207,9 @@ func (p *DefaultPrompter) PromptSubscription(ctx context.Context, msg string) (s
 	return subscriptionId, nil
 }
 
+// subscriptionFilterHint is the filter hint of the subscription selection.
+const subscriptionFilterHint = "Type part of a subscription name or ID"
+
 // formatSubscriptionOptions formats subscription infos into display options.
 func formatSubscriptionOptions(
 	subscriptionInfos []account.Subscription,
@@ -314,6 +321,7 @@ func (p *DefaultPrompter) PromptResourceGroupFrom(
 	}
 
 	pickPrompt := input.ConsoleOptions{
+		Key:     "resourceGroup",
 		Message: "Pick a resource group to use:",
 		Options: choices,
 		Help:    options.PickResourceGroupHelp,
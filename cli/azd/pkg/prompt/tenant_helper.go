@@ -125,9 +125,16 @@ func filterByTenantEnvVar(subscriptions []account.Subscription) []account.Subscr
 	return filtered
 }
 
+// tenantPrompt identifies the tenant selection prompt, which answers files answer with the `tenant` key.
+var tenantPrompt = input.ConsoleOptions{
+	Key:     "tenant",
+	Message: "Select a tenant",
+}
+
 // promptTenantSelection prompts the user to select a tenant when multiple tenants are available.
 // Returns the selected tenant ID, or empty string if the user chose "All tenants".
 // If there is only one tenant, it is returned automatically without prompting.
+
 func promptTenantSelection(
 	ctx context.Context,
 	console input.Console,
@@ -152,7 +159,8 @@ func promptTenantSelection(
 	options[len(tenants)] = allTenantsLabel
 
 	selectedIndex, err := console.Select(ctx, input.ConsoleOptions{
-		Message: "Select a tenant",
+		Key:     tenantPrompt.Key,
+		Message: tenantPrompt.Message,
 		Options: options,
 	})
 	if err != nil {
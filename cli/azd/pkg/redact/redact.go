@@ -0,0 +1,160 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package redact keeps track of secret values known to the running azd process (for example secure infrastructure
+// parameters collected via prompts) and removes them from text before it is written to debug logs, trace files or
+// --debug console output.
+package redact
+
+import (
+	"cmp"
+	"encoding/json"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Replacement is the text that replaces registered secret values.
+const Replacement = "<redacted>"
+
+// minValueLength is the shortest value that is redacted. Shorter values are ignored since replacing them would mangle
+// unrelated output (every "a" or "1" in the log) while providing no real protection.
+const minValueLength = 4
+
+var (
+	mu       sync.RWMutex
+	values   = map[string]struct{}{}
+	replacer *strings.Replacer
+)
+
+// Register adds secret values that must never appear in logs. Empty and very short values are ignored.
+func Register(secrets ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	changed := false
+	for _, secret := range secrets {
+		for _, form := range secretForms(secret) {
+			if len(form) < minValueLength {
+				continue
+			}
+
+			if _, has := values[form]; !has {
+				values[form] = struct{}{}
+				changed = true
+			}
+		}
+	}
+
+	if changed {
+		rebuildReplacer()
+	}
+}
+
+// RegisterValue registers every string found in value, which may be a string or a JSON-like object or array (for
+// example the value of a secureObject parameter). The JSON encoding of objects and arrays is registered as well.
+func RegisterValue(value any) {
+	var secrets []string
+	collectStrings(value, &secrets)
+
+	switch value.(type) {
+	case map[string]any, []any:
+		if encoded, err := json.Marshal(value); err == nil {
+			secrets = append(secrets, string(encoded))
+		}
+	}
+
+	Register(secrets...)
+}
+
+// String returns s with every registered secret value replaced by [Replacement].
+func String(s string) string {
+	mu.RLock()
+	r := replacer
+	mu.RUnlock()
+
+	if r == nil {
+		return s
+	}
+
+	return r.Replace(s)
+}
+
+// NewWriter returns a writer that redacts registered secret values before forwarding writes to w. Each call to Write is
+// redacted independently, which matches how the standard logger and the trace exporter write whole entries at once.
+func NewWriter(w io.Writer) io.Writer {
+	return &writer{inner: w}
+}
+
+type writer struct {
+	inner io.Writer
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	redacted := String(string(p))
+	if _, err := io.WriteString(w.inner, redacted); err != nil {
+		return 0, err
+	}
+
+	// Report the length of the original input so callers don't treat the redaction as a short write.
+	return len(p), nil
+}
+
+// secretForms returns the value along with its JSON-escaped form, which is how the value appears in JSON logs and
+// trace files when it contains quotes, backslashes or control characters.
+func secretForms(secret string) []string {
+	forms := []string{secret}
+
+	if encoded, err := json.Marshal(secret); err == nil {
+		if escaped := string(encoded[1 : len(encoded)-1]); escaped != secret {
+			forms = append(forms, escaped)
+		}
+	}
+
+	return forms
+}
+
+func collectStrings(value any, secrets *[]string) {
+	switch v := value.(type) {
+	case string:
+		*secrets = append(*secrets, v)
+	case map[string]any:
+		for _, item := range v {
+			collectStrings(item, secrets)
+		}
+	case []any:
+		for _, item := range v {
+			collectStrings(item, secrets)
+		}
+	}
+}
+
+// rebuildReplacer must be called with mu held for writing.
+func rebuildReplacer() {
+	// strings.Replacer tries the old strings in argument order, so longer values go first to avoid leaving part of a
+	// secret behind when one registered value is a prefix of another.
+	sorted := make([]string, 0, len(values))
+	for value := range values {
+		sorted = append(sorted, value)
+	}
+	slices.SortFunc(sorted, func(a, b string) int {
+		return cmp.Or(cmp.Compare(len(b), len(a)), strings.Compare(a, b))
+	})
+
+	oldnew := make([]string, 0, len(sorted)*2)
+	for _, value := range sorted {
+		oldnew = append(oldnew, value, Replacement)
+	}
+
+	replacer = strings.NewReplacer(oldnew...)
+}
+
+// reset clears all registered values. Used by tests.
+func reset() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	values = map[string]struct{}{}
+	replacer = nil
+}
@@ -0,0 +1,79 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package redact
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestString(t *testing.T) {
+	t.Cleanup(reset)
+
+	require.Equal(t, "nothing registered", String("nothing registered"))
+
+	Register("P@ssw0rd!", "", "abc")
+	Register("P@ssw0rd!-longer")
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "Plain", input: "password is P@ssw0rd!", expected: "password is <redacted>"},
+		{name: "Longest", input: "value=P@ssw0rd!-longer", expected: "value=<redacted>"},
+		{name: "Multiple", input: "P@ssw0rd! P@ssw0rd!", expected: "<redacted> <redacted>"},
+		{name: "ShortIgnored", input: "abc", expected: "abc"},
+		{name: "Unrelated", input: "hello world", expected: "hello world"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, String(tt.input))
+		})
+	}
+}
+
+func TestRegisterJsonEscaped(t *testing.T) {
+	t.Cleanup(reset)
+
+	Register(`se"cret\value`)
+
+	require.Equal(t, `{"password": "<redacted>"}`, String(`{"password": "se\"cret\\value"}`))
+	require.Equal(t, "raw <redacted>", String(`raw se"cret\value`))
+}
+
+func TestRegisterValue(t *testing.T) {
+	t.Cleanup(reset)
+
+	RegisterValue(map[string]any{
+		"user":     "admin-user",
+		"password": "hunter22",
+		"ports":    []any{"8080", float64(443)},
+		"enabled":  true,
+	})
+
+	require.Equal(t, "<redacted>:<redacted>@<redacted>", String("admin-user:hunter22@8080"))
+	require.Equal(t, "443 true", String("443 true"))
+	require.Equal(t, "<redacted>", String(`{"enabled":true,"password":"hunter22","ports":["8080",443],"user":"admin-user"}`))
+}
+
+func TestNewWriter(t *testing.T) {
+	t.Cleanup(reset)
+
+	Register("super-secret")
+
+	var buf bytes.Buffer
+	logger := log.New(NewWriter(&buf), "", 0)
+	logger.Printf("deploying with password super-secret")
+
+	require.Equal(t, "deploying with password <redacted>\n", buf.String())
+
+	n, err := NewWriter(&buf).Write([]byte("super-secret"))
+	require.NoError(t, err)
+	require.Equal(t, len("super-secret"), n)
+}
@@ -0,0 +1,175 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package servicelogs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+)
+
+// defaultFunctionAppSince is how far back the traces of a function app are queried when Options.Since isn't set,
+// since Application Insights keeps them for much longer than a log stream.
+const defaultFunctionAppSince = time.Hour
+
+// appInsightsConnectionStringSetting is the app setting with the Application Insights connection string of an app.
+const appInsightsConnectionStringSetting = "APPLICATIONINSIGHTS_CONNECTION_STRING"
+
+// appInsightsQueryResponse is the response of the query API of Application Insights.
+type appInsightsQueryResponse struct {
+	Tables []struct {
+		Rows [][]any `json:"rows"`
+	} `json:"tables"`
+}
+
+// streamFunctionApp writes the traces and the exceptions of a function app from the Application Insights resource of
+// its connection string. With Options.Follow, new ones are queried every poll interval. Traces take a few minutes to
+// be available in Application Insights, so they aren't as recent as a log stream.
+func (s *Streamer) streamFunctionApp(
+	ctx context.Context,
+	client *armappservice.WebAppsClient,
+	target *environment.TargetResource,
+	options Options,
+	write func(line string),
+) error {
+	settings, err := client.ListApplicationSettings(ctx, target.ResourceGroupName(), target.ResourceName(), nil)
+	if err != nil {
+		return fmt.Errorf("listing app settings of function app %s: %w", target.ResourceName(), err)
+	}
+
+	applicationId := ""
+	if connectionString, has := settings.Properties[appInsightsConnectionStringSetting]; has && connectionString != nil {
+		applicationId = connectionStringValue(*connectionString, "ApplicationId")
+	}
+	if applicationId == "" {
+		return &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf(
+				"function app %s has no Application Insights resource to read the logs from", target.ResourceName()),
+			Suggestion: fmt.Sprintf(
+				"Set the %s app setting of the function app to the connection string of an Application "+
+					"Insights resource, including its ApplicationId.",
+				appInsightsConnectionStringSetting),
+		}
+	}
+
+	clientOptions := &policy.ClientOptions{}
+	if s.clientOpts != nil {
+		clientOptions = &s.clientOpts.ClientOptions
+	}
+	pipeline := runtime.NewPipeline("servicelogs", "1.0.0", runtime.PipelineOptions{
+		PerRetry: []policy.Policy{
+			runtime.NewBearerTokenPolicy(
+				s.credential, []string{s.cloud.ApplicationInsightsEndpoint + "/.default"}, nil),
+		},
+	}, clientOptions)
+	endpoint := fmt.Sprintf("%s/v1/apps/%s/query", s.cloud.ApplicationInsightsEndpoint, url.PathEscape(applicationId))
+
+	since := options.since(time.Now())
+	if since.IsZero() {
+		since = time.Now().Add(-defaultFunctionAppSince)
+	}
+
+	for {
+		last, err := queryTraces(ctx, pipeline, endpoint, target.ResourceName(), since, write)
+		if err != nil {
+			return fmt.Errorf("querying Application Insights traces of function app %s: %w", target.ResourceName(), err)
+		}
+		if !last.IsZero() {
+			since = last
+		}
+
+		if !options.Follow {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.pollInterval):
+		}
+	}
+}
+
+// queryTraces writes the traces and exceptions of the app after since, in order, and returns the time of the last
+// one, or the zero time when there are none.
+func queryTraces(
+	ctx context.Context,
+	pipeline runtime.Pipeline,
+	endpoint string,
+	appName string,
+	since time.Time,
+	write func(line string),
+) (time.Time, error) {
+	query := fmt.Sprintf(
+		"union traces, exceptions"+
+			" | where timestamp > datetime(%s)"+
+			" | where cloud_RoleName =~ '%s'"+
+			" | project timestamp, message = iff(itemType == 'exception', strcat(type, ': ', outerMessage), message)"+
+			" | order by timestamp asc",
+		since.UTC().Format(time.RFC3339Nano),
+		strings.ReplaceAll(appName, "'", "\\'"))
+
+	req, err := runtime.NewRequest(ctx, http.MethodPost, endpoint)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("creating query request: %w", err)
+	}
+	if err := runtime.MarshalAsJSON(req, map[string]string{"query": query}); err != nil {
+		return time.Time{}, err
+	}
+
+	res, err := pipeline.Do(req)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if !runtime.HasStatusCode(res, http.StatusOK) {
+		return time.Time{}, runtime.NewResponseError(res)
+	}
+
+	var result appInsightsQueryResponse
+	if err := runtime.UnmarshalAsJSON(res, &result); err != nil {
+		return time.Time{}, err
+	}
+
+	var last time.Time
+	for _, table := range result.Tables {
+		for _, row := range table.Rows {
+			if len(row) < 2 {
+				continue
+			}
+
+			timestamp, _ := row[0].(string)
+			message, _ := row[1].(string)
+			if parsed, err := time.Parse(time.RFC3339Nano, timestamp); err == nil && parsed.After(last) {
+				last = parsed
+			}
+
+			write(timestamp + " " + strings.TrimRight(message, "\r\n"))
+		}
+	}
+
+	return last, nil
+}
+
+// connectionStringValue returns the value of a key of a connection string, such as an Application Insights
+// connection string.
+func connectionStringValue(connectionString string, key string) string {
+	for part := range strings.SplitSeq(connectionString, ";") {
+		name, value, found := strings.Cut(part, "=")
+		if found && strings.EqualFold(strings.TrimSpace(name), key) {
+			return strings.TrimSpace(value)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,97 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package servicelogs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+)
+
+// appServiceTimestampLayouts are the layouts of the timestamps that start the lines of the log stream of an App
+// Service.
+var appServiceTimestampLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02 15:04:05"}
+
+// streamAppService streams the log stream of an App Service, from its SCM site. Without Options.Follow, the stream is
+// read until it's idle, since the SCM site keeps it open.
+func (s *Streamer) streamAppService(
+	ctx context.Context,
+	site *armappservice.Site,
+	options Options,
+	write func(line string),
+) error {
+	scmHost := ""
+	if site.Properties != nil {
+		for _, state := range site.Properties.HostNameSSLStates {
+			if state.HostType != nil && *state.HostType == armappservice.HostTypeRepository && state.Name != nil {
+				scmHost = *state.Name
+				break
+			}
+		}
+	}
+	if scmHost == "" {
+		return fmt.Errorf("web app %s has no SCM site to stream the logs from", *site.Name)
+	}
+
+	scmOptions := &arm.ClientOptions{}
+	if s.clientOpts != nil {
+		optionsCopy := *s.clientOpts
+		scmOptions = &optionsCopy
+	}
+	scmOptions.DisableRPRegistration = true
+
+	pipeline, err := armruntime.NewPipeline("servicelogs", "1.0.0", s.credential, runtime.PipelineOptions{}, scmOptions)
+	if err != nil {
+		return fmt.Errorf("creating HTTP pipeline: %w", err)
+	}
+
+	body, err := openStream(ctx, pipeline, fmt.Sprintf("https://%s/api/logstream", scmHost), nil)
+	if err != nil {
+		return fmt.Errorf("opening log stream of web app %s: %w", *site.Name, err)
+	}
+
+	idleTimeout := s.idleTimeout
+	if options.Follow {
+		idleTimeout = 0
+	}
+
+	since := options.since(time.Now())
+	// Lines without a timestamp, such as the lines of a stack trace, are kept with the line before them.
+	keep := true
+	return readLines(ctx, body, idleTimeout, func(text string) {
+		if !since.IsZero() {
+			if timestamp, ok := parseLineTimestamp(text); ok {
+				keep = !timestamp.Before(since)
+			}
+		}
+
+		if keep {
+			write(text)
+		}
+	})
+}
+
+// parseLineTimestamp parses the timestamp that starts a log line. Timestamps without a time zone are in UTC.
+func parseLineTimestamp(line string) (time.Time, bool) {
+	for _, layout := range appServiceTimestampLayouts {
+		value := line
+		if !strings.Contains(layout, " ") {
+			value, _, _ = strings.Cut(line, " ")
+		} else if len(line) >= len(layout) {
+			value = line[:len(layout)]
+		}
+
+		if timestamp, err := time.Parse(layout, value); err == nil {
+			return timestamp, true
+		}
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,152 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package servicelogs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+)
+
+// containerAppTailLines is the number of recent lines returned by the log stream of a container app, which is also
+// the most it supports.
+const containerAppTailLines = 300
+
+// containerAppLogLine is a line of the log stream of a container app, requested with output=json.
+type containerAppLogLine struct {
+	TimeStamp string `json:"TimeStamp"`
+	Log       string `json:"Log"`
+}
+
+// eventStreamBaseUrl returns the scheme and the host of the event stream endpoint of a container app, which also
+// serves its log stream. The endpoint includes the path of the event stream of the app, which isn't used.
+func eventStreamBaseUrl(eventStreamEndpoint string) string {
+	if index := strings.Index(eventStreamEndpoint, "/subscriptions/"); index >= 0 {
+		return eventStreamEndpoint[:index]
+	}
+
+	return strings.TrimSuffix(eventStreamEndpoint, "/")
+}
+
+// streamContainerApp streams the console logs of a container of the first replica of the latest ready revision of a
+// container app. The container named after the app, or else the first container, is streamed.
+func (s *Streamer) streamContainerApp(
+	ctx context.Context,
+	target *environment.TargetResource,
+	options Options,
+	write func(line string),
+) error {
+	client, err := armappcontainers.NewContainerAppsClient(target.SubscriptionId(), s.credential, s.clientOpts)
+	if err != nil {
+		return fmt.Errorf("creating container-apps client: %w", err)
+	}
+
+	app, err := client.Get(ctx, target.ResourceGroupName(), target.ResourceName(), nil)
+	if err != nil {
+		return fmt.Errorf("getting container app: %w", err)
+	}
+
+	if app.Properties == nil || app.Properties.EventStreamEndpoint == nil ||
+		app.Properties.LatestReadyRevisionName == nil || *app.Properties.LatestReadyRevisionName == "" {
+		return fmt.Errorf("container app %s has no ready revision to stream the logs of", target.ResourceName())
+	}
+	revisionName := *app.Properties.LatestReadyRevisionName
+
+	replicasClient, err := armappcontainers.NewContainerAppsRevisionReplicasClient(
+		target.SubscriptionId(), s.credential, s.clientOpts)
+	if err != nil {
+		return fmt.Errorf("creating container-apps replicas client: %w", err)
+	}
+
+	replicas, err := replicasClient.ListReplicas(ctx, target.ResourceGroupName(), target.ResourceName(), revisionName, nil)
+	if err != nil {
+		return fmt.Errorf("listing replicas of revision %s: %w", revisionName, err)
+	}
+
+	if len(replicas.Value) == 0 || replicas.Value[0].Name == nil {
+		return fmt.Errorf(
+			"revision %s of container app %s has no running replica, it may be scaled to zero",
+			revisionName, target.ResourceName())
+	}
+	replica := replicas.Value[0]
+
+	containerName := ""
+	if replica.Properties != nil {
+		for _, container := range replica.Properties.Containers {
+			if container.Name == nil {
+				continue
+			}
+			if containerName == "" || strings.EqualFold(*container.Name, target.ResourceName()) {
+				containerName = *container.Name
+			}
+		}
+	}
+	if containerName == "" {
+		return fmt.Errorf("replica %s of container app %s has no containers", *replica.Name, target.ResourceName())
+	}
+
+	token, err := client.GetAuthToken(ctx, target.ResourceGroupName(), target.ResourceName(), nil)
+	if err != nil {
+		return fmt.Errorf("getting log stream token: %w", err)
+	}
+	if token.Properties == nil || token.Properties.Token == nil {
+		return fmt.Errorf("container app %s didn't return a log stream token", target.ResourceName())
+	}
+
+	query := url.Values{}
+	query.Set("follow", strconv.FormatBool(options.Follow))
+	query.Set("tailLines", strconv.Itoa(containerAppTailLines))
+	query.Set("output", "json")
+	endpoint := fmt.Sprintf(
+		"%s/subscriptions/%s/resourceGroups/%s/containerApps/%s/revisions/%s/replicas/%s/containers/%s/logstream?%s",
+		eventStreamBaseUrl(*app.Properties.EventStreamEndpoint),
+		url.PathEscape(target.SubscriptionId()),
+		url.PathEscape(target.ResourceGroupName()),
+		url.PathEscape(target.ResourceName()),
+		url.PathEscape(revisionName),
+		url.PathEscape(*replica.Name),
+		url.PathEscape(containerName),
+		query.Encode())
+
+	// The log stream authenticates with the token of the app, not with an Entra ID token.
+	clientOptions := &policy.ClientOptions{}
+	if s.clientOpts != nil {
+		clientOptions = &s.clientOpts.ClientOptions
+	}
+	pipeline := runtime.NewPipeline("servicelogs", "1.0.0", runtime.PipelineOptions{}, clientOptions)
+
+	body, err := openStream(ctx, pipeline, endpoint, http.Header{
+		"Authorization": []string{"Bearer " + *token.Properties.Token},
+	})
+	if err != nil {
+		return fmt.Errorf("opening log stream of container app %s: %w", target.ResourceName(), err)
+	}
+
+	since := options.since(time.Now())
+	return readLines(ctx, body, 0, func(text string) {
+		var line containerAppLogLine
+		if err := json.Unmarshal([]byte(text), &line); err != nil || line.TimeStamp == "" {
+			write(text)
+			return
+		}
+
+		if !since.IsZero() {
+			if timestamp, err := time.Parse(time.RFC3339Nano, line.TimeStamp); err == nil && timestamp.Before(since) {
+				return
+			}
+		}
+
+		write(line.TimeStamp + " " + strings.TrimRight(line.Log, "\r\n"))
+	})
+}
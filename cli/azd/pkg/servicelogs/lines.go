@@ -0,0 +1,97 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package servicelogs
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// maxLineSize is the longest log line that's read from a log stream.
+const maxLineSize = 1024 * 1024
+
+// openStream sends a GET request for a log stream, and returns the body of the response without reading it.
+func openStream(ctx context.Context, pipeline runtime.Pipeline, endpoint string, header http.Header) (io.ReadCloser, error) {
+	req, err := runtime.NewRequest(ctx, http.MethodGet, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("creating log stream request: %w", err)
+	}
+
+	for name, values := range header {
+		req.Raw().Header[name] = values
+	}
+	runtime.SkipBodyDownload(req)
+
+	res, err := pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !runtime.HasStatusCode(res, http.StatusOK) {
+		defer res.Body.Close()
+		return nil, runtime.NewResponseError(res)
+	}
+
+	return res.Body, nil
+}
+
+// readLines calls line with each line of body until body ends or ctx is done. With a non-zero idleTimeout, it also
+// returns once no line was read for idleTimeout, for streams that are kept open by the server. body is closed when
+// readLines returns.
+func readLines(ctx context.Context, body io.ReadCloser, idleTimeout time.Duration, line func(string)) error {
+	defer body.Close()
+
+	lines := make(chan string)
+	done := make(chan struct{})
+	defer close(done)
+
+	var scanErr error
+	go func() {
+		defer close(lines)
+
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-done:
+				return
+			}
+		}
+		scanErr = scanner.Err()
+	}()
+
+	var idle <-chan time.Time
+	for {
+		if idleTimeout > 0 {
+			idle = time.After(idleTimeout)
+		}
+
+		select {
+		case text, ok := <-lines:
+			if !ok {
+				// The server closes the stream when ctx is done, which isn't a failure to read it.
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				if scanErr != nil && !errors.Is(scanErr, io.ErrUnexpectedEOF) {
+					return fmt.Errorf("reading log stream: %w", scanErr)
+				}
+				return nil
+			}
+			line(text)
+		case <-idle:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package servicelogs streams the logs of the resources that host the services of a project.
+package servicelogs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+)
+
+// ErrUnsupportedResource is returned when the logs of a resource type can't be streamed.
+var ErrUnsupportedResource = errors.New("streaming the logs of this resource type isn't supported")
+
+// Options configure the logs that are streamed.
+type Options struct {
+	// Since only streams the logs written in this duration before now. Zero streams the recent logs kept by the source.
+	Since time.Duration
+	// Follow keeps streaming new logs until the context is done.
+	Follow bool
+}
+
+// Streamer streams the logs of the resource of a service.
+type Streamer struct {
+	credential azcore.TokenCredential
+	cloud      *cloud.Cloud
+	clientOpts *arm.ClientOptions
+	// idleTimeout is how long a log stream without --follow is read once it stops receiving lines.
+	idleTimeout time.Duration
+	// pollInterval is how often the traces of a function app are queried with --follow.
+	pollInterval time.Duration
+}
+
+// NewStreamer creates a Streamer that authenticates with credential.
+func NewStreamer(credential azcore.TokenCredential, cloud *cloud.Cloud, clientOpts *arm.ClientOptions) *Streamer {
+	return &Streamer{
+		credential:   credential,
+		cloud:        cloud,
+		clientOpts:   clientOpts,
+		idleTimeout:  5 * time.Second,
+		pollInterval: 10 * time.Second,
+	}
+}
+
+// Stream writes the log lines of the target resource to write, in the order they're received:
+//   - Container apps: the console logs of the latest ready revision.
+//   - App Service: the application and web server logs, from the log stream of the app.
+//   - Function apps: the traces and exceptions of the app in Application Insights.
+//
+// Without Options.Follow, Stream returns once the recent logs are written.
+func (s *Streamer) Stream(
+	ctx context.Context,
+	target *environment.TargetResource,
+	options Options,
+	write func(line string),
+) error {
+	switch azapi.AzureResourceType(target.ResourceType()) {
+	case azapi.AzureResourceTypeContainerApp:
+		return s.streamContainerApp(ctx, target, options, write)
+	case azapi.AzureResourceTypeWebSite:
+		client, err := armappservice.NewWebAppsClient(target.SubscriptionId(), s.credential, s.clientOpts)
+		if err != nil {
+			return fmt.Errorf("creating web-apps client: %w", err)
+		}
+
+		site, err := client.Get(ctx, target.ResourceGroupName(), target.ResourceName(), nil)
+		if err != nil {
+			return fmt.Errorf("getting web app: %w", err)
+		}
+
+		if site.Kind != nil && strings.Contains(strings.ToLower(*site.Kind), "functionapp") {
+			return s.streamFunctionApp(ctx, client, target, options, write)
+		}
+
+		return s.streamAppService(ctx, &site.Site, options, write)
+	default:
+		return fmt.Errorf("%s is a %s: %w", target.ResourceName(), target.ResourceType(), ErrUnsupportedResource)
+	}
+}
+
+// since returns the time from which logs are streamed, or the zero time when all recent logs are streamed.
+func (o Options) since(now time.Time) time.Time {
+	if o.Since <= 0 {
+		return time.Time{}
+	}
+
+	return now.Add(-o.Since)
+}
@@ -0,0 +1,229 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package servicelogs
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStreamer(mockContext *mocks.MockContext) *Streamer {
+	streamer := NewStreamer(mockContext.Credentials, cloud.AzurePublic(), mockContext.ArmClientOptions)
+	streamer.idleTimeout = 100 * time.Millisecond
+	return streamer
+}
+
+func textResponse(req *http.Request, body string) *http.Response {
+	return &http.Response{
+		Request:    req,
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestStream_ContainerApp(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	mockContext.HttpClient.When(func(req *http.Request) bool {
+		return req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "/containerApps/api")
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(req, http.StatusOK, armappcontainers.ContainerApp{
+			Properties: &armappcontainers.ContainerAppProperties{
+				EventStreamEndpoint: new("https://eastus.azurecontainerapps.dev/subscriptions/SUB_ID" +
+					"/resourceGroups/RG_ID/containerApps/api/eventstream"),
+				LatestReadyRevisionName: new("api--rev2"),
+			},
+		})
+	})
+	mockContext.HttpClient.When(func(req *http.Request) bool {
+		return req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "/revisions/api--rev2/replicas")
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(req, http.StatusOK, armappcontainers.ReplicaCollection{
+			Value: []*armappcontainers.Replica{{
+				Name: new("api--rev2-abc"),
+				Properties: &armappcontainers.ReplicaProperties{
+					Containers: []*armappcontainers.ReplicaContainer{{Name: new("sidecar")}, {Name: new("api")}},
+				},
+			}},
+		})
+	})
+	mockContext.HttpClient.When(func(req *http.Request) bool {
+		return req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/getAuthtoken")
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(req, http.StatusOK, armappcontainers.ContainerAppAuthToken{
+			Properties: &armappcontainers.ContainerAppAuthTokenProperties{Token: new("APP_TOKEN")},
+		})
+	})
+
+	var logStreamRequest *http.Request
+	mockContext.HttpClient.When(func(req *http.Request) bool {
+		return req.URL.Host == "eastus.azurecontainerapps.dev"
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		logStreamRequest = req
+		old, _ := json.Marshal(containerAppLogLine{
+			TimeStamp: time.Now().Add(-2 * time.Hour).Format(time.RFC3339Nano), Log: "old"})
+		recent, _ := json.Marshal(containerAppLogLine{TimeStamp: time.Now().Format(time.RFC3339Nano), Log: "recent\n"})
+		return textResponse(req, string(old)+"\n"+string(recent)+"\n"), nil
+	})
+
+	target := environment.NewTargetResource(
+		"SUB_ID", "RG_ID", "api", string(azapi.AzureResourceTypeContainerApp))
+
+	var lines []string
+	err := newTestStreamer(mockContext).Stream(
+		*mockContext.Context, target, Options{Since: time.Hour}, func(line string) { lines = append(lines, line) })
+	require.NoError(t, err)
+
+	require.NotNil(t, logStreamRequest)
+	require.Equal(t,
+		"/subscriptions/SUB_ID/resourceGroups/RG_ID/containerApps/api/revisions/api--rev2/replicas/api--rev2-abc"+
+			"/containers/api/logstream",
+		logStreamRequest.URL.Path)
+	require.Equal(t, "false", logStreamRequest.URL.Query().Get("follow"))
+	require.Equal(t, "Bearer APP_TOKEN", logStreamRequest.Header.Get("Authorization"))
+
+	require.Len(t, lines, 1)
+	require.True(t, strings.HasSuffix(lines[0], " recent"))
+}
+
+func TestStream_AppService(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	mockContext.HttpClient.When(func(req *http.Request) bool {
+		return req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "/sites/web")
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(req, http.StatusOK, armappservice.Site{
+			Name: new("web"),
+			Kind: new("app,linux"),
+			Properties: &armappservice.SiteProperties{
+				HostNameSSLStates: []*armappservice.HostNameSSLState{
+					{Name: new("web.azurewebsites.net"), HostType: new(armappservice.HostTypeStandard)},
+					{Name: new("web.scm.azurewebsites.net"), HostType: new(armappservice.HostTypeRepository)},
+				},
+			},
+		})
+	})
+
+	now := time.Now().UTC()
+	mockContext.HttpClient.When(func(req *http.Request) bool {
+		return req.URL.Host == "web.scm.azurewebsites.net" && req.URL.Path == "/api/logstream"
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		return textResponse(req, strings.Join([]string{
+			now.Add(-2*time.Hour).Format("2006-01-02T15:04:05") + "  old",
+			"  at old stack frame",
+			now.Format(time.RFC3339Nano) + " recent",
+			"  at recent stack frame",
+		}, "\n")), nil
+	})
+
+	target := environment.NewTargetResource("SUB_ID", "RG_ID", "web", string(azapi.AzureResourceTypeWebSite))
+
+	var lines []string
+	err := newTestStreamer(mockContext).Stream(
+		*mockContext.Context, target, Options{Since: time.Hour}, func(line string) { lines = append(lines, line) })
+	require.NoError(t, err)
+	require.Len(t, lines, 2)
+	require.True(t, strings.HasSuffix(lines[0], " recent"))
+	require.Equal(t, "  at recent stack frame", lines[1])
+}
+
+func TestStream_FunctionApp(t *testing.T) {
+	mockFunctionApp := func(t *testing.T, connectionString string) *mocks.MockContext {
+		mockContext := mocks.NewMockContext(t.Context())
+		mockContext.HttpClient.When(func(req *http.Request) bool {
+			return req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "/sites/func")
+		}).RespondFn(func(req *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(req, http.StatusOK, armappservice.Site{
+				Name: new("func"),
+				Kind: new("functionapp,linux"),
+			})
+		})
+		mockContext.HttpClient.When(func(req *http.Request) bool {
+			return req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/sites/func/config/appsettings/list")
+		}).RespondFn(func(req *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(req, http.StatusOK, armappservice.StringDictionary{
+				Properties: map[string]*string{appInsightsConnectionStringSetting: &connectionString},
+			})
+		})
+		return mockContext
+	}
+	target := environment.NewTargetResource("SUB_ID", "RG_ID", "func", string(azapi.AzureResourceTypeWebSite))
+
+	t.Run("Traces", func(t *testing.T) {
+		mockContext := mockFunctionApp(t, "InstrumentationKey=KEY;ApplicationId=APP_ID")
+
+		var query string
+		mockContext.HttpClient.When(func(req *http.Request) bool {
+			return req.URL.Host == "api.applicationinsights.io" && req.URL.Path == "/v1/apps/APP_ID/query"
+		}).RespondFn(func(req *http.Request) (*http.Response, error) {
+			var body map[string]string
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				return nil, err
+			}
+			query = body["query"]
+
+			return mocks.CreateHttpResponseWithBody(req, http.StatusOK, map[string]any{
+				"tables": []any{map[string]any{
+					"rows": [][]any{
+						{"2024-01-02T15:04:05Z", "Executing 'Functions.Http'"},
+						{"2024-01-02T15:04:06Z", "System.Exception: boom"},
+					},
+				}},
+			})
+		})
+
+		var lines []string
+		err := newTestStreamer(mockContext).Stream(
+			*mockContext.Context, target, Options{}, func(line string) { lines = append(lines, line) })
+		require.NoError(t, err)
+		require.Contains(t, query, "where cloud_RoleName =~ 'func'")
+		require.Equal(t, []string{
+			"2024-01-02T15:04:05Z Executing 'Functions.Http'",
+			"2024-01-02T15:04:06Z System.Exception: boom",
+		}, lines)
+	})
+
+	t.Run("NoApplicationInsights", func(t *testing.T) {
+		mockContext := mockFunctionApp(t, "InstrumentationKey=KEY")
+
+		err := newTestStreamer(mockContext).Stream(*mockContext.Context, target, Options{}, func(string) {})
+		_, ok := errors.AsType[*internal.ErrorWithSuggestion](err)
+		require.True(t, ok)
+		require.ErrorContains(t, err, "function app func has no Application Insights resource")
+	})
+}
+
+func TestStream_UnsupportedResource(t *testing.T) {
+	target := environment.NewTargetResource(
+		"SUB_ID", "RG_ID", "swa", string(azapi.AzureResourceTypeStaticWebSite))
+
+	err := NewStreamer(nil, cloud.AzurePublic(), nil).Stream(t.Context(), target, Options{}, func(string) {})
+	require.ErrorIs(t, err, ErrUnsupportedResource)
+}
+
+func Test_parseLineTimestamp(t *testing.T) {
+	timestamp, ok := parseLineTimestamp("2024-01-02T15:04:05.1234567Z  Application started")
+	require.True(t, ok)
+	require.Equal(t, time.Date(2024, 1, 2, 15, 4, 5, 123456700, time.UTC), timestamp)
+
+	timestamp, ok = parseLineTimestamp("2024-01-02 15:04:05 GET /health 200")
+	require.True(t, ok)
+	require.Equal(t, time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC), timestamp)
+
+	_, ok = parseLineTimestamp("   at Program.Main()")
+	require.False(t, ok)
+}
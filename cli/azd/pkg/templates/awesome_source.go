@@ -77,6 +77,8 @@ func newAwesomeAzdTemplateSource(
 			Description:    template.Description,
 			RepositoryPath: repoPath,
 			Tags:           append(append(template.Tags, template.AzureServiceTags...), template.LanguageTags...),
+			Languages:      template.LanguageTags,
+			AzureServices:  template.AzureServiceTags,
 		})
 	}
 
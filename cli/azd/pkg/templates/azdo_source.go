@@ -0,0 +1,147 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package templates
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// azdoScope is the scope of the tokens used to access Azure DevOps.
+const azdoScope = "499b84ac-1321-427f-aa17-267ca6975798/.default"
+
+// azdoFile is a file of an Azure DevOps git repository.
+type azdoFile struct {
+	// OrganizationUrl is the url of the organization, like https://dev.azure.com/<org>.
+	OrganizationUrl string
+	Project         string
+	Repository      string
+	Path            string
+	// Version is the branch, tag or commit of the file. The default branch is used when empty.
+	Version     string
+	VersionType string
+}
+
+// parseAzdoUrl parses the url of a file of an Azure DevOps git repository, as shown when browsing the file:
+//   - https://dev.azure.com/<org>/<project>/_git/<repo>?path=<path>[&version=GB<branch>]
+//   - https://<org>.visualstudio.com/<project>/_git/<repo>?path=<path>[&version=GB<branch>]
+//
+// Versions are prefixed with GB for branches, GT for tags and GC for commits.
+func parseAzdoUrl(location string) (*azdoFile, error) {
+	const format = "expected the form of 'https://dev.azure.com/<org>/<project>/_git/<repo>?path=<path>'"
+
+	parsed, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	host := strings.ToLower(parsed.Host)
+
+	var file azdoFile
+	switch {
+	case host == "dev.azure.com" && len(parts) == 4 && parts[2] == "_git":
+		file.OrganizationUrl = fmt.Sprintf("https://%s/%s", host, parts[0])
+		parts = parts[1:]
+	case strings.HasSuffix(host, ".visualstudio.com") && len(parts) == 3 && parts[1] == "_git":
+		file.OrganizationUrl = fmt.Sprintf("https://%s", host)
+	default:
+		return nil, fmt.Errorf("invalid Azure DevOps URL '%s', %s", location, format)
+	}
+
+	file.Project = parts[0]
+	file.Repository = parts[2]
+
+	query := parsed.Query()
+	file.Path = query.Get("path")
+	if file.Path == "" {
+		return nil, fmt.Errorf("invalid Azure DevOps URL '%s', the path of the file is missing, %s", location, format)
+	}
+
+	if version := query.Get("version"); version != "" {
+		if len(version) < 3 {
+			return nil, fmt.Errorf("invalid version '%s' in Azure DevOps URL '%s'", version, location)
+		}
+
+		switch strings.ToUpper(version[:2]) {
+		case "GB":
+			file.VersionType = "branch"
+		case "GT":
+			file.VersionType = "tag"
+		case "GC":
+			file.VersionType = "commit"
+		default:
+			return nil, fmt.Errorf(
+				"invalid version '%s' in Azure DevOps URL '%s', expected a GB, GT or GC prefix", version, location)
+		}
+
+		file.Version = version[2:]
+	}
+
+	return &file, nil
+}
+
+// itemsUrl returns the url of the Azure DevOps REST API returning the content of the file.
+func (f *azdoFile) itemsUrl() string {
+	query := url.Values{}
+	query.Set("path", f.Path)
+	query.Set("$format", "octetStream")
+	query.Set("api-version", "7.1")
+	if f.Version != "" {
+		query.Set("versionDescriptor.version", f.Version)
+		query.Set("versionDescriptor.versionType", f.VersionType)
+	}
+
+	return fmt.Sprintf("%s/%s/_apis/git/repositories/%s/items?%s",
+		f.OrganizationUrl, url.PathEscape(f.Project), url.PathEscape(f.Repository), query.Encode())
+}
+
+// newAzdoTemplateSource creates a new template source from a file of an Azure DevOps git repository, which is read
+// with credential.
+func newAzdoTemplateSource(
+	ctx context.Context,
+	name string,
+	location string,
+	transport policy.Transporter,
+	credential credentialFn,
+) (Source, error) {
+	file, err := parseAzdoUrl(location)
+	if err != nil {
+		return nil, err
+	}
+
+	cred, err := credential(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("authenticating to template source '%s': %w", location, err)
+	}
+
+	resp, err := getContent(ctx, file.itemsUrl(), transport, cred, azdoScope, nil)
+	if err != nil {
+		return nil, fmt.Errorf("request failed for template source '%s', %w", location, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		// Azure DevOps answers with a sign-in page when the user can't access the organization
+		if resp.StatusCode == http.StatusNonAuthoritativeInfo {
+			resp.Body.Close()
+			return nil, fmt.Errorf("the account logged in to azd can't access %s", file.OrganizationUrl)
+		}
+
+		return nil, runtime.NewResponseError(resp)
+	}
+
+	json, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading response body for template source '%s', %w", location, err)
+	}
+
+	return newJsonTemplateSource(name, string(json))
+}
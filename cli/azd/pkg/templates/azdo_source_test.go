@@ -0,0 +1,109 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package templates
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseAzdoUrl(t *testing.T) {
+	tests := []struct {
+		name     string
+		location string
+		want     *azdoFile
+	}{
+		{
+			name:     "DevAzureCom",
+			location: "https://dev.azure.com/org/project/_git/repo?path=/templates.json",
+			want: &azdoFile{
+				OrganizationUrl: "https://dev.azure.com/org",
+				Project:         "project",
+				Repository:      "repo",
+				Path:            "/templates.json",
+			},
+		},
+		{
+			name:     "VisualStudioComWithBranch",
+			location: "https://org.visualstudio.com/project/_git/repo?path=/catalog/templates.json&version=GBrelease/v1",
+			want: &azdoFile{
+				OrganizationUrl: "https://org.visualstudio.com",
+				Project:         "project",
+				Repository:      "repo",
+				Path:            "/catalog/templates.json",
+				Version:         "release/v1",
+				VersionType:     "branch",
+			},
+		},
+		{
+			name:     "Tag",
+			location: "https://dev.azure.com/org/project/_git/repo?path=/templates.json&version=GTv2",
+			want: &azdoFile{
+				OrganizationUrl: "https://dev.azure.com/org",
+				Project:         "project",
+				Repository:      "repo",
+				Path:            "/templates.json",
+				Version:         "v2",
+				VersionType:     "tag",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := parseAzdoUrl(tt.location)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, file)
+		})
+	}
+
+	for _, location := range []string{
+		"https://dev.azure.com/org/project/_git/repo",
+		"https://dev.azure.com/org/project?path=/templates.json",
+		"https://github.com/org/project/_git/repo?path=/templates.json",
+		"https://dev.azure.com/org/project/_git/repo?path=/templates.json&version=XXmain",
+	} {
+		_, err := parseAzdoUrl(location)
+		require.Error(t, err, location)
+	}
+}
+
+func Test_NewAzdoTemplateSource(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+
+	mockContext.HttpClient.When(func(req *http.Request) bool {
+		return req.Method == http.MethodGet &&
+			strings.HasPrefix(req.URL.String(), "https://dev.azure.com/org/project/_apis/git/repositories/repo/items?")
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		require.Equal(t, "Bearer ABC123", req.Header.Get("Authorization"))
+		require.Equal(t, "/templates.json", req.URL.Query().Get("path"))
+		require.Equal(t, "main", req.URL.Query().Get("versionDescriptor.version"))
+		require.Equal(t, "branch", req.URL.Query().Get("versionDescriptor.versionType"))
+
+		return mocks.CreateHttpResponseWithBody(req, http.StatusOK, testTemplates)
+	})
+
+	credential := func(ctx context.Context) (azcore.TokenCredential, error) {
+		return &mocks.MockCredentials{}, nil
+	}
+
+	source, err := newAzdoTemplateSource(
+		t.Context(),
+		"test",
+		"https://dev.azure.com/org/project/_git/repo?path=/templates.json&version=GBmain",
+		mockContext.HttpClient,
+		credential,
+	)
+	require.NoError(t, err)
+
+	templates, err := source.ListTemplates(t.Context())
+	require.NoError(t, err)
+	require.Len(t, templates, len(testTemplates))
+}
@@ -26,6 +26,7 @@ const (
 	SourceKindFile       SourceKind = "file"
 	SourceKindUrl        SourceKind = "url"
 	SourceKindGh         SourceKind = "gh"
+	SourceKindAzdo       SourceKind = "azdo"
 	SourceKindResource   SourceKind = "default"
 	SourceKindAwesomeAzd SourceKind = "awesome-azd"
 )
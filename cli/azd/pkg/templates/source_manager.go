@@ -10,7 +10,9 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/azure/azure-dev/cli/azd/pkg/auth"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
@@ -228,7 +230,7 @@ func (sm *sourceManager) CreateSource(ctx context.Context, config *SourceConfig)
 	case SourceKindFile:
 		source, err = newFileTemplateSource(config.Name, config.Location)
 	case SourceKindUrl:
-		source, err = newUrlTemplateSource(ctx, config.Name, config.Location, sm.transport)
+		source, err = newUrlTemplateSource(ctx, config.Name, config.Location, sm.transport, sm.credential)
 	case SourceKindAwesomeAzd:
 		source, err = newAwesomeAzdTemplateSource(ctx, SourceAwesomeAzd.Name, SourceAwesomeAzd.Location, sm.transport)
 	case SourceKindResource:
@@ -238,6 +240,8 @@ func (sm *sourceManager) CreateSource(ctx context.Context, config *SourceConfig)
 			source, err = newGhTemplateSource(ctx, config.Name, config.Location, ghCli, console)
 			return err
 		})
+	case SourceKindAzdo:
+		source, err = newAzdoTemplateSource(ctx, config.Name, config.Location, sm.transport, sm.credential)
 	default:
 		err = sm.serviceLocator.ResolveNamed(string(config.Type), &source)
 		if err != nil {
@@ -252,6 +256,18 @@ func (sm *sourceManager) CreateSource(ctx context.Context, config *SourceConfig)
 	return source, nil
 }
 
+// credential returns the credential of the account logged in to azd, used to access private template sources.
+func (sm *sourceManager) credential(ctx context.Context) (azcore.TokenCredential, error) {
+	var credential azcore.TokenCredential
+	err := sm.serviceLocator.Invoke(func(authManager *auth.Manager) error {
+		var err error
+		credential, err = authManager.CredentialForCurrentUser(ctx, nil)
+		return err
+	})
+
+	return credential, err
+}
+
 func (sm *sourceManager) addInternal(source *SourceConfig) error {
 	config, err := sm.configManager.Load()
 	if err != nil {
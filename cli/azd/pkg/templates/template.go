@@ -35,6 +35,12 @@ type Template struct {
 	// A list of tags associated with the template
 	Tags []string `json:"tags"`
 
+	// Languages are the programming languages used by the template.
+	Languages []string `json:"languages,omitempty"`
+
+	// AzureServices are the Azure services used by the template.
+	AzureServices []string `json:"azureServices,omitempty"`
+
 	// Additional metadata about the template
 	Metadata Metadata `json:"metadata"`
 }
@@ -64,6 +70,14 @@ func (t *Template) Display(writer io.Writer) error {
 		{"Tags", ":", strings.Join(t.Tags, ", ")},
 	}
 
+	if len(t.Languages) > 0 {
+		text = append(text, []string{"Languages", ":", strings.Join(t.Languages, ", ")})
+	}
+
+	if len(t.AzureServices) > 0 {
+		text = append(text, []string{"Azure services", ":", strings.Join(t.AzureServices, ", ")})
+	}
+
 	for _, line := range text {
 		_, err := tabs.Write([]byte(strings.Join(line, "\t") + "\n"))
 		if err != nil {
@@ -35,11 +35,78 @@ func NewTemplateManager(sourceManager SourceManager, console input.Console) (*Te
 type ListOptions struct {
 	Source string
 	Tags   []string
+
+	// Languages filters the templates by the programming languages they use. Templates that don't list their languages
+	// are matched by their tags.
+	Languages []string
+
+	// AzureServices filters the templates by the Azure services they use. Templates that don't list their services are
+	// matched by their tags.
+	AzureServices []string
+
+	// Search filters the templates by keywords, like an architecture or a scenario. Each keyword must be found in the
+	// name, title, description, repository path or tags of the template.
+	Search string
 }
 
 type sourceFilterPredicate func(config *SourceConfig) bool
 type templateFilterPredicate func(template *Template) bool
 
+// templateFilter returns the predicate matching the templates that satisfy all the options, or nil when the options
+// don't filter templates.
+func (o *ListOptions) templateFilter() templateFilterPredicate {
+	keywords := strings.Fields(strings.ToLower(o.Search))
+	if len(o.Tags) == 0 && len(o.Languages) == 0 && len(o.AzureServices) == 0 && len(keywords) == 0 {
+		return nil
+	}
+
+	return func(template *Template) bool {
+		languages := template.Languages
+		if len(languages) == 0 {
+			languages = template.Tags
+		}
+
+		azureServices := template.AzureServices
+		if len(azureServices) == 0 {
+			azureServices = template.Tags
+		}
+
+		if !containsAll(template.Tags, o.Tags) ||
+			!containsAll(languages, o.Languages) ||
+			!containsAll(azureServices, o.AzureServices) {
+			return false
+		}
+
+		searchText := strings.ToLower(strings.Join(append([]string{
+			template.Name,
+			template.Title,
+			template.Description,
+			template.RepositoryPath,
+		}, template.Tags...), " "))
+
+		for _, keyword := range keywords {
+			if !strings.Contains(searchText, keyword) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// containsAll reports whether values contains all the wanted values, ignoring case.
+func containsAll(values []string, wanted []string) bool {
+	for _, want := range wanted {
+		if !slices.ContainsFunc(values, func(value string) bool {
+			return strings.EqualFold(want, value)
+		}) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // ListTemplates retrieves the list of templates in a deterministic order.
 func (tm *TemplateManager) ListTemplates(ctx context.Context, options *ListOptions) ([]*Template, error) {
 	msg := "Retrieving templates..."
@@ -56,22 +123,8 @@ func (tm *TemplateManager) ListTemplates(ctx context.Context, options *ListOptio
 	}
 
 	var templateFilterPredicate templateFilterPredicate
-	if options != nil && len(options.Tags) > 0 {
-		// Find templates that match all the incoming tags
-		templateFilterPredicate = func(template *Template) bool {
-			match := false
-			for _, optionTag := range options.Tags {
-				match = slices.ContainsFunc(template.Tags, func(templateTag string) bool {
-					return strings.EqualFold(optionTag, templateTag)
-				})
-
-				if !match {
-					break
-				}
-			}
-
-			return match
-		}
+	if options != nil {
+		templateFilterPredicate = options.templateFilter()
 	}
 
 	sources, err := tm.getSources(ctx, sourceFilterPredicate)
@@ -198,6 +251,10 @@ func PromptTemplate(
 		return Template{}, fmt.Errorf("prompting for template: %w", err)
 	}
 
+	if len(templates) == 0 {
+		return Template{}, fmt.Errorf("prompting for template: %w", ErrTemplateNotFound)
+	}
+
 	// Display gallery links before template selection
 	PrintGalleryLinks(console.Handles().Stdout)
 
@@ -228,7 +285,12 @@ func PromptTemplate(
 			templateChoice += fmt.Sprintf(" (%s)", template.Source)
 		}
 
-		templateDetails = append(templateDetails, template.RepositoryPath)
+		// Languages are part of the details so the templates can be filtered by language
+		details := template.RepositoryPath
+		if len(template.Languages) > 0 {
+			details += ", " + strings.Join(template.Languages, ", ")
+		}
+		templateDetails = append(templateDetails, details)
 
 		if slices.Contains(templateNames, templateChoice) {
 			duplicateNames = append(duplicateNames, templateChoice)
@@ -242,6 +304,7 @@ func PromptTemplate(
 		Options:       templateNames,
 		OptionDetails: templateDetails,
 		DefaultValue:  templateNames[0],
+		FilterHint:    "Type part of a template name, repository or language",
 	})
 
 	// separate this prompt from the next log
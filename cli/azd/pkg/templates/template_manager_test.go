@@ -98,6 +98,58 @@ func Test_Templates_ListTemplates_WithTagFilter(t *testing.T) {
 	})
 }
 
+func Test_Templates_ListTemplates_WithMetadataFilters(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+
+	configManager := &mockUserConfigManager{}
+	configManager.On("Load").Return(config.NewConfig(defaultTemplateSourceData), nil)
+	addGhMocks(mockContext)
+
+	templateManager, err := NewTemplateManager(
+		NewSourceManager(NewSourceOptions(), mockContext.Container, configManager, mockContext.HttpClient),
+		mockContext.Console,
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		options *ListOptions
+		want    int
+	}{
+		{"Language", &ListOptions{Languages: []string{"Python"}}, 4},
+		{"LanguageAndService", &ListOptions{Languages: []string{"python"}, AzureServices: []string{"containerapp"}}, 1},
+		{"Search", &ListOptions{Search: "terraform"}, 3},
+		{"SearchAllKeywords", &ListOptions{Search: "Python  terraform"}, 1},
+		{"NoMatch", &ListOptions{Languages: []string{"python"}, Search: "aks"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templates, err := templateManager.ListTemplates(*mockContext.Context, tt.options)
+			require.NoError(t, err)
+			require.Len(t, templates, tt.want)
+		})
+	}
+}
+
+func Test_ListOptions_templateFilter(t *testing.T) {
+	template := &Template{
+		Name:          "RAG chat app",
+		Tags:          []string{"ai", "python", "openai"},
+		Languages:     []string{"python"},
+		AzureServices: []string{"openai", "aisearch"},
+	}
+
+	require.Nil(t, (&ListOptions{Source: "default"}).templateFilter())
+
+	require.True(t, (&ListOptions{AzureServices: []string{"OpenAI", "aisearch"}}).templateFilter()(template))
+	require.True(t, (&ListOptions{Search: "rag"}).templateFilter()(template))
+
+	// listed languages and services take precedence over tags
+	require.False(t, (&ListOptions{Languages: []string{"ai"}}).templateFilter()(template))
+	require.False(t, (&ListOptions{AzureServices: []string{"python"}}).templateFilter()(template))
+}
+
 func Test_Templates_ListTemplates_SourceError(t *testing.T) {
 	mockContext := mocks.NewMockContext(t.Context())
 
@@ -8,27 +8,53 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 )
 
-// newUrlTemplateSource creates a new template source from a URL.
-func newUrlTemplateSource(ctx context.Context, name string, url string, transport policy.Transporter) (Source, error) {
-	pipeline := runtime.NewPipeline("azd-templates", "1.0.0", runtime.PipelineOptions{}, &policy.ClientOptions{
-		Transport: transport,
-	})
+const (
+	storageScope = "https://storage.azure.com/.default"
+	// storageApiVersion is the version of the storage API used to read blobs with a bearer token.
+	storageApiVersion = "2021-08-06"
+)
 
-	req, err := runtime.NewRequest(ctx, http.MethodGet, url)
-	if err != nil {
-		return nil, err
-	}
+// credentialFn returns the credential used to access private template sources.
+type credentialFn func(ctx context.Context) (azcore.TokenCredential, error)
 
-	resp, err := pipeline.Do(req)
+// newUrlTemplateSource creates a new template source from a URL. Blobs of Azure Storage accounts that can't be read
+// anonymously are read with the credential returned by credential.
+func newUrlTemplateSource(
+	ctx context.Context,
+	name string,
+	url string,
+	transport policy.Transporter,
+	credential credentialFn,
+) (Source, error) {
+	resp, err := getContent(ctx, url, transport, nil, "", nil)
 	if err != nil {
 		return nil, fmt.Errorf("request failed for template source '%s', %w", url, err)
 	}
 
+	if resp.StatusCode != http.StatusOK && credential != nil && isStorageUrl(url) {
+		resp.Body.Close()
+
+		cred, err := credential(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("authenticating to template source '%s': %w", url, err)
+		}
+
+		resp, err = getContent(ctx, url, transport, cred, storageScope, map[string]string{
+			"x-ms-version": storageApiVersion,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("request failed for template source '%s', %w", url, err)
+		}
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, runtime.NewResponseError(resp)
 	}
@@ -40,3 +66,44 @@ func newUrlTemplateSource(ctx context.Context, name string, url string, transpor
 
 	return newJsonTemplateSource(name, string(json))
 }
+
+// getContent sends a GET request to url. The request is authenticated with a bearer token for scope when credential
+// isn't nil.
+func getContent(
+	ctx context.Context,
+	url string,
+	transport policy.Transporter,
+	credential azcore.TokenCredential,
+	scope string,
+	headers map[string]string,
+) (*http.Response, error) {
+	pipelineOptions := runtime.PipelineOptions{}
+	if credential != nil {
+		pipelineOptions.PerRetry = []policy.Policy{runtime.NewBearerTokenPolicy(credential, []string{scope}, nil)}
+	}
+
+	pipeline := runtime.NewPipeline("azd-templates", "1.0.0", pipelineOptions, &policy.ClientOptions{
+		Transport: transport,
+	})
+
+	req, err := runtime.NewRequest(ctx, http.MethodGet, url)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, value := range headers {
+		req.Raw().Header.Set(name, value)
+	}
+
+	return pipeline.Do(req)
+}
+
+// isStorageUrl reports whether rawUrl is the url of a blob of an Azure Storage account.
+func isStorageUrl(rawUrl string) bool {
+	parsed, err := url.Parse(rawUrl)
+	if err != nil {
+		return false
+	}
+
+	return strings.HasSuffix(strings.ToLower(parsed.Hostname()), ".blob.core.windows.net")
+}
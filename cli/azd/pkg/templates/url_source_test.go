@@ -4,9 +4,11 @@
 package templates
 
 import (
+	"context"
 	"net/http"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/stretchr/testify/require"
 )
@@ -23,7 +25,7 @@ func Test_NewUrlTemplateSource_ValidUrl(t *testing.T) {
 		return mocks.CreateHttpResponseWithBody(req, http.StatusOK, testTemplates)
 	})
 
-	source, err := newUrlTemplateSource(t.Context(), name, url, mockContext.HttpClient)
+	source, err := newUrlTemplateSource(t.Context(), name, url, mockContext.HttpClient, nil)
 	require.Nil(t, err)
 
 	require.Equal(t, name, source.Name())
@@ -41,7 +43,7 @@ func Test_NewUrlTemplateSource_ValidUrl_InvalidJson(t *testing.T) {
 		return mocks.CreateHttpResponseWithBody(req, http.StatusOK, "invalid json")
 	})
 
-	source, err := newUrlTemplateSource(t.Context(), name, url, mockContext.HttpClient)
+	source, err := newUrlTemplateSource(t.Context(), name, url, mockContext.HttpClient, nil)
 	require.Nil(t, source)
 	require.Error(t, err)
 }
@@ -58,7 +60,36 @@ func Test_NewUrlTemplateSource_InvalidUrl(t *testing.T) {
 		return mocks.CreateEmptyHttpResponse(req, http.StatusNotFound)
 	})
 
-	source, err := newUrlTemplateSource(t.Context(), name, url, mockContext.HttpClient)
+	source, err := newUrlTemplateSource(t.Context(), name, url, mockContext.HttpClient, nil)
 	require.Nil(t, source)
 	require.Error(t, err)
 }
+
+func Test_NewUrlTemplateSource_PrivateStorageBlob(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+
+	name := "test"
+	url := "https://account.blob.core.windows.net/templates/templates.json"
+
+	mockContext.HttpClient.When(func(req *http.Request) bool {
+		return req.Method == http.MethodGet && req.URL.String() == url
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		if req.Header.Get("Authorization") != "Bearer ABC123" {
+			return mocks.CreateEmptyHttpResponse(req, http.StatusNotFound)
+		}
+
+		require.Equal(t, storageApiVersion, req.Header.Get("x-ms-version"))
+		return mocks.CreateHttpResponseWithBody(req, http.StatusOK, testTemplates)
+	})
+
+	credential := func(ctx context.Context) (azcore.TokenCredential, error) {
+		return &mocks.MockCredentials{}, nil
+	}
+
+	source, err := newUrlTemplateSource(t.Context(), name, url, mockContext.HttpClient, credential)
+	require.NoError(t, err)
+
+	templates, err := source.ListTemplates(t.Context())
+	require.NoError(t, err)
+	require.Len(t, templates, len(testTemplates))
+}
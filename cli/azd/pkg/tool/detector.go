@@ -35,6 +35,17 @@ type ToolStatus struct {
 	Error error
 }
 
+// BelowMinVersion reports whether the tool is installed at a version older
+// than its pinned [ToolDefinition.MinVersion]. It returns false when the
+// tool has no pin or its installed version is unknown.
+func (s *ToolStatus) BelowMinVersion() bool {
+	if !s.Installed || s.Tool == nil || s.Tool.MinVersion == "" {
+		return false
+	}
+
+	return isNewerVersion(s.Tool.MinVersion, s.InstalledVersion)
+}
+
 // InstalledSkillAgent pairs an agent CLI with the skill version
 // installed through it.
 type InstalledSkillAgent struct {
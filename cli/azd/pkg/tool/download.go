@@ -0,0 +1,213 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package tool
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// MirrorEnvVar is the environment variable that points azd at a mirror of the artifacts it downloads for tools
+// (e.g. the Bicep and pack CLIs). The value is either an http(s) base URL or a local directory, which allows tools to
+// be bootstrapped on machines without internet access.
+//
+// A mirror uses the host and path of the original download URL as its layout, so
+// https://downloads.bicep.azure.com/v0.45.15/bicep-linux-x64 is looked up as
+// <mirror>/downloads.bicep.azure.com/v0.45.15/bicep-linux-x64.
+const MirrorEnvVar = "AZD_TOOL_MIRROR"
+
+// checksumSidecarSuffix is appended to the path of a mirrored artifact to find its checksum file. Mirrors may publish
+// a sha256 sidecar for artifacts whose checksum is not pinned in the tool manifest.
+const checksumSidecarSuffix = ".sha256"
+
+// MirrorUrl returns the location of rawUrl in the mirror configured via [MirrorEnvVar], and whether a mirror is
+// configured. The returned location is a URL for http(s) mirrors and a file path for local directory mirrors.
+func MirrorUrl(rawUrl string) (string, bool, error) {
+	mirror := strings.TrimSpace(os.Getenv(MirrorEnvVar))
+	if mirror == "" {
+		return rawUrl, false, nil
+	}
+
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return "", false, fmt.Errorf("parsing download URL: %w", err)
+	}
+
+	if isHttpUrl(mirror) {
+		return strings.TrimSuffix(mirror, "/") + "/" + u.Host + path.Clean("/"+u.Path), true, nil
+	}
+
+	dir := strings.TrimPrefix(mirror, "file://")
+	return filepath.Join(dir, u.Host, filepath.FromSlash(path.Clean("/"+u.Path))), true, nil
+}
+
+// ErrMissingChecksum is returned by [DownloadArtifact] when there is no checksum to verify an artifact against.
+var ErrMissingChecksum = errors.New("no checksum to verify the download against")
+
+// DownloadArtifact writes the artifact at rawUrl to dst, reading it from the mirror configured via [MirrorEnvVar]
+// when one is set. The content is verified against checksum while it is written; when checksum is empty and the
+// artifact comes from a mirror, the mirror's sha256 sidecar file is used instead. Artifacts without a checksum are
+// never downloaded, and [ErrMissingChecksum] is returned instead.
+//
+// On a checksum mismatch dst will already hold the unverified content, so callers should write to a temporary file and
+// discard it when an error is returned.
+func DownloadArtifact(
+	ctx context.Context,
+	client policy.Transporter,
+	rawUrl string,
+	checksum Checksum,
+	dst io.Writer,
+) error {
+	location, mirrored, err := MirrorUrl(rawUrl)
+	if err != nil {
+		return err
+	}
+
+	if mirrored {
+		log.Printf("downloading %s from mirror %s", rawUrl, location)
+
+		if checksum.Algorithm == "" && checksum.Value == "" {
+			checksum, err = mirrorChecksum(ctx, client, location)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if checksum.Algorithm == "" && checksum.Value == "" {
+		return fmt.Errorf("%w: %s", ErrMissingChecksum, rawUrl)
+	}
+
+	h, err := checksumHash(checksum)
+	if err != nil {
+		return err
+	}
+	dst = io.MultiWriter(dst, h)
+
+	body, err := openArtifact(ctx, client, location)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if _, err := io.Copy(dst, body); err != nil {
+		return fmt.Errorf("writing download: %w", err)
+	}
+
+	return compareChecksum(hex.EncodeToString(h.Sum(nil)), checksum.Value)
+}
+
+// mirrorChecksum reads the sha256 sidecar of a mirrored artifact. A mirror without a sidecar yields an empty
+// checksum. The sidecar may use the `sha256sum` output format, where the hash is followed by the file name.
+func mirrorChecksum(ctx context.Context, client httpDoer, location string) (Checksum, error) {
+	body, err := openArtifact(ctx, client, location+checksumSidecarSuffix)
+	if err != nil {
+		log.Printf("no checksum sidecar for %s: %v", location, err)
+		return Checksum{}, nil
+	}
+	defer body.Close()
+
+	contents, err := io.ReadAll(io.LimitReader(body, 1024))
+	if err != nil {
+		return Checksum{}, fmt.Errorf("reading checksum sidecar: %w", err)
+	}
+
+	fields := strings.Fields(string(contents))
+	if len(fields) == 0 {
+		return Checksum{}, fmt.Errorf("checksum sidecar for %s is empty", location)
+	}
+
+	return Checksum{Algorithm: "sha256", Value: fields[0]}, nil
+}
+
+// openArtifact opens location, which is either an http(s) URL or a local file path.
+func openArtifact(ctx context.Context, client httpDoer, location string) (io.ReadCloser, error) {
+	if !isHttpUrl(location) {
+		//nolint:gosec // location is derived from the user configured mirror
+		f, err := os.Open(location)
+		if err != nil {
+			return nil, fmt.Errorf("opening mirrored artifact: %w", err)
+		}
+		return f, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("downloading artifact: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("download failed: http error %d", resp.StatusCode)
+	}
+
+	return resp.Body, nil
+}
+
+func isHttpUrl(s string) bool {
+	return strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "http://")
+}
+
+// checksumHash returns the hash for the checksum's algorithm, rejecting partial checksum configuration.
+func checksumHash(checksum Checksum) (hash.Hash, error) {
+	if checksum.Algorithm == "" {
+		return nil, fmt.Errorf(
+			"checksum value is set but algorithm is empty" +
+				" — specify both algorithm and value, or neither",
+		)
+	}
+	if checksum.Value == "" {
+		return nil, fmt.Errorf(
+			"checksum algorithm %q is set but value is empty"+
+				" — specify both algorithm and value, or neither",
+			checksum.Algorithm,
+		)
+	}
+
+	switch checksum.Algorithm {
+	case "sha256":
+		return sha256.New(), nil
+	case "sha512":
+		return sha512.New(), nil
+	default:
+		return nil, fmt.Errorf(
+			"unsupported checksum algorithm: %s",
+			checksum.Algorithm,
+		)
+	}
+}
+
+// compareChecksum compares the hex-encoded actual and expected checksums.
+func compareChecksum(actual, expected string) error {
+	if !strings.EqualFold(actual, expected) {
+		return fmt.Errorf(
+			"checksum verification failed. "+
+				"Expected: %s, Got: %s. "+
+				"This may indicate a corrupted download",
+			expected, actual,
+		)
+	}
+
+	return nil
+}
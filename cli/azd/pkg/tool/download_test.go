@@ -0,0 +1,151 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package tool
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testArtifactUrl = "https://downloads.example.com/v1.0.0/tool-linux-x64"
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestMirrorUrl(t *testing.T) {
+	t.Run("NoMirror", func(t *testing.T) {
+		t.Setenv(MirrorEnvVar, "")
+
+		location, mirrored, err := MirrorUrl(testArtifactUrl)
+		require.NoError(t, err)
+		require.False(t, mirrored)
+		require.Equal(t, testArtifactUrl, location)
+	})
+
+	t.Run("HttpMirror", func(t *testing.T) {
+		t.Setenv(MirrorEnvVar, "https://mirror.contoso.com/tools/")
+
+		location, mirrored, err := MirrorUrl(testArtifactUrl)
+		require.NoError(t, err)
+		require.True(t, mirrored)
+		require.Equal(t, "https://mirror.contoso.com/tools/downloads.example.com/v1.0.0/tool-linux-x64", location)
+	})
+
+	t.Run("DirectoryMirror", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv(MirrorEnvVar, dir)
+
+		location, mirrored, err := MirrorUrl("https://downloads.example.com/../v1.0.0/tool-linux-x64")
+		require.NoError(t, err)
+		require.True(t, mirrored)
+		require.Equal(t, filepath.Join(dir, "downloads.example.com", "v1.0.0", "tool-linux-x64"), location)
+	})
+}
+
+func TestDownloadArtifact(t *testing.T) {
+	content := []byte("tool binary")
+
+	writeMirror := func(t *testing.T, sidecar string) {
+		dir := t.TempDir()
+		artifact := filepath.Join(dir, "downloads.example.com", "v1.0.0", "tool-linux-x64")
+		require.NoError(t, os.MkdirAll(filepath.Dir(artifact), 0o755))
+		require.NoError(t, os.WriteFile(artifact, content, 0o600))
+		if sidecar != "" {
+			require.NoError(t, os.WriteFile(artifact+checksumSidecarSuffix, []byte(sidecar), 0o600))
+		}
+		t.Setenv(MirrorEnvVar, dir)
+	}
+
+	t.Run("DirectoryMirrorWithSidecar", func(t *testing.T) {
+		writeMirror(t, sha256Hex(content)+"  tool-linux-x64\n")
+
+		var buf bytes.Buffer
+		err := DownloadArtifact(t.Context(), http.DefaultClient, testArtifactUrl, Checksum{}, &buf)
+		require.NoError(t, err)
+		require.Equal(t, content, buf.Bytes())
+	})
+
+	t.Run("DirectoryMirrorSidecarMismatch", func(t *testing.T) {
+		writeMirror(t, sha256Hex([]byte("something else")))
+
+		err := DownloadArtifact(t.Context(), http.DefaultClient, testArtifactUrl, Checksum{}, &bytes.Buffer{})
+		require.ErrorContains(t, err, "checksum verification failed")
+	})
+
+	t.Run("PinnedChecksumTakesPrecedence", func(t *testing.T) {
+		writeMirror(t, sha256Hex([]byte("something else")))
+
+		checksum := Checksum{Algorithm: "sha256", Value: sha256Hex(content)}
+		err := DownloadArtifact(t.Context(), http.DefaultClient, testArtifactUrl, checksum, &bytes.Buffer{})
+		require.NoError(t, err)
+	})
+
+	t.Run("MissingFromMirror", func(t *testing.T) {
+		t.Setenv(MirrorEnvVar, t.TempDir())
+
+		checksum := Checksum{Algorithm: "sha256", Value: sha256Hex(content)}
+		err := DownloadArtifact(t.Context(), http.DefaultClient, testArtifactUrl, checksum, &bytes.Buffer{})
+		require.ErrorContains(t, err, "opening mirrored artifact")
+	})
+
+	t.Run("HttpMirror", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/downloads.example.com/v1.0.0/tool-linux-x64":
+				_, _ = w.Write(content)
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer srv.Close()
+		t.Setenv(MirrorEnvVar, srv.URL)
+
+		checksum := Checksum{Algorithm: "sha256", Value: sha256Hex(content)}
+		var buf bytes.Buffer
+		err := DownloadArtifact(t.Context(), srv.Client(), testArtifactUrl, checksum, &buf)
+		require.NoError(t, err)
+		require.Equal(t, content, buf.Bytes())
+	})
+
+	t.Run("HttpError", func(t *testing.T) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		defer srv.Close()
+		t.Setenv(MirrorEnvVar, srv.URL)
+
+		checksum := Checksum{Algorithm: "sha256", Value: sha256Hex(content)}
+		err := DownloadArtifact(t.Context(), srv.Client(), testArtifactUrl, checksum, &bytes.Buffer{})
+		require.ErrorContains(t, err, "http error 404")
+	})
+
+	t.Run("MissingChecksum", func(t *testing.T) {
+		writeMirror(t, "")
+
+		var buf bytes.Buffer
+		err := DownloadArtifact(t.Context(), http.DefaultClient, testArtifactUrl, Checksum{}, &buf)
+		require.ErrorIs(t, err, ErrMissingChecksum)
+		require.Empty(t, buf.Bytes())
+	})
+
+	t.Run("TamperedDownload", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("tampered tool binary"))
+		}))
+		defer srv.Close()
+		t.Setenv(MirrorEnvVar, srv.URL)
+
+		checksum := Checksum{Algorithm: "sha256", Value: sha256Hex(content)}
+		err := DownloadArtifact(t.Context(), srv.Client(), testArtifactUrl, checksum, &bytes.Buffer{})
+		require.ErrorContains(t, err, "checksum verification failed")
+	})
+}
@@ -6,12 +6,9 @@ package tool
 import (
 	"bytes"
 	"context"
-	"crypto/sha256"
-	"crypto/sha512"
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"hash"
 	"io"
 	"net/http"
 	"net/url"
@@ -2345,32 +2342,14 @@ func (i *installer) uninstallUnsupportedError(
 // -----------------------------------------------------------------------
 
 // executeDirectDownload fetches the artifact from the strategy's
-// DirectDownloadUrl, verifies the checksum (when configured), and
-// places the downloaded file in a well-known location. The caller is
+// DirectDownloadUrl, verifies it against the strategy's checksum (or the
+// mirror's sidecar checksum; the download fails when there is neither),
+// and places the downloaded file in a well-known location. The caller is
 // responsible for post-download verification via the Detector.
 func (i *installer) executeDirectDownload(
 	ctx context.Context,
 	strategy *InstallStrategy,
 ) error {
-	req, err := http.NewRequestWithContext(
-		ctx, http.MethodGet, strategy.DirectDownloadUrl, nil,
-	)
-	if err != nil {
-		return fmt.Errorf("creating request: %w", err)
-	}
-
-	resp, err := i.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("downloading artifact: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf(
-			"download failed with HTTP %d", resp.StatusCode,
-		)
-	}
-
 	// Write to a temp file first.
 	tmpFile, err := os.CreateTemp("", "azd-tool-*")
 	if err != nil {
@@ -2383,20 +2362,17 @@ func (i *installer) executeDirectDownload(
 		os.Remove(tmpPath)
 	}()
 
-	if _, err := io.Copy(tmpFile, resp.Body); err != nil {
-		return fmt.Errorf("writing download: %w", err)
+	// Download (from the configured mirror, if any) and verify the
+	// checksum.
+	if err := DownloadArtifact(
+		ctx, i.httpClient, strategy.DirectDownloadUrl, strategy.Checksum, tmpFile,
+	); err != nil {
+		return err
 	}
 	if err := tmpFile.Close(); err != nil {
 		return fmt.Errorf("closing temp file: %w", err)
 	}
 
-	// Verify checksum when configured.
-	if err := validateChecksum(
-		tmpPath, strategy.Checksum,
-	); err != nil {
-		return err
-	}
-
 	// Move the artifact to a permanent location.
 	destDir, err := toolInstallDir()
 	if err != nil {
@@ -2477,33 +2453,9 @@ func validateChecksum(filePath string, checksum Checksum) error {
 		return nil
 	}
 
-	// Reject partial checksum configuration.
-	if checksum.Algorithm == "" {
-		return fmt.Errorf(
-			"checksum value is set but algorithm is empty" +
-				" — specify both algorithm and value, or neither",
-		)
-	}
-	if checksum.Value == "" {
-		return fmt.Errorf(
-			"checksum algorithm %q is set but value is empty"+
-				" — specify both algorithm and value, or neither",
-			checksum.Algorithm,
-		)
-	}
-
-	var hashAlgo hash.Hash
-
-	switch checksum.Algorithm {
-	case "sha256":
-		hashAlgo = sha256.New()
-	case "sha512":
-		hashAlgo = sha512.New()
-	default:
-		return fmt.Errorf(
-			"unsupported checksum algorithm: %s",
-			checksum.Algorithm,
-		)
+	hashAlgo, err := checksumHash(checksum)
+	if err != nil {
+		return err
 	}
 
 	//nolint:gosec // filePath from controlled download
@@ -2519,18 +2471,7 @@ func validateChecksum(filePath string, checksum Checksum) error {
 		return fmt.Errorf("computing checksum: %w", err)
 	}
 
-	actual := hex.EncodeToString(hashAlgo.Sum(nil))
-
-	if !strings.EqualFold(actual, checksum.Value) {
-		return fmt.Errorf(
-			"checksum verification failed. "+
-				"Expected: %s, Got: %s. "+
-				"This may indicate a corrupted download",
-			checksum.Value, actual,
-		)
-	}
-
-	return nil
+	return compareChecksum(hex.EncodeToString(hashAlgo.Sum(nil)), checksum.Value)
 }
 
 // toolInstallDir returns the directory where directly downloaded
@@ -143,7 +143,8 @@ type InstallStrategy struct {
 	// instead of PackageManager or InstallCommand.
 	DirectDownloadUrl string
 	// Checksum is the expected hash of the artifact referenced by
-	// DirectDownloadUrl. When empty, checksum verification is skipped.
+	// DirectDownloadUrl. When empty, the download fails unless the mirror
+	// configured via AZD_TOOL_MIRROR publishes a checksum for it.
 	Checksum Checksum
 	// FallbackUrl points to manual installation instructions.
 	FallbackUrl string
@@ -186,6 +187,16 @@ type ToolDefinition struct {
 	// because the agent CLI's plugin command syntax does not vary between
 	// operating systems. Ignored for other categories.
 	SkillAgents []SkillAgent
+	// ArtifactChecksums pins the checksums of the release artifacts that azd
+	// downloads on demand for the tool (e.g. the Bicep CLI used by the Bicep
+	// provider), keyed by "<version>/<artifact file name>". Downloads of
+	// artifacts without a pinned checksum fail unless the mirror configured
+	// via AZD_TOOL_MIRROR publishes one.
+	ArtifactChecksums map[string]Checksum
+	// MinVersion pins the oldest version of the tool azd works with. An
+	// installed tool older than MinVersion is reported as needing an
+	// upgrade. Empty when any version is accepted.
+	MinVersion string
 	// Dependencies lists the IDs of tools that must be installed before this one.
 	Dependencies []string
 	// SpinnerNote is an optional follow-up message shown after a successful
@@ -193,6 +204,12 @@ type ToolDefinition struct {
 	SpinnerNote string
 }
 
+// ArtifactChecksum returns the checksum pinned for the release artifact named artifact of version of the tool, or an
+// empty checksum when none is pinned.
+func (t *ToolDefinition) ArtifactChecksum(version string, artifact string) Checksum {
+	return t.ArtifactChecksums[version+"/"+artifact]
+}
+
 // BuiltInTools returns the full set of tools that ship with the azd tool registry.
 // The returned slice is a fresh copy; callers may safely append or modify it.
 func BuiltInTools() []*ToolDefinition {
@@ -232,6 +249,12 @@ var builtInTools = []*ToolDefinition{
 	azureMCPServer(),
 	azdAIExtensions(),
 	azureSkills(),
+	bicepCLI(),
+	terraformCLI(),
+	kubectlCLI(),
+	helmCLI(),
+	packCLI(),
+	swaCLI(),
 }
 
 // ---------------------------------------------------------------------------
@@ -459,6 +482,156 @@ func azureSkills() *ToolDefinition {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// Provider tools – CLIs driven by azd provisioning providers and service
+// targets. MinVersion mirrors the version each provider requires.
+// ---------------------------------------------------------------------------
+
+func bicepCLI() *ToolDefinition {
+	return &ToolDefinition{
+		Id:            "bicep-cli",
+		Name:          "Bicep CLI",
+		Description:   "Compiles Bicep templates for the Bicep provisioning provider.",
+		Category:      ToolCategoryCLI,
+		Priority:      ToolPriorityOptional,
+		Website:       "https://learn.microsoft.com/azure/azure-resource-manager/bicep/install",
+		DetectCommand: "bicep",
+		VersionArgs:   []string{"--version"},
+		VersionRegex:  `Bicep CLI version (\d+\.\d+\.\d+)`,
+		MinVersion:    "0.45.15",
+		InstallStrategies: map[string][]InstallStrategy{
+			"windows": {{PackageManager: "winget", PackageId: "Microsoft.Bicep"}},
+			"darwin":  {{PackageManager: "brew", PackageId: "azure/bicep/bicep"}},
+			"linux": {{
+				PackageManager: "brew",
+				PackageId:      "azure/bicep/bicep",
+				FallbackUrl:    "https://learn.microsoft.com/azure/azure-resource-manager/bicep/install#linux",
+			}},
+		},
+	}
+}
+
+func terraformCLI() *ToolDefinition {
+	return &ToolDefinition{
+		Id:            "terraform",
+		Name:          "Terraform CLI",
+		Description:   "Applies Terraform configurations for the Terraform provisioning provider.",
+		Category:      ToolCategoryCLI,
+		Priority:      ToolPriorityOptional,
+		Website:       "https://developer.hashicorp.com/terraform/install",
+		DetectCommand: "terraform",
+		VersionArgs:   []string{"version"},
+		VersionRegex:  `Terraform v(\d+\.\d+\.\d+)`,
+		MinVersion:    "1.1.7",
+		InstallStrategies: map[string][]InstallStrategy{
+			"windows": {{PackageManager: "winget", PackageId: "Hashicorp.Terraform"}},
+			"darwin":  {{PackageManager: "brew", PackageId: "hashicorp/tap/terraform"}},
+			"linux": {
+				{PackageManager: "apt", PackageId: "terraform"},
+				{
+					PackageManager: "brew",
+					PackageId:      "hashicorp/tap/terraform",
+					FallbackUrl:    "https://developer.hashicorp.com/terraform/install#linux",
+				},
+			},
+		},
+	}
+}
+
+func kubectlCLI() *ToolDefinition {
+	return &ToolDefinition{
+		Id:            "kubectl",
+		Name:          "Kubernetes CLI",
+		Description:   "Applies Kubernetes manifests for services deployed to AKS.",
+		Category:      ToolCategoryCLI,
+		Priority:      ToolPriorityOptional,
+		Website:       "https://kubernetes.io/docs/tasks/tools/",
+		DetectCommand: "kubectl",
+		VersionArgs:   []string{"version", "--client"},
+		VersionRegex:  `Client Version: v(\d+\.\d+\.\d+)`,
+		// kubectl supports clusters within one minor version of its own, and AKS no longer supports older clusters.
+		MinVersion: "1.28.0",
+		InstallStrategies: map[string][]InstallStrategy{
+			"windows": {{PackageManager: "winget", PackageId: "Kubernetes.kubectl"}},
+			"darwin":  {{PackageManager: "brew", PackageId: "kubectl"}},
+			"linux": {{
+				PackageManager: "brew",
+				PackageId:      "kubectl",
+				FallbackUrl:    "https://kubernetes.io/docs/tasks/tools/install-kubectl-linux/",
+			}},
+		},
+	}
+}
+
+func helmCLI() *ToolDefinition {
+	return &ToolDefinition{
+		Id:            "helm",
+		Name:          "Helm",
+		Description:   "Installs Helm charts for services deployed to AKS.",
+		Category:      ToolCategoryCLI,
+		Priority:      ToolPriorityOptional,
+		Website:       "https://helm.sh/docs/intro/install/",
+		DetectCommand: "helm",
+		VersionArgs:   []string{"version", "--short"},
+		VersionRegex:  `v(\d+\.\d+\.\d+)`,
+		// Charts in OCI registries, like Azure Container Registry, require Helm 3.8.
+		MinVersion: "3.8.0",
+		InstallStrategies: map[string][]InstallStrategy{
+			"windows": {{PackageManager: "winget", PackageId: "Helm.Helm"}},
+			"darwin":  {{PackageManager: "brew", PackageId: "helm"}},
+			"linux": {{
+				PackageManager: "brew",
+				PackageId:      "helm",
+				FallbackUrl:    "https://helm.sh/docs/intro/install/",
+			}},
+		},
+	}
+}
+
+func packCLI() *ToolDefinition {
+	return &ToolDefinition{
+		Id:            "pack",
+		Name:          "Buildpacks CLI",
+		Description:   "Builds container images from source for services without a Dockerfile.",
+		Category:      ToolCategoryCLI,
+		Priority:      ToolPriorityOptional,
+		Website:       "https://buildpacks.io/docs/for-platform-operators/how-to/integrate-ci/pack/",
+		DetectCommand: "pack",
+		VersionArgs:   []string{"--version"},
+		VersionRegex:  `(\d+\.\d+\.\d+)`,
+		MinVersion:    "0.30.0",
+		// pack is not published to winget; on Windows azd downloads it on
+		// demand into its own tools folder instead.
+		InstallStrategies: map[string][]InstallStrategy{
+			"darwin": {{PackageManager: "brew", PackageId: "buildpacks/tap/pack"}},
+			"linux": {{
+				PackageManager: "brew",
+				PackageId:      "buildpacks/tap/pack",
+				FallbackUrl:    "https://buildpacks.io/docs/for-platform-operators/how-to/integrate-ci/pack/",
+			}},
+		},
+	}
+}
+
+func swaCLI() *ToolDefinition {
+	return &ToolDefinition{
+		Id:            "swa-cli",
+		Name:          "Static Web Apps CLI",
+		Description:   "Builds and deploys services hosted on Azure Static Web Apps.",
+		Category:      ToolCategoryCLI,
+		Priority:      ToolPriorityOptional,
+		Website:       "https://azure.github.io/static-web-apps-cli/",
+		DetectCommand: "swa",
+		VersionArgs:   []string{"--version"},
+		VersionRegex:  `(\d+\.\d+\.\d+)`,
+		MinVersion:    "2.0.0",
+		InstallStrategies: allPlatforms(InstallStrategy{
+			PackageManager: "npm",
+			PackageId:      "@azure/static-web-apps-cli",
+		}),
+	}
+}
+
 // allPlatforms returns an [InstallStrategies] map that uses the same ordered
 // strategy list for Windows, macOS and Linux. A single strategy is the common
 // case; pass several for tools installable multiple ways on every platform.
@@ -19,7 +19,7 @@ func TestBuiltInTools(t *testing.T) {
 		t.Parallel()
 
 		tools := BuiltInTools()
-		require.Len(t, tools, 8, "expected 8 built-in tools")
+		require.Len(t, tools, 14, "expected 14 built-in tools")
 	})
 
 	t.Run("ContainsAllExpectedToolIDs", func(t *testing.T) {
@@ -34,6 +34,12 @@ func TestBuiltInTools(t *testing.T) {
 			"azure-mcp-server",
 			"azure.ai.agents",
 			"azure-skills",
+			"bicep-cli",
+			"terraform",
+			"kubectl",
+			"helm",
+			"pack",
+			"swa-cli",
 		}
 
 		tools := BuiltInTools()
@@ -522,3 +528,58 @@ func TestBuiltInTools_SkillAgentsHaveCommand(t *testing.T) {
 		}
 	}
 }
+
+// TestProviderToolsVersionRegex checks that each provider tool's VersionRegex
+// extracts the version from the tool's real version output.
+func TestProviderToolsVersionRegex(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		id      string
+		output  string
+		wantVer string
+	}{
+		{"bicep-cli", "Bicep CLI version 0.45.15 (a1b2c3d4e5)", "0.45.15"},
+		{"terraform", "Terraform v1.9.5\non linux_amd64", "1.9.5"},
+		{"kubectl", "Client Version: v1.31.1\nKustomize Version: v5.4.2", "1.31.1"},
+		{"helm", "v3.16.2+g13654a5", "3.16.2"},
+		{"pack", "0.35.1+git-3a22a7f.build-6099", "0.35.1"},
+		{"swa-cli", "2.0.1", "2.0.1"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.id, func(t *testing.T) {
+			t.Parallel()
+
+			td := FindTool(tc.id)
+			require.NotNil(t, td)
+			assert.Equal(t, tc.wantVer, matchVersion(tc.output, td.VersionRegex))
+		})
+	}
+}
+
+func TestToolStatusBelowMinVersion(t *testing.T) {
+	t.Parallel()
+
+	pinned := &ToolDefinition{Id: "pinned", MinVersion: "1.2.0"}
+
+	tests := []struct {
+		name   string
+		status ToolStatus
+		want   bool
+	}{
+		{"older", ToolStatus{Tool: pinned, Installed: true, InstalledVersion: "1.1.9"}, true},
+		{"equal", ToolStatus{Tool: pinned, Installed: true, InstalledVersion: "1.2.0"}, false},
+		{"newer", ToolStatus{Tool: pinned, Installed: true, InstalledVersion: "2.0.0"}, false},
+		{"unknown version", ToolStatus{Tool: pinned, Installed: true}, false},
+		{"not installed", ToolStatus{Tool: pinned}, false},
+		{"no pin", ToolStatus{Tool: &ToolDefinition{Id: "any"}, Installed: true, InstalledVersion: "0.0.1"}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, tc.status.BelowMinVersion())
+		})
+	}
+}
@@ -70,6 +70,7 @@ func (b *bashExecutor) Execute(
 	runArgs = runArgs.
 		WithCwd(execCtx.Cwd).
 		WithEnv(execCtx.EnvVars).
+		WithEnvIsolation(execCtx.EnvIsolation).
 		WithShell(true)
 
 	if execCtx.Interactive != nil {
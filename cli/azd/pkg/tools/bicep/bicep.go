@@ -24,6 +24,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tool"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/blang/semver/v4"
 )
@@ -32,6 +33,17 @@ import (
 // user).
 var Version semver.Version = semver.MustParse("0.45.15")
 
+// releaseChecksum returns the checksum pinned in the tool manifest for a bicep release. It is a var so tests can pin the
+// checksums of their fake releases.
+var releaseChecksum = func(version semver.Version, releaseName string) tool.Checksum {
+	bicepTool := tool.FindTool("bicep-cli")
+	if bicepTool == nil {
+		return tool.Checksum{}
+	}
+
+	return bicepTool.ArtifactChecksum(version.String(), releaseName)
+}
+
 // Cli is a wrapper around the bicep CLI.
 // The CLI automatically ensures bicep is installed before executing commands.
 //
@@ -166,54 +178,16 @@ func azdBicepPath() (string, error) {
 
 // downloadBicep downloads a given version of bicep from the release site, writing the output to name.
 func downloadBicep(ctx context.Context, transporter policy.Transporter, bicepVersion semver.Version, name string) error {
-	var arch string
-	switch runtime.GOARCH {
-	case "amd64":
-		arch = "x64"
-	case "arm64":
-		arch = "arm64"
-	default:
-		return fmt.Errorf("unsupported architecture: %s", runtime.GOARCH)
-	}
-
-	var releaseName string
-	switch runtime.GOOS {
-	case "windows":
-		releaseName = fmt.Sprintf("bicep-win-%s.exe", arch)
-	case "darwin":
-		releaseName = fmt.Sprintf("bicep-osx-%s", arch)
-	case "linux":
-		if preferMuslBicep(os.Stat) {
-			if runtime.GOARCH != "arm64" {
-				return fmt.Errorf("unsupported architecture: %s", runtime.GOARCH)
-			}
-
-			releaseName = "bicep-linux-musl-x64"
-		} else {
-			releaseName = fmt.Sprintf("bicep-linux-%s", arch)
-		}
-	default:
-		return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+	musl := runtime.GOOS == "linux" && preferMuslBicep(os.Stat)
+	releaseName, err := bicepReleaseName(runtime.GOOS, runtime.GOARCH, musl)
+	if err != nil {
+		return err
 	}
 
 	bicepReleaseUrl := fmt.Sprintf("https://downloads.bicep.azure.com/v%s/%s", bicepVersion, releaseName)
 
 	log.Printf("downloading bicep release %s -> %s", bicepReleaseUrl, name)
 
-	req, err := http.NewRequestWithContext(ctx, "GET", bicepReleaseUrl, nil)
-	if err != nil {
-		return err
-	}
-
-	resp, err := transporter.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("http error %d", resp.StatusCode)
-	}
-
 	f, err := os.CreateTemp(filepath.Dir(name), fmt.Sprintf("%s.tmp*", filepath.Base(name)))
 	if err != nil {
 		return err
@@ -223,7 +197,8 @@ func downloadBicep(ctx context.Context, transporter policy.Transporter, bicepVer
 		_ = os.Remove(f.Name()) //nolint:gosec // G703: temp file cleanup
 	}()
 
-	if _, err := io.Copy(f, resp.Body); err != nil {
+	checksum := releaseChecksum(bicepVersion, releaseName)
+	if err := tool.DownloadArtifact(ctx, transporter, bicepReleaseUrl, checksum, f); err != nil {
 		return err
 	}
 
@@ -242,6 +217,38 @@ func downloadBicep(ctx context.Context, transporter policy.Transporter, bicepVer
 	return nil
 }
 
+// bicepReleaseName returns the name of the bicep release asset for the given platform.
+func bicepReleaseName(goos string, goarch string, musl bool) (string, error) {
+	var arch string
+	switch goarch {
+	case "amd64":
+		arch = "x64"
+	case "arm64":
+		arch = "arm64"
+	default:
+		return "", fmt.Errorf("unsupported architecture: %s", goarch)
+	}
+
+	switch goos {
+	case "windows":
+		return fmt.Sprintf("bicep-win-%s.exe", arch), nil
+	case "darwin":
+		return fmt.Sprintf("bicep-osx-%s", arch), nil
+	case "linux":
+		if musl {
+			if goarch != "arm64" {
+				return "", fmt.Errorf("unsupported architecture: %s", goarch)
+			}
+
+			return "bicep-linux-musl-x64", nil
+		}
+
+		return fmt.Sprintf("bicep-linux-%s", arch), nil
+	default:
+		return "", fmt.Errorf("unsupported platform: %s", goos)
+	}
+}
+
 type stater func(name string) (os.FileInfo, error)
 
 // preferMuslBicep determines if we should install the version of bicep that used musl instead of glibc. We prefer
@@ -406,6 +413,10 @@ func (cli *Cli) Build(ctx context.Context, file string) (BuildResult, error) {
 	buildRes, err := cli.runCommand(ctx, nil, args...)
 
 	if err != nil {
+		if authErr := registryAuthError(buildRes.Stderr+"\n"+err.Error(), err); authErr != nil {
+			err = authErr
+		}
+
 		return BuildResult{}, fmt.Errorf(
 			"failed running bicep build: %w",
 			err,
@@ -435,6 +446,10 @@ func (cli *Cli) BuildBicepParam(ctx context.Context, file string, env []string)
 	buildRes, err := cli.runCommand(ctx, env, args...)
 
 	if err != nil {
+		if authErr := registryAuthError(buildRes.Stderr+"\n"+err.Error(), err); authErr != nil {
+			err = authErr
+		}
+
 		return BuildResult{}, fmt.Errorf(
 			"failed running bicep build: %w",
 			err,
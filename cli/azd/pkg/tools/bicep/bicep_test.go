@@ -5,6 +5,8 @@ package bicep
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -21,15 +23,29 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tool"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/blang/semver/v4"
 )
 
+// pinReleaseChecksum pins the checksum of content for every bicep release for the duration of the test.
+func pinReleaseChecksum(t *testing.T, content string) {
+	original := releaseChecksum
+	t.Cleanup(func() { releaseChecksum = original })
+
+	sum := sha256.Sum256([]byte(content))
+	releaseChecksum = func(semver.Version, string) tool.Checksum {
+		return tool.Checksum{Algorithm: "sha256", Value: hex.EncodeToString(sum[:])}
+	}
+}
+
 func TestNewBicepCli(t *testing.T) {
 	configRoot := t.TempDir()
 	t.Setenv("AZD_CONFIG_DIR", configRoot)
 
 	mockContext := mocks.NewMockContext(t.Context())
+	pinReleaseChecksum(t, "this is bicep")
 
 	mockContext.HttpClient.When(func(request *http.Request) bool {
 		return request.Method == http.MethodGet && request.URL.Host == "downloads.bicep.azure.com"
@@ -93,6 +109,7 @@ func TestNewBicepCliWillUpgrade(t *testing.T) {
 	require.NoError(t, err)
 
 	mockContext := mocks.NewMockContext(t.Context())
+	pinReleaseChecksum(t, NEW_FILE_CONTENTS)
 
 	mockContext.HttpClient.When(func(request *http.Request) bool {
 		return request.Method == http.MethodGet && request.URL.Host == "downloads.bicep.azure.com"
@@ -547,6 +564,7 @@ func TestEnsureInstalled_VersionCheckFailure(t *testing.T) {
 	t.Setenv("AZD_BICEP_TOOL_PATH", "")
 
 	mockContext := mocks.NewMockContext(t.Context())
+	pinReleaseChecksum(t, "bicep-bytes")
 	mockContext.HttpClient.When(func(request *http.Request) bool {
 		return request.URL.Host == "downloads.bicep.azure.com"
 	}).Respond(&http.Response{
@@ -570,6 +588,7 @@ func TestDownloadBicep_HttpError(t *testing.T) {
 	target := filepath.Join(dir, "bicep.out")
 
 	mockContext := mocks.NewMockContext(t.Context())
+	pinReleaseChecksum(t, "this is bicep")
 	mockContext.HttpClient.When(func(request *http.Request) bool {
 		return request.URL.Host == "downloads.bicep.azure.com"
 	}).Respond(&http.Response{
@@ -587,6 +606,7 @@ func TestDownloadBicep_TransportError(t *testing.T) {
 	target := filepath.Join(dir, "bicep.out")
 
 	mockContext := mocks.NewMockContext(t.Context())
+	pinReleaseChecksum(t, "this is bicep")
 	mockContext.HttpClient.When(func(request *http.Request) bool {
 		return true
 	}).SetNonRetriableError(fmt.Errorf("network is down"))
@@ -596,6 +616,38 @@ func TestDownloadBicep_TransportError(t *testing.T) {
 	require.Contains(t, err.Error(), "network is down")
 }
 
+func TestDownloadBicep_ChecksumMismatch(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "bicep.out")
+
+	mockContext := mocks.NewMockContext(t.Context())
+	pinReleaseChecksum(t, "this is bicep")
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.URL.Host == "downloads.bicep.azure.com"
+	}).Respond(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString("this is tampered bicep")),
+	})
+
+	err := downloadBicep(t.Context(), mockContext.HttpClient, Version, target)
+	require.ErrorContains(t, err, "checksum verification failed")
+	require.NoFileExists(t, target)
+}
+
+func TestDownloadBicep_MissingChecksum(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "bicep.out")
+
+	original := releaseChecksum
+	t.Cleanup(func() { releaseChecksum = original })
+	releaseChecksum = func(semver.Version, string) tool.Checksum { return tool.Checksum{} }
+
+	mockContext := mocks.NewMockContext(t.Context())
+	err := downloadBicep(t.Context(), mockContext.HttpClient, Version, target)
+	require.ErrorIs(t, err, tool.ErrMissingChecksum)
+	require.NoFileExists(t, target)
+}
+
 func TestRunStep_ActionFailure(t *testing.T) {
 	mockContext := mocks.NewMockContext(t.Context())
 	err := runStep(*mockContext.Context, mockContext.Console, "Working", func() error {
@@ -615,3 +667,34 @@ func TestAzdBicepPath(t *testing.T) {
 	require.NotEmpty(t, p)
 	require.True(t, strings.HasSuffix(p, "bicep") || strings.HasSuffix(p, "bicep.exe"))
 }
+
+func TestVersionMatchesToolManifest(t *testing.T) {
+	// `azd tool list` reports bicep installs older than the manifest pin, so it must track the version azd downloads.
+	require.Equal(t, Version.String(), tool.FindTool("bicep-cli").MinVersion)
+}
+
+func TestReleaseChecksumsPinned(t *testing.T) {
+	// downloadBicep refuses releases without a checksum, so every asset it can request for the pinned version must
+	// have one in the tool manifest.
+	bicepTool := tool.FindTool("bicep-cli")
+	require.NotNil(t, bicepTool)
+
+	for _, goos := range []string{"windows", "darwin", "linux"} {
+		for _, goarch := range []string{"amd64", "arm64"} {
+			for _, musl := range []bool{false, true} {
+				if musl && goos != "linux" {
+					continue
+				}
+
+				releaseName, err := bicepReleaseName(goos, goarch, musl)
+				if err != nil {
+					continue
+				}
+
+				checksum := bicepTool.ArtifactChecksum(Version.String(), releaseName)
+				assert.Equal(t, "sha256", checksum.Algorithm, "%s has no pinned checksum", releaseName)
+				assert.Len(t, checksum.Value, sha256.Size*2, "%s has no pinned checksum", releaseName)
+			}
+		}
+	}
+}
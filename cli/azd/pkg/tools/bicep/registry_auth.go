@@ -0,0 +1,94 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// RegistryAuthError is returned by Build and BuildBicepParam when bicep can't restore modules from a registry because
+// it isn't authorized to pull them, such as `br:<registry>/...` modules in a private Azure Container Registry.
+//
+// Bicep restores modules with its own credential chain (configured by credentialPrecedence in bicepconfig.json), which
+// by default uses the Azure CLI and Azure PowerShell sign-ins rather than the azd one.
+type RegistryAuthError struct {
+	// Registries are the registries that modules couldn't be restored from, in the order they were reported.
+	Registries []string
+	Err        error
+}
+
+func (e *RegistryAuthError) Error() string {
+	return fmt.Sprintf("not authorized to restore modules from %s: %v", strings.Join(e.Registries, ", "), e.Err)
+}
+
+func (e *RegistryAuthError) Unwrap() error {
+	return e.Err
+}
+
+// restoreFailurePattern matches the BCP192 diagnostic that bicep reports when a module can't be restored, capturing
+// the module reference. The reason follows it, and can span several lines.
+var restoreFailurePattern = regexp.MustCompile(`BCP192: Unable to restore the (?:artifact|module) with reference "([^"]+)"`)
+
+// authFailureMarkers are the fragments of a restore failure reason that mean the credential was missing or rejected.
+var authFailureMarkers = []string{
+	"401",
+	"403",
+	"unauthorized",
+	"forbidden",
+	"credentialunavailable",
+	"authenticationfailed",
+	"authentication failed",
+	"credential chain",
+}
+
+// registryAuthError returns a *RegistryAuthError wrapping err when the output of a bicep build reports that modules
+// couldn't be restored because of missing or rejected credentials. Otherwise it returns nil.
+func registryAuthError(output string, err error) *RegistryAuthError {
+	var registries []string
+	matches := restoreFailurePattern.FindAllStringSubmatchIndex(output, -1)
+	for i, match := range matches {
+		// The reason runs until the next restore failure.
+		end := len(output)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+
+		reason := strings.ToLower(output[match[1]:end])
+		if !slices.ContainsFunc(authFailureMarkers, func(marker string) bool {
+			return strings.Contains(reason, marker)
+		}) {
+			continue
+		}
+
+		if registry := moduleRegistry(output[match[2]:match[3]]); !slices.Contains(registries, registry) {
+			registries = append(registries, registry)
+		}
+	}
+
+	if len(registries) == 0 {
+		return nil
+	}
+
+	return &RegistryAuthError{Registries: registries, Err: err}
+}
+
+// moduleRegistry returns the registry of a module reference. For `br:<registry>/<path>:<tag>` references it's the
+// login server of the registry. For references that use an alias from bicepconfig.json, such as `br/<alias>:<path>`,
+// the registry isn't known so the alias is returned.
+func moduleRegistry(reference string) string {
+	if rest, ok := strings.CutPrefix(reference, "br:"); ok {
+		registry, _, _ := strings.Cut(rest, "/")
+		return registry
+	}
+
+	if rest, ok := strings.CutPrefix(reference, "br/"); ok {
+		alias, _, _ := strings.Cut(rest, ":")
+		return "br/" + alias
+	}
+
+	return reference
+}
@@ -0,0 +1,69 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryAuthError(t *testing.T) {
+	buildErr := errors.New("exit code: 1")
+
+	tests := []struct {
+		name       string
+		output     string
+		registries []string
+	}{
+		{
+			name: "Unauthorized",
+			output: `main.bicep(3,22) : Error BCP192: Unable to restore the artifact with reference ` +
+				`"br:contoso.azurecr.io/bicep/modules/storage:v1": Service request failed.` + "\n" +
+				`Status: 401 (Unauthorized)`,
+			registries: []string{"contoso.azurecr.io"},
+		},
+		{
+			name: "CredentialUnavailable",
+			output: `main.bicep(3,22) : Error BCP192: Unable to restore the artifact with reference ` +
+				`"br:contoso.azurecr.io/bicep/modules/storage:v1": Unhandled exception: ` +
+				`Azure.Identity.CredentialUnavailableException: DefaultAzureCredential failed to retrieve a token.`,
+			registries: []string{"contoso.azurecr.io"},
+		},
+		{
+			name: "SeveralModules",
+			output: `main.bicep(3,22) : Error BCP192: Unable to restore the artifact with reference ` +
+				`"br:contoso.azurecr.io/bicep/modules/storage:v1": Status: 403 (Forbidden)` + "\n" +
+				`main.bicep(9,22) : Error BCP192: Unable to restore the artifact with reference ` +
+				`"br:contoso.azurecr.io/bicep/modules/web:v1": Status: 403 (Forbidden)` + "\n" +
+				`main.bicep(15,22) : Error BCP192: Unable to restore the artifact with reference ` +
+				`"br/CoreModules:network:v2": Status: 401 (Unauthorized)`,
+			registries: []string{"contoso.azurecr.io", "br/CoreModules"},
+		},
+		{
+			name: "NotFound",
+			output: `main.bicep(3,22) : Error BCP192: Unable to restore the artifact with reference ` +
+				`"br:contoso.azurecr.io/bicep/modules/storage:v9": The artifact does not exist in the registry.`,
+		},
+		{
+			name:   "OtherError",
+			output: `main.bicep(1,1) : Error BCP007: This declaration type is not recognized.`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authErr := registryAuthError(tt.output, buildErr)
+			if tt.registries == nil {
+				require.Nil(t, authErr)
+				return
+			}
+
+			require.NotNil(t, authErr)
+			require.Equal(t, tt.registries, authErr.Registries)
+			require.ErrorIs(t, authErr, buildErr)
+		})
+	}
+}
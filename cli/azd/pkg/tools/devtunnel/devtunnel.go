@@ -0,0 +1,153 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package devtunnel
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+var _ tools.ExternalTool = (*Cli)(nil)
+
+// Cli is the Dev Tunnels CLI, which exposes local ports with public URLs.
+type Cli struct {
+	commandRunner exec.CommandRunner
+}
+
+func NewCli(commandRunner exec.CommandRunner) *Cli {
+	return &Cli{
+		commandRunner: commandRunner,
+	}
+}
+
+// Create creates the tunnel tunnelId, which allows anonymous access and forwards port. The tunnel expires after a day
+// when it isn't deleted.
+func (cli *Cli) Create(ctx context.Context, tunnelId string, port int) error {
+	runArgs := exec.NewRunArgs("devtunnel", "create", tunnelId, "--allow-anonymous", "--expiration", "1d")
+	if _, err := cli.commandRunner.Run(ctx, runArgs); err != nil {
+		return fmt.Errorf("devtunnel create: %w", err)
+	}
+
+	runArgs = exec.NewRunArgs("devtunnel", "port", "create", tunnelId, "--port-number", strconv.Itoa(port))
+	if _, err := cli.commandRunner.Run(ctx, runArgs); err != nil {
+		return fmt.Errorf("devtunnel port create: %w", err)
+	}
+
+	return nil
+}
+
+// Host hosts the tunnel tunnelId until ctx is done, and calls onUrl with the public URL of port once the tunnel
+// accepts connections.
+func (cli *Cli) Host(ctx context.Context, tunnelId string, port int, onUrl func(url string)) error {
+	out := &hostWriter{port: port, onUrl: onUrl}
+	runArgs := exec.NewRunArgs("devtunnel", "host", tunnelId).WithStdOut(out)
+	if _, err := cli.commandRunner.Run(ctx, runArgs); err != nil {
+		return fmt.Errorf("devtunnel host: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes the tunnel tunnelId.
+func (cli *Cli) Delete(ctx context.Context, tunnelId string) error {
+	runArgs := exec.NewRunArgs("devtunnel", "delete", tunnelId, "--force")
+	if _, err := cli.commandRunner.Run(ctx, runArgs); err != nil {
+		return fmt.Errorf("devtunnel delete: %w", err)
+	}
+
+	return nil
+}
+
+func (cli *Cli) CheckInstalled(_ context.Context) error {
+	return cli.commandRunner.ToolInPath("devtunnel")
+}
+
+func (cli *Cli) Name() string {
+	return "Dev Tunnels CLI"
+}
+
+func (cli *Cli) InstallUrl() string {
+	return "https://learn.microsoft.com/azure/developer/dev-tunnels/get-started"
+}
+
+var urlRegex = regexp.MustCompile(`https://[^\s,]+`)
+
+// hostWriter reads the output of devtunnel host, which lists the URLs of each port of the tunnel on a line like:
+//
+//	Connect via browser: https://abcd1234.usw2.devtunnels.ms:8080, https://abcd1234-8080.usw2.devtunnels.ms
+//
+// and calls onUrl once with the URL of port that doesn't need the port in the URL.
+type hostWriter struct {
+	mu      sync.Mutex
+	port    int
+	onUrl   func(url string)
+	pending []byte
+	found   bool
+}
+
+func (w *hostWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.pending = append(w.pending, p...)
+	for {
+		index := bytes.IndexByte(w.pending, '\n')
+		if index < 0 {
+			break
+		}
+
+		line := string(w.pending[:index])
+		w.pending = w.pending[index+1:]
+
+		if w.found || !strings.Contains(line, "Connect via browser:") {
+			continue
+		}
+
+		if tunnelUrl := portUrl(line, w.port); tunnelUrl != "" {
+			w.found = true
+			w.onUrl(tunnelUrl)
+		}
+	}
+
+	return len(p), nil
+}
+
+// portUrl returns the URL of port in line, preferring the URL without an explicit port, or else the first URL of line.
+func portUrl(line string, port int) string {
+	matches := urlRegex.FindAllString(line, -1)
+	if len(matches) == 0 {
+		return ""
+	}
+
+	var withPort string
+	for _, match := range matches {
+		parsed, err := url.Parse(match)
+		if err != nil {
+			continue
+		}
+
+		if parsed.Port() == "" && strings.Contains(parsed.Hostname(), fmt.Sprintf("-%d.", port)) {
+			return strings.TrimSuffix(match, "/")
+		}
+
+		if parsed.Port() == strconv.Itoa(port) && withPort == "" {
+			withPort = strings.TrimSuffix(match, "/")
+		}
+	}
+
+	if withPort != "" {
+		return withPort
+	}
+
+	return strings.TrimSuffix(matches[0], "/")
+}
@@ -0,0 +1,100 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package devtunnel
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DevTunnelCreate(t *testing.T) {
+	t.Run("NoErrors", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		cli := NewCli(mockContext.CommandRunner)
+
+		var ran [][]string
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return args.Cmd == "devtunnel"
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			ran = append(ran, args.Args)
+			return exec.RunResult{}, nil
+		})
+
+		require.NoError(t, cli.Create(t.Context(), "azd-api-1234", 8080))
+		require.Equal(t, [][]string{
+			{"create", "azd-api-1234", "--allow-anonymous", "--expiration", "1d"},
+			{"port", "create", "azd-api-1234", "--port-number", "8080"},
+		}, ran)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		cli := NewCli(mockContext.CommandRunner)
+
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.HasPrefix(command, "devtunnel create")
+		}).SetError(errors.New("not logged in"))
+
+		err := cli.Create(t.Context(), "azd-api-1234", 8080)
+		require.ErrorContains(t, err, "devtunnel create: not logged in")
+	})
+}
+
+func Test_DevTunnelHost(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{
+			name: "PortInHost",
+			output: "Hosting port: 8080\n" +
+				"Connect via browser: https://abcd1234-8080.usw2.devtunnels.ms\n" +
+				"Inspect network activity: https://abcd1234-8080-inspect.usw2.devtunnels.ms\n",
+			want: "https://abcd1234-8080.usw2.devtunnels.ms",
+		},
+		{
+			name: "BothUrls",
+			output: "Hosting port: 8080\r\n" +
+				"Connect via browser: https://abcd1234.usw2.devtunnels.ms:8080, " +
+				"https://abcd1234-8080.usw2.devtunnels.ms\r\n",
+			want: "https://abcd1234-8080.usw2.devtunnels.ms",
+		},
+		{
+			name:   "ExplicitPort",
+			output: "Connect via browser: https://abcd1234.usw2.devtunnels.ms:8080\n",
+			want:   "https://abcd1234.usw2.devtunnels.ms:8080",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(t.Context())
+			cli := NewCli(mockContext.CommandRunner)
+
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return command == "devtunnel host azd-api-1234"
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				// Write the output in pieces, like a running process does.
+				for chunk := range strings.SplitAfterSeq(tt.output, ":") {
+					fmt.Fprint(args.StdOut, chunk)
+				}
+				return exec.RunResult{}, nil
+			})
+
+			var urls []string
+			err := cli.Host(t.Context(), "azd-api-1234", 8080, func(url string) {
+				urls = append(urls, url)
+			})
+			require.NoError(t, err)
+			require.Equal(t, []string{tt.want}, urls)
+		})
+	}
+}
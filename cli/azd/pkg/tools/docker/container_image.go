@@ -5,6 +5,7 @@ package docker
 
 import (
 	"errors"
+	"regexp"
 	"strings"
 )
 
@@ -96,3 +97,16 @@ func ParseContainerImage(image string) (*ContainerImage, error) {
 
 	return containerImage, nil
 }
+
+var sha256DigestRegex = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// SplitImageDigest splits an image referenced by digest, such as myregistry.azurecr.io/api@sha256:<digest>, into its
+// name and its digest. ok is false when the image isn't referenced by a SHA-256 digest.
+func SplitImageDigest(image string) (name string, digest string, ok bool) {
+	name, digest, found := strings.Cut(image, "@")
+	if !found || name == "" || !sha256DigestRegex.MatchString(digest) {
+		return "", "", false
+	}
+
+	return name, digest, true
+}
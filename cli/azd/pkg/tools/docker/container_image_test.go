@@ -4,6 +4,7 @@
 package docker
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -186,3 +187,22 @@ func Test_ContainerImage_Local_And_Remote(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitImageDigest(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("ab", 32)
+
+	name, actualDigest, ok := SplitImageDigest("myregistry.azurecr.io/api@" + digest)
+	require.True(t, ok)
+	require.Equal(t, "myregistry.azurecr.io/api", name)
+	require.Equal(t, digest, actualDigest)
+
+	for _, image := range []string{
+		"myregistry.azurecr.io/api:latest",
+		"myregistry.azurecr.io/api@sha256:abcd",
+		"myregistry.azurecr.io/api@sha512:" + strings.Repeat("ab", 32),
+		"@" + digest,
+	} {
+		_, _, ok := SplitImageDigest(image)
+		require.False(t, ok, image)
+	}
+}
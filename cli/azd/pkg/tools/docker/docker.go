@@ -216,6 +216,17 @@ func (d *Cli) Inspect(ctx context.Context, imageName string, format string) (str
 	return out.Stdout, nil
 }
 
+// ManifestInspect returns the manifest of an image from its registry, without pulling the image. It fails when the image
+// isn't in the registry.
+func (d *Cli) ManifestInspect(ctx context.Context, imageName string) (string, error) {
+	out, err := d.executeCommand(ctx, "", "manifest", "inspect", imageName)
+	if err != nil {
+		return "", fmt.Errorf("inspecting manifest of image %s: %w", imageName, err)
+	}
+
+	return out.Stdout, nil
+}
+
 // Remove deletes a local Docker image by name or ID
 func (d *Cli) Remove(ctx context.Context, imageName string) error {
 	_, err := d.executeCommand(ctx, "", "rmi", imageName)
@@ -110,6 +110,7 @@ func (cli *Cli) Restore(ctx context.Context, project string, env []string) error
 	if len(env) > 0 {
 		runArgs = runArgs.WithEnv(append(runArgs.Env, env...))
 	}
+	runArgs = withProjectEnvIsolation(ctx, runArgs)
 	_, err := cli.commandRunner.Run(ctx, runArgs)
 	if err != nil {
 		return fmt.Errorf("dotnet restore on project '%s' failed: %w", project, err)
@@ -131,6 +132,7 @@ func (cli *Cli) Build(ctx context.Context, project string, configuration string,
 	if len(env) > 0 {
 		runArgs = runArgs.WithEnv(append(runArgs.Env, env...))
 	}
+	runArgs = withProjectEnvIsolation(ctx, runArgs)
 
 	_, err := cli.commandRunner.Run(ctx, runArgs)
 	if err != nil {
@@ -153,6 +155,7 @@ func (cli *Cli) Publish(ctx context.Context, project string, configuration strin
 	if len(env) > 0 {
 		runArgs = runArgs.WithEnv(append(runArgs.Env, env...))
 	}
+	runArgs = withProjectEnvIsolation(ctx, runArgs)
 
 	_, err := cli.commandRunner.Run(ctx, runArgs)
 	if err != nil {
@@ -598,6 +601,14 @@ func (cli *Cli) GitIgnore(ctx context.Context, projectPath string, options *GitI
 	return err
 }
 
+// withProjectEnvIsolation applies the environment isolation carried by ctx to a command that builds the user's project,
+// keeping the variables set by newDotNetRunArgs.
+func withProjectEnvIsolation(ctx context.Context, runArgs exec.RunArgs) exec.RunArgs {
+	return runArgs.WithEnvIsolation(
+		exec.EnvIsolationFromContext(ctx).With("DOTNET_CLI_WORKLOAD_UPDATE_NOTIFY_DISABLE", "DOTNET_NOLOGO"),
+	)
+}
+
 // newDotNetRunArgs creates a new RunArgs to run the specified dotnet command. It sets the environment variable
 // to disable output of workload update notifications, to make it easier for us to parse the output.
 func newDotNetRunArgs(args ...string) exec.RunArgs {
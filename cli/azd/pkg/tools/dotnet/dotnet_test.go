@@ -7,6 +7,7 @@ import (
 	_ "embed"
 	"testing"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/stretchr/testify/require"
 )
@@ -32,3 +33,15 @@ func Test_getTargetPort(t *testing.T) {
 		"\r\nWorkload updates are available. Run `dotnet workload list` for more information.\r\n", "")
 	require.Error(t, err)
 }
+
+func Test_withProjectEnvIsolation(t *testing.T) {
+	runArgs := withProjectEnvIsolation(t.Context(), newDotNetRunArgs("build"))
+	require.Nil(t, runArgs.EnvIsolation)
+
+	ctx := exec.WithEnvIsolation(t.Context(), &exec.EnvIsolation{Allow: []string{"API_URL"}})
+	runArgs = withProjectEnvIsolation(ctx, newDotNetRunArgs("build"))
+	require.NotNil(t, runArgs.EnvIsolation)
+	require.True(t, runArgs.EnvIsolation.Allows("API_URL"))
+	require.True(t, runArgs.EnvIsolation.Allows("DOTNET_NOLOGO"))
+	require.False(t, runArgs.EnvIsolation.Allows("AZURE_CLIENT_SECRET"))
+}
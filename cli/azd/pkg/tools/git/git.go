@@ -91,6 +91,51 @@ func (cli *Cli) ShallowClone(ctx context.Context, repositoryPath string, branch
 	return nil
 }
 
+// ShallowCloneCommit clones the given commit of the repository into target, an existing empty directory.
+func (cli *Cli) ShallowCloneCommit(ctx context.Context, repositoryPath string, commit string, target string) error {
+	if err := cli.InitRepo(ctx, target); err != nil {
+		return err
+	}
+
+	// Like ShallowClone, fetch with the default authentication so private repos can be fetched within a codespace.
+	runArgs := exec.NewRunArgs("git", "-C", target, "fetch", "--depth", "1", repositoryPath, commit)
+	if _, err := cli.commandRunner.Run(ctx, runArgs); err != nil {
+		return fmt.Errorf("failed to fetch commit %s of repository %s: %w", commit, repositoryPath, err)
+	}
+
+	runArgs = newRunArgs("-C", target, "checkout", "--quiet", "FETCH_HEAD")
+	if _, err := cli.commandRunner.Run(ctx, runArgs); err != nil {
+		return fmt.Errorf("failed to checkout commit %s: %w", commit, err)
+	}
+
+	return nil
+}
+
+// MergeFile merges the changes from base to other into the file current, and returns the merged contents, without
+// modifying current. Conflicts are marked in the merged contents with the given labels of current, base and other.
+func (cli *Cli) MergeFile(
+	ctx context.Context,
+	current string,
+	base string,
+	other string,
+	labels [3]string,
+) (merged string, conflicts bool, err error) {
+	runArgs := newRunArgs(
+		"merge-file", "-p",
+		"-L", labels[0], "-L", labels[1], "-L", labels[2],
+		current, base, other)
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+
+	// merge-file exits with the number of conflicts, and with a negative code on errors
+	if exitErr, ok := errors.AsType[*exec.ExitError](err); ok && exitErr.ExitCode > 0 && exitErr.ExitCode < 128 {
+		return res.Stdout, true, nil
+	} else if err != nil {
+		return "", false, fmt.Errorf("failed to merge file %s: %w", current, err)
+	}
+
+	return res.Stdout, false, nil
+}
+
 var noSuchRemoteRegex = regexp.MustCompile("(fatal|error): No such remote")
 var notGitRepositoryRegex = regexp.MustCompile("(fatal|error): not a git repository")
 var ErrNoSuchRemote = errors.New("no such remote")
@@ -135,6 +180,19 @@ func (cli *Cli) GetRepoRoot(ctx context.Context, repositoryPath string) (string,
 	return strings.TrimSpace(res.Stdout), nil
 }
 
+// GetHeadCommit returns the full sha of the commit checked out in the repository.
+func (cli *Cli) GetHeadCommit(ctx context.Context, repositoryPath string) (string, error) {
+	runArgs := newRunArgs("-C", repositoryPath, "rev-parse", "HEAD")
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if notGitRepositoryRegex.MatchString(res.Stderr) {
+		return "", ErrNotRepository
+	} else if err != nil {
+		return "", fmt.Errorf("failed to get head commit: %w", err)
+	}
+
+	return strings.TrimSpace(res.Stdout), nil
+}
+
 func (cli *Cli) InitRepo(ctx context.Context, repositoryPath string) error {
 	runArgs := newRunArgs("-C", repositoryPath, "init")
 	_, err := cli.commandRunner.Run(ctx, runArgs)
@@ -5,7 +5,10 @@ package git
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"slices"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -351,6 +354,56 @@ func TestGetRepoRoot(t *testing.T) {
 	}
 }
 
+func TestGetHeadCommit(t *testing.T) {
+	tests := []struct {
+		name       string
+		stdout     string
+		stderr     string
+		err        error
+		wantCommit string
+		wantErr    error
+	}{
+		{
+			name:       "Success",
+			stdout:     "3f2c1a9e5b7d4c6a8e0f1b2d3c4e5f6a7b8c9d0e\n",
+			wantCommit: "3f2c1a9e5b7d4c6a8e0f1b2d3c4e5f6a7b8c9d0e",
+		},
+		{
+			name:    "NotARepo",
+			stderr:  "fatal: not a git repository (or any parent)",
+			err:     errors.New("exit code: 128"),
+			wantErr: ErrNotRepository,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			runner := mockexec.NewMockCommandRunner()
+			runner.When(func(args exec.RunArgs, command string) bool {
+				return slices.Contains(args.Args, "rev-parse") && slices.Contains(args.Args, "HEAD")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				return exec.RunResult{
+					Stdout: tt.stdout,
+					Stderr: tt.stderr,
+				}, tt.err
+			})
+
+			cli := NewCli(runner)
+			commit, err := cli.GetHeadCommit(t.Context(), "/repo")
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantCommit, commit)
+		})
+	}
+}
+
 func TestShallowClone(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -426,6 +479,56 @@ func TestShallowClone(t *testing.T) {
 	}
 }
 
+func TestShallowCloneCommit(t *testing.T) {
+	var commands []string
+	runner := mockexec.NewMockCommandRunner()
+	runner.When(func(args exec.RunArgs, command string) bool {
+		return true
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		commands = append(commands, strings.Join(args.Args, " "))
+		return exec.RunResult{}, nil
+	})
+
+	cli := NewCli(runner)
+	err := cli.ShallowCloneCommit(t.Context(), "https://github.com/user/repo", "abc123", "/target")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"-C /target init",
+		"-C /target checkout -b main",
+		"-C /target fetch --depth 1 https://github.com/user/repo abc123",
+		"-C /target checkout --quiet FETCH_HEAD",
+	}, commands)
+}
+
+func TestMergeFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+
+	base := write("base", "a\nb\nc\n")
+	labels := [3]string{"local", "base", "template"}
+	cli := NewCli(exec.NewCommandRunner(nil))
+
+	t.Run("Clean", func(t *testing.T) {
+		merged, conflicts, err := cli.MergeFile(
+			t.Context(), write("current", "a\nb\nc\nlocal\n"), base, write("other", "template\na\nb\nc\n"), labels)
+		require.NoError(t, err)
+		require.False(t, conflicts)
+		require.Equal(t, "template\na\nb\nc\nlocal\n", merged)
+	})
+
+	t.Run("Conflicts", func(t *testing.T) {
+		merged, conflicts, err := cli.MergeFile(
+			t.Context(), write("current", "a\nlocal\nc\n"), base, write("other", "a\ntemplate\nc\n"), labels)
+		require.NoError(t, err)
+		require.True(t, conflicts)
+		require.Contains(t, merged, "<<<<<<< local\nlocal\n=======\ntemplate\n>>>>>>> template\n")
+	})
+}
+
 func TestInitRepo(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -6,6 +6,7 @@ package github
 import (
 	"archive/tar"
 	"archive/zip"
+	"bytes"
 	"compress/gzip"
 	"context"
 	"encoding/json"
@@ -18,7 +19,9 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
@@ -79,6 +82,10 @@ var (
 	ErrUserNotAuthorized    = errors.New("user is not authorized. " +
 		"Try running gh auth refresh with the required scopes to request additional authorization")
 	ErrRepositoryNameInUse = errors.New("repository name already in use")
+	ErrReleaseNotFound     = errors.New("release not found")
+	ErrWorkflowNotFound    = errors.New("workflow not found")
+	// ErrWorkflowNotDispatchable is returned when running a workflow without the workflow_dispatch trigger.
+	ErrWorkflowNotDispatchable = errors.New("workflow does not have the workflow_dispatch trigger")
 
 	// The hostname of the public GitHub service.
 	GitHubHostName = "github.com"
@@ -305,8 +312,24 @@ func (cli *Cli) ListVariables(
 	return ghOutputToMap(output.Stdout)
 }
 
-func (cli *Cli) SetSecret(ctx context.Context, repoSlug string, name string, value string) error {
-	runArgs := cli.newRunArgs("-R", repoSlug, "secret", "set", name).WithStdIn(strings.NewReader(value))
+type SetSecretOptions struct {
+	Environment string
+}
+
+func (cli *Cli) SetSecret(
+	ctx context.Context,
+	repoSlug string,
+	name string,
+	value string,
+	options *SetSecretOptions,
+) error {
+	args := []string{"-R", repoSlug, "secret", "set", name}
+
+	if options != nil && options.Environment != "" {
+		args = append(args, "--env", options.Environment)
+	}
+
+	runArgs := cli.newRunArgs(args...).WithStdIn(strings.NewReader(value))
 	_, err := cli.run(ctx, runArgs)
 	if err != nil {
 		return fmt.Errorf("failed running gh secret set: %w", err)
@@ -481,6 +504,62 @@ func (cli *Cli) GitHubActionsExists(ctx context.Context, repoSlug string) (bool,
 	return true, nil
 }
 
+// WorkflowRun is a run of a GitHub Actions workflow.
+type WorkflowRun struct {
+	DatabaseId   int64  `json:"databaseId"`
+	DisplayTitle string `json:"displayTitle"`
+	HeadBranch   string `json:"headBranch"`
+	HeadSha      string `json:"headSha"`
+	Event        string `json:"event"`
+	// Status is queued, in_progress, waiting or completed, among others.
+	Status string `json:"status"`
+	// Conclusion is the result of a completed run, like success, failure or cancelled. Empty until the run completes.
+	Conclusion string    `json:"conclusion"`
+	Url        string    `json:"url"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// RunWorkflow triggers the workflow on the branch, through its workflow_dispatch trigger. The workflow is the file
+// name of the workflow, like azure-dev.yml. Returns ErrWorkflowNotFound when the repository has no such workflow.
+func (cli *Cli) RunWorkflow(ctx context.Context, repoSlug string, workflow string, branch string) error {
+	runArgs := cli.newRunArgs("-R", repoSlug, "workflow", "run", workflow, "--ref", branch)
+	res, err := cli.run(ctx, runArgs)
+	if workflowNotFoundRegex.MatchString(res.Stderr) {
+		return fmt.Errorf("%w: %s", ErrWorkflowNotFound, workflow)
+	} else if workflowNotDispatchableRegex.MatchString(res.Stderr) {
+		return fmt.Errorf("%w: %s", ErrWorkflowNotDispatchable, workflow)
+	} else if err != nil {
+		return fmt.Errorf("failed running gh workflow run: %w", err)
+	}
+	return nil
+}
+
+// ListWorkflowRuns returns the latest runs of the workflow on the branch, newest first.
+// Returns ErrWorkflowNotFound when the repository has no such workflow.
+func (cli *Cli) ListWorkflowRuns(
+	ctx context.Context, repoSlug string, workflow string, branch string, limit int) ([]WorkflowRun, error) {
+	runArgs := cli.newRunArgs(
+		"-R", repoSlug, "run", "list",
+		"--workflow", workflow,
+		"--branch", branch,
+		"--limit", strconv.Itoa(limit),
+		"--json", "databaseId,displayTitle,headBranch,headSha,event,status,conclusion,url,createdAt",
+	)
+	res, err := cli.run(ctx, runArgs)
+	if workflowNotFoundRegex.MatchString(res.Stderr) {
+		return nil, fmt.Errorf("%w: %s", ErrWorkflowNotFound, workflow)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed running gh run list: %w", err)
+	}
+
+	var runs []WorkflowRun
+	if err := json.Unmarshal([]byte(res.Stdout), &runs); err != nil {
+		return nil, fmt.Errorf("could not unmarshal output as a []WorkflowRun: %w, output: %s", err, res.Stdout)
+	}
+
+	return runs, nil
+}
+
 func (cli *Cli) CreateEnvironmentIfNotExist(ctx context.Context, repoName string, envName string) error {
 	// Doc: https://docs.github.com/en/rest/deployments/environments?apiVersion=2022-11-28#create-or-update-an-environment
 	runArgs := cli.newRunArgs("api",
@@ -493,6 +572,63 @@ func (cli *Cli) CreateEnvironmentIfNotExist(ctx context.Context, repoName string
 	return err
 }
 
+// SetEnvironmentReviewers requires an approval from one of the given users or teams before a job that targets the
+// environment runs. Reviewers are GitHub logins, or "org/team" slugs for teams.
+func (cli *Cli) SetEnvironmentReviewers(
+	ctx context.Context, repoName string, envName string, reviewers []string) error {
+	type environmentReviewer struct {
+		Type string `json:"type"`
+		Id   int64  `json:"id"`
+	}
+
+	body := struct {
+		Reviewers []environmentReviewer `json:"reviewers"`
+	}{
+		Reviewers: make([]environmentReviewer, 0, len(reviewers)),
+	}
+
+	for _, reviewer := range reviewers {
+		reviewerType := "User"
+		// Doc: https://docs.github.com/en/rest/users/users?apiVersion=2022-11-28#get-a-user
+		path := fmt.Sprintf("/users/%s", reviewer)
+		if org, team, isTeam := strings.Cut(reviewer, "/"); isTeam {
+			reviewerType = "Team"
+			// Doc: https://docs.github.com/en/rest/teams/teams?apiVersion=2022-11-28#get-a-team-by-name
+			path = fmt.Sprintf("/orgs/%s/teams/%s", org, team)
+		}
+
+		res, err := cli.run(ctx, cli.newRunArgs("api", path, "--jq", ".id"))
+		if err != nil {
+			return fmt.Errorf("failed looking up reviewer %s: %w", reviewer, err)
+		}
+
+		id, err := strconv.ParseInt(strings.TrimSpace(res.Stdout), 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed parsing id of reviewer %s: %w", reviewer, err)
+		}
+
+		body.Reviewers = append(body.Reviewers, environmentReviewer{Type: reviewerType, Id: id})
+	}
+
+	bodyJson, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	// Doc: https://docs.github.com/en/rest/deployments/environments?apiVersion=2022-11-28#create-or-update-an-environment
+	runArgs := cli.newRunArgs("api",
+		"-X", "PUT",
+		fmt.Sprintf("/repos/%s/environments/%s", repoName, envName),
+		"-H", "Accept: application/vnd.github+json",
+		"--input", "-",
+	).WithStdIn(bytes.NewReader(bodyJson))
+
+	if _, err := cli.run(ctx, runArgs); err != nil {
+		return fmt.Errorf("failed setting reviewers for environment %s: %w", envName, err)
+	}
+	return nil
+}
+
 func (cli *Cli) DeleteEnvironment(ctx context.Context, repoName string, envName string) error {
 	// Doc: https://docs.github.com/en/rest/deployments/environments?apiVersion=2022-11-28#delete-an-environment
 	runArgs := cli.newRunArgs("api",
@@ -505,6 +641,73 @@ func (cli *Cli) DeleteEnvironment(ctx context.Context, repoName string, envName
 	return err
 }
 
+type ghCliReleaseAsset struct {
+	Name string `json:"name"`
+}
+
+// ListReleaseAssets returns the names of the assets of the release with the tag.
+// Returns ErrReleaseNotFound when the repository has no release with the tag.
+func (cli *Cli) ListReleaseAssets(ctx context.Context, repoSlug string, tag string) ([]string, error) {
+	runArgs := cli.newRunArgs("-R", repoSlug, "release", "view", tag, "--json", "assets")
+	res, err := cli.run(ctx, runArgs)
+	if releaseNotFoundRegex.MatchString(res.Stderr) {
+		return nil, ErrReleaseNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed running gh release view: %w", err)
+	}
+
+	var release struct {
+		Assets []ghCliReleaseAsset `json:"assets"`
+	}
+	if err := json.Unmarshal([]byte(res.Stdout), &release); err != nil {
+		return nil, fmt.Errorf("could not unmarshal output as a release: %w, output: %s", err, res.Stdout)
+	}
+
+	names := make([]string, 0, len(release.Assets))
+	for _, asset := range release.Assets {
+		names = append(names, asset.Name)
+	}
+
+	return names, nil
+}
+
+// CreateRelease creates a release with the tag. The tag is created from the default branch when it doesn't exist.
+func (cli *Cli) CreateRelease(ctx context.Context, repoSlug string, tag string, notes string) error {
+	runArgs := cli.newRunArgs("-R", repoSlug, "release", "create", tag, "--title", tag, "--notes", notes)
+	_, err := cli.run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed running gh release create: %w", err)
+	}
+	return nil
+}
+
+// UploadReleaseAsset uploads the file at path as an asset of the release with the tag, named after the file.
+func (cli *Cli) UploadReleaseAsset(ctx context.Context, repoSlug string, tag string, path string) error {
+	runArgs := cli.newRunArgs("-R", repoSlug, "release", "upload", tag, path, "--clobber")
+	_, err := cli.run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed running gh release upload: %w", err)
+	}
+	return nil
+}
+
+// DownloadReleaseAsset downloads the asset of the release with the tag to the file at path.
+func (cli *Cli) DownloadReleaseAsset(
+	ctx context.Context,
+	repoSlug string,
+	tag string,
+	asset string,
+	path string,
+) error {
+	runArgs := cli.newRunArgs(
+		"-R", repoSlug, "release", "download", tag, "--pattern", asset, "--output", path, "--clobber")
+	_, err := cli.run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed running gh release download: %w", err)
+	}
+	return nil
+}
+
 func (cli *Cli) newRunArgs(args ...string) exec.RunArgs {
 
 	runArgs := exec.NewRunArgs(cli.path, args...)
@@ -533,6 +736,9 @@ var isGhCliNotLoggedInMessageRegex = regexp.MustCompile(
 	"(To authenticate, please run `gh auth login`\\.)|(Try authenticating with:  gh auth login)|(To re-authenticate, run: gh auth login)|(To get started with GitHub CLI, please run:  gh auth login)",
 )
 var repositoryNameInUseRegex = regexp.MustCompile(`GraphQL: Name already exists on this account \(createRepository\)`)
+var releaseNotFoundRegex = regexp.MustCompile(`release not found`)
+var workflowNotFoundRegex = regexp.MustCompile(`could not find any workflows named|HTTP 404: Not Found`)
+var workflowNotDispatchableRegex = regexp.MustCompile(`does not have 'workflow_dispatch' trigger`)
 
 var notLoggedIntoAnyGitHubHostsMessageRegex = regexp.MustCompile(
 	"You are not logged into any GitHub hosts.",
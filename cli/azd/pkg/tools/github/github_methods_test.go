@@ -6,6 +6,7 @@ package github
 import (
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"testing"
 
@@ -242,7 +243,24 @@ func TestSetSecret(t *testing.T) {
 		cli, mockCtx := newTestCli(t)
 		respondOK(mockCtx, "secret set", "")
 
-		err := cli.SetSecret(t.Context(), "o/r", "KEY", "val")
+		err := cli.SetSecret(t.Context(), "o/r", "KEY", "val", nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("WithEnvironment", func(t *testing.T) {
+		t.Parallel()
+		cli, mockCtx := newTestCli(t)
+		mockCtx.CommandRunner.When(
+			func(_ exec.RunArgs, cmd string) bool {
+				return strings.Contains(cmd, "secret set") &&
+					strings.Contains(cmd, "--env prod")
+			},
+		).Respond(exec.NewRunResult(0, "", ""))
+
+		err := cli.SetSecret(
+			t.Context(), "o/r", "KEY", "val",
+			&SetSecretOptions{Environment: "prod"},
+		)
 		require.NoError(t, err)
 	})
 
@@ -251,7 +269,7 @@ func TestSetSecret(t *testing.T) {
 		cli, mockCtx := newTestCli(t)
 		respondErr(mockCtx, "secret set")
 
-		err := cli.SetSecret(t.Context(), "o/r", "KEY", "val")
+		err := cli.SetSecret(t.Context(), "o/r", "KEY", "val", nil)
 		require.Error(t, err)
 		require.Contains(
 			t, err.Error(), "failed running gh secret set",
@@ -626,6 +644,49 @@ func TestCreateEnvironmentIfNotExist(t *testing.T) {
 	})
 }
 
+func TestSetEnvironmentReviewers(t *testing.T) {
+	t.Parallel()
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+		cli, mockCtx := newTestCli(t)
+		respondOK(mockCtx, "api /users/octocat", "583231\n")
+		respondOK(mockCtx, "api /orgs/contoso/teams/release", "42\n")
+
+		var body string
+		mockCtx.CommandRunner.When(
+			func(_ exec.RunArgs, cmd string) bool {
+				return strings.Contains(cmd, "-X PUT /repos/o/r/environments/prod")
+			},
+		).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			contents, err := io.ReadAll(args.StdIn)
+			body = string(contents)
+			return exec.NewRunResult(0, "", ""), err
+		})
+
+		err := cli.SetEnvironmentReviewers(
+			t.Context(), "o/r", "prod", []string{"octocat", "contoso/release"},
+		)
+		require.NoError(t, err)
+		require.JSONEq(
+			t,
+			`{"reviewers":[{"type":"User","id":583231},{"type":"Team","id":42}]}`,
+			body,
+		)
+	})
+
+	t.Run("UnknownReviewer", func(t *testing.T) {
+		t.Parallel()
+		cli, mockCtx := newTestCli(t)
+		respondErr(mockCtx, "api /users/ghost")
+
+		err := cli.SetEnvironmentReviewers(
+			t.Context(), "o/r", "prod", []string{"ghost"},
+		)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed looking up reviewer ghost")
+	})
+}
+
 func TestDeleteEnvironment(t *testing.T) {
 	t.Parallel()
 	t.Run("Success", func(t *testing.T) {
@@ -806,3 +867,144 @@ func TestLogVersion_Error(t *testing.T) {
 	// logVersion logs the error but doesn't panic or return it.
 	cli.logVersion(t.Context())
 }
+
+// --------------- Workflows ---------------
+
+func TestRunWorkflow(t *testing.T) {
+	t.Parallel()
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+		cli, mockCtx := newTestCli(t)
+		var runArgs []string
+		mockCtx.CommandRunner.When(
+			func(_ exec.RunArgs, cmd string) bool {
+				return strings.Contains(cmd, "workflow run")
+			},
+		).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			runArgs = args.Args
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		err := cli.RunWorkflow(t.Context(), "o/r", "azure-dev.yml", "main")
+		require.NoError(t, err)
+		require.Equal(t, []string{"-R", "o/r", "workflow", "run", "azure-dev.yml", "--ref", "main"}, runArgs)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+		cli, mockCtx := newTestCli(t)
+		mockCtx.CommandRunner.When(
+			func(_ exec.RunArgs, cmd string) bool {
+				return strings.Contains(cmd, "workflow run")
+			},
+		).RespondFn(func(_ exec.RunArgs) (exec.RunResult, error) {
+			return exec.NewRunResult(1, "", "could not find any workflows named azure-dev.yml"),
+				errors.New("exit 1")
+		})
+
+		err := cli.RunWorkflow(t.Context(), "o/r", "azure-dev.yml", "main")
+		require.ErrorIs(t, err, ErrWorkflowNotFound)
+	})
+
+	t.Run("NotDispatchable", func(t *testing.T) {
+		t.Parallel()
+		cli, mockCtx := newTestCli(t)
+		mockCtx.CommandRunner.When(
+			func(_ exec.RunArgs, cmd string) bool {
+				return strings.Contains(cmd, "workflow run")
+			},
+		).RespondFn(func(_ exec.RunArgs) (exec.RunResult, error) {
+			return exec.NewRunResult(1, "", "HTTP 422: Workflow does not have 'workflow_dispatch' trigger"),
+				errors.New("exit 1")
+		})
+
+		err := cli.RunWorkflow(t.Context(), "o/r", "azure-dev.yml", "main")
+		require.ErrorIs(t, err, ErrWorkflowNotDispatchable)
+	})
+}
+
+func TestListWorkflowRuns(t *testing.T) {
+	t.Parallel()
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+		cli, mockCtx := newTestCli(t)
+		respondOK(
+			mockCtx, "run list",
+			`[{"databaseId":42,"displayTitle":"Update app","headBranch":"main","status":"completed",`+
+				`"conclusion":"success","url":"https://github.com/o/r/actions/runs/42",`+
+				`"createdAt":"2024-05-01T10:00:00Z"}]`,
+		)
+
+		runs, err := cli.ListWorkflowRuns(t.Context(), "o/r", "azure-dev.yml", "main", 1)
+		require.NoError(t, err)
+		require.Len(t, runs, 1)
+		require.Equal(t, int64(42), runs[0].DatabaseId)
+		require.Equal(t, "success", runs[0].Conclusion)
+		require.Equal(t, "https://github.com/o/r/actions/runs/42", runs[0].Url)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+		cli, mockCtx := newTestCli(t)
+		respondErr(mockCtx, "run list")
+
+		_, err := cli.ListWorkflowRuns(t.Context(), "o/r", "azure-dev.yml", "main", 1)
+		require.Error(t, err)
+	})
+}
+
+// --------------- Releases ---------------
+
+func TestListReleaseAssets(t *testing.T) {
+	t.Parallel()
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+		cli, mockCtx := newTestCli(t)
+		respondOK(
+			mockCtx, "release view",
+			`{"assets":[{"name":"abcd-api.zip"},{"name":"ef01-web.zip"}]}`,
+		)
+
+		assets, err := cli.ListReleaseAssets(t.Context(), "o/r", "azd-artifacts")
+		require.NoError(t, err)
+		require.Equal(t, []string{"abcd-api.zip", "ef01-web.zip"}, assets)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+		cli, mockCtx := newTestCli(t)
+		mockCtx.CommandRunner.When(
+			func(_ exec.RunArgs, cmd string) bool {
+				return strings.Contains(cmd, "release view")
+			},
+		).RespondFn(func(_ exec.RunArgs) (exec.RunResult, error) {
+			return exec.NewRunResult(1, "", "release not found"),
+				errors.New("exit 1")
+		})
+
+		_, err := cli.ListReleaseAssets(t.Context(), "o/r", "azd-artifacts")
+		require.ErrorIs(t, err, ErrReleaseNotFound)
+	})
+}
+
+func TestUploadReleaseAsset(t *testing.T) {
+	t.Parallel()
+	cli, mockCtx := newTestCli(t)
+	var uploadArgs []string
+	mockCtx.CommandRunner.When(
+		func(_ exec.RunArgs, cmd string) bool {
+			return strings.Contains(cmd, "release upload")
+		},
+	).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		uploadArgs = args.Args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	err := cli.UploadReleaseAsset(t.Context(), "o/r", "azd-artifacts", "/tmp/abcd-api.zip")
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		[]string{"-R", "o/r", "release", "upload", "azd-artifacts", "/tmp/abcd-api.zip", "--clobber"},
+		uploadArgs,
+	)
+}
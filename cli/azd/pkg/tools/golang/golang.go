@@ -84,10 +84,11 @@ func (cli *Cli) Build(
 	env []string,
 ) error {
 	_, err := cli.commandRunner.Run(ctx, exec.RunArgs{
-		Cmd:  "go",
-		Args: []string{"build", "-o", outputName, "."},
-		Cwd:  projectDir,
-		Env:  env,
+		Cmd:          "go",
+		Args:         []string{"build", "-o", outputName, "."},
+		Cwd:          projectDir,
+		Env:          env,
+		EnvIsolation: exec.EnvIsolationFromContext(ctx),
 	})
 	if err != nil {
 		return fmt.Errorf("building Go project: %w", err)
@@ -99,10 +100,11 @@ func (cli *Cli) Build(
 // ModDownload runs 'go mod download' to fetch dependencies.
 func (cli *Cli) ModDownload(ctx context.Context, projectDir string, env []string) error {
 	_, err := cli.commandRunner.Run(ctx, exec.RunArgs{
-		Cmd:  "go",
-		Args: []string{"mod", "download"},
-		Cwd:  projectDir,
-		Env:  env,
+		Cmd:          "go",
+		Args:         []string{"mod", "download"},
+		Cwd:          projectDir,
+		Env:          env,
+		EnvIsolation: exec.EnvIsolationFromContext(ctx),
 	})
 	if err != nil {
 		return fmt.Errorf("downloading Go modules: %w", err)
@@ -0,0 +1,79 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package kiota
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+var _ tools.ExternalTool = (*Cli)(nil)
+
+// Languages are the client languages supported by Kiota.
+var Languages = []string{"csharp", "dart", "go", "java", "php", "python", "ruby", "typescript"}
+
+// Cli is the Kiota CLI, which generates API clients from OpenAPI descriptions.
+type Cli struct {
+	commandRunner exec.CommandRunner
+}
+
+func NewCli(commandRunner exec.CommandRunner) *Cli {
+	return &Cli{
+		commandRunner: commandRunner,
+	}
+}
+
+// GenerateOptions are the options of a client generated by Kiota.
+type GenerateOptions struct {
+	// SpecPath is the path of the OpenAPI description.
+	SpecPath string
+	// Language is the language of the client, one of Languages.
+	Language string
+	// OutputPath is the directory the client is generated in. Its previous contents are removed.
+	OutputPath string
+	// ClassName is the name of the client class. Kiota names the class ApiClient when it is empty.
+	ClassName string
+}
+
+// Generate generates the client described by options.
+func (cli *Cli) Generate(ctx context.Context, options GenerateOptions, progress io.Writer) error {
+	args := []string{
+		"generate",
+		"--openapi", options.SpecPath,
+		"--language", options.Language,
+		"--output", options.OutputPath,
+		"--clean-output",
+	}
+
+	if options.ClassName != "" {
+		args = append(args, "--class-name", options.ClassName)
+	}
+
+	runArgs := exec.NewRunArgs("kiota", args...)
+	if progress != nil {
+		runArgs = runArgs.WithStdOut(progress).WithStdErr(progress)
+	}
+
+	if _, err := cli.commandRunner.Run(ctx, runArgs); err != nil {
+		return fmt.Errorf("kiota generate: %w", err)
+	}
+
+	return nil
+}
+
+func (cli *Cli) CheckInstalled(_ context.Context) error {
+	return cli.commandRunner.ToolInPath("kiota")
+}
+
+func (cli *Cli) Name() string {
+	return "Kiota"
+}
+
+func (cli *Cli) InstallUrl() string {
+	return "https://aka.ms/kiota/install"
+}
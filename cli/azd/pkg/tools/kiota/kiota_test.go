@@ -0,0 +1,66 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package kiota
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_KiotaGenerate(t *testing.T) {
+	t.Run("NoErrors", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		cli := NewCli(mockContext.CommandRunner)
+
+		ran := false
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.HasPrefix(command, "kiota generate")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			ran = true
+			require.Equal(t, []string{
+				"generate",
+				"--openapi", "openapi.yaml",
+				"--language", "typescript",
+				"--output", "client",
+				"--clean-output",
+				"--class-name", "TodoClient",
+			}, args.Args)
+
+			return exec.RunResult{}, nil
+		})
+
+		err := cli.Generate(t.Context(), GenerateOptions{
+			SpecPath:   "openapi.yaml",
+			Language:   "typescript",
+			OutputPath: "client",
+			ClassName:  "TodoClient",
+		}, nil)
+		require.NoError(t, err)
+		require.True(t, ran)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		cli := NewCli(mockContext.CommandRunner)
+
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.HasPrefix(command, "kiota generate")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			require.NotContains(t, args.Args, "--class-name")
+			return exec.RunResult{ExitCode: 1}, errors.New("invalid description")
+		})
+
+		err := cli.Generate(t.Context(), GenerateOptions{
+			SpecPath:   "openapi.yaml",
+			Language:   "go",
+			OutputPath: "client",
+		}, nil)
+		require.ErrorContains(t, err, "kiota generate: invalid description")
+	})
+}
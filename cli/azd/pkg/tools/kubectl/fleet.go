@@ -0,0 +1,68 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package kubectl
+
+// Resource types served by the hub cluster of an Azure Kubernetes Fleet Manager
+const (
+	ResourceTypeMemberCluster            ResourceType = "memberclusters.cluster.kubernetes-fleet.io"
+	ResourceTypeClusterResourceSnapshot  ResourceType = "clusterresourcesnapshots.placement.kubernetes-fleet.io"
+	ResourceTypeClusterStagedUpdateRun   ResourceType = "clusterstagedupdateruns.placement.kubernetes-fleet.io"
+	FleetPlacementApiVersion             string       = "placement.kubernetes-fleet.io/v1beta1"
+	FleetPlacementTrackingLabel          string       = "kubernetes-fleet.io/parent-CRP"
+	FleetResourceIndexLabel              string       = "kubernetes-fleet.io/resource-index"
+	FleetLatestSnapshotLabel             string       = "kubernetes-fleet.io/is-latest-snapshot"
+	FleetConditionStatusTrue             string       = "True"
+	FleetConditionStatusFalse            string       = "False"
+	FleetUpdateRunConditionSucceeded     string       = "Succeeded"
+	FleetUpdateRunConditionProgressing   string       = "Progressing"
+	FleetClusterUpdateConditionStarted   string       = "Started"
+	FleetClusterUpdateConditionSucceeded string       = "Succeeded"
+)
+
+// Condition is the standard k8s status condition used by the fleet placement APIs
+type Condition struct {
+	Type    string `json:"type"    yaml:"type"`
+	Status  string `json:"status"  yaml:"status"`
+	Reason  string `json:"reason"  yaml:"reason"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// FindCondition returns the condition with the specified type, or nil when not present
+func FindCondition(conditions []Condition, conditionType string) *Condition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+
+	return nil
+}
+
+type MemberCluster ResourceWithSpec[map[string]any, map[string]any]
+
+type ClusterResourceSnapshot ResourceWithSpec[map[string]any, map[string]any]
+
+type ClusterStagedUpdateRun ResourceWithSpec[ClusterStagedUpdateRunSpec, ClusterStagedUpdateRunStatus]
+
+type ClusterStagedUpdateRunSpec struct {
+	PlacementName            string `json:"placementName"            yaml:"placementName"`
+	ResourceSnapshotIndex    string `json:"resourceSnapshotIndex"    yaml:"resourceSnapshotIndex"`
+	StagedUpdateStrategyName string `json:"stagedRolloutStrategyName" yaml:"stagedRolloutStrategyName"`
+}
+
+type ClusterStagedUpdateRunStatus struct {
+	Conditions   []Condition           `json:"conditions"   yaml:"conditions"`
+	StagesStatus []StageUpdatingStatus `json:"stagesStatus" yaml:"stagesStatus"`
+}
+
+type StageUpdatingStatus struct {
+	StageName  string                  `json:"stageName"  yaml:"stageName"`
+	Clusters   []ClusterUpdatingStatus `json:"clusters"   yaml:"clusters"`
+	Conditions []Condition             `json:"conditions" yaml:"conditions"`
+}
+
+type ClusterUpdatingStatus struct {
+	ClusterName string      `json:"clusterName" yaml:"clusterName"`
+	Conditions  []Condition `json:"conditions"  yaml:"conditions"`
+}
@@ -273,20 +273,108 @@ func (cli *Cli) Exec(ctx context.Context, flags *KubeCliFlags, args ...string) (
 	return cli.executeCommandWithArgs(ctx, runArgs, flags)
 }
 
-func (cli *Cli) applyTemplate(ctx context.Context, filePath string, flags *KubeCliFlags) (*exec.RunResult, error) {
+// ExecInteractive runs a kubectl command attached to the console, such as an interactive `kubectl exec`. The arguments
+// are passed as is, so flags must come before a `--` that ends them.
+func (cli *Cli) ExecInteractive(ctx context.Context, args ...string) error {
+	runArgs := exec.
+		NewRunArgs("kubectl").
+		AppendParams(args...).
+		WithInteractive(true)
+
+	_, err := cli.executeCommandWithArgs(ctx, runArgs, nil)
+	return err
+}
+
+// Builds the kustomization in the specified directory and returns the resulting manifests
+func (cli *Cli) Kustomize(ctx context.Context, path string) (string, error) {
+	res, err := cli.Exec(ctx, nil, "kustomize", path)
+	if err != nil {
+		return "", fmt.Errorf("kubectl kustomize: %w", err)
+	}
+
+	return res.Stdout, nil
+}
+
+// RenderedManifest is a k8s manifest after template processing
+type RenderedManifest struct {
+	// The path of the manifest relative to the rendered directory. The '.tmpl' suffix is removed from templates.
+	Path    string
+	Content string
+}
+
+// Renders the manifests in the specified directory without applying them.
+// *.tmpl.yaml files are processed as templates, all other yaml files are returned as is.
+func (cli *Cli) Render(directoryPath string) ([]RenderedManifest, error) {
+	manifests := []RenderedManifest{}
+	err := filepath.WalkDir(directoryPath, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(directoryPath, path)
+		if err != nil {
+			return err
+		}
+
+		var content string
+		if strings.HasSuffix(strings.TrimSuffix(entry.Name(), ext), ".tmpl") {
+			content, err = cli.renderTemplate(path)
+			if err != nil {
+				return err
+			}
+
+			relativePath = strings.TrimSuffix(relativePath, ".tmpl"+ext) + ext
+		} else {
+			fileBytes, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed reading file '%s', %w", path, err)
+			}
+
+			content = string(fileBytes)
+		}
+
+		manifests = append(manifests, RenderedManifest{
+			Path:    filepath.ToSlash(relativePath),
+			Content: content,
+		})
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed rendering manifests in path '%s', %w", directoryPath, err)
+	}
+
+	return manifests, nil
+}
+
+func (cli *Cli) renderTemplate(filePath string) (string, error) {
 	k8sTemplate, err := template.ParseFiles(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed parsing template file '%s', %w", filePath, err)
+		return "", fmt.Errorf("failed parsing template file '%s', %w", filePath, err)
 	}
 
 	builder := strings.Builder{}
 	envSnapshot, _ := cli.snapshotState()
 	err = k8sTemplate.Execute(&builder, templateRoot{Env: envSnapshot})
 	if err != nil {
-		return nil, fmt.Errorf("failed executing template file '%s', %w", filePath, err)
+		return "", fmt.Errorf("failed executing template file '%s', %w", filePath, err)
+	}
+
+	return builder.String(), nil
+}
+
+func (cli *Cli) applyTemplate(ctx context.Context, filePath string, flags *KubeCliFlags) (*exec.RunResult, error) {
+	rendered, err := cli.renderTemplate(filePath)
+	if err != nil {
+		return nil, err
 	}
 
-	result, err := cli.ApplyWithStdIn(ctx, builder.String(), flags)
+	result, err := cli.ApplyWithStdIn(ctx, rendered, flags)
 	if err != nil {
 		return nil, fmt.Errorf("failed applying file '%s', %w", filePath, err)
 	}
@@ -259,6 +259,44 @@ func Test_Apply_Template(t *testing.T) {
 	})
 }
 
+func Test_Render(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	cli := NewCli(mockContext.CommandRunner)
+	cli.SetEnv(map[string]string{
+		"SERVICE_API_IMAGE_NAME":       "test.azureacr.io/repo/service:latest",
+		"AZURE_AKS_IDENTITY_CLIENT_ID": "EXAMPLE_CLIENT_ID",
+	})
+
+	manifests, err := cli.Render("../../../test/testdata/k8s/apply")
+	require.NoError(t, err)
+	require.Len(t, manifests, 2)
+
+	require.Equal(t, "raw/config-map.yaml", manifests[0].Path)
+	require.Contains(t, manifests[0].Content, "ConfigMap")
+
+	// Templates are rendered and lose the .tmpl suffix
+	require.Equal(t, "templates/deployment.yaml", manifests[1].Path)
+	require.Contains(t, manifests[1].Content, "test.azureacr.io/repo/service:latest")
+	require.Contains(t, manifests[1].Content, "EXAMPLE_CLIENT_ID")
+}
+
+func Test_Cli_Kustomize(t *testing.T) {
+	mockCtx := mocks.NewMockContext(t.Context())
+	var capturedArgs exec.RunArgs
+	mockCtx.CommandRunner.When(func(args exec.RunArgs, cmd string) bool {
+		return strings.Contains(cmd, "kubectl kustomize")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		capturedArgs = args
+		return exec.NewRunResult(0, "kind: ConfigMap\n", ""), nil
+	})
+
+	cli := NewCli(mockCtx.CommandRunner)
+	output, err := cli.Kustomize(*mockCtx.Context, "./overlays/prod")
+	require.NoError(t, err)
+	require.Equal(t, "kind: ConfigMap\n", output)
+	require.Equal(t, []string{"kustomize", "./overlays/prod"}, capturedArgs.Args)
+}
+
 func Test_Cli_Name(t *testing.T) {
 	cli := NewCli(nil)
 	require.Equal(t, "kubectl", cli.Name())
@@ -38,6 +38,7 @@ type ResourceMetadata struct {
 	Name        string `json:"name"      yaml:"name"`
 	Namespace   string `json:"namespace" yaml:"namespace"`
 	Annotations map[string]any
+	Labels      map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
 }
 
 type Deployment ResourceWithSpec[DeploymentSpec, DeploymentStatus]
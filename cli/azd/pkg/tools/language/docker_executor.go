@@ -0,0 +1,224 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package language
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/errorhandler"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+)
+
+// dockerWorkspaceDir is the directory of the container where the
+// project is mounted.
+const dockerWorkspaceDir = "/workspace"
+
+// dockerTools abstracts the container engine operations needed by
+// dockerExecutor, decoupling it from the concrete [docker.Cli]
+// for testability. [docker.Cli] satisfies this interface.
+type dockerTools interface {
+	CheckInstalled(ctx context.Context) error
+	ContainerEngine() string
+}
+
+// dockerHookConfig holds the typed configuration that users can
+// specify in azure.yaml under a docker hook's config section.
+type dockerHookConfig struct {
+	// Image is the container image the script runs in. Required.
+	Image string `json:"image"`
+
+	// Shell is the shell of the image that runs the script.
+	// Empty means sh.
+	Shell string `json:"shell"`
+
+	// Platform is the platform of the image (e.g. linux/amd64).
+	// Passed as `--platform` to docker run. Empty means the
+	// platform of the container engine.
+	Platform string `json:"platform"`
+}
+
+// dockerExecutor implements [tools.HookExecutor] for shell scripts
+// that run inside a container. The project is mounted at
+// /workspace, the working directory maps to the same directory
+// under /workspace, and the hook environment is forwarded to the
+// container.
+type dockerExecutor struct {
+	commandRunner exec.CommandRunner
+	dockerCli     dockerTools
+
+	// config holds parsed hook configuration from azure.yaml.
+	config dockerHookConfig
+}
+
+// NewDockerExecutor creates a docker HookExecutor.
+// Takes only IoC-injectable deps.
+func NewDockerExecutor(
+	commandRunner exec.CommandRunner,
+	dockerCli *docker.Cli,
+) tools.HookExecutor {
+	return newDockerExecutorInternal(commandRunner, dockerCli)
+}
+
+// newDockerExecutorInternal creates a dockerExecutor using the
+// dockerTools interface. This allows tests to inject mocks.
+func newDockerExecutorInternal(
+	commandRunner exec.CommandRunner,
+	dockerCli dockerTools,
+) *dockerExecutor {
+	return &dockerExecutor{
+		commandRunner: commandRunner,
+		dockerCli:     dockerCli,
+	}
+}
+
+// Prepare parses the hook configuration and verifies that a
+// container engine (Docker or Podman) is installed and running.
+func (e *dockerExecutor) Prepare(
+	ctx context.Context,
+	_ string,
+	execCtx tools.ExecutionContext,
+) error {
+	cfg, err := tools.UnmarshalHookConfig[dockerHookConfig](
+		execCtx.Config,
+	)
+	if err != nil {
+		return fmt.Errorf("parsing docker hook config: %w", err)
+	}
+
+	if cfg.Image == "" {
+		return &errorhandler.ErrorWithSuggestion{
+			Err: errors.New(
+				"docker hooks require an image",
+			),
+			Message: fmt.Sprintf(
+				"Hook '%s' runs in a container but doesn't "+
+					"declare its image.",
+				execCtx.HookName,
+			),
+			Suggestion: "Set 'config.image' on the hook " +
+				"(e.g. config: { image: alpine:3.20 }).",
+		}
+	}
+	if cfg.Shell == "" {
+		cfg.Shell = "sh"
+	}
+	e.config = cfg
+
+	if err := e.dockerCli.CheckInstalled(ctx); err != nil {
+		return &errorhandler.ErrorWithSuggestion{
+			Err: err,
+			Message: "Docker or Podman is required to run " +
+				"docker hooks.",
+			Suggestion: "Install Docker from " +
+				"https://aka.ms/azure-dev/docker-install " +
+				"and make sure it is running.",
+			Links: []errorhandler.ErrorLink{{
+				Title: "Install Docker",
+				URL:   "https://aka.ms/azure-dev/docker-install",
+			}},
+		}
+	}
+
+	return nil
+}
+
+// Execute runs the hook script inside the container:
+//
+//	docker run --rm -v <project>:/workspace -w <cwd> -e KEY... <image> <shell> <script>
+//
+// Only the names of the environment variables appear on the
+// command line; the container engine reads their values from its
+// own environment, so secrets are not logged.
+func (e *dockerExecutor) Execute(
+	ctx context.Context,
+	scriptPath string,
+	execCtx tools.ExecutionContext,
+) (exec.RunResult, error) {
+	mountDir := execCtx.BoundaryDir
+	if mountDir == "" {
+		mountDir = execCtx.Cwd
+	}
+
+	workDir, err := dockerContainerPath(mountDir, execCtx.Cwd)
+	if err != nil {
+		// Inline hooks may run from a directory outside of the
+		// project; they start at the root of the mount instead.
+		workDir = dockerWorkspaceDir
+	}
+
+	args := []string{"run", "--rm"}
+	if execCtx.Interactive != nil && *execCtx.Interactive {
+		args = append(args, "-i")
+	}
+	if e.config.Platform != "" {
+		args = append(args, "--platform", e.config.Platform)
+	}
+	args = append(args,
+		"-v", fmt.Sprintf("%s:%s", mountDir, dockerWorkspaceDir),
+		"-w", workDir,
+	)
+	// Only the keys of EnvVars are forwarded to the container, so an
+	// isolated hook environment is already limited to the allowed
+	// keys; the container engine itself keeps the full environment.
+	for _, envVar := range execCtx.EnvVars {
+		key, _, _ := strings.Cut(envVar, "=")
+		args = append(args, "-e", key)
+	}
+	args = append(args, e.config.Image, e.config.Shell)
+
+	if execCtx.InlineScript != "" {
+		args = append(args, "-c", "set -e\n"+execCtx.InlineScript)
+	} else {
+		containerScript, err := dockerContainerPath(mountDir, scriptPath)
+		if err != nil {
+			return exec.RunResult{}, fmt.Errorf(
+				"script '%s' must be in the project directory "+
+					"to run in a container: %w",
+				scriptPath, err,
+			)
+		}
+		args = append(args, containerScript)
+	}
+
+	runArgs := exec.NewRunArgs(e.dockerCli.ContainerEngine(), args...).
+		WithCwd(execCtx.Cwd).
+		WithEnv(execCtx.EnvVars)
+
+	if execCtx.Interactive != nil {
+		runArgs = runArgs.WithInteractive(*execCtx.Interactive)
+	}
+	if execCtx.StdOut != nil {
+		runArgs = runArgs.WithStdOut(execCtx.StdOut)
+	}
+
+	return e.commandRunner.Run(ctx, runArgs)
+}
+
+// Cleanup is a no-op for the docker executor — the container is
+// removed when the script exits (--rm) and inline scripts are
+// passed on the command line.
+func (e *dockerExecutor) Cleanup(_ context.Context) error {
+	return nil
+}
+
+// dockerContainerPath maps hostPath, which must be inside
+// mountDir, to its path inside the container.
+func dockerContainerPath(mountDir string, hostPath string) (string, error) {
+	rel, err := filepath.Rel(mountDir, hostPath)
+	if err != nil {
+		return "", err
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("'%s' is outside of '%s'", hostPath, mountDir)
+	}
+
+	return path.Join(dockerWorkspaceDir, filepath.ToSlash(rel)), nil
+}
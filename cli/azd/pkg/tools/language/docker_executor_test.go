@@ -0,0 +1,143 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package language
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/errorhandler"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockDockerTools — test double for the dockerTools interface
+type mockDockerTools struct {
+	checkInstalledErr error
+	engine            string
+}
+
+func (m *mockDockerTools) CheckInstalled(_ context.Context) error {
+	return m.checkInstalledErr
+}
+
+func (m *mockDockerTools) ContainerEngine() string {
+	if m.engine == "" {
+		return "docker"
+	}
+	return m.engine
+}
+
+func TestDockerPrepare_ImageRequired(t *testing.T) {
+	e := newDockerExecutorInternal(&mockCommandRunner{}, &mockDockerTools{})
+
+	err := e.Prepare(t.Context(), "", tools.ExecutionContext{HookName: "preprovision"})
+
+	sugErr, ok := errors.AsType[*errorhandler.ErrorWithSuggestion](err)
+	require.True(t, ok)
+	assert.Contains(t, sugErr.Message, "preprovision")
+	assert.Contains(t, sugErr.Suggestion, "config.image")
+}
+
+func TestDockerPrepare_EngineNotInstalled(t *testing.T) {
+	cli := &mockDockerTools{checkInstalledErr: errors.New("neither docker nor podman is installed")}
+	e := newDockerExecutorInternal(&mockCommandRunner{}, cli)
+
+	err := e.Prepare(t.Context(), "", tools.ExecutionContext{
+		Config: map[string]any{"image": "alpine:3.20"},
+	})
+
+	sugErr, ok := errors.AsType[*errorhandler.ErrorWithSuggestion](err)
+	require.True(t, ok)
+	assert.ErrorIs(t, sugErr, cli.checkInstalledErr)
+}
+
+func TestDockerExecute(t *testing.T) {
+	root := t.TempDir()
+	serviceDir := filepath.Join(root, "src", "api")
+
+	tests := []struct {
+		name         string
+		config       map[string]any
+		execCtx      tools.ExecutionContext
+		scriptPath   string
+		expectedArgs []string
+	}{
+		{
+			name:   "ScriptFile",
+			config: map[string]any{"image": "mcr.microsoft.com/azure-cli:latest"},
+			execCtx: tools.ExecutionContext{
+				BoundaryDir: root,
+				Cwd:         root,
+				EnvVars:     []string{"AZURE_ENV_NAME=dev", "DB_PASSWORD=secret"},
+			},
+			scriptPath: filepath.Join(root, "scripts", "seed.sh"),
+			expectedArgs: []string{
+				"run", "--rm",
+				"-v", root + ":/workspace",
+				"-w", "/workspace",
+				"-e", "AZURE_ENV_NAME",
+				"-e", "DB_PASSWORD",
+				"mcr.microsoft.com/azure-cli:latest", "sh", "/workspace/scripts/seed.sh",
+			},
+		},
+		{
+			name: "InlineScriptInServiceDir",
+			config: map[string]any{
+				"image":    "node:22",
+				"shell":    "bash",
+				"platform": "linux/amd64",
+			},
+			execCtx: tools.ExecutionContext{
+				BoundaryDir:  root,
+				Cwd:          serviceDir,
+				InlineScript: "npm ci",
+				Interactive:  new(true),
+			},
+			expectedArgs: []string{
+				"run", "--rm", "-i",
+				"--platform", "linux/amd64",
+				"-v", root + ":/workspace",
+				"-w", "/workspace/src/api",
+				"node:22", "bash", "-c", "set -e\nnpm ci",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &mockCommandRunner{}
+			e := newDockerExecutorInternal(runner, &mockDockerTools{engine: "podman"})
+			tt.execCtx.Config = tt.config
+
+			require.NoError(t, e.Prepare(t.Context(), tt.scriptPath, tt.execCtx))
+			_, err := e.Execute(t.Context(), tt.scriptPath, tt.execCtx)
+			require.NoError(t, err)
+
+			assert.Equal(t, "podman", runner.lastRunArgs.Cmd)
+			assert.Equal(t, tt.expectedArgs, runner.lastRunArgs.Args)
+			assert.Equal(t, tt.execCtx.Cwd, runner.lastRunArgs.Cwd)
+			assert.Equal(t, tt.execCtx.EnvVars, runner.lastRunArgs.Env)
+		})
+	}
+}
+
+func TestDockerExecute_ScriptOutsideProject(t *testing.T) {
+	root := t.TempDir()
+	runner := &mockCommandRunner{}
+	e := newDockerExecutorInternal(runner, &mockDockerTools{})
+	execCtx := tools.ExecutionContext{
+		BoundaryDir: filepath.Join(root, "project"),
+		Cwd:         filepath.Join(root, "project"),
+		Config:      map[string]any{"image": "alpine:3.20"},
+	}
+	scriptPath := filepath.Join(root, "other", "hook.sh")
+
+	require.NoError(t, e.Prepare(t.Context(), scriptPath, execCtx))
+	_, err := e.Execute(t.Context(), scriptPath, execCtx)
+	require.ErrorContains(t, err, "must be in the project directory")
+}
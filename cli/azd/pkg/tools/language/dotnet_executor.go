@@ -249,6 +249,13 @@ func (e *dotnetExecutor) Execute(
 		execCtx.EnvVars...,
 	))
 
+	// The dotnet settings above are kept when the hook's
+	// environment is isolated.
+	runArgs = runArgs.WithEnvIsolation(execCtx.EnvIsolation.With(
+		"DOTNET_CLI_WORKLOAD_UPDATE_NOTIFY_DISABLE",
+		"DOTNET_NOLOGO",
+	))
+
 	// Prefer configured cwd; fall back to script's directory.
 	cwd := execCtx.Cwd
 	if cwd == "" {
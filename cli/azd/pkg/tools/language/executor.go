@@ -29,16 +29,21 @@ const (
 	HookKindPython HookKind = "python"
 	// HookKindDotNet identifies .NET (C#) scripts (.cs files).
 	HookKindDotNet HookKind = "dotnet"
+	// HookKindDocker identifies shell scripts that run inside a
+	// container. Never inferred from the file extension.
+	HookKindDocker HookKind = "docker"
 )
 
-// IsShell reports whether k is one of the built-in shell
-// kinds (Bash or PowerShell). Shell hooks support inline scripts
-// and are executed directly by the OS shell. Non-shell kinds
-// (Python, JS, TS, DotNet) require a file on disk and are executed
-// through dedicated [tools.HookExecutor] implementations.
+// IsShell reports whether k is one of the shell kinds (Bash,
+// PowerShell, or a shell inside a container). Shell hooks support
+// inline scripts and run from the project or service directory.
+// Non-shell kinds (Python, JS, TS, DotNet) require a file on disk
+// and are executed through dedicated [tools.HookExecutor]
+// implementations.
 func (k HookKind) IsShell() bool {
 	return k == HookKindBash ||
-		k == HookKindPowerShell
+		k == HookKindPowerShell ||
+		k == HookKindDocker
 }
 
 // InferKindFromPath determines the [HookKind] from the
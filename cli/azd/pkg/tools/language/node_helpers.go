@@ -179,7 +179,8 @@ func buildNodeRunArgs(
 	allArgs := slices.Concat(args, []string{scriptPath})
 	runArgs := exec.
 		NewRunArgs(cmd, allArgs...).
-		WithEnv(execCtx.EnvVars)
+		WithEnv(execCtx.EnvVars).
+		WithEnvIsolation(execCtx.EnvIsolation)
 
 	// Prefer configured cwd; fall back to script's directory.
 	cwd := execCtx.Cwd
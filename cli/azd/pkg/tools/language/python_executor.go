@@ -229,7 +229,8 @@ func (e *pythonExecutor) Execute(
 
 	runArgs := exec.
 		NewRunArgs(pyCmd, scriptPath).
-		WithEnv(execCtx.EnvVars)
+		WithEnv(execCtx.EnvVars).
+		WithEnvIsolation(execCtx.EnvIsolation)
 
 	// Prefer configured cwd; fall back to script's directory.
 	cwd := execCtx.Cwd
@@ -177,7 +177,8 @@ func (cli *Cli) Compile(ctx context.Context, projectPath string, env []string) e
 		return err
 	}
 
-	runArgs := exec.NewRunArgs(mvnCmd, "compile").WithCwd(projectPath).WithEnv(env)
+	runArgs := exec.NewRunArgs(mvnCmd, "compile").WithCwd(projectPath).WithEnv(env).
+		WithEnvIsolation(exec.EnvIsolationFromContext(ctx))
 	_, err = cli.commandRunner.Run(ctx, runArgs)
 	if err != nil {
 		return fmt.Errorf("mvn compile on project '%s' failed: %w", projectPath, err)
@@ -193,7 +194,8 @@ func (cli *Cli) Package(ctx context.Context, projectPath string, env []string) e
 	}
 
 	// Maven's package phase includes tests by default. Skip it explicitly.
-	runArgs := exec.NewRunArgs(mvnCmd, "package", "-DskipTests").WithCwd(projectPath).WithEnv(env)
+	runArgs := exec.NewRunArgs(mvnCmd, "package", "-DskipTests").WithCwd(projectPath).WithEnv(env).
+		WithEnvIsolation(exec.EnvIsolationFromContext(ctx))
 	_, err = cli.commandRunner.Run(ctx, runArgs)
 	if err != nil {
 		return fmt.Errorf("mvn package on project '%s' failed: %w", projectPath, err)
@@ -207,7 +209,8 @@ func (cli *Cli) ResolveDependencies(ctx context.Context, projectPath string, env
 	if err != nil {
 		return err
 	}
-	runArgs := exec.NewRunArgs(mvnCmd, "dependency:resolve").WithCwd(projectPath).WithEnv(env)
+	runArgs := exec.NewRunArgs(mvnCmd, "dependency:resolve").WithCwd(projectPath).WithEnv(env).
+		WithEnvIsolation(exec.EnvIsolationFromContext(ctx))
 	_, err = cli.commandRunner.Run(ctx, runArgs)
 	if err != nil {
 		return fmt.Errorf("mvn dependency:resolve on project '%s' failed: %w", projectPath, err)
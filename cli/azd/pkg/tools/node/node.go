@@ -92,7 +92,7 @@ func (c *npmCli) CheckInstalled(ctx context.Context) error {
 
 func (c *npmCli) Install(ctx context.Context, projectPath string, env []string) error {
 	runArgs := exec.NewRunArgs("npm", "install", "--no-audit", "--no-fund", "--prefer-offline").
-		WithCwd(projectPath).WithEnv(env)
+		WithCwd(projectPath).WithEnv(env).WithEnvIsolation(exec.EnvIsolationFromContext(ctx))
 	if _, err := c.commandRunner.Run(ctx, runArgs); err != nil {
 		return fmt.Errorf("failed to install project %s using npm: %w", projectPath, err)
 	}
@@ -101,7 +101,8 @@ func (c *npmCli) Install(ctx context.Context, projectPath string, env []string)
 
 func (c *npmCli) RunScript(ctx context.Context, projectPath string, scriptName string, env []string) error {
 	// npm supports --if-present after the script name
-	runArgs := exec.NewRunArgs("npm", "run", scriptName, "--if-present").WithCwd(projectPath).WithEnv(env)
+	runArgs := exec.NewRunArgs("npm", "run", scriptName, "--if-present").WithCwd(projectPath).WithEnv(env).
+		WithEnvIsolation(exec.EnvIsolationFromContext(ctx))
 	if _, err := c.commandRunner.Run(ctx, runArgs); err != nil {
 		return fmt.Errorf("failed to run npm script %s: %w", scriptName, err)
 	}
@@ -109,7 +110,8 @@ func (c *npmCli) RunScript(ctx context.Context, projectPath string, scriptName s
 }
 
 func (c *npmCli) Prune(ctx context.Context, projectPath string, production bool, env []string) error {
-	runArgs := exec.NewRunArgs("npm", "prune").WithCwd(projectPath).WithEnv(env)
+	runArgs := exec.NewRunArgs("npm", "prune").WithCwd(projectPath).WithEnv(env).
+		WithEnvIsolation(exec.EnvIsolationFromContext(ctx))
 	if production {
 		runArgs = runArgs.AppendParams("--production")
 	}
@@ -134,7 +136,8 @@ func (c *pnpmCli) CheckInstalled(ctx context.Context) error {
 }
 
 func (c *pnpmCli) Install(ctx context.Context, projectPath string, env []string) error {
-	runArgs := exec.NewRunArgs("pnpm", "install", "--prefer-offline").WithCwd(projectPath).WithEnv(env)
+	runArgs := exec.NewRunArgs("pnpm", "install", "--prefer-offline").WithCwd(projectPath).WithEnv(env).
+		WithEnvIsolation(exec.EnvIsolationFromContext(ctx))
 	if _, err := c.commandRunner.Run(ctx, runArgs); err != nil {
 		return fmt.Errorf("failed to install project %s using pnpm: %w", projectPath, err)
 	}
@@ -143,7 +146,8 @@ func (c *pnpmCli) Install(ctx context.Context, projectPath string, env []string)
 
 func (c *pnpmCli) RunScript(ctx context.Context, projectPath string, scriptName string, env []string) error {
 	// pnpm requires --if-present before the script name per its CLI spec
-	runArgs := exec.NewRunArgs("pnpm", "run", "--if-present", scriptName).WithCwd(projectPath).WithEnv(env)
+	runArgs := exec.NewRunArgs("pnpm", "run", "--if-present", scriptName).WithCwd(projectPath).WithEnv(env).
+		WithEnvIsolation(exec.EnvIsolationFromContext(ctx))
 	if _, err := c.commandRunner.Run(ctx, runArgs); err != nil {
 		return fmt.Errorf("failed to run pnpm script %s: %w", scriptName, err)
 	}
@@ -152,7 +156,8 @@ func (c *pnpmCli) RunScript(ctx context.Context, projectPath string, scriptName
 
 func (c *pnpmCli) Prune(ctx context.Context, projectPath string, production bool, env []string) error {
 	// pnpm uses --prod instead of --production
-	runArgs := exec.NewRunArgs("pnpm", "prune").WithCwd(projectPath).WithEnv(env)
+	runArgs := exec.NewRunArgs("pnpm", "prune").WithCwd(projectPath).WithEnv(env).
+		WithEnvIsolation(exec.EnvIsolationFromContext(ctx))
 	if production {
 		runArgs = runArgs.AppendParams("--prod")
 	}
@@ -179,7 +184,8 @@ func (c *yarnCli) CheckInstalled(ctx context.Context) error {
 func (c *yarnCli) Install(ctx context.Context, projectPath string, env []string) error {
 	// Yarn Berry (v2+) does not support --prefer-offline and deprecated --non-interactive.
 	// Plain install works for both Classic (v1) and Berry (v2+).
-	runArgs := exec.NewRunArgs("yarn", "install").WithCwd(projectPath).WithEnv(env)
+	runArgs := exec.NewRunArgs("yarn", "install").WithCwd(projectPath).WithEnv(env).
+		WithEnvIsolation(exec.EnvIsolationFromContext(ctx))
 	if _, err := c.commandRunner.Run(ctx, runArgs); err != nil {
 		return fmt.Errorf("failed to install project %s using yarn: %w", projectPath, err)
 	}
@@ -196,7 +202,8 @@ func (c *yarnCli) RunScript(ctx context.Context, projectPath string, scriptName
 	if !exists {
 		return nil
 	}
-	runArgs := exec.NewRunArgs("yarn", "run", scriptName).WithCwd(projectPath).WithEnv(env)
+	runArgs := exec.NewRunArgs("yarn", "run", scriptName).WithCwd(projectPath).WithEnv(env).
+		WithEnvIsolation(exec.EnvIsolationFromContext(ctx))
 	if _, err := c.commandRunner.Run(ctx, runArgs); err != nil {
 		return fmt.Errorf("failed to run yarn script %s: %w", scriptName, err)
 	}
@@ -210,7 +217,8 @@ func (c *yarnCli) Prune(ctx context.Context, projectPath string, production bool
 	// requires the yarn workspace-tools plugin.
 	// This is a known limitation for Yarn Berry users; most Berry+azd projects use
 	// nodeLinker: node-modules where this remains functional.
-	runArgs := exec.NewRunArgs("yarn", "install").WithCwd(projectPath).WithEnv(env)
+	runArgs := exec.NewRunArgs("yarn", "install").WithCwd(projectPath).WithEnv(env).
+		WithEnvIsolation(exec.EnvIsolationFromContext(ctx))
 	if production {
 		runArgs = runArgs.AppendParams("--production")
 	}
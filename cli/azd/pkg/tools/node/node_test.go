@@ -234,6 +234,24 @@ func TestRunScript_PassesEnvVars(t *testing.T) {
 	require.Equal(t, env, capturedArgs.Env)
 }
 
+func TestRunScript_AppliesEnvIsolationFromContext(t *testing.T) {
+	var capturedArgs exec.RunArgs
+	runner := mockexec.NewMockCommandRunner()
+	runner.When(func(args exec.RunArgs, command string) bool {
+		capturedArgs = args
+		return args.Cmd == "npm"
+	}).Respond(exec.RunResult{})
+
+	cli := NewCliWithPackageManager(runner, PackageManagerNpm)
+	require.NoError(t, cli.RunScript(t.Context(), "/project", "build", nil))
+	require.Nil(t, capturedArgs.EnvIsolation)
+
+	isolation := &exec.EnvIsolation{Allow: []string{"VITE_*"}}
+	ctx := exec.WithEnvIsolation(t.Context(), isolation)
+	require.NoError(t, cli.RunScript(ctx, "/project", "build", nil))
+	require.Same(t, isolation, capturedArgs.EnvIsolation)
+}
+
 func TestPrune_WithoutProduction(t *testing.T) {
 	runner := mockexec.NewMockCommandRunner()
 	runner.When(func(args exec.RunArgs, command string) bool {
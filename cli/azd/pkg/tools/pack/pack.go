@@ -25,6 +25,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tool"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/blang/semver/v4"
 )
@@ -33,6 +34,17 @@ import (
 // user).
 var Version semver.Version = semver.MustParse("0.30.0")
 
+// releaseChecksum returns the checksum pinned in the tool manifest for a pack release. It is a var so tests can pin the
+// checksums of their fake releases.
+var releaseChecksum = func(version semver.Version, releaseName string) tool.Checksum {
+	packTool := tool.FindTool("pack")
+	if packTool == nil {
+		return tool.Checksum{}
+	}
+
+	return packTool.ArtifactChecksum(version.String(), releaseName)
+}
+
 var statusCodeFailureRegexp = regexp.MustCompile(`failed with status code: (\d+)`)
 
 // All buildpacks groups have failed to detect w/o error.
@@ -205,12 +217,15 @@ func (cli *Cli) enableExperimental(ctx context.Context) error {
 	return nil
 }
 
+// Build builds the image from the source in cwd with the builder image. When cacheVolume is set, the build cache is kept
+// in the named docker volume.
 func (cli *Cli) Build(
 	ctx context.Context,
 	cwd string,
 	builder string,
 	imageName string,
 	environ []string,
+	cacheVolume string,
 	progressWriter io.Writer,
 ) error {
 	err := cli.enableExperimental(ctx)
@@ -225,6 +240,9 @@ func (cli *Cli) Build(
 
 	runArgs := exec.NewRunArgs(cli.path, "build", imageName, "--builder", builder, "--path", cwd)
 	runArgs.Args = append(runArgs.Args, envArgs...)
+	if cacheVolume != "" {
+		runArgs.Args = append(runArgs.Args, "--cache", fmt.Sprintf("type=build;format=volume;name=%s", cacheVolume))
+	}
 	if progressWriter != nil {
 		runArgs = runArgs.WithStdOut(progressWriter).WithStdErr(progressWriter)
 	}
@@ -372,47 +390,39 @@ func extractCli(src, dst string) (string, error) {
 }
 
 // downloadPack downloads a given version of pack cli from the release site.
-func downloadPack(
-	ctx context.Context,
-	transporter policy.Transporter,
-	version semver.Version,
-	extractFile func(src, dst string) (string, error),
-	path string) error {
-	systemArch := runtime.GOARCH
+// packReleaseName returns the name of the pack release archive for the given platform.
+func packReleaseName(goos string, goarch string, version semver.Version) (string, error) {
 	archString := "" // amd64 is the implicit default
-	if systemArch != "amd64" {
-		archString = fmt.Sprintf("-%s", systemArch)
+	if goarch != "amd64" {
+		archString = fmt.Sprintf("-%s", goarch)
 	}
 
-	var releaseName string
-	switch runtime.GOOS {
+	switch goos {
 	case "windows":
-		releaseName = fmt.Sprintf("pack-v%s-windows%s.zip", version, archString)
+		return fmt.Sprintf("pack-v%s-windows%s.zip", version, archString), nil
 	case "darwin":
-		releaseName = fmt.Sprintf("pack-v%s-macos%s.tgz", version, archString)
+		return fmt.Sprintf("pack-v%s-macos%s.tgz", version, archString), nil
 	case "linux":
-		releaseName = fmt.Sprintf("pack-v%s-linux%s.tgz", version, archString)
+		return fmt.Sprintf("pack-v%s-linux%s.tgz", version, archString), nil
 	default:
-		return fmt.Errorf("unsupported platform")
+		return "", fmt.Errorf("unsupported platform")
 	}
+}
 
-	// example: https://github.com/buildpacks/pack/releases/download/v0.29.0/pack-v0.29.0-windows.zip
-	ghReleaseUrl := fmt.Sprintf("https://github.com/buildpacks/pack/releases/download/v%s/%s", version, releaseName)
-	log.Printf("downloading pack cli release %s -> %s", ghReleaseUrl, releaseName)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", ghReleaseUrl, nil)
+func downloadPack(
+	ctx context.Context,
+	transporter policy.Transporter,
+	version semver.Version,
+	extractFile func(src, dst string) (string, error),
+	path string) error {
+	releaseName, err := packReleaseName(runtime.GOOS, runtime.GOARCH, version)
 	if err != nil {
 		return err
 	}
 
-	resp, err := transporter.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("http error %d", resp.StatusCode)
-	}
+	// example: https://github.com/buildpacks/pack/releases/download/v0.29.0/pack-v0.29.0-windows.zip
+	ghReleaseUrl := fmt.Sprintf("https://github.com/buildpacks/pack/releases/download/v%s/%s", version, releaseName)
+	log.Printf("downloading pack cli release %s -> %s", ghReleaseUrl, releaseName)
 
 	tmpPath := filepath.Dir(path)
 	compressedRelease, err := os.CreateTemp(tmpPath, releaseName)
@@ -424,7 +434,8 @@ func downloadPack(
 		_ = os.Remove(compressedRelease.Name()) //nolint:gosec // G703: temp file cleanup
 	}()
 
-	if _, err := io.Copy(compressedRelease, resp.Body); err != nil {
+	checksum := releaseChecksum(version, releaseName)
+	if err := tool.DownloadArtifact(ctx, transporter, ghReleaseUrl, checksum, compressedRelease); err != nil {
 		return err
 	}
 	if err := compressedRelease.Close(); err != nil {
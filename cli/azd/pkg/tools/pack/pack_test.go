@@ -5,6 +5,8 @@ package pack
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
@@ -16,12 +18,25 @@ import (
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tool"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockzip"
 	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// pinReleaseChecksum pins the checksum of content for every pack release for the duration of the test.
+func pinReleaseChecksum(t *testing.T, content string) {
+	original := releaseChecksum
+	t.Cleanup(func() { releaseChecksum = original })
+
+	sum := sha256.Sum256([]byte(content))
+	releaseChecksum = func(semver.Version, string) tool.Checksum {
+		return tool.Checksum{Algorithm: "sha256", Value: hex.EncodeToString(sum[:])}
+	}
+}
+
 func Test_extractZip(t *testing.T) {
 	const contentZipped = "zipped pack"
 
@@ -151,6 +166,7 @@ func TestNewPackCliInstall(t *testing.T) {
 	t.Setenv("AZD_CONFIG_DIR", configRoot)
 
 	mockContext := mocks.NewMockContext(t.Context())
+	pinReleaseChecksum(t, "pack cli")
 
 	mockContext.HttpClient.When(func(request *http.Request) bool {
 		return request.Method == http.MethodGet && request.URL.Host == "github.com"
@@ -205,6 +221,7 @@ func TestNewPackCliUpgrade(t *testing.T) {
 	require.NoError(t, err)
 
 	mockContext := mocks.NewMockContext(t.Context())
+	pinReleaseChecksum(t, "pack cli")
 
 	mockContext.HttpClient.When(func(request *http.Request) bool {
 		return request.Method == http.MethodGet && request.URL.Host == "github.com"
@@ -245,3 +262,82 @@ func TestNewPackCliUpgrade(t *testing.T) {
 
 	require.Equal(t, "pack cli", string(contents))
 }
+
+func TestDownloadPackChecksumMismatch(t *testing.T) {
+	t.Setenv("AZD_CONFIG_DIR", t.TempDir())
+	cliPath, err := packCliPath()
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(cliPath), 0700))
+
+	mockContext := mocks.NewMockContext(t.Context())
+	pinReleaseChecksum(t, "pack cli")
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && request.URL.Host == "github.com"
+	}).Respond(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString("tampered pack cli")),
+	})
+
+	extracted := false
+	err = downloadPack(t.Context(), mockContext.HttpClient, Version, func(src, dst string) (string, error) {
+		extracted = true
+		return src, nil
+	}, cliPath)
+	require.ErrorContains(t, err, "checksum verification failed")
+	require.False(t, extracted)
+	require.NoFileExists(t, cliPath)
+}
+
+func TestVersionMatchesToolManifest(t *testing.T) {
+	// `azd tool list` reports pack installs older than the manifest pin, so it must track the version azd downloads.
+	require.Equal(t, Version.String(), tool.FindTool("pack").MinVersion)
+}
+
+func TestBuild(t *testing.T) {
+	for _, cacheVolume := range []string{"", "azd-build-cache"} {
+		t.Run("CacheVolume="+cacheVolume, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(t.Context())
+			var buildArgs []string
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return len(args.Args) > 0 && args.Args[0] == "config"
+			}).Respond(exec.NewRunResult(0, "", ""))
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return len(args.Args) > 0 && args.Args[0] == "build"
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				buildArgs = args.Args
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			cli := NewPackCliWithPath(mockContext.CommandRunner, "pack")
+			err := cli.Build(
+				*mockContext.Context, "/src", "builder:latest", "app-web", []string{"BP_NODE_VERSION=20"}, cacheVolume, nil)
+			require.NoError(t, err)
+
+			expected := []string{
+				"build", "app-web", "--builder", "builder:latest", "--path", "/src", "--env", "BP_NODE_VERSION=20",
+			}
+			if cacheVolume != "" {
+				expected = append(expected, "--cache", "type=build;format=volume;name=azd-build-cache")
+			}
+			require.Equal(t, expected, buildArgs)
+		})
+	}
+}
+
+func TestReleaseChecksumsPinned(t *testing.T) {
+	// downloadPack refuses releases without a checksum, so every archive it can request for the pinned version must
+	// have one in the tool manifest.
+	packTool := tool.FindTool("pack")
+	require.NotNil(t, packTool)
+
+	for _, goos := range []string{"windows", "darwin", "linux"} {
+		for _, goarch := range []string{"amd64", "arm64"} {
+			releaseName, err := packReleaseName(goos, goarch, Version)
+			require.NoError(t, err)
+
+			checksum := packTool.ArtifactChecksum(Version.String(), releaseName)
+			assert.Equal(t, "sha256", checksum.Algorithm, "%s has no pinned checksum", releaseName)
+			assert.Len(t, checksum.Value, sha256.Size*2, "%s has no pinned checksum", releaseName)
+		}
+	}
+}
@@ -111,6 +111,7 @@ func (p *powershellExecutor) Execute(
 	runArgs := exec.NewRunArgs(p.shellCmd, path).
 		WithCwd(execCtx.Cwd).
 		WithEnv(execCtx.EnvVars).
+		WithEnvIsolation(execCtx.EnvIsolation).
 		WithShell(true)
 
 	if execCtx.Interactive != nil {
@@ -133,7 +133,10 @@ func (cli *Cli) Run(
 	runCmd := strings.Join(append([]string{pyString}, args...), " ")
 	// We need to ensure the virtual environment is activated before running the script
 	commands := []string{envActivationCmd, runCmd}
-	runArgs := exec.NewRunArgs("").WithCwd(workingDir).WithEnv(env)
+	runArgs := exec.NewRunArgs("").
+		WithCwd(workingDir).
+		WithEnv(env).
+		WithEnvIsolation(exec.EnvIsolationFromContext(ctx))
 	runResult, err := cli.commandRunner.RunList(ctx, commands, runArgs)
 
 	if err != nil {
@@ -54,6 +54,12 @@ type ExecutionContext struct {
 	// specified. Executors must not mutate this map — it is shared
 	// with the underlying HookConfig.
 	Config map[string]any
+
+	// EnvIsolation restricts the environment of the hook script to
+	// the allowed keys when set. Executors apply it only to the
+	// command that runs the hook, not to the tools used to prepare
+	// it.
+	EnvIsolation *exec.EnvIsolation
 }
 
 // HookExecutor is the unified interface for all hook execution.
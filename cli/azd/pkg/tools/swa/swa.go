@@ -126,7 +126,8 @@ func (cli *Cli) run(
 		NewRunArgs("npx", "-y", swaCliPackage).
 		AppendParams(args...).
 		WithCwd(cwd).
-		WithEnv(env)
+		WithEnv(env).
+		WithEnvIsolation(exec.EnvIsolationFromContext(ctx))
 
 	if buildProgress != nil {
 		runArgs = runArgs.WithStdOut(buildProgress).WithStdErr(buildProgress)
@@ -0,0 +1,68 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package syft
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+var _ tools.ExternalTool = (*Cli)(nil)
+
+// The SBOM formats written by Syft
+const (
+	FormatSpdxJson      = "spdx-json"
+	FormatCycloneDxJson = "cyclonedx-json"
+)
+
+// Cli is the Syft CLI, which generates software bills of materials (SBOMs) of container images, files and directories.
+type Cli struct {
+	commandRunner exec.CommandRunner
+}
+
+func NewCli(commandRunner exec.CommandRunner) *Cli {
+	return &Cli{
+		commandRunner: commandRunner,
+	}
+}
+
+// DirectorySource is the source of the SBOM of a directory.
+func DirectorySource(path string) string {
+	return "dir:" + path
+}
+
+// FileSource is the source of the SBOM of a file, such as a zip archive, which Syft unpacks.
+func FileSource(path string) string {
+	return "file:" + path
+}
+
+// ImageSource is the source of the SBOM of a local image of the container engine, 'docker' or 'podman'.
+func ImageSource(containerEngine string, image string) string {
+	return fmt.Sprintf("%s:%s", containerEngine, image)
+}
+
+// Generate writes the SBOM of source, in format, to the file at outputPath.
+func (cli *Cli) Generate(ctx context.Context, source string, format string, outputPath string) error {
+	runArgs := exec.NewRunArgs("syft", "scan", source, "--output", fmt.Sprintf("%s=%s", format, outputPath), "--quiet")
+	if _, err := cli.commandRunner.Run(ctx, runArgs); err != nil {
+		return fmt.Errorf("syft scan: %w", err)
+	}
+
+	return nil
+}
+
+func (cli *Cli) CheckInstalled(_ context.Context) error {
+	return cli.commandRunner.ToolInPath("syft")
+}
+
+func (cli *Cli) Name() string {
+	return "Syft"
+}
+
+func (cli *Cli) InstallUrl() string {
+	return "https://github.com/anchore/syft#installation"
+}
@@ -0,0 +1,53 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package syft
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SyftGenerate(t *testing.T) {
+	t.Run("NoErrors", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		cli := NewCli(mockContext.CommandRunner)
+
+		ran := false
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.HasPrefix(command, "syft scan")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			ran = true
+			require.Equal(t, []string{
+				"scan",
+				"podman:web:azd-deploy-1",
+				"--output", "cyclonedx-json=dist/web.cdx.json",
+				"--quiet",
+			}, args.Args)
+
+			return exec.RunResult{}, nil
+		})
+
+		err := cli.Generate(
+			t.Context(), ImageSource("podman", "web:azd-deploy-1"), FormatCycloneDxJson, "dist/web.cdx.json")
+		require.NoError(t, err)
+		require.True(t, ran)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		cli := NewCli(mockContext.CommandRunner)
+
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.HasPrefix(command, "syft scan")
+		}).SetError(errors.New("could not determine source"))
+
+		err := cli.Generate(t.Context(), FileSource("dist/web.zip"), FormatSpdxJson, "dist/web.spdx.json")
+		require.ErrorContains(t, err, "could not determine source")
+	})
+}
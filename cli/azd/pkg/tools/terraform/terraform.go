@@ -168,6 +168,35 @@ func (cli *Cli) Plan(
 	return cmdRes.Stdout, nil
 }
 
+// PlanQuiet runs `terraform plan` like Plan, but captures the output instead of streaming it to the console.
+// -input=false makes terraform fail instead of waiting on a prompt that isn't visible. It's used when azd renders
+// the saved plan itself, such as for `azd provision --preview`.
+func (cli *Cli) PlanQuiet(
+	ctx context.Context,
+	modulePath string,
+	planFilePath string,
+	additionalArgs ...string,
+) (string, error) {
+	args := []string{
+		fmt.Sprintf("-chdir=%s", modulePath),
+		"plan",
+		fmt.Sprintf("-out=%s", planFilePath),
+		"-lock=false",
+		"-input=false",
+	}
+
+	args = append(args, additionalArgs...)
+	cmdRes, err := cli.runCommand(ctx, args...)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed running terraform plan: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return cmdRes.Stdout, nil
+}
+
 func (cli *Cli) Apply(ctx context.Context, modulePath string, additionalArgs ...string) (string, error) {
 	args := []string{
 		fmt.Sprintf("-chdir=%s", modulePath),
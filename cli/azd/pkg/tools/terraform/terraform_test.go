@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tool"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 )
@@ -308,6 +309,15 @@ func getCommandTestCases() []commandTestCase {
 			interactive:  true,
 			errContains:  "failed running terraform plan",
 		},
+		{
+			name: "PlanQuiet",
+			invoke: func(cli *Cli, ctx context.Context) (string, error) {
+				return cli.PlanQuiet(ctx, "/module", "/plan.out")
+			},
+			expectedArgs: []string{"-chdir=/module", "plan", "-out=/plan.out", "-lock=false", "-input=false"},
+			interactive:  false,
+			errContains:  "failed running terraform plan",
+		},
 		{
 			name: "Apply",
 			invoke: func(cli *Cli, ctx context.Context) (string, error) {
@@ -643,3 +653,8 @@ func Test_ImplementsExternalTool(t *testing.T) {
 	// Verify the Cli type satisfies the tools.ExternalTool interface
 	var _ tools.ExternalTool = cli
 }
+
+func TestMinimumVersionMatchesToolManifest(t *testing.T) {
+	cli := &Cli{}
+	require.Equal(t, cli.versionInfo().MinimumVersion.String(), tool.FindTool("terraform").MinVersion)
+}
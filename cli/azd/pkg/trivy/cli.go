@@ -0,0 +1,116 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package trivy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+)
+
+// Cli is a wrapper around the Trivy vulnerability scanner CLI
+type Cli struct {
+	commandRunner exec.CommandRunner
+}
+
+// NewCli creates a new instance of the Trivy CLI wrapper
+func NewCli(commandRunner exec.CommandRunner) *Cli {
+	return &Cli{
+		commandRunner: commandRunner,
+	}
+}
+
+// Gets the name of the Tool
+func (cli *Cli) Name() string {
+	return "Trivy"
+}
+
+// Returns the installation URL to install the Trivy CLI
+func (cli *Cli) InstallUrl() string {
+	return "https://trivy.dev/latest/getting-started/installation/"
+}
+
+// Checks whether or not the Trivy CLI is installed and available within the PATH
+func (cli *Cli) CheckInstalled(ctx context.Context) error {
+	return cli.commandRunner.ToolInPath("trivy")
+}
+
+// ScanOptions are the options of an image scan
+type ScanOptions struct {
+	// The severities of the vulnerabilities to report, for example HIGH and CRITICAL
+	Severities []string
+	// Whether vulnerabilities without a fixed version are ignored
+	IgnoreUnfixed bool
+	// Where the image is read from, for example 'docker' or 'podman'. Defaults to the sources Trivy tries by default.
+	ImageSource string
+}
+
+// Report is the JSON report of a Trivy scan
+type Report struct {
+	ArtifactName string   `json:"ArtifactName"`
+	Results      []Result `json:"Results"`
+}
+
+// Result holds the vulnerabilities found in one target of the image, such as the OS packages or a lock file
+type Result struct {
+	Target          string          `json:"Target"`
+	Class           string          `json:"Class"`
+	Type            string          `json:"Type"`
+	Vulnerabilities []Vulnerability `json:"Vulnerabilities"`
+}
+
+// Vulnerability is a vulnerability of a package of the image
+type Vulnerability struct {
+	VulnerabilityID  string `json:"VulnerabilityID"`
+	PkgName          string `json:"PkgName"`
+	InstalledVersion string `json:"InstalledVersion"`
+	FixedVersion     string `json:"FixedVersion"`
+	Severity         string `json:"Severity"`
+	Title            string `json:"Title"`
+	PrimaryURL       string `json:"PrimaryURL"`
+}
+
+// Vulnerabilities returns the vulnerabilities of all the targets of the report.
+func (r *Report) Vulnerabilities() []Vulnerability {
+	var vulnerabilities []Vulnerability
+	for _, result := range r.Results {
+		vulnerabilities = append(vulnerabilities, result.Vulnerabilities...)
+	}
+
+	return vulnerabilities
+}
+
+// ScanImage scans a local image for vulnerabilities. The scan runs in cwd, so that a .trivyignore file in cwd is used.
+func (cli *Cli) ScanImage(ctx context.Context, cwd string, image string, options ScanOptions) (*Report, error) {
+	runArgs := exec.NewRunArgs(
+		"trivy", "image", "--format", "json", "--quiet", "--scanners", "vuln",
+	).WithCwd(cwd)
+
+	if len(options.Severities) > 0 {
+		runArgs = runArgs.AppendParams("--severity", strings.Join(options.Severities, ","))
+	}
+
+	if options.IgnoreUnfixed {
+		runArgs = runArgs.AppendParams("--ignore-unfixed")
+	}
+
+	if options.ImageSource != "" {
+		runArgs = runArgs.AppendParams("--image-src", options.ImageSource)
+	}
+
+	res, err := cli.commandRunner.Run(ctx, runArgs.AppendParams(image))
+	if err != nil {
+		return nil, fmt.Errorf("scanning image %s: %w", image, err)
+	}
+
+	var report Report
+	if err := json.Unmarshal([]byte(res.Stdout), &report); err != nil {
+		return nil, fmt.Errorf("reading scan report of image %s: %w", image, err)
+	}
+
+	return &report, nil
+}
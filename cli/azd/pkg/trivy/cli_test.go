@@ -0,0 +1,81 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package trivy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+const testReport = `{
+  "SchemaVersion": 2,
+  "ArtifactName": "contoso.azurecr.io/app/web:azd-deploy-1",
+  "Results": [
+    {
+      "Target": "contoso.azurecr.io/app/web:azd-deploy-1 (debian 12.5)",
+      "Class": "os-pkgs",
+      "Type": "debian",
+      "Vulnerabilities": [
+        {
+          "VulnerabilityID": "CVE-2024-0001",
+          "PkgName": "openssl",
+          "InstalledVersion": "3.0.11-1",
+          "FixedVersion": "3.0.13-1",
+          "Severity": "CRITICAL"
+        }
+      ]
+    },
+    {
+      "Target": "app/package-lock.json",
+      "Class": "lang-pkgs",
+      "Type": "npm"
+    }
+  ]
+}`
+
+func TestScanImage(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	var runArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "trivy"
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		runArgs = args
+		return exec.NewRunResult(0, testReport, ""), nil
+	})
+
+	cli := NewCli(mockContext.CommandRunner)
+	report, err := cli.ScanImage(*mockContext.Context, "/src/web", "contoso.azurecr.io/app/web:azd-deploy-1", ScanOptions{
+		Severities:    []string{"HIGH", "CRITICAL"},
+		IgnoreUnfixed: true,
+		ImageSource:   "podman",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "/src/web", runArgs.Cwd)
+	require.Equal(t, []string{
+		"image", "--format", "json", "--quiet", "--scanners", "vuln",
+		"--severity", "HIGH,CRITICAL", "--ignore-unfixed", "--image-src", "podman",
+		"contoso.azurecr.io/app/web:azd-deploy-1",
+	}, runArgs.Args)
+
+	vulnerabilities := report.Vulnerabilities()
+	require.Len(t, vulnerabilities, 1)
+	require.Equal(t, "CVE-2024-0001", vulnerabilities[0].VulnerabilityID)
+	require.Equal(t, "3.0.13-1", vulnerabilities[0].FixedVersion)
+}
+
+func TestScanImage_Error(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "trivy"
+	}).SetError(errors.New("unable to find the specified image"))
+
+	cli := NewCli(mockContext.CommandRunner)
+	_, err := cli.ScanImage(*mockContext.Context, "/src/web", "web:latest", ScanOptions{})
+	require.ErrorContains(t, err, "scanning image web:latest: unable to find the specified image")
+}
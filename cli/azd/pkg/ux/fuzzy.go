@@ -0,0 +1,134 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package ux
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Scores of the characters of a fuzzy match. Matches that are contiguous, or start words, like "wus" for "West US",
+// rank above matches scattered across the text.
+const (
+	fuzzyMatchScore       = 1
+	fuzzyConsecutiveBonus = 2
+	fuzzyWordStartBonus   = 3
+	fuzzySubstringBonus   = 10
+)
+
+// fuzzyMatch reports whether all the characters of filter appear in text, in order, ignoring case and the whitespace in
+// filter. It returns the score of the match, higher for better matches, and the indexes of the matched runes of text.
+func fuzzyMatch(text string, filter string) (int, []int, bool) {
+	pattern := []rune{}
+	for _, r := range filter {
+		if !unicode.IsSpace(r) {
+			pattern = append(pattern, unicode.ToLower(r))
+		}
+	}
+
+	if len(pattern) == 0 {
+		return 0, nil, true
+	}
+
+	runes := []rune(text)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	// Prefer filter as a substring of text, as typed or without its whitespace, which a greedy subsequence match could
+	// split up.
+	for _, substring := range [][]rune{[]rune(strings.ToLower(strings.TrimSpace(filter))), pattern} {
+		if start := indexRunes(lower, substring); start >= 0 {
+			positions := make([]int, len(substring))
+			for i := range positions {
+				positions[i] = start + i
+			}
+
+			return fuzzySubstringBonus + fuzzyScore(runes, positions), positions, true
+		}
+	}
+
+	positions := make([]int, 0, len(pattern))
+	for i := 0; i < len(lower) && len(positions) < len(pattern); i++ {
+		if lower[i] == pattern[len(positions)] {
+			positions = append(positions, i)
+		}
+	}
+
+	if len(positions) < len(pattern) {
+		return 0, nil, false
+	}
+
+	return fuzzyScore(runes, positions), positions, true
+}
+
+// fuzzyScore scores the matched runes of text at positions.
+func fuzzyScore(runes []rune, positions []int) int {
+	score := 0
+	for i, position := range positions {
+		score += fuzzyMatchScore
+
+		if i > 0 && positions[i-1] == position-1 {
+			score += fuzzyConsecutiveBonus
+		}
+
+		if position == 0 || !unicode.IsLetter(runes[position-1]) && !unicode.IsDigit(runes[position-1]) ||
+			unicode.IsUpper(runes[position]) && unicode.IsLower(runes[position-1]) {
+			score += fuzzyWordStartBonus
+		}
+	}
+
+	return score
+}
+
+// indexRunes returns the index of the first instance of pattern in runes, or -1 when pattern isn't in runes.
+func indexRunes(runes []rune, pattern []rune) int {
+	for i := 0; i+len(pattern) <= len(runes); i++ {
+		if string(runes[i:i+len(pattern)]) == string(pattern) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// highlightMatches applies highlight to the runes of text at positions, grouping consecutive runes. Only the text before
+// the first terminal escape sequence is highlighted, so that styled parts of text, like option details, keep their style.
+func highlightMatches(text string, positions []int, highlight func(format string, a ...any) string) string {
+	plainLen := len(text)
+	if i := strings.Index(text, "\x1b"); i >= 0 {
+		plainLen = i
+	}
+
+	matched := map[int]bool{}
+	for _, position := range positions {
+		matched[position] = true
+	}
+
+	var sb strings.Builder
+	var run strings.Builder
+	index := 0
+	for _, r := range text[:plainLen] {
+		if matched[index] {
+			run.WriteRune(r)
+		} else {
+			if run.Len() > 0 {
+				sb.WriteString(highlight("%s", run.String()))
+				run.Reset()
+			}
+
+			sb.WriteRune(r)
+		}
+
+		index++
+	}
+
+	if run.Len() > 0 {
+		sb.WriteString(highlight("%s", run.String()))
+	}
+
+	sb.WriteString(text[plainLen:])
+	return sb.String()
+}
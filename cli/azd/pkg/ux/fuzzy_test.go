@@ -0,0 +1,59 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package ux
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		filter    string
+		matched   bool
+		positions []int
+	}{
+		{"Substring", "East US 2 (eastus2)", "US 2", true, []int{5, 6, 7, 8}},
+		{"IgnoresCase", "West Europe", "weur", true, []int{0, 1, 6, 7}},
+		{"Subsequence", "West US 2 (westus2)", "wus2", true, []int{0, 5, 6, 8}},
+		{"IgnoresFilterWhitespace", "westus2", "west us", true, []int{0, 1, 2, 3, 4, 5}},
+		{"OutOfOrder", "West US", "usw", false, nil},
+		{"EmptyFilter", "West US", " ", true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, positions, matched := fuzzyMatch(tt.text, tt.filter)
+			assert.Equal(t, tt.matched, matched)
+			assert.Equal(t, tt.positions, positions)
+		})
+	}
+}
+
+func TestFuzzyMatch_Score(t *testing.T) {
+	substring, _, _ := fuzzyMatch("Awus", "wus")
+	wordStarts, _, _ := fuzzyMatch("West US", "wus")
+	scattered, _, _ := fuzzyMatch("Swiss bus", "wus")
+
+	assert.Greater(t, substring, wordStarts)
+	assert.Greater(t, wordStarts, scattered)
+}
+
+func TestHighlightMatches(t *testing.T) {
+	highlight := func(format string, a ...any) string {
+		return "[" + fmt.Sprintf(format, a...) + "]"
+	}
+
+	assert.Equal(t, "[We]st [U]S", highlightMatches("West US", []int{0, 1, 5}, highlight))
+	assert.Equal(t, "West US", highlightMatches("West US", nil, highlight))
+
+	// Matches in the styled part of the text aren't highlighted
+	assert.Equal(t,
+		"[W]est \x1b[90m(westus)\x1b[0m",
+		highlightMatches("West \x1b[90m(westus)\x1b[0m", []int{0, 6}, highlight))
+}
@@ -4,11 +4,14 @@
 package ux
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -41,6 +44,9 @@ type SelectOptions struct {
 	DisplayNumbers *bool
 	// Whether or not to disable filtering (default: true)
 	EnableFiltering *bool
+	// The optional text displayed in the filter until the user types, like what the options can be filtered by
+	// (default: "Type to filter list")
+	FilterHint string
 }
 
 type SelectChoice struct {
@@ -60,6 +66,7 @@ var DefaultSelectOptions SelectOptions = SelectOptions{
 	DisplayCount:    6,
 	EnableFiltering: new(true),
 	DisplayNumbers:  new(false),
+	FilterHint:      "Type to filter list",
 }
 
 // Select is a component for prompting the user to select an option from a list.
@@ -73,8 +80,10 @@ type Select struct {
 	showHelp           bool
 	complete           bool
 	filter             string
+	appliedFilter      string
 	choices            []*indexedSelectChoice
 	filteredChoices    []*indexedSelectChoice
+	filterMatches      map[int][]int
 	selectedChoice     *indexedSelectChoice
 	hasValidationError bool
 	validationMessage  string
@@ -198,10 +207,13 @@ func (p *Select) Ask(ctx context.Context) (*int, error) {
 	return &p.selectedChoice.Index, nil
 }
 
+// applyFilter filters the choices to the ones that fuzzy match the filter, best matches first. The selection moves
+// to the best match when the filter changes.
 func (p *Select) applyFilter() {
 	// Filter options
 	if p.filter == "" {
 		p.filteredChoices = p.choices
+		p.appliedFilter = ""
 	}
 
 	if p.cancelled || p.complete || p.filter == "" {
@@ -209,6 +221,8 @@ func (p *Select) applyFilter() {
 	}
 
 	p.filteredChoices = []*indexedSelectChoice{}
+	p.filterMatches = map[int][]int{}
+	scores := map[int]int{}
 	for _, option := range p.choices {
 		// Attempt to parse the filter as an index
 		if p.options.DisplayNumbers != nil && *p.options.DisplayNumbers {
@@ -216,22 +230,34 @@ func (p *Select) applyFilter() {
 			if err == nil {
 				if index == option.Index+1 {
 					p.filteredChoices = append(p.filteredChoices, option)
+					scores[option.Index] = math.MaxInt
 					continue
 				}
 			}
 		}
 
-		containsValue := strings.Contains(strings.ToLower(option.Value), strings.ToLower(p.filter))
-		containsLabel := strings.Contains(strings.ToLower(option.Label), strings.ToLower(p.filter))
+		// Details of the options are styled, match the text without the terminal escape sequences
+		score, positions, matched := fuzzyMatch(specialTextRegex.ReplaceAllString(option.Label, ""), p.filter)
+		if !matched {
+			score, _, matched = fuzzyMatch(option.Value, p.filter)
+		}
 
-		if containsValue || containsLabel {
+		if matched {
 			p.filteredChoices = append(p.filteredChoices, option)
+			p.filterMatches[option.Index] = positions
+			scores[option.Index] = score
 		}
 	}
 
-	if *p.currentIndex > len(p.filteredChoices)-1 {
+	slices.SortStableFunc(p.filteredChoices, func(a, b *indexedSelectChoice) int {
+		return cmp.Compare(scores[b.Index], scores[a.Index])
+	})
+
+	if p.filter != p.appliedFilter || *p.currentIndex > len(p.filteredChoices)-1 {
 		p.currentIndex = new(0)
 	}
+
+	p.appliedFilter = p.filter
 }
 
 func (p *Select) renderOptions(printer Printer, indent string) {
@@ -269,16 +295,9 @@ func (p *Select) renderOptions(printer Printer, indent string) {
 	for index, option := range p.filteredChoices[start:end] {
 		displayValue := option.Label
 
-		// Underline the matching portion of the string
+		// Underline the matching characters of the string
 		if p.filter != "" {
-			matchIndex := strings.Index(strings.ToLower(displayValue), strings.ToLower(p.filter))
-			if matchIndex > -1 {
-				displayValue = fmt.Sprintf("%s%s%s",
-					displayValue[:matchIndex],                                    // Start of the string
-					underline(displayValue[matchIndex:matchIndex+len(p.filter)]), // Highlighted filter
-					displayValue[matchIndex+len(p.filter):],                      // End of the string
-				)
-			}
+			displayValue = highlightMatches(displayValue, p.filterMatches[option.Index], underline)
 		}
 
 		// Show item digit prefixes
@@ -362,7 +381,7 @@ func (p *Select) renderMessage(printer Printer) {
 
 		if p.filter == "" {
 			p.cursorPosition = new(printer.CursorPosition())
-			printer.Fprintf("%s", output.WithGrayFormat("Type to filter list"))
+			printer.Fprintf("%s", output.WithGrayFormat("%s", p.options.FilterHint))
 		} else {
 			printer.Fprintf("%s", p.filter)
 			p.cursorPosition = new(printer.CursorPosition())
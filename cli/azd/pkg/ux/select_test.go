@@ -149,6 +149,50 @@ func TestSelect_applyFilter_matches(t *testing.T) {
 	assert.Len(t, s.filteredChoices, 2)
 }
 
+func TestSelect_applyFilter_fuzzy(t *testing.T) {
+	s := NewSelect(&SelectOptions{
+		Writer:  io.Discard,
+		Message: "Choose",
+		Choices: []*SelectChoice{
+			{Value: "swedencentral", Label: "Sweden Central (swedencentral)"},
+			{Value: "westus", Label: "West US (westus)"},
+			{Value: "westus2", Label: "West US 2 \x1b[90m(westus2)\x1b[0m"},
+			{Value: "eastus", Label: "East US (eastus)"},
+		},
+	})
+	s.currentIndex = new(2)
+	s.filter = "wus2"
+
+	s.applyFilter()
+	require.Len(t, s.filteredChoices, 1)
+	assert.Equal(t, "westus2", s.filteredChoices[0].Value)
+	assert.Equal(t, 0, *s.currentIndex)
+
+	// Best matches come first: a substring, then word starts, then scattered characters
+	s.filter = "wes"
+	s.applyFilter()
+	values := []string{}
+	for _, choice := range s.filteredChoices {
+		values = append(values, choice.Value)
+	}
+	assert.Equal(t, []string{"westus", "westus2", "swedencentral"}, values)
+}
+
+func TestSelect_Render_filter_hint(t *testing.T) {
+	var buf bytes.Buffer
+	printer := NewPrinter(&buf)
+
+	s := NewSelect(&SelectOptions{
+		Writer:     io.Discard,
+		Message:    "Choose",
+		Choices:    []*SelectChoice{{Value: "a", Label: "Alpha"}},
+		FilterHint: "Type a name or ID",
+	})
+
+	require.NoError(t, s.Render(printer))
+	assert.Contains(t, buf.String(), "Type a name or ID")
+}
+
 func TestSelect_applyFilter_no_match(t *testing.T) {
 	s := NewSelect(&SelectOptions{
 		Writer:  io.Discard,
@@ -0,0 +1,258 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package watch
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/ignore"
+	"github.com/fsnotify/fsnotify"
+)
+
+// dirIgnoredFolders are the folders of dependencies and build outputs that a DirWatcher never watches, even when no
+// .gitignore file lists them.
+var dirIgnoredFolders = map[string]struct{}{
+	".git":         {},
+	".azure":       {},
+	".venv":        {},
+	"__pycache__":  {},
+	"node_modules": {},
+	"bin":          {},
+	"obj":          {},
+}
+
+// DirWatcher watches a set of directories recursively, and reports the files that changed in batches: a batch is sent
+// once no file changed for the debounce duration, so that saving several files at once produces a single batch.
+type DirWatcher struct {
+	// Changes receives the sorted, absolute paths of the files changed since the previous batch. It is closed when the
+	// context passed to NewDirWatcher is done.
+	Changes <-chan []string
+
+	watcher *fsnotify.Watcher
+	roots   []*dirRoot
+
+	mu      sync.Mutex
+	paused  bool
+	pending map[string]struct{}
+}
+
+// dirRoot is a watched directory, with the ignore patterns of its .gitignore and .azdxignore files.
+type dirRoot struct {
+	path    string
+	matcher *ignore.Matcher
+}
+
+// NewDirWatcher starts watching dirs and their subdirectories. Paths matched by the .gitignore and .azdxignore files at
+// the root of each directory, and the folders of dependencies and build outputs such as node_modules, are ignored.
+func NewDirWatcher(ctx context.Context, dirs []string, debounce time.Duration) (*DirWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create watcher: %w", err)
+	}
+
+	changes := make(chan []string)
+	dw := &DirWatcher{
+		Changes: changes,
+		watcher: watcher,
+		pending: map[string]struct{}{},
+	}
+
+	for _, dir := range dirs {
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			watcher.Close()
+			return nil, err
+		}
+
+		matcher, err := ignore.NewMatcher(absDir)
+		if err != nil {
+			watcher.Close()
+			return nil, fmt.Errorf("failed to load ignore patterns: %w", err)
+		}
+
+		dw.roots = append(dw.roots, &dirRoot{path: absDir, matcher: matcher})
+	}
+
+	for _, root := range dw.roots {
+		if _, err := dw.watchRecursive(root.path, false); err != nil {
+			watcher.Close()
+			return nil, fmt.Errorf("watcher failed: %w", err)
+		}
+	}
+
+	go dw.run(ctx, changes, debounce)
+
+	return dw, nil
+}
+
+// Pause stops recording changes, for example while the watched files are rebuilt.
+func (dw *DirWatcher) Pause() {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+	dw.paused = true
+}
+
+// Resume records changes again. The changes made while the watcher was paused are discarded.
+func (dw *DirWatcher) Resume() {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+	dw.paused = false
+	clear(dw.pending)
+}
+
+func (dw *DirWatcher) run(ctx context.Context, changes chan<- []string, debounce time.Duration) {
+	defer close(changes)
+	defer dw.watcher.Close()
+
+	// timer is nil until a change is recorded, and restarts on every change.
+	var timer <-chan time.Time
+
+	for {
+		select {
+		case event, ok := <-dw.watcher.Events:
+			if !ok {
+				return
+			}
+			if dw.record(event) {
+				timer = time.After(debounce)
+			}
+		case err, ok := <-dw.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("watcher error: %v", err)
+		case <-timer:
+			timer = nil
+
+			dw.mu.Lock()
+			batch := slices.Sorted(maps.Keys(dw.pending))
+			clear(dw.pending)
+			dw.mu.Unlock()
+
+			if len(batch) == 0 {
+				continue
+			}
+
+			select {
+			case changes <- batch:
+			case <-ctx.Done():
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// record records the file of a file system event, and reports whether it was recorded.
+func (dw *DirWatcher) record(event fsnotify.Event) bool {
+	if event.Op == fsnotify.Chmod {
+		return false
+	}
+
+	info, statErr := os.Stat(event.Name)
+	isDir := statErr == nil && info.IsDir()
+	if dw.isIgnored(event.Name, isDir) {
+		return false
+	}
+
+	if isDir {
+		if !event.Has(fsnotify.Create) {
+			return false
+		}
+
+		// Watch the directories that are created, and record the files created in them before they were watched.
+		recorded, err := dw.watchRecursive(event.Name, true)
+		if err != nil {
+			log.Printf("failed to watch new directory %s: %v", event.Name, err)
+		}
+		return recorded
+	}
+
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+	if dw.paused {
+		return false
+	}
+
+	dw.pending[event.Name] = struct{}{}
+	return true
+}
+
+// isIgnored reports whether path is ignored by the default ignored folders, or the ignore patterns of the watched
+// directories that contain it.
+func (dw *DirWatcher) isIgnored(path string, isDir bool) bool {
+	for _, root := range dw.roots {
+		rel, err := filepath.Rel(root.path, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+
+		if rel == "." {
+			return false
+		}
+
+		for part := range strings.SplitSeq(filepath.ToSlash(rel), "/") {
+			if _, ignored := dirIgnoredFolders[part]; ignored {
+				return true
+			}
+		}
+
+		if root.matcher.IsIgnored(rel, isDir) {
+			return true
+		}
+
+		// A path removed from the disk can't be checked for being a directory, so it is also checked as a
+		// directory, so that directory-only patterns still apply.
+		if !isDir && root.matcher.IsIgnored(rel, true) && !exists(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// watchRecursive watches root and its subdirectories. When recordFiles is set, the files found in them are recorded as
+// changed, and it reports whether any file was recorded.
+func (dw *DirWatcher) watchRecursive(root string, recordFiles bool) (bool, error) {
+	recorded := false
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			if recordFiles && !dw.isIgnored(path, false) {
+				dw.mu.Lock()
+				if !dw.paused {
+					dw.pending[path] = struct{}{}
+					recorded = true
+				}
+				dw.mu.Unlock()
+			}
+			return nil
+		}
+
+		if dw.isIgnored(path, true) {
+			return filepath.SkipDir
+		}
+
+		return dw.watcher.Add(path)
+	})
+	return recorded, err
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirWatcher(t *testing.T) {
+	api := t.TempDir()
+	web := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(api, "src"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(web, "node_modules", "pkg"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(web, "dist"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(web, ".gitignore"), []byte("dist/\n*.log\n"), 0600))
+
+	watcher, err := NewDirWatcher(t.Context(), []string{api, web}, 50*time.Millisecond)
+	require.NoError(t, err)
+
+	receive := func() []string {
+		select {
+		case batch := <-watcher.Changes:
+			return batch
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for changes")
+			return nil
+		}
+	}
+
+	requireNoChanges := func() {
+		select {
+		case batch := <-watcher.Changes:
+			t.Fatalf("unexpected changes: %v", batch)
+		case <-time.After(300 * time.Millisecond):
+		}
+	}
+
+	t.Run("Batch", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(api, "src", "main.py"), []byte("print(1)"), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(web, "index.js"), []byte("1"), 0600))
+
+		require.Equal(t, []string{
+			filepath.Join(api, "src", "main.py"),
+			filepath.Join(web, "index.js"),
+		}, receive())
+	})
+
+	t.Run("NewDirectory", func(t *testing.T) {
+		require.NoError(t, os.MkdirAll(filepath.Join(api, "lib", "util"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(api, "lib", "util", "text.py"), []byte("1"), 0600))
+
+		require.Equal(t, []string{filepath.Join(api, "lib", "util", "text.py")}, receive())
+	})
+
+	t.Run("Ignored", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(web, "node_modules", "pkg", "index.js"), []byte("1"), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(web, "dist", "bundle.js"), []byte("1"), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(web, "debug.log"), []byte("1"), 0600))
+
+		requireNoChanges()
+	})
+
+	t.Run("Paused", func(t *testing.T) {
+		watcher.Pause()
+		require.NoError(t, os.WriteFile(filepath.Join(api, "src", "main.py"), []byte("print(2)"), 0600))
+		time.Sleep(100 * time.Millisecond)
+		watcher.Resume()
+
+		requireNoChanges()
+
+		require.NoError(t, os.Remove(filepath.Join(api, "src", "main.py")))
+		require.Equal(t, []string{filepath.Join(api, "src", "main.py")}, receive())
+	})
+}
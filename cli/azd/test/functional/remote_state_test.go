@@ -90,6 +90,7 @@ func createBlobClient(
 		auth.ExternalAuthConfiguration{},
 		azCli,
 		"",
+		nil,
 	)
 	require.NoError(t, err)
 
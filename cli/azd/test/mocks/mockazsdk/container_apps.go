@@ -200,6 +200,73 @@ func MockContainerAppJobUpdate(
 	return mockRequest
 }
 
+func MockContainerAppJobStart(
+	mockContext *mocks.MockContext,
+	subscriptionId string,
+	resourceGroup string,
+	jobName string,
+	executionName string,
+) *http.Request {
+	mockRequest := &http.Request{}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.Contains(
+			request.URL.Path,
+			fmt.Sprintf(
+				"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.App/jobs/%s/start",
+				subscriptionId,
+				resourceGroup,
+				jobName,
+			),
+		)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		*mockRequest = *request
+
+		response := armappcontainers.JobsClientStartResponse{
+			JobExecutionBase: armappcontainers.JobExecutionBase{
+				Name: &executionName,
+			},
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+	})
+
+	return mockRequest
+}
+
+func MockContainerAppJobExecutionGet(
+	mockContext *mocks.MockContext,
+	subscriptionId string,
+	resourceGroup string,
+	jobName string,
+	execution *armappcontainers.JobExecution,
+) *http.Request {
+	mockRequest := &http.Request{}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.Contains(
+			request.URL.Path,
+			fmt.Sprintf(
+				"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.App/jobs/%s/executions/%s",
+				subscriptionId,
+				resourceGroup,
+				jobName,
+				*execution.Name,
+			),
+		)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		*mockRequest = *request
+
+		response := armappcontainers.ContainerAppsAPIClientJobExecutionResponse{
+			JobExecution: *execution,
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+	})
+
+	return mockRequest
+}
+
 func MockContainerAppSecretsList(
 	mockContext *mocks.MockContext,
 	subscriptionId string,
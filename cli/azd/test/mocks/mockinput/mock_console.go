@@ -39,6 +39,7 @@ type MockConsole struct {
 	spinnerOps  []SpinnerOp
 	noPrompt    bool
 	isTerminal  bool
+	verbosity   input.Verbosity
 }
 
 func NewMockConsole() *MockConsole {
@@ -153,6 +154,14 @@ func (c *MockConsole) IsNoPromptMode() bool {
 	return c.noPrompt
 }
 
+func (c *MockConsole) SetVerbosity(verbosity input.Verbosity) {
+	c.verbosity = verbosity
+}
+
+func (c *MockConsole) Verbosity() input.Verbosity {
+	return c.verbosity
+}
+
 func (c *MockConsole) SupportsPromptDialog() bool {
 	return false
 }
@@ -185,7 +194,10 @@ func (c *MockConsole) Select(ctx context.Context, options input.ConsoleOptions)
 func (c *MockConsole) MultiSelect(ctx context.Context, options input.ConsoleOptions) ([]string, error) {
 	c.log = append(c.log, options.Message)
 	value, err := c.respond("MultiSelect", options)
-	return value.([]string), err
+
+	// Responses that only return an error, like RespondFn returning (nil, err), have no selection.
+	selected, _ := value.([]string)
+	return selected, err
 }
 
 // Writes messages to the underlying writer
@@ -5,6 +5,7 @@ package mocktools
 
 import (
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/bash"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/language"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/node"
@@ -41,4 +42,9 @@ func RegisterHookExecutors(mockCtx *mocks.MockContext) {
 		string(language.HookKindDotNet),
 		language.NewDotNetExecutor,
 	)
+	mockCtx.Container.MustRegisterSingleton(docker.NewCli)
+	mockCtx.Container.MustRegisterNamedTransient(
+		string(language.HookKindDocker),
+		language.NewDockerExecutor,
+	)
 }